@@ -2,17 +2,50 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"os"
 
+	"Aicon-assignment/internal/infrastructure/admin"
+	"Aicon-assignment/internal/infrastructure/config"
 	"Aicon-assignment/internal/infrastructure/server"
+	"Aicon-assignment/internal/pkg/reqctx"
 )
 
 func main() {
 	ctx := context.Background()
 
+	// 引数がある場合は運用向けのサブコマンドを実行する
+	if len(os.Args) > 1 {
+		if err := runCommand(reqctx.WithSystem(ctx), os.Args[1], os.Args[2:]); err != nil {
+			log.Fatalf("%s: %v", os.Args[1], err)
+		}
+		return
+	}
+
+	// 不正な設定・足りない設定がある場合は、すべて示して起動しない
+	if err := config.Validate(); err != nil {
+		log.Fatalf("invalid configuration:\n%v", err)
+	}
+
 	server := server.NewServer()
 
 	if err := server.Run(ctx); err != nil {
 		log.Fatalf("Failed to start server: %v", err)
 	}
 }
+
+func runCommand(ctx context.Context, name string, args []string) error {
+	switch name {
+	case "replay-events":
+		return admin.ReplayEvents(ctx, args, os.Stdout)
+	case "doctor":
+		return admin.Doctor(ctx, args, os.Stdout)
+	case "reindex-search":
+		return admin.ReindexSearch(ctx, args, os.Stdout)
+	case "verify-replica":
+		return admin.VerifyReplica(ctx, args, os.Stdout)
+	default:
+		return fmt.Errorf("unknown command")
+	}
+}
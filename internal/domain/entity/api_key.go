@@ -0,0 +1,72 @@
+package entity
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// API キーの名前の最大長
+const MaxAPIKeyNameLength = 100
+
+// 画面を使わないクライアント（バッチやスクリプト）がユーザーとして API を呼ぶためのキー
+// キーは発行時にだけ返し、保存するのはハッシュ値と見分けるための先頭部分のみ
+type APIKey struct {
+	ID         int64      `json:"id"`
+	UserID     int64      `json:"user_id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"` // キーの先頭部分。一覧でどのキーかを見分けるために使う
+	Key        string     `json:"key,omitempty"`
+	KeyHash    string     `json:"-"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+// 一覧で表示するキーの先頭部分の長さ
+const apiKeyPrefixLength = 11
+
+func NewAPIKey(userID int64, name, key string, now time.Time) (*APIKey, error) {
+	apiKey := &APIKey{
+		UserID:    userID,
+		Name:      strings.TrimSpace(name),
+		Key:       key,
+		KeyHash:   HashToken(key),
+		CreatedAt: now,
+	}
+	apiKey.Prefix = key
+	if len(key) > apiKeyPrefixLength {
+		apiKey.Prefix = key[:apiKeyPrefixLength]
+	}
+
+	if err := apiKey.validate(); err != nil {
+		return nil, err
+	}
+	return apiKey, nil
+}
+
+func (k *APIKey) validate() error {
+	var errs []string
+
+	if k.UserID <= 0 {
+		errs = append(errs, "user id must be positive")
+	}
+	if k.Name == "" {
+		errs = append(errs, "name is required")
+	} else if len(k.Name) > MaxAPIKeyNameLength {
+		errs = append(errs, "name must be 100 characters or less")
+	}
+	if k.Key == "" {
+		errs = append(errs, "key must not be empty")
+	}
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// 失効していないキーか
+func (k *APIKey) Active() bool {
+	return k.RevokedAt == nil
+}
@@ -0,0 +1,57 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+)
+
+// 添付ファイルの種類
+const (
+	AttachmentKindDocument = "document" // 鑑定書など
+	AttachmentKindReceipt  = "receipt"  // 購入時のレシート（1 アイテムに 1 つ）
+)
+
+// レシートとして受け付ける形式
+var ReceiptContentTypes = []string{"application/pdf", "image/jpeg"}
+
+// アイテムに添付したファイル（鑑定書など）。中身は SHA-256 で共有し、同じファイルは 1 つだけ保存する
+type Attachment struct {
+	ID          int64     `json:"id"`
+	ItemID      int64     `json:"item_id"`
+	Kind        string    `json:"kind"`
+	Filename    string    `json:"filename"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	SHA256      string    `json:"sha256"`
+	URL         string    `json:"url,omitempty"` // ファイルを取得する API のパス（レシートの場合）
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// レシートを取得する API のパス
+func ReceiptPath(itemID int64) string {
+	return fmt.Sprintf("/items/%d/receipt", itemID)
+}
+
+// 添付ファイルの中身と、それを参照している添付ファイルの数
+type AttachmentContent struct {
+	SHA256         string
+	Size           int64
+	RefCount       int
+	UnreferencedAt *time.Time // 参照がなくなった日時（参照がある場合は nil）
+}
+
+// ファイルストア上の添付ファイルの中身のキー
+func AttachmentBlobKey(sha256 string) string {
+	return "attachments/sha256/" + sha256[:2] + "/" + sha256
+}
+
+// 重複排除による容量の集計
+type AttachmentStorageReport struct {
+	Attachments          int64 `json:"attachments"`
+	Contents             int64 `json:"contents"`
+	LogicalBytes         int64 `json:"logical_bytes"`         // 添付ファイルのサイズの合計（重複を含む）
+	StoredBytes          int64 `json:"stored_bytes"`          // 実際に保存している中身のサイズの合計
+	SavedBytes           int64 `json:"saved_bytes"`           // 重複排除で保存せずに済んだサイズ（LogicalBytes − 参照されている中身のサイズ）
+	UnreferencedContents int64 `json:"unreferenced_contents"` // 参照がなく、削除を待っている中身
+	UnreferencedBytes    int64 `json:"unreferenced_bytes"`
+}
@@ -0,0 +1,58 @@
+package entity
+
+import (
+	"time"
+
+	"Aicon-assignment/internal/pkg/filter"
+	"Aicon-assignment/internal/pkg/listquery"
+)
+
+// 監査ログのアクション
+const (
+	AuditActionItemCreate = "item.create"
+	AuditActionItemUpdate = "item.update"
+	AuditActionItemDelete = "item.delete"
+	AuditActionItemMerge  = "item.merge"
+	AuditActionItemSplit  = "item.split"
+	AuditActionItemPurge  = "item.purge"
+
+	AuditActionItemAssignOwner = "item.assign_owner"
+
+	AuditActionVerificationStart   = "item.verification.start"
+	AuditActionVerificationSignOff = "item.verification.sign_off"
+	AuditActionVerificationReopen  = "item.verification.reopen"
+)
+
+// 監査ログの1エントリ
+type AuditEntry struct {
+	ID           int64     `json:"id"`
+	Action       string    `json:"action"`
+	ItemID       int64     `json:"item_id"`
+	Actor        string    `json:"actor"`
+	Impersonator string    `json:"impersonator,omitempty"` // 管理者が Actor になりすまして操作した場合の管理者
+	Reason       string    `json:"reason,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// GET /items/:id/audit-log で使える絞り込み・並び替え
+var AuditEntryListSpec = listquery.Spec{
+	Filters:     filter.Fields{"action": filter.String, "actor": filter.String, "impersonator": filter.String},
+	Sorts:       []string{"id", "created_at"},
+	DefaultSort: []listquery.SortField{{Field: "created_at"}, {Field: "id"}},
+}
+
+func (e *AuditEntry) FilterValue(field string) any {
+	switch field {
+	case "id":
+		return e.ID
+	case "action":
+		return e.Action
+	case "actor":
+		return e.Actor
+	case "impersonator":
+		return e.Impersonator
+	case "created_at":
+		return e.CreatedAt.UnixNano()
+	}
+	return nil
+}
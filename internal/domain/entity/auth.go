@@ -0,0 +1,34 @@
+package entity
+
+import (
+	"errors"
+	"time"
+)
+
+// パスワードの長さ（バイト）。bcrypt は 72 バイトを超える部分を無視するため、それより長いものは受け付けない
+const (
+	PasswordMinLength = 8
+	PasswordMaxLength = 72
+)
+
+// ログインで発行するアクセストークン（RFC 6749 5.1 の形式）
+type AccessToken struct {
+	AccessToken string    `json:"access_token"`
+	TokenType   string    `json:"token_type"`
+	ExpiresIn   int       `json:"expires_in"` // 秒
+	ExpiresAt   time.Time `json:"expires_at"`
+
+	// アクセストークンの期限が切れたら POST /auth/refresh で新しいものと交換する
+	RefreshToken     string    `json:"refresh_token"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+}
+
+func ValidatePassword(password string) error {
+	if len(password) < PasswordMinLength {
+		return errors.New("password must be at least 8 characters")
+	}
+	if len(password) > PasswordMaxLength {
+		return errors.New("password must be 72 bytes or less")
+	}
+	return nil
+}
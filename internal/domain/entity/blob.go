@@ -0,0 +1,17 @@
+package entity
+
+import "time"
+
+// ファイルストアに置いたファイルの記録
+type Blob struct {
+	Key       string     `json:"key"`      // "exports/items/....parquet" など
+	Location  string     `json:"location"` // ストア上の場所（"s3://bucket/key" など）
+	Size      int64      `json:"size"`
+	SHA256    string     `json:"sha256"` // 中身の SHA-256（16 進数）
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"` // nil の場合は削除するまで残す
+}
+
+func (b *Blob) ExpiredAt(now time.Time) bool {
+	return b.ExpiresAt != nil && !now.Before(*b.ExpiresAt)
+}
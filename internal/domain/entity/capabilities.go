@@ -0,0 +1,31 @@
+package entity
+
+// このデプロイで使える任意の機能。フロントエンドが使えない機能を隠せるように公開する
+type Capabilities struct {
+	GraphQL        bool     `json:"graphql"`
+	Webhooks       bool     `json:"webhooks"`
+	SCIM           bool     `json:"scim"`            // SCIM のトークンを設定している
+	Login          bool     `json:"login"`           // パスワードでログインしてアクセストークンを使う（false の場合は X-User-ID）
+	Register       bool     `json:"register"`        // POST /auth/register でアカウントを作成できる
+	LoginProviders []string `json:"login_providers"` // GET /auth/oidc/login で使える外部の ID プロバイダー
+	Search         string   `json:"search"`          // キーワード検索の方式（"meilisearch" または "database"）
+	Currencies     []string `json:"currencies"`      // 金額に使える通貨
+	VirusScan      bool     `json:"virus_scan"`      // アップロードしたファイルを検査する
+	ImageURLs      string   `json:"image_urls"`      // 画像の配信方法（"cdn", "presigned" または "api"）
+	Thumbnails     []string `json:"thumbnails"`      // 生成するサムネイルの大きさ
+	ReadOnly       bool     `json:"read_only"`       // 読み取り専用モード（書き込みの操作を隠す）
+	Sandbox        bool     `json:"sandbox"`         // サンドボックスの API キーを発行して /sandbox を使える
+}
+
+// キーワード検索の方式
+const (
+	SearchBackendMeilisearch = "meilisearch"
+	SearchBackendDatabase    = "database"
+)
+
+// 画像の配信方法
+const (
+	ImageURLsCDN       = "cdn"
+	ImageURLsPresigned = "presigned"
+	ImageURLsAPI       = "api"
+)
@@ -0,0 +1,101 @@
+package entity
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+// 廃止予定のエンドポイント
+type Deprecation struct {
+	Method string     `json:"method"` // "*" で全メソッド
+	Route  string     `json:"route"`  // ルートのパターン（"/items/:id" など）。path.Match の形式も使える
+	Since  time.Time  `json:"since"`  // 廃止予定になった日
+	Sunset *time.Time `json:"sunset,omitempty"`
+	Link   string     `json:"link,omitempty"` // 移行方法を説明するページ
+}
+
+func (d Deprecation) Matches(method, route string) bool {
+	if d.Method != "*" && !strings.EqualFold(d.Method, method) {
+		return false
+	}
+	if d.Route == route {
+		return true
+	}
+	matched, _ := path.Match(d.Route, route)
+	return matched
+}
+
+// 廃止予定のエンドポイントを呼び出し元ごとに数えたもの
+type DeprecationUsage struct {
+	Method    string    `json:"method"`
+	Route     string    `json:"route"`
+	Caller    string    `json:"caller"` // "user:1" など。認証ユーザーがいない場合は anonymous
+	Calls     int64     `json:"calls"`
+	FirstSeen time.Time `json:"first_seen"`
+	LastSeen  time.Time `json:"last_seen"`
+}
+
+// 廃止予定のエンドポイントごとの利用状況
+type DeprecationReport struct {
+	Deprecation
+	Calls   int64               `json:"calls"`
+	Callers []*DeprecationUsage `json:"callers"` // 最後に呼び出した日時の降順
+}
+
+// "GET /items/summary since=2024-06-01 sunset=2025-01-01 link=https://...; ..." の形式の廃止予定を読み込む
+// 廃止予定は ; 区切りで、メソッド・ルートのパターンに続けて key=value で指定する。日付は UTC の YYYY-MM-DD
+func ParseDeprecations(value string) ([]Deprecation, error) {
+	var deprecations []Deprecation
+	for _, part := range strings.Split(value, ";") {
+		fields := strings.Fields(part)
+		if len(fields) == 0 {
+			continue
+		}
+		raw := strings.TrimSpace(part)
+		if len(fields) < 3 {
+			return nil, fmt.Errorf("deprecation %q: method, route and since are required", raw)
+		}
+
+		deprecation := Deprecation{Method: strings.ToUpper(fields[0]), Route: fields[1]}
+		for _, option := range fields[2:] {
+			key, rawValue, ok := strings.Cut(option, "=")
+			if !ok {
+				return nil, fmt.Errorf("deprecation %q: invalid option %q", raw, option)
+			}
+
+			var err error
+			switch key {
+			case "since":
+				deprecation.Since, err = time.Parse(time.DateOnly, rawValue)
+			case "sunset":
+				var sunset time.Time
+				sunset, err = time.Parse(time.DateOnly, rawValue)
+				deprecation.Sunset = &sunset
+			case "link":
+				var u *url.URL
+				u, err = url.Parse(rawValue)
+				if err == nil && (u.Scheme != "http" && u.Scheme != "https" || u.Host == "") {
+					err = fmt.Errorf("must be an absolute http(s) URL")
+				}
+				deprecation.Link = rawValue
+			default:
+				err = fmt.Errorf("unknown option")
+			}
+			if err != nil {
+				return nil, fmt.Errorf("deprecation %q: %s: %w", raw, key, err)
+			}
+		}
+
+		if deprecation.Since.IsZero() {
+			return nil, fmt.Errorf("deprecation %q: since is required", raw)
+		}
+		if deprecation.Sunset != nil && deprecation.Sunset.Before(deprecation.Since) {
+			return nil, fmt.Errorf("deprecation %q: sunset must not be before since", raw)
+		}
+		deprecations = append(deprecations, deprecation)
+	}
+	return deprecations, nil
+}
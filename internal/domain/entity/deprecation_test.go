@@ -0,0 +1,33 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDeprecations(t *testing.T) {
+	deprecations, err := ParseDeprecations("get /items/summary since=2024-06-01 sunset=2025-01-01 link=https://example.com/migrate; * /items/:id/split since=2024-07-01;")
+	require.NoError(t, err)
+	sunset := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, []Deprecation{
+		{Method: "GET", Route: "/items/summary", Since: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), Sunset: &sunset, Link: "https://example.com/migrate"},
+		{Method: "*", Route: "/items/:id/split", Since: time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)},
+	}, deprecations)
+	assert.True(t, deprecations[1].Matches("POST", "/items/:id/split"))
+	assert.False(t, deprecations[0].Matches("POST", "/items/summary"))
+
+	for _, invalid := range []string{
+		"GET /items",
+		"GET /items sunset=2025-01-01",
+		"GET /items since=2024/06/01",
+		"GET /items since=2024-06-01 sunset=2024-01-01",
+		"GET /items since=2024-06-01 link=/docs",
+		"GET /items since=2024-06-01 owner=team",
+	} {
+		_, err := ParseDeprecations(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
@@ -0,0 +1,41 @@
+package entity
+
+import "time"
+
+// ドメインイベントの種類
+const (
+	EventItemCreated = "item.created"
+	EventItemUpdated = "item.updated"
+	EventItemDeleted = "item.deleted"
+)
+
+var ValidEventTypes = []string{EventItemCreated, EventItemUpdated, EventItemDeleted}
+
+// 削除イベントの理由。利用者が削除した場合は空
+const (
+	DeleteReasonMerged = "merged" // 別のアイテムに統合した（画像などは統合先に付け替え済み）
+	DeleteReasonSplit  = "split"  // 複数のアイテムに分割した（画像などは元のアイテムに残す）
+)
+
+// アイテムに関するドメインイベント
+type Event struct {
+	ID         string    `json:"id"`
+	Type       string    `json:"type"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Actor      string    `json:"actor"`
+	ItemID     int64     `json:"item_id"`
+	Item       *Item     `json:"item,omitempty"` // 削除イベントでは削除前の状態
+	// 更新イベントで値が変わったフィールド
+	ChangedFields []string `json:"changed_fields,omitempty"`
+	// 削除イベントで、統合・分割による削除の場合の理由（DeleteReasonMerged など）
+	DeleteReason string `json:"delete_reason,omitempty"`
+}
+
+func IsValidEventType(eventType string) bool {
+	for _, valid := range ValidEventTypes {
+		if eventType == valid {
+			return true
+		}
+	}
+	return false
+}
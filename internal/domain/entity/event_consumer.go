@@ -0,0 +1,116 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// 確認（ack）を待つ時間のデフォルトと上限（秒）
+const (
+	DefaultAckTimeoutSeconds = 30
+	MaxAckTimeoutSeconds     = 3600
+)
+
+// 消費者の作成時に読み始める位置
+const (
+	ConsumerStartEarliest = "earliest" // イベントストアの最初から
+	ConsumerStartLatest   = "latest"   // 作成した後に追記されたイベントから
+)
+
+var consumerNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]{0,63}$`)
+
+// イベントストアを読み進める名前付きの消費者（連携先ごとに作る）
+// 配信したイベントは AckDeadline までに確認されなければ、次の取得で AckedSequence の後から再配信する（at-least-once）
+type EventConsumer struct {
+	Name              string     `json:"name"`
+	AckedSequence     int64      `json:"acked_sequence"`     // この連番まで処理済み
+	DeliveredSequence int64      `json:"delivered_sequence"` // この連番まで配信済み（未確認のものを含む）
+	AckTimeoutSeconds int        `json:"ack_timeout_seconds"`
+	AckDeadline       *time.Time `json:"ack_deadline,omitempty"` // 未確認の配信がない場合は nil
+	Redeliveries      int        `json:"redeliveries"`           // 確認されずに再配信した回数の累計
+	LastAckedAt       *time.Time `json:"last_acked_at,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+// 消費者に配信したイベント
+type ConsumerBatch struct {
+	Consumer     string         `json:"consumer"`
+	Events       []*StoredEvent `json:"events"`
+	Redelivered  bool           `json:"redelivered"`            // 確認されなかったイベントを含む
+	AckDeadline  *time.Time     `json:"ack_deadline,omitempty"` // イベントがない場合は nil
+	LastSequence int64          `json:"last_sequence"`          // イベントストアの最新の連番
+}
+
+// startSequence の後のイベントから読み始める消費者。ackTimeoutSeconds が 0 の場合はデフォルト
+func NewEventConsumer(name string, startSequence int64, ackTimeoutSeconds int, now time.Time) (*EventConsumer, error) {
+	var errs []string
+	name = strings.TrimSpace(name)
+	if !consumerNamePattern.MatchString(name) {
+		errs = append(errs, "name must be 1-64 lowercase letters, digits, '.', '_' or '-' and start with a letter or digit")
+	}
+	if ackTimeoutSeconds == 0 {
+		ackTimeoutSeconds = DefaultAckTimeoutSeconds
+	}
+	if ackTimeoutSeconds < 1 || ackTimeoutSeconds > MaxAckTimeoutSeconds {
+		errs = append(errs, fmt.Sprintf("ack_timeout_seconds must be between 1 and %d", MaxAckTimeoutSeconds))
+	}
+	if len(errs) > 0 {
+		return nil, errors.New(strings.Join(errs, ", "))
+	}
+
+	return &EventConsumer{
+		Name:              name,
+		AckedSequence:     startSequence,
+		DeliveredSequence: startSequence,
+		AckTimeoutSeconds: ackTimeoutSeconds,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}, nil
+}
+
+// 確認を待っている配信があり、まだ期限前か。この間は次のイベントを配信しない
+func (c *EventConsumer) Leased(now time.Time) bool {
+	return c.DeliveredSequence > c.AckedSequence && c.AckDeadline != nil && now.Before(*c.AckDeadline)
+}
+
+// AckedSequence の後から読んだ events を配信したことを記録する。再配信の場合は true を返す
+// 期限内の配信があるかは呼び出し元で確かめる
+func (c *EventConsumer) Deliver(events []*StoredEvent, now time.Time) bool {
+	redelivered := c.DeliveredSequence > c.AckedSequence
+	c.UpdatedAt = now
+	if len(events) == 0 {
+		c.DeliveredSequence = c.AckedSequence
+		c.AckDeadline = nil
+		return false
+	}
+
+	if redelivered {
+		c.Redeliveries++
+	}
+	deadline := now.Add(time.Duration(c.AckTimeoutSeconds) * time.Second)
+	c.DeliveredSequence = events[len(events)-1].Sequence
+	c.AckDeadline = &deadline
+	return redelivered
+}
+
+// sequence までのイベントを処理済みにする。一部だけ確認してもよく、確認済みの連番は無視する
+func (c *EventConsumer) Ack(sequence int64, now time.Time) error {
+	if sequence <= c.AckedSequence {
+		return nil
+	}
+	if sequence > c.DeliveredSequence {
+		return fmt.Errorf("sequence %d has not been delivered (delivered up to %d)", sequence, c.DeliveredSequence)
+	}
+
+	c.AckedSequence = sequence
+	if c.AckedSequence == c.DeliveredSequence {
+		c.AckDeadline = nil
+	}
+	c.LastAckedAt = &now
+	c.UpdatedAt = now
+	return nil
+}
@@ -0,0 +1,67 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventConsumer(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	events := func(sequences ...int64) []*StoredEvent {
+		var stored []*StoredEvent
+		for _, sequence := range sequences {
+			stored = append(stored, &StoredEvent{Sequence: sequence})
+		}
+		return stored
+	}
+
+	t.Run("正常系: 配信したイベントは確認するまで期限内は貸し出し中", func(t *testing.T) {
+		c, err := NewEventConsumer("erp", 0, 0, now)
+		require.NoError(t, err)
+		assert.Equal(t, DefaultAckTimeoutSeconds, c.AckTimeoutSeconds)
+
+		assert.False(t, c.Deliver(events(1, 2, 3), now))
+		assert.Equal(t, int64(3), c.DeliveredSequence)
+		assert.Equal(t, now.Add(30*time.Second), *c.AckDeadline)
+		assert.True(t, c.Leased(now.Add(29*time.Second)))
+
+		require.NoError(t, c.Ack(2, now))
+		assert.Equal(t, int64(2), c.AckedSequence)
+		assert.True(t, c.Leased(now), "一部だけ確認した場合は貸し出し中のまま")
+
+		require.NoError(t, c.Ack(3, now))
+		assert.False(t, c.Leased(now))
+		assert.Nil(t, c.AckDeadline)
+		assert.NoError(t, c.Ack(1, now), "確認済みの連番は無視する")
+	})
+
+	t.Run("正常系: 期限までに確認されなければ再配信として数える", func(t *testing.T) {
+		c, _ := NewEventConsumer("erp", 0, 10, now)
+		c.Deliver(events(1, 2), now)
+		assert.False(t, c.Leased(now.Add(10*time.Second)))
+
+		assert.True(t, c.Deliver(events(1, 2, 3), now.Add(10*time.Second)))
+		assert.Equal(t, 1, c.Redeliveries)
+		assert.Equal(t, int64(3), c.DeliveredSequence)
+	})
+
+	t.Run("異常系: 配信していない連番は確認できない", func(t *testing.T) {
+		c, _ := NewEventConsumer("erp", 5, 0, now)
+		c.Deliver(events(6), now)
+
+		err := c.Ack(7, now)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "sequence 7 has not been delivered (delivered up to 6)")
+		assert.Equal(t, int64(5), c.AckedSequence)
+	})
+
+	t.Run("異常系: 名前と確認の期限", func(t *testing.T) {
+		_, err := NewEventConsumer("ERP system", 0, MaxAckTimeoutSeconds+1, now)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "name must be")
+		assert.Contains(t, err.Error(), "ack_timeout_seconds must be between 1 and 3600")
+	})
+}
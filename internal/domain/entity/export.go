@@ -0,0 +1,43 @@
+package entity
+
+import "time"
+
+// エクスポートでのレコードの変更の種類
+const (
+	ExportChangeCreated = "created"
+	ExportChangeUpdated = "updated"
+	ExportChangeDeleted = "deleted"
+)
+
+// エクスポートの形式
+const (
+	ExportFormatJSON    = "json"    // レコードをレスポンスで返す
+	ExportFormatParquet = "parquet" // テーブルごとの Parquet ファイルをファイルストアに書き出す
+)
+
+// エクスポートの記録（ブックマーク）
+// Watermark はエクスポートした時点のイベントストアの最後の連番で、次の差分エクスポートの起点になる
+type Export struct {
+	ID            int64     `json:"id"`
+	Watermark     int64     `json:"watermark"`
+	SinceExportID *int64    `json:"since_export_id,omitempty"` // 差分エクスポートの起点にしたエクスポート。全件の場合は nil
+	Format        string    `json:"format"`
+	RecordCount   int       `json:"record_count"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// エクスポートで書き出したファイル
+type ExportFile struct {
+	Table    string `json:"table"`
+	Location string `json:"location"` // ファイルストア上の場所
+	Rows     int    `json:"rows"`
+	Size     int64  `json:"size"`
+	SHA256   string `json:"sha256"`
+}
+
+// エクスポートする1レコード。削除の場合 Item は nil
+type ExportRecord struct {
+	Change string `json:"change"`
+	ItemID int64  `json:"item_id"`
+	Item   *Item  `json:"item,omitempty"`
+}
@@ -0,0 +1,125 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ロックできるフィールド（ChangedFields が返すフィールド）。サインオフで指定がなければすべてロックする
+var LockableFields = []string{"name", "category", "brand", "purchase_price", "purchase_date"}
+
+// 鑑定士がサインオフでロックしたフィールドのルール名
+const SignOffLockRule = "sign-off"
+
+// 組織あたりのルールの上限
+const MaxFieldLockRules = 20
+
+// ルールの条件にできる状態。アイテムがこの状態（またはその先の状態）になるとロックする
+var FieldLockStatuses = []string{VerificationInReview, VerificationVerified}
+
+// 状態の順序（未検証 → 検証中 → 検証済）
+var verificationOrder = []string{VerificationUnverified, VerificationInReview, VerificationVerified}
+
+// アイテムが Status になったら Fields を変更できなくするルール
+type FieldLockRule struct {
+	Name   string   `json:"name"`
+	Status string   `json:"status"`
+	Fields []string `json:"fields"`
+}
+
+// 組織のアイテムに適用するロックのルール
+type FieldLockPolicy struct {
+	OrgID     int64           `json:"organization_id"`
+	Rules     []FieldLockRule `json:"rules"`
+	UpdatedAt *time.Time      `json:"updated_at,omitempty"` // 設定していない場合は nil
+}
+
+// ロックされたフィールドと、ロックしたルール
+type FieldLock struct {
+	Field  string `json:"field"`
+	Rule   string `json:"rule"`
+	Status string `json:"status"`
+}
+
+func (l FieldLock) String() string {
+	return fmt.Sprintf("%s is locked by rule %q (%s)", l.Field, l.Rule, l.Status)
+}
+
+// まだルールを設定していない組織。サインオフのロックだけが適用される
+func NewFieldLockPolicy(orgID int64) *FieldLockPolicy {
+	return &FieldLockPolicy{OrgID: orgID, Rules: []FieldLockRule{}}
+}
+
+// ルールをすべて置き換える。名前は組織の中で一意
+func (p *FieldLockPolicy) Replace(rules []FieldLockRule, now time.Time) error {
+	var errs []string
+	if len(rules) > MaxFieldLockRules {
+		errs = append(errs, fmt.Sprintf("at most %d rules can be configured", MaxFieldLockRules))
+	}
+
+	normalized := make([]FieldLockRule, 0, len(rules))
+	seen := make(map[string]bool, len(rules))
+	for i, rule := range rules {
+		rule.Name = strings.TrimSpace(rule.Name)
+		switch {
+		case rule.Name == "":
+			errs = append(errs, fmt.Sprintf("rules[%d]: name is required", i))
+		case len(rule.Name) > 50:
+			errs = append(errs, fmt.Sprintf("rules[%d]: name must be 50 characters or less", i))
+		case rule.Name == SignOffLockRule:
+			errs = append(errs, fmt.Sprintf("rules[%d]: name %q is reserved", i, SignOffLockRule))
+		case seen[rule.Name]:
+			errs = append(errs, fmt.Sprintf("rules[%d]: name %q is used more than once", i, rule.Name))
+		}
+		seen[rule.Name] = true
+
+		if !slices.Contains(FieldLockStatuses, rule.Status) {
+			errs = append(errs, fmt.Sprintf("rules[%d]: status must be one of %s", i, strings.Join(FieldLockStatuses, ", ")))
+		}
+		if len(rule.Fields) == 0 {
+			errs = append(errs, fmt.Sprintf("rules[%d]: fields must not be empty", i))
+		}
+		for _, field := range rule.Fields {
+			if !slices.Contains(LockableFields, field) {
+				errs = append(errs, fmt.Sprintf("rules[%d]: %s cannot be locked", i, field))
+			}
+		}
+		fields := slices.Clone(rule.Fields)
+		slices.Sort(fields)
+		rule.Fields = slices.Compact(fields)
+		normalized = append(normalized, rule)
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, ", "))
+	}
+
+	p.Rules = normalized
+	p.UpdatedAt = &now
+	return nil
+}
+
+// 検証状態 v のアイテムに適用されるロック。サインオフでロックしたフィールドと、組織のルールでロックしたフィールド
+// policy が nil の場合（組織に属さないアイテム）はサインオフのロックだけを返す
+func FieldLocks(v *ItemVerification, policy *FieldLockPolicy) []FieldLock {
+	var locks []FieldLock
+	for _, field := range v.Locked() {
+		locks = append(locks, FieldLock{Field: field, Rule: SignOffLockRule, Status: VerificationVerified})
+	}
+	if policy == nil {
+		return locks
+	}
+
+	current := slices.Index(verificationOrder, v.Status)
+	for _, rule := range policy.Rules {
+		if current < slices.Index(verificationOrder, rule.Status) {
+			continue
+		}
+		for _, field := range rule.Fields {
+			locks = append(locks, FieldLock{Field: field, Rule: rule.Name, Status: rule.Status})
+		}
+	}
+	return locks
+}
@@ -0,0 +1,79 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFieldLockPolicy_Replace(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("正常系: ルールを置き換える", func(t *testing.T) {
+		policy := NewFieldLockPolicy(1)
+		err := policy.Replace([]FieldLockRule{
+			{Name: " price-after-verification ", Status: VerificationVerified, Fields: []string{"purchase_price", "brand", "purchase_price"}},
+		}, now)
+		require.NoError(t, err)
+		assert.Equal(t, []FieldLockRule{{Name: "price-after-verification", Status: VerificationVerified, Fields: []string{"brand", "purchase_price"}}}, policy.Rules)
+		assert.Equal(t, now, *policy.UpdatedAt)
+	})
+
+	t.Run("異常系: 名前の重複・予約された名前・知らない状態やフィールド", func(t *testing.T) {
+		policy := NewFieldLockPolicy(1)
+		err := policy.Replace([]FieldLockRule{
+			{Name: "a", Status: VerificationVerified, Fields: []string{"name"}},
+			{Name: "a", Status: "sold", Fields: []string{"owner_id"}},
+			{Name: SignOffLockRule, Status: VerificationInReview},
+		}, now)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `rules[1]: name "a" is used more than once`)
+		assert.Contains(t, err.Error(), "rules[1]: status must be one of in_review, verified")
+		assert.Contains(t, err.Error(), "rules[1]: owner_id cannot be locked")
+		assert.Contains(t, err.Error(), `rules[2]: name "sign-off" is reserved`)
+		assert.Contains(t, err.Error(), "rules[2]: fields must not be empty")
+		assert.Empty(t, policy.Rules)
+	})
+}
+
+func TestFieldLocks(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	policy := NewFieldLockPolicy(1)
+	require.NoError(t, policy.Replace([]FieldLockRule{
+		{Name: "name-in-review", Status: VerificationInReview, Fields: []string{"name"}},
+		{Name: "price-after-verification", Status: VerificationVerified, Fields: []string{"purchase_price"}},
+	}, now))
+
+	t.Run("正常系: 未検証のアイテムはロックされない", func(t *testing.T) {
+		assert.Empty(t, FieldLocks(NewItemVerification(1), policy))
+	})
+
+	t.Run("正常系: その状態になったルールだけが適用される", func(t *testing.T) {
+		v := NewItemVerification(1)
+		v.Start(5, now)
+		assert.Equal(t, []FieldLock{{Field: "name", Rule: "name-in-review", Status: VerificationInReview}}, FieldLocks(v, policy))
+	})
+
+	t.Run("正常系: 検証済みではサインオフのロックと、それまでの状態のルールも適用される", func(t *testing.T) {
+		v := NewItemVerification(1)
+		v.Start(5, now)
+		require.NoError(t, v.SignOff(5, "ok", 9, []string{"brand"}, now))
+
+		locks := FieldLocks(v, policy)
+		assert.Equal(t, []FieldLock{
+			{Field: "brand", Rule: SignOffLockRule, Status: VerificationVerified},
+			{Field: "name", Rule: "name-in-review", Status: VerificationInReview},
+			{Field: "purchase_price", Rule: "price-after-verification", Status: VerificationVerified},
+		}, locks)
+		assert.Equal(t, `purchase_price is locked by rule "price-after-verification" (verified)`, locks[2].String())
+	})
+
+	t.Run("正常系: 組織に属さないアイテムはサインオフのロックだけ", func(t *testing.T) {
+		v := NewItemVerification(1)
+		v.Start(5, now)
+		require.NoError(t, v.SignOff(5, "ok", 9, []string{"brand"}, now))
+		assert.Len(t, FieldLocks(v, nil), 1)
+	})
+}
@@ -0,0 +1,76 @@
+package entity
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// なりすましセッションの有効期間
+const (
+	DefaultImpersonationTTL = 30 * time.Minute
+	MaxImpersonationTTL     = 4 * time.Hour
+)
+
+// 管理者が他のユーザーとして操作するためのセッション
+// トークンは発行時にだけ返し、保存するのはハッシュ値のみ
+type ImpersonationSession struct {
+	ID        int64      `json:"id"`
+	Token     string     `json:"token,omitempty"`
+	TokenHash string     `json:"-"`
+	AdminID   int64      `json:"admin_id"`
+	UserID    int64      `json:"user_id"`
+	Reason    string     `json:"reason"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	EndedAt   *time.Time `json:"ended_at,omitempty"`
+}
+
+func NewImpersonationSession(adminID, userID int64, reason, token string, ttl time.Duration, now time.Time) (*ImpersonationSession, error) {
+	session := &ImpersonationSession{
+		Token:     token,
+		TokenHash: HashToken(token),
+		AdminID:   adminID,
+		UserID:    userID,
+		Reason:    strings.TrimSpace(reason),
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+	}
+
+	if err := session.validate(ttl); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+func (s *ImpersonationSession) validate(ttl time.Duration) error {
+	var errs []string
+
+	if s.UserID <= 0 {
+		errs = append(errs, "user id must be positive")
+	}
+	if s.AdminID == s.UserID {
+		errs = append(errs, "cannot impersonate yourself")
+	}
+	if s.Reason == "" {
+		errs = append(errs, "reason is required")
+	} else if len(s.Reason) > 500 {
+		errs = append(errs, "reason must be 500 characters or less")
+	}
+	if ttl <= 0 || ttl > MaxImpersonationTTL {
+		errs = append(errs, "ttl_minutes must be between 1 and 240")
+	}
+	if s.Token == "" {
+		errs = append(errs, "token must not be empty")
+	}
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// 期限内で、終了されていないセッションか
+func (s *ImpersonationSession) Active(now time.Time) bool {
+	return s.EndedAt == nil && now.Before(s.ExpiresAt)
+}
@@ -0,0 +1,101 @@
+package entity
+
+import "time"
+
+// 取り込みの置き場（ImportBatch）の状態
+const (
+	ImportBatchStaged    = "staged"    // 確認・編集中
+	ImportBatchCommitted = "committed" // アイテムとして登録済み
+)
+
+// 確定するまでアイテムとして登録しない取り込み。行を確認・編集してから確定する
+// 期限（ExpiresAt）までに確定しなかったものは破棄する
+type ImportBatch struct {
+	ID          int64      `json:"id"`
+	OwnerID     int64      `json:"owner_id"`
+	Filename    string     `json:"filename"`
+	ProfileID   *int64     `json:"profile_id,omitempty"` // 読み込みに使った取り込みプロファイル
+	Status      string     `json:"status"`
+	Rows        int        `json:"rows"`
+	InvalidRows int        `json:"invalid_rows"`
+	Created     int        `json:"created"` // 確定して登録したアイテムの数
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	CommittedAt *time.Time `json:"committed_at,omitempty"`
+}
+
+// 置き場を作る。期限は ttl 後で、編集するたびに延ばす
+func NewImportBatch(ownerID int64, filename string, profileID *int64, now time.Time, ttl time.Duration) *ImportBatch {
+	return &ImportBatch{
+		OwnerID:   ownerID,
+		Filename:  filename,
+		ProfileID: profileID,
+		Status:    ImportBatchStaged,
+		CreatedAt: now,
+		UpdatedAt: now,
+		ExpiresAt: now.Add(ttl),
+	}
+}
+
+func (b *ImportBatch) Staged() bool {
+	return b.Status == ImportBatchStaged
+}
+
+func (b *ImportBatch) Expired(now time.Time) bool {
+	return !now.Before(b.ExpiresAt)
+}
+
+// 編集したことを記録し、期限を延ばす
+func (b *ImportBatch) Touch(now time.Time, ttl time.Duration) {
+	b.UpdatedAt = now
+	b.ExpiresAt = now.Add(ttl)
+}
+
+// 確定したことを記録する。確定したものは期限まで結果を確認できる
+func (b *ImportBatch) Commit(created int, now time.Time) {
+	b.Status = ImportBatchCommitted
+	b.Created = created
+	b.InvalidRows = 0
+	b.UpdatedAt = now
+	b.CommittedAt = &now
+}
+
+// 置き場の 1 行。Row はファイル上の行番号で、置き場の中で行を指すのに使う
+type StagedImportRow struct {
+	BatchID       int64  `json:"-"`
+	Row           int    `json:"row"`
+	Name          string `json:"name"`
+	Category      string `json:"category"`
+	Brand         string `json:"brand"`
+	PurchasePrice int    `json:"purchase_price"`
+	PurchaseDate  string `json:"purchase_date"`
+	OrgID         *int64 `json:"organization_id,omitempty"`
+	// ファイルを読んだときの問題（数値でない価格など）。そのフィールドを編集すると消える
+	ReadErrors ValidationErrors `json:"-"`
+	// 最後に検証したときの問題。空の行だけを確定できる
+	Errors    ValidationErrors `json:"errors"`
+	UpdatedAt time.Time        `json:"updated_at"`
+}
+
+func (r *StagedImportRow) Valid() bool {
+	return len(r.Errors) == 0
+}
+
+// 編集したフィールドの読み込み時の問題を取り除く
+func (r *StagedImportRow) ClearReadErrors(fields ...string) {
+	kept := ValidationErrors{}
+	for _, problem := range r.ReadErrors {
+		cleared := false
+		for _, field := range fields {
+			if problem.Field == field {
+				cleared = true
+				break
+			}
+		}
+		if !cleared {
+			kept = append(kept, problem)
+		}
+	}
+	r.ReadErrors = kept
+}
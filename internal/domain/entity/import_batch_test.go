@@ -0,0 +1,41 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImportBatch_Expiry(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	batch := NewImportBatch(7, "items.csv", nil, now, 72*time.Hour)
+
+	t.Run("正常系: 期限までは確認・編集できる", func(t *testing.T) {
+		assert.True(t, batch.Staged())
+		assert.False(t, batch.Expired(now.Add(71*time.Hour)))
+		assert.True(t, batch.Expired(now.Add(72*time.Hour)))
+	})
+
+	t.Run("正常系: 編集すると期限が延びる", func(t *testing.T) {
+		batch.Touch(now.Add(70*time.Hour), 72*time.Hour)
+		assert.False(t, batch.Expired(now.Add(100*time.Hour)))
+	})
+
+	t.Run("正常系: 確定すると登録した数を記録する", func(t *testing.T) {
+		batch.Commit(3, now.Add(71*time.Hour))
+		assert.False(t, batch.Staged())
+		assert.Equal(t, 3, batch.Created)
+		assert.NotNil(t, batch.CommittedAt)
+	})
+}
+
+func TestStagedImportRow_ClearReadErrors(t *testing.T) {
+	row := &StagedImportRow{ReadErrors: ValidationErrors{
+		{Field: "purchase_price", Message: "purchase_price must be an integer"},
+		{Field: "purchase_date", Message: "purchase_date must be in the format DD.MM.YYYY"},
+	}}
+
+	row.ClearReadErrors("purchase_price", "name")
+	assert.Equal(t, ValidationErrors{{Field: "purchase_date", Message: "purchase_date must be in the format DD.MM.YYYY"}}, row.ReadErrors)
+}
@@ -0,0 +1,214 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// 取り込みのプロファイルの名前の最大長
+const MaxImportProfileNameLength = 100
+
+// 取り込みで値を入れられるアイテムのフィールド
+var ImportFields = []string{"name", "category", "brand", "purchase_price", "purchase_date", "organization_id"}
+
+// 仕入れ先ごとの CSV・Excel の形式。毎月届くファイルを列の対応づけなしに取り込めるよう、ユーザーごとに保存する
+type ImportProfile struct {
+	ID      int64  `json:"id"`
+	OwnerID int64  `json:"owner_id"`
+	Name    string `json:"name"`
+	// ファイルの列名 → アイテムのフィールド。列名は小文字にそろえて保存し、大文字小文字と前後の空白を区別しない
+	// 対応づけていない列は、列名をそのままフィールド名として扱う
+	Columns map[string]string `json:"columns"`
+	// purchase_date の書式（YYYY/MM/DD、DD.MM.YYYY など）。空の場合は YYYY-MM-DD
+	DateFormat string `json:"date_format,omitempty"`
+	// 列がない・空のときに使う値（フィールド → 値）。ファイルの値と同じ書式で書く
+	Defaults  map[string]string `json:"defaults,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+func NewImportProfile(ownerID int64, name string, columns map[string]string, dateFormat string, defaults map[string]string, now time.Time) (*ImportProfile, error) {
+	profile := &ImportProfile{
+		OwnerID:    ownerID,
+		Name:       strings.TrimSpace(name),
+		Columns:    NormalizeImportColumns(columns),
+		DateFormat: strings.TrimSpace(dateFormat),
+		Defaults:   defaults,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if err := profile.Validate(); err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+// 列名を小文字にそろえ、フィールド名の前後の空白を取り除く
+func NormalizeImportColumns(columns map[string]string) map[string]string {
+	normalized := make(map[string]string, len(columns))
+	for column, field := range columns {
+		normalized[normalizeImportColumn(column)] = strings.TrimSpace(field)
+	}
+	return normalized
+}
+
+func normalizeImportColumn(column string) string {
+	return strings.ToLower(strings.TrimSpace(column))
+}
+
+func (p *ImportProfile) Validate() error {
+	var errs []string
+
+	if p.Name == "" {
+		errs = append(errs, "name is required")
+	} else if len(p.Name) > MaxImportProfileNameLength {
+		errs = append(errs, "name must be 100 characters or less")
+	}
+
+	mapped := make(map[string]string, len(p.Columns))
+	for column, field := range p.Columns {
+		switch {
+		case column == "":
+			errs = append(errs, "column names must not be empty")
+		case !isImportField(field):
+			errs = append(errs, fmt.Sprintf("column %s is mapped to an unknown field: %s", column, field))
+		case mapped[field] != "":
+			errs = append(errs, fmt.Sprintf("field %s is mapped from more than one column", field))
+		default:
+			mapped[field] = column
+		}
+	}
+
+	if _, err := p.dateLayout(); err != nil {
+		errs = append(errs, err.Error())
+	}
+
+	for field, value := range p.Defaults {
+		if !isImportField(field) {
+			errs = append(errs, fmt.Sprintf("unknown default field: %s", field))
+			continue
+		}
+		if err := p.checkDefault(field, value); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+func (p *ImportProfile) checkDefault(field, value string) error {
+	switch field {
+	case "purchase_price":
+		if _, err := strconv.Atoi(value); err != nil {
+			return errors.New("default purchase_price must be an integer")
+		}
+	case "organization_id":
+		if id, err := strconv.ParseInt(value, 10, 64); err != nil || id <= 0 {
+			return errors.New("default organization_id must be a positive integer")
+		}
+	case "purchase_date":
+		if _, err := p.ParseDate(value); err != nil {
+			return fmt.Errorf("default purchase_date: %w", err)
+		}
+	}
+	return nil
+}
+
+func isImportField(field string) bool {
+	for _, f := range ImportFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// ファイルの列名に対応するフィールド。対応づけていない列は false
+func (p *ImportProfile) Field(column string) (string, bool) {
+	field, ok := p.Columns[normalizeImportColumn(column)]
+	return field, ok
+}
+
+// フィールドに対応づけたファイルの列名。対応づけていない場合はフィールド名を返す
+func (p *ImportProfile) Column(field string) string {
+	for column, f := range p.Columns {
+		if f == field {
+			return column
+		}
+	}
+	return field
+}
+
+// 列がない・空のときに使う値。ない場合は空
+func (p *ImportProfile) Default(field string) string {
+	return strings.TrimSpace(p.Defaults[field])
+}
+
+// プロファイルの書式の日付を YYYY-MM-DD に直す
+func (p *ImportProfile) ParseDate(value string) (string, error) {
+	layout, err := p.dateLayout()
+	if err != nil {
+		return "", err
+	}
+	date, err := time.Parse(layout, strings.TrimSpace(value))
+	if err != nil {
+		return "", fmt.Errorf("purchase_date must be in the format %s", p.dateFormat())
+	}
+	return date.Format("2006-01-02"), nil
+}
+
+func (p *ImportProfile) dateFormat() string {
+	if p.DateFormat == "" {
+		return "YYYY-MM-DD"
+	}
+	return p.DateFormat
+}
+
+// 日付の書式で使える記号と、対応する Go のレイアウト
+var dateFormatTokens = map[string]string{"YYYY": "2006", "YY": "06", "MM": "01", "M": "1", "DD": "02", "D": "2"}
+
+// 記号の間に使える区切り
+const dateFormatSeparators = "/-. 年月日"
+
+// 書式を time.Parse のレイアウトに直す。年・月・日を 1 つずつ含み、それ以外は区切りだけにする
+func (p *ImportProfile) dateLayout() (string, error) {
+	format := p.dateFormat()
+	invalid := fmt.Errorf("date_format must combine YYYY (or YY), MM (or M) and DD (or D) with separators: %s", format)
+
+	var layout strings.Builder
+	seen := map[byte]bool{}
+	for i := 0; i < len(format); {
+		c := format[i]
+		if c == 'Y' || c == 'M' || c == 'D' {
+			n := 1
+			for i+n < len(format) && format[i+n] == c {
+				n++
+			}
+			token, ok := dateFormatTokens[format[i:i+n]]
+			if !ok || seen[c] {
+				return "", invalid
+			}
+			seen[c] = true
+			layout.WriteString(token)
+			i += n
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(format[i:])
+		if !strings.ContainsRune(dateFormatSeparators, r) {
+			return "", invalid
+		}
+		layout.WriteString(format[i : i+size])
+		i += size
+	}
+	if len(seen) != 3 {
+		return "", invalid
+	}
+	return layout.String(), nil
+}
@@ -0,0 +1,93 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewImportProfile(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		columns    map[string]string
+		dateFormat string
+		defaults   map[string]string
+		wantErr    string
+	}{
+		{
+			name:       "正常系: 列名・日付の書式・既定値",
+			columns:    map[string]string{" Artikel ": "name", "Preis": "purchase_price"},
+			dateFormat: "DD.MM.YYYY",
+			defaults:   map[string]string{"category": "時計", "purchase_date": "01.01.2024"},
+		},
+		{
+			name:       "正常系: 年月日の区切り",
+			dateFormat: "YYYY年M月D日",
+		},
+		{
+			name:    "異常系: 知らないフィールド",
+			columns: map[string]string{"Farbe": "color"},
+			wantErr: "column farbe is mapped to an unknown field: color",
+		},
+		{
+			name:    "異常系: 1 つのフィールドに 2 つの列",
+			columns: map[string]string{"Artikel": "name", "Bezeichnung": "name"},
+			wantErr: "field name is mapped from more than one column",
+		},
+		{
+			name:       "異常系: 日付の書式に日がない",
+			dateFormat: "YYYY-MM",
+			wantErr:    "date_format must combine",
+		},
+		{
+			name:       "異常系: 日付の書式に時刻を含む",
+			dateFormat: "YYYY-MM-DD hh:mm",
+			wantErr:    "date_format must combine",
+		},
+		{
+			name:     "異常系: 既定の価格が数値でない",
+			defaults: map[string]string{"purchase_price": "free"},
+			wantErr:  "default purchase_price must be an integer",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile, err := NewImportProfile(7, "supplier A", tt.columns, tt.dateFormat, tt.defaults, now)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, int64(7), profile.OwnerID)
+		})
+	}
+}
+
+func TestImportProfile_Mapping(t *testing.T) {
+	profile, err := NewImportProfile(7, "supplier A", map[string]string{"Artikel": "name"}, "MM/DD/YY", map[string]string{"category": " 時計 "}, time.Now())
+	require.NoError(t, err)
+
+	t.Run("正常系: 列名は大文字小文字と空白を区別しない", func(t *testing.T) {
+		field, ok := profile.Field(" ARTIKEL")
+		assert.True(t, ok)
+		assert.Equal(t, "name", field)
+		assert.Equal(t, "artikel", profile.Column("name"))
+		assert.Equal(t, "時計", profile.Default("category"))
+	})
+
+	t.Run("正常系: 日付を YYYY-MM-DD に直す", func(t *testing.T) {
+		date, err := profile.ParseDate("01/15/24")
+		require.NoError(t, err)
+		assert.Equal(t, "2024-01-15", date)
+	})
+
+	t.Run("異常系: 書式に合わない日付", func(t *testing.T) {
+		_, err := profile.ParseDate("2024-01-15")
+		assert.EqualError(t, err, "purchase_date must be in the format MM/DD/YY")
+	})
+}
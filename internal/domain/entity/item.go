@@ -1,8 +1,9 @@
 package entity
 
 import (
-	"errors"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -15,20 +16,36 @@ type Item struct {
 	PurchaseDate  string    `json:"purchase_date"` // YYYY-MM-DD 形式
 	CreatedAt     time.Time `json:"created_at"`
 	UpdatedAt     time.Time `json:"updated_at"`
+	OrgID         *int64    `json:"organization_id,omitempty"` // 所属する組織（未設定の場合は nil）
+	OwnerID       *int64    `json:"owner_id,omitempty"`        // 持ち主のユーザー（持ち主のいないアイテムは nil）
+
+	Images       []ItemImage `json:"images,omitempty"`        // 画像（アイテムを1件取得したときだけ読み込む）
+	PrimaryImage *ItemImage  `json:"primary_image,omitempty"` // 一覧に表示する画像（一覧・検索・1件取得で読み込む）
+	Receipt      *Attachment `json:"receipt,omitempty"`       // 購入時のレシート（アイテムを1件取得したときだけ読み込む）
 }
 
-// カテゴリー定義
-var ValidCategories = []string{"時計", "バッグ", "ジュエリー", "靴", "その他"}
+// 組み込みのカテゴリー。参照データの categories を読み込むまでと、categories が 1 件もない場合に使う
+var DefaultCategories = []string{"時計", "バッグ", "ジュエリー", "靴", "その他"}
+
+var (
+	categoriesMu    sync.RWMutex
+	validCategories = DefaultCategories
+)
 
 func NewItem(name, category, brand string, purchasePrice int, purchaseDate string) (*Item, error) {
+	return NewItemAt(time.Now(), name, category, brand, purchasePrice, purchaseDate)
+}
+
+// 作成日時を指定してアイテムを作成する
+func NewItemAt(now time.Time, name, category, brand string, purchasePrice int, purchaseDate string) (*Item, error) {
 	item := &Item{
 		Name:          strings.TrimSpace(name),
 		Category:      strings.TrimSpace(category),
 		Brand:         strings.TrimSpace(brand),
 		PurchasePrice: purchasePrice,
 		PurchaseDate:  strings.TrimSpace(purchaseDate),
-		CreatedAt:     time.Now(),
-		UpdatedAt:     time.Now(),
+		CreatedAt:     now,
+		UpdatedAt:     now,
 	}
 
 	if err := item.Validate(); err != nil {
@@ -39,44 +56,62 @@ func NewItem(name, category, brand string, purchasePrice int, purchaseDate strin
 }
 
 // アイテムフィールドのバリデーション
+// 問題がある場合は ValidationErrors を返す
 func (i *Item) Validate() error {
-	var errs []string
+	var errs ValidationErrors
 
 	if i.Name == "" {
-		errs = append(errs, "name is required")
+		errs = append(errs, FieldError{"name", "name is required"})
 	} else if len(i.Name) > 100 {
-		errs = append(errs, "name must be 100 characters or less")
+		errs = append(errs, FieldError{"name", "name must be 100 characters or less"})
 	}
 
 	if i.Category == "" {
-		errs = append(errs, "category is required")
+		errs = append(errs, FieldError{"category", "category is required"})
 	} else if !isValidCategory(i.Category) {
-		errs = append(errs, "category must be one of: 時計, バッグ, ジュエリー, 靴, その他")
+		errs = append(errs, FieldError{"category", "category must be one of: " + strings.Join(GetValidCategories(), ", ")})
 	}
 
 	if i.Brand == "" {
-		errs = append(errs, "brand is required")
+		errs = append(errs, FieldError{"brand", "brand is required"})
 	} else if len(i.Brand) > 100 {
-		errs = append(errs, "brand must be 100 characters or less")
+		errs = append(errs, FieldError{"brand", "brand must be 100 characters or less"})
 	}
 
 	if i.PurchasePrice < 0 {
-		errs = append(errs, "purchase_price must be 0 or greater")
+		errs = append(errs, FieldError{"purchase_price", "purchase_price must be 0 or greater"})
 	}
 
 	if i.PurchaseDate == "" {
-		errs = append(errs, "purchase_date is required")
+		errs = append(errs, FieldError{"purchase_date", "purchase_date is required"})
 	} else if !isValidDateFormat(i.PurchaseDate) {
-		errs = append(errs, "purchase_date must be in YYYY-MM-DD format")
+		errs = append(errs, FieldError{"purchase_date", "purchase_date must be in YYYY-MM-DD format"})
 	}
 
 	if len(errs) > 0 {
-		return errors.New(strings.Join(errs, ", "))
+		return errs
 	}
 
 	return nil
 }
 
+// 入力の問題と、そのフィールド
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"error"`
+}
+
+// フィールドごとの入力の問題。メッセージは ", " で連結する
+type ValidationErrors []FieldError
+
+func (e ValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, fe := range e {
+		messages[i] = fe.Message
+	}
+	return strings.Join(messages, ", ")
+}
+
 // アイテムフィールドのアップデート
 func (i *Item) Update(name, category, brand string, purchasePrice int, purchaseDate string) error {
 	i.Name = strings.TrimSpace(name)
@@ -91,6 +126,11 @@ func (i *Item) Update(name, category, brand string, purchasePrice int, purchaseD
 
 // アイテムフィールドの部分更新（name、brand、purchase_price のみ）
 func (i *Item) PartialUpdate(name, brand *string, purchasePrice *int) error {
+	return i.PartialUpdateAt(time.Now(), name, brand, purchasePrice)
+}
+
+// 更新日時を指定して部分更新する
+func (i *Item) PartialUpdateAt(now time.Time, name, brand *string, purchasePrice *int) error {
 	if name != nil {
 		i.Name = strings.TrimSpace(*name)
 	}
@@ -100,19 +140,40 @@ func (i *Item) PartialUpdate(name, brand *string, purchasePrice *int) error {
 	if purchasePrice != nil {
 		i.PurchasePrice = *purchasePrice
 	}
-	i.UpdatedAt = time.Now()
+	i.UpdatedAt = now
 
 	return i.Validate()
 }
 
+// 変更を監視できるフィールド
+var ItemWatchableFields = []string{"name", "category", "brand", "purchase_price", "purchase_date"}
+
+// 2つの状態で値が異なるフィールド名を返す
+func ChangedFields(before, after *Item) []string {
+	var changed []string
+	if before.Name != after.Name {
+		changed = append(changed, "name")
+	}
+	if before.Category != after.Category {
+		changed = append(changed, "category")
+	}
+	if before.Brand != after.Brand {
+		changed = append(changed, "brand")
+	}
+	if before.PurchasePrice != after.PurchasePrice {
+		changed = append(changed, "purchase_price")
+	}
+	if before.PurchaseDate != after.PurchaseDate {
+		changed = append(changed, "purchase_date")
+	}
+	return changed
+}
+
 // カテゴリーのバリデーション
 func isValidCategory(category string) bool {
-	for _, valid := range ValidCategories {
-		if category == valid {
-			return true
-		}
-	}
-	return false
+	categoriesMu.RLock()
+	defer categoriesMu.RUnlock()
+	return slices.Contains(validCategories, category)
 }
 
 // デート形式のバリデーション
@@ -123,5 +184,17 @@ func isValidDateFormat(dateStr string) bool {
 
 // カテゴリーの取得
 func GetValidCategories() []string {
-	return ValidCategories
+	categoriesMu.RLock()
+	defer categoriesMu.RUnlock()
+	return slices.Clone(validCategories)
+}
+
+// 参照データから読み込んだカテゴリーに置き換える。空の場合は組み込みのカテゴリーに戻す
+func SetValidCategories(categories []string) {
+	if len(categories) == 0 {
+		categories = DefaultCategories
+	}
+	categoriesMu.Lock()
+	defer categoriesMu.Unlock()
+	validCategories = slices.Clone(categories)
 }
@@ -0,0 +1,82 @@
+package entity
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"time"
+)
+
+// サムネイルの生成状況
+const (
+	ThumbnailNone        = "none"        // 未生成（サムネイルの機能より前にアップロードした画像）
+	ThumbnailPending     = "pending"     // 生成中
+	ThumbnailReady       = "ready"       // 生成済み
+	ThumbnailFailed      = "failed"      // 生成に失敗した
+	ThumbnailUnsupported = "unsupported" // 生成できない形式（WebP）
+)
+
+// 生成するサムネイルの大きさ。長辺を MaxEdge に収める
+type ThumbnailSize struct {
+	Name    string
+	MaxEdge int
+}
+
+var ThumbnailSizes = []ThumbnailSize{
+	{Name: "small", MaxEdge: 200},
+	{Name: "medium", MaxEdge: 800},
+}
+
+// 画像を縮小したサムネイル。ファイルは元の画像のキーから決まる
+type ImageThumbnail struct {
+	Size        string `json:"size"`
+	ContentType string `json:"content_type"`
+	Key         string `json:"-"`
+	URL         string `json:"url"`
+}
+
+// アイテムの画像。ファイルはファイルストアに画像ごとに保存する
+type ItemImage struct {
+	ID          int64     `json:"id"`
+	ItemID      int64     `json:"item_id"`
+	Filename    string    `json:"filename"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	Key         string    `json:"-"`             // ファイルストア上のキー
+	URL         string    `json:"url,omitempty"` // 直接取得する URL（事前署名 URL・CDN を使わない場合は空）
+	CreatedAt   time.Time `json:"created_at"`
+
+	Position  int  `json:"position"`   // ギャラリーでの順番（0 から）
+	IsPrimary bool `json:"is_primary"` // 一覧に表示する画像。画像があるアイテムには 1 枚だけある
+
+	ThumbnailStatus string           `json:"thumbnail_status"`
+	Thumbnails      []ImageThumbnail `json:"thumbnails,omitempty"` // 生成済みの場合だけ含める
+}
+
+// 大きさの名前からサムネイルを探す。生成済みでない場合は nil
+func (i *ItemImage) Thumbnail(size string) *ImageThumbnail {
+	for j := range i.Thumbnails {
+		if i.Thumbnails[j].Size == size {
+			return &i.Thumbnails[j]
+		}
+	}
+	return nil
+}
+
+// 受け付ける画像の形式と、ファイルストア上のキーに付ける拡張子
+var ImageExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+// ファイルストア上の画像のキー。name は推測できないランダムな文字列にする
+func ItemImageBlobKey(itemID int64, name, contentType string) string {
+	return fmt.Sprintf("images/items/%d/%s%s", itemID, name, ImageExtensions[contentType])
+}
+
+// サムネイルのキー。元の画像のキーの拡張子の前に大きさの名前を付ける
+func ItemImageThumbnailKey(imageKey, size, contentType string) string {
+	return fmt.Sprintf("%s_%s%s", strings.TrimSuffix(imageKey, path.Ext(imageKey)), size, ImageExtensions[contentType])
+}
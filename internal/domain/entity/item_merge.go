@@ -0,0 +1,62 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// 統合時にどちらのアイテムの値を残すか
+const (
+	MergeKeepTarget = "target" // 統合先（残るアイテム）の値
+	MergeKeepSource = "source" // 統合元（削除されるアイテム）の値
+)
+
+// 統合元の値を取り込んで重複したアイテムを1つにまとめる
+// choices はフィールド名から MergeKeepTarget / MergeKeepSource への対応で、指定のないフィールドは統合先の値を残す
+func (i *Item) MergeFrom(source *Item, choices map[string]string, now time.Time) error {
+	if source.ID == i.ID {
+		return errors.New("cannot merge an item into itself")
+	}
+
+	var errs []string
+	fields := make([]string, 0, len(choices))
+	for field := range choices {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		if !slices.Contains(ItemWatchableFields, field) {
+			errs = append(errs, fmt.Sprintf("unknown merge field: %s", field))
+		} else if choice := choices[field]; choice != MergeKeepTarget && choice != MergeKeepSource {
+			errs = append(errs, fmt.Sprintf("%s must be %q or %q", field, MergeKeepTarget, MergeKeepSource))
+		}
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, ", "))
+	}
+
+	for field, choice := range choices {
+		if choice != MergeKeepSource {
+			continue
+		}
+		switch field {
+		case "name":
+			i.Name = source.Name
+		case "category":
+			i.Category = source.Category
+		case "brand":
+			i.Brand = source.Brand
+		case "purchase_price":
+			i.PurchasePrice = source.PurchasePrice
+		case "purchase_date":
+			i.PurchaseDate = source.PurchaseDate
+		}
+	}
+	i.UpdatedAt = now
+
+	return i.Validate()
+}
@@ -0,0 +1,144 @@
+package entity
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"Aicon-assignment/internal/pkg/filter"
+	"Aicon-assignment/internal/pkg/listquery"
+)
+
+// ?filter= で使えるアイテムのフィールド
+var ItemFilterFields = filter.Fields{
+	"name":           filter.String,
+	"category":       filter.String,
+	"brand":          filter.String,
+	"purchase_price": filter.Number,
+	"purchase_date":  filter.Date,
+}
+
+// GET /items で使える絞り込み・並び替え
+var ItemListSpec = listquery.Spec{
+	Filters:    ItemFilterFields,
+	Shorthands: []string{"category", "brand"},
+	Bounds: []listquery.Bound{
+		{Param: "min_price", Field: "purchase_price", Op: "gte"},
+		{Param: "max_price", Field: "purchase_price", Op: "lte"},
+		{Param: "purchased_from", Field: "purchase_date", Op: "gte"},
+		{Param: "purchased_to", Field: "purchase_date", Op: "lte"},
+	},
+	Sorts:       []string{"id", "name", "category", "brand", "purchase_price", "purchase_date", "created_at", "updated_at"},
+	DefaultSort: []listquery.SortField{{Field: "created_at", Desc: true}},
+	Cursor:      true,
+}
+
+// カーソルで指定した場合の並び。作成日時が同じアイテムは ID で順序を決める
+var ItemCursorSort = []listquery.SortField{{Field: "created_at", Desc: true}, {Field: "id", Desc: true}}
+
+// アイテム一覧の取得条件
+type ItemQuery struct {
+	Filter filter.Expr // nil の場合は絞り込まない
+	Sort   []listquery.SortField
+	Limit  int // 0 の場合は全件
+	Offset int
+	After  *ItemCursor // 指定した場合は ItemCursorSort の並びでこの位置より後のみ
+}
+
+// 一覧クエリからリポジトリ向けの取得条件を作る
+func NewItemQuery(q listquery.Query) (ItemQuery, error) {
+	if !q.Page.CursorMode {
+		return ItemQuery{
+			Filter: q.Filter,
+			Sort:   q.Sort,
+			Limit:  q.Page.Size,
+			Offset: q.Page.Offset(),
+		}, nil
+	}
+
+	query := ItemQuery{Filter: q.Filter, Sort: ItemCursorSort, Limit: q.Page.Size}
+	if q.Page.Cursor != "" {
+		after, err := DecodeItemCursor(q.Page.Cursor)
+		if err != nil {
+			return ItemQuery{}, err
+		}
+		query.After = after
+	}
+	return query, nil
+}
+
+// 一覧の続きを取得するための位置（最後に返したアイテムの作成日時とID）
+type ItemCursor struct {
+	CreatedAt time.Time
+	ID        int64
+}
+
+func NewItemCursor(item *Item) *ItemCursor {
+	return &ItemCursor{CreatedAt: item.CreatedAt, ID: item.ID}
+}
+
+// クライアントには中身を意識させないよう、base64 で不透明な文字列にする
+func (c *ItemCursor) Encode() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d:%d", c.CreatedAt.UnixNano(), c.ID)))
+}
+
+func DecodeItemCursor(s string) (*ItemCursor, error) {
+	invalid := errors.New("cursor is invalid")
+
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, invalid
+	}
+	rawNanos, rawID, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return nil, invalid
+	}
+	nanos, err := strconv.ParseInt(rawNanos, 10, 64)
+	if err != nil {
+		return nil, invalid
+	}
+	id, err := strconv.ParseInt(rawID, 10, 64)
+	if err != nil || id <= 0 {
+		return nil, invalid
+	}
+	return &ItemCursor{CreatedAt: time.Unix(0, nanos).UTC(), ID: id}, nil
+}
+
+// ItemCursorSort の並びで、アイテムがカーソルの位置より後にあるか
+func (c *ItemCursor) Precedes(item *Item) bool {
+	if item.CreatedAt.Equal(c.CreatedAt) {
+		return item.ID < c.ID
+	}
+	return item.CreatedAt.Before(c.CreatedAt)
+}
+
+// 条件式の評価・並び替えに使うフィールドの値
+func (i *Item) FilterValue(field string) any {
+	switch field {
+	case "id":
+		return i.ID
+	case "name":
+		return i.Name
+	case "category":
+		return i.Category
+	case "brand":
+		return i.Brand
+	case "purchase_price":
+		return int64(i.PurchasePrice)
+	case "purchase_date":
+		return i.PurchaseDate
+	case "created_at":
+		return i.CreatedAt.UnixNano()
+	case "updated_at":
+		return i.UpdatedAt.UnixNano()
+	}
+	return nil
+}
+
+// メモリ上のアイテムが条件に一致するか（カーソルの位置は含めない）
+func (q ItemQuery) Matches(item *Item) bool {
+	return q.Filter == nil || filter.Evaluate(q.Filter, item.FilterValue)
+}
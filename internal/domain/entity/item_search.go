@@ -0,0 +1,49 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	DefaultSearchLimit = 20
+	MaxSearchLimit     = 100
+
+	// 長すぎるキーワードで LIKE が重くならないよう制限する
+	MaxSearchKeywordLength = 100
+)
+
+// 名前・ブランドのキーワード検索の条件
+type ItemSearch struct {
+	Keyword string
+	Limit   int
+}
+
+// キーワードは前後の空白を除いて検証する。limit が 0 の場合は DefaultSearchLimit
+func NewItemSearch(keyword string, limit int) (ItemSearch, error) {
+	keyword = strings.TrimSpace(keyword)
+	if keyword == "" {
+		return ItemSearch{}, errors.New("q is required")
+	}
+	if utf8.RuneCountInString(keyword) > MaxSearchKeywordLength {
+		return ItemSearch{}, fmt.Errorf("q must be %d characters or less", MaxSearchKeywordLength)
+	}
+
+	if limit == 0 {
+		limit = DefaultSearchLimit
+	}
+	if limit < 1 || limit > MaxSearchLimit {
+		return ItemSearch{}, fmt.Errorf("limit must be between 1 and %d", MaxSearchLimit)
+	}
+
+	return ItemSearch{Keyword: keyword, Limit: limit}, nil
+}
+
+// 名前かブランドにキーワードを含むか（大文字小文字を区別しない）
+func (s ItemSearch) Matches(item *Item) bool {
+	keyword := strings.ToLower(s.Keyword)
+	return strings.Contains(strings.ToLower(item.Name), keyword) ||
+		strings.Contains(strings.ToLower(item.Brand), keyword)
+}
@@ -0,0 +1,61 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// 分割後のアイテム。category / brand / purchase_date を省略した場合は元のアイテムの値を使う
+type SplitComponent struct {
+	Name          string `json:"name"`
+	Category      string `json:"category"`
+	Brand         string `json:"brand"`
+	PurchasePrice int    `json:"purchase_price"`
+	PurchaseDate  string `json:"purchase_date"`
+}
+
+// 1つとして登録されたアイテムを複数のアイテムに分ける
+// 購入価格の配分の合計は元のアイテムの購入価格と一致しなければならない
+func (i *Item) Split(components []SplitComponent, now time.Time) ([]*Item, error) {
+	if len(components) < 2 {
+		return nil, errors.New("at least two components are required")
+	}
+
+	var errs []string
+	items := make([]*Item, 0, len(components))
+	total := 0
+	for n, component := range components {
+		item, err := NewItemAt(now,
+			component.Name,
+			orDefault(component.Category, i.Category),
+			orDefault(component.Brand, i.Brand),
+			component.PurchasePrice,
+			orDefault(component.PurchaseDate, i.PurchaseDate),
+		)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("components[%d]: %s", n, err.Error()))
+			continue
+		}
+		item.OrgID = i.OrgID
+		items = append(items, item)
+		total += component.PurchasePrice
+	}
+	if len(errs) > 0 {
+		return nil, errors.New(strings.Join(errs, ", "))
+	}
+
+	if total != i.PurchasePrice {
+		return nil, fmt.Errorf("purchase prices of components must sum to %d, got %d", i.PurchasePrice, total)
+	}
+
+	return items, nil
+}
+
+func orDefault(value, fallback string) string {
+	if strings.TrimSpace(value) == "" {
+		return fallback
+	}
+	return value
+}
@@ -1,6 +1,7 @@
 package entity
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -157,6 +158,20 @@ func TestNewItem(t *testing.T) {
 	}
 }
 
+func TestNewItemAt(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	item, err := NewItemAt(now, "ロレックス デイトナ", "時計", "ROLEX", 1500000, "2023-01-15")
+	require.NoError(t, err)
+	assert.Equal(t, now, item.CreatedAt)
+	assert.Equal(t, now, item.UpdatedAt)
+
+	later := now.Add(time.Hour)
+	require.NoError(t, item.PartialUpdateAt(later, strPtr("デイトナ"), nil, nil))
+	assert.Equal(t, now, item.CreatedAt)
+	assert.Equal(t, later, item.UpdatedAt)
+}
+
 func TestItem_Update(t *testing.T) {
 	// 初期アイテムを作成
 	item, err := NewItem("初期アイテム", "時計", "初期ブランド", 100000, "2023-01-01")
@@ -476,3 +491,123 @@ func strPtr(s string) *string {
 func intPtr(i int) *int {
 	return &i
 }
+
+func TestChangedFields(t *testing.T) {
+	before, _ := NewItem("時計1", "時計", "ROLEX", 1000000, "2023-01-01")
+	after := *before
+	after.Brand = "OMEGA"
+	after.PurchasePrice = 1200000
+
+	assert.Equal(t, []string{"brand", "purchase_price"}, ChangedFields(before, &after))
+	assert.Empty(t, ChangedFields(before, before))
+}
+
+func TestItem_MergeFrom(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	newItems := func() (*Item, *Item) {
+		target, _ := NewItem("デイトナ", "時計", "ROLEX", 1000000, "2023-01-01")
+		target.ID = 1
+		source, _ := NewItem("デイトナ 116500LN", "時計", "ROLEX", 1500000, "2022-12-24")
+		source.ID = 2
+		return target, source
+	}
+
+	t.Run("正常系: source を指定したフィールドだけ取り込む", func(t *testing.T) {
+		target, source := newItems()
+		err := target.MergeFrom(source, map[string]string{"name": MergeKeepSource, "purchase_price": MergeKeepTarget, "purchase_date": MergeKeepSource}, now)
+		assert.NoError(t, err)
+		assert.Equal(t, "デイトナ 116500LN", target.Name)
+		assert.Equal(t, 1000000, target.PurchasePrice)
+		assert.Equal(t, "2022-12-24", target.PurchaseDate)
+		assert.Equal(t, now, target.UpdatedAt)
+	})
+
+	t.Run("異常系: 不正な指定", func(t *testing.T) {
+		target, source := newItems()
+		err := target.MergeFrom(source, map[string]string{"color": MergeKeepSource, "name": "both"}, now)
+		assert.EqualError(t, err, `unknown merge field: color, name must be "target" or "source"`)
+	})
+
+	t.Run("異常系: 自分自身との統合", func(t *testing.T) {
+		target, _ := newItems()
+		assert.Error(t, target.MergeFrom(target, nil, now))
+	})
+}
+
+func TestItem_Split(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	original, _ := NewItem("サブマリーナ ブレス付き", "時計", "ROLEX", 1200000, "2023-01-01")
+	original.ID = 1
+
+	t.Run("正常系: 省略した項目は元のアイテムの値を使う", func(t *testing.T) {
+		items, err := original.Split([]SplitComponent{
+			{Name: "サブマリーナ", PurchasePrice: 1000000},
+			{Name: "社外ブレスレット", Brand: "その他ブランド", Category: "その他", PurchasePrice: 200000},
+		}, now)
+		require.NoError(t, err)
+		require.Len(t, items, 2)
+		assert.Equal(t, "ROLEX", items[0].Brand)
+		assert.Equal(t, "時計", items[0].Category)
+		assert.Equal(t, "2023-01-01", items[1].PurchaseDate)
+		assert.Equal(t, "その他", items[1].Category)
+	})
+
+	t.Run("異常系: 配分の合計が元の価格と一致しない", func(t *testing.T) {
+		_, err := original.Split([]SplitComponent{
+			{Name: "サブマリーナ", PurchasePrice: 1000000},
+			{Name: "社外ブレスレット", PurchasePrice: 100000},
+		}, now)
+		assert.EqualError(t, err, "purchase prices of components must sum to 1200000, got 1100000")
+	})
+
+	t.Run("異常系: 分割後が1つだけ", func(t *testing.T) {
+		_, err := original.Split([]SplitComponent{{Name: "サブマリーナ", PurchasePrice: 1200000}}, now)
+		assert.EqualError(t, err, "at least two components are required")
+	})
+
+	t.Run("異常系: 不正な分割後のアイテム", func(t *testing.T) {
+		_, err := original.Split([]SplitComponent{
+			{Name: "", PurchasePrice: 1000000},
+			{Name: "社外ブレスレット", PurchasePrice: 200000},
+		}, now)
+		assert.ErrorContains(t, err, "components[0]:")
+	})
+}
+
+func TestItemCursor(t *testing.T) {
+	createdAt := time.Date(2024, 1, 15, 10, 0, 0, 123, time.UTC)
+	cursor := NewItemCursor(&Item{ID: 42, CreatedAt: createdAt})
+
+	t.Run("正常系: エンコードした値から元の位置に戻せる", func(t *testing.T) {
+		decoded, err := DecodeItemCursor(cursor.Encode())
+		require.NoError(t, err)
+		assert.True(t, createdAt.Equal(decoded.CreatedAt))
+		assert.Equal(t, int64(42), decoded.ID)
+	})
+
+	t.Run("正常系: 作成日時の降順・同じ日時ならIDの降順で後ろにあるか", func(t *testing.T) {
+		assert.True(t, cursor.Precedes(&Item{ID: 50, CreatedAt: createdAt.Add(-time.Second)}))
+		assert.True(t, cursor.Precedes(&Item{ID: 41, CreatedAt: createdAt}))
+		assert.False(t, cursor.Precedes(&Item{ID: 42, CreatedAt: createdAt}))
+		assert.False(t, cursor.Precedes(&Item{ID: 1, CreatedAt: createdAt.Add(time.Second)}))
+	})
+
+	t.Run("異常系: 不正なカーソル", func(t *testing.T) {
+		for _, raw := range []string{"!!!", "MTIz", cursor.Encode() + "x"} {
+			_, err := DecodeItemCursor(raw)
+			assert.EqualError(t, err, "cursor is invalid", raw)
+		}
+	})
+}
+
+func TestItemSearch_Matches(t *testing.T) {
+	search, err := NewItemSearch("rolex", 0)
+	require.NoError(t, err)
+
+	assert.True(t, search.Matches(&Item{Name: "デイトナ", Brand: "ROLEX"}), "ブランドは大文字小文字を区別しない")
+	assert.True(t, search.Matches(&Item{Name: "Rolex 箱", Brand: "その他"}), "名前の部分一致")
+	assert.False(t, search.Matches(&Item{Name: "スピードマスター", Brand: "OMEGA"}))
+
+	_, err = NewItemSearch(strings.Repeat("あ", MaxSearchKeywordLength+1), 0)
+	assert.EqualError(t, err, "q must be 100 characters or less")
+}
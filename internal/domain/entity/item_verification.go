@@ -0,0 +1,104 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// アイテムの検証状態（未検証 → 検証中 → 検証済）
+const (
+	VerificationUnverified = "unverified" // 未検証
+	VerificationInReview   = "in_review"  // 検証中。鑑定士が確認している
+	VerificationVerified   = "verified"   // 検証済。検証したフィールドは変更できない
+)
+
+// アイテムの検証状態。鑑定士が鑑定書を添えてサインオフすると検証済みになり、
+// LockedFields のフィールドは管理者が検証をやり直すまで変更できない
+type ItemVerification struct {
+	ItemID        int64      `json:"item_id"`
+	Status        string     `json:"status"`
+	AppraiserID   *int64     `json:"appraiser_id,omitempty"`   // 検証を担当している（した）鑑定士
+	Notes         string     `json:"notes,omitempty"`          // 鑑定士の所見
+	CertificateID *int64     `json:"certificate_id,omitempty"` // 鑑定書の添付ファイル
+	LockedFields  []string   `json:"locked_fields"`
+	StartedAt     *time.Time `json:"started_at,omitempty"`
+	VerifiedAt    *time.Time `json:"verified_at,omitempty"`
+	UpdatedAt     *time.Time `json:"updated_at,omitempty"`
+}
+
+// まだ検証を始めていないアイテムの状態
+func NewItemVerification(itemID int64) *ItemVerification {
+	return &ItemVerification{ItemID: itemID, Status: VerificationUnverified, LockedFields: []string{}}
+}
+
+func (v *ItemVerification) Verified() bool {
+	return v.Status == VerificationVerified
+}
+
+// 変更できないフィールド。検証済みでなければ空
+func (v *ItemVerification) Locked() []string {
+	if !v.Verified() {
+		return nil
+	}
+	return v.LockedFields
+}
+
+// 鑑定士が検証を始める（未検証 → 検証中）。状態は呼び出し元で確かめる
+func (v *ItemVerification) Start(appraiserID int64, now time.Time) {
+	v.Status = VerificationInReview
+	v.AppraiserID = &appraiserID
+	v.StartedAt = &now
+	v.UpdatedAt = &now
+}
+
+// 鑑定士が所見と鑑定書を添えて検証済みにする（検証中 → 検証済）
+// fields が空の場合は LockableFields をすべてロックする。状態は呼び出し元で確かめる
+func (v *ItemVerification) SignOff(appraiserID int64, notes string, certificateID int64, fields []string, now time.Time) error {
+	var errs []string
+	notes = strings.TrimSpace(notes)
+	if notes == "" {
+		errs = append(errs, "notes is required")
+	} else if len(notes) > 2000 {
+		errs = append(errs, "notes must be 2000 characters or less")
+	}
+	if certificateID <= 0 {
+		errs = append(errs, "certificate_id is required")
+	}
+	if len(fields) == 0 {
+		fields = LockableFields
+	}
+	for _, field := range fields {
+		if !slices.Contains(LockableFields, field) {
+			errs = append(errs, fmt.Sprintf("%s cannot be locked", field))
+		}
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, ", "))
+	}
+
+	locked := slices.Clone(fields)
+	slices.Sort(locked)
+	v.Status = VerificationVerified
+	v.AppraiserID = &appraiserID
+	v.Notes = notes
+	v.CertificateID = &certificateID
+	v.LockedFields = slices.Compact(locked)
+	v.VerifiedAt = &now
+	v.UpdatedAt = &now
+	return nil
+}
+
+// 管理者が検証をやり直す（検証中・検証済 → 未検証）。ロックは解除される
+func (v *ItemVerification) Reopen(now time.Time) {
+	v.Status = VerificationUnverified
+	v.AppraiserID = nil
+	v.Notes = ""
+	v.CertificateID = nil
+	v.LockedFields = []string{}
+	v.StartedAt = nil
+	v.VerifiedAt = nil
+	v.UpdatedAt = &now
+}
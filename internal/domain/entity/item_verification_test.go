@@ -0,0 +1,58 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestItemVerification_Workflow(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("正常系: 検証を始めてサインオフすると指定したフィールドがロックされる", func(t *testing.T) {
+		v := NewItemVerification(1)
+		assert.Empty(t, v.Locked())
+
+		v.Start(5, now)
+		assert.Equal(t, VerificationInReview, v.Status)
+		assert.Empty(t, v.Locked())
+
+		require.NoError(t, v.SignOff(5, " 刻印を確認 ", 9, []string{"purchase_price", "brand", "brand"}, now.Add(time.Hour)))
+		assert.True(t, v.Verified())
+		assert.Equal(t, "刻印を確認", v.Notes)
+		assert.Equal(t, int64(9), *v.CertificateID)
+		assert.Equal(t, []string{"brand", "purchase_price"}, v.Locked())
+	})
+
+	t.Run("正常系: フィールドを指定しなければすべてロックされる", func(t *testing.T) {
+		v := NewItemVerification(1)
+		v.Start(5, now)
+		require.NoError(t, v.SignOff(5, "ok", 9, nil, now))
+		assert.ElementsMatch(t, LockableFields, v.Locked())
+	})
+
+	t.Run("正常系: やり直すと未検証に戻りロックが外れる", func(t *testing.T) {
+		v := NewItemVerification(1)
+		v.Start(5, now)
+		require.NoError(t, v.SignOff(5, "ok", 9, nil, now))
+
+		v.Reopen(now.Add(time.Hour))
+		assert.Equal(t, VerificationUnverified, v.Status)
+		assert.Nil(t, v.CertificateID)
+		assert.Empty(t, v.Locked())
+	})
+
+	t.Run("異常系: 所見・鑑定書がない、ロックできないフィールドを指定した", func(t *testing.T) {
+		v := NewItemVerification(1)
+		v.Start(5, now)
+
+		err := v.SignOff(5, " ", 0, []string{"organization_id"}, now)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "notes is required")
+		assert.Contains(t, err.Error(), "certificate_id is required")
+		assert.Contains(t, err.Error(), "organization_id cannot be locked")
+		assert.Equal(t, VerificationInReview, v.Status)
+	})
+}
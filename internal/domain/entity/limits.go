@@ -0,0 +1,46 @@
+package entity
+
+// サーバーが適用している上限。クライアントが値を決め打ちせずに合わせられるように公開する
+type ServerLimits struct {
+	MaxBodyBytes *int64       `json:"max_body_bytes"` // リクエストボディ全体の上限（null は上限なし）
+	RateLimit    *RateLimit   `json:"rate_limit"`     // null はレート制限なし
+	Bulk         BulkLimits   `json:"bulk"`
+	Import       ImportLimits `json:"import"`
+	Pagination   PageLimits   `json:"pagination"`
+	Search       SearchLimits `json:"search"`
+	Attachments  UploadLimits `json:"attachments"`
+	Receipts     UploadLimits `json:"receipts"`
+	Images       UploadLimits `json:"images"`
+}
+
+type RateLimit struct {
+	Requests      int `json:"requests"` // WindowSeconds あたりに受け付けるリクエスト数
+	WindowSeconds int `json:"window_seconds"`
+}
+
+type BulkLimits struct {
+	MaxCreateItems int `json:"max_create_items"` // POST /items/bulk
+	MaxItemIDs     int `json:"max_item_ids"`     // PATCH・DELETE の一括操作
+}
+
+type ImportLimits struct {
+	MaxRows      int `json:"max_rows"`
+	MaxLineBytes int `json:"max_line_bytes"` // NDJSON の 1 行
+}
+
+type PageLimits struct {
+	DefaultSize int `json:"default_size"`
+	MaxSize     int `json:"max_size"`
+}
+
+type SearchLimits struct {
+	DefaultLimit     int `json:"default_limit"`
+	MaxLimit         int `json:"max_limit"`
+	MaxKeywordLength int `json:"max_keyword_length"`
+}
+
+type UploadLimits struct {
+	MaxSizeBytes int64    `json:"max_size_bytes"`          // 1 ファイルの上限
+	MaxPerItem   int      `json:"max_per_item,omitempty"`  // 1 アイテムあたりの上限（0 は上限なし）
+	ContentTypes []string `json:"content_types,omitempty"` // 受け付ける形式（空の場合は制限なし）
+}
@@ -0,0 +1,133 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// 通知を送るチャンネル
+type NotificationChannel string
+
+const (
+	ChannelSlack NotificationChannel = "slack" // チームで共有する Slack のチャンネル（Incoming Webhook）
+	ChannelLINE  NotificationChannel = "line"  // LINE 公式アカウントからのメッセージ（Messaging API）
+	ChannelEmail NotificationChannel = "email" // ユーザーのメールアドレス
+	ChannelPush  NotificationChannel = "push"  // モバイルアプリのプッシュ通知（FCM）
+)
+
+var NotificationChannels = []NotificationChannel{ChannelSlack, ChannelLINE, ChannelEmail, ChannelPush}
+
+// ユーザーごとに宛先のあるチャンネルか。Slack はチームで1つのため、ユーザーごとの設定の対象にしない
+func (c NotificationChannel) Personal() bool {
+	return c != ChannelSlack
+}
+
+// 1ユーザーが登録できるプッシュ通知の端末の数
+const MaxPushTokens = 10
+
+// "line,slack" の形式のチャンネルの一覧を読み込む。重複は取り除く
+func ParseNotificationChannels(value string) ([]NotificationChannel, error) {
+	var channels []NotificationChannel
+	for _, name := range strings.Split(value, ",") {
+		channel := NotificationChannel(strings.ToLower(strings.TrimSpace(name)))
+		if channel == "" {
+			continue
+		}
+		if !slices.Contains(NotificationChannels, channel) {
+			return nil, fmt.Errorf("unknown notification channel %q", name)
+		}
+		if !slices.Contains(channels, channel) {
+			channels = append(channels, channel)
+		}
+	}
+	return channels, nil
+}
+
+// チャンネルによらない通知の内容。Slack・LINE・プッシュ通知では Title と Body を改行でつなげて送る
+type Notification struct {
+	Title string
+	Body  string
+}
+
+func (n Notification) Text() string {
+	if n.Body == "" {
+		return n.Title
+	}
+	return n.Title + "\n" + n.Body
+}
+
+// ユーザーが通知を受け取るチャンネルと、チャンネルごとの宛先
+// メールはユーザーのメールアドレスに送る
+type NotificationPreference struct {
+	UserID     int64                 `json:"user_id"`
+	Channels   []NotificationChannel `json:"channels"`
+	LINEUserID string                `json:"line_user_id,omitempty"` // 公式アカウントを友だちに追加したユーザーの ID（U で始まる 33 文字）
+	PushTokens []string              `json:"push_tokens"`            // FCM の登録トークン（端末ごと）
+	UpdatedAt  *time.Time            `json:"updated_at,omitempty"`   // 設定していない場合は nil
+}
+
+// 設定していないユーザーは個人のチャンネルで通知を受け取らない
+func DefaultNotificationPreference(userID int64) *NotificationPreference {
+	return &NotificationPreference{UserID: userID, Channels: []NotificationChannel{}, PushTokens: []string{}}
+}
+
+func (p *NotificationPreference) Enabled(channel NotificationChannel) bool {
+	return slices.Contains(p.Channels, channel)
+}
+
+func (p *NotificationPreference) Validate() error {
+	var errs []string
+
+	seen := map[NotificationChannel]bool{}
+	for _, channel := range p.Channels {
+		switch {
+		case !slices.Contains(NotificationChannels, channel):
+			errs = append(errs, fmt.Sprintf("unknown channel %q", channel))
+		case !channel.Personal():
+			errs = append(errs, fmt.Sprintf("channel %s is shared by the team and cannot be chosen per user", channel))
+		case seen[channel]:
+			errs = append(errs, fmt.Sprintf("channel %s is given more than once", channel))
+		}
+		seen[channel] = true
+	}
+
+	if p.LINEUserID != "" && !validLINEUserID(p.LINEUserID) {
+		errs = append(errs, "line_user_id must be a LINE user ID (U followed by 32 hex digits)")
+	}
+	if p.Enabled(ChannelLINE) && p.LINEUserID == "" {
+		errs = append(errs, "line_user_id is required to receive LINE notifications")
+	}
+
+	if len(p.PushTokens) > MaxPushTokens {
+		errs = append(errs, fmt.Sprintf("push_tokens must be %d or fewer", MaxPushTokens))
+	}
+	for _, token := range p.PushTokens {
+		if token == "" || len(token) > 4096 {
+			errs = append(errs, "push_tokens must not be empty or longer than 4096 characters")
+			break
+		}
+	}
+	if p.Enabled(ChannelPush) && len(p.PushTokens) == 0 {
+		errs = append(errs, "push_tokens is required to receive push notifications")
+	}
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+func validLINEUserID(id string) bool {
+	if len(id) != 33 || id[0] != 'U' {
+		return false
+	}
+	for _, r := range id[1:] {
+		if !strings.ContainsRune("0123456789abcdef", r) {
+			return false
+		}
+	}
+	return true
+}
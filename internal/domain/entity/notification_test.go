@@ -0,0 +1,82 @@
+package entity
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseNotificationChannels(t *testing.T) {
+	channels, err := ParseNotificationChannels(" LINE, slack,,line ")
+	require.NoError(t, err)
+	assert.Equal(t, []NotificationChannel{ChannelLINE, ChannelSlack}, channels)
+
+	channels, err = ParseNotificationChannels("")
+	require.NoError(t, err)
+	assert.Empty(t, channels)
+
+	_, err = ParseNotificationChannels("line,fax")
+	assert.EqualError(t, err, `unknown notification channel "fax"`)
+}
+
+func TestNotificationPreference_Validate(t *testing.T) {
+	lineUserID := "U" + strings.Repeat("0123456789abcdef", 2)
+
+	tests := []struct {
+		name        string
+		preference  NotificationPreference
+		expectedErr string
+	}{
+		{
+			name:       "正常系: LINE・メール・プッシュ通知",
+			preference: NotificationPreference{Channels: []NotificationChannel{ChannelLINE, ChannelEmail, ChannelPush}, LINEUserID: lineUserID, PushTokens: []string{"token"}},
+		},
+		{
+			name:       "正常系: 何も受け取らない",
+			preference: NotificationPreference{},
+		},
+		{
+			name:        "異常系: Slack はユーザーごとに選べない",
+			preference:  NotificationPreference{Channels: []NotificationChannel{ChannelSlack}},
+			expectedErr: "channel slack is shared by the team and cannot be chosen per user",
+		},
+		{
+			name:        "異常系: 知らないチャンネル・重複",
+			preference:  NotificationPreference{Channels: []NotificationChannel{"fax", ChannelEmail, ChannelEmail}},
+			expectedErr: `unknown channel "fax", channel email is given more than once`,
+		},
+		{
+			name:        "異常系: LINE の宛先がない",
+			preference:  NotificationPreference{Channels: []NotificationChannel{ChannelLINE}},
+			expectedErr: "line_user_id is required to receive LINE notifications",
+		},
+		{
+			name:        "異常系: LINE のユーザー ID の形式",
+			preference:  NotificationPreference{LINEUserID: "@aicon"},
+			expectedErr: "line_user_id must be a LINE user ID",
+		},
+		{
+			name:        "異常系: プッシュ通知の端末がない",
+			preference:  NotificationPreference{Channels: []NotificationChannel{ChannelPush}},
+			expectedErr: "push_tokens is required to receive push notifications",
+		},
+		{
+			name:        "異常系: 端末が多すぎる",
+			preference:  NotificationPreference{PushTokens: make([]string, MaxPushTokens+1)},
+			expectedErr: "push_tokens must be 10 or fewer",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.preference.Validate()
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expectedErr)
+		})
+	}
+}
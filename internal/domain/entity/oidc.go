@@ -0,0 +1,67 @@
+package entity
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"time"
+)
+
+// 外部の ID プロバイダー（OIDC / OAuth2）
+const (
+	IdentityProviderGoogle = "google"
+	IdentityProviderGitHub = "github"
+)
+
+// ID プロバイダーが確かめた利用者
+type ExternalIdentity struct {
+	Provider      string
+	Subject       string // プロバイダー内で変わらない利用者の ID
+	Email         string
+	EmailVerified bool
+	Name          string
+	Login         string // GitHub のログイン名など、ユーザー名の候補（ない場合は空）
+}
+
+// ユーザーに紐付けた外部の ID。同じ ID を複数のユーザーに紐付けることはできない
+type UserIdentity struct {
+	ID        int64     `json:"id"`
+	UserID    int64     `json:"user_id"`
+	Provider  string    `json:"provider"`
+	Subject   string    `json:"subject"`
+	Email     string    `json:"email,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// 認可画面に送り出してからコールバックで戻るまでのログインの要求
+// state は URL に載るため保存するのはハッシュ値のみ。PKCE の code_verifier と nonce はサーバーの外に出さない
+type OIDCLogin struct {
+	StateHash    string
+	Provider     string
+	Nonce        string
+	CodeVerifier string
+	UserID       *int64 // 指定した場合はログインせず、このユーザーに ID を紐付ける
+	CreatedAt    time.Time
+	ExpiresAt    time.Time
+}
+
+func NewOIDCLogin(provider, state, nonce, codeVerifier string, userID *int64, ttl time.Duration, now time.Time) *OIDCLogin {
+	return &OIDCLogin{
+		StateHash:    HashToken(state),
+		Provider:     provider,
+		Nonce:        nonce,
+		CodeVerifier: codeVerifier,
+		UserID:       userID,
+		CreatedAt:    now,
+		ExpiresAt:    now.Add(ttl),
+	}
+}
+
+func (l *OIDCLogin) Expired(now time.Time) bool {
+	return !now.Before(l.ExpiresAt)
+}
+
+// PKCE の code_challenge（S256）
+func (l *OIDCLogin) CodeChallenge() string {
+	sum := sha256.Sum256([]byte(l.CodeVerifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
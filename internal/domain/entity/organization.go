@@ -0,0 +1,195 @@
+package entity
+
+import (
+	"errors"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"Aicon-assignment/internal/pkg/listquery"
+)
+
+// 組織でのロール
+const (
+	OrgRoleAdmin  = "admin"  // メンバーと招待を管理できる
+	OrgRoleMember = "member" // 閲覧・操作のみ
+)
+
+var OrgRoles = []string{OrgRoleAdmin, OrgRoleMember}
+
+// 招待の有効期間
+const InvitationTTL = 7 * 24 * time.Hour
+
+type Organization struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// 組織ごとのアイテムの件数と購入価格の合計（削除済みのアイテムは含めない）
+type OrganizationItemSummary struct {
+	OrgID      int64  `json:"organization_id"`
+	Name       string `json:"name"`
+	ItemCount  int    `json:"item_count"`
+	TotalValue int64  `json:"total_value"`
+}
+
+// 組織ごとの集計はページングだけに対応する（並びは組織のID順）
+var OrganizationSummaryListSpec = listquery.Spec{}
+
+func NewOrganizationAt(now time.Time, name string) (*Organization, error) {
+	org := &Organization{
+		Name:      strings.TrimSpace(name),
+		CreatedAt: now,
+	}
+	if org.Name == "" {
+		return nil, errors.New("name is required")
+	}
+	if len(org.Name) > 100 {
+		return nil, errors.New("name must be 100 characters or less")
+	}
+	return org, nil
+}
+
+// 組織に所属するユーザーとロール
+type Membership struct {
+	OrgID     int64     `json:"organization_id"`
+	UserID    int64     `json:"user_id"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func IsOrgRole(role string) bool {
+	return slices.Contains(OrgRoles, role)
+}
+
+func ValidateOrgRole(role string) error {
+	if !IsOrgRole(role) {
+		return errors.New("role must be one of: admin, member")
+	}
+	return nil
+}
+
+// メールで送る組織への招待
+// トークンはメールでだけ送り、保存するのはハッシュ値のみ
+type Invitation struct {
+	ID         int64      `json:"id"`
+	OrgID      int64      `json:"organization_id"`
+	Email      string     `json:"email"`
+	Role       string     `json:"role"`
+	Token      string     `json:"-"`
+	TokenHash  string     `json:"-"`
+	InvitedBy  int64      `json:"invited_by"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	AcceptedAt *time.Time `json:"accepted_at,omitempty"`
+	AcceptedBy *int64     `json:"accepted_by,omitempty"`
+}
+
+func NewInvitation(orgID, invitedBy int64, email, role, token string, now time.Time) (*Invitation, error) {
+	invitation := &Invitation{
+		OrgID:     orgID,
+		Email:     strings.TrimSpace(email),
+		Role:      strings.TrimSpace(role),
+		Token:     token,
+		TokenHash: HashToken(token),
+		InvitedBy: invitedBy,
+		CreatedAt: now,
+		ExpiresAt: now.Add(InvitationTTL),
+	}
+	if invitation.Role == "" {
+		invitation.Role = OrgRoleMember
+	}
+
+	var errs []string
+	if invitation.Email == "" {
+		errs = append(errs, "email is required")
+	} else if len(invitation.Email) > 255 || !strings.Contains(invitation.Email, "@") {
+		errs = append(errs, "email must be a valid address of 255 characters or less")
+	}
+	if err := ValidateOrgRole(invitation.Role); err != nil {
+		errs = append(errs, err.Error())
+	}
+	if token == "" {
+		errs = append(errs, "token must not be empty")
+	}
+	if len(errs) > 0 {
+		return nil, errors.New(strings.Join(errs, ", "))
+	}
+
+	return invitation, nil
+}
+
+// 期限内で、まだ承諾されていない招待か
+func (i *Invitation) Pending(now time.Time) bool {
+	return i.AcceptedAt == nil && now.Before(i.ExpiresAt)
+}
+
+// 招待されたアドレスのユーザーか。大文字小文字は区別しない
+func (i *Invitation) IsFor(user *User) bool {
+	return user.Email != "" && strings.EqualFold(i.Email, user.Email)
+}
+
+// 設定されていない場合のアクセントカラー
+const DefaultAccentColor = "#1F2937"
+
+var accentColorPattern = regexp.MustCompile(`^#[0-9A-F]{6}$`)
+
+// 共有ページなど、組織の外に見せる画面に表示する名前・ロゴ・アクセントカラー
+type Branding struct {
+	OrgID       int64     `json:"organization_id"`
+	DisplayName string    `json:"display_name"`
+	LogoURL     string    `json:"logo_url,omitempty"`
+	AccentColor string    `json:"accent_color"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// まだ設定されていない組織のブランディング。組織名をそのまま表示する
+func DefaultBranding(org *Organization) *Branding {
+	return &Branding{
+		OrgID:       org.ID,
+		DisplayName: org.Name,
+		AccentColor: DefaultAccentColor,
+		UpdatedAt:   org.CreatedAt,
+	}
+}
+
+// displayName を省略した場合は組織名、accentColor を省略した場合は DefaultAccentColor を使う
+func NewBranding(org *Organization, displayName, logoURL, accentColor string, now time.Time) (*Branding, error) {
+	branding := &Branding{
+		OrgID:       org.ID,
+		DisplayName: strings.TrimSpace(displayName),
+		LogoURL:     strings.TrimSpace(logoURL),
+		AccentColor: strings.ToUpper(strings.TrimSpace(accentColor)),
+		UpdatedAt:   now,
+	}
+	if branding.DisplayName == "" {
+		branding.DisplayName = org.Name
+	}
+	if branding.AccentColor == "" {
+		branding.AccentColor = DefaultAccentColor
+	}
+
+	var errs []string
+	if len(branding.DisplayName) > 100 {
+		errs = append(errs, "display_name must be 100 characters or less")
+	}
+	if branding.LogoURL != "" {
+		if u, err := url.Parse(branding.LogoURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, "logo_url must be an absolute http(s) URL")
+		} else if len(branding.LogoURL) > 2048 {
+			errs = append(errs, "logo_url must be 2048 characters or less")
+		}
+	}
+	if !accentColorPattern.MatchString(branding.AccentColor) {
+		errs = append(errs, "accent_color must be a hex color such as #1F2937")
+	}
+	if len(errs) > 0 {
+		return nil, errors.New(strings.Join(errs, ", "))
+	}
+
+	return branding, nil
+}
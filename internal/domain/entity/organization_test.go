@@ -0,0 +1,57 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewBranding(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	org := &Organization{ID: 1, Name: "Aicon"}
+
+	tests := []struct {
+		name        string
+		displayName string
+		logoURL     string
+		accentColor string
+		want        *Branding
+		expectedErr string
+	}{
+		{
+			name: "正常系: 省略した項目はデフォルト",
+			want: &Branding{OrgID: 1, DisplayName: "Aicon", AccentColor: DefaultAccentColor, UpdatedAt: now},
+		},
+		{
+			name:        "正常系: アクセントカラーは大文字にそろえる",
+			displayName: " Aicon 鑑定 ",
+			logoURL:     "https://cdn.example.com/logo.png",
+			accentColor: "#ff8800",
+			want:        &Branding{OrgID: 1, DisplayName: "Aicon 鑑定", LogoURL: "https://cdn.example.com/logo.png", AccentColor: "#FF8800", UpdatedAt: now},
+		},
+		{
+			name:        "異常系: ロゴのURLが相対パス",
+			logoURL:     "/logo.png",
+			expectedErr: "logo_url must be an absolute http(s) URL",
+		},
+		{
+			name:        "異常系: アクセントカラーが16進数でない",
+			accentColor: "orange",
+			expectedErr: "accent_color must be a hex color such as #1F2937",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewBranding(org, tt.displayName, tt.logoURL, tt.accentColor, now)
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
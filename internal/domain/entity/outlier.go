@@ -0,0 +1,123 @@
+package entity
+
+import (
+	"math"
+	"slices"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// 外れ値と判定する修正 Z スコアの閾値（Iglewicz & Hoaglin）
+	OutlierScoreThreshold = 3.5
+	// 外れ値を判定するのに必要なカテゴリー内のアイテム数
+	OutlierMinSamples = 5
+)
+
+// 外れ値を調べる項目
+const (
+	OutlierFieldPurchasePrice = "purchase_price"
+	OutlierFieldPurchaseDate  = "purchase_date"
+	OutlierFieldNameLength    = "name_length"
+)
+
+// カテゴリー内で統計的に外れているアイテムの項目
+type Outlier struct {
+	Item   *Item   `json:"item"`
+	Field  string  `json:"field"`
+	Value  any     `json:"value"`
+	Median any     `json:"median"`
+	Score  float64 `json:"score"` // 修正 Z スコア。正なら中央値より大きい
+}
+
+// 外れ値を調べる項目と、アイテムから数値を取り出す方法
+var outlierMetrics = []struct {
+	field  string
+	value  func(*Item) float64
+	format func(float64) any
+}{
+	{
+		field:  OutlierFieldPurchasePrice,
+		value:  func(i *Item) float64 { return float64(i.PurchasePrice) },
+		format: func(v float64) any { return int(v) },
+	},
+	{
+		field: OutlierFieldPurchaseDate,
+		value: func(i *Item) float64 {
+			date, _ := time.Parse("2006-01-02", i.PurchaseDate)
+			return float64(date.Unix() / 86400)
+		},
+		format: func(v float64) any { return time.Unix(int64(v)*86400, 0).UTC().Format("2006-01-02") },
+	},
+	{
+		field:  OutlierFieldNameLength,
+		value:  func(i *Item) float64 { return float64(utf8.RuneCountInString(i.Name)) },
+		format: func(v float64) any { return int(v) },
+	},
+}
+
+// カテゴリーごとに価格・購入日・名前の長さの外れ値を探す
+// 中央値と MAD による修正 Z スコアを使うため、外れ値自体に中央値が引きずられにくい
+func FindOutliers(items []*Item) []*Outlier {
+	byCategory := make(map[string][]*Item)
+	var categories []string
+	for _, item := range items {
+		if _, ok := byCategory[item.Category]; !ok {
+			categories = append(categories, item.Category)
+		}
+		byCategory[item.Category] = append(byCategory[item.Category], item)
+	}
+	slices.Sort(categories)
+
+	outliers := []*Outlier{}
+	for _, category := range categories {
+		group := byCategory[category]
+		if len(group) < OutlierMinSamples {
+			continue
+		}
+
+		for _, metric := range outlierMetrics {
+			values := make([]float64, len(group))
+			for i, item := range group {
+				values[i] = metric.value(item)
+			}
+
+			center := median(slices.Clone(values))
+			deviations := make([]float64, len(values))
+			for i, v := range values {
+				deviations[i] = math.Abs(v - center)
+			}
+			mad := median(deviations)
+			// 半数以上が同じ値の場合はばらつきを測れないので判定しない
+			if mad == 0 {
+				continue
+			}
+
+			for i, item := range group {
+				score := 0.6745 * (values[i] - center) / mad
+				if math.Abs(score) <= OutlierScoreThreshold {
+					continue
+				}
+				outliers = append(outliers, &Outlier{
+					Item:   item,
+					Field:  metric.field,
+					Value:  metric.format(values[i]),
+					Median: metric.format(center),
+					Score:  math.Round(score*100) / 100,
+				})
+			}
+		}
+	}
+
+	return outliers
+}
+
+// 中央値（values は並び替えられる）
+func median[T int | float64](values []T) T {
+	slices.Sort(values)
+	mid := len(values) / 2
+	if len(values)%2 == 0 {
+		return (values[mid-1] + values[mid]) / 2
+	}
+	return values[mid]
+}
@@ -0,0 +1,58 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindOutliers(t *testing.T) {
+	watch := func(id int64, name string, price int, date string) *Item {
+		return &Item{ID: id, Name: name, Category: "時計", Brand: "ROLEX", PurchasePrice: price, PurchaseDate: date}
+	}
+
+	t.Run("正常系: 価格の外れ値を見つける", func(t *testing.T) {
+		items := []*Item{
+			watch(1, "デイトナ", 1000000, "2023-01-01"),
+			watch(2, "サブマリーナ", 1100000, "2023-01-03"),
+			watch(3, "GMTマスター", 1200000, "2023-01-02"),
+			watch(4, "エクスプローラー", 900000, "2023-01-04"),
+			watch(5, "デイトジャスト", 120000000, "2023-01-05"),
+		}
+
+		outliers := FindOutliers(items)
+		require.Len(t, outliers, 1)
+		assert.Equal(t, int64(5), outliers[0].Item.ID)
+		assert.Equal(t, OutlierFieldPurchasePrice, outliers[0].Field)
+		assert.Equal(t, 120000000, outliers[0].Value)
+		assert.Equal(t, 1100000, outliers[0].Median)
+		assert.Greater(t, outliers[0].Score, OutlierScoreThreshold)
+	})
+
+	t.Run("正常系: 購入日の外れ値を見つける", func(t *testing.T) {
+		items := []*Item{
+			watch(1, "デイトナ", 1000000, "2023-01-01"),
+			watch(2, "サブマリーナ", 1100000, "2023-01-03"),
+			watch(3, "GMTマスター", 1200000, "2023-01-02"),
+			watch(4, "エクスプローラー", 900000, "2023-01-04"),
+			watch(5, "デイトジャスト", 1050000, "1923-01-05"),
+		}
+
+		outliers := FindOutliers(items)
+		require.Len(t, outliers, 1)
+		assert.Equal(t, OutlierFieldPurchaseDate, outliers[0].Field)
+		assert.Equal(t, "1923-01-05", outliers[0].Value)
+		assert.Equal(t, "2023-01-02", outliers[0].Median)
+	})
+
+	t.Run("正常系: 件数が少ないカテゴリーは判定しない", func(t *testing.T) {
+		items := []*Item{
+			watch(1, "デイトナ", 1000000, "2023-01-01"),
+			watch(2, "サブマリーナ", 1100000, "2023-01-03"),
+			watch(3, "デイトジャスト", 120000000, "2023-01-05"),
+		}
+
+		assert.Empty(t, FindOutliers(items))
+	})
+}
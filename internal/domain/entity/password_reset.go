@@ -0,0 +1,30 @@
+package entity
+
+import "time"
+
+// パスワードを忘れたユーザーにメールで送る、パスワードの再設定用のトークン
+// 1 回だけ使え、有効期限を過ぎると使えない。保存するのはハッシュ値のみ
+type PasswordResetToken struct {
+	ID        int64
+	UserID    int64
+	Token     string // 発行時にだけ入っている
+	TokenHash string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	UsedAt    *time.Time // パスワードを再設定した日時（後から発行したトークンで再設定した場合も記録する）
+}
+
+func NewPasswordResetToken(userID int64, token string, ttl time.Duration, now time.Time) *PasswordResetToken {
+	return &PasswordResetToken{
+		UserID:    userID,
+		Token:     token,
+		TokenHash: HashToken(token),
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+	}
+}
+
+// 期限内で、まだ使っていないトークンか
+func (t *PasswordResetToken) Valid(now time.Time) bool {
+	return t.UsedAt == nil && now.Before(t.ExpiresAt)
+}
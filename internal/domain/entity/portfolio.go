@@ -0,0 +1,124 @@
+package entity
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 参照データの categories で評価額の割合を設定していない場合の値（購入価格のまま）
+const DefaultValuePercent = 100
+
+// 履歴の期間の既定値と上限
+const (
+	DefaultPortfolioRange = "1y"
+	maxPortfolioRangeDays = 10 * 366
+)
+
+// ある日のポートフォリオ全体の価値
+type PortfolioSnapshot struct {
+	Date          string                    `json:"date"` // YYYY-MM-DD（UTC）
+	ItemCount     int                       `json:"item_count"`
+	PurchaseValue int64                     `json:"purchase_value"` // 購入価格の合計
+	CurrentValue  int64                     `json:"current_value"`  // 現在の評価額の合計
+	Categories    []*PortfolioCategoryValue `json:"categories"`
+	RecordedAt    time.Time                 `json:"recorded_at"`
+}
+
+// カテゴリーごとの価値
+type PortfolioCategoryValue struct {
+	Category      string `json:"category"`
+	ItemCount     int    `json:"item_count"`
+	PurchaseValue int64  `json:"purchase_value"`
+	CurrentValue  int64  `json:"current_value"`
+}
+
+// アイテムの購入価格と、カテゴリーごとの評価額の割合（%）から now の日付のスナップショットを作る
+// 割合を設定していないカテゴリーは購入価格をそのまま評価額とする
+func NewPortfolioSnapshot(items []*Item, valuePercents map[string]int64, now time.Time) *PortfolioSnapshot {
+	snapshot := &PortfolioSnapshot{
+		Date:       now.UTC().Format("2006-01-02"),
+		Categories: []*PortfolioCategoryValue{},
+		RecordedAt: now,
+	}
+
+	byCategory := map[string]*PortfolioCategoryValue{}
+	for _, item := range items {
+		group, ok := byCategory[item.Category]
+		if !ok {
+			group = &PortfolioCategoryValue{Category: item.Category}
+			byCategory[item.Category] = group
+			snapshot.Categories = append(snapshot.Categories, group)
+		}
+		percent, ok := valuePercents[item.Category]
+		if !ok {
+			percent = DefaultValuePercent
+		}
+		current := int64(item.PurchasePrice) * percent / 100
+
+		group.ItemCount++
+		group.PurchaseValue += int64(item.PurchasePrice)
+		group.CurrentValue += current
+		snapshot.ItemCount++
+		snapshot.PurchaseValue += int64(item.PurchasePrice)
+		snapshot.CurrentValue += current
+	}
+
+	sort.Slice(snapshot.Categories, func(i, j int) bool {
+		return snapshot.Categories[i].Category < snapshot.Categories[j].Category
+	})
+	return snapshot
+}
+
+// 履歴の期間（"30d", "12w", "6m", "1y" の形式）を解釈し、today を含む最初の日付（YYYY-MM-DD）を返す
+// 空文字の場合は DefaultPortfolioRange
+func PortfolioRangeStart(spec string, today time.Time) (string, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		spec = DefaultPortfolioRange
+	}
+	invalid := fmt.Errorf("range must be a number followed by d, w, m or y (e.g. 30d, 1y)")
+	if len(spec) < 2 {
+		return "", invalid
+	}
+	n, err := strconv.Atoi(spec[:len(spec)-1])
+	if err != nil || n <= 0 {
+		return "", invalid
+	}
+	tooLong := fmt.Errorf("range must be 10 years or less")
+	if n > maxPortfolioRangeDays {
+		return "", tooLong
+	}
+
+	today = today.UTC()
+	var start time.Time
+	switch spec[len(spec)-1] {
+	case 'd':
+		start = today.AddDate(0, 0, -n)
+	case 'w':
+		start = today.AddDate(0, 0, -7*n)
+	case 'm':
+		start = today.AddDate(0, -n, 0)
+	case 'y':
+		start = today.AddDate(-n, 0, 0)
+	default:
+		return "", invalid
+	}
+	if today.Sub(start) > maxPortfolioRangeDays*24*time.Hour {
+		return "", tooLong
+	}
+	return start.AddDate(0, 0, 1).Format("2006-01-02"), nil
+}
+
+// from から to までの期間の最初と最後の日付（UTC の YYYY-MM-DD）
+func PortfolioDateRange(from, to time.Time) (string, string, error) {
+	if to.Before(from) {
+		return "", "", fmt.Errorf("to must not be before from")
+	}
+	if to.Sub(from) > maxPortfolioRangeDays*24*time.Hour {
+		return "", "", fmt.Errorf("range must be 10 years or less")
+	}
+	return from.UTC().Format("2006-01-02"), to.UTC().Format("2006-01-02"), nil
+}
@@ -0,0 +1,85 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPortfolioSnapshot(t *testing.T) {
+	now := time.Date(2024, 6, 1, 23, 30, 0, 0, time.UTC)
+	items := []*Item{
+		{ID: 1, Category: "時計", PurchasePrice: 1000000},
+		{ID: 2, Category: "時計", PurchasePrice: 500000},
+		{ID: 3, Category: "バッグ", PurchasePrice: 300000},
+		{ID: 4, Category: "靴", PurchasePrice: 99},
+	}
+
+	snapshot := NewPortfolioSnapshot(items, map[string]int64{"時計": 110, "靴": 50}, now)
+
+	assert.Equal(t, "2024-06-01", snapshot.Date)
+	assert.Equal(t, 4, snapshot.ItemCount)
+	assert.Equal(t, int64(1800099), snapshot.PurchaseValue)
+	assert.Equal(t, int64(1650000+300000+49), snapshot.CurrentValue)
+	assert.Equal(t, []*PortfolioCategoryValue{
+		{Category: "バッグ", ItemCount: 1, PurchaseValue: 300000, CurrentValue: 300000},
+		{Category: "時計", ItemCount: 2, PurchaseValue: 1500000, CurrentValue: 1650000},
+		{Category: "靴", ItemCount: 1, PurchaseValue: 99, CurrentValue: 49},
+	}, snapshot.Categories)
+}
+
+func TestPortfolioRangeStart(t *testing.T) {
+	today := time.Date(2024, 6, 1, 15, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		spec        string
+		want        string
+		expectedErr string
+	}{
+		{name: "正常系: 日数", spec: "7d", want: "2024-05-26"},
+		{name: "正常系: 週", spec: "2w", want: "2024-05-19"},
+		{name: "正常系: 月", spec: "6m", want: "2023-12-02"},
+		{name: "正常系: 省略した場合は 1 年", spec: "", want: "2023-06-02"},
+		{name: "異常系: 単位が不正", spec: "1h", expectedErr: "range must be a number followed by d, w, m or y"},
+		{name: "異常系: 数値がない", spec: "y", expectedErr: "range must be a number followed by d, w, m or y"},
+		{name: "異常系: 0", spec: "0d", expectedErr: "range must be a number followed by d, w, m or y"},
+		{name: "異常系: 10 年を超える", spec: "11y", expectedErr: "range must be 10 years or less"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := PortfolioRangeStart(tt.spec, today)
+			if tt.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPortfolioDateRange(t *testing.T) {
+	from := time.Date(2024, 5, 1, 23, 0, 0, 0, time.FixedZone("JST", 9*60*60))
+
+	t.Run("正常系: UTC の日付にする", func(t *testing.T) {
+		start, end, err := PortfolioDateRange(from, from.AddDate(0, 1, 0))
+		require.NoError(t, err)
+		assert.Equal(t, "2024-05-01", start)
+		assert.Equal(t, "2024-06-01", end)
+	})
+
+	t.Run("異常系: 終わりが始まりより前", func(t *testing.T) {
+		_, _, err := PortfolioDateRange(from, from.Add(-time.Hour))
+		assert.EqualError(t, err, "to must not be before from")
+	})
+
+	t.Run("異常系: 10 年を超える", func(t *testing.T) {
+		_, _, err := PortfolioDateRange(from, from.AddDate(11, 0, 0))
+		assert.EqualError(t, err, "range must be 10 years or less")
+	})
+}
@@ -0,0 +1,69 @@
+package entity
+
+import (
+	"fmt"
+
+	"Aicon-assignment/internal/pkg/filter"
+)
+
+const (
+	// 中央値を比較に使うのに必要な同じブランド・カテゴリーのアイテム数
+	PriceAnomalyMinSamples = 3
+	// 中央値の何倍（または何分の一）を超えたら警告するか
+	PriceAnomalyFactor = 10
+)
+
+// 購入価格が同じブランド・カテゴリーの相場から大きく外れていることを表す警告
+type PriceAnomaly struct {
+	PurchasePrice int    `json:"purchase_price"`
+	Median        int    `json:"median"`
+	Samples       int    `json:"samples"`
+	Brand         string `json:"brand"`
+	Category      string `json:"category"`
+}
+
+func (a *PriceAnomaly) Message() string {
+	return fmt.Sprintf("purchase_price %d deviates from the median %d of %d items with the same brand and category",
+		a.PurchasePrice, a.Median, a.Samples)
+}
+
+// 比較対象にする同じブランド・カテゴリーのアイテムの条件
+func PricePeerQuery(item *Item) ItemQuery {
+	return ItemQuery{Filter: &filter.Logical{
+		Op:    filter.OpAnd,
+		Left:  &filter.Comparison{Field: "brand", Op: filter.OpEq, Value: item.Brand},
+		Right: &filter.Comparison{Field: "category", Op: filter.OpEq, Value: item.Category},
+	}}
+}
+
+// 同じブランド・カテゴリーのアイテムの価格と比べて、外れ値なら警告を返す
+// peers に item 自身が含まれていても比較からは除く。比較できるアイテムが少ない場合は判定しない
+func DetectPriceAnomaly(item *Item, peers []*Item) *PriceAnomaly {
+	prices := make([]int, 0, len(peers))
+	for _, peer := range peers {
+		if item.ID != 0 && peer.ID == item.ID {
+			continue
+		}
+		prices = append(prices, peer.PurchasePrice)
+	}
+	if len(prices) < PriceAnomalyMinSamples {
+		return nil
+	}
+
+	center := median(prices)
+	if center <= 0 {
+		return nil
+	}
+
+	if item.PurchasePrice < center*PriceAnomalyFactor && item.PurchasePrice*PriceAnomalyFactor > center {
+		return nil
+	}
+
+	return &PriceAnomaly{
+		PurchasePrice: item.PurchasePrice,
+		Median:        center,
+		Samples:       len(prices),
+		Brand:         item.Brand,
+		Category:      item.Category,
+	}
+}
@@ -0,0 +1,56 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectPriceAnomaly(t *testing.T) {
+	peers := []*Item{
+		{ID: 1, PurchasePrice: 20000},
+		{ID: 2, PurchasePrice: 30000},
+		{ID: 3, PurchasePrice: 40000},
+	}
+
+	tests := []struct {
+		name     string
+		item     *Item
+		peers    []*Item
+		expected *PriceAnomaly
+	}{
+		{
+			name:  "正常系: 相場の範囲内",
+			item:  &Item{PurchasePrice: 250000, Brand: "NIKE", Category: "靴"},
+			peers: peers,
+		},
+		{
+			name:     "正常系: 中央値の10倍以上は警告",
+			item:     &Item{PurchasePrice: 15000000, Brand: "NIKE", Category: "靴"},
+			peers:    peers,
+			expected: &PriceAnomaly{PurchasePrice: 15000000, Median: 30000, Samples: 3, Brand: "NIKE", Category: "靴"},
+		},
+		{
+			name:     "正常系: 中央値の10分の1以下は警告",
+			item:     &Item{PurchasePrice: 3000, Brand: "NIKE", Category: "靴"},
+			peers:    peers,
+			expected: &PriceAnomaly{PurchasePrice: 3000, Median: 30000, Samples: 3, Brand: "NIKE", Category: "靴"},
+		},
+		{
+			name:  "正常系: 自分自身を除くと件数が足りない",
+			item:  &Item{ID: 3, PurchasePrice: 15000000},
+			peers: peers,
+		},
+		{
+			name:  "正常系: 比較対象がない",
+			item:  &Item{PurchasePrice: 15000000},
+			peers: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, DetectPriceAnomaly(tt.item, tt.peers))
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package entity
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"Aicon-assignment/internal/pkg/filter"
+	"Aicon-assignment/internal/pkg/listquery"
+)
+
+// 購入価格の変更履歴
+type PriceChange struct {
+	ID        int64     `json:"id"`
+	ItemID    int64     `json:"item_id"`
+	OldPrice  int       `json:"old_price"`
+	NewPrice  int       `json:"new_price"`
+	Actor     string    `json:"actor"`
+	Reason    string    `json:"reason,omitempty"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+const maxPriceChangeReasonLength = 500
+
+func NewPriceChange(itemID int64, oldPrice, newPrice int, actor, reason string, now time.Time) (*PriceChange, error) {
+	change := &PriceChange{
+		ItemID:    itemID,
+		OldPrice:  oldPrice,
+		NewPrice:  newPrice,
+		Actor:     strings.TrimSpace(actor),
+		Reason:    strings.TrimSpace(reason),
+		ChangedAt: now,
+	}
+
+	if len(change.Reason) > maxPriceChangeReasonLength {
+		return nil, errors.New("reason must be 500 characters or less")
+	}
+
+	return change, nil
+}
+
+// GET /items/:id/price-history で使える絞り込み・並び替え
+var PriceChangeListSpec = listquery.Spec{
+	Filters: filter.Fields{
+		"old_price": filter.Number,
+		"new_price": filter.Number,
+		"actor":     filter.String,
+	},
+	Sorts:       []string{"id", "changed_at"},
+	DefaultSort: []listquery.SortField{{Field: "changed_at"}, {Field: "id"}},
+}
+
+func (c *PriceChange) FilterValue(field string) any {
+	switch field {
+	case "id":
+		return c.ID
+	case "old_price":
+		return int64(c.OldPrice)
+	case "new_price":
+		return int64(c.NewPrice)
+	case "actor":
+		return c.Actor
+	case "changed_at":
+		return c.ChangedAt.UnixNano()
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package entity
+
+import "time"
+
+// アップロードされたファイルの種類
+const (
+	UploadKindImage      = "image"
+	UploadKindAttachment = "attachment"
+	UploadKindReceipt    = "receipt"
+)
+
+// アップロードされたファイルの情報（ウイルス検査と隔離で使う）
+type Upload struct {
+	Kind        string `json:"kind"` // image, attachment, receipt
+	ItemID      int64  `json:"item_id"`
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+}
+
+// ウイルス検査の結果
+type ScanResult struct {
+	Clean     bool
+	Signature string // 検出したウイルスの名前（Clean の場合は空）
+}
+
+// ウイルスが見つかり隔離したファイル。アイテムには追加せず、管理者が確認して削除する
+type QuarantinedFile struct {
+	ID int64 `json:"id"`
+	Upload
+	Size      int64     `json:"size"`
+	Signature string    `json:"signature"`
+	Key       string    `json:"-"` // ファイルストア上のキー
+	Actor     string    `json:"actor"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ファイルストア上の隔離したファイルのキー。name は推測できないランダムな文字列にする
+func QuarantineBlobKey(name string) string {
+	return "quarantine/" + name
+}
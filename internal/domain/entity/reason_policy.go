@@ -0,0 +1,20 @@
+package entity
+
+// 破壊的な操作に理由の入力を求めるポリシー
+type ReasonPolicy struct {
+	// 削除時に理由を必須にする
+	RequireOnDelete bool
+	// 更新前後いずれかの購入価格がこの金額以上の場合、更新時に理由を必須にする（0 の場合は無効）
+	HighValueThreshold int
+}
+
+func (p ReasonPolicy) RequiresReasonForDelete() bool {
+	return p.RequireOnDelete
+}
+
+func (p ReasonPolicy) RequiresReasonForUpdate(oldPrice, newPrice int) bool {
+	if p.HighValueThreshold <= 0 {
+		return false
+	}
+	return oldPrice >= p.HighValueThreshold || newPrice >= p.HighValueThreshold
+}
@@ -0,0 +1,301 @@
+package entity
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// 参照データの項目の型
+const (
+	ReferenceFieldString     = "string"
+	ReferenceFieldInteger    = "integer"
+	ReferenceFieldBoolean    = "boolean"
+	ReferenceFieldStringList = "string_list"
+)
+
+// 参照データの種類
+const (
+	ReferenceCategories          = "categories"
+	ReferenceBrands              = "brands"
+	ReferenceVocabularies        = "vocabularies"
+	ReferenceValidationProfiles  = "validation_profiles"
+	ReferenceClassificationRules = "classification_rules"
+)
+
+// 参照データのキーの最大長
+const MaxReferenceKeyLength = 100
+
+// 参照データの項目の定義。管理画面が入力欄を組み立てられるよう、そのまま公開する
+type ReferenceField struct {
+	Name        string   `json:"name"`
+	Type        string   `json:"type"`
+	Required    bool     `json:"required"`
+	MaxLength   int      `json:"max_length,omitempty"` // 文字列（リストの場合は各要素）の最大長
+	Enum        []string `json:"enum,omitempty"`       // 指定できる値
+	Min         *int64   `json:"min,omitempty"`        // 整数の下限
+	References  string   `json:"references,omitempty"` // 値が指す参照データの種類（キーで参照する）
+	Description string   `json:"description"`
+}
+
+// 参照データの種類と、その項目の定義
+type ReferenceType struct {
+	Name           string           `json:"name"`
+	Description    string           `json:"description"`
+	KeyDescription string           `json:"key_description"`
+	Fields         []ReferenceField `json:"fields"`
+}
+
+// 参照データの 1 件。項目の値は ReferenceType.Normalize で型をそろえたもの
+type ReferenceRecord struct {
+	Type       string         `json:"type"`
+	Key        string         `json:"key"`
+	Attributes map[string]any `json:"attributes"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+}
+
+// 管理 API で扱う参照データの種類。種類を増やす場合はここに定義を加える
+var ReferenceTypes = []ReferenceType{
+	{
+		Name:           ReferenceCategories,
+		Description:    "アイテムのカテゴリー",
+		KeyDescription: "カテゴリー名（アイテムの category に指定する値）",
+		Fields: []ReferenceField{
+			{Name: "display_order", Type: ReferenceFieldInteger, Description: "一覧での並び順（小さい順）"},
+			{Name: "validation_profile", Type: ReferenceFieldString, References: ReferenceValidationProfiles, Description: "このカテゴリーのアイテムに適用する入力チェック"},
+			{Name: "value_percent", Type: ReferenceFieldInteger, Min: new(int64), Description: "現在の評価額の購入価格に対する割合（%）。未設定の場合は 100"},
+		},
+	},
+	{
+		Name:           ReferenceBrands,
+		Description:    "ブランド",
+		KeyDescription: "ブランド名",
+		Fields: []ReferenceField{
+			{Name: "display_name", Type: ReferenceFieldString, MaxLength: 100, Description: "表示名"},
+			{Name: "aliases", Type: ReferenceFieldStringList, MaxLength: 100, Description: "表記ゆれ（入力をこのブランドに寄せる）"},
+		},
+	},
+	{
+		Name:           ReferenceVocabularies,
+		Description:    "入力候補などに使う用語の一覧",
+		KeyDescription: "用語集の名前",
+		Fields: []ReferenceField{
+			{Name: "description", Type: ReferenceFieldString, MaxLength: 500, Description: "用途"},
+			{Name: "terms", Type: ReferenceFieldStringList, Required: true, MaxLength: 100, Description: "用語"},
+		},
+	},
+	{
+		Name:           ReferenceValidationProfiles,
+		Description:    "カテゴリーごとのアイテムの入力チェック",
+		KeyDescription: "プロファイル名",
+		Fields: []ReferenceField{
+			{Name: "description", Type: ReferenceFieldString, MaxLength: 500, Description: "用途"},
+			{Name: "min_price", Type: ReferenceFieldInteger, Description: "購入価格の下限"},
+			{Name: "max_price", Type: ReferenceFieldInteger, Description: "購入価格の上限"},
+			{Name: "brand_vocabulary", Type: ReferenceFieldString, References: ReferenceVocabularies, Description: "ブランドをこの用語集の用語に限る"},
+		},
+	},
+	{
+		Name:           ReferenceClassificationRules,
+		Description:    "アイテムのカテゴリーを推定するルール",
+		KeyDescription: "ルール名",
+		Fields: []ReferenceField{
+			{Name: "field", Type: ReferenceFieldString, Required: true, Enum: []string{"name", "brand"}, Description: "照合するアイテムの項目"},
+			{Name: "contains", Type: ReferenceFieldString, Required: true, MaxLength: 100, Description: "項目に含まれる文字列（大文字小文字を区別しない）"},
+			{Name: "category", Type: ReferenceFieldString, Required: true, References: ReferenceCategories, Description: "一致したアイテムのカテゴリー"},
+			{Name: "priority", Type: ReferenceFieldInteger, Description: "複数のルールに一致した場合は大きいものを使う"},
+		},
+	},
+}
+
+// 名前から参照データの種類を返す
+func FindReferenceType(name string) (*ReferenceType, bool) {
+	for i := range ReferenceTypes {
+		if ReferenceTypes[i].Name == name {
+			return &ReferenceTypes[i], true
+		}
+	}
+	return nil, false
+}
+
+// キーを検証し、前後の空白を取り除いて返す
+func NormalizeReferenceKey(key string) (string, error) {
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return "", ValidationErrors{{"key", "key is required"}}
+	}
+	if len(key) > MaxReferenceKeyLength {
+		return "", ValidationErrors{{"key", "key must be 100 characters or less"}}
+	}
+	return key, nil
+}
+
+// 項目の定義に従って値を検証し、型をそろえた値を返す（整数は int64、リストは []string）
+// 定義にない項目はエラーにする。値が null の項目は省略したものとして扱う
+func (t *ReferenceType) Normalize(attributes map[string]any) (map[string]any, error) {
+	var errs ValidationErrors
+	normalized := make(map[string]any, len(attributes))
+
+	names := make([]string, 0, len(attributes))
+	for name := range attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if t.field(name) == nil {
+			errs = append(errs, FieldError{"attributes." + name, fmt.Sprintf("%s is not a field of %s", name, t.Name)})
+		}
+	}
+
+	for _, field := range t.Fields {
+		raw, ok := attributes[field.Name]
+		if !ok || raw == nil {
+			if field.Required {
+				errs = append(errs, FieldError{"attributes." + field.Name, field.Name + " is required"})
+			}
+			continue
+		}
+		value, err := field.normalize(raw)
+		if err != nil {
+			errs = append(errs, FieldError{"attributes." + field.Name, field.Name + " " + err.Error()})
+			continue
+		}
+		normalized[field.Name] = value
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return normalized, nil
+}
+
+func (t *ReferenceType) field(name string) *ReferenceField {
+	for i := range t.Fields {
+		if t.Fields[i].Name == name {
+			return &t.Fields[i]
+		}
+	}
+	return nil
+}
+
+func (f *ReferenceField) normalize(raw any) (any, error) {
+	switch f.Type {
+	case ReferenceFieldString:
+		value, ok := raw.(string)
+		if !ok {
+			return nil, fmt.Errorf("must be a string")
+		}
+		value = strings.TrimSpace(value)
+		if f.Required && value == "" {
+			return nil, fmt.Errorf("must not be empty")
+		}
+		return value, f.checkString(value)
+	case ReferenceFieldInteger:
+		var value int64
+		switch raw := raw.(type) {
+		case int:
+			value = int64(raw)
+		case int64:
+			value = raw
+		case float64:
+			if raw != math.Trunc(raw) || math.Abs(raw) > math.MaxInt32 {
+				return nil, fmt.Errorf("must be an integer")
+			}
+			value = int64(raw)
+		default:
+			return nil, fmt.Errorf("must be an integer")
+		}
+		if f.Min != nil && value < *f.Min {
+			return nil, fmt.Errorf("must be %d or more", *f.Min)
+		}
+		return value, nil
+	case ReferenceFieldBoolean:
+		value, ok := raw.(bool)
+		if !ok {
+			return nil, fmt.Errorf("must be a boolean")
+		}
+		return value, nil
+	case ReferenceFieldStringList:
+		var list []string
+		switch value := raw.(type) {
+		case []string:
+			list = value
+		case []any:
+			for _, element := range value {
+				s, ok := element.(string)
+				if !ok {
+					return nil, fmt.Errorf("must be a list of strings")
+				}
+				list = append(list, s)
+			}
+		default:
+			return nil, fmt.Errorf("must be a list of strings")
+		}
+		values := make([]string, 0, len(list))
+		for _, element := range list {
+			element = strings.TrimSpace(element)
+			if element == "" {
+				continue
+			}
+			if err := f.checkString(element); err != nil {
+				return nil, err
+			}
+			if !slices.Contains(values, element) {
+				values = append(values, element)
+			}
+		}
+		if f.Required && len(values) == 0 {
+			return nil, fmt.Errorf("must not be empty")
+		}
+		return values, nil
+	}
+	return nil, fmt.Errorf("has unknown type %s", f.Type)
+}
+
+func (f *ReferenceField) checkString(value string) error {
+	if f.MaxLength > 0 && len(value) > f.MaxLength {
+		return fmt.Errorf("must be %d characters or less", f.MaxLength)
+	}
+	if len(f.Enum) > 0 && !slices.Contains(f.Enum, value) {
+		return fmt.Errorf("must be one of: %s", strings.Join(f.Enum, ", "))
+	}
+	return nil
+}
+
+// 項目が指している参照データのキー（空の場合は何も指していない）
+func (r *ReferenceRecord) Reference(field string) string {
+	value, _ := r.Attributes[field].(string)
+	return value
+}
+
+// 整数の項目の値。設定していない場合は ok が false
+func (r *ReferenceRecord) Int(field string) (int64, bool) {
+	switch value := r.Attributes[field].(type) {
+	case int64:
+		return value, true
+	case float64:
+		return int64(value), true
+	}
+	return 0, false
+}
+
+// リストの項目の値。設定していない場合は nil
+func (r *ReferenceRecord) Strings(field string) []string {
+	switch value := r.Attributes[field].(type) {
+	case []string:
+		return value
+	case []any:
+		values := make([]string, 0, len(value))
+		for _, v := range value {
+			if s, ok := v.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReferenceType_Normalize(t *testing.T) {
+	tests := []struct {
+		name        string
+		refType     string
+		attributes  map[string]any
+		want        map[string]any
+		expectedErr string
+	}{
+		{
+			name:       "正常系: JSON の数値は int64、リストは重複と空の要素を除いた []string にそろえる",
+			refType:    ReferenceBrands,
+			attributes: map[string]any{"display_name": " ロレックス ", "aliases": []any{"rolex", " ", "rolex", "ROLEX"}},
+			want:       map[string]any{"display_name": "ロレックス", "aliases": []string{"rolex", "ROLEX"}},
+		},
+		{
+			name:       "正常系: null の項目は省略したものとして扱う",
+			refType:    ReferenceCategories,
+			attributes: map[string]any{"display_order": float64(3), "validation_profile": nil},
+			want:       map[string]any{"display_order": int64(3)},
+		},
+		{
+			name:        "異常系: 必須の項目がない",
+			refType:     ReferenceVocabularies,
+			attributes:  map[string]any{"terms": []any{}},
+			expectedErr: "terms must not be empty",
+		},
+		{
+			name:        "異常系: 指定できない値",
+			refType:     ReferenceClassificationRules,
+			attributes:  map[string]any{"field": "color", "contains": "red", "category": "時計"},
+			expectedErr: "field must be one of: name, brand",
+		},
+		{
+			name:        "異常系: 整数でない",
+			refType:     ReferenceValidationProfiles,
+			attributes:  map[string]any{"min_price": 1.5},
+			expectedErr: "min_price must be an integer",
+		},
+		{
+			name:        "異常系: 下限より小さい",
+			refType:     ReferenceCategories,
+			attributes:  map[string]any{"value_percent": float64(-10)},
+			expectedErr: "value_percent must be 0 or more",
+		},
+		{
+			name:        "異常系: 定義にない項目",
+			refType:     ReferenceCategories,
+			attributes:  map[string]any{"color": "red"},
+			expectedErr: "color is not a field of categories",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			definition, ok := FindReferenceType(tt.refType)
+			require.True(t, ok)
+
+			got, err := definition.Normalize(tt.attributes)
+			if tt.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package entity
+
+import "time"
+
+// アクセストークンを発行し直すためのリフレッシュトークン
+// ログインのたびに新しいファミリーを作り、使うたびに同じファミリーの新しいトークンと交換する
+// トークンは発行時にだけ返し、保存するのはハッシュ値のみ
+type RefreshToken struct {
+	ID        int64
+	UserID    int64
+	FamilyID  string
+	Token     string
+	TokenHash string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	UsedAt    *time.Time // 新しいトークンと交換した日時
+	RevokedAt *time.Time // ログアウトや再利用の検知で失効した日時
+}
+
+func NewRefreshToken(userID int64, familyID, token string, ttl time.Duration, now time.Time) *RefreshToken {
+	return &RefreshToken{
+		UserID:    userID,
+		FamilyID:  familyID,
+		Token:     token,
+		TokenHash: HashToken(token),
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+	}
+}
+
+// 期限内で、失効していないトークンか（交換済みかどうかは問わない）
+func (t *RefreshToken) Valid(now time.Time) bool {
+	return t.RevokedAt == nil && now.Before(t.ExpiresAt)
+}
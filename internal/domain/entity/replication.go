@@ -0,0 +1,21 @@
+package entity
+
+import "time"
+
+// 変更ストリームの 1 回分。スタンバイは LastSequence に追いつくまで取得を繰り返す
+type ChangeBatch struct {
+	Events       []*StoredEvent `json:"events"`
+	LastSequence int64          `json:"last_sequence"` // 取得時点の送信元のイベントストアの最後の連番
+}
+
+// スタンバイの複製の状況
+type ReplicationStatus struct {
+	Source          string     `json:"source"`
+	AppliedSequence int64      `json:"applied_sequence"` // 適用済みの送信元の連番
+	SourceSequence  int64      `json:"source_sequence"`  // 最後に確認した送信元の連番
+	LagEvents       int64      `json:"lag_events"`
+	LagSeconds      float64    `json:"lag_seconds"` // 最後に追いついた時点（まだの場合は起動時）からの経過時間
+	CaughtUpAt      *time.Time `json:"caught_up_at,omitempty"`
+	LastPolledAt    *time.Time `json:"last_polled_at,omitempty"`
+	LastError       string     `json:"last_error,omitempty"`
+}
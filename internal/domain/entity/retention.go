@@ -0,0 +1,63 @@
+package entity
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// 保持期間を設定できるデータの種類
+const (
+	RetentionAuditLogs         = "audit_logs"         // 監査ログ
+	RetentionDeletedItems      = "deleted_items"      // 統合・分割で論理削除したアイテム
+	RetentionWebhookDeliveries = "webhook_deliveries" // Webhook の配信記録
+)
+
+var RetentionDataTypes = []string{RetentionAuditLogs, RetentionDeletedItems, RetentionWebhookDeliveries}
+
+// データの種類ごとの保持期間。RetainDays が 0 の場合は削除しない
+type RetentionPolicy struct {
+	DataType   string     `json:"data_type"`
+	RetainDays int        `json:"retain_days"`
+	UpdatedAt  *time.Time `json:"updated_at,omitempty"` // デフォルト値のままの場合は nil
+}
+
+// 設定を変更していない場合の保持期間
+func DefaultRetentionPolicies() []*RetentionPolicy {
+	return []*RetentionPolicy{
+		{DataType: RetentionAuditLogs, RetainDays: 730},
+		{DataType: RetentionDeletedItems, RetainDays: 90},
+		{DataType: RetentionWebhookDeliveries, RetainDays: 30},
+	}
+}
+
+func IsRetentionDataType(dataType string) bool {
+	return slices.Contains(RetentionDataTypes, dataType)
+}
+
+func (p *RetentionPolicy) Validate() error {
+	if !IsRetentionDataType(p.DataType) {
+		return fmt.Errorf("unknown data type: %s", p.DataType)
+	}
+	if p.RetainDays < 0 {
+		return fmt.Errorf("retain_days must be 0 or greater")
+	}
+	return nil
+}
+
+// これより前のデータが削除対象になる日時。削除しない設定の場合は false
+func (p *RetentionPolicy) Cutoff(now time.Time) (time.Time, bool) {
+	if p.RetainDays == 0 {
+		return time.Time{}, false
+	}
+	return now.AddDate(0, 0, -p.RetainDays), true
+}
+
+// 保持期間の適用結果。DryRun の場合 Count は削除対象の件数で、実際には削除しない
+type RetentionResult struct {
+	DataType   string     `json:"data_type"`
+	RetainDays int        `json:"retain_days"`
+	Cutoff     *time.Time `json:"cutoff,omitempty"`
+	Count      int64      `json:"count"`
+	DryRun     bool       `json:"dry_run"`
+}
@@ -0,0 +1,59 @@
+package entity
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+// サンドボックスのデータセットに用意するアイテムの数
+const SandboxItemCount = 40
+
+// 架空のアイテムの元になる商品（カテゴリー・ブランド・名前と購入価格の範囲）
+var sandboxProducts = []struct {
+	category, brand, name string
+	minPrice, maxPrice    int
+}{
+	{"時計", "ROLEX", "ロレックス デイトナ", 1200000, 3500000},
+	{"時計", "ROLEX", "ロレックス サブマリーナー", 900000, 1800000},
+	{"時計", "OMEGA", "オメガ スピードマスター", 500000, 900000},
+	{"時計", "Cartier", "カルティエ タンク", 400000, 800000},
+	{"バッグ", "HERMÈS", "エルメス バーキン", 1500000, 4000000},
+	{"バッグ", "HERMÈS", "エルメス ケリー", 1200000, 3000000},
+	{"バッグ", "LOUIS VUITTON", "ルイ・ヴィトン ネヴァーフル", 150000, 300000},
+	{"バッグ", "CHANEL", "シャネル マトラッセ", 600000, 1500000},
+	{"ジュエリー", "Tiffany & Co.", "ティファニー ネックレス", 50000, 400000},
+	{"ジュエリー", "Cartier", "カルティエ ラブブレス", 700000, 1200000},
+	{"ジュエリー", "BVLGARI", "ブルガリ ビー・ゼロワン リング", 150000, 400000},
+	{"靴", "Christian Louboutin", "ルブタン パンプス", 90000, 180000},
+	{"靴", "JIMMY CHOO", "ジミー チュウ パンプス", 80000, 150000},
+	{"靴", "GUCCI", "グッチ ローファー", 90000, 160000},
+	{"その他", "Apple", "アップルウォッチ", 40000, 150000},
+	{"その他", "Montblanc", "モンブラン 万年筆", 60000, 200000},
+}
+
+// サンドボックスの API キーごとに用意する架空のアイテム（持ち主は ownerID）
+// 乱数の種を固定しているため、リセットするといつも同じ内容に戻る。購入日は now から 3 年以内
+func SandboxItems(ownerID int64, now time.Time) []*Item {
+	rng := rand.New(rand.NewPCG(1, 2))
+
+	items := make([]*Item, 0, SandboxItemCount)
+	for range SandboxItemCount {
+		product := sandboxProducts[rng.IntN(len(sandboxProducts))]
+		// 千円単位に丸める
+		price := (product.minPrice + rng.IntN(product.maxPrice-product.minPrice+1)) / 1000 * 1000
+		date := now.AddDate(0, 0, -1-rng.IntN(3*365)).UTC().Format("2006-01-02")
+
+		// 運用中にカテゴリーを変えていても作れるよう、Validate は通さない
+		items = append(items, &Item{
+			Name:          product.name,
+			Category:      product.category,
+			Brand:         product.brand,
+			PurchasePrice: price,
+			PurchaseDate:  date,
+			OwnerID:       &ownerID,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		})
+	}
+	return items
+}
@@ -0,0 +1,34 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSandboxItems(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("正常系: 持ち主のアイテムを決まった数だけ作る", func(t *testing.T) {
+		items := SandboxItems(7, now)
+
+		assert.Len(t, items, SandboxItemCount)
+		for _, item := range items {
+			assert.Equal(t, int64(7), *item.OwnerID)
+			assert.NoError(t, item.Validate())
+			assert.Less(t, item.PurchaseDate, "2024-06-01")
+			assert.GreaterOrEqual(t, item.PurchaseDate, "2021-06-01")
+		}
+	})
+
+	t.Run("正常系: 作り直しても同じ内容になる", func(t *testing.T) {
+		first, second := SandboxItems(7, now), SandboxItems(8, now)
+
+		for i := range first {
+			assert.Equal(t, first[i].Name, second[i].Name)
+			assert.Equal(t, first[i].PurchasePrice, second[i].PurchasePrice)
+			assert.Equal(t, first[i].PurchaseDate, second[i].PurchaseDate)
+		}
+	})
+}
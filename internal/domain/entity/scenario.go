@@ -0,0 +1,102 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 1 回のシナリオで指定できる調整の数
+const MaxScenarioAdjustments = 100
+
+// カテゴリーの評価額を何 % 増減させるかの仮定（"時計 +10%" なら Percent は 10）
+type ScenarioAdjustment struct {
+	Category string `json:"category"`
+	Percent  int64  `json:"percent"`
+}
+
+// 現在のスナップショットに仮定を当てはめた結果
+type ScenarioResult struct {
+	Baseline       *PortfolioSnapshot        `json:"baseline"`
+	ProjectedValue int64                     `json:"projected_value"` // 仮定を当てはめた評価額の合計
+	Change         int64                     `json:"change"`          // 現在の評価額との差
+	Categories     []*ScenarioCategoryResult `json:"categories"`
+}
+
+// カテゴリーごとの結果。調整を指定していないカテゴリーは Percent が 0 で、評価額は変わらない
+type ScenarioCategoryResult struct {
+	Category       string `json:"category"`
+	CurrentValue   int64  `json:"current_value"`
+	Percent        int64  `json:"percent"`
+	ProjectedValue int64  `json:"projected_value"`
+	Change         int64  `json:"change"`
+}
+
+// 調整を検証する。カテゴリーはスナップショットにあるもの（アイテムのあるもの）に限り、同じカテゴリーは 1 回だけ指定できる
+func ValidateScenario(baseline *PortfolioSnapshot, adjustments []ScenarioAdjustment) error {
+	var errs ValidationErrors
+	if len(adjustments) == 0 {
+		errs = append(errs, FieldError{"adjustments", "at least one adjustment is required"})
+	}
+	if len(adjustments) > MaxScenarioAdjustments {
+		errs = append(errs, FieldError{"adjustments", fmt.Sprintf("adjustments must be %d or fewer", MaxScenarioAdjustments)})
+	}
+
+	seen := map[string]bool{}
+	for i, adjustment := range adjustments {
+		field := fmt.Sprintf("adjustments[%d]", i)
+		category := strings.TrimSpace(adjustment.Category)
+		switch {
+		case category == "":
+			errs = append(errs, FieldError{field + ".category", "category is required"})
+		case seen[category]:
+			errs = append(errs, FieldError{field + ".category", fmt.Sprintf("category %s is adjusted more than once", category)})
+		case baseline.category(category) == nil:
+			errs = append(errs, FieldError{field + ".category", fmt.Sprintf("category %s has no items", category)})
+		}
+		seen[category] = true
+		if adjustment.Percent < -100 {
+			errs = append(errs, FieldError{field + ".percent", "percent must be -100 or more"})
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// baseline のカテゴリーごとの評価額に調整を当てはめる。adjustments は ValidateScenario で検証したもの
+func ApplyScenario(baseline *PortfolioSnapshot, adjustments []ScenarioAdjustment) *ScenarioResult {
+	percents := make(map[string]int64, len(adjustments))
+	for _, adjustment := range adjustments {
+		percents[strings.TrimSpace(adjustment.Category)] = adjustment.Percent
+	}
+
+	result := &ScenarioResult{
+		Baseline:   baseline,
+		Categories: make([]*ScenarioCategoryResult, 0, len(baseline.Categories)),
+	}
+	for _, group := range baseline.Categories {
+		percent := percents[group.Category]
+		projected := group.CurrentValue * (100 + percent) / 100
+		result.Categories = append(result.Categories, &ScenarioCategoryResult{
+			Category:       group.Category,
+			CurrentValue:   group.CurrentValue,
+			Percent:        percent,
+			ProjectedValue: projected,
+			Change:         projected - group.CurrentValue,
+		})
+		result.ProjectedValue += projected
+	}
+	result.Change = result.ProjectedValue - baseline.CurrentValue
+	return result
+}
+
+func (s *PortfolioSnapshot) category(name string) *PortfolioCategoryValue {
+	for _, group := range s.Categories {
+		if group.Category == name {
+			return group
+		}
+	}
+	return nil
+}
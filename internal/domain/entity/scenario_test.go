@@ -0,0 +1,85 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testScenarioBaseline() *PortfolioSnapshot {
+	return NewPortfolioSnapshot([]*Item{
+		{ID: 1, Category: "時計", PurchasePrice: 1000000},
+		{ID: 2, Category: "バッグ", PurchasePrice: 200000},
+		{ID: 3, Category: "靴", PurchasePrice: 50000},
+	}, map[string]int64{"時計": 110}, time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC))
+}
+
+func TestApplyScenario(t *testing.T) {
+	baseline := testScenarioBaseline()
+
+	result := ApplyScenario(baseline, []ScenarioAdjustment{
+		{Category: "時計", Percent: 10},
+		{Category: " バッグ ", Percent: -5},
+	})
+
+	assert.Same(t, baseline, result.Baseline)
+	require.Len(t, result.Categories, 3)
+	// カテゴリー名の順
+	assert.Equal(t, &ScenarioCategoryResult{Category: "バッグ", CurrentValue: 200000, Percent: -5, ProjectedValue: 190000, Change: -10000}, result.Categories[0])
+	assert.Equal(t, &ScenarioCategoryResult{Category: "時計", CurrentValue: 1100000, Percent: 10, ProjectedValue: 1210000, Change: 110000}, result.Categories[1])
+	assert.Equal(t, &ScenarioCategoryResult{Category: "靴", CurrentValue: 50000, ProjectedValue: 50000}, result.Categories[2])
+	assert.Equal(t, int64(1450000), result.ProjectedValue)
+	assert.Equal(t, int64(100000), result.Change)
+}
+
+func TestValidateScenario(t *testing.T) {
+	baseline := testScenarioBaseline()
+
+	tests := []struct {
+		name        string
+		adjustments []ScenarioAdjustment
+		wantErr     string
+	}{
+		{
+			name:        "正常系: アイテムのあるカテゴリーを -100% まで調整できる",
+			adjustments: []ScenarioAdjustment{{Category: "時計", Percent: 25}, {Category: "靴", Percent: -100}},
+		},
+		{
+			name:    "異常系: 調整がない",
+			wantErr: "at least one adjustment is required",
+		},
+		{
+			name:        "異常系: アイテムのないカテゴリー",
+			adjustments: []ScenarioAdjustment{{Category: "宝石", Percent: 10}},
+			wantErr:     "category 宝石 has no items",
+		},
+		{
+			name:        "異常系: 同じカテゴリーを 2 回指定",
+			adjustments: []ScenarioAdjustment{{Category: "時計", Percent: 10}, {Category: "時計 ", Percent: 5}},
+			wantErr:     "category 時計 is adjusted more than once",
+		},
+		{
+			name:        "異常系: -100% より小さい",
+			adjustments: []ScenarioAdjustment{{Category: "時計", Percent: -101}},
+			wantErr:     "percent must be -100 or more",
+		},
+		{
+			name:        "異常系: カテゴリーがない",
+			adjustments: []ScenarioAdjustment{{Percent: 10}},
+			wantErr:     "category is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateScenario(baseline, tt.adjustments)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package entity
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SLI（サービスレベル指標）の種類
+const (
+	SLIAvailability = "availability" // 5xx 以外で応答したリクエストの割合
+	SLILatency      = "latency"      // 閾値以内に応答したリクエストの割合
+)
+
+// ルートごとのサービスレベル目標
+type SLObjective struct {
+	Method           string        `json:"method"` // "*" で全メソッド
+	Route            string        `json:"route"`  // ルートのパターン（"/items/:id" など）。"*" で全ルート、path.Match の形式も使える
+	Availability     float64       `json:"availability,omitempty"`
+	LatencyThreshold time.Duration `json:"latency_threshold,omitempty"`
+	LatencyTarget    float64       `json:"latency_target,omitempty"`
+	// 燃焼率アラートを送るチャンネル。空の場合は設定済みのすべてのチャンネル
+	Channels []NotificationChannel `json:"channels,omitempty"`
+}
+
+func (o SLObjective) Matches(method, route string) bool {
+	if o.Method != "*" && !strings.EqualFold(o.Method, method) {
+		return false
+	}
+	if o.Route == "*" || o.Route == route {
+		return true
+	}
+	matched, _ := path.Match(o.Route, route)
+	return matched
+}
+
+// SLI の目標値。0 の場合はその SLI を追跡しない
+func (o SLObjective) Target(sli string) float64 {
+	switch sli {
+	case SLIAvailability:
+		return o.Availability
+	case SLILatency:
+		if o.LatencyThreshold <= 0 {
+			return 0
+		}
+		return o.LatencyTarget
+	}
+	return 0
+}
+
+// エラーバジェットを消費する速さ。1 でちょうど期間内にバジェットを使い切る
+func BurnRate(bad, total int64, target float64) float64 {
+	if total == 0 || target <= 0 || target >= 1 {
+		return 0
+	}
+	return (float64(bad) / float64(total)) / (1 - target)
+}
+
+// ルート・SLI ごとの現在の状況
+type SLOStatus struct {
+	Method    string             `json:"method"`
+	Route     string             `json:"route"`
+	SLI       string             `json:"sli"`
+	Target    float64            `json:"target"`
+	Requests  int64              `json:"requests"` // 起動してからの件数
+	Bad       int64              `json:"bad"`      // 起動してから目標を満たさなかった件数
+	BurnRates map[string]float64 `json:"burn_rates"`
+}
+
+// エラーバジェットの消費が速すぎることの通知
+type SLOAlert struct {
+	Method        string    `json:"method"`
+	Route         string    `json:"route"`
+	SLI           string    `json:"sli"`
+	Target        float64   `json:"target"`
+	ShortBurnRate float64   `json:"short_burn_rate"`
+	LongBurnRate  float64   `json:"long_burn_rate"`
+	Threshold     float64   `json:"threshold"`
+	FiredAt       time.Time `json:"fired_at"`
+	// 目標で指定した送り先（空の場合はすべてのチャンネル）
+	Channels []NotificationChannel `json:"channels,omitempty"`
+}
+
+func (a SLOAlert) String() string {
+	return fmt.Sprintf("%s %s %s SLO (%g) is burning its error budget %.1fx too fast (threshold %.1fx)",
+		a.Method, a.Route, a.SLI, a.Target, a.ShortBurnRate, a.Threshold)
+}
+
+// "GET /items availability=0.999 latency=300ms latency_target=0.99; * * availability=0.99" の形式の目標を読み込む
+// 目標は ; 区切りで、メソッド・ルートのパターンに続けて key=value で指定する
+// channels=line,slack でアラートを送るチャンネルを選べる
+func ParseSLObjectives(value string) ([]SLObjective, error) {
+	var objectives []SLObjective
+	for _, part := range strings.Split(value, ";") {
+		fields := strings.Fields(part)
+		if len(fields) == 0 {
+			continue
+		}
+		raw := strings.TrimSpace(part)
+		if len(fields) < 3 {
+			return nil, fmt.Errorf("slo %q: method, route and at least one objective are required", raw)
+		}
+
+		objective := SLObjective{Method: strings.ToUpper(fields[0]), Route: fields[1]}
+		for _, option := range fields[2:] {
+			key, rawValue, ok := strings.Cut(option, "=")
+			if !ok {
+				return nil, fmt.Errorf("slo %q: invalid option %q", raw, option)
+			}
+
+			var err error
+			switch key {
+			case "availability":
+				objective.Availability, err = parseRatio(rawValue)
+			case "latency":
+				objective.LatencyThreshold, err = time.ParseDuration(rawValue)
+				if err == nil && objective.LatencyThreshold <= 0 {
+					err = fmt.Errorf("must be positive")
+				}
+			case "latency_target":
+				objective.LatencyTarget, err = parseRatio(rawValue)
+			case "channels":
+				objective.Channels, err = ParseNotificationChannels(rawValue)
+			default:
+				err = fmt.Errorf("unknown option")
+			}
+			if err != nil {
+				return nil, fmt.Errorf("slo %q: %s: %w", raw, key, err)
+			}
+		}
+
+		if objective.LatencyThreshold > 0 && objective.LatencyTarget == 0 {
+			return nil, fmt.Errorf("slo %q: latency requires latency_target", raw)
+		}
+		if objective.LatencyTarget > 0 && objective.LatencyThreshold == 0 {
+			return nil, fmt.Errorf("slo %q: latency_target requires latency", raw)
+		}
+		if objective.Availability == 0 && objective.LatencyThreshold == 0 {
+			return nil, fmt.Errorf("slo %q: availability or latency is required", raw)
+		}
+		objectives = append(objectives, objective)
+	}
+	return objectives, nil
+}
+
+// 目標は 0 より大きく 1 より小さい割合（1 ではエラーバジェットがない）
+func parseRatio(value string) (float64, error) {
+	ratio, err := strconv.ParseFloat(value, 64)
+	if err != nil || ratio <= 0 || ratio >= 1 {
+		return 0, fmt.Errorf("must be greater than 0 and less than 1")
+	}
+	return ratio, nil
+}
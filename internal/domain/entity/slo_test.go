@@ -0,0 +1,40 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSLObjectives(t *testing.T) {
+	objectives, err := ParseSLObjectives("get /items availability=0.999 latency=300ms latency_target=0.99 channels=LINE,slack,line; * * availability=0.99;")
+	require.NoError(t, err)
+	assert.Equal(t, []SLObjective{
+		{Method: "GET", Route: "/items", Availability: 0.999, LatencyThreshold: 300 * time.Millisecond, LatencyTarget: 0.99,
+			Channels: []NotificationChannel{ChannelLINE, ChannelSlack}},
+		{Method: "*", Route: "*", Availability: 0.99},
+	}, objectives)
+	assert.True(t, objectives[1].Matches("DELETE", "/items/:id"))
+	assert.Zero(t, objectives[1].Target(SLILatency))
+
+	for _, invalid := range []string{
+		"GET /items",
+		"GET /items availability=1",
+		"GET /items availability=99.9",
+		"GET /items latency=300ms",
+		"GET /items latency_target=0.9",
+		"GET /items errors=0.1",
+		"GET /items availability=0.99 channels=fax",
+		"GET /items channels=line",
+	} {
+		_, err := ParseSLObjectives(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestBurnRate(t *testing.T) {
+	assert.InDelta(t, 2, BurnRate(2, 100, 0.99), 0.0001)
+	assert.Zero(t, BurnRate(0, 0, 0.99))
+}
@@ -0,0 +1,18 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// イベントストアに追記されたイベント
+// ペイロードは追記時点の最新のスキーマの版で保存し、読み出し時に最新の版へ変換する
+type StoredEvent struct {
+	Sequence      int64           `json:"sequence"` // 追記順の連番
+	EventID       string          `json:"event_id"`
+	EventType     string          `json:"event_type"`
+	ItemID        int64           `json:"item_id"`
+	SchemaVersion string          `json:"schema_version"`
+	Payload       json.RawMessage `json:"payload"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+}
@@ -0,0 +1,52 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 集計の軸
+type SummaryDimension string
+
+const (
+	DimensionCategory SummaryDimension = "category"
+	DimensionBrand    SummaryDimension = "brand"
+	DimensionYear     SummaryDimension = "year" // 購入年
+)
+
+var ValidSummaryDimensions = []SummaryDimension{DimensionCategory, DimensionBrand, DimensionYear}
+
+// 文字列を集計軸に変換する。空文字の場合はカテゴリー
+func ParseSummaryDimension(s string) (SummaryDimension, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return DimensionCategory, nil
+	}
+
+	for _, dim := range ValidSummaryDimensions {
+		if string(dim) == s {
+			return dim, nil
+		}
+	}
+
+	names := make([]string, len(ValidSummaryDimensions))
+	for i, dim := range ValidSummaryDimensions {
+		names[i] = string(dim)
+	}
+	return "", fmt.Errorf("group_by must be one of: %s", strings.Join(names, ", "))
+}
+
+// アイテムが属するグループのキー
+func (i *Item) SummaryKey(dim SummaryDimension) string {
+	switch dim {
+	case DimensionBrand:
+		return i.Brand
+	case DimensionYear:
+		if len(i.PurchaseDate) >= 4 {
+			return i.PurchaseDate[:4]
+		}
+		return ""
+	default:
+		return i.Category
+	}
+}
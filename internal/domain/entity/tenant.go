@@ -0,0 +1,34 @@
+package entity
+
+import "time"
+
+// 組織のデータを書き出す形式のバージョン。形式を変えたら上げる
+const TenantBundleVersion = 1
+
+// 別のデプロイに移すために書き出した 1 つの組織のデータ
+// ID はすべて書き出し元のもので、取り込み先では新しい ID を振り直す
+type TenantBundle struct {
+	Version      int           `json:"version"`
+	ExportedAt   time.Time     `json:"exported_at"`
+	Organization Organization  `json:"organization"`
+	Branding     *Branding     `json:"branding,omitempty"` // 設定していない場合は nil
+	Users        []*TenantUser `json:"users"`              // メンバーとアイテムの持ち主
+	Members      []*Membership `json:"members"`
+	Items        []*TenantItem `json:"items"`
+}
+
+// 書き出したユーザー。パスワードのハッシュと、アプリ全体でのロールは含めない
+type TenantUser struct {
+	ID          int64  `json:"id"`
+	UserName    string `json:"user_name"`
+	DisplayName string `json:"display_name,omitempty"`
+	Email       string `json:"email,omitempty"`
+	ExternalID  string `json:"external_id,omitempty"`
+	Active      bool   `json:"active"`
+}
+
+// 書き出したアイテムと価格変更履歴
+type TenantItem struct {
+	Item
+	PriceHistory []*PriceChange `json:"price_history,omitempty"`
+}
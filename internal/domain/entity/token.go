@@ -0,0 +1,12 @@
+package entity
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// 照合に使うトークンのハッシュ値。トークンそのものは保存しない
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
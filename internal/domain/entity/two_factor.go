@@ -0,0 +1,90 @@
+package entity
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"slices"
+	"strings"
+	"time"
+)
+
+// 確認したときに発行するリカバリーコードの数
+const RecoveryCodeCount = 10
+
+// ユーザーの二要素認証（TOTP）の設定
+type TwoFactor struct {
+	UserID int64
+	Secret string // 認証アプリと共有する鍵（base32）。TOTP の計算に使うため、ハッシュにせずに保存する
+	// 最初のコードを確かめて有効にした日時。nil の間は登録中で、ログインでは使わない
+	EnabledAt *time.Time
+	// 最後に使ったコードのステップ。同じコードを 2 回使えないよう、これ以前のステップは受け付けない
+	LastUsedStep       int64
+	RecoveryCodeHashes []string // 未使用のリカバリーコードのハッシュ値
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+}
+
+// 登録中の設定を作る。有効にするのは Enable で最初のコードを確かめてから
+func NewTwoFactor(userID int64, secret string, now time.Time) *TwoFactor {
+	return &TwoFactor{
+		UserID:    userID,
+		Secret:    secret,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+func (t *TwoFactor) Enabled() bool {
+	return t.EnabledAt != nil
+}
+
+// 有効にし、リカバリーコードを hashes に入れ替える
+func (t *TwoFactor) Enable(step int64, hashes []string, now time.Time) {
+	t.EnabledAt = &now
+	t.LastUsedStep = step
+	t.RecoveryCodeHashes = hashes
+	t.UpdatedAt = now
+}
+
+// 確かめたコードのステップを記録する。以前に使ったステップ以前のものは false
+func (t *TwoFactor) UseStep(step int64, now time.Time) bool {
+	if step <= t.LastUsedStep {
+		return false
+	}
+	t.LastUsedStep = step
+	t.UpdatedAt = now
+	return true
+}
+
+// 未使用のリカバリーコードであれば使用済みにする
+func (t *TwoFactor) UseRecoveryCode(code string, now time.Time) bool {
+	hash := HashToken(NormalizeRecoveryCode(code))
+	i := slices.Index(t.RecoveryCodeHashes, hash)
+	if i < 0 {
+		return false
+	}
+	t.RecoveryCodeHashes = slices.Delete(slices.Clone(t.RecoveryCodeHashes), i, i+1)
+	t.UpdatedAt = now
+	return true
+}
+
+// リカバリーコードを作り、コードとそのハッシュ値を返す。コードはこのときしか表示できない
+func NewRecoveryCodes() (codes, hashes []string, err error) {
+	encoding := base32.StdEncoding.WithPadding(base32.NoPadding)
+	for range RecoveryCodeCount {
+		b := make([]byte, 5)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+		code := strings.ToLower(encoding.EncodeToString(b)) // 8 文字
+		codes = append(codes, code[:4]+"-"+code[4:])
+		hashes = append(hashes, HashToken(code))
+	}
+	return codes, hashes, nil
+}
+
+// 入力されたリカバリーコードから区切りと空白を取り除き、小文字にそろえる
+func NormalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
@@ -0,0 +1,49 @@
+package entity
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTwoFactor_UseStep(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	twoFactor := NewTwoFactor(7, "SECRET", now)
+	twoFactor.Enable(100, nil, now)
+
+	assert.False(t, twoFactor.UseStep(99, now), "異常系: 以前のステップ")
+	assert.False(t, twoFactor.UseStep(100, now), "異常系: 同じステップ")
+	assert.True(t, twoFactor.UseStep(101, now), "正常系: 新しいステップ")
+	assert.Equal(t, int64(101), twoFactor.LastUsedStep)
+}
+
+func TestNewRecoveryCodes(t *testing.T) {
+	codes, hashes, err := NewRecoveryCodes()
+	require.NoError(t, err)
+	require.Len(t, codes, RecoveryCodeCount)
+	require.Len(t, hashes, RecoveryCodeCount)
+
+	for _, code := range codes {
+		assert.Regexp(t, `^[a-z2-7]{4}-[a-z2-7]{4}$`, code)
+	}
+
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	twoFactor := NewTwoFactor(7, "SECRET", now)
+	twoFactor.Enable(1, hashes, now)
+
+	t.Run("正常系: 大文字・区切りなしでも使える", func(t *testing.T) {
+		assert.True(t, twoFactor.UseRecoveryCode(strings.ToUpper(strings.ReplaceAll(codes[0], "-", "")), now))
+		assert.Len(t, twoFactor.RecoveryCodeHashes, RecoveryCodeCount-1)
+	})
+
+	t.Run("異常系: 使ったコードは使えない", func(t *testing.T) {
+		assert.False(t, twoFactor.UseRecoveryCode(codes[0], now))
+	})
+
+	t.Run("異常系: 発行していないコード", func(t *testing.T) {
+		assert.False(t, twoFactor.UseRecoveryCode("aaaa-aaaa", now))
+	})
+}
@@ -0,0 +1,130 @@
+package entity
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"Aicon-assignment/internal/pkg/filter"
+)
+
+// アプリ全体でのユーザーのロール（組織でのロールとは別）
+const (
+	UserRoleAdmin     = "admin"     // 一括削除・完全削除・運用者向けのエンドポイントを使える
+	UserRoleMember    = "member"    // 自分のアイテムだけを管理できる
+	UserRoleAppraiser = "appraiser" // アイテムの検証を担当し、鑑定書を添えて検証済みにできる
+)
+
+var UserRoles = []string{UserRoleAdmin, UserRoleMember, UserRoleAppraiser}
+
+// スタッフのアカウント。IdP から SCIM で作成・無効化される
+type User struct {
+	ID           int64     `json:"id"`
+	UserName     string    `json:"user_name"`
+	DisplayName  string    `json:"display_name,omitempty"`
+	Email        string    `json:"email,omitempty"`
+	ExternalID   string    `json:"external_id,omitempty"` // IdP 側のID
+	Active       bool      `json:"active"`
+	Role         string    `json:"role"`
+	PasswordHash string    `json:"-"` // bcrypt のハッシュ値。空の場合はパスワードでログインできない
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+func NewUserAt(now time.Time, userName, displayName, email, externalID string, active bool) (*User, error) {
+	user := &User{
+		UserName:    strings.TrimSpace(userName),
+		DisplayName: strings.TrimSpace(displayName),
+		Email:       strings.TrimSpace(email),
+		ExternalID:  strings.TrimSpace(externalID),
+		Active:      active,
+		Role:        UserRoleMember,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	if err := user.Validate(); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (u *User) Validate() error {
+	var errs []string
+
+	if u.UserName == "" {
+		errs = append(errs, "userName is required")
+	} else if len(u.UserName) > 255 {
+		errs = append(errs, "userName must be 255 characters or less")
+	}
+	if len(u.DisplayName) > 255 {
+		errs = append(errs, "displayName must be 255 characters or less")
+	}
+	if u.Email != "" && (len(u.Email) > 255 || !strings.Contains(u.Email, "@")) {
+		errs = append(errs, "email must be a valid address of 255 characters or less")
+	}
+	if len(u.ExternalID) > 255 {
+		errs = append(errs, "externalId must be 255 characters or less")
+	}
+	if !IsUserRole(u.Role) {
+		errs = append(errs, "role must be one of: admin, member")
+	}
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+func (u *User) IsAdmin() bool {
+	return u.Role == UserRoleAdmin
+}
+
+func IsUserRole(role string) bool {
+	return slices.Contains(UserRoles, role)
+}
+
+// 絞り込みに使えるユーザーのフィールド。active は 1 / 0 で比較する
+var UserFilterFields = filter.Fields{
+	"user_name":    filter.String,
+	"display_name": filter.String,
+	"email":        filter.String,
+	"external_id":  filter.String,
+	"active":       filter.Number,
+	"role":         filter.String,
+}
+
+// ユーザー一覧の取得条件。並びは常に ID の昇順
+type UserQuery struct {
+	Filter filter.Expr // nil の場合は絞り込まない
+	Limit  int         // 0 の場合は全件
+	Offset int
+}
+
+func (q UserQuery) Matches(user *User) bool {
+	return q.Filter == nil || filter.Evaluate(q.Filter, user.FilterValue)
+}
+
+func (u *User) FilterValue(field string) any {
+	switch field {
+	case "id":
+		return u.ID
+	case "user_name":
+		return u.UserName
+	case "display_name":
+		return u.DisplayName
+	case "email":
+		return u.Email
+	case "external_id":
+		return u.ExternalID
+	case "role":
+		return u.Role
+	case "active":
+		if u.Active {
+			return int64(1)
+		}
+		return int64(0)
+	}
+	return nil
+}
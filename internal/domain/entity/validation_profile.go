@@ -0,0 +1,54 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+)
+
+// カテゴリーのアイテムに適用する入力チェック（参照データの validation_profiles から組み立てる）
+type ValidationProfile struct {
+	Name     string
+	MinPrice *int64
+	MaxPrice *int64
+	Brands   []string // 空の場合はブランドを限らない
+}
+
+// プロファイルと、brand_vocabulary が指す用語集（ない場合は nil）から入力チェックを組み立てる
+func NewValidationProfile(profile, vocabulary *ReferenceRecord) *ValidationProfile {
+	p := &ValidationProfile{Name: profile.Key}
+	if price, ok := profile.Int("min_price"); ok {
+		p.MinPrice = &price
+	}
+	if price, ok := profile.Int("max_price"); ok {
+		p.MaxPrice = &price
+	}
+	if vocabulary != nil {
+		p.Brands = vocabulary.Strings("terms")
+	}
+	return p
+}
+
+// アイテムがプロファイルの条件を満たすかを確かめる。ブランドは大文字小文字を区別しない
+func (p *ValidationProfile) Check(item *Item) ValidationErrors {
+	var errs ValidationErrors
+	price := int64(item.PurchasePrice)
+	if p.MinPrice != nil && price < *p.MinPrice {
+		errs = append(errs, FieldError{"purchase_price", fmt.Sprintf("purchase_price must be %d or greater (%s)", *p.MinPrice, p.Name)})
+	}
+	if p.MaxPrice != nil && price > *p.MaxPrice {
+		errs = append(errs, FieldError{"purchase_price", fmt.Sprintf("purchase_price must be %d or less (%s)", *p.MaxPrice, p.Name)})
+	}
+	if len(p.Brands) > 0 && !containsFold(p.Brands, item.Brand) {
+		errs = append(errs, FieldError{"brand", fmt.Sprintf("brand must be one of the terms allowed by %s", p.Name)})
+	}
+	return errs
+}
+
+func containsFold(values []string, target string) bool {
+	for _, value := range values {
+		if strings.EqualFold(value, target) {
+			return true
+		}
+	}
+	return false
+}
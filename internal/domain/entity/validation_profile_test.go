@@ -0,0 +1,43 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidationProfile_Check(t *testing.T) {
+	minPrice, maxPrice := int64(10000), int64(5000000)
+	profile := &ValidationProfile{Name: "watch", MinPrice: &minPrice, MaxPrice: &maxPrice, Brands: []string{"ROLEX", "OMEGA"}}
+
+	tests := []struct {
+		name     string
+		item     Item
+		expected []string
+	}{
+		{
+			name: "正常系: ブランドは大文字小文字を区別しない",
+			item: Item{Brand: "rolex", PurchasePrice: 1500000},
+		},
+		{
+			name:     "異常系: 価格が下限より低い",
+			item:     Item{Brand: "ROLEX", PurchasePrice: 5000},
+			expected: []string{"purchase_price must be 10000 or greater (watch)"},
+		},
+		{
+			name:     "異常系: 価格が上限より高く、ブランドが用語集にない",
+			item:     Item{Brand: "SEIKO", PurchasePrice: 6000000},
+			expected: []string{"purchase_price must be 5000000 or less (watch)", "brand must be one of the terms allowed by watch"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var messages []string
+			for _, e := range profile.Check(&tt.item) {
+				messages = append(messages, e.Message)
+			}
+			assert.Equal(t, tt.expected, messages)
+		})
+	}
+}
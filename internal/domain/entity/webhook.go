@@ -0,0 +1,200 @@
+package entity
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"text/template"
+	"time"
+
+	"Aicon-assignment/internal/pkg/filter"
+	"Aicon-assignment/internal/pkg/listquery"
+)
+
+// すべてのイベントを購読する場合の指定
+const WebhookAllEvents = "*"
+
+// Webhookの購読設定
+type Webhook struct {
+	ID      int64    `json:"id"`
+	OwnerID *int64   `json:"owner_id,omitempty"` // 登録したユーザー（持ち主を記録する前に登録したものは nil）
+	URL     string   `json:"url"`
+	Events  []string `json:"events"`
+	// 更新イベントを受け取るフィールド。空の場合はすべての更新を受け取る
+	WatchFields []string `json:"watch_fields,omitempty"`
+	// 受け取るイベントスキーマの版（v1, v2 ...）
+	EventVersion string `json:"event_version"`
+	// ペイロードのテンプレート（Go の text/template）。空の場合はイベントをそのままJSONにする
+	PayloadTemplate string    `json:"payload_template,omitempty"`
+	Secret          string    `json:"-"` // 署名用の共有シークレット
+	Active          bool      `json:"active"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+func NewWebhook(rawURL string, events []string, payloadTemplate, secret string, now time.Time) (*Webhook, error) {
+	webhook := &Webhook{
+		URL:             strings.TrimSpace(rawURL),
+		Events:          normalizeEvents(events),
+		PayloadTemplate: payloadTemplate,
+		Secret:          secret,
+		Active:          true,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+
+	if err := webhook.Validate(); err != nil {
+		return nil, err
+	}
+
+	return webhook, nil
+}
+
+func (w *Webhook) Validate() error {
+	var errs []string
+
+	if w.URL == "" {
+		errs = append(errs, "url is required")
+	} else if u, err := url.Parse(w.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		errs = append(errs, "url must be an absolute http(s) URL")
+	}
+
+	if len(w.Events) == 0 {
+		errs = append(errs, "events is required")
+	}
+	for _, event := range w.Events {
+		if event != WebhookAllEvents && !IsValidEventType(event) {
+			errs = append(errs, fmt.Sprintf("unknown event type: %s", event))
+		}
+	}
+
+	for _, field := range w.WatchFields {
+		if !isWatchableField(field) {
+			errs = append(errs, fmt.Sprintf("unknown watch field: %s", field))
+		}
+	}
+
+	if _, err := w.parseTemplate(); err != nil {
+		errs = append(errs, fmt.Sprintf("payload_template is invalid: %s", err.Error()))
+	}
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, ", "))
+	}
+
+	return nil
+}
+
+// イベントを購読しているか
+func (w *Webhook) Subscribes(eventType string) bool {
+	for _, event := range w.Events {
+		if event == WebhookAllEvents || event == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// イベントを配信するか
+// 監視フィールドを指定している場合、更新イベントはそのフィールドが変わったときだけ配信する
+func (w *Webhook) Wants(event *Event) bool {
+	if !w.Subscribes(event.Type) {
+		return false
+	}
+	if event.Type != EventItemUpdated || len(w.WatchFields) == 0 {
+		return true
+	}
+	for _, watched := range w.WatchFields {
+		for _, changed := range event.ChangedFields {
+			if watched == changed {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// 購読している版の形に変換したイベントからペイロードを組み立てる
+// テンプレートの出力は有効なJSONでなければならない
+func (w *Webhook) RenderPayload(event any) ([]byte, error) {
+	if w.PayloadTemplate == "" {
+		return json.Marshal(event)
+	}
+
+	tmpl, err := w.parseTemplate()
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, event); err != nil {
+		return nil, fmt.Errorf("failed to render payload: %w", err)
+	}
+	if !json.Valid(buf.Bytes()) {
+		return nil, errors.New("rendered payload is not valid JSON")
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (w *Webhook) parseTemplate() (*template.Template, error) {
+	return template.New("payload").
+		Option("missingkey=error").
+		Funcs(payloadTemplateFuncs).
+		Parse(w.PayloadTemplate)
+}
+
+// テンプレート内で使える関数
+var payloadTemplateFuncs = template.FuncMap{
+	// 値をJSONとして埋め込む（文字列のエスケープ漏れを防ぐ）
+	"json": func(v any) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+	"formatTime": func(layout string, t time.Time) string {
+		return t.Format(layout)
+	},
+}
+
+func isWatchableField(field string) bool {
+	for _, watchable := range ItemWatchableFields {
+		if field == watchable {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeEvents(events []string) []string {
+	normalized := make([]string, 0, len(events))
+	for _, event := range events {
+		if event = strings.TrimSpace(event); event != "" {
+			normalized = append(normalized, event)
+		}
+	}
+	return normalized
+}
+
+// GET /webhooks で使える絞り込み・並び替え
+var WebhookListSpec = listquery.Spec{
+	Filters:     filter.Fields{"url": filter.String, "event_version": filter.String},
+	Sorts:       []string{"id", "url", "created_at"},
+	DefaultSort: []listquery.SortField{{Field: "id"}},
+}
+
+func (w *Webhook) FilterValue(field string) any {
+	switch field {
+	case "id":
+		return w.ID
+	case "url":
+		return w.URL
+	case "event_version":
+		return w.EventVersion
+	case "created_at":
+		return w.CreatedAt.UnixNano()
+	}
+	return nil
+}
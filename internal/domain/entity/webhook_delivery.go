@@ -0,0 +1,78 @@
+package entity
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"Aicon-assignment/internal/pkg/filter"
+	"Aicon-assignment/internal/pkg/listquery"
+)
+
+// 配信結果によるフィルター
+const (
+	DeliveryStatusSuccess = "success"
+	DeliveryStatusFailed  = "failed"
+)
+
+// Webhookの配信試行の記録
+type WebhookDelivery struct {
+	ID            int64           `json:"id"`
+	WebhookID     int64           `json:"webhook_id"`
+	EventID       string          `json:"event_id"`
+	EventType     string          `json:"event_type"`
+	Payload       json.RawMessage `json:"payload"`
+	SchemaVersion string          `json:"schema_version,omitempty"` // テンプレートなしで送った場合のイベントの版
+	Success       bool            `json:"success"`
+	StatusCode    int             `json:"status_code,omitempty"`
+	DurationMs    int64           `json:"duration_ms"`
+	Error         string          `json:"error,omitempty"`
+	RedeliveryOf  *int64          `json:"redelivery_of,omitempty"` // 再配信の場合は元の配信ID
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+// 配信の集計
+type WebhookDeliveryStats struct {
+	WebhookID      int64      `json:"webhook_id"`
+	Total          int        `json:"total"`
+	Succeeded      int        `json:"succeeded"`
+	Failed         int        `json:"failed"`
+	AvgDurationMs  float64    `json:"avg_duration_ms"`
+	LastDeliveryAt *time.Time `json:"last_delivery_at,omitempty"`
+}
+
+// 配信結果のフィルター値を検証する。空文字はフィルターなし
+func ValidateDeliveryStatus(status string) error {
+	switch status {
+	case "", DeliveryStatusSuccess, DeliveryStatusFailed:
+		return nil
+	}
+	return fmt.Errorf("status must be one of: %s, %s", DeliveryStatusSuccess, DeliveryStatusFailed)
+}
+
+// GET /webhooks/:id/deliveries で使える絞り込み・並び替え
+var WebhookDeliveryListSpec = listquery.Spec{
+	Filters: filter.Fields{
+		"event_type":  filter.String,
+		"status_code": filter.Number,
+		"duration_ms": filter.Number,
+	},
+	Sorts:       []string{"id", "status_code", "duration_ms", "created_at"},
+	DefaultSort: []listquery.SortField{{Field: "created_at", Desc: true}, {Field: "id", Desc: true}},
+}
+
+func (d *WebhookDelivery) FilterValue(field string) any {
+	switch field {
+	case "id":
+		return d.ID
+	case "event_type":
+		return d.EventType
+	case "status_code":
+		return int64(d.StatusCode)
+	case "duration_ms":
+		return d.DurationMs
+	case "created_at":
+		return d.CreatedAt.UnixNano()
+	}
+	return nil
+}
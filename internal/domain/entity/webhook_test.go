@@ -0,0 +1,128 @@
+package entity
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewWebhook(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		url         string
+		events      []string
+		template    string
+		wantErr     bool
+		expectedErr string
+	}{
+		{
+			name:   "正常系: テンプレートなし",
+			url:    "https://example.com/hook",
+			events: []string{EventItemCreated},
+		},
+		{
+			name:     "正常系: テンプレートあり・全イベント購読",
+			url:      "https://example.com/hook",
+			events:   []string{"*"},
+			template: `{"text": {{json .Item.Name}}}`,
+		},
+		{
+			name:        "異常系: URLが不正",
+			url:         "ftp://example.com",
+			events:      []string{EventItemCreated},
+			wantErr:     true,
+			expectedErr: "url must be an absolute http(s) URL",
+		},
+		{
+			name:        "異常系: 未知のイベント",
+			url:         "https://example.com/hook",
+			events:      []string{"item.exploded"},
+			wantErr:     true,
+			expectedErr: "unknown event type: item.exploded",
+		},
+		{
+			name:        "異常系: テンプレートの構文エラー",
+			url:         "https://example.com/hook",
+			events:      []string{EventItemCreated},
+			template:    `{"text": {{.Item.Name}`,
+			wantErr:     true,
+			expectedErr: "payload_template is invalid",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			webhook, err := NewWebhook(tt.url, tt.events, tt.template, "", now)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				assert.Nil(t, webhook)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.True(t, webhook.Active)
+		})
+	}
+}
+
+func TestWebhook_RenderPayload(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	item, _ := NewItemAt(now, "ロレックス \"デイトナ\"", "時計", "ROLEX", 1500000, "2023-01-15")
+	event := &Event{ID: "ev-1", Type: EventItemCreated, OccurredAt: now, ItemID: 1, Item: item}
+
+	t.Run("正常系: テンプレートなしはイベントそのまま", func(t *testing.T) {
+		webhook := &Webhook{}
+		payload, err := webhook.RenderPayload(event)
+		require.NoError(t, err)
+
+		var decoded Event
+		require.NoError(t, json.Unmarshal(payload, &decoded))
+		assert.Equal(t, "ev-1", decoded.ID)
+	})
+
+	t.Run("正常系: テンプレートで形を変える", func(t *testing.T) {
+		webhook := &Webhook{PayloadTemplate: `{"text": {{json .Item.Name}}, "date": "{{formatTime "2006-01-02" .OccurredAt}}"}`}
+		payload, err := webhook.RenderPayload(event)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"text": "ロレックス \"デイトナ\"", "date": "2024-01-01"}`, string(payload))
+	})
+
+	t.Run("異常系: JSONにならないテンプレート", func(t *testing.T) {
+		webhook := &Webhook{PayloadTemplate: `text={{.Item.Name}}`}
+		_, err := webhook.RenderPayload(event)
+		assert.EqualError(t, err, "rendered payload is not valid JSON")
+	})
+}
+
+func TestWebhook_Subscribes(t *testing.T) {
+	webhook := &Webhook{Events: []string{EventItemCreated}}
+	assert.True(t, webhook.Subscribes(EventItemCreated))
+	assert.False(t, webhook.Subscribes(EventItemDeleted))
+
+	all := &Webhook{Events: []string{WebhookAllEvents}}
+	assert.True(t, all.Subscribes(EventItemDeleted))
+}
+
+func TestWebhook_Wants(t *testing.T) {
+	webhook := &Webhook{Events: []string{WebhookAllEvents}, WatchFields: []string{"purchase_price"}}
+
+	assert.True(t, webhook.Wants(&Event{Type: EventItemUpdated, ChangedFields: []string{"name", "purchase_price"}}))
+	assert.False(t, webhook.Wants(&Event{Type: EventItemUpdated, ChangedFields: []string{"name"}}))
+	// 更新以外のイベントは監視フィールドに関係なく配信する
+	assert.True(t, webhook.Wants(&Event{Type: EventItemCreated}))
+
+	all := &Webhook{Events: []string{EventItemUpdated}}
+	assert.True(t, all.Wants(&Event{Type: EventItemUpdated, ChangedFields: []string{"brand"}}))
+}
+
+func TestWebhook_ValidateWatchFields(t *testing.T) {
+	webhook := &Webhook{URL: "https://example.com/hook", Events: []string{EventItemUpdated}, WatchFields: []string{"purchase_price", "color"}}
+	assert.EqualError(t, webhook.Validate(), "unknown watch field: color")
+}
@@ -3,14 +3,91 @@ package errors
 import "errors"
 
 var (
-	ErrItemNotFound   = errors.New("item not found")
-	ErrInvalidInput   = errors.New("invalid input")
-	ErrDatabaseError  = errors.New("database error")
-	ErrDuplicateEntry = errors.New("duplicate entry")
+	ErrItemNotFound          = errors.New("item not found")
+	ErrWebhookNotFound       = errors.New("webhook not found")
+	ErrDeliveryNotFound      = errors.New("webhook delivery not found")
+	ErrRetentionNotFound     = errors.New("retention policy not found")
+	ErrImpersonationNotFound = errors.New("impersonation session not found")
+	ErrAPIKeyNotFound        = errors.New("api key not found")
+	ErrRefreshTokenNotFound  = errors.New("refresh token not found")
+	ErrIdentityNotFound      = errors.New("linked identity not found")
+	ErrOIDCLoginNotFound     = errors.New("login request not found or expired")
+	ErrTwoFactorNotFound     = errors.New("two-factor authentication is not set up")
+	ErrPreferenceNotFound    = errors.New("notification preference not found")
+	ErrTwoFactorRequired     = errors.New("two-factor code required")
+	ErrInvalidTwoFactorCode  = errors.New("invalid two-factor code")
+	ErrPasswordResetNotFound = errors.New("password reset token is invalid or expired")
+	ErrReferenceTypeNotFound = errors.New("unknown reference data type")
+	ErrReferenceNotFound     = errors.New("reference data not found")
+	ErrReferenceInUse        = errors.New("reference data is in use")
+	ErrStalePreview          = errors.New("data changed since the preview")
+	ErrUserNotFound          = errors.New("user not found")
+	ErrOrganizationNotFound  = errors.New("organization not found")
+	ErrMemberNotFound        = errors.New("organization member not found")
+	ErrInvitationNotFound    = errors.New("invitation not found or no longer valid")
+	ErrBrandingNotFound      = errors.New("branding not configured")
+	ErrExportNotFound        = errors.New("export not found")
+	ErrBlobNotFound          = errors.New("file not found")
+	ErrChecksumMismatch      = errors.New("stored file does not match its checksum")
+	ErrAttachmentNotFound    = errors.New("attachment not found")
+	ErrImageNotFound         = errors.New("image not found")
+	ErrThumbnailNotFound     = errors.New("thumbnail not found")
+	ErrReceiptNotFound       = errors.New("receipt not found")
+	ErrQuarantineNotFound    = errors.New("quarantined file not found")
+	ErrImportProfileNotFound = errors.New("import profile not found")
+	ErrImportBatchNotFound   = errors.New("import not found or expired")
+	ErrStagedRowNotFound     = errors.New("staged import row not found")
+	ErrImportCommitted       = errors.New("import has already been committed")
+	ErrConsumerNotFound      = errors.New("event consumer not found")
+	ErrBatchInFlight         = errors.New("delivered events are awaiting acknowledgement")
+	ErrVerificationState     = errors.New("operation not allowed in the current verification state")
+	ErrFieldLocked           = errors.New("field is locked")
+	ErrInfectedFile          = errors.New("file is infected and has been quarantined")
+	ErrScanUnavailable       = errors.New("virus scan is unavailable")
+	ErrInvalidInput          = errors.New("invalid input")
+	ErrDatabaseError         = errors.New("database error")
+	ErrDuplicateEntry        = errors.New("duplicate entry")
+	ErrConstraintViolation   = errors.New("constraint violation")
+	ErrSerializationFailure  = errors.New("serialization failure")
+	ErrConnectionLost        = errors.New("database connection lost")
+	ErrReasonRequired        = errors.New("reason is required for this operation")
+	ErrUnauthenticated       = errors.New("authentication required")
+	ErrForbidden             = errors.New("operation not permitted")
+	ErrLastAdmin             = errors.New("organization must keep at least one admin")
+	ErrLastUserAdmin         = errors.New("at least one active admin user must remain")
 )
 
 func IsNotFoundError(err error) bool {
-	return errors.Is(err, ErrItemNotFound)
+	return errors.Is(err, ErrItemNotFound) ||
+		errors.Is(err, ErrWebhookNotFound) ||
+		errors.Is(err, ErrDeliveryNotFound) ||
+		errors.Is(err, ErrRetentionNotFound) ||
+		errors.Is(err, ErrImpersonationNotFound) ||
+		errors.Is(err, ErrAPIKeyNotFound) ||
+		errors.Is(err, ErrRefreshTokenNotFound) ||
+		errors.Is(err, ErrIdentityNotFound) ||
+		errors.Is(err, ErrOIDCLoginNotFound) ||
+		errors.Is(err, ErrTwoFactorNotFound) ||
+		errors.Is(err, ErrPreferenceNotFound) ||
+		errors.Is(err, ErrPasswordResetNotFound) ||
+		errors.Is(err, ErrReferenceTypeNotFound) ||
+		errors.Is(err, ErrReferenceNotFound) ||
+		errors.Is(err, ErrUserNotFound) ||
+		errors.Is(err, ErrOrganizationNotFound) ||
+		errors.Is(err, ErrMemberNotFound) ||
+		errors.Is(err, ErrInvitationNotFound) ||
+		errors.Is(err, ErrBrandingNotFound) ||
+		errors.Is(err, ErrExportNotFound) ||
+		errors.Is(err, ErrBlobNotFound) ||
+		errors.Is(err, ErrAttachmentNotFound) ||
+		errors.Is(err, ErrImageNotFound) ||
+		errors.Is(err, ErrThumbnailNotFound) ||
+		errors.Is(err, ErrReceiptNotFound) ||
+		errors.Is(err, ErrQuarantineNotFound) ||
+		errors.Is(err, ErrImportProfileNotFound) ||
+		errors.Is(err, ErrImportBatchNotFound) ||
+		errors.Is(err, ErrStagedRowNotFound) ||
+		errors.Is(err, ErrConsumerNotFound)
 }
 
 func IsDatabaseError(err error) bool {
@@ -20,3 +97,63 @@ func IsDatabaseError(err error) bool {
 func IsValidationError(err error) bool {
 	return errors.Is(err, ErrInvalidInput)
 }
+
+// 一意制約違反（409 Conflict 相当）
+func IsConflictError(err error) bool {
+	return errors.Is(err, ErrDuplicateEntry)
+}
+
+// 外部キー制約違反など、整合性エラー（422 Unprocessable Entity 相当）
+func IsConstraintError(err error) bool {
+	return errors.Is(err, ErrConstraintViolation)
+}
+
+// 接続断など、DBが利用できない状態（503 Service Unavailable 相当）
+func IsUnavailableError(err error) bool {
+	return errors.Is(err, ErrConnectionLost)
+}
+
+// リトライで回復する可能性のある一時的なエラー
+func IsTransientError(err error) bool {
+	return errors.Is(err, ErrSerializationFailure) || errors.Is(err, ErrConnectionLost)
+}
+
+// ポリシーにより理由の入力が必要な操作で、理由が指定されていない
+func IsReasonRequiredError(err error) bool {
+	return errors.Is(err, ErrReasonRequired)
+}
+
+// 呼び出し元のユーザーが特定できない（401 Unauthorized 相当）
+func IsUnauthenticatedError(err error) bool {
+	return errors.Is(err, ErrUnauthenticated)
+}
+
+// 二要素認証のコードがない、または違う
+func IsTwoFactorError(err error) bool {
+	return errors.Is(err, ErrTwoFactorRequired) || errors.Is(err, ErrInvalidTwoFactorCode)
+}
+
+// 呼び出し元のユーザーには許可されていない操作（403 Forbidden 相当）
+func IsForbiddenError(err error) bool {
+	return errors.Is(err, ErrForbidden)
+}
+
+// 他のデータから参照されているため削除できない（409 Conflict 相当）
+func IsInUseError(err error) bool {
+	return errors.Is(err, ErrReferenceInUse)
+}
+
+// プレビューで確認した内容と現在のデータが食い違っている（409 Conflict 相当）
+func IsStalePreviewError(err error) bool {
+	return errors.Is(err, ErrStalePreview)
+}
+
+// 検証の状態に合わない操作、または検証済みでロックされたフィールドの変更（409 Conflict 相当）
+func IsLockedError(err error) bool {
+	return errors.Is(err, ErrVerificationState) || errors.Is(err, ErrFieldLocked)
+}
+
+// 組織（またはアプリ全体）から最後の管理者がいなくなる操作
+func IsLastAdminError(err error) bool {
+	return errors.Is(err, ErrLastAdmin) || errors.Is(err, ErrLastUserAdmin)
+}
@@ -0,0 +1,107 @@
+// Package eventschema は外部に公開するイベントの版（スキーマ）を管理する。
+// ドメインイベント（entity.Event）の形が変わっても、購読者は固定した版の形で受け取れる。
+package eventschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"Aicon-assignment/internal/domain/entity"
+)
+
+// イベントスキーマの1つの版
+type Schema struct {
+	Version string
+	// ドメインイベントをこの版の形に変換する
+	Encode func(event *entity.Event) any
+	// 1つ前の版のペイロードをこの版の形に変換する。最初の版では nil
+	Upcast func(previous []byte) (any, error)
+	// この版のペイロードをドメインイベントに戻す
+	Decode func(payload []byte) (*entity.Event, error)
+}
+
+// 版の一覧。登録順が古い順になる
+type Registry struct {
+	schemas []Schema
+}
+
+func NewRegistry(schemas ...Schema) *Registry {
+	return &Registry{schemas: schemas}
+}
+
+// アプリケーションで使う版の一覧
+var Default = NewRegistry(schemaV1, schemaV2)
+
+func (r *Registry) Versions() []string {
+	versions := make([]string, 0, len(r.schemas))
+	for _, schema := range r.schemas {
+		versions = append(versions, schema.Version)
+	}
+	return versions
+}
+
+func (r *Registry) Latest() string {
+	return r.schemas[len(r.schemas)-1].Version
+}
+
+func (r *Registry) Validate(version string) error {
+	if r.indexOf(version) < 0 {
+		return fmt.Errorf("unknown event version: %s (supported: %s)", version, strings.Join(r.Versions(), ", "))
+	}
+	return nil
+}
+
+// ドメインイベントを指定した版の形に変換する
+func (r *Registry) Encode(event *entity.Event, version string) (any, error) {
+	i := r.indexOf(version)
+	if i < 0 {
+		return nil, r.Validate(version)
+	}
+	return r.schemas[i].Encode(event), nil
+}
+
+// 古い版のペイロードを新しい版まで順に変換する
+func (r *Registry) Upcast(payload []byte, from, to string) ([]byte, error) {
+	fromIndex, toIndex := r.indexOf(from), r.indexOf(to)
+	if fromIndex < 0 {
+		return nil, r.Validate(from)
+	}
+	if toIndex < 0 {
+		return nil, r.Validate(to)
+	}
+	if fromIndex > toIndex {
+		return nil, fmt.Errorf("cannot downcast event from %s to %s", from, to)
+	}
+
+	for i := fromIndex + 1; i <= toIndex; i++ {
+		upcasted, err := r.schemas[i].Upcast(payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to upcast event to %s: %w", r.schemas[i].Version, err)
+		}
+		if payload, err = json.Marshal(upcasted); err != nil {
+			return nil, err
+		}
+	}
+
+	return payload, nil
+}
+
+// 保存済みのペイロードを最新の版まで変換してドメインイベントに戻す
+func (r *Registry) Decode(payload []byte, version string) (*entity.Event, error) {
+	latest := r.Latest()
+	upcasted, err := r.Upcast(payload, version, latest)
+	if err != nil {
+		return nil, err
+	}
+	return r.schemas[len(r.schemas)-1].Decode(upcasted)
+}
+
+func (r *Registry) indexOf(version string) int {
+	for i, schema := range r.schemas {
+		if schema.Version == version {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,102 @@
+package eventschema
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"Aicon-assignment/internal/domain/entity"
+)
+
+func sampleEvent(t *testing.T) *entity.Event {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	item, err := entity.NewItemAt(now, "ロレックス デイトナ", "時計", "ROLEX", 1500000, "2023-01-15")
+	require.NoError(t, err)
+	item.ID = 1
+	return &entity.Event{ID: "ev-1", Type: entity.EventItemCreated, OccurredAt: now, Actor: "anonymous", ItemID: 1, Item: item}
+}
+
+func TestRegistry_Encode(t *testing.T) {
+	event := sampleEvent(t)
+
+	tests := []struct {
+		name     string
+		version  string
+		wantErr  bool
+		expected string
+	}{
+		{
+			name:     "正常系: v1は価格が数値",
+			version:  V1,
+			expected: `"purchase_price":1500000`,
+		},
+		{
+			name:     "正常系: v2は価格が通貨付き",
+			version:  V2,
+			expected: `"purchase_price":{"amount":1500000,"currency":"JPY"}`,
+		},
+		{
+			name:    "異常系: 未知の版",
+			version: "v9",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded, err := Default.Encode(event, tt.version)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			payload, err := json.Marshal(encoded)
+			require.NoError(t, err)
+			assert.Contains(t, string(payload), tt.expected)
+		})
+	}
+}
+
+func TestRegistry_Upcast(t *testing.T) {
+	event := sampleEvent(t)
+	v1, err := Default.Encode(event, V1)
+	require.NoError(t, err)
+	v1Payload, err := json.Marshal(v1)
+	require.NoError(t, err)
+
+	t.Run("正常系: v1をv2に変換するとv2で直接変換した結果と一致する", func(t *testing.T) {
+		upcasted, err := Default.Upcast(v1Payload, V1, V2)
+		require.NoError(t, err)
+
+		v2, err := Default.Encode(event, V2)
+		require.NoError(t, err)
+		expected, err := json.Marshal(v2)
+		require.NoError(t, err)
+
+		assert.JSONEq(t, string(expected), string(upcasted))
+	})
+
+	t.Run("正常系: 同じ版はそのまま", func(t *testing.T) {
+		upcasted, err := Default.Upcast(v1Payload, V1, V1)
+		require.NoError(t, err)
+		assert.Equal(t, v1Payload, upcasted)
+	})
+
+	t.Run("正常系: 古い版のペイロードからドメインイベントに戻せる", func(t *testing.T) {
+		decoded, err := Default.Decode(v1Payload, V1)
+		require.NoError(t, err)
+		assert.Equal(t, event.ID, decoded.ID)
+		assert.Equal(t, event.Item.Name, decoded.Item.Name)
+		assert.Equal(t, event.Item.PurchasePrice, decoded.Item.PurchasePrice)
+		assert.True(t, event.Item.CreatedAt.Equal(decoded.Item.CreatedAt))
+	})
+
+	t.Run("異常系: 古い版には戻せない", func(t *testing.T) {
+		_, err := Default.Upcast(v1Payload, V2, V1)
+		assert.Error(t, err)
+	})
+}
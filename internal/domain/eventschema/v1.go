@@ -0,0 +1,84 @@
+package eventschema
+
+import (
+	"encoding/json"
+	"time"
+
+	"Aicon-assignment/internal/domain/entity"
+)
+
+const V1 = "v1"
+
+// v1: 最初に公開した形。entity.Event に項目が増えても変えない
+type EventV1 struct {
+	ID         string    `json:"id"`
+	Type       string    `json:"type"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Actor      string    `json:"actor"`
+	ItemID     int64     `json:"item_id"`
+	Item       *ItemV1   `json:"item,omitempty"`
+}
+
+type ItemV1 struct {
+	ID            int64     `json:"id"`
+	Name          string    `json:"name"`
+	Category      string    `json:"category"`
+	Brand         string    `json:"brand"`
+	PurchasePrice int       `json:"purchase_price"`
+	PurchaseDate  string    `json:"purchase_date"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+var schemaV1 = Schema{
+	Version: V1,
+	Encode: func(event *entity.Event) any {
+		encoded := &EventV1{
+			ID:         event.ID,
+			Type:       event.Type,
+			OccurredAt: event.OccurredAt,
+			Actor:      event.Actor,
+			ItemID:     event.ItemID,
+		}
+		if item := event.Item; item != nil {
+			encoded.Item = &ItemV1{
+				ID:            item.ID,
+				Name:          item.Name,
+				Category:      item.Category,
+				Brand:         item.Brand,
+				PurchasePrice: item.PurchasePrice,
+				PurchaseDate:  item.PurchaseDate,
+				CreatedAt:     item.CreatedAt,
+				UpdatedAt:     item.UpdatedAt,
+			}
+		}
+		return encoded
+	},
+	Decode: func(payload []byte) (*entity.Event, error) {
+		var event EventV1
+		if err := json.Unmarshal(payload, &event); err != nil {
+			return nil, err
+		}
+
+		decoded := &entity.Event{
+			ID:         event.ID,
+			Type:       event.Type,
+			OccurredAt: event.OccurredAt,
+			Actor:      event.Actor,
+			ItemID:     event.ItemID,
+		}
+		if item := event.Item; item != nil {
+			decoded.Item = &entity.Item{
+				ID:            item.ID,
+				Name:          item.Name,
+				Category:      item.Category,
+				Brand:         item.Brand,
+				PurchasePrice: item.PurchasePrice,
+				PurchaseDate:  item.PurchaseDate,
+				CreatedAt:     item.CreatedAt,
+				UpdatedAt:     item.UpdatedAt,
+			}
+		}
+		return decoded, nil
+	},
+}
@@ -0,0 +1,120 @@
+package eventschema
+
+import (
+	"encoding/json"
+	"time"
+
+	"Aicon-assignment/internal/domain/entity"
+)
+
+const V2 = "v2"
+
+// 通貨を持たない時点の金額はすべて円
+const DefaultCurrency = "JPY"
+
+// v2: 版を明示し、金額を通貨付きにした形
+type EventV2 struct {
+	SchemaVersion string    `json:"schema_version"`
+	ID            string    `json:"id"`
+	Type          string    `json:"type"`
+	OccurredAt    time.Time `json:"occurred_at"`
+	Actor         string    `json:"actor"`
+	ItemID        int64     `json:"item_id"`
+	Item          *ItemV2   `json:"item,omitempty"`
+	// 更新イベントで値が変わったフィールド（v2 に後から追加した任意項目）
+	ChangedFields []string `json:"changed_fields,omitempty"`
+	// 統合・分割による削除の理由（v2 に後から追加した任意項目）
+	DeleteReason string `json:"delete_reason,omitempty"`
+}
+
+type ItemV2 struct {
+	ID            int64     `json:"id"`
+	Name          string    `json:"name"`
+	Category      string    `json:"category"`
+	Brand         string    `json:"brand"`
+	PurchasePrice Money     `json:"purchase_price"`
+	PurchaseDate  string    `json:"purchase_date"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	// 持ち主のユーザー（v2 に後から追加した任意項目）
+	OwnerID *int64 `json:"owner_id,omitempty"`
+}
+
+type Money struct {
+	Amount   int    `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+var schemaV2 = Schema{
+	Version: V2,
+	Encode: func(event *entity.Event) any {
+		encoded := upcastV1(schemaV1.Encode(event).(*EventV1))
+		encoded.ChangedFields = event.ChangedFields
+		encoded.DeleteReason = event.DeleteReason
+		if encoded.Item != nil {
+			encoded.Item.OwnerID = event.Item.OwnerID
+		}
+		return encoded
+	},
+	Upcast: func(previous []byte) (any, error) {
+		var event EventV1
+		if err := json.Unmarshal(previous, &event); err != nil {
+			return nil, err
+		}
+		return upcastV1(&event), nil
+	},
+	Decode: func(payload []byte) (*entity.Event, error) {
+		var event EventV2
+		if err := json.Unmarshal(payload, &event); err != nil {
+			return nil, err
+		}
+
+		decoded := &entity.Event{
+			ID:            event.ID,
+			Type:          event.Type,
+			OccurredAt:    event.OccurredAt,
+			Actor:         event.Actor,
+			ItemID:        event.ItemID,
+			ChangedFields: event.ChangedFields,
+			DeleteReason:  event.DeleteReason,
+		}
+		if item := event.Item; item != nil {
+			decoded.Item = &entity.Item{
+				ID:            item.ID,
+				Name:          item.Name,
+				Category:      item.Category,
+				Brand:         item.Brand,
+				PurchasePrice: item.PurchasePrice.Amount,
+				PurchaseDate:  item.PurchaseDate,
+				CreatedAt:     item.CreatedAt,
+				UpdatedAt:     item.UpdatedAt,
+				OwnerID:       item.OwnerID,
+			}
+		}
+		return decoded, nil
+	},
+}
+
+func upcastV1(event *EventV1) *EventV2 {
+	upcasted := &EventV2{
+		SchemaVersion: V2,
+		ID:            event.ID,
+		Type:          event.Type,
+		OccurredAt:    event.OccurredAt,
+		Actor:         event.Actor,
+		ItemID:        event.ItemID,
+	}
+	if item := event.Item; item != nil {
+		upcasted.Item = &ItemV2{
+			ID:            item.ID,
+			Name:          item.Name,
+			Category:      item.Category,
+			Brand:         item.Brand,
+			PurchasePrice: Money{Amount: item.PurchasePrice, Currency: DefaultCurrency},
+			PurchaseDate:  item.PurchaseDate,
+			CreatedAt:     item.CreatedAt,
+			UpdatedAt:     item.UpdatedAt,
+		}
+	}
+	return upcasted
+}
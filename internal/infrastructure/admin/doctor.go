@@ -0,0 +1,205 @@
+package admin
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"Aicon-assignment/internal/infrastructure/config"
+	databaseInfra "Aicon-assignment/internal/infrastructure/database"
+	mailInfra "Aicon-assignment/internal/infrastructure/mail"
+	"Aicon-assignment/internal/interfaces/database"
+)
+
+// 失敗した確認項目がある
+var ErrDoctorFailed = errors.New("self-check failed")
+
+// 確認結果
+const (
+	checkPass = "PASS"
+	checkFail = "FAIL"
+	checkSkip = "SKIP" // 設定されていない・この環境では使わない
+)
+
+type checkResult struct {
+	Name   string
+	Status string
+	Detail string
+}
+
+// スキーマの確認に使う、テーブル定義のファイル
+const schemaFile = "sql/init.sql"
+
+var createTablePattern = regexp.MustCompile(`(?i)CREATE TABLE IF NOT EXISTS\s+` + "`?" + `(\w+)`)
+
+// 設定と依存先への接続を確認し、結果を表で表示する
+// 失敗した項目がある場合は ErrDoctorFailed を返す。DB にも SMTP サーバーにも書き込まない
+//
+//	doctor [-timeout 5s]
+func Doctor(ctx context.Context, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("doctor", flag.ContinueOnError)
+	flags.SetOutput(out)
+	timeout := flags.Duration("timeout", 5*time.Second, "timeout for each network check")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	results := []checkResult{checkConfig()}
+	results = append(results, checkDatabase(ctx, *timeout)...)
+	results = append(results, checkSMTP(ctx, *timeout), checkSCIM())
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CHECK\tSTATUS\tDETAIL")
+	failed := 0
+	for _, result := range results {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", result.Name, result.Status, result.Detail)
+		if result.Status == checkFail {
+			failed++
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%w: %d of %d checks failed", ErrDoctorFailed, failed, len(results))
+	}
+	return nil
+}
+
+func checkConfig() checkResult {
+	if err := config.Validate(); err != nil {
+		return checkResult{"config", checkFail, strings.ReplaceAll(err.Error(), "\n", "; ")}
+	}
+	return checkResult{"config", checkPass, fmt.Sprintf("APP_ENV=%q", config.AppEnv)}
+}
+
+// 接続と、init.sql のテーブルがすべて作成されているかを確認する
+func checkDatabase(ctx context.Context, timeout time.Duration) []checkResult {
+	switch config.AppEnv {
+	case "memory", "dev-in-memory", "test":
+		skipped := "APP_ENV=" + config.AppEnv + " uses in-memory storage"
+		return []checkResult{{"database", checkSkip, skipped}, {"schema", checkSkip, skipped}, {"schema_version", checkSkip, skipped}, {"optional_columns", checkSkip, skipped}}
+	}
+
+	db, err := sql.Open("mysql", config.GetDSN())
+	if err != nil {
+		return []checkResult{{"database", checkFail, err.Error()}, {"schema", checkSkip, "database unavailable"}, {"schema_version", checkSkip, "database unavailable"}, {"optional_columns", checkSkip, "database unavailable"}}
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if err := db.PingContext(ctx); err != nil {
+		return []checkResult{
+			{"database", checkFail, fmt.Sprintf("%s:%s/%s: %v", config.DBHost, config.DBPort, config.DBName, err)},
+			{"schema", checkSkip, "database unavailable"},
+			{"schema_version", checkSkip, "database unavailable"},
+			{"optional_columns", checkSkip, "database unavailable"},
+		}
+	}
+	connected := checkResult{"database", checkPass, fmt.Sprintf("connected to %s:%s/%s", config.DBHost, config.DBPort, config.DBName)}
+
+	return []checkResult{connected, checkSchema(ctx, db), checkSchemaVersion(ctx, db), checkOptionalColumns(ctx, db)}
+}
+
+// 任意の列がなくても起動できるため、ない列は失敗にせず一覧を表示する
+func checkOptionalColumns(ctx context.Context, db *sql.DB) checkResult {
+	missing, err := databaseInfra.DetectMissingColumns(ctx, &databaseInfra.MySqlHandler{Conn: db}, database.OptionalColumns)
+	if err != nil {
+		return checkResult{"optional_columns", checkFail, err.Error()}
+	}
+	if len(missing) == 0 {
+		return checkResult{"optional_columns", checkPass, fmt.Sprintf("all %d present", len(database.OptionalColumns))}
+	}
+	return checkResult{"optional_columns", checkPass, "missing (features disabled until migrated): " + database.JoinColumns(missing)}
+}
+
+func checkSchemaVersion(ctx context.Context, db *sql.DB) checkResult {
+	info, err := databaseInfra.CheckSchemaCompatibility(ctx, &databaseInfra.MySqlHandler{Conn: db})
+	if err != nil {
+		return checkResult{"schema_version", checkFail, err.Error()}
+	}
+	return checkResult{"schema_version", checkPass, fmt.Sprintf("database %d (compatible from %d), binary %d", info.Version, info.MinCompatible, databaseInfra.SchemaVersion)}
+}
+
+func checkSchema(ctx context.Context, db *sql.DB) checkResult {
+	definition, err := os.ReadFile(schemaFile)
+	if err != nil {
+		return checkResult{"schema", checkFail, err.Error()}
+	}
+	var expected []string
+	for _, m := range createTablePattern.FindAllStringSubmatch(string(definition), -1) {
+		expected = append(expected, m[1])
+	}
+
+	rows, err := db.QueryContext(ctx, `SELECT table_name FROM information_schema.tables WHERE table_schema = DATABASE()`)
+	if err != nil {
+		return checkResult{"schema", checkFail, err.Error()}
+	}
+	defer rows.Close()
+
+	existing := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return checkResult{"schema", checkFail, err.Error()}
+		}
+		existing[strings.ToLower(name)] = true
+	}
+	if err := rows.Err(); err != nil {
+		return checkResult{"schema", checkFail, err.Error()}
+	}
+
+	var missing []string
+	for _, table := range expected {
+		if !existing[strings.ToLower(table)] {
+			missing = append(missing, table)
+		}
+	}
+	if len(missing) > 0 {
+		return checkResult{"schema", checkFail, fmt.Sprintf("missing tables: %s (apply %s)", strings.Join(missing, ", "), schemaFile)}
+	}
+	return checkResult{"schema", checkPass, fmt.Sprintf("all %d tables in %s exist", len(expected), schemaFile)}
+}
+
+func checkSMTP(ctx context.Context, timeout time.Duration) checkResult {
+	if config.SMTPAddr == "" {
+		return checkResult{"smtp", checkSkip, "SMTP_ADDR not set; mails are written to the log"}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	mailer := &mailInfra.SMTPMailer{
+		Addr:     config.SMTPAddr,
+		Username: config.SMTPUsername,
+		Password: config.SMTPPassword,
+		From:     config.MailFrom,
+	}
+	if err := mailer.Verify(ctx); err != nil {
+		return checkResult{"smtp", checkFail, fmt.Sprintf("%s: %v", config.SMTPAddr, err)}
+	}
+
+	detail := "connected to " + config.SMTPAddr
+	if config.SMTPUsername != "" {
+		detail += " and authenticated as " + config.SMTPUsername
+	}
+	return checkResult{"smtp", checkPass, detail}
+}
+
+func checkSCIM() checkResult {
+	if config.SCIMToken == "" {
+		return checkResult{"scim", checkSkip, "SCIM_TOKEN not set; /scim/v2 rejects every request"}
+	}
+	return checkResult{"scim", checkPass, "token configured"}
+}
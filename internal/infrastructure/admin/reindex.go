@@ -0,0 +1,52 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"Aicon-assignment/internal/infrastructure/config"
+	"Aicon-assignment/internal/infrastructure/container"
+	"Aicon-assignment/internal/usecase"
+)
+
+// 全文検索のインデックスの設定を反映し、全アイテムを登録し直す
+// 初めて全文検索を使うときや、インデックスへの反映に失敗してずれたときに使う
+//
+//	reindex-search [-clear]
+func ReindexSearch(ctx context.Context, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("reindex-search", flag.ContinueOnError)
+	flags.SetOutput(out)
+	clearIndex := flags.Bool("clear", false, "delete all documents before indexing, dropping deleted items left in the index")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	deps, err := container.New(config.AppEnv)
+	if err != nil {
+		return err
+	}
+	defer deps.Close()
+
+	if deps.SearchIndex == nil {
+		return errors.New("MEILISEARCH_URL is not set")
+	}
+
+	if err := deps.SearchIndex.Configure(ctx); err != nil {
+		return fmt.Errorf("failed to configure index: %w", err)
+	}
+	if *clearIndex {
+		if err := deps.SearchIndex.Clear(ctx); err != nil {
+			return fmt.Errorf("failed to clear index: %w", err)
+		}
+	}
+
+	indexed, err := usecase.ReindexItems(ctx, deps.ItemRepository, deps.SearchIndex)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "indexed %d items into %q\n", indexed, config.MeilisearchIndex)
+	return nil
+}
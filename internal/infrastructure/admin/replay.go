@@ -0,0 +1,114 @@
+// Package admin は運用向けのサブコマンドを実装する
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"Aicon-assignment/internal/domain/entity"
+	"Aicon-assignment/internal/infrastructure/config"
+	"Aicon-assignment/internal/infrastructure/container"
+	"Aicon-assignment/internal/usecase"
+)
+
+// プロジェクションと items テーブルが一致しない
+var ErrProjectionDrift = errors.New("projection does not match the items table")
+
+// イベントストアの全イベントを新しいプロジェクションに再生する
+//
+//	replay-events [-output items.json] [-verify]
+func ReplayEvents(ctx context.Context, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("replay-events", flag.ContinueOnError)
+	flags.SetOutput(out)
+	output := flags.String("output", "", "write the rebuilt items as JSON to this file")
+	verify := flags.Bool("verify", false, "compare the rebuilt items with the items table")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	deps, err := container.New(config.AppEnv)
+	if err != nil {
+		return err
+	}
+	defer deps.Close()
+
+	projection := usecase.NewItemProjection()
+	applied, err := usecase.ReplayEvents(ctx, deps.EventStore, projection)
+	if err != nil {
+		return err
+	}
+
+	items := projection.Items()
+	fmt.Fprintf(out, "replayed %d events into %d items\n", applied, len(items))
+
+	if *output != "" {
+		if err := writeJSON(*output, items); err != nil {
+			return fmt.Errorf("failed to write %s: %w", *output, err)
+		}
+		fmt.Fprintf(out, "wrote %s\n", *output)
+	}
+
+	if *verify {
+		current, err := deps.ItemRepository.FindAll(ctx)
+		if err != nil {
+			return err
+		}
+
+		drifts := diffItems(items, current)
+		for _, drift := range drifts {
+			fmt.Fprintln(out, drift)
+		}
+		if len(drifts) > 0 {
+			return fmt.Errorf("%w: %d differences", ErrProjectionDrift, len(drifts))
+		}
+		fmt.Fprintln(out, "projection matches the items table")
+	}
+
+	return nil
+}
+
+// 再生結果と現在のテーブルの差分を1行ずつ返す
+func diffItems(rebuilt, current []*entity.Item) []string {
+	byID := make(map[int64]*entity.Item, len(current))
+	for _, item := range current {
+		byID[item.ID] = item
+	}
+
+	var drifts []string
+	for _, item := range rebuilt {
+		existing, ok := byID[item.ID]
+		if !ok {
+			drifts = append(drifts, fmt.Sprintf("item %d: exists only in the projection", item.ID))
+			continue
+		}
+		delete(byID, item.ID)
+
+		if existing.Name != item.Name ||
+			existing.Category != item.Category ||
+			existing.Brand != item.Brand ||
+			existing.PurchasePrice != item.PurchasePrice ||
+			existing.PurchaseDate != item.PurchaseDate {
+			drifts = append(drifts, fmt.Sprintf("item %d: projection differs from the items table", item.ID))
+		}
+	}
+	for _, item := range current {
+		if _, ok := byID[item.ID]; ok {
+			drifts = append(drifts, fmt.Sprintf("item %d: exists only in the items table", item.ID))
+		}
+	}
+
+	return drifts
+}
+
+func writeJSON(path string, v any) error {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0o644)
+}
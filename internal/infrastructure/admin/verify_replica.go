@@ -0,0 +1,72 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"Aicon-assignment/internal/infrastructure/config"
+	"Aicon-assignment/internal/infrastructure/container"
+	"Aicon-assignment/internal/infrastructure/replication"
+	"Aicon-assignment/internal/usecase"
+)
+
+// スタンバイのアイテムが送信元の変更ストリームと一致しているかを確かめる
+// スタンバイが適用済みの連番までの送信元のイベントを再生し、スタンバイの items テーブルと比べる
+// スタンバイの遅れには左右されない。確認中にスタンバイが先に進んだ場合はやり直しを促す
+//
+//	verify-replica
+func VerifyReplica(ctx context.Context, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("verify-replica", flag.ContinueOnError)
+	flags.SetOutput(out)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if config.ReplicationSourceURL == "" {
+		return errors.New("REPLICATION_SOURCE_URL is not set")
+	}
+
+	deps, err := container.New(config.AppEnv)
+	if err != nil {
+		return err
+	}
+	defer deps.Close()
+
+	source := replication.NewHTTPSource(config.ReplicationSourceURL, config.ReplicationToken)
+	applied, err := deps.Checkpoints.Load(ctx, source.URL)
+	if err != nil {
+		return err
+	}
+	current, err := deps.ItemRepository.FindAll(ctx)
+	if err != nil {
+		return err
+	}
+	// アイテムを読む間に適用が進んでいないことを確かめる
+	after, err := deps.Checkpoints.Load(ctx, source.URL)
+	if err != nil {
+		return err
+	}
+	if after != applied {
+		return fmt.Errorf("standby applied changes during verification (%d -> %d); run again", applied, after)
+	}
+
+	projection, err := usecase.ReplayChangesUntil(ctx, source, applied)
+	if err != nil {
+		return err
+	}
+	expected := projection.Items()
+	fmt.Fprintf(out, "compared %d items up to source sequence %d\n", len(expected), applied)
+
+	drifts := diffItems(expected, current)
+	for _, drift := range drifts {
+		fmt.Fprintln(out, drift)
+	}
+	if len(drifts) > 0 {
+		return fmt.Errorf("%w: %d differences", ErrProjectionDrift, len(drifts))
+	}
+	fmt.Fprintln(out, "standby matches the source")
+	return nil
+}
@@ -0,0 +1,85 @@
+// Package blobstore は usecase.BlobStore の実装を提供する。
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+// ローカルのディレクトリにファイルを置く
+type LocalDir struct {
+	Root string
+}
+
+// 書き込み途中のファイルが読まれないよう、一時ファイルに書いてから名前を変える
+func (d *LocalDir) Put(ctx context.Context, key string, content io.Reader) error {
+	path, err := d.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, content); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", key, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", key, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write %s: %w", key, err)
+	}
+	return nil
+}
+
+func (d *LocalDir) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := d.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, domainErrors.ErrBlobNotFound
+	}
+	return f, err
+}
+
+func (d *LocalDir) Delete(ctx context.Context, key string) error {
+	path, err := d.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (d *LocalDir) Location(key string) string {
+	return filepath.Join(d.Root, filepath.FromSlash(key))
+}
+
+// ルートの外を指すキーは受け付けない
+func (d *LocalDir) path(key string) (string, error) {
+	path := d.Location(key)
+	if rel, err := filepath.Rel(d.Root, path); err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return "", fmt.Errorf("invalid key %q", key)
+	}
+	return path, nil
+}
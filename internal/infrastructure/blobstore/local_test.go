@@ -0,0 +1,48 @@
+package blobstore
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+func TestLocalDir(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("正常系: ディレクトリを作って書き、読み、削除する", func(t *testing.T) {
+		root := t.TempDir()
+		store := &LocalDir{Root: root}
+
+		require.NoError(t, store.Put(ctx, "exports/items/1.parquet", strings.NewReader("PAR1")))
+		assert.Equal(t, filepath.Join(root, "exports", "items", "1.parquet"), store.Location("exports/items/1.parquet"))
+
+		entries, err := os.ReadDir(filepath.Join(root, "exports", "items"))
+		require.NoError(t, err)
+		assert.Len(t, entries, 1, "一時ファイルを残さない")
+
+		body, err := store.Get(ctx, "exports/items/1.parquet")
+		require.NoError(t, err)
+		data, _ := io.ReadAll(body)
+		body.Close()
+		assert.Equal(t, "PAR1", string(data))
+
+		require.NoError(t, store.Delete(ctx, "exports/items/1.parquet"))
+		_, err = store.Get(ctx, "exports/items/1.parquet")
+		assert.ErrorIs(t, err, domainErrors.ErrBlobNotFound)
+		assert.NoError(t, store.Delete(ctx, "exports/items/1.parquet"), "存在しないファイルの削除はエラーにしない")
+	})
+
+	t.Run("異常系: ルートの外には書かない", func(t *testing.T) {
+		store := &LocalDir{Root: t.TempDir()}
+
+		assert.Error(t, store.Put(ctx, "../escape.parquet", strings.NewReader("PAR1")))
+	})
+}
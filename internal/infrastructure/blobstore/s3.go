@@ -0,0 +1,279 @@
+package blobstore
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+// S3 互換の API（AWS S3、MinIO、GCS の XML API）にファイルを置く
+// パス形式（{Endpoint}/{Bucket}/{key}）でアクセスし、リクエストは署名バージョン 4 で署名する
+type S3 struct {
+	Endpoint        string // "https://s3.ap-northeast-1.amazonaws.com" など
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	Scheme          string // Location の URI スキーム（"s3", "gs"）
+	Client          *http.Client
+}
+
+func NewS3(endpoint, region, bucket, accessKeyID, secretAccessKey string) *S3 {
+	if endpoint == "" {
+		endpoint = "https://s3." + region + ".amazonaws.com"
+	}
+	return &S3{
+		Endpoint:        strings.TrimRight(endpoint, "/"),
+		Region:          region,
+		Bucket:          bucket,
+		AccessKeyID:     accessKeyID,
+		SecretAccessKey: secretAccessKey,
+		Scheme:          "s3",
+		Client:          &http.Client{Timeout: 5 * time.Minute},
+	}
+}
+
+// GCS は HMAC キーを使って S3 互換の XML API で扱う
+func NewGCS(bucket, accessID, secret string) *S3 {
+	store := NewS3("https://storage.googleapis.com", "auto", bucket, accessID, secret)
+	store.Scheme = "gs"
+	return store
+}
+
+// 署名に内容の SHA-256 と長さが必要なため、一時ファイルに書き出してから送る
+func (s *S3) Put(ctx context.Context, key string, content io.Reader) error {
+	tmp, err := os.CreateTemp("", "blob-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	h := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, h), content)
+	if err != nil {
+		return fmt.Errorf("failed to buffer %s: %w", key, err)
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.objectURL(key), io.NopCloser(tmp))
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	res, err := s.do(req, hex.EncodeToString(h.Sum(nil)))
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	return nil
+}
+
+func (s *S3) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.objectURL(key), nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := s.do(req, emptySHA256)
+	if err != nil {
+		return nil, err
+	}
+	return res.Body, nil
+}
+
+func (s *S3) Delete(ctx context.Context, key string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, s.objectURL(key), nil)
+	if err != nil {
+		return err
+	}
+	res, err := s.do(req, emptySHA256)
+	if err != nil && err != domainErrors.ErrBlobNotFound {
+		return err
+	}
+	if res != nil {
+		res.Body.Close()
+	}
+	return nil
+}
+
+func (s *S3) Location(key string) string {
+	return s.Scheme + "://" + s.Bucket + "/" + key
+}
+
+func (s *S3) objectURL(key string) string {
+	return s.Endpoint + "/" + uriEncode(s.Bucket, false) + "/" + uriEncode(key, true)
+}
+
+// 署名して送り、2xx 以外はエラーにする（404 は ErrBlobNotFound）
+func (s *S3) do(req *http.Request, payloadSHA256 string) (*http.Response, error) {
+	s.sign(req, payloadSHA256, time.Now())
+	res, err := s.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %w", req.Method, req.URL.Path, err)
+	}
+	if res.StatusCode == http.StatusNotFound {
+		res.Body.Close()
+		return nil, domainErrors.ErrBlobNotFound
+	}
+	if res.StatusCode/100 != 2 {
+		defer res.Body.Close()
+		body, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return nil, fmt.Errorf("%s %s: unexpected status %d: %s", req.Method, req.URL.Path, res.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return res, nil
+}
+
+const emptySHA256 = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// 署名バージョン 4 の Authorization ヘッダーを付ける。リクエストのヘッダーはすべて署名に含める
+func (s *S3) sign(req *http.Request, payloadSHA256 string, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadSHA256)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		uriEncode(req.URL.Path, true),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadSHA256,
+	}, "\n")
+
+	scope := s.scope(date)
+	signature := s.signature(date, amzDate, canonicalRequest)
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// 事前署名 URL の有効期間の上限（署名バージョン 4 の制限）
+const maxPresignExpiry = 7 * 24 * time.Hour
+
+// PresignGet は key のオブジェクトを認証なしで expiresIn の間だけ取得できる URL を返す
+func (s *S3) PresignGet(key string, expiresIn time.Duration, now time.Time) (string, error) {
+	if expiresIn < time.Second || expiresIn > maxPresignExpiry {
+		return "", fmt.Errorf("presigned URL expiry must be between 1s and %s: %s", maxPresignExpiry, expiresIn)
+	}
+	u, err := url.Parse(s.objectURL(key))
+	if err != nil {
+		return "", err
+	}
+	return s.presign(u, expiresIn, now), nil
+}
+
+// 署名をヘッダーではなくクエリに付ける。本文は署名しない（UNSIGNED-PAYLOAD）
+func (s *S3) presign(u *url.URL, expiresIn time.Duration, now time.Time) string {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+
+	query := u.Query()
+	query.Set("X-Amz-Algorithm", "AWS4-HMAC-SHA256")
+	query.Set("X-Amz-Credential", s.AccessKeyID+"/"+s.scope(date))
+	query.Set("X-Amz-Date", amzDate)
+	query.Set("X-Amz-Expires", strconv.FormatInt(int64(expiresIn/time.Second), 10))
+	query.Set("X-Amz-SignedHeaders", "host")
+
+	canonicalRequest := strings.Join([]string{
+		http.MethodGet,
+		uriEncode(u.Path, true),
+		canonicalQuery(query),
+		"host:" + u.Host + "\n",
+		"host",
+		"UNSIGNED-PAYLOAD",
+	}, "\n")
+	query.Set("X-Amz-Signature", s.signature(date, amzDate, canonicalRequest))
+
+	return u.Scheme + "://" + u.Host + uriEncode(u.Path, true) + "?" + canonicalQuery(query)
+}
+
+func (s *S3) scope(date string) string {
+	return date + "/" + s.Region + "/s3/aws4_request"
+}
+
+func (s *S3) signature(date, amzDate, canonicalRequest string) string {
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + s.scope(date) + "\n" + sha256Hex(canonicalRequest)
+
+	key := hmacSHA256([]byte("AWS4"+s.SecretAccessKey), date)
+	key = hmacSHA256(key, s.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+func canonicalQuery(values url.Values) string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var parts []string
+	for _, key := range keys {
+		vals := append([]string(nil), values[key]...)
+		sort.Strings(vals)
+		for _, v := range vals {
+			parts = append(parts, uriEncode(key, false)+"="+uriEncode(v, false))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// RFC 3986 の非予約文字以外をエンコードする。keepSlash の場合は / をそのまま残す（パス用）
+func uriEncode(s string, keepSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && keepSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func sha256Hex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
@@ -4,29 +4,562 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
+// SchemaIncompatibleMode の値
+const (
+	SchemaModeRefuse   = "refuse"
+	SchemaModeReadOnly = "read-only"
+)
+
 var (
 	DBUser     string
 	DBPassword string
 	DBHost     string
 	DBName     string
 	DBPort     string
+
+	// 実行環境。"memory" の場合はDBを使わずインメモリで起動する
+	AppEnv string
+
+	// 設定を読み込む YAML ファイル（任意）。環境変数・.env で指定した値が優先する
+	ConfigFile string
+
+	// 待ち受けるアドレス（":8080" の形式）
+	Port string
+	// ブラウザからのアクセスを許可するオリジン（空の場合は CORS のヘッダーを付けない）
+	CORSAllowedOrigins []string
+
+	// SIGTERM・SIGINT を受けてから新しい接続の受け付けを止めるまでの時間。この間 /readyz は 503 を返す
+	ShutdownDelay time.Duration
+	// 処理中のリクエストの完了を待つ時間の上限。過ぎた場合は接続を切る
+	ShutdownTimeout time.Duration
+
+	// DB のスキーマとの互換性がない場合の動作。"refuse"（起動しない）または "read-only"（参照のみ受け付ける）
+	SchemaIncompatibleMode string
+
+	// 読み取り専用モードで起動する（起動後は /admin/read-only で切り替えられる）
+	ReadOnly       bool
+	ReadOnlyReason string
+
+	// 保持期間を過ぎたデータを削除する間隔（0 で定期実行しない）
+	RetentionInterval time.Duration
+	// 論理削除したアイテムを完全に削除するまでの日数（/admin/retention-policies で変更していない場合。0 で削除しない）
+	PurgeAfterDays int
+	// 確定していない取り込み（/imports）を破棄するまでの期間。行を編集するたびに延びる
+	ImportStagingTTL time.Duration
+
+	// エクスポートなどのファイルの置き場所（local, s3, gcs）
+	BlobStore string
+	// local: ファイルを置くディレクトリ
+	BlobDir string
+	// s3: バケットと接続先（S3Endpoint が空の場合は AWS。MinIO などはその URL）
+	S3Bucket          string
+	S3Region          string
+	S3Endpoint        string
+	S3AccessKeyID     string
+	S3SecretAccessKey string
+	// gcs: バケットと HMAC キー
+	GCSBucket     string
+	GCSHMACKeyID  string
+	GCSHMACSecret string
+	// Parquet エクスポートのファイルを残す日数（0 で削除しない）
+	ExportRetainDays int
+
+	// 添付ファイル 1 つのサイズの上限（MB）
+	AttachmentMaxSizeMB int
+	// 参照のなくなった添付ファイルの中身を削除するまでの猶予期間
+	AttachmentGCGrace time.Duration
+	// アイテムの画像 1 枚のサイズの上限（MB）
+	ImageMaxSizeMB int
+	// 1 アイテムに追加できる画像の枚数
+	ImageMaxPerItem int
+	// 画像の事前署名 URL の有効期間（BLOB_STORE が s3・gcs の場合。0 で API から配信する）
+	ImagePresignTTL time.Duration
+
+	// アップロードされたファイルのウイルス検査（"clamav"。空の場合は検査しない）
+	Scanner string
+	// clamd の接続先
+	ClamAVAddr string
+	// 1 ファイルの検査のタイムアウト
+	ScanTimeout time.Duration
+
+	// CDN から配信する場合の URL（空の場合は CDN を使わない）
+	CDNBaseURL string
+	// CDN の URL に付ける署名の鍵（空の場合は署名しない）と有効期間
+	CDNSigningKey string
+	CDNURLTTL     time.Duration
+
+	// IdP が SCIM エンドポイントを呼ぶときのトークン（空の場合は SCIM を無効にする）
+	SCIMToken string
+
+	// アクセストークン（JWT）の署名の鍵（空の場合はログインを無効にし、X-User-ID で名乗ったユーザーを使う。開発用の環境以外では必須）と有効期間
+	JWTSecret string
+	JWTTTL    time.Duration
+	// リフレッシュトークンの有効期間。使うたびに新しいトークンと交換し、期間もそこから数え直す
+	JWTRefreshTTL time.Duration
+	// POST /auth/register で誰でもアカウントを作成できるようにする（JWT_SECRET を設定している場合のみ）
+	RegistrationEnabled bool
+	// 二要素認証で認証アプリに表示するサービス名
+	TOTPIssuer string
+	// パスワードの再設定用のトークンの有効期間と、再設定のメールに載せるページの URL（空の場合はトークンだけを載せる）
+	PasswordResetTTL time.Duration
+	PasswordResetURL string
+	// Google / GitHub でのログイン（JWT_SECRET を設定している場合のみ。クライアント ID が空のプロバイダーは使わない）
+	OIDCRedirectURL        string // /auth/oidc/callback の URL。各プロバイダーに登録したものと一致させる
+	OIDCGoogleClientID     string
+	OIDCGoogleClientSecret string
+	OIDCGitHubClientID     string
+	OIDCGitHubClientSecret string
+	// 初めてログインした外部のアカウントのユーザーを作成する（false の場合は紐付け済みのユーザーだけがログインできる）
+	OIDCAutoProvision bool
+
+	// 書き込みリクエストに署名するクライアントの鍵（形式は middleware.ParseSigningKeys を参照。空の場合は検証しない）
+	SigningKeys string
+	// 署名のタイムスタンプとサーバーの時刻のずれの許容範囲
+	SignatureClockSkew time.Duration
+	// 使用済みの nonce を覚えておく場所（memory, redis）
+	ReplayCache string
+	// redis: 接続先とパスワード
+	RedisAddr     string
+	RedisPassword string
+
+	// 他のリージョンのスタンバイに変更ストリーム（GET /replication/events）を公開するときのトークン（空の場合は公開しない）
+	ReplicationToken string
+	// スタンバイとして動かす場合の送信元（プライマリの API のベース URL）。設定すると読み取り専用で起動し、変更を取り込み続ける
+	ReplicationSourceURL string
+	// スタンバイが送信元に変更を問い合わせる間隔と、1 回に取得するイベント数
+	ReplicationPollInterval time.Duration
+	ReplicationBatchSize    int
+
+	// 招待メールの送信設定（SMTPAddr が空の場合は送信せずログに出力する）
+	SMTPAddr      string
+	SMTPUsername  string
+	SMTPPassword  string
+	MailFrom      string
+	InvitationURL string // 招待メールに載せる承諾ページの URL
+
+	// キーワード検索に使う Meilisearch（空の場合は DB の LIKE で検索する）
+	MeilisearchURL    string
+	MeilisearchAPIKey string
+	MeilisearchIndex  string
+
+	// アクセスログの出力先（空で出力しない、"stdout" で標準出力、それ以外はファイルのパス）
+	AccessLog           string
+	AccessLogFormat     string   // "common", "combined" または "json"
+	AccessLogSampleRate float64  // 記録する割合（0〜1）
+	AccessLogRedact     []string // 値を伏せるクエリパラメータ名
+	AccessLogMaxSizeMB  int      // ファイルを切り替えるサイズ（0 で切り替えない）
+	AccessLogMaxBackups int      // 残す古いファイルの数
+
+	// ルートごとのサービスレベル目標（形式は entity.ParseSLObjectives を参照。空の場合は追跡しない）
+	SLOObjectives        string
+	SLOBurnRateThreshold float64       // 通知する燃焼率
+	SLOCheckInterval     time.Duration // 燃焼率を確認する間隔（0 で確認しない）
+	SLOAlertURL          string        // 旧設定。SlackWebhookURL が空の場合に Slack の URL として使う
+
+	// true の場合は Webhook をループバック・プライベートなどサーバーの内側のアドレスにも送る（開発環境で手元の受信側を使う場合）
+	WebhookAllowPrivateNetworks bool
+
+	// 通知のチャンネル。設定したものだけを使い、どれもない場合はログに出力する
+	SlackWebhookURL        string // Slack の Incoming Webhook の URL
+	LINEChannelAccessToken string // LINE 公式アカウント（Messaging API）のチャネルアクセストークン
+	FCMCredentialsFile     string // FCM のプッシュ通知に使うサービスアカウントの鍵（JSON）のパス
+
+	// 廃止予定のエンドポイント（形式は entity.ParseDeprecations を参照）
+	Deprecations string
+
+	// 他のインスタンスで変更したカテゴリーなどの参照データを読み込み直す間隔（0 で読み込み直さない）
+	ReferenceRefreshInterval time.Duration
+
+	// ポートフォリオ全体の価値を記録する間隔（0 で記録しない）
+	PortfolioSnapshotInterval time.Duration
+
+	// クライアントの種類ごとの最低バージョン（形式は middleware.ParseClientMinVersions を参照。空の場合は制限しない）
+	ClientMinVersions string
+
+	// クライアントごとに RateLimitWindow あたりに受け付けるリクエスト数（0 で制限しない）
+	RateLimitRequests int
+	RateLimitWindow   time.Duration
+	RateLimitBy       string // "ip" または "api_key"（API キーのないリクエストは IP アドレスごと）
+	// X-Forwarded-For の IP アドレスで数える（信頼できるロードバランサーの後ろで動かす場合のみ）
+	RateLimitTrustProxy bool
+
+	// 連携先の開発者向けのサンドボックス（/sandbox）を提供する
+	SandboxEnabled bool
+	// サンドボックスのデータを最初の状態に戻す時刻（UTC の HH:MM）
+	SandboxResetAt string
+
+	// アプリケーションのログの形式（"text" または "json"。空の場合は標準の log パッケージの形式）
+	LogFormat string
+	// この時間以上かかったクエリを警告としてログに残す（0 で残さない）
+	DBSlowQueryThreshold time.Duration
+
+	// 障害注入のルール（開発・ステージング環境でのみ有効。形式は middleware.ParseChaosRules を参照）
+	ChaosRules string
 )
 
 func init() {
+	processEnv = environ()
+
 	err := godotenv.Load()
 	if err != nil {
 		log.Println("⚠️  .envファイルが見つかりませんでした。")
 	}
 
+	// 環境変数・.env で指定していない設定だけを YAML ファイルから読み込む
+	ConfigFile = os.Getenv("CONFIG_FILE")
+	if ConfigFile != "" {
+		if err := applyConfigFile(ConfigFile); err != nil {
+			loadErrors = append(loadErrors, fmt.Errorf("CONFIG_FILE: %w", err))
+		}
+	}
+
 	DBUser = os.Getenv("DB_USER")
 	DBPassword = os.Getenv("DB_PASSWORD")
 	DBHost = os.Getenv("DB_HOST")
 	DBPort = os.Getenv("DB_PORT")
 	DBName = os.Getenv("DB_NAME")
+
+	AppEnv = os.Getenv("APP_ENV")
+
+	Port = getEnv("PORT", ":8080")
+	if normalized, ok := normalizePort(Port); ok {
+		Port = normalized
+	} else {
+		invalid("PORT", Port, "must be a port number such as 8080 or :8080")
+		Port = ":8080"
+	}
+	for _, origin := range getEnvList("CORS_ALLOWED_ORIGINS", nil) {
+		if !validOrigin(origin) {
+			invalid("CORS_ALLOWED_ORIGINS", origin, "each origin must be * or scheme://host[:port]")
+			continue
+		}
+		CORSAllowedOrigins = append(CORSAllowedOrigins, origin)
+	}
+
+	ShutdownDelay = getEnvDuration("SHUTDOWN_DELAY", 0)
+	if ShutdownDelay < 0 {
+		invalid("SHUTDOWN_DELAY", ShutdownDelay, "must be 0 or greater")
+		ShutdownDelay = 0
+	}
+	ShutdownTimeout = getEnvDuration("SHUTDOWN_TIMEOUT", 10*time.Second)
+	if ShutdownTimeout <= 0 {
+		invalid("SHUTDOWN_TIMEOUT", ShutdownTimeout, "must be greater than 0")
+		ShutdownTimeout = 10 * time.Second
+	}
+
+	// ログレベル・理由ポリシーは Reload で読み込み直せる。不正な値は Validate で報告する
+	reloadable, _ := loadReloadable(os.Getenv)
+	apply(reloadable)
+
+	SchemaIncompatibleMode = getEnv("SCHEMA_INCOMPATIBLE_MODE", SchemaModeRefuse)
+	if SchemaIncompatibleMode != SchemaModeRefuse && SchemaIncompatibleMode != SchemaModeReadOnly {
+		invalid("SCHEMA_INCOMPATIBLE_MODE", SchemaIncompatibleMode, "must be refuse or read-only")
+		SchemaIncompatibleMode = SchemaModeRefuse
+	}
+
+	ReadOnly = getEnvBool("READ_ONLY", false)
+	ReadOnlyReason = os.Getenv("READ_ONLY_REASON")
+
+	RetentionInterval = getEnvDuration("RETENTION_INTERVAL", 24*time.Hour)
+	PurgeAfterDays = getEnvInt("PURGE_AFTER_DAYS", 90)
+	if PurgeAfterDays < 0 {
+		invalid("PURGE_AFTER_DAYS", PurgeAfterDays, "must be 0 or greater")
+		PurgeAfterDays = 90
+	}
+	ImportStagingTTL = getEnvDuration("IMPORT_STAGING_TTL", 72*time.Hour)
+	if ImportStagingTTL <= 0 {
+		invalid("IMPORT_STAGING_TTL", ImportStagingTTL, "must be greater than 0")
+		ImportStagingTTL = 72 * time.Hour
+	}
+
+	BlobStore = getEnv("BLOB_STORE", "local")
+	if BlobStore != "local" && BlobStore != "s3" && BlobStore != "gcs" {
+		invalid("BLOB_STORE", BlobStore, "must be local, s3 or gcs")
+		BlobStore = "local"
+	}
+	BlobDir = getEnv("BLOB_DIR", "data")
+	S3Bucket = os.Getenv("S3_BUCKET")
+	S3Region = getEnv("S3_REGION", "ap-northeast-1")
+	S3Endpoint = os.Getenv("S3_ENDPOINT")
+	S3AccessKeyID = os.Getenv("S3_ACCESS_KEY_ID")
+	S3SecretAccessKey = os.Getenv("S3_SECRET_ACCESS_KEY")
+	GCSBucket = os.Getenv("GCS_BUCKET")
+	GCSHMACKeyID = os.Getenv("GCS_HMAC_KEY_ID")
+	GCSHMACSecret = os.Getenv("GCS_HMAC_SECRET")
+	ExportRetainDays = getEnvInt("EXPORT_RETAIN_DAYS", 0)
+	if ExportRetainDays < 0 {
+		invalid("EXPORT_RETAIN_DAYS", ExportRetainDays, "must be 0 or greater")
+		ExportRetainDays = 0
+	}
+
+	AttachmentMaxSizeMB = getEnvInt("ATTACHMENT_MAX_SIZE_MB", 20)
+	if AttachmentMaxSizeMB <= 0 {
+		invalid("ATTACHMENT_MAX_SIZE_MB", AttachmentMaxSizeMB, "must be greater than 0")
+		AttachmentMaxSizeMB = 20
+	}
+	AttachmentGCGrace = getEnvDuration("ATTACHMENT_GC_GRACE", 24*time.Hour)
+	ImageMaxSizeMB = getEnvInt("IMAGE_MAX_SIZE_MB", 10)
+	if ImageMaxSizeMB <= 0 {
+		invalid("IMAGE_MAX_SIZE_MB", ImageMaxSizeMB, "must be greater than 0")
+		ImageMaxSizeMB = 10
+	}
+	ImageMaxPerItem = getEnvInt("IMAGE_MAX_PER_ITEM", 20)
+	if ImageMaxPerItem <= 0 {
+		invalid("IMAGE_MAX_PER_ITEM", ImageMaxPerItem, "must be greater than 0")
+		ImageMaxPerItem = 20
+	}
+	ImagePresignTTL = getEnvDuration("IMAGE_PRESIGN_TTL", 15*time.Minute)
+	if ImagePresignTTL < 0 || ImagePresignTTL > 7*24*time.Hour {
+		invalid("IMAGE_PRESIGN_TTL", ImagePresignTTL, "must be between 0 and 168h")
+		ImagePresignTTL = 15 * time.Minute
+	}
+
+	Scanner = os.Getenv("SCANNER")
+	if Scanner != "" && Scanner != "clamav" {
+		invalid("SCANNER", Scanner, "must be empty or clamav")
+		Scanner = "clamav"
+	}
+	ClamAVAddr = getEnv("CLAMAV_ADDR", "localhost:3310")
+	ScanTimeout = getEnvDuration("SCAN_TIMEOUT", 30*time.Second)
+	if ScanTimeout <= 0 {
+		invalid("SCAN_TIMEOUT", ScanTimeout, "must be greater than 0")
+		ScanTimeout = 30 * time.Second
+	}
+
+	CDNBaseURL = os.Getenv("CDN_BASE_URL")
+	CDNSigningKey = os.Getenv("CDN_SIGNING_KEY")
+	CDNURLTTL = getEnvDuration("CDN_URL_TTL", time.Hour)
+	if CDNURLTTL <= 0 {
+		invalid("CDN_URL_TTL", CDNURLTTL, "must be greater than 0")
+		CDNURLTTL = time.Hour
+	}
+
+	SCIMToken = os.Getenv("SCIM_TOKEN")
+
+	JWTSecret = os.Getenv("JWT_SECRET")
+	JWTTTL = getEnvDuration("JWT_TTL", time.Hour)
+	if JWTTTL <= 0 {
+		invalid("JWT_TTL", JWTTTL, "must be greater than 0")
+		JWTTTL = time.Hour
+	}
+	JWTRefreshTTL = getEnvDuration("JWT_REFRESH_TTL", 30*24*time.Hour)
+	if JWTRefreshTTL <= 0 {
+		invalid("JWT_REFRESH_TTL", JWTRefreshTTL, "must be greater than 0")
+		JWTRefreshTTL = 30 * 24 * time.Hour
+	}
+	RegistrationEnabled = getEnvBool("REGISTRATION_ENABLED", true)
+	TOTPIssuer = getEnv("TOTP_ISSUER", "Aicon")
+	PasswordResetTTL = getEnvDuration("PASSWORD_RESET_TTL", time.Hour)
+	if PasswordResetTTL <= 0 {
+		invalid("PASSWORD_RESET_TTL", PasswordResetTTL, "must be greater than 0")
+		PasswordResetTTL = time.Hour
+	}
+	PasswordResetURL = os.Getenv("PASSWORD_RESET_URL")
+	OIDCRedirectURL = os.Getenv("OIDC_REDIRECT_URL")
+	OIDCGoogleClientID = os.Getenv("OIDC_GOOGLE_CLIENT_ID")
+	OIDCGoogleClientSecret = os.Getenv("OIDC_GOOGLE_CLIENT_SECRET")
+	OIDCGitHubClientID = os.Getenv("OIDC_GITHUB_CLIENT_ID")
+	OIDCGitHubClientSecret = os.Getenv("OIDC_GITHUB_CLIENT_SECRET")
+	OIDCAutoProvision = getEnvBool("OIDC_AUTO_PROVISION", true)
+
+	SigningKeys = os.Getenv("SIGNING_KEYS")
+	SignatureClockSkew = getEnvDuration("SIGNATURE_CLOCK_SKEW", 5*time.Minute)
+	if SignatureClockSkew <= 0 || SignatureClockSkew > time.Hour {
+		invalid("SIGNATURE_CLOCK_SKEW", SignatureClockSkew, "must be greater than 0 and at most 1h")
+		SignatureClockSkew = 5 * time.Minute
+	}
+	ReplayCache = getEnv("REPLAY_CACHE", "memory")
+	if ReplayCache != "memory" && ReplayCache != "redis" {
+		invalid("REPLAY_CACHE", ReplayCache, "must be memory or redis")
+		ReplayCache = "memory"
+	}
+	RedisAddr = getEnv("REDIS_ADDR", "localhost:6379")
+	RedisPassword = os.Getenv("REDIS_PASSWORD")
+
+	ReplicationToken = os.Getenv("REPLICATION_TOKEN")
+	ReplicationSourceURL = os.Getenv("REPLICATION_SOURCE_URL")
+	ReplicationPollInterval = getEnvDuration("REPLICATION_POLL_INTERVAL", 2*time.Second)
+	if ReplicationPollInterval <= 0 {
+		invalid("REPLICATION_POLL_INTERVAL", ReplicationPollInterval, "must be greater than 0")
+		ReplicationPollInterval = 2 * time.Second
+	}
+	ReplicationBatchSize = getEnvInt("REPLICATION_BATCH_SIZE", 500)
+	if ReplicationBatchSize <= 0 || ReplicationBatchSize > 1000 {
+		invalid("REPLICATION_BATCH_SIZE", ReplicationBatchSize, "must be between 1 and 1000")
+		ReplicationBatchSize = 500
+	}
+
+	SMTPAddr = os.Getenv("SMTP_ADDR")
+	SMTPUsername = os.Getenv("SMTP_USERNAME")
+	SMTPPassword = os.Getenv("SMTP_PASSWORD")
+	MailFrom = getEnv("MAIL_FROM", "no-reply@localhost")
+	InvitationURL = os.Getenv("INVITATION_URL")
+
+	ChaosRules = os.Getenv("CHAOS_RULES")
+
+	MeilisearchURL = os.Getenv("MEILISEARCH_URL")
+	MeilisearchAPIKey = os.Getenv("MEILISEARCH_API_KEY")
+	MeilisearchIndex = getEnv("MEILISEARCH_INDEX", "items")
+
+	ClientMinVersions = os.Getenv("CLIENT_MIN_VERSIONS")
+
+	RateLimitRequests = getEnvInt("RATE_LIMIT_REQUESTS", 0)
+	if RateLimitRequests < 0 {
+		invalid("RATE_LIMIT_REQUESTS", RateLimitRequests, "must be 0 or greater")
+		RateLimitRequests = 0
+	}
+	RateLimitWindow = getEnvDuration("RATE_LIMIT_WINDOW", time.Minute)
+	if RateLimitWindow <= 0 {
+		invalid("RATE_LIMIT_WINDOW", RateLimitWindow, "must be greater than 0")
+		RateLimitWindow = time.Minute
+	}
+	RateLimitBy = getEnv("RATE_LIMIT_BY", "ip")
+	RateLimitTrustProxy = getEnvBool("RATE_LIMIT_TRUST_PROXY", false)
+
+	LogFormat = os.Getenv("LOG_FORMAT")
+	DBSlowQueryThreshold = getEnvDuration("DB_SLOW_QUERY_THRESHOLD", 500*time.Millisecond)
+
+	SandboxEnabled = getEnvBool("SANDBOX_ENABLED", false)
+	SandboxResetAt = getEnv("SANDBOX_RESET_AT", "18:00")
+
+	Deprecations = os.Getenv("DEPRECATIONS")
+
+	ReferenceRefreshInterval = getEnvDuration("REFERENCE_REFRESH_INTERVAL", time.Minute)
+
+	PortfolioSnapshotInterval = getEnvDuration("PORTFOLIO_SNAPSHOT_INTERVAL", time.Hour)
+
+	SLOObjectives = os.Getenv("SLO_OBJECTIVES")
+	SLOBurnRateThreshold = getEnvFloat("SLO_BURN_RATE_THRESHOLD", 14.4)
+	SLOCheckInterval = getEnvDuration("SLO_CHECK_INTERVAL", time.Minute)
+	SLOAlertURL = os.Getenv("SLO_ALERT_URL")
+
+	WebhookAllowPrivateNetworks = getEnvBool("WEBHOOK_ALLOW_PRIVATE_NETWORKS", false)
+
+	SlackWebhookURL = getEnv("SLACK_WEBHOOK_URL", SLOAlertURL)
+	LINEChannelAccessToken = os.Getenv("LINE_CHANNEL_ACCESS_TOKEN")
+	FCMCredentialsFile = os.Getenv("FCM_CREDENTIALS_FILE")
+
+	AccessLog = os.Getenv("ACCESS_LOG")
+	AccessLogFormat = getEnv("ACCESS_LOG_FORMAT", "combined")
+	switch AccessLogFormat {
+	case "common", "combined", "json":
+	default:
+		invalid("ACCESS_LOG_FORMAT", AccessLogFormat, "must be common, combined or json")
+		AccessLogFormat = "combined"
+	}
+	AccessLogSampleRate = getEnvFloat("ACCESS_LOG_SAMPLE_RATE", 1)
+	if AccessLogSampleRate > 1 {
+		invalid("ACCESS_LOG_SAMPLE_RATE", AccessLogSampleRate, "must be between 0 and 1")
+		AccessLogSampleRate = 1
+	}
+	AccessLogRedact = getEnvList("ACCESS_LOG_REDACT", []string{"token", "access_token", "refresh_token", "api_key", "password", "secret", "signature"})
+	AccessLogMaxSizeMB = getEnvInt("ACCESS_LOG_MAX_SIZE_MB", 100)
+	AccessLogMaxBackups = getEnvInt("ACCESS_LOG_MAX_BACKUPS", 5)
+}
+
+// 開発用の環境か。JWT_SECRET がなくても起動でき、X-User-ID で名乗ったユーザーを信用する
+// APP_ENV 未設定を含め、それ以外の環境では JWT_SECRET が必須で、X-User-ID は受け付けない
+func Development() bool {
+	switch AppEnv {
+	case "development", "memory", "dev-in-memory", "test":
+		return true
+	default:
+		return false
+	}
+}
+
+// 障害注入を許可する環境か。本番や APP_ENV 未設定の環境では常に無効にする
+func ChaosAllowed() bool {
+	switch AppEnv {
+	case "development", "staging", "memory", "dev-in-memory":
+		return true
+	default:
+		return false
+	}
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvBool(key string, defaultValue bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		invalid(key, value, "must be true or false")
+		return defaultValue
+	}
+	return parsed
+}
+
+// 0 以上の整数
+func getEnvInt(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed < 0 {
+		invalid(key, value, "must be an integer of 0 or greater")
+		return defaultValue
+	}
+	return parsed
+}
+
+// 0 以上の小数
+func getEnvFloat(key string, defaultValue float64) float64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil || parsed < 0 {
+		invalid(key, value, "must be a number of 0 or greater")
+		return defaultValue
+	}
+	return parsed
+}
+
+// カンマ区切りの一覧。空白だけの要素は無視する
+func getEnvList(key string, defaultValue []string) []string {
+	value := os.Getenv(key)
+	if strings.TrimSpace(value) == "" {
+		return defaultValue
+	}
+	var list []string
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			list = append(list, part)
+		}
+	}
+	return list
+}
+
+// 1h30m のような time.Duration の形式
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil || parsed < 0 {
+		invalid(key, value, "must be a duration of 0 or greater such as 30s or 1h30m")
+		return defaultValue
+	}
+	return parsed
 }
 
 // DB接続文字列を返す
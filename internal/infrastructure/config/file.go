@@ -0,0 +1,137 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// 設定ファイルのキー。環境変数と同じ名前で、小文字でも書ける（db_host → DB_HOST）
+var configKeyPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+
+// 環境変数・.env で指定していないキーだけを設定ファイルの値で埋める
+func applyConfigFile(path string) error {
+	values, err := readConfigFile(path)
+	if err != nil {
+		return err
+	}
+	for key, value := range values {
+		if _, ok := os.LookupEnv(key); ok {
+			continue
+		}
+		if err := os.Setenv(key, value); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+func readConfigFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseConfigFile(string(data))
+}
+
+// 1 階層の YAML（"key: value" の並び）を読み込む。入れ子のマッピングは扱わない
+// 一覧は [a, b] または "- a" の行で書け、環境変数と同じくカンマ区切りの値にする
+func parseConfigFile(data string) (map[string]string, error) {
+	values := make(map[string]string)
+	var listKey string
+	var list []string
+	flush := func() {
+		if listKey != "" {
+			values[listKey] = strings.Join(list, ",")
+			listKey, list = "", nil
+		}
+	}
+
+	for i, line := range strings.Split(data, "\n") {
+		number := i + 1
+		line = strings.TrimRight(line, " \t\r")
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || trimmed == "---" {
+			continue
+		}
+
+		if line[0] == ' ' || line[0] == '\t' {
+			item, ok := strings.CutPrefix(trimmed, "- ")
+			if !ok || listKey == "" {
+				return nil, fmt.Errorf("line %d: nested values are not supported", number)
+			}
+			value, err := parseConfigValue(item)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", number, err)
+			}
+			list = append(list, value)
+			continue
+		}
+		flush()
+
+		key, raw, ok := strings.Cut(line, ":")
+		if !ok || !configKeyPattern.MatchString(strings.TrimSpace(key)) {
+			return nil, fmt.Errorf("line %d: expected \"key: value\"", number)
+		}
+		key = strings.ToUpper(strings.TrimSpace(key))
+		if _, ok := values[key]; ok {
+			return nil, fmt.Errorf("line %d: %s is set more than once", number, key)
+		}
+
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			// 次の行から "- " の一覧が続く（続かなければ空の値）
+			listKey = key
+			values[key] = ""
+			continue
+		}
+		if strings.HasPrefix(raw, "[") {
+			if !strings.HasSuffix(raw, "]") {
+				return nil, fmt.Errorf("line %d: unterminated list", number)
+			}
+			var items []string
+			for _, item := range strings.Split(strings.TrimSuffix(strings.TrimPrefix(raw, "["), "]"), ",") {
+				if item = strings.TrimSpace(item); item == "" {
+					continue
+				}
+				value, err := parseConfigValue(item)
+				if err != nil {
+					return nil, fmt.Errorf("line %d: %w", number, err)
+				}
+				items = append(items, value)
+			}
+			values[key] = strings.Join(items, ",")
+			continue
+		}
+		value, err := parseConfigValue(raw)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", number, err)
+		}
+		values[key] = value
+	}
+	flush()
+	return values, nil
+}
+
+// 引用符で囲んだ値はそのまま、囲んでいない値は " #" 以降をコメントとして除く
+func parseConfigValue(raw string) (string, error) {
+	switch {
+	case strings.HasPrefix(raw, `"`):
+		value, err := strconv.Unquote(raw)
+		if err != nil {
+			return "", fmt.Errorf("invalid quoted value %s", raw)
+		}
+		return value, nil
+	case strings.HasPrefix(raw, "'"):
+		if len(raw) < 2 || !strings.HasSuffix(raw, "'") {
+			return "", fmt.Errorf("invalid quoted value %s", raw)
+		}
+		return strings.ReplaceAll(raw[1:len(raw)-1], "''", "'"), nil
+	}
+	if value, _, ok := strings.Cut(raw, " #"); ok {
+		return strings.TrimSpace(value), nil
+	}
+	return raw, nil
+}
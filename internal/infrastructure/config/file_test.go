@@ -0,0 +1,66 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseConfigFile(t *testing.T) {
+	t.Run("正常系: キーは環境変数の名前にし、一覧はカンマ区切りにする", func(t *testing.T) {
+		got, err := parseConfigFile(`---
+# サーバー
+port: 9090
+shutdown_timeout: 30s   # 処理中のリクエストを待つ時間
+DB_HOST: "mysql"
+mail_from: 'O''Brien <no-reply@example.com>'
+access_log_redact: [token, "password"]
+cors_allowed_origins:
+  - https://app.example.com
+  - https://admin.example.com
+chaos_rules:
+`)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{
+			"PORT":                 "9090",
+			"SHUTDOWN_TIMEOUT":     "30s",
+			"DB_HOST":              "mysql",
+			"MAIL_FROM":            "O'Brien <no-reply@example.com>",
+			"ACCESS_LOG_REDACT":    "token,password",
+			"CORS_ALLOWED_ORIGINS": "https://app.example.com,https://admin.example.com",
+			"CHAOS_RULES":          "",
+		}, got)
+	})
+
+	t.Run("異常系: 入れ子・重複・形式の誤りは行番号を示す", func(t *testing.T) {
+		tests := map[string]string{
+			"db:\n  host: mysql\n":        "line 2: nested values are not supported",
+			"port: 1\nPORT: 2\n":          "line 2: PORT is set more than once",
+			"just a line\n":               `line 1: expected "key: value"`,
+			"mail_from: \"unterminated\n": "line 1: invalid quoted value",
+			"redact: [a, b\n":             "line 1: unterminated list",
+		}
+		for input, want := range tests {
+			_, err := parseConfigFile(input)
+			require.Error(t, err, input)
+			assert.Contains(t, err.Error(), want)
+		}
+	})
+}
+
+func TestApplyConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("config_test_a: from-file\nconfig_test_b: from-file\n"), 0o600))
+	t.Setenv("CONFIG_TEST_A", "from-env")
+	t.Setenv("CONFIG_TEST_B", "")
+	os.Unsetenv("CONFIG_TEST_B")
+
+	require.NoError(t, applyConfigFile(path))
+	assert.Equal(t, "from-env", os.Getenv("CONFIG_TEST_A"))
+	assert.Equal(t, "from-file", os.Getenv("CONFIG_TEST_B"))
+
+	assert.Error(t, applyConfigFile(filepath.Join(t.TempDir(), "missing.yaml")))
+}
@@ -0,0 +1,155 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/joho/godotenv"
+)
+
+// 再起動せずに読み込み直せる設定
+// 接続先やポートなど、起動時に一度だけ使う設定は含めない
+type Reloadable struct {
+	LogLevel slog.Level `json:"log_level"`
+
+	// 破壊的な操作に理由を求めるポリシー
+	ReasonPolicyDelete    bool    `json:"reason_policy_delete"`     // 削除時に理由を必須にする
+	ReasonPolicyHighValue int     `json:"reason_policy_high_value"` // この金額以上のアイテムの更新時に理由を必須にする（0 で無効）
+	ReasonPolicyOrgs      []int64 `json:"reason_policy_orgs"`       // ポリシーを適用する組織ID（空の場合は全組織）
+
+	// 警告をエラーにする strict モードを常に適用する組織ID
+	StrictModeOrgs []int64 `json:"strict_mode_orgs"`
+}
+
+var (
+	current  atomic.Pointer[Reloadable]
+	reloadMu sync.Mutex
+
+	// LOG_FORMAT で差し替えたロガーのレベル。読み込み直すと変わる
+	LogLevel = new(slog.LevelVar)
+
+	// .env を読み込む前の環境変数。.env より優先する
+	processEnv map[string]string
+
+	// Reload で読み込み直すファイル
+	envFile = ".env"
+)
+
+// 現在の設定を返す
+func Current() Reloadable {
+	return *current.Load()
+}
+
+// .env・CONFIG_FILE と環境変数を読み込み直して設定を置き換える
+// 不正な値が1つでもある場合は何も変更せずにエラーを返す
+// 起動時と同様に、プロセスの環境変数・.env・CONFIG_FILE の順に優先する
+func Reload() (Reloadable, error) {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+
+	file, err := godotenv.Read(envFile)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return Current(), fmt.Errorf("failed to read .env: %w", err)
+	}
+	var yaml map[string]string
+	if ConfigFile != "" {
+		if yaml, err = readConfigFile(ConfigFile); err != nil {
+			return Current(), fmt.Errorf("failed to read %s: %w", ConfigFile, err)
+		}
+	}
+
+	reloadable, err := loadReloadable(func(key string) string {
+		if value, ok := processEnv[key]; ok {
+			return value
+		}
+		if value, ok := file[key]; ok {
+			return value
+		}
+		return yaml[key]
+	})
+	if err != nil {
+		return Current(), err
+	}
+
+	apply(reloadable)
+	return reloadable, nil
+}
+
+func apply(reloadable Reloadable) {
+	slog.SetLogLoggerLevel(reloadable.LogLevel)
+	LogLevel.Set(reloadable.LogLevel)
+	current.Store(&reloadable)
+}
+
+// 不正な値はデフォルト値にした上で、すべてのエラーをまとめて返す
+func loadReloadable(getenv func(key string) string) (Reloadable, error) {
+	reloadable := Reloadable{LogLevel: slog.LevelInfo}
+	var errs []error
+
+	if value := getenv("LOG_LEVEL"); value != "" {
+		if err := reloadable.LogLevel.UnmarshalText([]byte(value)); err != nil {
+			errs = append(errs, fmt.Errorf("LOG_LEVEL: invalid value %q", value))
+		}
+	}
+
+	if value := getenv("REASON_POLICY_DELETE"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("REASON_POLICY_DELETE: invalid value %q", value))
+		}
+		reloadable.ReasonPolicyDelete = parsed
+	}
+
+	if value := getenv("REASON_POLICY_HIGH_VALUE"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			errs = append(errs, fmt.Errorf("REASON_POLICY_HIGH_VALUE: invalid value %q", value))
+		} else {
+			reloadable.ReasonPolicyHighValue = parsed
+		}
+	}
+
+	var err error
+	reloadable.ReasonPolicyOrgs, err = parseOrgIDs("REASON_POLICY_ORGS", getenv("REASON_POLICY_ORGS"))
+	errs = append(errs, err)
+	reloadable.StrictModeOrgs, err = parseOrgIDs("STRICT_MODE_ORGS", getenv("STRICT_MODE_ORGS"))
+	errs = append(errs, err)
+
+	return reloadable, errors.Join(errs...)
+}
+
+// "1, 3" の形式の組織IDを読み込む。不正な値はエラーに含め、それ以外の値は返す
+func parseOrgIDs(key, value string) ([]int64, error) {
+	var ids []int64
+	var errs []error
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		parsed, err := strconv.ParseInt(part, 10, 64)
+		if err != nil || parsed <= 0 {
+			errs = append(errs, fmt.Errorf("%s: invalid value %q", key, part))
+			continue
+		}
+		ids = append(ids, parsed)
+	}
+	return ids, errors.Join(errs...)
+}
+
+func environ() map[string]string {
+	env := make(map[string]string)
+	for _, entry := range os.Environ() {
+		if key, value, ok := strings.Cut(entry, "="); ok {
+			env[key] = value
+		}
+	}
+	return env
+}
@@ -0,0 +1,110 @@
+package config
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadReloadable(t *testing.T) {
+	env := func(values map[string]string) func(string) string {
+		return func(key string) string { return values[key] }
+	}
+
+	t.Run("正常系: 未設定の場合はデフォルト", func(t *testing.T) {
+		got, err := loadReloadable(env(nil))
+		require.NoError(t, err)
+		assert.Equal(t, Reloadable{LogLevel: slog.LevelInfo}, got)
+	})
+
+	t.Run("正常系: すべて指定", func(t *testing.T) {
+		got, err := loadReloadable(env(map[string]string{
+			"LOG_LEVEL":                "debug",
+			"REASON_POLICY_DELETE":     "true",
+			"REASON_POLICY_HIGH_VALUE": "1000000",
+			"REASON_POLICY_ORGS":       "1, 3,",
+			"STRICT_MODE_ORGS":         "2",
+		}))
+		require.NoError(t, err)
+		assert.Equal(t, Reloadable{
+			LogLevel:              slog.LevelDebug,
+			ReasonPolicyDelete:    true,
+			ReasonPolicyHighValue: 1000000,
+			ReasonPolicyOrgs:      []int64{1, 3},
+			StrictModeOrgs:        []int64{2},
+		}, got)
+	})
+
+	t.Run("異常系: 不正な値はすべてエラーに含め、その項目はデフォルト", func(t *testing.T) {
+		got, err := loadReloadable(env(map[string]string{
+			"LOG_LEVEL":                "verbose",
+			"REASON_POLICY_HIGH_VALUE": "-1",
+			"REASON_POLICY_ORGS":       "1,x",
+			"STRICT_MODE_ORGS":         "0",
+		}))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "LOG_LEVEL")
+		assert.Contains(t, err.Error(), "REASON_POLICY_HIGH_VALUE")
+		assert.Contains(t, err.Error(), `REASON_POLICY_ORGS: invalid value "x"`)
+		assert.Contains(t, err.Error(), `STRICT_MODE_ORGS: invalid value "0"`)
+		assert.Equal(t, slog.LevelInfo, got.LogLevel)
+		assert.Equal(t, []int64{1}, got.ReasonPolicyOrgs)
+	})
+}
+
+func TestReload(t *testing.T) {
+	before := Current()
+	t.Cleanup(func() { apply(before) })
+	envFile = filepath.Join(t.TempDir(), ".env")
+	t.Cleanup(func() { envFile = ".env" })
+
+	processEnv = map[string]string{"REASON_POLICY_DELETE": "true"}
+	t.Cleanup(func() { processEnv = environ() })
+
+	t.Run("正常系: プロセスの環境変数は .env より優先する", func(t *testing.T) {
+		writeEnvFile(t, "REASON_POLICY_DELETE=false\nREASON_POLICY_HIGH_VALUE=500\n")
+
+		got, err := Reload()
+		require.NoError(t, err)
+		assert.True(t, got.ReasonPolicyDelete)
+		assert.Equal(t, 500, got.ReasonPolicyHighValue)
+		assert.Equal(t, got, Current())
+	})
+
+	t.Run("正常系: LOG_FORMAT のロガーのレベルも変わる", func(t *testing.T) {
+		writeEnvFile(t, "REASON_POLICY_HIGH_VALUE=500\nLOG_LEVEL=warn\n")
+
+		_, err := Reload()
+		require.NoError(t, err)
+		assert.Equal(t, slog.LevelWarn, LogLevel.Level())
+	})
+
+	t.Run("正常系: CONFIG_FILE の値は .env で指定していない項目にだけ使う", func(t *testing.T) {
+		ConfigFile = filepath.Join(t.TempDir(), "config.yaml")
+		t.Cleanup(func() { ConfigFile = "" })
+		require.NoError(t, os.WriteFile(ConfigFile, []byte("reason_policy_high_value: 900\nstrict_mode_orgs: [4, 5]\n"), 0o600))
+		writeEnvFile(t, "REASON_POLICY_HIGH_VALUE=500\n")
+
+		got, err := Reload()
+		require.NoError(t, err)
+		assert.Equal(t, 500, got.ReasonPolicyHighValue)
+		assert.Equal(t, []int64{4, 5}, got.StrictModeOrgs)
+	})
+
+	t.Run("異常系: 不正な値がある場合は何も変更しない", func(t *testing.T) {
+		writeEnvFile(t, "REASON_POLICY_HIGH_VALUE=100\nLOG_LEVEL=loud\n")
+
+		_, err := Reload()
+		require.Error(t, err)
+		assert.Equal(t, 500, Current().ReasonPolicyHighValue)
+	})
+}
+
+func writeEnvFile(t *testing.T, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(envFile, []byte(content), 0o600))
+}
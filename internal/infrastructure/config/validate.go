@@ -0,0 +1,88 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// 起動時の読み込みで見つかった不正な値。値はデフォルトに戻してあり、Validate でまとめて返す
+var loadErrors []error
+
+func invalid(key string, value any, rule string) {
+	loadErrors = append(loadErrors, fmt.Errorf("%s: invalid value %q (%s)", key, fmt.Sprint(value), rule))
+}
+
+// 設定をすべて検証し、不正な値と足りない値をまとめて返す（問題がなければ nil）
+// サーバーはこのエラーがあると起動しない
+func Validate() error {
+	errs := slices.Clone(loadErrors)
+	if _, err := loadReloadable(os.Getenv); err != nil {
+		errs = append(errs, err)
+	}
+	errs = append(errs, checkRequired()...)
+	return errors.Join(errs...)
+}
+
+// 組み合わせによって必須になる設定
+func checkRequired() []error {
+	var errs []error
+	required := func(key, value, when string) {
+		if strings.TrimSpace(value) == "" {
+			errs = append(errs, fmt.Errorf("%s: required %s", key, when))
+		}
+	}
+
+	if usesDatabase() {
+		when := fmt.Sprintf("when APP_ENV=%q uses MySQL", AppEnv)
+		required("DB_HOST", DBHost, when)
+		required("DB_PORT", DBPort, when)
+		required("DB_USER", DBUser, when)
+		required("DB_NAME", DBName, when)
+	}
+	if !Development() {
+		required("JWT_SECRET", JWTSecret, fmt.Sprintf("when APP_ENV=%q is not a development environment", AppEnv))
+	}
+	switch BlobStore {
+	case "s3":
+		required("S3_BUCKET", S3Bucket, "when BLOB_STORE=s3")
+	case "gcs":
+		required("GCS_BUCKET", GCSBucket, "when BLOB_STORE=gcs")
+	}
+	if ReplayCache == "redis" {
+		required("REDIS_ADDR", RedisAddr, "when REPLAY_CACHE=redis")
+	}
+	return errs
+}
+
+// インメモリで動かす APP_ENV 以外は MySQL に接続する
+func usesDatabase() bool {
+	switch AppEnv {
+	case "memory", "dev-in-memory", "test":
+		return false
+	default:
+		return true
+	}
+}
+
+// "8080" と ":8080" のどちらでも受け付け、":8080" の形式にする
+func normalizePort(value string) (string, bool) {
+	port, err := strconv.Atoi(strings.TrimPrefix(value, ":"))
+	if err != nil || port <= 0 || port > 65535 {
+		return "", false
+	}
+	return ":" + strconv.Itoa(port), true
+}
+
+// "*" または "https://example.com" の形式（パスを含まない）
+func validOrigin(origin string) bool {
+	if origin == "*" {
+		return true
+	}
+	parsed, err := url.Parse(origin)
+	return err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != "" && parsed.Path == "" && parsed.RawQuery == ""
+}
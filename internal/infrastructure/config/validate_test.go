@@ -0,0 +1,78 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizePort(t *testing.T) {
+	for input, want := range map[string]string{"8080": ":8080", ":9090": ":9090"} {
+		got, ok := normalizePort(input)
+		assert.True(t, ok, input)
+		assert.Equal(t, want, got)
+	}
+	for _, input := range []string{"", "http", ":0", "70000", "localhost:8080"} {
+		_, ok := normalizePort(input)
+		assert.False(t, ok, input)
+	}
+}
+
+func TestValidOrigin(t *testing.T) {
+	for _, origin := range []string{"*", "https://app.example.com", "http://localhost:3000"} {
+		assert.True(t, validOrigin(origin), origin)
+	}
+	for _, origin := range []string{"app.example.com", "https://app.example.com/path", "ftp://example.com", "https://"} {
+		assert.False(t, validOrigin(origin), origin)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	saved := []*string{&AppEnv, &DBHost, &DBPort, &DBUser, &DBName, &BlobStore, &S3Bucket, &JWTSecret}
+	values := make([]string, len(saved))
+	for i, p := range saved {
+		values[i] = *p
+	}
+	savedErrors := loadErrors
+	t.Cleanup(func() {
+		for i, p := range saved {
+			*p = values[i]
+		}
+		loadErrors = savedErrors
+	})
+	loadErrors = nil
+
+	t.Run("正常系: インメモリでは DB の設定は不要", func(t *testing.T) {
+		AppEnv, DBHost, DBPort, DBUser, DBName, BlobStore, JWTSecret = "memory", "", "", "", "", "local", ""
+		assert.NoError(t, Validate())
+	})
+
+	t.Run("異常系: 足りない設定と不正な値をまとめて返す", func(t *testing.T) {
+		AppEnv, DBHost, DBPort, DBUser, DBName = "production", "mysql", "3306", "", "app"
+		BlobStore, S3Bucket = "s3", ""
+		invalid("PORT", "http", "must be a port number such as 8080 or :8080")
+		t.Cleanup(func() { loadErrors = nil })
+
+		err := Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `PORT: invalid value "http"`)
+		assert.Contains(t, err.Error(), `DB_USER: required when APP_ENV="production" uses MySQL`)
+		assert.Contains(t, err.Error(), "S3_BUCKET: required when BLOB_STORE=s3")
+		assert.NotContains(t, err.Error(), "DB_HOST")
+		assert.Contains(t, err.Error(), `JWT_SECRET: required when APP_ENV="production" is not a development environment`)
+	})
+
+	t.Run("異常系: APP_ENV 未設定の場合も JWT_SECRET が必須", func(t *testing.T) {
+		AppEnv, DBHost, DBPort, DBUser, DBName, BlobStore, JWTSecret = "", "mysql", "3306", "root", "app", "local", ""
+		assert.ErrorContains(t, Validate(), "JWT_SECRET: required")
+
+		JWTSecret = "0123456789abcdef0123456789abcdef"
+		assert.NoError(t, Validate())
+	})
+
+	t.Run("正常系: 開発用の環境では JWT_SECRET は不要", func(t *testing.T) {
+		AppEnv, DBHost, DBPort, DBUser, DBName, BlobStore, JWTSecret = "development", "mysql", "3306", "root", "app", "local", ""
+		assert.NoError(t, Validate())
+	})
+}
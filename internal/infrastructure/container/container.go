@@ -0,0 +1,1274 @@
+// Package container はアプリケーションの依存関係を組み立てるコンポジションルート。
+// 環境ごとの差分（DB接続の有無など）は ProviderSet にまとめ、それ以外の組み立ては共通化する。
+package container
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"Aicon-assignment/internal/domain/entity"
+	"Aicon-assignment/internal/domain/eventschema"
+	"Aicon-assignment/internal/infrastructure/blobstore"
+	"Aicon-assignment/internal/infrastructure/config"
+	databaseInfra "Aicon-assignment/internal/infrastructure/database"
+	mailInfra "Aicon-assignment/internal/infrastructure/mail"
+	"Aicon-assignment/internal/infrastructure/notify"
+	"Aicon-assignment/internal/infrastructure/oidc"
+	"Aicon-assignment/internal/infrastructure/replaycache"
+	replicationInfra "Aicon-assignment/internal/infrastructure/replication"
+	"Aicon-assignment/internal/infrastructure/scanner"
+	searchInfra "Aicon-assignment/internal/infrastructure/search"
+	webhookInfra "Aicon-assignment/internal/infrastructure/webhook"
+	"Aicon-assignment/internal/interfaces/controller/attachments"
+	authController "Aicon-assignment/internal/interfaces/controller/auth"
+	"Aicon-assignment/internal/interfaces/controller/deprecations"
+	"Aicon-assignment/internal/interfaces/controller/docs"
+	"Aicon-assignment/internal/interfaces/controller/exports"
+	"Aicon-assignment/internal/interfaces/controller/grafana"
+	graphqlController "Aicon-assignment/internal/interfaces/controller/graphql"
+	"Aicon-assignment/internal/interfaces/controller/images"
+	"Aicon-assignment/internal/interfaces/controller/impersonation"
+	"Aicon-assignment/internal/interfaces/controller/importprofiles"
+	itemController "Aicon-assignment/internal/interfaces/controller/items"
+	"Aicon-assignment/internal/interfaces/controller/notifications"
+	"Aicon-assignment/internal/interfaces/controller/organizations"
+	"Aicon-assignment/internal/interfaces/controller/quarantine"
+	"Aicon-assignment/internal/interfaces/controller/reference"
+	replicationController "Aicon-assignment/internal/interfaces/controller/replication"
+	"Aicon-assignment/internal/interfaces/controller/reports"
+	"Aicon-assignment/internal/interfaces/controller/retention"
+	"Aicon-assignment/internal/interfaces/controller/scim"
+	"Aicon-assignment/internal/interfaces/controller/system"
+	"Aicon-assignment/internal/interfaces/controller/tenants"
+	"Aicon-assignment/internal/interfaces/controller/users"
+	"Aicon-assignment/internal/interfaces/controller/verifications"
+	webhookController "Aicon-assignment/internal/interfaces/controller/webhooks"
+	"Aicon-assignment/internal/interfaces/database"
+	appMiddleware "Aicon-assignment/internal/interfaces/middleware"
+	"Aicon-assignment/internal/pkg/cdnurl"
+	"Aicon-assignment/internal/pkg/clock"
+	"Aicon-assignment/internal/pkg/idgen"
+	"Aicon-assignment/internal/pkg/listquery"
+	"Aicon-assignment/internal/usecase"
+)
+
+// SCIM エンドポイントのパス
+const SCIMBasePath = "/scim/v2"
+
+// リプレイキャッシュ（Redis）の 1 回の問い合わせのタイムアウト
+const replayCacheTimeout = 2 * time.Second
+
+// アクセストークンの署名の鍵の最小の長さ（HS256 の鍵は 256 ビット以上にする）
+const minJWTSecretLength = 32
+
+// モックサーバー・テストで固定する時刻
+var FrozenTime = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// 組み立て済みの依存関係
+type Container struct {
+	Env string
+
+	Clock       clock.Clock
+	IDGenerator idgen.IDGenerator
+
+	ItemRepository     usecase.ItemRepository
+	AuditLogRepository usecase.AuditLogRepository
+	WebhookRepository  usecase.WebhookRepository
+	DeliveryRepository usecase.WebhookDeliveryRepository
+	EventStore         usecase.EventStore
+	RetentionPolicies  usecase.RetentionPolicyRepository
+	Impersonations     usecase.ImpersonationRepository
+	APIKeys            usecase.APIKeyRepository
+	SandboxKeys        usecase.APIKeyRepository
+	RefreshTokens      usecase.RefreshTokenRepository
+	TwoFactors         usecase.TwoFactorRepository
+	PasswordResets     usecase.PasswordResetRepository
+	UserIdentities     usecase.UserIdentityRepository
+	OIDCLogins         usecase.OIDCLoginRepository
+	ReferenceData      usecase.ReferenceDataRepository
+	PortfolioSnapshots usecase.PortfolioSnapshotRepository
+	UserRepository     usecase.UserRepository
+	Organizations      usecase.OrganizationRepository
+	Invitations        usecase.InvitationRepository
+	DeprecationUsage   usecase.DeprecationUsageRepository
+	Exports            usecase.ExportRepository
+	Blobs              usecase.BlobRepository
+	Attachments        usecase.AttachmentRepository
+	Images             usecase.ImageRepository
+	Quarantine         usecase.QuarantineRepository
+	Checkpoints        usecase.ReplicationCheckpointRepository
+	EventConsumers     usecase.EventConsumerRepository
+	ImportProfiles     usecase.ImportProfileRepository
+	ImportStaging      usecase.ImportStagingRepository
+	Verifications      usecase.ItemVerificationRepository
+	Preferences        usecase.NotificationPreferenceRepository
+	Transactor         usecase.Transactor
+
+	// DB にある任意の列。CheckSchema で確かめるまではすべてあるものとして扱う
+	SchemaColumns *database.SchemaColumns
+
+	// 署名付きリクエストの使用済み nonce（SIGNING_KEYS を設定していない場合は nil）
+	ReplayCache usecase.ReplayCache
+
+	// サンドボックスの API キーごとの架空のアイテム（SANDBOX_ENABLED が false の場合は nil）
+	SandboxItems *database.SandboxItemRepository
+
+	// 全文検索のインデックス（MEILISEARCH_URL が空の場合は nil で、リポジトリの LIKE で検索する）
+	SearchIndex *searchInfra.MeilisearchIndex
+
+	WebhookSender        usecase.WebhookSender
+	Mailer               usecase.Mailer
+	ItemUsecase          usecase.ItemUsecase
+	WebhookUsecase       usecase.WebhookUsecase
+	RetentionUsecase     usecase.RetentionUsecase
+	ImpersonationUsecase usecase.ImpersonationUsecase
+	AuthUsecase          usecase.AuthUsecase          // JWT_SECRET を設定していない場合は nil
+	TwoFactorUsecase     usecase.TwoFactorUsecase     // JWT_SECRET を設定していない場合は nil
+	PasswordResetUsecase usecase.PasswordResetUsecase // JWT_SECRET を設定していない場合は nil
+	OIDCUsecase          usecase.OIDCUsecase          // JWT_SECRET か OIDC のプロバイダーを設定していない場合は nil
+	APIKeyUsecase        usecase.APIKeyUsecase
+	SandboxKeyUsecase    usecase.APIKeyUsecase // SANDBOX_ENABLED が false の場合は nil
+	SandboxItemUsecase   usecase.ItemUsecase   // SANDBOX_ENABLED が false の場合は nil
+	ReferenceUsecase     usecase.ReferenceUsecase
+	PortfolioUsecase     usecase.PortfolioUsecase
+	ScenarioUsecase      usecase.ScenarioUsecase
+	DashboardUsecase     usecase.DashboardUsecase
+	UserUsecase          usecase.UserUsecase
+	OrganizationUsecase  usecase.OrganizationUsecase
+	TenantUsecase        usecase.TenantUsecase
+	SLOUsecase           usecase.SLOUsecase
+	DeprecationUsecase   usecase.DeprecationUsecase
+	ExportUsecase        usecase.ExportUsecase
+	BlobUsecase          usecase.BlobUsecase
+	AttachmentUsecase    usecase.AttachmentUsecase
+	ImageUsecase         usecase.ImageUsecase
+	QuarantineUsecase    usecase.QuarantineUsecase
+	ImportProfileUsecase usecase.ImportProfileUsecase
+	ImportStagingUsecase usecase.ImportStagingUsecase
+	VerificationUsecase  usecase.VerificationUsecase
+	NotificationUsecase  usecase.NotificationUsecase
+	ChangeStream         usecase.ChangeSource   // 他のリージョンのスタンバイに公開する変更ストリーム
+	ReplicaUsecase       usecase.ReplicaUsecase // REPLICATION_SOURCE_URL を設定していない場合は nil
+	EventConsumerUsecase usecase.EventConsumerUsecase
+
+	ItemHandler          *itemController.ItemHandler
+	WebhookHandler       *webhookController.WebhookHandler
+	RetentionHandler     *retention.RetentionHandler
+	ImpersonationHandler *impersonation.ImpersonationHandler
+	AuthHandler          *authController.AuthHandler          // JWT_SECRET を設定していない場合は nil
+	TwoFactorHandler     *authController.TwoFactorHandler     // JWT_SECRET を設定していない場合は nil
+	PasswordResetHandler *authController.PasswordResetHandler // JWT_SECRET を設定していない場合は nil
+	OIDCHandler          *authController.OIDCHandler          // OIDCUsecase がない場合は nil
+	APIKeyHandler        *authController.APIKeyHandler
+	SandboxKeyHandler    *authController.APIKeyHandler // SANDBOX_ENABLED が false の場合は nil
+	SandboxItemHandler   *itemController.ItemHandler   // SANDBOX_ENABLED が false の場合は nil
+	ReferenceHandler     *reference.ReferenceHandler
+	ReportHandler        *reports.ReportHandler
+	GrafanaHandler       *grafana.GrafanaHandler
+	DocsHandler          *docs.DocsHandler
+	GraphQLHandler       *graphqlController.GraphQLHandler
+	SCIMHandler          *scim.SCIMHandler
+	UserHandler          *users.UserHandler
+	OrganizationHandler  *organizations.OrganizationHandler
+	TenantHandler        *tenants.TenantHandler
+	DeprecationHandler   *deprecations.DeprecationHandler
+	ExportHandler        *exports.ExportHandler
+	AttachmentHandler    *attachments.AttachmentHandler
+	ImageHandler         *images.ImageHandler
+	QuarantineHandler    *quarantine.QuarantineHandler
+	ImportProfileHandler *importprofiles.ImportProfileHandler
+	VerificationHandler  *verifications.VerificationHandler
+	NotificationHandler  *notifications.NotificationHandler
+	ReplicationHandler   *replicationController.ReplicationHandler
+	EventConsumerHandler *replicationController.EventConsumerHandler
+	SystemHandler        *system.SystemHandler
+
+	// 書き込みを受け付けるかどうか。ハンドラーではなく ReadOnly.Middleware で判定する（/graphql だけはハンドラーで判定する）
+	ReadOnly *appMiddleware.ReadOnlyMode
+
+	sqlHandler database.SqlHandler
+	closers    []func() error
+}
+
+// 環境ごとに差し替える依存関係の提供関数
+type ProviderSet struct {
+	Name               string
+	Clock              func() clock.Clock
+	IDGenerator        func() idgen.IDGenerator
+	ItemRepository     func(c *Container) (usecase.ItemRepository, error)
+	AuditLogRepository func(c *Container) (usecase.AuditLogRepository, error)
+	WebhookRepository  func(c *Container) (usecase.WebhookRepository, error)
+	DeliveryRepository func(c *Container) (usecase.WebhookDeliveryRepository, error)
+	EventStore         func(c *Container) (usecase.EventStore, error)
+	RetentionPolicies  func(c *Container) (usecase.RetentionPolicyRepository, error)
+	Impersonations     func(c *Container) (usecase.ImpersonationRepository, error)
+	APIKeys            func(c *Container) (usecase.APIKeyRepository, error)
+	SandboxKeys        func(c *Container) (usecase.APIKeyRepository, error)
+	RefreshTokens      func(c *Container) (usecase.RefreshTokenRepository, error)
+	TwoFactors         func(c *Container) (usecase.TwoFactorRepository, error)
+	PasswordResets     func(c *Container) (usecase.PasswordResetRepository, error)
+	UserIdentities     func(c *Container) (usecase.UserIdentityRepository, error)
+	OIDCLogins         func(c *Container) (usecase.OIDCLoginRepository, error)
+	ReferenceData      func(c *Container) (usecase.ReferenceDataRepository, error)
+	PortfolioSnapshots func(c *Container) (usecase.PortfolioSnapshotRepository, error)
+	UserRepository     func(c *Container) (usecase.UserRepository, error)
+	Organizations      func(c *Container) (usecase.OrganizationRepository, error)
+	Invitations        func(c *Container) (usecase.InvitationRepository, error)
+	DeprecationUsage   func(c *Container) (usecase.DeprecationUsageRepository, error)
+	Exports            func(c *Container) (usecase.ExportRepository, error)
+	Blobs              func(c *Container) (usecase.BlobRepository, error)
+	Attachments        func(c *Container) (usecase.AttachmentRepository, error)
+	Images             func(c *Container) (usecase.ImageRepository, error)
+	Quarantine         func(c *Container) (usecase.QuarantineRepository, error)
+	Checkpoints        func(c *Container) (usecase.ReplicationCheckpointRepository, error)
+	EventConsumers     func(c *Container) (usecase.EventConsumerRepository, error)
+	ImportProfiles     func(c *Container) (usecase.ImportProfileRepository, error)
+	ImportStaging      func(c *Container) (usecase.ImportStagingRepository, error)
+	Verifications      func(c *Container) (usecase.ItemVerificationRepository, error)
+	Preferences        func(c *Container) (usecase.NotificationPreferenceRepository, error)
+	Transactor         func(c *Container) (usecase.Transactor, error)
+}
+
+// 本番用: MySQL に接続する
+var ProdProviders = ProviderSet{
+	Name:        "prod",
+	Clock:       func() clock.Clock { return clock.System{} },
+	IDGenerator: func() idgen.IDGenerator { return idgen.NewSequence() },
+	ItemRepository: func(c *Container) (usecase.ItemRepository, error) {
+		return &database.ItemRepository{SqlHandler: c.SqlHandler()}, nil
+	},
+	AuditLogRepository: func(c *Container) (usecase.AuditLogRepository, error) {
+		return &database.AuditLogRepository{SqlHandler: c.SqlHandler()}, nil
+	},
+	WebhookRepository: func(c *Container) (usecase.WebhookRepository, error) {
+		return &database.WebhookRepository{SqlHandler: c.SqlHandler(), Columns: c.SchemaColumns}, nil
+	},
+	DeliveryRepository: func(c *Container) (usecase.WebhookDeliveryRepository, error) {
+		return &database.WebhookDeliveryRepository{SqlHandler: c.SqlHandler(), Columns: c.SchemaColumns}, nil
+	},
+	EventStore: func(c *Container) (usecase.EventStore, error) {
+		return &database.EventStore{SqlHandler: c.SqlHandler()}, nil
+	},
+	RetentionPolicies: func(c *Container) (usecase.RetentionPolicyRepository, error) {
+		return &database.RetentionPolicyRepository{SqlHandler: c.SqlHandler()}, nil
+	},
+	Impersonations: func(c *Container) (usecase.ImpersonationRepository, error) {
+		return &database.ImpersonationRepository{SqlHandler: c.SqlHandler()}, nil
+	},
+	APIKeys: func(c *Container) (usecase.APIKeyRepository, error) {
+		return &database.APIKeyRepository{SqlHandler: c.SqlHandler()}, nil
+	},
+	SandboxKeys: func(c *Container) (usecase.APIKeyRepository, error) {
+		return database.NewSandboxKeyRepository(c.SqlHandler()), nil
+	},
+	RefreshTokens: func(c *Container) (usecase.RefreshTokenRepository, error) {
+		return &database.RefreshTokenRepository{SqlHandler: c.SqlHandler()}, nil
+	},
+	TwoFactors: func(c *Container) (usecase.TwoFactorRepository, error) {
+		return &database.TwoFactorRepository{SqlHandler: c.SqlHandler()}, nil
+	},
+	PasswordResets: func(c *Container) (usecase.PasswordResetRepository, error) {
+		return &database.PasswordResetRepository{SqlHandler: c.SqlHandler()}, nil
+	},
+	UserIdentities: func(c *Container) (usecase.UserIdentityRepository, error) {
+		return &database.UserIdentityRepository{SqlHandler: c.SqlHandler()}, nil
+	},
+	OIDCLogins: func(c *Container) (usecase.OIDCLoginRepository, error) {
+		return &database.OIDCLoginRepository{SqlHandler: c.SqlHandler()}, nil
+	},
+	ReferenceData: func(c *Container) (usecase.ReferenceDataRepository, error) {
+		return &database.ReferenceDataRepository{SqlHandler: c.SqlHandler()}, nil
+	},
+	PortfolioSnapshots: func(c *Container) (usecase.PortfolioSnapshotRepository, error) {
+		return &database.PortfolioSnapshotRepository{SqlHandler: c.SqlHandler()}, nil
+	},
+	UserRepository: func(c *Container) (usecase.UserRepository, error) {
+		return &database.UserRepository{SqlHandler: c.SqlHandler()}, nil
+	},
+	Organizations: func(c *Container) (usecase.OrganizationRepository, error) {
+		return &database.OrganizationRepository{SqlHandler: c.SqlHandler()}, nil
+	},
+	Invitations: func(c *Container) (usecase.InvitationRepository, error) {
+		return &database.InvitationRepository{SqlHandler: c.SqlHandler()}, nil
+	},
+	DeprecationUsage: func(c *Container) (usecase.DeprecationUsageRepository, error) {
+		return &database.DeprecationUsageRepository{SqlHandler: c.SqlHandler()}, nil
+	},
+	Exports: func(c *Container) (usecase.ExportRepository, error) {
+		return &database.ExportRepository{SqlHandler: c.SqlHandler()}, nil
+	},
+	Blobs: func(c *Container) (usecase.BlobRepository, error) {
+		return &database.BlobRepository{SqlHandler: c.SqlHandler()}, nil
+	},
+	Attachments: func(c *Container) (usecase.AttachmentRepository, error) {
+		return &database.AttachmentRepository{SqlHandler: c.SqlHandler()}, nil
+	},
+	Images: func(c *Container) (usecase.ImageRepository, error) {
+		return &database.ImageRepository{SqlHandler: c.SqlHandler()}, nil
+	},
+	Quarantine: func(c *Container) (usecase.QuarantineRepository, error) {
+		return &database.QuarantineRepository{SqlHandler: c.SqlHandler()}, nil
+	},
+	Checkpoints: func(c *Container) (usecase.ReplicationCheckpointRepository, error) {
+		return &database.ReplicationCheckpointRepository{SqlHandler: c.SqlHandler()}, nil
+	},
+	EventConsumers: func(c *Container) (usecase.EventConsumerRepository, error) {
+		return &database.EventConsumerRepository{SqlHandler: c.SqlHandler()}, nil
+	},
+	ImportProfiles: func(c *Container) (usecase.ImportProfileRepository, error) {
+		return &database.ImportProfileRepository{SqlHandler: c.SqlHandler()}, nil
+	},
+	ImportStaging: func(c *Container) (usecase.ImportStagingRepository, error) {
+		return &database.ImportStagingRepository{SqlHandler: c.SqlHandler()}, nil
+	},
+	Verifications: func(c *Container) (usecase.ItemVerificationRepository, error) {
+		return &database.ItemVerificationRepository{SqlHandler: c.SqlHandler()}, nil
+	},
+	Preferences: func(c *Container) (usecase.NotificationPreferenceRepository, error) {
+		return &database.NotificationPreferenceRepository{SqlHandler: c.SqlHandler()}, nil
+	},
+	Transactor: func(c *Container) (usecase.Transactor, error) {
+		return c.SqlHandler(), nil
+	},
+}
+
+// 開発用: DBなしでサンプルデータ入りのインメモリリポジトリを使う
+// レスポンスを固定できるよう時刻も固定する
+var DevInMemoryProviders = ProviderSet{
+	Name:        "dev-in-memory",
+	Clock:       func() clock.Clock { return clock.NewFrozen(FrozenTime) },
+	IDGenerator: func() idgen.IDGenerator { return idgen.NewSequence() },
+	ItemRepository: func(c *Container) (usecase.ItemRepository, error) {
+		return database.NewMemoryItemRepository(c.IDGenerator, sampleItems(c.Clock.Now())...), nil
+	},
+	AuditLogRepository: func(c *Container) (usecase.AuditLogRepository, error) {
+		return database.NewMemoryAuditLogRepository(), nil
+	},
+	WebhookRepository: func(c *Container) (usecase.WebhookRepository, error) {
+		return database.NewMemoryWebhookRepository(idgen.NewSequence()), nil
+	},
+	DeliveryRepository: func(c *Container) (usecase.WebhookDeliveryRepository, error) {
+		return database.NewMemoryWebhookDeliveryRepository(), nil
+	},
+	EventStore: func(c *Container) (usecase.EventStore, error) {
+		return database.NewMemoryEventStore(), nil
+	},
+	RetentionPolicies: func(c *Container) (usecase.RetentionPolicyRepository, error) {
+		return database.NewMemoryRetentionPolicyRepository(), nil
+	},
+	Impersonations: func(c *Container) (usecase.ImpersonationRepository, error) {
+		return database.NewMemoryImpersonationRepository(), nil
+	},
+	APIKeys: func(c *Container) (usecase.APIKeyRepository, error) {
+		return database.NewMemoryAPIKeyRepository(), nil
+	},
+	SandboxKeys: func(c *Container) (usecase.APIKeyRepository, error) {
+		return database.NewMemoryAPIKeyRepository(), nil
+	},
+	RefreshTokens: func(c *Container) (usecase.RefreshTokenRepository, error) {
+		return database.NewMemoryRefreshTokenRepository(), nil
+	},
+	TwoFactors: func(c *Container) (usecase.TwoFactorRepository, error) {
+		return database.NewMemoryTwoFactorRepository(), nil
+	},
+	PasswordResets: func(c *Container) (usecase.PasswordResetRepository, error) {
+		return database.NewMemoryPasswordResetRepository(), nil
+	},
+	UserIdentities: func(c *Container) (usecase.UserIdentityRepository, error) {
+		return database.NewMemoryUserIdentityRepository(), nil
+	},
+	OIDCLogins: func(c *Container) (usecase.OIDCLoginRepository, error) {
+		return database.NewMemoryOIDCLoginRepository(), nil
+	},
+	ReferenceData: func(c *Container) (usecase.ReferenceDataRepository, error) {
+		return database.NewMemoryReferenceDataRepository(sampleCategories(c.Clock.Now())...), nil
+	},
+	PortfolioSnapshots: func(c *Container) (usecase.PortfolioSnapshotRepository, error) {
+		return database.NewMemoryPortfolioSnapshotRepository(), nil
+	},
+	UserRepository: func(c *Container) (usecase.UserRepository, error) {
+		return database.NewMemoryUserRepository(sampleUsers(c.Clock.Now())...), nil
+	},
+	Organizations: func(c *Container) (usecase.OrganizationRepository, error) {
+		return database.NewMemoryOrganizationRepository(), nil
+	},
+	Invitations: func(c *Container) (usecase.InvitationRepository, error) {
+		return database.NewMemoryInvitationRepository(), nil
+	},
+	DeprecationUsage: func(c *Container) (usecase.DeprecationUsageRepository, error) {
+		return database.NewMemoryDeprecationUsageRepository(), nil
+	},
+	Exports: func(c *Container) (usecase.ExportRepository, error) {
+		return database.NewMemoryExportRepository(), nil
+	},
+	Blobs: func(c *Container) (usecase.BlobRepository, error) {
+		return database.NewMemoryBlobRepository(), nil
+	},
+	Attachments: func(c *Container) (usecase.AttachmentRepository, error) {
+		return database.NewMemoryAttachmentRepository(), nil
+	},
+	Images: func(c *Container) (usecase.ImageRepository, error) {
+		return database.NewMemoryImageRepository(), nil
+	},
+	Quarantine: func(c *Container) (usecase.QuarantineRepository, error) {
+		return database.NewMemoryQuarantineRepository(), nil
+	},
+	Checkpoints: func(c *Container) (usecase.ReplicationCheckpointRepository, error) {
+		return database.NewMemoryReplicationCheckpointRepository(), nil
+	},
+	EventConsumers: func(c *Container) (usecase.EventConsumerRepository, error) {
+		return database.NewMemoryEventConsumerRepository(), nil
+	},
+	ImportProfiles: func(c *Container) (usecase.ImportProfileRepository, error) {
+		return database.NewMemoryImportProfileRepository(), nil
+	},
+	ImportStaging: func(c *Container) (usecase.ImportStagingRepository, error) {
+		return database.NewMemoryImportStagingRepository(), nil
+	},
+	Verifications: func(c *Container) (usecase.ItemVerificationRepository, error) {
+		return database.NewMemoryItemVerificationRepository(), nil
+	},
+	Preferences: func(c *Container) (usecase.NotificationPreferenceRepository, error) {
+		return database.NewMemoryNotificationPreferenceRepository(), nil
+	},
+	Transactor: func(c *Container) (usecase.Transactor, error) {
+		return database.MemoryTransactor{}, nil
+	},
+}
+
+// テスト用: 空のインメモリリポジトリを使う
+var TestProviders = ProviderSet{
+	Name:        "test",
+	Clock:       func() clock.Clock { return clock.NewFrozen(FrozenTime) },
+	IDGenerator: func() idgen.IDGenerator { return idgen.NewSequence() },
+	ItemRepository: func(c *Container) (usecase.ItemRepository, error) {
+		return database.NewMemoryItemRepository(c.IDGenerator), nil
+	},
+	AuditLogRepository: func(c *Container) (usecase.AuditLogRepository, error) {
+		return database.NewMemoryAuditLogRepository(), nil
+	},
+	WebhookRepository: func(c *Container) (usecase.WebhookRepository, error) {
+		return database.NewMemoryWebhookRepository(idgen.NewSequence()), nil
+	},
+	DeliveryRepository: func(c *Container) (usecase.WebhookDeliveryRepository, error) {
+		return database.NewMemoryWebhookDeliveryRepository(), nil
+	},
+	EventStore: func(c *Container) (usecase.EventStore, error) {
+		return database.NewMemoryEventStore(), nil
+	},
+	RetentionPolicies: func(c *Container) (usecase.RetentionPolicyRepository, error) {
+		return database.NewMemoryRetentionPolicyRepository(), nil
+	},
+	Impersonations: func(c *Container) (usecase.ImpersonationRepository, error) {
+		return database.NewMemoryImpersonationRepository(), nil
+	},
+	APIKeys: func(c *Container) (usecase.APIKeyRepository, error) {
+		return database.NewMemoryAPIKeyRepository(), nil
+	},
+	SandboxKeys: func(c *Container) (usecase.APIKeyRepository, error) {
+		return database.NewMemoryAPIKeyRepository(), nil
+	},
+	RefreshTokens: func(c *Container) (usecase.RefreshTokenRepository, error) {
+		return database.NewMemoryRefreshTokenRepository(), nil
+	},
+	TwoFactors: func(c *Container) (usecase.TwoFactorRepository, error) {
+		return database.NewMemoryTwoFactorRepository(), nil
+	},
+	PasswordResets: func(c *Container) (usecase.PasswordResetRepository, error) {
+		return database.NewMemoryPasswordResetRepository(), nil
+	},
+	UserIdentities: func(c *Container) (usecase.UserIdentityRepository, error) {
+		return database.NewMemoryUserIdentityRepository(), nil
+	},
+	OIDCLogins: func(c *Container) (usecase.OIDCLoginRepository, error) {
+		return database.NewMemoryOIDCLoginRepository(), nil
+	},
+	ReferenceData: func(c *Container) (usecase.ReferenceDataRepository, error) {
+		return database.NewMemoryReferenceDataRepository(), nil
+	},
+	PortfolioSnapshots: func(c *Container) (usecase.PortfolioSnapshotRepository, error) {
+		return database.NewMemoryPortfolioSnapshotRepository(), nil
+	},
+	UserRepository: func(c *Container) (usecase.UserRepository, error) {
+		return database.NewMemoryUserRepository(), nil
+	},
+	Organizations: func(c *Container) (usecase.OrganizationRepository, error) {
+		return database.NewMemoryOrganizationRepository(), nil
+	},
+	Invitations: func(c *Container) (usecase.InvitationRepository, error) {
+		return database.NewMemoryInvitationRepository(), nil
+	},
+	DeprecationUsage: func(c *Container) (usecase.DeprecationUsageRepository, error) {
+		return database.NewMemoryDeprecationUsageRepository(), nil
+	},
+	Exports: func(c *Container) (usecase.ExportRepository, error) {
+		return database.NewMemoryExportRepository(), nil
+	},
+	Blobs: func(c *Container) (usecase.BlobRepository, error) {
+		return database.NewMemoryBlobRepository(), nil
+	},
+	Attachments: func(c *Container) (usecase.AttachmentRepository, error) {
+		return database.NewMemoryAttachmentRepository(), nil
+	},
+	Images: func(c *Container) (usecase.ImageRepository, error) {
+		return database.NewMemoryImageRepository(), nil
+	},
+	Quarantine: func(c *Container) (usecase.QuarantineRepository, error) {
+		return database.NewMemoryQuarantineRepository(), nil
+	},
+	Checkpoints: func(c *Container) (usecase.ReplicationCheckpointRepository, error) {
+		return database.NewMemoryReplicationCheckpointRepository(), nil
+	},
+	EventConsumers: func(c *Container) (usecase.EventConsumerRepository, error) {
+		return database.NewMemoryEventConsumerRepository(), nil
+	},
+	ImportProfiles: func(c *Container) (usecase.ImportProfileRepository, error) {
+		return database.NewMemoryImportProfileRepository(), nil
+	},
+	ImportStaging: func(c *Container) (usecase.ImportStagingRepository, error) {
+		return database.NewMemoryImportStagingRepository(), nil
+	},
+	Verifications: func(c *Container) (usecase.ItemVerificationRepository, error) {
+		return database.NewMemoryItemVerificationRepository(), nil
+	},
+	Preferences: func(c *Container) (usecase.NotificationPreferenceRepository, error) {
+		return database.NewMemoryNotificationPreferenceRepository(), nil
+	},
+	Transactor: func(c *Container) (usecase.Transactor, error) {
+		return database.MemoryTransactor{}, nil
+	},
+}
+
+// APP_ENV の値から ProviderSet を選ぶ
+func ProvidersFor(env string) ProviderSet {
+	switch env {
+	case "memory", "dev-in-memory":
+		return DevInMemoryProviders
+	case "test":
+		return TestProviders
+	default:
+		return ProdProviders
+	}
+}
+
+func New(env string) (*Container, error) {
+	return Build(env, ProvidersFor(env))
+}
+
+func Build(env string, providers ProviderSet) (*Container, error) {
+	c := &Container{
+		Env:           env,
+		Clock:         providers.Clock(),
+		IDGenerator:   providers.IDGenerator(),
+		SchemaColumns: database.NewSchemaColumns(),
+	}
+
+	itemRepo, err := providers.ItemRepository(c)
+	if err != nil {
+		c.Close()
+		return nil, fmt.Errorf("failed to provide item repository (%s): %w", providers.Name, err)
+	}
+	c.ItemRepository = itemRepo
+
+	auditLogRepo, err := providers.AuditLogRepository(c)
+	if err != nil {
+		c.Close()
+		return nil, fmt.Errorf("failed to provide audit log repository (%s): %w", providers.Name, err)
+	}
+	c.AuditLogRepository = auditLogRepo
+
+	webhookRepo, err := providers.WebhookRepository(c)
+	if err != nil {
+		c.Close()
+		return nil, fmt.Errorf("failed to provide webhook repository (%s): %w", providers.Name, err)
+	}
+	c.WebhookRepository = webhookRepo
+
+	deliveryRepo, err := providers.DeliveryRepository(c)
+	if err != nil {
+		c.Close()
+		return nil, fmt.Errorf("failed to provide webhook delivery repository (%s): %w", providers.Name, err)
+	}
+	c.DeliveryRepository = deliveryRepo
+
+	eventStore, err := providers.EventStore(c)
+	if err != nil {
+		c.Close()
+		return nil, fmt.Errorf("failed to provide event store (%s): %w", providers.Name, err)
+	}
+	c.EventStore = eventStore
+
+	retentionPolicies, err := providers.RetentionPolicies(c)
+	if err != nil {
+		c.Close()
+		return nil, fmt.Errorf("failed to provide retention policy repository (%s): %w", providers.Name, err)
+	}
+	c.RetentionPolicies = retentionPolicies
+
+	impersonations, err := providers.Impersonations(c)
+	if err != nil {
+		c.Close()
+		return nil, fmt.Errorf("failed to provide impersonation repository (%s): %w", providers.Name, err)
+	}
+	c.Impersonations = impersonations
+
+	apiKeys, err := providers.APIKeys(c)
+	if err != nil {
+		c.Close()
+		return nil, fmt.Errorf("failed to provide api key repository (%s): %w", providers.Name, err)
+	}
+	c.APIKeys = apiKeys
+
+	sandboxKeys, err := providers.SandboxKeys(c)
+	if err != nil {
+		c.Close()
+		return nil, fmt.Errorf("failed to provide sandbox key repository (%s): %w", providers.Name, err)
+	}
+	c.SandboxKeys = sandboxKeys
+
+	refreshTokens, err := providers.RefreshTokens(c)
+	if err != nil {
+		c.Close()
+		return nil, fmt.Errorf("failed to provide refresh token repository (%s): %w", providers.Name, err)
+	}
+	c.RefreshTokens = refreshTokens
+
+	twoFactors, err := providers.TwoFactors(c)
+	if err != nil {
+		c.Close()
+		return nil, fmt.Errorf("failed to provide two-factor repository (%s): %w", providers.Name, err)
+	}
+	c.TwoFactors = twoFactors
+
+	passwordResets, err := providers.PasswordResets(c)
+	if err != nil {
+		c.Close()
+		return nil, fmt.Errorf("failed to provide password reset repository (%s): %w", providers.Name, err)
+	}
+	c.PasswordResets = passwordResets
+
+	userIdentities, err := providers.UserIdentities(c)
+	if err != nil {
+		c.Close()
+		return nil, fmt.Errorf("failed to provide user identity repository (%s): %w", providers.Name, err)
+	}
+	c.UserIdentities = userIdentities
+
+	oidcLogins, err := providers.OIDCLogins(c)
+	if err != nil {
+		c.Close()
+		return nil, fmt.Errorf("failed to provide oidc login repository (%s): %w", providers.Name, err)
+	}
+	c.OIDCLogins = oidcLogins
+
+	referenceData, err := providers.ReferenceData(c)
+	if err != nil {
+		c.Close()
+		return nil, fmt.Errorf("failed to provide reference data repository (%s): %w", providers.Name, err)
+	}
+	c.ReferenceData = referenceData
+
+	portfolioSnapshots, err := providers.PortfolioSnapshots(c)
+	if err != nil {
+		c.Close()
+		return nil, fmt.Errorf("failed to provide portfolio snapshot repository (%s): %w", providers.Name, err)
+	}
+	c.PortfolioSnapshots = portfolioSnapshots
+
+	userRepo, err := providers.UserRepository(c)
+	if err != nil {
+		c.Close()
+		return nil, fmt.Errorf("failed to provide user repository (%s): %w", providers.Name, err)
+	}
+	c.UserRepository = userRepo
+
+	orgs, err := providers.Organizations(c)
+	if err != nil {
+		c.Close()
+		return nil, fmt.Errorf("failed to provide organization repository (%s): %w", providers.Name, err)
+	}
+	c.Organizations = orgs
+
+	invitations, err := providers.Invitations(c)
+	if err != nil {
+		c.Close()
+		return nil, fmt.Errorf("failed to provide invitation repository (%s): %w", providers.Name, err)
+	}
+	c.Invitations = invitations
+
+	deprecationUsage, err := providers.DeprecationUsage(c)
+	if err != nil {
+		c.Close()
+		return nil, fmt.Errorf("failed to provide deprecation usage repository (%s): %w", providers.Name, err)
+	}
+	c.DeprecationUsage = deprecationUsage
+
+	exportRepo, err := providers.Exports(c)
+	if err != nil {
+		c.Close()
+		return nil, fmt.Errorf("failed to provide export repository (%s): %w", providers.Name, err)
+	}
+	c.Exports = exportRepo
+
+	blobs, err := providers.Blobs(c)
+	if err != nil {
+		c.Close()
+		return nil, fmt.Errorf("failed to provide blob repository (%s): %w", providers.Name, err)
+	}
+	c.Blobs = blobs
+
+	attachmentRepo, err := providers.Attachments(c)
+	if err != nil {
+		c.Close()
+		return nil, fmt.Errorf("failed to provide attachment repository (%s): %w", providers.Name, err)
+	}
+	c.Attachments = attachmentRepo
+
+	imageRepo, err := providers.Images(c)
+	if err != nil {
+		c.Close()
+		return nil, fmt.Errorf("failed to provide image repository (%s): %w", providers.Name, err)
+	}
+	c.Images = imageRepo
+
+	quarantineRepo, err := providers.Quarantine(c)
+	if err != nil {
+		c.Close()
+		return nil, fmt.Errorf("failed to provide quarantine repository (%s): %w", providers.Name, err)
+	}
+	c.Quarantine = quarantineRepo
+
+	checkpoints, err := providers.Checkpoints(c)
+	if err != nil {
+		c.Close()
+		return nil, fmt.Errorf("failed to provide replication checkpoint repository (%s): %w", providers.Name, err)
+	}
+	c.Checkpoints = checkpoints
+
+	eventConsumers, err := providers.EventConsumers(c)
+	if err != nil {
+		c.Close()
+		return nil, fmt.Errorf("failed to provide event consumer repository (%s): %w", providers.Name, err)
+	}
+	c.EventConsumers = eventConsumers
+
+	importProfiles, err := providers.ImportProfiles(c)
+	if err != nil {
+		c.Close()
+		return nil, fmt.Errorf("failed to provide import profile repository (%s): %w", providers.Name, err)
+	}
+	c.ImportProfiles = importProfiles
+
+	importStaging, err := providers.ImportStaging(c)
+	if err != nil {
+		c.Close()
+		return nil, fmt.Errorf("failed to provide import staging repository (%s): %w", providers.Name, err)
+	}
+	c.ImportStaging = importStaging
+
+	verificationRepo, err := providers.Verifications(c)
+	if err != nil {
+		c.Close()
+		return nil, fmt.Errorf("failed to provide item verification repository (%s): %w", providers.Name, err)
+	}
+	c.Verifications = verificationRepo
+
+	preferences, err := providers.Preferences(c)
+	if err != nil {
+		c.Close()
+		return nil, fmt.Errorf("failed to provide notification preference repository (%s): %w", providers.Name, err)
+	}
+	c.Preferences = preferences
+
+	transactor, err := providers.Transactor(c)
+	if err != nil {
+		c.Close()
+		return nil, fmt.Errorf("failed to provide transactor (%s): %w", providers.Name, err)
+	}
+	c.Transactor = transactor
+
+	c.WebhookSender = webhookInfra.NewHTTPSender(config.WebhookAllowPrivateNetworks)
+	c.Mailer = mailerFromConfig()
+	c.WebhookUsecase = usecase.NewWebhookUsecase(c.WebhookRepository, c.DeliveryRepository, c.WebhookSender, c.Clock)
+	c.addCloser(func() error {
+		c.WebhookUsecase.Wait()
+		return nil
+	})
+
+	blobStore, err := blobStoreFromConfig()
+	if err != nil {
+		c.Close()
+		return nil, err
+	}
+	c.BlobUsecase = usecase.NewBlobUsecase(blobStore, c.Blobs, c.Clock)
+	c.QuarantineUsecase = usecase.NewQuarantineUsecase(scannerFromConfig(), c.Quarantine, c.BlobUsecase, c.Clock)
+	imageURLs, imageDelivery := imageURLsFromConfig(blobStore)
+	c.ImageUsecase = usecase.NewImageUsecase(c.Images, c.ItemRepository, c.BlobUsecase, c.QuarantineUsecase, imageURLs, config.ImageMaxPerItem, c.Clock)
+	c.addCloser(func() error {
+		c.ImageUsecase.Wait()
+		return nil
+	})
+	c.AttachmentUsecase = usecase.NewAttachmentUsecase(
+		c.Attachments,
+		c.ItemRepository,
+		c.BlobUsecase,
+		c.QuarantineUsecase,
+		c.Transactor,
+		config.AttachmentGCGrace,
+		c.Clock,
+	)
+
+	c.ReferenceUsecase = usecase.NewReferenceUsecase(c.ReferenceData, c.ItemRepository, c.Transactor, c.Clock)
+	c.PortfolioUsecase = usecase.NewPortfolioUsecase(c.ItemRepository, c.ReferenceData, c.PortfolioSnapshots, c.Clock)
+	c.ScenarioUsecase = usecase.NewScenarioUsecase(c.ItemRepository, c.ReferenceData, c.Clock)
+
+	imageCleaner := usecase.NewItemImageCleaner(c.Images, c.BlobUsecase)
+	publishers := usecase.Publishers{
+		usecase.NewEventRecorder(c.EventStore),
+		c.WebhookUsecase,
+		imageCleaner,
+	}
+	itemOptions := []usecase.Option{
+		usecase.WithClock(c.Clock),
+		usecase.WithAuditLog(c.AuditLogRepository),
+		usecase.WithReasonPolicy(configReasonPolicy{}),
+		usecase.WithTransactor(c.Transactor),
+		usecase.WithOrganizations(c.Organizations),
+		usecase.WithUsers(c.UserRepository),
+		usecase.WithImages(c.ImageUsecase),
+		usecase.WithReceipts(c.AttachmentUsecase),
+		usecase.WithCategories(c.ReferenceUsecase),
+		usecase.WithVerifications(c.Verifications),
+		usecase.WithMergedRecords(c.Images, c.Attachments),
+		usecase.WithPurgedRecords(imageCleaner),
+	}
+	// 全文検索を使う場合は、アイテムの変更をイベント経由でインデックスに反映する
+	if config.MeilisearchURL != "" {
+		c.SearchIndex = searchInfra.NewMeilisearchIndex(config.MeilisearchURL, config.MeilisearchAPIKey, config.MeilisearchIndex)
+		publishers = append(publishers, usecase.NewSearchIndexer(c.SearchIndex))
+		itemOptions = append(itemOptions, usecase.WithSearcher(c.SearchIndex))
+	}
+	c.ItemUsecase = usecase.NewItemUsecase(c.ItemRepository, append(itemOptions, usecase.WithEventPublisher(publishers))...)
+	c.DashboardUsecase = usecase.NewDashboardUsecase(c.ItemUsecase, c.PortfolioUsecase)
+	c.ImportProfileUsecase = usecase.NewImportProfileUsecase(c.ImportProfiles, c.Clock)
+	c.ImportStagingUsecase = usecase.NewImportStagingUsecase(c.ImportStaging, c.ItemUsecase, c.Transactor, config.ImportStagingTTL, c.Clock)
+	c.VerificationUsecase = usecase.NewVerificationUsecase(c.Verifications, c.ItemRepository, c.Attachments, c.AuditLogRepository, c.Transactor, c.Clock)
+
+	c.RetentionUsecase = usecase.NewRetentionUsecase(c.RetentionPolicies, map[string]usecase.RetentionTarget{
+		entity.RetentionAuditLogs: {Count: c.AuditLogRepository.CountBefore, Purge: c.AuditLogRepository.DeleteBefore},
+		entity.RetentionDeletedItems: {
+			Count:             c.ItemRepository.CountDeletedBefore,
+			Purge:             c.ItemUsecase.PurgeExpired,
+			DefaultRetainDays: func() int { return config.PurgeAfterDays },
+		},
+		entity.RetentionWebhookDeliveries: {Count: c.DeliveryRepository.CountBefore, Purge: c.DeliveryRepository.DeleteBefore},
+	}, c.Clock)
+	c.UserUsecase = usecase.NewUserUsecase(c.UserRepository, c.Clock)
+	c.ImpersonationUsecase = usecase.NewImpersonationUsecase(c.Impersonations, c.UserRepository, c.Clock)
+	c.APIKeyUsecase = usecase.NewAPIKeyUsecase(c.APIKeys, c.UserRepository, c.Clock)
+	// サンドボックスは本番のデータ・イベント・監査ログと切り離し、アイテムの基本の操作だけを提供する
+	if config.SandboxEnabled {
+		c.SandboxItems = database.NewSandboxItemRepository(c.Clock)
+		c.SandboxKeyUsecase = usecase.NewSandboxKeyUsecase(c.SandboxKeys, c.UserRepository, c.Clock)
+		c.SandboxItemUsecase = usecase.NewItemUsecase(c.SandboxItems, usecase.WithClock(c.Clock))
+	}
+	if config.JWTSecret != "" {
+		if len(config.JWTSecret) < minJWTSecretLength {
+			c.Close()
+			return nil, fmt.Errorf("JWT_SECRET must be at least %d bytes", minJWTSecretLength)
+		}
+		c.TwoFactorUsecase = usecase.NewTwoFactorUsecase(c.TwoFactors, c.UserRepository, config.TOTPIssuer, c.Clock)
+		c.AuthUsecase = usecase.NewAuthUsecase(c.UserRepository, c.RefreshTokens, c.TwoFactorUsecase, []byte(config.JWTSecret), config.JWTTTL, config.JWTRefreshTTL, c.Clock)
+		c.PasswordResetUsecase = usecase.NewPasswordResetUsecase(
+			c.PasswordResets,
+			c.UserRepository,
+			c.RefreshTokens,
+			c.Mailer,
+			c.Transactor,
+			config.PasswordResetTTL,
+			config.PasswordResetURL,
+			c.Clock,
+		)
+		if providers := identityProvidersFromConfig(); len(providers) > 0 {
+			if config.OIDCRedirectURL == "" {
+				c.Close()
+				return nil, errors.New("OIDC_REDIRECT_URL is required to log in with an identity provider")
+			}
+			c.OIDCUsecase = usecase.NewOIDCUsecase(providers, c.UserRepository, c.UserIdentities, c.OIDCLogins, c.AuthUsecase, c.Transactor, config.OIDCAutoProvision, c.Clock)
+		}
+	}
+	if config.SigningKeys != "" {
+		c.ReplayCache = replayCacheFromConfig(c.Clock)
+	}
+	c.OrganizationUsecase = usecase.NewOrganizationUsecase(
+		c.Organizations,
+		c.Invitations,
+		c.UserRepository,
+		c.Mailer,
+		c.Transactor,
+		c.Clock,
+		config.InvitationURL,
+	)
+	c.TenantUsecase = usecase.NewTenantUsecase(c.Organizations, c.UserRepository, c.ItemRepository, c.Transactor, publishers, c.Clock)
+
+	objectives, err := entity.ParseSLObjectives(config.SLOObjectives)
+	if err != nil {
+		c.Close()
+		return nil, fmt.Errorf("invalid SLO_OBJECTIVES: %w", err)
+	}
+	channels, err := notificationChannelsFromConfig(c.Mailer)
+	if err != nil {
+		c.Close()
+		return nil, err
+	}
+	c.NotificationUsecase = usecase.NewNotificationUsecase(channels, c.Preferences, c.UserRepository, c.Clock)
+	c.SLOUsecase = usecase.NewSLOUsecase(objectives, config.SLOBurnRateThreshold, usecase.NewSLOAlertNotifier(c.NotificationUsecase), c.Clock)
+
+	deprecationList, err := entity.ParseDeprecations(config.Deprecations)
+	if err != nil {
+		c.Close()
+		return nil, fmt.Errorf("invalid DEPRECATIONS: %w", err)
+	}
+	c.DeprecationUsecase = usecase.NewDeprecationUsecase(deprecationList, c.DeprecationUsage, c.Clock)
+	c.ExportUsecase = usecase.NewExportUsecase(
+		c.Exports,
+		c.ItemRepository,
+		c.EventStore,
+		c.BlobUsecase,
+		time.Duration(config.ExportRetainDays)*24*time.Hour,
+		c.Clock,
+	)
+
+	c.ChangeStream = usecase.NewChangeStream(c.EventStore)
+	c.EventConsumerUsecase = usecase.NewEventConsumerUsecase(c.EventConsumers, c.EventStore, c.Transactor, c.Clock)
+	if config.ReplicationSourceURL != "" {
+		replica, err := c.replicaFromConfig()
+		if err != nil {
+			c.Close()
+			return nil, err
+		}
+		c.ReplicaUsecase = replica
+	}
+
+	c.ItemHandler = itemController.NewItemHandler(c.ItemUsecase,
+		itemController.WithImportProfiles(c.ImportProfileUsecase),
+		itemController.WithImportStaging(c.ImportStagingUsecase),
+	)
+	c.ImportProfileHandler = importprofiles.NewImportProfileHandler(c.ImportProfileUsecase)
+	c.VerificationHandler = verifications.NewVerificationHandler(c.VerificationUsecase)
+	c.NotificationHandler = notifications.NewNotificationHandler(c.NotificationUsecase)
+	c.WebhookHandler = webhookController.NewWebhookHandler(c.WebhookUsecase)
+	c.RetentionHandler = retention.NewRetentionHandler(c.RetentionUsecase)
+	c.ImpersonationHandler = impersonation.NewImpersonationHandler(c.ImpersonationUsecase)
+	if c.AuthUsecase != nil {
+		c.AuthHandler = authController.NewAuthHandler(c.AuthUsecase)
+		c.TwoFactorHandler = authController.NewTwoFactorHandler(c.TwoFactorUsecase)
+		c.PasswordResetHandler = authController.NewPasswordResetHandler(c.PasswordResetUsecase)
+	}
+	if c.OIDCUsecase != nil {
+		c.OIDCHandler = authController.NewOIDCHandler(c.OIDCUsecase)
+	}
+	c.APIKeyHandler = authController.NewAPIKeyHandler(c.APIKeyUsecase)
+	if c.SandboxKeyUsecase != nil {
+		c.SandboxKeyHandler = authController.NewAPIKeyHandler(c.SandboxKeyUsecase)
+		c.SandboxItemHandler = itemController.NewItemHandler(c.SandboxItemUsecase)
+	}
+	c.SCIMHandler = scim.NewSCIMHandler(c.UserUsecase, SCIMBasePath)
+	c.UserHandler = users.NewUserHandler(c.UserUsecase)
+	c.OrganizationHandler = organizations.NewOrganizationHandler(c.OrganizationUsecase)
+	c.TenantHandler = tenants.NewTenantHandler(c.TenantUsecase)
+	c.ReferenceHandler = reference.NewReferenceHandler(c.ReferenceUsecase)
+	c.ReportHandler = reports.NewReportHandler(c.PortfolioUsecase, c.ScenarioUsecase)
+	c.GrafanaHandler = grafana.NewGrafanaHandler(c.DashboardUsecase)
+	c.DocsHandler = docs.NewDocsHandler()
+	c.DeprecationHandler = deprecations.NewDeprecationHandler(c.DeprecationUsecase)
+	c.ExportHandler = exports.NewExportHandler(c.ExportUsecase)
+	c.AttachmentHandler = attachments.NewAttachmentHandler(c.AttachmentUsecase, int64(config.AttachmentMaxSizeMB)<<20)
+	c.ImageHandler = images.NewImageHandler(c.ImageUsecase, int64(config.ImageMaxSizeMB)<<20)
+	c.QuarantineHandler = quarantine.NewQuarantineHandler(c.QuarantineUsecase)
+	c.ReplicationHandler = replicationController.NewReplicationHandler(c.ChangeStream, c.ReplicaUsecase)
+	c.EventConsumerHandler = replicationController.NewEventConsumerHandler(c.EventConsumerUsecase)
+	c.ReadOnly = appMiddleware.NewReadOnlyMode(config.ReadOnly, config.ReadOnlyReason)
+	c.SystemHandler = system.NewSystemHandler(func() (any, error) { return config.Reload() }, c.ReadOnly, c.SLOUsecase, c.ReplicaUsecase, serverLimits(), capabilities(imageDelivery), c.readinessChecks())
+	c.GraphQLHandler = graphqlController.NewGraphQLHandler(c.ItemUsecase, c.ReadOnly)
+
+	return c, nil
+}
+
+// DB のスキーマがこのバイナリと互換性があるかを確認し、任意の列のうちないものを SchemaColumns に記録する
+// DB を使わない環境では何もしない
+func (c *Container) CheckSchema(ctx context.Context) error {
+	if c.sqlHandler == nil {
+		return nil
+	}
+	if _, err := databaseInfra.CheckSchemaCompatibility(ctx, c.sqlHandler); err != nil {
+		return err
+	}
+	missing, err := databaseInfra.DetectMissingColumns(ctx, c.sqlHandler, database.OptionalColumns)
+	if err != nil {
+		return err
+	}
+	c.SchemaColumns.SetMissing(missing)
+	return nil
+}
+
+// /readyz で確認する依存先。使っていないもの（インメモリの DB・キャッシュなど）は含めない
+func (c *Container) readinessChecks() []system.ReadinessCheck {
+	var checks []system.ReadinessCheck
+	if c.sqlHandler != nil {
+		checks = append(checks, system.ReadinessCheck{Name: "database", Check: func(ctx context.Context) error {
+			return database.Ping(ctx, c.sqlHandler)
+		}})
+	}
+	if redis, ok := c.ReplayCache.(*replaycache.Redis); ok {
+		checks = append(checks, system.ReadinessCheck{Name: "cache", Check: redis.Ping})
+	}
+	if c.SearchIndex != nil {
+		checks = append(checks, system.ReadinessCheck{Name: "search", Check: c.SearchIndex.Ping})
+	}
+	return checks
+}
+
+// MySQL への接続。最初に必要になった時点で接続し、以降は使い回す
+func (c *Container) SqlHandler() database.SqlHandler {
+	if c.sqlHandler == nil {
+		c.sqlHandler = databaseInfra.NewSqlHandler()
+		// クエリの所要時間をリクエストIDと一緒にログに残す
+		c.sqlHandler = database.NewLoggingSqlHandler(c.sqlHandler, config.DBSlowQueryThreshold)
+		// 障害注入でリクエストごとに接続断を起こせるようにする
+		if config.ChaosAllowed() && config.ChaosRules != "" {
+			c.sqlHandler = database.NewChaosSqlHandler(c.sqlHandler)
+		}
+		c.addCloser(c.sqlHandler.Close)
+	}
+	return c.sqlHandler
+}
+
+// スタンバイとして送信元の変更ストリームをアイテムに適用する
+// 送信元の ID のまま書き込むため、アイテムのリポジトリが usecase.ItemReplicaWriter を実装している必要がある
+func (c *Container) replicaFromConfig() (usecase.ReplicaUsecase, error) {
+	if config.ReplicationToken == "" {
+		return nil, errors.New("REPLICATION_TOKEN is required when REPLICATION_SOURCE_URL is set")
+	}
+	items, ok := c.ItemRepository.(usecase.ItemReplicaWriter)
+	if !ok {
+		return nil, fmt.Errorf("item repository %T cannot be used as a replica", c.ItemRepository)
+	}
+	source := replicationInfra.NewHTTPSource(config.ReplicationSourceURL, config.ReplicationToken)
+	return usecase.NewReplicaUsecase(source, source.URL, items, c.Checkpoints, c.Transactor, config.ReplicationBatchSize, c.Clock), nil
+}
+
+// 設定の再読み込みを反映するため、呼び出しごとに現在の設定からポリシーを組み立てる
+type configReasonPolicy struct{}
+
+func (configReasonPolicy) PolicyFor(ctx context.Context) entity.ReasonPolicy {
+	current := config.Current()
+	return usecase.StaticReasonPolicy{
+		Policy: entity.ReasonPolicy{
+			RequireOnDelete:    current.ReasonPolicyDelete,
+			HighValueThreshold: current.ReasonPolicyHighValue,
+		},
+		Orgs: current.ReasonPolicyOrgs,
+	}.PolicyFor(ctx)
+}
+
+// SMTP サーバーが設定されていない場合は送信せずログに出力する
+func mailerFromConfig() usecase.Mailer {
+	if config.SMTPAddr == "" {
+		return mailInfra.LogMailer{}
+	}
+	return &mailInfra.SMTPMailer{
+		Addr:     config.SMTPAddr,
+		Username: config.SMTPUsername,
+		Password: config.SMTPPassword,
+		From:     config.MailFrom,
+	}
+}
+
+// 設定したチャンネルだけを使う。メールは SMTP サーバーがなくてもログに出力するため常に使える
+func notificationChannelsFromConfig(mailer usecase.Mailer) (usecase.NotificationChannels, error) {
+	channels := usecase.NotificationChannels{
+		entity.ChannelEmail: &notify.EmailSender{Mailer: mailer},
+	}
+	if config.SlackWebhookURL != "" {
+		channels[entity.ChannelSlack] = notify.NewSlackSender(config.SlackWebhookURL)
+	}
+	if config.LINEChannelAccessToken != "" {
+		channels[entity.ChannelLINE] = notify.NewLINESender(config.LINEChannelAccessToken)
+	}
+	if config.FCMCredentialsFile != "" {
+		credentials, err := os.ReadFile(config.FCMCredentialsFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read FCM_CREDENTIALS_FILE: %w", err)
+		}
+		sender, err := notify.NewFCMSender(credentials)
+		if err != nil {
+			return nil, fmt.Errorf("invalid FCM_CREDENTIALS_FILE: %w", err)
+		}
+		channels[entity.ChannelPush] = sender
+	}
+	return channels, nil
+}
+
+// クライアント ID を設定したプロバイダーだけを使う
+func identityProvidersFromConfig() map[string]usecase.IdentityProvider {
+	providers := map[string]usecase.IdentityProvider{}
+	if config.OIDCGoogleClientID != "" {
+		providers[entity.IdentityProviderGoogle] = oidc.NewGoogle(oidc.Config{
+			ClientID:     config.OIDCGoogleClientID,
+			ClientSecret: config.OIDCGoogleClientSecret,
+			RedirectURL:  config.OIDCRedirectURL,
+		})
+	}
+	if config.OIDCGitHubClientID != "" {
+		providers[entity.IdentityProviderGitHub] = oidc.NewGitHub(oidc.Config{
+			ClientID:     config.OIDCGitHubClientID,
+			ClientSecret: config.OIDCGitHubClientSecret,
+			RedirectURL:  config.OIDCRedirectURL,
+		})
+	}
+	return providers
+}
+
+func blobStoreFromConfig() (usecase.BlobStore, error) {
+	switch config.BlobStore {
+	case "s3":
+		if config.S3Bucket == "" {
+			return nil, errors.New("S3_BUCKET is required when BLOB_STORE=s3")
+		}
+		return blobstore.NewS3(config.S3Endpoint, config.S3Region, config.S3Bucket, config.S3AccessKeyID, config.S3SecretAccessKey), nil
+	case "gcs":
+		if config.GCSBucket == "" {
+			return nil, errors.New("GCS_BUCKET is required when BLOB_STORE=gcs")
+		}
+		return blobstore.NewGCS(config.GCSBucket, config.GCSHMACKeyID, config.GCSHMACSecret), nil
+	default:
+		return &blobstore.LocalDir{Root: config.BlobDir}, nil
+	}
+}
+
+// アップロードされたファイルのウイルス検査。SCANNER が空の場合は nil で、検査しない
+func scannerFromConfig() usecase.Scanner {
+	switch config.Scanner {
+	case "clamav":
+		return scanner.NewClamAV(config.ClamAVAddr, config.ScanTimeout)
+	default:
+		return nil
+	}
+}
+
+// 署名付きリクエストの再送を見分けるキャッシュ。複数のインスタンスで動かす場合は redis にする
+func replayCacheFromConfig(clk clock.Clock) usecase.ReplayCache {
+	switch config.ReplayCache {
+	case "redis":
+		return replaycache.NewRedis(config.RedisAddr, config.RedisPassword, replayCacheTimeout)
+	default:
+		return replaycache.NewMemory(clk)
+	}
+}
+
+// 画像を直接取得する URL と配信方法。CDN を優先し、なければファイルストアの事前署名 URL を使う
+// どちらも使わない場合は nil で、画像は API から配信する
+func imageURLsFromConfig(store usecase.BlobStore) (usecase.ImageURLs, string) {
+	if config.CDNBaseURL != "" {
+		return usecase.NewCDNImageURLs(cdnurl.NewSigner(config.CDNBaseURL, config.CDNSigningKey, config.CDNURLTTL)), entity.ImageURLsCDN
+	}
+	if signer, ok := store.(usecase.BlobURLSigner); ok && config.ImagePresignTTL > 0 {
+		return usecase.NewPresignedImageURLs(signer, config.ImagePresignTTL), entity.ImageURLsPresigned
+	}
+	return nil, entity.ImageURLsAPI
+}
+
+// GET /meta/capabilities で公開する機能の有無。読み取り専用モードは返すときの状態をハンドラーが入れる
+func capabilities(imageURLs string) entity.Capabilities {
+	search := entity.SearchBackendDatabase
+	if config.MeilisearchURL != "" {
+		search = entity.SearchBackendMeilisearch
+	}
+	thumbnails := make([]string, len(entity.ThumbnailSizes))
+	for i, size := range entity.ThumbnailSizes {
+		thumbnails[i] = size.Name
+	}
+	loginProviders := []string{}
+	if config.JWTSecret != "" {
+		for name := range identityProvidersFromConfig() {
+			loginProviders = append(loginProviders, name)
+		}
+		sort.Strings(loginProviders)
+	}
+
+	return entity.Capabilities{
+		GraphQL:        true,
+		Webhooks:       true,
+		SCIM:           config.SCIMToken != "",
+		Login:          config.JWTSecret != "",
+		Register:       config.JWTSecret != "" && config.RegistrationEnabled,
+		LoginProviders: loginProviders,
+		Search:         search,
+		Currencies:     []string{eventschema.DefaultCurrency},
+		VirusScan:      config.Scanner != "",
+		ImageURLs:      imageURLs,
+		Thumbnails:     thumbnails,
+		Sandbox:        config.SandboxEnabled,
+	}
+}
+
+// GET /meta/limits で公開する上限。ハンドラー・ユースケースが実際に使う値から組み立てる
+// リクエストボディ全体の上限は設けていないため null のまま返す。レート制限は設定している場合のみ返す
+func serverLimits() entity.ServerLimits {
+	imageTypes := make([]string, 0, len(entity.ImageExtensions))
+	for contentType := range entity.ImageExtensions {
+		imageTypes = append(imageTypes, contentType)
+	}
+	sort.Strings(imageTypes)
+
+	limits := entity.ServerLimits{
+		Bulk: entity.BulkLimits{
+			MaxCreateItems: usecase.MaxBulkCreateItems,
+			MaxItemIDs:     usecase.MaxBulkItemIDs,
+		},
+		Import: entity.ImportLimits{
+			MaxRows:      usecase.MaxImportRows,
+			MaxLineBytes: itemController.MaxNDJSONLine,
+		},
+		Pagination: entity.PageLimits{
+			DefaultSize: listquery.DefaultPageSize,
+			MaxSize:     listquery.MaxPageSize,
+		},
+		Search: entity.SearchLimits{
+			DefaultLimit:     entity.DefaultSearchLimit,
+			MaxLimit:         entity.MaxSearchLimit,
+			MaxKeywordLength: entity.MaxSearchKeywordLength,
+		},
+		Attachments: entity.UploadLimits{MaxSizeBytes: int64(config.AttachmentMaxSizeMB) << 20},
+		Receipts:    entity.UploadLimits{MaxSizeBytes: int64(config.AttachmentMaxSizeMB) << 20, ContentTypes: entity.ReceiptContentTypes},
+		Images:      entity.UploadLimits{MaxSizeBytes: int64(config.ImageMaxSizeMB) << 20, MaxPerItem: config.ImageMaxPerItem, ContentTypes: imageTypes},
+	}
+	if config.RateLimitRequests > 0 {
+		limits.RateLimit = &entity.RateLimit{
+			Requests:      config.RateLimitRequests,
+			WindowSeconds: int(config.RateLimitWindow / time.Second),
+		}
+	}
+	return limits
+}
+
+// 確保したリソースを登録と逆順に解放する
+func (c *Container) Close() error {
+	var errs []error
+	for i := len(c.closers) - 1; i >= 0; i-- {
+		if err := c.closers[i](); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	c.closers = nil
+	return errors.Join(errs...)
+}
+
+func (c *Container) addCloser(closer func() error) {
+	c.closers = append(c.closers, closer)
+}
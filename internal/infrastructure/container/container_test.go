@@ -0,0 +1,64 @@
+package container
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"Aicon-assignment/internal/domain/entity"
+	"Aicon-assignment/internal/infrastructure/config"
+)
+
+func TestProvidersFor(t *testing.T) {
+	assert.Equal(t, "prod", ProvidersFor("").Name)
+	assert.Equal(t, "prod", ProvidersFor("production").Name)
+	assert.Equal(t, "dev-in-memory", ProvidersFor("memory").Name)
+	assert.Equal(t, "test", ProvidersFor("test").Name)
+}
+
+func TestBuild_InMemory(t *testing.T) {
+	c, err := Build("memory", DevInMemoryProviders)
+	require.NoError(t, err)
+	defer c.Close()
+
+	assert.NotNil(t, c.ItemHandler)
+	assert.NotNil(t, c.SystemHandler)
+	assert.False(t, c.ReadOnly.Status().Enabled)
+
+	items, err := c.ItemUsecase.GetAllItems(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, items, 5)
+}
+
+func TestServerLimits(t *testing.T) {
+	limits := serverLimits()
+
+	assert.Nil(t, limits.MaxBodyBytes)
+	assert.Nil(t, limits.RateLimit)
+	assert.Equal(t, 100, limits.Pagination.MaxSize)
+	assert.Equal(t, []string{"image/gif", "image/jpeg", "image/png", "image/webp"}, limits.Images.ContentTypes)
+	assert.Positive(t, limits.Images.MaxSizeBytes)
+
+	t.Run("正常系: レート制限を設定している場合は公開する", func(t *testing.T) {
+		requests, window := config.RateLimitRequests, config.RateLimitWindow
+		t.Cleanup(func() { config.RateLimitRequests, config.RateLimitWindow = requests, window })
+		config.RateLimitRequests, config.RateLimitWindow = 120, time.Minute
+
+		assert.Equal(t, &entity.RateLimit{Requests: 120, WindowSeconds: 60}, serverLimits().RateLimit)
+	})
+}
+
+func TestCapabilities(t *testing.T) {
+	capabilities := capabilities(entity.ImageURLsAPI)
+
+	assert.True(t, capabilities.Webhooks)
+	assert.True(t, capabilities.GraphQL)
+	assert.Equal(t, entity.SearchBackendDatabase, capabilities.Search)
+	assert.Empty(t, capabilities.LoginProviders)
+	assert.Equal(t, []string{"JPY"}, capabilities.Currencies)
+	assert.Equal(t, []string{"small", "medium"}, capabilities.Thumbnails)
+	assert.False(t, capabilities.Sandbox)
+}
@@ -0,0 +1,71 @@
+package container
+
+import (
+	"time"
+
+	"Aicon-assignment/internal/domain/entity"
+)
+
+// sql/init.sql と同じサンプルデータ。開発用の管理者（ID 1）のアイテムにする
+func sampleItems(now time.Time) []*entity.Item {
+	owner := int64(1)
+	rows := []struct {
+		name, category, brand string
+		price                 int
+		date                  string
+	}{
+		{"ロレックス デイトナ", "時計", "ROLEX", 1500000, "2023-01-15"},
+		{"エルメス バーキン", "バッグ", "HERMÈS", 2000000, "2023-02-20"},
+		{"ティファニー ネックレス", "ジュエリー", "Tiffany & Co.", 300000, "2023-03-10"},
+		{"ルブタン パンプス", "靴", "Christian Louboutin", 150000, "2023-04-05"},
+		{"アップルウォッチ", "その他", "Apple", 50000, "2023-05-12"},
+	}
+
+	items := make([]*entity.Item, 0, len(rows))
+	for _, row := range rows {
+		item, err := entity.NewItemAt(now, row.name, row.category, row.brand, row.price, row.date)
+		if err != nil {
+			panic(err)
+		}
+		item.OwnerID = &owner
+		items = append(items, item)
+	}
+	return items
+}
+
+// 開発用のユーザー（1: 管理者、2: スタッフ、3: 鑑定士）
+func sampleUsers(now time.Time) []*entity.User {
+	rows := []struct {
+		userName, displayName, email, role string
+	}{
+		{"admin", "管理者", "admin@example.com", entity.UserRoleAdmin},
+		{"staff", "スタッフ", "staff@example.com", entity.UserRoleMember},
+		{"appraiser", "鑑定士", "appraiser@example.com", entity.UserRoleAppraiser},
+	}
+
+	users := make([]*entity.User, 0, len(rows))
+	for _, row := range rows {
+		user, err := entity.NewUserAt(now, row.userName, row.displayName, row.email, "", true)
+		if err != nil {
+			panic(err)
+		}
+		user.Role = row.role
+		users = append(users, user)
+	}
+	return users
+}
+
+// sql/init.sql と同じ初期のカテゴリー
+func sampleCategories(now time.Time) []*entity.ReferenceRecord {
+	records := make([]*entity.ReferenceRecord, 0, len(entity.DefaultCategories))
+	for i, category := range entity.DefaultCategories {
+		records = append(records, &entity.ReferenceRecord{
+			Type:       entity.ReferenceCategories,
+			Key:        category,
+			Attributes: map[string]any{"display_order": int64(i + 1)},
+			CreatedAt:  now,
+			UpdatedAt:  now,
+		})
+	}
+	return records
+}
@@ -0,0 +1,113 @@
+package databaseInfra
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"Aicon-assignment/internal/interfaces/database"
+)
+
+// このバイナリが前提とするスキーマの版。sql/init.sql の schema_version の初期値と合わせる
+// スキーマを変更するマイグレーションでは schema_version.version を上げ、
+// 古いバイナリが動かなくなる変更（列の削除など）では min_compatible も上げる
+// 2: 版のチェックを入れてから追加したテーブル・列（API キー・添付ファイル・画像・リフレッシュトークン・参照データなど）
+const SchemaVersion = 2
+
+var (
+	// マイグレーションがまだ適用されていない
+	ErrSchemaTooOld = errors.New("database schema is older than this binary requires")
+	// このバイナリでは扱えない変更が適用されている
+	ErrSchemaTooNew = errors.New("database schema is newer than this binary supports")
+)
+
+// DB に記録されているスキーマの版
+type SchemaVersionInfo struct {
+	Version       int // 適用済みのスキーマの版
+	MinCompatible int // このスキーマで動かせるバイナリの最も古い版
+}
+
+func ReadSchemaVersion(ctx context.Context, h database.SqlHandler) (SchemaVersionInfo, error) {
+	var info SchemaVersionInfo
+	err := h.QueryRow(ctx, `SELECT version, min_compatible FROM schema_version WHERE id = 1`).
+		Scan(&info.Version, &info.MinCompatible)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return info, fmt.Errorf("%w: schema_version has no row", ErrSchemaTooOld)
+		}
+		return info, fmt.Errorf("failed to read schema_version: %w", err)
+	}
+	return info, nil
+}
+
+// binary の版のバイナリがこのスキーマで動くかを確認する
+// DB の方が新しくても、min_compatible 以上であれば動かせる（ブルーグリーンデプロイ中の旧バイナリなど）
+func (info SchemaVersionInfo) CompatibleWith(binary int) error {
+	switch {
+	case info.Version < binary:
+		return fmt.Errorf("%w (database %d, binary %d)", ErrSchemaTooOld, info.Version, binary)
+	case binary < info.MinCompatible:
+		return fmt.Errorf("%w (database %d requires binary %d or later, binary %d)", ErrSchemaTooNew, info.Version, info.MinCompatible, binary)
+	}
+	return nil
+}
+
+// DB のスキーマがこのバイナリと互換性があるかを確認する
+func CheckSchemaCompatibility(ctx context.Context, h database.SqlHandler) (SchemaVersionInfo, error) {
+	info, err := ReadSchemaVersion(ctx, h)
+	if err != nil {
+		return info, err
+	}
+	return info, info.CompatibleWith(SchemaVersion)
+}
+
+// columns のうち DB にない列を返す
+// 任意の列は版を上げずに追加するため、schema_version ではなく information_schema で確かめる
+func DetectMissingColumns(ctx context.Context, h database.SqlHandler, columns []database.OptionalColumn) ([]database.OptionalColumn, error) {
+	if len(columns) == 0 {
+		return nil, nil
+	}
+	tables := make([]any, 0, len(columns))
+	seen := make(map[string]bool)
+	for _, column := range columns {
+		if !seen[column.Table] {
+			seen[column.Table] = true
+			tables = append(tables, column.Table)
+		}
+	}
+
+	query := `SELECT table_name, column_name FROM information_schema.columns WHERE table_schema = DATABASE() AND table_name IN (?` +
+		strings.Repeat(", ?", len(tables)-1) + `)`
+	rows, err := h.Query(ctx, query, tables...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read columns: %w", err)
+	}
+	defer rows.Close()
+
+	existing := make(map[string]bool)
+	for rows.Next() {
+		var table, column string
+		if err := rows.Scan(&table, &column); err != nil {
+			return nil, fmt.Errorf("failed to read columns: %w", err)
+		}
+		existing[strings.ToLower(table)+"."+strings.ToLower(column)] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read columns: %w", err)
+	}
+
+	var missing []database.OptionalColumn
+	for _, column := range columns {
+		if !existing[column.String()] {
+			missing = append(missing, column)
+		}
+	}
+	return missing, nil
+}
+
+// 版が合わないことによるエラーか。DB に接続できない場合などは含まない
+func IsSchemaIncompatible(err error) bool {
+	return errors.Is(err, ErrSchemaTooOld) || errors.Is(err, ErrSchemaTooNew)
+}
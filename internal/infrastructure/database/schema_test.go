@@ -0,0 +1,118 @@
+package databaseInfra
+
+import (
+	"context"
+	"os"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"Aicon-assignment/internal/interfaces/database"
+)
+
+func TestSchemaVersionInfo_CompatibleWith(t *testing.T) {
+	tests := []struct {
+		name    string
+		info    SchemaVersionInfo
+		binary  int
+		wantErr error
+	}{
+		{name: "正常系: 同じ版", info: SchemaVersionInfo{Version: 2, MinCompatible: 1}, binary: 2},
+		{name: "正常系: DB の方が新しいが互換性あり", info: SchemaVersionInfo{Version: 3, MinCompatible: 2}, binary: 2},
+		{name: "異常系: マイグレーション未適用", info: SchemaVersionInfo{Version: 1, MinCompatible: 1}, binary: 2, wantErr: ErrSchemaTooOld},
+		{name: "異常系: 互換性のない新しいスキーマ", info: SchemaVersionInfo{Version: 4, MinCompatible: 3}, binary: 2, wantErr: ErrSchemaTooNew},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.info.CompatibleWith(tt.binary)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.True(t, IsSchemaIncompatible(err))
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+// init.sql で作成する版と SchemaVersion がずれていないか
+func TestSchemaVersion_MatchesInitSQL(t *testing.T) {
+	definition, err := os.ReadFile("../../../sql/init.sql")
+	require.NoError(t, err)
+
+	m := regexp.MustCompile(`INSERT INTO schema_version \(id, version, min_compatible\) VALUES \(1, (\d+), \d+\)`).FindSubmatch(definition)
+	require.NotNil(t, m, "init.sql must insert the initial schema_version row")
+	version, err := strconv.Atoi(string(m[1]))
+	require.NoError(t, err)
+	assert.Equal(t, SchemaVersion, version)
+}
+
+// information_schema.columns の代わりに決まった列を返す
+type columnsHandler struct {
+	database.SqlHandler
+	columns [][2]string
+}
+
+func (h *columnsHandler) Query(ctx context.Context, statement string, args ...interface{}) (database.Rows, error) {
+	return &columnRows{columns: h.columns, index: -1}, nil
+}
+
+type columnRows struct {
+	columns [][2]string
+	index   int
+}
+
+func (r *columnRows) Next() bool {
+	r.index++
+	return r.index < len(r.columns)
+}
+
+func (r *columnRows) Scan(dest ...interface{}) error {
+	*dest[0].(*string) = r.columns[r.index][0]
+	*dest[1].(*string) = r.columns[r.index][1]
+	return nil
+}
+
+func (r *columnRows) Close() error { return nil }
+func (r *columnRows) Err() error   { return nil }
+
+func TestDetectMissingColumns(t *testing.T) {
+	t.Run("正常系: すべての列がある", func(t *testing.T) {
+		h := &columnsHandler{columns: [][2]string{
+			{"webhooks", "id"}, {"webhooks", "watch_fields"}, {"webhooks", "owner_id"}, {"WEBHOOK_DELIVERIES", "SCHEMA_VERSION"},
+		}}
+
+		missing, err := DetectMissingColumns(context.Background(), h, database.OptionalColumns)
+		require.NoError(t, err)
+		assert.Empty(t, missing)
+	})
+
+	t.Run("正常系: マイグレーションを適用していない列を返す", func(t *testing.T) {
+		h := &columnsHandler{columns: [][2]string{{"webhooks", "id"}, {"webhooks", "owner_id"}, {"webhook_deliveries", "schema_version"}}}
+
+		missing, err := DetectMissingColumns(context.Background(), h, database.OptionalColumns)
+		require.NoError(t, err)
+		assert.Equal(t, []database.OptionalColumn{database.ColumnWebhookWatchFields}, missing)
+	})
+}
+
+func TestSchemaColumns(t *testing.T) {
+	t.Run("正常系: 確かめる前と nil はすべての列があるものとして扱う", func(t *testing.T) {
+		var unchecked *database.SchemaColumns
+		assert.True(t, unchecked.Has(database.ColumnWebhookWatchFields))
+		assert.True(t, database.NewSchemaColumns().Has(database.ColumnWebhookWatchFields))
+	})
+
+	t.Run("正常系: 記録したない列", func(t *testing.T) {
+		columns := database.NewSchemaColumns()
+		columns.SetMissing([]database.OptionalColumn{database.ColumnWebhookWatchFields})
+
+		assert.False(t, columns.Has(database.ColumnWebhookWatchFields))
+		assert.True(t, columns.Has(database.ColumnDeliverySchemaVersion))
+		assert.Equal(t, "webhooks.watch_fields", database.JoinColumns(columns.Missing()))
+	})
+}
@@ -44,8 +44,51 @@ func NewSqlHandler() database.SqlHandler {
 	return &MySqlHandler{Conn: conn}
 }
 
+// *sql.DB と *sql.Tx に共通するクエリの実行方法
+type queryer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+type txKey struct{}
+
+// トランザクション中であればそのトランザクションで、そうでなければ接続プールで実行する
+func (h *MySqlHandler) conn(ctx context.Context) queryer {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return h.Conn
+}
+
+func (h *MySqlHandler) Transaction(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	// 入れ子の場合は外側のトランザクションに参加する
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := h.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err = fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
 func (h *MySqlHandler) Execute(ctx context.Context, statement string, args ...interface{}) (database.Result, error) {
-	result, err := h.Conn.ExecContext(ctx, statement, args...)
+	result, err := h.conn(ctx).ExecContext(ctx, statement, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -53,7 +96,7 @@ func (h *MySqlHandler) Execute(ctx context.Context, statement string, args ...in
 }
 
 func (h *MySqlHandler) Query(ctx context.Context, statement string, args ...interface{}) (database.Rows, error) {
-	rows, err := h.Conn.QueryContext(ctx, statement, args...)
+	rows, err := h.conn(ctx).QueryContext(ctx, statement, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -61,7 +104,7 @@ func (h *MySqlHandler) Query(ctx context.Context, statement string, args ...inte
 }
 
 func (h *MySqlHandler) QueryRow(ctx context.Context, statement string, args ...interface{}) database.Row {
-	row := h.Conn.QueryRowContext(ctx, statement, args...)
+	row := h.conn(ctx).QueryRowContext(ctx, statement, args...)
 	return &mysqlRow{row: row}
 }
 
@@ -0,0 +1,105 @@
+// Package logfile はサイズで切り替えるログファイルを提供する。
+package logfile
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// 一定のサイズを超えたら path.1, path.2, ... に退避して新しいファイルに書き込む
+// 退避するファイルは MaxBackups 個までで、それより古いものは削除する
+type RotatingFile struct {
+	Path       string
+	MaxBytes   int64 // 0 の場合は切り替えない
+	MaxBackups int
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+func Open(path string, maxBytes int64, maxBackups int) (*RotatingFile, error) {
+	f := &RotatingFile{Path: path, MaxBytes: maxBytes, MaxBackups: maxBackups}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		// 切り替え時に開き直せなかった場合
+		if err := f.open(); err != nil {
+			return 0, err
+		}
+	}
+	if f.MaxBytes > 0 && f.size > 0 && f.size+int64(len(p)) > f.MaxBytes {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+func (f *RotatingFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
+
+func (f *RotatingFile) open() error {
+	file, err := os.OpenFile(f.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to stat log file: %w", err)
+	}
+	f.file = file
+	f.size = info.Size()
+	return nil
+}
+
+func (f *RotatingFile) rotate() error {
+	err := f.file.Close()
+	f.file = nil
+	if err != nil {
+		return fmt.Errorf("failed to close log file: %w", err)
+	}
+
+	if f.MaxBackups <= 0 {
+		os.Remove(f.Path)
+	} else {
+		os.Remove(f.backupPath(f.MaxBackups))
+		for i := f.MaxBackups - 1; i >= 1; i-- {
+			os.Rename(f.backupPath(i), f.backupPath(i+1))
+		}
+		// 退避できない場合は同じファイルに追記を続け、次の書き込みで再び試す
+		os.Rename(f.Path, f.backupPath(1))
+	}
+
+	return f.open()
+}
+
+func (f *RotatingFile) backupPath(n int) string {
+	return fmt.Sprintf("%s.%d", f.Path, n)
+}
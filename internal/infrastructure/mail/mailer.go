@@ -0,0 +1,101 @@
+// Package mail は usecase.Mailer の実装を提供する。
+package mail
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+
+	"Aicon-assignment/internal/pkg/reqctx"
+	"Aicon-assignment/internal/usecase"
+)
+
+// メールを送らずにログへ出力する（開発・テスト用）
+type LogMailer struct{}
+
+func (LogMailer) Send(ctx context.Context, mail usecase.Mail) error {
+	reqctx.Logger(ctx).Info("mail not sent (no SMTP server configured)",
+		"to", mail.To, "subject", mail.Subject, "body", mail.Body)
+	return nil
+}
+
+// SMTP サーバー経由で送信する
+type SMTPMailer struct {
+	Addr     string // host:port
+	Username string // 空の場合は認証しない
+	Password string
+	From     string
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, mail usecase.Mail) error {
+	var auth smtp.Auth
+	if m.Username != "" {
+		host, _, err := net.SplitHostPort(m.Addr)
+		if err != nil {
+			return fmt.Errorf("invalid SMTP address %q: %w", m.Addr, err)
+		}
+		auth = smtp.PlainAuth("", m.Username, m.Password, host)
+	}
+
+	if err := smtp.SendMail(m.Addr, auth, m.From, []string{mail.To}, message(m.From, mail)); err != nil {
+		return fmt.Errorf("failed to send mail: %w", err)
+	}
+	return nil
+}
+
+// SMTP サーバーに接続し、設定されていれば認証まで行う。メールは送らない
+func (m *SMTPMailer) Verify(ctx context.Context) error {
+	host, _, err := net.SplitHostPort(m.Addr)
+	if err != nil {
+		return fmt.Errorf("invalid SMTP address %q: %w", m.Addr, err)
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", m.Addr)
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if err := client.Hello("localhost"); err != nil {
+		return err
+	}
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return fmt.Errorf("STARTTLS failed: %w", err)
+		}
+	}
+	if m.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", m.Username, m.Password, host)); err != nil {
+			return fmt.Errorf("authentication failed: %w", err)
+		}
+	}
+	return client.Quit()
+}
+
+// 件名は日本語を含むため MIME エンコードする
+func message(from string, mail usecase.Mail) []byte {
+	var b strings.Builder
+	b.WriteString("From: " + from + "\r\n")
+	b.WriteString("To: " + mail.To + "\r\n")
+	b.WriteString("Subject: " + mime.BEncoding.Encode("UTF-8", mail.Subject) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(mail.Body, "\n", "\r\n"))
+	return []byte(b.String())
+}
@@ -0,0 +1,17 @@
+package notify
+
+import (
+	"context"
+
+	"Aicon-assignment/internal/domain/entity"
+	"Aicon-assignment/internal/usecase"
+)
+
+// メールで送る。件名は通知のタイトル
+type EmailSender struct {
+	Mailer usecase.Mailer
+}
+
+func (s *EmailSender) Send(ctx context.Context, to string, notification entity.Notification) error {
+	return s.Mailer.Send(ctx, usecase.Mail{To: to, Subject: notification.Title, Body: notification.Body})
+}
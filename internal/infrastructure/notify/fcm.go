@@ -0,0 +1,187 @@
+package notify
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"Aicon-assignment/internal/domain/entity"
+)
+
+// FCM の HTTP v1 API
+const (
+	FCMBaseURL = "https://fcm.googleapis.com"
+	fcmScope   = "https://www.googleapis.com/auth/firebase.messaging"
+)
+
+// 期限の直前のアクセストークンは使わず、取り直す
+const tokenRefreshMargin = time.Minute
+
+// Firebase Cloud Messaging（HTTP v1 API）でモバイルアプリにプッシュ通知を送る。宛先は端末の登録トークン
+// アクセストークンはサービスアカウントの鍵で署名した JWT と引き換えに取得し、期限まで使い回す
+type FCMSender struct {
+	BaseURL     string
+	Client      *http.Client
+	credentials serviceAccount
+	key         *rsa.PrivateKey
+	now         func() time.Time
+
+	mu          sync.Mutex
+	accessToken string
+	expiresAt   time.Time
+}
+
+// Firebase のコンソールで作成するサービスアカウントの鍵（JSON）の必要な項目
+type serviceAccount struct {
+	ProjectID   string `json:"project_id"`
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	TokenURI    string `json:"token_uri"`
+}
+
+func NewFCMSender(credentialsJSON []byte) (*FCMSender, error) {
+	var credentials serviceAccount
+	if err := json.Unmarshal(credentialsJSON, &credentials); err != nil {
+		return nil, fmt.Errorf("invalid service account key: %w", err)
+	}
+	if credentials.ProjectID == "" || credentials.ClientEmail == "" || credentials.TokenURI == "" {
+		return nil, errors.New("invalid service account key: project_id, client_email and token_uri are required")
+	}
+	key, err := parseRSAPrivateKey(credentials.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid service account key: %w", err)
+	}
+
+	return &FCMSender{
+		BaseURL:     FCMBaseURL,
+		Client:      &http.Client{Timeout: sendTimeout},
+		credentials: credentials,
+		key:         key,
+		now:         time.Now,
+	}, nil
+}
+
+func parseRSAPrivateKey(value string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(value))
+	if block == nil {
+		return nil, errors.New("private_key is not PEM encoded")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private_key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("private_key must be an RSA key")
+	}
+	return key, nil
+}
+
+type fcmRequest struct {
+	Message fcmMessage `json:"message"`
+}
+
+type fcmMessage struct {
+	Token        string          `json:"token"`
+	Notification fcmNotification `json:"notification"`
+}
+
+type fcmNotification struct {
+	Title string `json:"title"`
+	Body  string `json:"body,omitempty"`
+}
+
+func (s *FCMSender) Send(ctx context.Context, to string, notification entity.Notification) error {
+	token, err := s.token(ctx)
+	if err != nil {
+		return err
+	}
+	endpoint := strings.TrimSuffix(s.BaseURL, "/") + "/v1/projects/" + url.PathEscape(s.credentials.ProjectID) + "/messages:send"
+	return postJSON(ctx, s.Client, endpoint, token, fcmRequest{Message: fcmMessage{
+		Token:        to,
+		Notification: fcmNotification{Title: notification.Title, Body: notification.Body},
+	}})
+}
+
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// 有効なアクセストークンを返す。期限が近い場合は取り直す
+func (s *FCMSender) token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if s.accessToken != "" && now.Add(tokenRefreshMargin).Before(s.expiresAt) {
+		return s.accessToken, nil
+	}
+
+	assertion, err := s.assertion(now)
+	if err != nil {
+		return "", err
+	}
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.credentials.TokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("failed to build token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to get FCM access token: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to get FCM access token: unexpected status code %d", resp.StatusCode)
+	}
+
+	var body tokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil || body.AccessToken == "" {
+		return "", errors.New("failed to get FCM access token: invalid token response")
+	}
+	s.accessToken = body.AccessToken
+	s.expiresAt = now.Add(time.Duration(body.ExpiresIn) * time.Second)
+	return s.accessToken, nil
+}
+
+// サービスアカウントの鍵で RS256 の署名をした JWT
+func (s *FCMSender) assertion(now time.Time) (string, error) {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256","typ":"JWT"}`))
+	claims, err := json.Marshal(map[string]any{
+		"iss":   s.credentials.ClientEmail,
+		"scope": fcmScope,
+		"aud":   s.credentials.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode token claims: %w", err)
+	}
+
+	unsigned := header + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign token request: %w", err)
+	}
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
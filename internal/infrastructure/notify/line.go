@@ -0,0 +1,47 @@
+package notify
+
+import (
+	"context"
+	"net/http"
+
+	"Aicon-assignment/internal/domain/entity"
+)
+
+// LINE の Messaging API でプッシュメッセージを送る URL
+const LINEPushURL = "https://api.line.me/v2/bot/message/push"
+
+// LINE のテキストメッセージの最大文字数
+const lineMaxTextLength = 5000
+
+// 公式アカウントから、友だちに追加したユーザーへ LINE のメッセージを送る
+// LINE Notify は 2025 年 3 月に終了したため、Messaging API を使う。宛先は LINE のユーザー ID
+type LINESender struct {
+	URL         string
+	AccessToken string // チャネルアクセストークン（長期）
+	Client      *http.Client
+}
+
+func NewLINESender(accessToken string) *LINESender {
+	return &LINESender{URL: LINEPushURL, AccessToken: accessToken, Client: &http.Client{Timeout: sendTimeout}}
+}
+
+type linePushRequest struct {
+	To       string        `json:"to"`
+	Messages []lineMessage `json:"messages"`
+}
+
+type lineMessage struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+func (s *LINESender) Send(ctx context.Context, to string, notification entity.Notification) error {
+	text := []rune(notification.Text())
+	if len(text) > lineMaxTextLength {
+		text = append(text[:lineMaxTextLength-1], '…')
+	}
+	return postJSON(ctx, s.Client, s.URL, s.AccessToken, linePushRequest{
+		To:       to,
+		Messages: []lineMessage{{Type: "text", Text: string(text)}},
+	})
+}
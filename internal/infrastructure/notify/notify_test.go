@@ -0,0 +1,171 @@
+package notify
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"Aicon-assignment/internal/domain/entity"
+	"Aicon-assignment/internal/usecase"
+)
+
+type recordedRequest struct {
+	Path        string
+	Auth        string
+	ContentType string
+	Body        string
+}
+
+// 受けたリクエストを記録し、パスごとに決めた応答を返す
+func newTestServer(t *testing.T, responses map[string]string) (*httptest.Server, *[]recordedRequest) {
+	var requests []recordedRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, recordedRequest{
+			Path:        r.URL.Path,
+			Auth:        r.Header.Get("Authorization"),
+			ContentType: r.Header.Get("Content-Type"),
+			Body:        string(body),
+		})
+		response, ok := responses[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(response))
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+var testNotification = entity.Notification{Title: "SLO アラート", Body: "GET /items availability"}
+
+func TestSlackSender_Send(t *testing.T) {
+	server, requests := newTestServer(t, map[string]string{"/hook": "ok"})
+
+	require.NoError(t, NewSlackSender(server.URL+"/hook").Send(context.Background(), "", testNotification))
+	require.Len(t, *requests, 1)
+	assert.JSONEq(t, `{"text": "SLO アラート\nGET /items availability"}`, (*requests)[0].Body)
+	assert.Empty(t, (*requests)[0].Auth)
+
+	err := NewSlackSender(server.URL+"/unknown").Send(context.Background(), "", testNotification)
+	assert.EqualError(t, err, "failed to send notification: unexpected status code 401")
+}
+
+func TestLINESender_Send(t *testing.T) {
+	server, requests := newTestServer(t, map[string]string{"/v2/bot/message/push": "{}"})
+	sender := NewLINESender("channel-token")
+	sender.URL = server.URL + "/v2/bot/message/push"
+
+	require.NoError(t, sender.Send(context.Background(), "U123", testNotification))
+	require.Len(t, *requests, 1)
+	assert.Equal(t, "Bearer channel-token", (*requests)[0].Auth)
+	assert.JSONEq(t, `{"to": "U123", "messages": [{"type": "text", "text": "SLO アラート\nGET /items availability"}]}`, (*requests)[0].Body)
+
+	t.Run("正常系: 長いメッセージは切り詰める", func(t *testing.T) {
+		require.NoError(t, sender.Send(context.Background(), "U123", entity.Notification{Title: strings.Repeat("あ", 6000)}))
+
+		var body linePushRequest
+		require.NoError(t, json.Unmarshal([]byte((*requests)[1].Body), &body))
+		assert.Equal(t, lineMaxTextLength, len([]rune(body.Messages[0].Text)))
+	})
+}
+
+func TestFCMSender_Send(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	server, requests := newTestServer(t, map[string]string{
+		"/token":                               `{"access_token": "access-1", "expires_in": 3600}`,
+		"/v1/projects/aicon-app/messages:send": `{"name": "projects/aicon-app/messages/1"}`,
+	})
+	credentials, err := json.Marshal(map[string]string{
+		"type":         "service_account",
+		"project_id":   "aicon-app",
+		"client_email": "notifier@aicon-app.iam.gserviceaccount.com",
+		"private_key":  string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		"token_uri":    server.URL + "/token",
+	})
+	require.NoError(t, err)
+
+	sender, err := NewFCMSender(credentials)
+	require.NoError(t, err)
+	sender.BaseURL = server.URL
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	sender.now = func() time.Time { return now }
+
+	require.NoError(t, sender.Send(context.Background(), "device-1", testNotification))
+	require.NoError(t, sender.Send(context.Background(), "device-2", testNotification))
+
+	// アクセストークンは1回だけ取得して使い回す
+	require.Len(t, *requests, 3)
+	tokenRequest := (*requests)[0]
+	assert.Equal(t, "/token", tokenRequest.Path)
+	assert.Equal(t, "application/x-www-form-urlencoded", tokenRequest.ContentType)
+	form, err := url.ParseQuery(tokenRequest.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "urn:ietf:params:oauth:grant-type:jwt-bearer", form.Get("grant_type"))
+
+	parts := strings.Split(form.Get("assertion"), ".")
+	require.Len(t, parts, 3)
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	require.NoError(t, err)
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	assert.NoError(t, rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], signature))
+	claims, err := base64.RawURLEncoding.DecodeString(parts[1])
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"iss": "notifier@aicon-app.iam.gserviceaccount.com", "scope": "https://www.googleapis.com/auth/firebase.messaging",
+		"aud": "`+server.URL+`/token", "iat": 1704067200, "exp": 1704070800}`, string(claims))
+
+	send := (*requests)[2]
+	assert.Equal(t, "/v1/projects/aicon-app/messages:send", send.Path)
+	assert.Equal(t, "Bearer access-1", send.Auth)
+	assert.JSONEq(t, `{"message": {"token": "device-2", "notification": {"title": "SLO アラート", "body": "GET /items availability"}}}`, send.Body)
+
+	t.Run("正常系: 期限が近づいたら取り直す", func(t *testing.T) {
+		now = now.Add(time.Hour - 30*time.Second)
+
+		require.NoError(t, sender.Send(context.Background(), "device-1", testNotification))
+		assert.Equal(t, "/token", (*requests)[3].Path)
+	})
+
+	t.Run("異常系: 鍵の形式が誤っている", func(t *testing.T) {
+		_, err := NewFCMSender([]byte(`{"project_id": "aicon-app", "client_email": "a@b", "token_uri": "https://example.com", "private_key": "x"}`))
+		assert.EqualError(t, err, "invalid service account key: private_key is not PEM encoded")
+	})
+}
+
+// メールはタイトルを件名にする
+type recordingMailer struct {
+	mails []usecase.Mail
+}
+
+func (m *recordingMailer) Send(ctx context.Context, mail usecase.Mail) error {
+	m.mails = append(m.mails, mail)
+	return nil
+}
+
+func TestEmailSender_Send(t *testing.T) {
+	mailer := &recordingMailer{}
+
+	require.NoError(t, (&EmailSender{Mailer: mailer}).Send(context.Background(), "admin@example.com", testNotification))
+	assert.Equal(t, []usecase.Mail{{To: "admin@example.com", Subject: "SLO アラート", Body: "GET /items availability"}}, mailer.mails)
+}
@@ -0,0 +1,62 @@
+// Package notify は usecase.NotificationSender の実装（Slack・LINE・メール・プッシュ通知）を提供する。
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"Aicon-assignment/internal/domain/entity"
+)
+
+// 通知先のサービスの応答を待つ時間
+const sendTimeout = 10 * time.Second
+
+// Slack の Incoming Webhook に送る。チームで1つのチャンネルのため、宛先は使わない
+type SlackSender struct {
+	URL    string
+	Client *http.Client
+}
+
+func NewSlackSender(url string) *SlackSender {
+	return &SlackSender{URL: url, Client: &http.Client{Timeout: sendTimeout}}
+}
+
+type slackPayload struct {
+	Text string `json:"text"`
+}
+
+func (s *SlackSender) Send(ctx context.Context, to string, notification entity.Notification) error {
+	return postJSON(ctx, s.Client, s.URL, "", slackPayload{Text: notification.Text()})
+}
+
+// JSON を POST し、2xx 以外はエラーにする。token が空でなければ Bearer で送る
+func postJSON(ctx context.Context, client *http.Client, url, token string, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build notification request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send notification: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("failed to send notification: unexpected status code %d", resp.StatusCode)
+	}
+	return nil
+}
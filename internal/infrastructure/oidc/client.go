@@ -0,0 +1,104 @@
+// Package oidc は usecase.IdentityProvider の実装を提供する。
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// プロバイダーに登録したクライアント
+type Config struct {
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string // コールバックの URL。プロバイダーに登録したものと一致させる
+}
+
+func newHTTPClient() *http.Client {
+	return &http.Client{Timeout: 10 * time.Second}
+}
+
+// 認可画面の URL。PKCE の code_challenge は S256 で送る
+func authCodeURL(base string, cfg Config, scope, state, codeChallenge string, extra url.Values) string {
+	params := url.Values{
+		"client_id":             {cfg.ClientID},
+		"redirect_uri":          {cfg.RedirectURL},
+		"response_type":         {"code"},
+		"scope":                 {scope},
+		"state":                 {state},
+		"code_challenge":        {codeChallenge},
+		"code_challenge_method": {"S256"},
+	}
+	for name, values := range extra {
+		params[name] = values
+	}
+	return base + "?" + params.Encode()
+}
+
+// トークンエンドポイントで認可コードを交換し、応答の JSON を out に読み込む
+func exchangeCode(ctx context.Context, client *http.Client, tokenURL string, cfg Config, code, codeVerifier string, out any) error {
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {cfg.RedirectURL},
+		"client_id":     {cfg.ClientID},
+		"client_secret": {cfg.ClientSecret},
+		"code_verifier": {codeVerifier},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to build token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return doJSON(client, req, out)
+}
+
+// API を呼び出し、応答の JSON を out に読み込む
+func getJSON(ctx context.Context, client *http.Client, url, accessToken string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	return doJSON(client, req, out)
+}
+
+// エラーの応答（RFC 6749 5.2）は error と error_description を返す
+type errorResponse struct {
+	Error       string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+func doJSON(client *http.Client, req *http.Request, out any) error {
+	req.Header.Set("Accept", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call %s: %w", req.URL.Host, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("failed to read response from %s: %w", req.URL.Host, err)
+	}
+
+	// GitHub はエラーでも 200 で error を返す
+	var failure errorResponse
+	_ = json.Unmarshal(body, &failure)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 || failure.Error != "" {
+		if failure.Error != "" {
+			return fmt.Errorf("%s returned %s: %s", req.URL.Host, failure.Error, failure.Description)
+		}
+		return fmt.Errorf("%s returned status code %d", req.URL.Host, resp.StatusCode)
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to decode response from %s: %w", req.URL.Host, err)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package oidc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"Aicon-assignment/internal/domain/entity"
+)
+
+// GitHub の OAuth2（ID トークンがないため、API でユーザーとメールアドレスを取得する）
+type GitHub struct {
+	Config
+	AuthURL  string
+	TokenURL string
+	APIURL   string
+	Client   *http.Client
+}
+
+func NewGitHub(cfg Config) *GitHub {
+	return &GitHub{
+		Config:   cfg,
+		AuthURL:  "https://github.com/login/oauth/authorize",
+		TokenURL: "https://github.com/login/oauth/access_token",
+		APIURL:   "https://api.github.com",
+		Client:   newHTTPClient(),
+	}
+}
+
+// nonce は OIDC の ID トークンのためのものなので使わない
+func (g *GitHub) AuthCodeURL(state, nonce, codeChallenge string) string {
+	return authCodeURL(g.AuthURL, g.Config, "read:user user:email", state, codeChallenge, nil)
+}
+
+func (g *GitHub) Exchange(ctx context.Context, code, codeVerifier, nonce string) (*entity.ExternalIdentity, error) {
+	var token struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := exchangeCode(ctx, g.Client, g.TokenURL, g.Config, code, codeVerifier, &token); err != nil {
+		return nil, err
+	}
+	if token.AccessToken == "" {
+		return nil, errors.New("github returned no access token")
+	}
+
+	var user struct {
+		ID    int64  `json:"id"`
+		Login string `json:"login"`
+		Name  string `json:"name"`
+	}
+	if err := getJSON(ctx, g.Client, g.APIURL+"/user", token.AccessToken, &user); err != nil {
+		return nil, err
+	}
+	if user.ID == 0 {
+		return nil, errors.New("github returned no user id")
+	}
+
+	// 公開していないメールアドレスも含め、確認済みの主アドレスだけを使う
+	var emails []struct {
+		Email    string `json:"email"`
+		Primary  bool   `json:"primary"`
+		Verified bool   `json:"verified"`
+	}
+	if err := getJSON(ctx, g.Client, g.APIURL+"/user/emails", token.AccessToken, &emails); err != nil {
+		return nil, err
+	}
+
+	identity := &entity.ExternalIdentity{
+		Provider: entity.IdentityProviderGitHub,
+		Subject:  strconv.FormatInt(user.ID, 10),
+		Name:     user.Name,
+		Login:    user.Login,
+	}
+	for _, email := range emails {
+		if email.Primary && email.Verified {
+			identity.Email = email.Email
+			identity.EmailVerified = true
+		}
+	}
+	return identity, nil
+}
@@ -0,0 +1,147 @@
+package oidc
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"Aicon-assignment/internal/domain/entity"
+)
+
+var testConfig = Config{ClientID: "client-id", ClientSecret: "client-secret", RedirectURL: "https://app.example.com/auth/oidc/callback"}
+
+// 署名のない ID トークンを組み立てる（署名は検証しないため）
+func unsignedIDToken(t *testing.T, claims map[string]any) string {
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+	return "eyJhbGciOiJSUzI1NiJ9." + base64.RawURLEncoding.EncodeToString(payload) + ".signature"
+}
+
+func newTestGoogle(t *testing.T, claims map[string]any) (*Google, *url.Values) {
+	var form url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		form = r.PostForm
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"access_token": "access", "id_token": unsignedIDToken(t, claims)})
+	}))
+	t.Cleanup(server.Close)
+
+	google := NewGoogle(testConfig)
+	google.TokenURL = server.URL + "/token"
+	google.Now = func() time.Time { return time.Unix(1700000000, 0) }
+	return google, &form
+}
+
+func TestGoogle_AuthCodeURL(t *testing.T) {
+	google := NewGoogle(testConfig)
+
+	parsed, err := url.Parse(google.AuthCodeURL("state", "nonce", "challenge"))
+	require.NoError(t, err)
+	query := parsed.Query()
+	assert.Equal(t, "code", query.Get("response_type"))
+	assert.Equal(t, "client-id", query.Get("client_id"))
+	assert.Equal(t, testConfig.RedirectURL, query.Get("redirect_uri"))
+	assert.Equal(t, "openid email profile", query.Get("scope"))
+	assert.Equal(t, "state", query.Get("state"))
+	assert.Equal(t, "nonce", query.Get("nonce"))
+	assert.Equal(t, "challenge", query.Get("code_challenge"))
+	assert.Equal(t, "S256", query.Get("code_challenge_method"))
+}
+
+func TestGoogle_Exchange(t *testing.T) {
+	valid := func() map[string]any {
+		return map[string]any{
+			"iss": "https://accounts.google.com", "aud": "client-id", "sub": "10769150350006150715113082367",
+			"exp": 1700000600, "nonce": "nonce", "email": "yamada@example.com", "email_verified": true, "name": "山田太郎",
+		}
+	}
+
+	t.Run("正常系: ID トークンの項目を確かめて利用者を返す", func(t *testing.T) {
+		google, form := newTestGoogle(t, valid())
+
+		identity, err := google.Exchange(context.Background(), "code", "verifier", "nonce")
+		require.NoError(t, err)
+		assert.Equal(t, &entity.ExternalIdentity{
+			Provider: entity.IdentityProviderGoogle, Subject: "10769150350006150715113082367",
+			Email: "yamada@example.com", EmailVerified: true, Name: "山田太郎",
+		}, identity)
+		assert.Equal(t, "authorization_code", form.Get("grant_type"))
+		assert.Equal(t, "code", form.Get("code"))
+		assert.Equal(t, "verifier", form.Get("code_verifier"))
+		assert.Equal(t, "client-secret", form.Get("client_secret"))
+	})
+
+	tests := []struct {
+		name        string
+		modify      func(claims map[string]any)
+		expectedErr string
+	}{
+		{name: "異常系: nonce が違う", modify: func(c map[string]any) { c["nonce"] = "other" }, expectedErr: "nonce does not match"},
+		{name: "異常系: 他のクライアントに発行した", modify: func(c map[string]any) { c["aud"] = "other-client" }, expectedErr: "issued to another client"},
+		{name: "異常系: 有効期限切れ", modify: func(c map[string]any) { c["exp"] = 1699999999 }, expectedErr: "expired"},
+		{name: "異常系: 発行元が違う", modify: func(c map[string]any) { c["iss"] = "https://evil.example.com" }, expectedErr: "unexpected id token issuer"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := valid()
+			tt.modify(claims)
+			google, _ := newTestGoogle(t, claims)
+
+			_, err := google.Exchange(context.Background(), "code", "verifier", "nonce")
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expectedErr)
+		})
+	}
+}
+
+func TestGitHub_Exchange(t *testing.T) {
+	newTestGitHub := func(t *testing.T, tokenResponse string) *GitHub {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(tokenResponse))
+		})
+		mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "Bearer gho_token", r.Header.Get("Authorization"))
+			_, _ = w.Write([]byte(`{"id":583231,"login":"yamada","name":"山田太郎"}`))
+		})
+		mux.HandleFunc("/user/emails", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`[{"email":"old@example.com","primary":false,"verified":true},{"email":"yamada@example.com","primary":true,"verified":true}]`))
+		})
+		server := httptest.NewServer(mux)
+		t.Cleanup(server.Close)
+
+		github := NewGitHub(testConfig)
+		github.TokenURL = server.URL + "/token"
+		github.APIURL = server.URL
+		return github
+	}
+
+	t.Run("正常系: ユーザーと確認済みの主アドレスを返す", func(t *testing.T) {
+		github := newTestGitHub(t, `{"access_token":"gho_token","token_type":"bearer"}`)
+
+		identity, err := github.Exchange(context.Background(), "code", "verifier", "")
+		require.NoError(t, err)
+		assert.Equal(t, &entity.ExternalIdentity{
+			Provider: entity.IdentityProviderGitHub, Subject: "583231",
+			Email: "yamada@example.com", EmailVerified: true, Name: "山田太郎", Login: "yamada",
+		}, identity)
+	})
+
+	t.Run("異常系: 200 で返るエラー", func(t *testing.T) {
+		github := newTestGitHub(t, `{"error":"bad_verification_code","error_description":"The code passed is incorrect or expired."}`)
+
+		_, err := github.Exchange(context.Background(), "code", "verifier", "")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "bad_verification_code")
+	})
+}
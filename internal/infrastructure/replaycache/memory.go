@@ -0,0 +1,54 @@
+// Package replaycache は usecase.ReplayCache の実装を提供する。
+package replaycache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"Aicon-assignment/internal/pkg/clock"
+)
+
+// 期限切れのキーを掃除する間隔（Remember の呼び出し回数）
+const sweepEvery = 1024
+
+// プロセス内で覚えておくリプレイキャッシュ
+// 複数のインスタンスで動かす場合は、別のインスタンスに再送されたリクエストを見逃すため Redis を使う
+type Memory struct {
+	mu      sync.Mutex
+	expires map[string]time.Time
+	calls   int
+	clock   clock.Clock
+}
+
+func NewMemory(clock clock.Clock) *Memory {
+	return &Memory{expires: make(map[string]time.Time), clock: clock}
+}
+
+func (m *Memory) Remember(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.clock.Now()
+	m.calls++
+	if m.calls%sweepEvery == 0 {
+		for k, expiresAt := range m.expires {
+			if !now.Before(expiresAt) {
+				delete(m.expires, k)
+			}
+		}
+	}
+
+	if expiresAt, ok := m.expires[key]; ok && now.Before(expiresAt) {
+		return false, nil
+	}
+	m.expires[key] = now.Add(ttl)
+	return true, nil
+}
+
+// 覚えているキーの数（期限切れで未掃除のものを含む）
+func (m *Memory) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.expires)
+}
@@ -0,0 +1,131 @@
+package replaycache
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Redis の SET key 1 NX PX ttl で覚えておくリプレイキャッシュ。複数のインスタンスでキャッシュを共有できる
+// リクエストごとに接続し、RESP（Redis のプロトコル）の最小限だけを扱う
+type Redis struct {
+	Addr     string // "localhost:6379" など
+	Password string // 空の場合は AUTH しない
+	Prefix   string // キーの先頭に付ける文字列
+	Timeout  time.Duration
+}
+
+func NewRedis(addr, password string, timeout time.Duration) *Redis {
+	return &Redis{Addr: addr, Password: password, Prefix: "replay:", Timeout: timeout}
+}
+
+func (r *Redis) Remember(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	conn, reader, err := r.connect(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	// PX は 1 ミリ秒以上でなければならない
+	ms := max(ttl.Milliseconds(), 1)
+	reply, err := command(conn, reader, "SET", r.Prefix+key, "1", "NX", "PX", strconv.FormatInt(ms, 10))
+	if err != nil {
+		return false, err
+	}
+	switch reply {
+	case "OK":
+		return true, nil
+	case "":
+		// NX でキーがすでにある場合は nil が返る
+		return false, nil
+	default:
+		return false, fmt.Errorf("redis: unexpected reply to SET: %q", reply)
+	}
+}
+
+// Redis に接続でき、応答があるかを確認する（/readyz 用）
+func (r *Redis) Ping(ctx context.Context) error {
+	conn, reader, err := r.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	reply, err := command(conn, reader, "PING")
+	if err != nil {
+		return err
+	}
+	if reply != "PONG" {
+		return fmt.Errorf("redis: unexpected reply to PING: %q", reply)
+	}
+	return nil
+}
+
+// 接続し、パスワードがあれば AUTH する。呼び出し元が接続を閉じる
+func (r *Redis) connect(ctx context.Context) (net.Conn, *bufio.Reader, error) {
+	dialer := net.Dialer{Timeout: r.Timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", r.Addr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("redis: %w", err)
+	}
+	deadline := time.Now().Add(r.Timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	conn.SetDeadline(deadline)
+	reader := bufio.NewReader(conn)
+
+	if r.Password != "" {
+		reply, err := command(conn, reader, "AUTH", r.Password)
+		if err != nil {
+			conn.Close()
+			return nil, nil, err
+		}
+		if reply != "OK" {
+			conn.Close()
+			return nil, nil, fmt.Errorf("redis: unexpected reply to AUTH: %q", reply)
+		}
+	}
+	return conn, reader, nil
+}
+
+// コマンドを送り、単純な文字列か nil（空文字列）の応答を返す。エラーの応答は error にする
+func command(conn net.Conn, reader *bufio.Reader, args ...string) (string, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if _, err := conn.Write([]byte(b.String())); err != nil {
+		return "", fmt.Errorf("redis: %w", err)
+	}
+
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return "", fmt.Errorf("redis: failed to read reply: %w", err)
+	}
+	line = strings.TrimRight(line, "\r\n")
+	if line == "" {
+		return "", fmt.Errorf("redis: empty reply")
+	}
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return "", fmt.Errorf("redis: %s", line[1:])
+	case '$':
+		if line == "$-1" {
+			return "", nil
+		}
+		return "", fmt.Errorf("redis: unexpected bulk reply: %q", line)
+	case '_':
+		// RESP3 の nil
+		return "", nil
+	default:
+		return "", fmt.Errorf("redis: unexpected reply: %q", line)
+	}
+}
@@ -0,0 +1,185 @@
+package replaycache
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"Aicon-assignment/internal/pkg/clock"
+)
+
+func TestMemory(t *testing.T) {
+	ctx := context.Background()
+	clk := clock.NewFrozen(time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC))
+	cache := NewMemory(clk)
+
+	t.Run("正常系: 初めてのキーは新しい", func(t *testing.T) {
+		fresh, err := cache.Remember(ctx, "client-a:n1", time.Minute)
+		require.NoError(t, err)
+		assert.True(t, fresh)
+	})
+
+	t.Run("異常系: 期限内の同じキーは覚えている", func(t *testing.T) {
+		fresh, err := cache.Remember(ctx, "client-a:n1", time.Minute)
+		require.NoError(t, err)
+		assert.False(t, fresh)
+	})
+
+	t.Run("正常系: 期限が過ぎたキーは新しいものとして扱う", func(t *testing.T) {
+		clk.Advance(time.Minute)
+		fresh, err := cache.Remember(ctx, "client-a:n1", time.Minute)
+		require.NoError(t, err)
+		assert.True(t, fresh)
+	})
+
+	t.Run("正常系: 期限切れのキーは掃除する", func(t *testing.T) {
+		cache := NewMemory(clk)
+		for i := 0; i < sweepEvery-1; i++ {
+			_, err := cache.Remember(ctx, fmt.Sprintf("k%d", i), time.Second)
+			require.NoError(t, err)
+		}
+		clk.Advance(time.Second)
+		_, err := cache.Remember(ctx, "last", time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, 1, cache.Len())
+	})
+}
+
+// SET key value NX PX ms と AUTH・PING だけに応答する Redis
+func fakeRedis(t *testing.T, password string) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	var mu sync.Mutex
+	keys := make(map[string]bool)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				reader := bufio.NewReader(conn)
+				authed := password == ""
+				for {
+					args, err := readCommand(reader)
+					if err != nil {
+						return
+					}
+					switch strings.ToUpper(args[0]) {
+					case "AUTH":
+						if args[1] != password {
+							io.WriteString(conn, "-WRONGPASS invalid password\r\n")
+							continue
+						}
+						authed = true
+						io.WriteString(conn, "+OK\r\n")
+					case "SET":
+						if !authed {
+							io.WriteString(conn, "-NOAUTH Authentication required.\r\n")
+							continue
+						}
+						mu.Lock()
+						exists := keys[args[1]]
+						keys[args[1]] = true
+						mu.Unlock()
+						if exists {
+							io.WriteString(conn, "$-1\r\n")
+						} else {
+							io.WriteString(conn, "+OK\r\n")
+						}
+					case "PING":
+						if !authed {
+							io.WriteString(conn, "-NOAUTH Authentication required.\r\n")
+							continue
+						}
+						io.WriteString(conn, "+PONG\r\n")
+					default:
+						io.WriteString(conn, "-ERR unknown command\r\n")
+					}
+				}
+			}()
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func readCommand(reader *bufio.Reader) ([]string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, n)
+	for i := range args {
+		if _, err := reader.ReadString('\n'); err != nil {
+			return nil, err
+		}
+		value, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		args[i] = strings.TrimRight(value, "\r\n")
+	}
+	return args, nil
+}
+
+func TestRedis(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("正常系: 初めてのキーは新しく、2 回目は覚えている", func(t *testing.T) {
+		cache := NewRedis(fakeRedis(t, ""), "", time.Second)
+
+		fresh, err := cache.Remember(ctx, "client-a:n1", time.Minute)
+		require.NoError(t, err)
+		assert.True(t, fresh)
+
+		fresh, err = cache.Remember(ctx, "client-a:n1", time.Minute)
+		require.NoError(t, err)
+		assert.False(t, fresh)
+	})
+
+	t.Run("正常系: パスワードで認証する", func(t *testing.T) {
+		cache := NewRedis(fakeRedis(t, "secret"), "secret", time.Second)
+
+		fresh, err := cache.Remember(ctx, "client-a:n1", time.Minute)
+		require.NoError(t, err)
+		assert.True(t, fresh)
+	})
+
+	t.Run("正常系: PING に応答がある", func(t *testing.T) {
+		assert.NoError(t, NewRedis(fakeRedis(t, "secret"), "secret", time.Second).Ping(ctx))
+	})
+
+	t.Run("異常系: パスワードが違う", func(t *testing.T) {
+		cache := NewRedis(fakeRedis(t, "secret"), "wrong", time.Second)
+
+		_, err := cache.Remember(ctx, "client-a:n1", time.Minute)
+		assert.ErrorContains(t, err, "WRONGPASS")
+	})
+
+	t.Run("異常系: 接続できない", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		addr := listener.Addr().String()
+		listener.Close()
+
+		_, err = NewRedis(addr, "", time.Second).Remember(ctx, "client-a:n1", time.Minute)
+		assert.Error(t, err)
+	})
+}
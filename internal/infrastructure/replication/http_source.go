@@ -0,0 +1,67 @@
+// Package replication はスタンバイがプライマリの変更ストリームを取得するクライアントを提供する。
+package replication
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"Aicon-assignment/internal/domain/entity"
+)
+
+// プライマリが変更ストリームを公開するパス
+const EventsPath = "/replication/events"
+
+// プライマリの GET /replication/events から変更を取得する usecase.ChangeSource
+type HTTPSource struct {
+	URL    string // プライマリの API のベース URL（"https://api.tokyo.example.com" など）
+	Token  string // プライマリの REPLICATION_TOKEN
+	Client *http.Client
+}
+
+func NewHTTPSource(baseURL, token string) *HTTPSource {
+	return &HTTPSource{
+		URL:    strings.TrimRight(baseURL, "/"),
+		Token:  token,
+		Client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (s *HTTPSource) Changes(ctx context.Context, afterSequence int64, limit int) (*entity.ChangeBatch, error) {
+	query := url.Values{}
+	query.Set("after", strconv.FormatInt(afterSequence, 10))
+	query.Set("limit", strconv.Itoa(limit))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL+EventsPath+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build replication request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+s.Token)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("replication source %s: %w", s.URL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Error string `json:"error"`
+		}
+		_ = json.NewDecoder(io.LimitReader(resp.Body, 4096)).Decode(&apiErr)
+		return nil, fmt.Errorf("replication source %s: status %d: %s", s.URL, resp.StatusCode, apiErr.Error)
+	}
+
+	var batch entity.ChangeBatch
+	if err := json.NewDecoder(resp.Body).Decode(&batch); err != nil {
+		return nil, fmt.Errorf("failed to decode replication response: %w", err)
+	}
+	return &batch, nil
+}
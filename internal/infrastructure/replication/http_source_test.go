@@ -0,0 +1,54 @@
+package replication
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPSource_Changes(t *testing.T) {
+	t.Run("正常系: 連番と件数を渡し、トークンを付けて取得する", func(t *testing.T) {
+		var gotURI, gotAuth string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			gotURI = r.URL.RequestURI()
+			gotAuth = r.Header.Get("Authorization")
+			_, _ = w.Write([]byte(`{"events":[{"sequence":11,"event_id":"e11","event_type":"item.deleted","item_id":3,"schema_version":"1","payload":{"id":"e11"}}],"last_sequence":20}`))
+		}))
+		t.Cleanup(server.Close)
+
+		batch, err := NewHTTPSource(server.URL+"/", "replica-token").Changes(context.Background(), 10, 100)
+
+		require.NoError(t, err)
+		assert.Equal(t, "/replication/events?after=10&limit=100", gotURI)
+		assert.Equal(t, "Bearer replica-token", gotAuth)
+		require.Len(t, batch.Events, 1)
+		assert.Equal(t, int64(11), batch.Events[0].Sequence)
+		assert.JSONEq(t, `{"id":"e11"}`, string(batch.Events[0].Payload))
+		assert.Equal(t, int64(20), batch.LastSequence)
+	})
+
+	t.Run("異常系: 認証エラーはメッセージ付きで返す", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":"invalid replication token"}`))
+		}))
+		t.Cleanup(server.Close)
+
+		_, err := NewHTTPSource(server.URL, "wrong").Changes(context.Background(), 0, 100)
+
+		assert.ErrorContains(t, err, "status 401: invalid replication token")
+	})
+
+	t.Run("異常系: 接続できない", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		server.Close()
+
+		_, err := NewHTTPSource(server.URL, "replica-token").Changes(context.Background(), 0, 100)
+
+		assert.Error(t, err)
+	})
+}
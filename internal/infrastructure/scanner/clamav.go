@@ -0,0 +1,86 @@
+// Package scanner は usecase.Scanner の実装を提供する。
+package scanner
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"Aicon-assignment/internal/domain/entity"
+)
+
+// clamd に一度に送る大きさ
+const chunkSize = 64 << 10
+
+// clamd（ClamAV のデーモン）の INSTREAM コマンドで検査する
+// 送れる大きさの上限は clamd の StreamMaxLength で、超えた場合は検査できなかったものとして扱う
+type ClamAV struct {
+	Addr    string // "localhost:3310" など
+	Timeout time.Duration
+}
+
+func NewClamAV(addr string, timeout time.Duration) *ClamAV {
+	return &ClamAV{Addr: addr, Timeout: timeout}
+}
+
+func (c *ClamAV) Scan(ctx context.Context, content io.Reader) (*entity.ScanResult, error) {
+	dialer := net.Dialer{Timeout: c.Timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", c.Addr)
+	if err != nil {
+		return nil, fmt.Errorf("clamav: %w", err)
+	}
+	defer conn.Close()
+	deadline := time.Now().Add(c.Timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	conn.SetDeadline(deadline)
+
+	if _, err := conn.Write([]byte("zINSTREAM\x00")); err != nil {
+		return nil, fmt.Errorf("clamav: %w", err)
+	}
+	// 長さ（4 バイトのビッグエンディアン）と中身を繰り返し、長さ 0 で終える
+	buf := make([]byte, 4+chunkSize)
+	for {
+		n, readErr := content.Read(buf[4:])
+		if n > 0 {
+			binary.BigEndian.PutUint32(buf, uint32(n))
+			if _, err := conn.Write(buf[:4+n]); err != nil {
+				return nil, fmt.Errorf("clamav: %w", err)
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return nil, fmt.Errorf("clamav: failed to read content: %w", readErr)
+		}
+	}
+	if _, err := conn.Write([]byte{0, 0, 0, 0}); err != nil {
+		return nil, fmt.Errorf("clamav: %w", err)
+	}
+
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil && reply == "" {
+		return nil, fmt.Errorf("clamav: failed to read reply: %w", err)
+	}
+	return parseReply(strings.TrimRight(reply, "\x00\n"))
+}
+
+// "stream: OK"、"stream: Eicar-Signature FOUND"、"INSTREAM size limit exceeded. ERROR" など
+func parseReply(reply string) (*entity.ScanResult, error) {
+	result := strings.TrimPrefix(reply, "stream: ")
+	switch {
+	case result == "OK":
+		return &entity.ScanResult{Clean: true}, nil
+	case strings.HasSuffix(result, " FOUND"):
+		return &entity.ScanResult{Signature: strings.TrimSuffix(result, " FOUND")}, nil
+	default:
+		return nil, fmt.Errorf("clamav: %s", reply)
+	}
+}
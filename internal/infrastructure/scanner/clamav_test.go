@@ -0,0 +1,96 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// INSTREAM を受け取り、中身に応じて応答する clamd
+func fakeClamd(t *testing.T, reply func(content []byte) string) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				command := make([]byte, len("zINSTREAM\x00"))
+				if _, err := io.ReadFull(conn, command); err != nil || string(command) != "zINSTREAM\x00" {
+					return
+				}
+				var content bytes.Buffer
+				for {
+					var size uint32
+					if err := binary.Read(conn, binary.BigEndian, &size); err != nil {
+						return
+					}
+					if size == 0 {
+						break
+					}
+					if _, err := io.CopyN(&content, conn, int64(size)); err != nil {
+						return
+					}
+				}
+				io.WriteString(conn, reply(content.Bytes())+"\x00")
+			}()
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func TestClamAV(t *testing.T) {
+	ctx := context.Background()
+	addr := fakeClamd(t, func(content []byte) string {
+		switch {
+		case bytes.Contains(content, []byte("EICAR")):
+			return "stream: Eicar-Signature FOUND"
+		case len(content) > 100<<10:
+			return "INSTREAM size limit exceeded. ERROR"
+		default:
+			return "stream: OK"
+		}
+	})
+	scanner := NewClamAV(addr, 5*time.Second)
+
+	t.Run("正常系: 問題のないファイル", func(t *testing.T) {
+		result, err := scanner.Scan(ctx, strings.NewReader("certificate"))
+		require.NoError(t, err)
+		assert.True(t, result.Clean)
+	})
+
+	t.Run("正常系: ウイルスの名前を返す", func(t *testing.T) {
+		result, err := scanner.Scan(ctx, strings.NewReader("X5O!P%@AP[4\\PZX54(P^)7CC)7}$EICAR-STANDARD-ANTIVIRUS-TEST-FILE!$H+H*"))
+		require.NoError(t, err)
+		assert.False(t, result.Clean)
+		assert.Equal(t, "Eicar-Signature", result.Signature)
+	})
+
+	t.Run("異常系: 上限を超えたファイルは検査できない", func(t *testing.T) {
+		_, err := scanner.Scan(ctx, bytes.NewReader(make([]byte, 200<<10)))
+		assert.ErrorContains(t, err, "size limit exceeded")
+	})
+
+	t.Run("異常系: 接続できない", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		closed := listener.Addr().String()
+		listener.Close()
+
+		_, err = NewClamAV(closed, time.Second).Scan(ctx, strings.NewReader("certificate"))
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,70 @@
+// Package scheduler はサーバーと一緒に動かす定期実行ジョブを扱う。
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"Aicon-assignment/internal/pkg/reqctx"
+)
+
+// interval ごとに job を実行する。ctx がキャンセルされるまで戻らない
+// job はシステムの処理（reqctx.WithSystem）として実行する。job のエラーはログに残し、次の実行は続ける
+func Every(ctx context.Context, interval time.Duration, name string, job func(ctx context.Context) error) {
+	logger := slog.Default().With("job", name)
+	ctx = reqctx.WithSystem(reqctx.WithLogger(ctx, logger))
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := job(ctx); err != nil {
+				logger.Error("scheduled job failed", "error", err)
+			}
+		}
+	}
+}
+
+// 毎日 UTC の at（0 時からの経過時間）に job を実行する。ctx がキャンセルされるまで戻らない
+func Daily(ctx context.Context, at time.Duration, name string, job func(ctx context.Context) error) {
+	logger := slog.Default().With("job", name)
+	ctx = reqctx.WithSystem(reqctx.WithLogger(ctx, logger))
+
+	for {
+		timer := time.NewTimer(untilNext(time.Now(), at))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+			if err := job(ctx); err != nil {
+				logger.Error("scheduled job failed", "error", err)
+			}
+		}
+	}
+}
+
+// now から次の UTC の at までの時間
+func untilNext(now time.Time, at time.Duration) time.Duration {
+	now = now.UTC()
+	next := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).Add(at)
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next.Sub(now)
+}
+
+// "HH:MM" の形式の時刻を 0 時からの経過時間にする
+func ParseTimeOfDay(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("time of day must be HH:MM: %q", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
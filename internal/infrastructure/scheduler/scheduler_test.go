@@ -0,0 +1,68 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"Aicon-assignment/internal/pkg/reqctx"
+)
+
+func TestEvery(t *testing.T) {
+	t.Run("正常系: ジョブはシステムの処理として実行する", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		system := make(chan bool, 1)
+
+		go Every(ctx, time.Millisecond, "test", func(ctx context.Context) error {
+			select {
+			case system <- reqctx.System(ctx):
+			default:
+			}
+			return nil
+		})
+
+		select {
+		case got := <-system:
+			assert.True(t, got)
+		case <-time.After(time.Second):
+			t.Fatal("job did not run")
+		}
+	})
+}
+
+func TestUntilNext(t *testing.T) {
+	at := 18 * time.Hour
+
+	t.Run("正常系: 今日の時刻がまだ来ていない", func(t *testing.T) {
+		now := time.Date(2024, 1, 1, 17, 30, 0, 0, time.UTC)
+		assert.Equal(t, 30*time.Minute, untilNext(now, at))
+	})
+
+	t.Run("正常系: 今日の時刻を過ぎている場合は翌日", func(t *testing.T) {
+		now := time.Date(2024, 1, 1, 18, 0, 0, 0, time.UTC)
+		assert.Equal(t, 24*time.Hour, untilNext(now, at))
+	})
+
+	t.Run("正常系: UTC 以外の時刻も UTC で数える", func(t *testing.T) {
+		now := time.Date(2024, 1, 2, 2, 0, 0, 0, time.FixedZone("JST", 9*60*60)) // 2024-01-01 17:00 UTC
+		assert.Equal(t, time.Hour, untilNext(now, at))
+	})
+}
+
+func TestParseTimeOfDay(t *testing.T) {
+	t.Run("正常系", func(t *testing.T) {
+		at, err := ParseTimeOfDay("03:30")
+		assert.NoError(t, err)
+		assert.Equal(t, 3*time.Hour+30*time.Minute, at)
+	})
+
+	t.Run("異常系: 形式が違う", func(t *testing.T) {
+		for _, s := range []string{"", "3", "25:00", "03:30:00"} {
+			_, err := ParseTimeOfDay(s)
+			assert.Error(t, err, s)
+		}
+	})
+}
@@ -0,0 +1,180 @@
+// Package search は usecase.SearchRepository の実装を提供する。
+package search
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"Aicon-assignment/internal/domain/entity"
+	"Aicon-assignment/internal/pkg/reqctx"
+)
+
+// Meilisearch の REST API を使う全文検索のインデックス
+// 書き込みは Meilisearch 側でタスクとして順に処理されるため、反映まで少し遅れる
+type MeilisearchIndex struct {
+	URL    string // "http://localhost:7700" など
+	APIKey string // 空の場合は Authorization ヘッダーを付けない
+	UID    string // インデックスの UID
+	Client *http.Client
+}
+
+func NewMeilisearchIndex(baseURL, apiKey, index string) *MeilisearchIndex {
+	return &MeilisearchIndex{
+		URL:    strings.TrimRight(baseURL, "/"),
+		APIKey: apiKey,
+		UID:    index,
+		Client: &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// インデックスに保存するドキュメント。検索結果からそのままアイテムを組み立てられるよう全項目を持つ
+type document struct {
+	ID            int64     `json:"id"`
+	Name          string    `json:"name"`
+	Category      string    `json:"category"`
+	Brand         string    `json:"brand"`
+	PurchasePrice int       `json:"purchase_price"`
+	PurchaseDate  string    `json:"purchase_date"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	CreatedAtMs   int64     `json:"created_at_ms"` // 並び替え用（Meilisearch は日時の文字列で並び替えられない）
+	OwnerID       *int64    `json:"owner_id"`      // 呼び出し元のユーザーのアイテムに絞り込む
+}
+
+func newDocument(item *entity.Item) document {
+	return document{
+		ID:            item.ID,
+		Name:          item.Name,
+		Category:      item.Category,
+		Brand:         item.Brand,
+		PurchasePrice: item.PurchasePrice,
+		PurchaseDate:  item.PurchaseDate,
+		CreatedAt:     item.CreatedAt,
+		UpdatedAt:     item.UpdatedAt,
+		CreatedAtMs:   item.CreatedAt.UnixMilli(),
+		OwnerID:       item.OwnerID,
+	}
+}
+
+func (d document) item() *entity.Item {
+	return &entity.Item{
+		ID:            d.ID,
+		Name:          d.Name,
+		Category:      d.Category,
+		Brand:         d.Brand,
+		PurchasePrice: d.PurchasePrice,
+		PurchaseDate:  d.PurchaseDate,
+		CreatedAt:     d.CreatedAt,
+		UpdatedAt:     d.UpdatedAt,
+		OwnerID:       d.OwnerID,
+	}
+}
+
+// 名前・ブランドだけを検索対象にし、作成日時の降順で並び替え・持ち主で絞り込みができるようにする
+// 設定を変えると Meilisearch がインデックスを作り直すため、起動時ではなく reindex-search で行う
+func (m *MeilisearchIndex) Configure(ctx context.Context) error {
+	return m.do(ctx, http.MethodPatch, "/settings", map[string][]string{
+		"searchableAttributes": {"name", "brand"},
+		"sortableAttributes":   {"created_at_ms", "id"},
+		"filterableAttributes": {"owner_id"},
+	}, nil)
+}
+
+func (m *MeilisearchIndex) Index(ctx context.Context, items []*entity.Item) error {
+	docs := make([]document, len(items))
+	for i, item := range items {
+		docs[i] = newDocument(item)
+	}
+	return m.do(ctx, http.MethodPost, "/documents?primaryKey=id", docs, nil)
+}
+
+func (m *MeilisearchIndex) Remove(ctx context.Context, ids []int64) error {
+	return m.do(ctx, http.MethodPost, "/documents/delete-batch", ids, nil)
+}
+
+// インデックスのドキュメントをすべて削除する
+func (m *MeilisearchIndex) Clear(ctx context.Context) error {
+	return m.do(ctx, http.MethodDelete, "/documents", nil, nil)
+}
+
+func (m *MeilisearchIndex) Search(ctx context.Context, search entity.ItemSearch) ([]*entity.Item, error) {
+	request := map[string]any{
+		"q":     search.Keyword,
+		"limit": search.Limit,
+		"sort":  []string{"created_at_ms:desc", "id:desc"},
+	}
+	// リポジトリと同じく、ユーザーのいるコンテキストではそのユーザーのアイテムだけを返す
+	if userID, ok := reqctx.UserID(ctx); ok {
+		request["filter"] = fmt.Sprintf("owner_id = %d", userID)
+	}
+	var result struct {
+		Hits []document `json:"hits"`
+	}
+	if err := m.do(ctx, http.MethodPost, "/search", request, &result); err != nil {
+		return nil, err
+	}
+
+	items := make([]*entity.Item, len(result.Hits))
+	for i, hit := range result.Hits {
+		items[i] = hit.item()
+	}
+	return items, nil
+}
+
+// Meilisearch に接続でき、インデックスを参照できるかを確認する（/readyz 用）
+func (m *MeilisearchIndex) Ping(ctx context.Context) error {
+	return m.do(ctx, http.MethodGet, "", nil, nil)
+}
+
+// インデックス配下の path に body を JSON で送り、out が nil でなければレスポンスを読み込む
+func (m *MeilisearchIndex) do(ctx context.Context, method, path string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode meilisearch request: %w", err)
+		}
+		reader = bytes.NewReader(encoded)
+	}
+
+	endpoint := m.URL + "/indexes/" + url.PathEscape(m.UID) + path
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return fmt.Errorf("failed to build meilisearch request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if m.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+m.APIKey)
+	}
+
+	resp, err := m.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("meilisearch %s %s: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var apiErr struct {
+			Message string `json:"message"`
+			Code    string `json:"code"`
+		}
+		_ = json.NewDecoder(io.LimitReader(resp.Body, 4096)).Decode(&apiErr)
+		return fmt.Errorf("meilisearch %s %s: status %d: %s (%s)", method, path, resp.StatusCode, apiErr.Message, apiErr.Code)
+	}
+
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("failed to decode meilisearch response: %w", err)
+		}
+	}
+	return nil
+}
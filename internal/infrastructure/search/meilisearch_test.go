@@ -0,0 +1,118 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"Aicon-assignment/internal/domain/entity"
+	"Aicon-assignment/internal/pkg/reqctx"
+)
+
+type recordedRequest struct {
+	Method string
+	Path   string
+	Auth   string
+	Body   string
+}
+
+func newTestIndex(t *testing.T, status int, response string) (*MeilisearchIndex, *[]recordedRequest) {
+	var requests []recordedRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, recordedRequest{
+			Method: r.Method,
+			Path:   r.URL.RequestURI(),
+			Auth:   r.Header.Get("Authorization"),
+			Body:   string(body),
+		})
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(response))
+	}))
+	t.Cleanup(server.Close)
+	return NewMeilisearchIndex(server.URL+"/", "master-key", "items"), &requests
+}
+
+func TestMeilisearchIndex_Index(t *testing.T) {
+	index, requests := newTestIndex(t, http.StatusAccepted, `{"taskUid":1}`)
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	err := index.Index(context.Background(), []*entity.Item{
+		{ID: 1, Name: "ロレックス デイトナ", Category: "時計", Brand: "ROLEX", PurchasePrice: 1500000, PurchaseDate: "2023-01-15", CreatedAt: createdAt, UpdatedAt: createdAt},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, *requests, 1)
+	req := (*requests)[0]
+	assert.Equal(t, http.MethodPost, req.Method)
+	assert.Equal(t, "/indexes/items/documents?primaryKey=id", req.Path)
+	assert.Equal(t, "Bearer master-key", req.Auth)
+
+	var docs []map[string]any
+	require.NoError(t, json.Unmarshal([]byte(req.Body), &docs))
+	require.Len(t, docs, 1)
+	assert.Equal(t, "ROLEX", docs[0]["brand"])
+	assert.Equal(t, float64(createdAt.UnixMilli()), docs[0]["created_at_ms"])
+}
+
+func TestMeilisearchIndex_Remove(t *testing.T) {
+	index, requests := newTestIndex(t, http.StatusAccepted, `{"taskUid":2}`)
+
+	require.NoError(t, index.Remove(context.Background(), []int64{3, 5}))
+	require.Len(t, *requests, 1)
+	assert.Equal(t, "/indexes/items/documents/delete-batch", (*requests)[0].Path)
+	assert.JSONEq(t, `[3,5]`, (*requests)[0].Body)
+}
+
+func TestMeilisearchIndex_Ping(t *testing.T) {
+	t.Run("正常系: インデックスを参照できる", func(t *testing.T) {
+		index, requests := newTestIndex(t, http.StatusOK, `{"uid":"items"}`)
+
+		require.NoError(t, index.Ping(context.Background()))
+		assert.Equal(t, "/indexes/items", (*requests)[0].Path)
+	})
+
+	t.Run("異常系: インデックスがない", func(t *testing.T) {
+		index, _ := newTestIndex(t, http.StatusNotFound, `{"message":"Index items not found.","code":"index_not_found"}`)
+
+		assert.ErrorContains(t, index.Ping(context.Background()), "index_not_found")
+	})
+}
+
+func TestMeilisearchIndex_Search(t *testing.T) {
+	t.Run("正常系: ヒットしたドキュメントをアイテムとして返す", func(t *testing.T) {
+		index, requests := newTestIndex(t, http.StatusOK, `{"hits":[{"id":2,"name":"バーキン","brand":"HERMES","created_at":"2024-01-01T00:00:00Z","created_at_ms":1704067200000}]}`)
+
+		items, err := index.Search(context.Background(), entity.ItemSearch{Keyword: "herm", Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, items, 1)
+		assert.Equal(t, int64(2), items[0].ID)
+		assert.Equal(t, "HERMES", items[0].Brand)
+
+		assert.Equal(t, "/indexes/items/search", (*requests)[0].Path)
+		assert.JSONEq(t, `{"q":"herm","limit":10,"sort":["created_at_ms:desc","id:desc"]}`, (*requests)[0].Body)
+	})
+
+	t.Run("正常系: ユーザーのいるコンテキストでは持ち主で絞り込む", func(t *testing.T) {
+		index, requests := newTestIndex(t, http.StatusOK, `{"hits":[]}`)
+
+		_, err := index.Search(reqctx.WithUserID(context.Background(), 7), entity.ItemSearch{Keyword: "herm", Limit: 10})
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"q":"herm","limit":10,"sort":["created_at_ms:desc","id:desc"],"filter":"owner_id = 7"}`, (*requests)[0].Body)
+	})
+
+	t.Run("異常系: Meilisearch のエラーを返す", func(t *testing.T) {
+		index, _ := newTestIndex(t, http.StatusBadRequest, `{"message":"Attribute created_at_ms is not sortable.","code":"invalid_search_sort"}`)
+
+		_, err := index.Search(context.Background(), entity.ItemSearch{Keyword: "herm", Limit: 10})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid_search_sort")
+	})
+}
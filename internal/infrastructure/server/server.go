@@ -3,20 +3,68 @@ package server
 import (
 	"context"
 	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/labstack/echo/v4"
 
+	"Aicon-assignment/internal/domain/entity"
+	"Aicon-assignment/internal/infrastructure/config"
+	"Aicon-assignment/internal/infrastructure/container"
 	databaseInfra "Aicon-assignment/internal/infrastructure/database"
-	itemController "Aicon-assignment/internal/interfaces/controller/items"
-	"Aicon-assignment/internal/interfaces/controller/system"
-	itemDatabase "Aicon-assignment/internal/interfaces/database"
-	"Aicon-assignment/internal/usecase"
+	"Aicon-assignment/internal/infrastructure/logfile"
+	"Aicon-assignment/internal/infrastructure/scheduler"
+	"Aicon-assignment/internal/interfaces/controller/docs"
+	replicationController "Aicon-assignment/internal/interfaces/controller/replication"
+	scimController "Aicon-assignment/internal/interfaces/controller/scim"
+	systemController "Aicon-assignment/internal/interfaces/controller/system"
+	"Aicon-assignment/internal/interfaces/database"
+	appMiddleware "Aicon-assignment/internal/interfaces/middleware"
 )
 
+// 読み取り専用モードを切り替えるエンドポイント
+const readOnlyPath = "/admin/read-only"
+
+// ログイン・トークンの交換・ログアウトのエンドポイント
+// 書き込むのはリフレッシュトークンだけのため、読み取り専用モードの間もログインを続けられるよう受け付ける
+const (
+	loginPath   = "/auth/login"
+	refreshPath = "/auth/refresh"
+	logoutPath  = "/auth/logout"
+)
+
+// 評価額の試算。何も保存しないため、読み取り専用モードの間も受け付ける
+const scenariosPath = "/reports/scenarios"
+
+// Grafana の JSON データソース。POST で問い合わせるが何も保存しないため、読み取り専用モードの間も受け付ける
+const (
+	grafanaSearchPath      = "/grafana/search"
+	grafanaMetricsPath     = "/grafana/metrics"
+	grafanaQueryPath       = "/grafana/query"
+	grafanaAnnotationsPath = "/grafana/annotations"
+)
+
+// GraphQL。参照にも POST を使うため、読み取り専用モードはハンドラーで更新（mutation）だけを拒否する
+const graphqlPath = "/graphql"
+
+// 試しの通知。何も保存しないため、読み取り専用モードの間も受け付ける
+const notificationTestPath = "/notifications/test"
+
+// ヘルスチェックとメトリクス。レート制限・リクエストログの対象にしない
+const (
+	healthPath    = "/health"
+	livenessPath  = "/healthz"
+	readinessPath = "/readyz"
+	metricsPath   = "/metrics"
+)
+
+var probePaths = []string{healthPath, livenessPath, readinessPath, metricsPath}
+
 // サーバー用の構造体
 type Server struct{}
 
@@ -26,65 +74,652 @@ func NewServer() *Server {
 
 // サーバー起動
 func (s *Server) Run(ctx context.Context) error {
+	if err := configureLogger(); err != nil {
+		return err
+	}
+
 	e := echo.New()
 
 	// 依存性注入
-	dbHandler := databaseInfra.NewSqlHandler()
-	defer dbHandler.Close()
+	deps, err := container.New(config.AppEnv)
+	if err != nil {
+		return fmt.Errorf("failed to build container: %w", err)
+	}
+	// リクエストとバックグラウンドの処理が止まった後に DB の接続などを閉じる
+	defer func() {
+		if err := deps.Close(); err != nil {
+			slog.Error("failed to close dependencies", "error", err)
+		}
+	}()
+
+	// アクセスログはアプリケーションのログとは別に書き出す。遅延を正しく測るため最初に通す
+	if config.AccessLog != "" {
+		w, closeLog, err := openAccessLog()
+		if err != nil {
+			return err
+		}
+		defer closeLog()
+		e.Use(appMiddleware.AccessLog(w, appMiddleware.AccessLogConfig{
+			Format:     config.AccessLogFormat,
+			SampleRate: config.AccessLogSampleRate,
+			Redact:     config.AccessLogRedact,
+		}))
+	}
 
-	itemRepo := &itemDatabase.ItemRepository{
-		SqlHandler: dbHandler,
+	// 許可したオリジンのブラウザからのアクセスを受け付ける。プリフライトは認証より前に応答する
+	if len(config.CORSAllowedOrigins) > 0 {
+		e.Use(appMiddleware.CORS(config.CORSAllowedOrigins))
 	}
 
-	itemUsecase := usecase.NewItemUsecase(itemRepo)
+	// クライアントから見た応答を SLO の追跡に記録する
+	e.Use(appMiddleware.SLO(deps.SLOUsecase))
 
-	systemHandler := system.NewSystemHandler()
-	itemHandler := itemController.NewItemHandler(itemUsecase)
+	// リクエストIDを振り、このリクエストのログ（ユースケース・リポジトリのものも含む）に付ける
+	e.Use(appMiddleware.RequestContext(slog.Default()))
+	e.Use(appMiddleware.RequestLog(probePaths...))
+
+	// 開発・ステージングでは設定したルートに遅延・500・DB の接続断を注入する
+	if config.ChaosRules != "" {
+		if !config.ChaosAllowed() {
+			slog.Warn("CHAOS_RULES is ignored outside development and staging", "app_env", config.AppEnv)
+		} else {
+			rules, err := appMiddleware.ParseChaosRules(config.ChaosRules)
+			if err != nil {
+				return fmt.Errorf("invalid CHAOS_RULES: %w", err)
+			}
+			slog.Warn("fault injection enabled", "rules", rules)
+			e.Use(appMiddleware.Chaos(rules, nil))
+		}
+	}
+
+	// クライアントごとのリクエスト数を制限する。ヘルスチェックとメトリクスの収集は数えない
+	if config.RateLimitRequests > 0 {
+		by, err := appMiddleware.ParseRateLimitBy(config.RateLimitBy)
+		if err != nil {
+			return fmt.Errorf("invalid RATE_LIMIT_BY: %w", err)
+		}
+		e.Use(appMiddleware.RateLimit(appMiddleware.RateLimitConfig{
+			Requests:   config.RateLimitRequests,
+			Window:     config.RateLimitWindow,
+			By:         by,
+			TrustProxy: config.RateLimitTrustProxy,
+			Clock:      deps.Clock,
+		}, probePaths...))
+	}
+
+	// 最低バージョンより古いアプリを 426 で拒否し、アップデートを促す
+	if config.ClientMinVersions != "" {
+		minimums, err := appMiddleware.ParseClientMinVersions(config.ClientMinVersions)
+		if err != nil {
+			return fmt.Errorf("invalid CLIENT_MIN_VERSIONS: %w", err)
+		}
+		e.Use(appMiddleware.ClientVersionGate(minimums))
+	}
+
+	// スキーマとの互換性がない場合は起動しないか、設定に応じて参照のみ受け付ける
+	if err := deps.CheckSchema(ctx); err != nil {
+		if !databaseInfra.IsSchemaIncompatible(err) || config.SchemaIncompatibleMode != config.SchemaModeReadOnly {
+			return fmt.Errorf("refusing to start: %w", err)
+		}
+		deps.ReadOnly.Force(err.Error())
+	}
+	// 任意の列がない古いスキーマでも起動し、その列を使う機能だけを使えなくする
+	if missing := deps.SchemaColumns.Missing(); len(missing) > 0 {
+		slog.Warn("optional columns are missing; features using them are disabled until the migration is applied", "columns", database.JoinColumns(missing))
+	}
+	// アイテムに指定できるカテゴリーを DB から読み込む。読み込めない場合は既定のカテゴリーを使う
+	if err := deps.ReferenceUsecase.LoadCategories(ctx); err != nil {
+		slog.Warn("failed to load categories; using the default categories", "error", err)
+	}
+	// スタンバイのデータは送信元の変更ストリームだけで更新する
+	if deps.ReplicaUsecase != nil {
+		deps.ReadOnly.Force("standby replica of " + config.ReplicationSourceURL)
+	}
+	if status := deps.ReadOnly.Status(); status.Enabled {
+		slog.Warn("starting in read-only mode", "reason", status.Reason)
+	}
+
+	// 読み取り専用モードの間は書き込みを拒否する。モードの切り替えとログインだけは常に受け付ける
+	e.Use(deps.ReadOnly.Middleware(readOnlyPath, loginPath, refreshPath, logoutPath, scenariosPath,
+		grafanaSearchPath, grafanaMetricsPath, grafanaQueryPath, grafanaAnnotationsPath, graphqlPath,
+		notificationTestPath))
+
+	// 署名付きの書き込みリクエストを検証し、再送されたものを拒否する
+	if config.SigningKeys != "" {
+		keys, err := appMiddleware.ParseSigningKeys(config.SigningKeys)
+		if err != nil {
+			return fmt.Errorf("invalid SIGNING_KEYS: %w", err)
+		}
+		e.Use(appMiddleware.VerifySignature(appMiddleware.SignatureConfig{
+			Keys:      keys,
+			ClockSkew: config.SignatureClockSkew,
+			Cache:     deps.ReplayCache,
+			Clock:     deps.Clock,
+		}))
+	}
+
+	// 呼び出し元のユーザー（なりすまし中は管理者も）をコンテキストに格納する
+	if deps.AuthUsecase == nil {
+		slog.Warn("JWT_SECRET is not set; callers are identified by X-User-ID and /items is not protected")
+	}
+	e.Use(appMiddleware.Identity(deps.UserUsecase, deps.ImpersonationUsecase, deps.AuthUsecase, deps.APIKeyUsecase, config.Development()))
+
+	// 警告をエラーにする strict モードのリクエストかを判定する
+	e.Use(appMiddleware.StrictMode(func() []int64 { return config.Current().StrictModeOrgs }))
+	e.Binder = &appMiddleware.StrictBinder{}
+
+	// 廃止予定のルートにヘッダーを付け、まだ呼び出しているユーザーを記録する
+	e.Use(appMiddleware.Deprecation(deps.DeprecationUsecase))
+
+	systemHandler := deps.SystemHandler
+	itemHandler := deps.ItemHandler
+	webhookHandler := deps.WebhookHandler
+	retentionHandler := deps.RetentionHandler
+	impersonationHandler := deps.ImpersonationHandler
+	scimHandler := deps.SCIMHandler
+	userHandler := deps.UserHandler
+	organizationHandler := deps.OrganizationHandler
+	tenantHandler := deps.TenantHandler
+	deprecationHandler := deps.DeprecationHandler
+	exportHandler := deps.ExportHandler
+	attachmentHandler := deps.AttachmentHandler
+	imageHandler := deps.ImageHandler
+	quarantineHandler := deps.QuarantineHandler
+	importProfileHandler := deps.ImportProfileHandler
+	verificationHandler := deps.VerificationHandler
+	notificationHandler := deps.NotificationHandler
+	replicationHandler := deps.ReplicationHandler
+	eventConsumerHandler := deps.EventConsumerHandler
+	referenceHandler := deps.ReferenceHandler
+	reportHandler := deps.ReportHandler
+	grafanaHandler := deps.GrafanaHandler
+
+	// 保持期間を過ぎたデータを定期的に削除する
+	jobCtx, stopJobs := context.WithCancel(ctx)
+	defer stopJobs()
+	if config.RetentionInterval > 0 {
+		go scheduler.Every(jobCtx, config.RetentionInterval, "retention", func(ctx context.Context) error {
+			if deps.ReadOnly.Status().Enabled {
+				slog.Info("retention skipped in read-only mode")
+				return nil
+			}
+			_, err := deps.RetentionUsecase.Run(ctx, false)
+			return err
+		})
+	}
+
+	// 期限を過ぎたファイル（エクスポートなど）を定期的に削除する
+	if config.RetentionInterval > 0 {
+		go scheduler.Every(jobCtx, config.RetentionInterval, "blob-expiry", func(ctx context.Context) error {
+			if deps.ReadOnly.Status().Enabled {
+				return nil
+			}
+			_, err := deps.BlobUsecase.DeleteExpired(ctx)
+			return err
+		})
+	}
+
+	// 参照のなくなった添付ファイルの中身を定期的に削除する
+	if config.RetentionInterval > 0 {
+		go scheduler.Every(jobCtx, config.RetentionInterval, "attachment-gc", func(ctx context.Context) error {
+			if deps.ReadOnly.Status().Enabled {
+				return nil
+			}
+			_, err := deps.AttachmentUsecase.CollectGarbage(ctx)
+			return err
+		})
+	}
+
+	// 期限までに確定しなかった取り込みの置き場を定期的に破棄する
+	if config.RetentionInterval > 0 {
+		go scheduler.Every(jobCtx, config.RetentionInterval, "import-staging-expiry", func(ctx context.Context) error {
+			if deps.ReadOnly.Status().Enabled {
+				return nil
+			}
+			_, err := deps.ImportStagingUsecase.PurgeExpired(ctx)
+			return err
+		})
+	}
+
+	// エラーバジェットの消費が速すぎる SLO を通知する
+	if config.SLOObjectives != "" && config.SLOCheckInterval > 0 {
+		go scheduler.Every(jobCtx, config.SLOCheckInterval, "slo", deps.SLOUsecase.CheckBurnRates)
+	}
+
+	// スタンバイでは送信元の変更を取り込み続ける
+	if deps.ReplicaUsecase != nil {
+		go scheduler.Every(jobCtx, config.ReplicationPollInterval, "replication", func(ctx context.Context) error {
+			_, err := deps.ReplicaUsecase.Sync(ctx)
+			return err
+		})
+	}
+
+	// 他のインスタンスで変更したカテゴリーを取り込む
+	if config.ReferenceRefreshInterval > 0 {
+		go scheduler.Every(jobCtx, config.ReferenceRefreshInterval, "reference-refresh", deps.ReferenceUsecase.LoadCategories)
+	}
+
+	// ポートフォリオ全体の価値を日ごとに記録する
+	if config.PortfolioSnapshotInterval > 0 {
+		go scheduler.Every(jobCtx, config.PortfolioSnapshotInterval, "portfolio-snapshot", func(ctx context.Context) error {
+			if deps.ReadOnly.Status().Enabled {
+				return nil
+			}
+			_, err := deps.PortfolioUsecase.RecordSnapshot(ctx)
+			return err
+		})
+	}
+
+	// サンドボックスのデータを毎日最初の状態に戻す
+	if deps.SandboxItems != nil {
+		resetAt, err := scheduler.ParseTimeOfDay(config.SandboxResetAt)
+		if err != nil {
+			return fmt.Errorf("invalid SANDBOX_RESET_AT: %w", err)
+		}
+		go scheduler.Daily(jobCtx, resetAt, "sandbox-reset", func(ctx context.Context) error {
+			slog.Info("sandbox datasets reset", "datasets", deps.SandboxItems.Reset())
+			return nil
+		})
+	}
+
+	// SIGHUP で設定を読み込み直す
+	go reloadOnSignal(jobCtx)
 
 	// ヘルスチェック
-	e.GET("/health", func(c echo.Context) error {
+	e.GET(healthPath, func(c echo.Context) error {
 		systemHandler.Health(c)
 		return nil
 	})
+	e.GET(livenessPath, systemHandler.Liveness)   // GET /healthz
+	e.GET(readinessPath, systemHandler.Readiness) // GET /readyz
+
+	// Prometheus 向けのメトリクス
+	e.GET(metricsPath, systemHandler.Metrics)
+
+	// クライアントが合わせるためのサーバーの上限と、使える機能
+	e.GET("/meta/limits", systemHandler.GetLimits)
+	e.GET("/meta/capabilities", systemHandler.GetCapabilities)
+
+	// アイテムと集計の API の仕様（OpenAPI 3）と、それを閲覧する Swagger UI
+	e.GET(docs.SpecPath, deps.DocsHandler.Spec) // GET /openapi.json
+	e.GET("/docs", deps.DocsHandler.SwaggerUI)  // GET /docs
+
+	// アカウントの作成・ログイン・トークンの交換・ログアウト・パスワードの変更と再設定
+	if deps.AuthHandler != nil {
+		e.POST(loginPath, deps.AuthHandler.Login)
+		e.POST(refreshPath, deps.AuthHandler.Refresh)
+		e.POST(logoutPath, deps.AuthHandler.Logout)
+		if config.RegistrationEnabled {
+			e.POST("/auth/register", deps.AuthHandler.Register)
+		}
+		e.PUT("/auth/password", deps.AuthHandler.ChangePassword, appMiddleware.RequireUser())
+		e.POST("/auth/password/forgot", deps.PasswordResetHandler.Forgot)
+		e.POST("/auth/password/reset", deps.PasswordResetHandler.Reset)
+	}
+
+	// 認証アプリ（TOTP）による二要素認証の登録・解除
+	if deps.TwoFactorHandler != nil {
+		twoFactorGroup := e.Group("/auth/2fa", appMiddleware.RequireUser())
+		{
+			twoFactorGroup.GET("", deps.TwoFactorHandler.Status)                                  // GET /auth/2fa
+			twoFactorGroup.POST("/enroll", deps.TwoFactorHandler.Enroll)                          // POST /auth/2fa/enroll
+			twoFactorGroup.POST("/confirm", deps.TwoFactorHandler.Confirm)                        // POST /auth/2fa/confirm
+			twoFactorGroup.POST("/recovery-codes", deps.TwoFactorHandler.RegenerateRecoveryCodes) // POST /auth/2fa/recovery-codes
+			twoFactorGroup.POST("/disable", deps.TwoFactorHandler.Disable)                        // POST /auth/2fa/disable
+		}
+	}
 
-	// アイテムに関するエンドポイント
+	// Google / GitHub でのログインと、ログイン中のユーザーへの ID の紐付け
+	if deps.OIDCHandler != nil {
+		oidcGroup := e.Group("/auth/oidc")
+		{
+			oidcGroup.GET("/providers", deps.OIDCHandler.Providers)                                   // GET /auth/oidc/providers
+			oidcGroup.GET("/login", deps.OIDCHandler.Login)                                           // GET /auth/oidc/login?provider=google
+			oidcGroup.GET("/callback", deps.OIDCHandler.Callback)                                     // GET /auth/oidc/callback
+			oidcGroup.POST("/link", deps.OIDCHandler.Link, appMiddleware.RequireUser())               // POST /auth/oidc/link
+			oidcGroup.GET("/identities", deps.OIDCHandler.Identities, appMiddleware.RequireUser())    // GET /auth/oidc/identities
+			oidcGroup.DELETE("/identities/:id", deps.OIDCHandler.Unlink, appMiddleware.RequireUser()) // DELETE /auth/oidc/identities/{id}
+		}
+	}
+
+	// 機械向けのクライアントが X-API-Key で使う API キー
+	apiKeyGroup := e.Group("/auth/apikeys", appMiddleware.RequireUser())
+	{
+		apiKeyGroup.POST("", deps.APIKeyHandler.Create)       // POST /auth/apikeys
+		apiKeyGroup.GET("", deps.APIKeyHandler.List)          // GET /auth/apikeys
+		apiKeyGroup.DELETE("/:id", deps.APIKeyHandler.Revoke) // DELETE /auth/apikeys/{id}
+	}
+
+	// 連携先の開発者向けのサンドボックス。キーの発行には本番のログインが必要で、
+	// /sandbox は発行したキー（X-API-Key）だけで使え、キーごとの架空のデータを扱う
+	if deps.SandboxKeyHandler != nil {
+		sandboxKeyGroup := e.Group("/auth/sandbox-keys", appMiddleware.RequireUser())
+		{
+			sandboxKeyGroup.POST("", deps.SandboxKeyHandler.Create)       // POST /auth/sandbox-keys
+			sandboxKeyGroup.GET("", deps.SandboxKeyHandler.List)          // GET /auth/sandbox-keys
+			sandboxKeyGroup.DELETE("/:id", deps.SandboxKeyHandler.Revoke) // DELETE /auth/sandbox-keys/{id}
+		}
+
+		sandboxItemHandler := deps.SandboxItemHandler
+		sandboxGroup := e.Group("/sandbox/items", appMiddleware.SandboxIdentity(deps.SandboxKeyUsecase))
+		{
+			sandboxGroup.GET("", sandboxItemHandler.GetItems)                          // GET /sandbox/items
+			sandboxGroup.POST("", sandboxItemHandler.CreateItem)                       // POST /sandbox/items
+			sandboxGroup.POST("/bulk", sandboxItemHandler.CreateItems)                 // POST /sandbox/items/bulk
+			sandboxGroup.PATCH("/bulk", sandboxItemHandler.UpdateItems)                // PATCH /sandbox/items/bulk
+			sandboxGroup.GET("/summary", sandboxItemHandler.GetSummary)                // GET /sandbox/items/summary
+			sandboxGroup.GET("/search", sandboxItemHandler.SearchItems)                // GET /sandbox/items/search
+			sandboxGroup.GET("/:id", sandboxItemHandler.GetItem)                       // GET /sandbox/items/{id}
+			sandboxGroup.PATCH("/:id", sandboxItemHandler.UpdateItem)                  // PATCH /sandbox/items/{id}
+			sandboxGroup.DELETE("/:id", sandboxItemHandler.DeleteItem)                 // DELETE /sandbox/items/{id}
+			sandboxGroup.GET("/:id/price-history", sandboxItemHandler.GetPriceHistory) // GET /sandbox/items/{id}/price-history
+		}
+	}
+
+	// 一括削除・完全削除と運用者向けのエンドポイントは管理者だけが使える
+	requireAdmin := appMiddleware.RequireRole(entity.UserRoleAdmin)
+	// アイテムの検証（サインオフ）は鑑定士だけができる
+	requireAppraiser := appMiddleware.RequireRole(entity.UserRoleAppraiser)
+
+	// アイテムに関するエンドポイント。認証を設定している場合はアクセストークンが必須
 	itemsGroup := e.Group("/items")
+	if deps.AuthUsecase != nil {
+		itemsGroup.Use(appMiddleware.RequireUser())
+	}
+	{
+		itemsGroup.GET("", itemHandler.GetItems)                             // GET /items
+		itemsGroup.POST("", itemHandler.CreateItem)                          // POST /items
+		itemsGroup.POST("/bulk", itemHandler.CreateItems)                    // POST /items/bulk
+		itemsGroup.PATCH("/bulk", itemHandler.UpdateItems)                   // PATCH /items/bulk
+		itemsGroup.POST("/import", itemHandler.ImportItems)                  // POST /items/import
+		itemsGroup.GET("/import/template", itemHandler.ImportTemplate)       // GET /items/import/template
+		itemsGroup.DELETE("", itemHandler.DeleteItems, requireAdmin)         // DELETE /items?ids=1,2,3
+		itemsGroup.GET("/:id", itemHandler.GetItem)                          // GET /items/{id}
+		itemsGroup.PATCH("/:id", itemHandler.UpdateItem)                     // PATCH /items/{id}
+		itemsGroup.DELETE("/:id", itemHandler.DeleteItem)                    // DELETE /items/{id}
+		itemsGroup.DELETE("/:id/purge", itemHandler.PurgeItem, requireAdmin) // DELETE /items/{id}/purge
+		itemsGroup.GET("/summary", itemHandler.GetSummary)                   // GET /items/summary (bonus)
+		itemsGroup.GET("/search", itemHandler.SearchItems)                   // GET /items/search
+		itemsGroup.GET("/export", itemHandler.ExportItems)                   // GET /items/export?format=csv
+		itemsGroup.GET("/:id/price-history", itemHandler.GetPriceHistory)    // GET /items/{id}/price-history
+		itemsGroup.GET("/:id/audit-log", itemHandler.GetAuditLog)            // GET /items/{id}/audit-log
+		itemsGroup.POST("/:id/merge", itemHandler.MergeItem)                 // POST /items/{id}/merge
+		itemsGroup.POST("/:id/split", itemHandler.SplitItem)                 // POST /items/{id}/split
+
+		itemsGroup.POST("/:id/attachments", attachmentHandler.Upload)                           // POST /items/{id}/attachments
+		itemsGroup.GET("/:id/attachments", attachmentHandler.List)                              // GET /items/{id}/attachments
+		itemsGroup.GET("/:id/attachments/:attachmentId", attachmentHandler.Download)            // GET /items/{id}/attachments/{attachmentId}
+		itemsGroup.DELETE("/:id/attachments/:attachmentId", attachmentHandler.Delete)           // DELETE /items/{id}/attachments/{attachmentId}
+		itemsGroup.POST("/:id/receipt", attachmentHandler.UploadReceipt)                        // POST /items/{id}/receipt
+		itemsGroup.GET("/:id/receipt", attachmentHandler.DownloadReceipt)                       // GET /items/{id}/receipt
+		itemsGroup.DELETE("/:id/receipt", attachmentHandler.DeleteReceipt)                      // DELETE /items/{id}/receipt
+		itemsGroup.POST("/:id/images", imageHandler.Upload)                                     // POST /items/{id}/images
+		itemsGroup.GET("/:id/images", imageHandler.List)                                        // GET /items/{id}/images
+		itemsGroup.GET("/:id/images/:imageId", imageHandler.Download)                           // GET /items/{id}/images/{imageId}
+		itemsGroup.PATCH("/:id/images/:imageId", imageHandler.Update)                           // PATCH /items/{id}/images/{imageId}
+		itemsGroup.DELETE("/:id/images/:imageId", imageHandler.Delete)                          // DELETE /items/{id}/images/{imageId}
+		itemsGroup.GET("/:id/images/:imageId/thumbnails/:size", imageHandler.DownloadThumbnail) // GET /items/{id}/images/{imageId}/thumbnails/{size}
+
+		itemsGroup.GET("/:id/verification", verificationHandler.Get)                                 // GET /items/{id}/verification
+		itemsGroup.POST("/:id/verification/start", verificationHandler.Start, requireAppraiser)      // POST /items/{id}/verification/start
+		itemsGroup.POST("/:id/verification/sign-off", verificationHandler.SignOff, requireAppraiser) // POST /items/{id}/verification/sign-off
+		itemsGroup.POST("/:id/verification/reopen", verificationHandler.Reopen, requireAdmin)        // POST /items/{id}/verification/reopen
+	}
+
+	// /items と同じ ItemUsecase を GraphQL で公開する。認証は /items と同じ
+	graphqlGroup := e.Group(graphqlPath)
+	if deps.AuthUsecase != nil {
+		graphqlGroup.Use(appMiddleware.RequireUser())
+	}
+	{
+		graphqlGroup.POST("", deps.GraphQLHandler.Query)        // POST /graphql
+		graphqlGroup.GET("", deps.GraphQLHandler.Query)         // GET /graphql?query=...
+		graphqlGroup.GET("/schema", deps.GraphQLHandler.Schema) // GET /graphql/schema
+	}
+
+	// 仕入れ先ごとの取り込みのプロファイル。ユーザーごとに保存するため、ログインが必要
+	importProfileGroup := e.Group("/import-profiles", appMiddleware.RequireUser())
+	{
+		importProfileGroup.GET("", importProfileHandler.ListImportProfiles)         // GET /import-profiles
+		importProfileGroup.POST("", importProfileHandler.CreateImportProfile)       // POST /import-profiles
+		importProfileGroup.GET("/:id", importProfileHandler.GetImportProfile)       // GET /import-profiles/{id}
+		importProfileGroup.PATCH("/:id", importProfileHandler.UpdateImportProfile)  // PATCH /import-profiles/{id}
+		importProfileGroup.DELETE("/:id", importProfileHandler.DeleteImportProfile) // DELETE /import-profiles/{id}
+	}
+
+	// 通知の受け取り方。ユーザーごとに保存するため、ログインが必要
+	notificationGroup := e.Group("/notifications", appMiddleware.RequireUser())
+	{
+		notificationGroup.GET("/preferences", notificationHandler.GetPreference)    // GET /notifications/preferences
+		notificationGroup.PUT("/preferences", notificationHandler.UpdatePreference) // PUT /notifications/preferences
+		notificationGroup.POST("/test", notificationHandler.SendTest)               // POST /notifications/test
+	}
+
+	// 取り込みの置き場。行を確認・編集してから確定し、アイテムとしてまとめて登録する
+	importGroup := e.Group("/imports", appMiddleware.RequireUser())
+	{
+		importGroup.POST("", itemHandler.StageImport)                     // POST /imports
+		importGroup.GET("/:id", itemHandler.GetImport)                    // GET /imports/{id}
+		importGroup.DELETE("/:id", itemHandler.DiscardImport)             // DELETE /imports/{id}
+		importGroup.GET("/:id/rows", itemHandler.GetImportRows)           // GET /imports/{id}/rows
+		importGroup.PATCH("/:id/rows/:row", itemHandler.UpdateImportRow)  // PATCH /imports/{id}/rows/{row}
+		importGroup.DELETE("/:id/rows/:row", itemHandler.DeleteImportRow) // DELETE /imports/{id}/rows/{row}
+		importGroup.POST("/:id/commit", itemHandler.CommitImport)         // POST /imports/{id}/commit
+	}
+
+	// データ確認用のレポート。認証は /items と同じ
+	// 推移と試算はすべての持ち主のアイテムを集計するため、管理者だけが使える
+	reportsGroup := e.Group("/reports")
+	if deps.AuthUsecase != nil {
+		reportsGroup.Use(appMiddleware.RequireUser())
+	}
+	{
+		reportsGroup.GET("/outliers", itemHandler.GetOutlierReport)                          // GET /reports/outliers
+		reportsGroup.GET("/portfolio-history", reportHandler.PortfolioHistory, requireAdmin) // GET /reports/portfolio-history?range=1y
+		reportsGroup.POST("/scenarios", reportHandler.RunScenario, requireAdmin)             // POST /reports/scenarios
+	}
+
+	// Grafana の JSON データソース（統計・推移のダッシュボード）。認証は /items と同じ
+	// ポートフォリオの指標はすべての持ち主のアイテムを集計するため、管理者だけに返す
+	grafanaGroup := e.Group("/grafana")
+	if deps.AuthUsecase != nil {
+		grafanaGroup.Use(appMiddleware.RequireUser())
+	}
 	{
-		itemsGroup.GET("", itemHandler.GetItems)           // GET /items
-		itemsGroup.POST("", itemHandler.CreateItem)        // POST /items
-		itemsGroup.GET("/:id", itemHandler.GetItem)        // GET /items/{id}
-		itemsGroup.PATCH("/:id", itemHandler.UpdateItem)   // PATCH /items/{id}
-		itemsGroup.DELETE("/:id", itemHandler.DeleteItem)  // DELETE /items/{id}
-		itemsGroup.GET("/summary", itemHandler.GetSummary) // GET /items/summary (bonus)
+		grafanaGroup.GET("", grafanaHandler.Health)                   // GET /grafana
+		grafanaGroup.POST("/search", grafanaHandler.Search)           // POST /grafana/search
+		grafanaGroup.POST("/metrics", grafanaHandler.Metrics)         // POST /grafana/metrics
+		grafanaGroup.POST("/query", grafanaHandler.Query)             // POST /grafana/query
+		grafanaGroup.POST("/annotations", grafanaHandler.Annotations) // POST /grafana/annotations
 	}
 
-	return s.startWithGracefulShutdown(ctx, e)
+	// Webhookに関するエンドポイント。すべての持ち主のアイテムのイベントを受け取るため管理者だけが登録でき、
+	// 各管理者は自分の登録した Webhook だけを扱える
+	webhooksGroup := e.Group("/webhooks", requireAdmin)
+	{
+		webhooksGroup.GET("", webhookHandler.ListWebhooks)                                    // GET /webhooks
+		webhooksGroup.POST("", webhookHandler.CreateWebhook)                                  // POST /webhooks
+		webhooksGroup.GET("/:id", webhookHandler.GetWebhook)                                  // GET /webhooks/{id}
+		webhooksGroup.PATCH("/:id", webhookHandler.UpdateWebhook)                             // PATCH /webhooks/{id}
+		webhooksGroup.DELETE("/:id", webhookHandler.DeleteWebhook)                            // DELETE /webhooks/{id}
+		webhooksGroup.POST("/:id/test", webhookHandler.TestWebhook)                           // POST /webhooks/{id}/test
+		webhooksGroup.GET("/:id/deliveries", webhookHandler.ListDeliveries)                   // GET /webhooks/{id}/deliveries
+		webhooksGroup.GET("/:id/deliveries/stats", webhookHandler.GetDeliveryStats)           // GET /webhooks/{id}/deliveries/stats
+		webhooksGroup.POST("/:id/deliveries/:deliveryId/redeliver", webhookHandler.Redeliver) // POST /webhooks/{id}/deliveries/{deliveryId}/redeliver
+	}
+
+	// データ連携向けのエクスポート。/items と同じく呼び出し元のユーザーのアイテムだけを対象にする
+	e.POST("/exports", exportHandler.CreateExport, appMiddleware.RequireUser()) // POST /exports
+
+	// 運用者向けのエンドポイント
+	adminGroup := e.Group("/admin", requireAdmin)
+	{
+		adminGroup.GET("/retention-policies", retentionHandler.ListPolicies)                 // GET /admin/retention-policies
+		adminGroup.PATCH("/retention-policies/:type", retentionHandler.UpdatePolicy)         // PATCH /admin/retention-policies/{type}
+		adminGroup.GET("/retention-policies/:type/preview", retentionHandler.PreviewPolicy)  // GET /admin/retention-policies/{type}/preview
+		adminGroup.POST("/retention/run", retentionHandler.Run)                              // POST /admin/retention/run
+		adminGroup.POST("/impersonate/:userId", impersonationHandler.Start)                  // POST /admin/impersonate/{userId}
+		adminGroup.GET("/impersonations", impersonationHandler.List)                         // GET /admin/impersonations
+		adminGroup.DELETE("/impersonations/:id", impersonationHandler.End)                   // DELETE /admin/impersonations/{id}
+		adminGroup.POST("/config/reload", systemHandler.ReloadConfig)                        // POST /admin/config/reload
+		adminGroup.GET("/read-only", systemHandler.GetReadOnly)                              // GET /admin/read-only
+		adminGroup.PUT("/read-only", systemHandler.SetReadOnly)                              // PUT /admin/read-only
+		adminGroup.GET("/slo", systemHandler.GetSLOs)                                        // GET /admin/slo
+		adminGroup.GET("/deprecations", deprecationHandler.Report)                           // GET /admin/deprecations
+		adminGroup.GET("/summary", itemHandler.GetOrganizationSummary)                       // GET /admin/summary
+		adminGroup.GET("/attachments/storage", attachmentHandler.StorageReport)              // GET /admin/attachments/storage
+		adminGroup.GET("/quarantine", quarantineHandler.List)                                // GET /admin/quarantine
+		adminGroup.DELETE("/quarantine/:id", quarantineHandler.Delete)                       // DELETE /admin/quarantine/{id}
+		adminGroup.POST("/images/thumbnails/reprocess", imageHandler.ReprocessThumbnails)    // POST /admin/images/thumbnails/reprocess
+		adminGroup.GET("/replication", replicationHandler.Status)                            // GET /admin/replication
+		adminGroup.PUT("/users/:id/role", userHandler.ChangeRole)                            // PUT /admin/users/{id}/role
+		adminGroup.GET("/organizations/:id/export", tenantHandler.Export)                    // GET /admin/organizations/{id}/export
+		adminGroup.POST("/organizations/import", tenantHandler.Import)                       // POST /admin/organizations/import
+		adminGroup.GET("/reference", referenceHandler.ListTypes)                             // GET /admin/reference
+		adminGroup.GET("/reference/:type", referenceHandler.List)                            // GET /admin/reference/{type}
+		adminGroup.POST("/reference/:type", referenceHandler.Create)                         // POST /admin/reference/{type}
+		adminGroup.GET("/reference/:type/:key", referenceHandler.Get)                        // GET /admin/reference/{type}/{key}
+		adminGroup.PUT("/reference/:type/:key", referenceHandler.Update)                     // PUT /admin/reference/{type}/{key}
+		adminGroup.DELETE("/reference/:type/:key", referenceHandler.Delete)                  // DELETE /admin/reference/{type}/{key}
+		adminGroup.POST("/reference/categories/:key/reassign", itemHandler.ReassignCategory) // POST /admin/reference/categories/{key}/reassign
+		adminGroup.PUT("/items/:id/owner", itemHandler.AssignOwner)                          // PUT /admin/items/{id}/owner
+	}
+
+	// 他のリージョンのスタンバイに変更ストリームを公開する
+	if config.ReplicationToken != "" {
+		e.GET("/replication/events", replicationHandler.Events, replicationController.RequireToken(config.ReplicationToken)) // GET /replication/events
+	}
+
+	// 名前付きの消費者ごとにイベントを配信する（確認されなければ再配信する）
+	eventConsumersGroup := e.Group("/event-consumers", requireAdmin)
+	{
+		eventConsumersGroup.POST("", eventConsumerHandler.Create)          // POST /event-consumers
+		eventConsumersGroup.GET("", eventConsumerHandler.List)             // GET /event-consumers
+		eventConsumersGroup.GET("/:name", eventConsumerHandler.Get)        // GET /event-consumers/{name}
+		eventConsumersGroup.DELETE("/:name", eventConsumerHandler.Delete)  // DELETE /event-consumers/{name}
+		eventConsumersGroup.POST("/:name/poll", eventConsumerHandler.Poll) // POST /event-consumers/{name}/poll
+		eventConsumersGroup.POST("/:name/ack", eventConsumerHandler.Ack)   // POST /event-consumers/{name}/ack
+	}
+
+	// IdP からのアカウントのプロビジョニング（SCIM v2）
+	scimGroup := e.Group(container.SCIMBasePath, scimController.RequireToken(config.SCIMToken))
+	{
+		scimGroup.GET("/Users", scimHandler.ListUsers)       // GET /scim/v2/Users
+		scimGroup.POST("/Users", scimHandler.CreateUser)     // POST /scim/v2/Users
+		scimGroup.GET("/Users/:id", scimHandler.GetUser)     // GET /scim/v2/Users/{id}
+		scimGroup.PATCH("/Users/:id", scimHandler.PatchUser) // PATCH /scim/v2/Users/{id}
+	}
+
+	// 組織のメンバーと招待の管理
+	organizationsGroup := e.Group("/organizations")
+	{
+		organizationsGroup.POST("", organizationHandler.Create)                             // POST /organizations
+		organizationsGroup.GET("/:id/members", organizationHandler.ListMembers)             // GET /organizations/{id}/members
+		organizationsGroup.PATCH("/:id/members/:userId", organizationHandler.ChangeRole)    // PATCH /organizations/{id}/members/{userId}
+		organizationsGroup.DELETE("/:id/members/:userId", organizationHandler.RemoveMember) // DELETE /organizations/{id}/members/{userId}
+		organizationsGroup.POST("/:id/invitations", organizationHandler.Invite)             // POST /organizations/{id}/invitations
+		organizationsGroup.GET("/:id/invitations", organizationHandler.ListInvitations)     // GET /organizations/{id}/invitations
+		organizationsGroup.GET("/:id/branding", organizationHandler.GetBranding)            // GET /organizations/{id}/branding
+		organizationsGroup.PUT("/:id/branding", organizationHandler.UpdateBranding)         // PUT /organizations/{id}/branding
+		organizationsGroup.GET("/:id/field-locks", organizationHandler.GetFieldLocks)       // GET /organizations/{id}/field-locks
+		organizationsGroup.PUT("/:id/field-locks", organizationHandler.UpdateFieldLocks)    // PUT /organizations/{id}/field-locks
+	}
+	e.POST("/invitations/accept", organizationHandler.AcceptInvitation) // POST /invitations/accept
+
+	return s.startWithGracefulShutdown(ctx, e, systemHandler)
 }
 
-func (s *Server) startWithGracefulShutdown(ctx context.Context, e *echo.Echo) error {
+// ACCESS_LOG が "stdout" の場合は標準出力、それ以外はサイズで切り替えるファイルに書き出す
+// LOG_FORMAT を設定している場合は、アプリケーションのログを標準エラー出力に text か json で書き出す
+// レベルは LOG_LEVEL に従い、読み込み直すと変わる
+func configureLogger() error {
+	options := &slog.HandlerOptions{Level: config.LogLevel}
+	switch config.LogFormat {
+	case "":
+		return nil
+	case "text":
+		slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, options)))
+	case "json":
+		slog.SetDefault(slog.New(slog.NewJSONHandler(os.Stderr, options)))
+	default:
+		return fmt.Errorf("invalid LOG_FORMAT %q: must be text or json", config.LogFormat)
+	}
+	return nil
+}
+
+func openAccessLog() (io.Writer, func() error, error) {
+	if config.AccessLog == "stdout" {
+		return os.Stdout, func() error { return nil }, nil
+	}
+	f, err := logfile.Open(config.AccessLog, int64(config.AccessLogMaxSizeMB)<<20, config.AccessLogMaxBackups)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open access log: %w", err)
+	}
+	return f, f.Close, nil
+}
+
+// SIGHUP を受け取るたびに設定を読み込み直す。不正な値がある場合は現在の設定のまま動かす
+func reloadOnSignal(ctx context.Context) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			applied, err := config.Reload()
+			if err != nil {
+				slog.Warn("config reload rejected", "error", err)
+				continue
+			}
+			slog.Info("config reloaded", "config", applied)
+		}
+	}
+}
+
+// SIGTERM・SIGINT（または ctx の終了）で停止する。停止の流れは次のとおり
+//  1. /readyz を 503 にし、SHUTDOWN_DELAY の間はリクエストを受け付け続ける（ロードバランサーが外すのを待つ）
+//  2. 新しい接続の受け付けを止め、処理中のリクエストの完了を SHUTDOWN_TIMEOUT まで待つ
+//  3. 待ちきれなかった接続は切る。DB の接続は Run を抜けるときに閉じる
+func (s *Server) startWithGracefulShutdown(ctx context.Context, e *echo.Echo, systemHandler *systemController.SystemHandler) error {
+	port := config.Port
+	serverErr := make(chan error, 1)
 	go func() {
-		port := ":8080"
 		fmt.Printf("🚀 Server starting on port %s\n", port)
-
 		if err := e.Start(port); err != nil && err != http.ErrServerClosed {
-			e.Logger.Fatal("Server startup failed:", err)
+			serverErr <- err
 		}
 	}()
 
 	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, os.Interrupt)
+	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(quit)
 
 	select {
-	case <-quit:
-		fmt.Println("\n🛑 Shutting down server...")
+	case err := <-serverErr:
+		return fmt.Errorf("server startup failed: %w", err)
+	case sig := <-quit:
+		fmt.Printf("\n🛑 Received %s, shutting down server...\n", sig)
 	case <-ctx.Done():
 		fmt.Println("\n🛑 Context cancelled, shutting down server...")
 	}
 
-	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	systemHandler.StartDraining()
+	if config.ShutdownDelay > 0 {
+		slog.Info("waiting before closing the listener", "delay", config.ShutdownDelay)
+		time.Sleep(config.ShutdownDelay)
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), config.ShutdownTimeout)
 	defer cancel()
 
 	if err := e.Shutdown(shutdownCtx); err != nil {
+		slog.Error("in-flight requests did not finish in time, closing connections", "timeout", config.ShutdownTimeout, "error", err)
+		if closeErr := e.Close(); closeErr != nil {
+			slog.Error("failed to close server", "error", closeErr)
+		}
 		return fmt.Errorf("server forced to shutdown: %w", err)
 	}
 
@@ -0,0 +1,79 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"syscall"
+)
+
+// 送信先にできないアドレスへの接続・登録で返すエラー
+var ErrDisallowedAddress = errors.New("webhook url must not point to a loopback, private, link-local or metadata address")
+
+// RFC 6598 の共有アドレス（100.100.100.200 など一部のクラウドのメタデータもここにある）と「このネットワーク」
+var disallowedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("0.0.0.0/8"),
+}
+
+// サーバーの内側（ループバック・プライベート・リンクローカル・メタデータ）のアドレスか
+// 169.254.169.254 はリンクローカル、fd00:ec2::254 はプライベート（ULA）に含まれる
+func isDisallowed(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if addr.IsLoopback() || addr.IsPrivate() || addr.IsUnspecified() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() || addr.IsMulticast() {
+		return true
+	}
+	for _, prefix := range disallowedPrefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// 接続の直前に接続先のアドレスを確かめる。名前解決の結果が登録後に変わった場合やリダイレクトも拒否できる
+func guardDial(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return fmt.Errorf("unexpected dial address %q: %w", address, err)
+	}
+	if isDisallowed(addr) {
+		return ErrDisallowedAddress
+	}
+	return nil
+}
+
+// URL のホストを名前解決し、送信先にできないアドレスが1つでもあれば拒否する
+func checkURL(ctx context.Context, resolver *net.Resolver, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid webhook url: %w", err)
+	}
+	host := u.Hostname()
+	if addr, err := netip.ParseAddr(host); err == nil {
+		if isDisallowed(addr) {
+			return ErrDisallowedAddress
+		}
+		return nil
+	}
+
+	addrs, err := resolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return fmt.Errorf("webhook url host %q cannot be resolved", host)
+	}
+	for _, addr := range addrs {
+		if isDisallowed(addr) {
+			return ErrDisallowedAddress
+		}
+	}
+	return nil
+}
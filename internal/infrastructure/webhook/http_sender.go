@@ -0,0 +1,94 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"Aicon-assignment/internal/domain/entity"
+	"Aicon-assignment/internal/usecase"
+)
+
+// 接続を使い回すために読み捨てるレスポンスボディの上限
+const maxDrainedBody = 64 << 10
+
+// HTTP POST でWebhookを送信する
+// 送信先が返したボディは記録も返却もしない（サーバーの内側の情報を読み出す手段にさせない）
+type HTTPSender struct {
+	Client *http.Client
+
+	// true の場合はループバック・プライベートなどのアドレスにも送る（開発環境で手元の受信側を使う場合）
+	AllowPrivateNetworks bool
+	resolver             *net.Resolver
+}
+
+// allowPrivateNetworks が false の場合、サーバーの内側のアドレスへは接続しない
+func NewHTTPSender(allowPrivateNetworks bool) *HTTPSender {
+	dialer := &net.Dialer{Timeout: 5 * time.Second, KeepAlive: 30 * time.Second}
+	if !allowPrivateNetworks {
+		dialer.Control = guardDial
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+	// プロキシを経由すると接続先のアドレスを確かめられないため使わない
+	transport.Proxy = nil
+
+	return &HTTPSender{
+		Client:               &http.Client{Timeout: 10 * time.Second, Transport: transport},
+		AllowPrivateNetworks: allowPrivateNetworks,
+		resolver:             net.DefaultResolver,
+	}
+}
+
+// 登録・更新する URL の送信先を確かめる（usecase.WebhookURLChecker）
+func (s *HTTPSender) CheckURL(ctx context.Context, rawURL string) error {
+	if s.AllowPrivateNetworks {
+		return nil
+	}
+	return checkURL(ctx, s.resolver, rawURL)
+}
+
+func (s *HTTPSender) Send(ctx context.Context, webhook *entity.Webhook, payload []byte) (*usecase.WebhookResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Aicon-assignment-webhook/1.0")
+	if webhook.Secret != "" {
+		req.Header.Set("X-Webhook-Signature", "sha256="+Sign(webhook.Secret, payload))
+	}
+
+	start := time.Now()
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return &usecase.WebhookResponse{Duration: time.Since(start)}, err
+	}
+	defer resp.Body.Close()
+
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxDrainedBody))
+	result := &usecase.WebhookResponse{
+		StatusCode: resp.StatusCode,
+		Duration:   time.Since(start),
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return result, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	return result, nil
+}
+
+// ペイロードの HMAC-SHA256 署名（受信側での検証用）
+func Sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
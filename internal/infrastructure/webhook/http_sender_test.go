@@ -0,0 +1,88 @@
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"Aicon-assignment/internal/domain/entity"
+)
+
+func TestIsDisallowed(t *testing.T) {
+	tests := []struct {
+		addr       string
+		disallowed bool
+	}{
+		{"127.0.0.1", true},
+		{"::1", true},
+		{"10.0.0.5", true},
+		{"172.16.3.4", true},
+		{"192.168.1.1", true},
+		{"169.254.169.254", true}, // AWS・GCP などのメタデータ
+		{"100.100.100.200", true}, // Alibaba Cloud のメタデータ
+		{"fd00:ec2::254", true},   // AWS の IPv6 のメタデータ
+		{"fe80::1", true},
+		{"0.0.0.0", true},
+		{"::ffff:127.0.0.1", true},
+		{"93.184.216.34", false},
+		{"2606:2800:220:1:248:1893:25c8:1946", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			assert.Equal(t, tt.disallowed, isDisallowed(netip.MustParseAddr(tt.addr)))
+		})
+	}
+}
+
+func TestHTTPSender_CheckURL(t *testing.T) {
+	sender := NewHTTPSender(false)
+
+	t.Run("正常系: 外部のアドレス", func(t *testing.T) {
+		assert.NoError(t, sender.CheckURL(context.Background(), "https://93.184.216.34/hook"))
+	})
+
+	t.Run("異常系: サーバーの内側のアドレス", func(t *testing.T) {
+		for _, rawURL := range []string{
+			"http://169.254.169.254/latest/meta-data/",
+			"http://127.0.0.1:8080/admin",
+			"http://[::1]/",
+			"http://localhost/hook",
+		} {
+			assert.ErrorIs(t, sender.CheckURL(context.Background(), rawURL), ErrDisallowedAddress, rawURL)
+		}
+	})
+
+	t.Run("正常系: 許可した場合は確かめない", func(t *testing.T) {
+		assert.NoError(t, NewHTTPSender(true).CheckURL(context.Background(), "http://127.0.0.1/hook"))
+	})
+}
+
+func TestHTTPSender_Send(t *testing.T) {
+	received := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received++
+		_, _ = w.Write([]byte("internal secret"))
+	}))
+	defer server.Close()
+	webhook := &entity.Webhook{URL: server.URL}
+
+	t.Run("異常系: 接続の直前にサーバーの内側のアドレスを拒否する", func(t *testing.T) {
+		resp, err := NewHTTPSender(false).Send(context.Background(), webhook, []byte(`{}`))
+		assert.ErrorIs(t, err, ErrDisallowedAddress)
+		assert.Zero(t, resp.StatusCode)
+		assert.Zero(t, received)
+	})
+
+	t.Run("正常系: 許可した場合は送る", func(t *testing.T) {
+		resp, err := NewHTTPSender(true).Send(context.Background(), webhook, []byte(`{}`))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, 1, received)
+	})
+}
@@ -0,0 +1,206 @@
+package attachments
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/interfaces/controller/response"
+	"Aicon-assignment/internal/pkg/reqctx"
+	"Aicon-assignment/internal/usecase"
+)
+
+type AttachmentHandler struct {
+	attachmentUsecase usecase.AttachmentUsecase
+	maxSize           int64 // 1 ファイルのサイズの上限（バイト）
+}
+
+func NewAttachmentHandler(attachmentUsecase usecase.AttachmentUsecase, maxSize int64) *AttachmentHandler {
+	return &AttachmentHandler{
+		attachmentUsecase: attachmentUsecase,
+		maxSize:           maxSize,
+	}
+}
+
+// multipart の file で送られたファイルをアイテムに添付する
+func (h *AttachmentHandler) Upload(c echo.Context) error {
+	itemID, ok := response.ParseID(c, "id")
+	if !ok {
+		return response.Error(c, http.StatusBadRequest, "invalid item ID")
+	}
+	header, err := c.FormFile("file")
+	if err != nil {
+		return response.ValidationError(c, errors.New("file is required"))
+	}
+	if header.Size > h.maxSize {
+		return response.Error(c, http.StatusRequestEntityTooLarge, fmt.Sprintf("file must be at most %d bytes", h.maxSize))
+	}
+	file, err := header.Open()
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "failed to read file")
+	}
+	defer file.Close()
+
+	attachment, err := h.attachmentUsecase.Upload(c.Request().Context(), itemID, header.Filename, header.Header.Get(echo.HeaderContentType), file)
+	if err != nil {
+		return errorResponse(c, err, "failed to upload attachment")
+	}
+	return c.JSON(http.StatusCreated, attachment)
+}
+
+func (h *AttachmentHandler) List(c echo.Context) error {
+	itemID, ok := response.ParseID(c, "id")
+	if !ok {
+		return response.Error(c, http.StatusBadRequest, "invalid item ID")
+	}
+	attachments, err := h.attachmentUsecase.List(c.Request().Context(), itemID)
+	if err != nil {
+		return errorResponse(c, err, "failed to list attachments")
+	}
+	return c.JSON(http.StatusOK, attachments)
+}
+
+// 添付ファイルの中身を返す
+func (h *AttachmentHandler) Download(c echo.Context) error {
+	itemID, attachmentID, ok := parseIDs(c)
+	if !ok {
+		return response.Error(c, http.StatusBadRequest, "invalid ID")
+	}
+	body, attachment, err := h.attachmentUsecase.Open(c.Request().Context(), itemID, attachmentID)
+	if err != nil {
+		return errorResponse(c, err, "failed to open attachment")
+	}
+	defer body.Close()
+
+	contentType := attachment.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, contentType)
+	res.Header().Set(echo.HeaderContentDisposition, mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename}))
+	res.Header().Set(echo.HeaderContentLength, strconv.FormatInt(attachment.Size, 10))
+	res.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(res, body); err != nil {
+		// ステータスは送信済みのため、途中で切れたことはログにだけ残す
+		reqctx.Logger(c.Request().Context()).Error("attachment download aborted", "attachment_id", attachment.ID, "error", err)
+	}
+	return nil
+}
+
+func (h *AttachmentHandler) Delete(c echo.Context) error {
+	itemID, attachmentID, ok := parseIDs(c)
+	if !ok {
+		return response.Error(c, http.StatusBadRequest, "invalid ID")
+	}
+	if err := h.attachmentUsecase.Delete(c.Request().Context(), itemID, attachmentID); err != nil {
+		return errorResponse(c, err, "failed to delete attachment")
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// multipart の file で送られたレシート（PDF・JPEG）をアイテムに添付する。すでにある場合は置き換える
+func (h *AttachmentHandler) UploadReceipt(c echo.Context) error {
+	itemID, ok := response.ParseID(c, "id")
+	if !ok {
+		return response.Error(c, http.StatusBadRequest, "invalid item ID")
+	}
+	header, err := c.FormFile("file")
+	if err != nil {
+		return response.ValidationError(c, errors.New("file is required"))
+	}
+	if header.Size > h.maxSize {
+		return response.Error(c, http.StatusRequestEntityTooLarge, fmt.Sprintf("file must be at most %d bytes", h.maxSize))
+	}
+	file, err := header.Open()
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "failed to read file")
+	}
+	defer file.Close()
+
+	receipt, err := h.attachmentUsecase.UploadReceipt(c.Request().Context(), itemID, header.Filename, file)
+	if err != nil {
+		return errorResponse(c, err, "failed to upload receipt")
+	}
+	return c.JSON(http.StatusCreated, receipt)
+}
+
+// レシートの中身を返す。PDF・JPEG はブラウザでそのまま表示できるよう inline で返す
+func (h *AttachmentHandler) DownloadReceipt(c echo.Context) error {
+	itemID, ok := response.ParseID(c, "id")
+	if !ok {
+		return response.Error(c, http.StatusBadRequest, "invalid item ID")
+	}
+	body, receipt, err := h.attachmentUsecase.OpenReceipt(c.Request().Context(), itemID)
+	if err != nil {
+		return errorResponse(c, err, "failed to open receipt")
+	}
+	defer body.Close()
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, receipt.ContentType)
+	res.Header().Set(echo.HeaderContentDisposition, mime.FormatMediaType("inline", map[string]string{"filename": receipt.Filename}))
+	res.Header().Set(echo.HeaderContentLength, strconv.FormatInt(receipt.Size, 10))
+	res.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(res, body); err != nil {
+		reqctx.Logger(c.Request().Context()).Error("receipt download aborted", "attachment_id", receipt.ID, "error", err)
+	}
+	return nil
+}
+
+func (h *AttachmentHandler) DeleteReceipt(c echo.Context) error {
+	itemID, ok := response.ParseID(c, "id")
+	if !ok {
+		return response.Error(c, http.StatusBadRequest, "invalid item ID")
+	}
+	if err := h.attachmentUsecase.DeleteReceipt(c.Request().Context(), itemID); err != nil {
+		return errorResponse(c, err, "failed to delete receipt")
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// 重複排除でどれだけ容量を節約できているかを返す
+func (h *AttachmentHandler) StorageReport(c echo.Context) error {
+	report, err := h.attachmentUsecase.StorageReport(c.Request().Context())
+	if err != nil {
+		return response.RepositoryError(c, err, "failed to get attachment storage report")
+	}
+	return c.JSON(http.StatusOK, report)
+}
+
+func parseIDs(c echo.Context) (int64, int64, bool) {
+	itemID, ok := response.ParseID(c, "id")
+	if !ok {
+		return 0, 0, false
+	}
+	attachmentID, ok := response.ParseID(c, "attachmentId")
+	return itemID, attachmentID, ok
+}
+
+func errorResponse(c echo.Context, err error, fallback string) error {
+	if errors.Is(err, domainErrors.ErrItemNotFound) {
+		return response.Error(c, http.StatusNotFound, "item not found")
+	}
+	if errors.Is(err, domainErrors.ErrReceiptNotFound) {
+		return response.Error(c, http.StatusNotFound, "receipt not found")
+	}
+	if domainErrors.IsNotFoundError(err) {
+		return response.Error(c, http.StatusNotFound, "attachment not found")
+	}
+	if domainErrors.IsValidationError(err) {
+		return response.ValidationError(c, err)
+	}
+	if errors.Is(err, domainErrors.ErrInfectedFile) {
+		return response.Error(c, http.StatusUnprocessableEntity, err.Error())
+	}
+	if errors.Is(err, domainErrors.ErrScanUnavailable) {
+		return response.Error(c, http.StatusServiceUnavailable, "virus scan is unavailable, try again later")
+	}
+	return response.RepositoryError(c, err, fallback)
+}
@@ -0,0 +1,76 @@
+package auth
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/interfaces/controller/response"
+	"Aicon-assignment/internal/usecase"
+)
+
+type APIKeyHandler struct {
+	apiKeyUsecase usecase.APIKeyUsecase
+}
+
+func NewAPIKeyHandler(apiKeyUsecase usecase.APIKeyUsecase) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyUsecase: apiKeyUsecase,
+	}
+}
+
+// ログイン中のユーザーの API キーを発行する。キーはこのレスポンスでしか返さない
+func (h *APIKeyHandler) Create(c echo.Context) error {
+	var input usecase.CreateAPIKeyInput
+	if err := c.Bind(&input); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	key, err := h.apiKeyUsecase.Create(c.Request().Context(), input)
+	if err != nil {
+		return h.errorResponse(c, err, "failed to create api key")
+	}
+
+	c.Response().Header().Set(echo.HeaderCacheControl, "no-store")
+	return c.JSON(http.StatusCreated, key)
+}
+
+// ログイン中のユーザーの API キーを失効したものも含めて返す
+func (h *APIKeyHandler) List(c echo.Context) error {
+	keys, err := h.apiKeyUsecase.List(c.Request().Context())
+	if err != nil {
+		return h.errorResponse(c, err, "failed to retrieve api keys")
+	}
+
+	return c.JSON(http.StatusOK, keys)
+}
+
+// API キーを失効させる
+func (h *APIKeyHandler) Revoke(c echo.Context) error {
+	id, ok := response.ParseID(c, "id")
+	if !ok {
+		return response.Error(c, http.StatusBadRequest, "invalid api key ID")
+	}
+
+	key, err := h.apiKeyUsecase.Revoke(c.Request().Context(), id)
+	if err != nil {
+		return h.errorResponse(c, err, "failed to revoke api key")
+	}
+
+	return c.JSON(http.StatusOK, key)
+}
+
+func (h *APIKeyHandler) errorResponse(c echo.Context, err error, fallback string) error {
+	switch {
+	case domainErrors.IsNotFoundError(err):
+		return response.Error(c, http.StatusNotFound, "api key not found")
+	case domainErrors.IsValidationError(err):
+		return response.ValidationError(c, err)
+	case domainErrors.IsUnauthenticatedError(err):
+		return response.Error(c, http.StatusUnauthorized, "authentication required")
+	case domainErrors.IsForbiddenError(err):
+		return response.Error(c, http.StatusForbidden, "cannot create api keys while impersonating")
+	}
+	return response.RepositoryError(c, err, fallback)
+}
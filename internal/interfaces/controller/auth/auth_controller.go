@@ -0,0 +1,138 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/interfaces/controller/response"
+	"Aicon-assignment/internal/pkg/reqctx"
+	"Aicon-assignment/internal/usecase"
+)
+
+type AuthHandler struct {
+	authUsecase usecase.AuthUsecase
+}
+
+func NewAuthHandler(authUsecase usecase.AuthUsecase) *AuthHandler {
+	return &AuthHandler{
+		authUsecase: authUsecase,
+	}
+}
+
+// ユーザー名とパスワードでログインし、アクセストークンとリフレッシュトークンを返す
+func (h *AuthHandler) Login(c echo.Context) error {
+	var input usecase.LoginInput
+	if err := c.Bind(&input); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid request format")
+	}
+	if input.UserName == "" || input.Password == "" {
+		return response.ValidationError(c, errors.New("user_name and password are required"))
+	}
+
+	token, err := h.authUsecase.Login(c.Request().Context(), input)
+	if err != nil {
+		switch {
+		case domainErrors.IsTwoFactorError(err):
+			// パスワードは正しいため、クライアントが OTP の入力を求められるよう区別して返す
+			return response.Error(c, http.StatusUnauthorized, err.Error())
+		case domainErrors.IsUnauthenticatedError(err):
+			return response.Error(c, http.StatusUnauthorized, "invalid user name or password")
+		}
+		return response.RepositoryError(c, err, "failed to log in")
+	}
+
+	// トークンをキャッシュさせない（RFC 6749 5.1）
+	c.Response().Header().Set(echo.HeaderCacheControl, "no-store")
+	return c.JSON(http.StatusOK, token)
+}
+
+type refreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// リフレッシュトークンを新しいアクセストークンとリフレッシュトークンに交換する
+func (h *AuthHandler) Refresh(c echo.Context) error {
+	var input refreshTokenRequest
+	if err := c.Bind(&input); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid request format")
+	}
+	if input.RefreshToken == "" {
+		return response.ValidationError(c, errors.New("refresh_token is required"))
+	}
+
+	token, err := h.authUsecase.Refresh(c.Request().Context(), input.RefreshToken)
+	if err != nil {
+		if domainErrors.IsUnauthenticatedError(err) {
+			return response.Error(c, http.StatusUnauthorized, "invalid or expired refresh token")
+		}
+		return response.RepositoryError(c, err, "failed to refresh token")
+	}
+
+	c.Response().Header().Set(echo.HeaderCacheControl, "no-store")
+	return c.JSON(http.StatusOK, token)
+}
+
+// リフレッシュトークンを失効させる。発行済みのアクセストークンは有効期限まで使える
+func (h *AuthHandler) Logout(c echo.Context) error {
+	var input refreshTokenRequest
+	if err := c.Bind(&input); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid request format")
+	}
+	if input.RefreshToken == "" {
+		return response.ValidationError(c, errors.New("refresh_token is required"))
+	}
+
+	if err := h.authUsecase.Logout(c.Request().Context(), input.RefreshToken); err != nil {
+		return response.RepositoryError(c, err, "failed to log out")
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ユーザー名とパスワードでアカウントを作成する
+func (h *AuthHandler) Register(c echo.Context) error {
+	var input usecase.RegisterInput
+	if err := c.Bind(&input); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	user, err := h.authUsecase.Register(c.Request().Context(), input)
+	if err != nil {
+		switch {
+		case domainErrors.IsValidationError(err):
+			return response.ValidationError(c, err)
+		case domainErrors.IsConflictError(err):
+			return response.Error(c, http.StatusConflict, "user name is already taken")
+		}
+		return response.RepositoryError(c, err, "failed to register user")
+	}
+
+	return c.JSON(http.StatusCreated, user)
+}
+
+// ログイン中のユーザーのパスワードを変更する
+func (h *AuthHandler) ChangePassword(c echo.Context) error {
+	userID, ok := reqctx.UserID(c.Request().Context())
+	if !ok {
+		return response.Error(c, http.StatusUnauthorized, "authentication required")
+	}
+	var input usecase.ChangePasswordInput
+	if err := c.Bind(&input); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	if err := h.authUsecase.ChangePassword(c.Request().Context(), userID, input); err != nil {
+		switch {
+		case domainErrors.IsValidationError(err):
+			return response.ValidationError(c, err)
+		case domainErrors.IsForbiddenError(err):
+			return response.Error(c, http.StatusForbidden, "current password is incorrect")
+		}
+		return response.RepositoryError(c, err, "failed to change password")
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
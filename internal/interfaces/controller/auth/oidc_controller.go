@@ -0,0 +1,142 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/interfaces/controller/response"
+	"Aicon-assignment/internal/pkg/reqctx"
+	"Aicon-assignment/internal/usecase"
+)
+
+type OIDCHandler struct {
+	oidcUsecase usecase.OIDCUsecase
+}
+
+func NewOIDCHandler(oidcUsecase usecase.OIDCUsecase) *OIDCHandler {
+	return &OIDCHandler{
+		oidcUsecase: oidcUsecase,
+	}
+}
+
+type linkRequest struct {
+	Provider string `json:"provider"`
+}
+
+type authorizationResponse struct {
+	AuthorizationURL string `json:"authorization_url"`
+}
+
+// 使えるプロバイダーの一覧
+func (h *OIDCHandler) Providers(c echo.Context) error {
+	return c.JSON(http.StatusOK, map[string][]string{"providers": h.oidcUsecase.Providers()})
+}
+
+// プロバイダーの認可画面にリダイレクトする
+func (h *OIDCHandler) Login(c echo.Context) error {
+	provider := c.QueryParam("provider")
+	if provider == "" {
+		return response.ValidationError(c, errors.New("provider is required"))
+	}
+
+	authorizationURL, err := h.oidcUsecase.Begin(c.Request().Context(), provider, nil)
+	if err != nil {
+		if domainErrors.IsValidationError(err) {
+			return response.ValidationError(c, err)
+		}
+		return response.RepositoryError(c, err, "failed to start login")
+	}
+
+	return c.Redirect(http.StatusFound, authorizationURL)
+}
+
+// 認可画面から戻った利用者のログイン（または ID の紐付け）を完了する
+func (h *OIDCHandler) Callback(c echo.Context) error {
+	// 利用者が拒否した場合などは、コードの代わりに error が返る（RFC 6749 4.1.2.1）
+	if reason := c.QueryParam("error"); reason != "" {
+		return response.Error(c, http.StatusUnauthorized, "login was not completed: "+reason)
+	}
+
+	result, err := h.oidcUsecase.Callback(c.Request().Context(), c.QueryParam("state"), c.QueryParam("code"))
+	if err != nil {
+		switch {
+		case domainErrors.IsUnauthenticatedError(err):
+			return response.Error(c, http.StatusUnauthorized, "login failed or expired; start again")
+		case domainErrors.IsConflictError(err):
+			return response.Error(c, http.StatusConflict, err.Error())
+		case domainErrors.IsValidationError(err):
+			return response.ValidationError(c, err)
+		}
+		return response.RepositoryError(c, err, "failed to complete login")
+	}
+
+	c.Response().Header().Set(echo.HeaderCacheControl, "no-store")
+	return c.JSON(http.StatusOK, result)
+}
+
+// ログイン中のユーザーに ID を紐付けるため、認可画面の URL を返す
+// ブラウザのリダイレクトには Authorization ヘッダーを付けられないため、URL はクライアントが開く
+func (h *OIDCHandler) Link(c echo.Context) error {
+	userID, ok := reqctx.UserID(c.Request().Context())
+	if !ok {
+		return response.Error(c, http.StatusUnauthorized, "authentication required")
+	}
+	var input linkRequest
+	if err := c.Bind(&input); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid request format")
+	}
+	if input.Provider == "" {
+		return response.ValidationError(c, errors.New("provider is required"))
+	}
+
+	authorizationURL, err := h.oidcUsecase.Begin(c.Request().Context(), input.Provider, &userID)
+	if err != nil {
+		if domainErrors.IsValidationError(err) {
+			return response.ValidationError(c, err)
+		}
+		return response.RepositoryError(c, err, "failed to start linking")
+	}
+
+	return c.JSON(http.StatusOK, authorizationResponse{AuthorizationURL: authorizationURL})
+}
+
+// ログイン中のユーザーに紐付けた ID の一覧
+func (h *OIDCHandler) Identities(c echo.Context) error {
+	userID, ok := reqctx.UserID(c.Request().Context())
+	if !ok {
+		return response.Error(c, http.StatusUnauthorized, "authentication required")
+	}
+
+	identities, err := h.oidcUsecase.Identities(c.Request().Context(), userID)
+	if err != nil {
+		return response.RepositoryError(c, err, "failed to retrieve identities")
+	}
+
+	return c.JSON(http.StatusOK, identities)
+}
+
+func (h *OIDCHandler) Unlink(c echo.Context) error {
+	userID, ok := reqctx.UserID(c.Request().Context())
+	if !ok {
+		return response.Error(c, http.StatusUnauthorized, "authentication required")
+	}
+	id, ok := response.ParseID(c, "id")
+	if !ok {
+		return response.Error(c, http.StatusBadRequest, "invalid identity ID")
+	}
+
+	if err := h.oidcUsecase.Unlink(c.Request().Context(), userID, id); err != nil {
+		switch {
+		case domainErrors.IsNotFoundError(err):
+			return response.Error(c, http.StatusNotFound, "identity not found")
+		case domainErrors.IsForbiddenError(err):
+			return response.Error(c, http.StatusForbidden, err.Error())
+		}
+		return response.RepositoryError(c, err, "failed to unlink identity")
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
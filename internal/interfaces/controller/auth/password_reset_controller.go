@@ -0,0 +1,66 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/interfaces/controller/response"
+	"Aicon-assignment/internal/usecase"
+)
+
+type PasswordResetHandler struct {
+	passwordResetUsecase usecase.PasswordResetUsecase
+}
+
+func NewPasswordResetHandler(passwordResetUsecase usecase.PasswordResetUsecase) *PasswordResetHandler {
+	return &PasswordResetHandler{
+		passwordResetUsecase: passwordResetUsecase,
+	}
+}
+
+type forgotPasswordRequest struct {
+	Email string `json:"email"`
+}
+
+// 再設定用のトークンをメールで送る。登録の有無を推測させないよう、常に 202 を返す
+func (h *PasswordResetHandler) Forgot(c echo.Context) error {
+	var input forgotPasswordRequest
+	if err := c.Bind(&input); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	if err := h.passwordResetUsecase.Request(c.Request().Context(), input.Email); err != nil {
+		if domainErrors.IsValidationError(err) {
+			return response.ValidationError(c, err)
+		}
+		return response.RepositoryError(c, err, "failed to request password reset")
+	}
+
+	return c.NoContent(http.StatusAccepted)
+}
+
+// メールで受け取ったトークンで新しいパスワードを設定する
+func (h *PasswordResetHandler) Reset(c echo.Context) error {
+	var input usecase.ResetPasswordInput
+	if err := c.Bind(&input); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid request format")
+	}
+	if input.Token == "" {
+		return response.ValidationError(c, errors.New("token is required"))
+	}
+
+	if err := h.passwordResetUsecase.Reset(c.Request().Context(), input); err != nil {
+		switch {
+		case domainErrors.IsValidationError(err):
+			return response.ValidationError(c, err)
+		case errors.Is(err, domainErrors.ErrPasswordResetNotFound):
+			return response.Error(c, http.StatusBadRequest, err.Error())
+		}
+		return response.RepositoryError(c, err, "failed to reset password")
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,132 @@
+package auth
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/interfaces/controller/response"
+	"Aicon-assignment/internal/pkg/reqctx"
+	"Aicon-assignment/internal/usecase"
+)
+
+type TwoFactorHandler struct {
+	twoFactorUsecase usecase.TwoFactorUsecase
+}
+
+func NewTwoFactorHandler(twoFactorUsecase usecase.TwoFactorUsecase) *TwoFactorHandler {
+	return &TwoFactorHandler{
+		twoFactorUsecase: twoFactorUsecase,
+	}
+}
+
+// 認証アプリのコードかリカバリーコード
+type codeRequest struct {
+	Code string `json:"code"`
+}
+
+// ログイン中のユーザーの二要素認証の状態
+func (h *TwoFactorHandler) Status(c echo.Context) error {
+	userID, ok := reqctx.UserID(c.Request().Context())
+	if !ok {
+		return response.Error(c, http.StatusUnauthorized, "authentication required")
+	}
+
+	status, err := h.twoFactorUsecase.Status(c.Request().Context(), userID)
+	if err != nil {
+		return response.RepositoryError(c, err, "failed to retrieve two-factor status")
+	}
+
+	return c.JSON(http.StatusOK, status)
+}
+
+// 新しい鍵を作り、認証アプリに登録する otpauth:// URL を返す
+func (h *TwoFactorHandler) Enroll(c echo.Context) error {
+	userID, ok := reqctx.UserID(c.Request().Context())
+	if !ok {
+		return response.Error(c, http.StatusUnauthorized, "authentication required")
+	}
+
+	enrollment, err := h.twoFactorUsecase.Enroll(c.Request().Context(), userID)
+	if err != nil {
+		return h.errorResponse(c, err, "failed to enroll two-factor authentication")
+	}
+
+	c.Response().Header().Set(echo.HeaderCacheControl, "no-store")
+	return c.JSON(http.StatusOK, enrollment)
+}
+
+// 認証アプリのコードを確かめて有効にし、リカバリーコードを返す
+func (h *TwoFactorHandler) Confirm(c echo.Context) error {
+	userID, code, ok, err := h.bindCode(c)
+	if !ok {
+		return err
+	}
+
+	codes, err := h.twoFactorUsecase.Confirm(c.Request().Context(), userID, code)
+	if err != nil {
+		if domainErrors.IsTwoFactorError(err) {
+			return response.ValidationError(c, err)
+		}
+		return h.errorResponse(c, err, "failed to confirm two-factor authentication")
+	}
+
+	c.Response().Header().Set(echo.HeaderCacheControl, "no-store")
+	return c.JSON(http.StatusOK, codes)
+}
+
+// リカバリーコードを作り直す
+func (h *TwoFactorHandler) RegenerateRecoveryCodes(c echo.Context) error {
+	userID, code, ok, err := h.bindCode(c)
+	if !ok {
+		return err
+	}
+
+	codes, err := h.twoFactorUsecase.RegenerateRecoveryCodes(c.Request().Context(), userID, code)
+	if err != nil {
+		return h.errorResponse(c, err, "failed to regenerate recovery codes")
+	}
+
+	c.Response().Header().Set(echo.HeaderCacheControl, "no-store")
+	return c.JSON(http.StatusOK, codes)
+}
+
+// 二要素認証を解除する
+func (h *TwoFactorHandler) Disable(c echo.Context) error {
+	userID, code, ok, err := h.bindCode(c)
+	if !ok {
+		return err
+	}
+
+	if err := h.twoFactorUsecase.Disable(c.Request().Context(), userID, code); err != nil {
+		return h.errorResponse(c, err, "failed to disable two-factor authentication")
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ok が false の場合は、返すレスポンスを書き込んだ結果が err に入る
+func (h *TwoFactorHandler) bindCode(c echo.Context) (int64, string, bool, error) {
+	userID, ok := reqctx.UserID(c.Request().Context())
+	if !ok {
+		return 0, "", false, response.Error(c, http.StatusUnauthorized, "authentication required")
+	}
+	var input codeRequest
+	if err := c.Bind(&input); err != nil {
+		return 0, "", false, response.Error(c, http.StatusBadRequest, "invalid request format")
+	}
+	return userID, input.Code, true, nil
+}
+
+func (h *TwoFactorHandler) errorResponse(c echo.Context, err error, fallback string) error {
+	switch {
+	case domainErrors.IsTwoFactorError(err):
+		return response.Error(c, http.StatusForbidden, err.Error())
+	case domainErrors.IsNotFoundError(err):
+		return response.Error(c, http.StatusNotFound, err.Error())
+	case domainErrors.IsConflictError(err):
+		return response.Error(c, http.StatusConflict, err.Error())
+	}
+	return response.RepositoryError(c, err, fallback)
+}
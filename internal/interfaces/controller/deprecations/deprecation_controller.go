@@ -0,0 +1,30 @@
+package deprecations
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"Aicon-assignment/internal/interfaces/controller/response"
+	"Aicon-assignment/internal/usecase"
+)
+
+type DeprecationHandler struct {
+	deprecationUsecase usecase.DeprecationUsecase
+}
+
+func NewDeprecationHandler(deprecationUsecase usecase.DeprecationUsecase) *DeprecationHandler {
+	return &DeprecationHandler{
+		deprecationUsecase: deprecationUsecase,
+	}
+}
+
+// 廃止予定のエンドポイントごとに、まだ呼び出している呼び出し元を返す
+func (h *DeprecationHandler) Report(c echo.Context) error {
+	reports, err := h.deprecationUsecase.Report(c.Request().Context())
+	if err != nil {
+		return response.RepositoryError(c, err, "failed to retrieve deprecation report")
+	}
+
+	return c.JSON(http.StatusOK, reports)
+}
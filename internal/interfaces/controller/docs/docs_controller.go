@@ -0,0 +1,53 @@
+// Package docs は API の仕様（OpenAPI 3）と、それを閲覧する Swagger UI を返す。
+package docs
+
+import (
+	_ "embed"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// アイテムと集計のエンドポイントの仕様。ルートやリクエスト・レスポンスの形を変えたら合わせて更新する
+//
+//go:embed openapi.json
+var openAPISpec []byte
+
+// Swagger UI は CDN から読み込み、同じサーバーの仕様を表示する
+const swaggerUIVersion = "5.17.14"
+
+var swaggerUIPage = `<!DOCTYPE html>
+<html lang="ja">
+<head>
+  <meta charset="utf-8">
+  <title>所持品管理 API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@` + swaggerUIVersion + `/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@` + swaggerUIVersion + `/swagger-ui-bundle.js" crossorigin></script>
+  <script>
+    window.onload = function () {
+      window.ui = SwaggerUIBundle({ url: "` + SpecPath + `", dom_id: "#swagger-ui" });
+    };
+  </script>
+</body>
+</html>
+`
+
+// 仕様を返すパス
+const SpecPath = "/openapi.json"
+
+type DocsHandler struct{}
+
+func NewDocsHandler() *DocsHandler {
+	return &DocsHandler{}
+}
+
+func (h *DocsHandler) Spec(c echo.Context) error {
+	return c.Blob(http.StatusOK, echo.MIMEApplicationJSONCharsetUTF8, openAPISpec)
+}
+
+func (h *DocsHandler) SwaggerUI(c echo.Context) error {
+	return c.HTML(http.StatusOK, swaggerUIPage)
+}
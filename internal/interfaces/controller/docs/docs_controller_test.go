@@ -0,0 +1,71 @@
+package docs
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAPISpec(t *testing.T) {
+	var spec map[string]any
+	require.NoError(t, json.Unmarshal(openAPISpec, &spec))
+
+	t.Run("正常系: アイテムと集計のエンドポイントを含む", func(t *testing.T) {
+		paths := spec["paths"].(map[string]any)
+		for _, path := range []string{"/items", "/items/{id}", "/items/summary", "/items/import", "/admin/summary"} {
+			assert.Contains(t, paths, path)
+		}
+	})
+
+	t.Run("正常系: すべての $ref が定義を指している", func(t *testing.T) {
+		var walk func(node any)
+		walk = func(node any) {
+			switch v := node.(type) {
+			case map[string]any:
+				if ref, ok := v["$ref"].(string); ok {
+					var target any = spec
+					for _, part := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+						target = target.(map[string]any)[part]
+					}
+					assert.NotNil(t, target, ref)
+				}
+				for _, child := range v {
+					walk(child)
+				}
+			case []any:
+				for _, child := range v {
+					walk(child)
+				}
+			}
+		}
+		walk(spec)
+	})
+}
+
+func TestDocsHandler(t *testing.T) {
+	e := echo.New()
+	h := NewDocsHandler()
+
+	t.Run("正常系: 仕様を JSON で返す", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		require.NoError(t, h.Spec(e.NewContext(httptest.NewRequest(http.MethodGet, SpecPath, nil), rec)))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Header().Get(echo.HeaderContentType), echo.MIMEApplicationJSON)
+		assert.JSONEq(t, string(openAPISpec), rec.Body.String())
+	})
+
+	t.Run("正常系: Swagger UI は仕様のパスを読み込む", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		require.NoError(t, h.SwaggerUI(e.NewContext(httptest.NewRequest(http.MethodGet, "/docs", nil), rec)))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `url: "/openapi.json"`)
+	})
+}
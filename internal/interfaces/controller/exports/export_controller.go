@@ -0,0 +1,61 @@
+package exports
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/interfaces/controller/response"
+	"Aicon-assignment/internal/usecase"
+)
+
+type ExportHandler struct {
+	exportUsecase usecase.ExportUsecase
+}
+
+func NewExportHandler(exportUsecase usecase.ExportUsecase) *ExportHandler {
+	return &ExportHandler{
+		exportUsecase: exportUsecase,
+	}
+}
+
+// parquet の場合はレコードを返さず、書き出したファイルを返す
+type parquetExportResponse struct {
+	*entity.Export
+	Files []*entity.ExportFile `json:"files"`
+}
+
+// アイテムをエクスポートする。?since_export={id} を指定した場合は、そのエクスポート以降の変更だけを返す
+// ?format=parquet の場合は Parquet ファイルをファイルストアに書き出す
+func (h *ExportHandler) CreateExport(c echo.Context) error {
+	var sinceExportID *int64
+	if raw := c.QueryParam("since_export"); raw != "" {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || id <= 0 {
+			return response.Error(c, http.StatusBadRequest, "invalid since_export")
+		}
+		sinceExportID = &id
+	}
+
+	result, err := h.exportUsecase.CreateExport(c.Request().Context(), usecase.CreateExportInput{
+		SinceExportID: sinceExportID,
+		Format:        c.QueryParam("format"),
+	})
+	if err != nil {
+		if domainErrors.IsUnauthenticatedError(err) {
+			return response.Error(c, http.StatusUnauthorized, "authentication required")
+		}
+		if domainErrors.IsValidationError(err) {
+			return response.ValidationError(c, err)
+		}
+		return response.RepositoryError(c, err, "failed to create export")
+	}
+
+	if result.Format == entity.ExportFormatParquet {
+		return c.JSON(http.StatusCreated, parquetExportResponse{Export: result.Export, Files: result.Files})
+	}
+	return c.JSON(http.StatusCreated, result)
+}
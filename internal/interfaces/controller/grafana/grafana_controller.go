@@ -0,0 +1,186 @@
+// Package grafana は Grafana の JSON データソース（simpod-json-datasource）の形式で統計・推移を返す。
+package grafana
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/interfaces/controller/response"
+	"Aicon-assignment/internal/usecase"
+)
+
+type GrafanaHandler struct {
+	dashboardUsecase usecase.DashboardUsecase
+}
+
+func NewGrafanaHandler(dashboardUsecase usecase.DashboardUsecase) *GrafanaHandler {
+	return &GrafanaHandler{
+		dashboardUsecase: dashboardUsecase,
+	}
+}
+
+type timeRange struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+type searchRequest struct {
+	Target string `json:"target"`
+}
+
+type metricOption struct {
+	Label string `json:"label"`
+	Value string `json:"value"`
+}
+
+type queryRequest struct {
+	Range   timeRange     `json:"range"`
+	Targets []queryTarget `json:"targets"`
+}
+
+type queryTarget struct {
+	Target string `json:"target"`
+	RefID  string `json:"refId"`
+	Hide   bool   `json:"hide"`
+}
+
+// 時系列の値は [値, UNIX ミリ秒] の組
+type timeSeriesResponse struct {
+	Target     string       `json:"target"`
+	RefID      string       `json:"refId,omitempty"`
+	Datapoints [][2]float64 `json:"datapoints"`
+}
+
+type tableResponse struct {
+	Type    string        `json:"type"`
+	RefID   string        `json:"refId,omitempty"`
+	Columns []tableColumn `json:"columns"`
+	Rows    [][]any       `json:"rows"`
+}
+
+type tableColumn struct {
+	Text string `json:"text"`
+	Type string `json:"type"`
+}
+
+type annotationRequest struct {
+	Range      timeRange      `json:"range"`
+	Annotation map[string]any `json:"annotation"`
+}
+
+type annotationResponse struct {
+	Annotation map[string]any `json:"annotation,omitempty"` // 古いプラグインはリクエストの annotation をそのまま返す必要がある
+	Time       int64          `json:"time"`
+	Title      string         `json:"title"`
+	Text       string         `json:"text"`
+	Tags       []string       `json:"tags"`
+}
+
+// データソースの接続確認
+func (h *GrafanaHandler) Health(c echo.Context) error {
+	return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// 指標の名前の一覧。target を指定した場合はその文字列を含むものだけ
+func (h *GrafanaHandler) Search(c echo.Context) error {
+	var req searchRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	names := []string{}
+	for _, metric := range h.dashboardUsecase.Metrics(c.Request().Context()) {
+		if strings.Contains(metric.Name, req.Target) {
+			names = append(names, metric.Name)
+		}
+	}
+	return c.JSON(http.StatusOK, names)
+}
+
+// 新しいプラグインが使う指標の一覧
+func (h *GrafanaHandler) Metrics(c echo.Context) error {
+	options := []metricOption{}
+	for _, metric := range h.dashboardUsecase.Metrics(c.Request().Context()) {
+		options = append(options, metricOption{Label: metric.Name, Value: metric.Name})
+	}
+	return c.JSON(http.StatusOK, options)
+}
+
+// 指標ごとに時系列（系列ごとに 1 要素）か表を返す
+func (h *GrafanaHandler) Query(c echo.Context) error {
+	var req queryRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	ctx := c.Request().Context()
+	results := []any{}
+	for _, target := range req.Targets {
+		if target.Hide || target.Target == "" {
+			continue
+		}
+		result, err := h.dashboardUsecase.Query(ctx, target.Target, req.Range.From, req.Range.To)
+		if err != nil {
+			return h.errorResponse(c, err, "failed to query metric")
+		}
+
+		if result.Table != nil {
+			table := tableResponse{Type: usecase.DashboardKindTable, RefID: target.RefID, Rows: result.Table.Rows}
+			for _, column := range result.Table.Columns {
+				table.Columns = append(table.Columns, tableColumn{Text: column.Name, Type: column.Type})
+			}
+			results = append(results, table)
+			continue
+		}
+		for _, series := range result.Series {
+			datapoints := make([][2]float64, 0, len(series.Points))
+			for _, point := range series.Points {
+				datapoints = append(datapoints, [2]float64{point.Value, float64(point.Time.UnixMilli())})
+			}
+			results = append(results, timeSeriesResponse{Target: series.Name, RefID: target.RefID, Datapoints: datapoints})
+		}
+	}
+	return c.JSON(http.StatusOK, results)
+}
+
+// 期間内に購入したアイテム。注釈のクエリにカテゴリーを書くとそのカテゴリーだけにする
+func (h *GrafanaHandler) Annotations(c echo.Context) error {
+	var req annotationRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid request format")
+	}
+	category, _ := req.Annotation["query"].(string)
+
+	annotations, err := h.dashboardUsecase.Annotations(c.Request().Context(), req.Range.From, req.Range.To, category)
+	if err != nil {
+		return h.errorResponse(c, err, "failed to load annotations")
+	}
+
+	results := make([]annotationResponse, 0, len(annotations))
+	for _, annotation := range annotations {
+		results = append(results, annotationResponse{
+			Annotation: req.Annotation,
+			Time:       annotation.Time.UnixMilli(),
+			Title:      annotation.Title,
+			Text:       annotation.Text,
+			Tags:       annotation.Tags,
+		})
+	}
+	return c.JSON(http.StatusOK, results)
+}
+
+func (h *GrafanaHandler) errorResponse(c echo.Context, err error, fallback string) error {
+	switch {
+	case domainErrors.IsUnauthenticatedError(err):
+		return response.Error(c, http.StatusUnauthorized, "authentication required")
+	case domainErrors.IsForbiddenError(err):
+		return response.Error(c, http.StatusForbidden, "admin role required")
+	case domainErrors.IsValidationError(err):
+		return response.ValidationError(c, err)
+	}
+	return response.RepositoryError(c, err, fallback)
+}
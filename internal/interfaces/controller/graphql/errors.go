@@ -0,0 +1,59 @@
+package graphql
+
+import (
+	"context"
+
+	"github.com/vektah/gqlparser/v2/gqlerror"
+
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/pkg/reqctx"
+)
+
+// エラーの種類は extensions.code で返す（REST のステータスコードに相当）
+const (
+	codeBadUserInput        = "BAD_USER_INPUT"
+	codeUnauthenticated     = "UNAUTHENTICATED"
+	codeForbidden           = "FORBIDDEN"
+	codeNotFound            = "NOT_FOUND"
+	codeReasonRequired      = "REASON_REQUIRED"
+	codeLocked              = "LOCKED"
+	codeConflict            = "CONFLICT"
+	codeConstraintViolation = "CONSTRAINT_VIOLATION"
+	codeUnavailable         = "SERVICE_UNAVAILABLE"
+	codeReadOnly            = "READ_ONLY"
+	codeInternal            = "INTERNAL_SERVER_ERROR"
+)
+
+func codedError(code, message string) *gqlerror.Error {
+	return &gqlerror.Error{Message: message, Extensions: map[string]any{"code": code}}
+}
+
+func badUserInput(err error) *gqlerror.Error {
+	return codedError(codeBadUserInput, err.Error())
+}
+
+// ユースケースのエラーを分類する。分類できないエラーは内容を返さず fallback のメッセージにする
+func resolverError(ctx context.Context, err error, fallback string) error {
+	switch {
+	case domainErrors.IsUnauthenticatedError(err):
+		return codedError(codeUnauthenticated, "authentication required")
+	case domainErrors.IsForbiddenError(err):
+		return codedError(codeForbidden, err.Error())
+	case domainErrors.IsNotFoundError(err):
+		return codedError(codeNotFound, "item not found")
+	case domainErrors.IsValidationError(err):
+		return badUserInput(err)
+	case domainErrors.IsReasonRequiredError(err):
+		return codedError(codeReasonRequired, "reason is required for this operation")
+	case domainErrors.IsLockedError(err):
+		return codedError(codeLocked, err.Error())
+	case domainErrors.IsConflictError(err):
+		return codedError(codeConflict, "conflicts with existing data")
+	case domainErrors.IsConstraintError(err):
+		return codedError(codeConstraintViolation, "violates data constraints")
+	case domainErrors.IsTransientError(err):
+		return codedError(codeUnavailable, "service temporarily unavailable")
+	}
+	reqctx.Logger(ctx).Error(fallback, "error", err)
+	return codedError(codeInternal, fallback)
+}
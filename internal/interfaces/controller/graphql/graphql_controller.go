@@ -0,0 +1,168 @@
+package graphql
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	gqlgen "github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/executor"
+	"github.com/labstack/echo/v4"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+
+	"Aicon-assignment/internal/interfaces/controller/graphql/generated"
+	"Aicon-assignment/internal/interfaces/middleware"
+	"Aicon-assignment/internal/usecase"
+)
+
+const (
+	// これより長いクエリは解析しない
+	maxQueryLength = 10000
+	// フィールドの入れ子の深さの上限
+	maxDepth = 10
+)
+
+//go:embed schema.graphqls
+var schemaSDL string
+
+type GraphQLHandler struct {
+	exec     *executor.Executor
+	readOnly *middleware.ReadOnlyMode
+}
+
+// イントロスペクションは有効にしない（スキーマは GET /graphql/schema で返す）
+func NewGraphQLHandler(itemUsecase usecase.ItemUsecase, readOnly *middleware.ReadOnlyMode) *GraphQLHandler {
+	exec := executor.New(generated.NewExecutableSchema(generated.Config{Resolvers: &Resolver{items: itemUsecase}}))
+	exec.Use(depthLimit{max: maxDepth})
+	return &GraphQLHandler{
+		exec:     exec,
+		readOnly: readOnly,
+	}
+}
+
+// GraphQL over HTTP のリクエスト
+type request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+// GraphQL over HTTP。リクエストの形が誤っている場合だけ 400 を返し、
+// クエリの誤りやリゾルバーのエラーは 200 のレスポンスの errors で返す
+func (h *GraphQLHandler) Query(c echo.Context) error {
+	var req request
+	if c.Request().Method == http.MethodGet {
+		req.Query = c.QueryParam("query")
+		req.OperationName = c.QueryParam("operationName")
+		if raw := c.QueryParam("variables"); raw != "" {
+			if err := decodeJSON(strings.NewReader(raw), &req.Variables); err != nil {
+				return c.JSON(http.StatusBadRequest, errorResponse(errors.New("variables must be a JSON object")))
+			}
+		}
+	} else if err := decodeJSON(c.Request().Body, &req); err != nil {
+		return c.JSON(http.StatusBadRequest, errorResponse(errors.New("invalid request format")))
+	}
+	if req.Query == "" {
+		return c.JSON(http.StatusBadRequest, errorResponse(errors.New("query is required")))
+	}
+	if len(req.Query) > maxQueryLength {
+		return c.JSON(http.StatusOK, errorResponse(fmt.Errorf("query must be at most %d characters", maxQueryLength)))
+	}
+
+	ctx := gqlgen.StartOperationTrace(c.Request().Context())
+	opCtx, errs := h.exec.CreateOperationContext(ctx, &gqlgen.RawParams{
+		Query:         req.Query,
+		OperationName: req.OperationName,
+		Variables:     req.Variables,
+	})
+	if errs != nil {
+		response := h.exec.DispatchError(gqlgen.WithOperationContext(ctx, opCtx), errs)
+		return c.JSON(http.StatusOK, &requestErrors{Errors: response.Errors})
+	}
+
+	if opCtx.Operation.Operation == ast.Mutation {
+		// GET はキャッシュ・プリフェッチされるため、更新は POST だけで受け付ける
+		if c.Request().Method == http.MethodGet {
+			return c.JSON(http.StatusMethodNotAllowed, errorResponse(errors.New("mutations must be sent with POST")))
+		}
+		// /graphql は参照にも POST を使うため、読み取り専用モードはミドルウェアではなくここで判定する
+		if status := h.readOnly.Status(); status.Enabled {
+			readOnlyErr := codedError(codeReadOnly, "service is in read-only mode")
+			if status.Reason != "" {
+				readOnlyErr.Extensions["reason"] = status.Reason
+			}
+			return c.JSON(http.StatusServiceUnavailable, &requestErrors{Errors: gqlerror.List{readOnlyErr}})
+		}
+	}
+
+	responses, ctx := h.exec.DispatchOperation(ctx, opCtx)
+	return c.JSON(http.StatusOK, responses(ctx))
+}
+
+// gqlgen は数値の変数を json.Number で受け取る
+func decodeJSON(r io.Reader, v any) error {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+	return decoder.Decode(v)
+}
+
+// スキーマを SDL で返す
+func (h *GraphQLHandler) Schema(c echo.Context) error {
+	return c.String(http.StatusOK, schemaSDL)
+}
+
+// 実行する前に返すエラー。gqlgen.Response と違い data を含めない
+type requestErrors struct {
+	Errors gqlerror.List `json:"errors"`
+}
+
+func errorResponse(err error) *requestErrors {
+	return &requestErrors{Errors: gqlerror.List{{Message: err.Error()}}}
+}
+
+// フィールドの入れ子が深すぎるクエリを実行しない
+type depthLimit struct {
+	max int
+}
+
+var _ gqlgen.OperationContextMutator = depthLimit{}
+
+func (depthLimit) ExtensionName() string {
+	return "DepthLimit"
+}
+
+func (depthLimit) Validate(gqlgen.ExecutableSchema) error {
+	return nil
+}
+
+func (l depthLimit) MutateOperationContext(ctx context.Context, opCtx *gqlgen.OperationContext) *gqlerror.Error {
+	if depth(opCtx.Operation.SelectionSet) > l.max {
+		return gqlerror.Errorf("query must be at most %d levels deep", l.max)
+	}
+	return nil
+}
+
+func depth(selections ast.SelectionSet) int {
+	deepest := 0
+	for _, selection := range selections {
+		var d int
+		switch sel := selection.(type) {
+		case *ast.Field:
+			d = 1 + depth(sel.SelectionSet)
+		case *ast.InlineFragment:
+			d = depth(sel.SelectionSet)
+		case *ast.FragmentSpread:
+			if sel.Definition != nil {
+				d = depth(sel.Definition.SelectionSet)
+			}
+		}
+		deepest = max(deepest, d)
+	}
+	return deepest
+}
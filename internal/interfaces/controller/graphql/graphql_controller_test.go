@@ -0,0 +1,252 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/interfaces/middleware"
+	"Aicon-assignment/internal/pkg/listquery"
+	"Aicon-assignment/internal/usecase"
+)
+
+// 呼び出しを記録するだけのユースケース
+type fakeItemUsecase struct {
+	usecase.ItemUsecase
+	items   []*entity.Item
+	query   listquery.Query
+	created usecase.CreateItemInput
+	updated usecase.UpdateItemInput
+	deleted int64
+	err     error
+}
+
+func (f *fakeItemUsecase) GetItemByID(ctx context.Context, id int64) (*entity.Item, error) {
+	for _, item := range f.items {
+		if item.ID == id {
+			return item, nil
+		}
+	}
+	return nil, domainErrors.ErrItemNotFound
+}
+
+func (f *fakeItemUsecase) ListItems(ctx context.Context, query listquery.Query) (*listquery.Result[*entity.Item], error) {
+	f.query = query
+	return &listquery.Result[*entity.Item]{Items: f.items, Total: len(f.items), NextCursor: "next"}, nil
+}
+
+func (f *fakeItemUsecase) GetSummary(ctx context.Context, groupBy string) (*usecase.Summary, error) {
+	return &usecase.Summary{GroupBy: groupBy, Groups: map[string]int{"バッグ": 1, "時計": 2, "靴": 1}, Total: 4}, nil
+}
+
+func (f *fakeItemUsecase) CreateItem(ctx context.Context, input usecase.CreateItemInput) (*entity.Item, error) {
+	f.created = input
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &entity.Item{ID: 3, Name: input.Name}, nil
+}
+
+func (f *fakeItemUsecase) UpdateItem(ctx context.Context, id int64, input usecase.UpdateItemInput) (*entity.Item, error) {
+	f.updated = input
+	return &entity.Item{ID: id, Name: *input.Name}, nil
+}
+
+func (f *fakeItemUsecase) DeleteItem(ctx context.Context, id int64, reason string) error {
+	f.deleted = id
+	return f.err
+}
+
+func newTestHandler(t *testing.T) (*GraphQLHandler, *fakeItemUsecase, *middleware.ReadOnlyMode) {
+	orgID := int64(7)
+	items := &fakeItemUsecase{items: []*entity.Item{
+		{ID: 1, Name: "ロレックス デイトナ", Category: "時計", Brand: "ROLEX", PurchasePrice: 1500000, PurchaseDate: "2023-01-15",
+			CreatedAt: time.Date(2023, 1, 15, 0, 0, 0, 0, time.UTC), OrgID: &orgID},
+		{ID: 2, Name: "エルメス バーキン", Category: "バッグ", Brand: "HERMÈS", PurchasePrice: 2000000, PurchaseDate: "2023-02-20"},
+	}}
+	readOnly := middleware.NewReadOnlyMode(false, "")
+	return NewGraphQLHandler(items, readOnly), items, readOnly
+}
+
+func post(t *testing.T, h *GraphQLHandler, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	require.NoError(t, h.Query(echo.New().NewContext(req, rec)))
+	return rec
+}
+
+func TestGraphQLHandler_Query(t *testing.T) {
+	t.Run("正常系: アイテムを1件取得する", func(t *testing.T) {
+		h, _, _ := newTestHandler(t)
+
+		rec := post(t, h, `{"query": "{ item(id: \"1\") { id name purchasePrice createdAt organizationId ownerId } }"}`)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"data": {"item": {"id": "1", "name": "ロレックス デイトナ", "purchasePrice": 1500000,
+			"createdAt": "2023-01-15T00:00:00Z", "organizationId": "7", "ownerId": null}}}`, rec.Body.String())
+	})
+
+	t.Run("正常系: 一覧の引数を GET /items と同じクエリにする", func(t *testing.T) {
+		h, items, _ := newTestHandler(t)
+
+		rec := post(t, h, `{"query": "query ($min: Int) { items(category: \"時計\", minPrice: $min, sort: \"-purchase_price\", page: 2, pageSize: 5) { totalCount nextCursor items { id } } }", "variables": {"min": 1000000}}`)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"data": {"items": {"totalCount": 2, "nextCursor": "next", "items": [{"id": "1"}, {"id": "2"}]}}}`, rec.Body.String())
+
+		expected, err := listquery.Parse(url.Values{
+			"category": {"時計"}, "min_price": {"1000000"}, "sort": {"-purchase_price"}, "page[number]": {"2"}, "page[size]": {"5"},
+		}, entity.ItemListSpec)
+		require.NoError(t, err)
+		assert.Equal(t, expected, items.query)
+	})
+
+	t.Run("正常系: カーソルで読む場合は総数を返さない", func(t *testing.T) {
+		h, _, _ := newTestHandler(t)
+
+		rec := post(t, h, `{"query": "{ items(first: 2, after: \"\") { totalCount nextCursor } }"}`)
+		assert.JSONEq(t, `{"data": {"items": {"totalCount": null, "nextCursor": "next"}}}`, rec.Body.String())
+	})
+
+	t.Run("正常系: 集計は件数の多い順", func(t *testing.T) {
+		h, _, _ := newTestHandler(t)
+
+		rec := post(t, h, `{"query": "{ summary { groupBy total groups { key count } } }"}`)
+		assert.JSONEq(t, `{"data": {"summary": {"groupBy": "CATEGORY", "total": 4,
+			"groups": [{"key": "時計", "count": 2}, {"key": "バッグ", "count": 1}, {"key": "靴", "count": 1}]}}}`, rec.Body.String())
+	})
+
+	t.Run("正常系: 登録・更新・削除", func(t *testing.T) {
+		h, items, _ := newTestHandler(t)
+
+		rec := post(t, h, `{"query": "mutation { createItem(input: {name: \"ケリー\", category: \"バッグ\", brand: \"HERMÈS\", purchasePrice: 1800000, purchaseDate: \"2024-01-01\", organizationId: 7}) { id name } }"}`)
+		assert.JSONEq(t, `{"data": {"createItem": {"id": "3", "name": "ケリー"}}}`, rec.Body.String())
+		assert.Equal(t, 1800000, items.created.PurchasePrice)
+		require.NotNil(t, items.created.OrgID)
+		assert.Equal(t, int64(7), *items.created.OrgID)
+		assert.False(t, items.created.ConfirmPrice)
+
+		rec = post(t, h, `{"query": "mutation { updateItem(id: \"1\", input: {name: \"デイトナ\"}) { name } }"}`)
+		assert.JSONEq(t, `{"data": {"updateItem": {"name": "デイトナ"}}}`, rec.Body.String())
+		assert.Nil(t, items.updated.PurchasePrice)
+
+		rec = post(t, h, `{"query": "mutation { deleteItem(id: \"2\") }"}`)
+		assert.JSONEq(t, `{"data": {"deleteItem": true}}`, rec.Body.String())
+		assert.Equal(t, int64(2), items.deleted)
+	})
+
+	t.Run("正常系: GET で参照する", func(t *testing.T) {
+		h, _, _ := newTestHandler(t)
+
+		req := httptest.NewRequest(http.MethodGet, "/graphql?query="+url.QueryEscape(`query ($id: ID!) { item(id: $id) { name } }`)+"&variables="+url.QueryEscape(`{"id": "2"}`), nil)
+		rec := httptest.NewRecorder()
+		require.NoError(t, h.Query(echo.New().NewContext(req, rec)))
+		assert.JSONEq(t, `{"data": {"item": {"name": "エルメス バーキン"}}}`, rec.Body.String())
+	})
+
+	errorTests := []struct {
+		name         string
+		query        string
+		err          error
+		expectedCode string
+	}{
+		{name: "異常系: 存在しないアイテム", query: `{ item(id: \"9\") { id } }`, expectedCode: "NOT_FOUND"},
+		{name: "異常系: 不正な ID", query: `{ item(id: \"abc\") { id } }`, expectedCode: "BAD_USER_INPUT"},
+		{name: "異常系: 更新する項目がない", query: `mutation { updateItem(id: \"1\", input: {}) { id } }`, expectedCode: "BAD_USER_INPUT"},
+		{name: "異常系: 入力の誤り", query: `mutation { deleteItem(id: \"1\") }`, err: fmt.Errorf("%w: name is required", domainErrors.ErrInvalidInput), expectedCode: "BAD_USER_INPUT"},
+		{name: "異常系: 権限がない", query: `mutation { deleteItem(id: \"1\") }`, err: domainErrors.ErrForbidden, expectedCode: "FORBIDDEN"},
+		{name: "異常系: 理由がない", query: `mutation { deleteItem(id: \"1\") }`, err: domainErrors.ErrReasonRequired, expectedCode: "REASON_REQUIRED"},
+		{name: "異常系: 分類できないエラーは内容を返さない", query: `mutation { deleteItem(id: \"1\") }`, err: fmt.Errorf("connection refused"), expectedCode: "INTERNAL_SERVER_ERROR"},
+	}
+	for _, tt := range errorTests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, items, _ := newTestHandler(t)
+			items.err = tt.err
+
+			rec := post(t, h, `{"query": "`+tt.query+`"}`)
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Contains(t, rec.Body.String(), `"code":"`+tt.expectedCode+`"`)
+			assert.NotContains(t, rec.Body.String(), "connection refused")
+		})
+	}
+
+	t.Run("異常系: クエリの誤りは data を含めない", func(t *testing.T) {
+		h, _, _ := newTestHandler(t)
+
+		rec := post(t, h, `{"query": "{ items { color } }"}`)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"errors": [{"message": "Cannot query field \"color\" on type \"ItemConnection\".", "locations": [{"line": 1, "column": 11}],
+			"extensions": {"code": "GRAPHQL_VALIDATION_FAILED"}}]}`, rec.Body.String())
+	})
+
+	t.Run("異常系: 長すぎるクエリは解析しない", func(t *testing.T) {
+		h, _, _ := newTestHandler(t)
+
+		rec := post(t, h, `{"query": "{ `+strings.Repeat("id ", maxQueryLength/3+1)+`}"}`)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"errors": [{"message": "query must be at most 10000 characters"}]}`, rec.Body.String())
+	})
+
+	t.Run("異常系: リクエストの形が誤っている", func(t *testing.T) {
+		h, _, _ := newTestHandler(t)
+
+		assert.Equal(t, http.StatusBadRequest, post(t, h, `{"query": `).Code)
+		assert.Equal(t, http.StatusBadRequest, post(t, h, `{}`).Code)
+	})
+
+	t.Run("異常系: GET では更新できない", func(t *testing.T) {
+		h, items, _ := newTestHandler(t)
+
+		req := httptest.NewRequest(http.MethodGet, "/graphql?query="+url.QueryEscape(`mutation { deleteItem(id: "1") }`), nil)
+		rec := httptest.NewRecorder()
+		require.NoError(t, h.Query(echo.New().NewContext(req, rec)))
+		assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+		assert.Zero(t, items.deleted)
+	})
+
+	t.Run("異常系: 読み取り専用モードでは更新を拒否し、参照は受け付ける", func(t *testing.T) {
+		h, items, readOnly := newTestHandler(t)
+		_, err := readOnly.Set(true, "maintenance")
+		require.NoError(t, err)
+
+		rec := post(t, h, `{"query": "mutation { deleteItem(id: \"1\") }"}`)
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+		assert.JSONEq(t, `{"errors": [{"message": "service is in read-only mode", "extensions": {"code": "READ_ONLY", "reason": "maintenance"}}]}`, rec.Body.String())
+		assert.Zero(t, items.deleted)
+
+		rec = post(t, h, `{"query": "{ item(id: \"1\") { id } }"}`)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+}
+
+func TestDepth(t *testing.T) {
+	doc, err := parser.ParseQuery(&ast.Source{Input: `{ a { ... on T { b { c } } } d }`})
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, depth(doc.Operations[0].SelectionSet), "インラインフラグメントは深さに数えない")
+}
+
+func TestGraphQLHandler_Schema(t *testing.T) {
+	h, _, _ := newTestHandler(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/graphql/schema", nil)
+	rec := httptest.NewRecorder()
+	require.NoError(t, h.Schema(echo.New().NewContext(req, rec)))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "type Query {")
+	assert.Contains(t, rec.Body.String(), "  deleteItem(id: ID!, reason: String): Boolean!\n")
+	assert.Contains(t, rec.Body.String(), "enum SummaryDimension {\n  CATEGORY\n  BRAND\n  YEAR\n}\n")
+}
@@ -0,0 +1,172 @@
+// Code generated by github.com/99designs/gqlgen, DO NOT EDIT.
+
+package model
+
+import (
+	"Aicon-assignment/internal/domain/entity"
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+type CreateItemInput struct {
+	Name          string `json:"name"`
+	Category      string `json:"category"`
+	Brand         string `json:"brand"`
+	PurchasePrice int    `json:"purchasePrice"`
+	// YYYY-MM-DD
+	PurchaseDate   string  `json:"purchaseDate"`
+	OrganizationID *string `json:"organizationId,omitempty"`
+	// true の場合は strict モードでも相場から外れた価格を受け付ける
+	ConfirmPrice *bool `json:"confirmPrice,omitempty"`
+}
+
+type ItemConnection struct {
+	Items []*entity.Item `json:"items"`
+	// 条件に合うアイテムの総数。カーソル（after）で読む場合は null
+	TotalCount *int `json:"totalCount,omitempty"`
+	// 次のページの after。最後のページでは null
+	NextCursor *string `json:"nextCursor,omitempty"`
+}
+
+type Mutation struct {
+}
+
+type Query struct {
+}
+
+type Summary struct {
+	GroupBy SummaryDimension `json:"groupBy"`
+	Total   int              `json:"total"`
+	// 件数の多い順（同じ件数は名前の順）
+	Groups []*SummaryGroup `json:"groups"`
+}
+
+type SummaryGroup struct {
+	Key   string `json:"key"`
+	Count int    `json:"count"`
+}
+
+type UpdateItemInput struct {
+	Name          *string `json:"name,omitempty"`
+	Brand         *string `json:"brand,omitempty"`
+	PurchasePrice *int    `json:"purchasePrice,omitempty"`
+	// 価格変更の理由
+	Reason       *string `json:"reason,omitempty"`
+	ConfirmPrice *bool   `json:"confirmPrice,omitempty"`
+	// ロックされたフィールドも変更する（管理者のみ、理由が必須）
+	OverrideLocks *bool `json:"overrideLocks,omitempty"`
+}
+
+type SortOrder string
+
+const (
+	SortOrderAsc  SortOrder = "ASC"
+	SortOrderDesc SortOrder = "DESC"
+)
+
+var AllSortOrder = []SortOrder{
+	SortOrderAsc,
+	SortOrderDesc,
+}
+
+func (e SortOrder) IsValid() bool {
+	switch e {
+	case SortOrderAsc, SortOrderDesc:
+		return true
+	}
+	return false
+}
+
+func (e SortOrder) String() string {
+	return string(e)
+}
+
+func (e *SortOrder) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = SortOrder(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid SortOrder", str)
+	}
+	return nil
+}
+
+func (e SortOrder) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *SortOrder) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e SortOrder) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type SummaryDimension string
+
+const (
+	SummaryDimensionCategory SummaryDimension = "CATEGORY"
+	SummaryDimensionBrand    SummaryDimension = "BRAND"
+	SummaryDimensionYear     SummaryDimension = "YEAR"
+)
+
+var AllSummaryDimension = []SummaryDimension{
+	SummaryDimensionCategory,
+	SummaryDimensionBrand,
+	SummaryDimensionYear,
+}
+
+func (e SummaryDimension) IsValid() bool {
+	switch e {
+	case SummaryDimensionCategory, SummaryDimensionBrand, SummaryDimensionYear:
+		return true
+	}
+	return false
+}
+
+func (e SummaryDimension) String() string {
+	return string(e)
+}
+
+func (e *SummaryDimension) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = SummaryDimension(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid SummaryDimension", str)
+	}
+	return nil
+}
+
+func (e SummaryDimension) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *SummaryDimension) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e SummaryDimension) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
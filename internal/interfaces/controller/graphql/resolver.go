@@ -0,0 +1,67 @@
+// Package graphql は /items と同じ ItemUsecase を GraphQL で公開する。
+// スキーマは schema.graphqls にあり、generated と model は gqlgen で作る
+package graphql
+
+//go:generate go run github.com/99designs/gqlgen generate
+
+import (
+	"errors"
+	"net/url"
+	"sort"
+	"strconv"
+
+	"Aicon-assignment/internal/interfaces/controller/graphql/model"
+	"Aicon-assignment/internal/usecase"
+)
+
+// リゾルバーは REST の /items と同じ ItemUsecase を呼ぶ
+type Resolver struct {
+	items usecase.ItemUsecase
+}
+
+func parseID(raw string) (int64, error) {
+	id, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, badUserInput(errors.New("invalid item ID"))
+	}
+	return id, nil
+}
+
+func optionalID(id *int64) *string {
+	if id == nil {
+		return nil
+	}
+	s := strconv.FormatInt(*id, 10)
+	return &s
+}
+
+// 省略した場合は既定値の false
+func flag(value *bool) bool {
+	return value != nil && *value
+}
+
+func setParam(values url.Values, name string, value *string) {
+	if value != nil {
+		values.Set(name, *value)
+	}
+}
+
+func setIntParam(values url.Values, name string, value *int) {
+	if value != nil {
+		values.Set(name, strconv.Itoa(*value))
+	}
+}
+
+func summaryGroups(summary *usecase.Summary) []*model.SummaryGroup {
+	groups := make([]*model.SummaryGroup, 0, len(summary.Groups))
+	for key, count := range summary.Groups {
+		groups = append(groups, &model.SummaryGroup{Key: key, Count: count})
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Count != groups[j].Count {
+			return groups[i].Count > groups[j].Count
+		}
+		return groups[i].Key < groups[j].Key
+	})
+	return groups
+}
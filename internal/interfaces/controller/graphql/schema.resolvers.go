@@ -0,0 +1,203 @@
+package graphql
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen
+
+import (
+	"Aicon-assignment/internal/domain/entity"
+	"Aicon-assignment/internal/interfaces/controller/graphql/generated"
+	"Aicon-assignment/internal/interfaces/controller/graphql/model"
+	"Aicon-assignment/internal/pkg/listquery"
+	"Aicon-assignment/internal/usecase"
+	"context"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ID is the resolver for the id field.
+func (r *itemResolver) ID(ctx context.Context, obj *entity.Item) (string, error) {
+	return strconv.FormatInt(obj.ID, 10), nil
+}
+
+// CreatedAt is the resolver for the createdAt field.
+func (r *itemResolver) CreatedAt(ctx context.Context, obj *entity.Item) (string, error) {
+	return obj.CreatedAt.Format(time.RFC3339), nil
+}
+
+// UpdatedAt is the resolver for the updatedAt field.
+func (r *itemResolver) UpdatedAt(ctx context.Context, obj *entity.Item) (string, error) {
+	return obj.UpdatedAt.Format(time.RFC3339), nil
+}
+
+// OrganizationID is the resolver for the organizationId field.
+func (r *itemResolver) OrganizationID(ctx context.Context, obj *entity.Item) (*string, error) {
+	return optionalID(obj.OrgID), nil
+}
+
+// OwnerID is the resolver for the ownerId field.
+func (r *itemResolver) OwnerID(ctx context.Context, obj *entity.Item) (*string, error) {
+	return optionalID(obj.OwnerID), nil
+}
+
+// CreateItem is the resolver for the createItem field.
+func (r *mutationResolver) CreateItem(ctx context.Context, input model.CreateItemInput) (*entity.Item, error) {
+	create := usecase.CreateItemInput{
+		Name:          input.Name,
+		Category:      input.Category,
+		Brand:         input.Brand,
+		PurchasePrice: input.PurchasePrice,
+		PurchaseDate:  input.PurchaseDate,
+		ConfirmPrice:  flag(input.ConfirmPrice),
+	}
+	if input.OrganizationID != nil {
+		orgID, err := parseID(*input.OrganizationID)
+		if err != nil {
+			return nil, err
+		}
+		create.OrgID = &orgID
+	}
+
+	item, err := r.items.CreateItem(ctx, create)
+	if err != nil {
+		return nil, resolverError(ctx, err, "failed to create item")
+	}
+	return item, nil
+}
+
+// UpdateItem is the resolver for the updateItem field.
+func (r *mutationResolver) UpdateItem(ctx context.Context, id string, input model.UpdateItemInput) (*entity.Item, error) {
+	itemID, err := parseID(id)
+	if err != nil {
+		return nil, err
+	}
+	if input.Name == nil && input.Brand == nil && input.PurchasePrice == nil {
+		return nil, badUserInput(errors.New("at least one field must be provided for update"))
+	}
+
+	item, err := r.items.UpdateItem(ctx, itemID, usecase.UpdateItemInput{
+		Name:          input.Name,
+		Brand:         input.Brand,
+		PurchasePrice: input.PurchasePrice,
+		Reason:        input.Reason,
+		ConfirmPrice:  flag(input.ConfirmPrice),
+		OverrideLocks: flag(input.OverrideLocks),
+	})
+	if err != nil {
+		return nil, resolverError(ctx, err, "failed to update item")
+	}
+	return item, nil
+}
+
+// DeleteItem is the resolver for the deleteItem field.
+func (r *mutationResolver) DeleteItem(ctx context.Context, id string, reason *string) (bool, error) {
+	itemID, err := parseID(id)
+	if err != nil {
+		return false, err
+	}
+	if reason == nil {
+		reason = new(string)
+	}
+	if err := r.items.DeleteItem(ctx, itemID, *reason); err != nil {
+		return false, resolverError(ctx, err, "failed to delete item")
+	}
+	return true, nil
+}
+
+// Item is the resolver for the item field.
+func (r *queryResolver) Item(ctx context.Context, id string) (*entity.Item, error) {
+	itemID, err := parseID(id)
+	if err != nil {
+		return nil, err
+	}
+	item, err := r.items.GetItemByID(ctx, itemID)
+	if err != nil {
+		return nil, resolverError(ctx, err, "failed to retrieve item")
+	}
+	return item, nil
+}
+
+// Items is the resolver for the items field.
+func (r *queryResolver) Items(ctx context.Context, filter *string, category *string, brand *string, minPrice *int, maxPrice *int, purchasedFrom *string, purchasedTo *string, sort *string, order *model.SortOrder, page *int, pageSize *int, first *int, after *string) (*model.ItemConnection, error) {
+	// 引数を GET /items のクエリパラメータにして同じ規則で解釈する
+	values := url.Values{}
+	setParam(values, "filter", filter)
+	setParam(values, "category", category)
+	setParam(values, "brand", brand)
+	setIntParam(values, "min_price", minPrice)
+	setIntParam(values, "max_price", maxPrice)
+	setParam(values, "purchased_from", purchasedFrom)
+	setParam(values, "purchased_to", purchasedTo)
+	setParam(values, "sort", sort)
+	if order != nil {
+		values.Set("order", strings.ToLower(string(*order)))
+	}
+	setIntParam(values, "page[number]", page)
+	setIntParam(values, "page[size]", pageSize)
+	setIntParam(values, "limit", first)
+	setParam(values, "cursor", after)
+
+	q, err := listquery.Parse(values, entity.ItemListSpec)
+	if err != nil {
+		return nil, badUserInput(err)
+	}
+	result, err := r.items.ListItems(ctx, q)
+	if err != nil {
+		return nil, resolverError(ctx, err, "failed to retrieve items")
+	}
+
+	connection := &model.ItemConnection{Items: result.Items}
+	if after == nil {
+		connection.TotalCount = &result.Total
+	}
+	if result.NextCursor != "" {
+		connection.NextCursor = &result.NextCursor
+	}
+	return connection, nil
+}
+
+// Search is the resolver for the search field.
+func (r *queryResolver) Search(ctx context.Context, q string, limit *int) ([]*entity.Item, error) {
+	n := 0
+	if limit != nil {
+		n = *limit
+	}
+	items, err := r.items.SearchItems(ctx, q, n)
+	if err != nil {
+		return nil, resolverError(ctx, err, "failed to search items")
+	}
+	return items, nil
+}
+
+// Summary is the resolver for the summary field.
+func (r *queryResolver) Summary(ctx context.Context, groupBy *model.SummaryDimension) (*model.Summary, error) {
+	dim := model.SummaryDimensionCategory
+	if groupBy != nil {
+		dim = *groupBy
+	}
+	summary, err := r.items.GetSummary(ctx, strings.ToLower(string(dim)))
+	if err != nil {
+		return nil, resolverError(ctx, err, "failed to retrieve summary")
+	}
+	return &model.Summary{
+		GroupBy: model.SummaryDimension(strings.ToUpper(summary.GroupBy)),
+		Total:   summary.Total,
+		Groups:  summaryGroups(summary),
+	}, nil
+}
+
+// Item returns generated.ItemResolver implementation.
+func (r *Resolver) Item() generated.ItemResolver { return &itemResolver{r} }
+
+// Mutation returns generated.MutationResolver implementation.
+func (r *Resolver) Mutation() generated.MutationResolver { return &mutationResolver{r} }
+
+// Query returns generated.QueryResolver implementation.
+func (r *Resolver) Query() generated.QueryResolver { return &queryResolver{r} }
+
+type itemResolver struct{ *Resolver }
+type mutationResolver struct{ *Resolver }
+type queryResolver struct{ *Resolver }
@@ -0,0 +1,6 @@
+//go:build tools
+
+package graphql
+
+// go generate で使う gqlgen を go.mod に残す
+import _ "github.com/99designs/gqlgen"
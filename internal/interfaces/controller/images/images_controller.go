@@ -0,0 +1,200 @@
+package images
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/interfaces/controller/response"
+	"Aicon-assignment/internal/pkg/reqctx"
+	"Aicon-assignment/internal/usecase"
+)
+
+type ImageHandler struct {
+	imageUsecase usecase.ImageUsecase
+	maxSize      int64 // 1 枚のサイズの上限（バイト）
+}
+
+func NewImageHandler(imageUsecase usecase.ImageUsecase, maxSize int64) *ImageHandler {
+	return &ImageHandler{
+		imageUsecase: imageUsecase,
+		maxSize:      maxSize,
+	}
+}
+
+// multipart の file で送られた画像をアイテムに追加する
+func (h *ImageHandler) Upload(c echo.Context) error {
+	itemID, ok := response.ParseID(c, "id")
+	if !ok {
+		return response.Error(c, http.StatusBadRequest, "invalid item ID")
+	}
+	header, err := c.FormFile("file")
+	if err != nil {
+		return response.ValidationError(c, errors.New("file is required"))
+	}
+	if header.Size > h.maxSize {
+		return response.Error(c, http.StatusRequestEntityTooLarge, fmt.Sprintf("image must be at most %d bytes", h.maxSize))
+	}
+	file, err := header.Open()
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, "failed to read file")
+	}
+	defer file.Close()
+
+	image, err := h.imageUsecase.Upload(c.Request().Context(), itemID, header.Filename, header.Header.Get(echo.HeaderContentType), file)
+	if err != nil {
+		return errorResponse(c, err, "failed to upload image")
+	}
+	return c.JSON(http.StatusCreated, image)
+}
+
+func (h *ImageHandler) List(c echo.Context) error {
+	itemID, ok := response.ParseID(c, "id")
+	if !ok {
+		return response.Error(c, http.StatusBadRequest, "invalid item ID")
+	}
+	images, err := h.imageUsecase.List(c.Request().Context(), itemID)
+	if err != nil {
+		return errorResponse(c, err, "failed to list images")
+	}
+	return c.JSON(http.StatusOK, images)
+}
+
+// 画像のファイルを返す。直接取得する URL がある場合はそこへリダイレクトし、API サーバーを経由させない
+func (h *ImageHandler) Download(c echo.Context) error {
+	itemID, imageID, ok := parseIDs(c)
+	if !ok {
+		return response.Error(c, http.StatusBadRequest, "invalid ID")
+	}
+	image, err := h.imageUsecase.Get(c.Request().Context(), itemID, imageID)
+	if err != nil {
+		return errorResponse(c, err, "failed to open image")
+	}
+	if image.URL != "" {
+		return c.Redirect(http.StatusFound, image.URL)
+	}
+
+	body, image, err := h.imageUsecase.Open(c.Request().Context(), itemID, imageID)
+	if err != nil {
+		return errorResponse(c, err, "failed to open image")
+	}
+	defer body.Close()
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, image.ContentType)
+	res.Header().Set(echo.HeaderContentDisposition, mime.FormatMediaType("inline", map[string]string{"filename": image.Filename}))
+	res.Header().Set(echo.HeaderContentLength, strconv.FormatInt(image.Size, 10))
+	res.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(res, body); err != nil {
+		// ステータスは送信済みのため、途中で切れたことはログにだけ残す
+		reqctx.Logger(c.Request().Context()).Error("image download aborted", "image_id", image.ID, "error", err)
+	}
+	return nil
+}
+
+// ギャラリーでの順番・一覧に表示する画像を変える
+func (h *ImageHandler) Update(c echo.Context) error {
+	itemID, imageID, ok := parseIDs(c)
+	if !ok {
+		return response.Error(c, http.StatusBadRequest, "invalid ID")
+	}
+	var input usecase.UpdateImageInput
+	if err := c.Bind(&input); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	image, err := h.imageUsecase.Update(c.Request().Context(), itemID, imageID, input)
+	if err != nil {
+		return errorResponse(c, err, "failed to update image")
+	}
+	return c.JSON(http.StatusOK, image)
+}
+
+// サムネイルのファイルを返す。画像と同じく、直接取得する URL がある場合はそこへリダイレクトする
+func (h *ImageHandler) DownloadThumbnail(c echo.Context) error {
+	itemID, imageID, ok := parseIDs(c)
+	if !ok {
+		return response.Error(c, http.StatusBadRequest, "invalid ID")
+	}
+	size := c.Param("size")
+	image, err := h.imageUsecase.Get(c.Request().Context(), itemID, imageID)
+	if err != nil {
+		return errorResponse(c, err, "failed to open thumbnail")
+	}
+	if image.URL != "" {
+		thumb := image.Thumbnail(size)
+		if thumb == nil {
+			return response.Error(c, http.StatusNotFound, "thumbnail not found")
+		}
+		return c.Redirect(http.StatusFound, thumb.URL)
+	}
+
+	body, thumb, err := h.imageUsecase.OpenThumbnail(c.Request().Context(), itemID, imageID, size)
+	if err != nil {
+		return errorResponse(c, err, "failed to open thumbnail")
+	}
+	defer body.Close()
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, "inline")
+	if err := c.Stream(http.StatusOK, thumb.ContentType, body); err != nil {
+		reqctx.Logger(c.Request().Context()).Error("thumbnail download aborted", "image_id", imageID, "size", size, "error", err)
+	}
+	return nil
+}
+
+// サムネイルが未生成・生成に失敗した画像のサムネイルをバックグラウンドで作り直す
+func (h *ImageHandler) ReprocessThumbnails(c echo.Context) error {
+	queued, err := h.imageUsecase.ReprocessThumbnails(c.Request().Context())
+	if err != nil {
+		return response.RepositoryError(c, err, "failed to reprocess thumbnails")
+	}
+	return c.JSON(http.StatusAccepted, map[string]int{"queued": queued})
+}
+
+func (h *ImageHandler) Delete(c echo.Context) error {
+	itemID, imageID, ok := parseIDs(c)
+	if !ok {
+		return response.Error(c, http.StatusBadRequest, "invalid ID")
+	}
+	if err := h.imageUsecase.Delete(c.Request().Context(), itemID, imageID); err != nil {
+		return errorResponse(c, err, "failed to delete image")
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func parseIDs(c echo.Context) (int64, int64, bool) {
+	itemID, ok := response.ParseID(c, "id")
+	if !ok {
+		return 0, 0, false
+	}
+	imageID, ok := response.ParseID(c, "imageId")
+	return itemID, imageID, ok
+}
+
+func errorResponse(c echo.Context, err error, fallback string) error {
+	if errors.Is(err, domainErrors.ErrItemNotFound) {
+		return response.Error(c, http.StatusNotFound, "item not found")
+	}
+	if errors.Is(err, domainErrors.ErrThumbnailNotFound) {
+		return response.Error(c, http.StatusNotFound, "thumbnail not found")
+	}
+	if domainErrors.IsNotFoundError(err) {
+		return response.Error(c, http.StatusNotFound, "image not found")
+	}
+	if domainErrors.IsValidationError(err) {
+		return response.ValidationError(c, err)
+	}
+	if errors.Is(err, domainErrors.ErrInfectedFile) {
+		return response.Error(c, http.StatusUnprocessableEntity, err.Error())
+	}
+	if errors.Is(err, domainErrors.ErrScanUnavailable) {
+		return response.Error(c, http.StatusServiceUnavailable, "virus scan is unavailable, try again later")
+	}
+	return response.RepositoryError(c, err, fallback)
+}
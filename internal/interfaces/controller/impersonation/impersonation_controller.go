@@ -0,0 +1,93 @@
+package impersonation
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/interfaces/controller/response"
+	"Aicon-assignment/internal/usecase"
+)
+
+type ImpersonationHandler struct {
+	impersonationUsecase usecase.ImpersonationUsecase
+}
+
+func NewImpersonationHandler(impersonationUsecase usecase.ImpersonationUsecase) *ImpersonationHandler {
+	return &ImpersonationHandler{
+		impersonationUsecase: impersonationUsecase,
+	}
+}
+
+// なりすましセッションを開始し、トークンを返す
+func (h *ImpersonationHandler) Start(c echo.Context) error {
+	userID, ok := response.ParseID(c, "userId")
+	if !ok {
+		return response.Error(c, http.StatusBadRequest, "invalid user ID")
+	}
+
+	var input usecase.StartImpersonationInput
+	if err := c.Bind(&input); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	session, err := h.impersonationUsecase.Start(c.Request().Context(), userID, input)
+	if err != nil {
+		return h.errorResponse(c, err, "failed to start impersonation")
+	}
+
+	return c.JSON(http.StatusCreated, session)
+}
+
+// ?active=true の場合は有効なセッションだけを返す
+func (h *ImpersonationHandler) List(c echo.Context) error {
+	activeOnly := false
+	if raw := c.QueryParam("active"); raw != "" {
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			return response.Error(c, http.StatusBadRequest, "invalid active")
+		}
+		activeOnly = parsed
+	}
+
+	sessions, err := h.impersonationUsecase.List(c.Request().Context(), activeOnly)
+	if err != nil {
+		return response.RepositoryError(c, err, "failed to retrieve impersonation sessions")
+	}
+
+	return c.JSON(http.StatusOK, sessions)
+}
+
+// 期限前にセッションを終了する
+func (h *ImpersonationHandler) End(c echo.Context) error {
+	id, ok := response.ParseID(c, "id")
+	if !ok {
+		return response.Error(c, http.StatusBadRequest, "invalid session ID")
+	}
+
+	session, err := h.impersonationUsecase.End(c.Request().Context(), id)
+	if err != nil {
+		return h.errorResponse(c, err, "failed to end impersonation")
+	}
+
+	return c.JSON(http.StatusOK, session)
+}
+
+func (h *ImpersonationHandler) errorResponse(c echo.Context, err error, fallback string) error {
+	switch {
+	case errors.Is(err, domainErrors.ErrUserNotFound):
+		return response.Error(c, http.StatusNotFound, "user not found")
+	case domainErrors.IsNotFoundError(err):
+		return response.Error(c, http.StatusNotFound, "impersonation session not found")
+	case domainErrors.IsValidationError(err):
+		return response.ValidationError(c, err)
+	case domainErrors.IsUnauthenticatedError(err):
+		return response.Error(c, http.StatusUnauthorized, "authentication required")
+	case domainErrors.IsForbiddenError(err):
+		return response.Error(c, http.StatusForbidden, "cannot start impersonation while impersonating")
+	}
+	return response.RepositoryError(c, err, fallback)
+}
@@ -0,0 +1,103 @@
+package importprofiles
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/interfaces/controller/response"
+	"Aicon-assignment/internal/usecase"
+)
+
+type ImportProfileHandler struct {
+	importProfileUsecase usecase.ImportProfileUsecase
+}
+
+func NewImportProfileHandler(importProfileUsecase usecase.ImportProfileUsecase) *ImportProfileHandler {
+	return &ImportProfileHandler{
+		importProfileUsecase: importProfileUsecase,
+	}
+}
+
+// 呼び出し元のユーザーのプロファイルを名前の順に返す
+func (h *ImportProfileHandler) ListImportProfiles(c echo.Context) error {
+	profiles, err := h.importProfileUsecase.List(c.Request().Context())
+	if err != nil {
+		return h.errorResponse(c, err, "failed to retrieve import profiles")
+	}
+
+	return c.JSON(http.StatusOK, profiles)
+}
+
+func (h *ImportProfileHandler) GetImportProfile(c echo.Context) error {
+	id, ok := response.ParseID(c, "id")
+	if !ok {
+		return response.Error(c, http.StatusBadRequest, "invalid import profile ID")
+	}
+
+	profile, err := h.importProfileUsecase.Get(c.Request().Context(), id)
+	if err != nil {
+		return h.errorResponse(c, err, "failed to retrieve import profile")
+	}
+
+	return c.JSON(http.StatusOK, profile)
+}
+
+func (h *ImportProfileHandler) CreateImportProfile(c echo.Context) error {
+	var input usecase.ImportProfileInput
+	if err := c.Bind(&input); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	profile, err := h.importProfileUsecase.Create(c.Request().Context(), input)
+	if err != nil {
+		return h.errorResponse(c, err, "failed to create import profile")
+	}
+
+	return c.JSON(http.StatusCreated, profile)
+}
+
+func (h *ImportProfileHandler) UpdateImportProfile(c echo.Context) error {
+	id, ok := response.ParseID(c, "id")
+	if !ok {
+		return response.Error(c, http.StatusBadRequest, "invalid import profile ID")
+	}
+
+	var input usecase.UpdateImportProfileInput
+	if err := c.Bind(&input); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	profile, err := h.importProfileUsecase.Update(c.Request().Context(), id, input)
+	if err != nil {
+		return h.errorResponse(c, err, "failed to update import profile")
+	}
+
+	return c.JSON(http.StatusOK, profile)
+}
+
+func (h *ImportProfileHandler) DeleteImportProfile(c echo.Context) error {
+	id, ok := response.ParseID(c, "id")
+	if !ok {
+		return response.Error(c, http.StatusBadRequest, "invalid import profile ID")
+	}
+
+	if err := h.importProfileUsecase.Delete(c.Request().Context(), id); err != nil {
+		return h.errorResponse(c, err, "failed to delete import profile")
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *ImportProfileHandler) errorResponse(c echo.Context, err error, fallback string) error {
+	switch {
+	case domainErrors.IsUnauthenticatedError(err):
+		return response.Error(c, http.StatusUnauthorized, "authentication required")
+	case domainErrors.IsNotFoundError(err):
+		return response.Error(c, http.StatusNotFound, "import profile not found")
+	case domainErrors.IsValidationError(err):
+		return response.ValidationError(c, err)
+	}
+	return response.RepositoryError(c, err, fallback)
+}
@@ -0,0 +1,221 @@
+package controller
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"Aicon-assignment/internal/domain/entity"
+	"Aicon-assignment/internal/interfaces/controller/response"
+	"Aicon-assignment/internal/pkg/listquery"
+	"Aicon-assignment/internal/pkg/reqctx"
+	"Aicon-assignment/internal/pkg/xlsx"
+)
+
+// Excel が UTF-8 と判定するためのバイトオーダーマーク
+const utf8BOM = "\ufeff"
+
+var itemCSVHeader = []string{"id", "name", "category", "brand", "purchase_price", "purchase_date", "organization_id", "created_at", "updated_at"}
+
+// 絞り込みに一致する全アイテムを CSV（?format=xlsx の場合は Excel、?format=ndjson の場合は 1 行 1 件の JSON）で返す
+// 絞り込みは GET /items と同じパラメータで指定する
+// ?bom=true で CSV の先頭に BOM を付ける（Excel で開く場合）
+// 全件をメモリに載せないよう、読み込んだ分から順に書き出す
+func (h *ItemHandler) ExportItems(c echo.Context) error {
+	format := c.QueryParam("format")
+	if format != "" && format != "csv" && format != "xlsx" && format != "ndjson" {
+		return response.Error(c, http.StatusBadRequest, "format must be csv, xlsx or ndjson")
+	}
+	q, err := listquery.Parse(c.QueryParams(), entity.ItemListSpec)
+	if err != nil {
+		return response.ValidationError(c, err)
+	}
+	bom := c.QueryParam("bom") == "true"
+
+	// ヘッダーを送る前に失敗した場合は通常のエラーレスポンスを返す
+	var w itemRowWriter
+	start := func() error {
+		res := c.Response()
+		switch format {
+		case "ndjson":
+			res.Header().Set(echo.HeaderContentType, ndjsonContentType)
+			res.Header().Set(echo.HeaderContentDisposition, `attachment; filename="items.ndjson"`)
+			res.WriteHeader(http.StatusOK)
+			w = &ndjsonItemWriter{enc: json.NewEncoder(res)}
+			return nil
+		case "xlsx":
+			res.Header().Set(echo.HeaderContentType, xlsxContentType)
+			res.Header().Set(echo.HeaderContentDisposition, `attachment; filename="items.xlsx"`)
+			res.WriteHeader(http.StatusOK)
+			xw, err := newXLSXItemWriter(res)
+			if err != nil {
+				return err
+			}
+			w = xw
+			return nil
+		}
+		res.Header().Set(echo.HeaderContentType, "text/csv; charset=utf-8")
+		res.Header().Set(echo.HeaderContentDisposition, `attachment; filename="items.csv"`)
+		res.WriteHeader(http.StatusOK)
+		if bom {
+			res.Write([]byte(utf8BOM))
+		}
+		w = newCSVItemWriter(res)
+		return nil
+	}
+
+	err = h.itemUsecase.EachItem(c.Request().Context(), q, func(items []*entity.Item) error {
+		if w == nil {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		for _, item := range items {
+			if err := w.Write(item); err != nil {
+				return err
+			}
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		c.Response().Flush()
+		return nil
+	})
+	if err != nil {
+		if w == nil {
+			return response.RepositoryError(c, err, "failed to export items")
+		}
+		// ステータスは送信済みのため、途中で切れたことはログにだけ残す
+		reqctx.Logger(c.Request().Context()).Error("item export aborted", "error", err)
+		return nil
+	}
+
+	if w == nil {
+		if err := start(); err != nil {
+			return err
+		}
+	}
+	return w.Close()
+}
+
+const (
+	xlsxContentType   = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	ndjsonContentType = "application/x-ndjson"
+)
+
+// エクスポートの形式ごとの書き出し
+type itemRowWriter interface {
+	Write(item *entity.Item) error
+	Flush() error
+	Close() error
+}
+
+type csvItemWriter struct {
+	w *csv.Writer
+}
+
+func newCSVItemWriter(out io.Writer) *csvItemWriter {
+	w := csv.NewWriter(out)
+	w.Write(itemCSVHeader)
+	return &csvItemWriter{w: w}
+}
+
+func (w *csvItemWriter) Write(item *entity.Item) error {
+	return w.w.Write(itemCSVRow(item))
+}
+
+func (w *csvItemWriter) Flush() error {
+	w.w.Flush()
+	return w.w.Error()
+}
+
+func (w *csvItemWriter) Close() error {
+	return w.Flush()
+}
+
+// GET /items と同じ形の JSON を 1 行に 1 件ずつ書く
+type ndjsonItemWriter struct {
+	enc *json.Encoder
+}
+
+func (w *ndjsonItemWriter) Write(item *entity.Item) error {
+	return w.enc.Encode(item)
+}
+
+func (w *ndjsonItemWriter) Flush() error {
+	return nil
+}
+
+func (w *ndjsonItemWriter) Close() error {
+	return nil
+}
+
+// xlsx では ID・価格を数値のセルにする。文字列は数式にならないため ' を付けない
+type xlsxItemWriter struct {
+	*xlsx.Writer
+}
+
+func newXLSXItemWriter(out io.Writer) (*xlsxItemWriter, error) {
+	w, err := xlsx.NewWriter(out, "items")
+	if err != nil {
+		return nil, err
+	}
+	header := make([]any, len(itemCSVHeader))
+	for i, name := range itemCSVHeader {
+		header[i] = name
+	}
+	if err := w.Write(header...); err != nil {
+		return nil, err
+	}
+	return &xlsxItemWriter{Writer: w}, nil
+}
+
+func (w *xlsxItemWriter) Write(item *entity.Item) error {
+	var orgID any
+	if item.OrgID != nil {
+		orgID = *item.OrgID
+	}
+	return w.Writer.Write(
+		item.ID,
+		item.Name,
+		item.Category,
+		item.Brand,
+		item.PurchasePrice,
+		item.PurchaseDate,
+		orgID,
+		item.CreatedAt.UTC().Format(time.RFC3339),
+		item.UpdatedAt.UTC().Format(time.RFC3339),
+	)
+}
+
+func itemCSVRow(item *entity.Item) []string {
+	orgID := ""
+	if item.OrgID != nil {
+		orgID = strconv.FormatInt(*item.OrgID, 10)
+	}
+	return []string{
+		strconv.FormatInt(item.ID, 10),
+		csvText(item.Name),
+		csvText(item.Category),
+		csvText(item.Brand),
+		strconv.Itoa(item.PurchasePrice),
+		item.PurchaseDate,
+		orgID,
+		item.CreatedAt.UTC().Format(time.RFC3339),
+		item.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+// 表計算ソフトで数式として解釈されないよう、= + - @ で始まる値の先頭に ' を付ける
+func csvText(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
@@ -0,0 +1,410 @@
+package controller
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/interfaces/controller/response"
+	"Aicon-assignment/internal/pkg/xlsx"
+	"Aicon-assignment/internal/usecase"
+)
+
+// 取り込むファイルに必要な列。organization_id は任意
+var itemImportColumns = []string{"name", "category", "brand", "purchase_price", "purchase_date"}
+
+// multipart の file で送られた CSV・Excel（xlsx）・NDJSON からアイテムを登録する
+// CSV と xlsx は 1 行目がヘッダー（列の順序は問わない）、NDJSON は 1 行に POST /items と同じ形の JSON を 1 件
+// 形式は ?format=csv|xlsx|ndjson で指定し、省略した場合はファイル名の拡張子で判定する
+// ?dry_run=true の場合は検証結果だけを返し、何も登録しない
+// ?profile=<ID> の場合は取り込みのプロファイルで列の対応づけ・日付の書式・既定値を決める（CSV と xlsx のみ）
+func (h *ItemHandler) ImportItems(c echo.Context) error {
+	dryRun := false
+	if raw := c.QueryParam("dry_run"); raw != "" {
+		var err error
+		if dryRun, err = strconv.ParseBool(raw); err != nil {
+			return response.ValidationError(c, errors.New("dry_run must be true or false"))
+		}
+	}
+
+	file, err := h.readImportFile(c)
+	if err != nil {
+		return importFileErrorResponse(c, err)
+	}
+
+	result, err := h.itemUsecase.ImportItems(c.Request().Context(), file.rows, dryRun)
+	if err != nil {
+		if domainErrors.IsValidationError(err) {
+			return response.ValidationError(c, err)
+		}
+		return response.RepositoryError(c, err, "failed to import items")
+	}
+
+	switch {
+	case dryRun:
+		return c.JSON(http.StatusOK, result)
+	case result.Failed == 0:
+		return c.JSON(http.StatusCreated, result)
+	default:
+		return c.JSON(http.StatusMultiStatus, result)
+	}
+}
+
+// 読み込んだ取り込みのファイル
+type importFile struct {
+	name    string
+	profile *entity.ImportProfile // ?profile= を指定しなかった場合は nil
+	rows    []usecase.ImportRow
+}
+
+// ファイルや指定の問題（400 で返す）
+type importFileError struct {
+	err error
+}
+
+func (e *importFileError) Error() string {
+	return e.err.Error()
+}
+
+var errUnreadableImportFile = errors.New("failed to read file")
+
+// multipart の file を ?format= と ?profile= に従って読み込む（POST /items/import と POST /imports で共通）
+func (h *ItemHandler) readImportFile(c echo.Context) (*importFile, error) {
+	invalid := func(message string) error {
+		return &importFileError{err: errors.New(message)}
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		return nil, invalid("file is required")
+	}
+	format := c.QueryParam("format")
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(header.Filename)) {
+		case ".xlsx":
+			format = "xlsx"
+		case ".ndjson", ".jsonl":
+			format = "ndjson"
+		default:
+			format = "csv"
+		}
+	}
+	if format != "csv" && format != "xlsx" && format != "ndjson" {
+		return nil, invalid("format must be csv, xlsx or ndjson")
+	}
+
+	imported := &importFile{name: header.Filename}
+	if raw := c.QueryParam("profile"); raw != "" {
+		switch {
+		case h.importProfiles == nil:
+			return nil, invalid("import profiles are not available")
+		case format == "ndjson":
+			return nil, invalid("profile can only be used with csv or xlsx")
+		}
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || id <= 0 {
+			return nil, invalid("profile must be a positive integer")
+		}
+		if imported.profile, err = h.importProfiles.Get(c.Request().Context(), id); err != nil {
+			return nil, err
+		}
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return nil, errUnreadableImportFile
+	}
+	defer file.Close()
+
+	switch format {
+	case "ndjson":
+		imported.rows, err = readNDJSONRows(file)
+	case "xlsx":
+		var records []importRecord
+		if records, err = readXLSXRecords(file, header.Size); err == nil {
+			imported.rows, err = importRows(records, true, imported.profile)
+		}
+	default:
+		var records []importRecord
+		if records, err = readCSVRecords(file); err == nil {
+			imported.rows, err = importRows(records, false, imported.profile)
+		}
+	}
+	if err != nil {
+		return nil, &importFileError{err: err}
+	}
+	return imported, nil
+}
+
+// readImportFile のエラーのレスポンス。ファイルの問題でなければプロファイルを引けなかったもの
+func importFileErrorResponse(c echo.Context, err error) error {
+	var fileErr *importFileError
+	switch {
+	case errors.As(err, &fileErr):
+		return response.ValidationError(c, fileErr.err)
+	case errors.Is(err, errUnreadableImportFile):
+		return response.Error(c, http.StatusBadRequest, err.Error())
+	}
+	return importProfileError(c, err)
+}
+
+func importProfileError(c echo.Context, err error) error {
+	switch {
+	case domainErrors.IsUnauthenticatedError(err):
+		return response.Error(c, http.StatusUnauthorized, "authentication required")
+	case domainErrors.IsNotFoundError(err):
+		return response.Error(c, http.StatusNotFound, "import profile not found")
+	}
+	return response.RepositoryError(c, err, "failed to retrieve import profile")
+}
+
+// 取り込みの 1 行分の値と、ファイル上の行番号
+type importRecord struct {
+	line  int
+	cells []string
+}
+
+// CSV として読めない場合はエラーにする
+func readCSVRecords(r io.Reader) ([]importRecord, error) {
+	br := bufio.NewReader(r)
+	// Excel で保存した CSV の BOM を読み飛ばす
+	if bom, _ := br.Peek(len(utf8BOM)); string(bom) == utf8BOM {
+		br.Discard(len(utf8BOM))
+	}
+
+	reader := csv.NewReader(br)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var records []importRecord
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %w", err)
+		}
+		// ヘッダーの 1 行を除いて上限を数える
+		if len(records) > usecase.MaxImportRows {
+			return nil, fmt.Errorf("at most %d rows can be imported at once", usecase.MaxImportRows)
+		}
+		line, _ := reader.FieldPos(0)
+		records = append(records, importRecord{line: line, cells: record})
+	}
+}
+
+// 最初のシートを読み込む
+func readXLSXRecords(r io.ReaderAt, size int64) ([]importRecord, error) {
+	rows, err := xlsx.ReadRows(r, size)
+	if err != nil {
+		if errors.Is(err, xlsx.ErrInvalidFile) {
+			return nil, errors.New("the file is not a valid xlsx file")
+		}
+		return nil, err
+	}
+	records := make([]importRecord, len(rows))
+	for i, row := range rows {
+		records[i] = importRecord{line: row.Num, cells: row.Cells}
+	}
+	return records, nil
+}
+
+// NDJSON の 1 行の上限
+const MaxNDJSONLine = 1 << 20
+
+// 1 行ずつ読み込む。JSON として読めない行や型の合わない値は、その行の問題として返す
+// エクスポートした NDJSON の id や created_at などは読み飛ばし、新しいアイテムとして登録する
+func readNDJSONRows(r io.Reader) ([]usecase.ImportRow, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), MaxNDJSONLine)
+
+	var rows []usecase.ImportRow
+	for line := 1; scanner.Scan(); line++ {
+		text := bytes.TrimSpace(scanner.Bytes())
+		if line == 1 {
+			text = bytes.TrimPrefix(text, []byte(utf8BOM))
+		}
+		if len(text) == 0 {
+			continue
+		}
+		if len(rows) >= usecase.MaxImportRows {
+			return nil, fmt.Errorf("at most %d rows can be imported at once", usecase.MaxImportRows)
+		}
+
+		row := usecase.ImportRow{Row: line}
+		if err := json.Unmarshal(text, &row.Input); err != nil {
+			var typeErr *json.UnmarshalTypeError
+			if errors.As(err, &typeErr) && typeErr.Field != "" {
+				row.Problems = entity.ValidationErrors{{Field: typeErr.Field, Message: fmt.Sprintf("%s has an invalid type", typeErr.Field)}}
+			} else {
+				row.Problems = entity.ValidationErrors{{Message: "each line must be a JSON object"}}
+			}
+		}
+		rows = append(rows, row)
+	}
+	if err := scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return nil, fmt.Errorf("a line exceeds %d bytes", MaxNDJSONLine)
+		}
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("the file is empty")
+	}
+	return rows, nil
+}
+
+// 1 件目をヘッダーとして列を対応づけ、残りを取り込む行にする。空の行は飛ばす
+// Excel では日付のセルがシリアル値になるため、xlsx の場合は数値の purchase_date を日付に直す
+// profile を指定した場合は、その列の対応づけ・日付の書式・既定値を使う
+func importRows(records []importRecord, xlsxDates bool, profile *entity.ImportProfile) ([]usecase.ImportRow, error) {
+	if len(records) == 0 {
+		return nil, errors.New("the file is empty")
+	}
+	columns := make(map[string]int, len(records[0].cells))
+	for i, name := range records[0].cells {
+		column := strings.ToLower(strings.TrimSpace(name))
+		if profile != nil {
+			if field, ok := profile.Field(column); ok {
+				column = field
+			}
+		}
+		columns[column] = i
+	}
+	for _, name := range itemImportColumns {
+		if _, ok := columns[name]; ok {
+			continue
+		}
+		if profile == nil {
+			return nil, fmt.Errorf("column %s is required", name)
+		}
+		if profile.Default(name) == "" {
+			return nil, fmt.Errorf("column %s is required by profile %s", profile.Column(name), profile.Name)
+		}
+	}
+
+	var rows []usecase.ImportRow
+	for _, record := range records[1:] {
+		if blankRecord(record.cells) {
+			continue
+		}
+		if len(rows) >= usecase.MaxImportRows {
+			return nil, fmt.Errorf("at most %d rows can be imported at once", usecase.MaxImportRows)
+		}
+		row := importRow(record.line, columns, record.cells, profile)
+		if row.Input.PurchaseDate != "" {
+			date, err := importDate(row.Input.PurchaseDate, xlsxDates, profile)
+			if err != nil {
+				row.Problems = append(row.Problems, entity.FieldError{Field: "purchase_date", Message: err.Error()})
+			}
+			row.Input.PurchaseDate = date
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// 日付を YYYY-MM-DD に直す。プロファイルの書式を優先し、xlsx の場合はシリアル値も受け付ける
+// 直せない場合は元の値を返す。プロファイルの書式に合わない場合はエラーも返す
+func importDate(value string, xlsxDates bool, profile *entity.ImportProfile) (string, error) {
+	var err error
+	if profile != nil {
+		var date string
+		if date, err = profile.ParseDate(value); err == nil {
+			return date, nil
+		}
+	}
+	if serial, parseErr := strconv.ParseFloat(value, 64); xlsxDates && parseErr == nil {
+		return xlsx.DateFromSerial(serial).Format("2006-01-02"), nil
+	}
+	return value, err
+}
+
+func blankRecord(cells []string) bool {
+	for _, cell := range cells {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// profile を指定した場合は、列がない・空のフィールドにその既定値を使う
+func importRow(line int, columns map[string]int, record []string, profile *entity.ImportProfile) usecase.ImportRow {
+	value := func(name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			if v := strings.TrimSpace(record[i]); v != "" {
+				return v
+			}
+		}
+		if profile != nil {
+			return profile.Default(name)
+		}
+		return ""
+	}
+
+	row := usecase.ImportRow{
+		Row: line,
+		Input: usecase.CreateItemInput{
+			Name:         value("name"),
+			Category:     value("category"),
+			Brand:        value("brand"),
+			PurchaseDate: value("purchase_date"),
+		},
+	}
+	if raw := value("purchase_price"); raw != "" {
+		price, err := strconv.Atoi(raw)
+		if err != nil {
+			row.Problems = append(row.Problems, entity.FieldError{Field: "purchase_price", Message: "purchase_price must be an integer"})
+		}
+		row.Input.PurchasePrice = price
+	}
+	if raw := value("organization_id"); raw != "" {
+		orgID, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || orgID <= 0 {
+			row.Problems = append(row.Problems, entity.FieldError{Field: "organization_id", Message: "organization_id must be a positive integer"})
+		} else {
+			row.Input.OrgID = &orgID
+		}
+	}
+	return row
+}
+
+// 取り込み用の Excel のひな形。category の列は有効なカテゴリーから選べるようにする
+func (h *ItemHandler) ImportTemplate(c echo.Context) error {
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, xlsxContentType)
+	res.Header().Set(echo.HeaderContentDisposition, `attachment; filename="items-import-template.xlsx"`)
+	res.WriteHeader(http.StatusOK)
+
+	w, err := xlsx.NewWriter(res, "items")
+	if err != nil {
+		return err
+	}
+	header := make([]any, 0, len(itemImportColumns)+1)
+	for _, name := range itemImportColumns {
+		header = append(header, name)
+	}
+	if err := w.Write(append(header, "organization_id")...); err != nil {
+		return err
+	}
+	categoryColumn := xlsx.ColumnName(1) // itemImportColumns の category
+	ref := fmt.Sprintf("%s2:%s%d", categoryColumn, categoryColumn, usecase.MaxImportRows+1)
+	if err := w.AddListValidation(ref, entity.GetValidCategories()); err != nil {
+		return err
+	}
+	return w.Close()
+}
@@ -0,0 +1,161 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/interfaces/controller/response"
+	"Aicon-assignment/internal/usecase"
+)
+
+// POST /items/import と同じファイル（?format=・?profile= も同じ）を置き場に読み込む
+// 行はまだアイテムとして登録せず、問題のある行も含めて置き場に残す
+func (h *ItemHandler) StageImport(c echo.Context) error {
+	file, err := h.readImportFile(c)
+	if err != nil {
+		return importFileErrorResponse(c, err)
+	}
+
+	input := usecase.StageImportInput{Filename: file.name, Rows: file.rows}
+	if file.profile != nil {
+		input.ProfileID = &file.profile.ID
+	}
+	batch, err := h.importStaging.Stage(c.Request().Context(), input)
+	if err != nil {
+		return importStagingError(c, err, "failed to stage import")
+	}
+
+	return c.JSON(http.StatusCreated, batch)
+}
+
+func (h *ItemHandler) GetImport(c echo.Context) error {
+	id, ok := response.ParseID(c, "id")
+	if !ok {
+		return response.Error(c, http.StatusBadRequest, "invalid import ID")
+	}
+
+	batch, err := h.importStaging.Get(c.Request().Context(), id)
+	if err != nil {
+		return importStagingError(c, err, "failed to retrieve import")
+	}
+
+	return c.JSON(http.StatusOK, batch)
+}
+
+// ?invalid=true の場合は問題のある行だけを返す
+func (h *ItemHandler) GetImportRows(c echo.Context) error {
+	id, ok := response.ParseID(c, "id")
+	if !ok {
+		return response.Error(c, http.StatusBadRequest, "invalid import ID")
+	}
+	invalidOnly := false
+	if raw := c.QueryParam("invalid"); raw != "" {
+		var err error
+		if invalidOnly, err = strconv.ParseBool(raw); err != nil {
+			return response.Error(c, http.StatusBadRequest, "invalid must be true or false")
+		}
+	}
+
+	rows, err := h.importStaging.ListRows(c.Request().Context(), id, invalidOnly)
+	if err != nil {
+		return importStagingError(c, err, "failed to retrieve import rows")
+	}
+
+	return c.JSON(http.StatusOK, rows)
+}
+
+// 行の値を直して検証し直す。:row はファイル上の行番号
+func (h *ItemHandler) UpdateImportRow(c echo.Context) error {
+	id, row, ok := parseImportRow(c)
+	if !ok {
+		return response.Error(c, http.StatusBadRequest, "invalid import ID or row")
+	}
+
+	var input usecase.UpdateStagedRowInput
+	if err := c.Bind(&input); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	staged, err := h.importStaging.UpdateRow(c.Request().Context(), id, row, input)
+	if err != nil {
+		return importStagingError(c, err, "failed to update import row")
+	}
+
+	return c.JSON(http.StatusOK, staged)
+}
+
+// 行を取り込みから外す
+func (h *ItemHandler) DeleteImportRow(c echo.Context) error {
+	id, row, ok := parseImportRow(c)
+	if !ok {
+		return response.Error(c, http.StatusBadRequest, "invalid import ID or row")
+	}
+
+	if err := h.importStaging.DeleteRow(c.Request().Context(), id, row); err != nil {
+		return importStagingError(c, err, "failed to delete import row")
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// 置き場のすべての行をまとめて登録する。問題のある行が残っている場合は何も登録せず 422 で結果を返す
+func (h *ItemHandler) CommitImport(c echo.Context) error {
+	id, ok := response.ParseID(c, "id")
+	if !ok {
+		return response.Error(c, http.StatusBadRequest, "invalid import ID")
+	}
+
+	result, err := h.importStaging.Commit(c.Request().Context(), id)
+	if err != nil {
+		return importStagingError(c, err, "failed to commit import")
+	}
+	if result.Failed > 0 {
+		return c.JSON(http.StatusUnprocessableEntity, result)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// 置き場を行ごと破棄する
+func (h *ItemHandler) DiscardImport(c echo.Context) error {
+	id, ok := response.ParseID(c, "id")
+	if !ok {
+		return response.Error(c, http.StatusBadRequest, "invalid import ID")
+	}
+
+	if err := h.importStaging.Discard(c.Request().Context(), id); err != nil {
+		return importStagingError(c, err, "failed to discard import")
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func parseImportRow(c echo.Context) (int64, int, bool) {
+	id, ok := response.ParseID(c, "id")
+	if !ok {
+		return 0, 0, false
+	}
+	row, err := strconv.Atoi(c.Param("row"))
+	if err != nil || row <= 0 {
+		return 0, 0, false
+	}
+	return id, row, true
+}
+
+func importStagingError(c echo.Context, err error, fallback string) error {
+	switch {
+	case domainErrors.IsUnauthenticatedError(err):
+		return response.Error(c, http.StatusUnauthorized, "authentication required")
+	case domainErrors.IsNotFoundError(err):
+		return response.Error(c, http.StatusNotFound, err.Error())
+	case domainErrors.IsValidationError(err):
+		return response.ValidationError(c, err)
+	case errors.Is(err, domainErrors.ErrImportCommitted):
+		return response.Error(c, http.StatusConflict, err.Error())
+	}
+	return response.RepositoryError(c, err, fallback)
+}
@@ -1,37 +1,95 @@
 package controller
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 
+	"Aicon-assignment/internal/domain/entity"
 	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/interfaces/controller/listing"
+	"Aicon-assignment/internal/interfaces/controller/response"
+	"Aicon-assignment/internal/pkg/listquery"
+	"Aicon-assignment/internal/pkg/reqctx"
 	"Aicon-assignment/internal/usecase"
 
 	"github.com/labstack/echo/v4"
 )
 
 type ItemHandler struct {
-	itemUsecase usecase.ItemUsecase
+	itemUsecase    usecase.ItemUsecase
+	importProfiles usecase.ImportProfileUsecase // nil の場合は取り込みでプロファイルを指定できない
+	importStaging  usecase.ImportStagingUsecase // /imports の置き場
 }
 
-func NewItemHandler(itemUsecase usecase.ItemUsecase) *ItemHandler {
-	return &ItemHandler{
+type HandlerOption func(*ItemHandler)
+
+// 取り込みで ?profile= に指定したプロファイルを引く
+func WithImportProfiles(importProfiles usecase.ImportProfileUsecase) HandlerOption {
+	return func(h *ItemHandler) {
+		h.importProfiles = importProfiles
+	}
+}
+
+// 取り込みを置き場に読み込み、確認・編集してから確定する（/imports）
+func WithImportStaging(importStaging usecase.ImportStagingUsecase) HandlerOption {
+	return func(h *ItemHandler) {
+		h.importStaging = importStaging
+	}
+}
+
+func NewItemHandler(itemUsecase usecase.ItemUsecase, opts ...HandlerOption) *ItemHandler {
+	h := &ItemHandler{
 		itemUsecase: itemUsecase,
 	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
 }
 
 // エラーレスポンスの形式
-type ErrorResponse struct {
-	Error   string   `json:"error"`
-	Details []string `json:"details,omitempty"`
-}
+type ErrorResponse = response.ErrorResponse
 
+// filter / sort / page のクエリパラメータで絞り込み・並び替え・ページングができる
+// cursor を指定した場合は作成日時の降順で、next_cursor を含むオブジェクトを返す
 func (h *ItemHandler) GetItems(c echo.Context) error {
-	items, err := h.itemUsecase.GetAllItems(c.Request().Context())
+	q, err := listquery.Parse(c.QueryParams(), entity.ItemListSpec)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: "failed to retrieve items",
-		})
+		return response.ValidationError(c, err)
+	}
+
+	result, err := h.itemUsecase.ListItems(c.Request().Context(), q)
+	if err != nil {
+		if domainErrors.IsValidationError(err) {
+			return response.ValidationError(c, err)
+		}
+		return response.RepositoryError(c, err, "failed to retrieve items")
+	}
+
+	return response.List(c, result, q)
+}
+
+// 名前・ブランドのキーワード検索。?q= は必須、?limit= で件数を指定できる
+func (h *ItemHandler) SearchItems(c echo.Context) error {
+	limit := 0
+	if raw := c.QueryParam("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return response.Error(c, http.StatusBadRequest, "limit must be an integer")
+		}
+		limit = n
+	}
+
+	items, err := h.itemUsecase.SearchItems(c.Request().Context(), c.QueryParam("q"), limit)
+	if err != nil {
+		if domainErrors.IsValidationError(err) {
+			return response.ValidationError(c, err)
+		}
+		return response.RepositoryError(c, err, "failed to search items")
 	}
 
 	return c.JSON(http.StatusOK, items)
@@ -53,9 +111,7 @@ func (h *ItemHandler) GetItem(c echo.Context) error {
 				Error: "item not found",
 			})
 		}
-		return c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: "failed to retrieve item",
-		})
+		return response.RepositoryError(c, err, "failed to retrieve item")
 	}
 
 	return c.JSON(http.StatusOK, item)
@@ -85,14 +141,49 @@ func (h *ItemHandler) CreateItem(c echo.Context) error {
 				Details: []string{err.Error()},
 			})
 		}
-		return c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: "failed to create item",
-		})
+		return response.RepositoryError(c, err, "failed to create item")
 	}
 
+	h.warnPurchasePrice(c, item, input.ConfirmPrice)
+
 	return c.JSON(http.StatusCreated, item)
 }
 
+// アイテムを一括登録する。?atomic=true の場合は1行でも問題があればどの行も登録しない
+// すべて登録できた場合は 201、問題のある行があった場合は 207（atomic の場合は 400）で行ごとの結果を返す
+// 一括登録では価格の警告は出さない
+func (h *ItemHandler) CreateItems(c echo.Context) error {
+	var inputs []usecase.CreateItemInput
+	if err := c.Bind(&inputs); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	atomic := false
+	if raw := c.QueryParam("atomic"); raw != "" {
+		var err error
+		if atomic, err = strconv.ParseBool(raw); err != nil {
+			return response.ValidationError(c, errors.New("atomic must be true or false"))
+		}
+	}
+
+	result, err := h.itemUsecase.CreateItems(c.Request().Context(), inputs, atomic)
+	if err != nil {
+		if domainErrors.IsValidationError(err) {
+			return response.ValidationError(c, err)
+		}
+		return response.RepositoryError(c, err, "failed to create items")
+	}
+
+	switch {
+	case len(result.Errors) == 0:
+		return c.JSON(http.StatusCreated, result)
+	case atomic:
+		return c.JSON(http.StatusBadRequest, result)
+	default:
+		return c.JSON(http.StatusMultiStatus, result)
+	}
+}
+
 func (h *ItemHandler) UpdateItem(c echo.Context) error {
 	idStr := c.Param("id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
@@ -129,14 +220,39 @@ func (h *ItemHandler) UpdateItem(c echo.Context) error {
 				Details: []string{err.Error()},
 			})
 		}
-		return c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: "failed to update item",
-		})
+		if domainErrors.IsReasonRequiredError(err) {
+			return c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+				Error: "reason is required for this operation",
+			})
+		}
+		if domainErrors.IsForbiddenError(err) {
+			return response.Error(c, http.StatusForbidden, err.Error())
+		}
+		if domainErrors.IsLockedError(err) {
+			return response.Error(c, http.StatusConflict, err.Error())
+		}
+		return response.RepositoryError(c, err, "failed to update item")
+	}
+
+	if input.PurchasePrice != nil {
+		h.warnPurchasePrice(c, item, input.ConfirmPrice)
 	}
 
 	return c.JSON(http.StatusOK, item)
 }
 
+// 購入価格が相場から大きく外れている場合は Warning ヘッダーで知らせる
+// 作成・更新自体は完了しているため、レスポンスのステータスや本文は変えない
+// 確認済み（confirm_price）の場合と、保存前に判定済みの strict モードの場合は判定しない
+func (h *ItemHandler) warnPurchasePrice(c echo.Context, item *entity.Item, confirmed bool) {
+	if confirmed || reqctx.Strict(c.Request().Context()) {
+		return
+	}
+	if anomaly := h.itemUsecase.CheckPurchasePrice(c.Request().Context(), item); anomaly != nil {
+		c.Response().Header().Add("Warning", fmt.Sprintf("299 - %q", anomaly.Message()))
+	}
+}
+
 func (h *ItemHandler) DeleteItem(c echo.Context) error {
 	idStr := c.Param("id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
@@ -146,32 +262,327 @@ func (h *ItemHandler) DeleteItem(c echo.Context) error {
 		})
 	}
 
-	err = h.itemUsecase.DeleteItem(c.Request().Context(), id)
+	// 削除理由はクエリパラメータで受け取る
+	err = h.itemUsecase.DeleteItem(c.Request().Context(), id, c.QueryParam("reason"))
 	if err != nil {
 		if domainErrors.IsNotFoundError(err) {
 			return c.JSON(http.StatusNotFound, ErrorResponse{
 				Error: "item not found",
 			})
 		}
-		return c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: "failed to delete item",
-		})
+		if domainErrors.IsReasonRequiredError(err) {
+			return c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+				Error: "reason is required for this operation",
+			})
+		}
+		return response.RepositoryError(c, err, "failed to delete item")
 	}
 
 	return c.NoContent(http.StatusNoContent)
 }
 
+// ?ids=1,2,3 のアイテムをまとめて削除する。存在しない ID は not_found で返す
+func (h *ItemHandler) DeleteItems(c echo.Context) error {
+	var ids []int64
+	for _, raw := range strings.Split(c.QueryParam("ids"), ",") {
+		if raw = strings.TrimSpace(raw); raw == "" {
+			continue
+		}
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return response.ValidationError(c, fmt.Errorf("invalid id: %s", raw))
+		}
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		return response.ValidationError(c, errors.New("ids is required"))
+	}
+
+	result, err := h.itemUsecase.DeleteItems(c.Request().Context(), ids, c.QueryParam("reason"))
+	if err != nil {
+		return h.bulkErrorResponse(c, err, "failed to delete items")
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// ids のアイテムすべてに同じ部分更新を適用する。存在しない ID は not_found で返す
+// 一括更新では価格の警告は出さない
+func (h *ItemHandler) UpdateItems(c echo.Context) error {
+	var input usecase.BulkUpdateItemsInput
+	if err := c.Bind(&input); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	result, err := h.itemUsecase.UpdateItems(c.Request().Context(), input)
+	if err != nil {
+		return h.bulkErrorResponse(c, err, "failed to update items")
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+func (h *ItemHandler) bulkErrorResponse(c echo.Context, err error, message string) error {
+	if domainErrors.IsValidationError(err) {
+		return response.ValidationError(c, err)
+	}
+	if domainErrors.IsReasonRequiredError(err) {
+		return response.Error(c, http.StatusUnprocessableEntity, "reason is required for this operation")
+	}
+	if domainErrors.IsForbiddenError(err) {
+		return response.Error(c, http.StatusForbidden, err.Error())
+	}
+	if domainErrors.IsLockedError(err) {
+		return response.Error(c, http.StatusConflict, err.Error())
+	}
+	return response.RepositoryError(c, err, message)
+}
+
+// アイテムを完全に削除する。統合・分割で論理削除したアイテムも対象にできる
+func (h *ItemHandler) PurgeItem(c echo.Context) error {
+	id, ok := response.ParseID(c, "id")
+	if !ok {
+		return response.Error(c, http.StatusBadRequest, "invalid item ID")
+	}
+
+	// 削除理由はクエリパラメータで受け取る
+	err := h.itemUsecase.PurgeItem(c.Request().Context(), id, c.QueryParam("reason"))
+	if err != nil {
+		if domainErrors.IsNotFoundError(err) {
+			return response.Error(c, http.StatusNotFound, "item not found")
+		}
+		if domainErrors.IsReasonRequiredError(err) {
+			return response.Error(c, http.StatusUnprocessableEntity, "reason is required for this operation")
+		}
+		if domainErrors.IsForbiddenError(err) {
+			return response.Error(c, http.StatusForbidden, err.Error())
+		}
+		return response.RepositoryError(c, err, "failed to purge item")
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// 重複したアイテムを統合する。:id が残るアイテムで、source_id のアイテムは削除される
+func (h *ItemHandler) MergeItem(c echo.Context) error {
+	id, ok := response.ParseID(c, "id")
+	if !ok {
+		return response.Error(c, http.StatusBadRequest, "invalid item ID")
+	}
+
+	var input usecase.MergeItemsInput
+	if err := c.Bind(&input); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid request format")
+	}
+	if input.SourceID == 0 {
+		return response.ValidationError(c, errors.New("source_id is required"))
+	}
+
+	item, err := h.itemUsecase.MergeItems(c.Request().Context(), id, input)
+	if err != nil {
+		if domainErrors.IsNotFoundError(err) {
+			return response.Error(c, http.StatusNotFound, "item not found")
+		}
+		if domainErrors.IsValidationError(err) {
+			return response.ValidationError(c, err)
+		}
+		if domainErrors.IsReasonRequiredError(err) {
+			return response.Error(c, http.StatusUnprocessableEntity, "reason is required for this operation")
+		}
+		if domainErrors.IsLockedError(err) {
+			return response.Error(c, http.StatusConflict, err.Error())
+		}
+		return response.RepositoryError(c, err, "failed to merge items")
+	}
+
+	return c.JSON(http.StatusOK, item)
+}
+
+// 1つとして登録されたアイテムを複数に分割する。作成したアイテムを返し、:id のアイテムは削除される
+func (h *ItemHandler) SplitItem(c echo.Context) error {
+	id, ok := response.ParseID(c, "id")
+	if !ok {
+		return response.Error(c, http.StatusBadRequest, "invalid item ID")
+	}
+
+	var input usecase.SplitItemInput
+	if err := c.Bind(&input); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	items, err := h.itemUsecase.SplitItem(c.Request().Context(), id, input)
+	if err != nil {
+		if domainErrors.IsNotFoundError(err) {
+			return response.Error(c, http.StatusNotFound, "item not found")
+		}
+		if domainErrors.IsValidationError(err) {
+			return response.ValidationError(c, err)
+		}
+		if domainErrors.IsReasonRequiredError(err) {
+			return response.Error(c, http.StatusUnprocessableEntity, "reason is required for this operation")
+		}
+		return response.RepositoryError(c, err, "failed to split item")
+	}
+
+	return c.JSON(http.StatusCreated, items)
+}
+
+// カテゴリー :key のアイテムを別のカテゴリーに付け替える。dry_run の場合は対象と入力チェックの結果だけを返す
+func (h *ItemHandler) ReassignCategory(c echo.Context) error {
+	from, err := url.PathUnescape(c.Param("key"))
+	if err != nil || from == "" {
+		return response.Error(c, http.StatusBadRequest, "invalid category")
+	}
+
+	var input usecase.ReassignCategoryInput
+	if err := c.Bind(&input); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	result, err := h.itemUsecase.ReassignCategory(c.Request().Context(), from, input)
+	if err != nil {
+		switch {
+		case domainErrors.IsValidationError(err):
+			return response.ValidationError(c, err)
+		case domainErrors.IsForbiddenError(err):
+			return response.Error(c, http.StatusForbidden, err.Error())
+		case domainErrors.IsNotFoundError(err):
+			return response.Error(c, http.StatusNotFound, err.Error())
+		case domainErrors.IsStalePreviewError(err), domainErrors.IsInUseError(err), domainErrors.IsLockedError(err):
+			return response.Error(c, http.StatusConflict, err.Error())
+		}
+		return response.RepositoryError(c, err, "failed to reassign category")
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// アイテムの持ち主を付け替える（管理者向け）
+func (h *ItemHandler) AssignOwner(c echo.Context) error {
+	id, ok := response.ParseID(c, "id")
+	if !ok {
+		return response.Error(c, http.StatusBadRequest, "invalid item ID")
+	}
+
+	var input usecase.AssignOwnerInput
+	if err := c.Bind(&input); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	item, err := h.itemUsecase.AssignOwner(c.Request().Context(), id, input)
+	if err != nil {
+		switch {
+		case domainErrors.IsValidationError(err):
+			return response.ValidationError(c, err)
+		case domainErrors.IsForbiddenError(err):
+			return response.Error(c, http.StatusForbidden, err.Error())
+		case domainErrors.IsNotFoundError(err):
+			return response.Error(c, http.StatusNotFound, "item not found")
+		}
+		return response.RepositoryError(c, err, "failed to assign owner")
+	}
+
+	return c.JSON(http.StatusOK, item)
+}
+
+func (h *ItemHandler) GetPriceHistory(c echo.Context) error {
+	return listing.SubResource[*entity.PriceChange]{
+		Parent: "item",
+		Spec:   entity.PriceChangeListSpec,
+		Value:  (*entity.PriceChange).FilterValue,
+		Fetch: func(c echo.Context, id int64) ([]*entity.PriceChange, error) {
+			return h.itemUsecase.GetPriceHistory(c.Request().Context(), id)
+		},
+		Error: itemListError("failed to retrieve price history"),
+	}.Handle(c)
+}
+
+func (h *ItemHandler) GetAuditLog(c echo.Context) error {
+	return listing.SubResource[*entity.AuditEntry]{
+		Parent: "item",
+		Spec:   entity.AuditEntryListSpec,
+		Value:  (*entity.AuditEntry).FilterValue,
+		Fetch: func(c echo.Context, id int64) ([]*entity.AuditEntry, error) {
+			return h.itemUsecase.GetAuditLog(c.Request().Context(), id)
+		},
+		Error: itemListError("failed to retrieve audit log"),
+	}.Handle(c)
+}
+
+// アイテム配下の一覧で共通のエラーレスポンス
+func itemListError(fallback string) func(c echo.Context, err error) error {
+	return func(c echo.Context, err error) error {
+		if domainErrors.IsNotFoundError(err) {
+			return response.Error(c, http.StatusNotFound, "item not found")
+		}
+		if domainErrors.IsValidationError(err) {
+			return response.Error(c, http.StatusBadRequest, "invalid item ID")
+		}
+		return response.RepositoryError(c, err, fallback)
+	}
+}
+
+// group_by を指定しない場合は、指定できるようになる前と同じ形（カテゴリー別の categories）で返す
+type categorySummaryResponse struct {
+	Categories map[string]int `json:"categories"`
+	Total      int            `json:"total"`
+}
+
+// group_by を指定した場合は group_by・groups・total を返す
 func (h *ItemHandler) GetSummary(c echo.Context) error {
-	summary, err := h.itemUsecase.GetCategorySummary(c.Request().Context())
+	groupBy := c.QueryParam("group_by")
+	summary, err := h.itemUsecase.GetSummary(c.Request().Context(), groupBy)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: "failed to retrieve summary",
-		})
+		if domainErrors.IsValidationError(err) {
+			return c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "validation failed",
+				Details: []string{err.Error()},
+			})
+		}
+		return response.RepositoryError(c, err, "failed to retrieve summary")
 	}
 
+	if groupBy == "" {
+		return c.JSON(http.StatusOK, categorySummaryResponse{Categories: summary.Groups, Total: summary.Total})
+	}
 	return c.JSON(http.StatusOK, summary)
 }
 
+// 組織ごとのアイテム数と購入価格の合計。組織のID順に page[number]/page[size] または limit/offset でページングする
+// ページを指定しない場合も先頭の1ページだけを返す
+func (h *ItemHandler) GetOrganizationSummary(c echo.Context) error {
+	q, err := listquery.Parse(c.QueryParams(), entity.OrganizationSummaryListSpec)
+	if err != nil {
+		return response.ValidationError(c, err)
+	}
+	if q.Page.Size == 0 {
+		q.Page = listquery.Page{Number: 1, Size: listquery.DefaultPageSize}
+	}
+
+	result, err := h.itemUsecase.SummarizeByOrganization(c.Request().Context(), q)
+	if err != nil {
+		if domainErrors.IsValidationError(err) {
+			return response.ValidationError(c, err)
+		}
+		return response.RepositoryError(c, err, "failed to retrieve organization summary")
+	}
+
+	return response.List(c, result, q)
+}
+
+// カテゴリー内の外れ値レポート。?category= で対象を絞り込める
+func (h *ItemHandler) GetOutlierReport(c echo.Context) error {
+	report, err := h.itemUsecase.GetOutlierReport(c.Request().Context(), c.QueryParam("category"))
+	if err != nil {
+		if domainErrors.IsValidationError(err) {
+			return response.ValidationError(c, err)
+		}
+		return response.RepositoryError(c, err, "failed to retrieve outlier report")
+	}
+
+	return c.JSON(http.StatusOK, report)
+}
+
 func validateCreateItemInput(input usecase.CreateItemInput) []string {
 	var errs []string
 
@@ -191,6 +602,9 @@ func validateCreateItemInput(input usecase.CreateItemInput) []string {
 	if input.PurchasePrice < 0 {
 		errs = append(errs, "purchase_price must be 0 or greater")
 	}
+	if input.OrgID != nil && *input.OrgID <= 0 {
+		errs = append(errs, "organization_id must be a positive integer")
+	}
 
 	return errs
 }
@@ -1,19 +1,31 @@
 package controller
 
 import (
+	"archive/zip"
+	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
 	"Aicon-assignment/internal/domain/entity"
 	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/interfaces/database"
+	"Aicon-assignment/internal/pkg/clock"
+	"Aicon-assignment/internal/pkg/idgen"
+	"Aicon-assignment/internal/pkg/listquery"
+	"Aicon-assignment/internal/pkg/xlsx"
 	"Aicon-assignment/internal/usecase"
 )
 
@@ -27,6 +39,30 @@ func (m *MockItemUsecase) GetAllItems(ctx context.Context) ([]*entity.Item, erro
 	return args.Get(0).([]*entity.Item), args.Error(1)
 }
 
+func (m *MockItemUsecase) ListItems(ctx context.Context, query listquery.Query) (*listquery.Result[*entity.Item], error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*listquery.Result[*entity.Item]), args.Error(1)
+}
+
+func (m *MockItemUsecase) SearchItems(ctx context.Context, keyword string, limit int) ([]*entity.Item, error) {
+	args := m.Called(ctx, keyword, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.Item), args.Error(1)
+}
+
+func (m *MockItemUsecase) SummarizeByOrganization(ctx context.Context, query listquery.Query) (*listquery.Result[*entity.OrganizationItemSummary], error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*listquery.Result[*entity.OrganizationItemSummary]), args.Error(1)
+}
+
 func (m *MockItemUsecase) GetItemByID(ctx context.Context, id int64) (*entity.Item, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
@@ -51,17 +87,149 @@ func (m *MockItemUsecase) UpdateItem(ctx context.Context, id int64, input usecas
 	return args.Get(0).(*entity.Item), args.Error(1)
 }
 
-func (m *MockItemUsecase) DeleteItem(ctx context.Context, id int64) error {
-	args := m.Called(ctx, id)
+func (m *MockItemUsecase) DeleteItem(ctx context.Context, id int64, reason string) error {
+	args := m.Called(ctx, id, reason)
 	return args.Error(0)
 }
 
-func (m *MockItemUsecase) GetCategorySummary(ctx context.Context) (*usecase.CategorySummary, error) {
-	args := m.Called(ctx)
+func (m *MockItemUsecase) CreateItems(ctx context.Context, inputs []usecase.CreateItemInput, atomic bool) (*usecase.BulkCreateResult, error) {
+	args := m.Called(ctx, inputs, atomic)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.BulkCreateResult), args.Error(1)
+}
+
+func (m *MockItemUsecase) EachItem(ctx context.Context, query listquery.Query, fn func(items []*entity.Item) error) error {
+	args := m.Called(ctx, query, fn)
+	if batches, ok := args.Get(0).([][]*entity.Item); ok {
+		for _, items := range batches {
+			if err := fn(items); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *MockItemUsecase) ImportItems(ctx context.Context, rows []usecase.ImportRow, dryRun bool) (*usecase.ImportResult, error) {
+	args := m.Called(ctx, rows, dryRun)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.ImportResult), args.Error(1)
+}
+
+func (m *MockItemUsecase) CommitImport(ctx context.Context, rows []usecase.ImportRow, then func(ctx context.Context, created []*entity.Item) error) (*usecase.ImportResult, error) {
+	args := m.Called(ctx, rows)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	result := args.Get(0).(*usecase.ImportResult)
+	if result.Failed == 0 && then != nil {
+		if err := then(ctx, nil); err != nil {
+			return nil, err
+		}
+	}
+	return result, args.Error(1)
+}
+
+func (m *MockItemUsecase) UpdateItems(ctx context.Context, input usecase.BulkUpdateItemsInput) (*usecase.BulkUpdateResult, error) {
+	args := m.Called(ctx, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.BulkUpdateResult), args.Error(1)
+}
+
+func (m *MockItemUsecase) DeleteItems(ctx context.Context, ids []int64, reason string) (*usecase.BulkDeleteResult, error) {
+	args := m.Called(ctx, ids, reason)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.BulkDeleteResult), args.Error(1)
+}
+
+func (m *MockItemUsecase) PurgeItem(ctx context.Context, id int64, reason string) error {
+	args := m.Called(ctx, id, reason)
+	return args.Error(0)
+}
+
+func (m *MockItemUsecase) PurgeExpired(ctx context.Context, cutoff time.Time) (int64, error) {
+	args := m.Called(ctx, cutoff)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockItemUsecase) GetPriceHistory(ctx context.Context, id int64) ([]*entity.PriceChange, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.PriceChange), args.Error(1)
+}
+
+func (m *MockItemUsecase) GetAuditLog(ctx context.Context, id int64) ([]*entity.AuditEntry, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.AuditEntry), args.Error(1)
+}
+
+func (m *MockItemUsecase) GetSummary(ctx context.Context, groupBy string) (*usecase.Summary, error) {
+	args := m.Called(ctx, groupBy)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.Summary), args.Error(1)
+}
+
+func (m *MockItemUsecase) CheckPurchasePrice(ctx context.Context, item *entity.Item) *entity.PriceAnomaly {
+	args := m.Called(ctx, item)
+	if args.Get(0) == nil {
+		return nil
+	}
+	return args.Get(0).(*entity.PriceAnomaly)
+}
+
+func (m *MockItemUsecase) GetOutlierReport(ctx context.Context, category string) (*usecase.OutlierReport, error) {
+	args := m.Called(ctx, category)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.OutlierReport), args.Error(1)
+}
+
+func (m *MockItemUsecase) MergeItems(ctx context.Context, targetID int64, input usecase.MergeItemsInput) (*entity.Item, error) {
+	args := m.Called(ctx, targetID, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Item), args.Error(1)
+}
+
+func (m *MockItemUsecase) SplitItem(ctx context.Context, id int64, input usecase.SplitItemInput) ([]*entity.Item, error) {
+	args := m.Called(ctx, id, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.Item), args.Error(1)
+}
+
+func (m *MockItemUsecase) AssignOwner(ctx context.Context, id int64, input usecase.AssignOwnerInput) (*entity.Item, error) {
+	args := m.Called(ctx, id, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Item), args.Error(1)
+}
+
+func (m *MockItemUsecase) ReassignCategory(ctx context.Context, from string, input usecase.ReassignCategoryInput) (*usecase.ReassignCategoryResult, error) {
+	args := m.Called(ctx, from, input)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(*usecase.CategorySummary), args.Error(1)
+	return args.Get(0).(*usecase.ReassignCategoryResult), args.Error(1)
 }
 
 func TestItemHandler_UpdateItem(t *testing.T) {
@@ -86,6 +254,7 @@ func TestItemHandler_UpdateItem(t *testing.T) {
 					PurchasePrice: intPtr(1500000),
 				}
 				mockUsecase.On("UpdateItem", mock.Anything, int64(1), input).Return(updatedItem, nil)
+				mockUsecase.On("CheckPurchasePrice", mock.Anything, updatedItem).Return(nil)
 			},
 			expectedStatus: http.StatusOK,
 			checkResponse: func(t *testing.T, body string) {
@@ -181,6 +350,42 @@ func TestItemHandler_UpdateItem(t *testing.T) {
 				assert.Equal(t, "failed to update item", errResp.Error)
 			},
 		},
+		{
+			name:        "異常系: 理由が必要 (422)",
+			itemID:      "1",
+			requestBody: `{"purchase_price": 3000000}`,
+			setupMock: func(mockUsecase *MockItemUsecase) {
+				input := usecase.UpdateItemInput{
+					PurchasePrice: intPtr(3000000),
+				}
+				mockUsecase.On("UpdateItem", mock.Anything, int64(1), input).Return((*entity.Item)(nil), domainErrors.ErrReasonRequired)
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:        "異常系: 一意制約違反 (409)",
+			itemID:      "1",
+			requestBody: `{"name": "名前"}`,
+			setupMock: func(mockUsecase *MockItemUsecase) {
+				input := usecase.UpdateItemInput{
+					Name: strPtr("名前"),
+				}
+				mockUsecase.On("UpdateItem", mock.Anything, int64(1), input).Return((*entity.Item)(nil), fmt.Errorf("%w: %w", domainErrors.ErrDatabaseError, domainErrors.ErrDuplicateEntry))
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:        "異常系: DB接続断 (503)",
+			itemID:      "1",
+			requestBody: `{"name": "名前"}`,
+			setupMock: func(mockUsecase *MockItemUsecase) {
+				input := usecase.UpdateItemInput{
+					Name: strPtr("名前"),
+				}
+				mockUsecase.On("UpdateItem", mock.Anything, int64(1), input).Return((*entity.Item)(nil), fmt.Errorf("%w: %w", domainErrors.ErrDatabaseError, domainErrors.ErrConnectionLost))
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+		},
 	}
 
 	for _, tt := range tests {
@@ -224,3 +429,671 @@ func strPtr(s string) *string {
 func intPtr(i int) *int {
 	return &i
 }
+
+func TestItemHandler_PriceWarning(t *testing.T) {
+	requestBody := `{"name": "スニーカー", "category": "靴", "brand": "NIKE", "purchase_price": 15000000, "purchase_date": "2023-01-01"}`
+	anomaly := &entity.PriceAnomaly{PurchasePrice: 15000000, Median: 30000, Samples: 3}
+
+	tests := []struct {
+		name        string
+		requestBody string
+		confirm     bool
+		expected    string
+	}{
+		{
+			name:        "正常系: 相場から外れた価格は Warning ヘッダーで知らせる",
+			requestBody: requestBody,
+			expected:    `299 - "purchase_price 15000000 deviates from the median 30000 of 3 items with the same brand and category"`,
+		},
+		{
+			name:        "正常系: confirm_price で警告を出さない",
+			requestBody: strings.Replace(requestBody, "{", `{"confirm_price": true, `, 1),
+			confirm:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			mockUsecase := new(MockItemUsecase)
+			item, _ := entity.NewItem("スニーカー", "靴", "NIKE", 15000000, "2023-01-01")
+			mockUsecase.On("CreateItem", mock.Anything, mock.Anything).Return(item, nil)
+			if !tt.confirm {
+				mockUsecase.On("CheckPurchasePrice", mock.Anything, item).Return(anomaly)
+			}
+			handler := NewItemHandler(mockUsecase)
+
+			req := httptest.NewRequest(http.MethodPost, "/items", strings.NewReader(tt.requestBody))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+
+			assert.NoError(t, handler.CreateItem(e.NewContext(req, rec)))
+			assert.Equal(t, http.StatusCreated, rec.Code)
+			assert.Equal(t, tt.expected, rec.Header().Get("Warning"))
+			mockUsecase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestItemHandler_GetSummary(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		groupBy  string
+		summary  *usecase.Summary
+		expected string
+	}{
+		{
+			name:     "正常系: group_by を指定しない場合は categories で返す",
+			summary:  &usecase.Summary{GroupBy: "category", Groups: map[string]int{"時計": 2, "靴": 0}, Total: 2},
+			expected: `{"categories": {"時計": 2, "靴": 0}, "total": 2}`,
+		},
+		{
+			name:     "正常系: group_by を指定した場合は groups で返す",
+			query:    "?group_by=brand",
+			groupBy:  "brand",
+			summary:  &usecase.Summary{GroupBy: "brand", Groups: map[string]int{"ROLEX": 2}, Total: 2},
+			expected: `{"group_by": "brand", "groups": {"ROLEX": 2}, "total": 2}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			mockUsecase := new(MockItemUsecase)
+			mockUsecase.On("GetSummary", mock.Anything, tt.groupBy).Return(tt.summary, nil)
+			handler := NewItemHandler(mockUsecase)
+
+			req := httptest.NewRequest(http.MethodGet, "/items/summary"+tt.query, nil)
+			rec := httptest.NewRecorder()
+
+			assert.NoError(t, handler.GetSummary(e.NewContext(req, rec)))
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.JSONEq(t, tt.expected, rec.Body.String())
+			mockUsecase.AssertExpectations(t)
+		})
+	}
+}
+
+// DELETE /items/{id} は論理削除し、保持期間を過ぎてから retention のジョブが完全に削除する
+func TestItemHandler_DeleteItem_PurgedAfterRetention(t *testing.T) {
+	deletedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clk := clock.NewFrozen(deletedAt)
+	item, err := entity.NewItem("時計1", "時計", "ROLEX", 1000000, "2023-01-01")
+	require.NoError(t, err)
+	repo := database.NewMemoryItemRepository(idgen.NewSequence(), item)
+	itemUsecase := usecase.NewItemUsecase(repo, usecase.WithClock(clk))
+
+	none := usecase.RetentionTarget{
+		Count: func(ctx context.Context, cutoff time.Time) (int64, error) { return 0, nil },
+		Purge: func(ctx context.Context, cutoff time.Time) (int64, error) { return 0, nil },
+	}
+	retention := usecase.NewRetentionUsecase(database.NewMemoryRetentionPolicyRepository(), map[string]usecase.RetentionTarget{
+		entity.RetentionAuditLogs: none,
+		entity.RetentionDeletedItems: {
+			Count:             repo.CountDeletedBefore,
+			Purge:             itemUsecase.PurgeExpired,
+			DefaultRetainDays: func() int { return 30 },
+		},
+		entity.RetentionWebhookDeliveries: none,
+	}, clk)
+	purge := func() int64 {
+		results, err := retention.Run(context.Background(), false)
+		require.NoError(t, err)
+		for _, result := range results {
+			if result.DataType == entity.RetentionDeletedItems {
+				return result.Count
+			}
+		}
+		t.Fatal("no result for deleted items")
+		return 0
+	}
+	remaining := func() int64 {
+		count, err := repo.CountDeletedBefore(context.Background(), deletedAt.AddDate(1, 0, 0))
+		require.NoError(t, err)
+		return count
+	}
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodDelete, "/items/1", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+	require.NoError(t, NewItemHandler(itemUsecase).DeleteItem(c))
+	require.Equal(t, http.StatusNoContent, rec.Code)
+
+	_, err = itemUsecase.GetItemByID(context.Background(), 1)
+	assert.ErrorIs(t, err, domainErrors.ErrItemNotFound)
+	assert.Equal(t, int64(1), remaining(), "削除した直後は行が残る")
+
+	clk.Advance(29 * 24 * time.Hour)
+	assert.Zero(t, purge(), "保持期間内は完全に削除しない")
+	assert.Equal(t, int64(1), remaining())
+
+	clk.Advance(2 * 24 * time.Hour)
+	assert.Equal(t, int64(1), purge())
+	assert.Zero(t, remaining())
+}
+
+func TestItemHandler_DeleteItems(t *testing.T) {
+	tests := []struct {
+		name         string
+		query        string
+		setupMock    func(*MockItemUsecase)
+		expectedCode int
+	}{
+		{
+			name:  "正常系: カンマ区切りの ID をまとめて削除する",
+			query: "ids=1,2,3",
+			setupMock: func(m *MockItemUsecase) {
+				m.On("DeleteItems", mock.Anything, []int64{1, 2, 3}, "").
+					Return(&usecase.BulkDeleteResult{Deleted: []int64{1, 3}, NotFound: []int64{2}}, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "異常系: ids がない",
+			query:        "",
+			setupMock:    func(m *MockItemUsecase) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "異常系: 数値でない ID",
+			query:        "ids=1,abc",
+			setupMock:    func(m *MockItemUsecase) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:  "異常系: 理由が必要",
+			query: "ids=1",
+			setupMock: func(m *MockItemUsecase) {
+				m.On("DeleteItems", mock.Anything, []int64{1}, "").Return(nil, domainErrors.ErrReasonRequired)
+			},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			mockUsecase := new(MockItemUsecase)
+			tt.setupMock(mockUsecase)
+			handler := NewItemHandler(mockUsecase)
+
+			req := httptest.NewRequest(http.MethodDelete, "/items?"+tt.query, nil)
+			rec := httptest.NewRecorder()
+
+			assert.NoError(t, handler.DeleteItems(e.NewContext(req, rec)))
+			assert.Equal(t, tt.expectedCode, rec.Code)
+			mockUsecase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestItemHandler_ExportItems(t *testing.T) {
+	item := &entity.Item{
+		ID: 1, Name: "=HYPERLINK(\"x\")", Category: "時計", Brand: "ROLEX", PurchasePrice: 1500000, PurchaseDate: "2023-01-15",
+		CreatedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), UpdatedAt: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		name         string
+		query        string
+		setupMock    func(*MockItemUsecase)
+		expectedCode int
+		expectedBody string
+		expectedRows []xlsx.Row
+	}{
+		{
+			name:  "正常系: 絞り込みに一致するアイテムを CSV で返す",
+			query: "format=csv&category=時計",
+			setupMock: func(m *MockItemUsecase) {
+				m.On("EachItem", mock.Anything, mock.MatchedBy(func(q listquery.Query) bool { return q.Filter != nil }), mock.Anything).
+					Return([][]*entity.Item{{item}}, nil)
+			},
+			expectedCode: http.StatusOK,
+			expectedBody: "id,name,category,brand,purchase_price,purchase_date,organization_id,created_at,updated_at\n" +
+				"1,\"'=HYPERLINK(\"\"x\"\")\",時計,ROLEX,1500000,2023-01-15,,2024-01-01T00:00:00Z,2024-01-02T00:00:00Z\n",
+		},
+		{
+			name:  "正常系: bom=true で先頭に BOM を付ける",
+			query: "bom=true",
+			setupMock: func(m *MockItemUsecase) {
+				m.On("EachItem", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+			},
+			expectedCode: http.StatusOK,
+			expectedBody: "\ufeffid,name,category,brand,purchase_price,purchase_date,organization_id,created_at,updated_at\n",
+		},
+		{
+			name:  "正常系: format=xlsx で Excel のファイルを返す",
+			query: "format=xlsx",
+			setupMock: func(m *MockItemUsecase) {
+				m.On("EachItem", mock.Anything, mock.Anything, mock.Anything).Return([][]*entity.Item{{item}}, nil)
+			},
+			expectedCode: http.StatusOK,
+			expectedRows: []xlsx.Row{
+				{Num: 1, Cells: []string{"id", "name", "category", "brand", "purchase_price", "purchase_date", "organization_id", "created_at", "updated_at"}},
+				{Num: 2, Cells: []string{"1", "=HYPERLINK(\"x\")", "時計", "ROLEX", "1500000", "2023-01-15", "", "2024-01-01T00:00:00Z", "2024-01-02T00:00:00Z"}},
+			},
+		},
+		{
+			name:  "正常系: format=ndjson で 1 行に 1 件ずつ返す",
+			query: "format=ndjson",
+			setupMock: func(m *MockItemUsecase) {
+				m.On("EachItem", mock.Anything, mock.Anything, mock.Anything).Return([][]*entity.Item{{item}, {item}}, nil)
+			},
+			expectedCode: http.StatusOK,
+			expectedBody: strings.Repeat(`{"id":1,"name":"=HYPERLINK(\"x\")","category":"時計","brand":"ROLEX","purchase_price":1500000,"purchase_date":"2023-01-15","created_at":"2024-01-01T00:00:00Z","updated_at":"2024-01-02T00:00:00Z"}`+"\n", 2),
+		},
+		{
+			name:         "異常系: 対応していない形式",
+			query:        "format=xml",
+			setupMock:    func(m *MockItemUsecase) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:  "異常系: 書き出す前に失敗した場合はエラーを返す",
+			query: "",
+			setupMock: func(m *MockItemUsecase) {
+				m.On("EachItem", mock.Anything, mock.Anything, mock.Anything).Return(nil, domainErrors.ErrDatabaseError)
+			},
+			expectedCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			mockUsecase := new(MockItemUsecase)
+			tt.setupMock(mockUsecase)
+			handler := NewItemHandler(mockUsecase)
+
+			req := httptest.NewRequest(http.MethodGet, "/items/export?"+tt.query, nil)
+			rec := httptest.NewRecorder()
+
+			assert.NoError(t, handler.ExportItems(e.NewContext(req, rec)))
+			assert.Equal(t, tt.expectedCode, rec.Code)
+			if tt.expectedBody != "" {
+				assert.Equal(t, tt.expectedBody, rec.Body.String())
+			}
+			if tt.expectedRows != nil {
+				rows, err := xlsx.ReadRows(bytes.NewReader(rec.Body.Bytes()), int64(rec.Body.Len()))
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedRows, rows)
+			}
+			mockUsecase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestItemHandler_ImportItems(t *testing.T) {
+	const header = "name,category,brand,purchase_price,purchase_date\n"
+
+	tests := []struct {
+		name         string
+		query        string
+		filename     string // 空の場合は items.csv
+		file         string // 空の場合はファイルを付けない
+		setupMock    func(*MockItemUsecase)
+		expectedCode int
+	}{
+		{
+			name:  "正常系: dry_run では検証結果を 200 で返す",
+			query: "dry_run=true",
+			file:  "\ufeff" + header + "デイトナ,時計,ROLEX,1500000,2023-01-15\n",
+			setupMock: func(m *MockItemUsecase) {
+				rows := []usecase.ImportRow{{Row: 2, Input: usecase.CreateItemInput{Name: "デイトナ", Category: "時計", Brand: "ROLEX", PurchasePrice: 1500000, PurchaseDate: "2023-01-15"}}}
+				m.On("ImportItems", mock.Anything, rows, true).Return(&usecase.ImportResult{DryRun: true, Rows: 1, Valid: 1}, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name: "正常系: 数値でない価格は行の問題として渡し、一部失敗は 207 を返す",
+			file: header + "デイトナ,時計,ROLEX,abc,2023-01-15\nバーキン,バッグ,HERMES,2500000,2023-02-01\n",
+			setupMock: func(m *MockItemUsecase) {
+				m.On("ImportItems", mock.Anything, mock.MatchedBy(func(rows []usecase.ImportRow) bool {
+					return len(rows) == 2 && rows[0].Problems[0].Field == "purchase_price" && rows[1].Row == 3
+				}), false).Return(&usecase.ImportResult{Rows: 2, Valid: 1, Created: 1, Failed: 1}, nil)
+			},
+			expectedCode: http.StatusMultiStatus,
+		},
+		{
+			name: "正常系: すべて登録できた場合は 201 を返す",
+			file: header + "デイトナ,時計,ROLEX,1500000,2023-01-15\n",
+			setupMock: func(m *MockItemUsecase) {
+				m.On("ImportItems", mock.Anything, mock.Anything, false).Return(&usecase.ImportResult{Rows: 1, Valid: 1, Created: 1}, nil)
+			},
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name:     "正常系: xlsx では日付のシリアル値を日付に直し、空の行を飛ばす",
+			filename: "items.xlsx",
+			file: xlsxFile(t,
+				[]any{"category", "name", "brand", "purchase_price", "purchase_date"},
+				[]any{"時計", "デイトナ", "ROLEX", 1500000, 45306},
+				[]any{nil, ""},
+			),
+			setupMock: func(m *MockItemUsecase) {
+				rows := []usecase.ImportRow{{Row: 2, Input: usecase.CreateItemInput{Name: "デイトナ", Category: "時計", Brand: "ROLEX", PurchasePrice: 1500000, PurchaseDate: "2024-01-15"}}}
+				m.On("ImportItems", mock.Anything, rows, false).Return(&usecase.ImportResult{Rows: 1, Valid: 1, Created: 1}, nil)
+			},
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name:     "正常系: ndjson では行ごとに読み込み、読めない行はその行の問題にする",
+			filename: "items.ndjson",
+			file: `{"id":9,"name":"デイトナ","category":"時計","brand":"ROLEX","purchase_price":1500000,"purchase_date":"2023-01-15","created_at":"2024-01-01T00:00:00Z"}` + "\n\n" +
+				`{"name":"バーキン","purchase_price":"高い"}` + "\n" +
+				`[1,2]` + "\n",
+			setupMock: func(m *MockItemUsecase) {
+				rows := []usecase.ImportRow{
+					{Row: 1, Input: usecase.CreateItemInput{Name: "デイトナ", Category: "時計", Brand: "ROLEX", PurchasePrice: 1500000, PurchaseDate: "2023-01-15"}},
+					{Row: 3, Input: usecase.CreateItemInput{Name: "バーキン"}, Problems: entity.ValidationErrors{{Field: "purchase_price", Message: "purchase_price has an invalid type"}}},
+					{Row: 4, Problems: entity.ValidationErrors{{Message: "each line must be a JSON object"}}},
+				}
+				m.On("ImportItems", mock.Anything, rows, true).Return(&usecase.ImportResult{DryRun: true, Rows: 3, Valid: 1, Failed: 2}, nil)
+			},
+			query:        "dry_run=true",
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "異常系: xlsx として読めない",
+			query:        "format=xlsx",
+			file:         header,
+			setupMock:    func(m *MockItemUsecase) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "異常系: ファイルがない",
+			setupMock:    func(m *MockItemUsecase) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "異常系: 必要な列がない",
+			file:         "name,category\nデイトナ,時計\n",
+			setupMock:    func(m *MockItemUsecase) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "異常系: dry_run が真偽値でない",
+			query:        "dry_run=maybe",
+			file:         header,
+			setupMock:    func(m *MockItemUsecase) {},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			mockUsecase := new(MockItemUsecase)
+			tt.setupMock(mockUsecase)
+			handler := NewItemHandler(mockUsecase)
+
+			var body bytes.Buffer
+			form := multipart.NewWriter(&body)
+			if tt.file != "" {
+				filename := tt.filename
+				if filename == "" {
+					filename = "items.csv"
+				}
+				part, err := form.CreateFormFile("file", filename)
+				assert.NoError(t, err)
+				part.Write([]byte(tt.file))
+			}
+			form.Close()
+
+			req := httptest.NewRequest(http.MethodPost, "/items/import?"+tt.query, &body)
+			req.Header.Set(echo.HeaderContentType, form.FormDataContentType())
+			rec := httptest.NewRecorder()
+
+			assert.NoError(t, handler.ImportItems(e.NewContext(req, rec)))
+			assert.Equal(t, tt.expectedCode, rec.Code)
+			mockUsecase.AssertExpectations(t)
+		})
+	}
+}
+
+// stubImportProfiles は ID 5 のプロファイルだけを返す取り込みプロファイル
+type stubImportProfiles struct {
+	usecase.ImportProfileUsecase
+	profile *entity.ImportProfile
+}
+
+func (s stubImportProfiles) Get(ctx context.Context, id int64) (*entity.ImportProfile, error) {
+	if id != 5 {
+		return nil, domainErrors.ErrImportProfileNotFound
+	}
+	return s.profile, nil
+}
+
+func TestItemHandler_ImportItemsWithProfile(t *testing.T) {
+	profile, err := entity.NewImportProfile(7, "supplier A", map[string]string{
+		"Artikel": "name",
+		"Marke":   "brand",
+		"Preis":   "purchase_price",
+		"Datum":   "purchase_date",
+	}, "DD.MM.YYYY", map[string]string{"category": "時計"}, time.Now())
+	require.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		query        string
+		filename     string
+		file         string
+		setupMock    func(*MockItemUsecase)
+		expectedCode int
+	}{
+		{
+			name:  "正常系: プロファイルの列名・日付の書式・既定値で取り込む",
+			query: "profile=5",
+			file:  "Artikel,Marke,Preis,Datum\nデイトナ,ROLEX,1500000,15.01.2024\nサブマリーナー,ROLEX,900000,2024/02/01\n",
+			setupMock: func(m *MockItemUsecase) {
+				rows := []usecase.ImportRow{
+					{Row: 2, Input: usecase.CreateItemInput{Name: "デイトナ", Category: "時計", Brand: "ROLEX", PurchasePrice: 1500000, PurchaseDate: "2024-01-15"}},
+					{Row: 3, Input: usecase.CreateItemInput{Name: "サブマリーナー", Category: "時計", Brand: "ROLEX", PurchasePrice: 900000, PurchaseDate: "2024/02/01"},
+						Problems: entity.ValidationErrors{{Field: "purchase_date", Message: "purchase_date must be in the format DD.MM.YYYY"}}},
+				}
+				m.On("ImportItems", mock.Anything, rows, false).Return(&usecase.ImportResult{Rows: 2, Valid: 1, Created: 1, Failed: 1}, nil)
+			},
+			expectedCode: http.StatusMultiStatus,
+		},
+		{
+			name:         "異常系: プロファイルの列がない",
+			query:        "profile=5",
+			file:         "Artikel,Marke,Preis\nデイトナ,ROLEX,1500000\n",
+			setupMock:    func(m *MockItemUsecase) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "異常系: プロファイルが見つからない",
+			query:        "profile=6",
+			file:         "Artikel,Marke,Preis,Datum\n",
+			setupMock:    func(m *MockItemUsecase) {},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "異常系: ndjson にはプロファイルを使えない",
+			query:        "profile=5",
+			filename:     "items.ndjson",
+			file:         `{"name":"デイトナ"}`,
+			setupMock:    func(m *MockItemUsecase) {},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			mockUsecase := new(MockItemUsecase)
+			tt.setupMock(mockUsecase)
+			handler := NewItemHandler(mockUsecase, WithImportProfiles(stubImportProfiles{profile: profile}))
+
+			var body bytes.Buffer
+			form := multipart.NewWriter(&body)
+			filename := tt.filename
+			if filename == "" {
+				filename = "items.csv"
+			}
+			part, err := form.CreateFormFile("file", filename)
+			require.NoError(t, err)
+			part.Write([]byte(tt.file))
+			form.Close()
+
+			req := httptest.NewRequest(http.MethodPost, "/items/import?"+tt.query, &body)
+			req.Header.Set(echo.HeaderContentType, form.FormDataContentType())
+			rec := httptest.NewRecorder()
+
+			assert.NoError(t, handler.ImportItems(e.NewContext(req, rec)))
+			assert.Equal(t, tt.expectedCode, rec.Code)
+			mockUsecase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestItemHandler_ImportTemplate(t *testing.T) {
+	t.Run("正常系: ヘッダーとカテゴリーの入力規則を含む xlsx を返す", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/items/import/template", nil)
+		rec := httptest.NewRecorder()
+
+		assert.NoError(t, NewItemHandler(new(MockItemUsecase)).ImportTemplate(echo.New().NewContext(req, rec)))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		rows, err := xlsx.ReadRows(bytes.NewReader(rec.Body.Bytes()), int64(rec.Body.Len()))
+		assert.NoError(t, err)
+		assert.Equal(t, []xlsx.Row{{Num: 1, Cells: []string{"name", "category", "brand", "purchase_price", "purchase_date", "organization_id"}}}, rows)
+
+		z, err := zip.NewReader(bytes.NewReader(rec.Body.Bytes()), int64(rec.Body.Len()))
+		assert.NoError(t, err)
+		f, err := z.Open("xl/worksheets/sheet1.xml")
+		assert.NoError(t, err)
+		sheet, _ := io.ReadAll(f)
+		assert.Contains(t, string(sheet), `sqref="B2:B10001"><formula1>&#34;時計,バッグ,ジュエリー,靴,その他&#34;</formula1>`)
+	})
+}
+
+// テスト用の xlsx ファイルを作る
+func xlsxFile(t *testing.T, rows ...[]any) string {
+	var buf bytes.Buffer
+	w, err := xlsx.NewWriter(&buf, "items")
+	assert.NoError(t, err)
+	for _, row := range rows {
+		assert.NoError(t, w.Write(row...))
+	}
+	assert.NoError(t, w.Close())
+	return buf.String()
+}
+
+// stubImportStaging は ID 4 の置き場だけを扱う取り込みの置き場
+type stubImportStaging struct {
+	usecase.ImportStagingUsecase
+	staged    *usecase.StageImportInput
+	committed bool // true の場合は確定済みとして扱う
+	result    *usecase.ImportResult
+}
+
+func (s *stubImportStaging) Stage(ctx context.Context, input usecase.StageImportInput) (*entity.ImportBatch, error) {
+	s.staged = &input
+	return &entity.ImportBatch{ID: 4, Filename: input.Filename, Status: entity.ImportBatchStaged, Rows: len(input.Rows)}, nil
+}
+
+func (s *stubImportStaging) UpdateRow(ctx context.Context, id int64, row int, input usecase.UpdateStagedRowInput) (*entity.StagedImportRow, error) {
+	switch {
+	case id != 4:
+		return nil, domainErrors.ErrImportBatchNotFound
+	case s.committed:
+		return nil, domainErrors.ErrImportCommitted
+	}
+	return &entity.StagedImportRow{Row: row, Name: *input.Name}, nil
+}
+
+func (s *stubImportStaging) Commit(ctx context.Context, id int64) (*usecase.ImportResult, error) {
+	if id != 4 {
+		return nil, domainErrors.ErrImportBatchNotFound
+	}
+	return s.result, nil
+}
+
+func TestItemHandler_StageImport(t *testing.T) {
+	e := echo.New()
+	staging := &stubImportStaging{}
+	handler := NewItemHandler(new(MockItemUsecase), WithImportStaging(staging))
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("file", "items.csv")
+	require.NoError(t, err)
+	part.Write([]byte("name,category,brand,purchase_price,purchase_date\nデイトナ,時計,ROLEX,abc,2024-01-15\n"))
+	form.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/imports", &body)
+	req.Header.Set(echo.HeaderContentType, form.FormDataContentType())
+	rec := httptest.NewRecorder()
+
+	require.NoError(t, handler.StageImport(e.NewContext(req, rec)))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	require.NotNil(t, staging.staged)
+	assert.Equal(t, "items.csv", staging.staged.Filename)
+	require.Len(t, staging.staged.Rows, 1)
+	// 読めない値も行として置き場に渡す
+	assert.Equal(t, "purchase_price", staging.staged.Rows[0].Problems[0].Field)
+}
+
+func TestItemHandler_UpdateImportRow(t *testing.T) {
+	tests := []struct {
+		name         string
+		id           string
+		row          string
+		committed    bool
+		expectedCode int
+	}{
+		{name: "正常系: 行を直す", id: "4", row: "3", expectedCode: http.StatusOK},
+		{name: "異常系: 確定した置き場", id: "4", row: "3", committed: true, expectedCode: http.StatusConflict},
+		{name: "異常系: 置き場が見つからない", id: "5", row: "3", expectedCode: http.StatusNotFound},
+		{name: "異常系: 行番号が不正", id: "4", row: "0", expectedCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			handler := NewItemHandler(new(MockItemUsecase), WithImportStaging(&stubImportStaging{committed: tt.committed}))
+
+			req := httptest.NewRequest(http.MethodPatch, "/imports/"+tt.id+"/rows/"+tt.row, strings.NewReader(`{"name":"デイトナ"}`))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("id", "row")
+			c.SetParamValues(tt.id, tt.row)
+
+			assert.NoError(t, handler.UpdateImportRow(c))
+			assert.Equal(t, tt.expectedCode, rec.Code)
+		})
+	}
+}
+
+func TestItemHandler_CommitImport(t *testing.T) {
+	tests := []struct {
+		name         string
+		result       *usecase.ImportResult
+		expectedCode int
+	}{
+		{name: "正常系: すべての行を登録した", result: &usecase.ImportResult{Rows: 2, Valid: 2, Created: 2}, expectedCode: http.StatusOK},
+		{name: "異常系: 問題のある行が残っている", result: &usecase.ImportResult{Rows: 2, Valid: 1, Failed: 1}, expectedCode: http.StatusUnprocessableEntity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			handler := NewItemHandler(new(MockItemUsecase), WithImportStaging(&stubImportStaging{result: tt.result}))
+
+			req := httptest.NewRequest(http.MethodPost, "/imports/4/commit", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("id")
+			c.SetParamValues("4")
+
+			assert.NoError(t, handler.CommitImport(c))
+			assert.Equal(t, tt.expectedCode, rec.Code)
+		})
+	}
+}
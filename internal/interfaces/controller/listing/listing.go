@@ -0,0 +1,44 @@
+// Package listing は親リソース配下の一覧エンドポイント（/items/:id/price-history など）を
+// 組み立てるためのヘルパー。ID の解釈、filter / sort / page の適用、レスポンスの形式を共通化し、
+// 各コントローラーは取得処理とエラーの対応だけを書けばよいようにする。
+package listing
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"Aicon-assignment/internal/interfaces/controller/response"
+	"Aicon-assignment/internal/pkg/listquery"
+)
+
+// 親リソース配下の一覧
+type SubResource[T any] struct {
+	Parent string // 親リソースの名前。不正なIDのエラーメッセージに使う（例: "item"）
+	Spec   listquery.Spec
+	// 要素のフィールドの値（絞り込み・並び替え用）
+	Value func(item T, field string) any
+	// 親リソースのIDから一覧を取得する。c はクエリパラメータの参照に使う
+	Fetch func(c echo.Context, parentID int64) ([]T, error)
+	// 取得に失敗した場合のレスポンス
+	Error func(c echo.Context, err error) error
+}
+
+func (l SubResource[T]) Handle(c echo.Context) error {
+	parentID, ok := response.ParseID(c, "id")
+	if !ok {
+		return response.Error(c, http.StatusBadRequest, "invalid "+l.Parent+" ID")
+	}
+
+	q, err := listquery.Parse(c.QueryParams(), l.Spec)
+	if err != nil {
+		return response.ValidationError(c, err)
+	}
+
+	items, err := l.Fetch(c, parentID)
+	if err != nil {
+		return l.Error(c, err)
+	}
+
+	return response.List(c, listquery.Apply(items, q, l.Value), q)
+}
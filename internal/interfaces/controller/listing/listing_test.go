@@ -0,0 +1,142 @@
+package listing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"Aicon-assignment/internal/interfaces/controller/response"
+	"Aicon-assignment/internal/pkg/filter"
+	"Aicon-assignment/internal/pkg/listquery"
+)
+
+type comment struct {
+	ID   int64  `json:"id"`
+	Body string `json:"body"`
+}
+
+var errParentNotFound = errors.New("parent not found")
+
+func commentList(fetch func(ctx context.Context, parentID int64) ([]comment, error)) SubResource[comment] {
+	return SubResource[comment]{
+		Parent: "item",
+		Spec: listquery.Spec{
+			Filters:     filter.Fields{"body": filter.String},
+			Sorts:       []string{"id"},
+			DefaultSort: []listquery.SortField{{Field: "id"}},
+		},
+		Value: func(c comment, field string) any {
+			if field == "id" {
+				return c.ID
+			}
+			return c.Body
+		},
+		Fetch: func(c echo.Context, parentID int64) ([]comment, error) {
+			return fetch(c.Request().Context(), parentID)
+		},
+		Error: func(c echo.Context, err error) error {
+			return response.Error(c, http.StatusNotFound, err.Error())
+		},
+	}
+}
+
+func TestSubResource_Handle(t *testing.T) {
+	comments := []comment{{1, "a"}, {2, "b"}, {3, "c"}}
+	fetch := func(ctx context.Context, parentID int64) ([]comment, error) {
+		if parentID != 1 {
+			return nil, errParentNotFound
+		}
+		return comments, nil
+	}
+
+	tests := []struct {
+		name           string
+		parentID       string
+		query          string
+		expectedStatus int
+		expectedIDs    []int64
+		expectedTotal  string
+		expectedLink   string
+	}{
+		{
+			name:           "正常系: 並び替えとページング",
+			parentID:       "1",
+			query:          "sort=-id&page[size]=2",
+			expectedStatus: http.StatusOK,
+			expectedIDs:    []int64{3, 2},
+			expectedTotal:  "3",
+			expectedLink:   `rel="next"`,
+		},
+		{
+			name:           "正常系: limit/offset でのページング",
+			parentID:       "1",
+			query:          "sort=-id&limit=1&offset=1",
+			expectedStatus: http.StatusOK,
+			expectedIDs:    []int64{2},
+			expectedTotal:  "3",
+			expectedLink:   `</items/1/comments?limit=1&offset=0&sort=-id>; rel="prev", </items/1/comments?limit=1&offset=2&sort=-id>; rel="next"`,
+		},
+		{
+			name:           "正常系: 絞り込み",
+			parentID:       "1",
+			query:          "filter[body]=b",
+			expectedStatus: http.StatusOK,
+			expectedIDs:    []int64{2},
+			expectedTotal:  "1",
+		},
+		{
+			name:           "異常系: 不正な親ID",
+			parentID:       "abc",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "異常系: 使えない並び替え",
+			parentID:       "1",
+			query:          "sort=body",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "異常系: 取得エラーは Error に委ねる",
+			parentID:       "2",
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/items/"+tt.parentID+"/comments?"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("id")
+			c.SetParamValues(tt.parentID)
+
+			err := commentList(fetch).Handle(c)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var got []comment
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+			ids := make([]int64, len(got))
+			for i, c := range got {
+				ids[i] = c.ID
+			}
+			assert.Equal(t, tt.expectedIDs, ids)
+			assert.Equal(t, tt.expectedTotal, rec.Header().Get("X-Total-Count"))
+			if tt.expectedLink != "" {
+				assert.Contains(t, rec.Header().Get("Link"), tt.expectedLink)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package notifications
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/interfaces/controller/response"
+	"Aicon-assignment/internal/usecase"
+)
+
+type NotificationHandler struct {
+	notificationUsecase usecase.NotificationUsecase
+}
+
+func NewNotificationHandler(notificationUsecase usecase.NotificationUsecase) *NotificationHandler {
+	return &NotificationHandler{
+		notificationUsecase: notificationUsecase,
+	}
+}
+
+// 設定に、このサーバーで選べるチャンネルを添えて返す
+type preferenceResponse struct {
+	*entity.NotificationPreference
+	AvailableChannels []entity.NotificationChannel `json:"available_channels"`
+}
+
+func (h *NotificationHandler) GetPreference(c echo.Context) error {
+	preference, err := h.notificationUsecase.GetPreference(c.Request().Context())
+	if err != nil {
+		return h.errorResponse(c, err, "failed to retrieve notification preference")
+	}
+
+	return c.JSON(http.StatusOK, h.preferenceResponse(preference))
+}
+
+func (h *NotificationHandler) UpdatePreference(c echo.Context) error {
+	var input usecase.NotificationPreferenceInput
+	if err := c.Bind(&input); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	preference, err := h.notificationUsecase.UpdatePreference(c.Request().Context(), input)
+	if err != nil {
+		return h.errorResponse(c, err, "failed to update notification preference")
+	}
+
+	return c.JSON(http.StatusOK, h.preferenceResponse(preference))
+}
+
+// 選んだチャンネルに試しの通知を送る。チャンネルごとの失敗は結果に含め、200 で返す
+func (h *NotificationHandler) SendTest(c echo.Context) error {
+	results, err := h.notificationUsecase.SendTest(c.Request().Context())
+	if err != nil {
+		return h.errorResponse(c, err, "failed to send test notification")
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{"results": results})
+}
+
+func (h *NotificationHandler) preferenceResponse(preference *entity.NotificationPreference) preferenceResponse {
+	return preferenceResponse{
+		NotificationPreference: preference,
+		AvailableChannels:      h.notificationUsecase.Channels(),
+	}
+}
+
+func (h *NotificationHandler) errorResponse(c echo.Context, err error, fallback string) error {
+	switch {
+	case domainErrors.IsUnauthenticatedError(err):
+		return response.Error(c, http.StatusUnauthorized, "authentication required")
+	case domainErrors.IsValidationError(err):
+		return response.ValidationError(c, err)
+	}
+	return response.RepositoryError(c, err, fallback)
+}
@@ -0,0 +1,235 @@
+package organizations
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/interfaces/controller/response"
+	"Aicon-assignment/internal/usecase"
+)
+
+type OrganizationHandler struct {
+	organizationUsecase usecase.OrganizationUsecase
+}
+
+func NewOrganizationHandler(organizationUsecase usecase.OrganizationUsecase) *OrganizationHandler {
+	return &OrganizationHandler{
+		organizationUsecase: organizationUsecase,
+	}
+}
+
+type acceptInvitationRequest struct {
+	Token string `json:"token"`
+}
+
+// 組織を作成する。作成したユーザーが管理者になる
+func (h *OrganizationHandler) Create(c echo.Context) error {
+	var input usecase.CreateOrganizationInput
+	if err := c.Bind(&input); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	org, err := h.organizationUsecase.CreateOrganization(c.Request().Context(), input)
+	if err != nil {
+		return h.errorResponse(c, err, "failed to create organization")
+	}
+
+	return c.JSON(http.StatusCreated, org)
+}
+
+func (h *OrganizationHandler) ListMembers(c echo.Context) error {
+	orgID, ok := response.ParseID(c, "id")
+	if !ok {
+		return response.Error(c, http.StatusBadRequest, "invalid organization ID")
+	}
+
+	members, err := h.organizationUsecase.ListMembers(c.Request().Context(), orgID)
+	if err != nil {
+		return h.errorResponse(c, err, "failed to retrieve members")
+	}
+
+	return c.JSON(http.StatusOK, members)
+}
+
+func (h *OrganizationHandler) ChangeRole(c echo.Context) error {
+	orgID, userID, ok := parseMemberPath(c)
+	if !ok {
+		return response.Error(c, http.StatusBadRequest, "invalid organization or user ID")
+	}
+
+	var input usecase.ChangeRoleInput
+	if err := c.Bind(&input); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	member, err := h.organizationUsecase.ChangeRole(c.Request().Context(), orgID, userID, input)
+	if err != nil {
+		return h.errorResponse(c, err, "failed to change role")
+	}
+
+	return c.JSON(http.StatusOK, member)
+}
+
+func (h *OrganizationHandler) RemoveMember(c echo.Context) error {
+	orgID, userID, ok := parseMemberPath(c)
+	if !ok {
+		return response.Error(c, http.StatusBadRequest, "invalid organization or user ID")
+	}
+
+	if err := h.organizationUsecase.RemoveMember(c.Request().Context(), orgID, userID); err != nil {
+		return h.errorResponse(c, err, "failed to remove member")
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// 招待メールを送る。トークンはレスポンスに含めない
+func (h *OrganizationHandler) Invite(c echo.Context) error {
+	orgID, ok := response.ParseID(c, "id")
+	if !ok {
+		return response.Error(c, http.StatusBadRequest, "invalid organization ID")
+	}
+
+	var input usecase.InviteInput
+	if err := c.Bind(&input); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	invitation, err := h.organizationUsecase.Invite(c.Request().Context(), orgID, input)
+	if err != nil {
+		return h.errorResponse(c, err, "failed to send invitation")
+	}
+
+	return c.JSON(http.StatusCreated, invitation)
+}
+
+func (h *OrganizationHandler) ListInvitations(c echo.Context) error {
+	orgID, ok := response.ParseID(c, "id")
+	if !ok {
+		return response.Error(c, http.StatusBadRequest, "invalid organization ID")
+	}
+
+	invitations, err := h.organizationUsecase.ListInvitations(c.Request().Context(), orgID)
+	if err != nil {
+		return h.errorResponse(c, err, "failed to retrieve invitations")
+	}
+
+	return c.JSON(http.StatusOK, invitations)
+}
+
+// 呼び出し元のユーザーとして招待を承諾する
+func (h *OrganizationHandler) AcceptInvitation(c echo.Context) error {
+	var req acceptInvitationRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	member, err := h.organizationUsecase.AcceptInvitation(c.Request().Context(), req.Token)
+	if err != nil {
+		return h.errorResponse(c, err, "failed to accept invitation")
+	}
+
+	return c.JSON(http.StatusCreated, member)
+}
+
+// 共有ページなどに表示するブランディング。認証なしで参照できる
+func (h *OrganizationHandler) GetBranding(c echo.Context) error {
+	orgID, ok := response.ParseID(c, "id")
+	if !ok {
+		return response.Error(c, http.StatusBadRequest, "invalid organization ID")
+	}
+
+	branding, err := h.organizationUsecase.GetBranding(c.Request().Context(), orgID)
+	if err != nil {
+		return h.errorResponse(c, err, "failed to retrieve branding")
+	}
+
+	return c.JSON(http.StatusOK, branding)
+}
+
+// ブランディングを置き換える。省略した項目はデフォルトに戻る
+func (h *OrganizationHandler) UpdateBranding(c echo.Context) error {
+	orgID, ok := response.ParseID(c, "id")
+	if !ok {
+		return response.Error(c, http.StatusBadRequest, "invalid organization ID")
+	}
+
+	var input usecase.UpdateBrandingInput
+	if err := c.Bind(&input); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	branding, err := h.organizationUsecase.UpdateBranding(c.Request().Context(), orgID, input)
+	if err != nil {
+		return h.errorResponse(c, err, "failed to update branding")
+	}
+
+	return c.JSON(http.StatusOK, branding)
+}
+
+// 組織のアイテムに適用するロックのルール
+func (h *OrganizationHandler) GetFieldLocks(c echo.Context) error {
+	orgID, ok := response.ParseID(c, "id")
+	if !ok {
+		return response.Error(c, http.StatusBadRequest, "invalid organization ID")
+	}
+
+	policy, err := h.organizationUsecase.GetFieldLocks(c.Request().Context(), orgID)
+	if err != nil {
+		return h.errorResponse(c, err, "failed to retrieve field lock rules")
+	}
+
+	return c.JSON(http.StatusOK, policy)
+}
+
+// ロックのルールをすべて置き換える
+func (h *OrganizationHandler) UpdateFieldLocks(c echo.Context) error {
+	orgID, ok := response.ParseID(c, "id")
+	if !ok {
+		return response.Error(c, http.StatusBadRequest, "invalid organization ID")
+	}
+
+	var input usecase.UpdateFieldLocksInput
+	if err := c.Bind(&input); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	policy, err := h.organizationUsecase.UpdateFieldLocks(c.Request().Context(), orgID, input)
+	if err != nil {
+		return h.errorResponse(c, err, "failed to update field lock rules")
+	}
+
+	return c.JSON(http.StatusOK, policy)
+}
+
+func parseMemberPath(c echo.Context) (int64, int64, bool) {
+	orgID, ok := response.ParseID(c, "id")
+	if !ok {
+		return 0, 0, false
+	}
+	userID, ok := response.ParseID(c, "userId")
+	if !ok {
+		return 0, 0, false
+	}
+	return orgID, userID, true
+}
+
+func (h *OrganizationHandler) errorResponse(c echo.Context, err error, fallback string) error {
+	switch {
+	case domainErrors.IsNotFoundError(err):
+		return response.Error(c, http.StatusNotFound, err.Error())
+	case domainErrors.IsValidationError(err):
+		return response.ValidationError(c, err)
+	case domainErrors.IsUnauthenticatedError(err):
+		return response.Error(c, http.StatusUnauthorized, "authentication required")
+	case domainErrors.IsForbiddenError(err):
+		return response.Error(c, http.StatusForbidden, err.Error())
+	case domainErrors.IsLastAdminError(err):
+		return response.Error(c, http.StatusConflict, err.Error())
+	case domainErrors.IsConflictError(err):
+		return response.Error(c, http.StatusConflict, "already a member of this organization")
+	}
+	return response.RepositoryError(c, err, fallback)
+}
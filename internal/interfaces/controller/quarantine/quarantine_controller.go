@@ -0,0 +1,44 @@
+package quarantine
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/interfaces/controller/response"
+	"Aicon-assignment/internal/usecase"
+)
+
+type QuarantineHandler struct {
+	quarantineUsecase usecase.QuarantineUsecase
+}
+
+func NewQuarantineHandler(quarantineUsecase usecase.QuarantineUsecase) *QuarantineHandler {
+	return &QuarantineHandler{
+		quarantineUsecase: quarantineUsecase,
+	}
+}
+
+// ウイルス検査で隔離したファイルの一覧（新しい順）
+func (h *QuarantineHandler) List(c echo.Context) error {
+	files, err := h.quarantineUsecase.List(c.Request().Context())
+	if err != nil {
+		return response.RepositoryError(c, err, "failed to list quarantined files")
+	}
+	return c.JSON(http.StatusOK, files)
+}
+
+func (h *QuarantineHandler) Delete(c echo.Context) error {
+	id, ok := response.ParseID(c, "id")
+	if !ok {
+		return response.Error(c, http.StatusBadRequest, "invalid ID")
+	}
+	if err := h.quarantineUsecase.Delete(c.Request().Context(), id); err != nil {
+		if domainErrors.IsNotFoundError(err) {
+			return response.Error(c, http.StatusNotFound, "quarantined file not found")
+		}
+		return response.RepositoryError(c, err, "failed to delete quarantined file")
+	}
+	return c.NoContent(http.StatusNoContent)
+}
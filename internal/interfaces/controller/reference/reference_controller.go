@@ -0,0 +1,153 @@
+package reference
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/labstack/echo/v4"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/interfaces/controller/response"
+	"Aicon-assignment/internal/usecase"
+)
+
+type ReferenceHandler struct {
+	referenceUsecase usecase.ReferenceUsecase
+}
+
+func NewReferenceHandler(referenceUsecase usecase.ReferenceUsecase) *ReferenceHandler {
+	return &ReferenceHandler{
+		referenceUsecase: referenceUsecase,
+	}
+}
+
+// 種類の一覧と参照データ
+type listResponse struct {
+	Schema  *entity.ReferenceType     `json:"schema"`
+	Records []*entity.ReferenceRecord `json:"records"`
+}
+
+// 更新のリクエスト。キーは URL で指定する
+type updateRequest struct {
+	Attributes map[string]any `json:"attributes"`
+}
+
+// 扱える種類と項目の定義を返す。管理画面はこれを見て入力欄を組み立てる
+func (h *ReferenceHandler) ListTypes(c echo.Context) error {
+	return c.JSON(http.StatusOK, h.referenceUsecase.Types())
+}
+
+func (h *ReferenceHandler) List(c echo.Context) error {
+	refType, ok := pathParam(c, "type")
+	if !ok {
+		return response.Error(c, http.StatusBadRequest, "invalid type")
+	}
+
+	schema, records, err := h.referenceUsecase.List(c.Request().Context(), refType)
+	if err != nil {
+		return h.errorResponse(c, err, "failed to retrieve reference data")
+	}
+
+	return c.JSON(http.StatusOK, listResponse{Schema: schema, Records: records})
+}
+
+func (h *ReferenceHandler) Get(c echo.Context) error {
+	refType, key, ok := recordParams(c)
+	if !ok {
+		return response.Error(c, http.StatusBadRequest, "invalid type or key")
+	}
+
+	record, err := h.referenceUsecase.Get(c.Request().Context(), refType, key)
+	if err != nil {
+		return h.errorResponse(c, err, "failed to retrieve reference data")
+	}
+
+	return c.JSON(http.StatusOK, record)
+}
+
+func (h *ReferenceHandler) Create(c echo.Context) error {
+	refType, ok := pathParam(c, "type")
+	if !ok {
+		return response.Error(c, http.StatusBadRequest, "invalid type")
+	}
+
+	var input usecase.ReferenceInput
+	if err := c.Bind(&input); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	record, err := h.referenceUsecase.Create(c.Request().Context(), refType, input)
+	if err != nil {
+		return h.errorResponse(c, err, "failed to create reference data")
+	}
+
+	return c.JSON(http.StatusCreated, record)
+}
+
+func (h *ReferenceHandler) Update(c echo.Context) error {
+	refType, key, ok := recordParams(c)
+	if !ok {
+		return response.Error(c, http.StatusBadRequest, "invalid type or key")
+	}
+
+	var input updateRequest
+	if err := c.Bind(&input); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	record, err := h.referenceUsecase.Update(c.Request().Context(), refType, key, input.Attributes)
+	if err != nil {
+		return h.errorResponse(c, err, "failed to update reference data")
+	}
+
+	return c.JSON(http.StatusOK, record)
+}
+
+func (h *ReferenceHandler) Delete(c echo.Context) error {
+	refType, key, ok := recordParams(c)
+	if !ok {
+		return response.Error(c, http.StatusBadRequest, "invalid type or key")
+	}
+
+	if err := h.referenceUsecase.Delete(c.Request().Context(), refType, key); err != nil {
+		return h.errorResponse(c, err, "failed to delete reference data")
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// キーには日本語などを使うため、パスパラメータはデコードしてから使う
+func pathParam(c echo.Context, name string) (string, bool) {
+	value, err := url.PathUnescape(c.Param(name))
+	if err != nil || value == "" {
+		return "", false
+	}
+	return value, true
+}
+
+func recordParams(c echo.Context) (string, string, bool) {
+	refType, ok := pathParam(c, "type")
+	if !ok {
+		return "", "", false
+	}
+	key, ok := pathParam(c, "key")
+	if !ok {
+		return "", "", false
+	}
+	return refType, key, true
+}
+
+func (h *ReferenceHandler) errorResponse(c echo.Context, err error, fallback string) error {
+	switch {
+	case domainErrors.IsNotFoundError(err):
+		return response.Error(c, http.StatusNotFound, err.Error())
+	case domainErrors.IsValidationError(err):
+		return response.ValidationError(c, err)
+	case domainErrors.IsForbiddenError(err):
+		return response.Error(c, http.StatusForbidden, err.Error())
+	case domainErrors.IsInUseError(err), domainErrors.IsConflictError(err):
+		return response.Error(c, http.StatusConflict, err.Error())
+	}
+	return response.RepositoryError(c, err, fallback)
+}
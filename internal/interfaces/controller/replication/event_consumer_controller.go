@@ -0,0 +1,111 @@
+package replication
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/interfaces/controller/response"
+	"Aicon-assignment/internal/usecase"
+)
+
+type EventConsumerHandler struct {
+	consumerUsecase usecase.EventConsumerUsecase
+}
+
+func NewEventConsumerHandler(consumerUsecase usecase.EventConsumerUsecase) *EventConsumerHandler {
+	return &EventConsumerHandler{
+		consumerUsecase: consumerUsecase,
+	}
+}
+
+type ackRequest struct {
+	Sequence int64 `json:"sequence"`
+}
+
+func (h *EventConsumerHandler) Create(c echo.Context) error {
+	var input usecase.CreateEventConsumerInput
+	if err := c.Bind(&input); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	consumer, err := h.consumerUsecase.Create(c.Request().Context(), input)
+	if err != nil {
+		return h.errorResponse(c, err, "failed to create event consumer")
+	}
+	return c.JSON(http.StatusCreated, consumer)
+}
+
+func (h *EventConsumerHandler) List(c echo.Context) error {
+	consumers, err := h.consumerUsecase.List(c.Request().Context())
+	if err != nil {
+		return h.errorResponse(c, err, "failed to retrieve event consumers")
+	}
+	return c.JSON(http.StatusOK, consumers)
+}
+
+func (h *EventConsumerHandler) Get(c echo.Context) error {
+	consumer, err := h.consumerUsecase.Get(c.Request().Context(), c.Param("name"))
+	if err != nil {
+		return h.errorResponse(c, err, "failed to retrieve event consumer")
+	}
+	return c.JSON(http.StatusOK, consumer)
+}
+
+func (h *EventConsumerHandler) Delete(c echo.Context) error {
+	if err := h.consumerUsecase.Delete(c.Request().Context(), c.Param("name")); err != nil {
+		return h.errorResponse(c, err, "failed to delete event consumer")
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// 確認済みの連番の後のイベントを配信する。?limit は 1〜1000（省略時は 1000）
+func (h *EventConsumerHandler) Poll(c echo.Context) error {
+	limit := usecase.MaxChangeBatch
+	if raw := c.QueryParam("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 || parsed > usecase.MaxChangeBatch {
+			return response.Error(c, http.StatusBadRequest, "invalid limit")
+		}
+		limit = parsed
+	}
+
+	batch, err := h.consumerUsecase.Poll(c.Request().Context(), c.Param("name"), limit)
+	if err != nil {
+		return h.errorResponse(c, err, "failed to deliver events")
+	}
+	return c.JSON(http.StatusOK, batch)
+}
+
+// {"sequence": N} までのイベントを処理済みにする
+func (h *EventConsumerHandler) Ack(c echo.Context) error {
+	var req ackRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	consumer, err := h.consumerUsecase.Ack(c.Request().Context(), c.Param("name"), req.Sequence)
+	if err != nil {
+		return h.errorResponse(c, err, "failed to acknowledge events")
+	}
+	return c.JSON(http.StatusOK, consumer)
+}
+
+func (h *EventConsumerHandler) errorResponse(c echo.Context, err error, fallback string) error {
+	switch {
+	case domainErrors.IsForbiddenError(err):
+		return response.Error(c, http.StatusForbidden, "admin role required")
+	case domainErrors.IsNotFoundError(err):
+		return response.Error(c, http.StatusNotFound, "event consumer not found")
+	case domainErrors.IsValidationError(err):
+		return response.ValidationError(c, err)
+	case errors.Is(err, domainErrors.ErrBatchInFlight):
+		return response.Error(c, http.StatusConflict, err.Error())
+	case domainErrors.IsConflictError(err):
+		return response.Error(c, http.StatusConflict, "event consumer already exists")
+	}
+	return response.RepositoryError(c, err, fallback)
+}
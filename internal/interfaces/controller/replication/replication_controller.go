@@ -0,0 +1,72 @@
+package replication
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"Aicon-assignment/internal/interfaces/controller/response"
+	"Aicon-assignment/internal/usecase"
+)
+
+type ReplicationHandler struct {
+	changes usecase.ChangeSource
+	replica usecase.ReplicaUsecase // スタンバイでない場合は nil
+}
+
+func NewReplicationHandler(changes usecase.ChangeSource, replica usecase.ReplicaUsecase) *ReplicationHandler {
+	return &ReplicationHandler{
+		changes: changes,
+		replica: replica,
+	}
+}
+
+// スタンバイと共有したトークンを Authorization: Bearer で確認する
+func RequireToken(token string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			given, ok := strings.CutPrefix(c.Request().Header.Get(echo.HeaderAuthorization), "Bearer ")
+			if token == "" || !ok || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+				return response.Error(c, http.StatusUnauthorized, "invalid replication token")
+			}
+			return next(c)
+		}
+	}
+}
+
+// ?after={連番} より後の変更を古い順に返す。?limit は 1〜1000（省略時は 1000）
+func (h *ReplicationHandler) Events(c echo.Context) error {
+	var after int64
+	if raw := c.QueryParam("after"); raw != "" {
+		parsed, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || parsed < 0 {
+			return response.Error(c, http.StatusBadRequest, "invalid after")
+		}
+		after = parsed
+	}
+	limit := usecase.MaxChangeBatch
+	if raw := c.QueryParam("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 || parsed > usecase.MaxChangeBatch {
+			return response.Error(c, http.StatusBadRequest, "invalid limit")
+		}
+		limit = parsed
+	}
+
+	batch, err := h.changes.Changes(c.Request().Context(), after, limit)
+	if err != nil {
+		return response.RepositoryError(c, err, "failed to load changes")
+	}
+	return c.JSON(http.StatusOK, batch)
+}
+
+// スタンバイの複製の状況（適用済みの連番・遅れ・最後のエラー）
+func (h *ReplicationHandler) Status(c echo.Context) error {
+	if h.replica == nil {
+		return response.Error(c, http.StatusNotFound, "this deployment is not a standby replica")
+	}
+	return c.JSON(http.StatusOK, h.replica.Status())
+}
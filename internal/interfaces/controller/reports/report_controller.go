@@ -0,0 +1,60 @@
+package reports
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/interfaces/controller/response"
+	"Aicon-assignment/internal/usecase"
+)
+
+type ReportHandler struct {
+	portfolioUsecase usecase.PortfolioUsecase
+	scenarioUsecase  usecase.ScenarioUsecase
+}
+
+func NewReportHandler(portfolioUsecase usecase.PortfolioUsecase, scenarioUsecase usecase.ScenarioUsecase) *ReportHandler {
+	return &ReportHandler{
+		portfolioUsecase: portfolioUsecase,
+		scenarioUsecase:  scenarioUsecase,
+	}
+}
+
+// ポートフォリオ全体の価値の推移。?range=30d のように期間を指定する（既定は 1y）
+func (h *ReportHandler) PortfolioHistory(c echo.Context) error {
+	history, err := h.portfolioUsecase.History(c.Request().Context(), c.QueryParam("range"))
+	if err != nil {
+		return h.errorResponse(c, err, "failed to retrieve portfolio history")
+	}
+
+	return c.JSON(http.StatusOK, history)
+}
+
+// カテゴリーごとの評価額の増減を仮定し、現在の評価額と比べた試算を返す。何も保存しない
+func (h *ReportHandler) RunScenario(c echo.Context) error {
+	var input usecase.ScenarioInput
+	if err := c.Bind(&input); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	result, err := h.scenarioUsecase.Run(c.Request().Context(), input)
+	if err != nil {
+		return h.errorResponse(c, err, "failed to run scenario")
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+func (h *ReportHandler) errorResponse(c echo.Context, err error, fallback string) error {
+	switch {
+	case domainErrors.IsValidationError(err):
+		return response.ValidationError(c, err)
+	case domainErrors.IsUnauthenticatedError(err):
+		return response.Error(c, http.StatusUnauthorized, "authentication required")
+	case domainErrors.IsForbiddenError(err):
+		return response.Error(c, http.StatusForbidden, err.Error())
+	}
+	return response.RepositoryError(c, err, fallback)
+}
@@ -0,0 +1,92 @@
+package response
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"Aicon-assignment/internal/pkg/listquery"
+)
+
+// カーソルで指定した場合のレスポンス。最後のページでは next_cursor を null にする
+type CursorPage[T any] struct {
+	Data       []T     `json:"data"`
+	NextCursor *string `json:"next_cursor"`
+}
+
+// 一覧を配列で返す。総件数は X-Total-Count、前後のページは Link ヘッダーで返す
+// カーソルで指定した場合は総件数を数えないため、CursorPage の形式で返す
+func List[T any](c echo.Context, result *listquery.Result[T], q listquery.Query) error {
+	if q.Page.CursorMode {
+		return cursorList(c, result)
+	}
+
+	c.Response().Header().Set("X-Total-Count", strconv.Itoa(result.Total))
+
+	if q.Page.Size > 0 {
+		var links []string
+		hasNext := q.Page.Offset()+len(result.Items) < result.Total
+		if q.Page.ByOffset() {
+			if q.Page.Start > 0 {
+				links = append(links, offsetLink(c, max(q.Page.Start-q.Page.Size, 0), q.Page.Size, "prev"))
+			}
+			if hasNext {
+				links = append(links, offsetLink(c, q.Page.Start+q.Page.Size, q.Page.Size, "next"))
+			}
+		} else {
+			if q.Page.Number > 1 {
+				links = append(links, pageLink(c, q.Page.Number-1, q.Page.Size, "prev"))
+			}
+			if hasNext {
+				links = append(links, pageLink(c, q.Page.Number+1, q.Page.Size, "next"))
+			}
+		}
+		if len(links) > 0 {
+			c.Response().Header().Add("Link", strings.Join(links, ", "))
+		}
+	}
+
+	items := result.Items
+	if items == nil {
+		items = []T{}
+	}
+	return c.JSON(http.StatusOK, items)
+}
+
+func cursorList[T any](c echo.Context, result *listquery.Result[T]) error {
+	page := CursorPage[T]{Data: result.Items}
+	if page.Data == nil {
+		page.Data = []T{}
+	}
+	if result.NextCursor != "" {
+		page.NextCursor = &result.NextCursor
+
+		u := *c.Request().URL
+		values := u.Query()
+		values.Set("cursor", result.NextCursor)
+		u.RawQuery = values.Encode()
+		c.Response().Header().Add("Link", fmt.Sprintf(`<%s>; rel="next"`, u.RequestURI()))
+	}
+	return c.JSON(http.StatusOK, page)
+}
+
+func pageLink(c echo.Context, number, size int, rel string) string {
+	return link(c, rel, map[string]int{"page[number]": number, "page[size]": size})
+}
+
+func offsetLink(c echo.Context, offset, limit int, rel string) string {
+	return link(c, rel, map[string]int{"offset": offset, "limit": limit})
+}
+
+func link(c echo.Context, rel string, params map[string]int) string {
+	u := *c.Request().URL
+	values := u.Query()
+	for key, value := range params {
+		values.Set(key, strconv.Itoa(value))
+	}
+	u.RawQuery = values.Encode()
+	return fmt.Sprintf(`<%s>; rel="%s"`, u.RequestURI(), rel)
+}
@@ -0,0 +1,66 @@
+// Package response は各コントローラーで共通のエラーレスポンスを提供する。
+package response
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/pkg/reqctx"
+)
+
+// エラーレスポンスの形式
+type ErrorResponse struct {
+	Error   string   `json:"error"`
+	Details []string `json:"details,omitempty"`
+}
+
+func Error(c echo.Context, status int, message string) error {
+	return c.JSON(status, ErrorResponse{
+		Error: message,
+	})
+}
+
+func ValidationError(c echo.Context, err error) error {
+	return c.JSON(http.StatusBadRequest, ErrorResponse{
+		Error:   "validation failed",
+		Details: []string{err.Error()},
+	})
+}
+
+// パスパラメータのIDを取得する
+func ParseID(c echo.Context, name string) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param(name), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return id, true
+}
+
+// リポジトリ由来のエラーを分類してレスポンスを返す
+// 分類できないエラーは fallback のメッセージで 500 を返す
+func RepositoryError(c echo.Context, err error, fallback string) error {
+	reqctx.Logger(c.Request().Context()).Error(fallback, "error", err)
+
+	switch {
+	case domainErrors.IsConflictError(err):
+		return c.JSON(http.StatusConflict, ErrorResponse{
+			Error: "conflicts with existing data",
+		})
+	case domainErrors.IsConstraintError(err):
+		return c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+			Error: "violates data constraints",
+		})
+	case domainErrors.IsTransientError(err):
+		c.Response().Header().Set("Retry-After", "1")
+		return c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+			Error: "service temporarily unavailable",
+		})
+	}
+
+	return c.JSON(http.StatusInternalServerError, ErrorResponse{
+		Error: fallback,
+	})
+}
@@ -0,0 +1,84 @@
+package retention
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/interfaces/controller/response"
+	"Aicon-assignment/internal/usecase"
+)
+
+type RetentionHandler struct {
+	retentionUsecase usecase.RetentionUsecase
+}
+
+func NewRetentionHandler(retentionUsecase usecase.RetentionUsecase) *RetentionHandler {
+	return &RetentionHandler{
+		retentionUsecase: retentionUsecase,
+	}
+}
+
+func (h *RetentionHandler) ListPolicies(c echo.Context) error {
+	policies, err := h.retentionUsecase.ListPolicies(c.Request().Context())
+	if err != nil {
+		return response.RepositoryError(c, err, "failed to retrieve retention policies")
+	}
+
+	return c.JSON(http.StatusOK, policies)
+}
+
+func (h *RetentionHandler) UpdatePolicy(c echo.Context) error {
+	var input usecase.UpdateRetentionPolicyInput
+	if err := c.Bind(&input); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	policy, err := h.retentionUsecase.UpdatePolicy(c.Request().Context(), c.Param("type"), input)
+	if err != nil {
+		return h.errorResponse(c, err, "failed to update retention policy")
+	}
+
+	return c.JSON(http.StatusOK, policy)
+}
+
+// 現在の設定で削除される件数を返す（dry-run）
+func (h *RetentionHandler) PreviewPolicy(c echo.Context) error {
+	result, err := h.retentionUsecase.Preview(c.Request().Context(), c.Param("type"))
+	if err != nil {
+		return h.errorResponse(c, err, "failed to preview retention policy")
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// すべての保持期間をすぐに適用する。?dry_run=true の場合は件数だけを返す
+func (h *RetentionHandler) Run(c echo.Context) error {
+	dryRun := false
+	if raw := c.QueryParam("dry_run"); raw != "" {
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			return response.Error(c, http.StatusBadRequest, "invalid dry_run")
+		}
+		dryRun = parsed
+	}
+
+	results, err := h.retentionUsecase.Run(c.Request().Context(), dryRun)
+	if err != nil {
+		return response.RepositoryError(c, err, "failed to apply retention policies")
+	}
+
+	return c.JSON(http.StatusOK, results)
+}
+
+func (h *RetentionHandler) errorResponse(c echo.Context, err error, fallback string) error {
+	switch {
+	case domainErrors.IsNotFoundError(err):
+		return response.Error(c, http.StatusNotFound, "retention policy not found")
+	case domainErrors.IsValidationError(err):
+		return response.ValidationError(c, err)
+	}
+	return response.RepositoryError(c, err, fallback)
+}
@@ -0,0 +1,219 @@
+package scim
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"Aicon-assignment/internal/pkg/filter"
+)
+
+// SCIM の属性名（小文字）と entity.UserFilterFields のフィールドの対応
+var userFilterAttributes = map[string]string{
+	"username":     "user_name",
+	"displayname":  "display_name",
+	"externalid":   "external_id",
+	"emails":       "email",
+	"emails.value": "email",
+	"active":       "active",
+}
+
+// SCIM の filter（RFC 7644 3.4.2.2）を条件式の構文木に変換する
+// 対応しているのは eq / ne と and / or / not、括弧のみ
+//
+//	userName eq "yamada" and active eq true
+func parseFilter(input string) (filter.Expr, error) {
+	if len(input) > filter.MaxLength {
+		return nil, fmt.Errorf("filter must be at most %d characters", filter.MaxLength)
+	}
+
+	tokens, err := tokenizeFilter(input)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &filterParser{tokens: tokens}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q", p.tokens[p.pos].text)
+	}
+	return expr, nil
+}
+
+type filterToken struct {
+	text   string
+	quoted bool
+}
+
+func tokenizeFilter(input string) ([]filterToken, error) {
+	var tokens []filterToken
+	runes := []rune(input)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(' || r == ')':
+			tokens = append(tokens, filterToken{text: string(r)})
+			i++
+		case r == '"':
+			var b strings.Builder
+			i++
+			for ; i < len(runes) && runes[i] != '"'; i++ {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+				}
+				b.WriteRune(runes[i])
+			}
+			if i >= len(runes) {
+				return nil, fmt.Errorf("unterminated string")
+			}
+			i++
+			tokens = append(tokens, filterToken{text: b.String(), quoted: true})
+		default:
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && runes[i] != '(' && runes[i] != ')' && runes[i] != '"' {
+				i++
+			}
+			tokens = append(tokens, filterToken{text: string(runes[start:i])})
+		}
+	}
+	return tokens, nil
+}
+
+type filterParser struct {
+	tokens      []filterToken
+	pos         int
+	comparisons int
+}
+
+func (p *filterParser) next() (filterToken, bool) {
+	if p.pos >= len(p.tokens) {
+		return filterToken{}, false
+	}
+	tok := p.tokens[p.pos]
+	p.pos++
+	return tok, true
+}
+
+// キーワードは大文字小文字を区別しない
+func (p *filterParser) accept(keyword string) bool {
+	if p.pos < len(p.tokens) && !p.tokens[p.pos].quoted && strings.EqualFold(p.tokens[p.pos].text, keyword) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *filterParser) parseOr() (filter.Expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("or") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &filter.Logical{Op: filter.OpOr, Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseAnd() (filter.Expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("and") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &filter.Logical{Op: filter.OpAnd, Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseUnary() (filter.Expr, error) {
+	negate := p.accept("not")
+	if negate || p.accept("(") {
+		if negate && !p.accept("(") {
+			return nil, fmt.Errorf("expected '(' after not")
+		}
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.accept(")") {
+			return nil, fmt.Errorf("expected ')'")
+		}
+		if negate {
+			return &filter.Not{Expr: expr}, nil
+		}
+		return expr, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *filterParser) parseComparison() (filter.Expr, error) {
+	attrTok, ok := p.next()
+	if !ok || attrTok.quoted {
+		return nil, fmt.Errorf("expected attribute name")
+	}
+	field, ok := userFilterAttributes[strings.ToLower(attrTok.text)]
+	if !ok {
+		return nil, fmt.Errorf("unsupported attribute: %s", attrTok.text)
+	}
+
+	opTok, ok := p.next()
+	if !ok {
+		return nil, fmt.Errorf("expected operator after %s", attrTok.text)
+	}
+	var op string
+	switch strings.ToLower(opTok.text) {
+	case "eq":
+		op = filter.OpEq
+	case "ne":
+		op = filter.OpNe
+	default:
+		return nil, fmt.Errorf("unsupported operator: %s", opTok.text)
+	}
+
+	valueTok, ok := p.next()
+	if !ok {
+		return nil, fmt.Errorf("expected value after %s %s", attrTok.text, opTok.text)
+	}
+	value, err := filterValue(attrTok.text, field, valueTok)
+	if err != nil {
+		return nil, err
+	}
+
+	p.comparisons++
+	if p.comparisons > filter.MaxComparisons {
+		return nil, fmt.Errorf("filter must have at most %d conditions", filter.MaxComparisons)
+	}
+
+	return &filter.Comparison{Field: field, Op: op, Value: value}, nil
+}
+
+// active は true / false を 1 / 0 に、それ以外は引用符付きの文字列として受け取る
+func filterValue(attr, field string, tok filterToken) (any, error) {
+	if field == "active" {
+		switch {
+		case !tok.quoted && strings.EqualFold(tok.text, "true"):
+			return int64(1), nil
+		case !tok.quoted && strings.EqualFold(tok.text, "false"):
+			return int64(0), nil
+		}
+		return nil, fmt.Errorf("%s must be compared with true or false", attr)
+	}
+	if !tok.quoted {
+		return nil, fmt.Errorf("%s must be compared with a quoted string", attr)
+	}
+	return tok.text, nil
+}
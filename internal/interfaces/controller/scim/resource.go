@@ -0,0 +1,229 @@
+package scim
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"Aicon-assignment/internal/domain/entity"
+	"Aicon-assignment/internal/usecase"
+)
+
+const (
+	SchemaUser         = "urn:ietf:params:scim:schemas:core:2.0:User"
+	SchemaListResponse = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	SchemaPatchOp      = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	SchemaError        = "urn:ietf:params:scim:api:messages:2.0:Error"
+
+	MIMEApplicationSCIM = "application/scim+json"
+)
+
+// SCIM の User リソース（RFC 7643 4.1）のうち、内部のユーザーに対応する属性
+type userResource struct {
+	Schemas     []string `json:"schemas"`
+	ID          string   `json:"id,omitempty"`
+	ExternalID  string   `json:"externalId,omitempty"`
+	UserName    string   `json:"userName"`
+	DisplayName string   `json:"displayName,omitempty"`
+	Emails      []email  `json:"emails,omitempty"`
+	Active      *bool    `json:"active,omitempty"`
+	Password    string   `json:"password,omitempty"` // 書き込みのみ（RFC 7643 4.1.1）。レスポンスには含めない
+	Meta        *meta    `json:"meta,omitempty"`
+}
+
+type email struct {
+	Value   string `json:"value"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+type meta struct {
+	ResourceType string    `json:"resourceType"`
+	Created      time.Time `json:"created"`
+	LastModified time.Time `json:"lastModified"`
+	Location     string    `json:"location"`
+}
+
+type listResponse struct {
+	Schemas      []string        `json:"schemas"`
+	TotalResults int             `json:"totalResults"`
+	StartIndex   int             `json:"startIndex"`
+	ItemsPerPage int             `json:"itemsPerPage"`
+	Resources    []*userResource `json:"Resources"`
+}
+
+type patchRequest struct {
+	Schemas    []string         `json:"schemas"`
+	Operations []patchOperation `json:"Operations"`
+}
+
+type patchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value"`
+}
+
+type errorResponse struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	SCIMType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail,omitempty"`
+}
+
+func toResource(user *entity.User, basePath string) *userResource {
+	active := user.Active
+	resource := &userResource{
+		Schemas:     []string{SchemaUser},
+		ID:          strconv.FormatInt(user.ID, 10),
+		ExternalID:  user.ExternalID,
+		UserName:    user.UserName,
+		DisplayName: user.DisplayName,
+		Active:      &active,
+		Meta: &meta{
+			ResourceType: "User",
+			Created:      user.CreatedAt,
+			LastModified: user.UpdatedAt,
+			Location:     basePath + "/Users/" + strconv.FormatInt(user.ID, 10),
+		},
+	}
+	if user.Email != "" {
+		resource.Emails = []email{{Value: user.Email, Type: "work", Primary: true}}
+	}
+	return resource
+}
+
+func (r *userResource) createInput() usecase.CreateUserInput {
+	return usecase.CreateUserInput{
+		UserName:    r.UserName,
+		DisplayName: r.DisplayName,
+		Email:       primaryEmail(r.Emails),
+		ExternalID:  r.ExternalID,
+		Active:      r.Active,
+		Password:    r.Password,
+	}
+}
+
+// primary の指定があるものを優先し、なければ先頭のアドレスを使う
+func primaryEmail(emails []email) string {
+	for _, e := range emails {
+		if e.Primary {
+			return e.Value
+		}
+	}
+	if len(emails) > 0 {
+		return emails[0].Value
+	}
+	return ""
+}
+
+// PatchOp を部分更新の入力に変換する
+// path を省略した場合は value のオブジェクトの各属性を置き換える
+func (r *patchRequest) updateInput() (usecase.UpdateUserInput, error) {
+	var input usecase.UpdateUserInput
+	if len(r.Operations) == 0 {
+		return input, fmt.Errorf("Operations is required")
+	}
+
+	for _, op := range r.Operations {
+		kind := strings.ToLower(op.Op)
+		if kind != "add" && kind != "replace" && kind != "remove" {
+			return input, fmt.Errorf("unsupported op: %s", op.Op)
+		}
+
+		if op.Path == "" {
+			if kind == "remove" {
+				return input, fmt.Errorf("remove requires a path")
+			}
+			var values map[string]json.RawMessage
+			if err := json.Unmarshal(op.Value, &values); err != nil {
+				return input, fmt.Errorf("value must be an object when path is omitted")
+			}
+			for attr, value := range values {
+				if err := applyPatch(&input, kind, attr, value); err != nil {
+					return input, err
+				}
+			}
+			continue
+		}
+
+		if err := applyPatch(&input, kind, op.Path, op.Value); err != nil {
+			return input, err
+		}
+	}
+	return input, nil
+}
+
+func applyPatch(input *usecase.UpdateUserInput, kind, path string, value json.RawMessage) error {
+	attr := strings.ToLower(path)
+	if strings.HasPrefix(attr, "emails[") && strings.HasSuffix(attr, "].value") {
+		attr = "emails.value"
+	}
+
+	if kind == "remove" {
+		empty := ""
+		switch attr {
+		case "displayname":
+			input.DisplayName = &empty
+		case "externalid":
+			input.ExternalID = &empty
+		case "emails", "emails.value":
+			input.Email = &empty
+		case "password":
+			input.Password = &empty
+		default:
+			return fmt.Errorf("cannot remove %s", path)
+		}
+		return nil
+	}
+
+	switch attr {
+	case "active":
+		active, err := patchBool(value)
+		if err != nil {
+			return fmt.Errorf("active must be a boolean")
+		}
+		input.Active = &active
+	case "username", "displayname", "externalid", "emails.value", "password":
+		var s string
+		if err := json.Unmarshal(value, &s); err != nil {
+			return fmt.Errorf("%s must be a string", path)
+		}
+		switch attr {
+		case "username":
+			input.UserName = &s
+		case "displayname":
+			input.DisplayName = &s
+		case "externalid":
+			input.ExternalID = &s
+		case "password":
+			input.Password = &s
+		default:
+			input.Email = &s
+		}
+	case "emails":
+		var emails []email
+		if err := json.Unmarshal(value, &emails); err != nil {
+			return fmt.Errorf("emails must be an array")
+		}
+		address := primaryEmail(emails)
+		input.Email = &address
+	default:
+		return fmt.Errorf("unsupported path: %s", path)
+	}
+	return nil
+}
+
+// 一部の IdP は active を "False" のような文字列で送るため、文字列も受け付ける
+func patchBool(value json.RawMessage) (bool, error) {
+	var b bool
+	if err := json.Unmarshal(value, &b); err == nil {
+		return b, nil
+	}
+	var s string
+	if err := json.Unmarshal(value, &s); err != nil {
+		return false, err
+	}
+	return strconv.ParseBool(strings.ToLower(s))
+}
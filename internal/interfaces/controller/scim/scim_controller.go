@@ -0,0 +1,193 @@
+// Package scim は IdP からスタッフのアカウントを作成・無効化するための
+// SCIM v2（RFC 7643 / 7644）の Users エンドポイントを提供する。
+package scim
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/pkg/reqctx"
+	"Aicon-assignment/internal/usecase"
+)
+
+// 一覧で1回に返す件数
+const (
+	DefaultCount = 100
+	MaxCount     = 200
+)
+
+type SCIMHandler struct {
+	userUsecase usecase.UserUsecase
+	basePath    string
+}
+
+// basePath は meta.location に使うパス（例: /scim/v2）
+func NewSCIMHandler(userUsecase usecase.UserUsecase, basePath string) *SCIMHandler {
+	return &SCIMHandler{
+		userUsecase: userUsecase,
+		basePath:    basePath,
+	}
+}
+
+// プロビジョニング用トークン（Authorization: Bearer ...）を検証する
+// トークンが設定されていない場合はすべてのリクエストを拒否する
+func RequireToken(token string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if token == "" {
+				return scimError(c, http.StatusUnauthorized, "", "SCIM provisioning is not configured")
+			}
+			given, ok := strings.CutPrefix(c.Request().Header.Get(echo.HeaderAuthorization), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+				return scimError(c, http.StatusUnauthorized, "", "invalid provisioning token")
+			}
+			return next(c)
+		}
+	}
+}
+
+func (h *SCIMHandler) CreateUser(c echo.Context) error {
+	var resource userResource
+	if err := json.NewDecoder(c.Request().Body).Decode(&resource); err != nil {
+		return scimError(c, http.StatusBadRequest, "invalidSyntax", "invalid request format")
+	}
+
+	user, err := h.userUsecase.CreateUser(c.Request().Context(), resource.createInput())
+	if err != nil {
+		return h.errorResponse(c, err, "failed to create user")
+	}
+
+	c.Response().Header().Set(echo.HeaderLocation, h.basePath+"/Users/"+strconv.FormatInt(user.ID, 10))
+	return respond(c, http.StatusCreated, toResource(user, h.basePath))
+}
+
+func (h *SCIMHandler) GetUser(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return scimError(c, http.StatusNotFound, "", "user not found")
+	}
+
+	user, err := h.userUsecase.GetUser(c.Request().Context(), id)
+	if err != nil {
+		return h.errorResponse(c, err, "failed to retrieve user")
+	}
+
+	return respond(c, http.StatusOK, toResource(user, h.basePath))
+}
+
+// ?filter=、?startIndex=（1始まり）、?count= に対応する
+func (h *SCIMHandler) ListUsers(c echo.Context) error {
+	var query entity.UserQuery
+	if raw := c.QueryParam("filter"); raw != "" {
+		expr, err := parseFilter(raw)
+		if err != nil {
+			return scimError(c, http.StatusBadRequest, "invalidFilter", err.Error())
+		}
+		query.Filter = expr
+	}
+
+	startIndex, ok := queryInt(c, "startIndex", 1)
+	if !ok {
+		return scimError(c, http.StatusBadRequest, "invalidValue", "invalid startIndex")
+	}
+	count, ok := queryInt(c, "count", DefaultCount)
+	if !ok {
+		return scimError(c, http.StatusBadRequest, "invalidValue", "invalid count")
+	}
+	// RFC 7644 3.4.2.4: 1 未満の startIndex は 1、負の count は 0 として扱う
+	startIndex = max(startIndex, 1)
+	count = min(max(count, 0), MaxCount)
+
+	query.Offset = startIndex - 1
+	// count=0 の場合は件数だけを返す
+	query.Limit = max(count, 1)
+
+	users, total, err := h.userUsecase.ListUsers(c.Request().Context(), query)
+	if err != nil {
+		return h.errorResponse(c, err, "failed to retrieve users")
+	}
+	if count == 0 {
+		users = users[:0]
+	}
+
+	resources := make([]*userResource, 0, len(users))
+	for _, user := range users {
+		resources = append(resources, toResource(user, h.basePath))
+	}
+
+	return respond(c, http.StatusOK, listResponse{
+		Schemas:      []string{SchemaListResponse},
+		TotalResults: total,
+		StartIndex:   startIndex,
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	})
+}
+
+// PatchOp で属性を変更する。active=false でアカウントを無効化する
+func (h *SCIMHandler) PatchUser(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return scimError(c, http.StatusNotFound, "", "user not found")
+	}
+
+	var patch patchRequest
+	if err := json.NewDecoder(c.Request().Body).Decode(&patch); err != nil {
+		return scimError(c, http.StatusBadRequest, "invalidSyntax", "invalid request format")
+	}
+	input, err := patch.updateInput()
+	if err != nil {
+		return scimError(c, http.StatusBadRequest, "invalidPath", err.Error())
+	}
+
+	user, err := h.userUsecase.UpdateUser(c.Request().Context(), id, input)
+	if err != nil {
+		return h.errorResponse(c, err, "failed to update user")
+	}
+
+	return respond(c, http.StatusOK, toResource(user, h.basePath))
+}
+
+func (h *SCIMHandler) errorResponse(c echo.Context, err error, fallback string) error {
+	switch {
+	case domainErrors.IsNotFoundError(err):
+		return scimError(c, http.StatusNotFound, "", "user not found")
+	case domainErrors.IsValidationError(err):
+		return scimError(c, http.StatusBadRequest, "invalidValue", err.Error())
+	case domainErrors.IsConflictError(err):
+		return scimError(c, http.StatusConflict, "uniqueness", "userName is already in use")
+	}
+
+	reqctx.Logger(c.Request().Context()).Error(fallback, "error", err)
+	return scimError(c, http.StatusInternalServerError, "", fallback)
+}
+
+func respond(c echo.Context, status int, body any) error {
+	c.Response().Header().Set(echo.HeaderContentType, MIMEApplicationSCIM)
+	return c.JSON(status, body)
+}
+
+func scimError(c echo.Context, status int, scimType, detail string) error {
+	return respond(c, status, errorResponse{
+		Schemas:  []string{SchemaError},
+		Status:   strconv.Itoa(status),
+		SCIMType: scimType,
+		Detail:   detail,
+	})
+}
+
+func queryInt(c echo.Context, name string, defaultValue int) (int, bool) {
+	raw := c.QueryParam(name)
+	if raw == "" {
+		return defaultValue, true
+	}
+	n, err := strconv.Atoi(raw)
+	return n, err == nil
+}
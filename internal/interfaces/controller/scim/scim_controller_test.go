@@ -0,0 +1,179 @@
+package scim
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"Aicon-assignment/internal/interfaces/database"
+	"Aicon-assignment/internal/pkg/clock"
+	"Aicon-assignment/internal/pkg/filter"
+	"Aicon-assignment/internal/usecase"
+)
+
+func TestParseFilter(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    filter.Expr
+		wantErr bool
+	}{
+		{
+			name:  "正常系: 属性名と演算子は大文字小文字を区別しない",
+			input: `USERNAME Eq "yamada"`,
+			want:  &filter.Comparison{Field: "user_name", Op: filter.OpEq, Value: "yamada"},
+		},
+		{
+			name:  "正常系: and と active",
+			input: `emails.value eq "a@example.com" and active eq false`,
+			want: &filter.Logical{
+				Op:    filter.OpAnd,
+				Left:  &filter.Comparison{Field: "email", Op: filter.OpEq, Value: "a@example.com"},
+				Right: &filter.Comparison{Field: "active", Op: filter.OpEq, Value: int64(0)},
+			},
+		},
+		{
+			name:  "正常系: not と括弧",
+			input: `not (externalId ne "x y")`,
+			want:  &filter.Not{Expr: &filter.Comparison{Field: "external_id", Op: filter.OpNe, Value: "x y"}},
+		},
+		{name: "異常系: 対応していない演算子", input: `userName sw "ya"`, wantErr: true},
+		{name: "異常系: 対応していない属性", input: `title eq "x"`, wantErr: true},
+		{name: "異常系: 文字列を引用符で囲んでいない", input: `userName eq yamada`, wantErr: true},
+		{name: "異常系: active に文字列", input: `active eq "true"`, wantErr: true},
+		{name: "異常系: 括弧が閉じていない", input: `(userName eq "x"`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseFilter(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPatchRequest_UpdateInput(t *testing.T) {
+	parse := func(body string) (usecase.UpdateUserInput, error) {
+		var patch patchRequest
+		require.NoError(t, json.Unmarshal([]byte(body), &patch))
+		return patch.updateInput()
+	}
+
+	t.Run("正常系: path 指定で active を無効にする", func(t *testing.T) {
+		input, err := parse(`{"Operations":[{"op":"replace","path":"active","value":false}]}`)
+		require.NoError(t, err)
+		require.NotNil(t, input.Active)
+		assert.False(t, *input.Active)
+	})
+
+	t.Run("正常系: path 省略・文字列の真偽値", func(t *testing.T) {
+		input, err := parse(`{"Operations":[{"op":"Replace","value":{"active":"False","displayName":"山田"}}]}`)
+		require.NoError(t, err)
+		require.NotNil(t, input.Active)
+		assert.False(t, *input.Active)
+		assert.Equal(t, "山田", *input.DisplayName)
+	})
+
+	t.Run("正常系: メールアドレスのフィルタ付き path", func(t *testing.T) {
+		input, err := parse(`{"Operations":[{"op":"replace","path":"emails[type eq \"work\"].value","value":"b@example.com"}]}`)
+		require.NoError(t, err)
+		assert.Equal(t, "b@example.com", *input.Email)
+	})
+
+	t.Run("異常系: 対応していない path", func(t *testing.T) {
+		_, err := parse(`{"Operations":[{"op":"replace","path":"title","value":"x"}]}`)
+		assert.Error(t, err)
+	})
+
+	t.Run("異常系: userName は削除できない", func(t *testing.T) {
+		_, err := parse(`{"Operations":[{"op":"remove","path":"userName"}]}`)
+		assert.Error(t, err)
+	})
+}
+
+func TestSCIMHandler(t *testing.T) {
+	e := echo.New()
+	users := usecase.NewUserUsecase(database.NewMemoryUserRepository(), clock.NewFrozen(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)))
+	handler := NewSCIMHandler(users, "/scim/v2")
+	group := e.Group("/scim/v2", RequireToken("secret"))
+	group.GET("/Users", handler.ListUsers)
+	group.POST("/Users", handler.CreateUser)
+	group.PATCH("/Users/:id", handler.PatchUser)
+
+	do := func(method, target, token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, MIMEApplicationSCIM)
+		if token != "" {
+			req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("異常系: トークンが違う", func(t *testing.T) {
+		rec := do(http.MethodGet, "/scim/v2/Users", "wrong", "")
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.Contains(t, rec.Body.String(), SchemaError)
+	})
+
+	t.Run("正常系: 作成・重複・無効化・絞り込み", func(t *testing.T) {
+		rec := do(http.MethodPost, "/scim/v2/Users", "secret",
+			`{"schemas":["`+SchemaUser+`"],"userName":"yamada","externalId":"idp-1","emails":[{"value":"yamada@example.com","primary":true}]}`)
+		require.Equal(t, http.StatusCreated, rec.Code)
+		assert.Equal(t, MIMEApplicationSCIM, rec.Header().Get(echo.HeaderContentType))
+		assert.Equal(t, "/scim/v2/Users/1", rec.Header().Get(echo.HeaderLocation))
+
+		rec = do(http.MethodPost, "/scim/v2/Users", "secret", `{"userName":"YAMADA"}`)
+		assert.Equal(t, http.StatusConflict, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"scimType":"uniqueness"`)
+
+		rec = do(http.MethodPost, "/scim/v2/Users", "secret", `{"userName":"suzuki"}`)
+		require.Equal(t, http.StatusCreated, rec.Code)
+
+		rec = do(http.MethodPatch, "/scim/v2/Users/1", "secret",
+			`{"schemas":["`+SchemaPatchOp+`"],"Operations":[{"op":"replace","path":"active","value":false}]}`)
+		require.Equal(t, http.StatusOK, rec.Code)
+		var resource userResource
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resource))
+		assert.False(t, *resource.Active)
+
+		rec = do(http.MethodGet, `/scim/v2/Users?filter=active+eq+true`, "secret", "")
+		require.Equal(t, http.StatusOK, rec.Code)
+		var list listResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+		assert.Equal(t, 1, list.TotalResults)
+		require.Len(t, list.Resources, 1)
+		assert.Equal(t, "suzuki", list.Resources[0].UserName)
+
+		rec = do(http.MethodGet, `/scim/v2/Users?count=1&startIndex=2`, "secret", "")
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+		assert.Equal(t, 2, list.TotalResults)
+		assert.Equal(t, 2, list.StartIndex)
+		require.Len(t, list.Resources, 1)
+		assert.Equal(t, "2", list.Resources[0].ID)
+	})
+
+	t.Run("異常系: 不正な filter", func(t *testing.T) {
+		rec := do(http.MethodGet, `/scim/v2/Users?filter=userName+co+%22a%22`, "secret", "")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"scimType":"invalidFilter"`)
+	})
+
+	t.Run("異常系: 存在しないユーザー", func(t *testing.T) {
+		rec := do(http.MethodPatch, "/scim/v2/Users/99", "secret", `{"Operations":[{"op":"replace","path":"active","value":false}]}`)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
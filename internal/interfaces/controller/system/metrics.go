@@ -0,0 +1,101 @@
+package system
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"Aicon-assignment/internal/domain/entity"
+)
+
+// Prometheus のテキスト形式
+const metricsContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// SLO の状況（スタンバイでは複製の状況も）を Prometheus のテキスト形式で返す
+func (handler *SystemHandler) Metrics(c echo.Context) error {
+	var b strings.Builder
+	writeSLOMetrics(&b, handler.slo.Statuses())
+	if handler.replica != nil {
+		writeReplicationMetrics(&b, handler.replica.Status())
+	}
+	return c.Blob(http.StatusOK, metricsContentType, []byte(b.String()))
+}
+
+// SLO の状況を JSON で返す
+func (handler *SystemHandler) GetSLOs(c echo.Context) error {
+	statuses := handler.slo.Statuses()
+	if statuses == nil {
+		statuses = []entity.SLOStatus{}
+	}
+	return c.JSON(http.StatusOK, statuses)
+}
+
+func writeSLOMetrics(b *strings.Builder, statuses []entity.SLOStatus) {
+	metric := func(name, kind, help string, value func(s entity.SLOStatus) []sample) {
+		fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+		for _, status := range statuses {
+			for _, s := range value(status) {
+				fmt.Fprintf(b, "%s{%s} %g\n", name, labels(status, s.extra...), s.value)
+			}
+		}
+	}
+
+	metric("slo_requests_total", "counter", "Requests counted towards the SLO since startup.", func(s entity.SLOStatus) []sample {
+		return []sample{{value: float64(s.Requests)}}
+	})
+	metric("slo_bad_requests_total", "counter", "Requests that missed the SLO since startup.", func(s entity.SLOStatus) []sample {
+		return []sample{{value: float64(s.Bad)}}
+	})
+	metric("slo_objective", "gauge", "Target ratio of good requests.", func(s entity.SLOStatus) []sample {
+		return []sample{{value: s.Target}}
+	})
+	metric("slo_burn_rate", "gauge", "Error budget burn rate over the window (1 spends the budget exactly).", func(s entity.SLOStatus) []sample {
+		windows := make([]string, 0, len(s.BurnRates))
+		for window := range s.BurnRates {
+			windows = append(windows, window)
+		}
+		sort.Strings(windows)
+
+		samples := make([]sample, len(windows))
+		for i, window := range windows {
+			samples[i] = sample{value: s.BurnRates[window], extra: []string{"window", window}}
+		}
+		return samples
+	})
+}
+
+func writeReplicationMetrics(b *strings.Builder, status entity.ReplicationStatus) {
+	metric := func(name, help string, value float64) {
+		fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s gauge\n", name, help, name)
+		fmt.Fprintf(b, "%s{source=\"%s\"} %g\n", name, labelEscaper.Replace(status.Source), value)
+	}
+
+	metric("replication_applied_sequence", "Last source event sequence applied by this standby.", float64(status.AppliedSequence))
+	metric("replication_source_sequence", "Last event sequence the source reported.", float64(status.SourceSequence))
+	metric("replication_lag_events", "Source events not yet applied by this standby.", float64(status.LagEvents))
+	metric("replication_lag_seconds", "Seconds since this standby was last caught up with the source.", status.LagSeconds)
+	up := 1.0
+	if status.LastError != "" {
+		up = 0
+	}
+	metric("replication_up", "Whether the last poll of the source succeeded.", up)
+}
+
+type sample struct {
+	value float64
+	extra []string // ラベル名と値の組
+}
+
+func labels(s entity.SLOStatus, extra ...string) string {
+	pairs := append([]string{"method", s.Method, "route", s.Route, "sli", s.SLI}, extra...)
+	parts := make([]string, 0, len(pairs)/2)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		parts = append(parts, fmt.Sprintf(`%s="%s"`, pairs[i], labelEscaper.Replace(pairs[i+1])))
+	}
+	return strings.Join(parts, ",")
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
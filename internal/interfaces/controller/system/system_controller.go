@@ -1,17 +1,189 @@
 package system
 
 import (
+	"context"
+	"errors"
 	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/labstack/echo/v4"
+
+	"Aicon-assignment/internal/domain/entity"
+	"Aicon-assignment/internal/interfaces/controller/response"
+	"Aicon-assignment/internal/interfaces/middleware"
+	"Aicon-assignment/internal/pkg/reqctx"
+	"Aicon-assignment/internal/usecase"
 )
 
-type SystemHandler struct{}
+// 設定を読み込み直し、適用後の設定を返す
+// 不正な値がある場合は設定を変更せずにエラーを返す
+type ConfigReloader func() (any, error)
+
+type SystemHandler struct {
+	reloadConfig ConfigReloader
+	readOnly     *middleware.ReadOnlyMode
+	slo          usecase.SLOUsecase
+	replica      usecase.ReplicaUsecase // スタンバイでない場合は nil
+	limits       entity.ServerLimits
+	capabilities entity.Capabilities
+	readiness    []ReadinessCheck
+	draining     atomic.Bool // 停止を始めた後は true
+}
+
+// /readyz で確認する依存先（DB・キャッシュ・検索など）
+type ReadinessCheck struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+// 依存先 1 つの確認にかける時間の上限
+const readinessTimeout = 2 * time.Second
+
+type ReadinessResponse struct {
+	Status string                     `json:"status"` // "ok"・"unavailable" または "shutting_down"
+	Checks map[string]DependencyState `json:"checks"`
+}
+
+type DependencyState struct {
+	Status    string `json:"status"` // "ok" または "error"
+	LatencyMS int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
 
 func (handler *SystemHandler) Health(ctx echo.Context) {
 	ctx.NoContent(http.StatusOK)
 }
 
-func NewSystemHandler() *SystemHandler {
-	return &SystemHandler{}
+// プロセスが応答できるか（Kubernetes の livenessProbe 用）。依存先は確認しない
+func (handler *SystemHandler) Liveness(c echo.Context) error {
+	return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// 依存先にすべて接続できるか（Kubernetes の readinessProbe 用）。1 つでも失敗すれば 503 を返す
+// 依存先は並行して確認し、それぞれの結果と所要時間を返す
+// 停止を始めた後は依存先を確認せずに 503 を返し、新しいリクエストが振り分けられないようにする
+func (handler *SystemHandler) Readiness(c echo.Context) error {
+	if handler.draining.Load() {
+		return c.JSON(http.StatusServiceUnavailable, ReadinessResponse{Status: "shutting_down", Checks: map[string]DependencyState{}})
+	}
+
+	result := ReadinessResponse{Status: "ok", Checks: make(map[string]DependencyState, len(handler.readiness))}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, check := range handler.readiness {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(c.Request().Context(), readinessTimeout)
+			defer cancel()
+
+			started := time.Now()
+			err := check.Check(ctx)
+			state := DependencyState{Status: "ok", LatencyMS: time.Since(started).Milliseconds()}
+			if err != nil {
+				state.Status = "error"
+				state.Error = err.Error()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			result.Checks[check.Name] = state
+			if err != nil {
+				result.Status = "unavailable"
+			}
+		}()
+	}
+	wg.Wait()
+
+	if result.Status != "ok" {
+		reqctx.Logger(c.Request().Context()).Warn("readiness check failed", "checks", result.Checks)
+		return c.JSON(http.StatusServiceUnavailable, result)
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+// 再起動せずに設定を読み込み直す（SIGHUP と同じ）
+func (handler *SystemHandler) ReloadConfig(c echo.Context) error {
+	applied, err := handler.reloadConfig()
+	if err != nil {
+		reqctx.Logger(c.Request().Context()).Warn("config reload rejected", "error", err)
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid configuration, current settings kept",
+			Details: strings.Split(err.Error(), "\n"),
+		})
+	}
+
+	reqctx.Logger(c.Request().Context()).Info("config reloaded", "config", applied)
+	return c.JSON(http.StatusOK, applied)
+}
+
+// サーバーが適用している上限
+func (handler *SystemHandler) GetLimits(c echo.Context) error {
+	return c.JSON(http.StatusOK, handler.limits)
+}
+
+// このデプロイで使える任意の機能
+func (handler *SystemHandler) GetCapabilities(c echo.Context) error {
+	capabilities := handler.capabilities
+	capabilities.ReadOnly = handler.readOnly.Status().Enabled
+	return c.JSON(http.StatusOK, capabilities)
+}
+
+// 読み取り専用モードの状態
+func (handler *SystemHandler) GetReadOnly(c echo.Context) error {
+	return c.JSON(http.StatusOK, handler.readOnly.Status())
+}
+
+type SetReadOnlyRequest struct {
+	Enabled *bool  `json:"enabled"`
+	Reason  string `json:"reason"`
+}
+
+// 読み取り専用モードを切り替える。有効な間も、このエンドポイントは受け付ける
+func (handler *SystemHandler) SetReadOnly(c echo.Context) error {
+	var req SetReadOnlyRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid request format")
+	}
+	if req.Enabled == nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "validation failed",
+			Details: []string{"enabled is required"},
+		})
+	}
+
+	status, err := handler.readOnly.Set(*req.Enabled, req.Reason)
+	if err != nil {
+		if errors.Is(err, middleware.ErrReadOnlyForced) {
+			return c.JSON(http.StatusConflict, response.ErrorResponse{
+				Error:   err.Error(),
+				Details: []string{status.Reason},
+			})
+		}
+		return response.Error(c, http.StatusInternalServerError, "failed to change read-only mode")
+	}
+
+	reqctx.Logger(c.Request().Context()).Warn("read-only mode changed", "enabled", status.Enabled, "reason", status.Reason)
+	return c.JSON(http.StatusOK, status)
+}
+
+// 停止を始めたことを /readyz に反映する
+func (handler *SystemHandler) StartDraining() {
+	handler.draining.Store(true)
+}
+
+func NewSystemHandler(reloadConfig ConfigReloader, readOnly *middleware.ReadOnlyMode, slo usecase.SLOUsecase, replica usecase.ReplicaUsecase, limits entity.ServerLimits, capabilities entity.Capabilities, readiness []ReadinessCheck) *SystemHandler {
+	return &SystemHandler{
+		reloadConfig: reloadConfig,
+		readOnly:     readOnly,
+		slo:          slo,
+		replica:      replica,
+		limits:       limits,
+		capabilities: capabilities,
+		readiness:    readiness,
+	}
 }
@@ -0,0 +1,72 @@
+package tenants
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/interfaces/controller/response"
+	"Aicon-assignment/internal/usecase"
+)
+
+type TenantHandler struct {
+	tenantUsecase usecase.TenantUsecase
+}
+
+func NewTenantHandler(tenantUsecase usecase.TenantUsecase) *TenantHandler {
+	return &TenantHandler{
+		tenantUsecase: tenantUsecase,
+	}
+}
+
+// 組織のデータを JSON のファイルとして書き出す
+func (h *TenantHandler) Export(c echo.Context) error {
+	orgID, ok := response.ParseID(c, "id")
+	if !ok {
+		return response.Error(c, http.StatusBadRequest, "invalid organization ID")
+	}
+
+	bundle, err := h.tenantUsecase.Export(c.Request().Context(), orgID)
+	if err != nil {
+		return h.errorResponse(c, err, "failed to export organization")
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="organization-%d.json"`, orgID))
+	return c.JSON(http.StatusOK, bundle)
+}
+
+// 書き出したデータを新しい組織として取り込む
+// ?name= で組織名を、?users=match|fail で同じユーザー名のユーザーがいる場合の扱いを指定する
+func (h *TenantHandler) Import(c echo.Context) error {
+	var bundle entity.TenantBundle
+	if err := c.Bind(&bundle); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	result, err := h.tenantUsecase.Import(c.Request().Context(), &bundle, usecase.TenantImportInput{
+		Name:  c.QueryParam("name"),
+		Users: c.QueryParam("users"),
+	})
+	if err != nil {
+		return h.errorResponse(c, err, "failed to import organization")
+	}
+
+	return c.JSON(http.StatusCreated, result)
+}
+
+func (h *TenantHandler) errorResponse(c echo.Context, err error, fallback string) error {
+	switch {
+	case domainErrors.IsNotFoundError(err):
+		return response.Error(c, http.StatusNotFound, "organization not found")
+	case domainErrors.IsValidationError(err):
+		return response.ValidationError(c, err)
+	case domainErrors.IsForbiddenError(err):
+		return response.Error(c, http.StatusForbidden, err.Error())
+	case domainErrors.IsConflictError(err):
+		return response.Error(c, http.StatusConflict, err.Error())
+	}
+	return response.RepositoryError(c, err, fallback)
+}
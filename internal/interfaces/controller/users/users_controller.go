@@ -0,0 +1,51 @@
+package users
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/interfaces/controller/response"
+	"Aicon-assignment/internal/usecase"
+)
+
+type UserHandler struct {
+	userUsecase usecase.UserUsecase
+}
+
+func NewUserHandler(userUsecase usecase.UserUsecase) *UserHandler {
+	return &UserHandler{
+		userUsecase: userUsecase,
+	}
+}
+
+// ユーザーのロール（admin / member）を変更する
+func (h *UserHandler) ChangeRole(c echo.Context) error {
+	id, ok := response.ParseID(c, "id")
+	if !ok {
+		return response.Error(c, http.StatusBadRequest, "invalid user ID")
+	}
+
+	var input usecase.ChangeUserRoleInput
+	if err := c.Bind(&input); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	user, err := h.userUsecase.ChangeRole(c.Request().Context(), id, input)
+	if err != nil {
+		switch {
+		case domainErrors.IsNotFoundError(err):
+			return response.Error(c, http.StatusNotFound, "user not found")
+		case domainErrors.IsValidationError(err):
+			return response.ValidationError(c, err)
+		case domainErrors.IsForbiddenError(err):
+			return response.Error(c, http.StatusForbidden, err.Error())
+		case domainErrors.IsLastAdminError(err):
+			return response.Error(c, http.StatusConflict, err.Error())
+		}
+		return response.RepositoryError(c, err, "failed to change role")
+	}
+
+	return c.JSON(http.StatusOK, user)
+}
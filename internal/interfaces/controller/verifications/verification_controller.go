@@ -0,0 +1,104 @@
+package verifications
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/interfaces/controller/response"
+	"Aicon-assignment/internal/usecase"
+)
+
+type VerificationHandler struct {
+	verificationUsecase usecase.VerificationUsecase
+}
+
+func NewVerificationHandler(verificationUsecase usecase.VerificationUsecase) *VerificationHandler {
+	return &VerificationHandler{verificationUsecase: verificationUsecase}
+}
+
+type reopenRequest struct {
+	Reason string `json:"reason"`
+}
+
+// 検証を始めていないアイテムは unverified を返す
+func (h *VerificationHandler) Get(c echo.Context) error {
+	itemID, ok := response.ParseID(c, "id")
+	if !ok {
+		return response.Error(c, http.StatusBadRequest, "invalid item ID")
+	}
+
+	verification, err := h.verificationUsecase.Get(c.Request().Context(), itemID)
+	if err != nil {
+		return errorResponse(c, err, "failed to retrieve verification")
+	}
+	return c.JSON(http.StatusOK, verification)
+}
+
+func (h *VerificationHandler) Start(c echo.Context) error {
+	itemID, ok := response.ParseID(c, "id")
+	if !ok {
+		return response.Error(c, http.StatusBadRequest, "invalid item ID")
+	}
+
+	verification, err := h.verificationUsecase.Start(c.Request().Context(), itemID)
+	if err != nil {
+		return errorResponse(c, err, "failed to start verification")
+	}
+	return c.JSON(http.StatusOK, verification)
+}
+
+// 所見（notes）と鑑定書（certificate_id）を添えて検証済みにする
+func (h *VerificationHandler) SignOff(c echo.Context) error {
+	itemID, ok := response.ParseID(c, "id")
+	if !ok {
+		return response.Error(c, http.StatusBadRequest, "invalid item ID")
+	}
+	var input usecase.SignOffVerificationInput
+	if err := c.Bind(&input); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	verification, err := h.verificationUsecase.SignOff(c.Request().Context(), itemID, input)
+	if err != nil {
+		return errorResponse(c, err, "failed to sign off verification")
+	}
+	return c.JSON(http.StatusOK, verification)
+}
+
+// 検証をやり直してロックを解除する。理由（reason）は必須
+func (h *VerificationHandler) Reopen(c echo.Context) error {
+	itemID, ok := response.ParseID(c, "id")
+	if !ok {
+		return response.Error(c, http.StatusBadRequest, "invalid item ID")
+	}
+	var input reopenRequest
+	if err := c.Bind(&input); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	verification, err := h.verificationUsecase.Reopen(c.Request().Context(), itemID, input.Reason)
+	if err != nil {
+		return errorResponse(c, err, "failed to reopen verification")
+	}
+	return c.JSON(http.StatusOK, verification)
+}
+
+func errorResponse(c echo.Context, err error, fallback string) error {
+	switch {
+	case domainErrors.IsUnauthenticatedError(err):
+		return response.Error(c, http.StatusUnauthorized, "authentication required")
+	case domainErrors.IsForbiddenError(err):
+		return response.Error(c, http.StatusForbidden, err.Error())
+	case domainErrors.IsNotFoundError(err):
+		return response.Error(c, http.StatusNotFound, "item not found")
+	case domainErrors.IsValidationError(err):
+		return response.ValidationError(c, err)
+	case domainErrors.IsReasonRequiredError(err):
+		return response.Error(c, http.StatusUnprocessableEntity, "reason is required for this operation")
+	case domainErrors.IsLockedError(err):
+		return response.Error(c, http.StatusConflict, err.Error())
+	}
+	return response.RepositoryError(c, err, fallback)
+}
@@ -0,0 +1,176 @@
+package webhooks
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/interfaces/controller/listing"
+	"Aicon-assignment/internal/interfaces/controller/response"
+	"Aicon-assignment/internal/pkg/listquery"
+	"Aicon-assignment/internal/usecase"
+)
+
+type WebhookHandler struct {
+	webhookUsecase usecase.WebhookUsecase
+}
+
+func NewWebhookHandler(webhookUsecase usecase.WebhookUsecase) *WebhookHandler {
+	return &WebhookHandler{
+		webhookUsecase: webhookUsecase,
+	}
+}
+
+func (h *WebhookHandler) ListWebhooks(c echo.Context) error {
+	q, err := listquery.Parse(c.QueryParams(), entity.WebhookListSpec)
+	if err != nil {
+		return response.ValidationError(c, err)
+	}
+
+	webhooks, err := h.webhookUsecase.ListWebhooks(c.Request().Context())
+	if err != nil {
+		return response.RepositoryError(c, err, "failed to retrieve webhooks")
+	}
+
+	return response.List(c, listquery.Apply(webhooks, q, (*entity.Webhook).FilterValue), q)
+}
+
+func (h *WebhookHandler) GetWebhook(c echo.Context) error {
+	id, ok := response.ParseID(c, "id")
+	if !ok {
+		return response.Error(c, http.StatusBadRequest, "invalid webhook ID")
+	}
+
+	webhook, err := h.webhookUsecase.GetWebhook(c.Request().Context(), id)
+	if err != nil {
+		return h.errorResponse(c, err, "failed to retrieve webhook")
+	}
+
+	return c.JSON(http.StatusOK, webhook)
+}
+
+func (h *WebhookHandler) CreateWebhook(c echo.Context) error {
+	var input usecase.CreateWebhookInput
+	if err := c.Bind(&input); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	webhook, err := h.webhookUsecase.CreateWebhook(c.Request().Context(), input)
+	if err != nil {
+		return h.errorResponse(c, err, "failed to create webhook")
+	}
+
+	return c.JSON(http.StatusCreated, webhook)
+}
+
+func (h *WebhookHandler) UpdateWebhook(c echo.Context) error {
+	id, ok := response.ParseID(c, "id")
+	if !ok {
+		return response.Error(c, http.StatusBadRequest, "invalid webhook ID")
+	}
+
+	var input usecase.UpdateWebhookInput
+	if err := c.Bind(&input); err != nil {
+		return response.Error(c, http.StatusBadRequest, "invalid request format")
+	}
+
+	webhook, err := h.webhookUsecase.UpdateWebhook(c.Request().Context(), id, input)
+	if err != nil {
+		return h.errorResponse(c, err, "failed to update webhook")
+	}
+
+	return c.JSON(http.StatusOK, webhook)
+}
+
+func (h *WebhookHandler) DeleteWebhook(c echo.Context) error {
+	id, ok := response.ParseID(c, "id")
+	if !ok {
+		return response.Error(c, http.StatusBadRequest, "invalid webhook ID")
+	}
+
+	if err := h.webhookUsecase.DeleteWebhook(c.Request().Context(), id); err != nil {
+		return h.errorResponse(c, err, "failed to delete webhook")
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// サンプルイベントでペイロードを描画し、テスト送信する
+func (h *WebhookHandler) TestWebhook(c echo.Context) error {
+	id, ok := response.ParseID(c, "id")
+	if !ok {
+		return response.Error(c, http.StatusBadRequest, "invalid webhook ID")
+	}
+
+	result, err := h.webhookUsecase.TestWebhook(c.Request().Context(), id)
+	if err != nil {
+		return h.errorResponse(c, err, "failed to test webhook")
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// 配信履歴。status=success|failed で絞り込める
+func (h *WebhookHandler) ListDeliveries(c echo.Context) error {
+	return listing.SubResource[*entity.WebhookDelivery]{
+		Parent: "webhook",
+		Spec:   entity.WebhookDeliveryListSpec,
+		Value:  (*entity.WebhookDelivery).FilterValue,
+		Fetch: func(c echo.Context, id int64) ([]*entity.WebhookDelivery, error) {
+			return h.webhookUsecase.ListDeliveries(c.Request().Context(), id, c.QueryParam("status"))
+		},
+		Error: func(c echo.Context, err error) error {
+			return h.errorResponse(c, err, "failed to retrieve deliveries")
+		},
+	}.Handle(c)
+}
+
+// 配信の集計（成功・失敗件数、平均レイテンシ）
+func (h *WebhookHandler) GetDeliveryStats(c echo.Context) error {
+	id, ok := response.ParseID(c, "id")
+	if !ok {
+		return response.Error(c, http.StatusBadRequest, "invalid webhook ID")
+	}
+
+	stats, err := h.webhookUsecase.GetDeliveryStats(c.Request().Context(), id)
+	if err != nil {
+		return h.errorResponse(c, err, "failed to retrieve delivery stats")
+	}
+
+	return c.JSON(http.StatusOK, stats)
+}
+
+// 過去の配信を同じペイロードで再送する
+func (h *WebhookHandler) Redeliver(c echo.Context) error {
+	id, ok := response.ParseID(c, "id")
+	if !ok {
+		return response.Error(c, http.StatusBadRequest, "invalid webhook ID")
+	}
+	deliveryID, ok := response.ParseID(c, "deliveryId")
+	if !ok {
+		return response.Error(c, http.StatusBadRequest, "invalid delivery ID")
+	}
+
+	delivery, err := h.webhookUsecase.Redeliver(c.Request().Context(), id, deliveryID)
+	if err != nil {
+		return h.errorResponse(c, err, "failed to redeliver webhook")
+	}
+
+	return c.JSON(http.StatusOK, delivery)
+}
+
+func (h *WebhookHandler) errorResponse(c echo.Context, err error, fallback string) error {
+	if errors.Is(err, domainErrors.ErrDeliveryNotFound) {
+		return response.Error(c, http.StatusNotFound, "webhook delivery not found")
+	}
+	if domainErrors.IsNotFoundError(err) {
+		return response.Error(c, http.StatusNotFound, "webhook not found")
+	}
+	if domainErrors.IsValidationError(err) {
+		return response.ValidationError(c, err)
+	}
+	return response.RepositoryError(c, err, fallback)
+}
@@ -0,0 +1,158 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+type APIKeyRepository struct {
+	SqlHandler
+	table string // 空の場合は api_keys
+}
+
+// サンドボックスの API キーを sandbox_api_keys に保存する
+func NewSandboxKeyRepository(h SqlHandler) *APIKeyRepository {
+	return &APIKeyRepository{SqlHandler: h, table: "sandbox_api_keys"}
+}
+
+func (r *APIKeyRepository) tableName() string {
+	if r.table == "" {
+		return "api_keys"
+	}
+	return r.table
+}
+
+const apiKeyColumns = `id, user_id, name, prefix, key_hash, created_at, last_used_at, revoked_at`
+
+func (r *APIKeyRepository) Create(ctx context.Context, key *entity.APIKey) error {
+	query := `
+        INSERT INTO ` + r.tableName() + ` (user_id, name, prefix, key_hash, created_at)
+        VALUES (?, ?, ?, ?, ?)
+    `
+
+	result, err := r.Execute(ctx, query,
+		key.UserID,
+		key.Name,
+		key.Prefix,
+		key.KeyHash,
+		key.CreatedAt,
+	)
+	if err != nil {
+		return wrapError(err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("%w: failed to get last insert id: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	key.ID = id
+
+	return nil
+}
+
+func (r *APIKeyRepository) FindByID(ctx context.Context, id int64) (*entity.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM ` + r.tableName() + ` WHERE id = ?`
+	return r.findOne(ctx, query, id)
+}
+
+func (r *APIKeyRepository) FindByKeyHash(ctx context.Context, keyHash string) (*entity.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM ` + r.tableName() + ` WHERE key_hash = ?`
+	return r.findOne(ctx, query, keyHash)
+}
+
+func (r *APIKeyRepository) findOne(ctx context.Context, query string, arg interface{}) (*entity.APIKey, error) {
+	key, err := scanAPIKey(r.QueryRow(ctx, query, arg))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domainErrors.ErrAPIKeyNotFound
+		}
+		return nil, wrapError(err)
+	}
+
+	return key, nil
+}
+
+func (r *APIKeyRepository) FindByUser(ctx context.Context, userID int64) ([]*entity.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM ` + r.tableName() + ` WHERE user_id = ? ORDER BY created_at DESC, id DESC`
+
+	rows, err := r.Query(ctx, query, userID)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer rows.Close()
+
+	keys := []*entity.APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, wrapError(err)
+		}
+		keys = append(keys, key)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, wrapError(err)
+	}
+
+	return keys, nil
+}
+
+func (r *APIKeyRepository) Revoke(ctx context.Context, id int64, at time.Time) error {
+	return r.update(ctx, `UPDATE `+r.tableName()+` SET revoked_at = ? WHERE id = ?`, at, id)
+}
+
+func (r *APIKeyRepository) TouchLastUsed(ctx context.Context, id int64, at time.Time) error {
+	return r.update(ctx, `UPDATE `+r.tableName()+` SET last_used_at = ? WHERE id = ?`, at, id)
+}
+
+func (r *APIKeyRepository) update(ctx context.Context, query string, at time.Time, id int64) error {
+	result, err := r.Execute(ctx, query, at, id)
+	if err != nil {
+		return wrapError(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%w: failed to get rows affected: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	if rowsAffected == 0 {
+		return domainErrors.ErrAPIKeyNotFound
+	}
+
+	return nil
+}
+
+func scanAPIKey(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*entity.APIKey, error) {
+	var key entity.APIKey
+	var lastUsedAt, revokedAt sql.NullTime
+
+	err := scanner.Scan(
+		&key.ID,
+		&key.UserID,
+		&key.Name,
+		&key.Prefix,
+		&key.KeyHash,
+		&key.CreatedAt,
+		&lastUsedAt,
+		&revokedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if lastUsedAt.Valid {
+		key.LastUsedAt = &lastUsedAt.Time
+	}
+	if revokedAt.Valid {
+		key.RevokedAt = &revokedAt.Time
+	}
+
+	return &key, nil
+}
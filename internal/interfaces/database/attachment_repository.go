@@ -0,0 +1,296 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+type AttachmentRepository struct {
+	SqlHandler
+}
+
+func (r *AttachmentRepository) Create(ctx context.Context, attachment *entity.Attachment) error {
+	query := `
+        INSERT INTO attachments (item_id, kind, filename, content_type, size, sha256, created_at)
+        VALUES (?, ?, ?, ?, ?, ?, ?)
+    `
+
+	result, err := r.Execute(ctx, query,
+		attachment.ItemID,
+		attachment.Kind,
+		attachment.Filename,
+		attachment.ContentType,
+		attachment.Size,
+		attachment.SHA256,
+		attachment.CreatedAt,
+	)
+	if err != nil {
+		return wrapError(err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("%w: failed to get last insert id: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	attachment.ID = id
+
+	return nil
+}
+
+func (r *AttachmentRepository) FindByID(ctx context.Context, id int64) (*entity.Attachment, error) {
+	query := `SELECT id, item_id, kind, filename, content_type, size, sha256, created_at FROM attachments WHERE id = ?`
+
+	attachment, err := scanAttachment(r.QueryRow(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domainErrors.ErrAttachmentNotFound
+		}
+		return nil, wrapError(err)
+	}
+	return attachment, nil
+}
+
+// 同時に置き換えられて 2 つになった場合は新しい方を返す
+func (r *AttachmentRepository) FindReceipt(ctx context.Context, itemID int64) (*entity.Attachment, error) {
+	query := `
+        SELECT id, item_id, kind, filename, content_type, size, sha256, created_at
+        FROM attachments
+        WHERE item_id = ? AND kind = ?
+        ORDER BY id DESC
+        LIMIT 1
+    `
+
+	attachment, err := scanAttachment(r.QueryRow(ctx, query, itemID, entity.AttachmentKindReceipt))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domainErrors.ErrReceiptNotFound
+		}
+		return nil, wrapError(err)
+	}
+	return attachment, nil
+}
+
+func (r *AttachmentRepository) FindByItemID(ctx context.Context, itemID int64) ([]*entity.Attachment, error) {
+	query := `
+        SELECT id, item_id, kind, filename, content_type, size, sha256, created_at
+        FROM attachments
+        WHERE item_id = ?
+        ORDER BY id
+    `
+
+	rows, err := r.Query(ctx, query, itemID)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer rows.Close()
+
+	attachments := []*entity.Attachment{}
+	for rows.Next() {
+		attachment, err := scanAttachment(rows)
+		if err != nil {
+			return nil, wrapError(err)
+		}
+		attachments = append(attachments, attachment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, wrapError(err)
+	}
+	return attachments, nil
+}
+
+// レシートは 1 アイテムに 1 つのため、統合先にレシートがある場合は統合元のレシートを書類として移す
+func (r *AttachmentRepository) MoveToItem(ctx context.Context, fromItemID, toItemID int64) error {
+	if _, err := r.FindReceipt(ctx, toItemID); err == nil {
+		query := `UPDATE attachments SET kind = ? WHERE item_id = ? AND kind = ?`
+		if _, err := r.Execute(ctx, query, entity.AttachmentKindDocument, fromItemID, entity.AttachmentKindReceipt); err != nil {
+			return wrapError(err)
+		}
+	} else if err != domainErrors.ErrReceiptNotFound {
+		return err
+	}
+
+	if _, err := r.Execute(ctx, `UPDATE attachments SET item_id = ? WHERE item_id = ?`, toItemID, fromItemID); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+func (r *AttachmentRepository) Delete(ctx context.Context, id int64) error {
+	result, err := r.Execute(ctx, `DELETE FROM attachments WHERE id = ?`, id)
+	if err != nil {
+		return wrapError(err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%w: failed to get affected rows: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	if affected == 0 {
+		return domainErrors.ErrAttachmentNotFound
+	}
+	return nil
+}
+
+func (r *AttachmentRepository) FindContent(ctx context.Context, sha256 string) (*entity.AttachmentContent, error) {
+	query := `SELECT sha256, size, ref_count, unreferenced_at FROM attachment_contents WHERE sha256 = ?`
+
+	content, err := scanAttachmentContent(r.QueryRow(ctx, query, sha256))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domainErrors.ErrAttachmentNotFound
+		}
+		return nil, wrapError(err)
+	}
+	return content, nil
+}
+
+func (r *AttachmentRepository) AddReference(ctx context.Context, sha256 string, size int64) error {
+	query := `
+        INSERT INTO attachment_contents (sha256, size, ref_count, unreferenced_at)
+        VALUES (?, ?, 1, NULL)
+        ON DUPLICATE KEY UPDATE ref_count = ref_count + 1, unreferenced_at = NULL
+    `
+
+	if _, err := r.Execute(ctx, query, sha256, size); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+func (r *AttachmentRepository) RemoveReference(ctx context.Context, sha256 string, now time.Time) error {
+	query := `
+        UPDATE attachment_contents
+        SET ref_count = GREATEST(ref_count - 1, 0),
+            unreferenced_at = IF(ref_count = 0, ?, NULL)
+        WHERE sha256 = ?
+    `
+
+	// MySQL の UPDATE は SET を左から順に評価するため、IF の ref_count は減らした後の値になる
+	if _, err := r.Execute(ctx, query, now, sha256); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+func (r *AttachmentRepository) FindUnreferenced(ctx context.Context, before time.Time, limit int) ([]*entity.AttachmentContent, error) {
+	query := `
+        SELECT sha256, size, ref_count, unreferenced_at
+        FROM attachment_contents
+        WHERE ref_count = 0 AND unreferenced_at <= ?
+        ORDER BY unreferenced_at
+        LIMIT ?
+    `
+
+	rows, err := r.Query(ctx, query, before, limit)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer rows.Close()
+
+	var contents []*entity.AttachmentContent
+	for rows.Next() {
+		content, err := scanAttachmentContent(rows)
+		if err != nil {
+			return nil, wrapError(err)
+		}
+		contents = append(contents, content)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, wrapError(err)
+	}
+	return contents, nil
+}
+
+func (r *AttachmentRepository) ReconcileReferences(ctx context.Context, now time.Time) (int64, error) {
+	query := `
+        UPDATE attachment_contents c
+        LEFT JOIN (SELECT sha256, COUNT(*) AS refs FROM attachments GROUP BY sha256) a ON a.sha256 = c.sha256
+        SET c.unreferenced_at = IF(COALESCE(a.refs, 0) = 0, COALESCE(c.unreferenced_at, ?), NULL),
+            c.ref_count = COALESCE(a.refs, 0)
+        WHERE c.ref_count <> COALESCE(a.refs, 0)
+    `
+
+	result, err := r.Execute(ctx, query, now)
+	if err != nil {
+		return 0, wrapError(err)
+	}
+	corrected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%w: failed to get affected rows: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	return corrected, nil
+}
+
+func (r *AttachmentRepository) DeleteContent(ctx context.Context, sha256 string) (bool, error) {
+	result, err := r.Execute(ctx, `DELETE FROM attachment_contents WHERE sha256 = ? AND ref_count = 0`, sha256)
+	if err != nil {
+		return false, wrapError(err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("%w: failed to get affected rows: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	return affected > 0, nil
+}
+
+func (r *AttachmentRepository) StorageReport(ctx context.Context) (*entity.AttachmentStorageReport, error) {
+	var report entity.AttachmentStorageReport
+
+	err := r.QueryRow(ctx, `SELECT COUNT(*), COALESCE(SUM(size), 0) FROM attachments`).
+		Scan(&report.Attachments, &report.LogicalBytes)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	query := `
+        SELECT COUNT(*), COALESCE(SUM(size), 0),
+               COALESCE(SUM(ref_count = 0), 0), COALESCE(SUM(IF(ref_count = 0, size, 0)), 0)
+        FROM attachment_contents
+    `
+	err = r.QueryRow(ctx, query).
+		Scan(&report.Contents, &report.StoredBytes, &report.UnreferencedContents, &report.UnreferencedBytes)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	report.SavedBytes = report.LogicalBytes - (report.StoredBytes - report.UnreferencedBytes)
+	return &report, nil
+}
+
+func scanAttachment(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*entity.Attachment, error) {
+	var attachment entity.Attachment
+	err := scanner.Scan(
+		&attachment.ID,
+		&attachment.ItemID,
+		&attachment.Kind,
+		&attachment.Filename,
+		&attachment.ContentType,
+		&attachment.Size,
+		&attachment.SHA256,
+		&attachment.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+func scanAttachmentContent(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*entity.AttachmentContent, error) {
+	var content entity.AttachmentContent
+	var unreferencedAt sql.NullTime
+	if err := scanner.Scan(&content.SHA256, &content.Size, &content.RefCount, &unreferencedAt); err != nil {
+		return nil, err
+	}
+	if unreferencedAt.Valid {
+		content.UnreferencedAt = &unreferencedAt.Time
+	}
+	return &content, nil
+}
@@ -0,0 +1,79 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+type AuditLogRepository struct {
+	SqlHandler
+}
+
+func (r *AuditLogRepository) Record(ctx context.Context, entry *entity.AuditEntry) error {
+	query := `
+        INSERT INTO audit_logs (action, item_id, actor, impersonator, reason, created_at)
+        VALUES (?, ?, ?, ?, ?, ?)
+    `
+
+	result, err := r.Execute(ctx, query,
+		entry.Action,
+		entry.ItemID,
+		entry.Actor,
+		entry.Impersonator,
+		entry.Reason,
+		entry.CreatedAt,
+	)
+	if err != nil {
+		return wrapError(err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("%w: failed to get last insert id: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	entry.ID = id
+
+	return nil
+}
+
+func (r *AuditLogRepository) FindByItemID(ctx context.Context, itemID int64) ([]*entity.AuditEntry, error) {
+	query := `
+        SELECT id, action, item_id, actor, impersonator, reason, created_at
+        FROM audit_logs
+        WHERE item_id = ?
+        ORDER BY created_at, id
+    `
+
+	rows, err := r.Query(ctx, query, itemID)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer rows.Close()
+
+	entries := []*entity.AuditEntry{}
+	for rows.Next() {
+		var entry entity.AuditEntry
+		if err := rows.Scan(&entry.ID, &entry.Action, &entry.ItemID, &entry.Actor, &entry.Impersonator, &entry.Reason, &entry.CreatedAt); err != nil {
+			return nil, wrapError(err)
+		}
+		entries = append(entries, &entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, wrapError(err)
+	}
+
+	return entries, nil
+}
+
+func (r *AuditLogRepository) CountBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	return countBefore(ctx, r.SqlHandler, `SELECT COUNT(*) FROM audit_logs WHERE created_at < ?`, cutoff)
+}
+
+func (r *AuditLogRepository) DeleteBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	return deleteBefore(ctx, r.SqlHandler, `DELETE FROM audit_logs WHERE created_at < ?`, cutoff)
+}
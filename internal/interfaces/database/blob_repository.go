@@ -0,0 +1,93 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+type BlobRepository struct {
+	SqlHandler
+}
+
+func (r *BlobRepository) Save(ctx context.Context, blob *entity.Blob) error {
+	query := `
+        INSERT INTO blobs (blob_key, location, size, sha256, created_at, expires_at)
+        VALUES (?, ?, ?, ?, ?, ?)
+        ON DUPLICATE KEY UPDATE
+            location = VALUES(location), size = VALUES(size), sha256 = VALUES(sha256),
+            created_at = VALUES(created_at), expires_at = VALUES(expires_at)
+    `
+
+	_, err := r.Execute(ctx, query, blob.Key, blob.Location, blob.Size, blob.SHA256, blob.CreatedAt, blob.ExpiresAt)
+	if err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+func (r *BlobRepository) FindByKey(ctx context.Context, key string) (*entity.Blob, error) {
+	query := `SELECT blob_key, location, size, sha256, created_at, expires_at FROM blobs WHERE blob_key = ?`
+
+	blob, err := scanBlob(r.QueryRow(ctx, query, key))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domainErrors.ErrBlobNotFound
+		}
+		return nil, wrapError(err)
+	}
+	return blob, nil
+}
+
+func (r *BlobRepository) FindExpired(ctx context.Context, now time.Time, limit int) ([]*entity.Blob, error) {
+	query := `
+        SELECT blob_key, location, size, sha256, created_at, expires_at
+        FROM blobs
+        WHERE expires_at IS NOT NULL AND expires_at <= ?
+        ORDER BY expires_at
+        LIMIT ?
+    `
+
+	rows, err := r.Query(ctx, query, now, limit)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer rows.Close()
+
+	var blobs []*entity.Blob
+	for rows.Next() {
+		blob, err := scanBlob(rows)
+		if err != nil {
+			return nil, wrapError(err)
+		}
+		blobs = append(blobs, blob)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, wrapError(err)
+	}
+	return blobs, nil
+}
+
+func (r *BlobRepository) Delete(ctx context.Context, key string) error {
+	if _, err := r.Execute(ctx, `DELETE FROM blobs WHERE blob_key = ?`, key); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+func scanBlob(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*entity.Blob, error) {
+	var blob entity.Blob
+	var expiresAt sql.NullTime
+	if err := scanner.Scan(&blob.Key, &blob.Location, &blob.Size, &blob.SHA256, &blob.CreatedAt, &expiresAt); err != nil {
+		return nil, err
+	}
+	if expiresAt.Valid {
+		blob.ExpiresAt = &expiresAt.Time
+	}
+	return &blob, nil
+}
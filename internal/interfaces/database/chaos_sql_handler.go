@@ -0,0 +1,73 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"math/rand/v2"
+
+	"Aicon-assignment/internal/pkg/reqctx"
+)
+
+// 障害注入用に、リクエストに設定された確率でクエリを接続断にする SqlHandler
+// 接続断は driver.ErrBadConn として返すため、リポジトリでは通常どおり ErrConnectionLost に分類される
+type ChaosSqlHandler struct {
+	SqlHandler
+	Random func() float64 // nil の場合は math/rand を使う
+}
+
+func NewChaosSqlHandler(h SqlHandler) *ChaosSqlHandler {
+	return &ChaosSqlHandler{SqlHandler: h}
+}
+
+func (h *ChaosSqlHandler) dropped(ctx context.Context) bool {
+	rate := reqctx.DBDropRate(ctx)
+	if rate <= 0 {
+		return false
+	}
+	random := h.Random
+	if random == nil {
+		random = rand.Float64
+	}
+	if random() >= rate {
+		return false
+	}
+	reqctx.Logger(ctx).Warn("chaos: dropping database connection")
+	return true
+}
+
+func (h *ChaosSqlHandler) Execute(ctx context.Context, statement string, args ...interface{}) (Result, error) {
+	if h.dropped(ctx) {
+		return nil, driver.ErrBadConn
+	}
+	return h.SqlHandler.Execute(ctx, statement, args...)
+}
+
+func (h *ChaosSqlHandler) Query(ctx context.Context, statement string, args ...interface{}) (Rows, error) {
+	if h.dropped(ctx) {
+		return nil, driver.ErrBadConn
+	}
+	return h.SqlHandler.Query(ctx, statement, args...)
+}
+
+func (h *ChaosSqlHandler) QueryRow(ctx context.Context, statement string, args ...interface{}) Row {
+	if h.dropped(ctx) {
+		return errRow{err: driver.ErrBadConn}
+	}
+	return h.SqlHandler.QueryRow(ctx, statement, args...)
+}
+
+func (h *ChaosSqlHandler) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if h.dropped(ctx) {
+		return driver.ErrBadConn
+	}
+	return h.SqlHandler.Transaction(ctx, fn)
+}
+
+// Scan で常にエラーを返す Row
+type errRow struct {
+	err error
+}
+
+func (r errRow) Scan(dest ...interface{}) error {
+	return r.err
+}
@@ -0,0 +1,52 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"Aicon-assignment/internal/domain/entity"
+)
+
+type DeprecationUsageRepository struct {
+	SqlHandler
+}
+
+func (r *DeprecationUsageRepository) Record(ctx context.Context, method, route, caller string, at time.Time) error {
+	query := `
+        INSERT INTO deprecation_usage (method, route, caller, calls, first_seen_at, last_seen_at)
+        VALUES (?, ?, ?, 1, ?, ?)
+        ON DUPLICATE KEY UPDATE calls = calls + 1, last_seen_at = VALUES(last_seen_at)
+    `
+
+	if _, err := r.Execute(ctx, query, method, route, caller, at, at); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+func (r *DeprecationUsageRepository) FindAll(ctx context.Context) ([]*entity.DeprecationUsage, error) {
+	rows, err := r.Query(ctx, `
+        SELECT method, route, caller, calls, first_seen_at, last_seen_at
+        FROM deprecation_usage
+        ORDER BY last_seen_at DESC, method, route, caller
+    `)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer rows.Close()
+
+	usage := []*entity.DeprecationUsage{}
+	for rows.Next() {
+		var row entity.DeprecationUsage
+		if err := rows.Scan(&row.Method, &row.Route, &row.Caller, &row.Calls, &row.FirstSeen, &row.LastSeen); err != nil {
+			return nil, wrapError(err)
+		}
+		usage = append(usage, &row)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, wrapError(err)
+	}
+
+	return usage, nil
+}
@@ -0,0 +1,56 @@
+package database
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+
+	"github.com/go-sql-driver/mysql"
+
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+// MySQLのエラー番号
+// https://dev.mysql.com/doc/mysql-errors/8.0/en/server-error-reference.html
+const (
+	mysqlErrDuplicateEntry  uint16 = 1062
+	mysqlErrRowIsReferenced uint16 = 1451
+	mysqlErrNoReferencedRow uint16 = 1452
+	mysqlErrLockWaitTimeout uint16 = 1205
+	mysqlErrLockDeadlock    uint16 = 1213
+	mysqlErrServerGone      uint16 = 2006
+	mysqlErrServerLost      uint16 = 2013
+)
+
+// ドライバーのエラーをドメインエラーに分類してラップする
+// すべての分類は ErrDatabaseError も満たすため、既存の IsDatabaseError 判定はそのまま使える
+func wrapError(err error) error {
+	kind := classifyError(err)
+	if kind == nil {
+		return fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	return fmt.Errorf("%w: %w: %s", domainErrors.ErrDatabaseError, kind, err.Error())
+}
+
+func classifyError(err error) error {
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, mysql.ErrInvalidConn) {
+		return domainErrors.ErrConnectionLost
+	}
+
+	var mysqlErr *mysql.MySQLError
+	if !errors.As(err, &mysqlErr) {
+		return nil
+	}
+
+	switch mysqlErr.Number {
+	case mysqlErrDuplicateEntry:
+		return domainErrors.ErrDuplicateEntry
+	case mysqlErrRowIsReferenced, mysqlErrNoReferencedRow:
+		return domainErrors.ErrConstraintViolation
+	case mysqlErrLockWaitTimeout, mysqlErrLockDeadlock:
+		return domainErrors.ErrSerializationFailure
+	case mysqlErrServerGone, mysqlErrServerLost:
+		return domainErrors.ErrConnectionLost
+	}
+	return nil
+}
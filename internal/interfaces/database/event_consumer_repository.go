@@ -0,0 +1,143 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+type EventConsumerRepository struct {
+	SqlHandler
+}
+
+const eventConsumerColumns = `name, acked_sequence, delivered_sequence, ack_timeout_seconds, ack_deadline, redeliveries, last_acked_at, created_at, updated_at`
+
+func (r *EventConsumerRepository) Create(ctx context.Context, consumer *entity.EventConsumer) error {
+	query := `
+        INSERT INTO event_consumers (` + eventConsumerColumns + `)
+        VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+    `
+	_, err := r.Execute(ctx, query,
+		consumer.Name,
+		consumer.AckedSequence,
+		consumer.DeliveredSequence,
+		consumer.AckTimeoutSeconds,
+		consumer.AckDeadline,
+		consumer.Redeliveries,
+		consumer.LastAckedAt,
+		consumer.CreatedAt,
+		consumer.UpdatedAt,
+	)
+	if err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+// トランザクションの中では行をロックし、同じ消費者の取得と確認を直列にする
+func (r *EventConsumerRepository) Find(ctx context.Context, name string) (*entity.EventConsumer, error) {
+	query := `SELECT ` + eventConsumerColumns + ` FROM event_consumers WHERE name = ? FOR UPDATE`
+
+	consumer, err := scanEventConsumer(r.QueryRow(ctx, query, name))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domainErrors.ErrConsumerNotFound
+		}
+		return nil, wrapError(err)
+	}
+	return consumer, nil
+}
+
+func (r *EventConsumerRepository) List(ctx context.Context) ([]*entity.EventConsumer, error) {
+	rows, err := r.Query(ctx, `SELECT `+eventConsumerColumns+` FROM event_consumers ORDER BY name`)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer rows.Close()
+
+	consumers := []*entity.EventConsumer{}
+	for rows.Next() {
+		consumer, err := scanEventConsumer(rows)
+		if err != nil {
+			return nil, wrapError(err)
+		}
+		consumers = append(consumers, consumer)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, wrapError(err)
+	}
+
+	return consumers, nil
+}
+
+func (r *EventConsumerRepository) Save(ctx context.Context, consumer *entity.EventConsumer) error {
+	query := `
+        UPDATE event_consumers
+        SET acked_sequence = ?, delivered_sequence = ?, ack_deadline = ?, redeliveries = ?, last_acked_at = ?, updated_at = ?
+        WHERE name = ?
+    `
+	return r.execute(ctx, query,
+		consumer.AckedSequence,
+		consumer.DeliveredSequence,
+		consumer.AckDeadline,
+		consumer.Redeliveries,
+		consumer.LastAckedAt,
+		consumer.UpdatedAt,
+		consumer.Name,
+	)
+}
+
+func (r *EventConsumerRepository) Delete(ctx context.Context, name string) error {
+	return r.execute(ctx, `DELETE FROM event_consumers WHERE name = ?`, name)
+}
+
+func (r *EventConsumerRepository) execute(ctx context.Context, query string, args ...interface{}) error {
+	result, err := r.Execute(ctx, query, args...)
+	if err != nil {
+		return wrapError(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%w: failed to get rows affected: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	if rowsAffected == 0 {
+		return domainErrors.ErrConsumerNotFound
+	}
+
+	return nil
+}
+
+func scanEventConsumer(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*entity.EventConsumer, error) {
+	var consumer entity.EventConsumer
+	var ackDeadline, lastAckedAt sql.NullTime
+
+	err := scanner.Scan(
+		&consumer.Name,
+		&consumer.AckedSequence,
+		&consumer.DeliveredSequence,
+		&consumer.AckTimeoutSeconds,
+		&ackDeadline,
+		&consumer.Redeliveries,
+		&lastAckedAt,
+		&consumer.CreatedAt,
+		&consumer.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if ackDeadline.Valid {
+		consumer.AckDeadline = &ackDeadline.Time
+	}
+	if lastAckedAt.Valid {
+		consumer.LastAckedAt = &lastAckedAt.Time
+	}
+	return &consumer, nil
+}
@@ -0,0 +1,91 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+// 追記専用のイベントストア。更新・削除は行わない
+type EventStore struct {
+	SqlHandler
+}
+
+func (s *EventStore) Append(ctx context.Context, event *entity.StoredEvent) error {
+	query := `
+        INSERT INTO domain_events (event_id, event_type, item_id, schema_version, payload, occurred_at)
+        VALUES (?, ?, ?, ?, ?, ?)
+    `
+
+	result, err := s.Execute(ctx, query,
+		event.EventID,
+		event.EventType,
+		event.ItemID,
+		event.SchemaVersion,
+		string(event.Payload),
+		event.OccurredAt,
+	)
+	if err != nil {
+		return wrapError(err)
+	}
+
+	sequence, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("%w: failed to get last insert id: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	event.Sequence = sequence
+
+	return nil
+}
+
+func (s *EventStore) LastSequence(ctx context.Context) (int64, error) {
+	var sequence int64
+	if err := s.QueryRow(ctx, `SELECT COALESCE(MAX(sequence), 0) FROM domain_events`).Scan(&sequence); err != nil {
+		return 0, wrapError(err)
+	}
+	return sequence, nil
+}
+
+func (s *EventStore) LoadAfter(ctx context.Context, afterSequence int64, limit int) ([]*entity.StoredEvent, error) {
+	query := `
+        SELECT sequence, event_id, event_type, item_id, schema_version, payload, occurred_at
+        FROM domain_events
+        WHERE sequence > ?
+        ORDER BY sequence
+        LIMIT ?
+    `
+
+	rows, err := s.Query(ctx, query, afterSequence, limit)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer rows.Close()
+
+	events := []*entity.StoredEvent{}
+	for rows.Next() {
+		var event entity.StoredEvent
+		var payload string
+		err := rows.Scan(
+			&event.Sequence,
+			&event.EventID,
+			&event.EventType,
+			&event.ItemID,
+			&event.SchemaVersion,
+			&payload,
+			&event.OccurredAt,
+		)
+		if err != nil {
+			return nil, wrapError(err)
+		}
+		event.Payload = []byte(payload)
+		events = append(events, &event)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, wrapError(err)
+	}
+
+	return events, nil
+}
@@ -0,0 +1,59 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+type ExportRepository struct {
+	SqlHandler
+}
+
+func (r *ExportRepository) Create(ctx context.Context, export *entity.Export) error {
+	query := `
+        INSERT INTO exports (watermark, since_export_id, format, record_count, created_at)
+        VALUES (?, ?, ?, ?, ?)
+    `
+
+	result, err := r.Execute(ctx, query,
+		export.Watermark,
+		export.SinceExportID,
+		export.Format,
+		export.RecordCount,
+		export.CreatedAt,
+	)
+	if err != nil {
+		return wrapError(err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("%w: failed to get last insert id: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	export.ID = id
+
+	return nil
+}
+
+func (r *ExportRepository) FindByID(ctx context.Context, id int64) (*entity.Export, error) {
+	query := `SELECT id, watermark, since_export_id, format, record_count, created_at FROM exports WHERE id = ?`
+
+	var export entity.Export
+	var sinceExportID sql.NullInt64
+	err := r.QueryRow(ctx, query, id).Scan(&export.ID, &export.Watermark, &sinceExportID, &export.Format, &export.RecordCount, &export.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domainErrors.ErrExportNotFound
+		}
+		return nil, wrapError(err)
+	}
+	if sinceExportID.Valid {
+		export.SinceExportID = &sinceExportID.Int64
+	}
+
+	return &export, nil
+}
@@ -0,0 +1,106 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+
+	"Aicon-assignment/internal/pkg/filter"
+	"Aicon-assignment/internal/pkg/listquery"
+)
+
+// アイテムの条件式で使えるフィールドとカラムの対応
+var itemFilterColumns = map[string]string{
+	"name":           "name",
+	"category":       "category",
+	"brand":          "brand",
+	"purchase_price": "purchase_price",
+	"purchase_date":  "purchase_date",
+}
+
+// 検証済みの条件式を WHERE 句に変換する
+// カラム名は対応表からのみ埋め込み、値はすべてプレースホルダで渡す
+func compileFilter(expr filter.Expr, columns map[string]string) (string, []interface{}, error) {
+	switch e := expr.(type) {
+	case *filter.Logical:
+		left, leftArgs, err := compileFilter(e.Left, columns)
+		if err != nil {
+			return "", nil, err
+		}
+		right, rightArgs, err := compileFilter(e.Right, columns)
+		if err != nil {
+			return "", nil, err
+		}
+		op := "AND"
+		if e.Op == filter.OpOr {
+			op = "OR"
+		}
+		return fmt.Sprintf("(%s %s %s)", left, op, right), append(leftArgs, rightArgs...), nil
+	case *filter.Not:
+		inner, args, err := compileFilter(e.Expr, columns)
+		if err != nil {
+			return "", nil, err
+		}
+		return fmt.Sprintf("(NOT %s)", inner), args, nil
+	case *filter.Comparison:
+		column, ok := columns[e.Field]
+		if !ok {
+			return "", nil, fmt.Errorf("unknown filter field: %s", e.Field)
+		}
+		op, ok := sqlOperators[e.Op]
+		if !ok {
+			return "", nil, fmt.Errorf("unknown filter operator: %s", e.Op)
+		}
+		return fmt.Sprintf("%s %s ?", column, op), []interface{}{e.Value}, nil
+	}
+	return "", nil, fmt.Errorf("unknown filter expression: %T", expr)
+}
+
+// 並び替えに使えるフィールドとカラムの対応
+var itemSortColumns = map[string]string{
+	"id":             "id",
+	"name":           "name",
+	"category":       "category",
+	"brand":          "brand",
+	"purchase_price": "purchase_price",
+	"purchase_date":  "purchase_date",
+	"created_at":     "created_at",
+	"updated_at":     "updated_at",
+}
+
+// 並び順を ORDER BY 句に変換する。結果を安定させるため最後に id で並べる
+func compileSort(fields []listquery.SortField, columns map[string]string) (string, error) {
+	clauses := make([]string, 0, len(fields)+1)
+	hasID := false
+	for _, field := range fields {
+		column, ok := columns[field.Field]
+		if !ok {
+			return "", fmt.Errorf("unknown sort field: %s", field.Field)
+		}
+		direction := "ASC"
+		if field.Desc {
+			direction = "DESC"
+		}
+		clauses = append(clauses, column+" "+direction)
+		hasID = hasID || column == "id"
+	}
+	if !hasID {
+		clauses = append(clauses, "id DESC")
+	}
+	return " ORDER BY " + strings.Join(clauses, ", "), nil
+}
+
+var sqlOperators = map[string]string{
+	filter.OpEq:  "=",
+	filter.OpNe:  "<>",
+	filter.OpGt:  ">",
+	filter.OpGte: ">=",
+	filter.OpLt:  "<",
+	filter.OpLte: "<=",
+}
+
+// LIKE の値に含まれるワイルドカードをエスケープする（MySQL の既定のエスケープ文字 \ を使う）
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
@@ -0,0 +1,222 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+type ImageRepository struct {
+	SqlHandler
+}
+
+func (r *ImageRepository) Create(ctx context.Context, image *entity.ItemImage) error {
+	query := `
+        INSERT INTO item_images (item_id, filename, content_type, size, blob_key, position, is_primary, thumbnail_status, created_at)
+        VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+    `
+
+	result, err := r.Execute(ctx, query,
+		image.ItemID,
+		image.Filename,
+		image.ContentType,
+		image.Size,
+		image.Key,
+		image.Position,
+		image.IsPrimary,
+		image.ThumbnailStatus,
+		image.CreatedAt,
+	)
+	if err != nil {
+		return wrapError(err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("%w: failed to get last insert id: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	image.ID = id
+
+	return nil
+}
+
+func (r *ImageRepository) FindByID(ctx context.Context, id int64) (*entity.ItemImage, error) {
+	query := `SELECT id, item_id, filename, content_type, size, blob_key, position, is_primary, thumbnail_status, created_at FROM item_images WHERE id = ?`
+
+	image, err := scanImage(r.QueryRow(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domainErrors.ErrImageNotFound
+		}
+		return nil, wrapError(err)
+	}
+	return image, nil
+}
+
+func (r *ImageRepository) FindByItemID(ctx context.Context, itemID int64) ([]*entity.ItemImage, error) {
+	query := `
+        SELECT id, item_id, filename, content_type, size, blob_key, position, is_primary, thumbnail_status, created_at
+        FROM item_images
+        WHERE item_id = ?
+        ORDER BY position, id
+    `
+	return r.findAll(ctx, query, itemID)
+}
+
+func (r *ImageRepository) FindPrimaryByItemIDs(ctx context.Context, itemIDs []int64) ([]*entity.ItemImage, error) {
+	if len(itemIDs) == 0 {
+		return []*entity.ItemImage{}, nil
+	}
+	placeholders := make([]string, len(itemIDs))
+	args := make([]interface{}, len(itemIDs))
+	for i, id := range itemIDs {
+		placeholders[i] = "?"
+		args[i] = id
+	}
+
+	query := `
+        SELECT id, item_id, filename, content_type, size, blob_key, position, is_primary, thumbnail_status, created_at
+        FROM item_images
+        WHERE is_primary = TRUE AND item_id IN (` + strings.Join(placeholders, ", ") + `)
+    `
+	return r.findAll(ctx, query, args...)
+}
+
+// 1 文で更新するため、途中で失敗しても順番が崩れない
+func (r *ImageRepository) UpdateGallery(ctx context.Context, itemID int64, orderedIDs []int64, primaryID int64) error {
+	if len(orderedIDs) == 0 {
+		return nil
+	}
+	placeholders := make([]string, len(orderedIDs))
+	args := make([]interface{}, 0, len(orderedIDs)+2)
+	for i, id := range orderedIDs {
+		placeholders[i] = "?"
+		args = append(args, id)
+	}
+	args = append(args, primaryID, itemID)
+
+	query := `
+        UPDATE item_images
+        SET position = FIELD(id, ` + strings.Join(placeholders, ", ") + `) - 1, is_primary = (id = ?)
+        WHERE item_id = ?
+    `
+	if _, err := r.Execute(ctx, query, args...); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+// 統合先のギャラリーの後ろに並べる。統合先に画像がある場合は、統合先の一覧に表示する画像をそのまま使う
+func (r *ImageRepository) MoveToItem(ctx context.Context, fromItemID, toItemID int64) error {
+	var offset, count int
+	if err := r.QueryRow(ctx, `SELECT COALESCE(MAX(position) + 1, 0), COUNT(*) FROM item_images WHERE item_id = ?`, toItemID).Scan(&offset, &count); err != nil {
+		return wrapError(err)
+	}
+
+	query := `
+        UPDATE item_images
+        SET item_id = ?, position = position + ?, is_primary = is_primary AND ?
+        WHERE item_id = ?
+    `
+	if _, err := r.Execute(ctx, query, toItemID, offset, count == 0, fromItemID); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+func (r *ImageRepository) FindByThumbnailStatus(ctx context.Context, statuses ...string) ([]*entity.ItemImage, error) {
+	if len(statuses) == 0 {
+		return []*entity.ItemImage{}, nil
+	}
+	placeholders := make([]string, len(statuses))
+	args := make([]interface{}, len(statuses))
+	for i, status := range statuses {
+		placeholders[i] = "?"
+		args[i] = status
+	}
+
+	query := `
+        SELECT id, item_id, filename, content_type, size, blob_key, position, is_primary, thumbnail_status, created_at
+        FROM item_images
+        WHERE thumbnail_status IN (` + strings.Join(placeholders, ", ") + `)
+        ORDER BY id
+    `
+	return r.findAll(ctx, query, args...)
+}
+
+func (r *ImageRepository) UpdateThumbnailStatus(ctx context.Context, id int64, status string) error {
+	result, err := r.Execute(ctx, `UPDATE item_images SET thumbnail_status = ? WHERE id = ?`, status, id)
+	if err != nil {
+		return wrapError(err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%w: failed to get affected rows: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	if affected == 0 {
+		return domainErrors.ErrImageNotFound
+	}
+	return nil
+}
+
+func (r *ImageRepository) findAll(ctx context.Context, query string, args ...interface{}) ([]*entity.ItemImage, error) {
+	rows, err := r.Query(ctx, query, args...)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer rows.Close()
+
+	images := []*entity.ItemImage{}
+	for rows.Next() {
+		image, err := scanImage(rows)
+		if err != nil {
+			return nil, wrapError(err)
+		}
+		images = append(images, image)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, wrapError(err)
+	}
+	return images, nil
+}
+
+func (r *ImageRepository) Delete(ctx context.Context, id int64) error {
+	result, err := r.Execute(ctx, `DELETE FROM item_images WHERE id = ?`, id)
+	if err != nil {
+		return wrapError(err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%w: failed to get affected rows: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	if affected == 0 {
+		return domainErrors.ErrImageNotFound
+	}
+	return nil
+}
+
+func scanImage(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*entity.ItemImage, error) {
+	var image entity.ItemImage
+	err := scanner.Scan(
+		&image.ID,
+		&image.ItemID,
+		&image.Filename,
+		&image.ContentType,
+		&image.Size,
+		&image.Key,
+		&image.Position,
+		&image.IsPrimary,
+		&image.ThumbnailStatus,
+		&image.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &image, nil
+}
@@ -0,0 +1,135 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+type ImpersonationRepository struct {
+	SqlHandler
+}
+
+const impersonationColumns = `id, token_hash, admin_id, user_id, reason, created_at, expires_at, ended_at`
+
+func (r *ImpersonationRepository) Create(ctx context.Context, session *entity.ImpersonationSession) error {
+	query := `
+        INSERT INTO impersonation_sessions (token_hash, admin_id, user_id, reason, created_at, expires_at)
+        VALUES (?, ?, ?, ?, ?, ?)
+    `
+
+	result, err := r.Execute(ctx, query,
+		session.TokenHash,
+		session.AdminID,
+		session.UserID,
+		session.Reason,
+		session.CreatedAt,
+		session.ExpiresAt,
+	)
+	if err != nil {
+		return wrapError(err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("%w: failed to get last insert id: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	session.ID = id
+
+	return nil
+}
+
+func (r *ImpersonationRepository) FindByID(ctx context.Context, id int64) (*entity.ImpersonationSession, error) {
+	query := `SELECT ` + impersonationColumns + ` FROM impersonation_sessions WHERE id = ?`
+	return r.findOne(ctx, query, id)
+}
+
+func (r *ImpersonationRepository) FindByTokenHash(ctx context.Context, tokenHash string) (*entity.ImpersonationSession, error) {
+	query := `SELECT ` + impersonationColumns + ` FROM impersonation_sessions WHERE token_hash = ?`
+	return r.findOne(ctx, query, tokenHash)
+}
+
+func (r *ImpersonationRepository) findOne(ctx context.Context, query string, arg interface{}) (*entity.ImpersonationSession, error) {
+	session, err := scanImpersonationSession(r.QueryRow(ctx, query, arg))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domainErrors.ErrImpersonationNotFound
+		}
+		return nil, wrapError(err)
+	}
+
+	return session, nil
+}
+
+func (r *ImpersonationRepository) FindAll(ctx context.Context) ([]*entity.ImpersonationSession, error) {
+	query := `SELECT ` + impersonationColumns + ` FROM impersonation_sessions ORDER BY created_at DESC, id DESC`
+
+	rows, err := r.Query(ctx, query)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer rows.Close()
+
+	sessions := []*entity.ImpersonationSession{}
+	for rows.Next() {
+		session, err := scanImpersonationSession(rows)
+		if err != nil {
+			return nil, wrapError(err)
+		}
+		sessions = append(sessions, session)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, wrapError(err)
+	}
+
+	return sessions, nil
+}
+
+func (r *ImpersonationRepository) End(ctx context.Context, id int64, at time.Time) error {
+	result, err := r.Execute(ctx, `UPDATE impersonation_sessions SET ended_at = ? WHERE id = ?`, at, id)
+	if err != nil {
+		return wrapError(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%w: failed to get rows affected: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	if rowsAffected == 0 {
+		return domainErrors.ErrImpersonationNotFound
+	}
+
+	return nil
+}
+
+func scanImpersonationSession(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*entity.ImpersonationSession, error) {
+	var session entity.ImpersonationSession
+	var endedAt sql.NullTime
+
+	err := scanner.Scan(
+		&session.ID,
+		&session.TokenHash,
+		&session.AdminID,
+		&session.UserID,
+		&session.Reason,
+		&session.CreatedAt,
+		&session.ExpiresAt,
+		&endedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if endedAt.Valid {
+		session.EndedAt = &endedAt.Time
+	}
+
+	return &session, nil
+}
@@ -0,0 +1,153 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+type ImportProfileRepository struct {
+	SqlHandler
+}
+
+const importProfileColumns = `id, owner_id, name, column_map, date_format, default_values, created_at, updated_at`
+
+func (r *ImportProfileRepository) FindByOwner(ctx context.Context, ownerID int64) ([]*entity.ImportProfile, error) {
+	query := `SELECT ` + importProfileColumns + ` FROM import_profiles WHERE owner_id = ? ORDER BY name, id`
+
+	rows, err := r.Query(ctx, query, ownerID)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer rows.Close()
+
+	profiles := []*entity.ImportProfile{}
+	for rows.Next() {
+		profile, err := scanImportProfile(rows)
+		if err != nil {
+			return nil, wrapError(err)
+		}
+		profiles = append(profiles, profile)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, wrapError(err)
+	}
+
+	return profiles, nil
+}
+
+func (r *ImportProfileRepository) FindByID(ctx context.Context, id int64) (*entity.ImportProfile, error) {
+	query := `SELECT ` + importProfileColumns + ` FROM import_profiles WHERE id = ?`
+
+	profile, err := scanImportProfile(r.QueryRow(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domainErrors.ErrImportProfileNotFound
+		}
+		return nil, wrapError(err)
+	}
+
+	return profile, nil
+}
+
+func (r *ImportProfileRepository) Create(ctx context.Context, profile *entity.ImportProfile) error {
+	columns, defaults, err := encodeImportProfile(profile)
+	if err != nil {
+		return err
+	}
+
+	query := `
+        INSERT INTO import_profiles (owner_id, name, column_map, date_format, default_values, created_at, updated_at)
+        VALUES (?, ?, ?, ?, ?, ?, ?)
+    `
+	result, err := r.Execute(ctx, query, profile.OwnerID, profile.Name, columns, profile.DateFormat, defaults, profile.CreatedAt, profile.UpdatedAt)
+	if err != nil {
+		return wrapError(err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("%w: failed to get last insert id: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	profile.ID = id
+
+	return nil
+}
+
+func (r *ImportProfileRepository) Update(ctx context.Context, profile *entity.ImportProfile) error {
+	columns, defaults, err := encodeImportProfile(profile)
+	if err != nil {
+		return err
+	}
+
+	query := `UPDATE import_profiles SET name = ?, column_map = ?, date_format = ?, default_values = ?, updated_at = ? WHERE id = ?`
+	return r.execute(ctx, query, profile.Name, columns, profile.DateFormat, defaults, profile.UpdatedAt, profile.ID)
+}
+
+func (r *ImportProfileRepository) Delete(ctx context.Context, id int64) error {
+	return r.execute(ctx, `DELETE FROM import_profiles WHERE id = ?`, id)
+}
+
+func (r *ImportProfileRepository) execute(ctx context.Context, query string, args ...interface{}) error {
+	result, err := r.Execute(ctx, query, args...)
+	if err != nil {
+		return wrapError(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%w: failed to get rows affected: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	if rowsAffected == 0 {
+		return domainErrors.ErrImportProfileNotFound
+	}
+
+	return nil
+}
+
+func encodeImportProfile(profile *entity.ImportProfile) (columns, defaults string, err error) {
+	encodedColumns, err := json.Marshal(profile.Columns)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to encode columns: %w", err)
+	}
+	encodedDefaults, err := json.Marshal(profile.Defaults)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to encode defaults: %w", err)
+	}
+	return string(encodedColumns), string(encodedDefaults), nil
+}
+
+func scanImportProfile(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*entity.ImportProfile, error) {
+	var profile entity.ImportProfile
+	var columns, defaults string
+
+	err := scanner.Scan(
+		&profile.ID,
+		&profile.OwnerID,
+		&profile.Name,
+		&columns,
+		&profile.DateFormat,
+		&defaults,
+		&profile.CreatedAt,
+		&profile.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(columns), &profile.Columns); err != nil {
+		return nil, fmt.Errorf("failed to decode columns of import profile %d: %w", profile.ID, err)
+	}
+	if err := json.Unmarshal([]byte(defaults), &profile.Defaults); err != nil {
+		return nil, fmt.Errorf("failed to decode defaults of import profile %d: %w", profile.ID, err)
+	}
+
+	return &profile, nil
+}
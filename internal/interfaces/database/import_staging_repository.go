@@ -0,0 +1,276 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+type ImportStagingRepository struct {
+	SqlHandler
+}
+
+// 1 文で挿入する行の数
+const stagedRowInsertBatch = 500
+
+const importBatchColumns = `id, owner_id, filename, profile_id, status, row_count, invalid_rows, created_count, created_at, updated_at, expires_at, committed_at`
+
+const stagedRowColumns = `batch_id, row_num, name, category, brand, purchase_price, purchase_date, organization_id, read_errors, errors, updated_at`
+
+func (r *ImportStagingRepository) CreateBatch(ctx context.Context, batch *entity.ImportBatch, rows []*entity.StagedImportRow) error {
+	query := `
+        INSERT INTO import_batches (owner_id, filename, profile_id, status, row_count, invalid_rows, created_count, created_at, updated_at, expires_at)
+        VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+    `
+	result, err := r.Execute(ctx, query, batch.OwnerID, batch.Filename, batch.ProfileID, batch.Status, batch.Rows, batch.InvalidRows, batch.Created, batch.CreatedAt, batch.UpdatedAt, batch.ExpiresAt)
+	if err != nil {
+		return wrapError(err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("%w: failed to get last insert id: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	batch.ID = id
+
+	for start := 0; start < len(rows); start += stagedRowInsertBatch {
+		end := min(start+stagedRowInsertBatch, len(rows))
+		if err := r.insertRows(ctx, id, rows[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *ImportStagingRepository) insertRows(ctx context.Context, batchID int64, rows []*entity.StagedImportRow) error {
+	placeholders := make([]string, len(rows))
+	args := make([]interface{}, 0, len(rows)*11)
+	for i, row := range rows {
+		row.BatchID = batchID
+		readErrors, problems, err := encodeRowErrors(row)
+		if err != nil {
+			return err
+		}
+		placeholders[i] = "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+		args = append(args, batchID, row.Row, row.Name, row.Category, row.Brand, row.PurchasePrice, row.PurchaseDate, row.OrgID, readErrors, problems, row.UpdatedAt)
+	}
+
+	query := `INSERT INTO import_batch_rows (` + stagedRowColumns + `) VALUES ` + strings.Join(placeholders, ", ")
+	if _, err := r.Execute(ctx, query, args...); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+func (r *ImportStagingRepository) FindBatch(ctx context.Context, id int64) (*entity.ImportBatch, error) {
+	query := `SELECT ` + importBatchColumns + ` FROM import_batches WHERE id = ?`
+
+	var batch entity.ImportBatch
+	var profileID sql.NullInt64
+	var committedAt sql.NullTime
+	err := r.QueryRow(ctx, query, id).Scan(
+		&batch.ID,
+		&batch.OwnerID,
+		&batch.Filename,
+		&profileID,
+		&batch.Status,
+		&batch.Rows,
+		&batch.InvalidRows,
+		&batch.Created,
+		&batch.CreatedAt,
+		&batch.UpdatedAt,
+		&batch.ExpiresAt,
+		&committedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domainErrors.ErrImportBatchNotFound
+		}
+		return nil, wrapError(err)
+	}
+	if profileID.Valid {
+		batch.ProfileID = &profileID.Int64
+	}
+	if committedAt.Valid {
+		batch.CommittedAt = &committedAt.Time
+	}
+
+	return &batch, nil
+}
+
+func (r *ImportStagingRepository) UpdateBatch(ctx context.Context, batch *entity.ImportBatch) error {
+	query := `
+        UPDATE import_batches
+        SET status = ?, row_count = ?, invalid_rows = ?, created_count = ?, updated_at = ?, expires_at = ?, committed_at = ?
+        WHERE id = ?
+    `
+	if _, err := r.Execute(ctx, query, batch.Status, batch.Rows, batch.InvalidRows, batch.Created, batch.UpdatedAt, batch.ExpiresAt, batch.CommittedAt, batch.ID); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+func (r *ImportStagingRepository) FindRows(ctx context.Context, batchID int64) ([]*entity.StagedImportRow, error) {
+	query := `SELECT ` + stagedRowColumns + ` FROM import_batch_rows WHERE batch_id = ? ORDER BY row_num`
+
+	rows, err := r.Query(ctx, query, batchID)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer rows.Close()
+
+	staged := []*entity.StagedImportRow{}
+	for rows.Next() {
+		row, err := scanStagedRow(rows)
+		if err != nil {
+			return nil, wrapError(err)
+		}
+		staged = append(staged, row)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, wrapError(err)
+	}
+
+	return staged, nil
+}
+
+func (r *ImportStagingRepository) FindRow(ctx context.Context, batchID int64, row int) (*entity.StagedImportRow, error) {
+	query := `SELECT ` + stagedRowColumns + ` FROM import_batch_rows WHERE batch_id = ? AND row_num = ?`
+
+	staged, err := scanStagedRow(r.QueryRow(ctx, query, batchID, row))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domainErrors.ErrStagedRowNotFound
+		}
+		return nil, wrapError(err)
+	}
+
+	return staged, nil
+}
+
+func (r *ImportStagingRepository) UpdateRow(ctx context.Context, row *entity.StagedImportRow) error {
+	readErrors, problems, err := encodeRowErrors(row)
+	if err != nil {
+		return err
+	}
+
+	query := `
+        UPDATE import_batch_rows
+        SET name = ?, category = ?, brand = ?, purchase_price = ?, purchase_date = ?, organization_id = ?, read_errors = ?, errors = ?, updated_at = ?
+        WHERE batch_id = ? AND row_num = ?
+    `
+	if _, err := r.Execute(ctx, query, row.Name, row.Category, row.Brand, row.PurchasePrice, row.PurchaseDate, row.OrgID, readErrors, problems, row.UpdatedAt, row.BatchID, row.Row); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+func (r *ImportStagingRepository) DeleteRows(ctx context.Context, batchID int64, rows ...int) error {
+	query := `DELETE FROM import_batch_rows WHERE batch_id = ?`
+	args := []interface{}{batchID}
+	if len(rows) > 0 {
+		placeholders := make([]string, len(rows))
+		for i, row := range rows {
+			placeholders[i] = "?"
+			args = append(args, row)
+		}
+		query += ` AND row_num IN (` + strings.Join(placeholders, ", ") + `)`
+	}
+
+	if _, err := r.Execute(ctx, query, args...); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+func (r *ImportStagingRepository) DeleteBatch(ctx context.Context, id int64) error {
+	if err := r.DeleteRows(ctx, id); err != nil {
+		return err
+	}
+	result, err := r.Execute(ctx, `DELETE FROM import_batches WHERE id = ?`, id)
+	if err != nil {
+		return wrapError(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%w: failed to get rows affected: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	if rowsAffected == 0 {
+		return domainErrors.ErrImportBatchNotFound
+	}
+	return nil
+}
+
+func (r *ImportStagingRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	query := `DELETE FROM import_batch_rows WHERE batch_id IN (SELECT id FROM import_batches WHERE expires_at <= ?)`
+	if _, err := r.Execute(ctx, query, before); err != nil {
+		return 0, wrapError(err)
+	}
+
+	result, err := r.Execute(ctx, `DELETE FROM import_batches WHERE expires_at <= ?`, before)
+	if err != nil {
+		return 0, wrapError(err)
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%w: failed to get rows affected: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	return deleted, nil
+}
+
+func encodeRowErrors(row *entity.StagedImportRow) (readErrors, problems string, err error) {
+	encodedRead, err := json.Marshal(row.ReadErrors)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to encode read errors: %w", err)
+	}
+	encodedProblems, err := json.Marshal(row.Errors)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to encode errors: %w", err)
+	}
+	return string(encodedRead), string(encodedProblems), nil
+}
+
+func scanStagedRow(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*entity.StagedImportRow, error) {
+	var row entity.StagedImportRow
+	var orgID sql.NullInt64
+	var readErrors, problems string
+
+	err := scanner.Scan(
+		&row.BatchID,
+		&row.Row,
+		&row.Name,
+		&row.Category,
+		&row.Brand,
+		&row.PurchasePrice,
+		&row.PurchaseDate,
+		&orgID,
+		&readErrors,
+		&problems,
+		&row.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if orgID.Valid {
+		row.OrgID = &orgID.Int64
+	}
+
+	if err := json.Unmarshal([]byte(readErrors), &row.ReadErrors); err != nil {
+		return nil, fmt.Errorf("failed to decode read errors of row %d: %w", row.Row, err)
+	}
+	if err := json.Unmarshal([]byte(problems), &row.Errors); err != nil {
+		return nil, fmt.Errorf("failed to decode errors of row %d: %w", row.Row, err)
+	}
+
+	return &row, nil
+}
@@ -0,0 +1,137 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+type InvitationRepository struct {
+	SqlHandler
+}
+
+const invitationColumns = `id, organization_id, email, role, token_hash, invited_by, created_at, expires_at, accepted_at, accepted_by`
+
+func (r *InvitationRepository) Create(ctx context.Context, invitation *entity.Invitation) error {
+	query := `
+        INSERT INTO organization_invitations (organization_id, email, role, token_hash, invited_by, created_at, expires_at)
+        VALUES (?, ?, ?, ?, ?, ?, ?)
+    `
+
+	result, err := r.Execute(ctx, query,
+		invitation.OrgID,
+		invitation.Email,
+		invitation.Role,
+		invitation.TokenHash,
+		invitation.InvitedBy,
+		invitation.CreatedAt,
+		invitation.ExpiresAt,
+	)
+	if err != nil {
+		return wrapError(err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("%w: failed to get last insert id: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	invitation.ID = id
+
+	return nil
+}
+
+func (r *InvitationRepository) FindByTokenHash(ctx context.Context, tokenHash string) (*entity.Invitation, error) {
+	query := `SELECT ` + invitationColumns + ` FROM organization_invitations WHERE token_hash = ?`
+
+	invitation, err := scanInvitation(r.QueryRow(ctx, query, tokenHash))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domainErrors.ErrInvitationNotFound
+		}
+		return nil, wrapError(err)
+	}
+
+	return invitation, nil
+}
+
+func (r *InvitationRepository) FindByOrgID(ctx context.Context, orgID int64) ([]*entity.Invitation, error) {
+	query := `SELECT ` + invitationColumns + ` FROM organization_invitations WHERE organization_id = ? ORDER BY created_at DESC, id DESC`
+
+	rows, err := r.Query(ctx, query, orgID)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer rows.Close()
+
+	invitations := []*entity.Invitation{}
+	for rows.Next() {
+		invitation, err := scanInvitation(rows)
+		if err != nil {
+			return nil, wrapError(err)
+		}
+		invitations = append(invitations, invitation)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, wrapError(err)
+	}
+
+	return invitations, nil
+}
+
+// 同じ招待を二重に承諾できないよう、未承諾の場合だけ更新する
+func (r *InvitationRepository) MarkAccepted(ctx context.Context, id, userID int64, at time.Time) error {
+	query := `UPDATE organization_invitations SET accepted_at = ?, accepted_by = ? WHERE id = ? AND accepted_at IS NULL`
+
+	result, err := r.Execute(ctx, query, at, userID, id)
+	if err != nil {
+		return wrapError(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%w: failed to get rows affected: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	if rowsAffected == 0 {
+		return domainErrors.ErrInvitationNotFound
+	}
+
+	return nil
+}
+
+func scanInvitation(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*entity.Invitation, error) {
+	var invitation entity.Invitation
+	var acceptedAt sql.NullTime
+	var acceptedBy sql.NullInt64
+
+	err := scanner.Scan(
+		&invitation.ID,
+		&invitation.OrgID,
+		&invitation.Email,
+		&invitation.Role,
+		&invitation.TokenHash,
+		&invitation.InvitedBy,
+		&invitation.CreatedAt,
+		&invitation.ExpiresAt,
+		&acceptedAt,
+		&acceptedBy,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if acceptedAt.Valid {
+		invitation.AcceptedAt = &acceptedAt.Time
+	}
+	if acceptedBy.Valid {
+		invitation.AcceptedBy = &acceptedBy.Int64
+	}
+
+	return &invitation, nil
+}
@@ -0,0 +1,90 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+type ItemVerificationRepository struct {
+	SqlHandler
+}
+
+func (r *ItemVerificationRepository) Find(ctx context.Context, itemID int64) (*entity.ItemVerification, error) {
+	query := `
+        SELECT item_id, status, appraiser_id, notes, certificate_id, locked_fields, started_at, verified_at, updated_at
+        FROM item_verifications
+        WHERE item_id = ?
+    `
+
+	var v entity.ItemVerification
+	var appraiserID, certificateID sql.NullInt64
+	var lockedFields []byte
+	var startedAt, verifiedAt sql.NullTime
+	var updatedAt time.Time
+	err := r.QueryRow(ctx, query, itemID).Scan(
+		&v.ItemID,
+		&v.Status,
+		&appraiserID,
+		&v.Notes,
+		&certificateID,
+		&lockedFields,
+		&startedAt,
+		&verifiedAt,
+		&updatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return entity.NewItemVerification(itemID), nil
+		}
+		return nil, wrapError(err)
+	}
+
+	if err := json.Unmarshal(lockedFields, &v.LockedFields); err != nil {
+		return nil, fmt.Errorf("%w: failed to decode locked fields: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	if v.LockedFields == nil {
+		v.LockedFields = []string{}
+	}
+	if appraiserID.Valid {
+		v.AppraiserID = &appraiserID.Int64
+	}
+	if certificateID.Valid {
+		v.CertificateID = &certificateID.Int64
+	}
+	if startedAt.Valid {
+		v.StartedAt = &startedAt.Time
+	}
+	if verifiedAt.Valid {
+		v.VerifiedAt = &verifiedAt.Time
+	}
+	v.UpdatedAt = &updatedAt
+
+	return &v, nil
+}
+
+func (r *ItemVerificationRepository) Save(ctx context.Context, v *entity.ItemVerification) error {
+	lockedFields, err := json.Marshal(v.LockedFields)
+	if err != nil {
+		return fmt.Errorf("%w: failed to encode locked fields: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+
+	query := `
+        INSERT INTO item_verifications (item_id, status, appraiser_id, notes, certificate_id, locked_fields, started_at, verified_at, updated_at)
+        VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+        ON DUPLICATE KEY UPDATE
+            status = VALUES(status), appraiser_id = VALUES(appraiser_id), notes = VALUES(notes),
+            certificate_id = VALUES(certificate_id), locked_fields = VALUES(locked_fields),
+            started_at = VALUES(started_at), verified_at = VALUES(verified_at), updated_at = VALUES(updated_at)
+    `
+	_, err = r.Execute(ctx, query, v.ItemID, v.Status, v.AppraiserID, v.Notes, v.CertificateID, lockedFields, v.StartedAt, v.VerifiedAt, v.UpdatedAt)
+	if err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 
 	"Aicon-assignment/internal/domain/entity"
@@ -16,14 +17,15 @@ type ItemRepository struct {
 
 func (r *ItemRepository) FindAll(ctx context.Context) ([]*entity.Item, error) {
 	query := `
-        SELECT id, name, category, brand, purchase_price, purchase_date, created_at, updated_at
+        SELECT id, name, category, brand, purchase_price, purchase_date, created_at, updated_at, organization_id, owner_id
         FROM items
-        ORDER BY created_at DESC
-    `
+        WHERE deleted_at IS NULL`
+	scope, args := ownerScope(ctx)
+	query += scope + " ORDER BY created_at DESC"
 
-	rows, err := r.Query(ctx, query)
+	rows, err := r.Query(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+		return nil, wrapError(err)
 	}
 	defer rows.Close()
 
@@ -31,33 +33,189 @@ func (r *ItemRepository) FindAll(ctx context.Context) ([]*entity.Item, error) {
 	for rows.Next() {
 		item, err := scanItem(rows)
 		if err != nil {
-			return nil, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+			return nil, wrapError(err)
+		}
+		items = append(items, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, wrapError(err)
+	}
+
+	return items, nil
+}
+
+func (r *ItemRepository) FindByOrganization(ctx context.Context, orgID int64) ([]*entity.Item, error) {
+	query := `
+        SELECT id, name, category, brand, purchase_price, purchase_date, created_at, updated_at, organization_id, owner_id
+        FROM items
+        WHERE deleted_at IS NULL AND organization_id = ?`
+	scope, scopeArgs := ownerScope(ctx)
+	query += scope + " ORDER BY id"
+
+	rows, err := r.Query(ctx, query, append([]any{orgID}, scopeArgs...)...)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer rows.Close()
+
+	items := []*entity.Item{}
+	for rows.Next() {
+		item, err := scanItem(rows)
+		if err != nil {
+			return nil, wrapError(err)
 		}
 		items = append(items, item)
 	}
 
 	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+		return nil, wrapError(err)
 	}
 
 	return items, nil
 }
 
+func (r *ItemRepository) FindByQuery(ctx context.Context, q entity.ItemQuery) ([]*entity.Item, error) {
+	where, args, err := itemWhere(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	orderBy, err := compileSort(q.Sort, itemSortColumns)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrInvalidInput, err.Error())
+	}
+
+	query := `
+        SELECT id, name, category, brand, purchase_price, purchase_date, created_at, updated_at, organization_id, owner_id
+        FROM items
+    ` + where + orderBy
+	if q.Limit > 0 {
+		query += " LIMIT ? OFFSET ?"
+		args = append(args, q.Limit, q.Offset)
+	}
+
+	rows, err := r.Query(ctx, query, args...)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer rows.Close()
+
+	items := []*entity.Item{}
+	for rows.Next() {
+		item, err := scanItem(rows)
+		if err != nil {
+			return nil, wrapError(err)
+		}
+		items = append(items, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, wrapError(err)
+	}
+
+	return items, nil
+}
+
+func (r *ItemRepository) CountByQuery(ctx context.Context, q entity.ItemQuery) (int, error) {
+	where, args, err := itemWhere(ctx, q)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	if err := r.QueryRow(ctx, `SELECT COUNT(*) FROM items`+where, args...).Scan(&count); err != nil {
+		return 0, wrapError(err)
+	}
+	return count, nil
+}
+
+// 照合順序（utf8mb4_unicode_ci）により大文字小文字を区別せずに部分一致で検索する
+func (r *ItemRepository) Search(ctx context.Context, search entity.ItemSearch) ([]*entity.Item, error) {
+	query := `
+        SELECT id, name, category, brand, purchase_price, purchase_date, created_at, updated_at, organization_id, owner_id
+        FROM items
+        WHERE deleted_at IS NULL AND (name LIKE ? OR brand LIKE ?)`
+	pattern := "%" + escapeLike(search.Keyword) + "%"
+	scope, scopeArgs := ownerScope(ctx)
+	query += scope + " ORDER BY created_at DESC, id DESC LIMIT ?"
+	args := append([]any{pattern, pattern}, scopeArgs...)
+
+	rows, err := r.Query(ctx, query, append(args, search.Limit)...)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer rows.Close()
+
+	items := []*entity.Item{}
+	for rows.Next() {
+		item, err := scanItem(rows)
+		if err != nil {
+			return nil, wrapError(err)
+		}
+		items = append(items, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, wrapError(err)
+	}
+
+	return items, nil
+}
+
+// 統合済み（論理削除済み）のアイテムと、呼び出し元以外のユーザーのアイテムは常に除く
+// カーソルの位置は idx_created_at（InnoDB では主キーの id を含む）を使えるよう展開して比較する
+func itemWhere(ctx context.Context, q entity.ItemQuery) (string, []interface{}, error) {
+	scope, args := ownerScope(ctx)
+	where := " WHERE deleted_at IS NULL" + scope
+	if q.Filter != nil {
+		condition, filterArgs, err := compileFilter(q.Filter, itemFilterColumns)
+		if err != nil {
+			return "", nil, fmt.Errorf("%w: %s", domainErrors.ErrInvalidInput, err.Error())
+		}
+		where += " AND " + condition
+		args = append(args, filterArgs...)
+	}
+	if q.After != nil {
+		where += " AND (created_at < ? OR (created_at = ? AND id < ?))"
+		args = append(args, q.After.CreatedAt, q.After.CreatedAt, q.After.ID)
+	}
+	return where, args, nil
+}
+
 func (r *ItemRepository) FindByID(ctx context.Context, id int64) (*entity.Item, error) {
 	query := `
-        SELECT id, name, category, brand, purchase_price, purchase_date, created_at, updated_at
+        SELECT id, name, category, brand, purchase_price, purchase_date, created_at, updated_at, organization_id, owner_id
         FROM items
-        WHERE id = ?
-    `
+        WHERE id = ? AND deleted_at IS NULL`
+	scope, args := ownerScope(ctx)
 
-	row := r.QueryRow(ctx, query, id)
+	row := r.QueryRow(ctx, query+scope, append([]any{id}, args...)...)
 
 	item, err := scanItem(row)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, domainErrors.ErrItemNotFound
 		}
-		return nil, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+		return nil, wrapError(err)
+	}
+
+	return item, nil
+}
+
+// 論理削除したアイテムも返す（完全に削除したアイテムは返さない）
+func (r *ItemRepository) FindByIDIncludingDeleted(ctx context.Context, id int64) (*entity.Item, error) {
+	query := `
+        SELECT id, name, category, brand, purchase_price, purchase_date, created_at, updated_at, organization_id, owner_id
+        FROM items
+        WHERE id = ?`
+	scope, args := ownerScope(ctx)
+
+	item, err := scanItem(r.QueryRow(ctx, query+scope, append([]any{id}, args...)...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domainErrors.ErrItemNotFound
+		}
+		return nil, wrapError(err)
 	}
 
 	return item, nil
@@ -65,8 +223,8 @@ func (r *ItemRepository) FindByID(ctx context.Context, id int64) (*entity.Item,
 
 func (r *ItemRepository) Create(ctx context.Context, item *entity.Item) (*entity.Item, error) {
 	query := `
-        INSERT INTO items (name, category, brand, purchase_price, purchase_date)
-        VALUES (?, ?, ?, ?, ?)
+        INSERT INTO items (name, category, brand, purchase_price, purchase_date, organization_id, owner_id)
+        VALUES (?, ?, ?, ?, ?, ?, ?)
     `
 
 	result, err := r.Execute(ctx, query,
@@ -75,9 +233,11 @@ func (r *ItemRepository) Create(ctx context.Context, item *entity.Item) (*entity
 		item.Brand,
 		item.PurchasePrice,
 		item.PurchaseDate,
+		item.OrgID,
+		ownerOf(ctx, item),
 	)
 	if err != nil {
-		return nil, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+		return nil, wrapError(err)
 	}
 
 	id, err := result.LastInsertId()
@@ -92,10 +252,10 @@ func (r *ItemRepository) Update(ctx context.Context, item *entity.Item) (*entity
 	query := `
         UPDATE items 
         SET name = ?, category = ?, brand = ?, purchase_price = ?, purchase_date = ?, updated_at = ?
-        WHERE id = ?
-    `
+        WHERE id = ? AND deleted_at IS NULL`
+	scope, args := ownerScope(ctx)
 
-	result, err := r.Execute(ctx, query,
+	result, err := r.Execute(ctx, query+scope, append([]any{
 		item.Name,
 		item.Category,
 		item.Brand,
@@ -103,9 +263,9 @@ func (r *ItemRepository) Update(ctx context.Context, item *entity.Item) (*entity
 		item.PurchaseDate,
 		item.UpdatedAt,
 		item.ID,
-	)
+	}, args...)...)
 	if err != nil {
-		return nil, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+		return nil, wrapError(err)
 	}
 
 	rowsAffected, err := result.RowsAffected()
@@ -120,12 +280,172 @@ func (r *ItemRepository) Update(ctx context.Context, item *entity.Item) (*entity
 	return r.FindByID(ctx, item.ID)
 }
 
-func (r *ItemRepository) Delete(ctx context.Context, id int64) error {
-	query := `DELETE FROM items WHERE id = ?`
+func (r *ItemRepository) RecordPriceChange(ctx context.Context, change *entity.PriceChange) error {
+	query := `
+        INSERT INTO item_price_history (item_id, old_price, new_price, actor, reason, changed_at)
+        VALUES (?, ?, ?, ?, ?, ?)
+    `
 
-	result, err := r.Execute(ctx, query, id)
+	result, err := r.Execute(ctx, query,
+		change.ItemID,
+		change.OldPrice,
+		change.NewPrice,
+		change.Actor,
+		change.Reason,
+		change.ChangedAt,
+	)
 	if err != nil {
-		return fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+		return wrapError(err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("%w: failed to get last insert id: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	change.ID = id
+
+	return nil
+}
+
+func (r *ItemRepository) FindPriceHistory(ctx context.Context, itemID int64) ([]*entity.PriceChange, error) {
+	query := `
+        SELECT id, item_id, old_price, new_price, actor, reason, changed_at
+        FROM item_price_history
+        WHERE item_id = ?
+        ORDER BY changed_at ASC, id ASC
+    `
+
+	rows, err := r.Query(ctx, query, itemID)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer rows.Close()
+
+	history := []*entity.PriceChange{}
+	for rows.Next() {
+		var change entity.PriceChange
+		if err := rows.Scan(
+			&change.ID,
+			&change.ItemID,
+			&change.OldPrice,
+			&change.NewPrice,
+			&change.Actor,
+			&change.Reason,
+			&change.ChangedAt,
+		); err != nil {
+			return nil, wrapError(err)
+		}
+		history = append(history, &change)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, wrapError(err)
+	}
+
+	return history, nil
+}
+
+// 価格変更履歴を統合先に付け替え、統合元を論理削除する
+// 統合元を参照していた履歴が残るよう、行は削除しない
+func (r *ItemRepository) MergeInto(ctx context.Context, sourceID, targetID int64, at time.Time) error {
+	scope, args := ownerScope(ctx)
+	result, err := r.Execute(ctx, `
+        UPDATE items
+        SET deleted_at = ?, merged_into = ?
+        WHERE id = ? AND deleted_at IS NULL`+scope, append([]any{at, targetID, sourceID}, args...)...)
+	if err != nil {
+		return wrapError(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%w: failed to get rows affected: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	if rowsAffected == 0 {
+		return domainErrors.ErrItemNotFound
+	}
+
+	if _, err := r.Execute(ctx, `UPDATE item_price_history SET item_id = ? WHERE item_id = ?`, targetID, sourceID); err != nil {
+		return wrapError(err)
+	}
+
+	return nil
+}
+
+func (r *ItemRepository) AssignOwner(ctx context.Context, id, ownerID int64) error {
+	scope, args := ownerScope(ctx)
+	result, err := r.Execute(ctx, `UPDATE items SET owner_id = ? WHERE id = ? AND deleted_at IS NULL`+scope, append([]any{ownerID, id}, args...)...)
+	if err != nil {
+		return wrapError(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%w: failed to get rows affected: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	if rowsAffected == 0 {
+		return domainErrors.ErrItemNotFound
+	}
+
+	return nil
+}
+
+func (r *ItemRepository) SoftDelete(ctx context.Context, id int64, at time.Time) error {
+	scope, args := ownerScope(ctx)
+	result, err := r.Execute(ctx, `UPDATE items SET deleted_at = ? WHERE id = ? AND deleted_at IS NULL`+scope, append([]any{at, id}, args...)...)
+	if err != nil {
+		return wrapError(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%w: failed to get rows affected: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	if rowsAffected == 0 {
+		return domainErrors.ErrItemNotFound
+	}
+
+	return nil
+}
+
+func (r *ItemRepository) CountDeletedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	return countBefore(ctx, r.SqlHandler, `SELECT COUNT(*) FROM items WHERE deleted_at IS NOT NULL AND deleted_at < ?`, cutoff)
+}
+
+func (r *ItemRepository) FindIDsDeletedBefore(ctx context.Context, cutoff time.Time) ([]int64, error) {
+	rows, err := r.Query(ctx, `SELECT id FROM items WHERE deleted_at IS NOT NULL AND deleted_at < ?`, cutoff)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, wrapError(err)
+		}
+		ids = append(ids, id)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, wrapError(err)
+	}
+
+	return ids, nil
+}
+
+// 論理削除したアイテムを物理削除する。価格変更履歴も外部キーにより削除される
+func (r *ItemRepository) PurgeDeletedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	return deleteBefore(ctx, r.SqlHandler, `DELETE FROM items WHERE deleted_at IS NOT NULL AND deleted_at < ?`, cutoff)
+}
+
+// 価格変更履歴は ON DELETE CASCADE で削除される
+func (r *ItemRepository) Purge(ctx context.Context, id int64) error {
+	scope, args := ownerScope(ctx)
+	result, err := r.Execute(ctx, `DELETE FROM items WHERE id = ?`+scope, append([]any{id}, args...)...)
+	if err != nil {
+		return wrapError(err)
 	}
 
 	rowsAffected, err := result.RowsAffected()
@@ -140,42 +460,137 @@ func (r *ItemRepository) Delete(ctx context.Context, id int64) error {
 	return nil
 }
 
-func (r *ItemRepository) GetSummaryByCategory(ctx context.Context) (map[string]int, error) {
+// 送信元のアイテムを同じ ID のまま書き込む（複製先で使う）
+func (r *ItemRepository) Put(ctx context.Context, item *entity.Item) error {
 	query := `
-        SELECT category, COUNT(*) as count
-        FROM items
-        GROUP BY category
+        INSERT INTO items (id, name, category, brand, purchase_price, purchase_date, organization_id, owner_id, created_at, updated_at)
+        VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+        ON DUPLICATE KEY UPDATE
+            name = VALUES(name), category = VALUES(category), brand = VALUES(brand),
+            purchase_price = VALUES(purchase_price), purchase_date = VALUES(purchase_date),
+            organization_id = VALUES(organization_id), owner_id = VALUES(owner_id), updated_at = VALUES(updated_at), deleted_at = NULL
     `
 
-	rows, err := r.Query(ctx, query)
+	_, err := r.Execute(ctx, query,
+		item.ID,
+		item.Name,
+		item.Category,
+		item.Brand,
+		item.PurchasePrice,
+		item.PurchaseDate,
+		item.OrgID,
+		item.OwnerID,
+		item.CreatedAt,
+		item.UpdatedAt,
+	)
+	if err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+// 複製先から取り除く。すでにない場合も成功とする
+func (r *ItemRepository) Remove(ctx context.Context, id int64) error {
+	if _, err := r.Execute(ctx, `DELETE FROM items WHERE id = ?`, id); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+// 集計軸ごとのGROUP BY式
+// ユーザー入力をSQLに埋め込まないよう、ここに定義された式のみを使う
+var summaryExpressions = map[entity.SummaryDimension]string{
+	entity.DimensionCategory: "category",
+	entity.DimensionBrand:    "brand",
+	entity.DimensionYear:     "CAST(YEAR(purchase_date) AS CHAR)",
+}
+
+func (r *ItemRepository) GetSummaryBy(ctx context.Context, dim entity.SummaryDimension) (map[string]int, error) {
+	expr, ok := summaryExpressions[dim]
+	if !ok {
+		return nil, fmt.Errorf("%w: unsupported summary dimension: %s", domainErrors.ErrInvalidInput, dim)
+	}
+
+	scope, args := ownerScope(ctx)
+	query := fmt.Sprintf(`
+        SELECT %s AS group_key, COUNT(*) as count
+        FROM items
+        WHERE deleted_at IS NULL%s
+        GROUP BY group_key
+    `, expr, scope)
+
+	rows, err := r.Query(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+		return nil, wrapError(err)
 	}
 	defer rows.Close()
 
 	summary := make(map[string]int)
 	for rows.Next() {
-		var category string
+		var key string
 		var count int
-		if err := rows.Scan(&category, &count); err != nil {
-			return nil, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+		if err := rows.Scan(&key, &count); err != nil {
+			return nil, wrapError(err)
 		}
-		summary[category] = count
+		summary[key] = count
 	}
 
 	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+		return nil, wrapError(err)
 	}
 
 	return summary, nil
 }
 
+func (r *ItemRepository) SummarizeByOrganization(ctx context.Context, orgIDs []int64) (map[int64]*entity.OrganizationItemSummary, error) {
+	summaries := make(map[int64]*entity.OrganizationItemSummary, len(orgIDs))
+	if len(orgIDs) == 0 {
+		return summaries, nil
+	}
+
+	placeholders := make([]string, len(orgIDs))
+	args := make([]any, len(orgIDs))
+	for i, id := range orgIDs {
+		placeholders[i] = "?"
+		args[i] = id
+	}
+	scope, scopeArgs := ownerScope(ctx)
+	args = append(args, scopeArgs...)
+	query := `
+        SELECT organization_id, COUNT(*), COALESCE(SUM(purchase_price), 0)
+        FROM items
+        WHERE deleted_at IS NULL AND organization_id IN (` + strings.Join(placeholders, ", ") + `)` + scope + `
+        GROUP BY organization_id
+    `
+
+	rows, err := r.Query(ctx, query, args...)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var summary entity.OrganizationItemSummary
+		if err := rows.Scan(&summary.OrgID, &summary.ItemCount, &summary.TotalValue); err != nil {
+			return nil, wrapError(err)
+		}
+		summaries[summary.OrgID] = &summary
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, wrapError(err)
+	}
+
+	return summaries, nil
+}
+
 func scanItem(scanner interface {
 	Scan(dest ...interface{}) error
 }) (*entity.Item, error) {
 	var item entity.Item
 	var purchaseDate string
 	var createdAt, updatedAt time.Time
+	var orgID, ownerID sql.NullInt64
 
 	err := scanner.Scan(
 		&item.ID,
@@ -186,10 +601,18 @@ func scanItem(scanner interface {
 		&purchaseDate,
 		&createdAt,
 		&updatedAt,
+		&orgID,
+		&ownerID,
 	)
 	if err != nil {
 		return nil, err
 	}
+	if orgID.Valid {
+		item.OrgID = &orgID.Int64
+	}
+	if ownerID.Valid {
+		item.OwnerID = &ownerID.Int64
+	}
 
 	if purchaseDate != "" {
 		// 複数の日付形式に対応してパース
@@ -0,0 +1,74 @@
+package database
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"Aicon-assignment/internal/pkg/reqctx"
+)
+
+// クエリの所要時間をリクエストのロガー（reqctx.Logger）に残す SqlHandler
+// SlowThreshold 以上かかったクエリは警告、それ以外は debug で残す。値はトークンなどを含むため残さない
+type LoggingSqlHandler struct {
+	SqlHandler
+	SlowThreshold time.Duration // 0 の場合は警告にしない
+}
+
+func NewLoggingSqlHandler(h SqlHandler, slowThreshold time.Duration) *LoggingSqlHandler {
+	return &LoggingSqlHandler{SqlHandler: h, SlowThreshold: slowThreshold}
+}
+
+func (h *LoggingSqlHandler) Execute(ctx context.Context, statement string, args ...interface{}) (Result, error) {
+	start := time.Now()
+	result, err := h.SqlHandler.Execute(ctx, statement, args...)
+	h.log(ctx, statement, start, err)
+	return result, err
+}
+
+// 行を読み終えるまでではなく、最初の応答までの時間を残す
+func (h *LoggingSqlHandler) Query(ctx context.Context, statement string, args ...interface{}) (Rows, error) {
+	start := time.Now()
+	rows, err := h.SqlHandler.Query(ctx, statement, args...)
+	h.log(ctx, statement, start, err)
+	return rows, err
+}
+
+// エラーは Scan で返るため、所要時間だけを残す
+func (h *LoggingSqlHandler) QueryRow(ctx context.Context, statement string, args ...interface{}) Row {
+	start := time.Now()
+	row := h.SqlHandler.QueryRow(ctx, statement, args...)
+	h.log(ctx, statement, start, nil)
+	return row
+}
+
+func (h *LoggingSqlHandler) log(ctx context.Context, statement string, start time.Time, err error) {
+	elapsed := time.Since(start)
+	logger := reqctx.Logger(ctx)
+	level := slog.LevelDebug
+	if h.SlowThreshold > 0 && elapsed >= h.SlowThreshold {
+		level = slog.LevelWarn
+	}
+	if !logger.Enabled(ctx, level) {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("statement", compactStatement(statement)),
+		slog.Float64("duration_ms", float64(elapsed.Microseconds())/1000),
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	message := "query"
+	if level == slog.LevelWarn {
+		message = "slow query"
+	}
+	logger.LogAttrs(ctx, level, message, attrs...)
+}
+
+// 改行やインデントを詰めて 1 行にする
+func compactStatement(statement string) string {
+	return strings.Join(strings.Fields(statement), " ")
+}
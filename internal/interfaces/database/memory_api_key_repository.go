@@ -0,0 +1,114 @@
+package database
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+// 開発・テスト用のインメモリ API キー
+type MemoryAPIKeyRepository struct {
+	mu     sync.RWMutex
+	keys   []*entity.APIKey
+	lastID int64
+}
+
+func NewMemoryAPIKeyRepository() *MemoryAPIKeyRepository {
+	return &MemoryAPIKeyRepository{}
+}
+
+func (r *MemoryAPIKeyRepository) Create(ctx context.Context, key *entity.APIKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.keys {
+		if existing.KeyHash == key.KeyHash {
+			return domainErrors.ErrDuplicateEntry
+		}
+	}
+
+	r.lastID++
+	key.ID = r.lastID
+	r.keys = append(r.keys, copyAPIKey(key))
+
+	return nil
+}
+
+func (r *MemoryAPIKeyRepository) FindByID(ctx context.Context, id int64) (*entity.APIKey, error) {
+	return r.find(func(key *entity.APIKey) bool { return key.ID == id })
+}
+
+func (r *MemoryAPIKeyRepository) FindByKeyHash(ctx context.Context, keyHash string) (*entity.APIKey, error) {
+	return r.find(func(key *entity.APIKey) bool { return key.KeyHash == keyHash })
+}
+
+func (r *MemoryAPIKeyRepository) find(match func(*entity.APIKey) bool) (*entity.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, key := range r.keys {
+		if match(key) {
+			return copyAPIKey(key), nil
+		}
+	}
+	return nil, domainErrors.ErrAPIKeyNotFound
+}
+
+func (r *MemoryAPIKeyRepository) FindByUser(ctx context.Context, userID int64) ([]*entity.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	keys := []*entity.APIKey{}
+	for _, key := range r.keys {
+		if key.UserID == userID {
+			keys = append(keys, copyAPIKey(key))
+		}
+	}
+	sort.SliceStable(keys, func(i, j int) bool {
+		if !keys[i].CreatedAt.Equal(keys[j].CreatedAt) {
+			return keys[i].CreatedAt.After(keys[j].CreatedAt)
+		}
+		return keys[i].ID > keys[j].ID
+	})
+	return keys, nil
+}
+
+func (r *MemoryAPIKeyRepository) Revoke(ctx context.Context, id int64, at time.Time) error {
+	return r.update(id, func(key *entity.APIKey) { key.RevokedAt = &at })
+}
+
+func (r *MemoryAPIKeyRepository) TouchLastUsed(ctx context.Context, id int64, at time.Time) error {
+	return r.update(id, func(key *entity.APIKey) { key.LastUsedAt = &at })
+}
+
+func (r *MemoryAPIKeyRepository) update(id int64, apply func(*entity.APIKey)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, key := range r.keys {
+		if key.ID == id {
+			apply(key)
+			return nil
+		}
+	}
+	return domainErrors.ErrAPIKeyNotFound
+}
+
+// キーは発行時にだけ返すため、保存するコピーからは取り除く
+func copyAPIKey(key *entity.APIKey) *entity.APIKey {
+	copied := *key
+	copied.Key = ""
+	if key.LastUsedAt != nil {
+		lastUsedAt := *key.LastUsedAt
+		copied.LastUsedAt = &lastUsedAt
+	}
+	if key.RevokedAt != nil {
+		revokedAt := *key.RevokedAt
+		copied.RevokedAt = &revokedAt
+	}
+	return &copied
+}
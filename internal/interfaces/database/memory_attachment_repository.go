@@ -0,0 +1,230 @@
+package database
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+// 開発・テスト用のインメモリの添付ファイルの記録
+type MemoryAttachmentRepository struct {
+	mu          sync.RWMutex
+	lastID      int64
+	attachments map[int64]entity.Attachment
+	contents    map[string]entity.AttachmentContent
+}
+
+func NewMemoryAttachmentRepository() *MemoryAttachmentRepository {
+	return &MemoryAttachmentRepository{
+		attachments: make(map[int64]entity.Attachment),
+		contents:    make(map[string]entity.AttachmentContent),
+	}
+}
+
+func (r *MemoryAttachmentRepository) Create(ctx context.Context, attachment *entity.Attachment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lastID++
+	attachment.ID = r.lastID
+	r.attachments[attachment.ID] = *attachment
+	return nil
+}
+
+func (r *MemoryAttachmentRepository) FindByID(ctx context.Context, id int64) (*entity.Attachment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	attachment, ok := r.attachments[id]
+	if !ok {
+		return nil, domainErrors.ErrAttachmentNotFound
+	}
+	return &attachment, nil
+}
+
+func (r *MemoryAttachmentRepository) FindByItemID(ctx context.Context, itemID int64) ([]*entity.Attachment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	attachments := []*entity.Attachment{}
+	for _, attachment := range r.attachments {
+		if attachment.ItemID == itemID {
+			a := attachment
+			attachments = append(attachments, &a)
+		}
+	}
+	sort.Slice(attachments, func(i, j int) bool { return attachments[i].ID < attachments[j].ID })
+	return attachments, nil
+}
+
+func (r *MemoryAttachmentRepository) FindReceipt(ctx context.Context, itemID int64) (*entity.Attachment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var receipt *entity.Attachment
+	for _, attachment := range r.attachments {
+		if attachment.ItemID == itemID && attachment.Kind == entity.AttachmentKindReceipt && (receipt == nil || attachment.ID > receipt.ID) {
+			a := attachment
+			receipt = &a
+		}
+	}
+	if receipt == nil {
+		return nil, domainErrors.ErrReceiptNotFound
+	}
+	return receipt, nil
+}
+
+func (r *MemoryAttachmentRepository) MoveToItem(ctx context.Context, fromItemID, toItemID int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	hasReceipt := false
+	for _, attachment := range r.attachments {
+		if attachment.ItemID == toItemID && attachment.Kind == entity.AttachmentKindReceipt {
+			hasReceipt = true
+		}
+	}
+	for id, attachment := range r.attachments {
+		if attachment.ItemID != fromItemID {
+			continue
+		}
+		if hasReceipt && attachment.Kind == entity.AttachmentKindReceipt {
+			attachment.Kind = entity.AttachmentKindDocument
+		}
+		attachment.ItemID = toItemID
+		r.attachments[id] = attachment
+	}
+	return nil
+}
+
+func (r *MemoryAttachmentRepository) Delete(ctx context.Context, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.attachments[id]; !ok {
+		return domainErrors.ErrAttachmentNotFound
+	}
+	delete(r.attachments, id)
+	return nil
+}
+
+func (r *MemoryAttachmentRepository) FindContent(ctx context.Context, sha256 string) (*entity.AttachmentContent, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	content, ok := r.contents[sha256]
+	if !ok {
+		return nil, domainErrors.ErrAttachmentNotFound
+	}
+	return &content, nil
+}
+
+func (r *MemoryAttachmentRepository) AddReference(ctx context.Context, sha256 string, size int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	content, ok := r.contents[sha256]
+	if !ok {
+		content = entity.AttachmentContent{SHA256: sha256, Size: size}
+	}
+	content.RefCount++
+	content.UnreferencedAt = nil
+	r.contents[sha256] = content
+	return nil
+}
+
+func (r *MemoryAttachmentRepository) RemoveReference(ctx context.Context, sha256 string, now time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	content, ok := r.contents[sha256]
+	if !ok {
+		return nil
+	}
+	content.RefCount = max(content.RefCount-1, 0)
+	if content.RefCount == 0 {
+		content.UnreferencedAt = &now
+	}
+	r.contents[sha256] = content
+	return nil
+}
+
+func (r *MemoryAttachmentRepository) FindUnreferenced(ctx context.Context, before time.Time, limit int) ([]*entity.AttachmentContent, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var contents []*entity.AttachmentContent
+	for _, content := range r.contents {
+		if content.RefCount == 0 && content.UnreferencedAt != nil && !content.UnreferencedAt.After(before) {
+			c := content
+			contents = append(contents, &c)
+		}
+	}
+	sort.Slice(contents, func(i, j int) bool { return contents[i].UnreferencedAt.Before(*contents[j].UnreferencedAt) })
+	if len(contents) > limit {
+		contents = contents[:limit]
+	}
+	return contents, nil
+}
+
+func (r *MemoryAttachmentRepository) ReconcileReferences(ctx context.Context, now time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	refs := make(map[string]int)
+	for _, attachment := range r.attachments {
+		refs[attachment.SHA256]++
+	}
+	var corrected int64
+	for sha256, content := range r.contents {
+		if content.RefCount == refs[sha256] {
+			continue
+		}
+		content.RefCount = refs[sha256]
+		if content.RefCount == 0 && content.UnreferencedAt == nil {
+			content.UnreferencedAt = &now
+		} else if content.RefCount > 0 {
+			content.UnreferencedAt = nil
+		}
+		r.contents[sha256] = content
+		corrected++
+	}
+	return corrected, nil
+}
+
+func (r *MemoryAttachmentRepository) DeleteContent(ctx context.Context, sha256 string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	content, ok := r.contents[sha256]
+	if !ok || content.RefCount > 0 {
+		return false, nil
+	}
+	delete(r.contents, sha256)
+	return true, nil
+}
+
+func (r *MemoryAttachmentRepository) StorageReport(ctx context.Context) (*entity.AttachmentStorageReport, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var report entity.AttachmentStorageReport
+	for _, attachment := range r.attachments {
+		report.Attachments++
+		report.LogicalBytes += attachment.Size
+	}
+	for _, content := range r.contents {
+		report.Contents++
+		report.StoredBytes += content.Size
+		if content.RefCount == 0 {
+			report.UnreferencedContents++
+			report.UnreferencedBytes += content.Size
+		}
+	}
+	report.SavedBytes = report.LogicalBytes - (report.StoredBytes - report.UnreferencedBytes)
+	return &report, nil
+}
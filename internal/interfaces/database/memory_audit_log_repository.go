@@ -0,0 +1,83 @@
+package database
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"Aicon-assignment/internal/domain/entity"
+)
+
+// 開発・テスト用のインメモリ監査ログ
+type MemoryAuditLogRepository struct {
+	mu      sync.RWMutex
+	entries []*entity.AuditEntry
+	lastID  int64
+}
+
+func NewMemoryAuditLogRepository() *MemoryAuditLogRepository {
+	return &MemoryAuditLogRepository{}
+}
+
+func (r *MemoryAuditLogRepository) Record(ctx context.Context, entry *entity.AuditEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lastID++
+	entry.ID = r.lastID
+	stored := *entry
+	r.entries = append(r.entries, &stored)
+
+	return nil
+}
+
+func (r *MemoryAuditLogRepository) FindByItemID(ctx context.Context, itemID int64) ([]*entity.AuditEntry, error) {
+	entries := []*entity.AuditEntry{}
+	for _, entry := range r.Entries() {
+		if entry.ItemID == itemID {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+// 記録済みのエントリを古い順に返す
+func (r *MemoryAuditLogRepository) Entries() []*entity.AuditEntry {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entries := make([]*entity.AuditEntry, len(r.entries))
+	for i, entry := range r.entries {
+		copied := *entry
+		entries[i] = &copied
+	}
+	return entries
+}
+
+func (r *MemoryAuditLogRepository) CountBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var count int64
+	for _, entry := range r.entries {
+		if entry.CreatedAt.Before(cutoff) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *MemoryAuditLogRepository) DeleteBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	kept := r.entries[:0]
+	for _, entry := range r.entries {
+		if !entry.CreatedAt.Before(cutoff) {
+			kept = append(kept, entry)
+		}
+	}
+	deleted := int64(len(r.entries) - len(kept))
+	r.entries = kept
+	return deleted, nil
+}
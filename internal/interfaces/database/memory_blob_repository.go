@@ -0,0 +1,66 @@
+package database
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+// 開発・テスト用のインメモリのファイルの記録
+type MemoryBlobRepository struct {
+	mu    sync.RWMutex
+	blobs map[string]entity.Blob
+}
+
+func NewMemoryBlobRepository() *MemoryBlobRepository {
+	return &MemoryBlobRepository{blobs: make(map[string]entity.Blob)}
+}
+
+func (r *MemoryBlobRepository) Save(ctx context.Context, blob *entity.Blob) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.blobs[blob.Key] = *blob
+	return nil
+}
+
+func (r *MemoryBlobRepository) FindByKey(ctx context.Context, key string) (*entity.Blob, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	blob, ok := r.blobs[key]
+	if !ok {
+		return nil, domainErrors.ErrBlobNotFound
+	}
+	return &blob, nil
+}
+
+func (r *MemoryBlobRepository) FindExpired(ctx context.Context, now time.Time, limit int) ([]*entity.Blob, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var expired []*entity.Blob
+	for _, blob := range r.blobs {
+		if blob.ExpiredAt(now) {
+			b := blob
+			expired = append(expired, &b)
+		}
+	}
+	sort.Slice(expired, func(i, j int) bool { return expired[i].ExpiresAt.Before(*expired[j].ExpiresAt) })
+	if len(expired) > limit {
+		expired = expired[:limit]
+	}
+	return expired, nil
+}
+
+func (r *MemoryBlobRepository) Delete(ctx context.Context, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.blobs, key)
+	return nil
+}
@@ -0,0 +1,60 @@
+package database
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"Aicon-assignment/internal/domain/entity"
+)
+
+// 開発・テスト用のインメモリ廃止予定エンドポイントの利用状況
+type MemoryDeprecationUsageRepository struct {
+	mu    sync.RWMutex
+	usage map[[3]string]entity.DeprecationUsage
+}
+
+func NewMemoryDeprecationUsageRepository() *MemoryDeprecationUsageRepository {
+	return &MemoryDeprecationUsageRepository{usage: make(map[[3]string]entity.DeprecationUsage)}
+}
+
+func (r *MemoryDeprecationUsageRepository) Record(ctx context.Context, method, route, caller string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := [3]string{method, route, caller}
+	row, ok := r.usage[key]
+	if !ok {
+		row = entity.DeprecationUsage{Method: method, Route: route, Caller: caller, FirstSeen: at}
+	}
+	row.Calls++
+	row.LastSeen = at
+	r.usage[key] = row
+	return nil
+}
+
+func (r *MemoryDeprecationUsageRepository) FindAll(ctx context.Context) ([]*entity.DeprecationUsage, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	usage := make([]*entity.DeprecationUsage, 0, len(r.usage))
+	for _, row := range r.usage {
+		copied := row
+		usage = append(usage, &copied)
+	}
+	sort.Slice(usage, func(i, j int) bool {
+		a, b := usage[i], usage[j]
+		if !a.LastSeen.Equal(b.LastSeen) {
+			return a.LastSeen.After(b.LastSeen)
+		}
+		if a.Method != b.Method {
+			return a.Method < b.Method
+		}
+		if a.Route != b.Route {
+			return a.Route < b.Route
+		}
+		return a.Caller < b.Caller
+	})
+	return usage, nil
+}
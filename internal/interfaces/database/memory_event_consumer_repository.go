@@ -0,0 +1,95 @@
+package database
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+// 開発・テスト用のインメモリのイベント消費者
+type MemoryEventConsumerRepository struct {
+	mu        sync.RWMutex
+	consumers map[string]*entity.EventConsumer
+}
+
+func NewMemoryEventConsumerRepository() *MemoryEventConsumerRepository {
+	return &MemoryEventConsumerRepository{
+		consumers: make(map[string]*entity.EventConsumer),
+	}
+}
+
+func (r *MemoryEventConsumerRepository) Create(ctx context.Context, consumer *entity.EventConsumer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.consumers[consumer.Name]; ok {
+		return domainErrors.ErrDuplicateEntry
+	}
+	r.consumers[consumer.Name] = copyEventConsumer(consumer)
+	return nil
+}
+
+func (r *MemoryEventConsumerRepository) Find(ctx context.Context, name string) (*entity.EventConsumer, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	consumer, ok := r.consumers[name]
+	if !ok {
+		return nil, domainErrors.ErrConsumerNotFound
+	}
+	return copyEventConsumer(consumer), nil
+}
+
+func (r *MemoryEventConsumerRepository) List(ctx context.Context) ([]*entity.EventConsumer, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	consumers := make([]*entity.EventConsumer, 0, len(r.consumers))
+	for _, consumer := range r.consumers {
+		consumers = append(consumers, copyEventConsumer(consumer))
+	}
+	sort.Slice(consumers, func(i, j int) bool { return consumers[i].Name < consumers[j].Name })
+	return consumers, nil
+}
+
+func (r *MemoryEventConsumerRepository) Save(ctx context.Context, consumer *entity.EventConsumer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.consumers[consumer.Name]
+	if !ok {
+		return domainErrors.ErrConsumerNotFound
+	}
+	saved := copyEventConsumer(consumer)
+	saved.AckTimeoutSeconds = existing.AckTimeoutSeconds
+	saved.CreatedAt = existing.CreatedAt
+	r.consumers[consumer.Name] = saved
+	return nil
+}
+
+func (r *MemoryEventConsumerRepository) Delete(ctx context.Context, name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.consumers[name]; !ok {
+		return domainErrors.ErrConsumerNotFound
+	}
+	delete(r.consumers, name)
+	return nil
+}
+
+func copyEventConsumer(consumer *entity.EventConsumer) *entity.EventConsumer {
+	copied := *consumer
+	if consumer.AckDeadline != nil {
+		deadline := *consumer.AckDeadline
+		copied.AckDeadline = &deadline
+	}
+	if consumer.LastAckedAt != nil {
+		ackedAt := *consumer.LastAckedAt
+		copied.LastAckedAt = &ackedAt
+	}
+	return &copied
+}
@@ -0,0 +1,57 @@
+package database
+
+import (
+	"context"
+	"sync"
+
+	"Aicon-assignment/internal/domain/entity"
+)
+
+// 開発・テスト用のインメモリイベントストア
+type MemoryEventStore struct {
+	mu     sync.RWMutex
+	events []*entity.StoredEvent
+}
+
+func NewMemoryEventStore() *MemoryEventStore {
+	return &MemoryEventStore{}
+}
+
+func (s *MemoryEventStore) Append(ctx context.Context, event *entity.StoredEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	event.Sequence = int64(len(s.events) + 1)
+	s.events = append(s.events, copyStoredEvent(event))
+
+	return nil
+}
+
+func (s *MemoryEventStore) LoadAfter(ctx context.Context, afterSequence int64, limit int) ([]*entity.StoredEvent, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	events := []*entity.StoredEvent{}
+	// 連番は 1 始まりで欠番がないので位置で引ける
+	for i := afterSequence; i < int64(len(s.events)) && len(events) < limit; i++ {
+		if i < 0 {
+			continue
+		}
+		events = append(events, copyStoredEvent(s.events[i]))
+	}
+
+	return events, nil
+}
+
+func (s *MemoryEventStore) LastSequence(ctx context.Context) (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return int64(len(s.events)), nil
+}
+
+func copyStoredEvent(event *entity.StoredEvent) *entity.StoredEvent {
+	copied := *event
+	copied.Payload = append([]byte(nil), event.Payload...)
+	return &copied
+}
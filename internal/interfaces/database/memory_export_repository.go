@@ -0,0 +1,41 @@
+package database
+
+import (
+	"context"
+	"sync"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+// 開発・テスト用のインメモリエクスポートの記録
+type MemoryExportRepository struct {
+	mu      sync.RWMutex
+	exports []entity.Export
+}
+
+func NewMemoryExportRepository() *MemoryExportRepository {
+	return &MemoryExportRepository{}
+}
+
+func (r *MemoryExportRepository) Create(ctx context.Context, export *entity.Export) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	export.ID = int64(len(r.exports) + 1)
+	r.exports = append(r.exports, *export)
+
+	return nil
+}
+
+// ID は 1 始まりで欠番がないので位置で引ける
+func (r *MemoryExportRepository) FindByID(ctx context.Context, id int64) (*entity.Export, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if id < 1 || id > int64(len(r.exports)) {
+		return nil, domainErrors.ErrExportNotFound
+	}
+	export := r.exports[id-1]
+	return &export, nil
+}
@@ -0,0 +1,155 @@
+package database
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+// 開発・テスト用のインメモリの画像の記録
+type MemoryImageRepository struct {
+	mu     sync.RWMutex
+	lastID int64
+	images map[int64]entity.ItemImage
+}
+
+func NewMemoryImageRepository() *MemoryImageRepository {
+	return &MemoryImageRepository{images: make(map[int64]entity.ItemImage)}
+}
+
+func (r *MemoryImageRepository) Create(ctx context.Context, image *entity.ItemImage) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lastID++
+	image.ID = r.lastID
+	r.images[image.ID] = *image
+	return nil
+}
+
+func (r *MemoryImageRepository) FindByID(ctx context.Context, id int64) (*entity.ItemImage, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	image, ok := r.images[id]
+	if !ok {
+		return nil, domainErrors.ErrImageNotFound
+	}
+	return &image, nil
+}
+
+func (r *MemoryImageRepository) FindByItemID(ctx context.Context, itemID int64) ([]*entity.ItemImage, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	images := []*entity.ItemImage{}
+	for _, image := range r.images {
+		if image.ItemID == itemID {
+			img := image
+			images = append(images, &img)
+		}
+	}
+	sort.Slice(images, func(i, j int) bool {
+		if images[i].Position != images[j].Position {
+			return images[i].Position < images[j].Position
+		}
+		return images[i].ID < images[j].ID
+	})
+	return images, nil
+}
+
+func (r *MemoryImageRepository) FindPrimaryByItemIDs(ctx context.Context, itemIDs []int64) ([]*entity.ItemImage, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	images := []*entity.ItemImage{}
+	for _, image := range r.images {
+		if image.IsPrimary && slices.Contains(itemIDs, image.ItemID) {
+			img := image
+			images = append(images, &img)
+		}
+	}
+	return images, nil
+}
+
+func (r *MemoryImageRepository) UpdateGallery(ctx context.Context, itemID int64, orderedIDs []int64, primaryID int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, image := range r.images {
+		if image.ItemID != itemID {
+			continue
+		}
+		// FIELD() と同じく、含まれない画像の位置は -1 になる
+		image.Position = slices.Index(orderedIDs, id)
+		image.IsPrimary = id == primaryID
+		r.images[id] = image
+	}
+	return nil
+}
+
+func (r *MemoryImageRepository) MoveToItem(ctx context.Context, fromItemID, toItemID int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	offset, count := 0, 0
+	for _, image := range r.images {
+		if image.ItemID == toItemID {
+			offset = max(offset, image.Position+1)
+			count++
+		}
+	}
+	for id, image := range r.images {
+		if image.ItemID != fromItemID {
+			continue
+		}
+		image.ItemID = toItemID
+		image.Position += offset
+		image.IsPrimary = image.IsPrimary && count == 0
+		r.images[id] = image
+	}
+	return nil
+}
+
+func (r *MemoryImageRepository) FindByThumbnailStatus(ctx context.Context, statuses ...string) ([]*entity.ItemImage, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	images := []*entity.ItemImage{}
+	for _, image := range r.images {
+		if slices.Contains(statuses, image.ThumbnailStatus) {
+			img := image
+			images = append(images, &img)
+		}
+	}
+	sort.Slice(images, func(i, j int) bool { return images[i].ID < images[j].ID })
+	return images, nil
+}
+
+func (r *MemoryImageRepository) UpdateThumbnailStatus(ctx context.Context, id int64, status string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	image, ok := r.images[id]
+	if !ok {
+		return domainErrors.ErrImageNotFound
+	}
+	image.ThumbnailStatus = status
+	r.images[id] = image
+	return nil
+}
+
+func (r *MemoryImageRepository) Delete(ctx context.Context, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.images[id]; !ok {
+		return domainErrors.ErrImageNotFound
+	}
+	delete(r.images, id)
+	return nil
+}
@@ -0,0 +1,95 @@
+package database
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+// 開発・テスト用のインメモリなりすましセッション
+type MemoryImpersonationRepository struct {
+	mu       sync.RWMutex
+	sessions []*entity.ImpersonationSession
+	lastID   int64
+}
+
+func NewMemoryImpersonationRepository() *MemoryImpersonationRepository {
+	return &MemoryImpersonationRepository{}
+}
+
+func (r *MemoryImpersonationRepository) Create(ctx context.Context, session *entity.ImpersonationSession) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lastID++
+	session.ID = r.lastID
+	r.sessions = append(r.sessions, copyImpersonationSession(session))
+
+	return nil
+}
+
+func (r *MemoryImpersonationRepository) FindByID(ctx context.Context, id int64) (*entity.ImpersonationSession, error) {
+	return r.find(func(session *entity.ImpersonationSession) bool { return session.ID == id })
+}
+
+func (r *MemoryImpersonationRepository) FindByTokenHash(ctx context.Context, tokenHash string) (*entity.ImpersonationSession, error) {
+	return r.find(func(session *entity.ImpersonationSession) bool { return session.TokenHash == tokenHash })
+}
+
+func (r *MemoryImpersonationRepository) find(match func(*entity.ImpersonationSession) bool) (*entity.ImpersonationSession, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, session := range r.sessions {
+		if match(session) {
+			return copyImpersonationSession(session), nil
+		}
+	}
+	return nil, domainErrors.ErrImpersonationNotFound
+}
+
+func (r *MemoryImpersonationRepository) FindAll(ctx context.Context) ([]*entity.ImpersonationSession, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	sessions := make([]*entity.ImpersonationSession, 0, len(r.sessions))
+	for _, session := range r.sessions {
+		sessions = append(sessions, copyImpersonationSession(session))
+	}
+	sort.SliceStable(sessions, func(i, j int) bool {
+		if !sessions[i].CreatedAt.Equal(sessions[j].CreatedAt) {
+			return sessions[i].CreatedAt.After(sessions[j].CreatedAt)
+		}
+		return sessions[i].ID > sessions[j].ID
+	})
+	return sessions, nil
+}
+
+func (r *MemoryImpersonationRepository) End(ctx context.Context, id int64, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, session := range r.sessions {
+		if session.ID == id {
+			endedAt := at
+			session.EndedAt = &endedAt
+			return nil
+		}
+	}
+	return domainErrors.ErrImpersonationNotFound
+}
+
+// トークンは発行時にだけ返すため、保存するコピーからは取り除く
+func copyImpersonationSession(session *entity.ImpersonationSession) *entity.ImpersonationSession {
+	copied := *session
+	copied.Token = ""
+	if session.EndedAt != nil {
+		endedAt := *session.EndedAt
+		copied.EndedAt = &endedAt
+	}
+	return &copied
+}
@@ -0,0 +1,116 @@
+package database
+
+import (
+	"context"
+	"maps"
+	"sort"
+	"sync"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+// 開発・テスト用のインメモリ取り込みプロファイル
+type MemoryImportProfileRepository struct {
+	mu       sync.RWMutex
+	profiles map[int64]*entity.ImportProfile
+	lastID   int64
+}
+
+func NewMemoryImportProfileRepository() *MemoryImportProfileRepository {
+	return &MemoryImportProfileRepository{
+		profiles: make(map[int64]*entity.ImportProfile),
+	}
+}
+
+func (r *MemoryImportProfileRepository) FindByOwner(ctx context.Context, ownerID int64) ([]*entity.ImportProfile, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	profiles := []*entity.ImportProfile{}
+	for _, profile := range r.profiles {
+		if profile.OwnerID == ownerID {
+			profiles = append(profiles, copyImportProfile(profile))
+		}
+	}
+	sort.Slice(profiles, func(i, j int) bool {
+		if profiles[i].Name != profiles[j].Name {
+			return profiles[i].Name < profiles[j].Name
+		}
+		return profiles[i].ID < profiles[j].ID
+	})
+	return profiles, nil
+}
+
+func (r *MemoryImportProfileRepository) FindByID(ctx context.Context, id int64) (*entity.ImportProfile, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	profile, ok := r.profiles[id]
+	if !ok {
+		return nil, domainErrors.ErrImportProfileNotFound
+	}
+	return copyImportProfile(profile), nil
+}
+
+func (r *MemoryImportProfileRepository) Create(ctx context.Context, profile *entity.ImportProfile) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.nameTaken(profile) {
+		return domainErrors.ErrDuplicateEntry
+	}
+	r.lastID++
+	profile.ID = r.lastID
+	r.profiles[profile.ID] = copyImportProfile(profile)
+
+	return nil
+}
+
+func (r *MemoryImportProfileRepository) Update(ctx context.Context, profile *entity.ImportProfile) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.profiles[profile.ID]
+	if !ok {
+		return domainErrors.ErrImportProfileNotFound
+	}
+	if r.nameTaken(profile) {
+		return domainErrors.ErrDuplicateEntry
+	}
+	updated := copyImportProfile(profile)
+	updated.OwnerID = existing.OwnerID
+	updated.CreatedAt = existing.CreatedAt
+	r.profiles[profile.ID] = updated
+
+	return nil
+}
+
+func (r *MemoryImportProfileRepository) Delete(ctx context.Context, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.profiles[id]; !ok {
+		return domainErrors.ErrImportProfileNotFound
+	}
+	delete(r.profiles, id)
+
+	return nil
+}
+
+// 同じ持ち主の別のプロファイルが同じ名前を使っているか（DB の一意制約と同じ）
+func (r *MemoryImportProfileRepository) nameTaken(profile *entity.ImportProfile) bool {
+	for _, existing := range r.profiles {
+		if existing.ID != profile.ID && existing.OwnerID == profile.OwnerID && existing.Name == profile.Name {
+			return true
+		}
+	}
+	return false
+}
+
+func copyImportProfile(profile *entity.ImportProfile) *entity.ImportProfile {
+	copied := *profile
+	copied.Columns = maps.Clone(profile.Columns)
+	copied.Defaults = maps.Clone(profile.Defaults)
+	return &copied
+}
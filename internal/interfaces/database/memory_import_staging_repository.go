@@ -0,0 +1,157 @@
+package database
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+// 開発・テスト用のインメモリ取り込みの置き場
+type MemoryImportStagingRepository struct {
+	mu      sync.RWMutex
+	batches map[int64]*entity.ImportBatch
+	rows    map[int64]map[int]*entity.StagedImportRow
+	lastID  int64
+}
+
+func NewMemoryImportStagingRepository() *MemoryImportStagingRepository {
+	return &MemoryImportStagingRepository{
+		batches: make(map[int64]*entity.ImportBatch),
+		rows:    make(map[int64]map[int]*entity.StagedImportRow),
+	}
+}
+
+func (r *MemoryImportStagingRepository) CreateBatch(ctx context.Context, batch *entity.ImportBatch, rows []*entity.StagedImportRow) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lastID++
+	batch.ID = r.lastID
+	r.batches[batch.ID] = copyImportBatch(batch)
+
+	stored := make(map[int]*entity.StagedImportRow, len(rows))
+	for _, row := range rows {
+		row.BatchID = batch.ID
+		stored[row.Row] = copyStagedRow(row)
+	}
+	r.rows[batch.ID] = stored
+
+	return nil
+}
+
+func (r *MemoryImportStagingRepository) FindBatch(ctx context.Context, id int64) (*entity.ImportBatch, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	batch, ok := r.batches[id]
+	if !ok {
+		return nil, domainErrors.ErrImportBatchNotFound
+	}
+	return copyImportBatch(batch), nil
+}
+
+func (r *MemoryImportStagingRepository) UpdateBatch(ctx context.Context, batch *entity.ImportBatch) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.batches[batch.ID]; !ok {
+		return domainErrors.ErrImportBatchNotFound
+	}
+	r.batches[batch.ID] = copyImportBatch(batch)
+
+	return nil
+}
+
+func (r *MemoryImportStagingRepository) FindRows(ctx context.Context, batchID int64) ([]*entity.StagedImportRow, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	rows := []*entity.StagedImportRow{}
+	for _, row := range r.rows[batchID] {
+		rows = append(rows, copyStagedRow(row))
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].Row < rows[j].Row })
+	return rows, nil
+}
+
+func (r *MemoryImportStagingRepository) FindRow(ctx context.Context, batchID int64, row int) (*entity.StagedImportRow, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	staged, ok := r.rows[batchID][row]
+	if !ok {
+		return nil, domainErrors.ErrStagedRowNotFound
+	}
+	return copyStagedRow(staged), nil
+}
+
+func (r *MemoryImportStagingRepository) UpdateRow(ctx context.Context, row *entity.StagedImportRow) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.rows[row.BatchID][row.Row]; !ok {
+		return domainErrors.ErrStagedRowNotFound
+	}
+	r.rows[row.BatchID][row.Row] = copyStagedRow(row)
+
+	return nil
+}
+
+func (r *MemoryImportStagingRepository) DeleteRows(ctx context.Context, batchID int64, rows ...int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(rows) == 0 {
+		delete(r.rows, batchID)
+		return nil
+	}
+	for _, row := range rows {
+		delete(r.rows[batchID], row)
+	}
+	return nil
+}
+
+func (r *MemoryImportStagingRepository) DeleteBatch(ctx context.Context, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.batches[id]; !ok {
+		return domainErrors.ErrImportBatchNotFound
+	}
+	delete(r.batches, id)
+	delete(r.rows, id)
+
+	return nil
+}
+
+func (r *MemoryImportStagingRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var deleted int64
+	for id, batch := range r.batches {
+		if !batch.ExpiresAt.After(before) {
+			delete(r.batches, id)
+			delete(r.rows, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func copyImportBatch(batch *entity.ImportBatch) *entity.ImportBatch {
+	copied := *batch
+	return &copied
+}
+
+func copyStagedRow(row *entity.StagedImportRow) *entity.StagedImportRow {
+	copied := *row
+	copied.ReadErrors = slices.Clone(row.ReadErrors)
+	copied.Errors = slices.Clone(row.Errors)
+	return &copied
+}
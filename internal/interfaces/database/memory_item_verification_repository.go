@@ -0,0 +1,44 @@
+package database
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"Aicon-assignment/internal/domain/entity"
+)
+
+// 開発・テスト用のインメモリ検証状態リポジトリ
+type MemoryItemVerificationRepository struct {
+	mu            sync.RWMutex
+	verifications map[int64]*entity.ItemVerification
+}
+
+func NewMemoryItemVerificationRepository() *MemoryItemVerificationRepository {
+	return &MemoryItemVerificationRepository{verifications: make(map[int64]*entity.ItemVerification)}
+}
+
+func (r *MemoryItemVerificationRepository) Find(ctx context.Context, itemID int64) (*entity.ItemVerification, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	v, ok := r.verifications[itemID]
+	if !ok {
+		return entity.NewItemVerification(itemID), nil
+	}
+	return copyItemVerification(v), nil
+}
+
+func (r *MemoryItemVerificationRepository) Save(ctx context.Context, v *entity.ItemVerification) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.verifications[v.ItemID] = copyItemVerification(v)
+	return nil
+}
+
+func copyItemVerification(v *entity.ItemVerification) *entity.ItemVerification {
+	copied := *v
+	copied.LockedFields = slices.Clone(v.LockedFields)
+	return &copied
+}
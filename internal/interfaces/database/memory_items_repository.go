@@ -0,0 +1,423 @@
+package database
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/pkg/idgen"
+	"Aicon-assignment/internal/pkg/listquery"
+)
+
+// DBを使わない開発・テスト用のインメモリリポジトリ
+type MemoryItemRepository struct {
+	mu           sync.RWMutex
+	items        map[int64]*entity.Item
+	priceHistory []*entity.PriceChange
+	lastChangeID int64
+	deletedAt    map[int64]time.Time    // 統合・分割で取り除いたアイテムと削除日時
+	removed      map[int64]*entity.Item // 取り除いたアイテム（完全に削除するときに持ち主を確かめる）
+	ids          idgen.IDGenerator
+}
+
+func NewMemoryItemRepository(ids idgen.IDGenerator, seed ...*entity.Item) *MemoryItemRepository {
+	r := &MemoryItemRepository{
+		items:     make(map[int64]*entity.Item),
+		deletedAt: make(map[int64]time.Time),
+		removed:   make(map[int64]*entity.Item),
+		ids:       ids,
+	}
+	for _, item := range seed {
+		r.insert(item)
+	}
+	return r
+}
+
+func (r *MemoryItemRepository) FindAll(ctx context.Context) ([]*entity.Item, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	items := make([]*entity.Item, 0, len(r.items))
+	for _, item := range r.items {
+		if visibleTo(ctx, item) {
+			items = append(items, copyItem(item))
+		}
+	}
+
+	// MySQL実装と同じく作成日時の降順
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].CreatedAt.Equal(items[j].CreatedAt) {
+			return items[i].ID > items[j].ID
+		}
+		return items[i].CreatedAt.After(items[j].CreatedAt)
+	})
+
+	return items, nil
+}
+
+func (r *MemoryItemRepository) FindByOrganization(ctx context.Context, orgID int64) ([]*entity.Item, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	items := []*entity.Item{}
+	for _, item := range r.items {
+		if item.OrgID != nil && *item.OrgID == orgID && visibleTo(ctx, item) {
+			items = append(items, copyItem(item))
+		}
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].ID < items[j].ID })
+
+	return items, nil
+}
+
+func (r *MemoryItemRepository) FindByQuery(ctx context.Context, q entity.ItemQuery) ([]*entity.Item, error) {
+	// FindAll の並び（作成日時の降順）を基準に絞り込み・並び替えを行う
+	all, err := r.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	items := listquery.Apply(all, listquery.Query{Filter: q.Filter, Sort: q.Sort}, (*entity.Item).FilterValue).Items
+	if q.After != nil {
+		items = slices.DeleteFunc(items, func(item *entity.Item) bool { return !q.After.Precedes(item) })
+	}
+	if q.Limit > 0 {
+		start := min(q.Offset, len(items))
+		items = items[start:min(start+q.Limit, len(items))]
+	}
+	return items, nil
+}
+
+func (r *MemoryItemRepository) Search(ctx context.Context, search entity.ItemSearch) ([]*entity.Item, error) {
+	all, err := r.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	items := []*entity.Item{}
+	for _, item := range all {
+		if len(items) == search.Limit {
+			break
+		}
+		if search.Matches(item) {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+func (r *MemoryItemRepository) CountByQuery(ctx context.Context, q entity.ItemQuery) (int, error) {
+	all, err := r.FindAll(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, item := range all {
+		if q.Matches(item) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *MemoryItemRepository) FindByID(ctx context.Context, id int64) (*entity.Item, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	item, ok := r.items[id]
+	if !ok || !visibleTo(ctx, item) {
+		return nil, domainErrors.ErrItemNotFound
+	}
+	return copyItem(item), nil
+}
+
+// 取り除いたアイテムも返す
+func (r *MemoryItemRepository) FindByIDIncludingDeleted(ctx context.Context, id int64) (*entity.Item, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	item, ok := r.items[id]
+	if !ok {
+		item, ok = r.removed[id]
+	}
+	if !ok || !visibleTo(ctx, item) {
+		return nil, domainErrors.ErrItemNotFound
+	}
+	return copyItem(item), nil
+}
+
+func (r *MemoryItemRepository) Create(ctx context.Context, item *entity.Item) (*entity.Item, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	owned := copyItem(item)
+	owned.OwnerID = ownerOf(ctx, item)
+	return copyItem(r.insert(owned)), nil
+}
+
+func (r *MemoryItemRepository) Update(ctx context.Context, item *entity.Item) (*entity.Item, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.items[item.ID]
+	if !ok || !visibleTo(ctx, existing) {
+		return nil, domainErrors.ErrItemNotFound
+	}
+
+	updated := copyItem(item)
+	updated.CreatedAt = existing.CreatedAt
+	updated.OwnerID = existing.OwnerID
+	r.items[item.ID] = updated
+
+	return copyItem(updated), nil
+}
+
+func (r *MemoryItemRepository) RecordPriceChange(ctx context.Context, change *entity.PriceChange) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.items[change.ItemID]; !ok {
+		return domainErrors.ErrItemNotFound
+	}
+
+	r.lastChangeID++
+	change.ID = r.lastChangeID
+	stored := *change
+	r.priceHistory = append(r.priceHistory, &stored)
+
+	return nil
+}
+
+func (r *MemoryItemRepository) FindPriceHistory(ctx context.Context, itemID int64) ([]*entity.PriceChange, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	history := []*entity.PriceChange{}
+	for _, change := range r.priceHistory {
+		if change.ItemID == itemID {
+			copied := *change
+			history = append(history, &copied)
+		}
+	}
+	return history, nil
+}
+
+// インメモリでは統合元を削除し、価格変更履歴を統合先に付け替える
+func (r *MemoryItemRepository) MergeInto(ctx context.Context, sourceID, targetID int64, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	source, ok := r.items[sourceID]
+	if !ok || !visibleTo(ctx, source) {
+		return domainErrors.ErrItemNotFound
+	}
+	if _, ok := r.items[targetID]; !ok {
+		return domainErrors.ErrItemNotFound
+	}
+	delete(r.items, sourceID)
+	r.deletedAt[sourceID] = at
+	r.removed[sourceID] = source
+
+	for _, change := range r.priceHistory {
+		if change.ItemID == sourceID {
+			change.ItemID = targetID
+		}
+	}
+
+	return nil
+}
+
+func (r *MemoryItemRepository) AssignOwner(ctx context.Context, id, ownerID int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	item, ok := r.items[id]
+	if !ok || !visibleTo(ctx, item) {
+		return domainErrors.ErrItemNotFound
+	}
+	owned := copyItem(item)
+	owned.OwnerID = &ownerID
+	r.items[id] = owned
+
+	return nil
+}
+
+// インメモリでは一覧から取り除き、価格変更履歴は残す
+func (r *MemoryItemRepository) SoftDelete(ctx context.Context, id int64, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	item, ok := r.items[id]
+	if !ok || !visibleTo(ctx, item) {
+		return domainErrors.ErrItemNotFound
+	}
+	delete(r.items, id)
+	r.deletedAt[id] = at
+	r.removed[id] = item
+
+	return nil
+}
+
+func (r *MemoryItemRepository) CountDeletedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var count int64
+	for _, at := range r.deletedAt {
+		if at.Before(cutoff) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *MemoryItemRepository) FindIDsDeletedBefore(ctx context.Context, cutoff time.Time) ([]int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var ids []int64
+	for id, at := range r.deletedAt {
+		if at.Before(cutoff) {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// 論理削除したアイテムの価格変更履歴も削除する
+func (r *MemoryItemRepository) PurgeDeletedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	purged := make(map[int64]bool)
+	for id, at := range r.deletedAt {
+		if at.Before(cutoff) {
+			purged[id] = true
+			delete(r.deletedAt, id)
+			delete(r.removed, id)
+		}
+	}
+
+	history := r.priceHistory[:0]
+	for _, change := range r.priceHistory {
+		if !purged[change.ItemID] {
+			history = append(history, change)
+		}
+	}
+	r.priceHistory = history
+
+	return int64(len(purged)), nil
+}
+
+func (r *MemoryItemRepository) Purge(ctx context.Context, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	item, ok := r.items[id]
+	if !ok {
+		item, ok = r.removed[id]
+	}
+	if !ok || !visibleTo(ctx, item) {
+		return domainErrors.ErrItemNotFound
+	}
+	delete(r.items, id)
+	delete(r.deletedAt, id)
+	delete(r.removed, id)
+
+	history := r.priceHistory[:0]
+	for _, change := range r.priceHistory {
+		if change.ItemID != id {
+			history = append(history, change)
+		}
+	}
+	r.priceHistory = history
+
+	return nil
+}
+
+func (r *MemoryItemRepository) Put(ctx context.Context, item *entity.Item) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.items[item.ID] = copyItem(item)
+	delete(r.deletedAt, item.ID)
+	delete(r.removed, item.ID)
+
+	return nil
+}
+
+func (r *MemoryItemRepository) Remove(ctx context.Context, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.items, id)
+	delete(r.deletedAt, id)
+	delete(r.removed, id)
+
+	history := r.priceHistory[:0]
+	for _, change := range r.priceHistory {
+		if change.ItemID != id {
+			history = append(history, change)
+		}
+	}
+	r.priceHistory = history
+
+	return nil
+}
+
+func (r *MemoryItemRepository) GetSummaryBy(ctx context.Context, dim entity.SummaryDimension) (map[string]int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	summary := make(map[string]int)
+	for _, item := range r.items {
+		if visibleTo(ctx, item) {
+			summary[item.SummaryKey(dim)]++
+		}
+	}
+	return summary, nil
+}
+
+func (r *MemoryItemRepository) SummarizeByOrganization(ctx context.Context, orgIDs []int64) (map[int64]*entity.OrganizationItemSummary, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	wanted := make(map[int64]bool, len(orgIDs))
+	for _, id := range orgIDs {
+		wanted[id] = true
+	}
+
+	summaries := make(map[int64]*entity.OrganizationItemSummary)
+	for _, item := range r.items {
+		if item.OrgID == nil || !wanted[*item.OrgID] || !visibleTo(ctx, item) {
+			continue
+		}
+		summary, ok := summaries[*item.OrgID]
+		if !ok {
+			summary = &entity.OrganizationItemSummary{OrgID: *item.OrgID}
+			summaries[*item.OrgID] = summary
+		}
+		summary.ItemCount++
+		summary.TotalValue += int64(item.PurchasePrice)
+	}
+	return summaries, nil
+}
+
+// 呼び出し側でロックを取得していること
+func (r *MemoryItemRepository) insert(item *entity.Item) *entity.Item {
+	stored := copyItem(item)
+	stored.ID = r.ids.NextID()
+	r.items[stored.ID] = stored
+	return stored
+}
+
+// 呼び出し元による変更がストアに波及しないようコピーを返す
+func copyItem(item *entity.Item) *entity.Item {
+	copied := *item
+	return &copied
+}
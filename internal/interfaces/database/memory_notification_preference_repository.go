@@ -0,0 +1,46 @@
+package database
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+// 開発・テスト用のインメモリの通知の設定
+type MemoryNotificationPreferenceRepository struct {
+	mu          sync.RWMutex
+	preferences map[int64]*entity.NotificationPreference
+}
+
+func NewMemoryNotificationPreferenceRepository() *MemoryNotificationPreferenceRepository {
+	return &MemoryNotificationPreferenceRepository{preferences: map[int64]*entity.NotificationPreference{}}
+}
+
+func (r *MemoryNotificationPreferenceRepository) FindByUser(ctx context.Context, userID int64) (*entity.NotificationPreference, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	preference, ok := r.preferences[userID]
+	if !ok {
+		return nil, domainErrors.ErrPreferenceNotFound
+	}
+	return copyNotificationPreference(preference), nil
+}
+
+func (r *MemoryNotificationPreferenceRepository) Save(ctx context.Context, preference *entity.NotificationPreference) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.preferences[preference.UserID] = copyNotificationPreference(preference)
+	return nil
+}
+
+func copyNotificationPreference(preference *entity.NotificationPreference) *entity.NotificationPreference {
+	copied := *preference
+	copied.Channels = slices.Clone(preference.Channels)
+	copied.PushTokens = slices.Clone(preference.PushTokens)
+	return &copied
+}
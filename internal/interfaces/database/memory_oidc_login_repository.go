@@ -0,0 +1,57 @@
+package database
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+// 開発・テスト用のインメモリのログインの要求
+type MemoryOIDCLoginRepository struct {
+	mu     sync.Mutex
+	logins map[string]entity.OIDCLogin
+}
+
+func NewMemoryOIDCLoginRepository() *MemoryOIDCLoginRepository {
+	return &MemoryOIDCLoginRepository{logins: map[string]entity.OIDCLogin{}}
+}
+
+func (r *MemoryOIDCLoginRepository) Create(ctx context.Context, login *entity.OIDCLogin) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.logins[login.StateHash]; ok {
+		return domainErrors.ErrDuplicateEntry
+	}
+	r.logins[login.StateHash] = *login
+	return nil
+}
+
+func (r *MemoryOIDCLoginRepository) Take(ctx context.Context, stateHash string) (*entity.OIDCLogin, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	login, ok := r.logins[stateHash]
+	if !ok {
+		return nil, domainErrors.ErrOIDCLoginNotFound
+	}
+	delete(r.logins, stateHash)
+	return &login, nil
+}
+
+func (r *MemoryOIDCLoginRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var deleted int64
+	for stateHash, login := range r.logins {
+		if login.ExpiresAt.Before(before) {
+			delete(r.logins, stateHash)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
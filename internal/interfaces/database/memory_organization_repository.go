@@ -0,0 +1,291 @@
+package database
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+// 開発・テスト用のインメモリ組織リポジトリ
+type MemoryOrganizationRepository struct {
+	mu            sync.RWMutex
+	organizations []*entity.Organization
+	members       []*entity.Membership
+	branding      map[int64]entity.Branding
+	fieldLocks    map[int64]entity.FieldLockPolicy
+	lastID        int64
+}
+
+func NewMemoryOrganizationRepository() *MemoryOrganizationRepository {
+	return &MemoryOrganizationRepository{
+		branding:   make(map[int64]entity.Branding),
+		fieldLocks: make(map[int64]entity.FieldLockPolicy),
+	}
+}
+
+func (r *MemoryOrganizationRepository) Create(ctx context.Context, org *entity.Organization) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lastID++
+	org.ID = r.lastID
+	copied := *org
+	r.organizations = append(r.organizations, &copied)
+
+	return nil
+}
+
+func (r *MemoryOrganizationRepository) FindByID(ctx context.Context, id int64) (*entity.Organization, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, org := range r.organizations {
+		if org.ID == id {
+			copied := *org
+			return &copied, nil
+		}
+	}
+	return nil, domainErrors.ErrOrganizationNotFound
+}
+
+func (r *MemoryOrganizationRepository) FindPage(ctx context.Context, limit, offset int) ([]*entity.Organization, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	// ID は作成順に振るため、登録順がそのまま ID 順になる
+	orgs := r.organizations
+	if limit > 0 {
+		start := min(offset, len(orgs))
+		orgs = orgs[start:min(start+limit, len(orgs))]
+	}
+
+	page := make([]*entity.Organization, 0, len(orgs))
+	for _, org := range orgs {
+		copied := *org
+		page = append(page, &copied)
+	}
+	return page, nil
+}
+
+func (r *MemoryOrganizationRepository) Count(ctx context.Context) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return len(r.organizations), nil
+}
+
+func (r *MemoryOrganizationRepository) FindMembers(ctx context.Context, orgID int64) ([]*entity.Membership, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	members := []*entity.Membership{}
+	for _, member := range r.members {
+		if member.OrgID == orgID {
+			copied := *member
+			members = append(members, &copied)
+		}
+	}
+	sort.Slice(members, func(i, j int) bool { return members[i].UserID < members[j].UserID })
+	return members, nil
+}
+
+func (r *MemoryOrganizationRepository) FindMember(ctx context.Context, orgID, userID int64) (*entity.Membership, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if member := r.member(orgID, userID); member != nil {
+		copied := *member
+		return &copied, nil
+	}
+	return nil, domainErrors.ErrMemberNotFound
+}
+
+func (r *MemoryOrganizationRepository) AddMember(ctx context.Context, member *entity.Membership) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.member(member.OrgID, member.UserID) != nil {
+		return domainErrors.ErrDuplicateEntry
+	}
+
+	copied := *member
+	r.members = append(r.members, &copied)
+	return nil
+}
+
+func (r *MemoryOrganizationRepository) UpdateMember(ctx context.Context, member *entity.Membership) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing := r.member(member.OrgID, member.UserID)
+	if existing == nil {
+		return domainErrors.ErrMemberNotFound
+	}
+	existing.Role = member.Role
+	existing.UpdatedAt = member.UpdatedAt
+	return nil
+}
+
+func (r *MemoryOrganizationRepository) RemoveMember(ctx context.Context, orgID, userID int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, member := range r.members {
+		if member.OrgID == orgID && member.UserID == userID {
+			r.members = append(r.members[:i], r.members[i+1:]...)
+			return nil
+		}
+	}
+	return domainErrors.ErrMemberNotFound
+}
+
+func (r *MemoryOrganizationRepository) CountAdmins(ctx context.Context, orgID int64) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	count := 0
+	for _, member := range r.members {
+		if member.OrgID == orgID && member.Role == entity.OrgRoleAdmin {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *MemoryOrganizationRepository) FindBranding(ctx context.Context, orgID int64) (*entity.Branding, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	branding, ok := r.branding[orgID]
+	if !ok {
+		return nil, domainErrors.ErrBrandingNotFound
+	}
+	return &branding, nil
+}
+
+func (r *MemoryOrganizationRepository) SaveBranding(ctx context.Context, branding *entity.Branding) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.branding[branding.OrgID] = *branding
+	return nil
+}
+
+func (r *MemoryOrganizationRepository) FindFieldLockPolicy(ctx context.Context, orgID int64) (*entity.FieldLockPolicy, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	policy, ok := r.fieldLocks[orgID]
+	if !ok {
+		return entity.NewFieldLockPolicy(orgID), nil
+	}
+	policy.Rules = slices.Clone(policy.Rules)
+	return &policy, nil
+}
+
+func (r *MemoryOrganizationRepository) SaveFieldLockPolicy(ctx context.Context, policy *entity.FieldLockPolicy) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	copied := *policy
+	copied.Rules = slices.Clone(policy.Rules)
+	r.fieldLocks[policy.OrgID] = copied
+	return nil
+}
+
+func (r *MemoryOrganizationRepository) member(orgID, userID int64) *entity.Membership {
+	for _, member := range r.members {
+		if member.OrgID == orgID && member.UserID == userID {
+			return member
+		}
+	}
+	return nil
+}
+
+// 開発・テスト用のインメモリ招待リポジトリ
+type MemoryInvitationRepository struct {
+	mu          sync.RWMutex
+	invitations []*entity.Invitation
+	lastID      int64
+}
+
+func NewMemoryInvitationRepository() *MemoryInvitationRepository {
+	return &MemoryInvitationRepository{}
+}
+
+func (r *MemoryInvitationRepository) Create(ctx context.Context, invitation *entity.Invitation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lastID++
+	invitation.ID = r.lastID
+	r.invitations = append(r.invitations, copyInvitation(invitation))
+
+	return nil
+}
+
+func (r *MemoryInvitationRepository) FindByTokenHash(ctx context.Context, tokenHash string) (*entity.Invitation, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, invitation := range r.invitations {
+		if invitation.TokenHash == tokenHash {
+			return copyInvitation(invitation), nil
+		}
+	}
+	return nil, domainErrors.ErrInvitationNotFound
+}
+
+func (r *MemoryInvitationRepository) FindByOrgID(ctx context.Context, orgID int64) ([]*entity.Invitation, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	invitations := []*entity.Invitation{}
+	for _, invitation := range r.invitations {
+		if invitation.OrgID == orgID {
+			invitations = append(invitations, copyInvitation(invitation))
+		}
+	}
+	sort.SliceStable(invitations, func(i, j int) bool {
+		if !invitations[i].CreatedAt.Equal(invitations[j].CreatedAt) {
+			return invitations[i].CreatedAt.After(invitations[j].CreatedAt)
+		}
+		return invitations[i].ID > invitations[j].ID
+	})
+	return invitations, nil
+}
+
+func (r *MemoryInvitationRepository) MarkAccepted(ctx context.Context, id, userID int64, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, invitation := range r.invitations {
+		if invitation.ID == id && invitation.AcceptedAt == nil {
+			acceptedAt, acceptedBy := at, userID
+			invitation.AcceptedAt = &acceptedAt
+			invitation.AcceptedBy = &acceptedBy
+			return nil
+		}
+	}
+	return domainErrors.ErrInvitationNotFound
+}
+
+// トークンはメールでだけ送るため、保存するコピーからは取り除く
+func copyInvitation(invitation *entity.Invitation) *entity.Invitation {
+	copied := *invitation
+	copied.Token = ""
+	if invitation.AcceptedAt != nil {
+		acceptedAt := *invitation.AcceptedAt
+		copied.AcceptedAt = &acceptedAt
+	}
+	if invitation.AcceptedBy != nil {
+		acceptedBy := *invitation.AcceptedBy
+		copied.AcceptedBy = &acceptedBy
+	}
+	return &copied
+}
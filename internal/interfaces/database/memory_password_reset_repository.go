@@ -0,0 +1,86 @@
+package database
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+// 開発・テスト用のインメモリのパスワード再設定トークン
+type MemoryPasswordResetRepository struct {
+	mu     sync.RWMutex
+	tokens []*entity.PasswordResetToken
+	lastID int64
+}
+
+func NewMemoryPasswordResetRepository() *MemoryPasswordResetRepository {
+	return &MemoryPasswordResetRepository{}
+}
+
+func (r *MemoryPasswordResetRepository) Create(ctx context.Context, token *entity.PasswordResetToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.tokens {
+		if existing.TokenHash == token.TokenHash {
+			return domainErrors.ErrDuplicateEntry
+		}
+	}
+
+	r.lastID++
+	token.ID = r.lastID
+	r.tokens = append(r.tokens, copyPasswordResetToken(token))
+
+	return nil
+}
+
+func (r *MemoryPasswordResetRepository) FindByTokenHash(ctx context.Context, tokenHash string) (*entity.PasswordResetToken, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, token := range r.tokens {
+		if token.TokenHash == tokenHash {
+			return copyPasswordResetToken(token), nil
+		}
+	}
+	return nil, domainErrors.ErrPasswordResetNotFound
+}
+
+func (r *MemoryPasswordResetRepository) MarkUsed(ctx context.Context, id int64, at time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, token := range r.tokens {
+		if token.ID == id && token.UsedAt == nil {
+			token.UsedAt = &at
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *MemoryPasswordResetRepository) MarkUsedByUser(ctx context.Context, userID int64, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, token := range r.tokens {
+		if token.UserID == userID && token.UsedAt == nil {
+			token.UsedAt = &at
+		}
+	}
+	return nil
+}
+
+// トークンは発行時にだけ返すため、保存するコピーからは取り除く
+func copyPasswordResetToken(token *entity.PasswordResetToken) *entity.PasswordResetToken {
+	copied := *token
+	copied.Token = ""
+	if token.UsedAt != nil {
+		usedAt := *token.UsedAt
+		copied.UsedAt = &usedAt
+	}
+	return &copied
+}
@@ -0,0 +1,52 @@
+package database
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+
+	"Aicon-assignment/internal/domain/entity"
+)
+
+// 開発・テスト用のインメモリのポートフォリオのスナップショット
+type MemoryPortfolioSnapshotRepository struct {
+	mu        sync.RWMutex
+	snapshots map[string]*entity.PortfolioSnapshot
+}
+
+func NewMemoryPortfolioSnapshotRepository() *MemoryPortfolioSnapshotRepository {
+	return &MemoryPortfolioSnapshotRepository{snapshots: map[string]*entity.PortfolioSnapshot{}}
+}
+
+func (r *MemoryPortfolioSnapshotRepository) Save(ctx context.Context, snapshot *entity.PortfolioSnapshot) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.snapshots[snapshot.Date] = copySnapshot(snapshot)
+	return nil
+}
+
+func (r *MemoryPortfolioSnapshotRepository) FindSince(ctx context.Context, from string) ([]*entity.PortfolioSnapshot, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	snapshots := []*entity.PortfolioSnapshot{}
+	for date, snapshot := range r.snapshots {
+		if date >= from {
+			snapshots = append(snapshots, copySnapshot(snapshot))
+		}
+	}
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].Date < snapshots[j].Date })
+	return snapshots, nil
+}
+
+func copySnapshot(snapshot *entity.PortfolioSnapshot) *entity.PortfolioSnapshot {
+	copied := *snapshot
+	copied.Categories = slices.Clone(snapshot.Categories)
+	for i, category := range copied.Categories {
+		value := *category
+		copied.Categories[i] = &value
+	}
+	return &copied
+}
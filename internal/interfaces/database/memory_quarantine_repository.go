@@ -0,0 +1,66 @@
+package database
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+// 開発・テスト用のインメモリの隔離したファイルの記録
+type MemoryQuarantineRepository struct {
+	mu     sync.RWMutex
+	lastID int64
+	files  map[int64]entity.QuarantinedFile
+}
+
+func NewMemoryQuarantineRepository() *MemoryQuarantineRepository {
+	return &MemoryQuarantineRepository{files: make(map[int64]entity.QuarantinedFile)}
+}
+
+func (r *MemoryQuarantineRepository) Create(ctx context.Context, file *entity.QuarantinedFile) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lastID++
+	file.ID = r.lastID
+	r.files[file.ID] = *file
+	return nil
+}
+
+func (r *MemoryQuarantineRepository) FindAll(ctx context.Context) ([]*entity.QuarantinedFile, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	files := make([]*entity.QuarantinedFile, 0, len(r.files))
+	for _, file := range r.files {
+		f := file
+		files = append(files, &f)
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].ID > files[j].ID })
+	return files, nil
+}
+
+func (r *MemoryQuarantineRepository) FindByID(ctx context.Context, id int64) (*entity.QuarantinedFile, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	file, ok := r.files[id]
+	if !ok {
+		return nil, domainErrors.ErrQuarantineNotFound
+	}
+	return &file, nil
+}
+
+func (r *MemoryQuarantineRepository) Delete(ctx context.Context, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.files[id]; !ok {
+		return domainErrors.ErrQuarantineNotFound
+	}
+	delete(r.files, id)
+	return nil
+}
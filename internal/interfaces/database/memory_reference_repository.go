@@ -0,0 +1,105 @@
+package database
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sort"
+	"sync"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+// 開発・テスト用のインメモリ参照データ
+type MemoryReferenceDataRepository struct {
+	mu      sync.RWMutex
+	records []*entity.ReferenceRecord
+}
+
+func NewMemoryReferenceDataRepository(seed ...*entity.ReferenceRecord) *MemoryReferenceDataRepository {
+	r := &MemoryReferenceDataRepository{}
+	for _, record := range seed {
+		r.records = append(r.records, copyReference(record))
+	}
+	return r
+}
+
+func (r *MemoryReferenceDataRepository) FindAll(ctx context.Context, refType string) ([]*entity.ReferenceRecord, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	records := []*entity.ReferenceRecord{}
+	for _, record := range r.records {
+		if record.Type == refType {
+			records = append(records, copyReference(record))
+		}
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].Key < records[j].Key })
+	return records, nil
+}
+
+func (r *MemoryReferenceDataRepository) FindByKey(ctx context.Context, refType, key string) (*entity.ReferenceRecord, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if i := r.index(refType, key); i >= 0 {
+		return copyReference(r.records[i]), nil
+	}
+	return nil, domainErrors.ErrReferenceNotFound
+}
+
+func (r *MemoryReferenceDataRepository) Create(ctx context.Context, record *entity.ReferenceRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.index(record.Type, record.Key) >= 0 {
+		return domainErrors.ErrDuplicateEntry
+	}
+	r.records = append(r.records, copyReference(record))
+	return nil
+}
+
+func (r *MemoryReferenceDataRepository) Update(ctx context.Context, record *entity.ReferenceRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.index(record.Type, record.Key)
+	if i < 0 {
+		return domainErrors.ErrReferenceNotFound
+	}
+	updated := copyReference(record)
+	updated.CreatedAt = r.records[i].CreatedAt
+	r.records[i] = updated
+	return nil
+}
+
+func (r *MemoryReferenceDataRepository) Delete(ctx context.Context, refType, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.index(refType, key)
+	if i < 0 {
+		return domainErrors.ErrReferenceNotFound
+	}
+	r.records = slices.Delete(r.records, i, i+1)
+	return nil
+}
+
+func (r *MemoryReferenceDataRepository) index(refType, key string) int {
+	return slices.IndexFunc(r.records, func(record *entity.ReferenceRecord) bool {
+		return record.Type == refType && record.Key == key
+	})
+}
+
+// リストの値は呼び出し側で書き換えられないよう複製する
+func copyReference(record *entity.ReferenceRecord) *entity.ReferenceRecord {
+	copied := *record
+	copied.Attributes = maps.Clone(record.Attributes)
+	for name, value := range copied.Attributes {
+		if list, ok := value.([]string); ok {
+			copied.Attributes[name] = slices.Clone(list)
+		}
+	}
+	return &copied
+}
@@ -0,0 +1,99 @@
+package database
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+// 開発・テスト用のインメモリリフレッシュトークン
+type MemoryRefreshTokenRepository struct {
+	mu     sync.RWMutex
+	tokens []*entity.RefreshToken
+	lastID int64
+}
+
+func NewMemoryRefreshTokenRepository() *MemoryRefreshTokenRepository {
+	return &MemoryRefreshTokenRepository{}
+}
+
+func (r *MemoryRefreshTokenRepository) Create(ctx context.Context, token *entity.RefreshToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.tokens {
+		if existing.TokenHash == token.TokenHash {
+			return domainErrors.ErrDuplicateEntry
+		}
+	}
+
+	r.lastID++
+	token.ID = r.lastID
+	r.tokens = append(r.tokens, copyRefreshToken(token))
+
+	return nil
+}
+
+func (r *MemoryRefreshTokenRepository) FindByTokenHash(ctx context.Context, tokenHash string) (*entity.RefreshToken, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, token := range r.tokens {
+		if token.TokenHash == tokenHash {
+			return copyRefreshToken(token), nil
+		}
+	}
+	return nil, domainErrors.ErrRefreshTokenNotFound
+}
+
+func (r *MemoryRefreshTokenRepository) MarkUsed(ctx context.Context, id int64, at time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, token := range r.tokens {
+		if token.ID == id && token.UsedAt == nil {
+			token.UsedAt = &at
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *MemoryRefreshTokenRepository) RevokeFamily(ctx context.Context, familyID string, at time.Time) error {
+	r.revoke(at, func(token *entity.RefreshToken) bool { return token.FamilyID == familyID })
+	return nil
+}
+
+func (r *MemoryRefreshTokenRepository) RevokeByUser(ctx context.Context, userID int64, at time.Time) error {
+	r.revoke(at, func(token *entity.RefreshToken) bool { return token.UserID == userID })
+	return nil
+}
+
+func (r *MemoryRefreshTokenRepository) revoke(at time.Time, match func(*entity.RefreshToken) bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, token := range r.tokens {
+		if token.RevokedAt == nil && match(token) {
+			token.RevokedAt = &at
+		}
+	}
+}
+
+// トークンは発行時にだけ返すため、保存するコピーからは取り除く
+func copyRefreshToken(token *entity.RefreshToken) *entity.RefreshToken {
+	copied := *token
+	copied.Token = ""
+	if token.UsedAt != nil {
+		usedAt := *token.UsedAt
+		copied.UsedAt = &usedAt
+	}
+	if token.RevokedAt != nil {
+		revokedAt := *token.RevokedAt
+		copied.RevokedAt = &revokedAt
+	}
+	return &copied
+}
@@ -0,0 +1,32 @@
+package database
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// 開発・テスト用のインメモリの複製の進み具合
+type MemoryReplicationCheckpointRepository struct {
+	mu        sync.RWMutex
+	sequences map[string]int64
+}
+
+func NewMemoryReplicationCheckpointRepository() *MemoryReplicationCheckpointRepository {
+	return &MemoryReplicationCheckpointRepository{sequences: make(map[string]int64)}
+}
+
+func (r *MemoryReplicationCheckpointRepository) Load(ctx context.Context, source string) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.sequences[source], nil
+}
+
+func (r *MemoryReplicationCheckpointRepository) Save(ctx context.Context, source string, sequence int64, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.sequences[source] = sequence
+	return nil
+}
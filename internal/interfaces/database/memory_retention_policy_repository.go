@@ -0,0 +1,42 @@
+package database
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"Aicon-assignment/internal/domain/entity"
+)
+
+// 開発・テスト用のインメモリ保持期間設定
+type MemoryRetentionPolicyRepository struct {
+	mu       sync.RWMutex
+	policies map[string]entity.RetentionPolicy
+}
+
+func NewMemoryRetentionPolicyRepository() *MemoryRetentionPolicyRepository {
+	return &MemoryRetentionPolicyRepository{policies: make(map[string]entity.RetentionPolicy)}
+}
+
+func (r *MemoryRetentionPolicyRepository) FindAll(ctx context.Context) ([]*entity.RetentionPolicy, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	policies := make([]*entity.RetentionPolicy, 0, len(r.policies))
+	for _, policy := range r.policies {
+		copied := policy
+		policies = append(policies, &copied)
+	}
+	sort.Slice(policies, func(i, j int) bool {
+		return policies[i].DataType < policies[j].DataType
+	})
+	return policies, nil
+}
+
+func (r *MemoryRetentionPolicyRepository) Save(ctx context.Context, policy *entity.RetentionPolicy) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.policies[policy.DataType] = *policy
+	return nil
+}
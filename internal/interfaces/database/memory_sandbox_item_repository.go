@@ -0,0 +1,222 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/pkg/clock"
+	"Aicon-assignment/internal/pkg/idgen"
+	"Aicon-assignment/internal/pkg/reqctx"
+)
+
+// サンドボックスのアイテム。API キーごと（reqctx.Sandbox）に別々のインメモリのデータセットを持つ
+// データセットは最初に使ったときに架空のアイテム（entity.SandboxItems）で作り、Reset で捨てる
+// 本番のデータとは共有しないため、MySQL を使う構成でもメモリに置く
+type SandboxItemRepository struct {
+	mu       sync.Mutex
+	datasets map[string]*MemoryItemRepository
+	clock    clock.Clock
+}
+
+func NewSandboxItemRepository(clock clock.Clock) *SandboxItemRepository {
+	return &SandboxItemRepository{
+		datasets: make(map[string]*MemoryItemRepository),
+		clock:    clock,
+	}
+}
+
+// すべてのデータセットを捨て、捨てた数を返す。次に使ったときに最初の状態から作り直す
+func (r *SandboxItemRepository) Reset() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n := len(r.datasets)
+	r.datasets = make(map[string]*MemoryItemRepository)
+	return n
+}
+
+// 呼び出し元のサンドボックスのデータセット。サンドボックスのキーで呼び出していない場合はエラー
+func (r *SandboxItemRepository) dataset(ctx context.Context) (*MemoryItemRepository, error) {
+	sandboxID, ok := reqctx.Sandbox(ctx)
+	if !ok {
+		return nil, fmt.Errorf("%w: sandbox api key required", domainErrors.ErrUnauthenticated)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if dataset, ok := r.datasets[sandboxID]; ok {
+		return dataset, nil
+	}
+	ownerID, ok := reqctx.UserID(ctx)
+	if !ok {
+		return nil, fmt.Errorf("%w: sandbox api key required", domainErrors.ErrUnauthenticated)
+	}
+	dataset := NewMemoryItemRepository(idgen.NewSequence(), entity.SandboxItems(ownerID, r.clock.Now())...)
+	r.datasets[sandboxID] = dataset
+	return dataset, nil
+}
+
+func (r *SandboxItemRepository) FindAll(ctx context.Context) ([]*entity.Item, error) {
+	dataset, err := r.dataset(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return dataset.FindAll(ctx)
+}
+
+func (r *SandboxItemRepository) FindByOrganization(ctx context.Context, orgID int64) ([]*entity.Item, error) {
+	dataset, err := r.dataset(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return dataset.FindByOrganization(ctx, orgID)
+}
+
+func (r *SandboxItemRepository) FindByQuery(ctx context.Context, q entity.ItemQuery) ([]*entity.Item, error) {
+	dataset, err := r.dataset(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return dataset.FindByQuery(ctx, q)
+}
+
+func (r *SandboxItemRepository) Search(ctx context.Context, search entity.ItemSearch) ([]*entity.Item, error) {
+	dataset, err := r.dataset(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return dataset.Search(ctx, search)
+}
+
+func (r *SandboxItemRepository) CountByQuery(ctx context.Context, q entity.ItemQuery) (int, error) {
+	dataset, err := r.dataset(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return dataset.CountByQuery(ctx, q)
+}
+
+func (r *SandboxItemRepository) FindByID(ctx context.Context, id int64) (*entity.Item, error) {
+	dataset, err := r.dataset(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return dataset.FindByID(ctx, id)
+}
+
+func (r *SandboxItemRepository) FindByIDIncludingDeleted(ctx context.Context, id int64) (*entity.Item, error) {
+	dataset, err := r.dataset(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return dataset.FindByIDIncludingDeleted(ctx, id)
+}
+
+func (r *SandboxItemRepository) Create(ctx context.Context, item *entity.Item) (*entity.Item, error) {
+	dataset, err := r.dataset(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return dataset.Create(ctx, item)
+}
+
+func (r *SandboxItemRepository) Update(ctx context.Context, item *entity.Item) (*entity.Item, error) {
+	dataset, err := r.dataset(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return dataset.Update(ctx, item)
+}
+
+func (r *SandboxItemRepository) RecordPriceChange(ctx context.Context, change *entity.PriceChange) error {
+	dataset, err := r.dataset(ctx)
+	if err != nil {
+		return err
+	}
+	return dataset.RecordPriceChange(ctx, change)
+}
+
+func (r *SandboxItemRepository) FindPriceHistory(ctx context.Context, itemID int64) ([]*entity.PriceChange, error) {
+	dataset, err := r.dataset(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return dataset.FindPriceHistory(ctx, itemID)
+}
+
+func (r *SandboxItemRepository) MergeInto(ctx context.Context, sourceID, targetID int64, at time.Time) error {
+	dataset, err := r.dataset(ctx)
+	if err != nil {
+		return err
+	}
+	return dataset.MergeInto(ctx, sourceID, targetID, at)
+}
+
+func (r *SandboxItemRepository) AssignOwner(ctx context.Context, id, ownerID int64) error {
+	dataset, err := r.dataset(ctx)
+	if err != nil {
+		return err
+	}
+	return dataset.AssignOwner(ctx, id, ownerID)
+}
+
+func (r *SandboxItemRepository) SoftDelete(ctx context.Context, id int64, at time.Time) error {
+	dataset, err := r.dataset(ctx)
+	if err != nil {
+		return err
+	}
+	return dataset.SoftDelete(ctx, id, at)
+}
+
+func (r *SandboxItemRepository) CountDeletedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	dataset, err := r.dataset(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return dataset.CountDeletedBefore(ctx, cutoff)
+}
+
+func (r *SandboxItemRepository) FindIDsDeletedBefore(ctx context.Context, cutoff time.Time) ([]int64, error) {
+	dataset, err := r.dataset(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return dataset.FindIDsDeletedBefore(ctx, cutoff)
+}
+
+func (r *SandboxItemRepository) PurgeDeletedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	dataset, err := r.dataset(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return dataset.PurgeDeletedBefore(ctx, cutoff)
+}
+
+func (r *SandboxItemRepository) Purge(ctx context.Context, id int64) error {
+	dataset, err := r.dataset(ctx)
+	if err != nil {
+		return err
+	}
+	return dataset.Purge(ctx, id)
+}
+
+func (r *SandboxItemRepository) GetSummaryBy(ctx context.Context, dim entity.SummaryDimension) (map[string]int, error) {
+	dataset, err := r.dataset(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return dataset.GetSummaryBy(ctx, dim)
+}
+
+func (r *SandboxItemRepository) SummarizeByOrganization(ctx context.Context, orgIDs []int64) (map[int64]*entity.OrganizationItemSummary, error) {
+	dataset, err := r.dataset(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return dataset.SummarizeByOrganization(ctx, orgIDs)
+}
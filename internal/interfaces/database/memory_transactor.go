@@ -0,0 +1,11 @@
+package database
+
+import "context"
+
+// インメモリリポジトリ用のトランザクション
+// ロールバックはできないため、途中で失敗した場合はそれまでの変更が残る
+type MemoryTransactor struct{}
+
+func (MemoryTransactor) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
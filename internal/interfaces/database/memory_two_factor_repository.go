@@ -0,0 +1,56 @@
+package database
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+// 開発・テスト用のインメモリの二要素認証の設定
+type MemoryTwoFactorRepository struct {
+	mu       sync.RWMutex
+	settings map[int64]*entity.TwoFactor
+}
+
+func NewMemoryTwoFactorRepository() *MemoryTwoFactorRepository {
+	return &MemoryTwoFactorRepository{settings: map[int64]*entity.TwoFactor{}}
+}
+
+func (r *MemoryTwoFactorRepository) FindByUser(ctx context.Context, userID int64) (*entity.TwoFactor, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	twoFactor, ok := r.settings[userID]
+	if !ok {
+		return nil, domainErrors.ErrTwoFactorNotFound
+	}
+	return copyTwoFactor(twoFactor), nil
+}
+
+func (r *MemoryTwoFactorRepository) Save(ctx context.Context, twoFactor *entity.TwoFactor) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.settings[twoFactor.UserID] = copyTwoFactor(twoFactor)
+	return nil
+}
+
+func (r *MemoryTwoFactorRepository) Delete(ctx context.Context, userID int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.settings[userID]; !ok {
+		return domainErrors.ErrTwoFactorNotFound
+	}
+	delete(r.settings, userID)
+	return nil
+}
+
+func copyTwoFactor(twoFactor *entity.TwoFactor) *entity.TwoFactor {
+	copied := *twoFactor
+	copied.RecoveryCodeHashes = slices.Clone(twoFactor.RecoveryCodeHashes)
+	return &copied
+}
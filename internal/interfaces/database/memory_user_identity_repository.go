@@ -0,0 +1,80 @@
+package database
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+// 開発・テスト用のインメモリの外部 ID
+type MemoryUserIdentityRepository struct {
+	mu         sync.RWMutex
+	identities []*entity.UserIdentity
+	lastID     int64
+}
+
+func NewMemoryUserIdentityRepository() *MemoryUserIdentityRepository {
+	return &MemoryUserIdentityRepository{}
+}
+
+func (r *MemoryUserIdentityRepository) Create(ctx context.Context, identity *entity.UserIdentity) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.identities {
+		if existing.Provider == identity.Provider && existing.Subject == identity.Subject {
+			return domainErrors.ErrDuplicateEntry
+		}
+	}
+
+	r.lastID++
+	identity.ID = r.lastID
+	copied := *identity
+	r.identities = append(r.identities, &copied)
+
+	return nil
+}
+
+func (r *MemoryUserIdentityRepository) FindBySubject(ctx context.Context, provider, subject string) (*entity.UserIdentity, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, identity := range r.identities {
+		if identity.Provider == provider && identity.Subject == subject {
+			copied := *identity
+			return &copied, nil
+		}
+	}
+	return nil, domainErrors.ErrIdentityNotFound
+}
+
+func (r *MemoryUserIdentityRepository) FindByUser(ctx context.Context, userID int64) ([]*entity.UserIdentity, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	identities := []*entity.UserIdentity{}
+	for _, identity := range r.identities {
+		if identity.UserID == userID {
+			copied := *identity
+			identities = append(identities, &copied)
+		}
+	}
+	return identities, nil
+}
+
+func (r *MemoryUserIdentityRepository) Delete(ctx context.Context, userID, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := slices.IndexFunc(r.identities, func(identity *entity.UserIdentity) bool {
+		return identity.ID == id && identity.UserID == userID
+	})
+	if i < 0 {
+		return domainErrors.ErrIdentityNotFound
+	}
+	r.identities = slices.Delete(r.identities, i, i+1)
+	return nil
+}
@@ -0,0 +1,113 @@
+package database
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+// 開発・テスト用のインメモリユーザーリポジトリ
+type MemoryUserRepository struct {
+	mu     sync.RWMutex
+	users  []*entity.User
+	lastID int64
+}
+
+func NewMemoryUserRepository(seed ...*entity.User) *MemoryUserRepository {
+	r := &MemoryUserRepository{}
+	for _, user := range seed {
+		r.lastID++
+		user.ID = r.lastID
+		copied := *user
+		r.users = append(r.users, &copied)
+	}
+	return r
+}
+
+func (r *MemoryUserRepository) Create(ctx context.Context, user *entity.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.userNameTaken(user.UserName, 0) {
+		return domainErrors.ErrDuplicateEntry
+	}
+
+	r.lastID++
+	user.ID = r.lastID
+	copied := *user
+	r.users = append(r.users, &copied)
+
+	return nil
+}
+
+func (r *MemoryUserRepository) FindByID(ctx context.Context, id int64) (*entity.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, user := range r.users {
+		if user.ID == id {
+			copied := *user
+			return &copied, nil
+		}
+	}
+	return nil, domainErrors.ErrUserNotFound
+}
+
+func (r *MemoryUserRepository) FindByQuery(ctx context.Context, q entity.UserQuery) ([]*entity.User, error) {
+	users := r.matching(q)
+	if q.Limit > 0 {
+		start := min(q.Offset, len(users))
+		users = users[start:min(start+q.Limit, len(users))]
+	}
+	return users, nil
+}
+
+func (r *MemoryUserRepository) CountByQuery(ctx context.Context, q entity.UserQuery) (int, error) {
+	return len(r.matching(q)), nil
+}
+
+// ID の昇順に格納しているため、並び替えは不要
+func (r *MemoryUserRepository) matching(q entity.UserQuery) []*entity.User {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	users := []*entity.User{}
+	for _, user := range r.users {
+		if q.Matches(user) {
+			copied := *user
+			users = append(users, &copied)
+		}
+	}
+	return users
+}
+
+func (r *MemoryUserRepository) Update(ctx context.Context, user *entity.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.userNameTaken(user.UserName, user.ID) {
+		return domainErrors.ErrDuplicateEntry
+	}
+
+	for i, stored := range r.users {
+		if stored.ID == user.ID {
+			copied := *user
+			r.users[i] = &copied
+			return nil
+		}
+	}
+	return domainErrors.ErrUserNotFound
+}
+
+// MySQL の照合順序に合わせて大文字小文字を区別しない
+func (r *MemoryUserRepository) userNameTaken(userName string, exceptID int64) bool {
+	for _, user := range r.users {
+		if user.ID != exceptID && strings.EqualFold(user.UserName, userName) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,144 @@
+package database
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+// 開発・テスト用のインメモリ配信記録リポジトリ
+type MemoryWebhookDeliveryRepository struct {
+	mu         sync.RWMutex
+	deliveries []*entity.WebhookDelivery
+	lastID     int64
+}
+
+func NewMemoryWebhookDeliveryRepository() *MemoryWebhookDeliveryRepository {
+	return &MemoryWebhookDeliveryRepository{}
+}
+
+func (r *MemoryWebhookDeliveryRepository) Record(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lastID++
+	delivery.ID = r.lastID
+	r.deliveries = append(r.deliveries, copyWebhookDelivery(delivery))
+
+	return nil
+}
+
+func (r *MemoryWebhookDeliveryRepository) FindByID(ctx context.Context, id int64) (*entity.WebhookDelivery, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, delivery := range r.deliveries {
+		if delivery.ID == id {
+			return copyWebhookDelivery(delivery), nil
+		}
+	}
+	return nil, domainErrors.ErrDeliveryNotFound
+}
+
+func (r *MemoryWebhookDeliveryRepository) FindByWebhookID(ctx context.Context, webhookID int64, status string) ([]*entity.WebhookDelivery, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	deliveries := []*entity.WebhookDelivery{}
+	for _, delivery := range r.deliveries {
+		if delivery.WebhookID != webhookID || !matchesDeliveryStatus(delivery, status) {
+			continue
+		}
+		deliveries = append(deliveries, copyWebhookDelivery(delivery))
+	}
+	sort.SliceStable(deliveries, func(i, j int) bool {
+		if !deliveries[i].CreatedAt.Equal(deliveries[j].CreatedAt) {
+			return deliveries[i].CreatedAt.After(deliveries[j].CreatedAt)
+		}
+		return deliveries[i].ID > deliveries[j].ID
+	})
+
+	return deliveries, nil
+}
+
+func (r *MemoryWebhookDeliveryRepository) GetStats(ctx context.Context, webhookID int64) (*entity.WebhookDeliveryStats, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	stats := &entity.WebhookDeliveryStats{WebhookID: webhookID}
+	var totalDuration int64
+	for _, delivery := range r.deliveries {
+		if delivery.WebhookID != webhookID {
+			continue
+		}
+		stats.Total++
+		if delivery.Success {
+			stats.Succeeded++
+		} else {
+			stats.Failed++
+		}
+		totalDuration += delivery.DurationMs
+		if stats.LastDeliveryAt == nil || delivery.CreatedAt.After(*stats.LastDeliveryAt) {
+			createdAt := delivery.CreatedAt
+			stats.LastDeliveryAt = &createdAt
+		}
+	}
+	if stats.Total > 0 {
+		stats.AvgDurationMs = float64(totalDuration) / float64(stats.Total)
+	}
+
+	return stats, nil
+}
+
+func (r *MemoryWebhookDeliveryRepository) CountBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var count int64
+	for _, delivery := range r.deliveries {
+		if delivery.CreatedAt.Before(cutoff) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *MemoryWebhookDeliveryRepository) DeleteBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	kept := r.deliveries[:0]
+	for _, delivery := range r.deliveries {
+		if !delivery.CreatedAt.Before(cutoff) {
+			kept = append(kept, delivery)
+		}
+	}
+	deleted := int64(len(r.deliveries) - len(kept))
+	r.deliveries = kept
+	return deleted, nil
+}
+
+func matchesDeliveryStatus(delivery *entity.WebhookDelivery, status string) bool {
+	switch status {
+	case entity.DeliveryStatusSuccess:
+		return delivery.Success
+	case entity.DeliveryStatusFailed:
+		return !delivery.Success
+	default:
+		return true
+	}
+}
+
+func copyWebhookDelivery(delivery *entity.WebhookDelivery) *entity.WebhookDelivery {
+	copied := *delivery
+	copied.Payload = append([]byte(nil), delivery.Payload...)
+	if delivery.RedeliveryOf != nil {
+		redeliveryOf := *delivery.RedeliveryOf
+		copied.RedeliveryOf = &redeliveryOf
+	}
+	return &copied
+}
@@ -0,0 +1,96 @@
+package database
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/pkg/idgen"
+)
+
+// 開発・テスト用のインメモリWebhookリポジトリ
+type MemoryWebhookRepository struct {
+	mu       sync.RWMutex
+	webhooks map[int64]*entity.Webhook
+	ids      idgen.IDGenerator
+}
+
+func NewMemoryWebhookRepository(ids idgen.IDGenerator) *MemoryWebhookRepository {
+	return &MemoryWebhookRepository{
+		webhooks: make(map[int64]*entity.Webhook),
+		ids:      ids,
+	}
+}
+
+func (r *MemoryWebhookRepository) FindAll(ctx context.Context) ([]*entity.Webhook, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	webhooks := make([]*entity.Webhook, 0, len(r.webhooks))
+	for _, webhook := range r.webhooks {
+		webhooks = append(webhooks, copyWebhook(webhook))
+	}
+	sort.Slice(webhooks, func(i, j int) bool { return webhooks[i].ID < webhooks[j].ID })
+
+	return webhooks, nil
+}
+
+func (r *MemoryWebhookRepository) FindByID(ctx context.Context, id int64) (*entity.Webhook, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	webhook, ok := r.webhooks[id]
+	if !ok {
+		return nil, domainErrors.ErrWebhookNotFound
+	}
+	return copyWebhook(webhook), nil
+}
+
+func (r *MemoryWebhookRepository) Create(ctx context.Context, webhook *entity.Webhook) (*entity.Webhook, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := copyWebhook(webhook)
+	stored.ID = r.ids.NextID()
+	r.webhooks[stored.ID] = stored
+
+	return copyWebhook(stored), nil
+}
+
+func (r *MemoryWebhookRepository) Update(ctx context.Context, webhook *entity.Webhook) (*entity.Webhook, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.webhooks[webhook.ID]
+	if !ok {
+		return nil, domainErrors.ErrWebhookNotFound
+	}
+
+	updated := copyWebhook(webhook)
+	updated.Secret = existing.Secret
+	updated.CreatedAt = existing.CreatedAt
+	r.webhooks[webhook.ID] = updated
+
+	return copyWebhook(updated), nil
+}
+
+func (r *MemoryWebhookRepository) Delete(ctx context.Context, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.webhooks[id]; !ok {
+		return domainErrors.ErrWebhookNotFound
+	}
+	delete(r.webhooks, id)
+
+	return nil
+}
+
+func copyWebhook(webhook *entity.Webhook) *entity.Webhook {
+	copied := *webhook
+	copied.Events = append([]string(nil), webhook.Events...)
+	copied.WatchFields = append([]string(nil), webhook.WatchFields...)
+	return &copied
+}
@@ -0,0 +1,79 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+type NotificationPreferenceRepository struct {
+	SqlHandler
+}
+
+func (r *NotificationPreferenceRepository) FindByUser(ctx context.Context, userID int64) (*entity.NotificationPreference, error) {
+	query := `
+        SELECT user_id, channels, line_user_id, push_tokens, updated_at
+        FROM notification_preferences
+        WHERE user_id = ?
+    `
+
+	var preference entity.NotificationPreference
+	var channels, pushTokens string
+	err := r.QueryRow(ctx, query, userID).Scan(
+		&preference.UserID,
+		&channels,
+		&preference.LINEUserID,
+		&pushTokens,
+		&preference.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domainErrors.ErrPreferenceNotFound
+		}
+		return nil, wrapError(err)
+	}
+
+	if err := json.Unmarshal([]byte(channels), &preference.Channels); err != nil {
+		return nil, fmt.Errorf("failed to decode notification channels of user %d: %w", userID, err)
+	}
+	if err := json.Unmarshal([]byte(pushTokens), &preference.PushTokens); err != nil {
+		return nil, fmt.Errorf("failed to decode push tokens of user %d: %w", userID, err)
+	}
+
+	return &preference, nil
+}
+
+func (r *NotificationPreferenceRepository) Save(ctx context.Context, preference *entity.NotificationPreference) error {
+	channels, err := json.Marshal(preference.Channels)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification channels: %w", err)
+	}
+	pushTokens, err := json.Marshal(preference.PushTokens)
+	if err != nil {
+		return fmt.Errorf("failed to encode push tokens: %w", err)
+	}
+
+	query := `
+        INSERT INTO notification_preferences (user_id, channels, line_user_id, push_tokens, updated_at)
+        VALUES (?, ?, ?, ?, ?)
+        ON DUPLICATE KEY UPDATE
+            channels = VALUES(channels), line_user_id = VALUES(line_user_id),
+            push_tokens = VALUES(push_tokens), updated_at = VALUES(updated_at)
+    `
+	_, err = r.Execute(ctx, query,
+		preference.UserID,
+		string(channels),
+		preference.LINEUserID,
+		string(pushTokens),
+		preference.UpdatedAt,
+	)
+	if err != nil {
+		return wrapError(err)
+	}
+
+	return nil
+}
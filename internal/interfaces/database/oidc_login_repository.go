@@ -0,0 +1,94 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+type OIDCLoginRepository struct {
+	SqlHandler
+}
+
+func (r *OIDCLoginRepository) Create(ctx context.Context, login *entity.OIDCLogin) error {
+	query := `
+        INSERT INTO oidc_logins (state_hash, provider, nonce, code_verifier, user_id, created_at, expires_at)
+        VALUES (?, ?, ?, ?, ?, ?, ?)
+    `
+
+	_, err := r.Execute(ctx, query,
+		login.StateHash,
+		login.Provider,
+		login.Nonce,
+		login.CodeVerifier,
+		login.UserID,
+		login.CreatedAt,
+		login.ExpiresAt,
+	)
+	if err != nil {
+		return wrapError(err)
+	}
+
+	return nil
+}
+
+// 同時に同じ state で戻ってきた場合も、削除できた 1 つだけが要求を受け取る
+func (r *OIDCLoginRepository) Take(ctx context.Context, stateHash string) (*entity.OIDCLogin, error) {
+	query := `
+        SELECT state_hash, provider, nonce, code_verifier, user_id, created_at, expires_at
+        FROM oidc_logins WHERE state_hash = ?
+    `
+
+	var login entity.OIDCLogin
+	var userID sql.NullInt64
+	err := r.QueryRow(ctx, query, stateHash).Scan(
+		&login.StateHash,
+		&login.Provider,
+		&login.Nonce,
+		&login.CodeVerifier,
+		&userID,
+		&login.CreatedAt,
+		&login.ExpiresAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domainErrors.ErrOIDCLoginNotFound
+		}
+		return nil, wrapError(err)
+	}
+	if userID.Valid {
+		login.UserID = &userID.Int64
+	}
+
+	result, err := r.Execute(ctx, `DELETE FROM oidc_logins WHERE state_hash = ?`, stateHash)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to get rows affected: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	if rowsAffected == 0 {
+		return nil, domainErrors.ErrOIDCLoginNotFound
+	}
+
+	return &login, nil
+}
+
+func (r *OIDCLoginRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.Execute(ctx, `DELETE FROM oidc_logins WHERE expires_at < ?`, before)
+	if err != nil {
+		return 0, wrapError(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%w: failed to get rows affected: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+
+	return rowsAffected, nil
+}
@@ -0,0 +1,140 @@
+package database
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+// 後から追加した、マイグレーションを適用していない古いスキーマにはないことがある列
+// ない場合、リポジトリは読み込みでは Default を使い、書き込みでは列を省く
+// 既定値以外を書き込む必要がある操作（その列を使う機能）は ErrInvalidInput にする
+type OptionalColumn struct {
+	Table   string
+	Column  string
+	Default string // 列がない場合に SELECT で代わりに使う式
+}
+
+func (c OptionalColumn) String() string {
+	return c.Table + "." + c.Column
+}
+
+var (
+	ColumnWebhookWatchFields    = OptionalColumn{Table: "webhooks", Column: "watch_fields", Default: "''"}
+	ColumnDeliverySchemaVersion = OptionalColumn{Table: "webhook_deliveries", Column: "schema_version", Default: "''"}
+	ColumnWebhookOwner          = OptionalColumn{Table: "webhooks", Column: "owner_id", Default: "NULL"}
+)
+
+// "table.column" をカンマで区切って並べる
+func JoinColumns(columns []OptionalColumn) string {
+	names := make([]string, len(columns))
+	for i, column := range columns {
+		names[i] = column.String()
+	}
+	return strings.Join(names, ", ")
+}
+
+// 起動時に確かめる任意の列。新しい列を段階的に公開する場合はここに加える
+var OptionalColumns = []OptionalColumn{
+	ColumnWebhookWatchFields,
+	ColumnDeliverySchemaVersion,
+	ColumnWebhookOwner,
+}
+
+// DB にある任意の列。確かめるまではすべてあるものとして扱う
+// nil の場合もすべてあるものとして扱うため、スキーマを確かめない環境ではリポジトリに渡さなくてよい
+type SchemaColumns struct {
+	mu      sync.RWMutex
+	missing map[OptionalColumn]bool
+}
+
+func NewSchemaColumns() *SchemaColumns {
+	return &SchemaColumns{missing: make(map[OptionalColumn]bool)}
+}
+
+// 確かめた結果で置き換える
+func (s *SchemaColumns) SetMissing(columns []OptionalColumn) {
+	missing := make(map[OptionalColumn]bool, len(columns))
+	for _, column := range columns {
+		missing[column] = true
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.missing = missing
+}
+
+func (s *SchemaColumns) Has(column OptionalColumn) bool {
+	if s == nil {
+		return true
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return !s.missing[column]
+}
+
+// ない列を "table.column" の順に返す
+func (s *SchemaColumns) Missing() []OptionalColumn {
+	if s == nil {
+		return nil
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	missing := make([]OptionalColumn, 0, len(s.missing))
+	for column := range s.missing {
+		missing = append(missing, column)
+	}
+	sort.Slice(missing, func(i, j int) bool { return missing[i].String() < missing[j].String() })
+	return missing
+}
+
+// SELECT で使う列。ない場合は既定値を同じ名前で返す
+func (s *SchemaColumns) selectExpr(column OptionalColumn) string {
+	if s.Has(column) {
+		return column.Column
+	}
+	return column.Default + " AS " + column.Column
+}
+
+// INSERT・UPDATE で書き込む列と値
+type columnValues struct {
+	columns *SchemaColumns
+	names   []string
+	values  []any
+}
+
+func (v *columnValues) add(name string, value any) {
+	v.names = append(v.names, name)
+	v.values = append(v.values, value)
+}
+
+// 列がある場合だけ加える。ない場合、既定値なら省き、そうでなければ ErrInvalidInput を返す
+func (v *columnValues) addOptional(column OptionalColumn, value any, isDefault bool) error {
+	if v.columns.Has(column) {
+		v.add(column.Column, value)
+		return nil
+	}
+	if isDefault {
+		return nil
+	}
+	return fmt.Errorf("%w: %s is not available until the database migration adding %s is applied", domainErrors.ErrInvalidInput, column.Column, column)
+}
+
+// 列がある場合だけ加える。ない場合は値を捨てる（記録できなくても操作は続けられる列）
+func (v *columnValues) addIfPresent(column OptionalColumn, value any) {
+	if v.columns.Has(column) {
+		v.add(column.Column, value)
+	}
+}
+
+// INSERT の列の一覧とプレースホルダー
+func (v *columnValues) insert() (string, string) {
+	return strings.Join(v.names, ", "), strings.TrimSuffix(strings.Repeat("?, ", len(v.names)), ", ")
+}
+
+// UPDATE の SET 句
+func (v *columnValues) assignments() string {
+	return strings.Join(v.names, " = ?, ") + " = ?"
+}
@@ -0,0 +1,281 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+type OrganizationRepository struct {
+	SqlHandler
+}
+
+func (r *OrganizationRepository) Create(ctx context.Context, org *entity.Organization) error {
+	result, err := r.Execute(ctx, `INSERT INTO organizations (name, created_at) VALUES (?, ?)`, org.Name, org.CreatedAt)
+	if err != nil {
+		return wrapError(err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("%w: failed to get last insert id: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	org.ID = id
+
+	return nil
+}
+
+func (r *OrganizationRepository) FindByID(ctx context.Context, id int64) (*entity.Organization, error) {
+	var org entity.Organization
+	err := r.QueryRow(ctx, `SELECT id, name, created_at FROM organizations WHERE id = ?`, id).
+		Scan(&org.ID, &org.Name, &org.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domainErrors.ErrOrganizationNotFound
+		}
+		return nil, wrapError(err)
+	}
+
+	return &org, nil
+}
+
+func (r *OrganizationRepository) FindPage(ctx context.Context, limit, offset int) ([]*entity.Organization, error) {
+	query := `SELECT id, name, created_at FROM organizations ORDER BY id`
+	var args []any
+	if limit > 0 {
+		query += ` LIMIT ? OFFSET ?`
+		args = append(args, limit, offset)
+	}
+
+	rows, err := r.Query(ctx, query, args...)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer rows.Close()
+
+	orgs := []*entity.Organization{}
+	for rows.Next() {
+		var org entity.Organization
+		if err := rows.Scan(&org.ID, &org.Name, &org.CreatedAt); err != nil {
+			return nil, wrapError(err)
+		}
+		orgs = append(orgs, &org)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, wrapError(err)
+	}
+
+	return orgs, nil
+}
+
+func (r *OrganizationRepository) Count(ctx context.Context) (int, error) {
+	var count int
+	if err := r.QueryRow(ctx, `SELECT COUNT(*) FROM organizations`).Scan(&count); err != nil {
+		return 0, wrapError(err)
+	}
+	return count, nil
+}
+
+const membershipColumns = `organization_id, user_id, role, created_at, updated_at`
+
+func (r *OrganizationRepository) FindMembers(ctx context.Context, orgID int64) ([]*entity.Membership, error) {
+	query := `SELECT ` + membershipColumns + ` FROM organization_members WHERE organization_id = ? ORDER BY user_id`
+
+	rows, err := r.Query(ctx, query, orgID)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer rows.Close()
+
+	members := []*entity.Membership{}
+	for rows.Next() {
+		member, err := scanMembership(rows)
+		if err != nil {
+			return nil, wrapError(err)
+		}
+		members = append(members, member)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, wrapError(err)
+	}
+
+	return members, nil
+}
+
+func (r *OrganizationRepository) FindMember(ctx context.Context, orgID, userID int64) (*entity.Membership, error) {
+	query := `SELECT ` + membershipColumns + ` FROM organization_members WHERE organization_id = ? AND user_id = ?`
+
+	member, err := scanMembership(r.QueryRow(ctx, query, orgID, userID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domainErrors.ErrMemberNotFound
+		}
+		return nil, wrapError(err)
+	}
+
+	return member, nil
+}
+
+func (r *OrganizationRepository) AddMember(ctx context.Context, member *entity.Membership) error {
+	query := `
+        INSERT INTO organization_members (organization_id, user_id, role, created_at, updated_at)
+        VALUES (?, ?, ?, ?, ?)
+    `
+
+	if _, err := r.Execute(ctx, query, member.OrgID, member.UserID, member.Role, member.CreatedAt, member.UpdatedAt); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+func (r *OrganizationRepository) UpdateMember(ctx context.Context, member *entity.Membership) error {
+	query := `UPDATE organization_members SET role = ?, updated_at = ? WHERE organization_id = ? AND user_id = ?`
+
+	result, err := r.Execute(ctx, query, member.Role, member.UpdatedAt, member.OrgID, member.UserID)
+	if err != nil {
+		return wrapError(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%w: failed to get rows affected: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	if rowsAffected == 0 {
+		return domainErrors.ErrMemberNotFound
+	}
+
+	return nil
+}
+
+func (r *OrganizationRepository) RemoveMember(ctx context.Context, orgID, userID int64) error {
+	result, err := r.Execute(ctx, `DELETE FROM organization_members WHERE organization_id = ? AND user_id = ?`, orgID, userID)
+	if err != nil {
+		return wrapError(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%w: failed to get rows affected: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	if rowsAffected == 0 {
+		return domainErrors.ErrMemberNotFound
+	}
+
+	return nil
+}
+
+// 同時に管理者を外す操作で管理者がいなくならないよう、トランザクション内では行をロックする
+func (r *OrganizationRepository) CountAdmins(ctx context.Context, orgID int64) (int, error) {
+	rows, err := r.Query(ctx, `
+        SELECT user_id FROM organization_members
+        WHERE organization_id = ? AND role = ?
+        FOR UPDATE
+    `, orgID, entity.OrgRoleAdmin)
+	if err != nil {
+		return 0, wrapError(err)
+	}
+	defer rows.Close()
+
+	count := 0
+	for rows.Next() {
+		count++
+	}
+	if err = rows.Err(); err != nil {
+		return 0, wrapError(err)
+	}
+
+	return count, nil
+}
+
+func (r *OrganizationRepository) FindBranding(ctx context.Context, orgID int64) (*entity.Branding, error) {
+	query := `
+        SELECT organization_id, display_name, logo_url, accent_color, updated_at
+        FROM organization_branding
+        WHERE organization_id = ?
+    `
+
+	var branding entity.Branding
+	err := r.QueryRow(ctx, query, orgID).
+		Scan(&branding.OrgID, &branding.DisplayName, &branding.LogoURL, &branding.AccentColor, &branding.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domainErrors.ErrBrandingNotFound
+		}
+		return nil, wrapError(err)
+	}
+
+	return &branding, nil
+}
+
+func (r *OrganizationRepository) SaveBranding(ctx context.Context, branding *entity.Branding) error {
+	query := `
+        INSERT INTO organization_branding (organization_id, display_name, logo_url, accent_color, updated_at)
+        VALUES (?, ?, ?, ?, ?)
+        ON DUPLICATE KEY UPDATE
+            display_name = VALUES(display_name),
+            logo_url = VALUES(logo_url),
+            accent_color = VALUES(accent_color),
+            updated_at = VALUES(updated_at)
+    `
+
+	_, err := r.Execute(ctx, query, branding.OrgID, branding.DisplayName, branding.LogoURL, branding.AccentColor, branding.UpdatedAt)
+	if err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+func (r *OrganizationRepository) FindFieldLockPolicy(ctx context.Context, orgID int64) (*entity.FieldLockPolicy, error) {
+	query := `SELECT rules, updated_at FROM organization_field_locks WHERE organization_id = ?`
+
+	var rules []byte
+	var updatedAt time.Time
+	err := r.QueryRow(ctx, query, orgID).Scan(&rules, &updatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return entity.NewFieldLockPolicy(orgID), nil
+		}
+		return nil, wrapError(err)
+	}
+
+	policy := entity.NewFieldLockPolicy(orgID)
+	if err := json.Unmarshal(rules, &policy.Rules); err != nil {
+		return nil, fmt.Errorf("%w: failed to decode field lock rules: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	policy.UpdatedAt = &updatedAt
+	return policy, nil
+}
+
+func (r *OrganizationRepository) SaveFieldLockPolicy(ctx context.Context, policy *entity.FieldLockPolicy) error {
+	rules, err := json.Marshal(policy.Rules)
+	if err != nil {
+		return fmt.Errorf("%w: failed to encode field lock rules: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+
+	query := `
+        INSERT INTO organization_field_locks (organization_id, rules, updated_at)
+        VALUES (?, ?, ?)
+        ON DUPLICATE KEY UPDATE rules = VALUES(rules), updated_at = VALUES(updated_at)
+    `
+	if _, err := r.Execute(ctx, query, policy.OrgID, rules, policy.UpdatedAt); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+func scanMembership(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*entity.Membership, error) {
+	var member entity.Membership
+	if err := scanner.Scan(&member.OrgID, &member.UserID, &member.Role, &member.CreatedAt, &member.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
@@ -0,0 +1,42 @@
+package database
+
+import (
+	"context"
+
+	"Aicon-assignment/internal/domain/entity"
+	"Aicon-assignment/internal/pkg/reqctx"
+)
+
+// 呼び出し元のユーザーのアイテムだけを対象にする条件（" AND ..." の形）
+// ユーザーのいないコンテキスト（定期実行や運用向けのサブコマンド）と reqctx.WithAllOwners のコンテキストでは
+// 全ユーザーのアイテムを対象にする
+func ownerScope(ctx context.Context) (string, []any) {
+	if userID, ok := scopedUser(ctx); ok {
+		return " AND owner_id = ?", []any{userID}
+	}
+	return "", nil
+}
+
+// 呼び出し元のユーザーから見えるアイテムか
+func visibleTo(ctx context.Context, item *entity.Item) bool {
+	userID, ok := scopedUser(ctx)
+	return !ok || (item.OwnerID != nil && *item.OwnerID == userID)
+}
+
+// 作成するアイテムの持ち主。指定がない場合は呼び出し元のユーザーにする
+func ownerOf(ctx context.Context, item *entity.Item) *int64 {
+	if item.OwnerID != nil {
+		return item.OwnerID
+	}
+	if userID, ok := scopedUser(ctx); ok {
+		return &userID
+	}
+	return nil
+}
+
+func scopedUser(ctx context.Context) (int64, bool) {
+	if reqctx.AllOwners(ctx) {
+		return 0, false
+	}
+	return reqctx.UserID(ctx)
+}
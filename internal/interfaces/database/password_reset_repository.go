@@ -0,0 +1,90 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+type PasswordResetRepository struct {
+	SqlHandler
+}
+
+const passwordResetColumns = `id, user_id, token_hash, created_at, expires_at, used_at`
+
+func (r *PasswordResetRepository) Create(ctx context.Context, token *entity.PasswordResetToken) error {
+	query := `
+        INSERT INTO password_reset_tokens (user_id, token_hash, created_at, expires_at)
+        VALUES (?, ?, ?, ?)
+    `
+
+	result, err := r.Execute(ctx, query,
+		token.UserID,
+		token.TokenHash,
+		token.CreatedAt,
+		token.ExpiresAt,
+	)
+	if err != nil {
+		return wrapError(err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("%w: failed to get last insert id: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	token.ID = id
+
+	return nil
+}
+
+func (r *PasswordResetRepository) FindByTokenHash(ctx context.Context, tokenHash string) (*entity.PasswordResetToken, error) {
+	query := `SELECT ` + passwordResetColumns + ` FROM password_reset_tokens WHERE token_hash = ?`
+
+	var token entity.PasswordResetToken
+	var usedAt sql.NullTime
+	err := r.QueryRow(ctx, query, tokenHash).Scan(
+		&token.ID,
+		&token.UserID,
+		&token.TokenHash,
+		&token.CreatedAt,
+		&token.ExpiresAt,
+		&usedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domainErrors.ErrPasswordResetNotFound
+		}
+		return nil, wrapError(err)
+	}
+	if usedAt.Valid {
+		token.UsedAt = &usedAt.Time
+	}
+
+	return &token, nil
+}
+
+func (r *PasswordResetRepository) MarkUsed(ctx context.Context, id int64, at time.Time) (bool, error) {
+	result, err := r.Execute(ctx, `UPDATE password_reset_tokens SET used_at = ? WHERE id = ? AND used_at IS NULL`, at, id)
+	if err != nil {
+		return false, wrapError(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("%w: failed to get rows affected: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+
+	return rowsAffected > 0, nil
+}
+
+func (r *PasswordResetRepository) MarkUsedByUser(ctx context.Context, userID int64, at time.Time) error {
+	query := `UPDATE password_reset_tokens SET used_at = ? WHERE user_id = ? AND used_at IS NULL`
+	if _, err := r.Execute(ctx, query, at, userID); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"Aicon-assignment/internal/domain/entity"
+)
+
+type PortfolioSnapshotRepository struct {
+	SqlHandler
+}
+
+func (r *PortfolioSnapshotRepository) Save(ctx context.Context, snapshot *entity.PortfolioSnapshot) error {
+	categories, err := json.Marshal(snapshot.Categories)
+	if err != nil {
+		return fmt.Errorf("failed to encode categories: %w", err)
+	}
+
+	query := `
+        INSERT INTO portfolio_snapshots (snapshot_date, item_count, purchase_value, current_value, categories, recorded_at)
+        VALUES (?, ?, ?, ?, ?, ?)
+        ON DUPLICATE KEY UPDATE
+            item_count = VALUES(item_count), purchase_value = VALUES(purchase_value),
+            current_value = VALUES(current_value), categories = VALUES(categories), recorded_at = VALUES(recorded_at)
+    `
+	_, err = r.Execute(ctx, query,
+		snapshot.Date,
+		snapshot.ItemCount,
+		snapshot.PurchaseValue,
+		snapshot.CurrentValue,
+		string(categories),
+		snapshot.RecordedAt,
+	)
+	if err != nil {
+		return wrapError(err)
+	}
+
+	return nil
+}
+
+func (r *PortfolioSnapshotRepository) FindSince(ctx context.Context, from string) ([]*entity.PortfolioSnapshot, error) {
+	query := `
+        SELECT DATE_FORMAT(snapshot_date, '%Y-%m-%d'), item_count, purchase_value, current_value, categories, recorded_at
+        FROM portfolio_snapshots
+        WHERE snapshot_date >= ?
+        ORDER BY snapshot_date
+    `
+
+	rows, err := r.Query(ctx, query, from)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer rows.Close()
+
+	snapshots := []*entity.PortfolioSnapshot{}
+	for rows.Next() {
+		var snapshot entity.PortfolioSnapshot
+		var categories string
+		err := rows.Scan(
+			&snapshot.Date,
+			&snapshot.ItemCount,
+			&snapshot.PurchaseValue,
+			&snapshot.CurrentValue,
+			&categories,
+			&snapshot.RecordedAt,
+		)
+		if err != nil {
+			return nil, wrapError(err)
+		}
+		if err := json.Unmarshal([]byte(categories), &snapshot.Categories); err != nil {
+			return nil, fmt.Errorf("failed to decode categories of %s: %w", snapshot.Date, err)
+		}
+		snapshots = append(snapshots, &snapshot)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, wrapError(err)
+	}
+
+	return snapshots, nil
+}
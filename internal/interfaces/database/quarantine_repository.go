@@ -0,0 +1,115 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+type QuarantineRepository struct {
+	SqlHandler
+}
+
+const quarantineColumns = `id, kind, item_id, filename, content_type, size, signature, blob_key, actor, created_at`
+
+func (r *QuarantineRepository) Create(ctx context.Context, file *entity.QuarantinedFile) error {
+	query := `
+        INSERT INTO quarantined_files (kind, item_id, filename, content_type, size, signature, blob_key, actor, created_at)
+        VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+    `
+
+	result, err := r.Execute(ctx, query,
+		file.Kind,
+		file.ItemID,
+		file.Filename,
+		file.ContentType,
+		file.Size,
+		file.Signature,
+		file.Key,
+		file.Actor,
+		file.CreatedAt,
+	)
+	if err != nil {
+		return wrapError(err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("%w: failed to get last insert id: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	file.ID = id
+
+	return nil
+}
+
+func (r *QuarantineRepository) FindAll(ctx context.Context) ([]*entity.QuarantinedFile, error) {
+	rows, err := r.Query(ctx, `SELECT `+quarantineColumns+` FROM quarantined_files ORDER BY id DESC`)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer rows.Close()
+
+	files := []*entity.QuarantinedFile{}
+	for rows.Next() {
+		file, err := scanQuarantinedFile(rows)
+		if err != nil {
+			return nil, wrapError(err)
+		}
+		files = append(files, file)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, wrapError(err)
+	}
+	return files, nil
+}
+
+func (r *QuarantineRepository) FindByID(ctx context.Context, id int64) (*entity.QuarantinedFile, error) {
+	file, err := scanQuarantinedFile(r.QueryRow(ctx, `SELECT `+quarantineColumns+` FROM quarantined_files WHERE id = ?`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domainErrors.ErrQuarantineNotFound
+		}
+		return nil, wrapError(err)
+	}
+	return file, nil
+}
+
+func (r *QuarantineRepository) Delete(ctx context.Context, id int64) error {
+	result, err := r.Execute(ctx, `DELETE FROM quarantined_files WHERE id = ?`, id)
+	if err != nil {
+		return wrapError(err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%w: failed to get affected rows: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	if affected == 0 {
+		return domainErrors.ErrQuarantineNotFound
+	}
+	return nil
+}
+
+func scanQuarantinedFile(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*entity.QuarantinedFile, error) {
+	var file entity.QuarantinedFile
+	err := scanner.Scan(
+		&file.ID,
+		&file.Kind,
+		&file.ItemID,
+		&file.Filename,
+		&file.ContentType,
+		&file.Size,
+		&file.Signature,
+		&file.Key,
+		&file.Actor,
+		&file.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &file, nil
+}
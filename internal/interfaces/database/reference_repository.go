@@ -0,0 +1,134 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+type ReferenceDataRepository struct {
+	SqlHandler
+}
+
+const referenceColumns = `ref_type, ref_key, attributes, created_at, updated_at`
+
+func (r *ReferenceDataRepository) FindAll(ctx context.Context, refType string) ([]*entity.ReferenceRecord, error) {
+	query := `SELECT ` + referenceColumns + ` FROM reference_data WHERE ref_type = ? ORDER BY ref_key`
+
+	rows, err := r.Query(ctx, query, refType)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer rows.Close()
+
+	records := []*entity.ReferenceRecord{}
+	for rows.Next() {
+		record, err := scanReference(rows)
+		if err != nil {
+			return nil, wrapError(err)
+		}
+		records = append(records, record)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, wrapError(err)
+	}
+
+	return records, nil
+}
+
+func (r *ReferenceDataRepository) FindByKey(ctx context.Context, refType, key string) (*entity.ReferenceRecord, error) {
+	query := `SELECT ` + referenceColumns + ` FROM reference_data WHERE ref_type = ? AND ref_key = ?`
+
+	record, err := scanReference(r.QueryRow(ctx, query, refType, key))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domainErrors.ErrReferenceNotFound
+		}
+		return nil, wrapError(err)
+	}
+
+	return record, nil
+}
+
+func (r *ReferenceDataRepository) Create(ctx context.Context, record *entity.ReferenceRecord) error {
+	attributes, err := json.Marshal(record.Attributes)
+	if err != nil {
+		return fmt.Errorf("failed to encode attributes: %w", err)
+	}
+
+	query := `
+        INSERT INTO reference_data (ref_type, ref_key, attributes, created_at, updated_at)
+        VALUES (?, ?, ?, ?, ?)
+    `
+	if _, err := r.Execute(ctx, query, record.Type, record.Key, string(attributes), record.CreatedAt, record.UpdatedAt); err != nil {
+		return wrapError(err)
+	}
+
+	return nil
+}
+
+func (r *ReferenceDataRepository) Update(ctx context.Context, record *entity.ReferenceRecord) error {
+	attributes, err := json.Marshal(record.Attributes)
+	if err != nil {
+		return fmt.Errorf("failed to encode attributes: %w", err)
+	}
+
+	query := `UPDATE reference_data SET attributes = ?, updated_at = ? WHERE ref_type = ? AND ref_key = ?`
+	return r.execute(ctx, query, string(attributes), record.UpdatedAt, record.Type, record.Key)
+}
+
+func (r *ReferenceDataRepository) Delete(ctx context.Context, refType, key string) error {
+	return r.execute(ctx, `DELETE FROM reference_data WHERE ref_type = ? AND ref_key = ?`, refType, key)
+}
+
+func (r *ReferenceDataRepository) execute(ctx context.Context, query string, args ...interface{}) error {
+	result, err := r.Execute(ctx, query, args...)
+	if err != nil {
+		return wrapError(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%w: failed to get rows affected: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	if rowsAffected == 0 {
+		return domainErrors.ErrReferenceNotFound
+	}
+
+	return nil
+}
+
+// JSON の数値は float64 になるため、種類の定義があれば型をそろえ直す
+func scanReference(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*entity.ReferenceRecord, error) {
+	var record entity.ReferenceRecord
+	var attributes string
+
+	err := scanner.Scan(
+		&record.Type,
+		&record.Key,
+		&attributes,
+		&record.CreatedAt,
+		&record.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(attributes), &record.Attributes); err != nil {
+		return nil, fmt.Errorf("failed to decode attributes of %s %q: %w", record.Type, record.Key, err)
+	}
+	if definition, ok := entity.FindReferenceType(record.Type); ok {
+		if normalized, err := definition.Normalize(record.Attributes); err == nil {
+			record.Attributes = normalized
+		}
+	}
+
+	return &record, nil
+}
@@ -0,0 +1,117 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+type RefreshTokenRepository struct {
+	SqlHandler
+}
+
+const refreshTokenColumns = `id, user_id, family_id, token_hash, created_at, expires_at, used_at, revoked_at`
+
+func (r *RefreshTokenRepository) Create(ctx context.Context, token *entity.RefreshToken) error {
+	query := `
+        INSERT INTO refresh_tokens (user_id, family_id, token_hash, created_at, expires_at)
+        VALUES (?, ?, ?, ?, ?)
+    `
+
+	result, err := r.Execute(ctx, query,
+		token.UserID,
+		token.FamilyID,
+		token.TokenHash,
+		token.CreatedAt,
+		token.ExpiresAt,
+	)
+	if err != nil {
+		return wrapError(err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("%w: failed to get last insert id: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	token.ID = id
+
+	return nil
+}
+
+func (r *RefreshTokenRepository) FindByTokenHash(ctx context.Context, tokenHash string) (*entity.RefreshToken, error) {
+	query := `SELECT ` + refreshTokenColumns + ` FROM refresh_tokens WHERE token_hash = ?`
+
+	token, err := scanRefreshToken(r.QueryRow(ctx, query, tokenHash))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domainErrors.ErrRefreshTokenNotFound
+		}
+		return nil, wrapError(err)
+	}
+
+	return token, nil
+}
+
+func (r *RefreshTokenRepository) MarkUsed(ctx context.Context, id int64, at time.Time) (bool, error) {
+	result, err := r.Execute(ctx, `UPDATE refresh_tokens SET used_at = ? WHERE id = ? AND used_at IS NULL`, at, id)
+	if err != nil {
+		return false, wrapError(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("%w: failed to get rows affected: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+
+	return rowsAffected > 0, nil
+}
+
+func (r *RefreshTokenRepository) RevokeFamily(ctx context.Context, familyID string, at time.Time) error {
+	query := `UPDATE refresh_tokens SET revoked_at = ? WHERE family_id = ? AND revoked_at IS NULL`
+	if _, err := r.Execute(ctx, query, at, familyID); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+func (r *RefreshTokenRepository) RevokeByUser(ctx context.Context, userID int64, at time.Time) error {
+	query := `UPDATE refresh_tokens SET revoked_at = ? WHERE user_id = ? AND revoked_at IS NULL`
+	if _, err := r.Execute(ctx, query, at, userID); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+func scanRefreshToken(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*entity.RefreshToken, error) {
+	var token entity.RefreshToken
+	var usedAt, revokedAt sql.NullTime
+
+	err := scanner.Scan(
+		&token.ID,
+		&token.UserID,
+		&token.FamilyID,
+		&token.TokenHash,
+		&token.CreatedAt,
+		&token.ExpiresAt,
+		&usedAt,
+		&revokedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if usedAt.Valid {
+		token.UsedAt = &usedAt.Time
+	}
+	if revokedAt.Valid {
+		token.RevokedAt = &revokedAt.Time
+	}
+
+	return &token, nil
+}
@@ -0,0 +1,36 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+type ReplicationCheckpointRepository struct {
+	SqlHandler
+}
+
+func (r *ReplicationCheckpointRepository) Load(ctx context.Context, source string) (int64, error) {
+	var sequence int64
+	err := r.QueryRow(ctx, `SELECT last_sequence FROM replication_checkpoints WHERE source = ?`, source).Scan(&sequence)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, nil
+		}
+		return 0, wrapError(err)
+	}
+	return sequence, nil
+}
+
+func (r *ReplicationCheckpointRepository) Save(ctx context.Context, source string, sequence int64, at time.Time) error {
+	query := `
+        INSERT INTO replication_checkpoints (source, last_sequence, updated_at)
+        VALUES (?, ?, ?)
+        ON DUPLICATE KEY UPDATE last_sequence = VALUES(last_sequence), updated_at = VALUES(updated_at)
+    `
+
+	if _, err := r.Execute(ctx, query, source, sequence, at); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+// 保持期間を過ぎたデータの件数を数える
+// query は cutoff を1つだけプレースホルダに取る COUNT 文
+func countBefore(ctx context.Context, h SqlHandler, query string, cutoff time.Time) (int64, error) {
+	var count int64
+	if err := h.QueryRow(ctx, query, cutoff).Scan(&count); err != nil {
+		return 0, wrapError(err)
+	}
+	return count, nil
+}
+
+// 保持期間を過ぎたデータを削除し、削除した件数を返す
+// statement は cutoff を1つだけプレースホルダに取る DELETE 文
+func deleteBefore(ctx context.Context, h SqlHandler, statement string, cutoff time.Time) (int64, error) {
+	result, err := h.Execute(ctx, statement, cutoff)
+	if err != nil {
+		return 0, wrapError(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%w: failed to get rows affected: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	return rowsAffected, nil
+}
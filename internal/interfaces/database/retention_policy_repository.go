@@ -0,0 +1,47 @@
+package database
+
+import (
+	"context"
+
+	"Aicon-assignment/internal/domain/entity"
+)
+
+type RetentionPolicyRepository struct {
+	SqlHandler
+}
+
+func (r *RetentionPolicyRepository) FindAll(ctx context.Context) ([]*entity.RetentionPolicy, error) {
+	rows, err := r.Query(ctx, `SELECT data_type, retain_days, updated_at FROM retention_policies ORDER BY data_type`)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer rows.Close()
+
+	policies := []*entity.RetentionPolicy{}
+	for rows.Next() {
+		var policy entity.RetentionPolicy
+		if err := rows.Scan(&policy.DataType, &policy.RetainDays, &policy.UpdatedAt); err != nil {
+			return nil, wrapError(err)
+		}
+		policies = append(policies, &policy)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, wrapError(err)
+	}
+
+	return policies, nil
+}
+
+func (r *RetentionPolicyRepository) Save(ctx context.Context, policy *entity.RetentionPolicy) error {
+	query := `
+        INSERT INTO retention_policies (data_type, retain_days, updated_at)
+        VALUES (?, ?, ?)
+        ON DUPLICATE KEY UPDATE retain_days = VALUES(retain_days), updated_at = VALUES(updated_at)
+    `
+
+	if _, err := r.Execute(ctx, query, policy.DataType, policy.RetainDays, policy.UpdatedAt); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
@@ -6,6 +6,8 @@ type SqlHandler interface {
 	Execute(ctx context.Context, statement string, args ...interface{}) (Result, error)
 	Query(ctx context.Context, statement string, args ...interface{}) (Rows, error)
 	QueryRow(ctx context.Context, statement string, args ...interface{}) Row
+	// fn に渡した ctx を使うクエリはすべて同じトランザクションで実行し、fn がエラーを返したらロールバックする
+	Transaction(ctx context.Context, fn func(ctx context.Context) error) error
 	Close() error
 }
 
@@ -24,3 +26,12 @@ type Rows interface {
 type Row interface {
 	Scan(dest ...interface{}) error
 }
+
+// DB に接続でき、クエリに応答があるかを確認する（/readyz 用）
+func Ping(ctx context.Context, h SqlHandler) error {
+	var one int
+	if err := h.QueryRow(ctx, "SELECT 1").Scan(&one); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
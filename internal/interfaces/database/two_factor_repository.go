@@ -0,0 +1,101 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+type TwoFactorRepository struct {
+	SqlHandler
+}
+
+func (r *TwoFactorRepository) FindByUser(ctx context.Context, userID int64) (*entity.TwoFactor, error) {
+	query := `
+        SELECT user_id, secret, enabled_at, last_used_step, recovery_codes, created_at, updated_at
+        FROM user_two_factor
+        WHERE user_id = ?
+    `
+
+	var twoFactor entity.TwoFactor
+	var enabledAt sql.NullTime
+	var recoveryCodes string
+	err := r.QueryRow(ctx, query, userID).Scan(
+		&twoFactor.UserID,
+		&twoFactor.Secret,
+		&enabledAt,
+		&twoFactor.LastUsedStep,
+		&recoveryCodes,
+		&twoFactor.CreatedAt,
+		&twoFactor.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domainErrors.ErrTwoFactorNotFound
+		}
+		return nil, wrapError(err)
+	}
+
+	if enabledAt.Valid {
+		twoFactor.EnabledAt = &enabledAt.Time
+	}
+	if err := json.Unmarshal([]byte(recoveryCodes), &twoFactor.RecoveryCodeHashes); err != nil {
+		return nil, fmt.Errorf("failed to decode recovery codes of user %d: %w", userID, err)
+	}
+
+	return &twoFactor, nil
+}
+
+func (r *TwoFactorRepository) Save(ctx context.Context, twoFactor *entity.TwoFactor) error {
+	hashes := twoFactor.RecoveryCodeHashes
+	if hashes == nil {
+		hashes = []string{}
+	}
+	recoveryCodes, err := json.Marshal(hashes)
+	if err != nil {
+		return fmt.Errorf("failed to encode recovery codes: %w", err)
+	}
+
+	query := `
+        INSERT INTO user_two_factor (user_id, secret, enabled_at, last_used_step, recovery_codes, created_at, updated_at)
+        VALUES (?, ?, ?, ?, ?, ?, ?)
+        ON DUPLICATE KEY UPDATE
+            secret = VALUES(secret), enabled_at = VALUES(enabled_at), last_used_step = VALUES(last_used_step),
+            recovery_codes = VALUES(recovery_codes), created_at = VALUES(created_at), updated_at = VALUES(updated_at)
+    `
+	_, err = r.Execute(ctx, query,
+		twoFactor.UserID,
+		twoFactor.Secret,
+		twoFactor.EnabledAt,
+		twoFactor.LastUsedStep,
+		string(recoveryCodes),
+		twoFactor.CreatedAt,
+		twoFactor.UpdatedAt,
+	)
+	if err != nil {
+		return wrapError(err)
+	}
+
+	return nil
+}
+
+func (r *TwoFactorRepository) Delete(ctx context.Context, userID int64) error {
+	result, err := r.Execute(ctx, `DELETE FROM user_two_factor WHERE user_id = ?`, userID)
+	if err != nil {
+		return wrapError(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%w: failed to get rows affected: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	if rowsAffected == 0 {
+		return domainErrors.ErrTwoFactorNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,118 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+type UserIdentityRepository struct {
+	SqlHandler
+}
+
+const userIdentityColumns = `id, user_id, provider, subject, email, created_at`
+
+func (r *UserIdentityRepository) Create(ctx context.Context, identity *entity.UserIdentity) error {
+	query := `
+        INSERT INTO user_identities (user_id, provider, subject, email, created_at)
+        VALUES (?, ?, ?, ?, ?)
+    `
+
+	result, err := r.Execute(ctx, query,
+		identity.UserID,
+		identity.Provider,
+		identity.Subject,
+		identity.Email,
+		identity.CreatedAt,
+	)
+	if err != nil {
+		return wrapError(err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("%w: failed to get last insert id: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	identity.ID = id
+
+	return nil
+}
+
+func (r *UserIdentityRepository) FindBySubject(ctx context.Context, provider, subject string) (*entity.UserIdentity, error) {
+	query := `SELECT ` + userIdentityColumns + ` FROM user_identities WHERE provider = ? AND subject = ?`
+
+	identity, err := scanUserIdentity(r.QueryRow(ctx, query, provider, subject))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domainErrors.ErrIdentityNotFound
+		}
+		return nil, wrapError(err)
+	}
+
+	return identity, nil
+}
+
+func (r *UserIdentityRepository) FindByUser(ctx context.Context, userID int64) ([]*entity.UserIdentity, error) {
+	query := `SELECT ` + userIdentityColumns + ` FROM user_identities WHERE user_id = ? ORDER BY id`
+
+	rows, err := r.Query(ctx, query, userID)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer rows.Close()
+
+	identities := []*entity.UserIdentity{}
+	for rows.Next() {
+		identity, err := scanUserIdentity(rows)
+		if err != nil {
+			return nil, wrapError(err)
+		}
+		identities = append(identities, identity)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, wrapError(err)
+	}
+
+	return identities, nil
+}
+
+func (r *UserIdentityRepository) Delete(ctx context.Context, userID, id int64) error {
+	result, err := r.Execute(ctx, `DELETE FROM user_identities WHERE id = ? AND user_id = ?`, id, userID)
+	if err != nil {
+		return wrapError(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%w: failed to get rows affected: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	if rowsAffected == 0 {
+		return domainErrors.ErrIdentityNotFound
+	}
+
+	return nil
+}
+
+func scanUserIdentity(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*entity.UserIdentity, error) {
+	var identity entity.UserIdentity
+
+	err := scanner.Scan(
+		&identity.ID,
+		&identity.UserID,
+		&identity.Provider,
+		&identity.Subject,
+		&identity.Email,
+		&identity.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &identity, nil
+}
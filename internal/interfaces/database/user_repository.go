@@ -0,0 +1,185 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+type UserRepository struct {
+	SqlHandler
+}
+
+const userColumns = `id, user_name, display_name, email, external_id, active, role, password_hash, created_at, updated_at`
+
+// ユーザーの条件式で使えるフィールドとカラムの対応
+var userFilterColumns = map[string]string{
+	"user_name":    "user_name",
+	"display_name": "display_name",
+	"email":        "email",
+	"external_id":  "external_id",
+	"active":       "active",
+	"role":         "role",
+}
+
+func (r *UserRepository) Create(ctx context.Context, user *entity.User) error {
+	query := `
+        INSERT INTO users (user_name, display_name, email, external_id, active, role, password_hash, created_at, updated_at)
+        VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+    `
+
+	result, err := r.Execute(ctx, query,
+		user.UserName,
+		user.DisplayName,
+		user.Email,
+		user.ExternalID,
+		user.Active,
+		user.Role,
+		user.PasswordHash,
+		user.CreatedAt,
+		user.UpdatedAt,
+	)
+	if err != nil {
+		return wrapError(err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("%w: failed to get last insert id: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	user.ID = id
+
+	return nil
+}
+
+func (r *UserRepository) FindByID(ctx context.Context, id int64) (*entity.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE id = ?`
+
+	user, err := scanUser(r.QueryRow(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domainErrors.ErrUserNotFound
+		}
+		return nil, wrapError(err)
+	}
+
+	return user, nil
+}
+
+func (r *UserRepository) FindByQuery(ctx context.Context, q entity.UserQuery) ([]*entity.User, error) {
+	where, args, err := userWhere(q)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT ` + userColumns + ` FROM users` + where + ` ORDER BY id`
+	if q.Limit > 0 {
+		query += ` LIMIT ? OFFSET ?`
+		args = append(args, q.Limit, q.Offset)
+	}
+
+	rows, err := r.Query(ctx, query, args...)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer rows.Close()
+
+	users := []*entity.User{}
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, wrapError(err)
+		}
+		users = append(users, user)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, wrapError(err)
+	}
+
+	return users, nil
+}
+
+func (r *UserRepository) CountByQuery(ctx context.Context, q entity.UserQuery) (int, error) {
+	where, args, err := userWhere(q)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	if err := r.QueryRow(ctx, `SELECT COUNT(*) FROM users`+where, args...).Scan(&count); err != nil {
+		return 0, wrapError(err)
+	}
+	return count, nil
+}
+
+func userWhere(q entity.UserQuery) (string, []interface{}, error) {
+	if q.Filter == nil {
+		return "", nil, nil
+	}
+	where, args, err := compileFilter(q.Filter, userFilterColumns)
+	if err != nil {
+		return "", nil, fmt.Errorf("%w: %s", domainErrors.ErrInvalidInput, err.Error())
+	}
+	return " WHERE " + where, args, nil
+}
+
+func (r *UserRepository) Update(ctx context.Context, user *entity.User) error {
+	query := `
+        UPDATE users
+        SET user_name = ?, display_name = ?, email = ?, external_id = ?, active = ?, role = ?, password_hash = ?, updated_at = ?
+        WHERE id = ?
+    `
+
+	result, err := r.Execute(ctx, query,
+		user.UserName,
+		user.DisplayName,
+		user.Email,
+		user.ExternalID,
+		user.Active,
+		user.Role,
+		user.PasswordHash,
+		user.UpdatedAt,
+		user.ID,
+	)
+	if err != nil {
+		return wrapError(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%w: failed to get rows affected: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	if rowsAffected == 0 {
+		return domainErrors.ErrUserNotFound
+	}
+
+	return nil
+}
+
+func scanUser(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*entity.User, error) {
+	var user entity.User
+
+	err := scanner.Scan(
+		&user.ID,
+		&user.UserName,
+		&user.DisplayName,
+		&user.Email,
+		&user.ExternalID,
+		&user.Active,
+		&user.Role,
+		&user.PasswordHash,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
@@ -0,0 +1,170 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+type WebhookDeliveryRepository struct {
+	SqlHandler
+	Columns *SchemaColumns // nil の場合はすべての列があるものとして扱う
+}
+
+// schema_version がない場合は記録しない。再送時に新しい版へ変換しないだけで、配信はできる
+func (r *WebhookDeliveryRepository) columns() string {
+	return `id, webhook_id, event_id, event_type, payload, ` + r.Columns.selectExpr(ColumnDeliverySchemaVersion) + `, success, status_code, duration_ms, error, redelivery_of, created_at`
+}
+
+func (r *WebhookDeliveryRepository) Record(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	values := columnValues{columns: r.Columns}
+	values.add("webhook_id", delivery.WebhookID)
+	values.add("event_id", delivery.EventID)
+	values.add("event_type", delivery.EventType)
+	values.add("payload", string(delivery.Payload))
+	values.addIfPresent(ColumnDeliverySchemaVersion, delivery.SchemaVersion)
+	values.add("success", delivery.Success)
+	values.add("status_code", delivery.StatusCode)
+	values.add("duration_ms", delivery.DurationMs)
+	values.add("response_snippet", "") // レスポンスのボディは記録しなくなった。NOT NULL の列のため空にする
+	values.add("error", delivery.Error)
+	values.add("redelivery_of", delivery.RedeliveryOf)
+	values.add("created_at", delivery.CreatedAt)
+	names, placeholders := values.insert()
+
+	result, err := r.Execute(ctx, `INSERT INTO webhook_deliveries (`+names+`) VALUES (`+placeholders+`)`, values.values...)
+	if err != nil {
+		return wrapError(err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("%w: failed to get last insert id: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	delivery.ID = id
+
+	return nil
+}
+
+func (r *WebhookDeliveryRepository) FindByID(ctx context.Context, id int64) (*entity.WebhookDelivery, error) {
+	query := `SELECT ` + r.columns() + ` FROM webhook_deliveries WHERE id = ?`
+
+	delivery, err := scanWebhookDelivery(r.QueryRow(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domainErrors.ErrDeliveryNotFound
+		}
+		return nil, wrapError(err)
+	}
+
+	return delivery, nil
+}
+
+func (r *WebhookDeliveryRepository) FindByWebhookID(ctx context.Context, webhookID int64, status string) ([]*entity.WebhookDelivery, error) {
+	query := `SELECT ` + r.columns() + ` FROM webhook_deliveries WHERE webhook_id = ?`
+	args := []interface{}{webhookID}
+	switch status {
+	case entity.DeliveryStatusSuccess:
+		query += ` AND success = TRUE`
+	case entity.DeliveryStatusFailed:
+		query += ` AND success = FALSE`
+	}
+	query += ` ORDER BY created_at DESC, id DESC`
+
+	rows, err := r.Query(ctx, query, args...)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer rows.Close()
+
+	deliveries := []*entity.WebhookDelivery{}
+	for rows.Next() {
+		delivery, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, wrapError(err)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, wrapError(err)
+	}
+
+	return deliveries, nil
+}
+
+func (r *WebhookDeliveryRepository) GetStats(ctx context.Context, webhookID int64) (*entity.WebhookDeliveryStats, error) {
+	query := `
+        SELECT
+            COUNT(*),
+            COALESCE(SUM(CASE WHEN success THEN 1 ELSE 0 END), 0),
+            COALESCE(AVG(duration_ms), 0),
+            MAX(created_at)
+        FROM webhook_deliveries
+        WHERE webhook_id = ?
+    `
+
+	stats := &entity.WebhookDeliveryStats{WebhookID: webhookID}
+	var lastDeliveryAt sql.NullTime
+	err := r.QueryRow(ctx, query, webhookID).Scan(
+		&stats.Total,
+		&stats.Succeeded,
+		&stats.AvgDurationMs,
+		&lastDeliveryAt,
+	)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	stats.Failed = stats.Total - stats.Succeeded
+	if lastDeliveryAt.Valid {
+		stats.LastDeliveryAt = &lastDeliveryAt.Time
+	}
+
+	return stats, nil
+}
+
+func scanWebhookDelivery(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*entity.WebhookDelivery, error) {
+	var delivery entity.WebhookDelivery
+	var payload string
+	var redeliveryOf sql.NullInt64
+
+	err := scanner.Scan(
+		&delivery.ID,
+		&delivery.WebhookID,
+		&delivery.EventID,
+		&delivery.EventType,
+		&payload,
+		&delivery.SchemaVersion,
+		&delivery.Success,
+		&delivery.StatusCode,
+		&delivery.DurationMs,
+		&delivery.Error,
+		&redeliveryOf,
+		&delivery.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	delivery.Payload = []byte(payload)
+	if redeliveryOf.Valid {
+		delivery.RedeliveryOf = &redeliveryOf.Int64
+	}
+
+	return &delivery, nil
+}
+
+func (r *WebhookDeliveryRepository) CountBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	return countBefore(ctx, r.SqlHandler, `SELECT COUNT(*) FROM webhook_deliveries WHERE created_at < ?`, cutoff)
+}
+
+func (r *WebhookDeliveryRepository) DeleteBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	return deleteBefore(ctx, r.SqlHandler, `DELETE FROM webhook_deliveries WHERE created_at < ?`, cutoff)
+}
@@ -0,0 +1,171 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+type WebhookRepository struct {
+	SqlHandler
+	Columns *SchemaColumns // nil の場合はすべての列があるものとして扱う
+}
+
+func (r *WebhookRepository) columns() string {
+	return `id, ` + r.Columns.selectExpr(ColumnWebhookOwner) + `, url, events, ` + r.Columns.selectExpr(ColumnWebhookWatchFields) + `, event_version, payload_template, secret, active, created_at, updated_at`
+}
+
+func (r *WebhookRepository) FindAll(ctx context.Context) ([]*entity.Webhook, error) {
+	query := `SELECT ` + r.columns() + ` FROM webhooks ORDER BY id`
+
+	rows, err := r.Query(ctx, query)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer rows.Close()
+
+	webhooks := []*entity.Webhook{}
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, wrapError(err)
+		}
+		webhooks = append(webhooks, webhook)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, wrapError(err)
+	}
+
+	return webhooks, nil
+}
+
+func (r *WebhookRepository) FindByID(ctx context.Context, id int64) (*entity.Webhook, error) {
+	query := `SELECT ` + r.columns() + ` FROM webhooks WHERE id = ?`
+
+	webhook, err := scanWebhook(r.QueryRow(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domainErrors.ErrWebhookNotFound
+		}
+		return nil, wrapError(err)
+	}
+
+	return webhook, nil
+}
+
+func (r *WebhookRepository) Create(ctx context.Context, webhook *entity.Webhook) (*entity.Webhook, error) {
+	values := columnValues{columns: r.Columns}
+	// owner_id がない場合は持ち主を記録せず、持ち主を記録する前の Webhook と同じに扱う
+	values.addIfPresent(ColumnWebhookOwner, webhook.OwnerID)
+	values.add("url", webhook.URL)
+	values.add("events", strings.Join(webhook.Events, ","))
+	if err := values.addOptional(ColumnWebhookWatchFields, strings.Join(webhook.WatchFields, ","), len(webhook.WatchFields) == 0); err != nil {
+		return nil, err
+	}
+	values.add("event_version", webhook.EventVersion)
+	values.add("payload_template", webhook.PayloadTemplate)
+	values.add("secret", webhook.Secret)
+	values.add("active", webhook.Active)
+	values.add("created_at", webhook.CreatedAt)
+	values.add("updated_at", webhook.UpdatedAt)
+	names, placeholders := values.insert()
+
+	result, err := r.Execute(ctx, `INSERT INTO webhooks (`+names+`) VALUES (`+placeholders+`)`, values.values...)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to get last insert id: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+
+	return r.FindByID(ctx, id)
+}
+
+func (r *WebhookRepository) Update(ctx context.Context, webhook *entity.Webhook) (*entity.Webhook, error) {
+	values := columnValues{columns: r.Columns}
+	values.add("url", webhook.URL)
+	values.add("events", strings.Join(webhook.Events, ","))
+	if err := values.addOptional(ColumnWebhookWatchFields, strings.Join(webhook.WatchFields, ","), len(webhook.WatchFields) == 0); err != nil {
+		return nil, err
+	}
+	values.add("event_version", webhook.EventVersion)
+	values.add("payload_template", webhook.PayloadTemplate)
+	values.add("active", webhook.Active)
+	values.add("updated_at", webhook.UpdatedAt)
+
+	result, err := r.Execute(ctx, `UPDATE webhooks SET `+values.assignments()+` WHERE id = ?`, append(values.values, webhook.ID)...)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to get rows affected: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	if rowsAffected == 0 {
+		return nil, domainErrors.ErrWebhookNotFound
+	}
+
+	return r.FindByID(ctx, webhook.ID)
+}
+
+func (r *WebhookRepository) Delete(ctx context.Context, id int64) error {
+	result, err := r.Execute(ctx, `DELETE FROM webhooks WHERE id = ?`, id)
+	if err != nil {
+		return wrapError(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%w: failed to get rows affected: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	if rowsAffected == 0 {
+		return domainErrors.ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+func scanWebhook(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*entity.Webhook, error) {
+	var webhook entity.Webhook
+	var events, watchFields string
+	var ownerID sql.NullInt64
+
+	err := scanner.Scan(
+		&webhook.ID,
+		&ownerID,
+		&webhook.URL,
+		&events,
+		&watchFields,
+		&webhook.EventVersion,
+		&webhook.PayloadTemplate,
+		&webhook.Secret,
+		&webhook.Active,
+		&webhook.CreatedAt,
+		&webhook.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if ownerID.Valid {
+		webhook.OwnerID = &ownerID.Int64
+	}
+	if events != "" {
+		webhook.Events = strings.Split(events, ",")
+	}
+	if watchFields != "" {
+		webhook.WatchFields = strings.Split(watchFields, ",")
+	}
+
+	return &webhook, nil
+}
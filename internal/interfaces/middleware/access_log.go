@@ -0,0 +1,158 @@
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"Aicon-assignment/internal/pkg/reqctx"
+)
+
+// アクセスログの形式
+const (
+	AccessLogCommon   = "common"   // Apache の Common Log Format
+	AccessLogCombined = "combined" // Apache の Combined Log Format（Referer と User-Agent 付き）
+	AccessLogJSON     = "json"     // 1行1件の JSON
+)
+
+// 値を伏せたクエリパラメータに入れる文字列
+const redactedValue = "REDACTED"
+
+type AccessLogConfig struct {
+	Format     string
+	SampleRate float64  // 記録する割合（0〜1）。5xx は割合によらず記録する
+	Redact     []string // 値を伏せるクエリパラメータ名（大文字小文字を区別しない）
+	Random     func() float64
+}
+
+// JSON 形式で書き出す1件分
+type accessLogEntry struct {
+	Time      string  `json:"time"`
+	RemoteIP  string  `json:"remote_ip"`
+	Method    string  `json:"method"`
+	URI       string  `json:"uri"`
+	Route     string  `json:"route"`
+	Protocol  string  `json:"protocol"`
+	Status    int     `json:"status"`
+	Bytes     int64   `json:"bytes"`
+	LatencyMS float64 `json:"latency_ms"`
+	RequestID string  `json:"request_id,omitempty"`
+	UserID    *int64  `json:"user_id,omitempty"`
+	Referer   string  `json:"referer,omitempty"`
+	UserAgent string  `json:"user_agent,omitempty"`
+}
+
+// アプリケーションのログとは別に、ログ基盤でそのまま取り込める形式でアクセスログを書き出す
+// アプリケーションのログに影響しないよう、書き込みに失敗してもレスポンスは変えない
+func AccessLog(w io.Writer, config AccessLogConfig) echo.MiddlewareFunc {
+	random := config.Random
+	if random == nil {
+		random = rand.Float64
+	}
+	redact := make(map[string]bool, len(config.Redact))
+	for _, name := range config.Redact {
+		redact[strings.ToLower(name)] = true
+	}
+	var mu sync.Mutex
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+
+			err := next(c)
+			if err != nil {
+				// ステータスコードを確定させるため、ここでエラーレスポンスを書き出す
+				c.Error(err)
+			}
+
+			res := c.Response()
+			if res.Status < 500 && random() >= config.SampleRate {
+				return err
+			}
+
+			req := c.Request()
+			entry := accessLogEntry{
+				Time:      start.Format(time.RFC3339),
+				RemoteIP:  c.RealIP(),
+				Method:    req.Method,
+				URI:       redactURI(req.URL, redact),
+				Route:     c.Path(),
+				Protocol:  req.Proto,
+				Status:    res.Status,
+				Bytes:     res.Size,
+				LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+				RequestID: res.Header().Get(echo.HeaderXRequestID),
+				Referer:   req.Referer(),
+				UserAgent: req.UserAgent(),
+			}
+			if userID, ok := reqctx.UserID(req.Context()); ok {
+				entry.UserID = &userID
+			}
+
+			line := formatAccessLog(config.Format, entry, start)
+			mu.Lock()
+			_, _ = io.WriteString(w, line)
+			mu.Unlock()
+
+			return err
+		}
+	}
+}
+
+func formatAccessLog(format string, entry accessLogEntry, start time.Time) string {
+	if format == AccessLogJSON {
+		b, _ := json.Marshal(entry)
+		return string(b) + "\n"
+	}
+
+	user := "-"
+	if entry.UserID != nil {
+		user = strconv.FormatInt(*entry.UserID, 10)
+	}
+	bytes := "-"
+	if entry.Bytes > 0 {
+		bytes = strconv.FormatInt(entry.Bytes, 10)
+	}
+	line := fmt.Sprintf(`%s - %s [%s] "%s %s %s" %d %s`,
+		entry.RemoteIP, user, start.Format("02/Jan/2006:15:04:05 -0700"),
+		entry.Method, entry.URI, entry.Protocol, entry.Status, bytes)
+	if format == AccessLogCombined {
+		line += fmt.Sprintf(` "%s" "%s"`, orDash(entry.Referer), orDash(entry.UserAgent))
+	}
+	return line + "\n"
+}
+
+// トークンなどが記録されないよう、指定したクエリパラメータの値を伏せる
+func redactURI(u *url.URL, redact map[string]bool) string {
+	if u.RawQuery == "" || len(redact) == 0 {
+		return u.RequestURI()
+	}
+
+	parts := strings.Split(u.RawQuery, "&")
+	for i, part := range parts {
+		rawName, _, _ := strings.Cut(part, "=")
+		name, err := url.QueryUnescape(rawName)
+		if err != nil {
+			name = rawName
+		}
+		if redact[strings.ToLower(name)] {
+			parts[i] = rawName + "=" + redactedValue
+		}
+	}
+	return u.EscapedPath() + "?" + strings.Join(parts, "&")
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return strings.ReplaceAll(s, `"`, `\"`)
+}
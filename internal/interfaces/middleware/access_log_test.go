@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serveAccessLog(t *testing.T, config AccessLogConfig, target string, status int) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	e := echo.New()
+	e.Use(AccessLog(&buf, config))
+	e.GET("/items", func(c echo.Context) error {
+		return c.String(status, "ok")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	req.Header.Set("User-Agent", "curl/8.0")
+	e.ServeHTTP(httptest.NewRecorder(), req)
+	return buf.String()
+}
+
+func TestAccessLog_Combined(t *testing.T) {
+	line := serveAccessLog(t, AccessLogConfig{
+		Format:     AccessLogCombined,
+		SampleRate: 1,
+		Redact:     []string{"Token"},
+	}, "/items?token=secret&category=%E6%99%82%E8%A8%88", http.StatusOK)
+
+	assert.Regexp(t, `^192\.0\.2\.1 - - \[[^\]]+\] "GET /items\?token=REDACTED&category=%E6%99%82%E8%A8%88 HTTP/1\.1" 200 2 "-" "curl/8\.0"\n$`, line)
+	assert.NotContains(t, line, "secret")
+}
+
+func TestAccessLog_JSON(t *testing.T) {
+	line := serveAccessLog(t, AccessLogConfig{Format: AccessLogJSON, SampleRate: 1}, "/items", http.StatusCreated)
+
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal([]byte(line), &entry))
+	assert.Equal(t, "GET", entry["method"])
+	assert.Equal(t, "/items", entry["route"])
+	assert.Equal(t, float64(http.StatusCreated), entry["status"])
+}
+
+func TestAccessLog_Sampling(t *testing.T) {
+	config := AccessLogConfig{Format: AccessLogCommon, SampleRate: 0.1, Random: func() float64 { return 0.5 }}
+
+	assert.Empty(t, serveAccessLog(t, config, "/items", http.StatusOK), "sampled out")
+	assert.NotEmpty(t, serveAccessLog(t, config, "/items", http.StatusInternalServerError), "5xx is always logged")
+}
+
+func TestAccessLog_HandlerError(t *testing.T) {
+	line := serveAccessLog(t, AccessLogConfig{Format: AccessLogCommon, SampleRate: 1}, "/unknown", http.StatusOK)
+	assert.Contains(t, line, `"GET /unknown HTTP/1.1" 404`)
+}
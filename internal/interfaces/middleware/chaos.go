@@ -0,0 +1,140 @@
+package middleware
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"Aicon-assignment/internal/interfaces/controller/response"
+	"Aicon-assignment/internal/pkg/reqctx"
+)
+
+// 障害を注入したレスポンスに付けるヘッダー
+const HeaderChaos = "X-Chaos-Injected"
+
+// ルートごとに注入する障害
+type ChaosRule struct {
+	Method     string        // "*" で全メソッド
+	Path       string        // ルートのパターン（"/items/:id" など）。"*" で全ルート、path.Match の形式も使える
+	Latency    time.Duration // レスポンスを返す前に待つ時間
+	ErrorRate  float64       // 500 を返す確率（0〜1）
+	DBDropRate float64       // クエリごとに DB の接続断を起こす確率（0〜1）
+}
+
+func (r ChaosRule) matches(method, route string) bool {
+	if r.Method != "*" && !strings.EqualFold(r.Method, method) {
+		return false
+	}
+	if r.Path == "*" || r.Path == route {
+		return true
+	}
+	matched, _ := path.Match(r.Path, route)
+	return matched
+}
+
+// クライアントの再試行やサーバー側の再試行を検証するため、設定したルートに障害を注入する
+// 開発・ステージング専用。最初に一致したルールだけを適用する
+// random は 0 以上 1 未満の値を返す関数で、nil の場合は math/rand を使う
+func Chaos(rules []ChaosRule, random func() float64) echo.MiddlewareFunc {
+	if random == nil {
+		random = rand.Float64
+	}
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			rule, ok := matchChaosRule(rules, req.Method, c.Path())
+			if !ok {
+				return next(c)
+			}
+
+			ctx := req.Context()
+			logger := reqctx.Logger(ctx)
+
+			if rule.Latency > 0 {
+				logger.Debug("chaos: injecting latency", "latency", rule.Latency)
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-time.After(rule.Latency):
+				}
+			}
+
+			if rule.ErrorRate > 0 && random() < rule.ErrorRate {
+				logger.Warn("chaos: injecting internal server error")
+				c.Response().Header().Set(HeaderChaos, "error")
+				return response.Error(c, http.StatusInternalServerError, "injected fault")
+			}
+
+			if rule.DBDropRate > 0 {
+				c.SetRequest(req.WithContext(reqctx.WithDBDropRate(ctx, rule.DBDropRate)))
+			}
+			return next(c)
+		}
+	}
+}
+
+func matchChaosRule(rules []ChaosRule, method, route string) (ChaosRule, bool) {
+	for _, rule := range rules {
+		if rule.matches(method, route) {
+			return rule, true
+		}
+	}
+	return ChaosRule{}, false
+}
+
+// "GET /items latency=200ms error_rate=0.1; * /items/:id db_drop_rate=0.5" の形式のルールを読み込む
+// ルールは ; 区切りで、メソッド・ルートのパターンに続けて障害を key=value で指定する
+func ParseChaosRules(value string) ([]ChaosRule, error) {
+	var rules []ChaosRule
+	for _, part := range strings.Split(value, ";") {
+		fields := strings.Fields(part)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) < 3 {
+			return nil, fmt.Errorf("chaos rule %q: method, path and at least one fault are required", strings.TrimSpace(part))
+		}
+
+		rule := ChaosRule{Method: strings.ToUpper(fields[0]), Path: fields[1]}
+		for _, option := range fields[2:] {
+			key, raw, ok := strings.Cut(option, "=")
+			if !ok {
+				return nil, fmt.Errorf("chaos rule %q: invalid option %q", strings.TrimSpace(part), option)
+			}
+
+			var err error
+			switch key {
+			case "latency":
+				rule.Latency, err = time.ParseDuration(raw)
+				if err == nil && rule.Latency < 0 {
+					err = fmt.Errorf("must not be negative")
+				}
+			case "error_rate":
+				rule.ErrorRate, err = parseRate(raw)
+			case "db_drop_rate":
+				rule.DBDropRate, err = parseRate(raw)
+			default:
+				err = fmt.Errorf("unknown option")
+			}
+			if err != nil {
+				return nil, fmt.Errorf("chaos rule %q: %s: %w", strings.TrimSpace(part), key, err)
+			}
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func parseRate(value string) (float64, error) {
+	rate, err := strconv.ParseFloat(value, 64)
+	if err != nil || rate < 0 || rate > 1 {
+		return 0, fmt.Errorf("must be between 0 and 1")
+	}
+	return rate, nil
+}
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"Aicon-assignment/internal/pkg/reqctx"
+)
+
+func TestParseChaosRules(t *testing.T) {
+	rules, err := ParseChaosRules("get /items latency=200ms error_rate=0.1; * /items/:id db_drop_rate=0.5 ;")
+	require.NoError(t, err)
+	assert.Equal(t, []ChaosRule{
+		{Method: "GET", Path: "/items", Latency: 200 * time.Millisecond, ErrorRate: 0.1},
+		{Method: "*", Path: "/items/:id", DBDropRate: 0.5},
+	}, rules)
+
+	for _, invalid := range []string{
+		"GET /items",
+		"GET /items error_rate=1.5",
+		"GET /items latency=fast",
+		"GET /items latency=-1s",
+		"GET /items retries=3",
+		"GET /items 0.5",
+	} {
+		_, err := ParseChaosRules(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestChaos(t *testing.T) {
+	rules := []ChaosRule{
+		{Method: "POST", Path: "/items", ErrorRate: 0.5},
+		{Method: "*", Path: "/items/*", DBDropRate: 0.3},
+	}
+
+	serve := func(method, route string, random float64) (*httptest.ResponseRecorder, float64) {
+		e := echo.New()
+		var dropRate float64
+		e.Use(Chaos(rules, func() float64 { return random }))
+		e.Any(route, func(c echo.Context) error {
+			dropRate = reqctx.DBDropRate(c.Request().Context())
+			return c.NoContent(http.StatusNoContent)
+		})
+
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(method, route, nil))
+		return rec, dropRate
+	}
+
+	rec, _ := serve(http.MethodPost, "/items", 0.4)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Equal(t, "error", rec.Header().Get(HeaderChaos))
+
+	rec, _ = serve(http.MethodPost, "/items", 0.6)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	rec, _ = serve(http.MethodGet, "/items", 0)
+	assert.Equal(t, http.StatusNoContent, rec.Code, "method does not match")
+
+	rec, dropRate := serve(http.MethodDelete, "/items/:id", 0)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, 0.3, dropRate)
+}
@@ -0,0 +1,139 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"Aicon-assignment/internal/interfaces/controller/response"
+	"Aicon-assignment/internal/pkg/reqctx"
+)
+
+// クライアントの種類とバージョンを "ios/2.3.1" の形式で送るヘッダー
+const HeaderClientVersion = "X-Client-Version"
+
+// "2.3.1" のようなドット区切りのバージョン。"-beta.1" や "+build" の部分は比較に使わない
+type ClientVersion []int
+
+func ParseClientVersion(value string) (ClientVersion, error) {
+	core, _, _ := strings.Cut(value, "+")
+	core, _, _ = strings.Cut(core, "-")
+	if core == "" {
+		return nil, fmt.Errorf("invalid version %q", value)
+	}
+
+	var version ClientVersion
+	for _, part := range strings.Split(core, ".") {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid version %q", value)
+		}
+		version = append(version, n)
+	}
+	return version, nil
+}
+
+// 足りない桁は 0 として比較する（"2.3" と "2.3.0" は同じ）
+func (v ClientVersion) Less(other ClientVersion) bool {
+	for i := 0; i < max(len(v), len(other)); i++ {
+		var a, b int
+		if i < len(v) {
+			a = v[i]
+		}
+		if i < len(other) {
+			b = other[i]
+		}
+		if a != b {
+			return a < b
+		}
+	}
+	return false
+}
+
+func (v ClientVersion) String() string {
+	parts := make([]string, len(v))
+	for i, n := range v {
+		parts[i] = strconv.Itoa(n)
+	}
+	return strings.Join(parts, ".")
+}
+
+// 古いクライアントに返す 426 のボディ
+type UpgradeRequiredResponse struct {
+	Error          string `json:"error"`
+	Message        string `json:"message"`
+	ClientType     string `json:"client_type"`
+	ClientVersion  string `json:"client_version"`
+	MinimumVersion string `json:"minimum_version"`
+}
+
+// 最低バージョンが設定された種類のクライアントのうち、それより古いものを 426 で拒否する
+// ヘッダーがないリクエストや最低バージョンのない種類（Web やサーバー間の呼び出しなど）は常に受け付ける
+func ClientVersionGate(minimums map[string]ClientVersion) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			raw := strings.TrimSpace(c.Request().Header.Get(HeaderClientVersion))
+			if raw == "" {
+				return next(c)
+			}
+
+			clientType, rawVersion, ok := strings.Cut(raw, "/")
+			clientType = strings.ToLower(clientType)
+			minimum, configured := minimums[clientType]
+			if !configured {
+				return next(c)
+			}
+			if !ok {
+				return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+					Error:   "invalid " + HeaderClientVersion + " header",
+					Details: []string{"expected <client>/<version>, e.g. ios/2.3.1"},
+				})
+			}
+
+			version, err := ParseClientVersion(rawVersion)
+			if err != nil {
+				return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+					Error:   "invalid " + HeaderClientVersion + " header",
+					Details: []string{err.Error()},
+				})
+			}
+			if version.Less(minimum) {
+				reqctx.Logger(c.Request().Context()).Info("rejected outdated client",
+					"client_type", clientType, "client_version", version.String(), "minimum_version", minimum.String())
+				return c.JSON(http.StatusUpgradeRequired, UpgradeRequiredResponse{
+					Error:          "client upgrade required",
+					Message:        fmt.Sprintf("This version of the %s app is no longer supported. Please update to %s or later.", clientType, minimum),
+					ClientType:     clientType,
+					ClientVersion:  version.String(),
+					MinimumVersion: minimum.String(),
+				})
+			}
+			return next(c)
+		}
+	}
+}
+
+// "ios=2.3.0, android=2.1.0" の形式でクライアントの種類ごとの最低バージョンを読み込む
+func ParseClientMinVersions(value string) (map[string]ClientVersion, error) {
+	minimums := make(map[string]ClientVersion)
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		clientType, rawVersion, ok := strings.Cut(part, "=")
+		clientType = strings.ToLower(strings.TrimSpace(clientType))
+		if !ok || clientType == "" {
+			return nil, fmt.Errorf("client minimum version %q: expected <client>=<version>", part)
+		}
+		version, err := ParseClientVersion(strings.TrimSpace(rawVersion))
+		if err != nil {
+			return nil, fmt.Errorf("client minimum version %q: %w", part, err)
+		}
+		minimums[clientType] = version
+	}
+	return minimums, nil
+}
@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseClientMinVersions(t *testing.T) {
+	minimums, err := ParseClientMinVersions("iOS=2.3.0, android=2.1 ,")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]ClientVersion{"ios": {2, 3, 0}, "android": {2, 1}}, minimums)
+
+	for _, invalid := range []string{"ios", "=2.0", "ios=", "ios=2.x", "ios=v2"} {
+		_, err := ParseClientMinVersions(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestClientVersion_Less(t *testing.T) {
+	parse := func(s string) ClientVersion {
+		v, err := ParseClientVersion(s)
+		require.NoError(t, err)
+		return v
+	}
+	assert.True(t, parse("2.2.9").Less(parse("2.3.0")))
+	assert.True(t, parse("2.9").Less(parse("2.10")))
+	assert.False(t, parse("2.3").Less(parse("2.3.0")))
+	assert.False(t, parse("2.3.0-beta.1").Less(parse("2.3.0")), "pre-release suffix is ignored")
+	assert.False(t, parse("3.0.0").Less(parse("2.3.0")))
+}
+
+func TestClientVersionGate(t *testing.T) {
+	gate := ClientVersionGate(map[string]ClientVersion{"ios": {2, 3, 0}})
+
+	serve := func(header string) *httptest.ResponseRecorder {
+		e := echo.New()
+		e.Use(gate)
+		e.GET("/items", func(c echo.Context) error { return c.NoContent(http.StatusNoContent) })
+
+		req := httptest.NewRequest(http.MethodGet, "/items", nil)
+		if header != "" {
+			req.Header.Set(HeaderClientVersion, header)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("正常系: 最低バージョン以上のクライアントは受け付ける", func(t *testing.T) {
+		assert.Equal(t, http.StatusNoContent, serve("ios/2.3.0").Code)
+		assert.Equal(t, http.StatusNoContent, serve("iOS/2.10").Code)
+	})
+
+	t.Run("正常系: ヘッダーがない・最低バージョンのない種類は受け付ける", func(t *testing.T) {
+		assert.Equal(t, http.StatusNoContent, serve("").Code)
+		assert.Equal(t, http.StatusNoContent, serve("web/1.0.0").Code)
+		assert.Equal(t, http.StatusNoContent, serve("android/0.1").Code)
+	})
+
+	t.Run("異常系: 古いクライアントは 426", func(t *testing.T) {
+		rec := serve("ios/2.2.9")
+		assert.Equal(t, http.StatusUpgradeRequired, rec.Code)
+
+		var body UpgradeRequiredResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		assert.Equal(t, "ios", body.ClientType)
+		assert.Equal(t, "2.2.9", body.ClientVersion)
+		assert.Equal(t, "2.3.0", body.MinimumVersion)
+		assert.NotEmpty(t, body.Message)
+	})
+
+	t.Run("異常系: バージョンを解釈できない場合は 400", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, serve("ios").Code)
+		assert.Equal(t, http.StatusBadRequest, serve("ios/latest").Code)
+	})
+}
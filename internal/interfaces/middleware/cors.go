@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// 許可したオリジン（"*" の場合はすべて）のブラウザからのアクセスを受け付ける
+// プリフライト（OPTIONS）には認証などを通さずに 204 で応答するため、認証より外側で使う
+func CORS(allowedOrigins []string) echo.MiddlewareFunc {
+	allowAll := slices.Contains(allowedOrigins, "*")
+	allowMethods := strings.Join([]string{http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}, ",")
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			header := c.Response().Header()
+			header.Add(echo.HeaderVary, echo.HeaderOrigin)
+
+			origin := req.Header.Get(echo.HeaderOrigin)
+			if origin == "" || (!allowAll && !slices.Contains(allowedOrigins, origin)) {
+				return next(c)
+			}
+			if allowAll {
+				header.Set(echo.HeaderAccessControlAllowOrigin, "*")
+			} else {
+				header.Set(echo.HeaderAccessControlAllowOrigin, origin)
+			}
+
+			if req.Method != http.MethodOptions || req.Header.Get(echo.HeaderAccessControlRequestMethod) == "" {
+				return next(c)
+			}
+			header.Add(echo.HeaderVary, echo.HeaderAccessControlRequestMethod)
+			header.Add(echo.HeaderVary, echo.HeaderAccessControlRequestHeaders)
+			header.Set(echo.HeaderAccessControlAllowMethods, allowMethods)
+			if requested := req.Header.Get(echo.HeaderAccessControlRequestHeaders); requested != "" {
+				header.Set(echo.HeaderAccessControlAllowHeaders, requested)
+			}
+			return c.NoContent(http.StatusNoContent)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCORS(t *testing.T) {
+	newServer := func(origins ...string) *echo.Echo {
+		e := echo.New()
+		e.Use(CORS(origins))
+		e.GET("/items", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+		return e
+	}
+	send := func(e *echo.Echo, method, origin string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/items", nil)
+		if origin != "" {
+			req.Header.Set(echo.HeaderOrigin, origin)
+		}
+		if method == http.MethodOptions {
+			req.Header.Set(echo.HeaderAccessControlRequestMethod, http.MethodGet)
+			req.Header.Set(echo.HeaderAccessControlRequestHeaders, "Authorization")
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("正常系: 許可したオリジンにはヘッダーを付ける", func(t *testing.T) {
+		rec := send(newServer("https://app.example.com"), http.MethodGet, "https://app.example.com")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "https://app.example.com", rec.Header().Get(echo.HeaderAccessControlAllowOrigin))
+	})
+
+	t.Run("正常系: プリフライトには 204 で応答する", func(t *testing.T) {
+		rec := send(newServer("https://app.example.com"), http.MethodOptions, "https://app.example.com")
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		assert.Contains(t, rec.Header().Get(echo.HeaderAccessControlAllowMethods), http.MethodPatch)
+		assert.Equal(t, "Authorization", rec.Header().Get(echo.HeaderAccessControlAllowHeaders))
+	})
+
+	t.Run("正常系: * の場合はすべてのオリジンを許可する", func(t *testing.T) {
+		rec := send(newServer("*"), http.MethodGet, "https://other.example.com")
+		assert.Equal(t, "*", rec.Header().Get(echo.HeaderAccessControlAllowOrigin))
+	})
+
+	t.Run("異常系: 許可していないオリジンにはヘッダーを付けない", func(t *testing.T) {
+		rec := send(newServer("https://app.example.com"), http.MethodGet, "https://evil.example.com")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Empty(t, rec.Header().Get(echo.HeaderAccessControlAllowOrigin))
+	})
+}
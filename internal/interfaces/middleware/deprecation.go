@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"Aicon-assignment/internal/interfaces/controller/response"
+	"Aicon-assignment/internal/pkg/reqctx"
+	"Aicon-assignment/internal/usecase"
+)
+
+// 廃止予定のルートを示すヘッダー（RFC 9745, RFC 8594）
+const (
+	HeaderDeprecation = "Deprecation"
+	HeaderSunset      = "Sunset"
+)
+
+// 廃止予定のルートのレスポンスに Deprecation・Sunset・Link ヘッダーを付け、呼び出し元ごとの利用を記録する
+// strict モードのリクエストは利用を記録した上で 400 で拒否する
+// 呼び出し元を記録するため Identity より内側で使う
+func Deprecation(deprecations usecase.DeprecationUsecase) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			deprecation, ok := deprecations.Lookup(req.Method, c.Path())
+			if !ok {
+				return next(c)
+			}
+
+			header := c.Response().Header()
+			header.Set(HeaderDeprecation, fmt.Sprintf("@%d", deprecation.Since.Unix()))
+			if deprecation.Sunset != nil {
+				header.Set(HeaderSunset, deprecation.Sunset.UTC().Format(http.TimeFormat))
+			}
+			if deprecation.Link != "" {
+				header.Add("Link", fmt.Sprintf(`<%s>; rel="deprecation"; type="text/html"`, deprecation.Link))
+			}
+
+			deprecations.RecordUsage(req.Context(), deprecation)
+			if reqctx.Strict(req.Context()) {
+				details := []string{fmt.Sprintf("%s %s is deprecated and rejected in strict mode", req.Method, c.Path())}
+				if deprecation.Link != "" {
+					details = append(details, "see "+deprecation.Link)
+				}
+				return c.JSON(http.StatusBadRequest, response.ErrorResponse{Error: "endpoint is deprecated", Details: details})
+			}
+			return next(c)
+		}
+	}
+}
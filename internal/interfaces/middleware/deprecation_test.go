@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"Aicon-assignment/internal/domain/entity"
+	"Aicon-assignment/internal/interfaces/database"
+	"Aicon-assignment/internal/pkg/clock"
+	"Aicon-assignment/internal/pkg/reqctx"
+	"Aicon-assignment/internal/usecase"
+)
+
+func TestDeprecation(t *testing.T) {
+	since := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	sunset := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	usage := database.NewMemoryDeprecationUsageRepository()
+	deprecations := usecase.NewDeprecationUsecase([]entity.Deprecation{
+		{Method: "GET", Route: "/items/summary", Since: since, Sunset: &sunset, Link: "https://example.com/migrate"},
+	}, usage, clock.NewFrozen(since))
+
+	e := echo.New()
+	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.SetRequest(c.Request().WithContext(reqctx.WithUserID(c.Request().Context(), 7)))
+			return next(c)
+		}
+	})
+	e.Use(Deprecation(deprecations))
+	e.GET("/items/summary", func(c echo.Context) error { return c.NoContent(http.StatusNoContent) })
+	e.GET("/items", func(c echo.Context) error { return c.NoContent(http.StatusNoContent) })
+
+	serve := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+
+	rec := serve("/items/summary")
+	assert.Equal(t, "@1717200000", rec.Header().Get(HeaderDeprecation))
+	assert.Equal(t, "Wed, 01 Jan 2025 00:00:00 GMT", rec.Header().Get(HeaderSunset))
+	assert.Equal(t, `<https://example.com/migrate>; rel="deprecation"; type="text/html"`, rec.Header().Get("Link"))
+	serve("/items/summary")
+
+	rec = serve("/items")
+	assert.Empty(t, rec.Header().Get(HeaderDeprecation), "not deprecated")
+
+	rows, err := usage.FindAll(context.Background())
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, "user:7", rows[0].Caller)
+	assert.Equal(t, int64(2), rows[0].Calls)
+}
@@ -0,0 +1,193 @@
+package middleware
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/interfaces/controller/response"
+	"Aicon-assignment/internal/pkg/reqctx"
+	"Aicon-assignment/internal/usecase"
+)
+
+// 呼び出し元のユーザーIDを指定するヘッダー
+const HeaderUserID = "X-User-ID"
+
+// 機械向けのクライアントが API キーを渡すヘッダー
+const HeaderAPIKey = "X-API-Key"
+
+// なりすまし中のレスポンスに付けるヘッダー
+const HeaderImpersonatedBy = "X-Impersonated-By"
+
+// 呼び出し元のユーザーをコンテキストに格納する
+// auth がある場合は Authorization: Bearer のアクセストークン（JWT）を検証し、X-User-ID は受け付けない
+// auth がない（認証を設定していない）開発用の環境（trustUserIDHeader が true）では、X-User-ID で名乗ったユーザーを使う
+// （存在しない・無効化されたユーザーは 401）。それ以外では X-User-ID は 401 にする
+// Authorization: Bearer imp_... の場合はなりすましセッションを検証し、
+// なりすまされているユーザーと管理者の両方を格納する（ロールはなりすまされているユーザーのもの）
+// X-API-Key の場合は、auth の有無にかかわらず API キーを検証してキーの持ち主のユーザーを格納する
+// サンドボックスの API キーはサンドボックスのルートにだけ通し、それ以外のルートでは 401 にする
+func Identity(users usecase.UserUsecase, impersonation usecase.ImpersonationUsecase, auth usecase.AuthUsecase, apiKeys usecase.APIKeyUsecase, trustUserIDHeader bool) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			ctx := req.Context()
+
+			if token, ok := impersonationToken(req); ok {
+				session, err := impersonation.Authenticate(ctx, token)
+				if err != nil {
+					if domainErrors.IsUnauthenticatedError(err) {
+						return response.Error(c, http.StatusUnauthorized, "invalid or expired impersonation token")
+					}
+					return response.RepositoryError(c, err, "failed to verify impersonation token")
+				}
+				user, err := users.ActiveUser(ctx, session.UserID)
+				if err != nil {
+					if domainErrors.IsUnauthenticatedError(err) {
+						return response.Error(c, http.StatusUnauthorized, "impersonated user is unknown or deactivated")
+					}
+					return response.RepositoryError(c, err, "failed to verify user")
+				}
+
+				ctx = reqctx.WithUserID(ctx, session.UserID)
+				ctx = reqctx.WithUserRole(ctx, user.Role)
+				ctx = reqctx.WithImpersonatorID(ctx, session.AdminID)
+				ctx = reqctx.WithLogger(ctx, reqctx.Logger(ctx).With(
+					slog.Int64("user_id", session.UserID),
+					slog.Int64("impersonator_id", session.AdminID),
+					slog.Int64("impersonation_session_id", session.ID),
+				))
+				c.Response().Header().Set(HeaderImpersonatedBy, fmt.Sprintf("user:%d", session.AdminID))
+				c.SetRequest(req.WithContext(ctx))
+				return next(c)
+			}
+
+			// サンドボックスのキーはサンドボックスのルートで SandboxIdentity が検証する
+			if key := req.Header.Get(HeaderAPIKey); strings.HasPrefix(key, usecase.SandboxKeyPrefix) {
+				if strings.HasPrefix(c.Path(), SandboxPathPrefix) {
+					return next(c)
+				}
+				return response.Error(c, http.StatusUnauthorized, "sandbox api keys are only accepted under "+SandboxPathPrefix)
+			}
+
+			if key := req.Header.Get(HeaderAPIKey); key != "" {
+				if req.Header.Get(echo.HeaderAuthorization) != "" || req.Header.Get(HeaderUserID) != "" {
+					return response.Error(c, http.StatusBadRequest, HeaderAPIKey+" cannot be combined with other credentials")
+				}
+				user, err := apiKeys.Authenticate(ctx, key)
+				if err != nil {
+					if domainErrors.IsUnauthenticatedError(err) {
+						return response.Error(c, http.StatusUnauthorized, "invalid or revoked api key")
+					}
+					return response.RepositoryError(c, err, "failed to verify api key")
+				}
+				ctx = reqctx.WithUserID(ctx, user.ID)
+				ctx = reqctx.WithUserRole(ctx, user.Role)
+				ctx = reqctx.WithLogger(ctx, reqctx.Logger(ctx).With(slog.Int64("user_id", user.ID), slog.String("auth", "api_key")))
+				c.SetRequest(req.WithContext(ctx))
+				return next(c)
+			}
+
+			if auth != nil {
+				if req.Header.Get(HeaderUserID) != "" {
+					return unauthorized(c, HeaderUserID+" is not accepted, use Authorization: Bearer with an access token")
+				}
+				token, ok := accessToken(req)
+				if !ok {
+					return next(c)
+				}
+				user, err := auth.Authenticate(ctx, token)
+				if err != nil {
+					if domainErrors.IsUnauthenticatedError(err) {
+						return unauthorized(c, "invalid or expired access token")
+					}
+					return response.RepositoryError(c, err, "failed to verify access token")
+				}
+				ctx = reqctx.WithUserID(ctx, user.ID)
+				ctx = reqctx.WithUserRole(ctx, user.Role)
+				ctx = reqctx.WithLogger(ctx, reqctx.Logger(ctx).With(slog.Int64("user_id", user.ID)))
+				c.SetRequest(req.WithContext(ctx))
+				return next(c)
+			}
+
+			if raw := req.Header.Get(HeaderUserID); raw != "" {
+				if !trustUserIDHeader {
+					return unauthorized(c, HeaderUserID+" is only accepted in development environments")
+				}
+				userID, err := strconv.ParseInt(raw, 10, 64)
+				if err != nil || userID <= 0 {
+					return response.Error(c, http.StatusBadRequest, "invalid "+HeaderUserID)
+				}
+				user, err := users.ActiveUser(ctx, userID)
+				if err != nil {
+					if domainErrors.IsUnauthenticatedError(err) {
+						return response.Error(c, http.StatusUnauthorized, "unknown or deactivated user")
+					}
+					return response.RepositoryError(c, err, "failed to verify user")
+				}
+				ctx = reqctx.WithUserID(ctx, userID)
+				ctx = reqctx.WithUserRole(ctx, user.Role)
+				ctx = reqctx.WithLogger(ctx, reqctx.Logger(ctx).With(slog.Int64("user_id", userID)))
+				c.SetRequest(req.WithContext(ctx))
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// ログインしていない（Identity でユーザーを格納していない）リクエストを 401 で拒否する
+func RequireUser() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if _, ok := reqctx.UserID(c.Request().Context()); !ok {
+				return unauthorized(c, "authentication required")
+			}
+			return next(c)
+		}
+	}
+}
+
+// 呼び出し元のユーザーが role でないリクエストを拒否する。ログインしていない場合は 401、ロールが違う場合は 403
+func RequireRole(role string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx := c.Request().Context()
+			if _, ok := reqctx.UserID(ctx); !ok {
+				return unauthorized(c, "authentication required")
+			}
+			if reqctx.UserRole(ctx) != role {
+				return response.Error(c, http.StatusForbidden, role+" role required")
+			}
+			return next(c)
+		}
+	}
+}
+
+func unauthorized(c echo.Context, message string) error {
+	c.Response().Header().Set(echo.HeaderWWWAuthenticate, "Bearer")
+	return response.Error(c, http.StatusUnauthorized, message)
+}
+
+// JWT の形式（ドットで区切った 3 つの部分）の Bearer トークンだけをアクセストークンとして扱う
+// SCIM のプロビジョニング用トークンなど、それ以外の Bearer トークンはそれぞれのルートで検証する
+func accessToken(req *http.Request) (string, bool) {
+	token, ok := strings.CutPrefix(req.Header.Get(echo.HeaderAuthorization), "Bearer ")
+	if !ok || strings.Count(token, ".") != 2 {
+		return "", false
+	}
+	return token, true
+}
+
+func impersonationToken(req *http.Request) (string, bool) {
+	token, ok := strings.CutPrefix(req.Header.Get(echo.HeaderAuthorization), "Bearer ")
+	if !ok || !strings.HasPrefix(token, usecase.ImpersonationTokenPrefix) {
+		return "", false
+	}
+	return token, true
+}
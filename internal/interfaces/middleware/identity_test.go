@@ -0,0 +1,137 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/pkg/reqctx"
+	"Aicon-assignment/internal/usecase"
+)
+
+// 決まったキーだけを受け付ける API キーの認証
+type stubAPIKeys struct {
+	usecase.APIKeyUsecase
+	key  string
+	user *entity.User
+}
+
+func (s *stubAPIKeys) Authenticate(ctx context.Context, key string) (*entity.User, error) {
+	if key != s.key {
+		return nil, domainErrors.ErrUnauthenticated
+	}
+	return s.user, nil
+}
+
+func TestIdentity_APIKey(t *testing.T) {
+	apiKeys := &stubAPIKeys{key: "ak_valid", user: &entity.User{ID: 7, Role: entity.UserRoleMember, Active: true}}
+	e := echo.New()
+	e.Use(Identity(nil, nil, nil, apiKeys, false))
+	e.GET("/me", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		userID, ok := reqctx.UserID(ctx)
+		if !ok {
+			return c.NoContent(http.StatusNoContent)
+		}
+		return c.JSON(http.StatusOK, map[string]any{"user_id": userID, "role": reqctx.UserRole(ctx)})
+	})
+
+	send := func(headers map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/me", nil)
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("正常系: キーの持ち主のユーザーとロールを格納する", func(t *testing.T) {
+		rec := send(map[string]string{HeaderAPIKey: "ak_valid"})
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"user_id":7,"role":"member"}`, rec.Body.String())
+	})
+
+	t.Run("異常系: 失効した・存在しないキー", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized, send(map[string]string{HeaderAPIKey: "ak_revoked"}).Code)
+	})
+
+	t.Run("異常系: 他の認証情報と一緒に送った", func(t *testing.T) {
+		rec := send(map[string]string{HeaderAPIKey: "ak_valid", HeaderUserID: "1"})
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+// 有効なユーザーを 1 人だけ返すユーザーの参照
+type stubUsers struct {
+	usecase.UserUsecase
+	user *entity.User
+}
+
+func (s *stubUsers) ActiveUser(ctx context.Context, id int64) (*entity.User, error) {
+	if id != s.user.ID {
+		return nil, domainErrors.ErrUnauthenticated
+	}
+	return s.user, nil
+}
+
+func TestIdentity_UserIDHeader(t *testing.T) {
+	users := &stubUsers{user: &entity.User{ID: 1, Role: entity.UserRoleAdmin, Active: true}}
+	send := func(trustUserIDHeader bool) *httptest.ResponseRecorder {
+		e := echo.New()
+		e.Use(Identity(users, nil, nil, nil, trustUserIDHeader))
+		e.GET("/me", func(c echo.Context) error {
+			userID, _ := reqctx.UserID(c.Request().Context())
+			return c.JSON(http.StatusOK, map[string]any{"user_id": userID})
+		})
+		req := httptest.NewRequest(http.MethodGet, "/me", nil)
+		req.Header.Set(HeaderUserID, "1")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("正常系: 開発用の環境では名乗ったユーザーを使う", func(t *testing.T) {
+		rec := send(true)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"user_id":1}`, rec.Body.String())
+	})
+
+	t.Run("異常系: 開発用の環境以外では受け付けない", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized, send(false).Code)
+	})
+}
+
+func TestRequireRole(t *testing.T) {
+	e := echo.New()
+	e.GET("/admin", func(c echo.Context) error { return c.NoContent(http.StatusOK) }, RequireRole(entity.UserRoleAdmin))
+
+	send := func(ctx context.Context) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/admin", nil).WithContext(ctx)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("正常系: 管理者", func(t *testing.T) {
+		ctx := reqctx.WithUserRole(reqctx.WithUserID(context.Background(), 1), entity.UserRoleAdmin)
+		assert.Equal(t, http.StatusOK, send(ctx).Code)
+	})
+
+	t.Run("異常系: メンバー", func(t *testing.T) {
+		ctx := reqctx.WithUserRole(reqctx.WithUserID(context.Background(), 2), entity.UserRoleMember)
+		rec := send(ctx)
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+		assert.Contains(t, rec.Body.String(), "admin role required")
+	})
+
+	t.Run("異常系: ログインしていない", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized, send(context.Background()).Code)
+	})
+}
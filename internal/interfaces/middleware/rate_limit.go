@@ -0,0 +1,137 @@
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"Aicon-assignment/internal/domain/entity"
+	"Aicon-assignment/internal/interfaces/controller/response"
+	"Aicon-assignment/internal/pkg/clock"
+)
+
+// レート制限を数える単位
+const (
+	RateLimitByIP     = "ip"      // 接続元の IP アドレスごと
+	RateLimitByAPIKey = "api_key" // X-API-Key ごと。API キーのないリクエストは IP アドレスごと
+)
+
+type RateLimitConfig struct {
+	Requests int           // Window あたりに受け付けるリクエスト数
+	Window   time.Duration // この時間で Requests 回分まで回復する
+	By       string        // RateLimitByIP か RateLimitByAPIKey
+	// X-Forwarded-For・X-Real-IP の IP アドレスを使う（信頼できるロードバランサーの後ろで動かす場合のみ）
+	// false の場合は接続元の IP アドレスを使い、ヘッダーを偽って制限を逃れられないようにする
+	TrustProxy bool
+	Clock      clock.Clock
+}
+
+// "ip" または "api_key" を検証する
+func ParseRateLimitBy(value string) (string, error) {
+	switch value {
+	case RateLimitByIP, RateLimitByAPIKey:
+		return value, nil
+	}
+	return "", fmt.Errorf("invalid rate limit key %q (want %s or %s)", value, RateLimitByIP, RateLimitByAPIKey)
+}
+
+// クライアントごとのトークンバケットでリクエストを数え、上限を超えたものを 429 と Retry-After で拒否する
+// exempt のルート（ヘルスチェックなど）は数えない
+func RateLimit(config RateLimitConfig, exempt ...string) echo.MiddlewareFunc {
+	limiter := newRateLimiter(config.Requests, config.Window)
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if slices.Contains(exempt, c.Path()) {
+				return next(c)
+			}
+
+			ok, retryAfter := limiter.allow(rateLimitKey(c, config), config.Clock.Now())
+			if !ok {
+				seconds := int(math.Ceil(retryAfter.Seconds()))
+				c.Response().Header().Set("Retry-After", strconv.Itoa(max(seconds, 1)))
+				return response.Error(c, http.StatusTooManyRequests, "too many requests")
+			}
+			return next(c)
+		}
+	}
+}
+
+func rateLimitKey(c echo.Context, config RateLimitConfig) string {
+	if config.By == RateLimitByAPIKey {
+		if key := c.Request().Header.Get(HeaderAPIKey); key != "" {
+			// キーそのものは覚えておかない
+			return "key:" + entity.HashToken(key)
+		}
+	}
+	if config.TrustProxy {
+		return "ip:" + c.RealIP()
+	}
+	return "ip:" + echo.ExtractIPDirect()(c.Request())
+}
+
+type rateBucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+type rateLimiter struct {
+	mu        sync.Mutex
+	capacity  float64
+	perSecond float64 // 1 秒あたりに回復する数
+	window    time.Duration
+	buckets   map[string]*rateBucket
+	swept     time.Time
+}
+
+func newRateLimiter(requests int, window time.Duration) *rateLimiter {
+	return &rateLimiter{
+		capacity:  float64(requests),
+		perSecond: float64(requests) / window.Seconds(),
+		window:    window,
+		buckets:   map[string]*rateBucket{},
+	}
+}
+
+// 受け付ける場合は true。拒否する場合は次の 1 回を受け付けられるまでの時間を返す
+func (l *rateLimiter) allow(key string, now time.Time) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep(now)
+
+	bucket, ok := l.buckets[key]
+	if !ok {
+		bucket = &rateBucket{tokens: l.capacity, updated: now}
+		l.buckets[key] = bucket
+	}
+	if elapsed := now.Sub(bucket.updated).Seconds(); elapsed > 0 {
+		bucket.tokens = math.Min(l.capacity, bucket.tokens+elapsed*l.perSecond)
+		bucket.updated = now
+	}
+
+	if bucket.tokens < 1 {
+		return false, time.Duration((1 - bucket.tokens) / l.perSecond * float64(time.Second))
+	}
+	bucket.tokens--
+	return true, 0
+}
+
+// 満杯まで回復したバケットは新しく作ったものと同じため、Window ごとに捨ててメモリを使い続けないようにする
+func (l *rateLimiter) sweep(now time.Time) {
+	if now.Sub(l.swept) < l.window {
+		return
+	}
+	l.swept = now
+	for key, bucket := range l.buckets {
+		if now.Sub(bucket.updated) >= l.window {
+			delete(l.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"Aicon-assignment/internal/pkg/clock"
+)
+
+func TestRateLimit(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	newServer := func(config RateLimitConfig) *echo.Echo {
+		e := echo.New()
+		e.Use(RateLimit(config, "/health"))
+		ok := func(c echo.Context) error { return c.NoContent(http.StatusNoContent) }
+		e.GET("/items", ok)
+		e.GET("/health", ok)
+		return e
+	}
+	request := func(e *echo.Echo, path string, setup func(*http.Request)) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.RemoteAddr = "192.0.2.1:1234"
+		if setup != nil {
+			setup(req)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("正常系: 上限までは受け付け、超えたら 429 と Retry-After を返す", func(t *testing.T) {
+		clk := clock.NewFrozen(now)
+		e := newServer(RateLimitConfig{Requests: 2, Window: time.Minute, By: RateLimitByIP, Clock: clk})
+
+		assert.Equal(t, http.StatusNoContent, request(e, "/items", nil).Code)
+		assert.Equal(t, http.StatusNoContent, request(e, "/items", nil).Code)
+		rec := request(e, "/items", nil)
+		assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+		// 2 回/分のため、1 回分の回復に 30 秒
+		assert.Equal(t, "30", rec.Header().Get("Retry-After"))
+
+		clk.Advance(30 * time.Second)
+		assert.Equal(t, http.StatusNoContent, request(e, "/items", nil).Code)
+		assert.Equal(t, http.StatusTooManyRequests, request(e, "/items", nil).Code)
+	})
+
+	t.Run("正常系: ヘルスチェックは数えない", func(t *testing.T) {
+		e := newServer(RateLimitConfig{Requests: 1, Window: time.Minute, By: RateLimitByIP, Clock: clock.NewFrozen(now)})
+
+		for range 3 {
+			assert.Equal(t, http.StatusNoContent, request(e, "/health", nil).Code)
+		}
+		assert.Equal(t, http.StatusNoContent, request(e, "/items", nil).Code)
+	})
+
+	t.Run("正常系: IP アドレスごとに数える", func(t *testing.T) {
+		e := newServer(RateLimitConfig{Requests: 1, Window: time.Minute, By: RateLimitByIP, Clock: clock.NewFrozen(now)})
+
+		assert.Equal(t, http.StatusNoContent, request(e, "/items", nil).Code)
+		assert.Equal(t, http.StatusNoContent, request(e, "/items", func(r *http.Request) { r.RemoteAddr = "192.0.2.2:1234" }).Code)
+		assert.Equal(t, http.StatusTooManyRequests, request(e, "/items", nil).Code)
+	})
+
+	t.Run("正常系: TrustProxy でない場合は X-Forwarded-For を使わない", func(t *testing.T) {
+		e := newServer(RateLimitConfig{Requests: 1, Window: time.Minute, By: RateLimitByIP, Clock: clock.NewFrozen(now)})
+		spoofed := func(ip string) func(*http.Request) {
+			return func(r *http.Request) { r.Header.Set(echo.HeaderXForwardedFor, ip) }
+		}
+
+		assert.Equal(t, http.StatusNoContent, request(e, "/items", spoofed("198.51.100.1")).Code)
+		assert.Equal(t, http.StatusTooManyRequests, request(e, "/items", spoofed("198.51.100.2")).Code)
+	})
+
+	t.Run("正常系: API キーごとに数え、キーのないリクエストは IP アドレスごと", func(t *testing.T) {
+		e := newServer(RateLimitConfig{Requests: 1, Window: time.Minute, By: RateLimitByAPIKey, Clock: clock.NewFrozen(now)})
+		withKey := func(key string) func(*http.Request) {
+			return func(r *http.Request) { r.Header.Set(HeaderAPIKey, key) }
+		}
+
+		assert.Equal(t, http.StatusNoContent, request(e, "/items", withKey("ak_one")).Code)
+		assert.Equal(t, http.StatusNoContent, request(e, "/items", withKey("ak_two")).Code)
+		assert.Equal(t, http.StatusNoContent, request(e, "/items", nil).Code)
+		assert.Equal(t, http.StatusTooManyRequests, request(e, "/items", withKey("ak_one")).Code)
+	})
+}
+
+func TestRateLimiter_Sweep(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	limiter := newRateLimiter(10, time.Minute)
+
+	ok, _ := limiter.allow("ip:192.0.2.1", now)
+	require.True(t, ok)
+	ok, _ = limiter.allow("ip:192.0.2.2", now.Add(time.Minute))
+	require.True(t, ok)
+
+	// 1 分使われていないバケットは捨てる
+	assert.Len(t, limiter.buckets, 1)
+}
+
+func TestParseRateLimitBy(t *testing.T) {
+	by, err := ParseRateLimitBy("api_key")
+	require.NoError(t, err)
+	assert.Equal(t, RateLimitByAPIKey, by)
+
+	_, err = ParseRateLimitBy("user")
+	assert.Error(t, err)
+}
@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/labstack/echo/v4"
+
+	"Aicon-assignment/internal/interfaces/controller/response"
+)
+
+// 固定された読み取り専用モードを解除しようとした
+var ErrReadOnlyForced = errors.New("read-only mode is forced and cannot be changed")
+
+type ReadOnlyStatus struct {
+	Enabled bool   `json:"enabled"`
+	Reason  string `json:"reason,omitempty"`
+	Forced  bool   `json:"forced"` // 書き込むとデータを壊すおそれがあるため、起動中は解除できない
+}
+
+// 参照以外のリクエストを 503 で拒否する読み取り専用モード
+// DB のフェイルオーバーやリストアの間は管理者が切り替え、
+// DB のスキーマと互換性がない場合は起動時に固定する
+type ReadOnlyMode struct {
+	mu     sync.RWMutex
+	status ReadOnlyStatus
+}
+
+func NewReadOnlyMode(enabled bool, reason string) *ReadOnlyMode {
+	m := &ReadOnlyMode{}
+	m.Set(enabled, reason)
+	return m
+}
+
+func (m *ReadOnlyMode) Status() ReadOnlyStatus {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.status
+}
+
+// 無効にする場合、理由は破棄する
+func (m *ReadOnlyMode) Set(enabled bool, reason string) (ReadOnlyStatus, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.status.Forced {
+		return m.status, ErrReadOnlyForced
+	}
+	m.status = ReadOnlyStatus{Enabled: enabled}
+	if enabled {
+		m.status.Reason = strings.TrimSpace(reason)
+	}
+	return m.status, nil
+}
+
+// 読み取り専用モードにし、以降は解除できないようにする
+func (m *ReadOnlyMode) Force(reason string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.status = ReadOnlyStatus{Enabled: true, Reason: reason, Forced: true}
+}
+
+// 有効な間は参照以外のリクエストを拒否する
+// exempt のルート（モードの切り替えなど）は常に受け付ける
+func (m *ReadOnlyMode) Middleware(exempt ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			switch c.Request().Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				return next(c)
+			}
+			status := m.Status()
+			if !status.Enabled || slices.Contains(exempt, c.Path()) {
+				return next(c)
+			}
+
+			body := response.ErrorResponse{Error: "service is in read-only mode"}
+			if status.Reason != "" {
+				body.Details = []string{status.Reason}
+			}
+			return c.JSON(http.StatusServiceUnavailable, body)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"Aicon-assignment/internal/pkg/idgen"
+	"Aicon-assignment/internal/pkg/reqctx"
+)
+
+// クライアントが指定できるリクエストIDの最大長
+const maxRequestIDLength = 128
+
+// リクエストIDとリクエスト単位のロガーをコンテキストに格納する
+// クライアントが X-Request-ID を指定した場合はそれを引き継ぐ（長すぎる・使えない文字を含む場合は新しく振る）
+func RequestContext(baseLogger *slog.Logger) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+
+			requestID := req.Header.Get(echo.HeaderXRequestID)
+			if !validRequestID(requestID) {
+				requestID = idgen.NewRandomID()
+			}
+			c.Response().Header().Set(echo.HeaderXRequestID, requestID)
+
+			logger := baseLogger.With(
+				slog.String("request_id", requestID),
+				slog.String("method", req.Method),
+				slog.String("path", req.URL.Path),
+			)
+
+			ctx := reqctx.WithRequestID(req.Context(), requestID)
+			ctx = reqctx.WithLogger(ctx, logger)
+			c.SetRequest(req.WithContext(ctx))
+
+			return next(c)
+		}
+	}
+}
+
+// リクエストの終わりに、ステータスと所要時間をリクエストのロガーで残す（5xx はエラー、それ以外は info）
+// アクセスログと違ってアプリケーションのログに出すため、同じリクエストのログを request_id でまとめて追える
+// RequestContext の内側で使う。exempt のルート（c.Path()）は残さない
+func RequestLog(exempt ...string) echo.MiddlewareFunc {
+	skip := make(map[string]bool, len(exempt))
+	for _, path := range exempt {
+		skip[path] = true
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if skip[c.Path()] {
+				return next(c)
+			}
+			start := time.Now()
+
+			err := next(c)
+			if err != nil {
+				// ステータスコードを確定させるため、ここでエラーレスポンスを書き出す
+				c.Error(err)
+			}
+
+			// 内側で格納したユーザーIDなども含めるため、処理した後のコンテキストのロガーを使う
+			ctx := c.Request().Context()
+			status := c.Response().Status
+			level := slog.LevelInfo
+			if status >= 500 {
+				level = slog.LevelError
+			}
+			reqctx.Logger(ctx).LogAttrs(ctx, level, "request completed",
+				slog.String("route", c.Path()),
+				slog.Int("status", status),
+				slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+			)
+			return err
+		}
+	}
+}
+
+// ログやヘッダーにそのまま書き出せる値か（英数字と - _ . : だけ）
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-' || r == '_' || r == '.' || r == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,104 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"Aicon-assignment/internal/pkg/reqctx"
+)
+
+func TestRequestContext(t *testing.T) {
+	var buf bytes.Buffer
+	e := echo.New()
+	e.Use(RequestContext(slog.New(slog.NewJSONHandler(&buf, nil))))
+	e.Use(RequestLog("/health"))
+	e.GET("/items/:id", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		// Identity と同じく、内側でユーザーIDをロガーに加える
+		ctx = reqctx.WithLogger(ctx, reqctx.Logger(ctx).With(slog.Int64("user_id", 7)))
+		c.SetRequest(c.Request().WithContext(ctx))
+		reqctx.Logger(ctx).Info("handler")
+		return c.NoContent(http.StatusOK)
+	})
+	e.GET("/fail", func(c echo.Context) error { return errors.New("boom") })
+	e.GET("/health", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+
+	send := func(path, requestID string) *httptest.ResponseRecorder {
+		buf.Reset()
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if requestID != "" {
+			req.Header.Set(echo.HeaderXRequestID, requestID)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+	lines := func() []map[string]any {
+		var entries []map[string]any
+		for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+			if line == "" {
+				continue
+			}
+			var entry map[string]any
+			require.NoError(t, json.Unmarshal([]byte(line), &entry))
+			entries = append(entries, entry)
+		}
+		return entries
+	}
+
+	t.Run("正常系: 同じリクエストのログにはすべて同じリクエストIDが付く", func(t *testing.T) {
+		rec := send("/items/3", "req-abc.1")
+		assert.Equal(t, "req-abc.1", rec.Header().Get(echo.HeaderXRequestID))
+
+		entries := lines()
+		require.Len(t, entries, 2)
+		for _, entry := range entries {
+			assert.Equal(t, "req-abc.1", entry["request_id"])
+			assert.Equal(t, "GET", entry["method"])
+			assert.Equal(t, "/items/3", entry["path"])
+			assert.Equal(t, float64(7), entry["user_id"])
+		}
+		assert.Equal(t, "request completed", entries[1]["msg"])
+		assert.Equal(t, "/items/:id", entries[1]["route"])
+		assert.Equal(t, float64(200), entries[1]["status"])
+		assert.Contains(t, entries[1], "latency_ms")
+	})
+
+	t.Run("正常系: 指定がない場合は新しく振る", func(t *testing.T) {
+		rec := send("/items/3", "")
+		assert.NotEmpty(t, rec.Header().Get(echo.HeaderXRequestID))
+	})
+
+	t.Run("正常系: 使えない文字を含む・長すぎる場合は引き継がない", func(t *testing.T) {
+		for _, requestID := range []string{"abc\r\nlevel=ERROR", strings.Repeat("a", 129), "a b"} {
+			rec := send("/items/3", requestID)
+			assert.NotEqual(t, requestID, rec.Header().Get(echo.HeaderXRequestID))
+			assert.NotEmpty(t, rec.Header().Get(echo.HeaderXRequestID))
+		}
+	})
+
+	t.Run("正常系: 5xx はエラーとして残す", func(t *testing.T) {
+		rec := send("/fail", "req-fail")
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+
+		entries := lines()
+		require.Len(t, entries, 1)
+		assert.Equal(t, "ERROR", entries[0]["level"])
+		assert.Equal(t, float64(500), entries[0]["status"])
+	})
+
+	t.Run("正常系: 除外したルートは残さない", func(t *testing.T) {
+		send("/health", "")
+		assert.Empty(t, lines())
+	})
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/interfaces/controller/response"
+	"Aicon-assignment/internal/pkg/reqctx"
+	"Aicon-assignment/internal/usecase"
+)
+
+// サンドボックスのルートの接頭辞。サンドボックスの API キーはここでだけ受け付ける
+const SandboxPathPrefix = "/sandbox/"
+
+// サンドボックスのルートの認証。本番の認証とは別に、X-API-Key のサンドボックスのキーだけを受け付ける
+// キーの持ち主をメンバーとして格納し、キーごとのデータセットを選ぶ（reqctx.WithSandbox）
+func SandboxIdentity(keys usecase.APIKeyUsecase) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			ctx := req.Context()
+
+			key := req.Header.Get(HeaderAPIKey)
+			if !strings.HasPrefix(key, usecase.SandboxKeyPrefix) {
+				return unauthorized(c, "sandbox api key required in "+HeaderAPIKey)
+			}
+			if req.Header.Get(echo.HeaderAuthorization) != "" || req.Header.Get(HeaderUserID) != "" {
+				return response.Error(c, http.StatusBadRequest, HeaderAPIKey+" cannot be combined with other credentials")
+			}
+			user, err := keys.Authenticate(ctx, key)
+			if err != nil {
+				if domainErrors.IsUnauthenticatedError(err) {
+					return response.Error(c, http.StatusUnauthorized, "invalid or revoked sandbox api key")
+				}
+				return response.RepositoryError(c, err, "failed to verify sandbox api key")
+			}
+
+			// 管理者のキーでも、サンドボックスでは自分のデータセットだけを扱うメンバーにする
+			ctx = reqctx.WithUserID(ctx, user.ID)
+			ctx = reqctx.WithUserRole(ctx, entity.UserRoleMember)
+			ctx = reqctx.WithSandbox(ctx, entity.HashToken(key))
+			ctx = reqctx.WithLogger(ctx, reqctx.Logger(ctx).With(slog.Int64("user_id", user.ID), slog.String("auth", "sandbox")))
+			c.SetRequest(req.WithContext(ctx))
+			return next(c)
+		}
+	}
+}
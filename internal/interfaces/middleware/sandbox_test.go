@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"Aicon-assignment/internal/domain/entity"
+	"Aicon-assignment/internal/pkg/reqctx"
+)
+
+func TestSandboxIdentity(t *testing.T) {
+	apiKeys := &stubAPIKeys{key: "ak_valid", user: &entity.User{ID: 7, Role: entity.UserRoleAdmin, Active: true}}
+	sandboxKeys := &stubAPIKeys{key: "sbx_valid", user: &entity.User{ID: 7, Role: entity.UserRoleAdmin, Active: true}}
+	e := echo.New()
+	e.Use(Identity(nil, nil, nil, apiKeys, false))
+	me := func(c echo.Context) error {
+		ctx := c.Request().Context()
+		userID, ok := reqctx.UserID(ctx)
+		if !ok {
+			return c.NoContent(http.StatusNoContent)
+		}
+		sandboxID, _ := reqctx.Sandbox(ctx)
+		return c.JSON(http.StatusOK, map[string]any{"user_id": userID, "role": reqctx.UserRole(ctx), "sandbox": sandboxID})
+	}
+	e.GET("/me", me)
+	e.GET("/sandbox/me", me, SandboxIdentity(sandboxKeys))
+
+	send := func(path string, headers map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("正常系: キーの持ち主をメンバーとして格納し、キーごとのデータセットを選ぶ", func(t *testing.T) {
+		rec := send("/sandbox/me", map[string]string{HeaderAPIKey: "sbx_valid"})
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"user_id":7,"role":"member","sandbox":"`+entity.HashToken("sbx_valid")+`"}`, rec.Body.String())
+	})
+
+	t.Run("異常系: サンドボックスのキーがない", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized, send("/sandbox/me", nil).Code)
+	})
+
+	t.Run("異常系: 本番の API キーは受け付けない", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized, send("/sandbox/me", map[string]string{HeaderAPIKey: "ak_valid"}).Code)
+	})
+
+	t.Run("異常系: 失効した・存在しないキー", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized, send("/sandbox/me", map[string]string{HeaderAPIKey: "sbx_revoked"}).Code)
+	})
+
+	t.Run("異常系: 他の認証情報と一緒に送った", func(t *testing.T) {
+		rec := send("/sandbox/me", map[string]string{HeaderAPIKey: "sbx_valid", HeaderUserID: "1"})
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("異常系: サンドボックスのキーで本番のルートを呼んだ", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized, send("/me", map[string]string{HeaderAPIKey: "sbx_valid"}).Code)
+	})
+}
@@ -0,0 +1,144 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"Aicon-assignment/internal/interfaces/controller/response"
+	"Aicon-assignment/internal/pkg/clock"
+	"Aicon-assignment/internal/pkg/reqctx"
+	"Aicon-assignment/internal/usecase"
+)
+
+// 署名付きリクエストのヘッダー
+const (
+	HeaderSignatureKey       = "X-Signature-Key"
+	HeaderSignatureTimestamp = "X-Signature-Timestamp" // Unix 秒
+	HeaderSignatureNonce     = "X-Signature-Nonce"
+	HeaderSignature          = "X-Signature" // 署名対象の HMAC-SHA256 を 16 進数にしたもの
+)
+
+// nonce の長さ。短すぎるものは衝突しやすく、長すぎるものはキャッシュを圧迫する
+const (
+	minNonceLength = 16
+	maxNonceLength = 128
+)
+
+type SignatureConfig struct {
+	Keys      map[string][]byte // 鍵のID ごとの共有鍵
+	ClockSkew time.Duration     // 許容する時刻のずれ（前後それぞれ）
+	Cache     usecase.ReplayCache
+	Clock     clock.Clock
+}
+
+// "client-a:secret,client-b:secret" の形式で鍵を設定する
+func ParseSigningKeys(value string) (map[string][]byte, error) {
+	keys := make(map[string][]byte)
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		id, secret, ok := strings.Cut(entry, ":")
+		id, secret = strings.TrimSpace(id), strings.TrimSpace(secret)
+		if !ok || id == "" || secret == "" {
+			return nil, fmt.Errorf("invalid signing key %q (want id:secret)", entry)
+		}
+		if _, dup := keys[id]; dup {
+			return nil, fmt.Errorf("duplicate signing key id %q", id)
+		}
+		keys[id] = []byte(secret)
+	}
+	return keys, nil
+}
+
+// 署名付きの書き込みリクエストを検証し、同じリクエストの再送を拒否する
+// X-Signature-Key のないリクエストは検証しない（署名はサーバー間の連携などが任意で使う）
+// 署名の対象は "メソッド\nパスとクエリ\nタイムスタンプ\nnonce\nボディの SHA-256（16 進数）"
+// タイムスタンプが ClockSkew を超えてずれたもの、署名が合わないもの、使用済みの nonce は 401 にする
+func VerifySignature(cfg SignatureConfig) echo.MiddlewareFunc {
+	// 許容範囲内のタイムスタンプが受け付けられる間は nonce を覚えておく
+	nonceTTL := 2 * cfg.ClockSkew
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			keyID := req.Header.Get(HeaderSignatureKey)
+			if keyID == "" || !isWrite(req.Method) {
+				return next(c)
+			}
+
+			secret, ok := cfg.Keys[keyID]
+			if !ok {
+				return unsignedRequest(c, "unknown signing key")
+			}
+			nonce := req.Header.Get(HeaderSignatureNonce)
+			if len(nonce) < minNonceLength || len(nonce) > maxNonceLength {
+				return unsignedRequest(c, fmt.Sprintf("%s must be %d to %d characters", HeaderSignatureNonce, minNonceLength, maxNonceLength))
+			}
+			rawTimestamp := req.Header.Get(HeaderSignatureTimestamp)
+			timestamp, err := strconv.ParseInt(rawTimestamp, 10, 64)
+			if err != nil {
+				return unsignedRequest(c, fmt.Sprintf("%s must be a Unix time in seconds", HeaderSignatureTimestamp))
+			}
+			if skew := cfg.Clock.Now().Sub(time.Unix(timestamp, 0)); skew > cfg.ClockSkew || skew < -cfg.ClockSkew {
+				return unsignedRequest(c, "request timestamp is outside the allowed clock skew")
+			}
+
+			// ボディはハッシュを取った後でハンドラーが読めるように戻す
+			body, err := io.ReadAll(req.Body)
+			if err != nil {
+				return response.Error(c, http.StatusBadRequest, "failed to read request body")
+			}
+			req.Body = io.NopCloser(bytes.NewReader(body))
+
+			expected := RequestSignature(secret, req.Method, req.URL.RequestURI(), rawTimestamp, nonce, body)
+			if !hmac.Equal([]byte(strings.ToLower(req.Header.Get(HeaderSignature))), []byte(expected)) {
+				return unsignedRequest(c, "signature does not match")
+			}
+
+			// 署名を確かめてから nonce を使用済みにする（偽のリクエストで正規の nonce を潰されないようにする）
+			fresh, err := cfg.Cache.Remember(req.Context(), keyID+":"+nonce, nonceTTL)
+			if err != nil {
+				reqctx.Logger(req.Context()).Error("replay cache unavailable", "error", err)
+				return response.Error(c, http.StatusServiceUnavailable, "replay protection is unavailable")
+			}
+			if !fresh {
+				reqctx.Logger(req.Context()).Warn("replayed request rejected", "signing_key", keyID)
+				return unsignedRequest(c, "request has already been used")
+			}
+			return next(c)
+		}
+	}
+}
+
+// クライアントと同じ手順で署名を計算する
+func RequestSignature(secret []byte, method, requestURI, timestamp, nonce string, body []byte) string {
+	bodyHash := sha256.Sum256(body)
+	mac := hmac.New(sha256.New, secret)
+	fmt.Fprintf(mac, "%s\n%s\n%s\n%s\n%s", method, requestURI, timestamp, nonce, hex.EncodeToString(bodyHash[:]))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func isWrite(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	default:
+		return true
+	}
+}
+
+func unsignedRequest(c echo.Context, reason string) error {
+	return c.JSON(http.StatusUnauthorized, response.ErrorResponse{Error: "invalid request signature", Details: []string{reason}})
+}
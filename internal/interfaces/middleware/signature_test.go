@@ -0,0 +1,181 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"Aicon-assignment/internal/pkg/clock"
+)
+
+// 期限を考えずに覚えておくリプレイキャッシュ
+type mapReplayCache map[string]bool
+
+func (m mapReplayCache) Remember(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	if m[key] {
+		return false, nil
+	}
+	m[key] = true
+	return true, nil
+}
+
+type failingReplayCache struct{}
+
+func (failingReplayCache) Remember(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	return false, errors.New("connection refused")
+}
+
+func TestParseSigningKeys(t *testing.T) {
+	t.Run("正常系: 鍵のIDごとに共有鍵を返す", func(t *testing.T) {
+		keys, err := ParseSigningKeys("client-a:secret-a, client-b:secret-b")
+		require.NoError(t, err)
+		assert.Equal(t, map[string][]byte{"client-a": []byte("secret-a"), "client-b": []byte("secret-b")}, keys)
+	})
+
+	t.Run("異常系: 共有鍵がない", func(t *testing.T) {
+		_, err := ParseSigningKeys("client-a")
+		assert.Error(t, err)
+	})
+
+	t.Run("異常系: 鍵のIDが重複している", func(t *testing.T) {
+		_, err := ParseSigningKeys("client-a:x,client-a:y")
+		assert.Error(t, err)
+	})
+}
+
+func TestVerifySignature(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	secret := []byte("secret-a")
+	clk := clock.NewFrozen(now)
+
+	newServer := func(cfg SignatureConfig) *echo.Echo {
+		e := echo.New()
+		e.Use(VerifySignature(cfg))
+		e.POST("/items", func(c echo.Context) error {
+			body, _ := io.ReadAll(c.Request().Body)
+			return c.String(http.StatusCreated, string(body))
+		})
+		e.GET("/items", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+		return e
+	}
+	e := newServer(SignatureConfig{
+		Keys:      map[string][]byte{"client-a": secret},
+		ClockSkew: 5 * time.Minute,
+		Cache:     mapReplayCache{},
+		Clock:     clk,
+	})
+
+	type signed struct {
+		method, target, body, nonce string
+		at                          time.Time
+		key                         string
+		signature                   string // 空の場合は正しい署名を付ける
+	}
+	send := func(e *echo.Echo, s signed) *httptest.ResponseRecorder {
+		if s.method == "" {
+			s.method = http.MethodPost
+		}
+		if s.target == "" {
+			s.target = "/items"
+		}
+		if s.key == "" {
+			s.key = "client-a"
+		}
+		if s.at.IsZero() {
+			s.at = now
+		}
+		timestamp := strconv.FormatInt(s.at.Unix(), 10)
+		if s.signature == "" {
+			s.signature = RequestSignature(secret, s.method, s.target, timestamp, s.nonce, []byte(s.body))
+		}
+		req := httptest.NewRequest(s.method, s.target, strings.NewReader(s.body))
+		req.Header.Set(HeaderSignatureKey, s.key)
+		req.Header.Set(HeaderSignatureTimestamp, timestamp)
+		req.Header.Set(HeaderSignatureNonce, s.nonce)
+		req.Header.Set(HeaderSignature, s.signature)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("正常系: 署名が正しいリクエストを通し、ボディはハンドラーが読める", func(t *testing.T) {
+		rec := send(e, signed{body: `{"name":"a"}`, nonce: "nonce-0000000001"})
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Equal(t, `{"name":"a"}`, rec.Body.String())
+	})
+
+	t.Run("異常系: 同じ nonce の再送", func(t *testing.T) {
+		rec := send(e, signed{body: `{"name":"a"}`, nonce: "nonce-0000000001"})
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.Contains(t, rec.Body.String(), "request has already been used")
+	})
+
+	t.Run("正常系: 許容範囲内の時刻のずれ", func(t *testing.T) {
+		rec := send(e, signed{nonce: "nonce-0000000002", at: now.Add(-4 * time.Minute)})
+		assert.Equal(t, http.StatusCreated, rec.Code)
+	})
+
+	t.Run("異常系: 許容範囲を超えて古いタイムスタンプ", func(t *testing.T) {
+		rec := send(e, signed{nonce: "nonce-0000000003", at: now.Add(-6 * time.Minute)})
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.Contains(t, rec.Body.String(), "clock skew")
+	})
+
+	t.Run("異常系: 未来のタイムスタンプ", func(t *testing.T) {
+		rec := send(e, signed{nonce: "nonce-0000000004", at: now.Add(6 * time.Minute)})
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+
+	t.Run("異常系: ボディを書き換えたリクエスト", func(t *testing.T) {
+		timestamp := strconv.FormatInt(now.Unix(), 10)
+		signature := RequestSignature(secret, http.MethodPost, "/items", timestamp, "nonce-0000000005", []byte(`{"name":"a"}`))
+		rec := send(e, signed{body: `{"name":"b"}`, nonce: "nonce-0000000005", signature: signature})
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.Contains(t, rec.Body.String(), "signature does not match")
+
+		// 偽の署名では nonce を使用済みにしない
+		rec = send(e, signed{body: `{"name":"a"}`, nonce: "nonce-0000000005"})
+		assert.Equal(t, http.StatusCreated, rec.Code)
+	})
+
+	t.Run("異常系: 知らない鍵", func(t *testing.T) {
+		rec := send(e, signed{nonce: "nonce-0000000006", key: "client-z"})
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+
+	t.Run("異常系: 短すぎる nonce", func(t *testing.T) {
+		rec := send(e, signed{nonce: "short"})
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+
+	t.Run("正常系: 署名のないリクエストと参照は検証しない", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/items", strings.NewReader("{}"))
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+
+		rec = send(e, signed{method: http.MethodGet, nonce: "x", signature: "bad"})
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("異常系: リプレイキャッシュが使えない場合は受け付けない", func(t *testing.T) {
+		failing := newServer(SignatureConfig{
+			Keys:      map[string][]byte{"client-a": secret},
+			ClockSkew: 5 * time.Minute,
+			Cache:     failingReplayCache{},
+			Clock:     clk,
+		})
+		rec := send(failing, signed{nonce: "nonce-0000000007"})
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	})
+}
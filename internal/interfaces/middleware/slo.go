@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"Aicon-assignment/internal/usecase"
+)
+
+// ルートごとに応答のステータスと時間を SLO の追跡に記録する
+// クライアントから見た結果を記録するため、障害注入よりも外側で使う
+func SLO(slo usecase.SLOUsecase) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+
+			err := next(c)
+			if err != nil {
+				// ステータスコードを確定させるため、ここでエラーレスポンスを書き出す
+				c.Error(err)
+			}
+
+			slo.Record(c.Request().Method, c.Path(), c.Response().Status, time.Since(start))
+			return err
+		}
+	}
+}
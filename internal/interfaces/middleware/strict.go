@@ -0,0 +1,103 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"Aicon-assignment/internal/interfaces/controller/response"
+	"Aicon-assignment/internal/pkg/reqctx"
+)
+
+// strict モードを指定するヘッダー。レスポンスにも strict モードで処理したかを付ける
+const HeaderStrictMode = "X-Strict-Mode"
+
+// 処理は続けたが問題があったことを知らせるヘッダー（"299 - "message"" の形式）
+const HeaderWarning = "Warning"
+
+// X-Strict-Mode: true のリクエストと、strict モードを設定した組織のリクエストを strict モードにする
+// strict モードでは、通常は Warning ヘッダーで知らせるだけの問題（未知のフィールド、相場から外れた購入価格、
+// 廃止予定のルート）をエラーにする。組織に設定した strict モードはヘッダーで解除できない
+// 組織を参照するため Identity より内側、廃止予定のルートを拒否するため Deprecation より外側で使う
+func StrictMode(orgs func() []int64) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			strict := false
+			if raw := req.Header.Get(HeaderStrictMode); raw != "" {
+				parsed, err := strconv.ParseBool(raw)
+				if err != nil {
+					return response.Error(c, http.StatusBadRequest, "invalid "+HeaderStrictMode+" header")
+				}
+				strict = parsed
+			}
+			if orgID, ok := reqctx.OrgID(req.Context()); ok && slices.Contains(orgs(), orgID) {
+				strict = true
+			}
+
+			c.Response().Header().Set(HeaderStrictMode, strconv.FormatBool(strict))
+			c.SetRequest(req.WithContext(reqctx.WithStrict(req.Context(), strict)))
+			return next(c)
+		}
+	}
+}
+
+// JSON のボディにリクエストの型にないフィールドがあれば Warning ヘッダーで知らせ、strict モードでは 400 にする
+// フィールド名の綴り間違いなど、黙って無視されると気づきにくい問題を見つけるため
+type StrictBinder struct {
+	echo.DefaultBinder
+}
+
+func (b *StrictBinder) Bind(i interface{}, c echo.Context) error {
+	req := c.Request()
+	if req.ContentLength == 0 || !strings.HasPrefix(req.Header.Get(echo.HeaderContentType), echo.MIMEApplicationJSON) {
+		return b.DefaultBinder.Bind(i, c)
+	}
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	if err := b.DefaultBinder.Bind(i, c); err != nil {
+		return err
+	}
+
+	field, ok := unknownField(body, i)
+	if !ok {
+		return nil
+	}
+	message := fmt.Sprintf("unknown field %q", field)
+	c.Response().Header().Add(HeaderWarning, fmt.Sprintf("299 - %q", message))
+	if reqctx.Strict(req.Context()) {
+		return echo.NewHTTPError(http.StatusBadRequest, message)
+	}
+	return nil
+}
+
+// 未知のフィールドを拒否する設定で読み直し、最初に見つかった未知のフィールドを返す
+func unknownField(body []byte, i interface{}) (string, bool) {
+	t := reflect.TypeOf(i)
+	if t == nil || t.Kind() != reflect.Pointer {
+		return "", false
+	}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.DisallowUnknownFields()
+	err := decoder.Decode(reflect.New(t.Elem()).Interface())
+	if err == nil {
+		return "", false
+	}
+	field, ok := strings.CutPrefix(err.Error(), "json: unknown field ")
+	if !ok {
+		return "", false
+	}
+	return strings.Trim(field, `"`), true
+}
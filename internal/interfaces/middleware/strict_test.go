@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"Aicon-assignment/internal/domain/entity"
+	"Aicon-assignment/internal/interfaces/database"
+	"Aicon-assignment/internal/pkg/clock"
+	"Aicon-assignment/internal/pkg/reqctx"
+	"Aicon-assignment/internal/usecase"
+)
+
+func TestStrictMode(t *testing.T) {
+	type input struct {
+		Name string `json:"name"`
+	}
+	since := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	deprecations := usecase.NewDeprecationUsecase([]entity.Deprecation{
+		{Method: "GET", Route: "/old", Since: since},
+	}, database.NewMemoryDeprecationUsageRepository(), clock.NewFrozen(since))
+
+	e := echo.New()
+	e.Binder = &StrictBinder{}
+	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if org := c.Request().Header.Get("X-Test-Org"); org != "" {
+				c.SetRequest(c.Request().WithContext(reqctx.WithOrgID(c.Request().Context(), 3)))
+			}
+			return next(c)
+		}
+	})
+	e.Use(StrictMode(func() []int64 { return []int64{3} }))
+	e.Use(Deprecation(deprecations))
+	e.POST("/items", func(c echo.Context) error {
+		var in input
+		if err := c.Bind(&in); err != nil {
+			return c.NoContent(http.StatusBadRequest)
+		}
+		return c.String(http.StatusOK, in.Name)
+	})
+	e.GET("/old", func(c echo.Context) error { return c.NoContent(http.StatusNoContent) })
+
+	post := func(body string, header map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/items", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		for key, value := range header {
+			req.Header.Set(key, value)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("正常系: 通常は未知のフィールドを Warning ヘッダーで知らせて受け付ける", func(t *testing.T) {
+		rec := post(`{"name":"a","nmae":"b"}`, nil)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "a", rec.Body.String())
+		assert.Equal(t, `299 - "unknown field \"nmae\""`, rec.Header().Get(HeaderWarning))
+		assert.Equal(t, "false", rec.Header().Get(HeaderStrictMode))
+	})
+
+	t.Run("異常系: strict モードでは未知のフィールドを拒否する", func(t *testing.T) {
+		rec := post(`{"name":"a","nmae":"b"}`, map[string]string{HeaderStrictMode: "true"})
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Header().Get(HeaderWarning), "nmae")
+	})
+
+	t.Run("正常系: 未知のフィールドがなければ strict モードでも受け付ける", func(t *testing.T) {
+		rec := post(`{"name":"a"}`, map[string]string{HeaderStrictMode: "true"})
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Empty(t, rec.Header().Get(HeaderWarning))
+	})
+
+	t.Run("異常系: 組織の strict モードはヘッダーで解除できない", func(t *testing.T) {
+		rec := post(`{"nmae":"b"}`, map[string]string{HeaderStrictMode: "false", "X-Test-Org": "3"})
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, "true", rec.Header().Get(HeaderStrictMode))
+	})
+
+	t.Run("異常系: strict モードでは廃止予定のルートを拒否する", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/old", nil)
+		req.Header.Set(HeaderStrictMode, "1")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "endpoint is deprecated")
+	})
+
+	t.Run("異常系: 不正なヘッダー", func(t *testing.T) {
+		rec := post(`{}`, map[string]string{HeaderStrictMode: "yes please"})
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
@@ -0,0 +1,58 @@
+package cdnurl
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigner(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 10, 0, 0, time.UTC)
+	signer := NewSigner("https://cdn.example.com/", "secret", time.Hour)
+
+	parse := func(t *testing.T, raw string) *url.URL {
+		u, err := url.Parse(raw)
+		require.NoError(t, err)
+		return u
+	}
+
+	t.Run("正常系: バージョンと有効期限を付けて署名し、Verify で検証できる", func(t *testing.T) {
+		u := parse(t, signer.URL("items/1/front.jpg", "abc123", now))
+		assert.Equal(t, "cdn.example.com", u.Host)
+		assert.Equal(t, "/items/1/front.jpg", u.Path)
+		assert.Equal(t, "abc123", u.Query().Get(ParamVersion))
+		assert.Equal(t, "1717250400", u.Query().Get(ParamExpires)) // 2024-06-01T14:00:00Z
+		assert.NoError(t, signer.Verify(u.Path, u.Query(), now))
+	})
+
+	t.Run("正常系: 同じ期間内は同じ URL になり、内容が変わると URL も変わる", func(t *testing.T) {
+		first := signer.URL("items/1/front.jpg", "abc123", now)
+		assert.Equal(t, first, signer.URL("items/1/front.jpg", "abc123", now.Add(30*time.Minute)))
+		assert.NotEqual(t, first, signer.URL("items/1/front.jpg", "def456", now))
+	})
+
+	t.Run("正常系: 鍵がない場合は署名しない", func(t *testing.T) {
+		assert.Equal(t, "https://cdn.example.com/items/1/front.jpg?v=abc123",
+			NewSigner("https://cdn.example.com", "", time.Hour).URL("/items/1/front.jpg", "abc123", now))
+	})
+
+	t.Run("異常系: 期限切れ", func(t *testing.T) {
+		u := parse(t, signer.URL("items/1/front.jpg", "abc123", now))
+		assert.ErrorIs(t, signer.Verify(u.Path, u.Query(), now.Add(3*time.Hour)), ErrExpired)
+	})
+
+	t.Run("異常系: バージョンや有効期限を書き換えた", func(t *testing.T) {
+		u := parse(t, signer.URL("items/1/front.jpg", "abc123", now))
+		query := u.Query()
+		query.Set(ParamExpires, "9999999999")
+		assert.ErrorIs(t, signer.Verify(u.Path, query, now), ErrInvalidSignature)
+
+		query = u.Query()
+		query.Set(ParamVersion, "def456")
+		assert.ErrorIs(t, signer.Verify(u.Path, query, now), ErrInvalidSignature)
+		assert.ErrorIs(t, signer.Verify("/items/2/front.jpg", u.Query(), now), ErrInvalidSignature)
+	})
+}
@@ -0,0 +1,46 @@
+// Package clock は現在時刻の取得を抽象化し、テストで時刻を固定できるようにする。
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+type Clock interface {
+	Now() time.Time
+}
+
+// 実時間を返す Clock
+type System struct{}
+
+func (System) Now() time.Time {
+	return time.Now()
+}
+
+// 常に同じ時刻を返す Clock（テスト・モックサーバー用）
+type Frozen struct {
+	mu  sync.RWMutex
+	now time.Time
+}
+
+func NewFrozen(now time.Time) *Frozen {
+	return &Frozen{now: now}
+}
+
+func (f *Frozen) Now() time.Time {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.now
+}
+
+func (f *Frozen) Set(now time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = now
+}
+
+func (f *Frozen) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = f.now.Add(d)
+}
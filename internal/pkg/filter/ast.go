@@ -0,0 +1,62 @@
+// Package filter は ?filter= で受け取る小さな条件式を構文木に変換する。
+//
+//	category = "時計" AND purchase_price > 500000 AND purchase_date >= "2023-01-01"
+//
+// 使えるフィールドと型は呼び出し側が Fields で指定し、構文木は検証済みのものだけを返す。
+package filter
+
+// フィールドの型
+type FieldType int
+
+const (
+	String FieldType = iota
+	Number
+	Date // YYYY-MM-DD 形式の文字列
+)
+
+// 条件式で使えるフィールドの一覧
+type Fields map[string]FieldType
+
+// 比較演算子
+const (
+	OpEq  = "="
+	OpNe  = "!="
+	OpGt  = ">"
+	OpGte = ">="
+	OpLt  = "<"
+	OpLte = "<="
+)
+
+// 論理演算子
+const (
+	OpAnd = "AND"
+	OpOr  = "OR"
+)
+
+// 構文木のノード
+type Expr interface {
+	expr()
+}
+
+// AND / OR
+type Logical struct {
+	Op    string
+	Left  Expr
+	Right Expr
+}
+
+// NOT
+type Not struct {
+	Expr Expr
+}
+
+// フィールドと値の比較。Value は string または int64
+type Comparison struct {
+	Field string
+	Op    string
+	Value any
+}
+
+func (*Logical) expr()    {}
+func (*Not) expr()        {}
+func (*Comparison) expr() {}
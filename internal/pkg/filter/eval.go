@@ -0,0 +1,61 @@
+package filter
+
+import "strings"
+
+// 条件式をメモリ上の値で評価する
+// value はフィールド名から string または int64 の値を返す
+func Evaluate(expr Expr, value func(field string) any) bool {
+	switch e := expr.(type) {
+	case *Logical:
+		if e.Op == OpAnd {
+			return Evaluate(e.Left, value) && Evaluate(e.Right, value)
+		}
+		return Evaluate(e.Left, value) || Evaluate(e.Right, value)
+	case *Not:
+		return !Evaluate(e.Expr, value)
+	case *Comparison:
+		return compare(value(e.Field), e.Op, e.Value)
+	}
+	return false
+}
+
+func compare(actual any, op string, expected any) bool {
+	var cmp int
+	switch a := actual.(type) {
+	case int64:
+		b, ok := expected.(int64)
+		if !ok {
+			return false
+		}
+		switch {
+		case a < b:
+			cmp = -1
+		case a > b:
+			cmp = 1
+		}
+	case string:
+		b, ok := expected.(string)
+		if !ok {
+			return false
+		}
+		cmp = strings.Compare(a, b)
+	default:
+		return false
+	}
+
+	switch op {
+	case OpEq:
+		return cmp == 0
+	case OpNe:
+		return cmp != 0
+	case OpGt:
+		return cmp > 0
+	case OpGte:
+		return cmp >= 0
+	case OpLt:
+		return cmp < 0
+	case OpLte:
+		return cmp <= 0
+	}
+	return false
+}
@@ -0,0 +1,215 @@
+package filter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 条件式の上限。過度に複雑な式でDBに負荷をかけないようにする
+const (
+	MaxLength      = 1000
+	MaxComparisons = 20
+)
+
+// 条件式を構文木に変換し、フィールドと値の型を検証する
+func Parse(input string, fields Fields) (Expr, error) {
+	if len(input) > MaxLength {
+		return nil, fmt.Errorf("filter must be at most %d characters", MaxLength)
+	}
+
+	tokens, err := tokenize(input)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens, fields: fields}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos)
+	}
+
+	return expr, nil
+}
+
+type parser struct {
+	tokens      []token
+	pos         int
+	fields      Fields
+	comparisons int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// キーワードは大文字小文字を区別しない
+func (p *parser) acceptKeyword(keyword string) bool {
+	if tok := p.peek(); tok.kind == tokenIdent && strings.EqualFold(tok.text, keyword) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) parseOr() (Expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptKeyword(OpOr) {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &Logical{Op: OpOr, Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptKeyword(OpAnd) {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &Logical{Op: OpAnd, Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (Expr, error) {
+	if p.acceptKeyword("NOT") {
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &Not{Expr: expr}, nil
+	}
+
+	if p.peek().kind == tokenLParen {
+		p.next()
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if tok := p.next(); tok.kind != tokenRParen {
+			return nil, fmt.Errorf("expected ')' at position %d", tok.pos)
+		}
+		return expr, nil
+	}
+
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (Expr, error) {
+	fieldTok := p.next()
+	if fieldTok.kind != tokenIdent {
+		return nil, fmt.Errorf("expected field name at position %d", fieldTok.pos)
+	}
+	fieldType, ok := p.fields[fieldTok.text]
+	if !ok {
+		return nil, fmt.Errorf("unknown field: %s", fieldTok.text)
+	}
+
+	opTok := p.next()
+	if opTok.kind != tokenOperator {
+		return nil, fmt.Errorf("expected operator after %s at position %d", fieldTok.text, opTok.pos)
+	}
+	if fieldType == String && opTok.text != OpEq && opTok.text != OpNe {
+		return nil, fmt.Errorf("%s only supports = and !=", fieldTok.text)
+	}
+
+	valueTok := p.next()
+	value, err := parseValue(fieldTok.text, fieldType, valueTok)
+	if err != nil {
+		return nil, err
+	}
+
+	p.comparisons++
+	if p.comparisons > MaxComparisons {
+		return nil, fmt.Errorf("filter must have at most %d conditions", MaxComparisons)
+	}
+
+	return &Comparison{Field: fieldTok.text, Op: opTok.text, Value: value}, nil
+}
+
+func parseValue(field string, fieldType FieldType, tok token) (any, error) {
+	switch fieldType {
+	case Number:
+		if tok.kind != tokenNumber {
+			return nil, fmt.Errorf("%s must be compared with a number", field)
+		}
+		n, err := strconv.ParseInt(tok.text, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number for %s: %s", field, tok.text)
+		}
+		return n, nil
+	case Date:
+		if tok.kind != tokenString {
+			return nil, fmt.Errorf("%s must be compared with a quoted date", field)
+		}
+		if _, err := time.Parse("2006-01-02", tok.text); err != nil {
+			return nil, fmt.Errorf("%s must be in YYYY-MM-DD format", field)
+		}
+		return tok.text, nil
+	default:
+		if tok.kind != tokenString {
+			return nil, fmt.Errorf("%s must be compared with a quoted string", field)
+		}
+		return tok.text, nil
+	}
+}
+
+// 条件式の比較演算子に対応する名前（filter[field][gte]=... 形式で使う）
+var namedOperators = map[string]string{
+	"eq":  OpEq,
+	"ne":  OpNe,
+	"gt":  OpGt,
+	"gte": OpGte,
+	"lt":  OpLt,
+	"lte": OpLte,
+}
+
+// 引用符なしの値から検証済みの比較を作る
+// op は "eq", "gte" などの名前で指定する
+func NewComparison(field, op, raw string, fields Fields) (*Comparison, error) {
+	fieldType, ok := fields[field]
+	if !ok {
+		return nil, fmt.Errorf("unknown field: %s", field)
+	}
+	operator, ok := namedOperators[op]
+	if !ok {
+		return nil, fmt.Errorf("unknown operator for %s: %s", field, op)
+	}
+	if fieldType == String && operator != OpEq && operator != OpNe {
+		return nil, fmt.Errorf("%s only supports = and !=", field)
+	}
+
+	tok := token{kind: tokenString, text: raw}
+	if fieldType == Number {
+		tok.kind = tokenNumber
+	}
+	value, err := parseValue(field, fieldType, tok)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Comparison{Field: field, Op: operator, Value: value}, nil
+}
@@ -0,0 +1,143 @@
+package filter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testFields = Fields{
+	"category":       String,
+	"purchase_price": Number,
+	"purchase_date":  Date,
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expected    Expr
+		expectedErr string
+	}{
+		{
+			name:  "正常系: ANDで連結",
+			input: `category = "時計" AND purchase_price > 500000 AND purchase_date >= "2023-01-01"`,
+			expected: &Logical{
+				Op: OpAnd,
+				Left: &Logical{
+					Op:    OpAnd,
+					Left:  &Comparison{Field: "category", Op: OpEq, Value: "時計"},
+					Right: &Comparison{Field: "purchase_price", Op: OpGt, Value: int64(500000)},
+				},
+				Right: &Comparison{Field: "purchase_date", Op: OpGte, Value: "2023-01-01"},
+			},
+		},
+		{
+			name:  "正常系: ANDはORより優先される",
+			input: `category = "時計" or category = "バッグ" and purchase_price <= 100`,
+			expected: &Logical{
+				Op:   OpOr,
+				Left: &Comparison{Field: "category", Op: OpEq, Value: "時計"},
+				Right: &Logical{
+					Op:    OpAnd,
+					Left:  &Comparison{Field: "category", Op: OpEq, Value: "バッグ"},
+					Right: &Comparison{Field: "purchase_price", Op: OpLte, Value: int64(100)},
+				},
+			},
+		},
+		{
+			name:  "正常系: 括弧とNOT",
+			input: `NOT (category = "靴" OR category != "その他")`,
+			expected: &Not{Expr: &Logical{
+				Op:    OpOr,
+				Left:  &Comparison{Field: "category", Op: OpEq, Value: "靴"},
+				Right: &Comparison{Field: "category", Op: OpNe, Value: "その他"},
+			}},
+		},
+		{
+			name:     "正常系: 文字列のエスケープ",
+			input:    `category = "a\"b"`,
+			expected: &Comparison{Field: "category", Op: OpEq, Value: `a"b`},
+		},
+		{
+			name:        "異常系: 許可されていないフィールド",
+			input:       `password = "x"`,
+			expectedErr: "unknown field: password",
+		},
+		{
+			name:        "異常系: 数値フィールドに文字列",
+			input:       `purchase_price > "1000"`,
+			expectedErr: "purchase_price must be compared with a number",
+		},
+		{
+			name:        "異常系: 日付の形式が不正",
+			input:       `purchase_date >= "2023/01/01"`,
+			expectedErr: "purchase_date must be in YYYY-MM-DD format",
+		},
+		{
+			name:        "異常系: 文字列フィールドの大小比較",
+			input:       `category > "a"`,
+			expectedErr: "category only supports = and !=",
+		},
+		{
+			name:        "異常系: SQLの埋め込み",
+			input:       `category = "時計"; DROP TABLE items`,
+			expectedErr: `unexpected ";"`,
+		},
+		{
+			name:        "異常系: 閉じていない括弧",
+			input:       `(category = "時計"`,
+			expectedErr: "expected ')'",
+		},
+		{
+			name:        "異常系: 閉じていない文字列",
+			input:       `category = "時計`,
+			expectedErr: "unterminated string",
+		},
+		{
+			name:        "異常系: 条件が多すぎる",
+			input:       strings.Repeat(`purchase_price > 1 AND `, MaxComparisons) + `purchase_price > 1`,
+			expectedErr: "at most 20 conditions",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr, err := Parse(tt.input, testFields)
+
+			if tt.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, expr)
+		})
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	expr, err := Parse(`category = "時計" AND (purchase_price > 500000 OR purchase_date < "2023-01-01")`, testFields)
+	require.NoError(t, err)
+
+	record := func(category string, price int64, date string) func(string) any {
+		return func(field string) any {
+			switch field {
+			case "category":
+				return category
+			case "purchase_price":
+				return price
+			default:
+				return date
+			}
+		}
+	}
+
+	assert.True(t, Evaluate(expr, record("時計", 1000000, "2023-06-01")))
+	assert.True(t, Evaluate(expr, record("時計", 1000, "2022-12-31")))
+	assert.False(t, Evaluate(expr, record("時計", 1000, "2023-06-01")))
+	assert.False(t, Evaluate(expr, record("バッグ", 1000000, "2023-06-01")))
+}
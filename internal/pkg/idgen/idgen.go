@@ -0,0 +1,29 @@
+// Package idgen はID採番を抽象化する。
+// MySQL では AUTO_INCREMENT が採番するため、主にインメモリ実装やテストで使う。
+package idgen
+
+import "sync/atomic"
+
+type IDGenerator interface {
+	NextID() int64
+}
+
+// 1から順に採番する IDGenerator
+type Sequence struct {
+	last atomic.Int64
+}
+
+func NewSequence() *Sequence {
+	return &Sequence{}
+}
+
+// start から採番を始める（テストでIDを固定したい場合に使う）
+func NewSequenceFrom(start int64) *Sequence {
+	s := &Sequence{}
+	s.last.Store(start - 1)
+	return s
+}
+
+func (s *Sequence) NextID() int64 {
+	return s.last.Add(1)
+}
@@ -0,0 +1,15 @@
+package idgen
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// 推測困難なランダムID（16バイトの16進文字列）を返す
+func NewRandomID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,95 @@
+// Package jwt は HS256 で署名した JSON Web Token（RFC 7519）を発行・検証する。
+// 発行するのもこのサーバーだけのため、アルゴリズムは HS256 に固定し、ヘッダーの alg が異なるトークンは受け付けない。
+package jwt
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalid = errors.New("jwt: invalid token")
+	ErrExpired = errors.New("jwt: token is expired")
+)
+
+// 扱う登録済みクレーム。時刻は Unix 秒
+type Claims struct {
+	Subject   string `json:"sub"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+	ID        string `json:"jti,omitempty"`
+}
+
+type header struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ,omitempty"`
+}
+
+var encoding = base64.RawURLEncoding
+
+// 固定のヘッダー {"alg":"HS256","typ":"JWT"} をエンコードしたもの
+var encodedHeader = encode(header{Alg: "HS256", Typ: "JWT"})
+
+// Sign は claims を secret で署名したトークンを返す
+func Sign(claims Claims, secret []byte) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("jwt: failed to encode claims: %w", err)
+	}
+	unsigned := encodedHeader + "." + encoding.EncodeToString(payload)
+	return unsigned + "." + signature(unsigned, secret), nil
+}
+
+// Verify は署名と有効期限を確かめてクレームを返す
+// 形式・署名が不正な場合は ErrInvalid、期限切れの場合は ErrExpired を返す
+func Verify(token string, secret []byte, now time.Time) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrInvalid
+	}
+
+	var h header
+	if err := decode(parts[0], &h); err != nil || h.Alg != "HS256" {
+		return nil, ErrInvalid
+	}
+	if !hmac.Equal([]byte(parts[2]), []byte(signature(parts[0]+"."+parts[1], secret))) {
+		return nil, ErrInvalid
+	}
+
+	var claims Claims
+	if err := decode(parts[1], &claims); err != nil {
+		return nil, ErrInvalid
+	}
+	if claims.ExpiresAt == 0 {
+		return nil, ErrInvalid
+	}
+	if !now.Before(time.Unix(claims.ExpiresAt, 0)) {
+		return nil, ErrExpired
+	}
+	return &claims, nil
+}
+
+func signature(unsigned string, secret []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(unsigned))
+	return encoding.EncodeToString(mac.Sum(nil))
+}
+
+func encode(v interface{}) string {
+	data, _ := json.Marshal(v)
+	return encoding.EncodeToString(data)
+}
+
+func decode(part string, v interface{}) error {
+	data, err := encoding.DecodeString(part)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,55 @@
+package jwt
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	secret := []byte("0123456789abcdef0123456789abcdef")
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	claims := Claims{Subject: "7", IssuedAt: now.Unix(), ExpiresAt: now.Add(time.Hour).Unix(), ID: "abc"}
+
+	token, err := Sign(claims, secret)
+	require.NoError(t, err)
+
+	t.Run("正常系: 署名したクレームを取り出せる", func(t *testing.T) {
+		got, err := Verify(token, secret, now)
+		require.NoError(t, err)
+		assert.Equal(t, claims, *got)
+	})
+
+	t.Run("異常系: 期限切れ", func(t *testing.T) {
+		_, err := Verify(token, secret, now.Add(time.Hour))
+		assert.ErrorIs(t, err, ErrExpired)
+	})
+
+	t.Run("異常系: 別の鍵で署名したトークン", func(t *testing.T) {
+		_, err := Verify(token, []byte("another secret"), now)
+		assert.ErrorIs(t, err, ErrInvalid)
+	})
+
+	t.Run("異常系: クレームを書き換えたトークン", func(t *testing.T) {
+		parts := strings.Split(token, ".")
+		forged := Claims{Subject: "1", IssuedAt: claims.IssuedAt, ExpiresAt: claims.ExpiresAt}
+		parts[1] = encode(forged)
+		_, err := Verify(strings.Join(parts, "."), secret, now)
+		assert.ErrorIs(t, err, ErrInvalid)
+	})
+
+	t.Run("異常系: 署名のないトークン（alg: none）", func(t *testing.T) {
+		parts := strings.Split(token, ".")
+		unsigned := encode(header{Alg: "none"}) + "." + parts[1] + "."
+		_, err := Verify(unsigned, secret, now)
+		assert.ErrorIs(t, err, ErrInvalid)
+	})
+
+	t.Run("異常系: 形式が不正", func(t *testing.T) {
+		_, err := Verify("not-a-token", secret, now)
+		assert.ErrorIs(t, err, ErrInvalid)
+	})
+}
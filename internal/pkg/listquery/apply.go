@@ -0,0 +1,62 @@
+package listquery
+
+import (
+	"sort"
+	"strings"
+
+	"Aicon-assignment/internal/pkg/filter"
+)
+
+// メモリ上の一覧に絞り込み・並び替え・ページングを適用する
+// value は要素とフィールド名から string または int64 の値を返す
+func Apply[T any](items []T, q Query, value func(item T, field string) any) *Result[T] {
+	matched := make([]T, 0, len(items))
+	for _, item := range items {
+		if q.Filter == nil || filter.Evaluate(q.Filter, func(field string) any { return value(item, field) }) {
+			matched = append(matched, item)
+		}
+	}
+
+	if len(q.Sort) > 0 {
+		sort.SliceStable(matched, func(i, j int) bool {
+			for _, s := range q.Sort {
+				cmp := compareValues(value(matched[i], s.Field), value(matched[j], s.Field))
+				if cmp == 0 {
+					continue
+				}
+				if s.Desc {
+					return cmp > 0
+				}
+				return cmp < 0
+			}
+			return false
+		})
+	}
+
+	result := &Result[T]{Items: matched, Total: len(matched)}
+	if q.Page.Size > 0 {
+		start := min(q.Page.Offset(), len(matched))
+		end := min(start+q.Page.Size, len(matched))
+		result.Items = matched[start:end]
+	}
+
+	return result
+}
+
+func compareValues(a, b any) int {
+	switch x := a.(type) {
+	case int64:
+		y, _ := b.(int64)
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+		return 0
+	case string:
+		y, _ := b.(string)
+		return strings.Compare(x, y)
+	}
+	return 0
+}
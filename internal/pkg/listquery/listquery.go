@@ -0,0 +1,301 @@
+// Package listquery は一覧系エンドポイントで共通のクエリパラメータを解釈する。
+// JSON:API の規約に合わせ、次の形式を受け付ける。
+//
+//	filter[category]=時計            等価条件
+//	filter[purchase_price][gte]=1000  演算子付きの条件（eq, ne, gt, gte, lt, lte）
+//	category=時計                     等価条件の省略形（Spec.Shorthands のフィールドのみ）
+//	min_price=1000                    演算子付きの条件の省略形（Spec.Bounds のパラメータのみ）
+//	filter=<条件式>                   filter パッケージの条件式
+//	sort=-created_at,name             並び順（- は降順）
+//	sort=purchase_price&order=desc    - を付けていないフィールドの向き（asc, desc）
+//	page[number]=2&page[size]=20      ページング
+//	limit=20&offset=40                ページング（件数で開始位置を指定する場合）
+//	cursor=<カーソル>&limit=20          カーソルによるページング（Spec.Cursor が true の一覧のみ）
+package listquery
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"Aicon-assignment/internal/pkg/filter"
+)
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// 一覧ごとに使えるフィールド
+type Spec struct {
+	Filters     filter.Fields // 絞り込みに使えるフィールド
+	Shorthands  []string      // filter[...] を付けずにパラメータ名のまま等価条件に使えるフィールド
+	Bounds      []Bound       // 独自のパラメータ名で範囲の条件に使えるフィールド
+	Sorts       []string      // 並び替えに使えるフィールド
+	DefaultSort []SortField
+	// カーソルによるページングに対応しているか
+	// カーソルの並びは一覧ごとに固定のため、sort とは併用できない
+	Cursor bool
+}
+
+// パラメータ Param の値を Field と Op で比較する条件にする
+type Bound struct {
+	Param string
+	Field string
+	Op    string // eq, ne, gt, gte, lt, lte
+}
+
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+// Size が 0 の場合はページングしない
+// limit/offset で指定した場合は Number を 0 とし、Start から数える
+// cursor で指定した場合は CursorMode を true とし、Cursor の続きから数える（Cursor が空なら先頭）
+type Page struct {
+	Number     int
+	Size       int
+	Start      int
+	Cursor     string
+	CursorMode bool
+}
+
+func (p Page) Offset() int {
+	if p.ByOffset() {
+		return p.Start
+	}
+	if p.Number <= 1 {
+		return 0
+	}
+	return (p.Number - 1) * p.Size
+}
+
+// limit/offset で指定されたページか
+func (p Page) ByOffset() bool {
+	return p.Size > 0 && p.Number == 0 && !p.CursorMode
+}
+
+// 解釈済みの一覧クエリ
+type Query struct {
+	Filter filter.Expr
+	Sort   []SortField
+	Page   Page
+}
+
+// 一覧の1ページ分と絞り込み後の総件数
+// カーソルで指定した場合は総件数を数えず、次のページのカーソルを返す（最後のページでは空）
+type Result[T any] struct {
+	Items      []T
+	Total      int
+	NextCursor string
+}
+
+var filterKeyPattern = regexp.MustCompile(`^filter\[(\w+)\](?:\[(\w+)\])?$`)
+
+// クエリパラメータを解釈し、Spec にないフィールドはエラーにする
+func Parse(values url.Values, spec Spec) (Query, error) {
+	var q Query
+	var conditions []filter.Expr
+
+	if expr := strings.TrimSpace(values.Get("filter")); expr != "" {
+		parsed, err := filter.Parse(expr, spec.Filters)
+		if err != nil {
+			return Query{}, fmt.Errorf("invalid filter: %w", err)
+		}
+		conditions = append(conditions, parsed)
+	}
+
+	// 結果を安定させるためキー順に処理する
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, field := range spec.Shorthands {
+		if values.Get(field) == "" {
+			continue
+		}
+		comparison, err := filter.NewComparison(field, "eq", values.Get(field), spec.Filters)
+		if err != nil {
+			return Query{}, fmt.Errorf("invalid %s: %w", field, err)
+		}
+		conditions = append(conditions, comparison)
+	}
+
+	for _, bound := range spec.Bounds {
+		if values.Get(bound.Param) == "" {
+			continue
+		}
+		comparison, err := filter.NewComparison(bound.Field, bound.Op, values.Get(bound.Param), spec.Filters)
+		if err != nil {
+			return Query{}, fmt.Errorf("invalid %s: %w", bound.Param, err)
+		}
+		conditions = append(conditions, comparison)
+	}
+
+	for _, key := range keys {
+		m := filterKeyPattern.FindStringSubmatch(key)
+		if m == nil {
+			continue
+		}
+		op := m[2]
+		if op == "" {
+			op = "eq"
+		}
+		comparison, err := filter.NewComparison(m[1], op, values.Get(key), spec.Filters)
+		if err != nil {
+			return Query{}, fmt.Errorf("invalid %s: %w", key, err)
+		}
+		conditions = append(conditions, comparison)
+	}
+
+	for _, condition := range conditions {
+		if q.Filter == nil {
+			q.Filter = condition
+		} else {
+			q.Filter = &filter.Logical{Op: filter.OpAnd, Left: q.Filter, Right: condition}
+		}
+	}
+
+	sortFields, err := parseSort(values.Get("sort"), values.Get("order"), spec)
+	if err != nil {
+		return Query{}, err
+	}
+	q.Sort = sortFields
+
+	if values.Has("cursor") {
+		if !spec.Cursor {
+			return Query{}, fmt.Errorf("cursor is not supported for this list")
+		}
+		page, err := parseCursor(values)
+		if err != nil {
+			return Query{}, err
+		}
+		q.Page = page
+		return q, nil
+	}
+
+	page, err := parsePage(values)
+	if err != nil {
+		return Query{}, err
+	}
+	q.Page = page
+
+	return q, nil
+}
+
+// order は - を付けていないフィールドの向きで、sort を省略した場合は既定の並びの向きを置き換える
+func parseSort(raw, order string, spec Spec) ([]SortField, error) {
+	var desc bool
+	switch strings.ToLower(strings.TrimSpace(order)) {
+	case "", "asc":
+	case "desc":
+		desc = true
+	default:
+		return nil, fmt.Errorf("invalid order: must be asc or desc")
+	}
+
+	if strings.TrimSpace(raw) == "" {
+		if order == "" {
+			return spec.DefaultSort, nil
+		}
+		fields := make([]SortField, len(spec.DefaultSort))
+		for i, field := range spec.DefaultSort {
+			fields[i] = SortField{Field: field.Field, Desc: desc}
+		}
+		return fields, nil
+	}
+
+	var fields []SortField
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		field := SortField{Field: strings.TrimPrefix(part, "-"), Desc: desc || strings.HasPrefix(part, "-")}
+		if !contains(spec.Sorts, field.Field) {
+			return nil, fmt.Errorf("invalid sort: unknown field %q (allowed: %s)", field.Field, strings.Join(spec.Sorts, ", "))
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+func parsePage(values url.Values) (Page, error) {
+	rawNumber, rawSize := values.Get("page[number]"), values.Get("page[size]")
+	rawLimit, rawOffset := values.Get("limit"), values.Get("offset")
+	if rawLimit != "" || rawOffset != "" {
+		if rawNumber != "" || rawSize != "" {
+			return Page{}, fmt.Errorf("use either page[number]/page[size] or limit/offset, not both")
+		}
+		return parseLimitOffset(rawLimit, rawOffset)
+	}
+	if rawNumber == "" && rawSize == "" {
+		return Page{}, nil
+	}
+
+	page := Page{Number: 1, Size: DefaultPageSize}
+	if rawNumber != "" {
+		n, err := strconv.Atoi(rawNumber)
+		if err != nil || n < 1 {
+			return Page{}, fmt.Errorf("page[number] must be a positive integer")
+		}
+		page.Number = n
+	}
+	if rawSize != "" {
+		n, err := strconv.Atoi(rawSize)
+		if err != nil || n < 1 || n > MaxPageSize {
+			return Page{}, fmt.Errorf("page[size] must be between 1 and %d", MaxPageSize)
+		}
+		page.Size = n
+	}
+	return page, nil
+}
+
+func parseLimitOffset(rawLimit, rawOffset string) (Page, error) {
+	page := Page{Size: DefaultPageSize}
+	if rawLimit != "" {
+		n, err := strconv.Atoi(rawLimit)
+		if err != nil || n < 1 || n > MaxPageSize {
+			return Page{}, fmt.Errorf("limit must be between 1 and %d", MaxPageSize)
+		}
+		page.Size = n
+	}
+	if rawOffset != "" {
+		n, err := strconv.Atoi(rawOffset)
+		if err != nil || n < 0 {
+			return Page{}, fmt.Errorf("offset must be a non-negative integer")
+		}
+		page.Start = n
+	}
+	return page, nil
+}
+
+func parseCursor(values url.Values) (Page, error) {
+	for _, key := range []string{"sort", "order", "offset", "page[number]", "page[size]"} {
+		if values.Get(key) != "" {
+			return Page{}, fmt.Errorf("cursor cannot be combined with %s", key)
+		}
+	}
+
+	page := Page{Size: DefaultPageSize, Cursor: values.Get("cursor"), CursorMode: true}
+	if rawLimit := values.Get("limit"); rawLimit != "" {
+		n, err := strconv.Atoi(rawLimit)
+		if err != nil || n < 1 || n > MaxPageSize {
+			return Page{}, fmt.Errorf("limit must be between 1 and %d", MaxPageSize)
+		}
+		page.Size = n
+	}
+	return page, nil
+}
+
+func contains(values []string, target string) bool {
+	for _, v := range values {
+		if v == target {
+			return true
+		}
+	}
+	return false
+}
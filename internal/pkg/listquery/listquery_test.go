@@ -0,0 +1,296 @@
+package listquery
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"Aicon-assignment/internal/pkg/filter"
+)
+
+var testSpec = Spec{
+	Filters:    filter.Fields{"category": filter.String, "price": filter.Number, "date": filter.Date},
+	Shorthands: []string{"category", "price"},
+	Bounds: []Bound{
+		{Param: "min_price", Field: "price", Op: "gte"},
+		{Param: "date_to", Field: "date", Op: "lte"},
+	},
+	Sorts:       []string{"id", "price"},
+	DefaultSort: []SortField{{Field: "id"}},
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name        string
+		query       string
+		expected    Query
+		expectedErr string
+	}{
+		{
+			name:     "正常系: 指定なしは既定の並び順",
+			query:    "",
+			expected: Query{Sort: []SortField{{Field: "id"}}},
+		},
+		{
+			name:  "正常系: filter[field] と演算子付きの条件をANDで連結",
+			query: "filter[category]=時計&filter[price][gte]=1000&sort=-price,id&page[number]=2&page[size]=10",
+			expected: Query{
+				Filter: &filter.Logical{
+					Op:    filter.OpAnd,
+					Left:  &filter.Comparison{Field: "category", Op: filter.OpEq, Value: "時計"},
+					Right: &filter.Comparison{Field: "price", Op: filter.OpGte, Value: int64(1000)},
+				},
+				Sort: []SortField{{Field: "price", Desc: true}, {Field: "id"}},
+				Page: Page{Number: 2, Size: 10},
+			},
+		},
+		{
+			name:  "正常系: 省略形の等価条件を他の条件とANDで連結",
+			query: "category=時計&price=1000&filter[price][lte]=5000",
+			expected: Query{
+				Filter: &filter.Logical{
+					Op: filter.OpAnd,
+					Left: &filter.Logical{
+						Op:    filter.OpAnd,
+						Left:  &filter.Comparison{Field: "category", Op: filter.OpEq, Value: "時計"},
+						Right: &filter.Comparison{Field: "price", Op: filter.OpEq, Value: int64(1000)},
+					},
+					Right: &filter.Comparison{Field: "price", Op: filter.OpLte, Value: int64(5000)},
+				},
+				Sort: []SortField{{Field: "id"}},
+			},
+		},
+		{
+			name:  "正常系: 範囲の条件を他の条件とANDで連結",
+			query: "min_price=1000&date_to=2023-12-31&category=時計",
+			expected: Query{
+				Filter: &filter.Logical{
+					Op: filter.OpAnd,
+					Left: &filter.Logical{
+						Op:    filter.OpAnd,
+						Left:  &filter.Comparison{Field: "category", Op: filter.OpEq, Value: "時計"},
+						Right: &filter.Comparison{Field: "price", Op: filter.OpGte, Value: int64(1000)},
+					},
+					Right: &filter.Comparison{Field: "date", Op: filter.OpLte, Value: "2023-12-31"},
+				},
+				Sort: []SortField{{Field: "id"}},
+			},
+		},
+		{
+			name:  "正常系: order は - のないフィールドの向き",
+			query: "sort=price,-id&order=desc",
+			expected: Query{
+				Sort: []SortField{{Field: "price", Desc: true}, {Field: "id", Desc: true}},
+			},
+		},
+		{
+			name:  "正常系: sort なしの order は既定の並びの向きを置き換える",
+			query: "order=DESC",
+			expected: Query{
+				Sort: []SortField{{Field: "id", Desc: true}},
+			},
+		},
+		{
+			name:     "正常系: 空の省略形は絞り込まない",
+			query:    "category=",
+			expected: Query{Sort: []SortField{{Field: "id"}}},
+		},
+		{
+			name:  "正常系: page[number]のみは既定のページサイズ",
+			query: "page[number]=3",
+			expected: Query{
+				Sort: []SortField{{Field: "id"}},
+				Page: Page{Number: 3, Size: DefaultPageSize},
+			},
+		},
+		{
+			name:  "正常系: limit/offset",
+			query: "limit=5&offset=7",
+			expected: Query{
+				Sort: []SortField{{Field: "id"}},
+				Page: Page{Size: 5, Start: 7},
+			},
+		},
+		{
+			name:  "正常系: offset のみは既定のページサイズ",
+			query: "offset=40",
+			expected: Query{
+				Sort: []SortField{{Field: "id"}},
+				Page: Page{Size: DefaultPageSize, Start: 40},
+			},
+		},
+		{
+			name:        "異常系: 許可されていない絞り込み",
+			query:       "filter[owner]=me",
+			expectedErr: "unknown field: owner",
+		},
+		{
+			name:        "異常系: 許可されていない並び替え",
+			query:       "sort=category",
+			expectedErr: `unknown field "category"`,
+		},
+		{
+			name:        "異常系: order の値が不正",
+			query:       "sort=price&order=up",
+			expectedErr: "invalid order: must be asc or desc",
+		},
+		{
+			name:        "異常系: 省略形の値が不正",
+			query:       "price=abc",
+			expectedErr: "invalid price:",
+		},
+		{
+			name:        "異常系: 範囲の日付が YYYY-MM-DD 形式でない",
+			query:       "date_to=2023/12/31",
+			expectedErr: "invalid date_to: date must be in YYYY-MM-DD format",
+		},
+		{
+			name:        "異常系: 範囲の金額が数値でない",
+			query:       "min_price=1e6",
+			expectedErr: "invalid min_price:",
+		},
+		{
+			name:        "異常系: ページサイズが上限を超える",
+			query:       "page[size]=1000",
+			expectedErr: "page[size] must be between 1 and 100",
+		},
+		{
+			name:        "異常系: offset が負",
+			query:       "offset=-1",
+			expectedErr: "offset must be a non-negative integer",
+		},
+		{
+			name:        "異常系: page と limit/offset の併用",
+			query:       "page[number]=2&limit=10",
+			expectedErr: "use either page[number]/page[size] or limit/offset",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := url.ParseQuery(tt.query)
+			require.NoError(t, err)
+
+			q, err := Parse(values, testSpec)
+
+			if tt.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, q)
+		})
+	}
+}
+
+func TestParse_Cursor(t *testing.T) {
+	cursorSpec := testSpec
+	cursorSpec.Cursor = true
+
+	tests := []struct {
+		name        string
+		spec        Spec
+		query       string
+		expected    Page
+		expectedErr string
+	}{
+		{
+			name:     "正常系: 空のカーソルは先頭から",
+			spec:     cursorSpec,
+			query:    "cursor=",
+			expected: Page{Size: DefaultPageSize, CursorMode: true},
+		},
+		{
+			name:     "正常系: カーソルと limit",
+			spec:     cursorSpec,
+			query:    "cursor=abc&limit=50",
+			expected: Page{Size: 50, Cursor: "abc", CursorMode: true},
+		},
+		{
+			name:        "異常系: カーソルに対応していない一覧",
+			spec:        testSpec,
+			query:       "cursor=abc",
+			expectedErr: "cursor is not supported for this list",
+		},
+		{
+			name:        "異常系: カーソルと sort の併用",
+			spec:        cursorSpec,
+			query:       "cursor=abc&sort=price",
+			expectedErr: "cursor cannot be combined with sort",
+		},
+		{
+			name:        "異常系: カーソルと offset の併用",
+			spec:        cursorSpec,
+			query:       "cursor=abc&offset=10",
+			expectedErr: "cursor cannot be combined with offset",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := url.ParseQuery(tt.query)
+			require.NoError(t, err)
+
+			q, err := Parse(values, tt.spec)
+
+			if tt.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, q.Page)
+			assert.False(t, q.Page.ByOffset())
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	type row struct {
+		id       int64
+		category string
+		price    int64
+	}
+	rows := []row{
+		{1, "時計", 300},
+		{2, "バッグ", 100},
+		{3, "時計", 200},
+		{4, "時計", 100},
+	}
+	value := func(r row, field string) any {
+		switch field {
+		case "id":
+			return r.id
+		case "category":
+			return r.category
+		}
+		return r.price
+	}
+
+	values, _ := url.ParseQuery("filter[category]=時計&sort=price&page[number]=1&page[size]=2")
+	q, err := Parse(values, testSpec)
+	require.NoError(t, err)
+
+	result := Apply(rows, q, value)
+
+	assert.Equal(t, 3, result.Total)
+	require.Len(t, result.Items, 2)
+	assert.Equal(t, int64(4), result.Items[0].id)
+	assert.Equal(t, int64(3), result.Items[1].id)
+
+	values, _ = url.ParseQuery("filter[category]=時計&sort=price&limit=2&offset=1")
+	q, err = Parse(values, testSpec)
+	require.NoError(t, err)
+
+	result = Apply(rows, q, value)
+
+	assert.Equal(t, 3, result.Total)
+	require.Len(t, result.Items, 2)
+	assert.Equal(t, int64(3), result.Items[0].id)
+	assert.Equal(t, int64(1), result.Items[1].id)
+}
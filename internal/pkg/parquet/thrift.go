@@ -0,0 +1,125 @@
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// Parquet の定数（parquet.thrift）
+const (
+	physicalInt32     = 1
+	physicalInt64     = 2
+	physicalByteArray = 6
+
+	repetitionRequired = 0
+	repetitionOptional = 1
+
+	convertedUTF8            = 0
+	convertedDate            = 6
+	convertedTimestampMillis = 9
+
+	pageTypeDataPage  = 0
+	encodingPlain     = 0
+	encodingRLE       = 3
+	codecUncompressed = 0
+)
+
+// Thrift の compact protocol の型
+const (
+	typeI32    = 5
+	typeI64    = 6
+	typeBinary = 8
+	typeList   = 9
+	typeStruct = 12
+)
+
+// Parquet のヘッダー・フッターに必要な分だけの Thrift compact protocol のエンコーダー
+type compactWriter struct {
+	buf     bytes.Buffer
+	lastIDs []int16 // 入れ子の構造体ごとの直前のフィールド ID
+	lastID  int16
+}
+
+func (w *compactWriter) field(id int16, fieldType byte) {
+	if delta := id - w.lastID; delta > 0 && delta <= 15 {
+		w.buf.WriteByte(byte(delta)<<4 | fieldType)
+	} else {
+		w.buf.WriteByte(fieldType)
+		w.varint(int64(id))
+	}
+	w.lastID = id
+}
+
+func (w *compactWriter) varint(v int64) {
+	w.buf.Write(binary.AppendUvarint(nil, uint64((v<<1)^(v>>63))))
+}
+
+func (w *compactWriter) i32(id int16, v int32) {
+	w.field(id, typeI32)
+	w.varint(int64(v))
+}
+
+func (w *compactWriter) i64(id int16, v int64) {
+	w.field(id, typeI64)
+	w.varint(v)
+}
+
+func (w *compactWriter) binary(id int16, s string) {
+	w.field(id, typeBinary)
+	w.str(s)
+}
+
+func (w *compactWriter) str(s string) {
+	w.buf.Write(binary.AppendUvarint(nil, uint64(len(s))))
+	w.buf.WriteString(s)
+}
+
+func (w *compactWriter) beginList(id int16, elemType byte, size int) {
+	w.field(id, typeList)
+	if size < 15 {
+		w.buf.WriteByte(byte(size)<<4 | elemType)
+	} else {
+		w.buf.WriteByte(0xF0 | elemType)
+		w.buf.Write(binary.AppendUvarint(nil, uint64(size)))
+	}
+}
+
+func (w *compactWriter) i32List(id int16, values []int32) {
+	w.beginList(id, typeI32, len(values))
+	for _, v := range values {
+		w.varint(int64(v))
+	}
+}
+
+func (w *compactWriter) stringList(id int16, values []string) {
+	w.beginList(id, typeBinary, len(values))
+	for _, v := range values {
+		w.str(v)
+	}
+}
+
+// フィールドとしての構造体
+func (w *compactWriter) beginStruct(id int16) {
+	w.field(id, typeStruct)
+	w.beginElement()
+}
+
+func (w *compactWriter) endStruct() {
+	w.endElement()
+}
+
+// リストの要素としての構造体
+func (w *compactWriter) beginElement() {
+	w.lastIDs = append(w.lastIDs, w.lastID)
+	w.lastID = 0
+}
+
+func (w *compactWriter) endElement() {
+	w.stop()
+	w.lastID = w.lastIDs[len(w.lastIDs)-1]
+	w.lastIDs = w.lastIDs[:len(w.lastIDs)-1]
+}
+
+func (w *compactWriter) stop() {
+	w.buf.WriteByte(0)
+}
@@ -0,0 +1,306 @@
+// Package parquet は DuckDB や Athena から直接読める Parquet ファイルを書き出す。
+// 対応するのは平坦なスキーマ・PLAIN エンコーディング・非圧縮だけで、読み込みには対応しない。
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+)
+
+var magic = []byte("PAR1")
+
+// 1 つの行グループに入れる行数
+const rowGroupSize = 10000
+
+type ColumnType int
+
+const (
+	Int64     ColumnType = iota // int64
+	String                      // string（UTF8）
+	Date                        // time.Time（日付のみ）
+	Timestamp                   // time.Time（UTC のミリ秒）
+)
+
+type Column struct {
+	Name     string
+	Type     ColumnType
+	Optional bool // true の場合 nil を書ける
+}
+
+// 行を受け取り、行グループごとに列単位で書き出す
+type Writer struct {
+	out     io.Writer
+	columns []Column
+	values  [][]any
+	rows    int
+
+	offset    int64
+	rowGroups []rowGroup
+	closed    bool
+}
+
+type rowGroup struct {
+	rows    int
+	chunks  []columnChunk
+	byteLen int64
+}
+
+type columnChunk struct {
+	offset    int64
+	size      int64
+	numValues int
+}
+
+func NewWriter(out io.Writer, columns []Column) (*Writer, error) {
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("parquet: at least one column is required")
+	}
+	if _, err := out.Write(magic); err != nil {
+		return nil, err
+	}
+	return &Writer{
+		out:     out,
+		columns: columns,
+		values:  make([][]any, len(columns)),
+		offset:  int64(len(magic)),
+	}, nil
+}
+
+// 1 行を書く。値の順序と型は列の定義に合わせる
+func (w *Writer) Write(row ...any) error {
+	if w.closed {
+		return fmt.Errorf("parquet: writer is closed")
+	}
+	if len(row) != len(w.columns) {
+		return fmt.Errorf("parquet: expected %d values, got %d", len(w.columns), len(row))
+	}
+	for i, value := range row {
+		if err := w.columns[i].check(value); err != nil {
+			return err
+		}
+	}
+	for i, value := range row {
+		w.values[i] = append(w.values[i], value)
+	}
+	w.rows++
+
+	if w.rows >= rowGroupSize {
+		return w.flush()
+	}
+	return nil
+}
+
+// 残りの行とフッターを書き出す。out は閉じない
+func (w *Writer) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	if err := w.flush(); err != nil {
+		return err
+	}
+
+	footer := w.fileMetaData()
+	if _, err := w.out.Write(footer); err != nil {
+		return err
+	}
+	if err := binary.Write(w.out, binary.LittleEndian, uint32(len(footer))); err != nil {
+		return err
+	}
+	_, err := w.out.Write(magic)
+	return err
+}
+
+func (c Column) check(value any) error {
+	if value == nil {
+		if !c.Optional {
+			return fmt.Errorf("parquet: column %s is required", c.Name)
+		}
+		return nil
+	}
+
+	var ok bool
+	switch c.Type {
+	case Int64:
+		_, ok = value.(int64)
+	case String:
+		_, ok = value.(string)
+	case Date, Timestamp:
+		_, ok = value.(time.Time)
+	}
+	if !ok {
+		return fmt.Errorf("parquet: column %s: unexpected value type %T", c.Name, value)
+	}
+	return nil
+}
+
+func (w *Writer) flush() error {
+	if w.rows == 0 {
+		return nil
+	}
+
+	group := rowGroup{rows: w.rows}
+	for i, column := range w.columns {
+		page := column.page(w.values[i])
+		if _, err := w.out.Write(page); err != nil {
+			return err
+		}
+		group.chunks = append(group.chunks, columnChunk{offset: w.offset, size: int64(len(page)), numValues: w.rows})
+		group.byteLen += int64(len(page))
+		w.offset += int64(len(page))
+		w.values[i] = w.values[i][:0]
+	}
+	w.rowGroups = append(w.rowGroups, group)
+	w.rows = 0
+	return nil
+}
+
+// 列の値を 1 つのデータページ（ヘッダー付き）にする
+func (c Column) page(values []any) []byte {
+	var data bytes.Buffer
+	if c.Optional {
+		levels := definitionLevels(values)
+		binary.Write(&data, binary.LittleEndian, uint32(len(levels)))
+		data.Write(levels)
+	}
+	for _, value := range values {
+		if value == nil {
+			continue
+		}
+		switch c.Type {
+		case Int64:
+			binary.Write(&data, binary.LittleEndian, value.(int64))
+		case String:
+			s := value.(string)
+			binary.Write(&data, binary.LittleEndian, uint32(len(s)))
+			data.WriteString(s)
+		case Date:
+			t := value.(time.Time)
+			days := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC).Unix() / 86400
+			binary.Write(&data, binary.LittleEndian, int32(days))
+		case Timestamp:
+			binary.Write(&data, binary.LittleEndian, value.(time.Time).UnixMilli())
+		}
+	}
+
+	var header compactWriter
+	header.i32(1, pageTypeDataPage)
+	header.i32(2, int32(data.Len()))
+	header.i32(3, int32(data.Len()))
+	header.beginStruct(5)
+	header.i32(1, int32(len(values)))
+	header.i32(2, encodingPlain)
+	header.i32(3, encodingRLE)
+	header.i32(4, encodingRLE)
+	header.endStruct()
+	header.stop()
+
+	return append(header.buf.Bytes(), data.Bytes()...)
+}
+
+// 定義レベル（値があれば 1、nil なら 0）を RLE のランで表す
+func definitionLevels(values []any) []byte {
+	var buf bytes.Buffer
+	for i := 0; i < len(values); {
+		present := values[i] != nil
+		run := 1
+		for i+run < len(values) && (values[i+run] != nil) == present {
+			run++
+		}
+		buf.Write(binary.AppendUvarint(nil, uint64(run)<<1))
+		if present {
+			buf.WriteByte(1)
+		} else {
+			buf.WriteByte(0)
+		}
+		i += run
+	}
+	return buf.Bytes()
+}
+
+func (w *Writer) fileMetaData() []byte {
+	var m compactWriter
+	m.i32(1, 1)
+
+	m.beginList(2, typeStruct, len(w.columns)+1)
+	m.beginElement()
+	m.binary(4, "schema")
+	m.i32(5, int32(len(w.columns)))
+	m.endElement()
+	for _, column := range w.columns {
+		m.beginElement()
+		m.i32(1, column.physicalType())
+		if column.Optional {
+			m.i32(3, repetitionOptional)
+		} else {
+			m.i32(3, repetitionRequired)
+		}
+		m.binary(4, column.Name)
+		if converted, ok := column.convertedType(); ok {
+			m.i32(6, converted)
+		}
+		m.endElement()
+	}
+
+	var total int64
+	for _, group := range w.rowGroups {
+		total += int64(group.rows)
+	}
+	m.i64(3, total)
+
+	m.beginList(4, typeStruct, len(w.rowGroups))
+	for _, group := range w.rowGroups {
+		m.beginElement()
+		m.beginList(1, typeStruct, len(group.chunks))
+		for i, chunk := range group.chunks {
+			column := w.columns[i]
+			m.beginElement()
+			m.i64(2, chunk.offset)
+			m.beginStruct(3)
+			m.i32(1, column.physicalType())
+			m.i32List(2, []int32{encodingPlain, encodingRLE})
+			m.stringList(3, []string{column.Name})
+			m.i32(4, codecUncompressed)
+			m.i64(5, int64(chunk.numValues))
+			m.i64(6, chunk.size)
+			m.i64(7, chunk.size)
+			m.i64(9, chunk.offset)
+			m.endStruct()
+			m.endElement()
+		}
+		m.i64(2, group.byteLen)
+		m.i64(3, int64(group.rows))
+		m.endElement()
+	}
+
+	m.binary(6, "Aicon-assignment")
+	m.stop()
+	return m.buf.Bytes()
+}
+
+func (c Column) physicalType() int32 {
+	switch c.Type {
+	case String:
+		return physicalByteArray
+	case Date:
+		return physicalInt32
+	default:
+		return physicalInt64
+	}
+}
+
+func (c Column) convertedType() (int32, bool) {
+	switch c.Type {
+	case String:
+		return convertedUTF8, true
+	case Date:
+		return convertedDate, true
+	case Timestamp:
+		return convertedTimestampMillis, true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,123 @@
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// テスト用の Thrift compact protocol のデコーダー。構造体はフィールド ID をキーにした map になる
+type compactReader struct {
+	buf *bytes.Reader
+}
+
+func (r *compactReader) uvarint() uint64 {
+	v, _ := binary.ReadUvarint(r.buf)
+	return v
+}
+
+func (r *compactReader) varint() int64 {
+	v := r.uvarint()
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+func (r *compactReader) value(fieldType byte) any {
+	switch fieldType {
+	case typeI32, typeI64:
+		return r.varint()
+	case typeBinary:
+		b := make([]byte, r.uvarint())
+		r.buf.Read(b)
+		return string(b)
+	case typeList:
+		header, _ := r.buf.ReadByte()
+		size := int(header >> 4)
+		if size == 15 {
+			size = int(r.uvarint())
+		}
+		list := make([]any, size)
+		for i := range list {
+			list[i] = r.value(header & 0x0F)
+		}
+		return list
+	case typeStruct:
+		return r.structure()
+	}
+	panic("unexpected thrift type")
+}
+
+func (r *compactReader) structure() map[int16]any {
+	fields := map[int16]any{}
+	var id int16
+	for {
+		header, _ := r.buf.ReadByte()
+		if header == 0 {
+			return fields
+		}
+		if delta := int16(header >> 4); delta != 0 {
+			id += delta
+		} else {
+			id = int16(r.varint())
+		}
+		fields[id] = r.value(header & 0x0F)
+	}
+}
+
+func TestWriter(t *testing.T) {
+	columns := []Column{
+		{Name: "id", Type: Int64},
+		{Name: "name", Type: String, Optional: true},
+		{Name: "purchase_date", Type: Date},
+		{Name: "updated_at", Type: Timestamp},
+	}
+	updatedAt := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("正常系: フッターにスキーマと行数を書き、列ごとにページを書く", func(t *testing.T) {
+		var out bytes.Buffer
+		w, err := NewWriter(&out, columns)
+		require.NoError(t, err)
+		require.NoError(t, w.Write(int64(1), "デイトナ", time.Date(1970, 1, 3, 0, 0, 0, 0, time.UTC), updatedAt))
+		require.NoError(t, w.Write(int64(2), nil, time.Date(1970, 1, 3, 0, 0, 0, 0, time.UTC), updatedAt))
+		require.NoError(t, w.Close())
+
+		file := out.Bytes()
+		assert.Equal(t, magic, file[:4])
+		assert.Equal(t, magic, file[len(file)-4:])
+		footerLen := int(binary.LittleEndian.Uint32(file[len(file)-8:]))
+		footer := (&compactReader{buf: bytes.NewReader(file[len(file)-8-footerLen : len(file)-8])}).structure()
+
+		assert.Equal(t, int64(2), footer[3])
+		schema := footer[2].([]any)
+		require.Len(t, schema, 5)
+		assert.Equal(t, int64(4), schema[0].(map[int16]any)[5])
+		assert.Equal(t, "name", schema[2].(map[int16]any)[4])
+		assert.Equal(t, int64(repetitionOptional), schema[2].(map[int16]any)[3])
+
+		// name 列のページ: 定義レベル（1, 0）と値 1 つ
+		chunks := footer[4].([]any)[0].(map[int16]any)[1].([]any)
+		offset := chunks[1].(map[int16]any)[2].(int64)
+		page := &compactReader{buf: bytes.NewReader(file[offset:])}
+		header := page.structure()
+		assert.Equal(t, int64(2), header[5].(map[int16]any)[1])
+		data := make([]byte, header[2].(int64))
+		page.buf.Read(data)
+		assert.Equal(t, []byte{4, 0, 0, 0, 1 << 1, 1, 1 << 1, 0, 12, 0, 0, 0}, data[:12])
+		assert.Equal(t, "デイトナ", string(data[12:]))
+	})
+
+	t.Run("異常系: 必須の列に nil", func(t *testing.T) {
+		w, err := NewWriter(&bytes.Buffer{}, columns)
+		require.NoError(t, err)
+		assert.Error(t, w.Write(nil, "デイトナ", updatedAt, updatedAt))
+	})
+
+	t.Run("異常系: 列の型と値の型が合わない", func(t *testing.T) {
+		w, err := NewWriter(&bytes.Buffer{}, columns)
+		require.NoError(t, err)
+		assert.Error(t, w.Write(int64(1), "デイトナ", "2024-06-01", updatedAt))
+	})
+}
@@ -0,0 +1,161 @@
+// Package reqctx はリクエストスコープの値（ロガー、リクエストID、ユーザーIDとロール、システムの処理か、組織ID、なりすまし元、strict モード、サンドボックス）を
+// context.Context に格納・取得するための型付きアクセサを提供する。
+package reqctx
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+)
+
+// 他パッケージのキーと衝突しないよう非公開の型を使う
+type contextKey int
+
+const (
+	loggerKey contextKey = iota
+	requestIDKey
+	userIDKey
+	userRoleKey
+	allOwnersKey
+	systemKey
+	orgIDKey
+	impersonatorIDKey
+	dbDropRateKey
+	strictKey
+	sandboxKey
+)
+
+var (
+	ErrNoUserID = errors.New("user id is not set in context")
+	ErrNoOrgID  = errors.New("organization id is not set in context")
+)
+
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey, logger)
+}
+
+// コンテキストのロガーを返す。未設定の場合は slog.Default() を返す
+func Logger(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerKey).(*slog.Logger); ok && logger != nil {
+		return logger
+	}
+	return slog.Default()
+}
+
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey).(string)
+	return requestID
+}
+
+func WithUserID(ctx context.Context, userID int64) context.Context {
+	return context.WithValue(ctx, userIDKey, userID)
+}
+
+func UserID(ctx context.Context) (int64, bool) {
+	userID, ok := ctx.Value(userIDKey).(int64)
+	return userID, ok
+}
+
+// 呼び出し元のユーザーのロール（entity.UserRoleAdmin など）を格納する
+func WithUserRole(ctx context.Context, role string) context.Context {
+	return context.WithValue(ctx, userRoleKey, role)
+}
+
+func UserRole(ctx context.Context) string {
+	role, _ := ctx.Value(userRoleKey).(string)
+	return role
+}
+
+// 管理者の運用操作（組織のデータの移行など）で、呼び出し元のユーザーに関係なくすべてのアイテムを対象にする
+func WithAllOwners(ctx context.Context) context.Context {
+	return context.WithValue(ctx, allOwnersKey, true)
+}
+
+func AllOwners(ctx context.Context) bool {
+	all, _ := ctx.Value(allOwnersKey).(bool)
+	return all
+}
+
+// 利用者のリクエストではなく、システム自身の処理（定期実行のジョブや運用向けのサブコマンド）であることを示す
+// 管理者だけに許可する操作もユーザーなしで行える
+func WithSystem(ctx context.Context) context.Context {
+	return context.WithValue(ctx, systemKey, true)
+}
+
+func System(ctx context.Context) bool {
+	system, _ := ctx.Value(systemKey).(bool)
+	return system
+}
+
+// ユーザーIDが必須の処理で使う
+func RequireUserID(ctx context.Context) (int64, error) {
+	userID, ok := UserID(ctx)
+	if !ok {
+		return 0, ErrNoUserID
+	}
+	return userID, nil
+}
+
+// 管理者がなりすましている場合に、なりすましている管理者のユーザーIDを格納する
+// UserID にはなりすまされているユーザーのIDを格納する
+func WithImpersonatorID(ctx context.Context, adminID int64) context.Context {
+	return context.WithValue(ctx, impersonatorIDKey, adminID)
+}
+
+func ImpersonatorID(ctx context.Context) (int64, bool) {
+	adminID, ok := ctx.Value(impersonatorIDKey).(int64)
+	return adminID, ok
+}
+
+func WithOrgID(ctx context.Context, orgID int64) context.Context {
+	return context.WithValue(ctx, orgIDKey, orgID)
+}
+
+func OrgID(ctx context.Context) (int64, bool) {
+	orgID, ok := ctx.Value(orgIDKey).(int64)
+	return orgID, ok
+}
+
+// テナントでスコープするクエリはこれを通して組織IDを取得する
+// 未設定のままクエリが発行されることを防ぐため、取得できない場合はエラーを返す
+func RequireOrgID(ctx context.Context) (int64, error) {
+	orgID, ok := OrgID(ctx)
+	if !ok {
+		return 0, ErrNoOrgID
+	}
+	return orgID, nil
+}
+
+// 警告をエラーにする strict モードのリクエストかを格納する
+func WithStrict(ctx context.Context, strict bool) context.Context {
+	return context.WithValue(ctx, strictKey, strict)
+}
+
+func Strict(ctx context.Context) bool {
+	strict, _ := ctx.Value(strictKey).(bool)
+	return strict
+}
+
+// サンドボックスの API キーで呼び出したリクエストに、キーごとのデータセットの ID を格納する
+func WithSandbox(ctx context.Context, sandboxID string) context.Context {
+	return context.WithValue(ctx, sandboxKey, sandboxID)
+}
+
+func Sandbox(ctx context.Context) (string, bool) {
+	sandboxID, ok := ctx.Value(sandboxKey).(string)
+	return sandboxID, ok
+}
+
+// 障害注入のため、このリクエストで発行するクエリを接続断にする確率を格納する
+func WithDBDropRate(ctx context.Context, rate float64) context.Context {
+	return context.WithValue(ctx, dbDropRateKey, rate)
+}
+
+func DBDropRate(ctx context.Context) float64 {
+	rate, _ := ctx.Value(dbDropRateKey).(float64)
+	return rate
+}
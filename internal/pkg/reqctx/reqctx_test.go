@@ -0,0 +1,49 @@
+package reqctx
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccessors(t *testing.T) {
+	ctx := context.Background()
+
+	// 未設定の場合
+	assert.Equal(t, "", RequestID(ctx))
+	assert.Equal(t, slog.Default(), Logger(ctx))
+	_, ok := UserID(ctx)
+	assert.False(t, ok)
+	_, ok = ImpersonatorID(ctx)
+	assert.False(t, ok)
+	_, err := RequireOrgID(ctx)
+	assert.ErrorIs(t, err, ErrNoOrgID)
+	_, ok = Sandbox(ctx)
+	assert.False(t, ok)
+
+	// 設定済みの場合
+	logger := slog.New(slog.NewTextHandler(nil, nil))
+	ctx = WithLogger(ctx, logger)
+	ctx = WithRequestID(ctx, "req-1")
+	ctx = WithUserID(ctx, 10)
+	ctx = WithOrgID(ctx, 20)
+	ctx = WithImpersonatorID(ctx, 30)
+	ctx = WithSandbox(ctx, "sandbox-1")
+
+	assert.Equal(t, logger, Logger(ctx))
+	assert.Equal(t, "req-1", RequestID(ctx))
+	userID, err := RequireUserID(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10), userID)
+	orgID, err := RequireOrgID(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(20), orgID)
+	adminID, ok := ImpersonatorID(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, int64(30), adminID)
+	sandboxID, ok := Sandbox(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, "sandbox-1", sandboxID)
+}
@@ -0,0 +1,124 @@
+// Package thumbnail は画像を縮小したサムネイルを作る。
+// 標準ライブラリで読める JPEG・PNG・GIF を扱い、縮小は範囲内の画素の平均（面積平均）で行う。
+package thumbnail
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+)
+
+// 展開する画素数の上限。小さなファイルで巨大な画像を展開させる攻撃を防ぐ
+const MaxPixels = 50_000_000
+
+// JPEG で書き出すときの品質
+const jpegQuality = 85
+
+var (
+	ErrUnsupported = errors.New("thumbnail: unsupported image type")
+	ErrTooLarge    = errors.New("thumbnail: image is too large")
+)
+
+// Supported は contentType の画像からサムネイルを作れるかを返す
+func Supported(contentType string) bool {
+	switch contentType {
+	case "image/jpeg", "image/png", "image/gif":
+		return true
+	}
+	return false
+}
+
+// ContentType は contentType の画像から作るサムネイルの形式を返す
+// JPEG はそのまま JPEG に、透過を含みうる PNG・GIF は PNG にする
+func ContentType(contentType string) string {
+	if contentType == "image/jpeg" {
+		return "image/jpeg"
+	}
+	return "image/png"
+}
+
+// Decode は画像を読み込む。画素数が MaxPixels を超える場合は展開せずに ErrTooLarge を返す
+func Decode(data []byte, contentType string) (image.Image, error) {
+	var decodeConfig func(io.Reader) (image.Config, error)
+	var decode func(io.Reader) (image.Image, error)
+	switch contentType {
+	case "image/jpeg":
+		decodeConfig, decode = jpeg.DecodeConfig, jpeg.Decode
+	case "image/png":
+		decodeConfig, decode = png.DecodeConfig, png.Decode
+	case "image/gif":
+		decodeConfig, decode = gif.DecodeConfig, gif.Decode
+	default:
+		return nil, ErrUnsupported
+	}
+
+	config, err := decodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("thumbnail: failed to read image: %w", err)
+	}
+	if int64(config.Width)*int64(config.Height) > MaxPixels {
+		return nil, fmt.Errorf("%w: %dx%d", ErrTooLarge, config.Width, config.Height)
+	}
+	img, err := decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("thumbnail: failed to decode image: %w", err)
+	}
+	return img, nil
+}
+
+// Resize は長辺が maxEdge に収まるよう縦横比を保って縮小する。収まっている画像は拡大しない
+func Resize(src image.Image, maxEdge int) image.Image {
+	bounds := src.Bounds()
+	sw, sh := bounds.Dx(), bounds.Dy()
+	dw, dh := sw, sh
+	if sw >= sh && sw > maxEdge {
+		dw, dh = maxEdge, max(1, sh*maxEdge/sw)
+	} else if sh > sw && sh > maxEdge {
+		dw, dh = max(1, sw*maxEdge/sh), maxEdge
+	}
+
+	// 透過の平均が正しくなるよう、乗算済みの RGBA にそろえてから平均する
+	rgba := image.NewRGBA(image.Rect(0, 0, sw, sh))
+	draw.Draw(rgba, rgba.Bounds(), src, bounds.Min, draw.Src)
+	if dw == sw && dh == sh {
+		return rgba
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		y0, y1 := y*sh/dh, max((y+1)*sh/dh, y*sh/dh+1)
+		for x := 0; x < dw; x++ {
+			x0, x1 := x*sw/dw, max((x+1)*sw/dw, x*sw/dw+1)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				row := rgba.Pix[sy*rgba.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					p := row[sx*4 : sx*4+4]
+					r, g, b, a = r+uint64(p[0]), g+uint64(p[1]), b+uint64(p[2]), a+uint64(p[3])
+					n++
+				}
+			}
+			i := dst.PixOffset(x, y)
+			dst.Pix[i], dst.Pix[i+1], dst.Pix[i+2], dst.Pix[i+3] = uint8(r/n), uint8(g/n), uint8(b/n), uint8(a/n)
+		}
+	}
+	return dst
+}
+
+// Encode はサムネイルを contentType（ContentType の戻り値）の形式で書き出す
+func Encode(w io.Writer, img image.Image, contentType string) error {
+	switch contentType {
+	case "image/jpeg":
+		return jpeg.Encode(w, img, &jpeg.Options{Quality: jpegQuality})
+	case "image/png":
+		return png.Encode(w, img)
+	}
+	return ErrUnsupported
+}
@@ -0,0 +1,69 @@
+package thumbnail
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func TestResize(t *testing.T) {
+	t.Run("正常系: 縦横比を保って長辺を合わせる", func(t *testing.T) {
+		assert.Equal(t, image.Rect(0, 0, 200, 100), Resize(image.NewRGBA(image.Rect(0, 0, 800, 400)), 200).Bounds())
+		assert.Equal(t, image.Rect(0, 0, 50, 200), Resize(image.NewRGBA(image.Rect(0, 0, 300, 1200)), 200).Bounds())
+	})
+
+	t.Run("正常系: 収まっている画像は拡大しない", func(t *testing.T) {
+		assert.Equal(t, image.Rect(0, 0, 120, 80), Resize(image.NewRGBA(image.Rect(0, 0, 120, 80)), 200).Bounds())
+	})
+
+	t.Run("正常系: 範囲内の画素を平均する", func(t *testing.T) {
+		src := image.NewRGBA(image.Rect(0, 0, 2, 1))
+		src.Set(0, 0, color.RGBA{R: 255, A: 255})
+		src.Set(1, 0, color.RGBA{B: 255, A: 255})
+
+		assert.Equal(t, color.RGBA{R: 127, B: 127, A: 255}, Resize(src, 1).At(0, 0))
+	})
+}
+
+func TestDecode(t *testing.T) {
+	t.Run("正常系: PNG を読み込む", func(t *testing.T) {
+		img, err := Decode(encodePNG(t, image.NewRGBA(image.Rect(0, 0, 4, 3))), "image/png")
+		require.NoError(t, err)
+		assert.Equal(t, image.Rect(0, 0, 4, 3), img.Bounds())
+	})
+
+	t.Run("異常系: 画素数が上限を超える画像は展開しない", func(t *testing.T) {
+		_, err := Decode(encodePNG(t, image.NewGray(image.Rect(0, 0, 10000, 5001))), "image/png")
+		assert.ErrorIs(t, err, ErrTooLarge)
+	})
+
+	t.Run("異常系: 読めない形式", func(t *testing.T) {
+		_, err := Decode([]byte("RIFF"), "image/webp")
+		assert.ErrorIs(t, err, ErrUnsupported)
+		assert.False(t, Supported("image/webp"))
+	})
+}
+
+func TestEncode(t *testing.T) {
+	t.Run("正常系: JPEG はそのまま JPEG、それ以外は PNG で書き出す", func(t *testing.T) {
+		assert.Equal(t, "image/jpeg", ContentType("image/jpeg"))
+		assert.Equal(t, "image/png", ContentType("image/gif"))
+
+		var buf bytes.Buffer
+		require.NoError(t, Encode(&buf, image.NewRGBA(image.Rect(0, 0, 2, 2)), "image/jpeg"))
+		_, format, err := image.DecodeConfig(&buf)
+		require.NoError(t, err)
+		assert.Equal(t, "jpeg", format)
+	})
+}
@@ -0,0 +1,83 @@
+package totp
+
+import (
+	"encoding/base32"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// RFC 6238 付録 B の SHA-1 の鍵
+var rfcSecret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+func TestCode(t *testing.T) {
+	// RFC 6238 付録 B のテストベクター（8 桁の下 6 桁）
+	tests := []struct {
+		name string
+		unix int64
+		want string
+	}{
+		{name: "正常系: 59", unix: 59, want: "287082"},
+		{name: "正常系: 1111111109", unix: 1111111109, want: "081804"},
+		{name: "正常系: 1234567890", unix: 1234567890, want: "005924"},
+		{name: "正常系: 20000000000", unix: 20000000000, want: "353130"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Code(rfcSecret, Step(time.Unix(tt.unix, 0)))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestVerify(t *testing.T) {
+	now := time.Unix(1111111109, 0)
+
+	t.Run("正常系: 前後 1 ステップのずれまで受け付け、一致したステップを返す", func(t *testing.T) {
+		previous, err := Code(rfcSecret, Step(now)-1)
+		require.NoError(t, err)
+
+		step, ok := Verify(rfcSecret, previous, now)
+		assert.True(t, ok)
+		assert.Equal(t, Step(now)-1, step)
+	})
+
+	t.Run("異常系: 2 ステップ以上前のコード", func(t *testing.T) {
+		old, err := Code(rfcSecret, Step(now)-2)
+		require.NoError(t, err)
+
+		_, ok := Verify(rfcSecret, old, now)
+		assert.False(t, ok)
+	})
+
+	t.Run("異常系: 桁数が違う", func(t *testing.T) {
+		_, ok := Verify(rfcSecret, "81804", now)
+		assert.False(t, ok)
+	})
+}
+
+func TestGenerateSecret(t *testing.T) {
+	secret, err := GenerateSecret()
+	require.NoError(t, err)
+	assert.Len(t, secret, 32)
+
+	_, err = Code(secret, 1)
+	assert.NoError(t, err)
+}
+
+func TestURL(t *testing.T) {
+	parsed, err := url.Parse(URL("Aicon Inventory", "yamada", "JBSWY3DPEHPK3PXP"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "otpauth", parsed.Scheme)
+	assert.Equal(t, "totp", parsed.Host)
+	assert.Equal(t, "/Aicon Inventory:yamada", parsed.Path)
+	assert.Equal(t, "JBSWY3DPEHPK3PXP", parsed.Query().Get("secret"))
+	assert.Equal(t, "Aicon Inventory", parsed.Query().Get("issuer"))
+	assert.Equal(t, "6", parsed.Query().Get("digits"))
+}
@@ -0,0 +1,203 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 読み込むファイルの展開後のサイズの上限（zip 爆弾への対策）
+const maxPartSize = 100 << 20
+
+var ErrInvalidFile = errors.New("xlsx: not a valid xlsx file")
+
+// シートの 1 行。Num は Excel 上の行番号（1 始まり）。空の行は含まない
+type Row struct {
+	Num   int
+	Cells []string
+}
+
+// 最初のシートの行を読み込む。セルの値は表示形式を適用しない生の値で、日付はシリアル値のまま返す
+func ReadRows(r io.ReaderAt, size int64) ([]Row, error) {
+	z, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, ErrInvalidFile
+	}
+	files := make(map[string]*zip.File, len(z.File))
+	for _, f := range z.File {
+		files[f.Name] = f
+	}
+
+	sheetPath, err := firstSheetPath(files)
+	if err != nil {
+		return nil, err
+	}
+	var shared []string
+	if f, ok := files["xl/sharedStrings.xml"]; ok {
+		if shared, err = readSharedStrings(f); err != nil {
+			return nil, err
+		}
+	}
+	f, ok := files[sheetPath]
+	if !ok {
+		return nil, ErrInvalidFile
+	}
+	return readSheet(f, shared)
+}
+
+// workbook.xml の最初のシートをリレーションからたどる
+func firstSheetPath(files map[string]*zip.File) (string, error) {
+	var wb struct {
+		Sheets []struct {
+			ID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	if err := decodePart(files["xl/workbook.xml"], &wb); err != nil {
+		return "", err
+	}
+	if len(wb.Sheets) == 0 {
+		return "", ErrInvalidFile
+	}
+
+	var rels struct {
+		Relationships []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+	if err := decodePart(files["xl/_rels/workbook.xml.rels"], &rels); err != nil {
+		return "", err
+	}
+	for _, rel := range rels.Relationships {
+		if rel.ID == wb.Sheets[0].ID {
+			if strings.HasPrefix(rel.Target, "/") {
+				return strings.TrimPrefix(rel.Target, "/"), nil
+			}
+			return path.Join("xl", rel.Target), nil
+		}
+	}
+	return "", ErrInvalidFile
+}
+
+type richText struct {
+	Text string `xml:"t"`
+	Runs []struct {
+		Text string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t richText) String() string {
+	if len(t.Runs) == 0 {
+		return t.Text
+	}
+	var b strings.Builder
+	for _, run := range t.Runs {
+		b.WriteString(run.Text)
+	}
+	return b.String()
+}
+
+func readSharedStrings(f *zip.File) ([]string, error) {
+	var sst struct {
+		Items []richText `xml:"si"`
+	}
+	if err := decodePart(f, &sst); err != nil {
+		return nil, err
+	}
+	shared := make([]string, len(sst.Items))
+	for i, item := range sst.Items {
+		shared[i] = item.String()
+	}
+	return shared, nil
+}
+
+func readSheet(f *zip.File, shared []string) ([]Row, error) {
+	var sheet struct {
+		Rows []struct {
+			Num   int `xml:"r,attr"`
+			Cells []struct {
+				Ref    string   `xml:"r,attr"`
+				Type   string   `xml:"t,attr"`
+				Value  string   `xml:"v"`
+				Inline richText `xml:"is"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+	if err := decodePart(f, &sheet); err != nil {
+		return nil, err
+	}
+
+	rows := make([]Row, 0, len(sheet.Rows))
+	for i, r := range sheet.Rows {
+		row := Row{Num: r.Num}
+		if row.Num == 0 {
+			row.Num = i + 1
+		}
+		for j, c := range r.Cells {
+			col := j
+			if c.Ref != "" {
+				if col = columnIndex(c.Ref); col < 0 {
+					return nil, fmt.Errorf("xlsx: invalid cell reference %q", c.Ref)
+				}
+			}
+			value := c.Value
+			switch c.Type {
+			case "s":
+				idx, err := strconv.Atoi(c.Value)
+				if err != nil || idx < 0 || idx >= len(shared) {
+					return nil, fmt.Errorf("xlsx: invalid shared string in %s", c.Ref)
+				}
+				value = shared[idx]
+			case "inlineStr":
+				value = c.Inline.String()
+			}
+			for len(row.Cells) < col {
+				row.Cells = append(row.Cells, "")
+			}
+			row.Cells = append(row.Cells[:col], value)
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func decodePart(f *zip.File, v any) error {
+	if f == nil {
+		return ErrInvalidFile
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return ErrInvalidFile
+	}
+	defer rc.Close()
+	if err := xml.NewDecoder(io.LimitReader(rc, maxPartSize)).Decode(v); err != nil {
+		return fmt.Errorf("xlsx: failed to read %s: %w", f.Name, err)
+	}
+	return nil
+}
+
+// "C12" の列部分を 0 始まりの列番号にする
+func columnIndex(ref string) int {
+	col := 0
+	i := 0
+	for ; i < len(ref) && 'A' <= ref[i] && ref[i] <= 'Z'; i++ {
+		col = col*26 + int(ref[i]-'A'+1)
+	}
+	if i == 0 {
+		return -1
+	}
+	return col - 1
+}
+
+// Excel の日付のシリアル値（1900 年基準）を日付にする
+func DateFromSerial(serial float64) time.Time {
+	days := math.Floor(serial)
+	return time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC).AddDate(0, 0, int(days))
+}
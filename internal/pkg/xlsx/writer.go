@@ -0,0 +1,163 @@
+// Package xlsx は Excel（xlsx）のシートを読み書きする。
+// 書き出しは 1 シートで、文字列・数値のセルとリストの入力規則（ドロップダウン）だけに対応する。
+// 読み込みは最初のシートのセルの値を文字列として返す。
+package xlsx
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// 行を受け取り、シートに順に書き出す。全行をメモリに載せない
+type Writer struct {
+	zip         *zip.Writer
+	sheet       *bufio.Writer
+	sheetName   string
+	rows        int
+	validations []validation
+	closed      bool
+}
+
+type validation struct {
+	ref    string
+	values []string
+}
+
+func NewWriter(out io.Writer, sheetName string) (*Writer, error) {
+	z := zip.NewWriter(out)
+	f, err := z.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	sheet := bufio.NewWriter(f)
+	sheet.WriteString(xml.Header)
+	sheet.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	return &Writer{zip: z, sheet: sheet, sheetName: sheetName}, nil
+}
+
+// 1 行を書く。値は string・int・int64・float64 か nil（空のセル）
+func (w *Writer) Write(cells ...any) error {
+	if w.closed {
+		return fmt.Errorf("xlsx: writer is closed")
+	}
+	w.rows++
+	fmt.Fprintf(w.sheet, `<row r="%d">`, w.rows)
+	for i, cell := range cells {
+		ref := ColumnName(i) + strconv.Itoa(w.rows)
+		switch v := cell.(type) {
+		case nil:
+		case string:
+			fmt.Fprintf(w.sheet, `<c r="%s" t="inlineStr"><is><t xml:space="preserve">`, ref)
+			xml.EscapeText(w.sheet, []byte(v))
+			w.sheet.WriteString(`</t></is></c>`)
+		case int:
+			fmt.Fprintf(w.sheet, `<c r="%s"><v>%d</v></c>`, ref, v)
+		case int64:
+			fmt.Fprintf(w.sheet, `<c r="%s"><v>%d</v></c>`, ref, v)
+		case float64:
+			fmt.Fprintf(w.sheet, `<c r="%s"><v>%s</v></c>`, ref, strconv.FormatFloat(v, 'f', -1, 64))
+		default:
+			return fmt.Errorf("xlsx: unexpected value type %T", cell)
+		}
+	}
+	w.sheet.WriteString(`</row>`)
+	return nil
+}
+
+// ref（"C2:C10001" など）のセルに、values から選ぶドロップダウンを付ける
+// 値はカンマ区切りで埋め込むため、カンマを含む値や合計 255 文字を超える一覧には使えない
+func (w *Writer) AddListValidation(ref string, values []string) error {
+	for _, v := range values {
+		if strings.Contains(v, ",") {
+			return fmt.Errorf("xlsx: list value %q contains a comma", v)
+		}
+	}
+	if len([]rune(strings.Join(values, ","))) > 255 {
+		return fmt.Errorf("xlsx: list values must fit in 255 characters")
+	}
+	w.validations = append(w.validations, validation{ref: ref, values: values})
+	return nil
+}
+
+// バッファした内容を書き出す。ストリーミング中のレスポンスに合わせて呼ぶ
+func (w *Writer) Flush() error {
+	if err := w.sheet.Flush(); err != nil {
+		return err
+	}
+	return w.zip.Flush()
+}
+
+// シートを閉じ、ブックの残りの部分を書き出す。out は閉じない
+func (w *Writer) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+
+	w.sheet.WriteString(`</sheetData>`)
+	if len(w.validations) > 0 {
+		fmt.Fprintf(w.sheet, `<dataValidations count="%d">`, len(w.validations))
+		for _, v := range w.validations {
+			fmt.Fprintf(w.sheet, `<dataValidation type="list" allowBlank="1" showErrorMessage="1" sqref="%s"><formula1>`, v.ref)
+			xml.EscapeText(w.sheet, []byte(`"`+strings.Join(v.values, ",")+`"`))
+			w.sheet.WriteString(`</formula1></dataValidation>`)
+		}
+		w.sheet.WriteString(`</dataValidations>`)
+	}
+	w.sheet.WriteString(`</worksheet>`)
+	if err := w.sheet.Flush(); err != nil {
+		return err
+	}
+
+	var sheetName strings.Builder
+	xml.EscapeText(&sheetName, []byte(w.sheetName))
+	parts := []struct{ name, body string }{
+		{"[Content_Types].xml", contentTypes},
+		{"_rels/.rels", rootRels},
+		{"xl/workbook.xml", fmt.Sprintf(workbook, sheetName.String())},
+		{"xl/_rels/workbook.xml.rels", workbookRels},
+	}
+	for _, part := range parts {
+		f, err := w.zip.Create(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, xml.Header+part.body); err != nil {
+			return err
+		}
+	}
+	return w.zip.Close()
+}
+
+// 0 始まりの列番号を A, B, ..., Z, AA, ... にする
+func ColumnName(i int) string {
+	name := ""
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = string(rune('A'+(i-1)%26)) + name
+	}
+	return name
+}
+
+const contentTypes = `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+	`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+	`<Default Extension="xml" ContentType="application/xml"/>` +
+	`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+	`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+	`</Types>`
+
+const rootRels = `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+const workbook = `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+	`<sheets><sheet name="%s" sheetId="1" r:id="rId1"/></sheets>` +
+	`</workbook>`
+
+const workbookRels = `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+	`</Relationships>`
@@ -0,0 +1,101 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriterAndReadRows(t *testing.T) {
+	t.Run("正常系: 書き出したシートを読み込める", func(t *testing.T) {
+		var out bytes.Buffer
+		w, err := NewWriter(&out, "items")
+		require.NoError(t, err)
+		require.NoError(t, w.Write("name", "purchase_price", "memo"))
+		require.NoError(t, w.Write("デイトナ <R&D>", 1500000, nil))
+		require.NoError(t, w.Write(nil, int64(3), " 前後の空白 "))
+		require.NoError(t, w.AddListValidation("B2:B10", []string{"時計", "バッグ"}))
+		require.NoError(t, w.Close())
+
+		z, err := zip.NewReader(bytes.NewReader(out.Bytes()), int64(out.Len()))
+		require.NoError(t, err)
+		sheet := readPart(t, z, "xl/worksheets/sheet1.xml")
+		assert.Contains(t, sheet, `<dataValidation type="list" allowBlank="1" showErrorMessage="1" sqref="B2:B10"><formula1>&#34;時計,バッグ&#34;</formula1></dataValidation>`)
+
+		rows, err := ReadRows(bytes.NewReader(out.Bytes()), int64(out.Len()))
+		require.NoError(t, err)
+		assert.Equal(t, []Row{
+			{Num: 1, Cells: []string{"name", "purchase_price", "memo"}},
+			{Num: 2, Cells: []string{"デイトナ <R&D>", "1500000"}},
+			{Num: 3, Cells: []string{"", "3", " 前後の空白 "}},
+		}, rows)
+	})
+
+	t.Run("正常系: 共有文字列と飛ばした列・行を読み込む", func(t *testing.T) {
+		file := buildFile(t, map[string]string{
+			"xl/workbook.xml":            `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="在庫" sheetId="1" r:id="rId3"/></sheets></workbook>`,
+			"xl/_rels/workbook.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId3" Target="worksheets/inventory.xml"/></Relationships>`,
+			"xl/sharedStrings.xml":       `<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><si><t>時計</t></si><si><r><t>ROL</t></r><r><t>EX</t></r></si></sst>`,
+			"xl/worksheets/inventory.xml": `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>` +
+				`<row r="1"><c r="A1" t="s"><v>0</v></c><c r="C1" t="s"><v>1</v></c></row>` +
+				`<row r="4"><c r="B4"><v>45306</v></c></row>` +
+				`</sheetData></worksheet>`,
+		})
+
+		rows, err := ReadRows(bytes.NewReader(file), int64(len(file)))
+		require.NoError(t, err)
+		assert.Equal(t, []Row{
+			{Num: 1, Cells: []string{"時計", "", "ROLEX"}},
+			{Num: 4, Cells: []string{"", "45306"}},
+		}, rows)
+	})
+
+	t.Run("異常系: xlsx でないファイル", func(t *testing.T) {
+		_, err := ReadRows(bytes.NewReader([]byte("name,category\n")), 14)
+		assert.ErrorIs(t, err, ErrInvalidFile)
+	})
+
+	t.Run("異常系: リストの値にカンマを含む", func(t *testing.T) {
+		w, err := NewWriter(io.Discard, "items")
+		require.NoError(t, err)
+		assert.Error(t, w.AddListValidation("A2:A10", []string{"a,b"}))
+	})
+}
+
+func TestColumnName(t *testing.T) {
+	assert.Equal(t, "A", ColumnName(0))
+	assert.Equal(t, "Z", ColumnName(25))
+	assert.Equal(t, "AA", ColumnName(26))
+	assert.Equal(t, 27, columnIndex("AB12"))
+}
+
+func TestDateFromSerial(t *testing.T) {
+	assert.Equal(t, time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC), DateFromSerial(45306))
+	assert.Equal(t, time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC), DateFromSerial(45306.75))
+}
+
+func buildFile(t *testing.T, parts map[string]string) []byte {
+	var buf bytes.Buffer
+	z := zip.NewWriter(&buf)
+	for name, body := range parts {
+		f, err := z.Create(name)
+		require.NoError(t, err)
+		f.Write([]byte(body))
+	}
+	require.NoError(t, z.Close())
+	return buf.Bytes()
+}
+
+func readPart(t *testing.T, z *zip.Reader, name string) string {
+	f, err := z.Open(name)
+	require.NoError(t, err)
+	defer f.Close()
+	body, err := io.ReadAll(f)
+	require.NoError(t, err)
+	return string(body)
+}
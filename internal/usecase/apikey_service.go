@@ -0,0 +1,168 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/pkg/clock"
+	"Aicon-assignment/internal/pkg/idgen"
+	"Aicon-assignment/internal/pkg/reqctx"
+)
+
+// API キーの接頭辞。他のトークンと見分けられるようにする
+const APIKeyPrefix = "ak_"
+
+// サンドボックスの API キーの接頭辞。サンドボックスのルートでだけ受け付ける
+const SandboxKeyPrefix = "sbx_"
+
+// 最終使用日時を記録し直す間隔。リクエストのたびに書き込まないようにする
+const apiKeyTouchInterval = time.Minute
+
+type APIKeyUsecase interface {
+	// Create は呼び出し元のユーザーの API キーを発行する。返すキーにだけ平文のキーが含まれる
+	Create(ctx context.Context, input CreateAPIKeyInput) (*entity.APIKey, error)
+	// List は呼び出し元のユーザーの API キーを失効したものも含めて返す
+	List(ctx context.Context) ([]*entity.APIKey, error)
+	// Revoke はキーを失効させる。本人のキーか、管理者の場合だけ許可する
+	Revoke(ctx context.Context, id int64) (*entity.APIKey, error)
+	// Authenticate はキーの持ち主のユーザーを返し、最終使用日時を記録する
+	Authenticate(ctx context.Context, key string) (*entity.User, error)
+}
+
+type CreateAPIKeyInput struct {
+	Name string `json:"name"`
+}
+
+type apiKeyUsecase struct {
+	keys   APIKeyRepository
+	users  UserRepository
+	prefix string
+	clock  clock.Clock
+}
+
+func NewAPIKeyUsecase(keys APIKeyRepository, users UserRepository, clock clock.Clock) APIKeyUsecase {
+	return &apiKeyUsecase{
+		keys:   keys,
+		users:  users,
+		prefix: APIKeyPrefix,
+		clock:  clock,
+	}
+}
+
+// サンドボックスの API キー。本番のキーとは別のリポジトリに保存し、接頭辞で見分ける
+func NewSandboxKeyUsecase(keys APIKeyRepository, users UserRepository, clock clock.Clock) APIKeyUsecase {
+	return &apiKeyUsecase{
+		keys:   keys,
+		users:  users,
+		prefix: SandboxKeyPrefix,
+		clock:  clock,
+	}
+}
+
+func (u *apiKeyUsecase) Create(ctx context.Context, input CreateAPIKeyInput) (*entity.APIKey, error) {
+	userID, ok := reqctx.UserID(ctx)
+	if !ok {
+		return nil, domainErrors.ErrUnauthenticated
+	}
+	// なりすまし中に発行すると、セッションの終了後も対象ユーザーとして操作できてしまう
+	if _, impersonating := reqctx.ImpersonatorID(ctx); impersonating {
+		return nil, fmt.Errorf("%w: cannot create api keys while impersonating", domainErrors.ErrForbidden)
+	}
+
+	key := idgen.NewRandomID()
+	if key != "" {
+		key = u.prefix + key
+	}
+
+	apiKey, err := entity.NewAPIKey(userID, input.Name, key, u.clock.Now())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrInvalidInput, err.Error())
+	}
+
+	if err := u.keys.Create(ctx, apiKey); err != nil {
+		return nil, fmt.Errorf("failed to create api key: %w", err)
+	}
+
+	reqctx.Logger(ctx).Info("api key created", "api_key_id", apiKey.ID, "user_id", userID, "prefix", apiKey.Prefix)
+	return apiKey, nil
+}
+
+func (u *apiKeyUsecase) List(ctx context.Context) ([]*entity.APIKey, error) {
+	userID, ok := reqctx.UserID(ctx)
+	if !ok {
+		return nil, domainErrors.ErrUnauthenticated
+	}
+
+	keys, err := u.keys.FindByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve api keys: %w", err)
+	}
+	return keys, nil
+}
+
+// 失効済みの場合はそのまま返す
+// 他のユーザーのキーは、管理者でなければ存在しないものとして扱う
+func (u *apiKeyUsecase) Revoke(ctx context.Context, id int64) (*entity.APIKey, error) {
+	userID, ok := reqctx.UserID(ctx)
+	if !ok {
+		return nil, domainErrors.ErrUnauthenticated
+	}
+	if id <= 0 {
+		return nil, domainErrors.ErrInvalidInput
+	}
+
+	apiKey, err := u.keys.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if apiKey.UserID != userID && requireAdmin(ctx) != nil {
+		return nil, domainErrors.ErrAPIKeyNotFound
+	}
+	if !apiKey.Active() {
+		return apiKey, nil
+	}
+
+	now := u.clock.Now()
+	if err := u.keys.Revoke(ctx, id, now); err != nil {
+		return nil, fmt.Errorf("failed to revoke api key: %w", err)
+	}
+	apiKey.RevokedAt = &now
+
+	reqctx.Logger(ctx).Info("api key revoked", "api_key_id", apiKey.ID, "user_id", apiKey.UserID, "revoked_by", userID)
+	return apiKey, nil
+}
+
+// 存在しない・失効したキーや、持ち主のユーザーが削除・無効化された場合は ErrUnauthenticated
+// 最終使用日時の記録に失敗しても認証は成功させる
+func (u *apiKeyUsecase) Authenticate(ctx context.Context, key string) (*entity.User, error) {
+	apiKey, err := u.keys.FindByKeyHash(ctx, entity.HashToken(key))
+	if err != nil {
+		if errors.Is(err, domainErrors.ErrAPIKeyNotFound) {
+			return nil, domainErrors.ErrUnauthenticated
+		}
+		return nil, fmt.Errorf("failed to retrieve api key: %w", err)
+	}
+	if !apiKey.Active() {
+		return nil, domainErrors.ErrUnauthenticated
+	}
+
+	user, err := u.users.FindByID(ctx, apiKey.UserID)
+	if errors.Is(err, domainErrors.ErrUserNotFound) || (err == nil && !user.Active) {
+		return nil, domainErrors.ErrUnauthenticated
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve user: %w", err)
+	}
+
+	now := u.clock.Now()
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) >= apiKeyTouchInterval {
+		if err := u.keys.TouchLastUsed(ctx, apiKey.ID, now); err != nil {
+			reqctx.Logger(ctx).Warn("failed to record api key usage", "api_key_id", apiKey.ID, "error", err)
+		}
+	}
+	return user, nil
+}
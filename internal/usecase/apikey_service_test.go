@@ -0,0 +1,230 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/pkg/clock"
+	"Aicon-assignment/internal/pkg/reqctx"
+)
+
+// MockAPIKeyRepository はテスト用の API キーリポジトリ
+type MockAPIKeyRepository struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyRepository) Create(ctx context.Context, key *entity.APIKey) error {
+	args := m.Called(ctx, key)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) FindByID(ctx context.Context, id int64) (*entity.APIKey, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) FindByKeyHash(ctx context.Context, keyHash string) (*entity.APIKey, error) {
+	args := m.Called(ctx, keyHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) FindByUser(ctx context.Context, userID int64) ([]*entity.APIKey, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) Revoke(ctx context.Context, id int64, at time.Time) error {
+	args := m.Called(ctx, id, at)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) TouchLastUsed(ctx context.Context, id int64, at time.Time) error {
+	args := m.Called(ctx, id, at)
+	return args.Error(0)
+}
+
+func TestAPIKeyUsecase_Create(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	asUser := reqctx.WithUserID(context.Background(), 7)
+
+	t.Run("正常系: キーを発行し、ハッシュ値だけを保存する", func(t *testing.T) {
+		keys := new(MockAPIKeyRepository)
+		keys.On("Create", mock.Anything, mock.MatchedBy(func(key *entity.APIKey) bool {
+			return key.UserID == 7 && key.KeyHash == entity.HashToken(key.Key)
+		})).Run(func(args mock.Arguments) {
+			args.Get(1).(*entity.APIKey).ID = 3
+		}).Return(nil)
+
+		key, err := NewAPIKeyUsecase(keys, new(MockUserRepository), clock.NewFrozen(now)).Create(asUser, CreateAPIKeyInput{Name: "nightly batch"})
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), key.ID)
+		assert.True(t, strings.HasPrefix(key.Key, APIKeyPrefix))
+		assert.True(t, strings.HasPrefix(key.Key, key.Prefix))
+		assert.Equal(t, now, key.CreatedAt)
+		keys.AssertExpectations(t)
+	})
+
+	t.Run("正常系: サンドボックスのキーは接頭辞で見分けられる", func(t *testing.T) {
+		keys := new(MockAPIKeyRepository)
+		keys.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+		key, err := NewSandboxKeyUsecase(keys, new(MockUserRepository), clock.NewFrozen(now)).Create(asUser, CreateAPIKeyInput{Name: "partner dev"})
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(key.Key, SandboxKeyPrefix))
+		assert.False(t, strings.HasPrefix(key.Key, APIKeyPrefix))
+	})
+
+	t.Run("異常系: 名前がない", func(t *testing.T) {
+		keys := new(MockAPIKeyRepository)
+
+		_, err := NewAPIKeyUsecase(keys, new(MockUserRepository), clock.NewFrozen(now)).Create(asUser, CreateAPIKeyInput{Name: "  "})
+		assert.ErrorIs(t, err, domainErrors.ErrInvalidInput)
+		keys.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("異常系: なりすまし中", func(t *testing.T) {
+		_, err := NewAPIKeyUsecase(new(MockAPIKeyRepository), new(MockUserRepository), clock.NewFrozen(now)).Create(reqctx.WithImpersonatorID(asUser, 1), CreateAPIKeyInput{Name: "batch"})
+		assert.ErrorIs(t, err, domainErrors.ErrForbidden)
+	})
+
+	t.Run("異常系: ログインしていない", func(t *testing.T) {
+		_, err := NewAPIKeyUsecase(new(MockAPIKeyRepository), new(MockUserRepository), clock.NewFrozen(now)).Create(context.Background(), CreateAPIKeyInput{Name: "batch"})
+		assert.ErrorIs(t, err, domainErrors.ErrUnauthenticated)
+	})
+}
+
+func TestAPIKeyUsecase_Revoke(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		wantErr error
+	}{
+		{
+			name: "正常系: 本人のキー",
+			ctx:  reqctx.WithUserRole(reqctx.WithUserID(context.Background(), 7), entity.UserRoleMember),
+		},
+		{
+			name: "正常系: 管理者は他のユーザーのキーも失効できる",
+			ctx:  reqctx.WithUserRole(reqctx.WithUserID(context.Background(), 1), entity.UserRoleAdmin),
+		},
+		{
+			name:    "異常系: 他のユーザーのキーは存在しないものとして扱う",
+			ctx:     reqctx.WithUserRole(reqctx.WithUserID(context.Background(), 8), entity.UserRoleMember),
+			wantErr: domainErrors.ErrAPIKeyNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys := new(MockAPIKeyRepository)
+			keys.On("FindByID", mock.Anything, int64(3)).Return(&entity.APIKey{ID: 3, UserID: 7, Name: "batch"}, nil)
+			if tt.wantErr == nil {
+				keys.On("Revoke", mock.Anything, int64(3), now).Return(nil)
+			}
+
+			key, err := NewAPIKeyUsecase(keys, new(MockUserRepository), clock.NewFrozen(now)).Revoke(tt.ctx, 3)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				keys.AssertNotCalled(t, "Revoke", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, key.RevokedAt)
+			assert.Equal(t, now, *key.RevokedAt)
+			keys.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAPIKeyUsecase_Authenticate(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	const key = "ak_0123456789abcdef"
+	revokedAt := now.Add(-time.Hour)
+	recentlyUsed := now.Add(-10 * time.Second)
+
+	tests := []struct {
+		name      string
+		stored    *entity.APIKey
+		user      *entity.User
+		wantTouch bool
+		wantErr   error
+	}{
+		{
+			name:      "正常系: キーの持ち主を返し、最終使用日時を記録する",
+			stored:    &entity.APIKey{ID: 3, UserID: 7},
+			user:      &entity.User{ID: 7, Active: true, Role: entity.UserRoleMember},
+			wantTouch: true,
+		},
+		{
+			name:   "正常系: 直前に使ったキーは最終使用日時を記録し直さない",
+			stored: &entity.APIKey{ID: 3, UserID: 7, LastUsedAt: &recentlyUsed},
+			user:   &entity.User{ID: 7, Active: true, Role: entity.UserRoleMember},
+		},
+		{
+			name:    "異常系: 存在しないキー",
+			wantErr: domainErrors.ErrUnauthenticated,
+		},
+		{
+			name:    "異常系: 失効したキー",
+			stored:  &entity.APIKey{ID: 3, UserID: 7, RevokedAt: &revokedAt},
+			wantErr: domainErrors.ErrUnauthenticated,
+		},
+		{
+			name:    "異常系: 無効化されたユーザーのキー",
+			stored:  &entity.APIKey{ID: 3, UserID: 7},
+			user:    &entity.User{ID: 7, Active: false},
+			wantErr: domainErrors.ErrUnauthenticated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys := new(MockAPIKeyRepository)
+			if tt.stored != nil {
+				keys.On("FindByKeyHash", mock.Anything, entity.HashToken(key)).Return(tt.stored, nil)
+			} else {
+				keys.On("FindByKeyHash", mock.Anything, mock.Anything).Return(nil, domainErrors.ErrAPIKeyNotFound)
+			}
+			if tt.wantTouch {
+				keys.On("TouchLastUsed", mock.Anything, int64(3), now).Return(nil)
+			}
+			users := new(MockUserRepository)
+			if tt.user != nil {
+				users.On("FindByID", mock.Anything, int64(7)).Return(tt.user, nil)
+			}
+
+			user, err := NewAPIKeyUsecase(keys, users, clock.NewFrozen(now)).Authenticate(context.Background(), key)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, int64(7), user.ID)
+			if !tt.wantTouch {
+				keys.AssertNotCalled(t, "TouchLastUsed", mock.Anything, mock.Anything, mock.Anything)
+			}
+			keys.AssertExpectations(t)
+		})
+	}
+}
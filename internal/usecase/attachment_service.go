@@ -0,0 +1,319 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/pkg/clock"
+	"Aicon-assignment/internal/pkg/reqctx"
+)
+
+// 参照のなくなった中身を一度に読み込む件数
+const unreferencedBatchSize = 100
+
+type AttachmentUsecase interface {
+	// Upload はアイテムにファイルを添付する。同じ中身のファイルがすでにあれば保存し直さず参照だけを増やす
+	Upload(ctx context.Context, itemID int64, filename, contentType string, content io.ReadSeeker) (*entity.Attachment, error)
+	List(ctx context.Context, itemID int64) ([]*entity.Attachment, error)
+	// Open は添付ファイルの中身を開く
+	Open(ctx context.Context, itemID, attachmentID int64) (io.ReadCloser, *entity.Attachment, error)
+	// Delete は添付ファイルを削除する。中身は参照がなくなっても CollectGarbage まで残す
+	Delete(ctx context.Context, itemID, attachmentID int64) error
+	// CollectGarbage は参照がなくなってから猶予期間を過ぎた中身を削除し、削除した件数を返す
+	CollectGarbage(ctx context.Context) (int64, error)
+	StorageReport(ctx context.Context) (*entity.AttachmentStorageReport, error)
+
+	// UploadReceipt はアイテムに購入時のレシート（PDF・JPEG）を添付する。すでにある場合は置き換える
+	// 形式は送られた Content-Type ではなく中身から判定する
+	UploadReceipt(ctx context.Context, itemID int64, filename string, content io.ReadSeeker) (*entity.Attachment, error)
+	// OpenReceipt はレシートの中身を開く
+	OpenReceipt(ctx context.Context, itemID int64) (io.ReadCloser, *entity.Attachment, error)
+	DeleteReceipt(ctx context.Context, itemID int64) error
+	ReceiptLoader
+}
+
+// アイテムのレシートを読み込む（アイテムを取得するときに ItemUsecase が使う）
+type ReceiptLoader interface {
+	// Receipt はアイテムの存在を確かめずにレシートを返す。ない場合は ErrReceiptNotFound を返す
+	Receipt(ctx context.Context, itemID int64) (*entity.Attachment, error)
+}
+
+type attachmentUsecase struct {
+	attachments AttachmentRepository
+	itemRepo    ItemRepository
+	blobs       BlobUsecase
+	scanner     UploadScanner // nil の場合は検査しない
+	transactor  Transactor
+	gcGrace     time.Duration // 参照がなくなってから中身を削除するまでの猶予期間
+	clock       clock.Clock
+}
+
+func NewAttachmentUsecase(attachments AttachmentRepository, itemRepo ItemRepository, blobs BlobUsecase, scanner UploadScanner, transactor Transactor, gcGrace time.Duration, clock clock.Clock) AttachmentUsecase {
+	return &attachmentUsecase{
+		attachments: attachments,
+		itemRepo:    itemRepo,
+		blobs:       blobs,
+		scanner:     scanner,
+		transactor:  transactor,
+		gcGrace:     gcGrace,
+		clock:       clock,
+	}
+}
+
+func (u *attachmentUsecase) Upload(ctx context.Context, itemID int64, filename, contentType string, content io.ReadSeeker) (*entity.Attachment, error) {
+	// 鑑定士は検証するアイテムに鑑定書を添付する
+	attachment, err := u.newAttachment(appraiserScope(ctx), itemID, entity.AttachmentKindDocument, filename)
+	if err != nil {
+		return nil, err
+	}
+	attachment.ContentType = contentType
+	if err := u.store(ctx, entity.UploadKindAttachment, attachment, content); err != nil {
+		return nil, err
+	}
+	err = u.transactor.Transaction(ctx, func(ctx context.Context) error {
+		if err := u.attachments.AddReference(ctx, attachment.SHA256, attachment.Size); err != nil {
+			return err
+		}
+		return u.attachments.Create(ctx, attachment)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return attachment, nil
+}
+
+// 前のレシートの削除と新しいレシートの追加を同じトランザクションで行い、レシートが 2 つにならないようにする
+func (u *attachmentUsecase) UploadReceipt(ctx context.Context, itemID int64, filename string, content io.ReadSeeker) (*entity.Attachment, error) {
+	attachment, err := u.newAttachment(ctx, itemID, entity.AttachmentKindReceipt, filename)
+	if err != nil {
+		return nil, err
+	}
+	head := make([]byte, imageSniffLen)
+	n, err := io.ReadFull(content, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to read receipt: %w", err)
+	}
+	if _, err := content.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("failed to read receipt: %w", err)
+	}
+	attachment.ContentType = http.DetectContentType(head[:n])
+	if !slices.Contains(entity.ReceiptContentTypes, attachment.ContentType) {
+		return nil, fmt.Errorf("%w: receipt must be one of %s", domainErrors.ErrInvalidInput, strings.Join(entity.ReceiptContentTypes, ", "))
+	}
+	if err := u.store(ctx, entity.UploadKindReceipt, attachment, content); err != nil {
+		return nil, err
+	}
+
+	err = u.transactor.Transaction(ctx, func(ctx context.Context) error {
+		previous, err := u.attachments.FindReceipt(ctx, itemID)
+		if err != nil && !errors.Is(err, domainErrors.ErrReceiptNotFound) {
+			return err
+		}
+		if previous != nil {
+			if err := u.attachments.Delete(ctx, previous.ID); err != nil {
+				return err
+			}
+			if err := u.attachments.RemoveReference(ctx, previous.SHA256, u.clock.Now()); err != nil {
+				return err
+			}
+		}
+		if err := u.attachments.AddReference(ctx, attachment.SHA256, attachment.Size); err != nil {
+			return err
+		}
+		return u.attachments.Create(ctx, attachment)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return withURL(attachment), nil
+}
+
+// ファイル名を整え、アイテムがあることを確かめる
+func (u *attachmentUsecase) newAttachment(ctx context.Context, itemID int64, kind, filename string) (*entity.Attachment, error) {
+	filename = strings.TrimSpace(filepath.Base(filename))
+	if filename == "" || filename == "." || filename == "/" {
+		return nil, fmt.Errorf("%w: filename is required", domainErrors.ErrInvalidInput)
+	}
+	if _, err := u.itemRepo.FindByID(ctx, itemID); err != nil {
+		return nil, err
+	}
+	return &entity.Attachment{ItemID: itemID, Kind: kind, Filename: filename, CreatedAt: u.clock.Now()}, nil
+}
+
+// 先に中身を読んで SHA-256 を求め、まだ保存していない（または削除を待っている）場合だけファイルストアに書く
+func (u *attachmentUsecase) store(ctx context.Context, uploadKind string, attachment *entity.Attachment, content io.ReadSeeker) error {
+	upload := entity.Upload{Kind: uploadKind, ItemID: attachment.ItemID, Filename: attachment.Filename, ContentType: attachment.ContentType}
+	if u.scanner != nil {
+		if err := u.scanner.ScanUpload(ctx, upload, content); err != nil {
+			return err
+		}
+	}
+
+	h := sha256.New()
+	size, err := io.Copy(h, content)
+	if err != nil {
+		return fmt.Errorf("failed to read attachment: %w", err)
+	}
+	sum := hex.EncodeToString(h.Sum(nil))
+	if _, err := content.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to read attachment: %w", err)
+	}
+
+	existing, err := u.attachments.FindContent(ctx, sum)
+	if err != nil && !errors.Is(err, domainErrors.ErrAttachmentNotFound) {
+		return err
+	}
+	if existing == nil || existing.RefCount == 0 {
+		if _, err := u.blobs.Put(ctx, entity.AttachmentBlobKey(sum), content, nil); err != nil {
+			return err
+		}
+	}
+	attachment.Size = size
+	attachment.SHA256 = sum
+	return nil
+}
+
+func (u *attachmentUsecase) List(ctx context.Context, itemID int64) ([]*entity.Attachment, error) {
+	if _, err := u.itemRepo.FindByID(appraiserScope(ctx), itemID); err != nil {
+		return nil, err
+	}
+	attachments, err := u.attachments.FindByItemID(ctx, itemID)
+	if err != nil {
+		return nil, err
+	}
+	for _, attachment := range attachments {
+		withURL(attachment)
+	}
+	return attachments, nil
+}
+
+func (u *attachmentUsecase) Open(ctx context.Context, itemID, attachmentID int64) (io.ReadCloser, *entity.Attachment, error) {
+	attachment, err := u.find(appraiserScope(ctx), itemID, attachmentID)
+	if err != nil {
+		return nil, nil, err
+	}
+	body, _, err := u.blobs.Open(ctx, entity.AttachmentBlobKey(attachment.SHA256))
+	if err != nil {
+		return nil, nil, err
+	}
+	return body, attachment, nil
+}
+
+func (u *attachmentUsecase) Delete(ctx context.Context, itemID, attachmentID int64) error {
+	attachment, err := u.find(ctx, itemID, attachmentID)
+	if err != nil {
+		return err
+	}
+	return u.transactor.Transaction(ctx, func(ctx context.Context) error {
+		if err := u.attachments.Delete(ctx, attachment.ID); err != nil {
+			return err
+		}
+		return u.attachments.RemoveReference(ctx, attachment.SHA256, u.clock.Now())
+	})
+}
+
+func (u *attachmentUsecase) Receipt(ctx context.Context, itemID int64) (*entity.Attachment, error) {
+	receipt, err := u.attachments.FindReceipt(ctx, itemID)
+	if err != nil {
+		return nil, err
+	}
+	return withURL(receipt), nil
+}
+
+func (u *attachmentUsecase) OpenReceipt(ctx context.Context, itemID int64) (io.ReadCloser, *entity.Attachment, error) {
+	if _, err := u.itemRepo.FindByID(ctx, itemID); err != nil {
+		return nil, nil, err
+	}
+	receipt, err := u.Receipt(ctx, itemID)
+	if err != nil {
+		return nil, nil, err
+	}
+	body, _, err := u.blobs.Open(ctx, entity.AttachmentBlobKey(receipt.SHA256))
+	if err != nil {
+		return nil, nil, err
+	}
+	return body, receipt, nil
+}
+
+func (u *attachmentUsecase) DeleteReceipt(ctx context.Context, itemID int64) error {
+	if _, err := u.itemRepo.FindByID(ctx, itemID); err != nil {
+		return err
+	}
+	receipt, err := u.attachments.FindReceipt(ctx, itemID)
+	if err != nil {
+		return err
+	}
+	return u.Delete(ctx, itemID, receipt.ID)
+}
+
+// レシートには取得する API のパスを付ける
+func withURL(attachment *entity.Attachment) *entity.Attachment {
+	if attachment.Kind == entity.AttachmentKindReceipt {
+		attachment.URL = entity.ReceiptPath(attachment.ItemID)
+	}
+	return attachment
+}
+
+// 他のアイテムの添付ファイルは見つからないものとして扱う
+func (u *attachmentUsecase) find(ctx context.Context, itemID, attachmentID int64) (*entity.Attachment, error) {
+	attachment, err := u.attachments.FindByID(ctx, attachmentID)
+	if err != nil {
+		return nil, err
+	}
+	if attachment.ItemID != itemID {
+		return nil, domainErrors.ErrAttachmentNotFound
+	}
+	return attachment, nil
+}
+
+// 記録を消してからファイルを消す。猶予期間があるため、削除と同時に同じ中身がアップロードされることはまずない
+// アイテムの完全削除で添付ファイルの記録ごと消えた場合に備え、先に参照の数を数え直す
+func (u *attachmentUsecase) CollectGarbage(ctx context.Context) (int64, error) {
+	now := u.clock.Now()
+	if _, err := u.attachments.ReconcileReferences(ctx, now); err != nil {
+		return 0, fmt.Errorf("failed to reconcile attachment references: %w", err)
+	}
+
+	before := now.Add(-u.gcGrace)
+	var deleted int64
+	for {
+		contents, err := u.attachments.FindUnreferenced(ctx, before, unreferencedBatchSize)
+		if err != nil {
+			return deleted, fmt.Errorf("failed to find unreferenced attachments: %w", err)
+		}
+		for _, content := range contents {
+			ok, err := u.attachments.DeleteContent(ctx, content.SHA256)
+			if err != nil {
+				return deleted, err
+			}
+			if !ok {
+				continue // 削除する前に参照された
+			}
+			if err := u.blobs.Delete(ctx, entity.AttachmentBlobKey(content.SHA256)); err != nil {
+				return deleted, err
+			}
+			deleted++
+		}
+		if len(contents) < unreferencedBatchSize {
+			if deleted > 0 {
+				reqctx.Logger(ctx).Info("unreferenced attachments deleted", "count", deleted)
+			}
+			return deleted, nil
+		}
+	}
+}
+
+func (u *attachmentUsecase) StorageReport(ctx context.Context) (*entity.AttachmentStorageReport, error) {
+	return u.attachments.StorageReport(ctx)
+}
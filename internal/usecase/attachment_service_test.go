@@ -0,0 +1,239 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/pkg/clock"
+)
+
+type MockAttachmentRepository struct {
+	mock.Mock
+}
+
+func (m *MockAttachmentRepository) Create(ctx context.Context, attachment *entity.Attachment) error {
+	args := m.Called(ctx, attachment)
+	attachment.ID = 1
+	return args.Error(0)
+}
+
+func (m *MockAttachmentRepository) FindByID(ctx context.Context, id int64) (*entity.Attachment, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Attachment), args.Error(1)
+}
+
+func (m *MockAttachmentRepository) FindByItemID(ctx context.Context, itemID int64) ([]*entity.Attachment, error) {
+	args := m.Called(ctx, itemID)
+	return args.Get(0).([]*entity.Attachment), args.Error(1)
+}
+
+func (m *MockAttachmentRepository) FindReceipt(ctx context.Context, itemID int64) (*entity.Attachment, error) {
+	args := m.Called(ctx, itemID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Attachment), args.Error(1)
+}
+
+func (m *MockAttachmentRepository) Delete(ctx context.Context, id int64) error {
+	return m.Called(ctx, id).Error(0)
+}
+
+func (m *MockAttachmentRepository) MoveToItem(ctx context.Context, fromItemID, toItemID int64) error {
+	return m.Called(ctx, fromItemID, toItemID).Error(0)
+}
+
+func (m *MockAttachmentRepository) FindContent(ctx context.Context, sha256 string) (*entity.AttachmentContent, error) {
+	args := m.Called(ctx, sha256)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.AttachmentContent), args.Error(1)
+}
+
+func (m *MockAttachmentRepository) AddReference(ctx context.Context, sha256 string, size int64) error {
+	return m.Called(ctx, sha256, size).Error(0)
+}
+
+func (m *MockAttachmentRepository) RemoveReference(ctx context.Context, sha256 string, now time.Time) error {
+	return m.Called(ctx, sha256, now).Error(0)
+}
+
+func (m *MockAttachmentRepository) FindUnreferenced(ctx context.Context, before time.Time, limit int) ([]*entity.AttachmentContent, error) {
+	args := m.Called(ctx, before, limit)
+	return args.Get(0).([]*entity.AttachmentContent), args.Error(1)
+}
+
+func (m *MockAttachmentRepository) ReconcileReferences(ctx context.Context, now time.Time) (int64, error) {
+	args := m.Called(ctx, now)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockAttachmentRepository) DeleteContent(ctx context.Context, sha256 string) (bool, error) {
+	args := m.Called(ctx, sha256)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockAttachmentRepository) StorageReport(ctx context.Context) (*entity.AttachmentStorageReport, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(*entity.AttachmentStorageReport), args.Error(1)
+}
+
+// 削除したキーも記録する fakeBlobs
+type deletingBlobs struct {
+	fakeBlobs
+	deleted []string
+}
+
+func (f *deletingBlobs) Delete(ctx context.Context, key string) error {
+	f.deleted = append(f.deleted, key)
+	return nil
+}
+
+func TestAttachmentUsecase(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	sum := strings.Repeat("b", 64)
+
+	newUsecase := func(repo *MockAttachmentRepository, items *MockItemRepository, blobs BlobUsecase, tx *fakeTransactor) AttachmentUsecase {
+		return NewAttachmentUsecase(repo, items, blobs, nil, tx, 24*time.Hour, clock.NewFrozen(now))
+	}
+	existingItem := func() *MockItemRepository {
+		items := new(MockItemRepository)
+		items.On("FindByID", mock.Anything, int64(1)).Return(&entity.Item{ID: 1}, nil)
+		return items
+	}
+
+	t.Run("正常系: 初めての中身はファイルストアに書き、参照を 1 にする", func(t *testing.T) {
+		repo := new(MockAttachmentRepository)
+		repo.On("FindContent", mock.Anything, mock.Anything).Return(nil, domainErrors.ErrAttachmentNotFound)
+		repo.On("AddReference", mock.Anything, mock.Anything, int64(11)).Return(nil)
+		repo.On("Create", mock.Anything, mock.Anything).Return(nil)
+		blobs := &fakeBlobs{}
+		tx := &fakeTransactor{}
+
+		attachment, err := newUsecase(repo, existingItem(), blobs, tx).Upload(ctx, 1, "../鑑定書.pdf", "application/pdf", strings.NewReader("certificate"))
+		require.NoError(t, err)
+		assert.Equal(t, "鑑定書.pdf", attachment.Filename)
+		assert.Equal(t, int64(11), attachment.Size)
+		assert.Equal(t, []byte("certificate"), blobs.files[entity.AttachmentBlobKey(attachment.SHA256)])
+		assert.True(t, tx.committed)
+	})
+
+	t.Run("正常系: 参照されている中身と同じファイルは保存し直さず参照だけを増やす", func(t *testing.T) {
+		repo := new(MockAttachmentRepository)
+		repo.On("FindContent", mock.Anything, mock.Anything).Return(&entity.AttachmentContent{RefCount: 4}, nil)
+		repo.On("AddReference", mock.Anything, mock.Anything, int64(11)).Return(nil)
+		repo.On("Create", mock.Anything, mock.Anything).Return(nil)
+		blobs := &fakeBlobs{}
+
+		_, err := newUsecase(repo, existingItem(), blobs, &fakeTransactor{}).Upload(ctx, 1, "鑑定書.pdf", "application/pdf", strings.NewReader("certificate"))
+		require.NoError(t, err)
+		assert.Empty(t, blobs.files)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("異常系: 存在しないアイテム", func(t *testing.T) {
+		items := new(MockItemRepository)
+		items.On("FindByID", mock.Anything, int64(2)).Return(nil, domainErrors.ErrItemNotFound)
+
+		_, err := newUsecase(new(MockAttachmentRepository), items, &fakeBlobs{}, &fakeTransactor{}).Upload(ctx, 2, "a.pdf", "", strings.NewReader("x"))
+		assert.ErrorIs(t, err, domainErrors.ErrItemNotFound)
+	})
+
+	t.Run("異常系: ウイルスが見つかったファイルは添付しない", func(t *testing.T) {
+		repo := new(MockAttachmentRepository)
+		quarantine := new(MockQuarantineRepository)
+		quarantine.On("Create", mock.Anything, mock.Anything).Return(nil)
+		blobs := &fakeBlobs{}
+		scanner := NewQuarantineUsecase(fakeScanner{}, quarantine, blobs, clock.NewFrozen(now))
+
+		_, err := NewAttachmentUsecase(repo, existingItem(), blobs, scanner, &fakeTransactor{}, 24*time.Hour, clock.NewFrozen(now)).
+			Upload(ctx, 1, "a.pdf", "application/pdf", strings.NewReader("EICAR"))
+		assert.ErrorIs(t, err, domainErrors.ErrInfectedFile)
+		assert.Len(t, blobs.files, 1) // 隔離したファイルだけ
+		repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("正常系: 削除すると中身の参照を減らす", func(t *testing.T) {
+		repo := new(MockAttachmentRepository)
+		repo.On("FindByID", mock.Anything, int64(5)).Return(&entity.Attachment{ID: 5, ItemID: 1, SHA256: sum}, nil)
+		repo.On("Delete", mock.Anything, int64(5)).Return(nil)
+		repo.On("RemoveReference", mock.Anything, sum, now).Return(nil)
+
+		require.NoError(t, newUsecase(repo, existingItem(), &fakeBlobs{}, &fakeTransactor{}).Delete(ctx, 1, 5))
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("異常系: 他のアイテムの添付ファイルは見つからない", func(t *testing.T) {
+		repo := new(MockAttachmentRepository)
+		repo.On("FindByID", mock.Anything, int64(5)).Return(&entity.Attachment{ID: 5, ItemID: 2}, nil)
+
+		err := newUsecase(repo, existingItem(), &fakeBlobs{}, &fakeTransactor{}).Delete(ctx, 1, 5)
+		assert.ErrorIs(t, err, domainErrors.ErrAttachmentNotFound)
+		repo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+
+	t.Run("正常系: レシートを置き換えると前のレシートの参照を減らす", func(t *testing.T) {
+		old := strings.Repeat("c", 64)
+		repo := new(MockAttachmentRepository)
+		repo.On("FindContent", mock.Anything, mock.Anything).Return(nil, domainErrors.ErrAttachmentNotFound)
+		repo.On("FindReceipt", mock.Anything, int64(1)).Return(&entity.Attachment{ID: 3, ItemID: 1, Kind: entity.AttachmentKindReceipt, SHA256: old}, nil)
+		repo.On("Delete", mock.Anything, int64(3)).Return(nil)
+		repo.On("RemoveReference", mock.Anything, old, now).Return(nil)
+		repo.On("AddReference", mock.Anything, mock.Anything, int64(13)).Return(nil)
+		repo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+		receipt, err := newUsecase(repo, existingItem(), &fakeBlobs{}, &fakeTransactor{}).
+			UploadReceipt(ctx, 1, "receipt.pdf", strings.NewReader("%PDF-1.7 ...."))
+		require.NoError(t, err)
+		assert.Equal(t, entity.AttachmentKindReceipt, receipt.Kind)
+		assert.Equal(t, "application/pdf", receipt.ContentType)
+		assert.Equal(t, "/items/1/receipt", receipt.URL)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("異常系: PDF・JPEG 以外はレシートにできない", func(t *testing.T) {
+		repo := new(MockAttachmentRepository)
+
+		_, err := newUsecase(repo, existingItem(), &fakeBlobs{}, &fakeTransactor{}).
+			UploadReceipt(ctx, 1, "receipt.pdf", strings.NewReader("just text"))
+		assert.ErrorIs(t, err, domainErrors.ErrInvalidInput)
+		repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("異常系: レシートのないアイテム", func(t *testing.T) {
+		repo := new(MockAttachmentRepository)
+		repo.On("FindReceipt", mock.Anything, int64(1)).Return(nil, domainErrors.ErrReceiptNotFound)
+
+		err := newUsecase(repo, existingItem(), &fakeBlobs{}, &fakeTransactor{}).DeleteReceipt(ctx, 1)
+		assert.ErrorIs(t, err, domainErrors.ErrReceiptNotFound)
+	})
+
+	t.Run("正常系: 猶予期間を過ぎた中身を削除し、削除前に参照されたものは残す", func(t *testing.T) {
+		other := strings.Repeat("a", 64)
+		repo := new(MockAttachmentRepository)
+		repo.On("ReconcileReferences", mock.Anything, now).Return(int64(0), nil)
+		repo.On("FindUnreferenced", mock.Anything, now.Add(-24*time.Hour), unreferencedBatchSize).
+			Return([]*entity.AttachmentContent{{SHA256: sum}, {SHA256: other}}, nil)
+		repo.On("DeleteContent", mock.Anything, sum).Return(true, nil)
+		repo.On("DeleteContent", mock.Anything, other).Return(false, nil)
+		blobs := &deletingBlobs{}
+
+		deleted, err := newUsecase(repo, existingItem(), blobs, &fakeTransactor{}).CollectGarbage(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), deleted)
+		assert.Equal(t, []string{entity.AttachmentBlobKey(sum)}, blobs.deleted)
+	})
+}
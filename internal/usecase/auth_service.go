@@ -0,0 +1,334 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/pkg/clock"
+	"Aicon-assignment/internal/pkg/filter"
+	"Aicon-assignment/internal/pkg/idgen"
+	"Aicon-assignment/internal/pkg/jwt"
+	"Aicon-assignment/internal/pkg/reqctx"
+)
+
+// リフレッシュトークンの先頭に付ける文字列
+const RefreshTokenPrefix = "rt_"
+
+type AuthUsecase interface {
+	// Login はユーザー名とパスワードを確かめ、アクセストークン（JWT）とリフレッシュトークンを発行する
+	// ユーザーがいない・パスワードが違う・無効化されている場合は、どれかを区別せず ErrUnauthenticated を返す
+	// 二要素認証を有効にしたユーザーは、パスワードが正しくても OTP がなければ ErrTwoFactorRequired を返す
+	Login(ctx context.Context, input LoginInput) (*entity.AccessToken, error)
+	// Refresh はリフレッシュトークンを新しいアクセストークンとリフレッシュトークンに交換する
+	// 交換済みのトークンが再び使われた場合は盗まれたものとみなし、同じファミリーのトークンをすべて失効させる
+	// 不正・期限切れ・失効したトークンや、無効化されたユーザーの場合は ErrUnauthenticated を返す
+	Refresh(ctx context.Context, refreshToken string) (*entity.AccessToken, error)
+	// Logout はリフレッシュトークンのファミリーを失効させる。不明なトークンの場合も何もせずに成功する
+	Logout(ctx context.Context, refreshToken string) error
+	// LoginAs は外部の ID プロバイダーなどで本人確認を済ませたユーザーに、新しいファミリーのトークンを発行する
+	// 無効化されたユーザーの場合は ErrUnauthenticated を返す
+	LoginAs(ctx context.Context, user *entity.User) (*entity.AccessToken, error)
+	// Authenticate はアクセストークンを検証し、トークンのユーザーを返す
+	// 不正・期限切れのトークンや、発行後に無効化されたユーザーの場合は ErrUnauthenticated を返す
+	Authenticate(ctx context.Context, token string) (*entity.User, error)
+	// Register はパスワードでログインする有効なユーザーを作成する。ユーザー名が使われている場合は ErrDuplicateEntry を返す
+	Register(ctx context.Context, input RegisterInput) (*entity.User, error)
+	// ChangePassword はユーザーのパスワードを変更する。今のパスワードが違う場合は ErrForbidden を返す
+	ChangePassword(ctx context.Context, userID int64, input ChangePasswordInput) error
+}
+
+type LoginInput struct {
+	UserName string `json:"user_name"`
+	Password string `json:"password"`
+	OTP      string `json:"otp,omitempty"` // 認証アプリのコードかリカバリーコード（二要素認証を有効にしたユーザーのみ）
+}
+
+type RegisterInput struct {
+	UserName    string `json:"user_name"`
+	DisplayName string `json:"display_name"`
+	Email       string `json:"email"`
+	Password    string `json:"password"`
+}
+
+type ChangePasswordInput struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
+type authUsecase struct {
+	users         UserRepository
+	refreshTokens RefreshTokenRepository
+	secondFactor  SecondFactor
+	secret        []byte
+	ttl           time.Duration
+	refreshTTL    time.Duration
+	clock         clock.Clock
+}
+
+// secondFactor が nil の場合は二要素認証を使わない
+func NewAuthUsecase(users UserRepository, refreshTokens RefreshTokenRepository, secondFactor SecondFactor, secret []byte, ttl, refreshTTL time.Duration, clock clock.Clock) AuthUsecase {
+	return &authUsecase{
+		users:         users,
+		refreshTokens: refreshTokens,
+		secondFactor:  secondFactor,
+		secret:        secret,
+		ttl:           ttl,
+		refreshTTL:    refreshTTL,
+		clock:         clock,
+	}
+}
+
+// ユーザーがいない場合も照合にかかる時間をそろえ、ユーザー名の有無を推測させないためのハッシュ値
+var dummyPasswordHash = sync.OnceValue(func() []byte {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("dummy password"), bcrypt.DefaultCost)
+	return hash
+})
+
+func (u *authUsecase) Login(ctx context.Context, input LoginInput) (*entity.AccessToken, error) {
+	user, err := u.findByUserName(ctx, input.UserName)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || user.PasswordHash == "" {
+		_ = bcrypt.CompareHashAndPassword(dummyPasswordHash(), []byte(input.Password))
+		reqctx.Logger(ctx).Info("login failed", "user_name", input.UserName)
+		return nil, domainErrors.ErrUnauthenticated
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(input.Password)); err != nil || !user.Active {
+		reqctx.Logger(ctx).Info("login failed", "user_id", user.ID)
+		return nil, domainErrors.ErrUnauthenticated
+	}
+	if u.secondFactor != nil {
+		if err := u.secondFactor.Check(ctx, user.ID, input.OTP); err != nil {
+			return nil, err
+		}
+	}
+
+	return u.LoginAs(ctx, user)
+}
+
+func (u *authUsecase) LoginAs(ctx context.Context, user *entity.User) (*entity.AccessToken, error) {
+	if !user.Active {
+		reqctx.Logger(ctx).Info("login failed", "user_id", user.ID)
+		return nil, domainErrors.ErrUnauthenticated
+	}
+
+	// ログインのたびに新しいファミリーを始める
+	token, err := u.issue(ctx, user, idgen.NewRandomID())
+	if err != nil {
+		return nil, err
+	}
+
+	reqctx.Logger(ctx).Info("user logged in", "user_id", user.ID)
+	return token, nil
+}
+
+func (u *authUsecase) Refresh(ctx context.Context, refreshToken string) (*entity.AccessToken, error) {
+	stored, err := u.findRefreshToken(ctx, refreshToken)
+	if err != nil {
+		return nil, err
+	}
+	now := u.clock.Now()
+	if stored == nil || !stored.Valid(now) {
+		return nil, fmt.Errorf("%w: invalid or expired refresh token", domainErrors.ErrUnauthenticated)
+	}
+
+	// 同時に交換しようとした場合も、先に記録した 1 つだけを交換する
+	used := false
+	if stored.UsedAt == nil {
+		if used, err = u.refreshTokens.MarkUsed(ctx, stored.ID, now); err != nil {
+			return nil, fmt.Errorf("failed to update refresh token: %w", err)
+		}
+	}
+	if !used {
+		reqctx.Logger(ctx).Warn("refresh token reused; revoking the token family",
+			"user_id", stored.UserID, "refresh_token_id", stored.ID)
+		if err := u.refreshTokens.RevokeFamily(ctx, stored.FamilyID, now); err != nil {
+			return nil, fmt.Errorf("failed to revoke refresh tokens: %w", err)
+		}
+		return nil, fmt.Errorf("%w: refresh token has already been used", domainErrors.ErrUnauthenticated)
+	}
+
+	user, err := u.users.FindByID(ctx, stored.UserID)
+	if err != nil && !errors.Is(err, domainErrors.ErrUserNotFound) {
+		return nil, fmt.Errorf("failed to retrieve user: %w", err)
+	}
+	if user == nil || !user.Active {
+		if err := u.refreshTokens.RevokeFamily(ctx, stored.FamilyID, now); err != nil {
+			return nil, fmt.Errorf("failed to revoke refresh tokens: %w", err)
+		}
+		return nil, domainErrors.ErrUnauthenticated
+	}
+
+	token, err := u.issue(ctx, user, stored.FamilyID)
+	if err != nil {
+		return nil, err
+	}
+
+	reqctx.Logger(ctx).Info("access token refreshed", "user_id", user.ID)
+	return token, nil
+}
+
+func (u *authUsecase) Logout(ctx context.Context, refreshToken string) error {
+	stored, err := u.findRefreshToken(ctx, refreshToken)
+	if err != nil || stored == nil {
+		return err
+	}
+	if err := u.refreshTokens.RevokeFamily(ctx, stored.FamilyID, u.clock.Now()); err != nil {
+		return fmt.Errorf("failed to revoke refresh tokens: %w", err)
+	}
+
+	reqctx.Logger(ctx).Info("user logged out", "user_id", stored.UserID)
+	return nil
+}
+
+// 不明なトークンの場合は nil を返す
+func (u *authUsecase) findRefreshToken(ctx context.Context, refreshToken string) (*entity.RefreshToken, error) {
+	if refreshToken == "" {
+		return nil, nil
+	}
+	stored, err := u.refreshTokens.FindByTokenHash(ctx, entity.HashToken(refreshToken))
+	if errors.Is(err, domainErrors.ErrRefreshTokenNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve refresh token: %w", err)
+	}
+	return stored, nil
+}
+
+// アクセストークンと、ファミリーの新しいリフレッシュトークンを発行する
+func (u *authUsecase) issue(ctx context.Context, user *entity.User, familyID string) (*entity.AccessToken, error) {
+	now := u.clock.Now()
+	expiresAt := now.Add(u.ttl)
+	token, err := jwt.Sign(jwt.Claims{
+		Subject:   strconv.FormatInt(user.ID, 10),
+		IssuedAt:  now.Unix(),
+		ExpiresAt: expiresAt.Unix(),
+		ID:        idgen.NewRandomID(),
+	}, u.secret)
+	if err != nil {
+		return nil, err
+	}
+
+	refresh := entity.NewRefreshToken(user.ID, familyID, RefreshTokenPrefix+idgen.NewRandomID(), u.refreshTTL, now)
+	if err := u.refreshTokens.Create(ctx, refresh); err != nil {
+		return nil, fmt.Errorf("failed to store refresh token: %w", err)
+	}
+
+	return &entity.AccessToken{
+		AccessToken:      token,
+		TokenType:        "Bearer",
+		ExpiresIn:        int(u.ttl.Seconds()),
+		ExpiresAt:        time.Unix(expiresAt.Unix(), 0).UTC(),
+		RefreshToken:     refresh.Token,
+		RefreshExpiresAt: refresh.ExpiresAt.UTC(),
+	}, nil
+}
+
+// ログイン名は大文字小文字を区別しない（DB の照合順序と同じ）
+func (u *authUsecase) findByUserName(ctx context.Context, userName string) (*entity.User, error) {
+	if userName == "" {
+		return nil, nil
+	}
+	users, err := u.users.FindByQuery(ctx, entity.UserQuery{
+		Filter: &filter.Comparison{Field: "user_name", Op: filter.OpEq, Value: userName},
+		Limit:  1,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve user: %w", err)
+	}
+	if len(users) == 0 {
+		return nil, nil
+	}
+	return users[0], nil
+}
+
+// 無効化されたユーザーのトークンは期限内でもすぐに使えなくなるよう、毎回ユーザーを確かめる
+func (u *authUsecase) Authenticate(ctx context.Context, token string) (*entity.User, error) {
+	claims, err := jwt.Verify(token, u.secret, u.clock.Now())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrUnauthenticated, err.Error())
+	}
+	userID, err := strconv.ParseInt(claims.Subject, 10, 64)
+	if err != nil || userID <= 0 {
+		return nil, fmt.Errorf("%w: invalid subject", domainErrors.ErrUnauthenticated)
+	}
+
+	user, err := u.users.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domainErrors.ErrUserNotFound) {
+			return nil, domainErrors.ErrUnauthenticated
+		}
+		return nil, fmt.Errorf("failed to retrieve user: %w", err)
+	}
+	if !user.Active {
+		return nil, domainErrors.ErrUnauthenticated
+	}
+	return user, nil
+}
+
+func (u *authUsecase) Register(ctx context.Context, input RegisterInput) (*entity.User, error) {
+	user, err := entity.NewUserAt(u.clock.Now(), input.UserName, input.DisplayName, input.Email, "", true)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrInvalidInput, err.Error())
+	}
+	if user.PasswordHash, err = hashPassword(input.Password); err != nil {
+		return nil, err
+	}
+
+	if err := u.users.Create(ctx, user); err != nil {
+		return nil, fmt.Errorf("failed to register user: %w", err)
+	}
+
+	reqctx.Logger(ctx).Info("user registered", "user_id", user.ID, "user_name", user.UserName)
+	return user, nil
+}
+
+// パスワードを設定していない（SCIM で作成された）ユーザーは変更できない
+// 発行済みのリフレッシュトークンは失効させる。アクセストークンは有効期限まで使える
+func (u *authUsecase) ChangePassword(ctx context.Context, userID int64, input ChangePasswordInput) error {
+	user, err := u.users.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.PasswordHash == "" || bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(input.CurrentPassword)) != nil {
+		reqctx.Logger(ctx).Info("password change rejected", "user_id", user.ID)
+		return fmt.Errorf("%w: current password is incorrect", domainErrors.ErrForbidden)
+	}
+	if user.PasswordHash, err = hashPassword(input.NewPassword); err != nil {
+		return err
+	}
+	user.UpdatedAt = u.clock.Now()
+
+	if err := u.users.Update(ctx, user); err != nil {
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+
+	if err := u.refreshTokens.RevokeByUser(ctx, user.ID, user.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to revoke refresh tokens: %w", err)
+	}
+
+	reqctx.Logger(ctx).Info("password changed", "user_id", user.ID)
+	return nil
+}
+
+// パスワードを検証して bcrypt のハッシュ値にする
+func hashPassword(password string) (string, error) {
+	if err := entity.ValidatePassword(password); err != nil {
+		return "", fmt.Errorf("%w: %s", domainErrors.ErrInvalidInput, err.Error())
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash password: %w", err)
+	}
+	return string(hash), nil
+}
@@ -0,0 +1,484 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/pkg/clock"
+)
+
+var testJWTSecret = []byte("0123456789abcdef0123456789abcdef")
+
+// MockRefreshTokenRepository はテスト用のリフレッシュトークンリポジトリ
+type MockRefreshTokenRepository struct {
+	mock.Mock
+}
+
+func (m *MockRefreshTokenRepository) Create(ctx context.Context, token *entity.RefreshToken) error {
+	args := m.Called(ctx, token)
+	return args.Error(0)
+}
+
+func (m *MockRefreshTokenRepository) FindByTokenHash(ctx context.Context, tokenHash string) (*entity.RefreshToken, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.RefreshToken), args.Error(1)
+}
+
+func (m *MockRefreshTokenRepository) MarkUsed(ctx context.Context, id int64, at time.Time) (bool, error) {
+	args := m.Called(ctx, id, at)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRefreshTokenRepository) RevokeFamily(ctx context.Context, familyID string, at time.Time) error {
+	args := m.Called(ctx, familyID, at)
+	return args.Error(0)
+}
+
+func (m *MockRefreshTokenRepository) RevokeByUser(ctx context.Context, userID int64, at time.Time) error {
+	args := m.Called(ctx, userID, at)
+	return args.Error(0)
+}
+
+// 発行したリフレッシュトークンをそのまま保存する
+func acceptingRefreshTokens() *MockRefreshTokenRepository {
+	tokens := new(MockRefreshTokenRepository)
+	tokens.On("Create", mock.Anything, mock.Anything).Return(nil).Maybe()
+	return tokens
+}
+
+func testUserWithPassword(t *testing.T, password string) *entity.User {
+	t.Helper()
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	require.NoError(t, err)
+	return &entity.User{ID: 7, UserName: "yamada", Active: true, PasswordHash: string(hash)}
+}
+
+func TestAuthUsecase_Login(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		input     LoginInput
+		setupMock func(*MockUserRepository)
+		wantErr   error
+	}{
+		{
+			name:  "正常系: 正しいパスワードでトークンを発行",
+			input: LoginInput{UserName: "yamada", Password: "correct horse"},
+			setupMock: func(m *MockUserRepository) {
+				m.On("FindByQuery", mock.Anything, mock.Anything).Return([]*entity.User{testUserWithPassword(t, "correct horse")}, nil)
+			},
+		},
+		{
+			name:  "異常系: パスワードが違う",
+			input: LoginInput{UserName: "yamada", Password: "wrong password"},
+			setupMock: func(m *MockUserRepository) {
+				m.On("FindByQuery", mock.Anything, mock.Anything).Return([]*entity.User{testUserWithPassword(t, "correct horse")}, nil)
+			},
+			wantErr: domainErrors.ErrUnauthenticated,
+		},
+		{
+			name:  "異常系: ユーザーがいない",
+			input: LoginInput{UserName: "nobody", Password: "correct horse"},
+			setupMock: func(m *MockUserRepository) {
+				m.On("FindByQuery", mock.Anything, mock.Anything).Return([]*entity.User{}, nil)
+			},
+			wantErr: domainErrors.ErrUnauthenticated,
+		},
+		{
+			name:  "異常系: パスワード未設定のユーザー",
+			input: LoginInput{UserName: "yamada", Password: "correct horse"},
+			setupMock: func(m *MockUserRepository) {
+				m.On("FindByQuery", mock.Anything, mock.Anything).Return([]*entity.User{{ID: 7, UserName: "yamada", Active: true}}, nil)
+			},
+			wantErr: domainErrors.ErrUnauthenticated,
+		},
+		{
+			name:  "異常系: 無効化されたユーザー",
+			input: LoginInput{UserName: "yamada", Password: "correct horse"},
+			setupMock: func(m *MockUserRepository) {
+				user := testUserWithPassword(t, "correct horse")
+				user.Active = false
+				m.On("FindByQuery", mock.Anything, mock.Anything).Return([]*entity.User{user}, nil)
+			},
+			wantErr: domainErrors.ErrUnauthenticated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users := new(MockUserRepository)
+			tt.setupMock(users)
+			usecase := NewAuthUsecase(users, acceptingRefreshTokens(), nil, testJWTSecret, time.Hour, 24*time.Hour, clock.NewFrozen(now))
+
+			token, err := usecase.Login(context.Background(), tt.input)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, token)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "Bearer", token.TokenType)
+			assert.Equal(t, 3600, token.ExpiresIn)
+			assert.Equal(t, now.Add(time.Hour), token.ExpiresAt)
+			assert.True(t, strings.HasPrefix(token.RefreshToken, RefreshTokenPrefix))
+			assert.Equal(t, now.Add(24*time.Hour), token.RefreshExpiresAt)
+			users.AssertExpectations(t)
+		})
+	}
+}
+
+// 決まったエラーを返す 2 つ目の要素
+type stubSecondFactor struct {
+	err error
+}
+
+func (s stubSecondFactor) Check(ctx context.Context, userID int64, code string) error {
+	return s.err
+}
+
+func TestAuthUsecase_Login_SecondFactor(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("異常系: 二要素認証のコードがない", func(t *testing.T) {
+		users := new(MockUserRepository)
+		users.On("FindByQuery", mock.Anything, mock.Anything).Return([]*entity.User{testUserWithPassword(t, "correct horse")}, nil)
+		refreshTokens := new(MockRefreshTokenRepository)
+		usecase := NewAuthUsecase(users, refreshTokens, stubSecondFactor{domainErrors.ErrTwoFactorRequired}, testJWTSecret, time.Hour, 24*time.Hour, clock.NewFrozen(now))
+
+		token, err := usecase.Login(context.Background(), LoginInput{UserName: "yamada", Password: "correct horse"})
+
+		assert.ErrorIs(t, err, domainErrors.ErrTwoFactorRequired)
+		assert.Nil(t, token)
+		refreshTokens.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("異常系: パスワードが違う場合はコードを確かめない", func(t *testing.T) {
+		users := new(MockUserRepository)
+		users.On("FindByQuery", mock.Anything, mock.Anything).Return([]*entity.User{testUserWithPassword(t, "correct horse")}, nil)
+		usecase := NewAuthUsecase(users, acceptingRefreshTokens(), stubSecondFactor{domainErrors.ErrTwoFactorRequired}, testJWTSecret, time.Hour, 24*time.Hour, clock.NewFrozen(now))
+
+		_, err := usecase.Login(context.Background(), LoginInput{UserName: "yamada", Password: "wrong password"})
+
+		assert.ErrorIs(t, err, domainErrors.ErrUnauthenticated)
+	})
+
+	t.Run("正常系: コードを確かめてトークンを発行", func(t *testing.T) {
+		users := new(MockUserRepository)
+		users.On("FindByQuery", mock.Anything, mock.Anything).Return([]*entity.User{testUserWithPassword(t, "correct horse")}, nil)
+		usecase := NewAuthUsecase(users, acceptingRefreshTokens(), stubSecondFactor{}, testJWTSecret, time.Hour, 24*time.Hour, clock.NewFrozen(now))
+
+		token, err := usecase.Login(context.Background(), LoginInput{UserName: "yamada", Password: "correct horse", OTP: "123456"})
+
+		require.NoError(t, err)
+		assert.NotEmpty(t, token.AccessToken)
+	})
+}
+
+func TestAuthUsecase_Authenticate(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	user := testUserWithPassword(t, "correct horse")
+
+	issue := func(t *testing.T, clk clock.Clock) string {
+		users := new(MockUserRepository)
+		users.On("FindByQuery", mock.Anything, mock.Anything).Return([]*entity.User{user}, nil)
+		token, err := NewAuthUsecase(users, acceptingRefreshTokens(), nil, testJWTSecret, time.Hour, 24*time.Hour, clk).Login(context.Background(), LoginInput{UserName: "yamada", Password: "correct horse"})
+		require.NoError(t, err)
+		return token.AccessToken
+	}
+
+	t.Run("正常系: トークンのユーザーを返す", func(t *testing.T) {
+		clk := clock.NewFrozen(now)
+		token := issue(t, clk)
+		users := new(MockUserRepository)
+		users.On("FindByID", mock.Anything, int64(7)).Return(user, nil)
+
+		got, err := NewAuthUsecase(users, acceptingRefreshTokens(), nil, testJWTSecret, time.Hour, 24*time.Hour, clk).Authenticate(context.Background(), token)
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(7), got.ID)
+	})
+
+	t.Run("異常系: 期限切れのトークン", func(t *testing.T) {
+		clk := clock.NewFrozen(now)
+		token := issue(t, clk)
+		clk.Advance(time.Hour)
+
+		_, err := NewAuthUsecase(new(MockUserRepository), acceptingRefreshTokens(), nil, testJWTSecret, time.Hour, 24*time.Hour, clk).Authenticate(context.Background(), token)
+
+		assert.ErrorIs(t, err, domainErrors.ErrUnauthenticated)
+	})
+
+	t.Run("異常系: 発行後に無効化されたユーザー", func(t *testing.T) {
+		clk := clock.NewFrozen(now)
+		token := issue(t, clk)
+		deactivated := *user
+		deactivated.Active = false
+		users := new(MockUserRepository)
+		users.On("FindByID", mock.Anything, int64(7)).Return(&deactivated, nil)
+
+		_, err := NewAuthUsecase(users, acceptingRefreshTokens(), nil, testJWTSecret, time.Hour, 24*time.Hour, clk).Authenticate(context.Background(), token)
+
+		assert.ErrorIs(t, err, domainErrors.ErrUnauthenticated)
+	})
+
+	t.Run("異常系: 削除されたユーザー", func(t *testing.T) {
+		clk := clock.NewFrozen(now)
+		token := issue(t, clk)
+		users := new(MockUserRepository)
+		users.On("FindByID", mock.Anything, int64(7)).Return(nil, domainErrors.ErrUserNotFound)
+
+		_, err := NewAuthUsecase(users, acceptingRefreshTokens(), nil, testJWTSecret, time.Hour, 24*time.Hour, clk).Authenticate(context.Background(), token)
+
+		assert.ErrorIs(t, err, domainErrors.ErrUnauthenticated)
+	})
+
+	t.Run("異常系: 別の鍵で署名されたトークン", func(t *testing.T) {
+		clk := clock.NewFrozen(now)
+		token := issue(t, clk)
+
+		_, err := NewAuthUsecase(new(MockUserRepository), acceptingRefreshTokens(), nil, []byte("another secret of enough length!"), time.Hour, 24*time.Hour, clk).Authenticate(context.Background(), token)
+
+		assert.ErrorIs(t, err, domainErrors.ErrUnauthenticated)
+	})
+}
+
+func TestAuthUsecase_Register(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		input     RegisterInput
+		setupMock func(*MockUserRepository)
+		wantErr   error
+	}{
+		{
+			name:  "正常系: 有効なユーザーをパスワード付きで作成",
+			input: RegisterInput{UserName: "yamada", Email: "yamada@example.com", Password: "correct horse"},
+			setupMock: func(m *MockUserRepository) {
+				m.On("Create", mock.Anything, mock.MatchedBy(func(user *entity.User) bool {
+					return user.Active && bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte("correct horse")) == nil
+				})).Return(nil)
+			},
+		},
+		{
+			name:      "異常系: パスワードが短い",
+			input:     RegisterInput{UserName: "yamada", Password: "short"},
+			setupMock: func(m *MockUserRepository) {},
+			wantErr:   domainErrors.ErrInvalidInput,
+		},
+		{
+			name:      "異常系: ユーザー名がない",
+			input:     RegisterInput{Password: "correct horse"},
+			setupMock: func(m *MockUserRepository) {},
+			wantErr:   domainErrors.ErrInvalidInput,
+		},
+		{
+			name:  "異常系: ユーザー名が使われている",
+			input: RegisterInput{UserName: "yamada", Password: "correct horse"},
+			setupMock: func(m *MockUserRepository) {
+				m.On("Create", mock.Anything, mock.Anything).Return(domainErrors.ErrDuplicateEntry)
+			},
+			wantErr: domainErrors.ErrDuplicateEntry,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users := new(MockUserRepository)
+			tt.setupMock(users)
+			usecase := NewAuthUsecase(users, acceptingRefreshTokens(), nil, testJWTSecret, time.Hour, 24*time.Hour, clock.NewFrozen(now))
+
+			user, err := usecase.Register(context.Background(), tt.input)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "yamada", user.UserName)
+			users.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuthUsecase_ChangePassword(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		user    func(t *testing.T) *entity.User
+		input   ChangePasswordInput
+		wantErr error
+	}{
+		{
+			name:  "正常系: 今のパスワードが正しい",
+			user:  func(t *testing.T) *entity.User { return testUserWithPassword(t, "correct horse") },
+			input: ChangePasswordInput{CurrentPassword: "correct horse", NewPassword: "battery staple"},
+		},
+		{
+			name:    "異常系: 今のパスワードが違う",
+			user:    func(t *testing.T) *entity.User { return testUserWithPassword(t, "correct horse") },
+			input:   ChangePasswordInput{CurrentPassword: "wrong password", NewPassword: "battery staple"},
+			wantErr: domainErrors.ErrForbidden,
+		},
+		{
+			name:    "異常系: パスワードを設定していないユーザー",
+			user:    func(t *testing.T) *entity.User { return &entity.User{ID: 7, UserName: "yamada", Active: true} },
+			input:   ChangePasswordInput{CurrentPassword: "", NewPassword: "battery staple"},
+			wantErr: domainErrors.ErrForbidden,
+		},
+		{
+			name:    "異常系: 新しいパスワードが短い",
+			user:    func(t *testing.T) *entity.User { return testUserWithPassword(t, "correct horse") },
+			input:   ChangePasswordInput{CurrentPassword: "correct horse", NewPassword: "short"},
+			wantErr: domainErrors.ErrInvalidInput,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users := new(MockUserRepository)
+			users.On("FindByID", mock.Anything, int64(7)).Return(tt.user(t), nil)
+			if tt.wantErr == nil {
+				users.On("Update", mock.Anything, mock.MatchedBy(func(user *entity.User) bool {
+					return bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(tt.input.NewPassword)) == nil
+				})).Return(nil)
+			}
+			refreshTokens := new(MockRefreshTokenRepository)
+			refreshTokens.On("RevokeByUser", mock.Anything, int64(7), now).Return(nil).Maybe()
+			usecase := NewAuthUsecase(users, refreshTokens, nil, testJWTSecret, time.Hour, 24*time.Hour, clock.NewFrozen(now))
+
+			err := usecase.ChangePassword(context.Background(), 7, tt.input)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				users.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+				refreshTokens.AssertNotCalled(t, "RevokeByUser", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			users.AssertExpectations(t)
+			refreshTokens.AssertCalled(t, "RevokeByUser", mock.Anything, int64(7), now)
+		})
+	}
+}
+
+func TestAuthUsecase_Refresh(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	user := &entity.User{ID: 7, UserName: "yamada", Active: true}
+	hash := entity.HashToken("rt_current")
+	stored := func() *entity.RefreshToken {
+		return &entity.RefreshToken{ID: 3, UserID: 7, FamilyID: "family-1", TokenHash: hash, CreatedAt: now.Add(-time.Hour), ExpiresAt: now.Add(time.Hour)}
+	}
+
+	t.Run("正常系: 同じファミリーの新しいトークンと交換する", func(t *testing.T) {
+		refreshTokens := new(MockRefreshTokenRepository)
+		refreshTokens.On("FindByTokenHash", mock.Anything, hash).Return(stored(), nil)
+		refreshTokens.On("MarkUsed", mock.Anything, int64(3), now).Return(true, nil)
+		refreshTokens.On("Create", mock.Anything, mock.MatchedBy(func(token *entity.RefreshToken) bool {
+			return token.UserID == 7 && token.FamilyID == "family-1" && token.ExpiresAt.Equal(now.Add(24*time.Hour))
+		})).Return(nil)
+		users := new(MockUserRepository)
+		users.On("FindByID", mock.Anything, int64(7)).Return(user, nil)
+
+		token, err := NewAuthUsecase(users, refreshTokens, nil, testJWTSecret, time.Hour, 24*time.Hour, clock.NewFrozen(now)).Refresh(context.Background(), "rt_current")
+		require.NoError(t, err)
+		assert.NotEmpty(t, token.AccessToken)
+		assert.NotEqual(t, "rt_current", token.RefreshToken)
+		refreshTokens.AssertExpectations(t)
+	})
+
+	t.Run("異常系: 交換済みのトークンの再利用でファミリーを失効させる", func(t *testing.T) {
+		used := stored()
+		usedAt := now.Add(-time.Minute)
+		used.UsedAt = &usedAt
+		refreshTokens := new(MockRefreshTokenRepository)
+		refreshTokens.On("FindByTokenHash", mock.Anything, hash).Return(used, nil)
+		refreshTokens.On("RevokeFamily", mock.Anything, "family-1", now).Return(nil)
+
+		_, err := NewAuthUsecase(new(MockUserRepository), refreshTokens, nil, testJWTSecret, time.Hour, 24*time.Hour, clock.NewFrozen(now)).Refresh(context.Background(), "rt_current")
+		assert.ErrorIs(t, err, domainErrors.ErrUnauthenticated)
+		refreshTokens.AssertExpectations(t)
+		refreshTokens.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("異常系: 同時に交換され、先に使われていた", func(t *testing.T) {
+		refreshTokens := new(MockRefreshTokenRepository)
+		refreshTokens.On("FindByTokenHash", mock.Anything, hash).Return(stored(), nil)
+		refreshTokens.On("MarkUsed", mock.Anything, int64(3), now).Return(false, nil)
+		refreshTokens.On("RevokeFamily", mock.Anything, "family-1", now).Return(nil)
+
+		_, err := NewAuthUsecase(new(MockUserRepository), refreshTokens, nil, testJWTSecret, time.Hour, 24*time.Hour, clock.NewFrozen(now)).Refresh(context.Background(), "rt_current")
+		assert.ErrorIs(t, err, domainErrors.ErrUnauthenticated)
+		refreshTokens.AssertExpectations(t)
+	})
+
+	t.Run("異常系: 期限切れのトークン", func(t *testing.T) {
+		expired := stored()
+		expired.ExpiresAt = now
+		refreshTokens := new(MockRefreshTokenRepository)
+		refreshTokens.On("FindByTokenHash", mock.Anything, hash).Return(expired, nil)
+
+		_, err := NewAuthUsecase(new(MockUserRepository), refreshTokens, nil, testJWTSecret, time.Hour, 24*time.Hour, clock.NewFrozen(now)).Refresh(context.Background(), "rt_current")
+		assert.ErrorIs(t, err, domainErrors.ErrUnauthenticated)
+		refreshTokens.AssertNotCalled(t, "MarkUsed", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("異常系: 不明なトークン", func(t *testing.T) {
+		refreshTokens := new(MockRefreshTokenRepository)
+		refreshTokens.On("FindByTokenHash", mock.Anything, mock.Anything).Return(nil, domainErrors.ErrRefreshTokenNotFound)
+
+		_, err := NewAuthUsecase(new(MockUserRepository), refreshTokens, nil, testJWTSecret, time.Hour, 24*time.Hour, clock.NewFrozen(now)).Refresh(context.Background(), "rt_unknown")
+		assert.ErrorIs(t, err, domainErrors.ErrUnauthenticated)
+	})
+
+	t.Run("異常系: 無効化されたユーザー", func(t *testing.T) {
+		refreshTokens := new(MockRefreshTokenRepository)
+		refreshTokens.On("FindByTokenHash", mock.Anything, hash).Return(stored(), nil)
+		refreshTokens.On("MarkUsed", mock.Anything, int64(3), now).Return(true, nil)
+		refreshTokens.On("RevokeFamily", mock.Anything, "family-1", now).Return(nil)
+		users := new(MockUserRepository)
+		users.On("FindByID", mock.Anything, int64(7)).Return(&entity.User{ID: 7, UserName: "yamada", Active: false}, nil)
+
+		_, err := NewAuthUsecase(users, refreshTokens, nil, testJWTSecret, time.Hour, 24*time.Hour, clock.NewFrozen(now)).Refresh(context.Background(), "rt_current")
+		assert.ErrorIs(t, err, domainErrors.ErrUnauthenticated)
+		refreshTokens.AssertExpectations(t)
+	})
+}
+
+func TestAuthUsecase_Logout(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("正常系: トークンのファミリーを失効させる", func(t *testing.T) {
+		refreshTokens := new(MockRefreshTokenRepository)
+		refreshTokens.On("FindByTokenHash", mock.Anything, entity.HashToken("rt_current")).Return(&entity.RefreshToken{ID: 3, UserID: 7, FamilyID: "family-1"}, nil)
+		refreshTokens.On("RevokeFamily", mock.Anything, "family-1", now).Return(nil)
+
+		err := NewAuthUsecase(new(MockUserRepository), refreshTokens, nil, testJWTSecret, time.Hour, 24*time.Hour, clock.NewFrozen(now)).Logout(context.Background(), "rt_current")
+		require.NoError(t, err)
+		refreshTokens.AssertExpectations(t)
+	})
+
+	t.Run("正常系: 不明なトークンは何もしない", func(t *testing.T) {
+		refreshTokens := new(MockRefreshTokenRepository)
+		refreshTokens.On("FindByTokenHash", mock.Anything, mock.Anything).Return(nil, domainErrors.ErrRefreshTokenNotFound)
+
+		err := NewAuthUsecase(new(MockUserRepository), refreshTokens, nil, testJWTSecret, time.Hour, 24*time.Hour, clock.NewFrozen(now)).Logout(context.Background(), "rt_unknown")
+		require.NoError(t, err)
+		refreshTokens.AssertNotCalled(t, "RevokeFamily", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,153 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"time"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/pkg/clock"
+	"Aicon-assignment/internal/pkg/reqctx"
+)
+
+// 期限切れのファイルを一度に読み込む件数
+const expiredBlobBatchSize = 100
+
+type BlobUsecase interface {
+	// Put はファイルをストアに書き、サイズと SHA-256 を記録する。expiresAt が nil の場合は削除するまで残す
+	Put(ctx context.Context, key string, content io.Reader, expiresAt *time.Time) (*entity.Blob, error)
+	// Open はファイルを開く。最後まで読んだときに記録した SHA-256 と一致しなければ ErrChecksumMismatch を返す
+	Open(ctx context.Context, key string) (io.ReadCloser, *entity.Blob, error)
+	Delete(ctx context.Context, key string) error
+	// DeleteExpired は期限を過ぎたファイルを削除し、削除した件数を返す
+	DeleteExpired(ctx context.Context) (int64, error)
+}
+
+type blobUsecase struct {
+	store BlobStore
+	blobs BlobRepository
+	clock clock.Clock
+}
+
+func NewBlobUsecase(store BlobStore, blobs BlobRepository, clock clock.Clock) BlobUsecase {
+	return &blobUsecase{
+		store: store,
+		blobs: blobs,
+		clock: clock,
+	}
+}
+
+// 書きながら SHA-256 を求める。記録に失敗した場合は書いたファイルも消す
+func (u *blobUsecase) Put(ctx context.Context, key string, content io.Reader, expiresAt *time.Time) (*entity.Blob, error) {
+	if key == "" {
+		return nil, fmt.Errorf("%w: key is required", domainErrors.ErrInvalidInput)
+	}
+
+	counter := &countingHash{hash: sha256.New()}
+	if err := u.store.Put(ctx, key, io.TeeReader(content, counter)); err != nil {
+		return nil, fmt.Errorf("failed to store %s: %w", key, err)
+	}
+
+	blob := &entity.Blob{
+		Key:       key,
+		Location:  u.store.Location(key),
+		Size:      counter.size,
+		SHA256:    hex.EncodeToString(counter.hash.Sum(nil)),
+		CreatedAt: u.clock.Now(),
+		ExpiresAt: expiresAt,
+	}
+	if err := u.blobs.Save(ctx, blob); err != nil {
+		if deleteErr := u.store.Delete(ctx, key); deleteErr != nil {
+			reqctx.Logger(ctx).Error("failed to delete unrecorded file", "key", key, "error", deleteErr)
+		}
+		return nil, fmt.Errorf("failed to record %s: %w", key, err)
+	}
+	return blob, nil
+}
+
+// 期限を過ぎたファイルは削除前でも見つからないものとして扱う
+func (u *blobUsecase) Open(ctx context.Context, key string) (io.ReadCloser, *entity.Blob, error) {
+	blob, err := u.blobs.FindByKey(ctx, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	if blob.ExpiredAt(u.clock.Now()) {
+		return nil, nil, domainErrors.ErrBlobNotFound
+	}
+
+	body, err := u.store.Get(ctx, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &verifyingReader{body: body, hash: sha256.New(), want: blob.SHA256}, blob, nil
+}
+
+func (u *blobUsecase) Delete(ctx context.Context, key string) error {
+	if err := u.store.Delete(ctx, key); err != nil {
+		return fmt.Errorf("failed to delete %s: %w", key, err)
+	}
+	if err := u.blobs.Delete(ctx, key); err != nil {
+		return fmt.Errorf("failed to delete record of %s: %w", key, err)
+	}
+	return nil
+}
+
+// ファイルを消してから記録を消すため、途中で失敗しても次の実行でやり直せる
+func (u *blobUsecase) DeleteExpired(ctx context.Context) (int64, error) {
+	now := u.clock.Now()
+	var deleted int64
+	for {
+		blobs, err := u.blobs.FindExpired(ctx, now, expiredBlobBatchSize)
+		if err != nil {
+			return deleted, fmt.Errorf("failed to find expired files: %w", err)
+		}
+		for _, blob := range blobs {
+			if err := u.Delete(ctx, blob.Key); err != nil {
+				return deleted, err
+			}
+			deleted++
+		}
+		if len(blobs) < expiredBlobBatchSize {
+			if deleted > 0 {
+				reqctx.Logger(ctx).Info("expired files deleted", "count", deleted)
+			}
+			return deleted, nil
+		}
+	}
+}
+
+type countingHash struct {
+	hash hash.Hash
+	size int64
+}
+
+func (c *countingHash) Write(p []byte) (int, error) {
+	c.size += int64(len(p))
+	return c.hash.Write(p)
+}
+
+// 読んだ内容の SHA-256 を求め、最後まで読んだところで記録と比べる
+type verifyingReader struct {
+	body io.ReadCloser
+	hash hash.Hash
+	want string
+}
+
+func (r *verifyingReader) Read(p []byte) (int, error) {
+	n, err := r.body.Read(p)
+	r.hash.Write(p[:n])
+	if errors.Is(err, io.EOF) && hex.EncodeToString(r.hash.Sum(nil)) != r.want {
+		return n, domainErrors.ErrChecksumMismatch
+	}
+	return n, err
+}
+
+func (r *verifyingReader) Close() error {
+	return r.body.Close()
+}
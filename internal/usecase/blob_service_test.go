@@ -0,0 +1,175 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/pkg/clock"
+)
+
+// MockBlobRepository はテスト用のファイルの記録
+type MockBlobRepository struct {
+	mock.Mock
+}
+
+func (m *MockBlobRepository) Save(ctx context.Context, blob *entity.Blob) error {
+	args := m.Called(ctx, blob)
+	return args.Error(0)
+}
+
+func (m *MockBlobRepository) FindByKey(ctx context.Context, key string) (*entity.Blob, error) {
+	args := m.Called(ctx, key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Blob), args.Error(1)
+}
+
+func (m *MockBlobRepository) FindExpired(ctx context.Context, now time.Time, limit int) ([]*entity.Blob, error) {
+	args := m.Called(ctx, now, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.Blob), args.Error(1)
+}
+
+func (m *MockBlobRepository) Delete(ctx context.Context, key string) error {
+	args := m.Called(ctx, key)
+	return args.Error(0)
+}
+
+// memoryBlobStore はテスト用のファイルストア
+type memoryBlobStore struct {
+	objects map[string][]byte
+}
+
+func (s *memoryBlobStore) Put(ctx context.Context, key string, content io.Reader) error {
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return err
+	}
+	s.objects[key] = data
+	return nil
+}
+
+func (s *memoryBlobStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	data, ok := s.objects[key]
+	if !ok {
+		return nil, domainErrors.ErrBlobNotFound
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (s *memoryBlobStore) Delete(ctx context.Context, key string) error {
+	delete(s.objects, key)
+	return nil
+}
+
+func (s *memoryBlobStore) Location(key string) string {
+	return "mem://" + key
+}
+
+func TestBlobUsecase(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("正常系: 書いた内容のサイズと SHA-256 を記録する", func(t *testing.T) {
+		store := &memoryBlobStore{objects: map[string][]byte{}}
+		blobs := new(MockBlobRepository)
+		blobs.On("Save", mock.Anything, mock.Anything).Return(nil)
+
+		blob, err := NewBlobUsecase(store, blobs, clock.NewFrozen(now)).Put(ctx, "exports/a.parquet", strings.NewReader("PAR1"), nil)
+		require.NoError(t, err)
+		assert.Equal(t, int64(4), blob.Size)
+		assert.Len(t, blob.SHA256, 64)
+		assert.Equal(t, "mem://exports/a.parquet", blob.Location)
+		assert.Equal(t, []byte("PAR1"), store.objects["exports/a.parquet"])
+	})
+
+	t.Run("異常系: 記録に失敗した場合は書いたファイルを消す", func(t *testing.T) {
+		store := &memoryBlobStore{objects: map[string][]byte{}}
+		blobs := new(MockBlobRepository)
+		blobs.On("Save", mock.Anything, mock.Anything).Return(domainErrors.ErrDatabaseError)
+
+		_, err := NewBlobUsecase(store, blobs, clock.NewFrozen(now)).Put(ctx, "exports/a.parquet", strings.NewReader("PAR1"), nil)
+		assert.ErrorIs(t, err, domainErrors.ErrDatabaseError)
+		assert.Empty(t, store.objects)
+	})
+
+	t.Run("異常系: ストアの内容が記録と異なる場合は最後まで読んだところでエラー", func(t *testing.T) {
+		store := &memoryBlobStore{objects: map[string][]byte{"a": []byte("tampered")}}
+		blobs := new(MockBlobRepository)
+		blobs.On("FindByKey", mock.Anything, "a").Return(&entity.Blob{Key: "a", SHA256: strings.Repeat("0", 64)}, nil)
+
+		body, _, err := NewBlobUsecase(store, blobs, clock.NewFrozen(now)).Open(ctx, "a")
+		require.NoError(t, err)
+		_, err = io.ReadAll(body)
+		assert.ErrorIs(t, err, domainErrors.ErrChecksumMismatch)
+	})
+
+	t.Run("正常系: 記録と一致する内容はそのまま読める", func(t *testing.T) {
+		store := &memoryBlobStore{objects: map[string][]byte{}}
+		blobs := new(MockBlobRepository)
+		var saved *entity.Blob
+		blobs.On("Save", mock.Anything, mock.Anything).Run(func(args mock.Arguments) { saved = args.Get(1).(*entity.Blob) }).Return(nil)
+		u := NewBlobUsecase(store, blobs, clock.NewFrozen(now))
+		_, err := u.Put(ctx, "a", strings.NewReader("PAR1"), nil)
+		require.NoError(t, err)
+		blobs.On("FindByKey", mock.Anything, "a").Return(saved, nil)
+
+		body, _, err := u.Open(ctx, "a")
+		require.NoError(t, err)
+		data, err := io.ReadAll(body)
+		require.NoError(t, err)
+		assert.Equal(t, "PAR1", string(data))
+	})
+
+	t.Run("異常系: 期限を過ぎたファイルは削除前でも開けない", func(t *testing.T) {
+		expired := now.Add(-time.Hour)
+		blobs := new(MockBlobRepository)
+		blobs.On("FindByKey", mock.Anything, "a").Return(&entity.Blob{Key: "a", ExpiresAt: &expired}, nil)
+
+		_, _, err := NewBlobUsecase(&memoryBlobStore{}, blobs, clock.NewFrozen(now)).Open(ctx, "a")
+		assert.ErrorIs(t, err, domainErrors.ErrBlobNotFound)
+	})
+
+	t.Run("正常系: 期限を過ぎたファイルとその記録を削除する", func(t *testing.T) {
+		store := &memoryBlobStore{objects: map[string][]byte{"a": []byte("x"), "b": []byte("y")}}
+		blobs := new(MockBlobRepository)
+		blobs.On("FindExpired", mock.Anything, now, expiredBlobBatchSize).Return([]*entity.Blob{{Key: "a"}}, nil)
+		blobs.On("Delete", mock.Anything, "a").Return(nil)
+
+		deleted, err := NewBlobUsecase(store, blobs, clock.NewFrozen(now)).DeleteExpired(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), deleted)
+		assert.Equal(t, map[string][]byte{"b": []byte("y")}, store.objects)
+	})
+
+	t.Run("異常系: ファイルを消せない場合は記録を残す", func(t *testing.T) {
+		blobs := new(MockBlobRepository)
+		blobs.On("FindExpired", mock.Anything, now, expiredBlobBatchSize).Return([]*entity.Blob{{Key: "a"}}, nil)
+
+		_, err := NewBlobUsecase(&failingBlobStore{}, blobs, clock.NewFrozen(now)).DeleteExpired(ctx)
+		assert.Error(t, err)
+		blobs.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+}
+
+type failingBlobStore struct {
+	memoryBlobStore
+}
+
+func (failingBlobStore) Delete(ctx context.Context, key string) error {
+	return errors.New("access denied")
+}
@@ -0,0 +1,29 @@
+package usecase
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// BlobStore is where exports, backups and attachments are written (local disk, S3, GCS)
+type BlobStore interface {
+	// Put writes content under key, replacing any existing object
+	Put(ctx context.Context, key string, content io.Reader) error
+
+	// Get returns domainErrors.ErrBlobNotFound if there is no object for the key
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+
+	// Delete removes the object; it is not an error if there is none
+	Delete(ctx context.Context, key string) error
+
+	// Location returns where the object for key is stored ("s3://bucket/key" etc.)
+	Location(key string) string
+}
+
+// BlobURLSigner is implemented by blob stores that can issue time-limited download URLs (S3 presigned URLs),
+// so clients can fetch objects directly instead of through the API server
+type BlobURLSigner interface {
+	// PresignGet returns a URL that allows anyone to GET the object for expiresIn from now
+	PresignGet(key string, expiresIn time.Duration, now time.Time) (string, error)
+}
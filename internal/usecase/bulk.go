@@ -0,0 +1,305 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/pkg/reqctx"
+)
+
+// 一括登録で一度に受け付ける行数の上限
+const MaxBulkCreateItems = 500
+
+// 一括登録の結果。行は入力の配列のインデックス（0 始まり）で示す
+type BulkCreateResult struct {
+	Atomic  bool             `json:"atomic"`
+	Created []BulkCreatedRow `json:"created"`
+	Errors  []BulkRowError   `json:"errors"`
+}
+
+type BulkCreatedRow struct {
+	Index int   `json:"index"`
+	ID    int64 `json:"id"`
+}
+
+type BulkRowError struct {
+	Index  int      `json:"index"`
+	Errors []string `json:"errors"`
+}
+
+// 複数のアイテムをまとめて登録する
+// atomic が true の場合、1行でも問題があればどの行も登録せず、登録は1つのトランザクションで行う
+// false の場合は問題のない行だけを登録し、問題のある行はエラーとして返す
+func (u *itemUsecase) CreateItems(ctx context.Context, inputs []CreateItemInput, atomic bool) (*BulkCreateResult, error) {
+	if len(inputs) == 0 {
+		return nil, fmt.Errorf("%w: at least one item is required", domainErrors.ErrInvalidInput)
+	}
+	if len(inputs) > MaxBulkCreateItems {
+		return nil, fmt.Errorf("%w: at most %d items can be created at once", domainErrors.ErrInvalidInput, MaxBulkCreateItems)
+	}
+
+	result := &BulkCreateResult{Atomic: atomic, Created: []BulkCreatedRow{}, Errors: []BulkRowError{}}
+	items := make([]*entity.Item, len(inputs))
+	for i, input := range inputs {
+		item, problems, err := u.newItem(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		if len(problems) > 0 {
+			result.Errors = append(result.Errors, BulkRowError{Index: i, Errors: problemMessages(problems)})
+			continue
+		}
+		items[i] = item
+	}
+
+	if atomic {
+		if len(result.Errors) > 0 {
+			return result, nil
+		}
+		return u.createAll(ctx, items, result)
+	}
+
+	for i, item := range items {
+		if item == nil {
+			continue
+		}
+		created, err := u.itemRepo.Create(ctx, item)
+		if err != nil {
+			reqctx.Logger(ctx).Error("failed to create item in bulk", "index", i, "error", err)
+			result.Errors = append(result.Errors, BulkRowError{Index: i, Errors: []string{"failed to create item"}})
+			continue
+		}
+		u.recordAudit(ctx, entity.AuditActionItemCreate, created.ID, "")
+		u.publish(ctx, entity.EventItemCreated, created)
+		result.Created = append(result.Created, BulkCreatedRow{Index: i, ID: created.ID})
+	}
+	return result, nil
+}
+
+// すべての行を1つのトランザクションで登録する
+func (u *itemUsecase) createAll(ctx context.Context, items []*entity.Item, result *BulkCreateResult) (*BulkCreateResult, error) {
+	created := make([]*entity.Item, 0, len(items))
+	err := u.transactor.Transaction(ctx, func(ctx context.Context) error {
+		created = created[:0]
+		for _, item := range items {
+			c, err := u.itemRepo.Create(ctx, item)
+			if err != nil {
+				return err
+			}
+			created = append(created, c)
+
+			if err := u.recordAuditIn(ctx, entity.AuditActionItemCreate, c.ID, ""); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create items: %w", err)
+	}
+
+	for i, item := range created {
+		u.publish(ctx, entity.EventItemCreated, item)
+		result.Created = append(result.Created, BulkCreatedRow{Index: i, ID: item.ID})
+	}
+	return result, nil
+}
+
+// 一括削除・一括更新で一度に指定できる ID の数の上限
+const MaxBulkItemIDs = 500
+
+type BulkDeleteResult struct {
+	Deleted  []int64 `json:"deleted"`
+	NotFound []int64 `json:"not_found"`
+}
+
+// ids のアイテムすべてに同じ部分更新を適用する
+type BulkUpdateItemsInput struct {
+	IDs []int64 `json:"ids"`
+	UpdateItemInput
+}
+
+type BulkUpdateResult struct {
+	Updated  []*entity.Item `json:"updated"`
+	NotFound []int64        `json:"not_found"`
+}
+
+// 複数のアイテムを1つのトランザクションで削除する
+// 存在しない ID はエラーにせず NotFound で返す
+func (u *itemUsecase) DeleteItems(ctx context.Context, ids []int64, reason string) (*BulkDeleteResult, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	ids, err := normalizeBulkIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	reason = strings.TrimSpace(reason)
+	if reason == "" && u.reasonPolicy.PolicyFor(ctx).RequiresReasonForDelete() {
+		return nil, domainErrors.ErrReasonRequired
+	}
+
+	var result *BulkDeleteResult
+	var deleted []*entity.Item
+	err = u.transactor.Transaction(ctx, func(ctx context.Context) error {
+		result = &BulkDeleteResult{Deleted: []int64{}, NotFound: []int64{}}
+		deleted = deleted[:0]
+		for _, id := range ids {
+			item, err := u.itemRepo.FindByID(ctx, id)
+			if domainErrors.IsNotFoundError(err) {
+				result.NotFound = append(result.NotFound, id)
+				continue
+			}
+			if err != nil {
+				return err
+			}
+
+			if err := u.itemRepo.SoftDelete(ctx, id, u.clock.Now()); err != nil {
+				return err
+			}
+			if err := u.recordAuditIn(ctx, entity.AuditActionItemDelete, id, reason); err != nil {
+				return err
+			}
+			deleted = append(deleted, item)
+			result.Deleted = append(result.Deleted, id)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete items: %w", err)
+	}
+
+	for _, item := range deleted {
+		u.publish(ctx, entity.EventItemDeleted, item)
+	}
+	return result, nil
+}
+
+// 複数のアイテムに同じ部分更新を1つのトランザクションで適用する
+// 存在しない ID はエラーにせず NotFound で返す。1件でも更新できない場合はどれも更新しない
+func (u *itemUsecase) UpdateItems(ctx context.Context, input BulkUpdateItemsInput) (*BulkUpdateResult, error) {
+	ids, err := normalizeBulkIDs(input.IDs)
+	if err != nil {
+		return nil, err
+	}
+
+	reason := ""
+	if input.Reason != nil {
+		reason = strings.TrimSpace(*input.Reason)
+	}
+
+	type pendingUpdate struct {
+		before      entity.Item
+		item        *entity.Item
+		priceChange *entity.PriceChange
+		overridden  []entity.FieldLock
+	}
+
+	var result *BulkUpdateResult
+	var changed [][]string
+	err = u.transactor.Transaction(ctx, func(ctx context.Context) error {
+		result = &BulkUpdateResult{Updated: []*entity.Item{}, NotFound: []int64{}}
+		changed = changed[:0]
+
+		// 書き込む前にすべてのアイテムに更新を適用して確かめる
+		now := u.clock.Now()
+		var pending []pendingUpdate
+		for _, id := range ids {
+			item, err := u.itemRepo.FindByID(ctx, id)
+			if domainErrors.IsNotFoundError(err) {
+				result.NotFound = append(result.NotFound, id)
+				continue
+			}
+			if err != nil {
+				return err
+			}
+
+			update := pendingUpdate{before: *item, item: item}
+			if err := item.PartialUpdateAt(now, input.Name, input.Brand, input.PurchasePrice); err != nil {
+				return fmt.Errorf("%w: item %d: %s", domainErrors.ErrInvalidInput, id, err.Error())
+			}
+			update.overridden, err = u.checkLockedFields(ctx, &update.before, item, input.OverrideLocks)
+			if err != nil {
+				return fmt.Errorf("item %d: %w", id, err)
+			}
+			if reason == "" && len(update.overridden) > 0 {
+				return domainErrors.ErrReasonRequired
+			}
+			if reason == "" && u.reasonPolicy.PolicyFor(ctx).RequiresReasonForUpdate(update.before.PurchasePrice, item.PurchasePrice) {
+				return domainErrors.ErrReasonRequired
+			}
+			if item.PurchasePrice != update.before.PurchasePrice {
+				update.priceChange, err = entity.NewPriceChange(id, update.before.PurchasePrice, item.PurchasePrice, actorFromContext(ctx), reason, now)
+				if err != nil {
+					return fmt.Errorf("%w: item %d: %s", domainErrors.ErrInvalidInput, id, err.Error())
+				}
+			}
+			pending = append(pending, update)
+		}
+
+		for _, update := range pending {
+			updated, err := u.itemRepo.Update(ctx, update.item)
+			if err != nil {
+				return err
+			}
+			if update.priceChange != nil {
+				if err := u.itemRepo.RecordPriceChange(ctx, update.priceChange); err != nil {
+					return err
+				}
+			}
+			if err := u.recordAuditIn(ctx, entity.AuditActionItemUpdate, updated.ID, overrideReason(update.overridden, reason)); err != nil {
+				return err
+			}
+			result.Updated = append(result.Updated, updated)
+			changed = append(changed, entity.ChangedFields(&update.before, updated))
+		}
+		return nil
+	})
+	if err != nil {
+		if domainErrors.IsValidationError(err) || errors.Is(err, domainErrors.ErrReasonRequired) || domainErrors.IsLockedError(err) || domainErrors.IsForbiddenError(err) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to update items: %w", err)
+	}
+
+	for i, item := range result.Updated {
+		u.publish(ctx, entity.EventItemUpdated, item, changed[i]...)
+	}
+	return result, nil
+}
+
+// 重複を除き、指定された順に並べた ID を返す
+func normalizeBulkIDs(ids []int64) ([]int64, error) {
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("%w: at least one id is required", domainErrors.ErrInvalidInput)
+	}
+	if len(ids) > MaxBulkItemIDs {
+		return nil, fmt.Errorf("%w: at most %d ids can be given at once", domainErrors.ErrInvalidInput, MaxBulkItemIDs)
+	}
+
+	seen := make(map[int64]bool, len(ids))
+	unique := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		if id <= 0 {
+			return nil, fmt.Errorf("%w: invalid id: %d", domainErrors.ErrInvalidInput, id)
+		}
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique, nil
+}
+
+func problemMessages(problems entity.ValidationErrors) []string {
+	messages := make([]string, len(problems))
+	for i, problem := range problems {
+		messages[i] = problem.Message
+	}
+	return messages
+}
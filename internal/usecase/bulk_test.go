@@ -0,0 +1,158 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/pkg/reqctx"
+)
+
+func TestItemUsecase_CreateItems(t *testing.T) {
+	valid := CreateItemInput{Name: "デイトナ", Category: "時計", Brand: "ROLEX", PurchasePrice: 1500000, PurchaseDate: "2023-01-15"}
+	invalid := CreateItemInput{Name: "バーキン", Category: "家電", Brand: "HERMES", PurchaseDate: "2023-01-15"}
+
+	// 作成した順に 1 から n までの ID を振る
+	newRepo := func(n int) *MockItemRepository {
+		mockRepo := new(MockItemRepository)
+		for id := int64(1); id <= int64(n); id++ {
+			mockRepo.On("Create", mock.Anything, mock.Anything).Return(&entity.Item{ID: id}, nil).Once()
+		}
+		return mockRepo
+	}
+
+	t.Run("正常系: 問題のない行だけを登録し、問題のある行をインデックス付きで返す", func(t *testing.T) {
+		mockRepo := newRepo(2)
+
+		result, err := NewItemUsecase(mockRepo).CreateItems(context.Background(), []CreateItemInput{valid, invalid, valid}, false)
+		require.NoError(t, err)
+		assert.Equal(t, []BulkCreatedRow{{Index: 0, ID: 1}, {Index: 2, ID: 2}}, result.Created)
+		require.Len(t, result.Errors, 1)
+		assert.Equal(t, 1, result.Errors[0].Index)
+		assert.Contains(t, result.Errors[0].Errors[0], "category must be one of")
+	})
+
+	t.Run("正常系: atomic では問題のある行があればどの行も登録しない", func(t *testing.T) {
+		mockRepo := new(MockItemRepository)
+		tx := &fakeTransactor{}
+
+		result, err := NewItemUsecase(mockRepo, WithTransactor(tx)).CreateItems(context.Background(), []CreateItemInput{valid, invalid}, true)
+		require.NoError(t, err)
+		assert.Empty(t, result.Created)
+		assert.Len(t, result.Errors, 1)
+		assert.False(t, tx.committed)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("正常系: atomic ではすべての行を1つのトランザクションで登録する", func(t *testing.T) {
+		mockRepo := newRepo(2)
+		tx := &fakeTransactor{}
+
+		result, err := NewItemUsecase(mockRepo, WithTransactor(tx)).CreateItems(context.Background(), []CreateItemInput{valid, valid}, true)
+		require.NoError(t, err)
+		assert.Equal(t, []BulkCreatedRow{{Index: 0, ID: 1}, {Index: 1, ID: 2}}, result.Created)
+		assert.True(t, tx.committed)
+	})
+
+	t.Run("異常系: atomic で登録に失敗した場合はロールバックする", func(t *testing.T) {
+		mockRepo := new(MockItemRepository)
+		mockRepo.On("Create", mock.Anything, mock.Anything).Return(nil, errors.New("connection reset"))
+		tx := &fakeTransactor{}
+
+		_, err := NewItemUsecase(mockRepo, WithTransactor(tx)).CreateItems(context.Background(), []CreateItemInput{valid}, true)
+		require.Error(t, err)
+		assert.True(t, tx.rolledBack)
+	})
+
+	t.Run("異常系: 空の配列", func(t *testing.T) {
+		_, err := NewItemUsecase(new(MockItemRepository)).CreateItems(context.Background(), nil, false)
+		assert.ErrorIs(t, err, domainErrors.ErrInvalidInput)
+	})
+}
+
+func TestItemUsecase_DeleteItems(t *testing.T) {
+	asAdmin := reqctx.WithUserRole(reqctx.WithUserID(context.Background(), 1), entity.UserRoleAdmin)
+
+	t.Run("正常系: 存在しない ID を not_found で返し、重複した ID は1回だけ削除する", func(t *testing.T) {
+		mockRepo := new(MockItemRepository)
+		mockRepo.On("FindByID", mock.Anything, int64(1)).Return(&entity.Item{ID: 1}, nil)
+		mockRepo.On("FindByID", mock.Anything, int64(2)).Return(nil, domainErrors.ErrItemNotFound)
+		mockRepo.On("SoftDelete", mock.Anything, int64(1), mock.Anything).Return(nil).Once()
+		tx := &fakeTransactor{}
+
+		result, err := NewItemUsecase(mockRepo, WithTransactor(tx)).DeleteItems(asAdmin, []int64{1, 2, 1}, "")
+		require.NoError(t, err)
+		assert.Equal(t, []int64{1}, result.Deleted)
+		assert.Equal(t, []int64{2}, result.NotFound)
+		assert.True(t, tx.committed)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("異常系: 削除に失敗した場合はロールバックする", func(t *testing.T) {
+		mockRepo := new(MockItemRepository)
+		mockRepo.On("FindByID", mock.Anything, int64(1)).Return(&entity.Item{ID: 1}, nil)
+		mockRepo.On("SoftDelete", mock.Anything, int64(1), mock.Anything).Return(errors.New("connection reset"))
+		tx := &fakeTransactor{}
+
+		_, err := NewItemUsecase(mockRepo, WithTransactor(tx)).DeleteItems(asAdmin, []int64{1}, "")
+		require.Error(t, err)
+		assert.True(t, tx.rolledBack)
+	})
+
+	t.Run("異常系: 不正な ID", func(t *testing.T) {
+		_, err := NewItemUsecase(new(MockItemRepository)).DeleteItems(asAdmin, []int64{0}, "")
+		assert.ErrorIs(t, err, domainErrors.ErrInvalidInput)
+	})
+
+	t.Run("異常系: 管理者でないユーザー", func(t *testing.T) {
+		mockRepo := new(MockItemRepository)
+		ctx := reqctx.WithUserRole(reqctx.WithUserID(context.Background(), 2), entity.UserRoleMember)
+
+		_, err := NewItemUsecase(mockRepo).DeleteItems(ctx, []int64{1}, "")
+		assert.ErrorIs(t, err, domainErrors.ErrForbidden)
+		mockRepo.AssertNotCalled(t, "SoftDelete", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestItemUsecase_UpdateItems(t *testing.T) {
+	brand := "CHANEL"
+	newItem := func(id int64) *entity.Item {
+		return &entity.Item{ID: id, Name: "バッグ", Category: "バッグ", Brand: "HERMES", PurchasePrice: 1000, PurchaseDate: "2023-01-15"}
+	}
+
+	t.Run("正常系: 存在する ID をすべて更新し、存在しない ID を not_found で返す", func(t *testing.T) {
+		mockRepo := new(MockItemRepository)
+		mockRepo.On("FindByID", mock.Anything, int64(1)).Return(newItem(1), nil)
+		mockRepo.On("FindByID", mock.Anything, int64(2)).Return(nil, domainErrors.ErrItemNotFound)
+		mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(item *entity.Item) bool {
+			return item.ID == 1 && item.Brand == brand
+		})).Return(newItem(1), nil)
+		tx := &fakeTransactor{}
+
+		input := BulkUpdateItemsInput{IDs: []int64{1, 2}, UpdateItemInput: UpdateItemInput{Brand: &brand}}
+		result, err := NewItemUsecase(mockRepo, WithTransactor(tx)).UpdateItems(context.Background(), input)
+		require.NoError(t, err)
+		assert.Len(t, result.Updated, 1)
+		assert.Equal(t, []int64{2}, result.NotFound)
+		assert.True(t, tx.committed)
+	})
+
+	t.Run("異常系: 1件でも更新内容が不正な場合はどれも更新しない", func(t *testing.T) {
+		mockRepo := new(MockItemRepository)
+		mockRepo.On("FindByID", mock.Anything, mock.Anything).Return(newItem(1), nil)
+		empty := ""
+		tx := &fakeTransactor{}
+
+		input := BulkUpdateItemsInput{IDs: []int64{1, 2}, UpdateItemInput: UpdateItemInput{Name: &empty}}
+		_, err := NewItemUsecase(mockRepo, WithTransactor(tx)).UpdateItems(context.Background(), input)
+		assert.ErrorIs(t, err, domainErrors.ErrInvalidInput)
+		assert.True(t, tx.rolledBack)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+}
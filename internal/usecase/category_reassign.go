@@ -0,0 +1,175 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/pkg/filter"
+	"Aicon-assignment/internal/pkg/listquery"
+	"Aicon-assignment/internal/pkg/reqctx"
+)
+
+type ReassignCategoryInput struct {
+	To            string `json:"to"`
+	DryRun        bool   `json:"dry_run"`        // true の場合は付け替えずに対象と入力チェックの結果だけを返す
+	ExpectedItems *int   `json:"expected_items"` // プレビューで確認した件数。付け替える場合は必須
+	DeleteSource  bool   `json:"delete_source"`  // 付け替えた後に元のカテゴリーを削除する
+	Reason        string `json:"reason"`
+}
+
+// 付け替え先のカテゴリーの入力チェックを通らないアイテム
+type ReassignViolation struct {
+	ItemID int64    `json:"item_id"`
+	Name   string   `json:"name"`
+	Errors []string `json:"errors"`
+}
+
+type ReassignCategoryResult struct {
+	From          string              `json:"from"`
+	To            string              `json:"to"`
+	Items         int                 `json:"items"`
+	Invalid       []ReassignViolation `json:"invalid"`
+	DryRun        bool                `json:"dry_run"`
+	SourceDeleted bool                `json:"source_deleted"`
+}
+
+// アイテムを付け替え先のカテゴリーの入力チェックに通すための参照先を設定する（設定しない場合、カテゴリーの付け替えはできない）
+func WithCategories(c CategoryCatalog) Option {
+	return func(u *itemUsecase) {
+		u.categories = c
+	}
+}
+
+// カテゴリー from のアイテム（組織をまたぐ）をすべて to に付け替える
+// 付け替える前に dry_run で対象の件数と入力チェックの結果を確認し、その件数を expected_items に指定してもらう
+// アイテムの更新、監査ログの記録、元のカテゴリーの削除を1つのトランザクションで行う
+func (u *itemUsecase) ReassignCategory(ctx context.Context, from string, input ReassignCategoryInput) (*ReassignCategoryResult, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if u.categories == nil {
+		return nil, fmt.Errorf("%w: categories are not available", domainErrors.ErrInvalidInput)
+	}
+
+	to := strings.TrimSpace(input.To)
+	if to == "" {
+		return nil, fmt.Errorf("%w: to is required", domainErrors.ErrInvalidInput)
+	}
+	if to == from {
+		return nil, fmt.Errorf("%w: to must be different from the current category", domainErrors.ErrInvalidInput)
+	}
+	if !input.DryRun && input.ExpectedItems == nil {
+		return nil, fmt.Errorf("%w: expected_items is required; check the items with dry_run first", domainErrors.ErrInvalidInput)
+	}
+	reason := strings.TrimSpace(input.Reason)
+
+	result := &ReassignCategoryResult{From: from, To: to, Invalid: []ReassignViolation{}, DryRun: input.DryRun}
+	var reassigned []*entity.Item
+	err := u.transactor.Transaction(ctx, func(ctx context.Context) error {
+		profile, err := u.categories.ValidationProfile(ctx, to)
+		if errors.Is(err, domainErrors.ErrReferenceNotFound) {
+			return fmt.Errorf("%w: category %q does not exist", domainErrors.ErrInvalidInput, to)
+		}
+		if err != nil {
+			return err
+		}
+
+		items, err := u.itemRepo.FindByQuery(reqctx.WithAllOwners(ctx), itemsInCategory(from))
+		if err != nil {
+			return err
+		}
+		result.Items = len(items)
+		for _, item := range items {
+			if violation := reassignViolation(item, to, profile); violation != nil {
+				result.Invalid = append(result.Invalid, *violation)
+			}
+		}
+		if input.DryRun {
+			return nil
+		}
+
+		if *input.ExpectedItems != len(items) {
+			return fmt.Errorf("%w: %d items are in category %q, but %d were expected; check them with dry_run again", domainErrors.ErrStalePreview, len(items), from, *input.ExpectedItems)
+		}
+		if len(result.Invalid) > 0 {
+			return fmt.Errorf("%w: %d items do not pass the checks of category %q; fix them first (see dry_run)", domainErrors.ErrInvalidInput, len(result.Invalid), to)
+		}
+
+		now := u.clock.Now()
+		description := withReason(fmt.Sprintf("category changed from %q to %q", from, to), reason)
+		for _, item := range items {
+			before := *item
+			item.Category = to
+			if _, err := u.checkLockedFields(ctx, &before, item, false); err != nil {
+				return fmt.Errorf("item %d: %w", item.ID, err)
+			}
+			item.UpdatedAt = now
+			updated, err := u.itemRepo.Update(reqctx.WithAllOwners(ctx), item)
+			if err != nil {
+				return fmt.Errorf("failed to update item %d: %w", item.ID, err)
+			}
+			if err := u.recordAuditIn(ctx, entity.AuditActionItemUpdate, item.ID, description); err != nil {
+				return err
+			}
+			reassigned = append(reassigned, updated)
+		}
+
+		// 削除は最後に行う。失敗した場合は付け替えも取り消す
+		if input.DeleteSource {
+			if err := u.categories.DeleteCategory(ctx, from); err != nil {
+				return err
+			}
+			result.SourceDeleted = true
+		}
+		return nil
+	})
+	if err != nil {
+		if domainErrors.IsValidationError(err) || domainErrors.IsStalePreviewError(err) ||
+			domainErrors.IsNotFoundError(err) || domainErrors.IsInUseError(err) || domainErrors.IsLockedError(err) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to reassign category: %w", err)
+	}
+
+	if !input.DryRun {
+		for _, item := range reassigned {
+			u.publish(ctx, entity.EventItemUpdated, item, "category")
+		}
+		reqctx.Logger(ctx).Info("category reassigned", "from", from, "to", to, "items", len(reassigned), "source_deleted", result.SourceDeleted)
+	}
+	return result, nil
+}
+
+// カテゴリーが category のアイテム（論理削除したものを除く）
+func itemsInCategory(category string) entity.ItemQuery {
+	return entity.ItemQuery{
+		Filter: &filter.Comparison{Field: "category", Op: filter.OpEq, Value: category},
+		Sort:   []listquery.SortField{{Field: "id"}},
+	}
+}
+
+// 付け替えた後のアイテムを、アイテム共通の入力チェックと付け替え先のプロファイルで確かめる
+func reassignViolation(item *entity.Item, to string, profile *entity.ValidationProfile) *ReassignViolation {
+	candidate := *item
+	candidate.Category = to
+
+	var messages []string
+	var errs entity.ValidationErrors
+	if err := candidate.Validate(); err != nil && !errors.As(err, &errs) {
+		messages = append(messages, err.Error())
+	}
+	if profile != nil {
+		errs = append(errs, profile.Check(&candidate)...)
+	}
+	for _, e := range errs {
+		messages = append(messages, e.Message)
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+	return &ReassignViolation{ItemID: item.ID, Name: item.Name, Errors: messages}
+}
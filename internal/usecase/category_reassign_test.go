@@ -0,0 +1,133 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/pkg/reqctx"
+)
+
+// fakeCategoryCatalog はカテゴリーごとの入力チェックを返し、削除したカテゴリーを記録する
+type fakeCategoryCatalog struct {
+	profiles map[string]*entity.ValidationProfile // 値が nil のカテゴリーは入力チェックなし
+	deleted  []string
+}
+
+func (f *fakeCategoryCatalog) ValidationProfile(ctx context.Context, category string) (*entity.ValidationProfile, error) {
+	profile, ok := f.profiles[category]
+	if !ok {
+		return nil, domainErrors.ErrReferenceNotFound
+	}
+	return profile, nil
+}
+
+func (f *fakeCategoryCatalog) DeleteCategory(ctx context.Context, category string) error {
+	f.deleted = append(f.deleted, category)
+	return nil
+}
+
+func TestItemUsecase_ReassignCategory(t *testing.T) {
+	asAdmin := reqctx.WithUserRole(reqctx.WithUserID(context.Background(), 1), entity.UserRoleAdmin)
+	newItems := func() []*entity.Item {
+		return []*entity.Item{
+			{ID: 1, Name: "デイトナ", Category: "その他", Brand: "ROLEX", PurchasePrice: 1500000, PurchaseDate: "2023-01-01"},
+			{ID: 2, Name: "スピードマスター", Category: "その他", Brand: "OMEGA", PurchasePrice: 800000, PurchaseDate: "2023-02-01"},
+		}
+	}
+	watchOnly := func() *fakeCategoryCatalog {
+		minPrice := int64(100000)
+		return &fakeCategoryCatalog{profiles: map[string]*entity.ValidationProfile{
+			"時計": {Name: "watch", MinPrice: &minPrice, Brands: []string{"rolex", "omega"}},
+		}}
+	}
+	expected := func(n int) *int { return &n }
+
+	t.Run("正常系: dry_run では付け替えずに対象の件数と入力チェックの結果を返す", func(t *testing.T) {
+		items := newItems()
+		items[1].Brand = "SEIKO"
+		mockRepo := new(MockItemRepository)
+		mockRepo.On("FindByQuery", mock.Anything, itemsInCategory("その他")).Return(items, nil)
+
+		result, err := NewItemUsecase(mockRepo, WithCategories(watchOnly())).ReassignCategory(asAdmin, "その他", ReassignCategoryInput{To: "時計", DryRun: true})
+		require.NoError(t, err)
+		assert.Equal(t, 2, result.Items)
+		require.Len(t, result.Invalid, 1)
+		assert.Equal(t, int64(2), result.Invalid[0].ItemID)
+		assert.Equal(t, []string{"brand must be one of the terms allowed by watch"}, result.Invalid[0].Errors)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("正常系: アイテムを付け替えて監査ログに残し、元のカテゴリーを削除する", func(t *testing.T) {
+		mockRepo := new(MockItemRepository)
+		mockRepo.On("FindByQuery", mock.Anything, itemsInCategory("その他")).Return(newItems(), nil)
+		mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(item *entity.Item) bool {
+			return item.Category == "時計"
+		})).Return(&entity.Item{ID: 1, Category: "時計"}, nil).Twice()
+		auditLog := new(MockAuditLogRepository)
+		auditLog.On("Record", mock.Anything, mock.MatchedBy(func(entry *entity.AuditEntry) bool {
+			return entry.Action == entity.AuditActionItemUpdate && entry.Reason == `category changed from "その他" to "時計": 整理`
+		})).Return(nil).Twice()
+		catalog := watchOnly()
+		events := &recordingPublisher{}
+		tx := &fakeTransactor{}
+
+		usecase := NewItemUsecase(mockRepo, WithCategories(catalog), WithAuditLog(auditLog), WithEventPublisher(events), WithTransactor(tx))
+		result, err := usecase.ReassignCategory(asAdmin, "その他", ReassignCategoryInput{To: "時計", ExpectedItems: expected(2), DeleteSource: true, Reason: "整理"})
+		require.NoError(t, err)
+		assert.True(t, result.SourceDeleted)
+		assert.True(t, tx.committed)
+		assert.Equal(t, []string{"その他"}, catalog.deleted)
+		require.Len(t, events.events, 2)
+		assert.Equal(t, []string{"category"}, events.events[0].ChangedFields)
+		mockRepo.AssertExpectations(t)
+		auditLog.AssertExpectations(t)
+	})
+
+	t.Run("異常系: プレビュー後にアイテムの件数が変わった", func(t *testing.T) {
+		mockRepo := new(MockItemRepository)
+		mockRepo.On("FindByQuery", mock.Anything, itemsInCategory("その他")).Return(newItems(), nil)
+		tx := &fakeTransactor{}
+
+		_, err := NewItemUsecase(mockRepo, WithCategories(watchOnly()), WithTransactor(tx)).ReassignCategory(asAdmin, "その他", ReassignCategoryInput{To: "時計", ExpectedItems: expected(1)})
+		assert.ErrorIs(t, err, domainErrors.ErrStalePreview)
+		assert.True(t, tx.rolledBack)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("異常系: 付け替え先の入力チェックを通らないアイテムがある", func(t *testing.T) {
+		items := newItems()
+		items[0].PurchasePrice = 5000
+		mockRepo := new(MockItemRepository)
+		mockRepo.On("FindByQuery", mock.Anything, itemsInCategory("その他")).Return(items, nil)
+		catalog := watchOnly()
+
+		_, err := NewItemUsecase(mockRepo, WithCategories(catalog)).ReassignCategory(asAdmin, "その他", ReassignCategoryInput{To: "時計", ExpectedItems: expected(2), DeleteSource: true})
+		assert.ErrorIs(t, err, domainErrors.ErrInvalidInput)
+		assert.Empty(t, catalog.deleted)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("異常系: 付け替えるには expected_items が必要", func(t *testing.T) {
+		_, err := NewItemUsecase(new(MockItemRepository), WithCategories(watchOnly())).ReassignCategory(asAdmin, "その他", ReassignCategoryInput{To: "時計"})
+		assert.ErrorIs(t, err, domainErrors.ErrInvalidInput)
+	})
+
+	t.Run("異常系: 存在しないカテゴリーへの付け替え", func(t *testing.T) {
+		_, err := NewItemUsecase(new(MockItemRepository), WithCategories(watchOnly())).ReassignCategory(asAdmin, "その他", ReassignCategoryInput{To: "アート", DryRun: true})
+		assert.ErrorIs(t, err, domainErrors.ErrInvalidInput)
+		assert.Contains(t, err.Error(), `category "アート" does not exist`)
+	})
+
+	t.Run("異常系: 管理者でないユーザー", func(t *testing.T) {
+		asMember := reqctx.WithUserRole(reqctx.WithUserID(context.Background(), 2), entity.UserRoleMember)
+
+		_, err := NewItemUsecase(new(MockItemRepository), WithCategories(watchOnly())).ReassignCategory(asMember, "その他", ReassignCategoryInput{To: "時計", DryRun: true})
+		assert.ErrorIs(t, err, domainErrors.ErrForbidden)
+	})
+}
@@ -0,0 +1,257 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/pkg/filter"
+	"Aicon-assignment/internal/pkg/listquery"
+)
+
+// ダッシュボードで使える指標
+const (
+	MetricPortfolioItemCount     = "portfolio.item_count"
+	MetricPortfolioPurchaseValue = "portfolio.purchase_value"
+	MetricPortfolioCurrentValue  = "portfolio.current_value"
+	MetricCategoryCurrentValue   = "portfolio.current_value_by_category" // カテゴリーごとに 1 つの系列
+	metricSummaryPrefix          = "summary."                            // summary.category など、GetSummary の集計
+)
+
+// 指標の値の形
+const (
+	DashboardKindTimeSeries = "timeseries"
+	DashboardKindTable      = "table"
+)
+
+// 1 回に返す注釈の上限
+const MaxDashboardAnnotations = 1000
+
+// 統計・推移の usecase をダッシュボード（Grafana など）向けの時系列・表・注釈にする
+// ポートフォリオの推移は管理者だけ、アイテムの集計は呼び出し元が見られるアイテムだけを対象にする
+type DashboardUsecase interface {
+	// Metrics は呼び出し元が使える指標を名前の順に返す
+	Metrics(ctx context.Context) []DashboardMetric
+	// Query は指標の from から to までの値を返す。集計（summary.*）は期間に関係なく現在の値の表
+	Query(ctx context.Context, metric string, from, to time.Time) (*DashboardResult, error)
+	// Annotations は from から to までに購入したアイテムを注釈として返す。category を指定した場合はそのカテゴリーだけ
+	Annotations(ctx context.Context, from, to time.Time, category string) ([]*DashboardAnnotation, error)
+}
+
+type DashboardMetric struct {
+	Name string `json:"name"`
+	Kind string `json:"kind"` // timeseries または table
+}
+
+// 時系列か表のどちらか
+type DashboardResult struct {
+	Series []*DashboardSeries
+	Table  *DashboardTable
+}
+
+type DashboardSeries struct {
+	Name   string
+	Points []DashboardPoint
+}
+
+type DashboardPoint struct {
+	Time  time.Time
+	Value float64
+}
+
+type DashboardTable struct {
+	Columns []DashboardColumn
+	Rows    [][]any
+}
+
+type DashboardColumn struct {
+	Name string
+	Type string // string または number
+}
+
+type DashboardAnnotation struct {
+	Time  time.Time
+	Title string
+	Text  string
+	Tags  []string
+}
+
+type dashboardUsecase struct {
+	items     ItemUsecase
+	portfolio PortfolioUsecase
+}
+
+func NewDashboardUsecase(items ItemUsecase, portfolio PortfolioUsecase) DashboardUsecase {
+	return &dashboardUsecase{
+		items:     items,
+		portfolio: portfolio,
+	}
+}
+
+var portfolioMetrics = []string{
+	MetricPortfolioItemCount,
+	MetricPortfolioPurchaseValue,
+	MetricPortfolioCurrentValue,
+	MetricCategoryCurrentValue,
+}
+
+func (u *dashboardUsecase) Metrics(ctx context.Context) []DashboardMetric {
+	var metrics []DashboardMetric
+	if requireAdmin(ctx) == nil {
+		for _, name := range portfolioMetrics {
+			metrics = append(metrics, DashboardMetric{Name: name, Kind: DashboardKindTimeSeries})
+		}
+	}
+	for _, dim := range entity.ValidSummaryDimensions {
+		metrics = append(metrics, DashboardMetric{Name: metricSummaryPrefix + string(dim), Kind: DashboardKindTable})
+	}
+	sort.Slice(metrics, func(i, j int) bool { return metrics[i].Name < metrics[j].Name })
+	return metrics
+}
+
+func (u *dashboardUsecase) Query(ctx context.Context, metric string, from, to time.Time) (*DashboardResult, error) {
+	if dim, ok := strings.CutPrefix(metric, metricSummaryPrefix); ok {
+		summary, err := u.items.GetSummary(ctx, dim)
+		if err != nil {
+			return nil, err
+		}
+		return &DashboardResult{Table: summaryTable(summary)}, nil
+	}
+	if !slices.Contains(portfolioMetrics, metric) {
+		return nil, fmt.Errorf("%w: unknown metric %q", domainErrors.ErrInvalidInput, metric)
+	}
+
+	snapshots, err := u.portfolio.Between(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return &DashboardResult{Series: portfolioSeries(metric, snapshots)}, nil
+}
+
+// 件数の多い順（同じ件数は名前の順）の表
+func summaryTable(summary *Summary) *DashboardTable {
+	keys := make([]string, 0, len(summary.Groups))
+	for key := range summary.Groups {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if summary.Groups[keys[i]] != summary.Groups[keys[j]] {
+			return summary.Groups[keys[i]] > summary.Groups[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+
+	table := &DashboardTable{
+		Columns: []DashboardColumn{
+			{Name: summary.GroupBy, Type: "string"},
+			{Name: "items", Type: "number"},
+		},
+		Rows: make([][]any, 0, len(keys)),
+	}
+	for _, key := range keys {
+		table.Rows = append(table.Rows, []any{key, summary.Groups[key]})
+	}
+	return table
+}
+
+// スナップショットの日付（UTC の 0 時）を時刻にした系列
+func portfolioSeries(metric string, snapshots []*entity.PortfolioSnapshot) []*DashboardSeries {
+	if metric != MetricCategoryCurrentValue {
+		series := &DashboardSeries{Name: metric, Points: []DashboardPoint{}}
+		for _, snapshot := range snapshots {
+			point := DashboardPoint{Time: snapshotTime(snapshot)}
+			switch metric {
+			case MetricPortfolioItemCount:
+				point.Value = float64(snapshot.ItemCount)
+			case MetricPortfolioPurchaseValue:
+				point.Value = float64(snapshot.PurchaseValue)
+			case MetricPortfolioCurrentValue:
+				point.Value = float64(snapshot.CurrentValue)
+			}
+			series.Points = append(series.Points, point)
+		}
+		return []*DashboardSeries{series}
+	}
+
+	byCategory := map[string]*DashboardSeries{}
+	for _, snapshot := range snapshots {
+		for _, category := range snapshot.Categories {
+			series, ok := byCategory[category.Category]
+			if !ok {
+				series = &DashboardSeries{Name: category.Category}
+				byCategory[category.Category] = series
+			}
+			series.Points = append(series.Points, DashboardPoint{Time: snapshotTime(snapshot), Value: float64(category.CurrentValue)})
+		}
+	}
+	series := make([]*DashboardSeries, 0, len(byCategory))
+	for _, s := range byCategory {
+		series = append(series, s)
+	}
+	sort.Slice(series, func(i, j int) bool { return series[i].Name < series[j].Name })
+	return series
+}
+
+func snapshotTime(snapshot *entity.PortfolioSnapshot) time.Time {
+	date, err := time.Parse("2006-01-02", snapshot.Date)
+	if err != nil {
+		return snapshot.RecordedAt
+	}
+	return date
+}
+
+var errEnoughAnnotations = errors.New("enough annotations")
+
+func (u *dashboardUsecase) Annotations(ctx context.Context, from, to time.Time, category string) ([]*DashboardAnnotation, error) {
+	start, end, err := entity.PortfolioDateRange(from, to)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrInvalidInput, err.Error())
+	}
+	conditions := [][3]string{{"purchase_date", "gte", start}, {"purchase_date", "lte", end}}
+	if category = strings.TrimSpace(category); category != "" {
+		conditions = append(conditions, [3]string{"category", "eq", category})
+	}
+	var expr filter.Expr
+	for _, condition := range conditions {
+		comparison, err := filter.NewComparison(condition[0], condition[1], condition[2], entity.ItemFilterFields)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", domainErrors.ErrInvalidInput, err.Error())
+		}
+		if expr == nil {
+			expr = comparison
+		} else {
+			expr = &filter.Logical{Op: filter.OpAnd, Left: expr, Right: comparison}
+		}
+	}
+
+	annotations := []*DashboardAnnotation{}
+	err = u.items.EachItem(ctx, listquery.Query{Filter: expr}, func(items []*entity.Item) error {
+		for _, item := range items {
+			date, err := time.Parse("2006-01-02", item.PurchaseDate)
+			if err != nil {
+				continue
+			}
+			annotations = append(annotations, &DashboardAnnotation{
+				Time:  date,
+				Title: item.Name,
+				Text:  fmt.Sprintf("%s / %s / %d JPY", item.Category, item.Brand, item.PurchasePrice),
+				Tags:  []string{"category:" + item.Category},
+			})
+			if len(annotations) >= MaxDashboardAnnotations {
+				return errEnoughAnnotations
+			}
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errEnoughAnnotations) {
+		return nil, err
+	}
+	sort.SliceStable(annotations, func(i, j int) bool { return annotations[i].Time.Before(annotations[j].Time) })
+	return annotations, nil
+}
@@ -0,0 +1,134 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/pkg/filter"
+	"Aicon-assignment/internal/pkg/listquery"
+	"Aicon-assignment/internal/pkg/reqctx"
+)
+
+// 集計とアイテムの読み出しだけを返す ItemUsecase
+type fakeDashboardItems struct {
+	ItemUsecase
+	summary *Summary
+	items   []*entity.Item
+	query   listquery.Query
+}
+
+func (f *fakeDashboardItems) GetSummary(ctx context.Context, groupBy string) (*Summary, error) {
+	return f.summary, nil
+}
+
+func (f *fakeDashboardItems) EachItem(ctx context.Context, query listquery.Query, fn func(items []*entity.Item) error) error {
+	f.query = query
+	return fn(f.items)
+}
+
+// 記録済みのスナップショットを返す PortfolioUsecase
+type fakeDashboardPortfolio struct {
+	PortfolioUsecase
+	snapshots []*entity.PortfolioSnapshot
+}
+
+func (f *fakeDashboardPortfolio) Between(ctx context.Context, from, to time.Time) ([]*entity.PortfolioSnapshot, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	return f.snapshots, nil
+}
+
+func TestDashboardUsecase(t *testing.T) {
+	from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	admin := reqctx.WithUserRole(reqctx.WithUserID(context.Background(), 1), entity.UserRoleAdmin)
+	member := reqctx.WithUserRole(reqctx.WithUserID(context.Background(), 2), entity.UserRoleMember)
+	portfolio := &fakeDashboardPortfolio{snapshots: []*entity.PortfolioSnapshot{
+		{Date: "2024-05-30", ItemCount: 2, CurrentValue: 900, Categories: []*entity.PortfolioCategoryValue{{Category: "時計", CurrentValue: 900}}},
+		{Date: "2024-05-31", ItemCount: 3, CurrentValue: 1000, Categories: []*entity.PortfolioCategoryValue{{Category: "時計", CurrentValue: 900}, {Category: "バッグ", CurrentValue: 100}}},
+	}}
+
+	t.Run("正常系: メンバーと呼び出し元の分からないリクエストにはポートフォリオの指標を返さない", func(t *testing.T) {
+		uc := NewDashboardUsecase(&fakeDashboardItems{}, portfolio)
+
+		assert.Len(t, uc.Metrics(admin), len(portfolioMetrics)+len(entity.ValidSummaryDimensions))
+		assert.Equal(t, []DashboardMetric{
+			{Name: "summary.brand", Kind: DashboardKindTable},
+			{Name: "summary.category", Kind: DashboardKindTable},
+			{Name: "summary.year", Kind: DashboardKindTable},
+		}, uc.Metrics(member))
+		assert.Equal(t, uc.Metrics(member), uc.Metrics(context.Background()))
+	})
+
+	t.Run("正常系: スナップショットの日付ごとの時系列", func(t *testing.T) {
+		uc := NewDashboardUsecase(&fakeDashboardItems{}, portfolio)
+
+		result, err := uc.Query(admin, MetricPortfolioCurrentValue, from, to)
+		require.NoError(t, err)
+		require.Len(t, result.Series, 1)
+		assert.Equal(t, []DashboardPoint{
+			{Time: time.Date(2024, 5, 30, 0, 0, 0, 0, time.UTC), Value: 900},
+			{Time: time.Date(2024, 5, 31, 0, 0, 0, 0, time.UTC), Value: 1000},
+		}, result.Series[0].Points)
+
+		result, err = uc.Query(admin, MetricCategoryCurrentValue, from, to)
+		require.NoError(t, err)
+		require.Len(t, result.Series, 2)
+		assert.Equal(t, "バッグ", result.Series[0].Name)
+		assert.Len(t, result.Series[0].Points, 1)
+		assert.Equal(t, "時計", result.Series[1].Name)
+		assert.Len(t, result.Series[1].Points, 2)
+	})
+
+	t.Run("正常系: 集計は件数の多い順の表", func(t *testing.T) {
+		items := &fakeDashboardItems{summary: &Summary{GroupBy: "category", Groups: map[string]int{"バッグ": 1, "時計": 3, "靴": 1}, Total: 5}}
+		uc := NewDashboardUsecase(items, portfolio)
+
+		result, err := uc.Query(member, "summary.category", from, to)
+		require.NoError(t, err)
+		assert.Equal(t, []DashboardColumn{{Name: "category", Type: "string"}, {Name: "items", Type: "number"}}, result.Table.Columns)
+		assert.Equal(t, [][]any{{"時計", 3}, {"バッグ", 1}, {"靴", 1}}, result.Table.Rows)
+	})
+
+	t.Run("正常系: 期間内に購入したアイテムの注釈", func(t *testing.T) {
+		items := &fakeDashboardItems{items: []*entity.Item{
+			{Name: "デイトナ", Category: "時計", Brand: "ROLEX", PurchasePrice: 1500000, PurchaseDate: "2024-05-20"},
+			{Name: "バーキン", Category: "バッグ", Brand: "HERMES", PurchasePrice: 2000000, PurchaseDate: "2024-05-10"},
+		}}
+		uc := NewDashboardUsecase(items, portfolio)
+
+		annotations, err := uc.Annotations(member, from, to, "時計")
+		require.NoError(t, err)
+		require.Len(t, annotations, 2)
+		assert.Equal(t, "バーキン", annotations[0].Title, "購入日の順")
+		assert.Equal(t, "時計 / ROLEX / 1500000 JPY", annotations[1].Text)
+		assert.Equal(t, []string{"category:時計"}, annotations[1].Tags)
+		assert.True(t, filter.Evaluate(items.query.Filter, func(field string) any {
+			return map[string]any{"purchase_date": "2024-05-20", "category": "時計"}[field]
+		}))
+		assert.False(t, filter.Evaluate(items.query.Filter, func(field string) any {
+			return map[string]any{"purchase_date": "2024-06-02", "category": "時計"}[field]
+		}))
+	})
+
+	t.Run("異常系: 不明な指標", func(t *testing.T) {
+		uc := NewDashboardUsecase(&fakeDashboardItems{}, portfolio)
+
+		_, err := uc.Query(admin, "items.deleted", from, to)
+		assert.ErrorIs(t, err, domainErrors.ErrInvalidInput)
+	})
+
+	t.Run("異常系: メンバーはポートフォリオの推移を見られない", func(t *testing.T) {
+		uc := NewDashboardUsecase(&fakeDashboardItems{}, portfolio)
+
+		_, err := uc.Query(member, MetricPortfolioItemCount, from, to)
+		assert.ErrorIs(t, err, domainErrors.ErrForbidden)
+	})
+}
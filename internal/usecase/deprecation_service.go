@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"Aicon-assignment/internal/domain/entity"
+	"Aicon-assignment/internal/pkg/clock"
+	"Aicon-assignment/internal/pkg/reqctx"
+)
+
+type DeprecationUsecase interface {
+	// Lookup は廃止予定のルートであれば最初に一致した廃止予定を返す
+	Lookup(method, route string) (entity.Deprecation, bool)
+	// RecordUsage は呼び出し元（コンテキストの認証ユーザー）による呼び出しを1件記録する
+	RecordUsage(ctx context.Context, deprecation entity.Deprecation)
+	// Report は廃止予定ごとに、まだ呼び出している呼び出し元を返す
+	Report(ctx context.Context) ([]*entity.DeprecationReport, error)
+}
+
+type deprecationUsecase struct {
+	deprecations []entity.Deprecation
+	usage        DeprecationUsageRepository
+	clock        clock.Clock
+}
+
+func NewDeprecationUsecase(deprecations []entity.Deprecation, usage DeprecationUsageRepository, clock clock.Clock) DeprecationUsecase {
+	return &deprecationUsecase{
+		deprecations: deprecations,
+		usage:        usage,
+		clock:        clock,
+	}
+}
+
+func (u *deprecationUsecase) Lookup(method, route string) (entity.Deprecation, bool) {
+	for _, deprecation := range u.deprecations {
+		if deprecation.Matches(method, route) {
+			return deprecation, true
+		}
+	}
+	return entity.Deprecation{}, false
+}
+
+// 集計のための記録なので、失敗しても呼び出し自体は止めずログに残す
+func (u *deprecationUsecase) RecordUsage(ctx context.Context, deprecation entity.Deprecation) {
+	caller := actorFromContext(ctx)
+	if err := u.usage.Record(ctx, deprecation.Method, deprecation.Route, caller, u.clock.Now()); err != nil {
+		reqctx.Logger(ctx).Error("failed to record deprecated endpoint usage", "method", deprecation.Method, "route", deprecation.Route, "caller", caller, "error", err)
+	}
+}
+
+// 設定から外れた廃止予定の記録は含めない
+func (u *deprecationUsecase) Report(ctx context.Context) ([]*entity.DeprecationReport, error) {
+	usage, err := retryTransient(ctx, func() ([]*entity.DeprecationUsage, error) {
+		return u.usage.FindAll(ctx)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve deprecated endpoint usage: %w", err)
+	}
+
+	reports := make([]*entity.DeprecationReport, len(u.deprecations))
+	for i, deprecation := range u.deprecations {
+		report := &entity.DeprecationReport{Deprecation: deprecation, Callers: []*entity.DeprecationUsage{}}
+		for _, row := range usage {
+			if row.Method == deprecation.Method && row.Route == deprecation.Route {
+				report.Calls += row.Calls
+				report.Callers = append(report.Callers, row)
+			}
+		}
+		reports[i] = report
+	}
+	return reports, nil
+}
@@ -0,0 +1,64 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"Aicon-assignment/internal/domain/entity"
+	"Aicon-assignment/internal/pkg/clock"
+)
+
+// MockDeprecationUsageRepository はテスト用の廃止予定エンドポイントの利用状況
+type MockDeprecationUsageRepository struct {
+	mock.Mock
+}
+
+func (m *MockDeprecationUsageRepository) Record(ctx context.Context, method, route, caller string, at time.Time) error {
+	args := m.Called(ctx, method, route, caller, at)
+	return args.Error(0)
+}
+
+func (m *MockDeprecationUsageRepository) FindAll(ctx context.Context) ([]*entity.DeprecationUsage, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.DeprecationUsage), args.Error(1)
+}
+
+func TestDeprecationUsecase_Report(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	summary := entity.Deprecation{Method: "GET", Route: "/items/summary", Since: now}
+	split := entity.Deprecation{Method: "*", Route: "/items/:id/split", Since: now}
+
+	t.Run("正常系: 廃止予定ごとに呼び出し元を集計する", func(t *testing.T) {
+		repo := new(MockDeprecationUsageRepository)
+		repo.On("FindAll", mock.Anything).Return([]*entity.DeprecationUsage{
+			{Method: "GET", Route: "/items/summary", Caller: "user:1", Calls: 3, LastSeen: now},
+			{Method: "GET", Route: "/items/summary", Caller: "anonymous", Calls: 2, LastSeen: now.Add(-time.Hour)},
+			{Method: "GET", Route: "/items/old", Caller: "user:1", Calls: 9, LastSeen: now},
+		}, nil)
+
+		reports, err := NewDeprecationUsecase([]entity.Deprecation{summary, split}, repo, clock.NewFrozen(now)).Report(context.Background())
+		require.NoError(t, err)
+		require.Len(t, reports, 2)
+		assert.Equal(t, int64(5), reports[0].Calls)
+		assert.Len(t, reports[0].Callers, 2)
+		assert.Zero(t, reports[1].Calls)
+		assert.Empty(t, reports[1].Callers)
+	})
+
+	t.Run("異常系: 記録に失敗しても呼び出しは止めない", func(t *testing.T) {
+		repo := new(MockDeprecationUsageRepository)
+		repo.On("Record", mock.Anything, "GET", "/items/summary", "anonymous", now).Return(errors.New("connection refused"))
+
+		NewDeprecationUsecase([]entity.Deprecation{summary}, repo, clock.NewFrozen(now)).RecordUsage(context.Background(), summary)
+		repo.AssertExpectations(t)
+	})
+}
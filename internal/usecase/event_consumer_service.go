@@ -0,0 +1,213 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/pkg/clock"
+)
+
+// イベントストアを名前付きの消費者ごとに読み進める（at-least-once の配信）
+// 取得したイベントは確認（Ack）するまで処理済みにならず、期限までに確認されなければ次の Poll で同じイベントから再配信する
+// 管理者（または管理者の API キー）だけが使える
+type EventConsumerUsecase interface {
+	Create(ctx context.Context, input CreateEventConsumerInput) (*entity.EventConsumer, error)
+	List(ctx context.Context) ([]*entity.EventConsumer, error)
+	Get(ctx context.Context, name string) (*entity.EventConsumer, error)
+	Delete(ctx context.Context, name string) error
+	// Poll は確認済みの連番の後のイベントを最大 limit 件配信する。確認を待っている配信が期限前なら ErrBatchInFlight
+	Poll(ctx context.Context, name string, limit int) (*entity.ConsumerBatch, error)
+	// Ack は sequence までのイベントを処理済みにする
+	Ack(ctx context.Context, name string, sequence int64) (*entity.EventConsumer, error)
+}
+
+type CreateEventConsumerInput struct {
+	Name              string `json:"name"`
+	Start             string `json:"start"`               // earliest（省略時）または latest
+	AckTimeoutSeconds int    `json:"ack_timeout_seconds"` // 省略時は 30 秒
+}
+
+type eventConsumerUsecase struct {
+	consumers  EventConsumerRepository
+	store      EventStore
+	transactor Transactor
+	clock      clock.Clock
+
+	mu sync.Mutex // インメモリのトランザクションはロックしないため、Poll と Ack をここでも直列にする
+}
+
+func NewEventConsumerUsecase(consumers EventConsumerRepository, store EventStore, transactor Transactor, clock clock.Clock) EventConsumerUsecase {
+	return &eventConsumerUsecase{
+		consumers:  consumers,
+		store:      store,
+		transactor: transactor,
+		clock:      clock,
+	}
+}
+
+func (u *eventConsumerUsecase) Create(ctx context.Context, input CreateEventConsumerInput) (*entity.EventConsumer, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	var start int64
+	switch input.Start {
+	case "", entity.ConsumerStartEarliest:
+	case entity.ConsumerStartLatest:
+		last, err := u.store.LastSequence(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read last sequence: %w", err)
+		}
+		start = last
+	default:
+		return nil, fmt.Errorf("%w: start must be %s or %s", domainErrors.ErrInvalidInput, entity.ConsumerStartEarliest, entity.ConsumerStartLatest)
+	}
+
+	consumer, err := entity.NewEventConsumer(input.Name, start, input.AckTimeoutSeconds, u.clock.Now())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrInvalidInput, err.Error())
+	}
+	if err := u.consumers.Create(ctx, consumer); err != nil {
+		if errors.Is(err, domainErrors.ErrDuplicateEntry) {
+			return nil, fmt.Errorf("%w: consumer %q already exists", err, consumer.Name)
+		}
+		return nil, fmt.Errorf("failed to create event consumer: %w", err)
+	}
+	return consumer, nil
+}
+
+func (u *eventConsumerUsecase) List(ctx context.Context) ([]*entity.EventConsumer, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	consumers, err := u.consumers.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve event consumers: %w", err)
+	}
+	return consumers, nil
+}
+
+func (u *eventConsumerUsecase) Get(ctx context.Context, name string) (*entity.EventConsumer, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	return u.find(ctx, name)
+}
+
+func (u *eventConsumerUsecase) Delete(ctx context.Context, name string) error {
+	if err := requireAdmin(ctx); err != nil {
+		return err
+	}
+
+	if err := u.consumers.Delete(ctx, name); err != nil {
+		if errors.Is(err, domainErrors.ErrConsumerNotFound) {
+			return err
+		}
+		return fmt.Errorf("failed to delete event consumer: %w", err)
+	}
+	return nil
+}
+
+func (u *eventConsumerUsecase) Poll(ctx context.Context, name string, limit int) (*entity.ConsumerBatch, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > MaxChangeBatch {
+		limit = MaxChangeBatch
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	var batch *entity.ConsumerBatch
+	err := u.transactor.Transaction(ctx, func(ctx context.Context) error {
+		consumer, err := u.find(ctx, name)
+		if err != nil {
+			return err
+		}
+		now := u.clock.Now()
+		if consumer.Leased(now) {
+			return fmt.Errorf("%w: events %d-%d must be acknowledged or time out at %s first",
+				domainErrors.ErrBatchInFlight, consumer.AckedSequence+1, consumer.DeliveredSequence, consumer.AckDeadline.Format(time.RFC3339))
+		}
+
+		events, err := u.store.LoadAfter(ctx, consumer.AckedSequence, limit)
+		if err != nil {
+			return fmt.Errorf("failed to load events after %d: %w", consumer.AckedSequence, err)
+		}
+		// 読んだイベントより前にならないよう、最後の連番は後から取る
+		last, err := u.store.LastSequence(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to read last sequence: %w", err)
+		}
+		if events == nil {
+			events = []*entity.StoredEvent{}
+		}
+
+		redelivered := consumer.Deliver(events, now)
+		if err := u.consumers.Save(ctx, consumer); err != nil {
+			return fmt.Errorf("failed to save event consumer: %w", err)
+		}
+		batch = &entity.ConsumerBatch{
+			Consumer:     consumer.Name,
+			Events:       events,
+			Redelivered:  redelivered,
+			AckDeadline:  consumer.AckDeadline,
+			LastSequence: last,
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return batch, nil
+}
+
+func (u *eventConsumerUsecase) Ack(ctx context.Context, name string, sequence int64) (*entity.EventConsumer, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if sequence <= 0 {
+		return nil, fmt.Errorf("%w: sequence must be positive", domainErrors.ErrInvalidInput)
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	var acked *entity.EventConsumer
+	err := u.transactor.Transaction(ctx, func(ctx context.Context) error {
+		consumer, err := u.find(ctx, name)
+		if err != nil {
+			return err
+		}
+		if err := consumer.Ack(sequence, u.clock.Now()); err != nil {
+			return fmt.Errorf("%w: %s", domainErrors.ErrInvalidInput, err.Error())
+		}
+		if err := u.consumers.Save(ctx, consumer); err != nil {
+			return fmt.Errorf("failed to save event consumer: %w", err)
+		}
+		acked = consumer
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return acked, nil
+}
+
+func (u *eventConsumerUsecase) find(ctx context.Context, name string) (*entity.EventConsumer, error) {
+	consumer, err := u.consumers.Find(ctx, name)
+	if err != nil {
+		if errors.Is(err, domainErrors.ErrConsumerNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to retrieve event consumer: %w", err)
+	}
+	return consumer, nil
+}
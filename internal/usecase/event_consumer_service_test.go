@@ -0,0 +1,158 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/pkg/clock"
+	"Aicon-assignment/internal/pkg/reqctx"
+)
+
+// 名前をキーにしたイベント消費者
+type fakeEventConsumers struct {
+	consumers map[string]entity.EventConsumer
+}
+
+func (f *fakeEventConsumers) Create(ctx context.Context, consumer *entity.EventConsumer) error {
+	if _, ok := f.consumers[consumer.Name]; ok {
+		return domainErrors.ErrDuplicateEntry
+	}
+	f.consumers[consumer.Name] = *consumer
+	return nil
+}
+
+func (f *fakeEventConsumers) Find(ctx context.Context, name string) (*entity.EventConsumer, error) {
+	consumer, ok := f.consumers[name]
+	if !ok {
+		return nil, domainErrors.ErrConsumerNotFound
+	}
+	return &consumer, nil
+}
+
+func (f *fakeEventConsumers) List(ctx context.Context) ([]*entity.EventConsumer, error) {
+	consumers := []*entity.EventConsumer{}
+	for _, consumer := range f.consumers {
+		consumers = append(consumers, &consumer)
+	}
+	return consumers, nil
+}
+
+func (f *fakeEventConsumers) Save(ctx context.Context, consumer *entity.EventConsumer) error {
+	f.consumers[consumer.Name] = *consumer
+	return nil
+}
+
+func (f *fakeEventConsumers) Delete(ctx context.Context, name string) error {
+	if _, ok := f.consumers[name]; !ok {
+		return domainErrors.ErrConsumerNotFound
+	}
+	delete(f.consumers, name)
+	return nil
+}
+
+func TestEventConsumerUsecase(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	admin := reqctx.WithUserRole(reqctx.WithUserID(context.Background(), 1), entity.UserRoleAdmin)
+	events := []*entity.StoredEvent{{Sequence: 1}, {Sequence: 2}, {Sequence: 3}}
+
+	setup := func() (*fakeEventConsumers, *MockEventStore, *clock.Frozen, EventConsumerUsecase) {
+		consumers := &fakeEventConsumers{consumers: make(map[string]entity.EventConsumer)}
+		store := new(MockEventStore)
+		store.On("LastSequence", mock.Anything).Return(int64(3), nil)
+		frozen := clock.NewFrozen(now)
+		return consumers, store, frozen, NewEventConsumerUsecase(consumers, store, noTransaction{}, frozen)
+	}
+
+	t.Run("正常系: 確認するまでは次を配信せず、確認すると続きを配信する", func(t *testing.T) {
+		_, store, _, uc := setup()
+		store.On("LoadAfter", mock.Anything, int64(0), 2).Return(events[:2], nil)
+		store.On("LoadAfter", mock.Anything, int64(2), 2).Return(events[2:], nil)
+
+		_, err := uc.Create(admin, CreateEventConsumerInput{Name: "erp", AckTimeoutSeconds: 60})
+		require.NoError(t, err)
+
+		batch, err := uc.Poll(admin, "erp", 2)
+		require.NoError(t, err)
+		assert.Len(t, batch.Events, 2)
+		assert.False(t, batch.Redelivered)
+		assert.Equal(t, now.Add(time.Minute), *batch.AckDeadline)
+		assert.Equal(t, int64(3), batch.LastSequence)
+
+		_, err = uc.Poll(admin, "erp", 2)
+		assert.ErrorIs(t, err, domainErrors.ErrBatchInFlight)
+
+		consumer, err := uc.Ack(admin, "erp", 2)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), consumer.AckedSequence)
+
+		batch, err = uc.Poll(admin, "erp", 2)
+		require.NoError(t, err)
+		assert.Equal(t, []*entity.StoredEvent{events[2]}, batch.Events)
+	})
+
+	t.Run("正常系: 期限までに確認されなかったイベントを再配信する", func(t *testing.T) {
+		consumers, store, frozen, uc := setup()
+		store.On("LoadAfter", mock.Anything, int64(0), MaxChangeBatch).Return(events, nil)
+
+		_, err := uc.Create(admin, CreateEventConsumerInput{Name: "erp", AckTimeoutSeconds: 10})
+		require.NoError(t, err)
+		_, err = uc.Poll(admin, "erp", 0)
+		require.NoError(t, err)
+
+		frozen.Advance(10 * time.Second)
+		batch, err := uc.Poll(admin, "erp", 0)
+		require.NoError(t, err)
+		assert.True(t, batch.Redelivered)
+		assert.Equal(t, events, batch.Events)
+		assert.Equal(t, 1, consumers.consumers["erp"].Redeliveries)
+	})
+
+	t.Run("正常系: latest で作成すると既存のイベントを読まない", func(t *testing.T) {
+		_, _, _, uc := setup()
+
+		consumer, err := uc.Create(admin, CreateEventConsumerInput{Name: "erp", Start: entity.ConsumerStartLatest})
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), consumer.AckedSequence)
+		assert.Equal(t, entity.DefaultAckTimeoutSeconds, consumer.AckTimeoutSeconds)
+	})
+
+	t.Run("異常系: 配信していない連番は確認できない", func(t *testing.T) {
+		_, _, _, uc := setup()
+		_, err := uc.Create(admin, CreateEventConsumerInput{Name: "erp"})
+		require.NoError(t, err)
+
+		_, err = uc.Ack(admin, "erp", 1)
+		assert.ErrorIs(t, err, domainErrors.ErrInvalidInput)
+	})
+
+	t.Run("異常系: 同じ名前の消費者は作成できない", func(t *testing.T) {
+		_, _, _, uc := setup()
+		_, err := uc.Create(admin, CreateEventConsumerInput{Name: "erp"})
+		require.NoError(t, err)
+
+		_, err = uc.Create(admin, CreateEventConsumerInput{Name: "erp"})
+		assert.ErrorIs(t, err, domainErrors.ErrDuplicateEntry)
+	})
+
+	t.Run("異常系: 管理者以外は使えない", func(t *testing.T) {
+		_, _, _, uc := setup()
+		member := reqctx.WithUserRole(reqctx.WithUserID(context.Background(), 2), entity.UserRoleMember)
+
+		_, err := uc.Poll(member, "erp", 0)
+		assert.ErrorIs(t, err, domainErrors.ErrForbidden)
+	})
+
+	t.Run("異常系: 存在しない消費者", func(t *testing.T) {
+		_, _, _, uc := setup()
+
+		_, err := uc.Poll(admin, "missing", 0)
+		assert.ErrorIs(t, err, domainErrors.ErrConsumerNotFound)
+	})
+}
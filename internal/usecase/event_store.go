@@ -0,0 +1,132 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	"Aicon-assignment/internal/domain/entity"
+	"Aicon-assignment/internal/domain/eventschema"
+	"Aicon-assignment/internal/pkg/reqctx"
+)
+
+// 複数の発行先に順に渡す
+type Publishers []EventPublisher
+
+func (p Publishers) Publish(ctx context.Context, event *entity.Event) {
+	for _, publisher := range p {
+		publisher.Publish(ctx, event)
+	}
+}
+
+// ドメインイベントをイベントストアに追記する発行先
+// 追記に失敗しても元の操作は成功させ、ログに残す
+type EventRecorder struct {
+	store EventStore
+}
+
+func NewEventRecorder(store EventStore) *EventRecorder {
+	return &EventRecorder{store: store}
+}
+
+func (r *EventRecorder) Publish(ctx context.Context, event *entity.Event) {
+	version := eventschema.Default.Latest()
+	encoded, err := eventschema.Default.Encode(event, version)
+	if err == nil {
+		var payload []byte
+		if payload, err = json.Marshal(encoded); err == nil {
+			err = r.store.Append(ctx, &entity.StoredEvent{
+				EventID:       event.ID,
+				EventType:     event.Type,
+				ItemID:        event.ItemID,
+				SchemaVersion: version,
+				Payload:       payload,
+				OccurredAt:    event.OccurredAt,
+			})
+		}
+	}
+	if err != nil {
+		reqctx.Logger(ctx).Error("failed to append event to store", "event_id", event.ID, "type", event.Type, "error", err)
+	}
+}
+
+// イベントを順に適用して組み立てる読み取りモデル
+type Projection interface {
+	Apply(ctx context.Context, event *entity.Event) error
+}
+
+const replayBatchSize = 500
+
+// イベントストアの全イベントを古い順にプロジェクションへ適用する
+// 適用したイベント数を返す
+func ReplayEvents(ctx context.Context, store EventStore, projection Projection) (int, error) {
+	var applied int
+	var after int64
+
+	for {
+		events, err := store.LoadAfter(ctx, after, replayBatchSize)
+		if err != nil {
+			return applied, fmt.Errorf("failed to load events after %d: %w", after, err)
+		}
+
+		for _, stored := range events {
+			event, err := eventschema.Default.Decode(stored.Payload, stored.SchemaVersion)
+			if err != nil {
+				return applied, fmt.Errorf("failed to decode event %d (%s): %w", stored.Sequence, stored.EventID, err)
+			}
+			if err := projection.Apply(ctx, event); err != nil {
+				return applied, fmt.Errorf("failed to apply event %d (%s): %w", stored.Sequence, stored.EventID, err)
+			}
+			applied++
+			after = stored.Sequence
+		}
+
+		if len(events) < replayBatchSize {
+			return applied, nil
+		}
+	}
+}
+
+// イベントからアイテムの現在の状態を組み立てるプロジェクション
+type ItemProjection struct {
+	mu    sync.RWMutex
+	items map[int64]*entity.Item
+}
+
+func NewItemProjection() *ItemProjection {
+	return &ItemProjection{items: make(map[int64]*entity.Item)}
+}
+
+func (p *ItemProjection) Apply(ctx context.Context, event *entity.Event) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	switch event.Type {
+	case entity.EventItemCreated, entity.EventItemUpdated:
+		if event.Item == nil {
+			return fmt.Errorf("event %s has no item", event.ID)
+		}
+		item := *event.Item
+		p.items[event.ItemID] = &item
+	case entity.EventItemDeleted:
+		delete(p.items, event.ItemID)
+	}
+	return nil
+}
+
+// 組み立てたアイテムをID順に返す
+func (p *ItemProjection) Items() []*entity.Item {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	items := make([]*entity.Item, 0, len(p.items))
+	for _, item := range p.items {
+		copied := *item
+		items = append(items, &copied)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].ID < items[j].ID })
+
+	return items
+}
@@ -0,0 +1,75 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"Aicon-assignment/internal/domain/entity"
+)
+
+// MockEventStore はテスト用のイベントストア
+type MockEventStore struct {
+	mock.Mock
+}
+
+func (m *MockEventStore) Append(ctx context.Context, event *entity.StoredEvent) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
+func (m *MockEventStore) LoadAfter(ctx context.Context, afterSequence int64, limit int) ([]*entity.StoredEvent, error) {
+	args := m.Called(ctx, afterSequence, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.StoredEvent), args.Error(1)
+}
+
+func (m *MockEventStore) LastSequence(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func TestReplayEvents(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// 記録済みのイベントを EventRecorder 経由で作る
+	var stored []*entity.StoredEvent
+	store := new(MockEventStore)
+	store.On("Append", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		event := args.Get(1).(*entity.StoredEvent)
+		event.Sequence = int64(len(stored) + 1)
+		stored = append(stored, event)
+	}).Return(nil)
+
+	recorder := NewEventRecorder(store)
+	watch, _ := entity.NewItemAt(now, "時計1", "時計", "ROLEX", 1000000, "2023-01-01")
+	watch.ID = 1
+	bag, _ := entity.NewItemAt(now, "バッグ1", "バッグ", "HERMES", 500000, "2023-01-02")
+	bag.ID = 2
+	recorder.Publish(context.Background(), &entity.Event{ID: "ev-1", Type: entity.EventItemCreated, ItemID: 1, Item: watch})
+	recorder.Publish(context.Background(), &entity.Event{ID: "ev-2", Type: entity.EventItemCreated, ItemID: 2, Item: bag})
+	updated := *watch
+	updated.PurchasePrice = 1200000
+	recorder.Publish(context.Background(), &entity.Event{ID: "ev-3", Type: entity.EventItemUpdated, ItemID: 1, Item: &updated})
+	recorder.Publish(context.Background(), &entity.Event{ID: "ev-4", Type: entity.EventItemDeleted, ItemID: 2, Item: bag})
+	require.Len(t, stored, 4)
+
+	store.On("LoadAfter", mock.Anything, int64(0), replayBatchSize).Return(stored, nil)
+
+	projection := NewItemProjection()
+	applied, err := ReplayEvents(context.Background(), store, projection)
+
+	require.NoError(t, err)
+	assert.Equal(t, 4, applied)
+	items := projection.Items()
+	require.Len(t, items, 1)
+	assert.Equal(t, int64(1), items[0].ID)
+	assert.Equal(t, 1200000, items[0].PurchasePrice)
+	store.AssertExpectations(t)
+}
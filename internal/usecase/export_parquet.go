@@ -0,0 +1,79 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	"Aicon-assignment/internal/domain/entity"
+	"Aicon-assignment/internal/pkg/parquet"
+)
+
+// items テーブルの列。削除のレコードは change と item_id 以外が null になる
+var itemParquetColumns = []parquet.Column{
+	{Name: "change", Type: parquet.String},
+	{Name: "item_id", Type: parquet.Int64},
+	{Name: "name", Type: parquet.String, Optional: true},
+	{Name: "category", Type: parquet.String, Optional: true},
+	{Name: "brand", Type: parquet.String, Optional: true},
+	{Name: "purchase_price", Type: parquet.Int64, Optional: true},
+	{Name: "purchase_date", Type: parquet.Date, Optional: true},
+	{Name: "organization_id", Type: parquet.Int64, Optional: true},
+	{Name: "created_at", Type: parquet.Timestamp, Optional: true},
+	{Name: "updated_at", Type: parquet.Timestamp, Optional: true},
+}
+
+// テーブルごとに "exports/<テーブル>/<作成日時>-<ウォーターマーク>.parquet" として書き出す
+func (u *exportUsecase) writeParquet(ctx context.Context, export *entity.Export, records []*entity.ExportRecord) ([]*entity.ExportFile, error) {
+	var buf bytes.Buffer
+	w, err := parquet.NewWriter(&buf, itemParquetColumns)
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		if err := w.Write(itemParquetRow(record)...); err != nil {
+			return nil, fmt.Errorf("failed to encode item %d: %w", record.ItemID, err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode items: %w", err)
+	}
+
+	var expiresAt *time.Time
+	if u.fileTTL > 0 {
+		t := export.CreatedAt.Add(u.fileTTL)
+		expiresAt = &t
+	}
+
+	key := fmt.Sprintf("exports/items/%s-%d.parquet", export.CreatedAt.UTC().Format("20060102T150405Z"), export.Watermark)
+	blob, err := u.blobs.Put(ctx, key, &buf, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+	return []*entity.ExportFile{{Table: "items", Location: blob.Location, Rows: len(records), Size: blob.Size, SHA256: blob.SHA256}}, nil
+}
+
+func itemParquetRow(record *entity.ExportRecord) []any {
+	row := make([]any, len(itemParquetColumns))
+	row[0] = record.Change
+	row[1] = record.ItemID
+
+	item := record.Item
+	if item == nil {
+		return row
+	}
+	row[2] = item.Name
+	row[3] = item.Category
+	row[4] = item.Brand
+	row[5] = int64(item.PurchasePrice)
+	if date, err := time.Parse(time.DateOnly, item.PurchaseDate); err == nil {
+		row[6] = date
+	}
+	if item.OrgID != nil {
+		row[7] = *item.OrgID
+	}
+	row[8] = item.CreatedAt
+	row[9] = item.UpdatedAt
+	return row
+}
@@ -0,0 +1,213 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/domain/eventschema"
+	"Aicon-assignment/internal/pkg/clock"
+	"Aicon-assignment/internal/pkg/listquery"
+	"Aicon-assignment/internal/pkg/reqctx"
+)
+
+const exportBatchSize = 500
+
+type ExportUsecase interface {
+	// CreateExport はエクスポートを記録し、レコードとともに返す
+	// SinceExportID を指定した場合は、そのエクスポート以降に作成・更新・削除されたアイテムだけを返す
+	// Format が parquet の場合は、レコードをテーブルごとの Parquet ファイルにしてファイルストアに書き出す
+	// 呼び出し元のユーザーのアイテムだけを対象にする。ユーザーがいない場合は ErrUnauthenticated を返す
+	CreateExport(ctx context.Context, input CreateExportInput) (*ExportResult, error)
+}
+
+type CreateExportInput struct {
+	SinceExportID *int64
+	Format        string // 空の場合は json
+}
+
+type ExportResult struct {
+	*entity.Export
+	Records []*entity.ExportRecord `json:"records"`
+	Files   []*entity.ExportFile   `json:"-"` // parquet の場合に書き出したファイル
+}
+
+type exportUsecase struct {
+	exports  ExportRepository
+	itemRepo ItemRepository
+	events   EventStore
+	blobs    BlobUsecase   // nil の場合は parquet でエクスポートできない
+	fileTTL  time.Duration // 書き出したファイルを残す期間（0 の場合は削除しない）
+	clock    clock.Clock
+}
+
+func NewExportUsecase(exports ExportRepository, itemRepo ItemRepository, events EventStore, blobs BlobUsecase, fileTTL time.Duration, clock clock.Clock) ExportUsecase {
+	return &exportUsecase{
+		exports:  exports,
+		itemRepo: itemRepo,
+		events:   events,
+		blobs:    blobs,
+		fileTTL:  fileTTL,
+		clock:    clock,
+	}
+}
+
+// ウォーターマークはアイテムを読む前に取るため、読んでいる間の変更は次の差分エクスポートにも含まれる
+func (u *exportUsecase) CreateExport(ctx context.Context, input CreateExportInput) (*ExportResult, error) {
+	userID, ok := reqctx.UserID(ctx)
+	if !ok {
+		return nil, domainErrors.ErrUnauthenticated
+	}
+
+	format := input.Format
+	if format == "" {
+		format = entity.ExportFormatJSON
+	}
+	switch format {
+	case entity.ExportFormatJSON:
+	case entity.ExportFormatParquet:
+		if u.blobs == nil {
+			return nil, fmt.Errorf("%w: parquet exports are not available (no file store configured)", domainErrors.ErrInvalidInput)
+		}
+	default:
+		return nil, fmt.Errorf("%w: format must be %s or %s", domainErrors.ErrInvalidInput, entity.ExportFormatJSON, entity.ExportFormatParquet)
+	}
+
+	sinceExportID := input.SinceExportID
+	var since *entity.Export
+	if sinceExportID != nil {
+		var err error
+		since, err = u.exports.FindByID(ctx, *sinceExportID)
+		if errors.Is(err, domainErrors.ErrExportNotFound) {
+			return nil, fmt.Errorf("%w: export %d does not exist", domainErrors.ErrInvalidInput, *sinceExportID)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve export: %w", err)
+		}
+	}
+
+	watermark, err := u.events.LastSequence(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read event watermark: %w", err)
+	}
+
+	var records []*entity.ExportRecord
+	if since == nil {
+		records, err = u.allItems(ctx)
+	} else {
+		records, err = u.changedItems(ctx, userID, since.Watermark, watermark)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	export := &entity.Export{
+		Watermark:     watermark,
+		SinceExportID: sinceExportID,
+		Format:        format,
+		RecordCount:   len(records),
+		CreatedAt:     u.clock.Now(),
+	}
+	result := &ExportResult{Export: export, Records: records}
+
+	// ファイルを書き出せなかったエクスポートを次の差分の起点にしないよう、記録より先に書き出す
+	if format == entity.ExportFormatParquet {
+		result.Files, err = u.writeParquet(ctx, export, records)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := u.exports.Create(ctx, export); err != nil {
+		return nil, fmt.Errorf("failed to save export: %w", err)
+	}
+
+	return result, nil
+}
+
+// 呼び出し元のユーザーの削除されていないアイテムを作成として返す（リポジトリが持ち主で絞り込む）
+func (u *exportUsecase) allItems(ctx context.Context) ([]*entity.ExportRecord, error) {
+	records := []*entity.ExportRecord{}
+	for {
+		items, err := u.itemRepo.FindByQuery(ctx, entity.ItemQuery{
+			Sort:   []listquery.SortField{{Field: "id"}},
+			Limit:  exportBatchSize,
+			Offset: len(records),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to load items: %w", err)
+		}
+		for _, item := range items {
+			records = append(records, &entity.ExportRecord{Change: entity.ExportChangeCreated, ItemID: item.ID, Item: item})
+		}
+		if len(items) < exportBatchSize {
+			return records, nil
+		}
+	}
+}
+
+// from より後、to までのイベントで変更されたアイテムを ID 順に返す
+// 期間内に作成されたアイテムは作成、それ以外は更新とし、現在は存在しないアイテムは削除とする
+// 期間内に作成して削除したアイテムは含めない
+// userID のユーザーのアイテムのイベントだけを使う
+func (u *exportUsecase) changedItems(ctx context.Context, userID, from, to int64) ([]*entity.ExportRecord, error) {
+	createdInRange := make(map[int64]bool)
+	touched := make(map[int64]bool)
+	for after := from; after < to; {
+		events, err := u.events.LoadAfter(ctx, after, exportBatchSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load events after %d: %w", after, err)
+		}
+		if len(events) == 0 {
+			break
+		}
+		for _, event := range events {
+			if event.Sequence > to {
+				break
+			}
+			if !eventOwnedBy(event, userID) {
+				continue
+			}
+			if !touched[event.ItemID] {
+				touched[event.ItemID] = true
+				createdInRange[event.ItemID] = event.EventType == entity.EventItemCreated
+			}
+		}
+		after = events[len(events)-1].Sequence
+	}
+
+	ids := make([]int64, 0, len(touched))
+	for id := range touched {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	records := []*entity.ExportRecord{}
+	for _, id := range ids {
+		item, err := retryTransient(ctx, func() (*entity.Item, error) {
+			return u.itemRepo.FindByID(ctx, id)
+		})
+		switch {
+		case domainErrors.IsNotFoundError(err):
+			if !createdInRange[id] {
+				records = append(records, &entity.ExportRecord{Change: entity.ExportChangeDeleted, ItemID: id})
+			}
+		case err != nil:
+			return nil, fmt.Errorf("failed to load item %d: %w", id, err)
+		case createdInRange[id]:
+			records = append(records, &entity.ExportRecord{Change: entity.ExportChangeCreated, ItemID: id, Item: item})
+		default:
+			records = append(records, &entity.ExportRecord{Change: entity.ExportChangeUpdated, ItemID: id, Item: item})
+		}
+	}
+	return records, nil
+}
+
+// イベントのアイテムの持ち主が userID か。持ち主を記録する前のイベントは誰のものでもないとする
+func eventOwnedBy(stored *entity.StoredEvent, userID int64) bool {
+	event, err := eventschema.Default.Decode(stored.Payload, stored.SchemaVersion)
+	return err == nil && event.Item != nil && event.Item.OwnerID != nil && *event.Item.OwnerID == userID
+}
@@ -0,0 +1,220 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/pkg/clock"
+	"Aicon-assignment/internal/pkg/reqctx"
+)
+
+// MockExportRepository はテスト用のエクスポートの記録
+type MockExportRepository struct {
+	mock.Mock
+}
+
+func (m *MockExportRepository) Create(ctx context.Context, export *entity.Export) error {
+	args := m.Called(ctx, export)
+	return args.Error(0)
+}
+
+func (m *MockExportRepository) FindByID(ctx context.Context, id int64) (*entity.Export, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Export), args.Error(1)
+}
+
+// fakeBlobs は書き出したファイルを保持するテスト用の BlobUsecase
+type fakeBlobs struct {
+	BlobUsecase
+	files     map[string][]byte
+	expiresAt map[string]*time.Time
+	err       error
+}
+
+func (f *fakeBlobs) Put(ctx context.Context, key string, content io.Reader, expiresAt *time.Time) (*entity.Blob, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return nil, err
+	}
+	if f.files == nil {
+		f.files = map[string][]byte{}
+	}
+	if f.expiresAt == nil {
+		f.expiresAt = map[string]*time.Time{}
+	}
+	f.files[key], f.expiresAt[key] = data, expiresAt
+	return &entity.Blob{Key: key, Location: "mem://" + key, Size: int64(len(data)), SHA256: "sha"}, nil
+}
+
+func (f *fakeBlobs) Open(ctx context.Context, key string) (io.ReadCloser, *entity.Blob, error) {
+	data, ok := f.files[key]
+	if !ok {
+		return nil, nil, domainErrors.ErrBlobNotFound
+	}
+	return io.NopCloser(bytes.NewReader(data)), &entity.Blob{Key: key, Size: int64(len(data))}, nil
+}
+
+func TestExportUsecase_CreateExport(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	owner := int64(7)
+	asOwner := reqctx.WithUserID(context.Background(), owner)
+	event := func(eventType string, itemID int64) *entity.Event {
+		return &entity.Event{ID: fmt.Sprintf("ev-%d", itemID), Type: eventType, ItemID: itemID, Item: &entity.Item{ID: itemID, OwnerID: &owner}}
+	}
+
+	t.Run("正常系: 起点を指定しない場合は全アイテムを作成として返す", func(t *testing.T) {
+		exports := new(MockExportRepository)
+		exports.On("Create", mock.Anything, mock.MatchedBy(func(export *entity.Export) bool {
+			return export.Watermark == 7 && export.SinceExportID == nil && export.RecordCount == 2
+		})).Return(nil)
+		events := new(MockEventStore)
+		events.On("LastSequence", mock.Anything).Return(int64(7), nil)
+		items := new(MockItemRepository)
+		items.On("FindByQuery", mock.Anything, mock.Anything).Return([]*entity.Item{{ID: 1}, {ID: 2}}, nil)
+
+		result, err := NewExportUsecase(exports, items, events, nil, 0, clock.NewFrozen(now)).CreateExport(asOwner, CreateExportInput{})
+		require.NoError(t, err)
+		require.Len(t, result.Records, 2)
+		assert.Equal(t, entity.ExportChangeCreated, result.Records[0].Change)
+		exports.AssertExpectations(t)
+	})
+
+	t.Run("正常系: 起点のウォーターマーク以降に変更されたアイテムだけを返す", func(t *testing.T) {
+		source := &fakeChangeSource{}
+		source.publish(t,
+			event(entity.EventItemUpdated, 2),
+			event(entity.EventItemCreated, 5),
+			event(entity.EventItemDeleted, 3),
+			event(entity.EventItemCreated, 6),
+			event(entity.EventItemDeleted, 6),
+		)
+		sinceID := int64(1)
+		exports := new(MockExportRepository)
+		exports.On("FindByID", mock.Anything, sinceID).Return(&entity.Export{ID: 1, Watermark: 0}, nil)
+		exports.On("Create", mock.Anything, mock.Anything).Return(nil)
+		events := new(MockEventStore)
+		events.On("LastSequence", mock.Anything).Return(int64(5), nil)
+		events.On("LoadAfter", mock.Anything, int64(0), exportBatchSize).Return(source.events, nil)
+		items := new(MockItemRepository)
+		items.On("FindByID", mock.Anything, int64(2)).Return(&entity.Item{ID: 2}, nil)
+		items.On("FindByID", mock.Anything, int64(3)).Return(nil, domainErrors.ErrItemNotFound)
+		items.On("FindByID", mock.Anything, int64(5)).Return(&entity.Item{ID: 5}, nil)
+		items.On("FindByID", mock.Anything, int64(6)).Return(nil, domainErrors.ErrItemNotFound)
+
+		result, err := NewExportUsecase(exports, items, events, nil, 0, clock.NewFrozen(now)).CreateExport(asOwner, CreateExportInput{SinceExportID: &sinceID})
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), result.Watermark)
+		assert.Equal(t, []*entity.ExportRecord{
+			{Change: entity.ExportChangeUpdated, ItemID: 2, Item: &entity.Item{ID: 2}},
+			{Change: entity.ExportChangeDeleted, ItemID: 3},
+			{Change: entity.ExportChangeCreated, ItemID: 5, Item: &entity.Item{ID: 5}},
+		}, result.Records)
+	})
+
+	t.Run("正常系: 他のユーザーのアイテムの変更を含めない", func(t *testing.T) {
+		mine, others := owner, int64(8)
+		source := &fakeChangeSource{}
+		source.publish(t,
+			&entity.Event{ID: "ev-1", Type: entity.EventItemUpdated, ItemID: 1, Item: &entity.Item{ID: 1, OwnerID: &mine}},
+			&entity.Event{ID: "ev-2", Type: entity.EventItemDeleted, ItemID: 2, Item: &entity.Item{ID: 2, OwnerID: &others}},
+		)
+		sinceID := int64(1)
+		exports := new(MockExportRepository)
+		exports.On("FindByID", mock.Anything, sinceID).Return(&entity.Export{ID: 1, Watermark: 0}, nil)
+		exports.On("Create", mock.Anything, mock.Anything).Return(nil)
+		events := new(MockEventStore)
+		events.On("LastSequence", mock.Anything).Return(int64(2), nil)
+		events.On("LoadAfter", mock.Anything, int64(0), exportBatchSize).Return(source.events, nil)
+		items := new(MockItemRepository)
+		items.On("FindByID", mock.Anything, int64(1)).Return(&entity.Item{ID: 1, OwnerID: &mine}, nil)
+
+		result, err := NewExportUsecase(exports, items, events, nil, 0, clock.NewFrozen(now)).CreateExport(asOwner, CreateExportInput{SinceExportID: &sinceID})
+		require.NoError(t, err)
+		require.Len(t, result.Records, 1)
+		assert.Equal(t, int64(1), result.Records[0].ItemID)
+		items.AssertNotCalled(t, "FindByID", mock.Anything, int64(2))
+	})
+
+	t.Run("異常系: 存在しないエクスポートを起点にする", func(t *testing.T) {
+		sinceID := int64(9)
+		exports := new(MockExportRepository)
+		exports.On("FindByID", mock.Anything, sinceID).Return(nil, domainErrors.ErrExportNotFound)
+
+		_, err := NewExportUsecase(exports, new(MockItemRepository), new(MockEventStore), nil, 0, clock.NewFrozen(now)).CreateExport(asOwner, CreateExportInput{SinceExportID: &sinceID})
+		assert.ErrorIs(t, err, domainErrors.ErrInvalidInput)
+	})
+
+	t.Run("正常系: parquet では items テーブルのファイルを書き出す", func(t *testing.T) {
+		exports := new(MockExportRepository)
+		exports.On("Create", mock.Anything, mock.MatchedBy(func(export *entity.Export) bool {
+			return export.Format == entity.ExportFormatParquet
+		})).Return(nil)
+		events := new(MockEventStore)
+		events.On("LastSequence", mock.Anything).Return(int64(7), nil)
+		items := new(MockItemRepository)
+		items.On("FindByQuery", mock.Anything, mock.Anything).Return([]*entity.Item{{ID: 1, PurchaseDate: "2023-01-15"}}, nil)
+		files := &fakeBlobs{}
+
+		result, err := NewExportUsecase(exports, items, events, files, 24*time.Hour, clock.NewFrozen(now)).
+			CreateExport(asOwner, CreateExportInput{Format: entity.ExportFormatParquet})
+		require.NoError(t, err)
+		key := "exports/items/20240101T000000Z-7.parquet"
+		data := files.files[key]
+		require.NotEmpty(t, data)
+		assert.Equal(t, "PAR1", string(data[:4]))
+		assert.Equal(t, []*entity.ExportFile{{Table: "items", Location: "mem://" + key, Rows: 1, Size: int64(len(data)), SHA256: "sha"}}, result.Files)
+		assert.Equal(t, now.Add(24*time.Hour), *files.expiresAt[key])
+	})
+
+	t.Run("異常系: ファイルを書き出せない場合はエクスポートを記録しない", func(t *testing.T) {
+		exports := new(MockExportRepository)
+		events := new(MockEventStore)
+		events.On("LastSequence", mock.Anything).Return(int64(7), nil)
+		items := new(MockItemRepository)
+		items.On("FindByQuery", mock.Anything, mock.Anything).Return([]*entity.Item{}, nil)
+
+		_, err := NewExportUsecase(exports, items, events, &fakeBlobs{err: errors.New("disk full")}, 0, clock.NewFrozen(now)).
+			CreateExport(asOwner, CreateExportInput{Format: entity.ExportFormatParquet})
+		require.Error(t, err)
+		exports.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("異常系: ファイルストアがない場合は parquet を使えない", func(t *testing.T) {
+		_, err := NewExportUsecase(new(MockExportRepository), new(MockItemRepository), new(MockEventStore), nil, 0, clock.NewFrozen(now)).
+			CreateExport(asOwner, CreateExportInput{Format: entity.ExportFormatParquet})
+		assert.ErrorIs(t, err, domainErrors.ErrInvalidInput)
+	})
+
+	t.Run("異常系: ユーザーがいない場合はエクスポートしない", func(t *testing.T) {
+		exports := new(MockExportRepository)
+		items := new(MockItemRepository)
+
+		_, err := NewExportUsecase(exports, items, new(MockEventStore), nil, 0, clock.NewFrozen(now)).CreateExport(context.Background(), CreateExportInput{})
+		assert.ErrorIs(t, err, domainErrors.ErrUnauthenticated)
+		items.AssertNotCalled(t, "FindByQuery", mock.Anything, mock.Anything)
+		exports.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("異常系: 不明な形式", func(t *testing.T) {
+		_, err := NewExportUsecase(new(MockExportRepository), new(MockItemRepository), new(MockEventStore), nil, 0, clock.NewFrozen(now)).
+			CreateExport(asOwner, CreateExportInput{Format: "xml"})
+		assert.ErrorIs(t, err, domainErrors.ErrInvalidInput)
+	})
+}
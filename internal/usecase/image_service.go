@@ -0,0 +1,557 @@
+package usecase
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/pkg/cdnurl"
+	"Aicon-assignment/internal/pkg/clock"
+	"Aicon-assignment/internal/pkg/idgen"
+	"Aicon-assignment/internal/pkg/reqctx"
+	"Aicon-assignment/internal/pkg/thumbnail"
+)
+
+// 形式の判定に読む先頭のバイト数（http.DetectContentType が見る範囲）
+const imageSniffLen = 512
+
+// 同時にサムネイルを生成する数。縮小は CPU を使うため、アップロードが重なっても増やさない
+const thumbnailWorkers = 2
+
+type ImageUsecase interface {
+	ItemImageLoader
+
+	// Upload はアイテムに画像を追加する。形式は送られた Content-Type ではなく中身から判定する
+	Upload(ctx context.Context, itemID int64, filename, contentType string, content io.ReadSeeker) (*entity.ItemImage, error)
+	List(ctx context.Context, itemID int64) ([]*entity.ItemImage, error)
+	Get(ctx context.Context, itemID, imageID int64) (*entity.ItemImage, error)
+	// Open は画像のファイルを開く
+	Open(ctx context.Context, itemID, imageID int64) (io.ReadCloser, *entity.ItemImage, error)
+	// OpenThumbnail は生成済みのサムネイルのファイルを開く。生成済みでない場合は ErrThumbnailNotFound を返す
+	OpenThumbnail(ctx context.Context, itemID, imageID int64, size string) (io.ReadCloser, *entity.ImageThumbnail, error)
+	// Update はギャラリーでの順番・一覧に表示する画像を変える
+	Update(ctx context.Context, itemID, imageID int64, input UpdateImageInput) (*entity.ItemImage, error)
+	// Delete は画像の記録とファイルを削除する。一覧に表示する画像を消した場合は、先頭の画像を代わりにする
+	Delete(ctx context.Context, itemID, imageID int64) error
+	// ReprocessThumbnails はサムネイルが未生成・生成に失敗した画像のサムネイルをバックグラウンドで作り直し、対象の件数を返す
+	ReprocessThumbnails(ctx context.Context) (int, error)
+	// Wait は生成中のサムネイルを待つ
+	Wait()
+}
+
+// アイテムの画像を読み込む（アイテムを取得するときに ItemUsecase が使う）
+type ItemImageLoader interface {
+	// ItemImages はアイテムの存在を確かめずに画像をギャラリーの順に返す
+	ItemImages(ctx context.Context, itemID int64) ([]*entity.ItemImage, error)
+	// PrimaryImages はアイテムごとの一覧に表示する画像を返す。画像のないアイテムは含まない
+	PrimaryImages(ctx context.Context, itemIDs []int64) (map[int64]*entity.ItemImage, error)
+}
+
+// 指定しなかった項目は変えない
+type UpdateImageInput struct {
+	Position  *int  `json:"position"`   // ギャラリーでの順番（0 から）。他の画像は詰めて並べ直す
+	IsPrimary *bool `json:"is_primary"` // true で一覧に表示する画像にする。false は指定できない（別の画像を true にする）
+}
+
+// 画像・サムネイルをクライアントが直接取得する URL を返す。version は内容が変わると変わる値（画像の作成日時）
+type ImageURLs interface {
+	URL(key string, version time.Time, now time.Time) (string, error)
+}
+
+// ファイルストアの事前署名 URL（S3 など）
+type presignedImageURLs struct {
+	signer BlobURLSigner
+	ttl    time.Duration
+}
+
+func NewPresignedImageURLs(signer BlobURLSigner, ttl time.Duration) ImageURLs {
+	return &presignedImageURLs{signer: signer, ttl: ttl}
+}
+
+func (p *presignedImageURLs) URL(key string, version time.Time, now time.Time) (string, error) {
+	return p.signer.PresignGet(key, p.ttl, now)
+}
+
+// CDN の署名付き URL。画像を差し替えると作成日時が変わり、URL も変わる
+type cdnImageURLs struct {
+	signer *cdnurl.Signer
+}
+
+func NewCDNImageURLs(signer *cdnurl.Signer) ImageURLs {
+	return &cdnImageURLs{signer: signer}
+}
+
+func (c *cdnImageURLs) URL(key string, version time.Time, now time.Time) (string, error) {
+	return c.signer.URL(key, strconv.FormatInt(version.Unix(), 10), now), nil
+}
+
+type imageUsecase struct {
+	images   ImageRepository
+	itemRepo ItemRepository
+	blobs    BlobUsecase
+	scanner  UploadScanner // nil の場合は検査しない
+	urls     ImageURLs     // nil の場合は API（GET /items/{id}/images/{imageId}）から取得する
+	maxItem  int           // 1 アイテムあたりの枚数の上限（0 は上限なし）
+	clock    clock.Clock
+
+	wg    sync.WaitGroup
+	slots chan struct{} // 同時に生成するサムネイルの数を抑える
+}
+
+func NewImageUsecase(images ImageRepository, itemRepo ItemRepository, blobs BlobUsecase, scanner UploadScanner, urls ImageURLs, maxPerItem int, clock clock.Clock) ImageUsecase {
+	return &imageUsecase{
+		images:   images,
+		itemRepo: itemRepo,
+		blobs:    blobs,
+		scanner:  scanner,
+		urls:     urls,
+		maxItem:  maxPerItem,
+		clock:    clock,
+		slots:    make(chan struct{}, thumbnailWorkers),
+	}
+}
+
+// ファイルを書いてから記録する。記録に失敗した場合は書いたファイルを消す
+func (u *imageUsecase) Upload(ctx context.Context, itemID int64, filename, contentType string, content io.ReadSeeker) (*entity.ItemImage, error) {
+	filename = strings.TrimSpace(filepath.Base(filename))
+	if filename == "" || filename == "." || filename == "/" {
+		return nil, fmt.Errorf("%w: filename is required", domainErrors.ErrInvalidInput)
+	}
+	if _, err := u.itemRepo.FindByID(ctx, itemID); err != nil {
+		return nil, err
+	}
+	gallery, err := u.images.FindByItemID(ctx, itemID)
+	if err != nil {
+		return nil, err
+	}
+	if u.maxItem > 0 && len(gallery) >= u.maxItem {
+		return nil, fmt.Errorf("%w: an item can have at most %d images", domainErrors.ErrInvalidInput, u.maxItem)
+	}
+	upload := entity.Upload{Kind: entity.UploadKindImage, ItemID: itemID, Filename: filename, ContentType: contentType}
+	if u.scanner != nil {
+		if err := u.scanner.ScanUpload(ctx, upload, content); err != nil {
+			return nil, err
+		}
+	}
+
+	r := bufio.NewReaderSize(content, imageSniffLen)
+	head, err := r.Peek(imageSniffLen)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to read image: %w", err)
+	}
+	contentType = http.DetectContentType(head)
+	if _, ok := entity.ImageExtensions[contentType]; !ok {
+		return nil, fmt.Errorf("%w: unsupported image type %s (jpeg, png, gif and webp are accepted)", domainErrors.ErrInvalidInput, contentType)
+	}
+
+	key := entity.ItemImageBlobKey(itemID, idgen.NewRandomID(), contentType)
+	blob, err := u.blobs.Put(ctx, key, r, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	image := &entity.ItemImage{
+		ItemID:          itemID,
+		Filename:        filename,
+		ContentType:     contentType,
+		Size:            blob.Size,
+		Key:             key,
+		Position:        len(gallery),
+		IsPrimary:       len(gallery) == 0,
+		ThumbnailStatus: entity.ThumbnailUnsupported,
+		CreatedAt:       u.clock.Now(),
+	}
+	if thumbnail.Supported(contentType) {
+		image.ThumbnailStatus = entity.ThumbnailPending
+	}
+	if err := u.images.Create(ctx, image); err != nil {
+		if deleteErr := u.blobs.Delete(ctx, key); deleteErr != nil {
+			reqctx.Logger(ctx).Error("failed to delete unrecorded image", "key", key, "error", deleteErr)
+		}
+		return nil, err
+	}
+	if image.ThumbnailStatus == entity.ThumbnailPending {
+		u.generateThumbnailsAsync(ctx, []*entity.ItemImage{image})
+	}
+	if err := u.setURLs(image); err != nil {
+		return nil, err
+	}
+	return image, nil
+}
+
+func (u *imageUsecase) List(ctx context.Context, itemID int64) ([]*entity.ItemImage, error) {
+	if _, err := u.itemRepo.FindByID(ctx, itemID); err != nil {
+		return nil, err
+	}
+	return u.ItemImages(ctx, itemID)
+}
+
+func (u *imageUsecase) ItemImages(ctx context.Context, itemID int64) ([]*entity.ItemImage, error) {
+	images, err := u.images.FindByItemID(ctx, itemID)
+	if err != nil {
+		return nil, err
+	}
+	for _, image := range images {
+		if err := u.setURLs(image); err != nil {
+			return nil, err
+		}
+	}
+	return images, nil
+}
+
+func (u *imageUsecase) PrimaryImages(ctx context.Context, itemIDs []int64) (map[int64]*entity.ItemImage, error) {
+	images, err := u.images.FindPrimaryByItemIDs(ctx, itemIDs)
+	if err != nil {
+		return nil, err
+	}
+	primary := make(map[int64]*entity.ItemImage, len(images))
+	for _, image := range images {
+		if err := u.setURLs(image); err != nil {
+			return nil, err
+		}
+		primary[image.ItemID] = image
+	}
+	return primary, nil
+}
+
+func (u *imageUsecase) Get(ctx context.Context, itemID, imageID int64) (*entity.ItemImage, error) {
+	image, err := u.find(ctx, itemID, imageID)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.setURLs(image); err != nil {
+		return nil, err
+	}
+	return image, nil
+}
+
+func (u *imageUsecase) Open(ctx context.Context, itemID, imageID int64) (io.ReadCloser, *entity.ItemImage, error) {
+	image, err := u.find(ctx, itemID, imageID)
+	if err != nil {
+		return nil, nil, err
+	}
+	body, _, err := u.blobs.Open(ctx, image.Key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return body, image, nil
+}
+
+func (u *imageUsecase) OpenThumbnail(ctx context.Context, itemID, imageID int64, size string) (io.ReadCloser, *entity.ImageThumbnail, error) {
+	image, err := u.find(ctx, itemID, imageID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := u.setURLs(image); err != nil {
+		return nil, nil, err
+	}
+	thumb := image.Thumbnail(size)
+	if thumb == nil {
+		return nil, nil, domainErrors.ErrThumbnailNotFound
+	}
+	body, _, err := u.blobs.Open(ctx, thumb.Key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return body, thumb, nil
+}
+
+func (u *imageUsecase) Update(ctx context.Context, itemID, imageID int64, input UpdateImageInput) (*entity.ItemImage, error) {
+	if input.Position == nil && input.IsPrimary == nil {
+		return nil, fmt.Errorf("%w: position or is_primary is required", domainErrors.ErrInvalidInput)
+	}
+	image, err := u.find(ctx, itemID, imageID)
+	if err != nil {
+		return nil, err
+	}
+	gallery, err := u.images.FindByItemID(ctx, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	primaryID := primaryOf(gallery)
+	if input.IsPrimary != nil {
+		if *input.IsPrimary {
+			primaryID = image.ID
+		} else if primaryID == image.ID {
+			return nil, fmt.Errorf("%w: an item with images needs a primary image, set is_primary on another image instead", domainErrors.ErrInvalidInput)
+		}
+	}
+
+	ids := make([]int64, 0, len(gallery))
+	for _, other := range gallery {
+		if other.ID != image.ID {
+			ids = append(ids, other.ID)
+		}
+	}
+	position := slices.IndexFunc(gallery, func(other *entity.ItemImage) bool { return other.ID == image.ID })
+	if input.Position != nil {
+		if *input.Position < 0 || *input.Position >= len(gallery) {
+			return nil, fmt.Errorf("%w: position must be between 0 and %d", domainErrors.ErrInvalidInput, len(gallery)-1)
+		}
+		position = *input.Position
+	}
+	ids = slices.Insert(ids, position, image.ID)
+
+	if err := u.images.UpdateGallery(ctx, itemID, ids, primaryID); err != nil {
+		return nil, err
+	}
+	return u.Get(ctx, itemID, imageID)
+}
+
+func (u *imageUsecase) Delete(ctx context.Context, itemID, imageID int64) error {
+	image, err := u.find(ctx, itemID, imageID)
+	if err != nil {
+		return err
+	}
+	if err := deleteImage(ctx, u.images, u.blobs, image); err != nil {
+		return err
+	}
+
+	// 残りの画像の順番を詰める。一覧に表示する画像を消した場合は先頭の画像を代わりにする
+	gallery, err := u.images.FindByItemID(ctx, itemID)
+	if err != nil || len(gallery) == 0 {
+		return err
+	}
+	ids := make([]int64, len(gallery))
+	for i, other := range gallery {
+		ids[i] = other.ID
+	}
+	return u.images.UpdateGallery(ctx, itemID, ids, primaryOf(gallery))
+}
+
+// 一覧に表示する画像の ID。ない場合は先頭の画像にする
+func primaryOf(gallery []*entity.ItemImage) int64 {
+	for _, image := range gallery {
+		if image.IsPrimary {
+			return image.ID
+		}
+	}
+	if len(gallery) == 0 {
+		return 0
+	}
+	return gallery[0].ID
+}
+
+// 他のアイテムの画像は見つからないものとして扱う
+func (u *imageUsecase) find(ctx context.Context, itemID, imageID int64) (*entity.ItemImage, error) {
+	image, err := u.images.FindByID(ctx, imageID)
+	if err != nil {
+		return nil, err
+	}
+	if image.ItemID != itemID {
+		return nil, domainErrors.ErrImageNotFound
+	}
+	return image, nil
+}
+
+// 直接取得する URL と、生成済みのサムネイルを付ける
+// 直接取得する URL がない場合、サムネイルの URL は API のパスにする
+func (u *imageUsecase) setURLs(image *entity.ItemImage) error {
+	now := u.clock.Now()
+	if u.urls != nil {
+		url, err := u.urls.URL(image.Key, image.CreatedAt, now)
+		if err != nil {
+			return fmt.Errorf("failed to build image URL: %w", err)
+		}
+		image.URL = url
+	}
+	if image.ThumbnailStatus != entity.ThumbnailReady {
+		return nil
+	}
+
+	image.Thumbnails = thumbnailsOf(image)
+	for i := range image.Thumbnails {
+		thumb := &image.Thumbnails[i]
+		if u.urls == nil {
+			thumb.URL = fmt.Sprintf("/items/%d/images/%d/thumbnails/%s", image.ItemID, image.ID, thumb.Size)
+			continue
+		}
+		url, err := u.urls.URL(thumb.Key, image.CreatedAt, now)
+		if err != nil {
+			return fmt.Errorf("failed to build thumbnail URL: %w", err)
+		}
+		thumb.URL = url
+	}
+	return nil
+}
+
+// 生成できない形式は unsupported にし、それ以外を生成中にしてから順に生成する
+func (u *imageUsecase) ReprocessThumbnails(ctx context.Context) (int, error) {
+	images, err := u.images.FindByThumbnailStatus(ctx, entity.ThumbnailNone, entity.ThumbnailFailed)
+	if err != nil {
+		return 0, err
+	}
+	var queued []*entity.ItemImage
+	for _, image := range images {
+		status := entity.ThumbnailPending
+		if !thumbnail.Supported(image.ContentType) {
+			status = entity.ThumbnailUnsupported
+		}
+		if err := u.images.UpdateThumbnailStatus(ctx, image.ID, status); err != nil {
+			if errors.Is(err, domainErrors.ErrImageNotFound) {
+				continue
+			}
+			return 0, err
+		}
+		if status == entity.ThumbnailPending {
+			queued = append(queued, image)
+		}
+	}
+
+	reqctx.Logger(ctx).Info("thumbnail reprocessing queued", "count", len(queued))
+	u.generateThumbnailsAsync(ctx, queued)
+	return len(queued), nil
+}
+
+func (u *imageUsecase) Wait() {
+	u.wg.Wait()
+}
+
+// リクエストの終了後も生成を続けるため、キャンセルを引き継がずにバックグラウンドで順に生成する
+func (u *imageUsecase) generateThumbnailsAsync(ctx context.Context, images []*entity.ItemImage) {
+	if len(images) == 0 {
+		return
+	}
+	ctx = context.WithoutCancel(ctx)
+
+	u.wg.Add(1)
+	go func() {
+		defer u.wg.Done()
+		for _, image := range images {
+			u.slots <- struct{}{}
+			u.generateThumbnails(ctx, image)
+			<-u.slots
+		}
+	}()
+}
+
+// 生成の結果を記録する。生成中に画像が削除された場合は、書いたサムネイルを消す
+func (u *imageUsecase) generateThumbnails(ctx context.Context, image *entity.ItemImage) {
+	logger := reqctx.Logger(ctx)
+
+	status := entity.ThumbnailReady
+	if err := u.writeThumbnails(ctx, image); err != nil {
+		logger.Warn("thumbnail generation failed", "image_id", image.ID, "error", err)
+		status = entity.ThumbnailFailed
+	}
+	if err := u.images.UpdateThumbnailStatus(ctx, image.ID, status); err != nil {
+		if errors.Is(err, domainErrors.ErrImageNotFound) {
+			deleteThumbnails(ctx, u.blobs, image)
+			return
+		}
+		logger.Error("failed to record thumbnail status", "image_id", image.ID, "status", status, "error", err)
+	}
+}
+
+func (u *imageUsecase) writeThumbnails(ctx context.Context, image *entity.ItemImage) error {
+	body, _, err := u.blobs.Open(ctx, image.Key)
+	if err != nil {
+		return err
+	}
+	data, err := io.ReadAll(body)
+	body.Close()
+	if err != nil {
+		return fmt.Errorf("failed to read image: %w", err)
+	}
+	src, err := thumbnail.Decode(data, image.ContentType)
+	if err != nil {
+		return err
+	}
+
+	for i, thumb := range thumbnailsOf(image) {
+		var buf bytes.Buffer
+		if err := thumbnail.Encode(&buf, thumbnail.Resize(src, entity.ThumbnailSizes[i].MaxEdge), thumb.ContentType); err != nil {
+			return fmt.Errorf("failed to encode %s thumbnail: %w", thumb.Size, err)
+		}
+		if _, err := u.blobs.Put(ctx, thumb.Key, &buf, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 画像のサムネイル（URL なし）を entity.ThumbnailSizes の順に返す。キーと形式は元の画像から決まる
+func thumbnailsOf(image *entity.ItemImage) []entity.ImageThumbnail {
+	contentType := thumbnail.ContentType(image.ContentType)
+	thumbs := make([]entity.ImageThumbnail, len(entity.ThumbnailSizes))
+	for i, size := range entity.ThumbnailSizes {
+		thumbs[i] = entity.ImageThumbnail{
+			Size:        size.Name,
+			ContentType: contentType,
+			Key:         entity.ItemImageThumbnailKey(image.Key, size.Name, contentType),
+		}
+	}
+	return thumbs
+}
+
+// サムネイルのファイルを消す。消せなくても画像の削除は続け、ログに残す
+func deleteThumbnails(ctx context.Context, blobs BlobUsecase, image *entity.ItemImage) {
+	for _, thumb := range thumbnailsOf(image) {
+		if err := blobs.Delete(ctx, thumb.Key); err != nil {
+			reqctx.Logger(ctx).Error("failed to delete thumbnail", "image_id", image.ID, "key", thumb.Key, "error", err)
+		}
+	}
+}
+
+// ファイルを消してから記録を消すため、途中で失敗しても記録が残り、やり直せる
+func deleteImage(ctx context.Context, images ImageRepository, blobs BlobUsecase, image *entity.ItemImage) error {
+	if err := blobs.Delete(ctx, image.Key); err != nil {
+		return err
+	}
+	// 生成中の場合も消す。この後に書かれたサムネイルは、生成の終わりに画像がないことに気づいて消す
+	if image.ThumbnailStatus == entity.ThumbnailReady || image.ThumbnailStatus == entity.ThumbnailPending {
+		deleteThumbnails(ctx, blobs, image)
+	}
+	return images.Delete(ctx, image.ID)
+}
+
+// アイテムの削除イベント（削除・完全削除）を受けて、そのアイテムの画像を削除する発行先
+// 統合・分割による削除イベント（DeleteReason のあるもの）では削除しない
+// 削除に失敗しても元の操作は成功させ、ログに残す
+// 統合・分割で論理削除したアイテムの画像は、完全に削除するときに PurgeItemRecords で削除する
+type ItemImageCleaner struct {
+	images ImageRepository
+	blobs  BlobUsecase
+}
+
+func NewItemImageCleaner(images ImageRepository, blobs BlobUsecase) *ItemImageCleaner {
+	return &ItemImageCleaner{images: images, blobs: blobs}
+}
+
+func (c *ItemImageCleaner) Publish(ctx context.Context, event *entity.Event) {
+	// 統合では画像を統合先に付け替え済み、分割では元のアイテムに残すため消さない
+	if event.Type != entity.EventItemDeleted || event.DeleteReason != "" {
+		return
+	}
+	if err := c.PurgeItemRecords(ctx, event.ItemID); err != nil {
+		reqctx.Logger(ctx).Error("failed to delete images of deleted item", "item_id", event.ItemID, "error", err)
+	}
+}
+
+// アイテムの画像をファイルごとすべて削除する。1枚消せなくても残りは消す
+func (c *ItemImageCleaner) PurgeItemRecords(ctx context.Context, itemID int64) error {
+	images, err := c.images.FindByItemID(ctx, itemID)
+	if err != nil {
+		return fmt.Errorf("failed to find images: %w", err)
+	}
+	var errs []error
+	for _, image := range images {
+		if err := deleteImage(ctx, c.images, c.blobs, image); err != nil {
+			errs = append(errs, fmt.Errorf("image %d: %w", image.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
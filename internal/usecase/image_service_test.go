@@ -0,0 +1,366 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/png"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/pkg/cdnurl"
+	"Aicon-assignment/internal/pkg/clock"
+)
+
+type MockImageRepository struct {
+	mock.Mock
+}
+
+func (m *MockImageRepository) Create(ctx context.Context, image *entity.ItemImage) error {
+	args := m.Called(ctx, image)
+	image.ID = 1
+	return args.Error(0)
+}
+
+func (m *MockImageRepository) FindByID(ctx context.Context, id int64) (*entity.ItemImage, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.ItemImage), args.Error(1)
+}
+
+func (m *MockImageRepository) FindByItemID(ctx context.Context, itemID int64) ([]*entity.ItemImage, error) {
+	args := m.Called(ctx, itemID)
+	return args.Get(0).([]*entity.ItemImage), args.Error(1)
+}
+
+func (m *MockImageRepository) FindPrimaryByItemIDs(ctx context.Context, itemIDs []int64) ([]*entity.ItemImage, error) {
+	args := m.Called(ctx, itemIDs)
+	return args.Get(0).([]*entity.ItemImage), args.Error(1)
+}
+
+func (m *MockImageRepository) UpdateGallery(ctx context.Context, itemID int64, orderedIDs []int64, primaryID int64) error {
+	return m.Called(ctx, itemID, orderedIDs, primaryID).Error(0)
+}
+
+func (m *MockImageRepository) MoveToItem(ctx context.Context, fromItemID, toItemID int64) error {
+	return m.Called(ctx, fromItemID, toItemID).Error(0)
+}
+
+func (m *MockImageRepository) FindByThumbnailStatus(ctx context.Context, statuses ...string) ([]*entity.ItemImage, error) {
+	args := m.Called(ctx, statuses)
+	return args.Get(0).([]*entity.ItemImage), args.Error(1)
+}
+
+func (m *MockImageRepository) UpdateThumbnailStatus(ctx context.Context, id int64, status string) error {
+	return m.Called(ctx, id, status).Error(0)
+}
+
+func (m *MockImageRepository) Delete(ctx context.Context, id int64) error {
+	return m.Called(ctx, id).Error(0)
+}
+
+// PNG のシグネチャで始まる画像（中身は壊れているためサムネイルは作れない）
+var testPNG = append([]byte("\x89PNG\r\n\x1a\n"), bytes.Repeat([]byte{0}, 32)...)
+
+// 読み込める PNG
+func encodeTestPNG(t *testing.T, width, height int) []byte {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, width, height))))
+	return buf.Bytes()
+}
+
+func TestImageUsecase(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	existingItem := func() *MockItemRepository {
+		items := new(MockItemRepository)
+		items.On("FindByID", mock.Anything, int64(1)).Return(&entity.Item{ID: 1}, nil)
+		return items
+	}
+
+	t.Run("正常系: 中身から形式を判定してファイルストアに書き、記録する", func(t *testing.T) {
+		repo := new(MockImageRepository)
+		repo.On("FindByItemID", mock.Anything, int64(1)).Return([]*entity.ItemImage{}, nil)
+		repo.On("Create", mock.Anything, mock.Anything).Return(nil)
+		repo.On("UpdateThumbnailStatus", mock.Anything, int64(1), entity.ThumbnailFailed).Return(nil)
+		blobs := &fakeBlobs{}
+
+		u := NewImageUsecase(repo, existingItem(), blobs, nil, nil, 0, clock.NewFrozen(now))
+		image, err := u.Upload(ctx, 1, "../front.png", "image/png", bytes.NewReader(testPNG))
+		require.NoError(t, err)
+		u.Wait()
+		assert.Equal(t, "front.png", image.Filename)
+		assert.Equal(t, "image/png", image.ContentType)
+		assert.Equal(t, int64(len(testPNG)), image.Size)
+		assert.True(t, strings.HasPrefix(image.Key, "images/items/1/"))
+		assert.True(t, strings.HasSuffix(image.Key, ".png"))
+		assert.Equal(t, testPNG, blobs.files[image.Key])
+		assert.Equal(t, now, image.CreatedAt)
+		assert.True(t, image.IsPrimary)
+	})
+
+	t.Run("正常系: 2 枚目以降はギャラリーの末尾に追加する", func(t *testing.T) {
+		repo := new(MockImageRepository)
+		repo.On("FindByItemID", mock.Anything, int64(1)).Return([]*entity.ItemImage{{ID: 5, ItemID: 1, IsPrimary: true}}, nil)
+		repo.On("Create", mock.Anything, mock.Anything).Return(nil)
+		repo.On("UpdateThumbnailStatus", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+		u := NewImageUsecase(repo, existingItem(), &fakeBlobs{}, nil, nil, 0, clock.NewFrozen(now))
+		image, err := u.Upload(ctx, 1, "back.png", "image/png", bytes.NewReader(testPNG))
+		require.NoError(t, err)
+		u.Wait()
+		assert.Equal(t, 1, image.Position)
+		assert.False(t, image.IsPrimary)
+	})
+
+	t.Run("異常系: 枚数の上限を超える画像は受け付けない", func(t *testing.T) {
+		repo := new(MockImageRepository)
+		repo.On("FindByItemID", mock.Anything, int64(1)).Return([]*entity.ItemImage{{ID: 5}, {ID: 6}}, nil)
+		blobs := &fakeBlobs{}
+
+		_, err := NewImageUsecase(repo, existingItem(), blobs, nil, nil, 2, clock.NewFrozen(now)).Upload(ctx, 1, "front.png", "image/png", bytes.NewReader(testPNG))
+		assert.True(t, domainErrors.IsValidationError(err))
+		assert.Empty(t, blobs.files)
+	})
+
+	t.Run("正常系: アップロード後にバックグラウンドでサムネイルを作る", func(t *testing.T) {
+		repo := new(MockImageRepository)
+		repo.On("FindByItemID", mock.Anything, int64(1)).Return([]*entity.ItemImage{}, nil)
+		repo.On("Create", mock.Anything, mock.Anything).Return(nil)
+		repo.On("UpdateThumbnailStatus", mock.Anything, int64(1), entity.ThumbnailReady).Return(nil)
+		blobs := &fakeBlobs{}
+
+		u := NewImageUsecase(repo, existingItem(), blobs, nil, nil, 0, clock.NewFrozen(now))
+		uploaded, err := u.Upload(ctx, 1, "front.png", "image/png", bytes.NewReader(encodeTestPNG(t, 1000, 500)))
+		require.NoError(t, err)
+		assert.Equal(t, entity.ThumbnailPending, uploaded.ThumbnailStatus)
+		assert.Empty(t, uploaded.Thumbnails)
+		u.Wait()
+
+		base := strings.TrimSuffix(uploaded.Key, ".png")
+		small, _, err := image.DecodeConfig(bytes.NewReader(blobs.files[base+"_small.png"]))
+		require.NoError(t, err)
+		assert.Equal(t, []int{200, 100}, []int{small.Width, small.Height})
+		medium, _, err := image.DecodeConfig(bytes.NewReader(blobs.files[base+"_medium.png"]))
+		require.NoError(t, err)
+		assert.Equal(t, []int{800, 400}, []int{medium.Width, medium.Height})
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("正常系: 生成済みのサムネイルに API の URL を付ける", func(t *testing.T) {
+		repo := new(MockImageRepository)
+		repo.On("FindByItemID", mock.Anything, int64(1)).Return([]*entity.ItemImage{
+			{ID: 5, ItemID: 1, ContentType: "image/jpeg", Key: "images/items/1/a.jpg", ThumbnailStatus: entity.ThumbnailReady},
+			{ID: 6, ItemID: 1, ContentType: "image/png", Key: "images/items/1/b.png", ThumbnailStatus: entity.ThumbnailPending},
+		}, nil)
+
+		images, err := NewImageUsecase(repo, existingItem(), &fakeBlobs{}, nil, nil, 0, clock.NewFrozen(now)).List(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, []entity.ImageThumbnail{
+			{Size: "small", ContentType: "image/jpeg", Key: "images/items/1/a_small.jpg", URL: "/items/1/images/5/thumbnails/small"},
+			{Size: "medium", ContentType: "image/jpeg", Key: "images/items/1/a_medium.jpg", URL: "/items/1/images/5/thumbnails/medium"},
+		}, images[0].Thumbnails)
+		assert.Empty(t, images[1].Thumbnails)
+	})
+
+	t.Run("異常系: 生成済みでないサムネイルは見つからない", func(t *testing.T) {
+		repo := new(MockImageRepository)
+		repo.On("FindByID", mock.Anything, int64(5)).Return(&entity.ItemImage{ID: 5, ItemID: 1, ThumbnailStatus: entity.ThumbnailFailed}, nil)
+
+		_, _, err := NewImageUsecase(repo, existingItem(), &fakeBlobs{}, nil, nil, 0, clock.NewFrozen(now)).OpenThumbnail(ctx, 1, 5, "small")
+		assert.ErrorIs(t, err, domainErrors.ErrThumbnailNotFound)
+	})
+
+	t.Run("正常系: 未生成・失敗した画像のサムネイルを作り直す", func(t *testing.T) {
+		blobs := &fakeBlobs{files: map[string][]byte{"images/items/1/a.png": encodeTestPNG(t, 300, 300)}}
+		repo := new(MockImageRepository)
+		repo.On("FindByThumbnailStatus", mock.Anything, []string{entity.ThumbnailNone, entity.ThumbnailFailed}).Return([]*entity.ItemImage{
+			{ID: 5, ItemID: 1, ContentType: "image/png", Key: "images/items/1/a.png", ThumbnailStatus: entity.ThumbnailNone},
+			{ID: 6, ItemID: 1, ContentType: "image/webp", Key: "images/items/1/b.webp", ThumbnailStatus: entity.ThumbnailNone},
+		}, nil)
+		repo.On("UpdateThumbnailStatus", mock.Anything, int64(5), entity.ThumbnailPending).Return(nil)
+		repo.On("UpdateThumbnailStatus", mock.Anything, int64(6), entity.ThumbnailUnsupported).Return(nil)
+		repo.On("UpdateThumbnailStatus", mock.Anything, int64(5), entity.ThumbnailReady).Return(nil)
+
+		u := NewImageUsecase(repo, existingItem(), blobs, nil, nil, 0, clock.NewFrozen(now))
+		queued, err := u.ReprocessThumbnails(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, queued)
+		u.Wait()
+
+		assert.Contains(t, blobs.files, "images/items/1/a_small.png")
+		assert.Contains(t, blobs.files, "images/items/1/a_medium.png")
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("異常系: 生成中に削除された画像のサムネイルは残さない", func(t *testing.T) {
+		blobs := &deletingBlobs{fakeBlobs: fakeBlobs{files: map[string][]byte{"images/items/1/a.png": encodeTestPNG(t, 10, 10)}}}
+		repo := new(MockImageRepository)
+		repo.On("FindByThumbnailStatus", mock.Anything, mock.Anything).Return([]*entity.ItemImage{
+			{ID: 5, ItemID: 1, ContentType: "image/png", Key: "images/items/1/a.png", ThumbnailStatus: entity.ThumbnailFailed},
+		}, nil)
+		repo.On("UpdateThumbnailStatus", mock.Anything, int64(5), entity.ThumbnailPending).Return(nil)
+		repo.On("UpdateThumbnailStatus", mock.Anything, int64(5), entity.ThumbnailReady).Return(domainErrors.ErrImageNotFound)
+
+		u := NewImageUsecase(repo, existingItem(), blobs, nil, nil, 0, clock.NewFrozen(now))
+		_, err := u.ReprocessThumbnails(ctx)
+		require.NoError(t, err)
+		u.Wait()
+
+		assert.Equal(t, []string{"images/items/1/a_small.png", "images/items/1/a_medium.png"}, blobs.deleted)
+	})
+
+	t.Run("異常系: 画像でないファイルは受け付けない", func(t *testing.T) {
+		repo := new(MockImageRepository)
+		repo.On("FindByItemID", mock.Anything, int64(1)).Return([]*entity.ItemImage{}, nil)
+		blobs := &fakeBlobs{}
+		_, err := NewImageUsecase(repo, existingItem(), blobs, nil, nil, 0, clock.NewFrozen(now)).Upload(ctx, 1, "front.png", "image/png", strings.NewReader("%PDF-1.7"))
+		assert.True(t, domainErrors.IsValidationError(err))
+		assert.Empty(t, blobs.files)
+	})
+
+	t.Run("異常系: 存在しないアイテム", func(t *testing.T) {
+		items := new(MockItemRepository)
+		items.On("FindByID", mock.Anything, int64(2)).Return(nil, domainErrors.ErrItemNotFound)
+		_, err := NewImageUsecase(new(MockImageRepository), items, &fakeBlobs{}, nil, nil, 0, clock.NewFrozen(now)).Upload(ctx, 2, "front.png", "image/png", bytes.NewReader(testPNG))
+		assert.ErrorIs(t, err, domainErrors.ErrItemNotFound)
+	})
+
+	t.Run("異常系: 記録に失敗した場合は書いたファイルを消す", func(t *testing.T) {
+		repo := new(MockImageRepository)
+		repo.On("FindByItemID", mock.Anything, int64(1)).Return([]*entity.ItemImage{}, nil)
+		repo.On("Create", mock.Anything, mock.Anything).Return(domainErrors.ErrDatabaseError)
+		blobs := &deletingBlobs{}
+
+		_, err := NewImageUsecase(repo, existingItem(), blobs, nil, nil, 0, clock.NewFrozen(now)).Upload(ctx, 1, "front.png", "image/png", bytes.NewReader(testPNG))
+		assert.ErrorIs(t, err, domainErrors.ErrDatabaseError)
+		require.Len(t, blobs.deleted, 1)
+		assert.True(t, strings.HasPrefix(blobs.deleted[0], "images/items/1/"))
+	})
+
+	t.Run("正常系: 削除するとファイルと記録を消す", func(t *testing.T) {
+		repo := new(MockImageRepository)
+		repo.On("FindByID", mock.Anything, int64(5)).Return(&entity.ItemImage{ID: 5, ItemID: 1, Key: "images/items/1/a.png"}, nil)
+		repo.On("Delete", mock.Anything, int64(5)).Return(nil)
+		repo.On("FindByItemID", mock.Anything, int64(1)).Return([]*entity.ItemImage{}, nil)
+		blobs := &deletingBlobs{}
+
+		require.NoError(t, NewImageUsecase(repo, existingItem(), blobs, nil, nil, 0, clock.NewFrozen(now)).Delete(ctx, 1, 5))
+		assert.Equal(t, []string{"images/items/1/a.png"}, blobs.deleted)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("正常系: 一覧に表示する画像を消すと、先頭の画像を代わりにして順番を詰める", func(t *testing.T) {
+		repo := new(MockImageRepository)
+		repo.On("FindByID", mock.Anything, int64(5)).Return(&entity.ItemImage{ID: 5, ItemID: 1, IsPrimary: true}, nil)
+		repo.On("Delete", mock.Anything, int64(5)).Return(nil)
+		repo.On("FindByItemID", mock.Anything, int64(1)).Return([]*entity.ItemImage{{ID: 7, Position: 1}, {ID: 6, Position: 2}}, nil)
+		repo.On("UpdateGallery", mock.Anything, int64(1), []int64{7, 6}, int64(7)).Return(nil)
+
+		require.NoError(t, NewImageUsecase(repo, existingItem(), &deletingBlobs{}, nil, nil, 0, clock.NewFrozen(now)).Delete(ctx, 1, 5))
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("正常系: 順番を変えると他の画像を詰めて並べ直す", func(t *testing.T) {
+		repo := new(MockImageRepository)
+		repo.On("FindByID", mock.Anything, int64(7)).Return(&entity.ItemImage{ID: 7, ItemID: 1, Position: 2}, nil)
+		repo.On("FindByItemID", mock.Anything, int64(1)).Return([]*entity.ItemImage{
+			{ID: 5, ItemID: 1, Position: 0, IsPrimary: true},
+			{ID: 6, ItemID: 1, Position: 1},
+			{ID: 7, ItemID: 1, Position: 2},
+		}, nil)
+		repo.On("UpdateGallery", mock.Anything, int64(1), []int64{7, 5, 6}, int64(7)).Return(nil)
+
+		position, primary := 0, true
+		_, err := NewImageUsecase(repo, existingItem(), &fakeBlobs{}, nil, nil, 0, clock.NewFrozen(now)).Update(ctx, 1, 7, UpdateImageInput{Position: &position, IsPrimary: &primary})
+		require.NoError(t, err)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("異常系: 一覧に表示する画像は false にできない", func(t *testing.T) {
+		repo := new(MockImageRepository)
+		repo.On("FindByID", mock.Anything, int64(5)).Return(&entity.ItemImage{ID: 5, ItemID: 1, IsPrimary: true}, nil)
+		repo.On("FindByItemID", mock.Anything, int64(1)).Return([]*entity.ItemImage{{ID: 5, ItemID: 1, IsPrimary: true}}, nil)
+
+		primary := false
+		_, err := NewImageUsecase(repo, existingItem(), &fakeBlobs{}, nil, nil, 0, clock.NewFrozen(now)).Update(ctx, 1, 5, UpdateImageInput{IsPrimary: &primary})
+		assert.True(t, domainErrors.IsValidationError(err))
+		repo.AssertNotCalled(t, "UpdateGallery", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("異常系: 範囲外の順番", func(t *testing.T) {
+		repo := new(MockImageRepository)
+		repo.On("FindByID", mock.Anything, int64(5)).Return(&entity.ItemImage{ID: 5, ItemID: 1}, nil)
+		repo.On("FindByItemID", mock.Anything, int64(1)).Return([]*entity.ItemImage{{ID: 5, ItemID: 1, IsPrimary: true}}, nil)
+
+		position := 1
+		_, err := NewImageUsecase(repo, existingItem(), &fakeBlobs{}, nil, nil, 0, clock.NewFrozen(now)).Update(ctx, 1, 5, UpdateImageInput{Position: &position})
+		assert.True(t, domainErrors.IsValidationError(err))
+	})
+
+	t.Run("正常系: 直接取得する URL を付ける", func(t *testing.T) {
+		repo := new(MockImageRepository)
+		repo.On("FindByItemID", mock.Anything, int64(1)).Return([]*entity.ItemImage{{ID: 5, ItemID: 1, ContentType: "image/png", Key: "images/items/1/a.png", ThumbnailStatus: entity.ThumbnailReady, CreatedAt: now}}, nil)
+		urls := NewCDNImageURLs(cdnurl.NewSigner("https://cdn.example.com", "", time.Hour))
+
+		images, err := NewImageUsecase(repo, existingItem(), &fakeBlobs{}, nil, urls, 0, clock.NewFrozen(now)).List(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, images, 1)
+		assert.Equal(t, "https://cdn.example.com/images/items/1/a.png?v=1717243200", images[0].URL)
+		require.Len(t, images[0].Thumbnails, 2)
+		assert.Equal(t, "https://cdn.example.com/images/items/1/a_small.png?v=1717243200", images[0].Thumbnails[0].URL)
+	})
+
+	t.Run("異常系: 他のアイテムの画像は見つからない", func(t *testing.T) {
+		repo := new(MockImageRepository)
+		repo.On("FindByID", mock.Anything, int64(5)).Return(&entity.ItemImage{ID: 5, ItemID: 2}, nil)
+
+		err := NewImageUsecase(repo, existingItem(), &deletingBlobs{}, nil, nil, 0, clock.NewFrozen(now)).Delete(ctx, 1, 5)
+		assert.ErrorIs(t, err, domainErrors.ErrImageNotFound)
+		repo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+}
+
+func TestItemImageCleaner(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("正常系: アイテムの削除イベントで画像をすべて消す", func(t *testing.T) {
+		repo := new(MockImageRepository)
+		repo.On("FindByItemID", mock.Anything, int64(1)).Return([]*entity.ItemImage{
+			{ID: 5, ItemID: 1, Key: "images/items/1/a.png"},
+			{ID: 6, ItemID: 1, Key: "images/items/1/b.jpg"},
+		}, nil)
+		repo.On("Delete", mock.Anything, mock.Anything).Return(nil)
+		blobs := &deletingBlobs{}
+
+		NewItemImageCleaner(repo, blobs).Publish(ctx, &entity.Event{Type: entity.EventItemDeleted, ItemID: 1})
+		assert.Equal(t, []string{"images/items/1/a.png", "images/items/1/b.jpg"}, blobs.deleted)
+		repo.AssertNumberOfCalls(t, "Delete", 2)
+	})
+
+	t.Run("正常系: 削除以外のイベントは無視する", func(t *testing.T) {
+		repo := new(MockImageRepository)
+		NewItemImageCleaner(repo, &deletingBlobs{}).Publish(ctx, &entity.Event{Type: entity.EventItemUpdated, ItemID: 1})
+		repo.AssertNotCalled(t, "FindByItemID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("正常系: 統合・分割による削除では画像を消さない", func(t *testing.T) {
+		for _, reason := range []string{entity.DeleteReasonMerged, entity.DeleteReasonSplit} {
+			repo := new(MockImageRepository)
+			blobs := &deletingBlobs{}
+			NewItemImageCleaner(repo, blobs).Publish(ctx, &entity.Event{Type: entity.EventItemDeleted, ItemID: 1, DeleteReason: reason})
+			repo.AssertNotCalled(t, "FindByItemID", mock.Anything, mock.Anything)
+			assert.Empty(t, blobs.deleted, reason)
+		}
+	})
+}
@@ -0,0 +1,161 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/pkg/clock"
+	"Aicon-assignment/internal/pkg/idgen"
+	"Aicon-assignment/internal/pkg/reqctx"
+)
+
+// なりすまし用トークンの接頭辞。通常の認証情報と見分けられるようにする
+const ImpersonationTokenPrefix = "imp_"
+
+type ImpersonationUsecase interface {
+	Start(ctx context.Context, userID int64, input StartImpersonationInput) (*entity.ImpersonationSession, error)
+	List(ctx context.Context, activeOnly bool) ([]*entity.ImpersonationSession, error)
+	End(ctx context.Context, id int64) (*entity.ImpersonationSession, error)
+	Authenticate(ctx context.Context, token string) (*entity.ImpersonationSession, error)
+}
+
+type StartImpersonationInput struct {
+	Reason     string `json:"reason"`
+	TTLMinutes int    `json:"ttl_minutes"` // 省略時は entity.DefaultImpersonationTTL
+}
+
+type impersonationUsecase struct {
+	sessions ImpersonationRepository
+	users    UserRepository
+	clock    clock.Clock
+}
+
+func NewImpersonationUsecase(sessions ImpersonationRepository, users UserRepository, clock clock.Clock) ImpersonationUsecase {
+	return &impersonationUsecase{
+		sessions: sessions,
+		users:    users,
+		clock:    clock,
+	}
+}
+
+// 呼び出し元の管理者として userID のユーザーになりすますセッションを開始する
+// 返すセッションにだけトークンが含まれる
+func (u *impersonationUsecase) Start(ctx context.Context, userID int64, input StartImpersonationInput) (*entity.ImpersonationSession, error) {
+	adminID, ok := reqctx.UserID(ctx)
+	if !ok {
+		return nil, domainErrors.ErrUnauthenticated
+	}
+	if _, impersonating := reqctx.ImpersonatorID(ctx); impersonating {
+		return nil, fmt.Errorf("%w: cannot start impersonation while impersonating", domainErrors.ErrForbidden)
+	}
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	target, err := u.users.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !target.Active {
+		return nil, fmt.Errorf("%w: user %d is deactivated", domainErrors.ErrInvalidInput, userID)
+	}
+
+	ttl := entity.DefaultImpersonationTTL
+	if input.TTLMinutes != 0 {
+		ttl = time.Duration(input.TTLMinutes) * time.Minute
+	}
+
+	token := idgen.NewRandomID()
+	if token != "" {
+		token = ImpersonationTokenPrefix + token
+	}
+
+	session, err := entity.NewImpersonationSession(adminID, userID, input.Reason, token, ttl, u.clock.Now())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrInvalidInput, err.Error())
+	}
+
+	if err := u.sessions.Create(ctx, session); err != nil {
+		return nil, fmt.Errorf("failed to create impersonation session: %w", err)
+	}
+
+	reqctx.Logger(ctx).Info("impersonation started",
+		"session_id", session.ID, "admin_id", adminID, "user_id", userID, "expires_at", session.ExpiresAt)
+	return session, nil
+}
+
+// activeOnly の場合は期限内で終了していないセッションだけを返す
+func (u *impersonationUsecase) List(ctx context.Context, activeOnly bool) ([]*entity.ImpersonationSession, error) {
+	sessions, err := u.sessions.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve impersonation sessions: %w", err)
+	}
+	if !activeOnly {
+		return sessions, nil
+	}
+
+	now := u.clock.Now()
+	active := []*entity.ImpersonationSession{}
+	for _, session := range sessions {
+		if session.Active(now) {
+			active = append(active, session)
+		}
+	}
+	return active, nil
+}
+
+// 期限前にセッションを終了する。終了済み・期限切れの場合はそのまま返す
+func (u *impersonationUsecase) End(ctx context.Context, id int64) (*entity.ImpersonationSession, error) {
+	if id <= 0 {
+		return nil, domainErrors.ErrInvalidInput
+	}
+
+	session, err := u.sessions.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := u.clock.Now()
+	if !session.Active(now) {
+		return session, nil
+	}
+
+	if err := u.sessions.End(ctx, id, now); err != nil {
+		return nil, fmt.Errorf("failed to end impersonation session: %w", err)
+	}
+	session.EndedAt = &now
+
+	reqctx.Logger(ctx).Info("impersonation ended",
+		"session_id", session.ID, "admin_id", session.AdminID, "user_id", session.UserID)
+	return session, nil
+}
+
+// トークンに対応する有効なセッションを返す
+// 存在しない・期限切れ・終了済みの場合や、管理者・対象ユーザーが無効化された場合は ErrUnauthenticated
+func (u *impersonationUsecase) Authenticate(ctx context.Context, token string) (*entity.ImpersonationSession, error) {
+	session, err := u.sessions.FindByTokenHash(ctx, entity.HashToken(token))
+	if err != nil {
+		if errors.Is(err, domainErrors.ErrImpersonationNotFound) {
+			return nil, domainErrors.ErrUnauthenticated
+		}
+		return nil, fmt.Errorf("failed to retrieve impersonation session: %w", err)
+	}
+	if !session.Active(u.clock.Now()) {
+		return nil, domainErrors.ErrUnauthenticated
+	}
+
+	for _, id := range []int64{session.AdminID, session.UserID} {
+		user, err := u.users.FindByID(ctx, id)
+		if errors.Is(err, domainErrors.ErrUserNotFound) || (err == nil && !user.Active) {
+			return nil, domainErrors.ErrUnauthenticated
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve user: %w", err)
+		}
+	}
+	return session, nil
+}
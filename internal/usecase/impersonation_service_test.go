@@ -0,0 +1,253 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/pkg/clock"
+	"Aicon-assignment/internal/pkg/reqctx"
+)
+
+// MockImpersonationRepository はテスト用のなりすましセッションリポジトリ
+type MockImpersonationRepository struct {
+	mock.Mock
+}
+
+func (m *MockImpersonationRepository) Create(ctx context.Context, session *entity.ImpersonationSession) error {
+	args := m.Called(ctx, session)
+	return args.Error(0)
+}
+
+func (m *MockImpersonationRepository) FindByID(ctx context.Context, id int64) (*entity.ImpersonationSession, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.ImpersonationSession), args.Error(1)
+}
+
+func (m *MockImpersonationRepository) FindByTokenHash(ctx context.Context, tokenHash string) (*entity.ImpersonationSession, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.ImpersonationSession), args.Error(1)
+}
+
+func (m *MockImpersonationRepository) FindAll(ctx context.Context) ([]*entity.ImpersonationSession, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.ImpersonationSession), args.Error(1)
+}
+
+func (m *MockImpersonationRepository) End(ctx context.Context, id int64, at time.Time) error {
+	args := m.Called(ctx, id, at)
+	return args.Error(0)
+}
+
+// ユーザー 1〜3 は有効、4 は無効化済み、それ以外は存在しない
+func newImpersonationUsers() *MockUserRepository {
+	users := new(MockUserRepository)
+	for id := int64(1); id <= 4; id++ {
+		users.On("FindByID", mock.Anything, id).Return(&entity.User{ID: id, Active: id != 4}, nil).Maybe()
+	}
+	users.On("FindByID", mock.Anything, mock.Anything).Return(nil, domainErrors.ErrUserNotFound).Maybe()
+	return users
+}
+
+func TestImpersonationUsecase_Start(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	admin := reqctx.WithUserRole(reqctx.WithUserID(context.Background(), 1), entity.UserRoleAdmin)
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		userID    int64
+		input     StartImpersonationInput
+		setupMock func(*MockImpersonationRepository)
+		wantTTL   time.Duration
+		wantErr   error
+	}{
+		{
+			name:   "正常系: 有効期間を省略した場合はデフォルト",
+			ctx:    admin,
+			userID: 2,
+			input:  StartImpersonationInput{Reason: "問い合わせ対応"},
+			setupMock: func(m *MockImpersonationRepository) {
+				m.On("Create", mock.Anything, mock.AnythingOfType("*entity.ImpersonationSession")).Return(nil)
+			},
+			wantTTL: entity.DefaultImpersonationTTL,
+		},
+		{
+			name:   "正常系: 有効期間を指定",
+			ctx:    admin,
+			userID: 2,
+			input:  StartImpersonationInput{Reason: "問い合わせ対応", TTLMinutes: 5},
+			setupMock: func(m *MockImpersonationRepository) {
+				m.On("Create", mock.Anything, mock.AnythingOfType("*entity.ImpersonationSession")).Return(nil)
+			},
+			wantTTL: 5 * time.Minute,
+		},
+		{
+			name:      "異常系: 呼び出し元が不明",
+			ctx:       context.Background(),
+			userID:    2,
+			input:     StartImpersonationInput{Reason: "問い合わせ対応"},
+			setupMock: func(m *MockImpersonationRepository) {},
+			wantErr:   domainErrors.ErrUnauthenticated,
+		},
+		{
+			name:      "異常系: なりすまし中にはなりすましを開始できない",
+			ctx:       reqctx.WithImpersonatorID(reqctx.WithUserID(context.Background(), 2), 1),
+			userID:    3,
+			input:     StartImpersonationInput{Reason: "問い合わせ対応"},
+			setupMock: func(m *MockImpersonationRepository) {},
+			wantErr:   domainErrors.ErrForbidden,
+		},
+		{
+			name:      "異常系: 管理者でないユーザー",
+			ctx:       reqctx.WithUserRole(reqctx.WithUserID(context.Background(), 2), entity.UserRoleMember),
+			userID:    3,
+			input:     StartImpersonationInput{Reason: "問い合わせ対応"},
+			setupMock: func(m *MockImpersonationRepository) {},
+			wantErr:   domainErrors.ErrForbidden,
+		},
+		{
+			name:      "異常系: 理由がない",
+			ctx:       admin,
+			userID:    2,
+			input:     StartImpersonationInput{},
+			setupMock: func(m *MockImpersonationRepository) {},
+			wantErr:   domainErrors.ErrInvalidInput,
+		},
+		{
+			name:      "異常系: 自分自身",
+			ctx:       admin,
+			userID:    1,
+			input:     StartImpersonationInput{Reason: "問い合わせ対応"},
+			setupMock: func(m *MockImpersonationRepository) {},
+			wantErr:   domainErrors.ErrInvalidInput,
+		},
+		{
+			name:      "異常系: 存在しないユーザー",
+			ctx:       admin,
+			userID:    99,
+			input:     StartImpersonationInput{Reason: "問い合わせ対応"},
+			setupMock: func(m *MockImpersonationRepository) {},
+			wantErr:   domainErrors.ErrUserNotFound,
+		},
+		{
+			name:      "異常系: 無効化されたユーザー",
+			ctx:       admin,
+			userID:    4,
+			input:     StartImpersonationInput{Reason: "問い合わせ対応"},
+			setupMock: func(m *MockImpersonationRepository) {},
+			wantErr:   domainErrors.ErrInvalidInput,
+		},
+		{
+			name:      "異常系: 有効期間が上限を超える",
+			ctx:       admin,
+			userID:    2,
+			input:     StartImpersonationInput{Reason: "問い合わせ対応", TTLMinutes: 241},
+			setupMock: func(m *MockImpersonationRepository) {},
+			wantErr:   domainErrors.ErrInvalidInput,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockImpersonationRepository)
+			tt.setupMock(repo)
+
+			session, err := NewImpersonationUsecase(repo, newImpersonationUsers(), clock.NewFrozen(now)).Start(tt.ctx, tt.userID, tt.input)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, session)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, int64(1), session.AdminID)
+				assert.Equal(t, tt.userID, session.UserID)
+				assert.Equal(t, now.Add(tt.wantTTL), session.ExpiresAt)
+				assert.True(t, strings.HasPrefix(session.Token, ImpersonationTokenPrefix))
+				assert.Equal(t, entity.HashToken(session.Token), session.TokenHash)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestImpersonationUsecase_Sessions(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	ended := now.Add(-time.Minute)
+	active := &entity.ImpersonationSession{ID: 1, AdminID: 1, UserID: 2, TokenHash: "active", ExpiresAt: now.Add(time.Minute)}
+	expired := &entity.ImpersonationSession{ID: 2, AdminID: 1, UserID: 3, TokenHash: "expired", ExpiresAt: now}
+	endedEarly := &entity.ImpersonationSession{ID: 3, AdminID: 1, UserID: 3, TokenHash: "ended", ExpiresAt: now.Add(time.Hour), EndedAt: &ended}
+	deactivated := &entity.ImpersonationSession{ID: 4, AdminID: 1, UserID: 4, TokenHash: "deactivated", ExpiresAt: now.Add(time.Hour)}
+
+	t.Run("正常系: 有効なセッションだけを返す", func(t *testing.T) {
+		repo := new(MockImpersonationRepository)
+		repo.On("FindAll", mock.Anything).Return([]*entity.ImpersonationSession{active, expired, endedEarly}, nil)
+
+		usecase := NewImpersonationUsecase(repo, newImpersonationUsers(), clock.NewFrozen(now))
+		all, err := usecase.List(context.Background(), false)
+		require.NoError(t, err)
+		assert.Len(t, all, 3)
+
+		sessions, err := usecase.List(context.Background(), true)
+		require.NoError(t, err)
+		assert.Equal(t, []*entity.ImpersonationSession{active}, sessions)
+	})
+
+	t.Run("正常系: 有効なセッションを終了する", func(t *testing.T) {
+		copied := *active
+		repo := new(MockImpersonationRepository)
+		repo.On("FindByID", mock.Anything, int64(1)).Return(&copied, nil)
+		repo.On("End", mock.Anything, int64(1), now).Return(nil)
+
+		session, err := NewImpersonationUsecase(repo, newImpersonationUsers(), clock.NewFrozen(now)).End(context.Background(), 1)
+		require.NoError(t, err)
+		require.NotNil(t, session.EndedAt)
+		assert.Equal(t, now, *session.EndedAt)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("正常系: 期限切れのセッションはそのまま返す", func(t *testing.T) {
+		repo := new(MockImpersonationRepository)
+		repo.On("FindByID", mock.Anything, int64(2)).Return(expired, nil)
+
+		session, err := NewImpersonationUsecase(repo, newImpersonationUsers(), clock.NewFrozen(now)).End(context.Background(), 2)
+		require.NoError(t, err)
+		assert.Nil(t, session.EndedAt)
+		repo.AssertNotCalled(t, "End", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("異常系: 期限切れ・終了済み・無効化されたユーザー・不明なトークンでは認証できない", func(t *testing.T) {
+		repo := new(MockImpersonationRepository)
+		repo.On("FindByTokenHash", mock.Anything, entity.HashToken("imp_active")).Return(active, nil)
+		repo.On("FindByTokenHash", mock.Anything, entity.HashToken("imp_expired")).Return(expired, nil)
+		repo.On("FindByTokenHash", mock.Anything, entity.HashToken("imp_ended")).Return(endedEarly, nil)
+		repo.On("FindByTokenHash", mock.Anything, entity.HashToken("imp_deactivated")).Return(deactivated, nil)
+		repo.On("FindByTokenHash", mock.Anything, entity.HashToken("imp_unknown")).Return(nil, domainErrors.ErrImpersonationNotFound)
+
+		usecase := NewImpersonationUsecase(repo, newImpersonationUsers(), clock.NewFrozen(now))
+		session, err := usecase.Authenticate(context.Background(), "imp_active")
+		require.NoError(t, err)
+		assert.Equal(t, active, session)
+
+		for _, token := range []string{"imp_expired", "imp_ended", "imp_deactivated", "imp_unknown"} {
+			_, err := usecase.Authenticate(context.Background(), token)
+			assert.ErrorIs(t, err, domainErrors.ErrUnauthenticated, token)
+		}
+	})
+}
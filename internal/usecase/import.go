@@ -0,0 +1,140 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/pkg/reqctx"
+)
+
+// CSV の取り込みで一度に受け付ける行数の上限
+const MaxImportRows = 10000
+
+// 取り込む 1 行。Row はファイル上の行番号（ヘッダーが 1 行目）
+type ImportRow struct {
+	Row      int
+	Input    CreateItemInput
+	Problems entity.ValidationErrors // 値を読み取るときに見つかった問題（数値でない価格など）
+}
+
+// 取り込みの結果。DryRun の場合は何も登録せず、Created は 0 になる
+type ImportResult struct {
+	DryRun  bool          `json:"dry_run"`
+	Rows    int           `json:"rows"`
+	Valid   int           `json:"valid"`
+	Created int           `json:"created"`
+	Failed  int           `json:"failed"`
+	Errors  []ImportError `json:"errors"`
+}
+
+type ImportError struct {
+	Row     int    `json:"row"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"error"`
+}
+
+// 行ごとにアイテムの検証を行い、問題のない行を登録する。問題のある行は行番号・フィールドとともに返す
+// dryRun の場合は検証だけを行い、何も登録しない
+func (u *itemUsecase) ImportItems(ctx context.Context, rows []ImportRow, dryRun bool) (*ImportResult, error) {
+	result := &ImportResult{DryRun: dryRun, Rows: len(rows), Errors: []ImportError{}}
+	items, err := u.checkImportRows(ctx, rows, result)
+	if err != nil {
+		return nil, err
+	}
+
+	if dryRun {
+		return result, nil
+	}
+	for i, item := range items {
+		if item == nil {
+			continue
+		}
+		created, err := u.itemRepo.Create(ctx, item)
+		if err != nil {
+			reqctx.Logger(ctx).Error("failed to import item", "row", rows[i].Row, "error", err)
+			result.Failed++
+			result.Errors = append(result.Errors, ImportError{Row: rows[i].Row, Message: "failed to create item"})
+			continue
+		}
+		u.recordAudit(ctx, entity.AuditActionItemCreate, created.ID, "")
+		u.publish(ctx, entity.EventItemCreated, created)
+		result.Created++
+	}
+	return result, nil
+}
+
+// 取り込みの置き場で確認した行をすべて登録する。1 行でも問題があれば何も登録せず、問題を返す
+// 登録は 1 つのトランザクションで行い、then（置き場の片づけなど）も同じトランザクションの中で呼ぶ
+func (u *itemUsecase) CommitImport(ctx context.Context, rows []ImportRow, then func(ctx context.Context, created []*entity.Item) error) (*ImportResult, error) {
+	result := &ImportResult{Rows: len(rows), Errors: []ImportError{}}
+	items, err := u.checkImportRows(ctx, rows, result)
+	if err != nil {
+		return nil, err
+	}
+	if result.Failed > 0 {
+		return result, nil
+	}
+
+	created := make([]*entity.Item, 0, len(items))
+	err = u.transactor.Transaction(ctx, func(ctx context.Context) error {
+		created = created[:0]
+		for _, item := range items {
+			c, err := u.itemRepo.Create(ctx, item)
+			if err != nil {
+				return err
+			}
+			created = append(created, c)
+
+			if err := u.recordAuditIn(ctx, entity.AuditActionItemCreate, c.ID, ""); err != nil {
+				return err
+			}
+		}
+		if then != nil {
+			return then(ctx, created)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to import items: %w", err)
+	}
+
+	for _, item := range created {
+		u.publish(ctx, entity.EventItemCreated, item)
+	}
+	result.Created = len(created)
+	return result, nil
+}
+
+// 行ごとにアイテムを検証し、問題を result に記録する。返すスライスは rows と同じ順で、問題のある行は nil
+func (u *itemUsecase) checkImportRows(ctx context.Context, rows []ImportRow, result *ImportResult) ([]*entity.Item, error) {
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%w: the file has no rows", domainErrors.ErrInvalidInput)
+	}
+	if len(rows) > MaxImportRows {
+		return nil, fmt.Errorf("%w: at most %d rows can be imported at once", domainErrors.ErrInvalidInput, MaxImportRows)
+	}
+
+	items := make([]*entity.Item, len(rows))
+	for i, row := range rows {
+		problems := row.Problems
+		if len(problems) == 0 {
+			var err error
+			items[i], problems, err = u.newItem(ctx, row.Input)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if len(problems) > 0 {
+			items[i] = nil
+			result.Failed++
+			for _, problem := range problems {
+				result.Errors = append(result.Errors, ImportError{Row: row.Row, Field: problem.Field, Message: problem.Message})
+			}
+			continue
+		}
+		result.Valid++
+	}
+	return items, nil
+}
@@ -0,0 +1,146 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/pkg/clock"
+	"Aicon-assignment/internal/pkg/reqctx"
+)
+
+// ユーザーごとの取り込みのプロファイル（仕入れ先のファイルの列の対応づけ・日付の書式・既定値）
+// 他のユーザーのプロファイルは存在しないものとして扱う
+type ImportProfileUsecase interface {
+	List(ctx context.Context) ([]*entity.ImportProfile, error)
+	Get(ctx context.Context, id int64) (*entity.ImportProfile, error)
+	Create(ctx context.Context, input ImportProfileInput) (*entity.ImportProfile, error)
+	Update(ctx context.Context, id int64, input UpdateImportProfileInput) (*entity.ImportProfile, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+type ImportProfileInput struct {
+	Name       string            `json:"name"`
+	Columns    map[string]string `json:"columns"`
+	DateFormat string            `json:"date_format"`
+	Defaults   map[string]string `json:"defaults"`
+}
+
+// 指定したものだけを変更する。columns と defaults は丸ごと置き換える
+type UpdateImportProfileInput struct {
+	Name       *string            `json:"name"`
+	Columns    *map[string]string `json:"columns"`
+	DateFormat *string            `json:"date_format"`
+	Defaults   *map[string]string `json:"defaults"`
+}
+
+type importProfileUsecase struct {
+	profiles ImportProfileRepository
+	clock    clock.Clock
+}
+
+func NewImportProfileUsecase(profiles ImportProfileRepository, clock clock.Clock) ImportProfileUsecase {
+	return &importProfileUsecase{
+		profiles: profiles,
+		clock:    clock,
+	}
+}
+
+func (u *importProfileUsecase) List(ctx context.Context) ([]*entity.ImportProfile, error) {
+	userID, ok := reqctx.UserID(ctx)
+	if !ok {
+		return nil, domainErrors.ErrUnauthenticated
+	}
+
+	profiles, err := u.profiles.FindByOwner(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve import profiles: %w", err)
+	}
+	return profiles, nil
+}
+
+func (u *importProfileUsecase) Get(ctx context.Context, id int64) (*entity.ImportProfile, error) {
+	userID, ok := reqctx.UserID(ctx)
+	if !ok {
+		return nil, domainErrors.ErrUnauthenticated
+	}
+	if id <= 0 {
+		return nil, domainErrors.ErrInvalidInput
+	}
+
+	profile, err := u.profiles.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, domainErrors.ErrImportProfileNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to retrieve import profile: %w", err)
+	}
+	if profile.OwnerID != userID {
+		return nil, domainErrors.ErrImportProfileNotFound
+	}
+	return profile, nil
+}
+
+func (u *importProfileUsecase) Create(ctx context.Context, input ImportProfileInput) (*entity.ImportProfile, error) {
+	userID, ok := reqctx.UserID(ctx)
+	if !ok {
+		return nil, domainErrors.ErrUnauthenticated
+	}
+
+	profile, err := entity.NewImportProfile(userID, input.Name, input.Columns, input.DateFormat, input.Defaults, u.clock.Now())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrInvalidInput, err.Error())
+	}
+	if err := u.profiles.Create(ctx, profile); err != nil {
+		return nil, fmt.Errorf("failed to create import profile: %w", err)
+	}
+
+	reqctx.Logger(ctx).Info("import profile created", "import_profile_id", profile.ID, "user_id", userID)
+	return profile, nil
+}
+
+func (u *importProfileUsecase) Update(ctx context.Context, id int64, input UpdateImportProfileInput) (*entity.ImportProfile, error) {
+	profile, err := u.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Name != nil {
+		profile.Name = *input.Name
+	}
+	if input.Columns != nil {
+		profile.Columns = *input.Columns
+	}
+	if input.DateFormat != nil {
+		profile.DateFormat = *input.DateFormat
+	}
+	if input.Defaults != nil {
+		profile.Defaults = *input.Defaults
+	}
+	// 入力の形をそろえるため、作成と同じ手順で組み立て直す
+	updated, err := entity.NewImportProfile(profile.OwnerID, profile.Name, profile.Columns, profile.DateFormat, profile.Defaults, profile.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrInvalidInput, err.Error())
+	}
+	updated.ID = profile.ID
+	updated.UpdatedAt = u.clock.Now()
+
+	if err := u.profiles.Update(ctx, updated); err != nil {
+		return nil, fmt.Errorf("failed to update import profile: %w", err)
+	}
+	return updated, nil
+}
+
+func (u *importProfileUsecase) Delete(ctx context.Context, id int64) error {
+	if _, err := u.Get(ctx, id); err != nil {
+		return err
+	}
+	if err := u.profiles.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete import profile: %w", err)
+	}
+
+	reqctx.Logger(ctx).Info("import profile deleted", "import_profile_id", id)
+	return nil
+}
@@ -0,0 +1,124 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/pkg/clock"
+	"Aicon-assignment/internal/pkg/reqctx"
+)
+
+// MockImportProfileRepository はテスト用の取り込みプロファイルのリポジトリ
+type MockImportProfileRepository struct {
+	mock.Mock
+}
+
+func (m *MockImportProfileRepository) FindByOwner(ctx context.Context, ownerID int64) ([]*entity.ImportProfile, error) {
+	args := m.Called(ctx, ownerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.ImportProfile), args.Error(1)
+}
+
+func (m *MockImportProfileRepository) FindByID(ctx context.Context, id int64) (*entity.ImportProfile, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.ImportProfile), args.Error(1)
+}
+
+func (m *MockImportProfileRepository) Create(ctx context.Context, profile *entity.ImportProfile) error {
+	args := m.Called(ctx, profile)
+	return args.Error(0)
+}
+
+func (m *MockImportProfileRepository) Update(ctx context.Context, profile *entity.ImportProfile) error {
+	args := m.Called(ctx, profile)
+	return args.Error(0)
+}
+
+func (m *MockImportProfileRepository) Delete(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func TestImportProfileUsecase_Create(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	asUser := reqctx.WithUserID(context.Background(), 7)
+
+	t.Run("正常系: 呼び出し元のユーザーのプロファイルとして保存する", func(t *testing.T) {
+		profiles := new(MockImportProfileRepository)
+		profiles.On("Create", mock.Anything, mock.MatchedBy(func(profile *entity.ImportProfile) bool {
+			return profile.OwnerID == 7 && profile.Columns["artikel"] == "name"
+		})).Run(func(args mock.Arguments) {
+			args.Get(1).(*entity.ImportProfile).ID = 3
+		}).Return(nil)
+
+		profile, err := NewImportProfileUsecase(profiles, clock.NewFrozen(now)).Create(asUser, ImportProfileInput{
+			Name:    "supplier A",
+			Columns: map[string]string{"Artikel": "name"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), profile.ID)
+		assert.Equal(t, now, profile.CreatedAt)
+		profiles.AssertExpectations(t)
+	})
+
+	t.Run("異常系: 知らないフィールドへの対応づけ", func(t *testing.T) {
+		profiles := new(MockImportProfileRepository)
+
+		_, err := NewImportProfileUsecase(profiles, clock.NewFrozen(now)).Create(asUser, ImportProfileInput{
+			Name:    "supplier A",
+			Columns: map[string]string{"Farbe": "color"},
+		})
+		assert.ErrorIs(t, err, domainErrors.ErrInvalidInput)
+		profiles.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("異常系: ログインしていない", func(t *testing.T) {
+		_, err := NewImportProfileUsecase(new(MockImportProfileRepository), clock.NewFrozen(now)).Create(context.Background(), ImportProfileInput{Name: "supplier A"})
+		assert.ErrorIs(t, err, domainErrors.ErrUnauthenticated)
+	})
+}
+
+func TestImportProfileUsecase_Update(t *testing.T) {
+	created := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	now := created.Add(time.Hour)
+	stored := func() *entity.ImportProfile {
+		return &entity.ImportProfile{ID: 3, OwnerID: 7, Name: "supplier A", Columns: map[string]string{"artikel": "name"}, CreatedAt: created, UpdatedAt: created}
+	}
+
+	t.Run("正常系: 指定したものだけを変更する", func(t *testing.T) {
+		profiles := new(MockImportProfileRepository)
+		profiles.On("FindByID", mock.Anything, int64(3)).Return(stored(), nil)
+		profiles.On("Update", mock.Anything, mock.Anything).Return(nil)
+
+		format := "DD.MM.YYYY"
+		profile, err := NewImportProfileUsecase(profiles, clock.NewFrozen(now)).Update(reqctx.WithUserID(context.Background(), 7), 3, UpdateImportProfileInput{DateFormat: &format})
+		require.NoError(t, err)
+		assert.Equal(t, "supplier A", profile.Name)
+		assert.Equal(t, "name", profile.Columns["artikel"])
+		assert.Equal(t, format, profile.DateFormat)
+		assert.Equal(t, created, profile.CreatedAt)
+		assert.Equal(t, now, profile.UpdatedAt)
+	})
+
+	t.Run("異常系: 他のユーザーのプロファイルは存在しないものとして扱う", func(t *testing.T) {
+		profiles := new(MockImportProfileRepository)
+		profiles.On("FindByID", mock.Anything, int64(3)).Return(stored(), nil)
+
+		name := "mine now"
+		_, err := NewImportProfileUsecase(profiles, clock.NewFrozen(now)).Update(reqctx.WithUserID(context.Background(), 8), 3, UpdateImportProfileInput{Name: &name})
+		assert.ErrorIs(t, err, domainErrors.ErrImportProfileNotFound)
+		profiles.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+}
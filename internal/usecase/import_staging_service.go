@@ -0,0 +1,382 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/pkg/clock"
+	"Aicon-assignment/internal/pkg/reqctx"
+)
+
+// 取り込みの置き場。ファイルの行をアイテムとして登録する前に確認・編集し、確定した時点でまとめて登録する
+// 他のユーザーの置き場と期限を過ぎた置き場は存在しないものとして扱う
+type ImportStagingUsecase interface {
+	Stage(ctx context.Context, input StageImportInput) (*entity.ImportBatch, error)
+	Get(ctx context.Context, id int64) (*entity.ImportBatch, error)
+	ListRows(ctx context.Context, id int64, invalidOnly bool) ([]*entity.StagedImportRow, error)
+	UpdateRow(ctx context.Context, id int64, row int, input UpdateStagedRowInput) (*entity.StagedImportRow, error)
+	DeleteRow(ctx context.Context, id int64, row int) error
+	Commit(ctx context.Context, id int64) (*ImportResult, error)
+	Discard(ctx context.Context, id int64) error
+	PurgeExpired(ctx context.Context) (int64, error)
+}
+
+type StageImportInput struct {
+	Filename  string
+	ProfileID *int64
+	Rows      []ImportRow
+}
+
+// 指定したフィールドだけを変更する
+type UpdateStagedRowInput struct {
+	Name          *string `json:"name"`
+	Category      *string `json:"category"`
+	Brand         *string `json:"brand"`
+	PurchasePrice *int    `json:"purchase_price"`
+	PurchaseDate  *string `json:"purchase_date"`
+	OrgID         *int64  `json:"organization_id"`
+}
+
+type importStagingUsecase struct {
+	staging    ImportStagingRepository
+	items      ItemUsecase
+	transactor Transactor
+	ttl        time.Duration
+	clock      clock.Clock
+}
+
+// 行の検証と確定したときの登録は items で行う。ttl は確定していない置き場を破棄するまでの期間
+func NewImportStagingUsecase(staging ImportStagingRepository, items ItemUsecase, transactor Transactor, ttl time.Duration, clock clock.Clock) ImportStagingUsecase {
+	return &importStagingUsecase{
+		staging:    staging,
+		items:      items,
+		transactor: transactor,
+		ttl:        ttl,
+		clock:      clock,
+	}
+}
+
+func (u *importStagingUsecase) Stage(ctx context.Context, input StageImportInput) (*entity.ImportBatch, error) {
+	userID, ok := reqctx.UserID(ctx)
+	if !ok {
+		return nil, domainErrors.ErrUnauthenticated
+	}
+
+	checked, err := u.items.ImportItems(ctx, input.Rows, true)
+	if err != nil {
+		return nil, err
+	}
+	problems := importProblemsByRow(checked)
+
+	now := u.clock.Now()
+	batch := entity.NewImportBatch(userID, input.Filename, input.ProfileID, now, u.ttl)
+	rows := make([]*entity.StagedImportRow, len(input.Rows))
+	for i, row := range input.Rows {
+		rows[i] = &entity.StagedImportRow{
+			Row:           row.Row,
+			Name:          row.Input.Name,
+			Category:      row.Input.Category,
+			Brand:         row.Input.Brand,
+			PurchasePrice: row.Input.PurchasePrice,
+			PurchaseDate:  row.Input.PurchaseDate,
+			OrgID:         row.Input.OrgID,
+			ReadErrors:    append(entity.ValidationErrors{}, row.Problems...),
+			Errors:        append(entity.ValidationErrors{}, problems[row.Row]...),
+			UpdatedAt:     now,
+		}
+		if !rows[i].Valid() {
+			batch.InvalidRows++
+		}
+	}
+	batch.Rows = len(rows)
+
+	err = u.transactor.Transaction(ctx, func(ctx context.Context) error {
+		return u.staging.CreateBatch(ctx, batch, rows)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to stage import: %w", err)
+	}
+
+	reqctx.Logger(ctx).Info("import staged", "import_id", batch.ID, "rows", batch.Rows, "invalid_rows", batch.InvalidRows, "user_id", userID)
+	return batch, nil
+}
+
+func (u *importStagingUsecase) Get(ctx context.Context, id int64) (*entity.ImportBatch, error) {
+	userID, ok := reqctx.UserID(ctx)
+	if !ok {
+		return nil, domainErrors.ErrUnauthenticated
+	}
+	if id <= 0 {
+		return nil, domainErrors.ErrInvalidInput
+	}
+
+	batch, err := u.staging.FindBatch(ctx, id)
+	if err != nil {
+		if errors.Is(err, domainErrors.ErrImportBatchNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to retrieve import: %w", err)
+	}
+	if batch.OwnerID != userID || batch.Expired(u.clock.Now()) {
+		return nil, domainErrors.ErrImportBatchNotFound
+	}
+	return batch, nil
+}
+
+// 確認・編集できる置き場。確定したものはエラー
+func (u *importStagingUsecase) staged(ctx context.Context, id int64) (*entity.ImportBatch, error) {
+	batch, err := u.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !batch.Staged() {
+		return nil, domainErrors.ErrImportCommitted
+	}
+	return batch, nil
+}
+
+// invalidOnly の場合は問題のある行だけを返す
+func (u *importStagingUsecase) ListRows(ctx context.Context, id int64, invalidOnly bool) ([]*entity.StagedImportRow, error) {
+	if _, err := u.Get(ctx, id); err != nil {
+		return nil, err
+	}
+
+	rows, err := u.staging.FindRows(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve import rows: %w", err)
+	}
+	if !invalidOnly {
+		return rows, nil
+	}
+	invalid := []*entity.StagedImportRow{}
+	for _, row := range rows {
+		if !row.Valid() {
+			invalid = append(invalid, row)
+		}
+	}
+	return invalid, nil
+}
+
+// 行を編集して検証し直す。編集したフィールドの読み込み時の問題は消え、置き場の期限は延びる
+func (u *importStagingUsecase) UpdateRow(ctx context.Context, id int64, row int, input UpdateStagedRowInput) (*entity.StagedImportRow, error) {
+	batch, err := u.staged(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	staged, err := u.findRow(ctx, id, row)
+	if err != nil {
+		return nil, err
+	}
+
+	var edited []string
+	if input.Name != nil {
+		staged.Name = *input.Name
+		edited = append(edited, "name")
+	}
+	if input.Category != nil {
+		staged.Category = *input.Category
+		edited = append(edited, "category")
+	}
+	if input.Brand != nil {
+		staged.Brand = *input.Brand
+		edited = append(edited, "brand")
+	}
+	if input.PurchasePrice != nil {
+		staged.PurchasePrice = *input.PurchasePrice
+		edited = append(edited, "purchase_price")
+	}
+	if input.PurchaseDate != nil {
+		staged.PurchaseDate = *input.PurchaseDate
+		edited = append(edited, "purchase_date")
+	}
+	if input.OrgID != nil {
+		staged.OrgID = input.OrgID
+		edited = append(edited, "organization_id")
+	}
+	if len(edited) == 0 {
+		return nil, fmt.Errorf("%w: no fields to update", domainErrors.ErrInvalidInput)
+	}
+	staged.ClearReadErrors(edited...)
+
+	wasValid := staged.Valid()
+	checked, err := u.items.ImportItems(ctx, []ImportRow{stagedImportRow(staged)}, true)
+	if err != nil {
+		return nil, err
+	}
+	staged.Errors = append(entity.ValidationErrors{}, importProblemsByRow(checked)[staged.Row]...)
+
+	now := u.clock.Now()
+	staged.UpdatedAt = now
+	switch {
+	case wasValid && !staged.Valid():
+		batch.InvalidRows++
+	case !wasValid && staged.Valid():
+		batch.InvalidRows--
+	}
+	batch.Touch(now, u.ttl)
+
+	err = u.transactor.Transaction(ctx, func(ctx context.Context) error {
+		if err := u.staging.UpdateRow(ctx, staged); err != nil {
+			return err
+		}
+		return u.staging.UpdateBatch(ctx, batch)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update import row: %w", err)
+	}
+	return staged, nil
+}
+
+// 行を取り込みから外す。置き場の期限は延びる
+func (u *importStagingUsecase) DeleteRow(ctx context.Context, id int64, row int) error {
+	batch, err := u.staged(ctx, id)
+	if err != nil {
+		return err
+	}
+	staged, err := u.findRow(ctx, id, row)
+	if err != nil {
+		return err
+	}
+
+	batch.Rows--
+	if !staged.Valid() {
+		batch.InvalidRows--
+	}
+	batch.Touch(u.clock.Now(), u.ttl)
+
+	err = u.transactor.Transaction(ctx, func(ctx context.Context) error {
+		if err := u.staging.DeleteRows(ctx, id, row); err != nil {
+			return err
+		}
+		return u.staging.UpdateBatch(ctx, batch)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete import row: %w", err)
+	}
+	return nil
+}
+
+func (u *importStagingUsecase) findRow(ctx context.Context, id int64, row int) (*entity.StagedImportRow, error) {
+	staged, err := u.staging.FindRow(ctx, id, row)
+	if err != nil {
+		if errors.Is(err, domainErrors.ErrStagedRowNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to retrieve import row: %w", err)
+	}
+	return staged, nil
+}
+
+// 置き場のすべての行をアイテムとして登録する。登録と置き場の片づけは 1 つのトランザクションで行う
+// 問題のある行が 1 行でもあれば何も登録せず、行の問題を記録し直して結果（Failed > 0）を返す
+func (u *importStagingUsecase) Commit(ctx context.Context, id int64) (*ImportResult, error) {
+	batch, err := u.staged(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	staged, err := u.staging.FindRows(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve import rows: %w", err)
+	}
+	if len(staged) == 0 {
+		return nil, fmt.Errorf("%w: the import has no rows", domainErrors.ErrInvalidInput)
+	}
+
+	rows := make([]ImportRow, len(staged))
+	for i, row := range staged {
+		rows[i] = stagedImportRow(row)
+	}
+	result, err := u.items.CommitImport(ctx, rows, func(ctx context.Context, created []*entity.Item) error {
+		batch.Commit(len(created), u.clock.Now())
+		if err := u.staging.UpdateBatch(ctx, batch); err != nil {
+			return err
+		}
+		return u.staging.DeleteRows(ctx, id)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Failed > 0 {
+		// 確認したあとに組織が削除されたなどで問題が見つかった行を、一覧に反映する
+		if err := u.recheck(ctx, batch, staged, result); err != nil {
+			reqctx.Logger(ctx).Error("failed to record import problems", "import_id", id, "error", err)
+		}
+		return result, nil
+	}
+
+	reqctx.Logger(ctx).Info("import committed", "import_id", id, "created", result.Created)
+	return result, nil
+}
+
+// 確定できなかったときの問題を行と置き場に記録する
+func (u *importStagingUsecase) recheck(ctx context.Context, batch *entity.ImportBatch, staged []*entity.StagedImportRow, result *ImportResult) error {
+	problems := importProblemsByRow(result)
+	return u.transactor.Transaction(ctx, func(ctx context.Context) error {
+		batch.InvalidRows = 0
+		for _, row := range staged {
+			row.Errors = append(entity.ValidationErrors{}, problems[row.Row]...)
+			if !row.Valid() {
+				batch.InvalidRows++
+			}
+			if err := u.staging.UpdateRow(ctx, row); err != nil {
+				return err
+			}
+		}
+		return u.staging.UpdateBatch(ctx, batch)
+	})
+}
+
+// 置き場を行ごと破棄する。確定したものも結果を消すために破棄できる
+func (u *importStagingUsecase) Discard(ctx context.Context, id int64) error {
+	if _, err := u.Get(ctx, id); err != nil {
+		return err
+	}
+	if err := u.staging.DeleteBatch(ctx, id); err != nil {
+		if errors.Is(err, domainErrors.ErrImportBatchNotFound) {
+			return err
+		}
+		return fmt.Errorf("failed to discard import: %w", err)
+	}
+
+	reqctx.Logger(ctx).Info("import discarded", "import_id", id)
+	return nil
+}
+
+// 期限を過ぎた置き場を破棄し、その数を返す（定期実行用）
+func (u *importStagingUsecase) PurgeExpired(ctx context.Context) (int64, error) {
+	deleted, err := u.staging.DeleteExpired(ctx, u.clock.Now())
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge expired imports: %w", err)
+	}
+	return deleted, nil
+}
+
+// 置き場の行を検証・登録する形に戻す。読み込み時の問題は残す
+func stagedImportRow(row *entity.StagedImportRow) ImportRow {
+	return ImportRow{
+		Row: row.Row,
+		Input: CreateItemInput{
+			Name:          row.Name,
+			Category:      row.Category,
+			Brand:         row.Brand,
+			PurchasePrice: row.PurchasePrice,
+			PurchaseDate:  row.PurchaseDate,
+			OrgID:         row.OrgID,
+		},
+		Problems: row.ReadErrors,
+	}
+}
+
+// 取り込みの結果の問題を行番号ごとにまとめる
+func importProblemsByRow(result *ImportResult) map[int]entity.ValidationErrors {
+	problems := make(map[int]entity.ValidationErrors, len(result.Errors))
+	for _, e := range result.Errors {
+		problems[e.Row] = append(problems[e.Row], entity.FieldError{Field: e.Field, Message: e.Message})
+	}
+	return problems
+}
@@ -0,0 +1,281 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/pkg/clock"
+	"Aicon-assignment/internal/pkg/reqctx"
+)
+
+// MockImportStagingRepository はテスト用の取り込みの置き場のリポジトリ
+type MockImportStagingRepository struct {
+	mock.Mock
+}
+
+func (m *MockImportStagingRepository) CreateBatch(ctx context.Context, batch *entity.ImportBatch, rows []*entity.StagedImportRow) error {
+	args := m.Called(ctx, batch, rows)
+	return args.Error(0)
+}
+
+func (m *MockImportStagingRepository) FindBatch(ctx context.Context, id int64) (*entity.ImportBatch, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.ImportBatch), args.Error(1)
+}
+
+func (m *MockImportStagingRepository) UpdateBatch(ctx context.Context, batch *entity.ImportBatch) error {
+	args := m.Called(ctx, batch)
+	return args.Error(0)
+}
+
+func (m *MockImportStagingRepository) FindRows(ctx context.Context, batchID int64) ([]*entity.StagedImportRow, error) {
+	args := m.Called(ctx, batchID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.StagedImportRow), args.Error(1)
+}
+
+func (m *MockImportStagingRepository) FindRow(ctx context.Context, batchID int64, row int) (*entity.StagedImportRow, error) {
+	args := m.Called(ctx, batchID, row)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.StagedImportRow), args.Error(1)
+}
+
+func (m *MockImportStagingRepository) UpdateRow(ctx context.Context, row *entity.StagedImportRow) error {
+	args := m.Called(ctx, row)
+	return args.Error(0)
+}
+
+func (m *MockImportStagingRepository) DeleteRows(ctx context.Context, batchID int64, rows ...int) error {
+	args := m.Called(ctx, batchID, rows)
+	return args.Error(0)
+}
+
+func (m *MockImportStagingRepository) DeleteBatch(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockImportStagingRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func newImportStagingUsecase(staging ImportStagingRepository, items ItemRepository, now time.Time) ImportStagingUsecase {
+	frozen := clock.NewFrozen(now)
+	return NewImportStagingUsecase(staging, NewItemUsecase(items, WithClock(frozen)), noTransaction{}, 72*time.Hour, frozen)
+}
+
+func TestImportStagingUsecase_Stage(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	asUser := reqctx.WithUserID(context.Background(), 7)
+	valid := ImportRow{Row: 2, Input: CreateItemInput{Name: "デイトナ", Category: "時計", Brand: "ROLEX", PurchasePrice: 1500000, PurchaseDate: "2023-01-15"}}
+	unparsed := ImportRow{Row: 3, Input: valid.Input, Problems: entity.ValidationErrors{{Field: "purchase_price", Message: "purchase_price must be an integer"}}}
+
+	t.Run("正常系: 問題のある行も含めて置き場に残し、アイテムは登録しない", func(t *testing.T) {
+		staging := new(MockImportStagingRepository)
+		items := new(MockItemRepository)
+		staging.On("CreateBatch", mock.Anything, mock.Anything, mock.MatchedBy(func(rows []*entity.StagedImportRow) bool {
+			return len(rows) == 2 && rows[0].Valid() && !rows[1].Valid() && len(rows[1].ReadErrors) == 1
+		})).Run(func(args mock.Arguments) {
+			args.Get(1).(*entity.ImportBatch).ID = 4
+		}).Return(nil)
+
+		batch, err := newImportStagingUsecase(staging, items, now).Stage(asUser, StageImportInput{Filename: "items.csv", Rows: []ImportRow{valid, unparsed}})
+		require.NoError(t, err)
+		assert.Equal(t, int64(4), batch.ID)
+		assert.Equal(t, int64(7), batch.OwnerID)
+		assert.Equal(t, 2, batch.Rows)
+		assert.Equal(t, 1, batch.InvalidRows)
+		assert.Equal(t, now.Add(72*time.Hour), batch.ExpiresAt)
+		items.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("異常系: 行がない", func(t *testing.T) {
+		_, err := newImportStagingUsecase(new(MockImportStagingRepository), new(MockItemRepository), now).Stage(asUser, StageImportInput{Filename: "items.csv"})
+		assert.ErrorIs(t, err, domainErrors.ErrInvalidInput)
+	})
+
+	t.Run("異常系: ログインしていない", func(t *testing.T) {
+		_, err := newImportStagingUsecase(new(MockImportStagingRepository), new(MockItemRepository), now).Stage(context.Background(), StageImportInput{Rows: []ImportRow{valid}})
+		assert.ErrorIs(t, err, domainErrors.ErrUnauthenticated)
+	})
+}
+
+func TestImportStagingUsecase_Get(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	stored := &entity.ImportBatch{ID: 4, OwnerID: 7, Status: entity.ImportBatchStaged, ExpiresAt: now.Add(time.Hour)}
+
+	t.Run("正常系: 自分の置き場を返す", func(t *testing.T) {
+		staging := new(MockImportStagingRepository)
+		staging.On("FindBatch", mock.Anything, int64(4)).Return(stored, nil)
+
+		batch, err := newImportStagingUsecase(staging, new(MockItemRepository), now).Get(reqctx.WithUserID(context.Background(), 7), 4)
+		require.NoError(t, err)
+		assert.Equal(t, int64(4), batch.ID)
+	})
+
+	t.Run("異常系: 他のユーザーの置き場は存在しないものとして扱う", func(t *testing.T) {
+		staging := new(MockImportStagingRepository)
+		staging.On("FindBatch", mock.Anything, int64(4)).Return(stored, nil)
+
+		_, err := newImportStagingUsecase(staging, new(MockItemRepository), now).Get(reqctx.WithUserID(context.Background(), 8), 4)
+		assert.ErrorIs(t, err, domainErrors.ErrImportBatchNotFound)
+	})
+
+	t.Run("異常系: 期限を過ぎた置き場は存在しないものとして扱う", func(t *testing.T) {
+		staging := new(MockImportStagingRepository)
+		staging.On("FindBatch", mock.Anything, int64(4)).Return(stored, nil)
+
+		_, err := newImportStagingUsecase(staging, new(MockItemRepository), now.Add(time.Hour)).Get(reqctx.WithUserID(context.Background(), 7), 4)
+		assert.ErrorIs(t, err, domainErrors.ErrImportBatchNotFound)
+	})
+}
+
+func TestImportStagingUsecase_UpdateRow(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	asUser := reqctx.WithUserID(context.Background(), 7)
+	stored := func() *entity.ImportBatch {
+		return &entity.ImportBatch{ID: 4, OwnerID: 7, Status: entity.ImportBatchStaged, Rows: 2, InvalidRows: 1, ExpiresAt: now.Add(time.Hour)}
+	}
+	unparsed := func() *entity.StagedImportRow {
+		problems := entity.ValidationErrors{{Field: "purchase_price", Message: "purchase_price must be an integer"}}
+		return &entity.StagedImportRow{BatchID: 4, Row: 3, Name: "デイトナ", Category: "時計", Brand: "ROLEX", PurchaseDate: "2023-01-15", ReadErrors: problems, Errors: problems}
+	}
+
+	t.Run("正常系: 直したフィールドの問題が消え、置き場の期限が延びる", func(t *testing.T) {
+		staging := new(MockImportStagingRepository)
+		staging.On("FindBatch", mock.Anything, int64(4)).Return(stored(), nil)
+		staging.On("FindRow", mock.Anything, int64(4), 3).Return(unparsed(), nil)
+		staging.On("UpdateRow", mock.Anything, mock.Anything).Return(nil)
+		staging.On("UpdateBatch", mock.Anything, mock.MatchedBy(func(batch *entity.ImportBatch) bool {
+			return batch.InvalidRows == 0 && batch.ExpiresAt.Equal(now.Add(72*time.Hour))
+		})).Return(nil)
+
+		price := 1500000
+		row, err := newImportStagingUsecase(staging, new(MockItemRepository), now).UpdateRow(asUser, 4, 3, UpdateStagedRowInput{PurchasePrice: &price})
+		require.NoError(t, err)
+		assert.True(t, row.Valid())
+		assert.Empty(t, row.ReadErrors)
+		assert.Equal(t, price, row.PurchasePrice)
+		staging.AssertExpectations(t)
+	})
+
+	t.Run("正常系: 直したあとも問題があれば残す", func(t *testing.T) {
+		staging := new(MockImportStagingRepository)
+		staging.On("FindBatch", mock.Anything, int64(4)).Return(stored(), nil)
+		staging.On("FindRow", mock.Anything, int64(4), 3).Return(unparsed(), nil)
+		staging.On("UpdateRow", mock.Anything, mock.Anything).Return(nil)
+		staging.On("UpdateBatch", mock.Anything, mock.MatchedBy(func(batch *entity.ImportBatch) bool {
+			return batch.InvalidRows == 1
+		})).Return(nil)
+
+		category := "家電"
+		row, err := newImportStagingUsecase(staging, new(MockItemRepository), now).UpdateRow(asUser, 4, 3, UpdateStagedRowInput{Category: &category})
+		require.NoError(t, err)
+		assert.False(t, row.Valid())
+		// 価格は直していないため、読み込み時の問題が残る
+		assert.Equal(t, "purchase_price", row.Errors[0].Field)
+	})
+
+	t.Run("異常系: 確定した置き場は編集できない", func(t *testing.T) {
+		staging := new(MockImportStagingRepository)
+		committed := stored()
+		committed.Status = entity.ImportBatchCommitted
+		staging.On("FindBatch", mock.Anything, int64(4)).Return(committed, nil)
+
+		name := "デイトナ"
+		_, err := newImportStagingUsecase(staging, new(MockItemRepository), now).UpdateRow(asUser, 4, 3, UpdateStagedRowInput{Name: &name})
+		assert.ErrorIs(t, err, domainErrors.ErrImportCommitted)
+	})
+
+	t.Run("異常系: 行がない", func(t *testing.T) {
+		staging := new(MockImportStagingRepository)
+		staging.On("FindBatch", mock.Anything, int64(4)).Return(stored(), nil)
+		staging.On("FindRow", mock.Anything, int64(4), 9).Return(nil, domainErrors.ErrStagedRowNotFound)
+
+		name := "デイトナ"
+		_, err := newImportStagingUsecase(staging, new(MockItemRepository), now).UpdateRow(asUser, 4, 9, UpdateStagedRowInput{Name: &name})
+		assert.ErrorIs(t, err, domainErrors.ErrStagedRowNotFound)
+	})
+}
+
+func TestImportStagingUsecase_Commit(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	asUser := reqctx.WithUserID(context.Background(), 7)
+	stored := func() *entity.ImportBatch {
+		return &entity.ImportBatch{ID: 4, OwnerID: 7, Status: entity.ImportBatchStaged, Rows: 2, ExpiresAt: now.Add(time.Hour)}
+	}
+	row := func(n int, category string) *entity.StagedImportRow {
+		return &entity.StagedImportRow{BatchID: 4, Row: n, Name: "デイトナ", Category: category, Brand: "ROLEX", PurchasePrice: 1500000, PurchaseDate: "2023-01-15", Errors: entity.ValidationErrors{}}
+	}
+
+	t.Run("正常系: すべての行を登録し、置き場を確定済みにして行を片づける", func(t *testing.T) {
+		staging := new(MockImportStagingRepository)
+		items := new(MockItemRepository)
+		staging.On("FindBatch", mock.Anything, int64(4)).Return(stored(), nil)
+		staging.On("FindRows", mock.Anything, int64(4)).Return([]*entity.StagedImportRow{row(2, "時計"), row(3, "時計")}, nil)
+		items.On("Create", mock.Anything, mock.Anything).Return(&entity.Item{ID: 1}, nil)
+		staging.On("UpdateBatch", mock.Anything, mock.MatchedBy(func(batch *entity.ImportBatch) bool {
+			return batch.Status == entity.ImportBatchCommitted && batch.Created == 2
+		})).Return(nil)
+		staging.On("DeleteRows", mock.Anything, int64(4), []int(nil)).Return(nil)
+
+		result, err := newImportStagingUsecase(staging, items, now).Commit(asUser, 4)
+		require.NoError(t, err)
+		assert.Equal(t, 2, result.Created)
+		staging.AssertExpectations(t)
+		items.AssertNumberOfCalls(t, "Create", 2)
+	})
+
+	t.Run("正常系: 問題のある行が残っていれば何も登録せず、問題を記録し直す", func(t *testing.T) {
+		staging := new(MockImportStagingRepository)
+		items := new(MockItemRepository)
+		staging.On("FindBatch", mock.Anything, int64(4)).Return(stored(), nil)
+		staging.On("FindRows", mock.Anything, int64(4)).Return([]*entity.StagedImportRow{row(2, "時計"), row(3, "家電")}, nil)
+		staging.On("UpdateRow", mock.Anything, mock.Anything).Return(nil)
+		staging.On("UpdateBatch", mock.Anything, mock.MatchedBy(func(batch *entity.ImportBatch) bool {
+			return batch.Staged() && batch.InvalidRows == 1
+		})).Return(nil)
+
+		result, err := newImportStagingUsecase(staging, items, now).Commit(asUser, 4)
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Failed)
+		assert.Equal(t, 3, result.Errors[0].Row)
+		items.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		staging.AssertNotCalled(t, "DeleteRows", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("異常系: すでに確定している", func(t *testing.T) {
+		staging := new(MockImportStagingRepository)
+		committed := stored()
+		committed.Status = entity.ImportBatchCommitted
+		staging.On("FindBatch", mock.Anything, int64(4)).Return(committed, nil)
+
+		_, err := newImportStagingUsecase(staging, new(MockItemRepository), now).Commit(asUser, 4)
+		assert.ErrorIs(t, err, domainErrors.ErrImportCommitted)
+	})
+}
+
+func TestImportStagingUsecase_PurgeExpired(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	staging := new(MockImportStagingRepository)
+	staging.On("DeleteExpired", mock.Anything, now).Return(int64(3), nil)
+
+	deleted, err := newImportStagingUsecase(staging, new(MockItemRepository), now).PurgeExpired(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), deleted)
+}
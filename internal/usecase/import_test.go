@@ -0,0 +1,104 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+func TestItemUsecase_ImportItems(t *testing.T) {
+	valid := ImportRow{Row: 2, Input: CreateItemInput{Name: "デイトナ", Category: "時計", Brand: "ROLEX", PurchasePrice: 1500000, PurchaseDate: "2023-01-15"}}
+	invalid := ImportRow{Row: 3, Input: CreateItemInput{Name: "バーキン", Category: "家電", Brand: "HERMES", PurchaseDate: "2023-01-15"}}
+	unparsed := ImportRow{Row: 4, Input: valid.Input, Problems: entity.ValidationErrors{{Field: "purchase_price", Message: "purchase_price must be an integer"}}}
+
+	t.Run("正常系: dry_run では検証結果だけを返し、何も登録しない", func(t *testing.T) {
+		mockRepo := new(MockItemRepository)
+
+		result, err := NewItemUsecase(mockRepo).ImportItems(context.Background(), []ImportRow{valid, invalid, unparsed}, true)
+		require.NoError(t, err)
+		assert.True(t, result.DryRun)
+		assert.Equal(t, 3, result.Rows)
+		assert.Equal(t, 1, result.Valid)
+		assert.Equal(t, 0, result.Created)
+		assert.Equal(t, 2, result.Failed)
+		require.Len(t, result.Errors, 2)
+		assert.Equal(t, 3, result.Errors[0].Row)
+		assert.Equal(t, "category", result.Errors[0].Field)
+		assert.Equal(t, ImportError{Row: 4, Field: "purchase_price", Message: "purchase_price must be an integer"}, result.Errors[1])
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("正常系: 問題のない行を登録し、登録できなかった行を数える", func(t *testing.T) {
+		mockRepo := new(MockItemRepository)
+		mockRepo.On("Create", mock.Anything, mock.Anything).Return(&entity.Item{ID: 1}, nil).Once()
+		mockRepo.On("Create", mock.Anything, mock.Anything).Return(nil, errors.New("connection reset")).Once()
+
+		result, err := NewItemUsecase(mockRepo).ImportItems(context.Background(), []ImportRow{valid, invalid, {Row: 5, Input: valid.Input}}, false)
+		require.NoError(t, err)
+		assert.Equal(t, 2, result.Valid)
+		assert.Equal(t, 1, result.Created)
+		assert.Equal(t, 2, result.Failed)
+		assert.Equal(t, ImportError{Row: 5, Message: "failed to create item"}, result.Errors[1])
+		mockRepo.AssertNumberOfCalls(t, "Create", 2)
+	})
+
+	t.Run("異常系: 行がない", func(t *testing.T) {
+		_, err := NewItemUsecase(new(MockItemRepository)).ImportItems(context.Background(), nil, false)
+		assert.ErrorIs(t, err, domainErrors.ErrInvalidInput)
+	})
+}
+
+func TestItemUsecase_CommitImport(t *testing.T) {
+	valid := ImportRow{Row: 2, Input: CreateItemInput{Name: "デイトナ", Category: "時計", Brand: "ROLEX", PurchasePrice: 1500000, PurchaseDate: "2023-01-15"}}
+	invalid := ImportRow{Row: 3, Input: CreateItemInput{Name: "バーキン", Category: "家電", Brand: "HERMES", PurchaseDate: "2023-01-15"}}
+
+	t.Run("正常系: すべての行を登録し、同じトランザクションの中で then を呼ぶ", func(t *testing.T) {
+		mockRepo := new(MockItemRepository)
+		mockRepo.On("Create", mock.Anything, mock.Anything).Return(&entity.Item{ID: 1}, nil).Once()
+		mockRepo.On("Create", mock.Anything, mock.Anything).Return(&entity.Item{ID: 2}, nil).Once()
+
+		var got []*entity.Item
+		result, err := NewItemUsecase(mockRepo).CommitImport(context.Background(), []ImportRow{valid, {Row: 4, Input: valid.Input}}, func(ctx context.Context, created []*entity.Item) error {
+			got = created
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 2, result.Created)
+		assert.Equal(t, 0, result.Failed)
+		require.Len(t, got, 2)
+		assert.Equal(t, int64(2), got[1].ID)
+	})
+
+	t.Run("正常系: 問題のある行があれば何も登録しない", func(t *testing.T) {
+		mockRepo := new(MockItemRepository)
+		called := false
+
+		result, err := NewItemUsecase(mockRepo).CommitImport(context.Background(), []ImportRow{valid, invalid}, func(ctx context.Context, created []*entity.Item) error {
+			called = true
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Failed)
+		assert.Equal(t, 0, result.Created)
+		assert.Equal(t, 3, result.Errors[0].Row)
+		assert.False(t, called)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("異常系: then が失敗した場合はエラーを返す", func(t *testing.T) {
+		mockRepo := new(MockItemRepository)
+		mockRepo.On("Create", mock.Anything, mock.Anything).Return(&entity.Item{ID: 1}, nil)
+
+		_, err := NewItemUsecase(mockRepo).CommitImport(context.Background(), []ImportRow{valid}, func(ctx context.Context, created []*entity.Item) error {
+			return domainErrors.ErrDatabaseError
+		})
+		assert.ErrorIs(t, err, domainErrors.ErrDatabaseError)
+	})
+}
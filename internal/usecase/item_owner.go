@@ -0,0 +1,81 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/pkg/reqctx"
+)
+
+type AssignOwnerInput struct {
+	OwnerID int64  `json:"owner_id"`
+	Reason  string `json:"reason"`
+}
+
+// 持ち主に指定するユーザーの参照先を設定する（設定しない場合、持ち主の付け替えでユーザーを確かめない）
+func WithUsers(repo UserRepository) Option {
+	return func(u *itemUsecase) {
+		u.users = repo
+	}
+}
+
+// アイテムの持ち主を付け替える（管理者のみ）
+// ユーザーなしで作成したアイテム（初期データなど）は持ち主がいないため、付け替えるまで管理者以外から見えない
+func (u *itemUsecase) AssignOwner(ctx context.Context, id int64, input AssignOwnerInput) (*entity.Item, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if id <= 0 {
+		return nil, domainErrors.ErrInvalidInput
+	}
+	if input.OwnerID <= 0 {
+		return nil, fmt.Errorf("%w: owner_id is required", domainErrors.ErrInvalidInput)
+	}
+	if err := u.checkOwner(ctx, input.OwnerID); err != nil {
+		return nil, err
+	}
+
+	ctx = reqctx.WithAllOwners(ctx)
+	if err := u.itemRepo.AssignOwner(ctx, id, input.OwnerID); err != nil {
+		if domainErrors.IsNotFoundError(err) {
+			return nil, domainErrors.ErrItemNotFound
+		}
+		return nil, fmt.Errorf("failed to assign owner: %w", err)
+	}
+
+	item, err := u.itemRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve item: %w", err)
+	}
+
+	reason := fmt.Sprintf("owner: user %d", input.OwnerID)
+	if r := strings.TrimSpace(input.Reason); r != "" {
+		reason += ": " + r
+	}
+	u.recordAudit(ctx, entity.AuditActionItemAssignOwner, id, reason)
+	u.publish(ctx, entity.EventItemUpdated, item, "owner_id")
+
+	return item, nil
+}
+
+// 持ち主に指定するユーザーが存在し、無効になっていないかを確かめる
+func (u *itemUsecase) checkOwner(ctx context.Context, ownerID int64) error {
+	if u.users == nil {
+		return nil
+	}
+	user, err := u.users.FindByID(ctx, ownerID)
+	if errors.Is(err, domainErrors.ErrUserNotFound) {
+		return fmt.Errorf("%w: user %d does not exist", domainErrors.ErrInvalidInput, ownerID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to retrieve user: %w", err)
+	}
+	if !user.Active {
+		return fmt.Errorf("%w: user %d is inactive", domainErrors.ErrInvalidInput, ownerID)
+	}
+	return nil
+}
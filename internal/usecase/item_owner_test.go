@@ -0,0 +1,71 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/pkg/reqctx"
+)
+
+func TestItemUsecase_AssignOwner(t *testing.T) {
+	asAdmin := reqctx.WithUserRole(reqctx.WithUserID(context.Background(), 1), entity.UserRoleAdmin)
+	allOwners := mock.MatchedBy(func(ctx context.Context) bool { return reqctx.AllOwners(ctx) })
+
+	t.Run("正常系: 持ち主を付け替えて監査ログとイベントを残す", func(t *testing.T) {
+		users := new(MockUserRepository)
+		users.On("FindByID", mock.Anything, int64(2)).Return(&entity.User{ID: 2, Active: true}, nil)
+		ownerID := int64(2)
+		mockRepo := new(MockItemRepository)
+		mockRepo.On("AssignOwner", allOwners, int64(5), int64(2)).Return(nil)
+		mockRepo.On("FindByID", allOwners, int64(5)).Return(&entity.Item{ID: 5, OwnerID: &ownerID}, nil)
+		auditLog := new(MockAuditLogRepository)
+		auditLog.On("Record", mock.Anything, mock.MatchedBy(func(e *entity.AuditEntry) bool {
+			return e.Action == entity.AuditActionItemAssignOwner && e.ItemID == 5 && e.Reason == "owner: user 2: 初期データの整理"
+		})).Return(nil)
+		publisher := &recordingPublisher{}
+
+		uc := NewItemUsecase(mockRepo, WithUsers(users), WithAuditLog(auditLog), WithEventPublisher(publisher))
+		item, err := uc.AssignOwner(asAdmin, 5, AssignOwnerInput{OwnerID: 2, Reason: "初期データの整理"})
+		require.NoError(t, err)
+		assert.Equal(t, &ownerID, item.OwnerID)
+		auditLog.AssertExpectations(t)
+		require.Len(t, publisher.events, 1)
+		assert.Equal(t, entity.EventItemUpdated, publisher.events[0].Type)
+		assert.Equal(t, []string{"owner_id"}, publisher.events[0].ChangedFields)
+	})
+
+	t.Run("異常系: 管理者以外は付け替えられない", func(t *testing.T) {
+		asMember := reqctx.WithUserRole(reqctx.WithUserID(context.Background(), 2), entity.UserRoleMember)
+		mockRepo := new(MockItemRepository)
+
+		_, err := NewItemUsecase(mockRepo).AssignOwner(asMember, 5, AssignOwnerInput{OwnerID: 2})
+		assert.ErrorIs(t, err, domainErrors.ErrForbidden)
+		mockRepo.AssertNotCalled(t, "AssignOwner", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("異常系: 存在しないユーザー・無効なユーザーは指定できない", func(t *testing.T) {
+		users := new(MockUserRepository)
+		users.On("FindByID", mock.Anything, int64(8)).Return(nil, domainErrors.ErrUserNotFound)
+		users.On("FindByID", mock.Anything, int64(9)).Return(&entity.User{ID: 9, Active: false}, nil)
+		uc := NewItemUsecase(new(MockItemRepository), WithUsers(users))
+
+		for _, ownerID := range []int64{0, 8, 9} {
+			_, err := uc.AssignOwner(asAdmin, 5, AssignOwnerInput{OwnerID: ownerID})
+			assert.ErrorIs(t, err, domainErrors.ErrInvalidInput, ownerID)
+		}
+	})
+
+	t.Run("異常系: 存在しないアイテム", func(t *testing.T) {
+		mockRepo := new(MockItemRepository)
+		mockRepo.On("AssignOwner", allOwners, int64(5), int64(2)).Return(domainErrors.ErrItemNotFound)
+
+		_, err := NewItemUsecase(mockRepo).AssignOwner(asAdmin, 5, AssignOwnerInput{OwnerID: 2})
+		assert.ErrorIs(t, err, domainErrors.ErrItemNotFound)
+	})
+}
@@ -0,0 +1,14 @@
+package usecase
+
+import "context"
+
+// Mailer sends a plain-text email
+type Mailer interface {
+	Send(ctx context.Context, mail Mail) error
+}
+
+type Mail struct {
+	To      string
+	Subject string
+	Body    string
+}
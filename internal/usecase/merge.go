@@ -0,0 +1,135 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+type MergeItemsInput struct {
+	SourceID int64             `json:"source_id"` // 統合して削除するアイテム
+	Fields   map[string]string `json:"fields"`    // フィールドごとに "target" / "source" のどちらの値を残すか
+	Reason   string            `json:"reason"`
+}
+
+// トランザクションを使わずにそのまま実行する（インメモリ実装用）
+type noTransaction struct{}
+
+func (noTransaction) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// 重複したアイテムを統合する
+// 統合先の更新、価格変更履歴・画像・添付ファイルの付け替え、統合元の論理削除、監査ログの記録を1つのトランザクションで行う
+func (u *itemUsecase) MergeItems(ctx context.Context, targetID int64, input MergeItemsInput) (*entity.Item, error) {
+	if targetID <= 0 || input.SourceID <= 0 {
+		return nil, domainErrors.ErrInvalidInput
+	}
+
+	reason := strings.TrimSpace(input.Reason)
+	if reason == "" && u.reasonPolicy.PolicyFor(ctx).RequiresReasonForDelete() {
+		return nil, domainErrors.ErrReasonRequired
+	}
+
+	var merged, source *entity.Item
+	var changedFields []string
+	err := u.transactor.Transaction(ctx, func(ctx context.Context) error {
+		target, err := u.itemRepo.FindByID(ctx, targetID)
+		if err != nil {
+			return err
+		}
+		source, err = u.itemRepo.FindByID(ctx, input.SourceID)
+		if err != nil {
+			return err
+		}
+
+		now := u.clock.Now()
+		before := *target
+		if err := target.MergeFrom(source, input.Fields, now); err != nil {
+			return fmt.Errorf("%w: %s", domainErrors.ErrInvalidInput, err.Error())
+		}
+		if _, err := u.checkLockedFields(ctx, &before, target, false); err != nil {
+			return err
+		}
+
+		merged, err = u.itemRepo.Update(ctx, target)
+		if err != nil {
+			return err
+		}
+		changedFields = entity.ChangedFields(&before, merged)
+
+		if err := u.itemRepo.MergeInto(ctx, source.ID, target.ID, now); err != nil {
+			return err
+		}
+		for _, records := range u.mergedRecords {
+			if err := records.MoveToItem(ctx, source.ID, target.ID); err != nil {
+				return err
+			}
+		}
+
+		if merged.PurchasePrice != before.PurchasePrice {
+			change, err := entity.NewPriceChange(target.ID, before.PurchasePrice, merged.PurchasePrice, actorFromContext(ctx), mergeReason(reason, source.ID), now)
+			if err != nil {
+				return fmt.Errorf("%w: %s", domainErrors.ErrInvalidInput, err.Error())
+			}
+			if err := u.itemRepo.RecordPriceChange(ctx, change); err != nil {
+				return err
+			}
+		}
+
+		// 統合先・統合元のどちらの監査ログからも統合を辿れるようにする
+		for _, itemID := range []int64{target.ID, source.ID} {
+			if err := u.recordAuditIn(ctx, entity.AuditActionItemMerge, itemID, mergeReason(reason, source.ID)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if domainErrors.IsNotFoundError(err) {
+			return nil, domainErrors.ErrItemNotFound
+		}
+		if domainErrors.IsValidationError(err) || domainErrors.IsLockedError(err) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to merge items: %w", err)
+	}
+
+	u.publish(ctx, entity.EventItemUpdated, merged, changedFields...)
+	u.publishDeleted(ctx, source, entity.DeleteReasonMerged)
+
+	return merged, nil
+}
+
+// トランザクション内で監査ログを記録する
+// recordAudit と違い、記録に失敗したら操作全体を取り消せるようエラーを返す
+func (u *itemUsecase) recordAuditIn(ctx context.Context, action string, itemID int64, reason string) error {
+	if u.auditLog == nil {
+		return nil
+	}
+
+	return u.auditLog.Record(ctx, &entity.AuditEntry{
+		Action:       action,
+		ItemID:       itemID,
+		Actor:        actorFromContext(ctx),
+		Impersonator: impersonatorFromContext(ctx),
+		Reason:       reason,
+		CreatedAt:    u.clock.Now(),
+	})
+}
+
+// 監査ログ・価格変更履歴に残す理由。統合元が分かるようにする
+func mergeReason(reason string, sourceID int64) string {
+	return withReason(fmt.Sprintf("merged item %d", sourceID), reason)
+}
+
+// 操作の説明に利用者が入力した理由を添える
+func withReason(description, reason string) string {
+	if reason == "" {
+		return description
+	}
+	return description + ": " + reason
+}
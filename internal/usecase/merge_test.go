@@ -0,0 +1,261 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/pkg/reqctx"
+)
+
+// fakeTransactor はトランザクションの結果を記録する
+type fakeTransactor struct {
+	committed  bool
+	rolledBack bool
+}
+
+func (f *fakeTransactor) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if err := fn(ctx); err != nil {
+		f.rolledBack = true
+		return err
+	}
+	f.committed = true
+	return nil
+}
+
+func TestItemUsecase_MergeItems(t *testing.T) {
+	newItems := func() (*entity.Item, *entity.Item) {
+		target, _ := entity.NewItem("デイトナ", "時計", "ROLEX", 1000000, "2023-01-01")
+		target.ID = 1
+		source, _ := entity.NewItem("デイトナ 116500LN", "時計", "ROLEX", 1500000, "2023-01-01")
+		source.ID = 2
+		return target, source
+	}
+
+	t.Run("正常系: 指定したフィールドを統合元から取り込み、統合元を削除する", func(t *testing.T) {
+		target, source := newItems()
+		mockRepo := new(MockItemRepository)
+		mockRepo.On("FindByID", mock.Anything, int64(1)).Return(target, nil)
+		mockRepo.On("FindByID", mock.Anything, int64(2)).Return(source, nil)
+		mockRepo.On("Update", mock.Anything, mock.Anything).Return(target, nil)
+		mockRepo.On("MergeInto", mock.Anything, int64(2), int64(1), mock.Anything).Return(nil)
+		mockRepo.On("RecordPriceChange", mock.Anything, mock.MatchedBy(func(change *entity.PriceChange) bool {
+			return change.ItemID == 1 && change.OldPrice == 1000000 && change.NewPrice == 1500000 && change.Reason == "merged item 2: 重複登録"
+		})).Return(nil)
+		auditLog := new(MockAuditLogRepository)
+		auditLog.On("Record", mock.Anything, mock.MatchedBy(func(entry *entity.AuditEntry) bool {
+			return entry.Action == entity.AuditActionItemMerge
+		})).Return(nil).Twice()
+		tx := &fakeTransactor{}
+
+		usecase := NewItemUsecase(mockRepo, WithAuditLog(auditLog), WithTransactor(tx))
+		merged, err := usecase.MergeItems(context.Background(), 1, MergeItemsInput{
+			SourceID: 2,
+			Fields:   map[string]string{"name": "source", "purchase_price": "source"},
+			Reason:   "重複登録",
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "デイトナ 116500LN", merged.Name)
+		assert.Equal(t, 1500000, merged.PurchasePrice)
+		assert.True(t, tx.committed)
+		mockRepo.AssertExpectations(t)
+		auditLog.AssertExpectations(t)
+	})
+
+	t.Run("正常系: 統合元の画像・添付ファイルを統合先に付け替える", func(t *testing.T) {
+		target, source := newItems()
+		mockRepo := new(MockItemRepository)
+		mockRepo.On("FindByID", mock.Anything, int64(1)).Return(target, nil)
+		mockRepo.On("FindByID", mock.Anything, int64(2)).Return(source, nil)
+		mockRepo.On("Update", mock.Anything, mock.Anything).Return(target, nil)
+		mockRepo.On("MergeInto", mock.Anything, int64(2), int64(1), mock.Anything).Return(nil)
+		images := new(MockImageRepository)
+		images.On("MoveToItem", mock.Anything, int64(2), int64(1)).Return(nil).Once()
+		attachments := new(MockAttachmentRepository)
+		attachments.On("MoveToItem", mock.Anything, int64(2), int64(1)).Return(nil).Once()
+		auditLog := new(MockAuditLogRepository)
+		auditLog.On("Record", mock.Anything, mock.Anything).Return(nil).Twice()
+		tx := &fakeTransactor{}
+
+		events := &recordingPublisher{}
+
+		usecase := NewItemUsecase(mockRepo, WithAuditLog(auditLog), WithTransactor(tx), WithMergedRecords(images, attachments), WithEventPublisher(events))
+		_, err := usecase.MergeItems(context.Background(), 1, MergeItemsInput{SourceID: 2})
+
+		require.NoError(t, err)
+		assert.True(t, tx.committed)
+		// 付け替えた画像を ItemImageCleaner が消さないよう、削除イベントに理由を添える
+		require.Len(t, events.events, 2)
+		assert.Equal(t, entity.EventItemDeleted, events.events[1].Type)
+		assert.Equal(t, entity.DeleteReasonMerged, events.events[1].DeleteReason)
+		images.AssertExpectations(t)
+		attachments.AssertExpectations(t)
+		auditLog.AssertExpectations(t)
+	})
+
+	t.Run("異常系: 添付ファイルの付け替えに失敗したらロールバックする", func(t *testing.T) {
+		target, source := newItems()
+		mockRepo := new(MockItemRepository)
+		mockRepo.On("FindByID", mock.Anything, int64(1)).Return(target, nil)
+		mockRepo.On("FindByID", mock.Anything, int64(2)).Return(source, nil)
+		mockRepo.On("Update", mock.Anything, mock.Anything).Return(target, nil)
+		mockRepo.On("MergeInto", mock.Anything, int64(2), int64(1), mock.Anything).Return(nil)
+		images := new(MockImageRepository)
+		images.On("MoveToItem", mock.Anything, int64(2), int64(1)).Return(nil)
+		attachments := new(MockAttachmentRepository)
+		attachments.On("MoveToItem", mock.Anything, int64(2), int64(1)).Return(errors.New("lock wait timeout"))
+		auditLog := new(MockAuditLogRepository)
+		tx := &fakeTransactor{}
+
+		usecase := NewItemUsecase(mockRepo, WithAuditLog(auditLog), WithTransactor(tx), WithMergedRecords(images, attachments))
+		_, err := usecase.MergeItems(context.Background(), 1, MergeItemsInput{SourceID: 2})
+
+		assert.Error(t, err)
+		assert.True(t, tx.rolledBack)
+		auditLog.AssertNotCalled(t, "Record", mock.Anything, mock.Anything)
+	})
+
+	t.Run("異常系: 監査ログの記録に失敗したらロールバックする", func(t *testing.T) {
+		target, source := newItems()
+		mockRepo := new(MockItemRepository)
+		mockRepo.On("FindByID", mock.Anything, int64(1)).Return(target, nil)
+		mockRepo.On("FindByID", mock.Anything, int64(2)).Return(source, nil)
+		mockRepo.On("Update", mock.Anything, mock.Anything).Return(target, nil)
+		mockRepo.On("MergeInto", mock.Anything, int64(2), int64(1), mock.Anything).Return(nil)
+		auditLog := new(MockAuditLogRepository)
+		auditLog.On("Record", mock.Anything, mock.Anything).Return(errors.New("disk full"))
+		tx := &fakeTransactor{}
+
+		usecase := NewItemUsecase(mockRepo, WithAuditLog(auditLog), WithTransactor(tx))
+		_, err := usecase.MergeItems(context.Background(), 1, MergeItemsInput{SourceID: 2})
+
+		assert.Error(t, err)
+		assert.True(t, tx.rolledBack)
+	})
+
+	t.Run("異常系: 不正なフィールドの指定", func(t *testing.T) {
+		target, source := newItems()
+		mockRepo := new(MockItemRepository)
+		mockRepo.On("FindByID", mock.Anything, int64(1)).Return(target, nil)
+		mockRepo.On("FindByID", mock.Anything, int64(2)).Return(source, nil)
+
+		usecase := NewItemUsecase(mockRepo)
+		_, err := usecase.MergeItems(context.Background(), 1, MergeItemsInput{SourceID: 2, Fields: map[string]string{"color": "source"}})
+
+		assert.ErrorIs(t, err, domainErrors.ErrInvalidInput)
+	})
+
+	t.Run("異常系: 統合元が見つからない", func(t *testing.T) {
+		target, _ := newItems()
+		mockRepo := new(MockItemRepository)
+		mockRepo.On("FindByID", mock.Anything, int64(1)).Return(target, nil)
+		mockRepo.On("FindByID", mock.Anything, int64(3)).Return(nil, domainErrors.ErrItemNotFound)
+
+		usecase := NewItemUsecase(mockRepo)
+		_, err := usecase.MergeItems(context.Background(), 1, MergeItemsInput{SourceID: 3})
+
+		assert.ErrorIs(t, err, domainErrors.ErrItemNotFound)
+	})
+}
+
+func TestItemUsecase_SplitItem(t *testing.T) {
+	newOriginal := func() *entity.Item {
+		item, _ := entity.NewItem("サブマリーナ ブレス付き", "時計", "ROLEX", 1200000, "2023-01-01")
+		item.ID = 1
+		return item
+	}
+	components := []entity.SplitComponent{
+		{Name: "サブマリーナ", PurchasePrice: 1000000},
+		{Name: "社外ブレスレット", PurchasePrice: 200000},
+	}
+
+	t.Run("正常系: 分割後のアイテムを作成し、元のアイテムを削除する", func(t *testing.T) {
+		mockRepo := new(MockItemRepository)
+		mockRepo.On("FindByID", mock.Anything, int64(1)).Return(newOriginal(), nil)
+		for i, component := range components {
+			created := &entity.Item{ID: int64(10 + i), Name: component.Name, PurchasePrice: component.PurchasePrice}
+			mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(item *entity.Item) bool {
+				return item.Name == component.Name && item.Brand == "ROLEX"
+			})).Return(created, nil).Once()
+		}
+		mockRepo.On("SoftDelete", mock.Anything, int64(1), mock.Anything).Return(nil)
+		auditLog := new(MockAuditLogRepository)
+		auditLog.On("Record", mock.Anything, mock.MatchedBy(func(entry *entity.AuditEntry) bool {
+			return entry.Action == entity.AuditActionItemCreate && entry.Reason == "split from item 1"
+		})).Return(nil).Twice()
+		auditLog.On("Record", mock.Anything, mock.MatchedBy(func(entry *entity.AuditEntry) bool {
+			return entry.Action == entity.AuditActionItemSplit && entry.ItemID == 1 && entry.Reason == "split into items 10, 11"
+		})).Return(nil).Once()
+		tx := &fakeTransactor{}
+
+		events := &recordingPublisher{}
+
+		usecase := NewItemUsecase(mockRepo, WithAuditLog(auditLog), WithTransactor(tx), WithEventPublisher(events))
+		items, err := usecase.SplitItem(context.Background(), 1, SplitItemInput{Components: components})
+
+		require.NoError(t, err)
+		require.Len(t, items, 2)
+		assert.Equal(t, int64(10), items[0].ID)
+		assert.True(t, tx.committed)
+		require.Len(t, events.events, 3)
+		assert.Equal(t, entity.EventItemDeleted, events.events[2].Type)
+		assert.Equal(t, entity.DeleteReasonSplit, events.events[2].DeleteReason)
+		mockRepo.AssertExpectations(t)
+		auditLog.AssertExpectations(t)
+	})
+
+	t.Run("正常系: 元のアイテムの画像は分割では残し、完全に削除するときに消す", func(t *testing.T) {
+		mockRepo := new(MockItemRepository)
+		mockRepo.On("FindByID", mock.Anything, int64(1)).Return(newOriginal(), nil).Once()
+		mockRepo.On("Create", mock.Anything, mock.Anything).Return(&entity.Item{ID: 10}, nil).Once()
+		mockRepo.On("Create", mock.Anything, mock.Anything).Return(&entity.Item{ID: 11}, nil).Once()
+		mockRepo.On("SoftDelete", mock.Anything, int64(1), mock.Anything).Return(nil)
+		images := new(MockImageRepository)
+		images.On("FindByItemID", mock.Anything, int64(1)).Return([]*entity.ItemImage{{ID: 5, ItemID: 1, Key: "images/items/1/a.png"}}, nil)
+		images.On("Delete", mock.Anything, int64(5)).Return(nil)
+		blobs := &deletingBlobs{}
+		cleaner := NewItemImageCleaner(images, blobs)
+
+		usecase := NewItemUsecase(mockRepo, WithTransactor(&fakeTransactor{}), WithEventPublisher(cleaner), WithPurgedRecords(cleaner))
+		_, err := usecase.SplitItem(context.Background(), 1, SplitItemInput{Components: components})
+		require.NoError(t, err)
+		assert.Empty(t, blobs.deleted, "分割では元のアイテムの画像を消さない")
+
+		mockRepo.On("FindByID", mock.Anything, int64(1)).Return(nil, domainErrors.ErrItemNotFound)
+		mockRepo.On("Purge", mock.Anything, int64(1)).Return(nil)
+		require.NoError(t, usecase.PurgeItem(reqctx.WithSystem(context.Background()), 1, ""))
+		assert.Equal(t, []string{"images/items/1/a.png"}, blobs.deleted)
+		images.AssertExpectations(t)
+	})
+
+	t.Run("異常系: 作成に失敗したらロールバックする", func(t *testing.T) {
+		mockRepo := new(MockItemRepository)
+		mockRepo.On("FindByID", mock.Anything, int64(1)).Return(newOriginal(), nil)
+		mockRepo.On("Create", mock.Anything, mock.Anything).Return(nil, domainErrors.ErrDatabaseError)
+		tx := &fakeTransactor{}
+
+		usecase := NewItemUsecase(mockRepo, WithTransactor(tx))
+		_, err := usecase.SplitItem(context.Background(), 1, SplitItemInput{Components: components})
+
+		assert.ErrorIs(t, err, domainErrors.ErrDatabaseError)
+		assert.True(t, tx.rolledBack)
+		mockRepo.AssertNotCalled(t, "SoftDelete", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("異常系: 配分の合計が一致しない", func(t *testing.T) {
+		mockRepo := new(MockItemRepository)
+		mockRepo.On("FindByID", mock.Anything, int64(1)).Return(newOriginal(), nil)
+
+		usecase := NewItemUsecase(mockRepo)
+		_, err := usecase.SplitItem(context.Background(), 1, SplitItemInput{Components: components[:1]})
+
+		assert.ErrorIs(t, err, domainErrors.ErrInvalidInput)
+	})
+}
@@ -0,0 +1,287 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/pkg/clock"
+	"Aicon-assignment/internal/pkg/reqctx"
+)
+
+// NotificationSender delivers a notification over one channel.
+// to is the recipient's address on the channel (LINE user ID, email address, FCM token) and is empty for Slack
+type NotificationSender interface {
+	Send(ctx context.Context, to string, notification entity.Notification) error
+}
+
+// 設定済みのチャンネルと送信の実装。設定していないチャンネルは含めない
+type NotificationChannels map[entity.NotificationChannel]NotificationSender
+
+type NotificationUsecase interface {
+	// Channels はこのサーバーで使えるチャンネルを返す
+	Channels() []entity.NotificationChannel
+	// GetPreference は呼び出し元のユーザーの設定を返す。設定していない場合は何も受け取らない設定
+	GetPreference(ctx context.Context) (*entity.NotificationPreference, error)
+	// UpdatePreference は呼び出し元のユーザーの設定を置き換える
+	UpdatePreference(ctx context.Context, input NotificationPreferenceInput) (*entity.NotificationPreference, error)
+	// SendTest は呼び出し元のユーザーが選んだチャンネルに試しの通知を送る
+	SendTest(ctx context.Context) ([]NotificationResult, error)
+	// NotifyAdmins は有効な管理者に通知する。channels が空の場合は設定済みのすべてのチャンネルに送る
+	// Slack には1回だけ送り、個人のチャンネルは各管理者の設定に従う
+	NotifyAdmins(ctx context.Context, channels []entity.NotificationChannel, notification entity.Notification) error
+}
+
+type NotificationPreferenceInput struct {
+	Channels   []entity.NotificationChannel `json:"channels"`
+	LINEUserID string                       `json:"line_user_id"`
+	PushTokens []string                     `json:"push_tokens"`
+}
+
+// チャンネルごとの送信の結果
+type NotificationResult struct {
+	Channel entity.NotificationChannel `json:"channel"`
+	Sent    int                        `json:"sent"`            // 送った宛先の数（プッシュ通知は端末ごと）
+	Error   string                     `json:"error,omitempty"` // 失敗した場合の理由
+}
+
+type notificationUsecase struct {
+	channels    NotificationChannels
+	preferences NotificationPreferenceRepository
+	users       UserRepository
+	clock       clock.Clock
+}
+
+func NewNotificationUsecase(channels NotificationChannels, preferences NotificationPreferenceRepository, users UserRepository, clock clock.Clock) NotificationUsecase {
+	return &notificationUsecase{
+		channels:    channels,
+		preferences: preferences,
+		users:       users,
+		clock:       clock,
+	}
+}
+
+func (u *notificationUsecase) Channels() []entity.NotificationChannel {
+	channels := []entity.NotificationChannel{}
+	for _, channel := range entity.NotificationChannels {
+		if _, ok := u.channels[channel]; ok {
+			channels = append(channels, channel)
+		}
+	}
+	return channels
+}
+
+func (u *notificationUsecase) GetPreference(ctx context.Context) (*entity.NotificationPreference, error) {
+	userID, ok := reqctx.UserID(ctx)
+	if !ok {
+		return nil, domainErrors.ErrUnauthenticated
+	}
+	return u.preferenceOf(ctx, userID)
+}
+
+func (u *notificationUsecase) preferenceOf(ctx context.Context, userID int64) (*entity.NotificationPreference, error) {
+	preference, err := u.preferences.FindByUser(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domainErrors.ErrPreferenceNotFound) {
+			return entity.DefaultNotificationPreference(userID), nil
+		}
+		return nil, fmt.Errorf("failed to retrieve notification preference: %w", err)
+	}
+	return preference, nil
+}
+
+func (u *notificationUsecase) UpdatePreference(ctx context.Context, input NotificationPreferenceInput) (*entity.NotificationPreference, error) {
+	userID, ok := reqctx.UserID(ctx)
+	if !ok {
+		return nil, domainErrors.ErrUnauthenticated
+	}
+
+	now := u.clock.Now()
+	preference := &entity.NotificationPreference{
+		UserID:     userID,
+		Channels:   input.Channels,
+		LINEUserID: strings.TrimSpace(input.LINEUserID),
+		PushTokens: []string{},
+		UpdatedAt:  &now,
+	}
+	if preference.Channels == nil {
+		preference.Channels = []entity.NotificationChannel{}
+	}
+	for _, token := range input.PushTokens {
+		if token = strings.TrimSpace(token); !slices.Contains(preference.PushTokens, token) {
+			preference.PushTokens = append(preference.PushTokens, token)
+		}
+	}
+	if err := preference.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrInvalidInput, err.Error())
+	}
+	for _, channel := range preference.Channels {
+		if _, ok := u.channels[channel]; !ok {
+			return nil, fmt.Errorf("%w: channel %s is not configured on this server", domainErrors.ErrInvalidInput, channel)
+		}
+	}
+	if preference.Enabled(entity.ChannelEmail) {
+		user, err := u.users.FindByID(ctx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve user: %w", err)
+		}
+		if user.Email == "" {
+			return nil, fmt.Errorf("%w: an email address is required to receive email notifications", domainErrors.ErrInvalidInput)
+		}
+	}
+
+	if err := u.preferences.Save(ctx, preference); err != nil {
+		return nil, fmt.Errorf("failed to save notification preference: %w", err)
+	}
+	reqctx.Logger(ctx).Info("notification preference updated", "user_id", userID, "channels", preference.Channels)
+	return preference, nil
+}
+
+func (u *notificationUsecase) SendTest(ctx context.Context) ([]NotificationResult, error) {
+	userID, ok := reqctx.UserID(ctx)
+	if !ok {
+		return nil, domainErrors.ErrUnauthenticated
+	}
+	preference, err := u.preferenceOf(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(preference.Channels) == 0 {
+		return nil, fmt.Errorf("%w: no notification channel is enabled", domainErrors.ErrInvalidInput)
+	}
+	user, err := u.users.FindByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve user: %w", err)
+	}
+
+	notification := entity.Notification{Title: "テスト通知", Body: "通知の設定を確認するためのメッセージです"}
+	results := []NotificationResult{}
+	for _, channel := range preference.Channels {
+		result := NotificationResult{Channel: channel}
+		var errs []error
+		for _, to := range recipientAddresses(user, preference, channel) {
+			if err := u.send(ctx, channel, to, notification); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			result.Sent++
+		}
+		if err := errors.Join(errs...); err != nil {
+			reqctx.Logger(ctx).Warn("test notification failed", "user_id", userID, "channel", channel, "error", err)
+			result.Error = err.Error()
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func (u *notificationUsecase) NotifyAdmins(ctx context.Context, channels []entity.NotificationChannel, notification entity.Notification) error {
+	selected := u.selectChannels(ctx, channels)
+	if len(selected) == 0 {
+		reqctx.Logger(ctx).Error("notification not sent (no channel configured)", "title", notification.Title, "body", notification.Body)
+		return nil
+	}
+
+	var errs []error
+	personal := false
+	for _, channel := range selected {
+		if channel.Personal() {
+			personal = true
+			continue
+		}
+		if err := u.send(ctx, channel, "", notification); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if !personal {
+		return errors.Join(errs...)
+	}
+
+	admins, err := u.users.FindByQuery(ctx, entity.UserQuery{Filter: activeAdmins})
+	if err != nil {
+		return errors.Join(append(errs, fmt.Errorf("failed to retrieve admins: %w", err))...)
+	}
+	for _, admin := range admins {
+		preference, err := u.preferenceOf(ctx, admin.ID)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, channel := range selected {
+			if !channel.Personal() || !preference.Enabled(channel) {
+				continue
+			}
+			for _, to := range recipientAddresses(admin, preference, channel) {
+				if err := u.send(ctx, channel, to, notification); err != nil {
+					errs = append(errs, fmt.Errorf("user %d: %w", admin.ID, err))
+				}
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// 指定したチャンネルのうち設定済みのもの。指定がない場合は設定済みのすべて
+func (u *notificationUsecase) selectChannels(ctx context.Context, channels []entity.NotificationChannel) []entity.NotificationChannel {
+	if len(channels) == 0 {
+		return u.Channels()
+	}
+	var selected []entity.NotificationChannel
+	for _, channel := range channels {
+		if _, ok := u.channels[channel]; !ok {
+			reqctx.Logger(ctx).Warn("notification channel is not configured", "channel", channel)
+			continue
+		}
+		selected = append(selected, channel)
+	}
+	return selected
+}
+
+// 宛先は通知先のサービスの識別子やトークンのため、エラーには含めない
+func (u *notificationUsecase) send(ctx context.Context, channel entity.NotificationChannel, to string, notification entity.Notification) error {
+	sender, ok := u.channels[channel]
+	if !ok {
+		return fmt.Errorf("channel %s is not configured", channel)
+	}
+	if err := sender.Send(ctx, to, notification); err != nil {
+		return fmt.Errorf("failed to send %s notification: %w", channel, err)
+	}
+	return nil
+}
+
+// チャンネルごとのユーザーの宛先
+func recipientAddresses(user *entity.User, preference *entity.NotificationPreference, channel entity.NotificationChannel) []string {
+	switch channel {
+	case entity.ChannelLINE:
+		if preference.LINEUserID != "" {
+			return []string{preference.LINEUserID}
+		}
+	case entity.ChannelEmail:
+		if user.Email != "" {
+			return []string{user.Email}
+		}
+	case entity.ChannelPush:
+		return preference.PushTokens
+	}
+	return nil
+}
+
+// SLO のアラートを管理者に通知する。送り先は目標の channels に従う
+type sloAlertNotifier struct {
+	notifications NotificationUsecase
+}
+
+func NewSLOAlertNotifier(notifications NotificationUsecase) AlertNotifier {
+	return &sloAlertNotifier{notifications: notifications}
+}
+
+func (n *sloAlertNotifier) Notify(ctx context.Context, alert entity.SLOAlert) error {
+	return n.notifications.NotifyAdmins(ctx, alert.Channels, entity.Notification{
+		Title: fmt.Sprintf("SLO アラート: %s %s (%s)", alert.Method, alert.Route, alert.SLI),
+		Body:  alert.String(),
+	})
+}
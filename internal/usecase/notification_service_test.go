@@ -0,0 +1,219 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/pkg/clock"
+	"Aicon-assignment/internal/pkg/reqctx"
+)
+
+// ユーザーごとの設定を map に保存する
+type fakePreferenceRepository struct {
+	preferences map[int64]*entity.NotificationPreference
+}
+
+func (r *fakePreferenceRepository) FindByUser(ctx context.Context, userID int64) (*entity.NotificationPreference, error) {
+	preference, ok := r.preferences[userID]
+	if !ok {
+		return nil, domainErrors.ErrPreferenceNotFound
+	}
+	return preference, nil
+}
+
+func (r *fakePreferenceRepository) Save(ctx context.Context, preference *entity.NotificationPreference) error {
+	r.preferences[preference.UserID] = preference
+	return nil
+}
+
+// 送った宛先を記録する。fail に含まれる宛先には失敗する
+type recordingSender struct {
+	sent []string
+	fail map[string]bool
+}
+
+func (s *recordingSender) Send(ctx context.Context, to string, notification entity.Notification) error {
+	if s.fail[to] {
+		return errors.New("unexpected status code 401")
+	}
+	s.sent = append(s.sent, to+": "+notification.Title)
+	return nil
+}
+
+var testLINEUserID = "U" + strings.Repeat("0123456789abcdef", 2)
+
+func newTestNotificationUsecase(users *MockUserRepository) (NotificationUsecase, map[entity.NotificationChannel]*recordingSender, *fakePreferenceRepository) {
+	senders := map[entity.NotificationChannel]*recordingSender{
+		entity.ChannelSlack: {}, entity.ChannelLINE: {}, entity.ChannelEmail: {},
+	}
+	channels := NotificationChannels{}
+	for channel, sender := range senders {
+		channels[channel] = sender
+	}
+	preferences := &fakePreferenceRepository{preferences: map[int64]*entity.NotificationPreference{}}
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	return NewNotificationUsecase(channels, preferences, users, clock.NewFrozen(now)), senders, preferences
+}
+
+func TestNotificationUsecase_Channels(t *testing.T) {
+	u, _, _ := newTestNotificationUsecase(&MockUserRepository{})
+	assert.Equal(t, []entity.NotificationChannel{entity.ChannelSlack, entity.ChannelLINE, entity.ChannelEmail}, u.Channels())
+}
+
+func TestNotificationUsecase_UpdatePreference(t *testing.T) {
+	ctx := reqctx.WithUserID(context.Background(), 2)
+
+	t.Run("正常系: 設定を置き換え、端末の重複を除く", func(t *testing.T) {
+		users := &MockUserRepository{}
+		users.On("FindByID", mock.Anything, int64(2)).Return(&entity.User{ID: 2, Email: "sato@example.com"}, nil)
+		u, _, preferences := newTestNotificationUsecase(users)
+
+		preference, err := u.UpdatePreference(ctx, NotificationPreferenceInput{
+			Channels:   []entity.NotificationChannel{entity.ChannelLINE, entity.ChannelEmail},
+			LINEUserID: " " + testLINEUserID + " ",
+			PushTokens: []string{"a", " a "},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, testLINEUserID, preference.LINEUserID)
+		assert.Equal(t, []string{"a"}, preference.PushTokens)
+		assert.Same(t, preference, preferences.preferences[2])
+
+		got, err := u.GetPreference(ctx)
+		require.NoError(t, err)
+		assert.Same(t, preference, got)
+	})
+
+	t.Run("正常系: 設定していない場合は何も受け取らない", func(t *testing.T) {
+		u, _, _ := newTestNotificationUsecase(&MockUserRepository{})
+
+		preference, err := u.GetPreference(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), preference.UserID)
+		assert.Empty(t, preference.Channels)
+	})
+
+	tests := []struct {
+		name        string
+		input       NotificationPreferenceInput
+		email       string
+		expectedErr string
+	}{
+		{name: "異常系: 設定していないチャンネル", input: NotificationPreferenceInput{Channels: []entity.NotificationChannel{entity.ChannelPush}, PushTokens: []string{"a"}}, expectedErr: "channel push is not configured on this server"},
+		{name: "異常系: LINE の宛先がない", input: NotificationPreferenceInput{Channels: []entity.NotificationChannel{entity.ChannelLINE}}, expectedErr: "line_user_id is required"},
+		{name: "異常系: メールアドレスがない", input: NotificationPreferenceInput{Channels: []entity.NotificationChannel{entity.ChannelEmail}}, expectedErr: "an email address is required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users := &MockUserRepository{}
+			users.On("FindByID", mock.Anything, int64(2)).Return(&entity.User{ID: 2, Email: tt.email}, nil)
+			u, _, preferences := newTestNotificationUsecase(users)
+
+			_, err := u.UpdatePreference(ctx, tt.input)
+			assert.True(t, domainErrors.IsValidationError(err))
+			assert.ErrorContains(t, err, tt.expectedErr)
+			assert.Empty(t, preferences.preferences)
+		})
+	}
+
+	t.Run("異常系: 未認証", func(t *testing.T) {
+		u, _, _ := newTestNotificationUsecase(&MockUserRepository{})
+
+		_, err := u.UpdatePreference(context.Background(), NotificationPreferenceInput{})
+		assert.ErrorIs(t, err, domainErrors.ErrUnauthenticated)
+	})
+}
+
+func TestNotificationUsecase_NotifyAdmins(t *testing.T) {
+	admins := []*entity.User{
+		{ID: 1, Email: "admin@example.com", Role: entity.UserRoleAdmin, Active: true},
+		{ID: 4, Email: "ops@example.com", Role: entity.UserRoleAdmin, Active: true},
+		{ID: 5, Email: "new@example.com", Role: entity.UserRoleAdmin, Active: true},
+	}
+	notification := entity.Notification{Title: "SLO アラート"}
+
+	setup := func() (NotificationUsecase, map[entity.NotificationChannel]*recordingSender) {
+		users := &MockUserRepository{}
+		users.On("FindByQuery", mock.Anything, entity.UserQuery{Filter: activeAdmins}).Return(admins, nil)
+		u, senders, preferences := newTestNotificationUsecase(users)
+		preferences.preferences[1] = &entity.NotificationPreference{UserID: 1, Channels: []entity.NotificationChannel{entity.ChannelLINE}, LINEUserID: testLINEUserID}
+		preferences.preferences[4] = &entity.NotificationPreference{UserID: 4, Channels: []entity.NotificationChannel{entity.ChannelEmail}}
+		return u, senders
+	}
+
+	t.Run("正常系: Slack には1回、個人のチャンネルは各管理者の設定に従って送る", func(t *testing.T) {
+		u, senders := setup()
+
+		require.NoError(t, u.NotifyAdmins(context.Background(), nil, notification))
+		assert.Equal(t, []string{": SLO アラート"}, senders[entity.ChannelSlack].sent)
+		assert.Equal(t, []string{testLINEUserID + ": SLO アラート"}, senders[entity.ChannelLINE].sent)
+		assert.Equal(t, []string{"ops@example.com: SLO アラート"}, senders[entity.ChannelEmail].sent)
+	})
+
+	t.Run("正常系: ルールで選んだチャンネルだけに送る", func(t *testing.T) {
+		u, senders := setup()
+
+		require.NoError(t, u.NotifyAdmins(context.Background(), []entity.NotificationChannel{entity.ChannelLINE, entity.ChannelPush}, notification))
+		assert.Empty(t, senders[entity.ChannelSlack].sent)
+		assert.Len(t, senders[entity.ChannelLINE].sent, 1)
+		assert.Empty(t, senders[entity.ChannelEmail].sent)
+	})
+
+	t.Run("異常系: 失敗しても他の宛先には送り、宛先はエラーに含めない", func(t *testing.T) {
+		u, senders := setup()
+		senders[entity.ChannelLINE].fail = map[string]bool{testLINEUserID: true}
+
+		err := u.NotifyAdmins(context.Background(), nil, notification)
+		assert.EqualError(t, err, "user 1: failed to send line notification: unexpected status code 401")
+		assert.Len(t, senders[entity.ChannelSlack].sent, 1)
+		assert.Len(t, senders[entity.ChannelEmail].sent, 1)
+	})
+}
+
+func TestNotificationUsecase_SendTest(t *testing.T) {
+	ctx := reqctx.WithUserID(context.Background(), 2)
+
+	t.Run("正常系: 選んだチャンネルごとの結果を返す", func(t *testing.T) {
+		users := &MockUserRepository{}
+		users.On("FindByID", mock.Anything, int64(2)).Return(&entity.User{ID: 2, Email: "sato@example.com"}, nil)
+		u, senders, preferences := newTestNotificationUsecase(users)
+		preferences.preferences[2] = &entity.NotificationPreference{UserID: 2, Channels: []entity.NotificationChannel{entity.ChannelLINE, entity.ChannelEmail}, LINEUserID: testLINEUserID}
+		senders[entity.ChannelLINE].fail = map[string]bool{testLINEUserID: true}
+
+		results, err := u.SendTest(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []NotificationResult{
+			{Channel: entity.ChannelLINE, Error: "failed to send line notification: unexpected status code 401"},
+			{Channel: entity.ChannelEmail, Sent: 1},
+		}, results)
+		assert.Empty(t, senders[entity.ChannelSlack].sent)
+	})
+
+	t.Run("異常系: チャンネルを選んでいない", func(t *testing.T) {
+		u, _, _ := newTestNotificationUsecase(&MockUserRepository{})
+
+		_, err := u.SendTest(ctx)
+		assert.True(t, domainErrors.IsValidationError(err))
+	})
+}
+
+func TestSLOAlertNotifier(t *testing.T) {
+	users := &MockUserRepository{}
+	u, senders, _ := newTestNotificationUsecase(users)
+
+	err := NewSLOAlertNotifier(u).Notify(context.Background(), entity.SLOAlert{
+		Method: "GET", Route: "/items", SLI: entity.SLIAvailability, Target: 0.999, ShortBurnRate: 20, Threshold: 14.4,
+		Channels: []entity.NotificationChannel{entity.ChannelSlack},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{": SLO アラート: GET /items (availability)"}, senders[entity.ChannelSlack].sent)
+	users.AssertNotCalled(t, "FindByQuery", mock.Anything, mock.Anything)
+}
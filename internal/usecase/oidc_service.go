@@ -0,0 +1,337 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/pkg/clock"
+	"Aicon-assignment/internal/pkg/filter"
+	"Aicon-assignment/internal/pkg/idgen"
+	"Aicon-assignment/internal/pkg/reqctx"
+)
+
+// 認可画面に送り出してからコールバックで戻るまでの猶予
+const OIDCLoginTTL = 10 * time.Minute
+
+// 外部の ID プロバイダー（OIDC / OAuth2）。認可コードフローと PKCE（S256）で利用者を確かめる
+type IdentityProvider interface {
+	// AuthCodeURL は利用者を送り出す認可画面の URL を返す
+	AuthCodeURL(state, nonce, codeChallenge string) string
+	// Exchange は認可コードをトークンに交換し、プロバイダーが確かめた利用者を返す
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (*entity.ExternalIdentity, error)
+}
+
+// パスワードの代わりに外部の ID プロバイダーでログインする
+type OIDCUsecase interface {
+	// Providers は使えるプロバイダーの名前を返す
+	Providers() []string
+	// Begin はログインを始め、利用者を送り出す認可画面の URL を返す
+	// linkUserID を指定した場合は、ログインせずにそのユーザーに ID を紐付ける
+	Begin(ctx context.Context, provider string, linkUserID *int64) (string, error)
+	// Callback は認可画面から戻った利用者のログイン（または ID の紐付け）を完了する
+	// 不正・期限切れ・使用済みの state や、プロバイダーが利用者を確かめられなかった場合は ErrUnauthenticated を返す
+	Callback(ctx context.Context, state, code string) (*OIDCResult, error)
+	// Identities はユーザーに紐付けた ID を返す
+	Identities(ctx context.Context, userID int64) ([]*entity.UserIdentity, error)
+	// Unlink は ID の紐付けを外す。パスワードも他の ID もなくなりログインできなくなる場合は ErrForbidden を返す
+	Unlink(ctx context.Context, userID, identityID int64) error
+}
+
+type OIDCResult struct {
+	Token    *entity.AccessToken  `json:"token,omitempty"`    // ログインした場合
+	Identity *entity.UserIdentity `json:"identity,omitempty"` // ID を紐付けた場合
+	Created  bool                 `json:"created"`            // 初めてのログインでユーザーを作成した
+}
+
+// 本人確認を済ませたユーザーにトークンを発行する（AuthUsecase が実装する）
+type TokenIssuer interface {
+	LoginAs(ctx context.Context, user *entity.User) (*entity.AccessToken, error)
+}
+
+type oidcUsecase struct {
+	providers     map[string]IdentityProvider
+	users         UserRepository
+	identities    UserIdentityRepository
+	logins        OIDCLoginRepository
+	tokens        TokenIssuer
+	transactor    Transactor
+	autoProvision bool
+	clock         clock.Clock
+}
+
+// autoProvision が false の場合は、ID を紐付けたユーザーだけがログインできる
+func NewOIDCUsecase(
+	providers map[string]IdentityProvider,
+	users UserRepository,
+	identities UserIdentityRepository,
+	logins OIDCLoginRepository,
+	tokens TokenIssuer,
+	transactor Transactor,
+	autoProvision bool,
+	clock clock.Clock,
+) OIDCUsecase {
+	return &oidcUsecase{
+		providers:     providers,
+		users:         users,
+		identities:    identities,
+		logins:        logins,
+		tokens:        tokens,
+		transactor:    transactor,
+		autoProvision: autoProvision,
+		clock:         clock,
+	}
+}
+
+func (u *oidcUsecase) Providers() []string {
+	names := make([]string, 0, len(u.providers))
+	for name := range u.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (u *oidcUsecase) Begin(ctx context.Context, provider string, linkUserID *int64) (string, error) {
+	p, ok := u.providers[provider]
+	if !ok {
+		return "", fmt.Errorf("%w: provider must be one of: %s", domainErrors.ErrInvalidInput, strings.Join(u.Providers(), ", "))
+	}
+
+	now := u.clock.Now()
+	// 期限切れの要求はここで片付ける（定期実行のジョブを増やさない）
+	if _, err := u.logins.DeleteExpired(ctx, now); err != nil {
+		reqctx.Logger(ctx).Warn("failed to delete expired oidc logins", "error", err)
+	}
+
+	// PKCE の code_verifier は 43 文字以上にする（RFC 7636 4.1）
+	state := idgen.NewRandomID()
+	login := entity.NewOIDCLogin(provider, state, idgen.NewRandomID(), idgen.NewRandomID()+idgen.NewRandomID(), linkUserID, OIDCLoginTTL, now)
+	if err := u.logins.Create(ctx, login); err != nil {
+		return "", fmt.Errorf("failed to store login request: %w", err)
+	}
+
+	return p.AuthCodeURL(state, login.Nonce, login.CodeChallenge()), nil
+}
+
+func (u *oidcUsecase) Callback(ctx context.Context, state, code string) (*OIDCResult, error) {
+	if state == "" || code == "" {
+		return nil, fmt.Errorf("%w: state and code are required", domainErrors.ErrUnauthenticated)
+	}
+	login, err := u.logins.Take(ctx, entity.HashToken(state))
+	if errors.Is(err, domainErrors.ErrOIDCLoginNotFound) {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrUnauthenticated, err.Error())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve login request: %w", err)
+	}
+	if login.Expired(u.clock.Now()) {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrUnauthenticated, domainErrors.ErrOIDCLoginNotFound.Error())
+	}
+	p, ok := u.providers[login.Provider]
+	if !ok {
+		return nil, fmt.Errorf("%w: provider %s is no longer available", domainErrors.ErrUnauthenticated, login.Provider)
+	}
+
+	identity, err := p.Exchange(ctx, code, login.CodeVerifier, login.Nonce)
+	if err != nil {
+		reqctx.Logger(ctx).Info("oidc login failed", "provider", login.Provider, "error", err)
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrUnauthenticated, err.Error())
+	}
+	identity.Provider = login.Provider
+
+	if login.UserID != nil {
+		linked, err := u.link(ctx, *login.UserID, identity)
+		if err != nil {
+			return nil, err
+		}
+		return &OIDCResult{Identity: linked}, nil
+	}
+	return u.login(ctx, identity)
+}
+
+// ID を紐付けたユーザーでログインする。紐付けたユーザーがいない場合は作成する
+func (u *oidcUsecase) login(ctx context.Context, identity *entity.ExternalIdentity) (*OIDCResult, error) {
+	var user *entity.User
+	created := false
+	linked, err := u.identities.FindBySubject(ctx, identity.Provider, identity.Subject)
+	switch {
+	case err == nil:
+		user, err = u.users.FindByID(ctx, linked.UserID)
+		if errors.Is(err, domainErrors.ErrUserNotFound) {
+			return nil, domainErrors.ErrUnauthenticated
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve user: %w", err)
+		}
+	case errors.Is(err, domainErrors.ErrIdentityNotFound):
+		if !u.autoProvision {
+			reqctx.Logger(ctx).Info("oidc login rejected: identity is not linked", "provider", identity.Provider)
+			return nil, fmt.Errorf("%w: no account is linked to this %s account", domainErrors.ErrUnauthenticated, identity.Provider)
+		}
+		if user, err = u.provision(ctx, identity); err != nil {
+			return nil, err
+		}
+		created = true
+	default:
+		return nil, fmt.Errorf("failed to retrieve identity: %w", err)
+	}
+
+	token, err := u.tokens.LoginAs(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	return &OIDCResult{Token: token, Created: created}, nil
+}
+
+// 初めてのログインでユーザーを作成し、ID を紐付ける
+// 同じメールアドレスのユーザーがいる場合は乗っ取りを防ぐため作成も紐付けもせず、ログインしてから紐付けてもらう
+func (u *oidcUsecase) provision(ctx context.Context, identity *entity.ExternalIdentity) (*entity.User, error) {
+	email := ""
+	if identity.EmailVerified {
+		email = identity.Email
+	}
+	if email != "" {
+		existing, err := u.users.FindByQuery(ctx, entity.UserQuery{
+			Filter: &filter.Comparison{Field: "email", Op: filter.OpEq, Value: email},
+			Limit:  1,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve user: %w", err)
+		}
+		if len(existing) > 0 {
+			return nil, fmt.Errorf("%w: an account with email %s already exists; log in and link your %s account", domainErrors.ErrDuplicateEntry, email, identity.Provider)
+		}
+	}
+
+	var user *entity.User
+	err := u.transactor.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		user, err = u.createUser(ctx, identity, email)
+		if err != nil {
+			return err
+		}
+		_, err = u.createIdentity(ctx, user.ID, identity)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	reqctx.Logger(ctx).Info("user provisioned by oidc login", "user_id", user.ID, "user_name", user.UserName, "provider", identity.Provider)
+	return user, nil
+}
+
+// ユーザー名が使われている場合は末尾に乱数を付けてもう一度試す
+func (u *oidcUsecase) createUser(ctx context.Context, identity *entity.ExternalIdentity, email string) (*entity.User, error) {
+	userName := provisionedUserName(identity)
+	for attempt := 0; ; attempt++ {
+		user, err := entity.NewUserAt(u.clock.Now(), userName, identity.Name, email, "", true)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", domainErrors.ErrInvalidInput, err.Error())
+		}
+		err = u.users.Create(ctx, user)
+		if err == nil {
+			return user, nil
+		}
+		if !domainErrors.IsConflictError(err) || attempt > 0 {
+			return nil, fmt.Errorf("failed to create user: %w", err)
+		}
+		userName = provisionedUserName(identity) + "-" + idgen.NewRandomID()[:6]
+	}
+}
+
+// プロバイダーのログイン名、メールアドレスの @ より前、プロバイダーの ID の順に使う
+func provisionedUserName(identity *entity.ExternalIdentity) string {
+	if identity.Login != "" {
+		return identity.Login
+	}
+	if local, _, ok := strings.Cut(identity.Email, "@"); ok && local != "" && identity.EmailVerified {
+		return local
+	}
+	return identity.Provider + "-" + identity.Subject
+}
+
+// 同じ ID を同じユーザーにもう一度紐付けた場合は何もしない
+func (u *oidcUsecase) link(ctx context.Context, userID int64, identity *entity.ExternalIdentity) (*entity.UserIdentity, error) {
+	user, err := u.users.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.Active {
+		return nil, domainErrors.ErrUnauthenticated
+	}
+
+	existing, err := u.identities.FindBySubject(ctx, identity.Provider, identity.Subject)
+	if err == nil {
+		if existing.UserID == user.ID {
+			return existing, nil
+		}
+		return nil, fmt.Errorf("%w: this %s account is linked to another user", domainErrors.ErrDuplicateEntry, identity.Provider)
+	}
+	if !errors.Is(err, domainErrors.ErrIdentityNotFound) {
+		return nil, fmt.Errorf("failed to retrieve identity: %w", err)
+	}
+
+	linked, err := u.createIdentity(ctx, user.ID, identity)
+	if err != nil {
+		return nil, err
+	}
+
+	reqctx.Logger(ctx).Info("identity linked", "user_id", user.ID, "provider", identity.Provider, "identity_id", linked.ID)
+	return linked, nil
+}
+
+func (u *oidcUsecase) createIdentity(ctx context.Context, userID int64, identity *entity.ExternalIdentity) (*entity.UserIdentity, error) {
+	linked := &entity.UserIdentity{
+		UserID:    userID,
+		Provider:  identity.Provider,
+		Subject:   identity.Subject,
+		CreatedAt: u.clock.Now(),
+	}
+	if identity.EmailVerified {
+		linked.Email = identity.Email
+	}
+	if err := u.identities.Create(ctx, linked); err != nil {
+		if domainErrors.IsConflictError(err) {
+			return nil, fmt.Errorf("%w: this %s account is linked to another user", domainErrors.ErrDuplicateEntry, identity.Provider)
+		}
+		return nil, fmt.Errorf("failed to link identity: %w", err)
+	}
+	return linked, nil
+}
+
+func (u *oidcUsecase) Identities(ctx context.Context, userID int64) ([]*entity.UserIdentity, error) {
+	identities, err := u.identities.FindByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve identities: %w", err)
+	}
+	return identities, nil
+}
+
+func (u *oidcUsecase) Unlink(ctx context.Context, userID, identityID int64) error {
+	return u.transactor.Transaction(ctx, func(ctx context.Context) error {
+		user, err := u.users.FindByID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		identities, err := u.identities.FindByUser(ctx, userID)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve identities: %w", err)
+		}
+		if user.PasswordHash == "" && len(identities) == 1 && identities[0].ID == identityID {
+			return fmt.Errorf("%w: cannot unlink the only way to log in; set a password or link another account first", domainErrors.ErrForbidden)
+		}
+		if err := u.identities.Delete(ctx, userID, identityID); err != nil {
+			return err
+		}
+
+		reqctx.Logger(ctx).Info("identity unlinked", "user_id", userID, "identity_id", identityID)
+		return nil
+	})
+}
@@ -0,0 +1,308 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/pkg/clock"
+)
+
+// MockUserIdentityRepository はテスト用の外部 ID のリポジトリ
+type MockUserIdentityRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserIdentityRepository) Create(ctx context.Context, identity *entity.UserIdentity) error {
+	args := m.Called(ctx, identity)
+	return args.Error(0)
+}
+
+func (m *MockUserIdentityRepository) FindBySubject(ctx context.Context, provider, subject string) (*entity.UserIdentity, error) {
+	args := m.Called(ctx, provider, subject)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.UserIdentity), args.Error(1)
+}
+
+func (m *MockUserIdentityRepository) FindByUser(ctx context.Context, userID int64) ([]*entity.UserIdentity, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.UserIdentity), args.Error(1)
+}
+
+func (m *MockUserIdentityRepository) Delete(ctx context.Context, userID, id int64) error {
+	args := m.Called(ctx, userID, id)
+	return args.Error(0)
+}
+
+// MockOIDCLoginRepository はテスト用のログインの要求のリポジトリ
+type MockOIDCLoginRepository struct {
+	mock.Mock
+}
+
+func (m *MockOIDCLoginRepository) Create(ctx context.Context, login *entity.OIDCLogin) error {
+	args := m.Called(ctx, login)
+	return args.Error(0)
+}
+
+func (m *MockOIDCLoginRepository) Take(ctx context.Context, stateHash string) (*entity.OIDCLogin, error) {
+	args := m.Called(ctx, stateHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.OIDCLogin), args.Error(1)
+}
+
+func (m *MockOIDCLoginRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// fakeIdentityProvider は決まった利用者を返す ID プロバイダー
+type fakeIdentityProvider struct {
+	identity *entity.ExternalIdentity
+	err      error
+	verifier string
+	nonce    string
+}
+
+func (p *fakeIdentityProvider) AuthCodeURL(state, nonce, codeChallenge string) string {
+	return "https://idp.example.com/authorize?" + url.Values{"state": {state}, "nonce": {nonce}, "code_challenge": {codeChallenge}}.Encode()
+}
+
+func (p *fakeIdentityProvider) Exchange(ctx context.Context, code, codeVerifier, nonce string) (*entity.ExternalIdentity, error) {
+	p.verifier, p.nonce = codeVerifier, nonce
+	if p.err != nil {
+		return nil, p.err
+	}
+	identity := *p.identity
+	return &identity, nil
+}
+
+// fakeTokenIssuer は発行したユーザーを記録する
+type fakeTokenIssuer struct {
+	user *entity.User
+}
+
+func (f *fakeTokenIssuer) LoginAs(ctx context.Context, user *entity.User) (*entity.AccessToken, error) {
+	if !user.Active {
+		return nil, domainErrors.ErrUnauthenticated
+	}
+	f.user = user
+	return &entity.AccessToken{AccessToken: "jwt", TokenType: "Bearer"}, nil
+}
+
+func TestOIDCUsecase_Begin(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("正常系: 要求を保存し、state と PKCE の code_challenge を付けた認可画面の URL を返す", func(t *testing.T) {
+		logins := new(MockOIDCLoginRepository)
+		logins.On("DeleteExpired", mock.Anything, now).Return(int64(0), nil)
+		var stored *entity.OIDCLogin
+		logins.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			stored = args.Get(1).(*entity.OIDCLogin)
+		}).Return(nil)
+		providers := map[string]IdentityProvider{entity.IdentityProviderGoogle: &fakeIdentityProvider{}}
+
+		usecase := NewOIDCUsecase(providers, new(MockUserRepository), new(MockUserIdentityRepository), logins, &fakeTokenIssuer{}, &fakeTransactor{}, true, clock.NewFrozen(now))
+		authorizationURL, err := usecase.Begin(context.Background(), entity.IdentityProviderGoogle, nil)
+		require.NoError(t, err)
+
+		parsed, err := url.Parse(authorizationURL)
+		require.NoError(t, err)
+		query := parsed.Query()
+		require.NotNil(t, stored)
+		assert.Equal(t, entity.HashToken(query.Get("state")), stored.StateHash)
+		assert.Equal(t, stored.Nonce, query.Get("nonce"))
+		assert.Equal(t, stored.CodeChallenge(), query.Get("code_challenge"))
+		assert.GreaterOrEqual(t, len(stored.CodeVerifier), 43)
+		assert.Equal(t, now.Add(OIDCLoginTTL), stored.ExpiresAt)
+		assert.Nil(t, stored.UserID)
+	})
+
+	t.Run("異常系: 設定していないプロバイダー", func(t *testing.T) {
+		providers := map[string]IdentityProvider{entity.IdentityProviderGoogle: &fakeIdentityProvider{}}
+
+		usecase := NewOIDCUsecase(providers, new(MockUserRepository), new(MockUserIdentityRepository), new(MockOIDCLoginRepository), &fakeTokenIssuer{}, &fakeTransactor{}, true, clock.NewFrozen(now))
+		_, err := usecase.Begin(context.Background(), entity.IdentityProviderGitHub, nil)
+		assert.ErrorIs(t, err, domainErrors.ErrInvalidInput)
+		assert.Contains(t, err.Error(), "provider must be one of: google")
+	})
+}
+
+func TestOIDCUsecase_Callback(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	github := &entity.ExternalIdentity{Subject: "1234", Email: "yamada@example.com", EmailVerified: true, Name: "山田", Login: "yamada"}
+	pending := func(userID *int64) *entity.OIDCLogin {
+		return entity.NewOIDCLogin(entity.IdentityProviderGitHub, "state", "nonce", "verifier", userID, OIDCLoginTTL, now.Add(-time.Minute))
+	}
+	takes := func(login *entity.OIDCLogin) *MockOIDCLoginRepository {
+		logins := new(MockOIDCLoginRepository)
+		logins.On("Take", mock.Anything, entity.HashToken("state")).Return(login, nil)
+		return logins
+	}
+	newUsecase := func(users *MockUserRepository, identities *MockUserIdentityRepository, logins *MockOIDCLoginRepository, tokens TokenIssuer, autoProvision bool) (OIDCUsecase, *fakeIdentityProvider) {
+		provider := &fakeIdentityProvider{identity: github}
+		providers := map[string]IdentityProvider{entity.IdentityProviderGitHub: provider}
+		return NewOIDCUsecase(providers, users, identities, logins, tokens, &fakeTransactor{}, autoProvision, clock.NewFrozen(now)), provider
+	}
+
+	t.Run("正常系: 紐付けたユーザーでログインする", func(t *testing.T) {
+		identities := new(MockUserIdentityRepository)
+		identities.On("FindBySubject", mock.Anything, entity.IdentityProviderGitHub, "1234").Return(&entity.UserIdentity{ID: 1, UserID: 7}, nil)
+		users := new(MockUserRepository)
+		users.On("FindByID", mock.Anything, int64(7)).Return(&entity.User{ID: 7, UserName: "yamada", Active: true}, nil)
+		tokens := &fakeTokenIssuer{}
+
+		usecase, provider := newUsecase(users, identities, takes(pending(nil)), tokens, true)
+		result, err := usecase.Callback(context.Background(), "state", "code")
+		require.NoError(t, err)
+		assert.Equal(t, "jwt", result.Token.AccessToken)
+		assert.False(t, result.Created)
+		assert.Equal(t, int64(7), tokens.user.ID)
+		assert.Equal(t, "verifier", provider.verifier)
+		assert.Equal(t, "nonce", provider.nonce)
+	})
+
+	t.Run("正常系: 初めてのログインでユーザーを作成して紐付ける", func(t *testing.T) {
+		identities := new(MockUserIdentityRepository)
+		identities.On("FindBySubject", mock.Anything, entity.IdentityProviderGitHub, "1234").Return(nil, domainErrors.ErrIdentityNotFound)
+		identities.On("Create", mock.Anything, mock.MatchedBy(func(identity *entity.UserIdentity) bool {
+			return identity.UserID == 8 && identity.Subject == "1234" && identity.Email == "yamada@example.com"
+		})).Return(nil)
+		users := new(MockUserRepository)
+		users.On("FindByQuery", mock.Anything, mock.Anything).Return([]*entity.User{}, nil)
+		users.On("Create", mock.Anything, mock.MatchedBy(func(user *entity.User) bool {
+			return user.UserName == "yamada" && user.Email == "yamada@example.com" && user.PasswordHash == "" && user.Role == entity.UserRoleMember
+		})).Run(func(args mock.Arguments) {
+			args.Get(1).(*entity.User).ID = 8
+		}).Return(nil)
+		tokens := &fakeTokenIssuer{}
+
+		usecase, _ := newUsecase(users, identities, takes(pending(nil)), tokens, true)
+		result, err := usecase.Callback(context.Background(), "state", "code")
+		require.NoError(t, err)
+		assert.True(t, result.Created)
+		assert.Equal(t, int64(8), tokens.user.ID)
+		identities.AssertExpectations(t)
+	})
+
+	t.Run("異常系: 同じメールアドレスのユーザーがいる場合は作成も紐付けもしない", func(t *testing.T) {
+		identities := new(MockUserIdentityRepository)
+		identities.On("FindBySubject", mock.Anything, entity.IdentityProviderGitHub, "1234").Return(nil, domainErrors.ErrIdentityNotFound)
+		users := new(MockUserRepository)
+		users.On("FindByQuery", mock.Anything, mock.Anything).Return([]*entity.User{{ID: 7, Email: "yamada@example.com"}}, nil)
+
+		usecase, _ := newUsecase(users, identities, takes(pending(nil)), &fakeTokenIssuer{}, true)
+		_, err := usecase.Callback(context.Background(), "state", "code")
+		assert.ErrorIs(t, err, domainErrors.ErrDuplicateEntry)
+		users.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		identities.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("異常系: 自動作成しない設定では紐付けていない ID でログインできない", func(t *testing.T) {
+		identities := new(MockUserIdentityRepository)
+		identities.On("FindBySubject", mock.Anything, entity.IdentityProviderGitHub, "1234").Return(nil, domainErrors.ErrIdentityNotFound)
+
+		usecase, _ := newUsecase(new(MockUserRepository), identities, takes(pending(nil)), &fakeTokenIssuer{}, false)
+		_, err := usecase.Callback(context.Background(), "state", "code")
+		assert.ErrorIs(t, err, domainErrors.ErrUnauthenticated)
+	})
+
+	t.Run("正常系: ログイン中に始めた場合はユーザーに ID を紐付ける", func(t *testing.T) {
+		userID := int64(7)
+		users := new(MockUserRepository)
+		users.On("FindByID", mock.Anything, userID).Return(&entity.User{ID: 7, Active: true}, nil)
+		identities := new(MockUserIdentityRepository)
+		identities.On("FindBySubject", mock.Anything, entity.IdentityProviderGitHub, "1234").Return(nil, domainErrors.ErrIdentityNotFound)
+		identities.On("Create", mock.Anything, mock.MatchedBy(func(identity *entity.UserIdentity) bool {
+			return identity.UserID == 7 && identity.Provider == entity.IdentityProviderGitHub
+		})).Return(nil)
+		tokens := &fakeTokenIssuer{}
+
+		usecase, _ := newUsecase(users, identities, takes(pending(&userID)), tokens, true)
+		result, err := usecase.Callback(context.Background(), "state", "code")
+		require.NoError(t, err)
+		assert.Nil(t, result.Token)
+		assert.Equal(t, int64(7), result.Identity.UserID)
+		assert.Nil(t, tokens.user)
+	})
+
+	t.Run("異常系: 他のユーザーに紐付けた ID", func(t *testing.T) {
+		userID := int64(7)
+		users := new(MockUserRepository)
+		users.On("FindByID", mock.Anything, userID).Return(&entity.User{ID: 7, Active: true}, nil)
+		identities := new(MockUserIdentityRepository)
+		identities.On("FindBySubject", mock.Anything, entity.IdentityProviderGitHub, "1234").Return(&entity.UserIdentity{ID: 1, UserID: 9}, nil)
+
+		usecase, _ := newUsecase(users, identities, takes(pending(&userID)), &fakeTokenIssuer{}, true)
+		_, err := usecase.Callback(context.Background(), "state", "code")
+		assert.ErrorIs(t, err, domainErrors.ErrDuplicateEntry)
+		identities.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("異常系: 使用済み・不明な state", func(t *testing.T) {
+		logins := new(MockOIDCLoginRepository)
+		logins.On("Take", mock.Anything, entity.HashToken("state")).Return(nil, domainErrors.ErrOIDCLoginNotFound)
+
+		usecase, _ := newUsecase(new(MockUserRepository), new(MockUserIdentityRepository), logins, &fakeTokenIssuer{}, true)
+		_, err := usecase.Callback(context.Background(), "state", "code")
+		assert.ErrorIs(t, err, domainErrors.ErrUnauthenticated)
+	})
+
+	t.Run("異常系: 期限切れの要求", func(t *testing.T) {
+		expired := entity.NewOIDCLogin(entity.IdentityProviderGitHub, "state", "nonce", "verifier", nil, OIDCLoginTTL, now.Add(-time.Hour))
+
+		usecase, _ := newUsecase(new(MockUserRepository), new(MockUserIdentityRepository), takes(expired), &fakeTokenIssuer{}, true)
+		_, err := usecase.Callback(context.Background(), "state", "code")
+		assert.ErrorIs(t, err, domainErrors.ErrUnauthenticated)
+	})
+
+	t.Run("異常系: プロバイダーが利用者を確かめられなかった", func(t *testing.T) {
+		usecase, provider := newUsecase(new(MockUserRepository), new(MockUserIdentityRepository), takes(pending(nil)), &fakeTokenIssuer{}, true)
+		provider.err = errors.New("invalid_grant")
+
+		_, err := usecase.Callback(context.Background(), "state", "code")
+		assert.ErrorIs(t, err, domainErrors.ErrUnauthenticated)
+	})
+}
+
+func TestOIDCUsecase_Unlink(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("正常系: 他にログインの手段があれば紐付けを外す", func(t *testing.T) {
+		users := new(MockUserRepository)
+		users.On("FindByID", mock.Anything, int64(7)).Return(&entity.User{ID: 7, Active: true}, nil)
+		identities := new(MockUserIdentityRepository)
+		identities.On("FindByUser", mock.Anything, int64(7)).Return([]*entity.UserIdentity{{ID: 1, UserID: 7}, {ID: 2, UserID: 7}}, nil)
+		identities.On("Delete", mock.Anything, int64(7), int64(1)).Return(nil)
+
+		usecase := NewOIDCUsecase(nil, users, identities, new(MockOIDCLoginRepository), &fakeTokenIssuer{}, &fakeTransactor{}, true, clock.NewFrozen(now))
+		require.NoError(t, usecase.Unlink(context.Background(), 7, 1))
+		identities.AssertExpectations(t)
+	})
+
+	t.Run("異常系: パスワードも他の ID もないユーザーの最後の ID", func(t *testing.T) {
+		users := new(MockUserRepository)
+		users.On("FindByID", mock.Anything, int64(7)).Return(&entity.User{ID: 7, Active: true}, nil)
+		identities := new(MockUserIdentityRepository)
+		identities.On("FindByUser", mock.Anything, int64(7)).Return([]*entity.UserIdentity{{ID: 1, UserID: 7}}, nil)
+
+		usecase := NewOIDCUsecase(nil, users, identities, new(MockOIDCLoginRepository), &fakeTokenIssuer{}, &fakeTransactor{}, true, clock.NewFrozen(now))
+		err := usecase.Unlink(context.Background(), 7, 1)
+		assert.ErrorIs(t, err, domainErrors.ErrForbidden)
+		identities.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,406 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/pkg/clock"
+	"Aicon-assignment/internal/pkg/idgen"
+	"Aicon-assignment/internal/pkg/reqctx"
+)
+
+type OrganizationUsecase interface {
+	CreateOrganization(ctx context.Context, input CreateOrganizationInput) (*entity.Organization, error)
+	ListMembers(ctx context.Context, orgID int64) ([]*entity.Membership, error)
+	ChangeRole(ctx context.Context, orgID, userID int64, input ChangeRoleInput) (*entity.Membership, error)
+	RemoveMember(ctx context.Context, orgID, userID int64) error
+	Invite(ctx context.Context, orgID int64, input InviteInput) (*entity.Invitation, error)
+	ListInvitations(ctx context.Context, orgID int64) ([]*entity.Invitation, error)
+	AcceptInvitation(ctx context.Context, token string) (*entity.Membership, error)
+	GetBranding(ctx context.Context, orgID int64) (*entity.Branding, error)
+	UpdateBranding(ctx context.Context, orgID int64, input UpdateBrandingInput) (*entity.Branding, error)
+	GetFieldLocks(ctx context.Context, orgID int64) (*entity.FieldLockPolicy, error)
+	UpdateFieldLocks(ctx context.Context, orgID int64, input UpdateFieldLocksInput) (*entity.FieldLockPolicy, error)
+}
+
+type CreateOrganizationInput struct {
+	Name string `json:"name"`
+}
+
+type ChangeRoleInput struct {
+	Role string `json:"role"`
+}
+
+type InviteInput struct {
+	Email string `json:"email"`
+	Role  string `json:"role"` // 省略時は member
+}
+
+// 省略した項目はデフォルト（組織名・ロゴなし・entity.DefaultAccentColor）に戻る
+type UpdateBrandingInput struct {
+	DisplayName string `json:"display_name"`
+	LogoURL     string `json:"logo_url"`
+	AccentColor string `json:"accent_color"`
+}
+
+// ルールをすべて置き換える。空の配列の場合はサインオフのロックだけが残る
+type UpdateFieldLocksInput struct {
+	Rules []entity.FieldLockRule `json:"rules"`
+}
+
+type organizationUsecase struct {
+	orgs        OrganizationRepository
+	invitations InvitationRepository
+	users       UserRepository
+	mailer      Mailer
+	transactor  Transactor
+	clock       clock.Clock
+	acceptURL   string
+}
+
+// acceptURL は招待メールに載せる承諾ページの URL。トークンはクエリパラメータで渡す
+func NewOrganizationUsecase(
+	orgs OrganizationRepository,
+	invitations InvitationRepository,
+	users UserRepository,
+	mailer Mailer,
+	transactor Transactor,
+	clock clock.Clock,
+	acceptURL string,
+) OrganizationUsecase {
+	return &organizationUsecase{
+		orgs:        orgs,
+		invitations: invitations,
+		users:       users,
+		mailer:      mailer,
+		transactor:  transactor,
+		clock:       clock,
+		acceptURL:   acceptURL,
+	}
+}
+
+// 組織を作成し、呼び出し元のユーザーを管理者として追加する
+func (u *organizationUsecase) CreateOrganization(ctx context.Context, input CreateOrganizationInput) (*entity.Organization, error) {
+	callerID, ok := reqctx.UserID(ctx)
+	if !ok {
+		return nil, domainErrors.ErrUnauthenticated
+	}
+
+	now := u.clock.Now()
+	org, err := entity.NewOrganizationAt(now, input.Name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrInvalidInput, err.Error())
+	}
+
+	err = u.transactor.Transaction(ctx, func(ctx context.Context) error {
+		if err := u.orgs.Create(ctx, org); err != nil {
+			return err
+		}
+		return u.orgs.AddMember(ctx, &entity.Membership{
+			OrgID:     org.ID,
+			UserID:    callerID,
+			Role:      entity.OrgRoleAdmin,
+			CreatedAt: now,
+			UpdatedAt: now,
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create organization: %w", err)
+	}
+
+	return org, nil
+}
+
+// メンバー一覧は組織のメンバーなら誰でも参照できる
+func (u *organizationUsecase) ListMembers(ctx context.Context, orgID int64) ([]*entity.Membership, error) {
+	if _, err := u.authorize(ctx, orgID, false); err != nil {
+		return nil, err
+	}
+
+	members, err := u.orgs.FindMembers(ctx, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve members: %w", err)
+	}
+	return members, nil
+}
+
+// 管理者がメンバーのロールを変更する。最後の管理者は降格できない
+func (u *organizationUsecase) ChangeRole(ctx context.Context, orgID, userID int64, input ChangeRoleInput) (*entity.Membership, error) {
+	if err := entity.ValidateOrgRole(input.Role); err != nil {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrInvalidInput, err.Error())
+	}
+	if _, err := u.authorize(ctx, orgID, true); err != nil {
+		return nil, err
+	}
+
+	var member *entity.Membership
+	err := u.transactor.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		member, err = u.orgs.FindMember(ctx, orgID, userID)
+		if err != nil {
+			return err
+		}
+		if member.Role == input.Role {
+			return nil
+		}
+		if member.Role == entity.OrgRoleAdmin {
+			if err := u.ensureAnotherAdmin(ctx, orgID); err != nil {
+				return err
+			}
+		}
+
+		member.Role = input.Role
+		member.UpdatedAt = u.clock.Now()
+		return u.orgs.UpdateMember(ctx, member)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return member, nil
+}
+
+// 管理者は任意のメンバーを、メンバーは自分自身を組織から外せる。最後の管理者は外せない
+func (u *organizationUsecase) RemoveMember(ctx context.Context, orgID, userID int64) error {
+	callerID, ok := reqctx.UserID(ctx)
+	if !ok {
+		return domainErrors.ErrUnauthenticated
+	}
+	if _, err := u.authorize(ctx, orgID, callerID != userID); err != nil {
+		return err
+	}
+
+	return u.transactor.Transaction(ctx, func(ctx context.Context) error {
+		member, err := u.orgs.FindMember(ctx, orgID, userID)
+		if err != nil {
+			return err
+		}
+		if member.Role == entity.OrgRoleAdmin {
+			if err := u.ensureAnotherAdmin(ctx, orgID); err != nil {
+				return err
+			}
+		}
+		return u.orgs.RemoveMember(ctx, orgID, userID)
+	})
+}
+
+// 招待を作成し、承諾用のトークンをメールで送る
+// トークンはメールにだけ載せ、招待した管理者には返さない
+func (u *organizationUsecase) Invite(ctx context.Context, orgID int64, input InviteInput) (*entity.Invitation, error) {
+	org, err := u.authorize(ctx, orgID, true)
+	if err != nil {
+		return nil, err
+	}
+	callerID, _ := reqctx.UserID(ctx)
+
+	invitation, err := entity.NewInvitation(orgID, callerID, input.Email, input.Role, idgen.NewRandomID(), u.clock.Now())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrInvalidInput, err.Error())
+	}
+
+	// メールを送れなかった場合は、誰も承諾できない招待が残らないようロールバックする
+	err = u.transactor.Transaction(ctx, func(ctx context.Context) error {
+		if err := u.invitations.Create(ctx, invitation); err != nil {
+			return err
+		}
+		if err := u.mailer.Send(ctx, u.invitationMail(org, invitation)); err != nil {
+			return fmt.Errorf("failed to send invitation mail: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create invitation: %w", err)
+	}
+
+	reqctx.Logger(ctx).Info("invitation sent",
+		"invitation_id", invitation.ID, "organization_id", orgID, "invited_by", callerID)
+	invitation.Token = ""
+	return invitation, nil
+}
+
+func (u *organizationUsecase) invitationMail(org *entity.Organization, invitation *entity.Invitation) Mail {
+	var body strings.Builder
+	fmt.Fprintf(&body, "You have been invited to join %s as %s.\n\n", org.Name, invitation.Role)
+	if u.acceptURL != "" {
+		fmt.Fprintf(&body, "Accept the invitation: %s?token=%s\n", u.acceptURL, invitation.Token)
+	} else {
+		fmt.Fprintf(&body, "Invitation token: %s\n", invitation.Token)
+	}
+	fmt.Fprintf(&body, "\nThis invitation expires at %s.\n", invitation.ExpiresAt.UTC().Format("2006-01-02 15:04 MST"))
+
+	return Mail{
+		To:      invitation.Email,
+		Subject: fmt.Sprintf("Invitation to %s", org.Name),
+		Body:    body.String(),
+	}
+}
+
+func (u *organizationUsecase) ListInvitations(ctx context.Context, orgID int64) ([]*entity.Invitation, error) {
+	if _, err := u.authorize(ctx, orgID, true); err != nil {
+		return nil, err
+	}
+
+	invitations, err := u.invitations.FindByOrgID(ctx, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve invitations: %w", err)
+	}
+	return invitations, nil
+}
+
+// 呼び出し元のユーザーとして招待を承諾し、組織に参加する
+// 招待されたメールアドレスのユーザーだけが承諾できる
+func (u *organizationUsecase) AcceptInvitation(ctx context.Context, token string) (*entity.Membership, error) {
+	callerID, ok := reqctx.UserID(ctx)
+	if !ok {
+		return nil, domainErrors.ErrUnauthenticated
+	}
+	if strings.TrimSpace(token) == "" {
+		return nil, fmt.Errorf("%w: token is required", domainErrors.ErrInvalidInput)
+	}
+
+	user, err := u.users.FindByID(ctx, callerID)
+	if err != nil {
+		return nil, err
+	}
+
+	invitation, err := u.invitations.FindByTokenHash(ctx, entity.HashToken(token))
+	if err != nil {
+		return nil, err
+	}
+	now := u.clock.Now()
+	if !invitation.Pending(now) {
+		return nil, domainErrors.ErrInvitationNotFound
+	}
+	if !invitation.IsFor(user) {
+		return nil, fmt.Errorf("%w: invitation was sent to a different email address", domainErrors.ErrForbidden)
+	}
+
+	member := &entity.Membership{
+		OrgID:     invitation.OrgID,
+		UserID:    callerID,
+		Role:      invitation.Role,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	err = u.transactor.Transaction(ctx, func(ctx context.Context) error {
+		if err := u.invitations.MarkAccepted(ctx, invitation.ID, callerID, now); err != nil {
+			return err
+		}
+		if err := u.orgs.AddMember(ctx, member); err != nil {
+			if errors.Is(err, domainErrors.ErrDuplicateEntry) {
+				return fmt.Errorf("%w: already a member of this organization", err)
+			}
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return member, nil
+}
+
+// 組織の外に見せる画面で使うため、認証なしで参照できる
+// 設定されていない場合は組織名を使ったデフォルトを返す
+func (u *organizationUsecase) GetBranding(ctx context.Context, orgID int64) (*entity.Branding, error) {
+	org, err := u.orgs.FindByID(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	branding, err := u.orgs.FindBranding(ctx, orgID)
+	if errors.Is(err, domainErrors.ErrBrandingNotFound) {
+		return entity.DefaultBranding(org), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve branding: %w", err)
+	}
+	return branding, nil
+}
+
+func (u *organizationUsecase) UpdateBranding(ctx context.Context, orgID int64, input UpdateBrandingInput) (*entity.Branding, error) {
+	org, err := u.authorize(ctx, orgID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	branding, err := entity.NewBranding(org, input.DisplayName, input.LogoURL, input.AccentColor, u.clock.Now())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrInvalidInput, err.Error())
+	}
+
+	if err := u.orgs.SaveBranding(ctx, branding); err != nil {
+		return nil, fmt.Errorf("failed to save branding: %w", err)
+	}
+	return branding, nil
+}
+
+// 組織のアイテムに適用するロックのルール。組織のメンバーが参照できる
+func (u *organizationUsecase) GetFieldLocks(ctx context.Context, orgID int64) (*entity.FieldLockPolicy, error) {
+	if _, err := u.authorize(ctx, orgID, false); err != nil {
+		return nil, err
+	}
+
+	policy, err := u.orgs.FindFieldLockPolicy(ctx, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve field lock rules: %w", err)
+	}
+	return policy, nil
+}
+
+func (u *organizationUsecase) UpdateFieldLocks(ctx context.Context, orgID int64, input UpdateFieldLocksInput) (*entity.FieldLockPolicy, error) {
+	if _, err := u.authorize(ctx, orgID, true); err != nil {
+		return nil, err
+	}
+
+	policy := entity.NewFieldLockPolicy(orgID)
+	if err := policy.Replace(input.Rules, u.clock.Now()); err != nil {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrInvalidInput, err.Error())
+	}
+
+	if err := u.orgs.SaveFieldLockPolicy(ctx, policy); err != nil {
+		return nil, fmt.Errorf("failed to save field lock rules: %w", err)
+	}
+	return policy, nil
+}
+
+// 呼び出し元が組織のメンバーであることを確認する。requireAdmin の場合は管理者であることも確認する
+func (u *organizationUsecase) authorize(ctx context.Context, orgID int64, requireAdmin bool) (*entity.Organization, error) {
+	callerID, ok := reqctx.UserID(ctx)
+	if !ok {
+		return nil, domainErrors.ErrUnauthenticated
+	}
+	org, err := u.orgs.FindByID(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	member, err := u.orgs.FindMember(ctx, orgID, callerID)
+	if errors.Is(err, domainErrors.ErrMemberNotFound) {
+		return nil, fmt.Errorf("%w: not a member of this organization", domainErrors.ErrForbidden)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve membership: %w", err)
+	}
+	if requireAdmin && member.Role != entity.OrgRoleAdmin {
+		return nil, fmt.Errorf("%w: organization admin role required", domainErrors.ErrForbidden)
+	}
+	return org, nil
+}
+
+// 管理者を外す前に、ほかに管理者が残ることを確認する
+func (u *organizationUsecase) ensureAnotherAdmin(ctx context.Context, orgID int64) error {
+	admins, err := u.orgs.CountAdmins(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	if admins <= 1 {
+		return domainErrors.ErrLastAdmin
+	}
+	return nil
+}
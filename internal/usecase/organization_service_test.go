@@ -0,0 +1,424 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/pkg/clock"
+	"Aicon-assignment/internal/pkg/reqctx"
+)
+
+// MockOrganizationRepository はテスト用の組織リポジトリ
+type MockOrganizationRepository struct {
+	mock.Mock
+}
+
+func (m *MockOrganizationRepository) Create(ctx context.Context, org *entity.Organization) error {
+	args := m.Called(ctx, org)
+	return args.Error(0)
+}
+
+func (m *MockOrganizationRepository) FindByID(ctx context.Context, id int64) (*entity.Organization, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Organization), args.Error(1)
+}
+
+func (m *MockOrganizationRepository) FindPage(ctx context.Context, limit, offset int) ([]*entity.Organization, error) {
+	args := m.Called(ctx, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.Organization), args.Error(1)
+}
+
+func (m *MockOrganizationRepository) Count(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockOrganizationRepository) FindMembers(ctx context.Context, orgID int64) ([]*entity.Membership, error) {
+	args := m.Called(ctx, orgID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.Membership), args.Error(1)
+}
+
+func (m *MockOrganizationRepository) FindMember(ctx context.Context, orgID, userID int64) (*entity.Membership, error) {
+	args := m.Called(ctx, orgID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Membership), args.Error(1)
+}
+
+func (m *MockOrganizationRepository) AddMember(ctx context.Context, member *entity.Membership) error {
+	args := m.Called(ctx, member)
+	return args.Error(0)
+}
+
+func (m *MockOrganizationRepository) UpdateMember(ctx context.Context, member *entity.Membership) error {
+	args := m.Called(ctx, member)
+	return args.Error(0)
+}
+
+func (m *MockOrganizationRepository) RemoveMember(ctx context.Context, orgID, userID int64) error {
+	args := m.Called(ctx, orgID, userID)
+	return args.Error(0)
+}
+
+func (m *MockOrganizationRepository) CountAdmins(ctx context.Context, orgID int64) (int, error) {
+	args := m.Called(ctx, orgID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockOrganizationRepository) FindBranding(ctx context.Context, orgID int64) (*entity.Branding, error) {
+	args := m.Called(ctx, orgID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Branding), args.Error(1)
+}
+
+func (m *MockOrganizationRepository) SaveBranding(ctx context.Context, branding *entity.Branding) error {
+	args := m.Called(ctx, branding)
+	return args.Error(0)
+}
+
+func (m *MockOrganizationRepository) FindFieldLockPolicy(ctx context.Context, orgID int64) (*entity.FieldLockPolicy, error) {
+	args := m.Called(ctx, orgID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.FieldLockPolicy), args.Error(1)
+}
+
+func (m *MockOrganizationRepository) SaveFieldLockPolicy(ctx context.Context, policy *entity.FieldLockPolicy) error {
+	args := m.Called(ctx, policy)
+	return args.Error(0)
+}
+
+// MockInvitationRepository はテスト用の招待リポジトリ
+type MockInvitationRepository struct {
+	mock.Mock
+}
+
+func (m *MockInvitationRepository) Create(ctx context.Context, invitation *entity.Invitation) error {
+	args := m.Called(ctx, invitation)
+	return args.Error(0)
+}
+
+func (m *MockInvitationRepository) FindByTokenHash(ctx context.Context, tokenHash string) (*entity.Invitation, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Invitation), args.Error(1)
+}
+
+func (m *MockInvitationRepository) FindByOrgID(ctx context.Context, orgID int64) ([]*entity.Invitation, error) {
+	args := m.Called(ctx, orgID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.Invitation), args.Error(1)
+}
+
+func (m *MockInvitationRepository) MarkAccepted(ctx context.Context, id, userID int64, at time.Time) error {
+	args := m.Called(ctx, id, userID, at)
+	return args.Error(0)
+}
+
+// fakeMailer は送信したメールを記録する
+type fakeMailer struct {
+	sent []Mail
+	err  error
+}
+
+func (f *fakeMailer) Send(ctx context.Context, mail Mail) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.sent = append(f.sent, mail)
+	return nil
+}
+
+// 組織1: ユーザー1が管理者、ユーザー2がメンバー
+func newOrganizationRepo() *MockOrganizationRepository {
+	orgs := new(MockOrganizationRepository)
+	orgs.On("FindByID", mock.Anything, int64(1)).Return(&entity.Organization{ID: 1, Name: "Aicon"}, nil).Maybe()
+	orgs.On("FindByID", mock.Anything, mock.Anything).Return(nil, domainErrors.ErrOrganizationNotFound).Maybe()
+	orgs.On("FindMember", mock.Anything, int64(1), int64(1)).Return(&entity.Membership{OrgID: 1, UserID: 1, Role: entity.OrgRoleAdmin}, nil).Maybe()
+	orgs.On("FindMember", mock.Anything, int64(1), int64(2)).Return(&entity.Membership{OrgID: 1, UserID: 2, Role: entity.OrgRoleMember}, nil).Maybe()
+	orgs.On("FindMember", mock.Anything, mock.Anything, mock.Anything).Return(nil, domainErrors.ErrMemberNotFound).Maybe()
+	return orgs
+}
+
+func TestOrganizationUsecase_ChangeRoleAndRemove(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	admin := reqctx.WithUserID(context.Background(), 1)
+	member := reqctx.WithUserID(context.Background(), 2)
+
+	newUsecase := func(orgs *MockOrganizationRepository) OrganizationUsecase {
+		return NewOrganizationUsecase(orgs, new(MockInvitationRepository), new(MockUserRepository), &fakeMailer{}, &fakeTransactor{}, clock.NewFrozen(now), "")
+	}
+
+	t.Run("正常系: メンバーを管理者に昇格する", func(t *testing.T) {
+		orgs := newOrganizationRepo()
+		orgs.On("UpdateMember", mock.Anything, mock.MatchedBy(func(m *entity.Membership) bool {
+			return m.UserID == 2 && m.Role == entity.OrgRoleAdmin && m.UpdatedAt.Equal(now)
+		})).Return(nil)
+
+		got, err := newUsecase(orgs).ChangeRole(admin, 1, 2, ChangeRoleInput{Role: entity.OrgRoleAdmin})
+		require.NoError(t, err)
+		assert.Equal(t, entity.OrgRoleAdmin, got.Role)
+		orgs.AssertExpectations(t)
+	})
+
+	t.Run("異常系: 最後の管理者は降格できない", func(t *testing.T) {
+		orgs := newOrganizationRepo()
+		orgs.On("CountAdmins", mock.Anything, int64(1)).Return(1, nil)
+
+		_, err := newUsecase(orgs).ChangeRole(admin, 1, 1, ChangeRoleInput{Role: entity.OrgRoleMember})
+		assert.ErrorIs(t, err, domainErrors.ErrLastAdmin)
+		orgs.AssertNotCalled(t, "UpdateMember", mock.Anything, mock.Anything)
+	})
+
+	t.Run("異常系: 最後の管理者は外せない", func(t *testing.T) {
+		orgs := newOrganizationRepo()
+		orgs.On("CountAdmins", mock.Anything, int64(1)).Return(1, nil)
+
+		err := newUsecase(orgs).RemoveMember(admin, 1, 1)
+		assert.ErrorIs(t, err, domainErrors.ErrLastAdmin)
+		orgs.AssertNotCalled(t, "RemoveMember", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("正常系: ほかに管理者がいれば管理者を外せる", func(t *testing.T) {
+		orgs := newOrganizationRepo()
+		orgs.On("CountAdmins", mock.Anything, int64(1)).Return(2, nil)
+		orgs.On("RemoveMember", mock.Anything, int64(1), int64(1)).Return(nil)
+
+		require.NoError(t, newUsecase(orgs).RemoveMember(admin, 1, 1))
+		orgs.AssertExpectations(t)
+	})
+
+	t.Run("正常系: メンバーは自分自身を外せる", func(t *testing.T) {
+		orgs := newOrganizationRepo()
+		orgs.On("RemoveMember", mock.Anything, int64(1), int64(2)).Return(nil)
+
+		require.NoError(t, newUsecase(orgs).RemoveMember(member, 1, 2))
+		orgs.AssertExpectations(t)
+	})
+
+	t.Run("異常系: メンバーはほかのユーザーのロールを変更できない", func(t *testing.T) {
+		_, err := newUsecase(newOrganizationRepo()).ChangeRole(member, 1, 1, ChangeRoleInput{Role: entity.OrgRoleMember})
+		assert.ErrorIs(t, err, domainErrors.ErrForbidden)
+	})
+
+	t.Run("異常系: 組織に所属していないユーザー", func(t *testing.T) {
+		outsider := reqctx.WithUserID(context.Background(), 3)
+		_, err := newUsecase(newOrganizationRepo()).ListMembers(outsider, 1)
+		assert.ErrorIs(t, err, domainErrors.ErrForbidden)
+	})
+
+	t.Run("異常系: 不正なロール", func(t *testing.T) {
+		_, err := newUsecase(newOrganizationRepo()).ChangeRole(admin, 1, 2, ChangeRoleInput{Role: "owner"})
+		assert.ErrorIs(t, err, domainErrors.ErrInvalidInput)
+	})
+}
+
+func TestOrganizationUsecase_Invite(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	admin := reqctx.WithUserID(context.Background(), 1)
+
+	t.Run("正常系: 招待メールにだけトークンを載せる", func(t *testing.T) {
+		invitations := new(MockInvitationRepository)
+		invitations.On("Create", mock.Anything, mock.AnythingOfType("*entity.Invitation")).Return(nil)
+		mailer := &fakeMailer{}
+
+		got, err := NewOrganizationUsecase(newOrganizationRepo(), invitations, new(MockUserRepository), mailer, &fakeTransactor{}, clock.NewFrozen(now), "https://example.com/invitations/accept").
+			Invite(admin, 1, InviteInput{Email: "new@example.com"})
+		require.NoError(t, err)
+		assert.Equal(t, entity.OrgRoleMember, got.Role)
+		assert.Empty(t, got.Token)
+		assert.Equal(t, now.Add(entity.InvitationTTL), got.ExpiresAt)
+
+		require.Len(t, mailer.sent, 1)
+		assert.Equal(t, "new@example.com", mailer.sent[0].To)
+		assert.Contains(t, mailer.sent[0].Body, "https://example.com/invitations/accept?token=")
+
+		created := invitations.Calls[0].Arguments.Get(1).(*entity.Invitation)
+		token := mailer.sent[0].Body[strings.Index(mailer.sent[0].Body, "token=")+len("token="):]
+		assert.Equal(t, created.TokenHash, entity.HashToken(strings.Fields(token)[0]))
+	})
+
+	t.Run("異常系: メールを送れない場合はロールバックする", func(t *testing.T) {
+		invitations := new(MockInvitationRepository)
+		invitations.On("Create", mock.Anything, mock.AnythingOfType("*entity.Invitation")).Return(nil)
+		transactor := &fakeTransactor{}
+
+		_, err := NewOrganizationUsecase(newOrganizationRepo(), invitations, new(MockUserRepository), &fakeMailer{err: errors.New("smtp down")}, transactor, clock.NewFrozen(now), "").
+			Invite(admin, 1, InviteInput{Email: "new@example.com"})
+		assert.Error(t, err)
+		assert.True(t, transactor.rolledBack)
+	})
+
+	t.Run("異常系: メンバーは招待できない", func(t *testing.T) {
+		_, err := NewOrganizationUsecase(newOrganizationRepo(), new(MockInvitationRepository), new(MockUserRepository), &fakeMailer{}, &fakeTransactor{}, clock.NewFrozen(now), "").
+			Invite(reqctx.WithUserID(context.Background(), 2), 1, InviteInput{Email: "new@example.com"})
+		assert.ErrorIs(t, err, domainErrors.ErrForbidden)
+	})
+}
+
+func TestOrganizationUsecase_AcceptInvitation(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	caller := reqctx.WithUserID(context.Background(), 3)
+
+	tests := []struct {
+		name       string
+		email      string
+		invitation *entity.Invitation
+		addErr     error
+		wantErr    error
+	}{
+		{
+			name:       "正常系: メールアドレスは大文字小文字を区別しない",
+			email:      "New@Example.com",
+			invitation: &entity.Invitation{ID: 5, OrgID: 1, Email: "new@example.com", Role: entity.OrgRoleAdmin, ExpiresAt: now.Add(time.Hour)},
+		},
+		{
+			name:       "異常系: 別のメールアドレス宛ての招待",
+			email:      "other@example.com",
+			invitation: &entity.Invitation{ID: 5, OrgID: 1, Email: "new@example.com", ExpiresAt: now.Add(time.Hour)},
+			wantErr:    domainErrors.ErrForbidden,
+		},
+		{
+			name:       "異常系: 期限切れの招待",
+			email:      "new@example.com",
+			invitation: &entity.Invitation{ID: 5, OrgID: 1, Email: "new@example.com", ExpiresAt: now},
+			wantErr:    domainErrors.ErrInvitationNotFound,
+		},
+		{
+			name:       "異常系: すでにメンバー",
+			email:      "new@example.com",
+			invitation: &entity.Invitation{ID: 5, OrgID: 1, Email: "new@example.com", Role: entity.OrgRoleMember, ExpiresAt: now.Add(time.Hour)},
+			addErr:     domainErrors.ErrDuplicateEntry,
+			wantErr:    domainErrors.ErrDuplicateEntry,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users := new(MockUserRepository)
+			users.On("FindByID", mock.Anything, int64(3)).Return(&entity.User{ID: 3, Email: tt.email, Active: true}, nil)
+			invitations := new(MockInvitationRepository)
+			invitations.On("FindByTokenHash", mock.Anything, entity.HashToken("secret")).Return(tt.invitation, nil)
+			invitations.On("MarkAccepted", mock.Anything, int64(5), int64(3), now).Return(nil).Maybe()
+			orgs := new(MockOrganizationRepository)
+			orgs.On("AddMember", mock.Anything, mock.AnythingOfType("*entity.Membership")).Return(tt.addErr).Maybe()
+			transactor := &fakeTransactor{}
+
+			member, err := NewOrganizationUsecase(orgs, invitations, users, &fakeMailer{}, transactor, clock.NewFrozen(now), "").
+				AcceptInvitation(caller, "secret")
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.False(t, transactor.committed)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.invitation.Role, member.Role)
+			assert.Equal(t, int64(3), member.UserID)
+			assert.True(t, transactor.committed)
+		})
+	}
+}
+
+func TestOrganizationUsecase_Branding(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	newUsecase := func(orgs *MockOrganizationRepository) OrganizationUsecase {
+		return NewOrganizationUsecase(orgs, new(MockInvitationRepository), new(MockUserRepository), &fakeMailer{}, &fakeTransactor{}, clock.NewFrozen(now), "")
+	}
+
+	t.Run("正常系: 未設定の場合は組織名を使う（認証なし）", func(t *testing.T) {
+		orgs := newOrganizationRepo()
+		orgs.On("FindBranding", mock.Anything, int64(1)).Return(nil, domainErrors.ErrBrandingNotFound)
+
+		got, err := newUsecase(orgs).GetBranding(context.Background(), 1)
+		require.NoError(t, err)
+		assert.Equal(t, "Aicon", got.DisplayName)
+		assert.Equal(t, entity.DefaultAccentColor, got.AccentColor)
+	})
+
+	t.Run("正常系: 管理者が変更する", func(t *testing.T) {
+		orgs := newOrganizationRepo()
+		orgs.On("SaveBranding", mock.Anything, mock.MatchedBy(func(b *entity.Branding) bool {
+			return b.OrgID == 1 && b.AccentColor == "#FF8800" && b.UpdatedAt.Equal(now)
+		})).Return(nil)
+
+		_, err := newUsecase(orgs).UpdateBranding(reqctx.WithUserID(context.Background(), 1), 1, UpdateBrandingInput{AccentColor: "#ff8800"})
+		require.NoError(t, err)
+		orgs.AssertExpectations(t)
+	})
+
+	t.Run("異常系: メンバーは変更できない", func(t *testing.T) {
+		_, err := newUsecase(newOrganizationRepo()).UpdateBranding(reqctx.WithUserID(context.Background(), 2), 1, UpdateBrandingInput{})
+		assert.ErrorIs(t, err, domainErrors.ErrForbidden)
+	})
+
+	t.Run("異常系: 存在しない組織", func(t *testing.T) {
+		_, err := newUsecase(newOrganizationRepo()).GetBranding(context.Background(), 99)
+		assert.ErrorIs(t, err, domainErrors.ErrOrganizationNotFound)
+	})
+}
+
+func TestOrganizationUsecase_FieldLocks(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	newUsecase := func(orgs *MockOrganizationRepository) OrganizationUsecase {
+		return NewOrganizationUsecase(orgs, new(MockInvitationRepository), new(MockUserRepository), &fakeMailer{}, &fakeTransactor{}, clock.NewFrozen(now), "")
+	}
+	rules := []entity.FieldLockRule{{Name: "appraised", Status: entity.VerificationInReview, Fields: []string{"purchase_price"}}}
+
+	t.Run("正常系: 管理者がルールを置き換える", func(t *testing.T) {
+		orgs := newOrganizationRepo()
+		orgs.On("SaveFieldLockPolicy", mock.Anything, mock.MatchedBy(func(p *entity.FieldLockPolicy) bool {
+			return p.OrgID == 1 && len(p.Rules) == 1 && p.UpdatedAt.Equal(now)
+		})).Return(nil)
+
+		_, err := newUsecase(orgs).UpdateFieldLocks(reqctx.WithUserID(context.Background(), 1), 1, UpdateFieldLocksInput{Rules: rules})
+		require.NoError(t, err)
+		orgs.AssertExpectations(t)
+	})
+
+	t.Run("正常系: メンバーはルールを参照できる", func(t *testing.T) {
+		orgs := newOrganizationRepo()
+		orgs.On("FindFieldLockPolicy", mock.Anything, int64(1)).Return(entity.NewFieldLockPolicy(1), nil)
+
+		got, err := newUsecase(orgs).GetFieldLocks(reqctx.WithUserID(context.Background(), 2), 1)
+		require.NoError(t, err)
+		assert.Empty(t, got.Rules)
+	})
+
+	t.Run("異常系: メンバーは変更できない", func(t *testing.T) {
+		_, err := newUsecase(newOrganizationRepo()).UpdateFieldLocks(reqctx.WithUserID(context.Background(), 2), 1, UpdateFieldLocksInput{Rules: rules})
+		assert.ErrorIs(t, err, domainErrors.ErrForbidden)
+	})
+
+	t.Run("異常系: ロックできないフィールド", func(t *testing.T) {
+		input := UpdateFieldLocksInput{Rules: []entity.FieldLockRule{{Name: "x", Status: entity.VerificationVerified, Fields: []string{"id"}}}}
+		_, err := newUsecase(newOrganizationRepo()).UpdateFieldLocks(reqctx.WithUserID(context.Background(), 1), 1, input)
+		assert.True(t, domainErrors.IsValidationError(err))
+	})
+}
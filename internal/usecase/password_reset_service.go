@@ -0,0 +1,187 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/pkg/clock"
+	"Aicon-assignment/internal/pkg/filter"
+	"Aicon-assignment/internal/pkg/idgen"
+	"Aicon-assignment/internal/pkg/reqctx"
+)
+
+// パスワードの再設定トークンの先頭に付ける文字列
+const PasswordResetTokenPrefix = "pr_"
+
+// 1 つのメールアドレスで再設定のメールを送るユーザーの上限
+const maxPasswordResetUsers = 10
+
+// パスワードを忘れたユーザーが、メールで受け取ったトークンでパスワードを設定し直す
+type PasswordResetUsecase interface {
+	// Request はメールアドレスのユーザーに再設定用のトークンをメールで送る
+	// 登録の有無を推測させないよう、該当するユーザーがいない場合やメールを送れなかった場合も成功する
+	Request(ctx context.Context, email string) error
+	// Reset はトークンを確かめて新しいパスワードを設定し、リフレッシュトークンをすべて失効させる
+	// 不明・期限切れ・使用済みのトークンの場合は ErrPasswordResetNotFound を返す
+	Reset(ctx context.Context, input ResetPasswordInput) error
+}
+
+type ResetPasswordInput struct {
+	Token       string `json:"token"`
+	NewPassword string `json:"new_password"`
+}
+
+type passwordResetUsecase struct {
+	tokens        PasswordResetRepository
+	users         UserRepository
+	refreshTokens RefreshTokenRepository
+	mailer        Mailer
+	transactor    Transactor
+	ttl           time.Duration
+	resetURL      string
+	clock         clock.Clock
+}
+
+// resetURL はメールに載せる再設定ページの URL（空の場合はトークンだけを載せる）
+func NewPasswordResetUsecase(
+	tokens PasswordResetRepository,
+	users UserRepository,
+	refreshTokens RefreshTokenRepository,
+	mailer Mailer,
+	transactor Transactor,
+	ttl time.Duration,
+	resetURL string,
+	clock clock.Clock,
+) PasswordResetUsecase {
+	return &passwordResetUsecase{
+		tokens:        tokens,
+		users:         users,
+		refreshTokens: refreshTokens,
+		mailer:        mailer,
+		transactor:    transactor,
+		ttl:           ttl,
+		resetURL:      resetURL,
+		clock:         clock,
+	}
+}
+
+func (u *passwordResetUsecase) Request(ctx context.Context, email string) error {
+	email = strings.TrimSpace(email)
+	if email == "" || !strings.Contains(email, "@") {
+		return fmt.Errorf("%w: email is required", domainErrors.ErrInvalidInput)
+	}
+
+	// メールアドレスは大文字小文字を区別しない（DB の照合順序と同じ）
+	users, err := u.users.FindByQuery(ctx, entity.UserQuery{
+		Filter: &filter.Comparison{Field: "email", Op: filter.OpEq, Value: email},
+		Limit:  maxPasswordResetUsers,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to retrieve users: %w", err)
+	}
+
+	for _, user := range users {
+		// パスワードでログインしない（SCIM・外部ログインだけの）ユーザーには送らない
+		if !user.Active || user.PasswordHash == "" {
+			continue
+		}
+		if err := u.send(ctx, user); err != nil {
+			reqctx.Logger(ctx).Error("failed to send password reset mail", "user_id", user.ID, "error", err)
+			continue
+		}
+		reqctx.Logger(ctx).Info("password reset requested", "user_id", user.ID)
+	}
+	return nil
+}
+
+func (u *passwordResetUsecase) send(ctx context.Context, user *entity.User) error {
+	token := entity.NewPasswordResetToken(user.ID, PasswordResetTokenPrefix+idgen.NewRandomID(), u.ttl, u.clock.Now())
+	if err := u.tokens.Create(ctx, token); err != nil {
+		return fmt.Errorf("failed to store password reset token: %w", err)
+	}
+	return u.mailer.Send(ctx, u.resetMail(user, token))
+}
+
+func (u *passwordResetUsecase) resetMail(user *entity.User, token *entity.PasswordResetToken) Mail {
+	var body strings.Builder
+	fmt.Fprintf(&body, "A password reset was requested for %s.\n\n", user.UserName)
+	if u.resetURL != "" {
+		fmt.Fprintf(&body, "Reset your password: %s?token=%s\n", u.resetURL, token.Token)
+	} else {
+		fmt.Fprintf(&body, "Password reset token: %s\n", token.Token)
+	}
+	fmt.Fprintf(&body, "\nThis link expires at %s and can be used once.\n", token.ExpiresAt.UTC().Format("2006-01-02 15:04 MST"))
+	body.WriteString("If you did not request this, you can ignore this email.\n")
+
+	return Mail{
+		To:      user.Email,
+		Subject: "Password reset",
+		Body:    body.String(),
+	}
+}
+
+func (u *passwordResetUsecase) Reset(ctx context.Context, input ResetPasswordInput) error {
+	if strings.TrimSpace(input.Token) == "" {
+		return fmt.Errorf("%w: token is required", domainErrors.ErrInvalidInput)
+	}
+	// パスワードが条件を満たさない場合は、トークンを使わずに入力し直せるようにする
+	passwordHash, err := hashPassword(input.NewPassword)
+	if err != nil {
+		return err
+	}
+
+	token, err := u.tokens.FindByTokenHash(ctx, entity.HashToken(input.Token))
+	if err != nil {
+		return err
+	}
+	now := u.clock.Now()
+	if !token.Valid(now) {
+		return domainErrors.ErrPasswordResetNotFound
+	}
+	user, err := u.users.FindByID(ctx, token.UserID)
+	if errors.Is(err, domainErrors.ErrUserNotFound) {
+		return domainErrors.ErrPasswordResetNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to retrieve user: %w", err)
+	}
+	if !user.Active {
+		return domainErrors.ErrPasswordResetNotFound
+	}
+
+	err = u.transactor.Transaction(ctx, func(ctx context.Context) error {
+		// 同じトークンで同時に再設定した場合も、先に記録した 1 つだけを受け付ける
+		used, err := u.tokens.MarkUsed(ctx, token.ID, now)
+		if err != nil {
+			return fmt.Errorf("failed to update password reset token: %w", err)
+		}
+		if !used {
+			return domainErrors.ErrPasswordResetNotFound
+		}
+		// 以前に送ったメールのトークンも使えなくする
+		if err := u.tokens.MarkUsedByUser(ctx, user.ID, now); err != nil {
+			return fmt.Errorf("failed to update password reset tokens: %w", err)
+		}
+
+		user.PasswordHash = passwordHash
+		user.UpdatedAt = now
+		if err := u.users.Update(ctx, user); err != nil {
+			return fmt.Errorf("failed to update user: %w", err)
+		}
+		if err := u.refreshTokens.RevokeByUser(ctx, user.ID, now); err != nil {
+			return fmt.Errorf("failed to revoke refresh tokens: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	reqctx.Logger(ctx).Info("password reset", "user_id", user.ID)
+	return nil
+}
@@ -0,0 +1,207 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/pkg/clock"
+)
+
+// MockPasswordResetRepository はテスト用のパスワード再設定トークンのリポジトリ
+type MockPasswordResetRepository struct {
+	mock.Mock
+}
+
+func (m *MockPasswordResetRepository) Create(ctx context.Context, token *entity.PasswordResetToken) error {
+	args := m.Called(ctx, token)
+	return args.Error(0)
+}
+
+func (m *MockPasswordResetRepository) FindByTokenHash(ctx context.Context, tokenHash string) (*entity.PasswordResetToken, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.PasswordResetToken), args.Error(1)
+}
+
+func (m *MockPasswordResetRepository) MarkUsed(ctx context.Context, id int64, at time.Time) (bool, error) {
+	args := m.Called(ctx, id, at)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockPasswordResetRepository) MarkUsedByUser(ctx context.Context, userID int64, at time.Time) error {
+	args := m.Called(ctx, userID, at)
+	return args.Error(0)
+}
+
+func TestPasswordResetUsecase_Request(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+
+	newUsecase := func(tokens *MockPasswordResetRepository, users *MockUserRepository, mailer *fakeMailer) PasswordResetUsecase {
+		return NewPasswordResetUsecase(tokens, users, new(MockRefreshTokenRepository), mailer, &fakeTransactor{}, time.Hour, "https://app.example.com/reset", clock.NewFrozen(now))
+	}
+
+	t.Run("正常系: トークンを保存し、URL を載せたメールを送る", func(t *testing.T) {
+		user := testUserWithPassword(t, "correct horse")
+		user.Email = "yamada@example.com"
+		users := new(MockUserRepository)
+		users.On("FindByQuery", mock.Anything, mock.Anything).Return([]*entity.User{user}, nil)
+		var stored *entity.PasswordResetToken
+		tokens := new(MockPasswordResetRepository)
+		tokens.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			stored = args.Get(1).(*entity.PasswordResetToken)
+		}).Return(nil)
+		mailer := &fakeMailer{}
+
+		err := newUsecase(tokens, users, mailer).Request(ctx, " yamada@example.com ")
+
+		require.NoError(t, err)
+		require.Len(t, mailer.sent, 1)
+		assert.Equal(t, "yamada@example.com", mailer.sent[0].To)
+		token := regexp.MustCompile(`\?token=(pr_[0-9a-f]+)`).FindStringSubmatch(mailer.sent[0].Body)
+		require.Len(t, token, 2)
+		assert.Equal(t, entity.HashToken(token[1]), stored.TokenHash)
+		assert.Equal(t, now.Add(time.Hour), stored.ExpiresAt)
+	})
+
+	t.Run("正常系: 該当するユーザーがいなくても成功し、メールは送らない", func(t *testing.T) {
+		users := new(MockUserRepository)
+		users.On("FindByQuery", mock.Anything, mock.Anything).Return([]*entity.User{}, nil)
+		mailer := &fakeMailer{}
+
+		err := newUsecase(new(MockPasswordResetRepository), users, mailer).Request(ctx, "nobody@example.com")
+
+		assert.NoError(t, err)
+		assert.Empty(t, mailer.sent)
+	})
+
+	t.Run("正常系: パスワードのないユーザーと無効化されたユーザーには送らない", func(t *testing.T) {
+		inactive := testUserWithPassword(t, "correct horse")
+		inactive.Active = false
+		users := new(MockUserRepository)
+		users.On("FindByQuery", mock.Anything, mock.Anything).Return([]*entity.User{
+			{ID: 8, UserName: "scim-user", Email: "yamada@example.com", Active: true},
+			inactive,
+		}, nil)
+		mailer := &fakeMailer{}
+
+		err := newUsecase(new(MockPasswordResetRepository), users, mailer).Request(ctx, "yamada@example.com")
+
+		assert.NoError(t, err)
+		assert.Empty(t, mailer.sent)
+	})
+
+	t.Run("正常系: メールを送れなくても成功を返す", func(t *testing.T) {
+		users := new(MockUserRepository)
+		users.On("FindByQuery", mock.Anything, mock.Anything).Return([]*entity.User{testUserWithPassword(t, "correct horse")}, nil)
+		tokens := new(MockPasswordResetRepository)
+		tokens.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+		err := newUsecase(tokens, users, &fakeMailer{err: errors.New("connection refused")}).Request(ctx, "yamada@example.com")
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("異常系: メールアドレスがない", func(t *testing.T) {
+		err := newUsecase(new(MockPasswordResetRepository), new(MockUserRepository), &fakeMailer{}).Request(ctx, "  ")
+
+		assert.ErrorIs(t, err, domainErrors.ErrInvalidInput)
+	})
+}
+
+func TestPasswordResetUsecase_Reset(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+	const rawToken = "pr_0123456789abcdef"
+
+	validToken := func() *entity.PasswordResetToken {
+		token := entity.NewPasswordResetToken(7, rawToken, time.Hour, now.Add(-10*time.Minute))
+		token.ID = 3
+		token.Token = ""
+		return token
+	}
+
+	t.Run("正常系: パスワードを設定し、トークンとリフレッシュトークンを失効させる", func(t *testing.T) {
+		user := testUserWithPassword(t, "correct horse")
+		users := new(MockUserRepository)
+		users.On("FindByID", mock.Anything, int64(7)).Return(user, nil)
+		users.On("Update", mock.Anything, mock.Anything).Return(nil)
+		tokens := new(MockPasswordResetRepository)
+		tokens.On("FindByTokenHash", mock.Anything, entity.HashToken(rawToken)).Return(validToken(), nil)
+		tokens.On("MarkUsed", mock.Anything, int64(3), now).Return(true, nil)
+		tokens.On("MarkUsedByUser", mock.Anything, int64(7), now).Return(nil)
+		refreshTokens := new(MockRefreshTokenRepository)
+		refreshTokens.On("RevokeByUser", mock.Anything, int64(7), now).Return(nil)
+		tx := &fakeTransactor{}
+
+		err := NewPasswordResetUsecase(tokens, users, refreshTokens, &fakeMailer{}, tx, time.Hour, "", clock.NewFrozen(now)).
+			Reset(ctx, ResetPasswordInput{Token: rawToken, NewPassword: "battery staple"})
+
+		require.NoError(t, err)
+		assert.True(t, tx.committed)
+		assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte("battery staple")))
+		tokens.AssertExpectations(t)
+		refreshTokens.AssertExpectations(t)
+	})
+
+	t.Run("異常系: 使用済みのトークン", func(t *testing.T) {
+		token := validToken()
+		usedAt := now.Add(-time.Minute)
+		token.UsedAt = &usedAt
+		tokens := new(MockPasswordResetRepository)
+		tokens.On("FindByTokenHash", mock.Anything, mock.Anything).Return(token, nil)
+
+		err := NewPasswordResetUsecase(tokens, new(MockUserRepository), new(MockRefreshTokenRepository), &fakeMailer{}, &fakeTransactor{}, time.Hour, "", clock.NewFrozen(now)).
+			Reset(ctx, ResetPasswordInput{Token: rawToken, NewPassword: "battery staple"})
+
+		assert.ErrorIs(t, err, domainErrors.ErrPasswordResetNotFound)
+	})
+
+	t.Run("異常系: 期限切れのトークン", func(t *testing.T) {
+		tokens := new(MockPasswordResetRepository)
+		tokens.On("FindByTokenHash", mock.Anything, mock.Anything).Return(validToken(), nil)
+
+		err := NewPasswordResetUsecase(tokens, new(MockUserRepository), new(MockRefreshTokenRepository), &fakeMailer{}, &fakeTransactor{}, time.Hour, "", clock.NewFrozen(now.Add(time.Hour))).
+			Reset(ctx, ResetPasswordInput{Token: rawToken, NewPassword: "battery staple"})
+
+		assert.ErrorIs(t, err, domainErrors.ErrPasswordResetNotFound)
+	})
+
+	t.Run("異常系: 同時に使われたトークン", func(t *testing.T) {
+		users := new(MockUserRepository)
+		users.On("FindByID", mock.Anything, int64(7)).Return(testUserWithPassword(t, "correct horse"), nil)
+		tokens := new(MockPasswordResetRepository)
+		tokens.On("FindByTokenHash", mock.Anything, mock.Anything).Return(validToken(), nil)
+		tokens.On("MarkUsed", mock.Anything, int64(3), now).Return(false, nil)
+		tx := &fakeTransactor{}
+
+		err := NewPasswordResetUsecase(tokens, users, new(MockRefreshTokenRepository), &fakeMailer{}, tx, time.Hour, "", clock.NewFrozen(now)).
+			Reset(ctx, ResetPasswordInput{Token: rawToken, NewPassword: "battery staple"})
+
+		assert.ErrorIs(t, err, domainErrors.ErrPasswordResetNotFound)
+		assert.True(t, tx.rolledBack)
+		users.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("異常系: 新しいパスワードが短い場合はトークンを使わない", func(t *testing.T) {
+		tokens := new(MockPasswordResetRepository)
+
+		err := NewPasswordResetUsecase(tokens, new(MockUserRepository), new(MockRefreshTokenRepository), &fakeMailer{}, &fakeTransactor{}, time.Hour, "", clock.NewFrozen(now)).
+			Reset(ctx, ResetPasswordInput{Token: rawToken, NewPassword: "short"})
+
+		assert.ErrorIs(t, err, domainErrors.ErrInvalidInput)
+		tokens.AssertNotCalled(t, "FindByTokenHash", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,148 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/pkg/clock"
+	"Aicon-assignment/internal/pkg/reqctx"
+)
+
+// ポートフォリオ全体の価値を日ごとに記録し、推移を返す
+type PortfolioUsecase interface {
+	// RecordSnapshot は現在のアイテムから今日のスナップショットを作り、同じ日のものを置き換える
+	RecordSnapshot(ctx context.Context) (*entity.PortfolioSnapshot, error)
+	// History は期間（"30d", "1y" など。空の場合は 1y）のスナップショットを日付の順に返す
+	History(ctx context.Context, rangeSpec string) (*PortfolioHistory, error)
+	// Between は from から to までの日付（UTC）のスナップショットを日付の順に返す
+	Between(ctx context.Context, from, to time.Time) ([]*entity.PortfolioSnapshot, error)
+}
+
+// 期間内のスナップショット。記録していない日は含まない
+type PortfolioHistory struct {
+	Range     string                      `json:"range"`
+	From      string                      `json:"from"`
+	To        string                      `json:"to"`
+	Snapshots []*entity.PortfolioSnapshot `json:"snapshots"`
+}
+
+type portfolioUsecase struct {
+	items     ItemRepository
+	records   ReferenceDataRepository
+	snapshots PortfolioSnapshotRepository
+	clock     clock.Clock
+}
+
+func NewPortfolioUsecase(items ItemRepository, records ReferenceDataRepository, snapshots PortfolioSnapshotRepository, clock clock.Clock) PortfolioUsecase {
+	return &portfolioUsecase{
+		items:     items,
+		records:   records,
+		snapshots: snapshots,
+		clock:     clock,
+	}
+}
+
+func (u *portfolioUsecase) RecordSnapshot(ctx context.Context) (*entity.PortfolioSnapshot, error) {
+	snapshot, err := currentPortfolio(ctx, u.items, u.records, u.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+	if err := u.snapshots.Save(ctx, snapshot); err != nil {
+		return nil, fmt.Errorf("failed to save portfolio snapshot: %w", err)
+	}
+
+	reqctx.Logger(ctx).Info("portfolio snapshot recorded", "date", snapshot.Date, "items", snapshot.ItemCount, "current_value", snapshot.CurrentValue)
+	return snapshot, nil
+}
+
+// 現在のアイテムから now の日付のスナップショットを作る（保存はしない）
+func currentPortfolio(ctx context.Context, items ItemRepository, records ReferenceDataRepository, now time.Time) (*entity.PortfolioSnapshot, error) {
+	// 持ち主に関係なく、すべてのアイテムを数える
+	ctx = reqctx.WithAllOwners(ctx)
+
+	all, err := retryTransient(ctx, func() ([]*entity.Item, error) {
+		return items.FindAll(ctx)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve items: %w", err)
+	}
+	valuePercents, err := valuePercents(ctx, records)
+	if err != nil {
+		return nil, err
+	}
+	return entity.NewPortfolioSnapshot(all, valuePercents, now), nil
+}
+
+// カテゴリーごとの評価額の割合（参照データの categories の value_percent）
+func valuePercents(ctx context.Context, records ReferenceDataRepository) (map[string]int64, error) {
+	categories, err := records.FindAll(ctx, entity.ReferenceCategories)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve categories: %w", err)
+	}
+	percents := map[string]int64{}
+	for _, record := range categories {
+		if percent, ok := record.Int("value_percent"); ok {
+			percents[record.Key] = percent
+		}
+	}
+	return percents, nil
+}
+
+// すべての持ち主のアイテムを集計した値のため、管理者だけが見られる
+func (u *portfolioUsecase) History(ctx context.Context, rangeSpec string) (*PortfolioHistory, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	now := u.clock.Now()
+	from, err := entity.PortfolioRangeStart(rangeSpec, now)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrInvalidInput, err.Error())
+	}
+	if rangeSpec == "" {
+		rangeSpec = entity.DefaultPortfolioRange
+	}
+
+	snapshots, err := retryTransient(ctx, func() ([]*entity.PortfolioSnapshot, error) {
+		return u.snapshots.FindSince(ctx, from)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve portfolio snapshots: %w", err)
+	}
+
+	return &PortfolioHistory{
+		Range:     rangeSpec,
+		From:      from,
+		To:        now.UTC().Format("2006-01-02"),
+		Snapshots: snapshots,
+	}, nil
+}
+
+func (u *portfolioUsecase) Between(ctx context.Context, from, to time.Time) ([]*entity.PortfolioSnapshot, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	start, end, err := entity.PortfolioDateRange(from, to)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrInvalidInput, err.Error())
+	}
+
+	recorded, err := retryTransient(ctx, func() ([]*entity.PortfolioSnapshot, error) {
+		return u.snapshots.FindSince(ctx, start)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve portfolio snapshots: %w", err)
+	}
+
+	snapshots := []*entity.PortfolioSnapshot{}
+	for _, snapshot := range recorded {
+		if snapshot.Date <= end {
+			snapshots = append(snapshots, snapshot)
+		}
+	}
+	return snapshots, nil
+}
@@ -0,0 +1,139 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/pkg/clock"
+	"Aicon-assignment/internal/pkg/reqctx"
+)
+
+// MockPortfolioSnapshotRepository はテスト用のスナップショットのリポジトリ
+type MockPortfolioSnapshotRepository struct {
+	mock.Mock
+}
+
+func (m *MockPortfolioSnapshotRepository) Save(ctx context.Context, snapshot *entity.PortfolioSnapshot) error {
+	args := m.Called(ctx, snapshot)
+	return args.Error(0)
+}
+
+func (m *MockPortfolioSnapshotRepository) FindSince(ctx context.Context, from string) ([]*entity.PortfolioSnapshot, error) {
+	args := m.Called(ctx, from)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.PortfolioSnapshot), args.Error(1)
+}
+
+func TestPortfolioUsecase_RecordSnapshot(t *testing.T) {
+	now := time.Date(2024, 6, 1, 15, 0, 0, 0, time.UTC)
+
+	t.Run("正常系: すべての持ち主のアイテムを、カテゴリーの評価額の割合で集計して保存する", func(t *testing.T) {
+		items := new(MockItemRepository)
+		items.On("FindAll", mock.MatchedBy(reqctx.AllOwners)).Return([]*entity.Item{
+			{ID: 1, Category: "時計", PurchasePrice: 1000000},
+			{ID: 2, Category: "バッグ", PurchasePrice: 200000},
+		}, nil)
+		records := new(MockReferenceDataRepository)
+		records.On("FindAll", mock.Anything, entity.ReferenceCategories).Return([]*entity.ReferenceRecord{
+			{Type: entity.ReferenceCategories, Key: "時計", Attributes: map[string]any{"value_percent": int64(120)}},
+			{Type: entity.ReferenceCategories, Key: "バッグ", Attributes: map[string]any{}},
+		}, nil)
+		snapshots := new(MockPortfolioSnapshotRepository)
+		snapshots.On("Save", mock.Anything, mock.Anything).Return(nil)
+
+		usecase := NewPortfolioUsecase(items, records, snapshots, clock.NewFrozen(now))
+		snapshot, err := usecase.RecordSnapshot(context.Background())
+		require.NoError(t, err)
+
+		assert.Equal(t, "2024-06-01", snapshot.Date)
+		assert.Equal(t, 2, snapshot.ItemCount)
+		assert.Equal(t, int64(1200000), snapshot.PurchaseValue)
+		assert.Equal(t, int64(1400000), snapshot.CurrentValue)
+		snapshots.AssertCalled(t, "Save", mock.Anything, snapshot)
+	})
+}
+
+func TestPortfolioUsecase_History(t *testing.T) {
+	now := time.Date(2024, 6, 1, 15, 0, 0, 0, time.UTC)
+	asAdmin := reqctx.WithUserRole(reqctx.WithUserID(context.Background(), 1), entity.UserRoleAdmin)
+
+	t.Run("正常系: 期間の最初の日以降のスナップショットを返す", func(t *testing.T) {
+		recorded := []*entity.PortfolioSnapshot{{Date: "2024-05-31"}, {Date: "2024-06-01"}}
+		snapshots := new(MockPortfolioSnapshotRepository)
+		snapshots.On("FindSince", mock.Anything, "2024-05-03").Return(recorded, nil)
+
+		usecase := NewPortfolioUsecase(new(MockItemRepository), new(MockReferenceDataRepository), snapshots, clock.NewFrozen(now))
+		history, err := usecase.History(asAdmin, "30d")
+		require.NoError(t, err)
+		assert.Equal(t, &PortfolioHistory{Range: "30d", From: "2024-05-03", To: "2024-06-01", Snapshots: recorded}, history)
+	})
+
+	t.Run("正常系: 期間を省略した場合は 1 年", func(t *testing.T) {
+		snapshots := new(MockPortfolioSnapshotRepository)
+		snapshots.On("FindSince", mock.Anything, "2023-06-02").Return([]*entity.PortfolioSnapshot{}, nil)
+
+		usecase := NewPortfolioUsecase(new(MockItemRepository), new(MockReferenceDataRepository), snapshots, clock.NewFrozen(now))
+		history, err := usecase.History(asAdmin, "")
+		require.NoError(t, err)
+		assert.Equal(t, "1y", history.Range)
+	})
+
+	t.Run("異常系: 期間の形式が不正", func(t *testing.T) {
+		usecase := NewPortfolioUsecase(new(MockItemRepository), new(MockReferenceDataRepository), new(MockPortfolioSnapshotRepository), clock.NewFrozen(now))
+		_, err := usecase.History(asAdmin, "1h")
+		assert.ErrorIs(t, err, domainErrors.ErrInvalidInput)
+	})
+
+	t.Run("異常系: メンバーは見られない", func(t *testing.T) {
+		asMember := reqctx.WithUserRole(reqctx.WithUserID(context.Background(), 2), entity.UserRoleMember)
+
+		usecase := NewPortfolioUsecase(new(MockItemRepository), new(MockReferenceDataRepository), new(MockPortfolioSnapshotRepository), clock.NewFrozen(now))
+		_, err := usecase.History(asMember, "1y")
+		assert.ErrorIs(t, err, domainErrors.ErrForbidden)
+	})
+
+	t.Run("異常系: 呼び出し元の分からないリクエストは見られない", func(t *testing.T) {
+		usecase := NewPortfolioUsecase(new(MockItemRepository), new(MockReferenceDataRepository), new(MockPortfolioSnapshotRepository), clock.NewFrozen(now))
+		_, err := usecase.History(context.Background(), "1y")
+		assert.ErrorIs(t, err, domainErrors.ErrUnauthenticated)
+	})
+}
+
+func TestPortfolioUsecase_Between(t *testing.T) {
+	now := time.Date(2024, 6, 1, 15, 0, 0, 0, time.UTC)
+	asAdmin := reqctx.WithUserRole(reqctx.WithUserID(context.Background(), 1), entity.UserRoleAdmin)
+
+	t.Run("正常系: 期間の最後の日より後のスナップショットを除く", func(t *testing.T) {
+		recorded := []*entity.PortfolioSnapshot{{Date: "2024-05-30"}, {Date: "2024-05-31"}, {Date: "2024-06-01"}}
+		snapshots := new(MockPortfolioSnapshotRepository)
+		snapshots.On("FindSince", mock.Anything, "2024-05-30").Return(recorded, nil)
+
+		usecase := NewPortfolioUsecase(new(MockItemRepository), new(MockReferenceDataRepository), snapshots, clock.NewFrozen(now))
+		got, err := usecase.Between(asAdmin, time.Date(2024, 5, 30, 0, 0, 0, 0, time.UTC), time.Date(2024, 5, 31, 23, 0, 0, 0, time.UTC))
+		require.NoError(t, err)
+		assert.Equal(t, recorded[:2], got)
+	})
+
+	t.Run("異常系: 終わりが始まりより前", func(t *testing.T) {
+		usecase := NewPortfolioUsecase(new(MockItemRepository), new(MockReferenceDataRepository), new(MockPortfolioSnapshotRepository), clock.NewFrozen(now))
+		_, err := usecase.Between(asAdmin, now, now.AddDate(0, 0, -1))
+		assert.ErrorIs(t, err, domainErrors.ErrInvalidInput)
+	})
+
+	t.Run("異常系: メンバーは見られない", func(t *testing.T) {
+		asMember := reqctx.WithUserRole(reqctx.WithUserID(context.Background(), 2), entity.UserRoleMember)
+
+		usecase := NewPortfolioUsecase(new(MockItemRepository), new(MockReferenceDataRepository), new(MockPortfolioSnapshotRepository), clock.NewFrozen(now))
+		_, err := usecase.Between(asMember, now.AddDate(0, 0, -7), now)
+		assert.ErrorIs(t, err, domainErrors.ErrForbidden)
+	})
+}
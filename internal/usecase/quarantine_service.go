@@ -0,0 +1,102 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/pkg/clock"
+	"Aicon-assignment/internal/pkg/idgen"
+	"Aicon-assignment/internal/pkg/reqctx"
+)
+
+// アップロードされたファイルを保存する前に検査する（画像・添付ファイルの Upload が使う）
+type UploadScanner interface {
+	// ScanUpload は content を検査し、読み込み位置を先頭に戻す
+	// ウイルスが見つかった場合はファイルを隔離して ErrInfectedFile を、検査できなかった場合は ErrScanUnavailable を返す
+	ScanUpload(ctx context.Context, upload entity.Upload, content io.ReadSeeker) error
+}
+
+type QuarantineUsecase interface {
+	UploadScanner
+
+	List(ctx context.Context) ([]*entity.QuarantinedFile, error)
+	// Delete は隔離したファイルと記録を削除する
+	Delete(ctx context.Context, id int64) error
+}
+
+type quarantineUsecase struct {
+	scanner    Scanner // nil の場合は検査しない
+	quarantine QuarantineRepository
+	blobs      BlobUsecase
+	clock      clock.Clock
+}
+
+func NewQuarantineUsecase(scanner Scanner, quarantine QuarantineRepository, blobs BlobUsecase, clock clock.Clock) QuarantineUsecase {
+	return &quarantineUsecase{
+		scanner:    scanner,
+		quarantine: quarantine,
+		blobs:      blobs,
+		clock:      clock,
+	}
+}
+
+// 検査できない場合は受け付けない（検査していないファイルを保存しない）
+func (u *quarantineUsecase) ScanUpload(ctx context.Context, upload entity.Upload, content io.ReadSeeker) error {
+	if u.scanner == nil {
+		return nil
+	}
+
+	result, err := u.scanner.Scan(ctx, content)
+	if err != nil {
+		reqctx.Logger(ctx).Error("virus scan failed", "kind", upload.Kind, "item_id", upload.ItemID, "error", err)
+		return fmt.Errorf("%w: %s", domainErrors.ErrScanUnavailable, err.Error())
+	}
+	if _, err := content.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to read upload: %w", err)
+	}
+	if result.Clean {
+		return nil
+	}
+
+	file := &entity.QuarantinedFile{
+		Upload:    upload,
+		Signature: result.Signature,
+		Key:       entity.QuarantineBlobKey(idgen.NewRandomID()),
+		Actor:     actorFromContext(ctx),
+		CreatedAt: u.clock.Now(),
+	}
+	blob, err := u.blobs.Put(ctx, file.Key, content, nil)
+	if err != nil {
+		return fmt.Errorf("failed to quarantine infected file: %w", err)
+	}
+	file.Size = blob.Size
+	if err := u.quarantine.Create(ctx, file); err != nil {
+		if deleteErr := u.blobs.Delete(ctx, file.Key); deleteErr != nil {
+			reqctx.Logger(ctx).Error("failed to delete unrecorded quarantined file", "key", file.Key, "error", deleteErr)
+		}
+		return fmt.Errorf("failed to record quarantined file: %w", err)
+	}
+
+	reqctx.Logger(ctx).Warn("infected upload quarantined",
+		"quarantine_id", file.ID, "kind", upload.Kind, "item_id", upload.ItemID, "filename", upload.Filename, "signature", result.Signature)
+	return fmt.Errorf("%w: %s", domainErrors.ErrInfectedFile, result.Signature)
+}
+
+func (u *quarantineUsecase) List(ctx context.Context) ([]*entity.QuarantinedFile, error) {
+	return u.quarantine.FindAll(ctx)
+}
+
+// ファイルを消してから記録を消すため、途中で失敗しても記録が残り、やり直せる
+func (u *quarantineUsecase) Delete(ctx context.Context, id int64) error {
+	file, err := u.quarantine.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := u.blobs.Delete(ctx, file.Key); err != nil {
+		return err
+	}
+	return u.quarantine.Delete(ctx, id)
+}
@@ -0,0 +1,130 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/pkg/clock"
+)
+
+type MockQuarantineRepository struct {
+	mock.Mock
+}
+
+func (m *MockQuarantineRepository) Create(ctx context.Context, file *entity.QuarantinedFile) error {
+	args := m.Called(ctx, file)
+	file.ID = 1
+	return args.Error(0)
+}
+
+func (m *MockQuarantineRepository) FindAll(ctx context.Context) ([]*entity.QuarantinedFile, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*entity.QuarantinedFile), args.Error(1)
+}
+
+func (m *MockQuarantineRepository) FindByID(ctx context.Context, id int64) (*entity.QuarantinedFile, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.QuarantinedFile), args.Error(1)
+}
+
+func (m *MockQuarantineRepository) Delete(ctx context.Context, id int64) error {
+	return m.Called(ctx, id).Error(0)
+}
+
+// 中身に EICAR を含むファイルをウイルスとみなす Scanner
+type fakeScanner struct {
+	err error
+}
+
+func (s fakeScanner) Scan(ctx context.Context, content io.Reader) (*entity.ScanResult, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return nil, err
+	}
+	if strings.Contains(string(data), "EICAR") {
+		return &entity.ScanResult{Signature: "Eicar-Signature"}, nil
+	}
+	return &entity.ScanResult{Clean: true}, nil
+}
+
+func TestQuarantineUsecase_ScanUpload(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	upload := entity.Upload{Kind: entity.UploadKindAttachment, ItemID: 1, Filename: "鑑定書.pdf", ContentType: "application/pdf"}
+
+	t.Run("正常系: 問題のないファイルは読み込み位置を先頭に戻して通す", func(t *testing.T) {
+		repo := new(MockQuarantineRepository)
+		content := strings.NewReader("certificate")
+
+		require.NoError(t, NewQuarantineUsecase(fakeScanner{}, repo, &fakeBlobs{}, clock.NewFrozen(now)).ScanUpload(ctx, upload, content))
+		data, _ := io.ReadAll(content)
+		assert.Equal(t, "certificate", string(data))
+		repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("正常系: ウイルスが見つかったファイルは隔離する", func(t *testing.T) {
+		repo := new(MockQuarantineRepository)
+		repo.On("Create", mock.Anything, mock.Anything).Return(nil)
+		blobs := &fakeBlobs{}
+
+		err := NewQuarantineUsecase(fakeScanner{}, repo, blobs, clock.NewFrozen(now)).ScanUpload(ctx, upload, strings.NewReader("EICAR"))
+		assert.ErrorIs(t, err, domainErrors.ErrInfectedFile)
+
+		file := repo.Calls[0].Arguments.Get(1).(*entity.QuarantinedFile)
+		assert.Equal(t, upload, file.Upload)
+		assert.Equal(t, "Eicar-Signature", file.Signature)
+		assert.Equal(t, int64(5), file.Size)
+		assert.Equal(t, "anonymous", file.Actor)
+		assert.True(t, strings.HasPrefix(file.Key, "quarantine/"))
+		assert.Equal(t, []byte("EICAR"), blobs.files[file.Key])
+	})
+
+	t.Run("異常系: 検査できない場合は受け付けない", func(t *testing.T) {
+		err := NewQuarantineUsecase(fakeScanner{err: errors.New("connection refused")}, new(MockQuarantineRepository), &fakeBlobs{}, clock.NewFrozen(now)).
+			ScanUpload(ctx, upload, strings.NewReader("certificate"))
+		assert.ErrorIs(t, err, domainErrors.ErrScanUnavailable)
+	})
+
+	t.Run("正常系: Scanner がなければ検査しない", func(t *testing.T) {
+		require.NoError(t, NewQuarantineUsecase(nil, new(MockQuarantineRepository), &fakeBlobs{}, clock.NewFrozen(now)).ScanUpload(ctx, upload, strings.NewReader("EICAR")))
+	})
+}
+
+func TestQuarantineUsecase_Delete(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("正常系: ファイルと記録を削除する", func(t *testing.T) {
+		repo := new(MockQuarantineRepository)
+		repo.On("FindByID", mock.Anything, int64(3)).Return(&entity.QuarantinedFile{ID: 3, Key: "quarantine/abc"}, nil)
+		repo.On("Delete", mock.Anything, int64(3)).Return(nil)
+		blobs := &deletingBlobs{}
+
+		require.NoError(t, NewQuarantineUsecase(nil, repo, blobs, clock.NewFrozen(time.Now())).Delete(ctx, 3))
+		assert.Equal(t, []string{"quarantine/abc"}, blobs.deleted)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("異常系: 存在しない", func(t *testing.T) {
+		repo := new(MockQuarantineRepository)
+		repo.On("FindByID", mock.Anything, int64(3)).Return(nil, domainErrors.ErrQuarantineNotFound)
+
+		err := NewQuarantineUsecase(nil, repo, &deletingBlobs{}, clock.NewFrozen(time.Now())).Delete(ctx, 3)
+		assert.ErrorIs(t, err, domainErrors.ErrQuarantineNotFound)
+	})
+}
@@ -0,0 +1,37 @@
+package usecase
+
+import (
+	"context"
+
+	"Aicon-assignment/internal/domain/entity"
+	"Aicon-assignment/internal/pkg/reqctx"
+)
+
+// リクエストの組織に適用する理由入力ポリシーを返す
+type ReasonPolicyProvider interface {
+	PolicyFor(ctx context.Context) entity.ReasonPolicy
+}
+
+// 設定値から組み立てる固定のポリシー
+// Orgs が空の場合はすべての組織に Policy を適用し、指定がある場合はその組織のみに適用する
+type StaticReasonPolicy struct {
+	Policy entity.ReasonPolicy
+	Orgs   []int64
+}
+
+func (p StaticReasonPolicy) PolicyFor(ctx context.Context) entity.ReasonPolicy {
+	if len(p.Orgs) == 0 {
+		return p.Policy
+	}
+
+	orgID, ok := reqctx.OrgID(ctx)
+	if !ok {
+		return entity.ReasonPolicy{}
+	}
+	for _, id := range p.Orgs {
+		if id == orgID {
+			return p.Policy
+		}
+	}
+	return entity.ReasonPolicy{}
+}
@@ -0,0 +1,327 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/pkg/clock"
+	"Aicon-assignment/internal/pkg/reqctx"
+)
+
+// 管理者が管理する参照データ（カテゴリー・ブランドなど）を、種類ごとの項目の定義に従って扱う
+// 種類ごとにコントローラーを書かずに済むよう、すべての種類を同じ操作で扱う
+type ReferenceUsecase interface {
+	CategoryCatalog
+
+	// Types は扱える参照データの種類と項目の定義を返す
+	Types() []entity.ReferenceType
+	// List は種類とその参照データをキーの順に返す
+	List(ctx context.Context, refType string) (*entity.ReferenceType, []*entity.ReferenceRecord, error)
+	Get(ctx context.Context, refType, key string) (*entity.ReferenceRecord, error)
+	Create(ctx context.Context, refType string, input ReferenceInput) (*entity.ReferenceRecord, error)
+	// Update は項目の値をすべて置き換える。キーは変更できない
+	Update(ctx context.Context, refType, key string, attributes map[string]any) (*entity.ReferenceRecord, error)
+	// Delete は他の参照データ（カテゴリーの場合はアイテムも）から参照されている場合は ErrReferenceInUse を返す
+	Delete(ctx context.Context, refType, key string) error
+	// LoadCategories は categories をアイテムに指定できるカテゴリーとして読み込む
+	LoadCategories(ctx context.Context) error
+}
+
+// アイテムのカテゴリーの参照データ（カテゴリーを付け替えるときに ItemUsecase が使う）
+type CategoryCatalog interface {
+	// ValidationProfile はカテゴリーのアイテムに適用する入力チェックを返す
+	// 設定していない場合は nil、カテゴリーがない場合は ErrReferenceNotFound を返す
+	ValidationProfile(ctx context.Context, category string) (*entity.ValidationProfile, error)
+	// DeleteCategory はカテゴリーを削除する。呼び出し元のトランザクションに参加する
+	DeleteCategory(ctx context.Context, category string) error
+}
+
+type ReferenceInput struct {
+	Key        string         `json:"key"`
+	Attributes map[string]any `json:"attributes"`
+}
+
+type referenceUsecase struct {
+	records    ReferenceDataRepository
+	items      ItemRepository
+	transactor Transactor
+	clock      clock.Clock
+}
+
+func NewReferenceUsecase(records ReferenceDataRepository, items ItemRepository, transactor Transactor, clock clock.Clock) ReferenceUsecase {
+	return &referenceUsecase{
+		records:    records,
+		items:      items,
+		transactor: transactor,
+		clock:      clock,
+	}
+}
+
+func (u *referenceUsecase) Types() []entity.ReferenceType {
+	return entity.ReferenceTypes
+}
+
+func (u *referenceUsecase) List(ctx context.Context, refType string) (*entity.ReferenceType, []*entity.ReferenceRecord, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, nil, err
+	}
+	definition, err := referenceType(refType)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	records, err := u.records.FindAll(ctx, refType)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to retrieve %s: %w", refType, err)
+	}
+	return definition, records, nil
+}
+
+func (u *referenceUsecase) Get(ctx context.Context, refType, key string) (*entity.ReferenceRecord, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if _, err := referenceType(refType); err != nil {
+		return nil, err
+	}
+	return u.records.FindByKey(ctx, refType, key)
+}
+
+func (u *referenceUsecase) Create(ctx context.Context, refType string, input ReferenceInput) (*entity.ReferenceRecord, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	definition, err := referenceType(refType)
+	if err != nil {
+		return nil, err
+	}
+	key, err := entity.NormalizeReferenceKey(input.Key)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrInvalidInput, err.Error())
+	}
+	attributes, err := definition.Normalize(input.Attributes)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrInvalidInput, err.Error())
+	}
+
+	now := u.clock.Now()
+	record := &entity.ReferenceRecord{Type: refType, Key: key, Attributes: attributes, CreatedAt: now, UpdatedAt: now}
+	err = u.transactor.Transaction(ctx, func(ctx context.Context) error {
+		if err := u.checkReferences(ctx, definition, record); err != nil {
+			return err
+		}
+		if err := u.records.Create(ctx, record); err != nil {
+			return fmt.Errorf("failed to create %s %q: %w", refType, key, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	reqctx.Logger(ctx).Info("reference data created", "type", refType, "key", key)
+	u.afterChange(ctx, refType)
+	return record, nil
+}
+
+func (u *referenceUsecase) Update(ctx context.Context, refType, key string, attributes map[string]any) (*entity.ReferenceRecord, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	definition, err := referenceType(refType)
+	if err != nil {
+		return nil, err
+	}
+	normalized, err := definition.Normalize(attributes)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrInvalidInput, err.Error())
+	}
+
+	var record *entity.ReferenceRecord
+	err = u.transactor.Transaction(ctx, func(ctx context.Context) error {
+		existing, err := u.records.FindByKey(ctx, refType, key)
+		if err != nil {
+			return err
+		}
+		existing.Attributes = normalized
+		existing.UpdatedAt = u.clock.Now()
+		if err := u.checkReferences(ctx, definition, existing); err != nil {
+			return err
+		}
+		if err := u.records.Update(ctx, existing); err != nil {
+			return fmt.Errorf("failed to update %s %q: %w", refType, key, err)
+		}
+		record = existing
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	reqctx.Logger(ctx).Info("reference data updated", "type", refType, "key", key)
+	u.afterChange(ctx, refType)
+	return record, nil
+}
+
+func (u *referenceUsecase) Delete(ctx context.Context, refType, key string) error {
+	if err := requireAdmin(ctx); err != nil {
+		return err
+	}
+	if _, err := referenceType(refType); err != nil {
+		return err
+	}
+
+	err := u.transactor.Transaction(ctx, func(ctx context.Context) error {
+		if _, err := u.records.FindByKey(ctx, refType, key); err != nil {
+			return err
+		}
+		if err := u.checkNotReferenced(ctx, refType, key); err != nil {
+			return err
+		}
+		if refType == entity.ReferenceCategories {
+			if err := u.checkNoItems(ctx, key); err != nil {
+				return err
+			}
+		}
+		return u.records.Delete(ctx, refType, key)
+	})
+	if err != nil {
+		return err
+	}
+
+	reqctx.Logger(ctx).Info("reference data deleted", "type", refType, "key", key)
+	u.afterChange(ctx, refType)
+	return nil
+}
+
+func (u *referenceUsecase) DeleteCategory(ctx context.Context, category string) error {
+	return u.Delete(ctx, entity.ReferenceCategories, category)
+}
+
+func (u *referenceUsecase) ValidationProfile(ctx context.Context, category string) (*entity.ValidationProfile, error) {
+	record, err := u.records.FindByKey(ctx, entity.ReferenceCategories, category)
+	if err != nil {
+		return nil, err
+	}
+	name := record.Reference("validation_profile")
+	if name == "" {
+		return nil, nil
+	}
+
+	profile, err := u.records.FindByKey(ctx, entity.ReferenceValidationProfiles, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up validation profile %q of category %q: %w", name, category, err)
+	}
+	var vocabulary *entity.ReferenceRecord
+	if terms := profile.Reference("brand_vocabulary"); terms != "" {
+		vocabulary, err = u.records.FindByKey(ctx, entity.ReferenceVocabularies, terms)
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up vocabulary %q of validation profile %q: %w", terms, name, err)
+		}
+	}
+	return entity.NewValidationProfile(profile, vocabulary), nil
+}
+
+// 表示順（指定のないものは最後）とキーの順に並べる
+func (u *referenceUsecase) LoadCategories(ctx context.Context) error {
+	records, err := u.records.FindAll(ctx, entity.ReferenceCategories)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve categories: %w", err)
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		return displayOrder(records[i]) < displayOrder(records[j])
+	})
+	categories := make([]string, len(records))
+	for i, record := range records {
+		categories[i] = record.Key
+	}
+	entity.SetValidCategories(categories)
+	return nil
+}
+
+func displayOrder(record *entity.ReferenceRecord) int64 {
+	if order, ok := record.Int("display_order"); ok {
+		return order
+	}
+	return math.MaxInt64
+}
+
+// カテゴリーの変更はすぐにアイテムの入力チェックに反映する
+func (u *referenceUsecase) afterChange(ctx context.Context, refType string) {
+	if refType != entity.ReferenceCategories {
+		return
+	}
+	if err := u.LoadCategories(ctx); err != nil {
+		reqctx.Logger(ctx).Warn("failed to reload categories", "error", err)
+	}
+}
+
+// 他の参照データを指す項目の値が、存在する参照データのキーかを確かめる
+func (u *referenceUsecase) checkReferences(ctx context.Context, definition *entity.ReferenceType, record *entity.ReferenceRecord) error {
+	var errs entity.ValidationErrors
+	for _, field := range definition.Fields {
+		target := record.Reference(field.Name)
+		if field.References == "" || target == "" {
+			continue
+		}
+		_, err := u.records.FindByKey(ctx, field.References, target)
+		if errors.Is(err, domainErrors.ErrReferenceNotFound) {
+			errs = append(errs, entity.FieldError{Field: "attributes." + field.Name, Message: fmt.Sprintf("%s %q does not exist in %s", field.Name, target, field.References)})
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to look up %s %q: %w", field.References, target, err)
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: %s", domainErrors.ErrInvalidInput, errs.Error())
+	}
+	return nil
+}
+
+// refType の key を指している参照データがないかを確かめる
+func (u *referenceUsecase) checkNotReferenced(ctx context.Context, refType, key string) error {
+	for _, definition := range entity.ReferenceTypes {
+		for _, field := range definition.Fields {
+			if field.References != refType {
+				continue
+			}
+			records, err := u.records.FindAll(ctx, definition.Name)
+			if err != nil {
+				return fmt.Errorf("failed to retrieve %s: %w", definition.Name, err)
+			}
+			for _, record := range records {
+				if record.Reference(field.Name) == key {
+					return fmt.Errorf("%w: %s %q is used by %s %q (%s)", domainErrors.ErrReferenceInUse, refType, key, definition.Name, record.Key, field.Name)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// カテゴリーを削除するとアイテムが入力チェックを通らなくなるため、先に別のカテゴリーへ付け替えてもらう
+// 組織をまたいで数える
+func (u *referenceUsecase) checkNoItems(ctx context.Context, category string) error {
+	count, err := u.items.CountByQuery(reqctx.WithAllOwners(ctx), itemsInCategory(category))
+	if err != nil {
+		return fmt.Errorf("failed to count items of category %q: %w", category, err)
+	}
+	if count > 0 {
+		return fmt.Errorf("%w: category %q is used by %d items; reassign them with POST /admin/reference/categories/{key}/reassign first", domainErrors.ErrReferenceInUse, category, count)
+	}
+	return nil
+}
+
+func referenceType(name string) (*entity.ReferenceType, error) {
+	definition, ok := entity.FindReferenceType(name)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrReferenceTypeNotFound, name)
+	}
+	return definition, nil
+}
@@ -0,0 +1,251 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/pkg/clock"
+	"Aicon-assignment/internal/pkg/reqctx"
+)
+
+// MockReferenceDataRepository はテスト用の参照データリポジトリ
+type MockReferenceDataRepository struct {
+	mock.Mock
+}
+
+func (m *MockReferenceDataRepository) FindAll(ctx context.Context, refType string) ([]*entity.ReferenceRecord, error) {
+	args := m.Called(ctx, refType)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.ReferenceRecord), args.Error(1)
+}
+
+func (m *MockReferenceDataRepository) FindByKey(ctx context.Context, refType, key string) (*entity.ReferenceRecord, error) {
+	args := m.Called(ctx, refType, key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.ReferenceRecord), args.Error(1)
+}
+
+func (m *MockReferenceDataRepository) Create(ctx context.Context, record *entity.ReferenceRecord) error {
+	args := m.Called(ctx, record)
+	return args.Error(0)
+}
+
+func (m *MockReferenceDataRepository) Update(ctx context.Context, record *entity.ReferenceRecord) error {
+	args := m.Called(ctx, record)
+	return args.Error(0)
+}
+
+func (m *MockReferenceDataRepository) Delete(ctx context.Context, refType, key string) error {
+	args := m.Called(ctx, refType, key)
+	return args.Error(0)
+}
+
+func category(key string, order int64) *entity.ReferenceRecord {
+	return &entity.ReferenceRecord{Type: entity.ReferenceCategories, Key: key, Attributes: map[string]any{"display_order": order}}
+}
+
+func TestReferenceUsecase_Create(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	asAdmin := reqctx.WithUserRole(reqctx.WithUserID(context.Background(), 1), entity.UserRoleAdmin)
+
+	t.Run("正常系: カテゴリーを追加し、アイテムに指定できるようにする", func(t *testing.T) {
+		t.Cleanup(func() { entity.SetValidCategories(nil) })
+		records := new(MockReferenceDataRepository)
+		records.On("Create", mock.Anything, mock.MatchedBy(func(r *entity.ReferenceRecord) bool {
+			return r.Key == "アート" && r.Attributes["display_order"] == int64(6) && r.CreatedAt.Equal(now)
+		})).Return(nil)
+		records.On("FindAll", mock.Anything, entity.ReferenceCategories).Return([]*entity.ReferenceRecord{category("アート", 6), category("時計", 1)}, nil)
+		tx := &fakeTransactor{}
+
+		record, err := NewReferenceUsecase(records, new(MockItemRepository), tx, clock.NewFrozen(now)).Create(asAdmin, entity.ReferenceCategories, ReferenceInput{
+			Key:        " アート ",
+			Attributes: map[string]any{"display_order": float64(6)},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "アート", record.Key)
+		assert.True(t, tx.committed)
+		assert.Equal(t, []string{"時計", "アート"}, entity.GetValidCategories())
+	})
+
+	t.Run("異常系: 存在しない参照データを指している", func(t *testing.T) {
+		records := new(MockReferenceDataRepository)
+		records.On("FindByKey", mock.Anything, entity.ReferenceCategories, "時計").Return(nil, domainErrors.ErrReferenceNotFound)
+		tx := &fakeTransactor{}
+
+		_, err := NewReferenceUsecase(records, new(MockItemRepository), tx, clock.NewFrozen(now)).Create(asAdmin, entity.ReferenceClassificationRules, ReferenceInput{
+			Key:        "rolex",
+			Attributes: map[string]any{"field": "brand", "contains": "ROLEX", "category": "時計"},
+		})
+		assert.ErrorIs(t, err, domainErrors.ErrInvalidInput)
+		assert.Contains(t, err.Error(), `category "時計" does not exist in categories`)
+		assert.True(t, tx.rolledBack)
+		records.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("異常系: 定義にない項目", func(t *testing.T) {
+		_, err := NewReferenceUsecase(new(MockReferenceDataRepository), new(MockItemRepository), &fakeTransactor{}, clock.NewFrozen(now)).Create(asAdmin, entity.ReferenceBrands, ReferenceInput{
+			Key:        "ROLEX",
+			Attributes: map[string]any{"country": "CH"},
+		})
+		assert.ErrorIs(t, err, domainErrors.ErrInvalidInput)
+	})
+
+	t.Run("異常系: 存在しない種類", func(t *testing.T) {
+		_, err := NewReferenceUsecase(new(MockReferenceDataRepository), new(MockItemRepository), &fakeTransactor{}, clock.NewFrozen(now)).Create(asAdmin, "colors", ReferenceInput{Key: "red"})
+		assert.ErrorIs(t, err, domainErrors.ErrReferenceTypeNotFound)
+	})
+
+	t.Run("異常系: 管理者でないユーザー", func(t *testing.T) {
+		asMember := reqctx.WithUserRole(reqctx.WithUserID(context.Background(), 2), entity.UserRoleMember)
+
+		_, err := NewReferenceUsecase(new(MockReferenceDataRepository), new(MockItemRepository), &fakeTransactor{}, clock.NewFrozen(now)).Create(asMember, entity.ReferenceBrands, ReferenceInput{Key: "ROLEX"})
+		assert.ErrorIs(t, err, domainErrors.ErrForbidden)
+	})
+}
+
+func TestReferenceUsecase_Update(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	asAdmin := reqctx.WithUserRole(reqctx.WithUserID(context.Background(), 1), entity.UserRoleAdmin)
+
+	t.Run("正常系: 項目の値をすべて置き換える", func(t *testing.T) {
+		records := new(MockReferenceDataRepository)
+		records.On("FindByKey", mock.Anything, entity.ReferenceBrands, "ROLEX").Return(&entity.ReferenceRecord{
+			Type:       entity.ReferenceBrands,
+			Key:        "ROLEX",
+			Attributes: map[string]any{"display_name": "ロレックス", "aliases": []string{"rolex"}},
+		}, nil)
+		records.On("Update", mock.Anything, mock.MatchedBy(func(r *entity.ReferenceRecord) bool {
+			_, hasAliases := r.Attributes["aliases"]
+			return r.Attributes["display_name"] == "Rolex" && !hasAliases && r.UpdatedAt.Equal(now)
+		})).Return(nil)
+
+		record, err := NewReferenceUsecase(records, new(MockItemRepository), &fakeTransactor{}, clock.NewFrozen(now)).Update(asAdmin, entity.ReferenceBrands, "ROLEX", map[string]any{"display_name": "Rolex"})
+		require.NoError(t, err)
+		assert.Equal(t, "ROLEX", record.Key)
+		records.AssertExpectations(t)
+	})
+
+	t.Run("異常系: 存在しない参照データ", func(t *testing.T) {
+		records := new(MockReferenceDataRepository)
+		records.On("FindByKey", mock.Anything, entity.ReferenceBrands, "ROLEX").Return(nil, domainErrors.ErrReferenceNotFound)
+
+		_, err := NewReferenceUsecase(records, new(MockItemRepository), &fakeTransactor{}, clock.NewFrozen(now)).Update(asAdmin, entity.ReferenceBrands, "ROLEX", nil)
+		assert.ErrorIs(t, err, domainErrors.ErrReferenceNotFound)
+	})
+}
+
+func TestReferenceUsecase_Delete(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	asAdmin := reqctx.WithUserRole(reqctx.WithUserID(context.Background(), 1), entity.UserRoleAdmin)
+
+	t.Run("正常系: 参照されていない用語集を削除する", func(t *testing.T) {
+		records := new(MockReferenceDataRepository)
+		records.On("FindByKey", mock.Anything, entity.ReferenceVocabularies, "brands").Return(&entity.ReferenceRecord{Type: entity.ReferenceVocabularies, Key: "brands"}, nil)
+		records.On("FindAll", mock.Anything, entity.ReferenceValidationProfiles).Return([]*entity.ReferenceRecord{
+			{Type: entity.ReferenceValidationProfiles, Key: "watch", Attributes: map[string]any{"brand_vocabulary": "watch-brands"}},
+		}, nil)
+		records.On("Delete", mock.Anything, entity.ReferenceVocabularies, "brands").Return(nil)
+
+		err := NewReferenceUsecase(records, new(MockItemRepository), &fakeTransactor{}, clock.NewFrozen(now)).Delete(asAdmin, entity.ReferenceVocabularies, "brands")
+		require.NoError(t, err)
+		records.AssertExpectations(t)
+	})
+
+	t.Run("異常系: 他の参照データから参照されている", func(t *testing.T) {
+		records := new(MockReferenceDataRepository)
+		records.On("FindByKey", mock.Anything, entity.ReferenceCategories, "時計").Return(category("時計", 1), nil)
+		records.On("FindAll", mock.Anything, entity.ReferenceClassificationRules).Return([]*entity.ReferenceRecord{
+			{Type: entity.ReferenceClassificationRules, Key: "rolex", Attributes: map[string]any{"category": "時計"}},
+		}, nil)
+		tx := &fakeTransactor{}
+
+		err := NewReferenceUsecase(records, new(MockItemRepository), tx, clock.NewFrozen(now)).Delete(asAdmin, entity.ReferenceCategories, "時計")
+		assert.ErrorIs(t, err, domainErrors.ErrReferenceInUse)
+		assert.True(t, tx.rolledBack)
+		records.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("異常系: アイテムが使っているカテゴリー", func(t *testing.T) {
+		records := new(MockReferenceDataRepository)
+		records.On("FindByKey", mock.Anything, entity.ReferenceCategories, "時計").Return(category("時計", 1), nil)
+		records.On("FindAll", mock.Anything, entity.ReferenceClassificationRules).Return([]*entity.ReferenceRecord{}, nil)
+		items := new(MockItemRepository)
+		items.On("CountByQuery", mock.Anything, itemsInCategory("時計")).Return(3, nil)
+		tx := &fakeTransactor{}
+
+		err := NewReferenceUsecase(records, items, tx, clock.NewFrozen(now)).Delete(asAdmin, entity.ReferenceCategories, "時計")
+		assert.ErrorIs(t, err, domainErrors.ErrReferenceInUse)
+		assert.Contains(t, err.Error(), `category "時計" is used by 3 items`)
+		assert.True(t, tx.rolledBack)
+		records.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestReferenceUsecase_ValidationProfile(t *testing.T) {
+	t.Run("正常系: プロファイルと用語集から入力チェックを組み立てる", func(t *testing.T) {
+		records := new(MockReferenceDataRepository)
+		records.On("FindByKey", mock.Anything, entity.ReferenceCategories, "時計").Return(&entity.ReferenceRecord{
+			Type: entity.ReferenceCategories, Key: "時計", Attributes: map[string]any{"validation_profile": "watch"},
+		}, nil)
+		records.On("FindByKey", mock.Anything, entity.ReferenceValidationProfiles, "watch").Return(&entity.ReferenceRecord{
+			Type: entity.ReferenceValidationProfiles, Key: "watch", Attributes: map[string]any{"min_price": int64(10000), "brand_vocabulary": "watch-brands"},
+		}, nil)
+		records.On("FindByKey", mock.Anything, entity.ReferenceVocabularies, "watch-brands").Return(&entity.ReferenceRecord{
+			Type: entity.ReferenceVocabularies, Key: "watch-brands", Attributes: map[string]any{"terms": []string{"ROLEX", "OMEGA"}},
+		}, nil)
+
+		profile, err := NewReferenceUsecase(records, new(MockItemRepository), &fakeTransactor{}, clock.NewFrozen(time.Now())).ValidationProfile(context.Background(), "時計")
+		require.NoError(t, err)
+		assert.Equal(t, "watch", profile.Name)
+		assert.Equal(t, int64(10000), *profile.MinPrice)
+		assert.Nil(t, profile.MaxPrice)
+		assert.Equal(t, []string{"ROLEX", "OMEGA"}, profile.Brands)
+	})
+
+	t.Run("正常系: プロファイルを設定していないカテゴリー", func(t *testing.T) {
+		records := new(MockReferenceDataRepository)
+		records.On("FindByKey", mock.Anything, entity.ReferenceCategories, "バッグ").Return(category("バッグ", 2), nil)
+
+		profile, err := NewReferenceUsecase(records, new(MockItemRepository), &fakeTransactor{}, clock.NewFrozen(time.Now())).ValidationProfile(context.Background(), "バッグ")
+		require.NoError(t, err)
+		assert.Nil(t, profile)
+	})
+}
+
+func TestReferenceUsecase_LoadCategories(t *testing.T) {
+	t.Run("正常系: 表示順（指定のないものは最後）の順に読み込む", func(t *testing.T) {
+		t.Cleanup(func() { entity.SetValidCategories(nil) })
+		records := new(MockReferenceDataRepository)
+		records.On("FindAll", mock.Anything, entity.ReferenceCategories).Return([]*entity.ReferenceRecord{
+			{Type: entity.ReferenceCategories, Key: "アート", Attributes: map[string]any{}},
+			category("バッグ", 2),
+			category("時計", 1),
+		}, nil)
+
+		err := NewReferenceUsecase(records, new(MockItemRepository), &fakeTransactor{}, clock.NewFrozen(time.Now())).LoadCategories(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, []string{"時計", "バッグ", "アート"}, entity.GetValidCategories())
+	})
+
+	t.Run("正常系: カテゴリーがない場合は既定のカテゴリーを使う", func(t *testing.T) {
+		t.Cleanup(func() { entity.SetValidCategories(nil) })
+		entity.SetValidCategories([]string{"アート"})
+		records := new(MockReferenceDataRepository)
+		records.On("FindAll", mock.Anything, entity.ReferenceCategories).Return([]*entity.ReferenceRecord{}, nil)
+
+		err := NewReferenceUsecase(records, new(MockItemRepository), &fakeTransactor{}, clock.NewFrozen(time.Now())).LoadCategories(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, entity.DefaultCategories, entity.GetValidCategories())
+	})
+}
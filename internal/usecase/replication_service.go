@@ -0,0 +1,242 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"Aicon-assignment/internal/domain/entity"
+	"Aicon-assignment/internal/domain/eventschema"
+	"Aicon-assignment/internal/pkg/clock"
+	"Aicon-assignment/internal/pkg/reqctx"
+)
+
+// 変更ストリームで 1 回に返すイベント数の上限
+const MaxChangeBatch = 1000
+
+// 変更ストリームの取得先。プライマリではイベントストア、スタンバイではプライマリの API
+type ChangeSource interface {
+	// Changes は afterSequence より後のイベントを古い順に最大 limit 件返す
+	Changes(ctx context.Context, afterSequence int64, limit int) (*entity.ChangeBatch, error)
+}
+
+// イベントストアを変更ストリームとして公開する
+type eventStoreChanges struct {
+	store EventStore
+}
+
+func NewChangeStream(store EventStore) ChangeSource {
+	return &eventStoreChanges{store: store}
+}
+
+func (s *eventStoreChanges) Changes(ctx context.Context, afterSequence int64, limit int) (*entity.ChangeBatch, error) {
+	if limit <= 0 || limit > MaxChangeBatch {
+		limit = MaxChangeBatch
+	}
+	events, err := s.store.LoadAfter(ctx, afterSequence, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load events after %d: %w", afterSequence, err)
+	}
+	// 読んだイベントより前にならないよう、最後の連番は後から取る
+	last, err := s.store.LastSequence(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read last sequence: %w", err)
+	}
+	if events == nil {
+		events = []*entity.StoredEvent{}
+	}
+	return &entity.ChangeBatch{Events: events, LastSequence: last}, nil
+}
+
+type ReplicaUsecase interface {
+	// Sync は送信元の変更を追いつくまで取得してアイテムに適用し、適用したイベント数を返す
+	// バッチごとに適用と適用済みの連番の記録を 1 つのトランザクションで行うため、途中で失敗しても次の Sync で続きから適用する
+	Sync(ctx context.Context) (int, error)
+	// Status は最後の Sync の結果から複製の状況を返す
+	Status() entity.ReplicationStatus
+}
+
+type replicaUsecase struct {
+	source      ChangeSource
+	sourceName  string // 適用済みの連番を記録するときの送信元の名前（URL など）
+	items       ItemReplicaWriter
+	checkpoints ReplicationCheckpointRepository
+	transactor  Transactor
+	batchSize   int
+	clock       clock.Clock
+	startedAt   time.Time
+
+	syncMu sync.Mutex // Sync を同時に走らせない
+	mu     sync.Mutex
+	status entity.ReplicationStatus
+}
+
+func NewReplicaUsecase(source ChangeSource, sourceName string, items ItemReplicaWriter, checkpoints ReplicationCheckpointRepository, transactor Transactor, batchSize int, clock clock.Clock) ReplicaUsecase {
+	if batchSize <= 0 || batchSize > MaxChangeBatch {
+		batchSize = MaxChangeBatch
+	}
+	return &replicaUsecase{
+		source:      source,
+		sourceName:  sourceName,
+		items:       items,
+		checkpoints: checkpoints,
+		transactor:  transactor,
+		batchSize:   batchSize,
+		clock:       clock,
+		startedAt:   clock.Now(),
+		status:      entity.ReplicationStatus{Source: sourceName},
+	}
+}
+
+func (u *replicaUsecase) Sync(ctx context.Context) (int, error) {
+	u.syncMu.Lock()
+	defer u.syncMu.Unlock()
+
+	applied, err := u.sync(ctx)
+	now := u.clock.Now()
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.status.LastPolledAt = &now
+	if err != nil {
+		u.status.LastError = err.Error()
+		return applied, err
+	}
+	u.status.LastError = ""
+	if u.status.AppliedSequence >= u.status.SourceSequence {
+		u.status.CaughtUpAt = &now
+	}
+	if applied > 0 {
+		reqctx.Logger(ctx).Info("replicated changes", "source", u.sourceName, "applied", applied, "sequence", u.status.AppliedSequence)
+	}
+	return applied, nil
+}
+
+func (u *replicaUsecase) sync(ctx context.Context) (int, error) {
+	after, err := u.checkpoints.Load(ctx, u.sourceName)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load replication checkpoint: %w", err)
+	}
+	u.setProgress(after, -1)
+
+	var applied int
+	for {
+		batch, err := u.source.Changes(ctx, after, u.batchSize)
+		if err != nil {
+			return applied, fmt.Errorf("failed to fetch changes after %d: %w", after, err)
+		}
+		u.setProgress(after, batch.LastSequence)
+		if len(batch.Events) == 0 {
+			return applied, nil
+		}
+
+		next, err := u.applyBatch(ctx, after, batch.Events)
+		if err != nil {
+			return applied, err
+		}
+		applied += len(batch.Events)
+		after = next
+		u.setProgress(after, -1)
+
+		if after >= batch.LastSequence {
+			return applied, nil
+		}
+	}
+}
+
+// イベントを適用し、最後に適用した連番を記録する
+func (u *replicaUsecase) applyBatch(ctx context.Context, after int64, events []*entity.StoredEvent) (int64, error) {
+	last := after
+	err := u.transactor.Transaction(ctx, func(ctx context.Context) error {
+		for _, stored := range events {
+			if stored.Sequence <= last {
+				return fmt.Errorf("source returned event %d after %d", stored.Sequence, last)
+			}
+			if err := u.apply(ctx, stored); err != nil {
+				return fmt.Errorf("failed to apply event %d (%s): %w", stored.Sequence, stored.EventID, err)
+			}
+			last = stored.Sequence
+		}
+		return u.checkpoints.Save(ctx, u.sourceName, last, u.clock.Now())
+	})
+	if err != nil {
+		return after, err
+	}
+	return last, nil
+}
+
+func (u *replicaUsecase) apply(ctx context.Context, stored *entity.StoredEvent) error {
+	event, err := eventschema.Default.Decode(stored.Payload, stored.SchemaVersion)
+	if err != nil {
+		return err
+	}
+	switch event.Type {
+	case entity.EventItemCreated, entity.EventItemUpdated:
+		if event.Item == nil {
+			return fmt.Errorf("event has no item")
+		}
+		item := *event.Item
+		item.ID = event.ItemID
+		return u.items.Put(ctx, &item)
+	case entity.EventItemDeleted:
+		return u.items.Remove(ctx, event.ItemID)
+	}
+	return nil
+}
+
+// sourceSequence が負の場合は送信元の連番を変えない
+func (u *replicaUsecase) setProgress(applied, sourceSequence int64) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.status.AppliedSequence = applied
+	if sourceSequence >= 0 {
+		u.status.SourceSequence = sourceSequence
+	}
+}
+
+// 送信元に接続できない・取得が止まっている間もラグが増えていくよう、呼び出し時点の時刻で計算する
+// 起動してからまだ一度も追いついていない場合は、起動からの経過時間をラグとする
+func (u *replicaUsecase) Status() entity.ReplicationStatus {
+	u.mu.Lock()
+	status := u.status
+	u.mu.Unlock()
+
+	status.LagEvents = max(status.SourceSequence-status.AppliedSequence, 0)
+	since := u.startedAt
+	if status.CaughtUpAt != nil {
+		since = *status.CaughtUpAt
+	}
+	status.LagSeconds = u.clock.Now().Sub(since).Seconds()
+	return status
+}
+
+// スタンバイの適用済みの連番までの送信元のイベントを再生し、アイテムの期待される状態を組み立てる
+// スタンバイの遅れに関係なく、適用済みの範囲が送信元と一致しているかを確かめるために使う
+func ReplayChangesUntil(ctx context.Context, source ChangeSource, until int64) (*ItemProjection, error) {
+	projection := NewItemProjection()
+	var after int64
+	for after < until {
+		batch, err := source.Changes(ctx, after, MaxChangeBatch)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch changes after %d: %w", after, err)
+		}
+		if len(batch.Events) == 0 {
+			return nil, fmt.Errorf("source has no events after %d but the standby has applied up to %d", after, until)
+		}
+		for _, stored := range batch.Events {
+			if stored.Sequence > until {
+				return projection, nil
+			}
+			event, err := eventschema.Default.Decode(stored.Payload, stored.SchemaVersion)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode event %d (%s): %w", stored.Sequence, stored.EventID, err)
+			}
+			if err := projection.Apply(ctx, event); err != nil {
+				return nil, err
+			}
+			after = stored.Sequence
+		}
+	}
+	return projection, nil
+}
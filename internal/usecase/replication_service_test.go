@@ -0,0 +1,241 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"Aicon-assignment/internal/domain/entity"
+	"Aicon-assignment/internal/pkg/clock"
+)
+
+// 記録済みのイベントを返す変更ストリーム
+type fakeChangeSource struct {
+	events []*entity.StoredEvent
+	err    error
+}
+
+func (f *fakeChangeSource) Changes(ctx context.Context, afterSequence int64, limit int) (*entity.ChangeBatch, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	batch := &entity.ChangeBatch{Events: []*entity.StoredEvent{}}
+	for _, event := range f.events {
+		if event.Sequence > afterSequence && len(batch.Events) < limit {
+			batch.Events = append(batch.Events, event)
+		}
+		batch.LastSequence = event.Sequence
+	}
+	return batch, nil
+}
+
+// EventRecorder で記録し、連番を振る
+func (f *fakeChangeSource) publish(t *testing.T, events ...*entity.Event) {
+	t.Helper()
+	store := &recordingEventStore{source: f}
+	recorder := NewEventRecorder(store)
+	for _, event := range events {
+		recorder.Publish(context.Background(), event)
+	}
+}
+
+type recordingEventStore struct {
+	EventStore
+	source *fakeChangeSource
+}
+
+func (s *recordingEventStore) Append(ctx context.Context, event *entity.StoredEvent) error {
+	event.Sequence = int64(len(s.source.events) + 1)
+	s.source.events = append(s.source.events, event)
+	return nil
+}
+
+// スタンバイのアイテム
+type fakeReplicaItems struct {
+	items  map[int64]*entity.Item
+	failOn int64 // この ID の書き込みを失敗させる
+}
+
+func (f *fakeReplicaItems) Put(ctx context.Context, item *entity.Item) error {
+	if item.ID == f.failOn {
+		return errors.New("disk full")
+	}
+	f.items[item.ID] = item
+	return nil
+}
+
+func (f *fakeReplicaItems) Remove(ctx context.Context, id int64) error {
+	delete(f.items, id)
+	return nil
+}
+
+type fakeCheckpoints struct {
+	sequences map[string]int64
+	saves     int
+}
+
+func (f *fakeCheckpoints) Load(ctx context.Context, source string) (int64, error) {
+	return f.sequences[source], nil
+}
+
+func (f *fakeCheckpoints) Save(ctx context.Context, source string, sequence int64, at time.Time) error {
+	f.sequences[source] = sequence
+	f.saves++
+	return nil
+}
+
+// 失敗したトランザクションの書き込みを取り消す
+type rollbackTransactor struct {
+	items       *fakeReplicaItems
+	checkpoints *fakeCheckpoints
+}
+
+func (r *rollbackTransactor) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	items := make(map[int64]*entity.Item, len(r.items.items))
+	for id, item := range r.items.items {
+		items[id] = item
+	}
+	sequences := make(map[string]int64, len(r.checkpoints.sequences))
+	for source, sequence := range r.checkpoints.sequences {
+		sequences[source] = sequence
+	}
+	if err := fn(ctx); err != nil {
+		r.items.items = items
+		r.checkpoints.sequences = sequences
+		return err
+	}
+	return nil
+}
+
+func TestReplicaUsecase_Sync(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	const sourceName = "https://tokyo.example.com"
+
+	watch, _ := entity.NewItemAt(now, "時計1", "時計", "ROLEX", 1000000, "2023-01-01")
+	watch.ID = 1
+	bag, _ := entity.NewItemAt(now, "バッグ1", "バッグ", "HERMES", 500000, "2023-01-02")
+	bag.ID = 2
+	repriced := *watch
+	repriced.PurchasePrice = 1200000
+
+	setup := func(t *testing.T, batchSize int) (*fakeChangeSource, *fakeReplicaItems, *fakeCheckpoints, *clock.Frozen, ReplicaUsecase) {
+		source := &fakeChangeSource{}
+		source.publish(t,
+			&entity.Event{ID: "ev-1", Type: entity.EventItemCreated, ItemID: 1, Item: watch},
+			&entity.Event{ID: "ev-2", Type: entity.EventItemCreated, ItemID: 2, Item: bag},
+			&entity.Event{ID: "ev-3", Type: entity.EventItemUpdated, ItemID: 1, Item: &repriced},
+			&entity.Event{ID: "ev-4", Type: entity.EventItemDeleted, ItemID: 2, Item: bag},
+		)
+		items := &fakeReplicaItems{items: make(map[int64]*entity.Item)}
+		checkpoints := &fakeCheckpoints{sequences: make(map[string]int64)}
+		clk := clock.NewFrozen(now)
+		replica := NewReplicaUsecase(source, sourceName, items, checkpoints, &rollbackTransactor{items: items, checkpoints: checkpoints}, batchSize, clk)
+		return source, items, checkpoints, clk, replica
+	}
+
+	t.Run("正常系: 作成・更新・削除を適用し、適用済みの連番を記録する", func(t *testing.T) {
+		_, items, checkpoints, _, replica := setup(t, 100)
+
+		applied, err := replica.Sync(context.Background())
+
+		require.NoError(t, err)
+		assert.Equal(t, 4, applied)
+		require.Len(t, items.items, 1)
+		assert.Equal(t, 1200000, items.items[1].PurchasePrice)
+		assert.Equal(t, int64(4), checkpoints.sequences[sourceName])
+
+		status := replica.Status()
+		assert.Equal(t, int64(4), status.AppliedSequence)
+		assert.Equal(t, int64(0), status.LagEvents)
+		assert.Equal(t, 0.0, status.LagSeconds)
+		assert.Empty(t, status.LastError)
+	})
+
+	t.Run("正常系: バッチごとに連番を記録し、追いつくまで取得する", func(t *testing.T) {
+		_, items, checkpoints, _, replica := setup(t, 3)
+
+		applied, err := replica.Sync(context.Background())
+
+		require.NoError(t, err)
+		assert.Equal(t, 4, applied)
+		assert.Len(t, items.items, 1)
+		assert.Equal(t, 2, checkpoints.saves)
+	})
+
+	t.Run("正常系: 記録した連番の続きから適用する", func(t *testing.T) {
+		source, _, checkpoints, _, replica := setup(t, 100)
+		_, err := replica.Sync(context.Background())
+		require.NoError(t, err)
+
+		source.publish(t, &entity.Event{ID: "ev-5", Type: entity.EventItemDeleted, ItemID: 1, Item: &repriced})
+		applied, err := replica.Sync(context.Background())
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, applied)
+		assert.Equal(t, int64(5), checkpoints.sequences[sourceName])
+	})
+
+	t.Run("異常系: 送信元に接続できない間はラグが増える", func(t *testing.T) {
+		source, _, _, clk, replica := setup(t, 100)
+		_, err := replica.Sync(context.Background())
+		require.NoError(t, err)
+
+		source.err = errors.New("connection refused")
+		clk.Advance(30 * time.Second)
+		_, err = replica.Sync(context.Background())
+
+		assert.ErrorContains(t, err, "connection refused")
+		status := replica.Status()
+		assert.Equal(t, 30.0, status.LagSeconds)
+		assert.Contains(t, status.LastError, "connection refused")
+	})
+
+	t.Run("異常系: 適用に失敗したバッチは連番を進めず、次の Sync でやり直す", func(t *testing.T) {
+		_, items, checkpoints, _, replica := setup(t, 3)
+		items.failOn = 2
+
+		_, err := replica.Sync(context.Background())
+
+		assert.ErrorContains(t, err, "disk full")
+		assert.Empty(t, items.items)
+		assert.Equal(t, int64(0), checkpoints.sequences[sourceName])
+		assert.Equal(t, int64(4), replica.Status().LagEvents)
+
+		items.failOn = 0
+		applied, err := replica.Sync(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 4, applied)
+	})
+}
+
+func TestReplayChangesUntil(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	watch, _ := entity.NewItemAt(now, "時計1", "時計", "ROLEX", 1000000, "2023-01-01")
+	watch.ID = 1
+	bag, _ := entity.NewItemAt(now, "バッグ1", "バッグ", "HERMES", 500000, "2023-01-02")
+	bag.ID = 2
+
+	source := &fakeChangeSource{}
+	source.publish(t,
+		&entity.Event{ID: "ev-1", Type: entity.EventItemCreated, ItemID: 1, Item: watch},
+		&entity.Event{ID: "ev-2", Type: entity.EventItemCreated, ItemID: 2, Item: bag},
+		&entity.Event{ID: "ev-3", Type: entity.EventItemDeleted, ItemID: 1, Item: watch},
+	)
+
+	t.Run("正常系: 指定した連番までのイベントだけを再生する", func(t *testing.T) {
+		projection, err := ReplayChangesUntil(context.Background(), source, 2)
+
+		require.NoError(t, err)
+		assert.Len(t, projection.Items(), 2)
+	})
+
+	t.Run("異常系: 送信元のイベントが指定した連番に届かない", func(t *testing.T) {
+		_, err := ReplayChangesUntil(context.Background(), source, 10)
+
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,92 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/pkg/listquery"
+)
+
+// データクレンジング向けの外れ値レポート
+type OutlierReport struct {
+	Category  string            `json:"category,omitempty"`
+	Threshold float64           `json:"threshold"`
+	Outliers  []*entity.Outlier `json:"outliers"`
+}
+
+// カテゴリー内で価格・購入日・名前の長さが統計的に外れているアイテムを返す
+// category を指定した場合はそのカテゴリーだけを調べる
+func (u *itemUsecase) GetOutlierReport(ctx context.Context, category string) (*OutlierReport, error) {
+	if category != "" && !slices.Contains(entity.GetValidCategories(), category) {
+		return nil, fmt.Errorf("%w: invalid category: %s", domainErrors.ErrInvalidInput, category)
+	}
+
+	items, err := retryTransient(ctx, func() ([]*entity.Item, error) {
+		return u.itemRepo.FindAll(ctx)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve items: %w", err)
+	}
+
+	if category != "" {
+		items = slices.DeleteFunc(items, func(item *entity.Item) bool {
+			return item.Category != category
+		})
+	}
+
+	return &OutlierReport{
+		Category:  category,
+		Threshold: entity.OutlierScoreThreshold,
+		Outliers:  entity.FindOutliers(items),
+	}, nil
+}
+
+// 組織をID順にページングし、組織ごとのアイテム数と購入価格の合計を返す
+// アイテムのない組織も 0 件として含める。組織に所属していないアイテムは数えない
+func (u *itemUsecase) SummarizeByOrganization(ctx context.Context, q listquery.Query) (*listquery.Result[*entity.OrganizationItemSummary], error) {
+	if u.orgs == nil {
+		return nil, errors.New("organization repository is not configured")
+	}
+	if q.Page.CursorMode {
+		return nil, fmt.Errorf("%w: cursor pagination is not supported", domainErrors.ErrInvalidInput)
+	}
+
+	orgs, err := retryTransient(ctx, func() ([]*entity.Organization, error) {
+		return u.orgs.FindPage(ctx, q.Page.Size, q.Page.Offset())
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve organizations: %w", err)
+	}
+	total, err := retryTransient(ctx, func() (int, error) {
+		return u.orgs.Count(ctx)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to count organizations: %w", err)
+	}
+
+	orgIDs := make([]int64, len(orgs))
+	for i, org := range orgs {
+		orgIDs[i] = org.ID
+	}
+	counted, err := retryTransient(ctx, func() (map[int64]*entity.OrganizationItemSummary, error) {
+		return u.itemRepo.SummarizeByOrganization(ctx, orgIDs)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize items: %w", err)
+	}
+
+	summaries := make([]*entity.OrganizationItemSummary, len(orgs))
+	for i, org := range orgs {
+		summary := &entity.OrganizationItemSummary{OrgID: org.ID, Name: org.Name}
+		if c, ok := counted[org.ID]; ok {
+			summary.ItemCount = c.ItemCount
+			summary.TotalValue = c.TotalValue
+		}
+		summaries[i] = summary
+	}
+	return &listquery.Result[*entity.OrganizationItemSummary]{Items: summaries, Total: total}, nil
+}
@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"time"
 
 	"Aicon-assignment/internal/domain/entity"
 )
@@ -11,18 +12,607 @@ type ItemRepository interface {
 	// FindAll retrieves all items
 	FindAll(ctx context.Context) ([]*entity.Item, error)
 
+	// FindByQuery retrieves items matching the query in the requested order
+	FindByQuery(ctx context.Context, query entity.ItemQuery) ([]*entity.Item, error)
+
+	// CountByQuery counts items matching the query, ignoring limit and offset
+	CountByQuery(ctx context.Context, query entity.ItemQuery) (int, error)
+
 	// FindByID retrieves an item by ID
 	FindByID(ctx context.Context, id int64) (*entity.Item, error)
 
+	// FindByIDIncludingDeleted retrieves an item by ID like FindByID, including soft-deleted items not yet purged
+	FindByIDIncludingDeleted(ctx context.Context, id int64) (*entity.Item, error)
+
+	// FindByOrganization retrieves the live items of the organization ordered by ID
+	FindByOrganization(ctx context.Context, orgID int64) ([]*entity.Item, error)
+
+	// SummarizeByOrganization counts items and sums their purchase prices per organization in a single grouped query;
+	// organizations without items are omitted, and Name is left empty
+	SummarizeByOrganization(ctx context.Context, orgIDs []int64) (map[int64]*entity.OrganizationItemSummary, error)
+
 	// Create creates a new item and returns it with the generated ID
 	Create(ctx context.Context, item *entity.Item) (*entity.Item, error)
 
 	// Update updates an existing item and returns it
 	Update(ctx context.Context, item *entity.Item) (*entity.Item, error)
 
-	// Delete deletes an item by ID
+	// AssignOwner sets the owner of a live item, including items created without a user
+	AssignOwner(ctx context.Context, id, ownerID int64) error
+
+	// RecordPriceChange appends an entry to the item's price history
+	RecordPriceChange(ctx context.Context, change *entity.PriceChange) error
+
+	// FindPriceHistory returns the price history of an item, oldest first
+	FindPriceHistory(ctx context.Context, itemID int64) ([]*entity.PriceChange, error)
+
+	// GetSummaryBy returns item counts grouped by the given dimension
+	GetSummaryBy(ctx context.Context, dim entity.SummaryDimension) (map[string]int, error)
+
+	// MergeInto moves the price history of the source item to the target and soft-deletes the source;
+	// the other records of the source are moved by the ItemRecordMovers given with WithMergedRecords
+	MergeInto(ctx context.Context, sourceID, targetID int64, at time.Time) error
+
+	// SoftDelete hides an item from all queries while keeping its row and history
+	SoftDelete(ctx context.Context, id int64, at time.Time) error
+
+	// CountDeletedBefore counts soft-deleted items deleted before cutoff
+	CountDeletedBefore(ctx context.Context, cutoff time.Time) (int64, error)
+
+	// FindIDsDeletedBefore returns the IDs of soft-deleted items deleted before cutoff
+	FindIDsDeletedBefore(ctx context.Context, cutoff time.Time) ([]int64, error)
+
+	// PurgeDeletedBefore permanently removes soft-deleted items deleted before cutoff
+	PurgeDeletedBefore(ctx context.Context, cutoff time.Time) (int64, error)
+
+	// Purge permanently removes an item and its history whether or not it is soft-deleted
+	Purge(ctx context.Context, id int64) error
+}
+
+// ItemSearcher finds items by keyword; the item repositories implement it with LIKE, and a SearchRepository can replace them
+type ItemSearcher interface {
+	// Search returns up to search.Limit items whose name or brand matches the keyword, ignoring case, newest first.
+	// LIKE implementations match substrings; full-text backends match words and their prefixes, tolerating typos
+	Search(ctx context.Context, search entity.ItemSearch) ([]*entity.Item, error)
+}
+
+// SearchRepository is a full-text search index of items, kept in sync with the item repository by SearchIndexer
+type SearchRepository interface {
+	ItemSearcher
+	// Index adds the items to the index, replacing documents with the same ID
+	Index(ctx context.Context, items []*entity.Item) error
+	// Remove deletes the items from the index; unknown IDs are ignored
+	Remove(ctx context.Context, ids []int64) error
+}
+
+// Transactor runs fn atomically; repositories called with the ctx passed to fn join the transaction
+type Transactor interface {
+	Transaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// ItemRecordMover re-links the records that belong to an item, used when merging items
+type ItemRecordMover interface {
+	// MoveToItem re-links the records of fromItemID to toItemID
+	MoveToItem(ctx context.Context, fromItemID, toItemID int64) error
+}
+
+// ItemRecordPurger removes the records that belong to an item and their files, used when purging items
+type ItemRecordPurger interface {
+	// PurgeItemRecords removes the records of itemID; the item itself may already be gone
+	PurgeItemRecords(ctx context.Context, itemID int64) error
+}
+
+// AuditLogRepository persists audit log entries
+type AuditLogRepository interface {
+	// Record appends an entry to the audit log
+	Record(ctx context.Context, entry *entity.AuditEntry) error
+
+	// FindByItemID returns the entries of an item, oldest first
+	FindByItemID(ctx context.Context, itemID int64) ([]*entity.AuditEntry, error)
+
+	// CountBefore counts entries created before cutoff
+	CountBefore(ctx context.Context, cutoff time.Time) (int64, error)
+
+	// DeleteBefore removes entries created before cutoff
+	DeleteBefore(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+// WebhookRepository defines the interface for webhook subscription data access
+type WebhookRepository interface {
+	FindAll(ctx context.Context) ([]*entity.Webhook, error)
+	FindByID(ctx context.Context, id int64) (*entity.Webhook, error)
+	Create(ctx context.Context, webhook *entity.Webhook) (*entity.Webhook, error)
+	Update(ctx context.Context, webhook *entity.Webhook) (*entity.Webhook, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+// WebhookDeliveryRepository persists webhook delivery attempts
+type WebhookDeliveryRepository interface {
+	// Record stores a delivery attempt and sets its ID
+	Record(ctx context.Context, delivery *entity.WebhookDelivery) error
+
+	// FindByID retrieves a delivery attempt by ID
+	FindByID(ctx context.Context, id int64) (*entity.WebhookDelivery, error)
+
+	// FindByWebhookID returns attempts of a webhook, newest first, optionally filtered by status
+	FindByWebhookID(ctx context.Context, webhookID int64, status string) ([]*entity.WebhookDelivery, error)
+
+	// GetStats aggregates the delivery attempts of a webhook
+	GetStats(ctx context.Context, webhookID int64) (*entity.WebhookDeliveryStats, error)
+
+	// CountBefore counts attempts made before cutoff
+	CountBefore(ctx context.Context, cutoff time.Time) (int64, error)
+
+	// DeleteBefore removes attempts made before cutoff
+	DeleteBefore(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+// RetentionPolicyRepository persists retention policies changed by administrators
+type RetentionPolicyRepository interface {
+	// FindAll returns the stored policies; data types without a stored policy are omitted
+	FindAll(ctx context.Context) ([]*entity.RetentionPolicy, error)
+
+	// Save creates or replaces the policy of its data type
+	Save(ctx context.Context, policy *entity.RetentionPolicy) error
+}
+
+// DeprecationUsageRepository counts calls to deprecated endpoints per caller
+type DeprecationUsageRepository interface {
+	// Record adds one call by the caller at the given time, creating the row on the first call
+	Record(ctx context.Context, method, route, caller string, at time.Time) error
+
+	// FindAll returns the usage of every endpoint and caller, most recently seen first
+	FindAll(ctx context.Context) ([]*entity.DeprecationUsage, error)
+}
+
+// UserRepository persists staff accounts provisioned from the IdP
+type UserRepository interface {
+	// Create stores a new user and sets its ID; returns domainErrors.ErrDuplicateEntry if the userName is taken
+	Create(ctx context.Context, user *entity.User) error
+
+	// FindByID returns domainErrors.ErrUserNotFound if the user does not exist
+	FindByID(ctx context.Context, id int64) (*entity.User, error)
+
+	// FindByQuery retrieves users matching the query ordered by ID
+	FindByQuery(ctx context.Context, query entity.UserQuery) ([]*entity.User, error)
+
+	// CountByQuery counts users matching the query, ignoring limit and offset
+	CountByQuery(ctx context.Context, query entity.UserQuery) (int, error)
+
+	// Update saves the user; returns domainErrors.ErrDuplicateEntry if the userName is taken
+	Update(ctx context.Context, user *entity.User) error
+}
+
+// OrganizationRepository persists organizations and their memberships
+type OrganizationRepository interface {
+	// Create stores a new organization and sets its ID
+	Create(ctx context.Context, org *entity.Organization) error
+
+	// FindByID returns domainErrors.ErrOrganizationNotFound if the organization does not exist
+	FindByID(ctx context.Context, id int64) (*entity.Organization, error)
+
+	// FindMembers returns the members of the organization ordered by user ID
+	FindMembers(ctx context.Context, orgID int64) ([]*entity.Membership, error)
+
+	// FindMember returns domainErrors.ErrMemberNotFound if the user is not a member
+	FindMember(ctx context.Context, orgID, userID int64) (*entity.Membership, error)
+
+	// AddMember returns domainErrors.ErrDuplicateEntry if the user is already a member
+	AddMember(ctx context.Context, member *entity.Membership) error
+
+	// UpdateMember saves the role of an existing member
+	UpdateMember(ctx context.Context, member *entity.Membership) error
+
+	// RemoveMember returns domainErrors.ErrMemberNotFound if the user is not a member
+	RemoveMember(ctx context.Context, orgID, userID int64) error
+
+	// CountAdmins counts the admins of the organization, locking them until the transaction ends
+	CountAdmins(ctx context.Context, orgID int64) (int, error)
+
+	// FindBranding returns domainErrors.ErrBrandingNotFound if the organization has not configured its branding
+	FindBranding(ctx context.Context, orgID int64) (*entity.Branding, error)
+
+	// SaveBranding creates or replaces the branding of the organization
+	SaveBranding(ctx context.Context, branding *entity.Branding) error
+
+	// FindFieldLockPolicy returns the field lock rules of the organization, or a policy without rules if none are configured
+	FindFieldLockPolicy(ctx context.Context, orgID int64) (*entity.FieldLockPolicy, error)
+
+	// SaveFieldLockPolicy creates or replaces the field lock rules of the organization
+	SaveFieldLockPolicy(ctx context.Context, policy *entity.FieldLockPolicy) error
+
+	// FindPage returns organizations ordered by ID; limit 0 returns all
+	FindPage(ctx context.Context, limit, offset int) ([]*entity.Organization, error)
+
+	// Count counts all organizations
+	Count(ctx context.Context) (int, error)
+}
+
+// InvitationRepository persists invitations to organizations
+type InvitationRepository interface {
+	// Create stores a new invitation and sets its ID
+	Create(ctx context.Context, invitation *entity.Invitation) error
+
+	// FindByTokenHash returns domainErrors.ErrInvitationNotFound if no invitation has the token
+	FindByTokenHash(ctx context.Context, tokenHash string) (*entity.Invitation, error)
+
+	// FindByOrgID returns the invitations of the organization, newest first
+	FindByOrgID(ctx context.Context, orgID int64) ([]*entity.Invitation, error)
+
+	// MarkAccepted records that the user accepted the invitation
+	MarkAccepted(ctx context.Context, id, userID int64, at time.Time) error
+}
+
+// ImpersonationRepository persists administrator impersonation sessions
+type ImpersonationRepository interface {
+	// Create stores a new session and sets its ID
+	Create(ctx context.Context, session *entity.ImpersonationSession) error
+
+	// FindByID returns domainErrors.ErrImpersonationNotFound if the session does not exist
+	FindByID(ctx context.Context, id int64) (*entity.ImpersonationSession, error)
+
+	// FindByTokenHash returns domainErrors.ErrImpersonationNotFound if no session has the token
+	FindByTokenHash(ctx context.Context, tokenHash string) (*entity.ImpersonationSession, error)
+
+	// FindAll returns sessions newest first
+	FindAll(ctx context.Context) ([]*entity.ImpersonationSession, error)
+
+	// End marks the session as ended at the given time
+	End(ctx context.Context, id int64, at time.Time) error
+}
+
+// APIKeyRepository stores API keys for machine clients
+type APIKeyRepository interface {
+	// Create stores a new key and sets its ID
+	Create(ctx context.Context, key *entity.APIKey) error
+
+	// FindByID returns domainErrors.ErrAPIKeyNotFound if the key does not exist
+	FindByID(ctx context.Context, id int64) (*entity.APIKey, error)
+
+	// FindByKeyHash returns domainErrors.ErrAPIKeyNotFound if no key has the hash
+	FindByKeyHash(ctx context.Context, keyHash string) (*entity.APIKey, error)
+
+	// FindByUser returns the user's keys, including revoked ones, newest first
+	FindByUser(ctx context.Context, userID int64) ([]*entity.APIKey, error)
+
+	// Revoke marks the key as revoked at the given time
+	Revoke(ctx context.Context, id int64, at time.Time) error
+
+	// TouchLastUsed records when the key was last used
+	TouchLastUsed(ctx context.Context, id int64, at time.Time) error
+}
+
+// RefreshTokenRepository stores refresh tokens by their hash
+type RefreshTokenRepository interface {
+	// Create stores a new token and sets its ID
+	Create(ctx context.Context, token *entity.RefreshToken) error
+
+	// FindByTokenHash returns domainErrors.ErrRefreshTokenNotFound if no token has the hash
+	FindByTokenHash(ctx context.Context, tokenHash string) (*entity.RefreshToken, error)
+
+	// MarkUsed records that the token was exchanged, only if it has not been used yet.
+	// It returns false if the token had already been used, e.g. by a concurrent request
+	MarkUsed(ctx context.Context, id int64, at time.Time) (bool, error)
+
+	// RevokeFamily revokes every token of the family that is not revoked yet
+	RevokeFamily(ctx context.Context, familyID string, at time.Time) error
+
+	// RevokeByUser revokes every token of the user that is not revoked yet
+	RevokeByUser(ctx context.Context, userID int64, at time.Time) error
+}
+
+// PasswordResetRepository stores password reset tokens by their hash
+type PasswordResetRepository interface {
+	// Create stores a new token and sets its ID
+	Create(ctx context.Context, token *entity.PasswordResetToken) error
+
+	// FindByTokenHash returns domainErrors.ErrPasswordResetNotFound if no token has the hash
+	FindByTokenHash(ctx context.Context, tokenHash string) (*entity.PasswordResetToken, error)
+
+	// MarkUsed records that the token was used, only if it has not been used yet.
+	// It returns false if the token had already been used, e.g. by a concurrent request
+	MarkUsed(ctx context.Context, id int64, at time.Time) (bool, error)
+
+	// MarkUsedByUser marks every unused token of the user as used, so older emails stop working
+	MarkUsedByUser(ctx context.Context, userID int64, at time.Time) error
+}
+
+// TwoFactorRepository stores each user's TOTP settings
+type TwoFactorRepository interface {
+	// FindByUser returns domainErrors.ErrTwoFactorNotFound if the user has not started enrolling
+	FindByUser(ctx context.Context, userID int64) (*entity.TwoFactor, error)
+
+	// Save stores the settings, replacing the user's existing ones
+	Save(ctx context.Context, twoFactor *entity.TwoFactor) error
+
+	// Delete removes the user's settings and returns domainErrors.ErrTwoFactorNotFound if there are none
+	Delete(ctx context.Context, userID int64) error
+}
+
+// NotificationPreferenceRepository stores the channels each user receives notifications on
+type NotificationPreferenceRepository interface {
+	// FindByUser returns domainErrors.ErrPreferenceNotFound if the user has not set a preference
+	FindByUser(ctx context.Context, userID int64) (*entity.NotificationPreference, error)
+
+	// Save stores the preference, replacing the user's existing one
+	Save(ctx context.Context, preference *entity.NotificationPreference) error
+}
+
+// UserIdentityRepository links external identities (OIDC / OAuth2) to users
+type UserIdentityRepository interface {
+	// Create stores a new link and sets its ID.
+	// It returns domainErrors.ErrDuplicateEntry if the identity is already linked to a user
+	Create(ctx context.Context, identity *entity.UserIdentity) error
+
+	// FindBySubject returns domainErrors.ErrIdentityNotFound if the identity is not linked
+	FindBySubject(ctx context.Context, provider, subject string) (*entity.UserIdentity, error)
+
+	// FindByUser returns the identities linked to the user ordered by ID
+	FindByUser(ctx context.Context, userID int64) ([]*entity.UserIdentity, error)
+
+	// Delete returns domainErrors.ErrIdentityNotFound if the user has no such identity
+	Delete(ctx context.Context, userID, id int64) error
+}
+
+// OIDCLoginRepository stores login requests between the redirect to the provider and the callback
+type OIDCLoginRepository interface {
+	Create(ctx context.Context, login *entity.OIDCLogin) error
+
+	// Take deletes and returns the request, so that each state can be used only once.
+	// It returns domainErrors.ErrOIDCLoginNotFound if there is no such request or it has already been taken
+	Take(ctx context.Context, stateHash string) (*entity.OIDCLogin, error)
+
+	// DeleteExpired deletes requests that expired before the time and returns how many were deleted
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
+}
+
+// ReferenceDataRepository stores admin-managed reference data of every type in one place
+type ReferenceDataRepository interface {
+	// FindAll returns the records of the type ordered by key
+	FindAll(ctx context.Context, refType string) ([]*entity.ReferenceRecord, error)
+
+	// FindByKey returns domainErrors.ErrReferenceNotFound if the record does not exist
+	FindByKey(ctx context.Context, refType, key string) (*entity.ReferenceRecord, error)
+
+	// Create returns domainErrors.ErrDuplicateEntry if the type already has the key
+	Create(ctx context.Context, record *entity.ReferenceRecord) error
+
+	// Update replaces the attributes and returns domainErrors.ErrReferenceNotFound if the record does not exist
+	Update(ctx context.Context, record *entity.ReferenceRecord) error
+
+	// Delete returns domainErrors.ErrReferenceNotFound if the record does not exist
+	Delete(ctx context.Context, refType, key string) error
+}
+
+// EventStore is an append-only log of domain events
+type EventStore interface {
+	// Append adds an event to the end of the log and sets its sequence
+	Append(ctx context.Context, event *entity.StoredEvent) error
+
+	// LoadAfter returns up to limit events with a sequence greater than afterSequence, oldest first
+	LoadAfter(ctx context.Context, afterSequence int64, limit int) ([]*entity.StoredEvent, error)
+
+	// LastSequence returns the sequence of the newest event, or 0 if the store is empty
+	LastSequence(ctx context.Context) (int64, error)
+}
+
+// ExportRepository persists export bookmarks
+type ExportRepository interface {
+	// Create stores a new export and sets its ID
+	Create(ctx context.Context, export *entity.Export) error
+
+	// FindByID returns domainErrors.ErrExportNotFound if the export does not exist
+	FindByID(ctx context.Context, id int64) (*entity.Export, error)
+}
+
+// BlobRepository records the size, checksum and expiry of files in the BlobStore
+type BlobRepository interface {
+	// Save stores the record, replacing any record with the same key
+	Save(ctx context.Context, blob *entity.Blob) error
+
+	// FindByKey returns domainErrors.ErrBlobNotFound if there is no record for the key
+	FindByKey(ctx context.Context, key string) (*entity.Blob, error)
+
+	// FindExpired returns up to limit records whose expiry is at or before now, oldest expiry first
+	FindExpired(ctx context.Context, now time.Time, limit int) ([]*entity.Blob, error)
+
+	// Delete removes the record; it is not an error if there is none
+	Delete(ctx context.Context, key string) error
+}
+
+// AttachmentRepository persists item attachments and the reference counts of their shared content
+type AttachmentRepository interface {
+	// MoveToItem also turns the receipt of fromItemID into a document if toItemID already has a receipt
+	ItemRecordMover
+
+	// Create stores a new attachment and sets its ID
+	Create(ctx context.Context, attachment *entity.Attachment) error
+
+	// FindByID returns domainErrors.ErrAttachmentNotFound if the attachment does not exist
+	FindByID(ctx context.Context, id int64) (*entity.Attachment, error)
+
+	// FindByItemID returns the attachments of the item, oldest first
+	FindByItemID(ctx context.Context, itemID int64) ([]*entity.Attachment, error)
+
+	// FindReceipt returns the receipt of the item, or domainErrors.ErrReceiptNotFound if it has none
+	FindReceipt(ctx context.Context, itemID int64) (*entity.Attachment, error)
+
+	// Delete removes the attachment; it does not change the reference count of its content
+	Delete(ctx context.Context, id int64) error
+
+	// FindContent returns domainErrors.ErrAttachmentNotFound if no content has the checksum
+	FindContent(ctx context.Context, sha256 string) (*entity.AttachmentContent, error)
+
+	// AddReference increments the reference count of the content, creating it with a count of 1 if it does not exist
+	AddReference(ctx context.Context, sha256 string, size int64) error
+
+	// RemoveReference decrements the reference count of the content and records now if it drops to zero
+	RemoveReference(ctx context.Context, sha256 string, now time.Time) error
+
+	// FindUnreferenced returns up to limit contents that have had no references since before, oldest first
+	FindUnreferenced(ctx context.Context, before time.Time, limit int) ([]*entity.AttachmentContent, error)
+
+	// ReconcileReferences corrects reference counts that no longer match the attachments (for example after
+	// attachments were removed together with a purged item) and returns how many contents were corrected
+	ReconcileReferences(ctx context.Context, now time.Time) (int64, error)
+
+	// DeleteContent removes the content only if it still has no references, and reports whether it did
+	DeleteContent(ctx context.Context, sha256 string) (bool, error)
+
+	// StorageReport totals the attachments and stored content
+	StorageReport(ctx context.Context) (*entity.AttachmentStorageReport, error)
+}
+
+// ImageRepository persists the records of item images; the files themselves are in the blob store
+type ImageRepository interface {
+	// MoveToItem appends the images of fromItemID to the gallery of toItemID; they are not primary if toItemID already has images
+	ItemRecordMover
+
+	// Create stores a new image and sets its ID
+	Create(ctx context.Context, image *entity.ItemImage) error
+
+	// FindByID returns domainErrors.ErrImageNotFound if the image does not exist
+	FindByID(ctx context.Context, id int64) (*entity.ItemImage, error)
+
+	// FindByItemID returns the images of the item in gallery order
+	FindByItemID(ctx context.Context, itemID int64) ([]*entity.ItemImage, error)
+
+	// FindPrimaryByItemIDs returns the primary image of each item that has one
+	FindPrimaryByItemIDs(ctx context.Context, itemIDs []int64) ([]*entity.ItemImage, error)
+
+	// UpdateGallery sets the position of each image to its index in orderedIDs and marks primaryID as the only primary image of the item
+	UpdateGallery(ctx context.Context, itemID int64, orderedIDs []int64, primaryID int64) error
+
+	// FindByThumbnailStatus returns the images whose thumbnail status is one of statuses, oldest first
+	FindByThumbnailStatus(ctx context.Context, statuses ...string) ([]*entity.ItemImage, error)
+
+	// UpdateThumbnailStatus returns domainErrors.ErrImageNotFound if the image does not exist
+	UpdateThumbnailStatus(ctx context.Context, id int64, status string) error
+
+	// Delete removes the image record; it does not delete the file
+	Delete(ctx context.Context, id int64) error
+}
+
+// QuarantineRepository persists the records of uploads that failed the virus scan
+type QuarantineRepository interface {
+	// Create stores a new record and sets its ID
+	Create(ctx context.Context, file *entity.QuarantinedFile) error
+
+	// FindAll returns all quarantined files, newest first
+	FindAll(ctx context.Context) ([]*entity.QuarantinedFile, error)
+
+	// FindByID returns domainErrors.ErrQuarantineNotFound if the record does not exist
+	FindByID(ctx context.Context, id int64) (*entity.QuarantinedFile, error)
+
+	// Delete removes the record; it does not delete the file
 	Delete(ctx context.Context, id int64) error
+}
+
+// ReplayCache remembers the nonces of signed requests until their timestamps can no longer be accepted
+type ReplayCache interface {
+	// Remember stores key for ttl and reports whether it was new; false means the key was already stored
+	Remember(ctx context.Context, key string, ttl time.Duration) (bool, error)
+}
+
+// ItemReplicaWriter writes items received from the primary region, keeping their IDs
+type ItemReplicaWriter interface {
+	// Put inserts the item with its ID, or replaces the stored item (restoring it if soft-deleted)
+	Put(ctx context.Context, item *entity.Item) error
+
+	// Remove deletes the item and its history; unknown IDs are ignored
+	Remove(ctx context.Context, id int64) error
+}
+
+// ReplicationCheckpointRepository remembers how far a standby has applied each source's change stream
+type ReplicationCheckpointRepository interface {
+	// Load returns 0 if nothing has been applied from source yet
+	Load(ctx context.Context, source string) (int64, error)
+
+	// Save records sequence as the last applied sequence of source
+	Save(ctx context.Context, source string, sequence int64, at time.Time) error
+}
+
+// EventConsumerRepository stores the named consumers of the EventStore and their acknowledged position
+type EventConsumerRepository interface {
+	// Create stores a new consumer; domainErrors.ErrDuplicateEntry if the name is taken
+	Create(ctx context.Context, consumer *entity.EventConsumer) error
+
+	// Find returns domainErrors.ErrConsumerNotFound if the consumer does not exist.
+	// Inside a transaction the consumer stays locked until it ends, so concurrent polls are served one at a time
+	Find(ctx context.Context, name string) (*entity.EventConsumer, error)
+
+	// List returns all consumers ordered by name
+	List(ctx context.Context) ([]*entity.EventConsumer, error)
+
+	// Save updates the positions, deadline and counters of an existing consumer
+	Save(ctx context.Context, consumer *entity.EventConsumer) error
+
+	// Delete returns domainErrors.ErrConsumerNotFound if the consumer does not exist
+	Delete(ctx context.Context, name string) error
+}
+
+// PortfolioSnapshotRepository stores one snapshot of the whole portfolio per day
+type PortfolioSnapshotRepository interface {
+	// Save stores the snapshot, replacing the one already recorded for the same date
+	Save(ctx context.Context, snapshot *entity.PortfolioSnapshot) error
+
+	// FindSince returns the snapshots dated on or after from (YYYY-MM-DD) ordered by date
+	FindSince(ctx context.Context, from string) ([]*entity.PortfolioSnapshot, error)
+}
+
+// ImportProfileRepository stores the users' column mappings for recurring import files
+type ImportProfileRepository interface {
+	// FindByOwner returns the user's profiles ordered by name
+	FindByOwner(ctx context.Context, ownerID int64) ([]*entity.ImportProfile, error)
+
+	// FindByID returns domainErrors.ErrImportProfileNotFound if the profile does not exist
+	FindByID(ctx context.Context, id int64) (*entity.ImportProfile, error)
+
+	// Create stores a new profile and sets its ID; domainErrors.ErrDuplicateEntry if the owner already has a profile with the name
+	Create(ctx context.Context, profile *entity.ImportProfile) error
+
+	// Update replaces the name, mappings, date format and defaults of the profile
+	Update(ctx context.Context, profile *entity.ImportProfile) error
+
+	// Delete returns domainErrors.ErrImportProfileNotFound if the profile does not exist
+	Delete(ctx context.Context, id int64) error
+}
+
+// ImportStagingRepository keeps imported rows for review until they are committed as items
+type ImportStagingRepository interface {
+	// CreateBatch stores the batch with its rows and sets the batch ID
+	CreateBatch(ctx context.Context, batch *entity.ImportBatch, rows []*entity.StagedImportRow) error
+
+	// FindBatch returns domainErrors.ErrImportBatchNotFound if the batch does not exist
+	FindBatch(ctx context.Context, id int64) (*entity.ImportBatch, error)
+
+	// UpdateBatch saves the status, counts and timestamps of the batch
+	UpdateBatch(ctx context.Context, batch *entity.ImportBatch) error
+
+	// FindRows returns the rows of the batch ordered by row number
+	FindRows(ctx context.Context, batchID int64) ([]*entity.StagedImportRow, error)
+
+	// FindRow returns domainErrors.ErrStagedRowNotFound if the batch has no such row
+	FindRow(ctx context.Context, batchID int64, row int) (*entity.StagedImportRow, error)
+
+	// UpdateRow saves the values and problems of the row
+	UpdateRow(ctx context.Context, row *entity.StagedImportRow) error
+
+	// DeleteRows removes the given rows of the batch, or all of them if none are given
+	DeleteRows(ctx context.Context, batchID int64, rows ...int) error
+
+	// DeleteBatch removes the batch and its rows
+	DeleteBatch(ctx context.Context, id int64) error
+
+	// DeleteExpired removes the batches that expired before the given time with their rows, returning the number of batches
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
+}
+
+// ItemVerificationRepository persists the verification state of items
+type ItemVerificationRepository interface {
+	// Find returns the verification of the item, or an unverified state if it has never been verified
+	Find(ctx context.Context, itemID int64) (*entity.ItemVerification, error)
 
-	// GetSummaryByCategory returns item counts grouped by category (bonus feature)
-	GetSummaryByCategory(ctx context.Context) (map[string]int, error)
+	// Save stores the verification, replacing the previous state of the item
+	Save(ctx context.Context, verification *entity.ItemVerification) error
 }
@@ -0,0 +1,176 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/pkg/clock"
+	"Aicon-assignment/internal/pkg/reqctx"
+)
+
+type RetentionUsecase interface {
+	ListPolicies(ctx context.Context) ([]*entity.RetentionPolicy, error)
+	UpdatePolicy(ctx context.Context, dataType string, input UpdateRetentionPolicyInput) (*entity.RetentionPolicy, error)
+	Preview(ctx context.Context, dataType string) (*entity.RetentionResult, error)
+	Run(ctx context.Context, dryRun bool) ([]*entity.RetentionResult, error)
+}
+
+type UpdateRetentionPolicyInput struct {
+	RetainDays *int `json:"retain_days"`
+}
+
+// 保持期間を過ぎたデータの数え方と削除の仕方
+type RetentionTarget struct {
+	Count func(ctx context.Context, cutoff time.Time) (int64, error)
+	Purge func(ctx context.Context, cutoff time.Time) (int64, error)
+	// 設定を変更していない場合の保持期間。nil の場合は entity.DefaultRetentionPolicies の値
+	DefaultRetainDays func() int
+}
+
+type retentionUsecase struct {
+	policies RetentionPolicyRepository
+	targets  map[string]RetentionTarget
+	clock    clock.Clock
+}
+
+// targets はデータの種類（entity.RetentionDataTypes）ごとの削除方法
+func NewRetentionUsecase(policies RetentionPolicyRepository, targets map[string]RetentionTarget, clock clock.Clock) RetentionUsecase {
+	return &retentionUsecase{
+		policies: policies,
+		targets:  targets,
+		clock:    clock,
+	}
+}
+
+// すべてのデータの種類の保持期間。変更していないものはデフォルト値を返す
+func (u *retentionUsecase) ListPolicies(ctx context.Context) ([]*entity.RetentionPolicy, error) {
+	stored, err := u.policies.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve retention policies: %w", err)
+	}
+
+	byType := make(map[string]*entity.RetentionPolicy, len(stored))
+	for _, policy := range stored {
+		byType[policy.DataType] = policy
+	}
+
+	policies := entity.DefaultRetentionPolicies()
+	for i, policy := range policies {
+		if saved, ok := byType[policy.DataType]; ok {
+			policies[i] = saved
+		} else if target, ok := u.targets[policy.DataType]; ok && target.DefaultRetainDays != nil {
+			policy.RetainDays = target.DefaultRetainDays()
+		}
+	}
+	return policies, nil
+}
+
+func (u *retentionUsecase) UpdatePolicy(ctx context.Context, dataType string, input UpdateRetentionPolicyInput) (*entity.RetentionPolicy, error) {
+	policy, err := u.policy(ctx, dataType)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.RetainDays != nil {
+		policy.RetainDays = *input.RetainDays
+	}
+	if err := policy.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrInvalidInput, err.Error())
+	}
+	now := u.clock.Now()
+	policy.UpdatedAt = &now
+
+	if err := u.policies.Save(ctx, policy); err != nil {
+		return nil, fmt.Errorf("failed to save retention policy: %w", err)
+	}
+
+	return policy, nil
+}
+
+// 現在の設定で削除されるデータの件数を返す。データは削除しない
+func (u *retentionUsecase) Preview(ctx context.Context, dataType string) (*entity.RetentionResult, error) {
+	policy, err := u.policy(ctx, dataType)
+	if err != nil {
+		return nil, err
+	}
+	return u.apply(ctx, policy, true)
+}
+
+// すべてのデータの種類に保持期間を適用する
+// 1つの種類で失敗しても残りの種類は処理し、最初のエラーを返す
+func (u *retentionUsecase) Run(ctx context.Context, dryRun bool) ([]*entity.RetentionResult, error) {
+	policies, err := u.ListPolicies(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*entity.RetentionResult, 0, len(policies))
+	var firstErr error
+	for _, policy := range policies {
+		result, err := u.apply(ctx, policy, dryRun)
+		if err != nil {
+			reqctx.Logger(ctx).Error("failed to apply retention policy", "data_type", policy.DataType, "error", err)
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		if !dryRun && result.Count > 0 {
+			reqctx.Logger(ctx).Info("purged expired data", "data_type", policy.DataType, "count", result.Count)
+		}
+		results = append(results, result)
+	}
+
+	return results, firstErr
+}
+
+func (u *retentionUsecase) apply(ctx context.Context, policy *entity.RetentionPolicy, dryRun bool) (*entity.RetentionResult, error) {
+	result := &entity.RetentionResult{
+		DataType:   policy.DataType,
+		RetainDays: policy.RetainDays,
+		DryRun:     dryRun,
+	}
+
+	cutoff, ok := policy.Cutoff(u.clock.Now())
+	if !ok {
+		return result, nil
+	}
+	result.Cutoff = &cutoff
+
+	target, ok := u.targets[policy.DataType]
+	if !ok {
+		return nil, fmt.Errorf("no retention target for %s", policy.DataType)
+	}
+
+	var err error
+	if dryRun {
+		result.Count, err = target.Count(ctx, cutoff)
+	} else {
+		result.Count, err = target.Purge(ctx, cutoff)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply retention policy for %s: %w", policy.DataType, err)
+	}
+
+	return result, nil
+}
+
+func (u *retentionUsecase) policy(ctx context.Context, dataType string) (*entity.RetentionPolicy, error) {
+	if !entity.IsRetentionDataType(dataType) {
+		return nil, domainErrors.ErrRetentionNotFound
+	}
+
+	policies, err := u.ListPolicies(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, policy := range policies {
+		if policy.DataType == dataType {
+			return policy, nil
+		}
+	}
+	return nil, domainErrors.ErrRetentionNotFound
+}
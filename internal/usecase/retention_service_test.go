@@ -0,0 +1,162 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/pkg/clock"
+)
+
+// MockRetentionPolicyRepository はテスト用の保持期間設定リポジトリ
+type MockRetentionPolicyRepository struct {
+	mock.Mock
+}
+
+func (m *MockRetentionPolicyRepository) FindAll(ctx context.Context) ([]*entity.RetentionPolicy, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.RetentionPolicy), args.Error(1)
+}
+
+func (m *MockRetentionPolicyRepository) Save(ctx context.Context, policy *entity.RetentionPolicy) error {
+	args := m.Called(ctx, policy)
+	return args.Error(0)
+}
+
+func TestRetentionUsecase(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// 件数だけを返し、削除された cutoff を記録する対象
+	newTarget := func(count int64, purged *[]time.Time) RetentionTarget {
+		return RetentionTarget{
+			Count: func(ctx context.Context, cutoff time.Time) (int64, error) { return count, nil },
+			Purge: func(ctx context.Context, cutoff time.Time) (int64, error) {
+				*purged = append(*purged, cutoff)
+				return count, nil
+			},
+		}
+	}
+
+	t.Run("正常系: 変更していない種類はデフォルトの保持期間を返す", func(t *testing.T) {
+		repo := new(MockRetentionPolicyRepository)
+		repo.On("FindAll", mock.Anything).Return([]*entity.RetentionPolicy{{DataType: entity.RetentionWebhookDeliveries, RetainDays: 7}}, nil)
+
+		policies, err := NewRetentionUsecase(repo, nil, clock.NewFrozen(now)).ListPolicies(context.Background())
+		require.NoError(t, err)
+		require.Len(t, policies, 3)
+		assert.Equal(t, 730, policies[0].RetainDays)
+		assert.Equal(t, 90, policies[1].RetainDays)
+		assert.Equal(t, 7, policies[2].RetainDays)
+	})
+
+	t.Run("正常系: 対象ごとのデフォルトの保持期間を使う", func(t *testing.T) {
+		repo := new(MockRetentionPolicyRepository)
+		repo.On("FindAll", mock.Anything).Return([]*entity.RetentionPolicy{}, nil)
+		var purged []time.Time
+		target := newTarget(2, &purged)
+		target.DefaultRetainDays = func() int { return 30 }
+
+		usecase := NewRetentionUsecase(repo, map[string]RetentionTarget{entity.RetentionDeletedItems: target}, clock.NewFrozen(now))
+		result, err := usecase.Preview(context.Background(), entity.RetentionDeletedItems)
+		require.NoError(t, err)
+		assert.Equal(t, 30, result.RetainDays)
+		assert.Equal(t, now.AddDate(0, 0, -30), *result.Cutoff)
+	})
+
+	t.Run("正常系: dry-run では削除しない", func(t *testing.T) {
+		repo := new(MockRetentionPolicyRepository)
+		repo.On("FindAll", mock.Anything).Return([]*entity.RetentionPolicy{}, nil)
+		var purged []time.Time
+		targets := map[string]RetentionTarget{
+			entity.RetentionAuditLogs:         newTarget(3, &purged),
+			entity.RetentionDeletedItems:      newTarget(1, &purged),
+			entity.RetentionWebhookDeliveries: newTarget(40, &purged),
+		}
+
+		usecase := NewRetentionUsecase(repo, targets, clock.NewFrozen(now))
+		result, err := usecase.Preview(context.Background(), entity.RetentionWebhookDeliveries)
+		require.NoError(t, err)
+		assert.Equal(t, int64(40), result.Count)
+		assert.True(t, result.DryRun)
+		assert.Equal(t, now.AddDate(0, 0, -30), *result.Cutoff)
+
+		results, err := usecase.Run(context.Background(), true)
+		require.NoError(t, err)
+		assert.Len(t, results, 3)
+		assert.Empty(t, purged)
+	})
+
+	t.Run("正常系: 保持期間を過ぎたデータを削除し、0日の種類は削除しない", func(t *testing.T) {
+		repo := new(MockRetentionPolicyRepository)
+		repo.On("FindAll", mock.Anything).Return([]*entity.RetentionPolicy{{DataType: entity.RetentionAuditLogs, RetainDays: 0}}, nil)
+		var purged []time.Time
+		targets := map[string]RetentionTarget{
+			entity.RetentionAuditLogs:         newTarget(3, &purged),
+			entity.RetentionDeletedItems:      newTarget(1, &purged),
+			entity.RetentionWebhookDeliveries: newTarget(40, &purged),
+		}
+
+		results, err := NewRetentionUsecase(repo, targets, clock.NewFrozen(now)).Run(context.Background(), false)
+		require.NoError(t, err)
+		require.Len(t, results, 3)
+		assert.Nil(t, results[0].Cutoff)
+		assert.Equal(t, int64(0), results[0].Count)
+		assert.Equal(t, []time.Time{now.AddDate(0, 0, -90), now.AddDate(0, 0, -30)}, purged)
+	})
+
+	t.Run("異常系: 1つの種類が失敗しても残りは処理する", func(t *testing.T) {
+		repo := new(MockRetentionPolicyRepository)
+		repo.On("FindAll", mock.Anything).Return([]*entity.RetentionPolicy{}, nil)
+		var purged []time.Time
+		failing := RetentionTarget{
+			Purge: func(ctx context.Context, cutoff time.Time) (int64, error) { return 0, errors.New("lock wait timeout") },
+		}
+		targets := map[string]RetentionTarget{
+			entity.RetentionAuditLogs:         failing,
+			entity.RetentionDeletedItems:      newTarget(1, &purged),
+			entity.RetentionWebhookDeliveries: newTarget(40, &purged),
+		}
+
+		results, err := NewRetentionUsecase(repo, targets, clock.NewFrozen(now)).Run(context.Background(), false)
+		assert.Error(t, err)
+		assert.Len(t, results, 2)
+		assert.Len(t, purged, 2)
+	})
+
+	t.Run("正常系: 保持期間を変更する", func(t *testing.T) {
+		repo := new(MockRetentionPolicyRepository)
+		repo.On("FindAll", mock.Anything).Return([]*entity.RetentionPolicy{}, nil)
+		repo.On("Save", mock.Anything, mock.MatchedBy(func(policy *entity.RetentionPolicy) bool {
+			return policy.DataType == entity.RetentionDeletedItems && policy.RetainDays == 30 && policy.UpdatedAt.Equal(now)
+		})).Return(nil)
+
+		days := 30
+		policy, err := NewRetentionUsecase(repo, nil, clock.NewFrozen(now)).UpdatePolicy(context.Background(), entity.RetentionDeletedItems, UpdateRetentionPolicyInput{RetainDays: &days})
+		require.NoError(t, err)
+		assert.Equal(t, 30, policy.RetainDays)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("異常系: 不正な保持期間・種類", func(t *testing.T) {
+		repo := new(MockRetentionPolicyRepository)
+		repo.On("FindAll", mock.Anything).Return([]*entity.RetentionPolicy{}, nil)
+		usecase := NewRetentionUsecase(repo, nil, clock.NewFrozen(now))
+
+		days := -1
+		_, err := usecase.UpdatePolicy(context.Background(), entity.RetentionAuditLogs, UpdateRetentionPolicyInput{RetainDays: &days})
+		assert.ErrorIs(t, err, domainErrors.ErrInvalidInput)
+
+		_, err = usecase.Preview(context.Background(), "sessions")
+		assert.ErrorIs(t, err, domainErrors.ErrRetentionNotFound)
+	})
+}
@@ -0,0 +1,38 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/pkg/reqctx"
+)
+
+const (
+	maxTransientAttempts = 3
+	transientBackoff     = 50 * time.Millisecond
+)
+
+// 一時的なDBエラー（デッドロック、接続断など）の場合のみ再試行する
+// 書き込みは冪等とは限らないため、読み取り系の処理でのみ使用すること
+func retryTransient[T any](ctx context.Context, fn func() (T, error)) (T, error) {
+	var result T
+	var err error
+
+	for attempt := 1; attempt <= maxTransientAttempts; attempt++ {
+		result, err = fn()
+		if err == nil || !domainErrors.IsTransientError(err) || attempt == maxTransientAttempts {
+			return result, err
+		}
+
+		reqctx.Logger(ctx).Warn("retrying after transient error", "attempt", attempt, "error", err)
+
+		select {
+		case <-ctx.Done():
+			return result, err
+		case <-time.After(transientBackoff * time.Duration(attempt)):
+		}
+	}
+
+	return result, err
+}
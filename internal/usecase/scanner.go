@@ -0,0 +1,14 @@
+package usecase
+
+import (
+	"context"
+	"io"
+
+	"Aicon-assignment/internal/domain/entity"
+)
+
+// Scanner checks uploaded files for viruses (ClamAV, an external scanning API)
+type Scanner interface {
+	// Scan reads content to the end; an error means the content could not be checked, not that it is infected
+	Scan(ctx context.Context, content io.Reader) (*entity.ScanResult, error)
+}
@@ -0,0 +1,51 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/pkg/clock"
+)
+
+// 「時計 +10%、バッグ -5%」のような仮定で、ポートフォリオの評価額がどう変わるかを試算する
+type ScenarioUsecase interface {
+	// Run は現在のアイテムから作ったスナップショットに調整を当てはめる。何も保存しない
+	Run(ctx context.Context, input ScenarioInput) (*entity.ScenarioResult, error)
+}
+
+type ScenarioInput struct {
+	Adjustments []entity.ScenarioAdjustment `json:"adjustments"`
+}
+
+type scenarioUsecase struct {
+	items   ItemRepository
+	records ReferenceDataRepository
+	clock   clock.Clock
+}
+
+func NewScenarioUsecase(items ItemRepository, records ReferenceDataRepository, clock clock.Clock) ScenarioUsecase {
+	return &scenarioUsecase{
+		items:   items,
+		records: records,
+		clock:   clock,
+	}
+}
+
+// すべての持ち主のアイテムを集計した値のため、推移と同じく管理者だけが使える
+func (u *scenarioUsecase) Run(ctx context.Context, input ScenarioInput) (*entity.ScenarioResult, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	baseline, err := currentPortfolio(ctx, u.items, u.records, u.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+	if err := entity.ValidateScenario(baseline, input.Adjustments); err != nil {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrInvalidInput, err.Error())
+	}
+
+	return entity.ApplyScenario(baseline, input.Adjustments), nil
+}
@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/pkg/clock"
+	"Aicon-assignment/internal/pkg/reqctx"
+)
+
+func TestScenarioUsecase_Run(t *testing.T) {
+	now := time.Date(2024, 6, 1, 15, 0, 0, 0, time.UTC)
+	asAdmin := reqctx.WithUserRole(reqctx.WithUserID(context.Background(), 1), entity.UserRoleAdmin)
+
+	newUsecase := func() ScenarioUsecase {
+		items := new(MockItemRepository)
+		items.On("FindAll", mock.MatchedBy(reqctx.AllOwners)).Return([]*entity.Item{
+			{ID: 1, Category: "時計", PurchasePrice: 1000000},
+			{ID: 2, Category: "バッグ", PurchasePrice: 200000},
+		}, nil)
+		records := new(MockReferenceDataRepository)
+		records.On("FindAll", mock.Anything, entity.ReferenceCategories).Return([]*entity.ReferenceRecord{
+			{Type: entity.ReferenceCategories, Key: "時計", Attributes: map[string]any{"value_percent": int64(120)}},
+		}, nil)
+		return NewScenarioUsecase(items, records, clock.NewFrozen(now))
+	}
+
+	t.Run("正常系: 現在の評価額に調整を当てはめる", func(t *testing.T) {
+		result, err := newUsecase().Run(asAdmin, ScenarioInput{Adjustments: []entity.ScenarioAdjustment{
+			{Category: "時計", Percent: 10},
+			{Category: "バッグ", Percent: -5},
+		}})
+		require.NoError(t, err)
+
+		assert.Equal(t, "2024-06-01", result.Baseline.Date)
+		assert.Equal(t, int64(1400000), result.Baseline.CurrentValue)
+		assert.Equal(t, int64(1320000+190000), result.ProjectedValue)
+		assert.Equal(t, int64(110000), result.Change)
+	})
+
+	t.Run("異常系: アイテムのないカテゴリー", func(t *testing.T) {
+		_, err := newUsecase().Run(asAdmin, ScenarioInput{Adjustments: []entity.ScenarioAdjustment{{Category: "宝石", Percent: 10}}})
+
+		assert.ErrorIs(t, err, domainErrors.ErrInvalidInput)
+	})
+
+	t.Run("異常系: 管理者以外", func(t *testing.T) {
+		asMember := reqctx.WithUserRole(reqctx.WithUserID(context.Background(), 2), entity.UserRoleMember)
+
+		_, err := NewScenarioUsecase(new(MockItemRepository), new(MockReferenceDataRepository), clock.NewFrozen(now)).
+			Run(asMember, ScenarioInput{Adjustments: []entity.ScenarioAdjustment{{Category: "時計", Percent: 10}}})
+
+		assert.ErrorIs(t, err, domainErrors.ErrForbidden)
+	})
+
+	t.Run("異常系: 呼び出し元の分からないリクエストは使えない", func(t *testing.T) {
+		_, err := NewScenarioUsecase(new(MockItemRepository), new(MockReferenceDataRepository), clock.NewFrozen(now)).
+			Run(context.Background(), ScenarioInput{Adjustments: []entity.ScenarioAdjustment{{Category: "時計", Percent: 10}}})
+
+		assert.ErrorIs(t, err, domainErrors.ErrUnauthenticated)
+	})
+}
@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"Aicon-assignment/internal/domain/entity"
+	"Aicon-assignment/internal/pkg/listquery"
+	"Aicon-assignment/internal/pkg/reqctx"
+)
+
+const reindexBatchSize = 500
+
+// アイテムの作成・更新・削除イベントを全文検索のインデックスに反映する発行先
+// 反映に失敗しても元の操作は成功させ、ログに残す（ずれた場合は ReindexItems で作り直す）
+type SearchIndexer struct {
+	index SearchRepository
+}
+
+func NewSearchIndexer(index SearchRepository) *SearchIndexer {
+	return &SearchIndexer{index: index}
+}
+
+func (s *SearchIndexer) Publish(ctx context.Context, event *entity.Event) {
+	var err error
+	switch event.Type {
+	case entity.EventItemCreated, entity.EventItemUpdated:
+		if event.Item == nil {
+			return
+		}
+		err = s.index.Index(ctx, []*entity.Item{event.Item})
+	case entity.EventItemDeleted:
+		err = s.index.Remove(ctx, []int64{event.ItemID})
+	default:
+		return
+	}
+	if err != nil {
+		reqctx.Logger(ctx).Error("failed to update search index", "event_id", event.ID, "type", event.Type, "item_id", event.ItemID, "error", err)
+	}
+}
+
+// 削除されていない全アイテムをID順にインデックスへ登録し直す
+// 登録した件数を返す。削除済みのアイテムが残っている場合は、先にインデックスを空にしてから使う
+func ReindexItems(ctx context.Context, itemRepo ItemRepository, index SearchRepository) (int, error) {
+	var indexed int
+	for {
+		items, err := itemRepo.FindByQuery(ctx, entity.ItemQuery{
+			Sort:   []listquery.SortField{{Field: "id"}},
+			Limit:  reindexBatchSize,
+			Offset: indexed,
+		})
+		if err != nil {
+			return indexed, fmt.Errorf("failed to load items after offset %d: %w", indexed, err)
+		}
+		if len(items) > 0 {
+			if err := index.Index(ctx, items); err != nil {
+				return indexed, fmt.Errorf("failed to index items after offset %d: %w", indexed, err)
+			}
+		}
+		indexed += len(items)
+
+		if len(items) < reindexBatchSize {
+			return indexed, nil
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"Aicon-assignment/internal/domain/entity"
+)
+
+// MockSearchRepository はテスト用の全文検索インデックス
+type MockSearchRepository struct {
+	mock.Mock
+}
+
+func (m *MockSearchRepository) Search(ctx context.Context, search entity.ItemSearch) ([]*entity.Item, error) {
+	args := m.Called(ctx, search)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.Item), args.Error(1)
+}
+
+func (m *MockSearchRepository) Index(ctx context.Context, items []*entity.Item) error {
+	args := m.Called(ctx, items)
+	return args.Error(0)
+}
+
+func (m *MockSearchRepository) Remove(ctx context.Context, ids []int64) error {
+	args := m.Called(ctx, ids)
+	return args.Error(0)
+}
+
+func TestSearchIndexer_Publish(t *testing.T) {
+	item := &entity.Item{ID: 1, Name: "時計1", Brand: "ROLEX"}
+
+	t.Run("正常系: 作成・更新はインデックスに登録し、削除は取り除く", func(t *testing.T) {
+		index := new(MockSearchRepository)
+		index.On("Index", mock.Anything, []*entity.Item{item}).Return(nil).Twice()
+		index.On("Remove", mock.Anything, []int64{1}).Return(nil).Once()
+
+		indexer := NewSearchIndexer(index)
+		indexer.Publish(context.Background(), &entity.Event{ID: "ev-1", Type: entity.EventItemCreated, ItemID: 1, Item: item})
+		indexer.Publish(context.Background(), &entity.Event{ID: "ev-2", Type: entity.EventItemUpdated, ItemID: 1, Item: item})
+		indexer.Publish(context.Background(), &entity.Event{ID: "ev-3", Type: entity.EventItemDeleted, ItemID: 1, Item: item})
+
+		index.AssertExpectations(t)
+	})
+
+	t.Run("異常系: インデックスへの反映に失敗しても panic しない", func(t *testing.T) {
+		index := new(MockSearchRepository)
+		index.On("Index", mock.Anything, mock.Anything).Return(errors.New("connection refused"))
+
+		NewSearchIndexer(index).Publish(context.Background(), &entity.Event{ID: "ev-1", Type: entity.EventItemCreated, ItemID: 1, Item: item})
+		index.AssertExpectations(t)
+	})
+}
+
+func TestReindexItems(t *testing.T) {
+	full := make([]*entity.Item, reindexBatchSize)
+	for i := range full {
+		full[i] = &entity.Item{ID: int64(i + 1)}
+	}
+	rest := []*entity.Item{{ID: int64(reindexBatchSize + 1)}}
+
+	repo := new(MockItemRepository)
+	repo.On("FindByQuery", mock.Anything, mock.MatchedBy(func(q entity.ItemQuery) bool { return q.Offset == 0 })).Return(full, nil)
+	repo.On("FindByQuery", mock.Anything, mock.MatchedBy(func(q entity.ItemQuery) bool { return q.Offset == reindexBatchSize })).Return(rest, nil)
+
+	index := new(MockSearchRepository)
+	index.On("Index", mock.Anything, full).Return(nil).Once()
+	index.On("Index", mock.Anything, rest).Return(nil).Once()
+
+	indexed, err := ReindexItems(context.Background(), repo, index)
+	require.NoError(t, err)
+	assert.Equal(t, reindexBatchSize+1, indexed)
+	index.AssertExpectations(t)
+}
@@ -2,19 +2,45 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"Aicon-assignment/internal/domain/entity"
 	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/pkg/clock"
+	"Aicon-assignment/internal/pkg/idgen"
+	"Aicon-assignment/internal/pkg/listquery"
+	"Aicon-assignment/internal/pkg/reqctx"
 )
 
 type ItemUsecase interface {
 	GetAllItems(ctx context.Context) ([]*entity.Item, error)
+	ListItems(ctx context.Context, query listquery.Query) (*listquery.Result[*entity.Item], error)
+	EachItem(ctx context.Context, query listquery.Query, fn func(items []*entity.Item) error) error
+	SearchItems(ctx context.Context, keyword string, limit int) ([]*entity.Item, error)
 	GetItemByID(ctx context.Context, id int64) (*entity.Item, error)
 	CreateItem(ctx context.Context, input CreateItemInput) (*entity.Item, error)
+	CreateItems(ctx context.Context, inputs []CreateItemInput, atomic bool) (*BulkCreateResult, error)
 	UpdateItem(ctx context.Context, id int64, input UpdateItemInput) (*entity.Item, error)
-	DeleteItem(ctx context.Context, id int64) error
-	GetCategorySummary(ctx context.Context) (*CategorySummary, error)
+	UpdateItems(ctx context.Context, input BulkUpdateItemsInput) (*BulkUpdateResult, error)
+	ImportItems(ctx context.Context, rows []ImportRow, dryRun bool) (*ImportResult, error)
+	CommitImport(ctx context.Context, rows []ImportRow, then func(ctx context.Context, created []*entity.Item) error) (*ImportResult, error)
+	DeleteItem(ctx context.Context, id int64, reason string) error
+	DeleteItems(ctx context.Context, ids []int64, reason string) (*BulkDeleteResult, error)
+	PurgeItem(ctx context.Context, id int64, reason string) error
+	PurgeExpired(ctx context.Context, cutoff time.Time) (int64, error)
+	GetPriceHistory(ctx context.Context, id int64) ([]*entity.PriceChange, error)
+	GetAuditLog(ctx context.Context, id int64) ([]*entity.AuditEntry, error)
+	GetSummary(ctx context.Context, groupBy string) (*Summary, error)
+	CheckPurchasePrice(ctx context.Context, item *entity.Item) *entity.PriceAnomaly
+	GetOutlierReport(ctx context.Context, category string) (*OutlierReport, error)
+	MergeItems(ctx context.Context, targetID int64, input MergeItemsInput) (*entity.Item, error)
+	SplitItem(ctx context.Context, id int64, input SplitItemInput) ([]*entity.Item, error)
+	ReassignCategory(ctx context.Context, from string, input ReassignCategoryInput) (*ReassignCategoryResult, error)
+	AssignOwner(ctx context.Context, id int64, input AssignOwnerInput) (*entity.Item, error)
+	SummarizeByOrganization(ctx context.Context, query listquery.Query) (*listquery.Result[*entity.OrganizationItemSummary], error)
 }
 
 type CreateItemInput struct {
@@ -23,31 +49,145 @@ type CreateItemInput struct {
 	Brand         string `json:"brand"`
 	PurchasePrice int    `json:"purchase_price"`
 	PurchaseDate  string `json:"purchase_date"`
+	OrgID         *int64 `json:"organization_id"` // 所属させる組織（任意）
+	ConfirmPrice  bool   `json:"confirm_price"`   // true の場合は価格の警告を出さない
 }
 
 type UpdateItemInput struct {
 	Name          *string `json:"name"`
 	Brand         *string `json:"brand"`
 	PurchasePrice *int    `json:"purchase_price"`
+	Reason        *string `json:"reason"`         // 価格変更の理由（任意）
+	ConfirmPrice  bool    `json:"confirm_price"`  // true の場合は価格の警告を出さない
+	OverrideLocks bool    `json:"override_locks"` // true の場合はロックされたフィールドも変更する（管理者のみ、理由が必須）
 }
 
-type CategorySummary struct {
-	Categories map[string]int `json:"categories"`
-	Total      int            `json:"total"`
+type Summary struct {
+	GroupBy string         `json:"group_by"`
+	Groups  map[string]int `json:"groups"`
+	Total   int            `json:"total"`
 }
 
 type itemUsecase struct {
-	itemRepo ItemRepository
+	itemRepo      ItemRepository
+	searcher      ItemSearcher
+	orgs          OrganizationRepository
+	users         UserRepository
+	images        ItemImageLoader
+	receipts      ReceiptLoader
+	categories    CategoryCatalog
+	verifications ItemVerificationRepository
+	auditLog      AuditLogRepository
+	mergedRecords []ItemRecordMover
+	purgedRecords []ItemRecordPurger
+	events        EventPublisher
+	reasonPolicy  ReasonPolicyProvider
+	transactor    Transactor
+	clock         clock.Clock
 }
 
-func NewItemUsecase(itemRepo ItemRepository) ItemUsecase {
-	return &itemUsecase{
-		itemRepo: itemRepo,
+// ItemUsecase の任意の依存関係を差し替えるオプション
+type Option func(*itemUsecase)
+
+// 現在時刻の取得元を差し替える（テストで時刻を固定する場合など）
+func WithClock(c clock.Clock) Option {
+	return func(u *itemUsecase) {
+		u.clock = c
+	}
+}
+
+// 監査ログの記録先を設定する
+func WithAuditLog(repo AuditLogRepository) Option {
+	return func(u *itemUsecase) {
+		u.auditLog = repo
+	}
+}
+
+// アイテムの作成・更新・削除イベントの発行先を設定する
+func WithEventPublisher(p EventPublisher) Option {
+	return func(u *itemUsecase) {
+		u.events = p
+	}
+}
+
+// 破壊的な操作に理由を求めるポリシーを設定する
+func WithReasonPolicy(p ReasonPolicyProvider) Option {
+	return func(u *itemUsecase) {
+		u.reasonPolicy = p
+	}
+}
+
+// キーワード検索の検索先を設定する（設定しない場合、リポジトリが ItemSearcher を実装していればそれを使う）
+func WithSearcher(s ItemSearcher) Option {
+	return func(u *itemUsecase) {
+		u.searcher = s
+	}
+}
+
+// アイテムを所属させる組織の参照先を設定する（設定しない場合、組織を指定した作成と組織ごとの集計はできない）
+func WithOrganizations(repo OrganizationRepository) Option {
+	return func(u *itemUsecase) {
+		u.orgs = repo
+	}
+}
+
+// 複数の更新をまとめて行う操作のトランザクションを設定する
+func WithTransactor(t Transactor) Option {
+	return func(u *itemUsecase) {
+		u.transactor = t
 	}
 }
 
+// アイテムを1件取得するときに画像も読み込む
+func WithImages(images ItemImageLoader) Option {
+	return func(u *itemUsecase) {
+		u.images = images
+	}
+}
+
+// アイテムを1件取得するときにレシートも読み込む
+func WithReceipts(receipts ReceiptLoader) Option {
+	return func(u *itemUsecase) {
+		u.receipts = receipts
+	}
+}
+
+// 統合するときに統合先へ付け替える記録（画像・添付ファイルなど）を設定する
+// 監査ログは追記のみのため付け替えない（統合元の記録は統合元の ID のまま残る）
+func WithMergedRecords(movers ...ItemRecordMover) Option {
+	return func(u *itemUsecase) {
+		u.mergedRecords = movers
+	}
+}
+
+// 完全に削除するときに一緒に消す記録（画像など）を設定する
+// 統合・分割で論理削除したアイテムは削除イベントで画像を消さないため、完全に削除するときに消す
+func WithPurgedRecords(purgers ...ItemRecordPurger) Option {
+	return func(u *itemUsecase) {
+		u.purgedRecords = purgers
+	}
+}
+
+func NewItemUsecase(itemRepo ItemRepository, opts ...Option) ItemUsecase {
+	u := &itemUsecase{
+		itemRepo:     itemRepo,
+		reasonPolicy: StaticReasonPolicy{},
+		transactor:   noTransaction{},
+		clock:        clock.System{},
+	}
+	if searcher, ok := itemRepo.(ItemSearcher); ok {
+		u.searcher = searcher
+	}
+	for _, opt := range opts {
+		opt(u)
+	}
+	return u
+}
+
 func (u *itemUsecase) GetAllItems(ctx context.Context) ([]*entity.Item, error) {
-	items, err := u.itemRepo.FindAll(ctx)
+	items, err := retryTransient(ctx, func() ([]*entity.Item, error) {
+		return u.itemRepo.FindAll(ctx)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to retrieve items: %w", err)
 	}
@@ -55,12 +195,139 @@ func (u *itemUsecase) GetAllItems(ctx context.Context) ([]*entity.Item, error) {
 	return items, nil
 }
 
+// 絞り込み・並び替え・ページングした一覧と、絞り込み後の総件数を返す
+// カーソルで指定した場合は総件数を数えず、次のページのカーソルを返す
+func (u *itemUsecase) ListItems(ctx context.Context, q listquery.Query) (*listquery.Result[*entity.Item], error) {
+	query, err := entity.NewItemQuery(q)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrInvalidInput, err.Error())
+	}
+	if q.Page.CursorMode {
+		return u.listItemsByCursor(ctx, query)
+	}
+
+	items, err := retryTransient(ctx, func() ([]*entity.Item, error) {
+		return u.itemRepo.FindByQuery(ctx, query)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve items: %w", err)
+	}
+
+	total := len(items)
+	if query.Limit > 0 {
+		total, err = retryTransient(ctx, func() (int, error) {
+			return u.itemRepo.CountByQuery(ctx, query)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to count items: %w", err)
+		}
+	}
+
+	if err := u.loadPrimaryImages(ctx, items); err != nil {
+		return nil, err
+	}
+	return &listquery.Result[*entity.Item]{Items: items, Total: total}, nil
+}
+
+// 一覧で表示する画像を 1 回の問い合わせでまとめて読み込む
+func (u *itemUsecase) loadPrimaryImages(ctx context.Context, items []*entity.Item) error {
+	if u.images == nil || len(items) == 0 {
+		return nil
+	}
+	ids := make([]int64, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+	}
+	primary, err := u.images.PrimaryImages(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve item images: %w", err)
+	}
+	for _, item := range items {
+		item.PrimaryImage = primary[item.ID]
+	}
+	return nil
+}
+
+// 一括で読み込むアイテムの件数
+const itemBatchSize = 500
+
+// 絞り込みに一致する全アイテムを、作成日時の降順で itemBatchSize 件ずつ fn に渡す
+// 並び替え・ページングの指定は使わない。読んでいる間に追加されたアイテムで重複・欠落しないようカーソルで続きを読む
+func (u *itemUsecase) EachItem(ctx context.Context, q listquery.Query, fn func(items []*entity.Item) error) error {
+	query := entity.ItemQuery{Filter: q.Filter, Sort: entity.ItemCursorSort, Limit: itemBatchSize}
+	for {
+		items, err := retryTransient(ctx, func() ([]*entity.Item, error) {
+			return u.itemRepo.FindByQuery(ctx, query)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to retrieve items: %w", err)
+		}
+		if len(items) > 0 {
+			if err := fn(items); err != nil {
+				return err
+			}
+		}
+		if len(items) < itemBatchSize {
+			return nil
+		}
+		query.After = entity.NewItemCursor(items[len(items)-1])
+	}
+}
+
+// 名前・ブランドにキーワードを含むアイテムを作成日時の降順で返す
+func (u *itemUsecase) SearchItems(ctx context.Context, keyword string, limit int) ([]*entity.Item, error) {
+	search, err := entity.NewItemSearch(keyword, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrInvalidInput, err.Error())
+	}
+	if u.searcher == nil {
+		return nil, errors.New("item search is not configured")
+	}
+
+	items, err := retryTransient(ctx, func() ([]*entity.Item, error) {
+		return u.searcher.Search(ctx, search)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search items: %w", err)
+	}
+	if err := u.loadPrimaryImages(ctx, items); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// 次のページがあるかを知るため1件多く取得する
+func (u *itemUsecase) listItemsByCursor(ctx context.Context, query entity.ItemQuery) (*listquery.Result[*entity.Item], error) {
+	pageSize := query.Limit
+	query.Limit++
+
+	items, err := retryTransient(ctx, func() ([]*entity.Item, error) {
+		return u.itemRepo.FindByQuery(ctx, query)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve items: %w", err)
+	}
+
+	result := &listquery.Result[*entity.Item]{Items: items}
+	if len(items) > pageSize {
+		result.Items = items[:pageSize]
+		result.NextCursor = entity.NewItemCursor(result.Items[pageSize-1]).Encode()
+	}
+	if err := u.loadPrimaryImages(ctx, result.Items); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 func (u *itemUsecase) GetItemByID(ctx context.Context, id int64) (*entity.Item, error) {
 	if id <= 0 {
 		return nil, domainErrors.ErrInvalidInput
 	}
 
-	item, err := u.itemRepo.FindByID(ctx, id)
+	item, err := retryTransient(ctx, func() (*entity.Item, error) {
+		return u.itemRepo.FindByID(ctx, id)
+	})
 	if err != nil {
 		if domainErrors.IsNotFoundError(err) {
 			return nil, domainErrors.ErrItemNotFound
@@ -68,28 +335,121 @@ func (u *itemUsecase) GetItemByID(ctx context.Context, id int64) (*entity.Item,
 		return nil, fmt.Errorf("failed to retrieve item: %w", err)
 	}
 
+	if u.images != nil {
+		images, err := u.images.ItemImages(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve item images: %w", err)
+		}
+		for _, image := range images {
+			item.Images = append(item.Images, *image)
+			if image.IsPrimary {
+				item.PrimaryImage = image
+			}
+		}
+	}
+	if u.receipts != nil {
+		receipt, err := u.receipts.Receipt(ctx, id)
+		if err != nil && !errors.Is(err, domainErrors.ErrReceiptNotFound) {
+			return nil, fmt.Errorf("failed to retrieve item receipt: %w", err)
+		}
+		item.Receipt = receipt
+	}
+
 	return item, nil
 }
 
 func (u *itemUsecase) CreateItem(ctx context.Context, input CreateItemInput) (*entity.Item, error) {
-	// バリデーションして、新しいエンティティを作成
-	item, err := entity.NewItem(
+	item, problems, err := u.newItem(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrInvalidInput, problems.Error())
+	}
+	if err := u.rejectPriceAnomaly(ctx, item, input.ConfirmPrice); err != nil {
+		return nil, err
+	}
+
+	createdItem, err := u.itemRepo.Create(ctx, item)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create item: %w", err)
+	}
+
+	u.recordAudit(ctx, entity.AuditActionItemCreate, createdItem.ID, "")
+	u.publish(ctx, entity.EventItemCreated, createdItem)
+
+	return createdItem, nil
+}
+
+// 入力をバリデーションして新しいエンティティを作成する
+// 入力の問題は problems で返し、err は組織の確認などに失敗した場合だけ返す
+func (u *itemUsecase) newItem(ctx context.Context, input CreateItemInput) (*entity.Item, entity.ValidationErrors, error) {
+	var problems entity.ValidationErrors
+	item, err := entity.NewItemAt(
+		u.clock.Now(),
 		input.Name,
 		input.Category,
 		input.Brand,
 		input.PurchasePrice,
 		input.PurchaseDate,
 	)
-	if err != nil {
-		return nil, fmt.Errorf("%w: %s", domainErrors.ErrInvalidInput, err.Error())
+	var invalid entity.ValidationErrors
+	if errors.As(err, &invalid) {
+		problems = append(problems, invalid...)
+	} else if err != nil {
+		problems = append(problems, entity.FieldError{Message: err.Error()})
 	}
 
-	createdItem, err := u.itemRepo.Create(ctx, item)
+	if input.OrgID != nil {
+		problem, err := u.checkOrganization(ctx, *input.OrgID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if problem != "" {
+			problems = append(problems, entity.FieldError{Field: "organization_id", Message: problem})
+		}
+	}
+
+	if len(problems) > 0 {
+		return nil, problems, nil
+	}
+	item.OrgID = input.OrgID
+	return item, nil, nil
+}
+
+// 組織が存在しない場合と、呼び出し元が組織のメンバーでない場合は入力の問題を返す
+// 管理者とシステムの処理（定期実行・CLI）はメンバーでなくても指定できる
+func (u *itemUsecase) checkOrganization(ctx context.Context, orgID int64) (string, error) {
+	if u.orgs == nil {
+		return "organizations are not available", nil
+	}
+	_, err := retryTransient(ctx, func() (*entity.Organization, error) {
+		return u.orgs.FindByID(ctx, orgID)
+	})
+	if errors.Is(err, domainErrors.ErrOrganizationNotFound) {
+		return fmt.Sprintf("organization %d does not exist", orgID), nil
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to create item: %w", err)
+		return "", fmt.Errorf("failed to retrieve organization: %w", err)
 	}
 
-	return createdItem, nil
+	if requireAdmin(ctx) == nil {
+		return "", nil
+	}
+	userID, ok := reqctx.UserID(ctx)
+	if !ok {
+		return fmt.Sprintf("not a member of organization %d", orgID), nil
+	}
+	_, err = retryTransient(ctx, func() (*entity.Membership, error) {
+		return u.orgs.FindMember(ctx, orgID, userID)
+	})
+	if errors.Is(err, domainErrors.ErrMemberNotFound) {
+		return fmt.Sprintf("not a member of organization %d", orgID), nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to retrieve membership: %w", err)
+	}
+	return "", nil
 }
 
 func (u *itemUsecase) UpdateItem(ctx context.Context, id int64, input UpdateItemInput) (*entity.Item, error) {
@@ -107,26 +467,214 @@ func (u *itemUsecase) UpdateItem(ctx context.Context, id int64, input UpdateItem
 	}
 
 	// 部分更新を適用
-	err = item.PartialUpdate(input.Name, input.Brand, input.PurchasePrice)
+	now := u.clock.Now()
+	before := *item
+	oldPrice := item.PurchasePrice
+	err = item.PartialUpdateAt(now, input.Name, input.Brand, input.PurchasePrice)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %s", domainErrors.ErrInvalidInput, err.Error())
 	}
+	overridden, err := u.checkLockedFields(ctx, &before, item, input.OverrideLocks)
+	if err != nil {
+		return nil, err
+	}
 
-	// アイテムを更新
-	updatedItem, err := u.itemRepo.Update(ctx, item)
+	reason := ""
+	if input.Reason != nil {
+		reason = strings.TrimSpace(*input.Reason)
+	}
+	if reason == "" && len(overridden) > 0 {
+		return nil, domainErrors.ErrReasonRequired
+	}
+	if reason == "" && u.reasonPolicy.PolicyFor(ctx).RequiresReasonForUpdate(oldPrice, item.PurchasePrice) {
+		return nil, domainErrors.ErrReasonRequired
+	}
+	if input.PurchasePrice != nil {
+		if err := u.rejectPriceAnomaly(ctx, item, input.ConfirmPrice); err != nil {
+			return nil, err
+		}
+	}
+
+	var priceChange *entity.PriceChange
+	if item.PurchasePrice != oldPrice {
+		priceChange, err = entity.NewPriceChange(item.ID, oldPrice, item.PurchasePrice, actorFromContext(ctx), reason, now)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", domainErrors.ErrInvalidInput, err.Error())
+		}
+	}
+
+	// アイテムの更新と価格変更履歴の記録は同じトランザクションで行い、履歴のない価格の変更を残さない
+	var updatedItem *entity.Item
+	err = u.transactor.Transaction(ctx, func(ctx context.Context) error {
+		updated, err := u.itemRepo.Update(ctx, item)
+		if err != nil {
+			return fmt.Errorf("failed to update item: %w", err)
+		}
+		if priceChange != nil {
+			if err := u.itemRepo.RecordPriceChange(ctx, priceChange); err != nil {
+				return fmt.Errorf("failed to record price change: %w", err)
+			}
+		}
+		updatedItem = updated
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to update item: %w", err)
+		return nil, err
 	}
 
+	u.recordAudit(ctx, entity.AuditActionItemUpdate, item.ID, overrideReason(overridden, reason))
+	u.publish(ctx, entity.EventItemUpdated, updatedItem, entity.ChangedFields(&before, updatedItem)...)
+
 	return updatedItem, nil
 }
 
-func (u *itemUsecase) DeleteItem(ctx context.Context, id int64) error {
+func (u *itemUsecase) GetPriceHistory(ctx context.Context, id int64) ([]*entity.PriceChange, error) {
+	if _, err := u.GetItemByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	history, err := u.itemRepo.FindPriceHistory(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve price history: %w", err)
+	}
+
+	return history, nil
+}
+
+// 購入価格が同じブランド・カテゴリーの中央値から大きく外れていれば警告を返す
+// 警告は操作を止めないため、判定に失敗した場合もログに残して nil を返す
+func (u *itemUsecase) CheckPurchasePrice(ctx context.Context, item *entity.Item) *entity.PriceAnomaly {
+	peers, err := u.itemRepo.FindByQuery(ctx, entity.PricePeerQuery(item))
+	if err != nil {
+		reqctx.Logger(ctx).Error("failed to check purchase price", "item_id", item.ID, "error", err)
+		return nil
+	}
+
+	return entity.DetectPriceAnomaly(item, peers)
+}
+
+// strict モードでは、通常は警告に留める相場から外れた購入価格を保存前にエラーにする
+// confirm_price で確認済みの場合は受け付ける
+func (u *itemUsecase) rejectPriceAnomaly(ctx context.Context, item *entity.Item, confirmed bool) error {
+	if confirmed || !reqctx.Strict(ctx) {
+		return nil
+	}
+	if anomaly := u.CheckPurchasePrice(ctx, item); anomaly != nil {
+		return fmt.Errorf("%w: %s (set confirm_price to accept it)", domainErrors.ErrInvalidInput, anomaly.Message())
+	}
+	return nil
+}
+
+// アイテムの監査ログ。削除済みのアイテムでも参照できる
+func (u *itemUsecase) GetAuditLog(ctx context.Context, id int64) ([]*entity.AuditEntry, error) {
+	if id <= 0 {
+		return nil, domainErrors.ErrInvalidInput
+	}
+	if u.auditLog == nil {
+		return []*entity.AuditEntry{}, nil
+	}
+	// 管理者以外は自分のアイテム（論理削除したものを含む）の監査ログだけを参照できる
+	if requireAdmin(ctx) != nil {
+		_, err := retryTransient(ctx, func() (*entity.Item, error) {
+			return u.itemRepo.FindByIDIncludingDeleted(ctx, id)
+		})
+		if err != nil {
+			if domainErrors.IsNotFoundError(err) {
+				return nil, domainErrors.ErrItemNotFound
+			}
+			return nil, fmt.Errorf("failed to check item existence: %w", err)
+		}
+	}
+
+	entries, err := retryTransient(ctx, func() ([]*entity.AuditEntry, error) {
+		return u.auditLog.FindByItemID(ctx, id)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve audit log: %w", err)
+	}
+
+	return entries, nil
+}
+
+// 監査ログを記録する
+// 操作自体は完了しているため、記録に失敗してもエラーにはせずログに残す
+func (u *itemUsecase) recordAudit(ctx context.Context, action string, itemID int64, reason string) {
+	if u.auditLog == nil {
+		return
+	}
+
+	entry := &entity.AuditEntry{
+		Action:       action,
+		ItemID:       itemID,
+		Actor:        actorFromContext(ctx),
+		Impersonator: impersonatorFromContext(ctx),
+		Reason:       reason,
+		CreatedAt:    u.clock.Now(),
+	}
+	if err := u.auditLog.Record(ctx, entry); err != nil {
+		reqctx.Logger(ctx).Error("failed to record audit log", "action", action, "item_id", itemID, "error", err)
+	}
+}
+
+// ドメインイベントを発行する
+func (u *itemUsecase) publish(ctx context.Context, eventType string, item *entity.Item, changedFields ...string) {
+	if u.events == nil {
+		return
+	}
+
+	u.events.Publish(ctx, u.newEvent(ctx, eventType, item, changedFields))
+}
+
+// 統合・分割で論理削除したアイテムの削除イベントを、理由を添えて発行する
+func (u *itemUsecase) publishDeleted(ctx context.Context, item *entity.Item, reason string) {
+	if u.events == nil {
+		return
+	}
+
+	event := u.newEvent(ctx, entity.EventItemDeleted, item, nil)
+	event.DeleteReason = reason
+	u.events.Publish(ctx, event)
+}
+
+func (u *itemUsecase) newEvent(ctx context.Context, eventType string, item *entity.Item, changedFields []string) *entity.Event {
+	return &entity.Event{
+		ID:            idgen.NewRandomID(),
+		Type:          eventType,
+		OccurredAt:    u.clock.Now(),
+		Actor:         actorFromContext(ctx),
+		ItemID:        item.ID,
+		Item:          item,
+		ChangedFields: changedFields,
+	}
+}
+
+// 操作者の識別子。認証ユーザーがいない場合は anonymous
+func actorFromContext(ctx context.Context) string {
+	if userID, ok := reqctx.UserID(ctx); ok {
+		return fmt.Sprintf("user:%d", userID)
+	}
+	return "anonymous"
+}
+
+// 管理者がなりすましている場合はその管理者、そうでなければ空文字
+func impersonatorFromContext(ctx context.Context) string {
+	if adminID, ok := reqctx.ImpersonatorID(ctx); ok {
+		return fmt.Sprintf("user:%d", adminID)
+	}
+	return ""
+}
+
+func (u *itemUsecase) DeleteItem(ctx context.Context, id int64, reason string) error {
 	if id <= 0 {
 		return domainErrors.ErrInvalidInput
 	}
 
-	_, err := u.itemRepo.FindByID(ctx, id)
+	reason = strings.TrimSpace(reason)
+	if reason == "" && u.reasonPolicy.PolicyFor(ctx).RequiresReasonForDelete() {
+		return domainErrors.ErrReasonRequired
+	}
+
+	item, err := u.itemRepo.FindByID(ctx, id)
 	if err != nil {
 		if domainErrors.IsNotFoundError(err) {
 			return domainErrors.ErrItemNotFound
@@ -134,37 +682,117 @@ func (u *itemUsecase) DeleteItem(ctx context.Context, id int64) error {
 		return fmt.Errorf("failed to check item existence: %w", err)
 	}
 
-	err = u.itemRepo.Delete(ctx, id)
+	// 保持期間（retention の deleted_items）を過ぎるまで行を残し、PurgeExpired で完全に削除する
+	err = u.itemRepo.SoftDelete(ctx, id, u.clock.Now())
 	if err != nil {
 		return fmt.Errorf("failed to delete item: %w", err)
 	}
 
+	u.recordAudit(ctx, entity.AuditActionItemDelete, id, reason)
+	u.publish(ctx, entity.EventItemDeleted, item)
+
+	return nil
+}
+
+// 統合・分割で論理削除したものも含め、アイテムを履歴ごと完全に削除する（元に戻せない）
+// 削除されていないアイテムの場合は削除イベントも発行する
+func (u *itemUsecase) PurgeItem(ctx context.Context, id int64, reason string) error {
+	if err := requireAdmin(ctx); err != nil {
+		return err
+	}
+	if id <= 0 {
+		return domainErrors.ErrInvalidInput
+	}
+
+	reason = strings.TrimSpace(reason)
+	if reason == "" && u.reasonPolicy.PolicyFor(ctx).RequiresReasonForDelete() {
+		return domainErrors.ErrReasonRequired
+	}
+
+	live, err := u.itemRepo.FindByID(ctx, id)
+	if err != nil && !domainErrors.IsNotFoundError(err) {
+		return fmt.Errorf("failed to check item existence: %w", err)
+	}
+
+	if err := u.itemRepo.Purge(ctx, id); err != nil {
+		if domainErrors.IsNotFoundError(err) {
+			return domainErrors.ErrItemNotFound
+		}
+		return fmt.Errorf("failed to purge item: %w", err)
+	}
+
+	u.purgeRecords(ctx, id)
+	u.recordAudit(ctx, entity.AuditActionItemPurge, id, reason)
+	if live != nil {
+		u.publish(ctx, entity.EventItemDeleted, live)
+	}
+
 	return nil
 }
 
-func (u *itemUsecase) GetCategorySummary(ctx context.Context) (*CategorySummary, error) {
-	categoryCounts, err := u.itemRepo.GetSummaryByCategory(ctx)
+// cutoff より前に論理削除したアイテムを完全に削除し、削除した件数を返す
+func (u *itemUsecase) PurgeExpired(ctx context.Context, cutoff time.Time) (int64, error) {
+	var ids []int64
+	if len(u.purgedRecords) > 0 {
+		var err error
+		ids, err = u.itemRepo.FindIDsDeletedBefore(ctx, cutoff)
+		if err != nil {
+			return 0, fmt.Errorf("failed to find deleted items: %w", err)
+		}
+	}
+
+	purged, err := u.itemRepo.PurgeDeletedBefore(ctx, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge deleted items: %w", err)
+	}
+	for _, id := range ids {
+		u.purgeRecords(ctx, id)
+	}
+	return purged, nil
+}
+
+// 完全に削除したアイテムの記録を消す。消せなくても削除は成功させ、ログに残す
+func (u *itemUsecase) purgeRecords(ctx context.Context, id int64) {
+	for _, records := range u.purgedRecords {
+		if err := records.PurgeItemRecords(ctx, id); err != nil {
+			reqctx.Logger(ctx).Error("failed to purge records of item", "item_id", id, "error", err)
+		}
+	}
+}
+
+func (u *itemUsecase) GetSummary(ctx context.Context, groupBy string) (*Summary, error) {
+	dim, err := entity.ParseSummaryDimension(groupBy)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrInvalidInput, err.Error())
+	}
+
+	counts, err := retryTransient(ctx, func() (map[string]int, error) {
+		return u.itemRepo.GetSummaryBy(ctx, dim)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to get category summary: %w", err)
+		return nil, fmt.Errorf("failed to get summary: %w", err)
 	}
 
 	// 合計計算
 	total := 0
-	for _, count := range categoryCounts {
+	groups := make(map[string]int, len(counts))
+	for key, count := range counts {
 		total += count
+		groups[key] = count
 	}
 
-	summary := make(map[string]int)
-	for _, category := range entity.GetValidCategories() {
-		if count, exists := categoryCounts[category]; exists {
-			summary[category] = count
-		} else {
-			summary[category] = 0
+	// カテゴリーは件数0のものも含めて返す
+	if dim == entity.DimensionCategory {
+		for _, category := range entity.GetValidCategories() {
+			if _, exists := groups[category]; !exists {
+				groups[category] = 0
+			}
 		}
 	}
 
-	return &CategorySummary{
-		Categories: summary,
-		Total:      total,
+	return &Summary{
+		GroupBy: string(dim),
+		Groups:  groups,
+		Total:   total,
 	}, nil
 }
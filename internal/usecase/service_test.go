@@ -2,7 +2,9 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -10,6 +12,9 @@ import (
 
 	"Aicon-assignment/internal/domain/entity"
 	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/pkg/clock"
+	"Aicon-assignment/internal/pkg/listquery"
+	"Aicon-assignment/internal/pkg/reqctx"
 )
 
 // MockItemRepository はtestify/mockを使用したモックリポジトリ
@@ -17,11 +22,29 @@ type MockItemRepository struct {
 	mock.Mock
 }
 
+func (m *MockItemRepository) FindByQuery(ctx context.Context, query entity.ItemQuery) ([]*entity.Item, error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.Item), args.Error(1)
+}
+
+func (m *MockItemRepository) CountByQuery(ctx context.Context, query entity.ItemQuery) (int, error) {
+	args := m.Called(ctx, query)
+	return args.Int(0), args.Error(1)
+}
+
 func (m *MockItemRepository) FindAll(ctx context.Context) ([]*entity.Item, error) {
 	args := m.Called(ctx)
 	return args.Get(0).([]*entity.Item), args.Error(1)
 }
 
+func (m *MockItemRepository) FindByOrganization(ctx context.Context, orgID int64) ([]*entity.Item, error) {
+	args := m.Called(ctx, orgID)
+	return args.Get(0).([]*entity.Item), args.Error(1)
+}
+
 func (m *MockItemRepository) FindByID(ctx context.Context, id int64) (*entity.Item, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
@@ -30,6 +53,19 @@ func (m *MockItemRepository) FindByID(ctx context.Context, id int64) (*entity.It
 	return args.Get(0).(*entity.Item), args.Error(1)
 }
 
+func (m *MockItemRepository) FindByIDIncludingDeleted(ctx context.Context, id int64) (*entity.Item, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Item), args.Error(1)
+}
+
+func (m *MockItemRepository) AssignOwner(ctx context.Context, id, ownerID int64) error {
+	args := m.Called(ctx, id, ownerID)
+	return args.Error(0)
+}
+
 func (m *MockItemRepository) Create(ctx context.Context, item *entity.Item) (*entity.Item, error) {
 	args := m.Called(ctx, item)
 	if args.Get(0) == nil {
@@ -46,19 +82,68 @@ func (m *MockItemRepository) Update(ctx context.Context, item *entity.Item) (*en
 	return args.Get(0).(*entity.Item), args.Error(1)
 }
 
-func (m *MockItemRepository) Delete(ctx context.Context, id int64) error {
+func (m *MockItemRepository) RecordPriceChange(ctx context.Context, change *entity.PriceChange) error {
+	args := m.Called(ctx, change)
+	return args.Error(0)
+}
+
+func (m *MockItemRepository) FindPriceHistory(ctx context.Context, itemID int64) ([]*entity.PriceChange, error) {
+	args := m.Called(ctx, itemID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.PriceChange), args.Error(1)
+}
+
+func (m *MockItemRepository) MergeInto(ctx context.Context, sourceID, targetID int64, at time.Time) error {
+	args := m.Called(ctx, sourceID, targetID, at)
+	return args.Error(0)
+}
+
+func (m *MockItemRepository) SoftDelete(ctx context.Context, id int64, at time.Time) error {
+	args := m.Called(ctx, id, at)
+	return args.Error(0)
+}
+
+func (m *MockItemRepository) CountDeletedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	args := m.Called(ctx, cutoff)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockItemRepository) FindIDsDeletedBefore(ctx context.Context, cutoff time.Time) ([]int64, error) {
+	args := m.Called(ctx, cutoff)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]int64), args.Error(1)
+}
+
+func (m *MockItemRepository) PurgeDeletedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	args := m.Called(ctx, cutoff)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockItemRepository) Purge(ctx context.Context, id int64) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
-func (m *MockItemRepository) GetSummaryByCategory(ctx context.Context) (map[string]int, error) {
-	args := m.Called(ctx)
+func (m *MockItemRepository) GetSummaryBy(ctx context.Context, dim entity.SummaryDimension) (map[string]int, error) {
+	args := m.Called(ctx, dim)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(map[string]int), args.Error(1)
 }
 
+func (m *MockItemRepository) SummarizeByOrganization(ctx context.Context, orgIDs []int64) (map[int64]*entity.OrganizationItemSummary, error) {
+	args := m.Called(ctx, orgIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[int64]*entity.OrganizationItemSummary), args.Error(1)
+}
+
 func TestNewItemUsecase(t *testing.T) {
 	mockRepo := new(MockItemRepository)
 	usecase := NewItemUsecase(mockRepo)
@@ -66,6 +151,27 @@ func TestNewItemUsecase(t *testing.T) {
 	assert.NotNil(t, usecase)
 }
 
+func TestItemUsecase_WithClock(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	mockRepo := new(MockItemRepository)
+	mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(item *entity.Item) bool {
+		return item.CreatedAt.Equal(now) && item.UpdatedAt.Equal(now)
+	})).Return(&entity.Item{ID: 1, CreatedAt: now, UpdatedAt: now}, nil)
+
+	usecase := NewItemUsecase(mockRepo, WithClock(clock.NewFrozen(now)))
+	item, err := usecase.CreateItem(context.Background(), CreateItemInput{
+		Name:          "ロレックス デイトナ",
+		Category:      "時計",
+		Brand:         "ROLEX",
+		PurchasePrice: 1500000,
+		PurchaseDate:  "2023-01-15",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, now, item.CreatedAt)
+	mockRepo.AssertExpectations(t)
+}
+
 func TestItemUsecase_GetAllItems(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -118,6 +224,189 @@ func TestItemUsecase_GetAllItems(t *testing.T) {
 	}
 }
 
+func TestItemUsecase_ListItems(t *testing.T) {
+	item1, _ := entity.NewItem("時計1", "時計", "ROLEX", 1000000, "2023-01-01")
+	item2, _ := entity.NewItem("時計2", "時計", "OMEGA", 800000, "2023-01-02")
+
+	tests := []struct {
+		name          string
+		query         listquery.Query
+		setupMock     func(*MockItemRepository)
+		expectedCount int
+		expectedTotal int
+	}{
+		{
+			name:  "正常系: ページングなしは件数を数えない",
+			query: listquery.Query{},
+			setupMock: func(mockRepo *MockItemRepository) {
+				mockRepo.On("FindByQuery", mock.Anything, entity.ItemQuery{}).Return([]*entity.Item{item1, item2}, nil)
+			},
+			expectedCount: 2,
+			expectedTotal: 2,
+		},
+		{
+			name:  "正常系: ページングありは総件数を別に数える",
+			query: listquery.Query{Page: listquery.Page{Number: 2, Size: 1}},
+			setupMock: func(mockRepo *MockItemRepository) {
+				query := entity.ItemQuery{Limit: 1, Offset: 1}
+				mockRepo.On("FindByQuery", mock.Anything, query).Return([]*entity.Item{item2}, nil)
+				mockRepo.On("CountByQuery", mock.Anything, query).Return(2, nil)
+			},
+			expectedCount: 1,
+			expectedTotal: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockItemRepository)
+			tt.setupMock(mockRepo)
+			usecase := NewItemUsecase(mockRepo)
+
+			result, err := usecase.ListItems(context.Background(), tt.query)
+
+			require.NoError(t, err)
+			assert.Len(t, result.Items, tt.expectedCount)
+			assert.Equal(t, tt.expectedTotal, result.Total)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestItemUsecase_ListItems_PrimaryImages(t *testing.T) {
+	t.Run("正常系: 一覧に表示する画像をまとめて読み込む", func(t *testing.T) {
+		mockRepo := new(MockItemRepository)
+		mockRepo.On("FindByQuery", mock.Anything, entity.ItemQuery{}).Return([]*entity.Item{{ID: 1}, {ID: 2}}, nil)
+		images := new(MockImageRepository)
+		images.On("FindPrimaryByItemIDs", mock.Anything, []int64{1, 2}).Return([]*entity.ItemImage{{ID: 5, ItemID: 2, IsPrimary: true}}, nil)
+
+		loader := NewImageUsecase(images, mockRepo, nil, nil, nil, 0, clock.NewFrozen(time.Now()))
+		result, err := NewItemUsecase(mockRepo, WithImages(loader)).ListItems(context.Background(), listquery.Query{})
+		require.NoError(t, err)
+		assert.Nil(t, result.Items[0].PrimaryImage)
+		require.NotNil(t, result.Items[1].PrimaryImage)
+		assert.Equal(t, int64(5), result.Items[1].PrimaryImage.ID)
+		images.AssertNumberOfCalls(t, "FindPrimaryByItemIDs", 1)
+	})
+}
+
+// テスト用の ItemSearcher
+type searcherFunc func(ctx context.Context, search entity.ItemSearch) ([]*entity.Item, error)
+
+func (f searcherFunc) Search(ctx context.Context, search entity.ItemSearch) ([]*entity.Item, error) {
+	return f(ctx, search)
+}
+
+func TestItemUsecase_SearchItems(t *testing.T) {
+	item, _ := entity.NewItem("デイトナ", "時計", "ROLEX", 1000000, "2023-01-01")
+
+	t.Run("正常系: キーワードの前後の空白を除き、既定の件数で検索する", func(t *testing.T) {
+		var got entity.ItemSearch
+		searcher := searcherFunc(func(ctx context.Context, search entity.ItemSearch) ([]*entity.Item, error) {
+			got = search
+			return []*entity.Item{item}, nil
+		})
+
+		items, err := NewItemUsecase(new(MockItemRepository), WithSearcher(searcher)).SearchItems(context.Background(), "  デイトナ ", 0)
+
+		require.NoError(t, err)
+		assert.Equal(t, []*entity.Item{item}, items)
+		assert.Equal(t, entity.ItemSearch{Keyword: "デイトナ", Limit: entity.DefaultSearchLimit}, got)
+	})
+
+	t.Run("異常系: キーワードが空", func(t *testing.T) {
+		_, err := NewItemUsecase(new(MockItemRepository)).SearchItems(context.Background(), " ", 0)
+		assert.True(t, domainErrors.IsValidationError(err))
+	})
+
+	t.Run("異常系: limit が上限を超える", func(t *testing.T) {
+		_, err := NewItemUsecase(new(MockItemRepository)).SearchItems(context.Background(), "ROLEX", entity.MaxSearchLimit+1)
+		assert.True(t, domainErrors.IsValidationError(err))
+	})
+}
+
+func TestItemUsecase_ListItems_Cursor(t *testing.T) {
+	item1, _ := entity.NewItem("時計1", "時計", "ROLEX", 1000000, "2023-01-01")
+	item2, _ := entity.NewItem("時計2", "時計", "OMEGA", 800000, "2023-01-02")
+	item1.ID, item2.ID = 1, 2
+
+	t.Run("正常系: 1件多く取得し、続きがあれば次のカーソルを返す", func(t *testing.T) {
+		mockRepo := new(MockItemRepository)
+		query := entity.ItemQuery{Sort: entity.ItemCursorSort, Limit: 2}
+		mockRepo.On("FindByQuery", mock.Anything, query).Return([]*entity.Item{item2, item1}, nil)
+
+		result, err := NewItemUsecase(mockRepo).ListItems(context.Background(),
+			listquery.Query{Page: listquery.Page{Size: 1, CursorMode: true}})
+
+		require.NoError(t, err)
+		assert.Equal(t, []*entity.Item{item2}, result.Items)
+		assert.Equal(t, entity.NewItemCursor(item2).Encode(), result.NextCursor)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("正常系: 最後のページは次のカーソルを返さない", func(t *testing.T) {
+		mockRepo := new(MockItemRepository)
+		cursor := entity.NewItemCursor(item2)
+		decoded, _ := entity.DecodeItemCursor(cursor.Encode())
+		query := entity.ItemQuery{Sort: entity.ItemCursorSort, Limit: 2, After: decoded}
+		mockRepo.On("FindByQuery", mock.Anything, query).Return([]*entity.Item{item1}, nil)
+
+		result, err := NewItemUsecase(mockRepo).ListItems(context.Background(),
+			listquery.Query{Page: listquery.Page{Size: 1, Cursor: cursor.Encode(), CursorMode: true}})
+
+		require.NoError(t, err)
+		assert.Equal(t, []*entity.Item{item1}, result.Items)
+		assert.Empty(t, result.NextCursor)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("異常系: 不正なカーソル", func(t *testing.T) {
+		mockRepo := new(MockItemRepository)
+
+		_, err := NewItemUsecase(mockRepo).ListItems(context.Background(),
+			listquery.Query{Page: listquery.Page{Size: 1, Cursor: "!!!", CursorMode: true}})
+
+		assert.True(t, domainErrors.IsValidationError(err))
+		mockRepo.AssertNotCalled(t, "FindByQuery", mock.Anything, mock.Anything)
+	})
+}
+
+func TestItemUsecase_EachItem(t *testing.T) {
+	t.Run("正常系: 1バッチ分そろっている間はカーソルで続きを読む", func(t *testing.T) {
+		full := make([]*entity.Item, itemBatchSize)
+		for i := range full {
+			full[i] = &entity.Item{ID: int64(itemBatchSize - i)}
+		}
+		last := &entity.Item{ID: 1000}
+
+		mockRepo := new(MockItemRepository)
+		mockRepo.On("FindByQuery", mock.Anything, entity.ItemQuery{Sort: entity.ItemCursorSort, Limit: itemBatchSize}).Return(full, nil)
+		mockRepo.On("FindByQuery", mock.Anything, entity.ItemQuery{
+			Sort: entity.ItemCursorSort, Limit: itemBatchSize, After: entity.NewItemCursor(full[itemBatchSize-1]),
+		}).Return([]*entity.Item{last}, nil)
+
+		var batches [][]*entity.Item
+		err := NewItemUsecase(mockRepo).EachItem(context.Background(), listquery.Query{}, func(items []*entity.Item) error {
+			batches = append(batches, items)
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, [][]*entity.Item{full, {last}}, batches)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("異常系: fn のエラーで読み込みを止める", func(t *testing.T) {
+		mockRepo := new(MockItemRepository)
+		mockRepo.On("FindByQuery", mock.Anything, mock.Anything).Return([]*entity.Item{{ID: 1}}, nil)
+
+		stop := errors.New("client gone")
+		err := NewItemUsecase(mockRepo).EachItem(context.Background(), listquery.Query{}, func(items []*entity.Item) error {
+			return stop
+		})
+		assert.ErrorIs(t, err, stop)
+	})
+}
+
 func TestItemUsecase_GetItemByID(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -162,6 +451,26 @@ func TestItemUsecase_GetItemByID(t *testing.T) {
 			},
 			expectError: true,
 		},
+		{
+			name: "正常系: 一時的なエラーは再試行される",
+			id:   1,
+			setupMock: func(mockRepo *MockItemRepository) {
+				item, _ := entity.NewItem("時計1", "時計", "ROLEX", 1000000, "2023-01-01")
+				item.ID = 1
+				mockRepo.On("FindByID", mock.Anything, int64(1)).Return((*entity.Item)(nil), domainErrors.ErrConnectionLost).Once()
+				mockRepo.On("FindByID", mock.Anything, int64(1)).Return(item, nil).Once()
+			},
+			expectError: false,
+		},
+		{
+			name: "異常系: 一時的なエラーが続く場合は諦める",
+			id:   1,
+			setupMock: func(mockRepo *MockItemRepository) {
+				mockRepo.On("FindByID", mock.Anything, int64(1)).Return((*entity.Item)(nil), domainErrors.ErrSerializationFailure).Times(3)
+			},
+			expectError: true,
+			expectedErr: domainErrors.ErrSerializationFailure,
+		},
 	}
 
 	for _, tt := range tests {
@@ -188,6 +497,21 @@ func TestItemUsecase_GetItemByID(t *testing.T) {
 			mockRepo.AssertExpectations(t)
 		})
 	}
+
+	t.Run("正常系: 画像も読み込む", func(t *testing.T) {
+		mockRepo := new(MockItemRepository)
+		mockRepo.On("FindByID", mock.Anything, int64(1)).Return(&entity.Item{ID: 1}, nil)
+		images := new(MockImageRepository)
+		images.On("FindByItemID", mock.Anything, int64(1)).Return([]*entity.ItemImage{{ID: 5, ItemID: 1, Filename: "front.png", IsPrimary: true}}, nil)
+
+		loader := NewImageUsecase(images, mockRepo, nil, nil, nil, 0, clock.NewFrozen(time.Now()))
+		item, err := NewItemUsecase(mockRepo, WithImages(loader)).GetItemByID(context.Background(), 1)
+		require.NoError(t, err)
+		require.Len(t, item.Images, 1)
+		assert.Equal(t, "front.png", item.Images[0].Filename)
+		require.NotNil(t, item.PrimaryImage)
+		assert.Equal(t, int64(5), item.PrimaryImage.ID)
+	})
 }
 
 func TestItemUsecase_CreateItem(t *testing.T) {
@@ -305,7 +629,7 @@ func TestItemUsecase_DeleteItem(t *testing.T) {
 				item, _ := entity.NewItem("時計1", "時計", "ROLEX", 1000000, "2023-01-01")
 				item.ID = 1
 				mockRepo.On("FindByID", mock.Anything, int64(1)).Return(item, nil)
-				mockRepo.On("Delete", mock.Anything, int64(1)).Return(nil)
+				mockRepo.On("SoftDelete", mock.Anything, int64(1), mock.Anything).Return(nil)
 			},
 			expectError: false,
 		},
@@ -336,13 +660,13 @@ func TestItemUsecase_DeleteItem(t *testing.T) {
 			expectError: true,
 		},
 		{
-			name: "異常系: Deleteでデータベースエラー",
+			name: "異常系: SoftDeleteでデータベースエラー",
 			id:   1,
 			setupMock: func(mockRepo *MockItemRepository) {
 				item, _ := entity.NewItem("時計1", "時計", "ROLEX", 1000000, "2023-01-01")
 				item.ID = 1
 				mockRepo.On("FindByID", mock.Anything, int64(1)).Return(item, nil)
-				mockRepo.On("Delete", mock.Anything, int64(1)).Return(domainErrors.ErrDatabaseError)
+				mockRepo.On("SoftDelete", mock.Anything, int64(1), mock.Anything).Return(domainErrors.ErrDatabaseError)
 			},
 			expectError: true,
 		},
@@ -355,7 +679,7 @@ func TestItemUsecase_DeleteItem(t *testing.T) {
 			usecase := NewItemUsecase(mockRepo)
 
 			ctx := context.Background()
-			err := usecase.DeleteItem(ctx, tt.id)
+			err := usecase.DeleteItem(ctx, tt.id, "")
 
 			if tt.expectError {
 				assert.Error(t, err)
@@ -396,6 +720,49 @@ func TestItemUsecase_UpdateItem(t *testing.T) {
 				updatedItem, _ := entity.NewItem("新しい名前", "時計", "新しいブランド", 1500000, "2023-01-01")
 				updatedItem.ID = 1
 				mockRepo.On("Update", mock.Anything, mock.AnythingOfType("*entity.Item")).Return(updatedItem, nil)
+				mockRepo.On("RecordPriceChange", mock.Anything, mock.MatchedBy(func(change *entity.PriceChange) bool {
+					return change.ItemID == 1 && change.OldPrice == 1000000 && change.NewPrice == 1500000
+				})).Return(nil)
+			},
+			expectError: false,
+		},
+		{
+			name: "正常系: 価格変更と理由が履歴に記録される",
+			id:   1,
+			input: UpdateItemInput{
+				PurchasePrice: intPtr(900000),
+				Reason:        strPtr("領収書の金額に訂正"),
+			},
+			setupMock: func(mockRepo *MockItemRepository) {
+				existingItem, _ := entity.NewItem("時計1", "時計", "ROLEX", 1000000, "2023-01-01")
+				existingItem.ID = 1
+				mockRepo.On("FindByID", mock.Anything, int64(1)).Return(existingItem, nil)
+
+				updatedItem, _ := entity.NewItem("時計1", "時計", "ROLEX", 900000, "2023-01-01")
+				updatedItem.ID = 1
+				mockRepo.On("Update", mock.Anything, mock.AnythingOfType("*entity.Item")).Return(updatedItem, nil)
+				mockRepo.On("RecordPriceChange", mock.Anything, mock.MatchedBy(func(change *entity.PriceChange) bool {
+					return change.OldPrice == 1000000 && change.NewPrice == 900000 &&
+						change.Reason == "領収書の金額に訂正" && change.Actor == "anonymous"
+				})).Return(nil)
+			},
+			expectError: false,
+		},
+		{
+			name: "正常系: 価格が変わらない場合は履歴を記録しない",
+			id:   1,
+			input: UpdateItemInput{
+				Name:          strPtr("新しい名前"),
+				PurchasePrice: intPtr(1000000),
+			},
+			setupMock: func(mockRepo *MockItemRepository) {
+				existingItem, _ := entity.NewItem("時計1", "時計", "ROLEX", 1000000, "2023-01-01")
+				existingItem.ID = 1
+				mockRepo.On("FindByID", mock.Anything, int64(1)).Return(existingItem, nil)
+
+				updatedItem, _ := entity.NewItem("新しい名前", "時計", "ROLEX", 1000000, "2023-01-01")
+				updatedItem.ID = 1
+				mockRepo.On("Update", mock.Anything, mock.AnythingOfType("*entity.Item")).Return(updatedItem, nil)
 			},
 			expectError: false,
 		},
@@ -445,6 +812,25 @@ func TestItemUsecase_UpdateItem(t *testing.T) {
 			},
 			expectError: true,
 		},
+		{
+			name: "異常系: 価格変更履歴の記録に失敗した場合は更新を取り消す",
+			id:   1,
+			input: UpdateItemInput{
+				PurchasePrice: intPtr(1500000),
+			},
+			setupMock: func(mockRepo *MockItemRepository) {
+				existingItem, _ := entity.NewItem("時計1", "時計", "ROLEX", 1000000, "2023-01-01")
+				existingItem.ID = 1
+				mockRepo.On("FindByID", mock.Anything, int64(1)).Return(existingItem, nil)
+
+				updatedItem, _ := entity.NewItem("時計1", "時計", "ROLEX", 1500000, "2023-01-01")
+				updatedItem.ID = 1
+				mockRepo.On("Update", mock.Anything, mock.AnythingOfType("*entity.Item")).Return(updatedItem, nil)
+				mockRepo.On("RecordPriceChange", mock.Anything, mock.AnythingOfType("*entity.PriceChange")).Return(domainErrors.ErrDatabaseError)
+			},
+			expectError: true,
+			expectedErr: domainErrors.ErrDatabaseError,
+		},
 		{
 			name: "異常系: データベースエラー（Update）",
 			id:   1,
@@ -465,7 +851,8 @@ func TestItemUsecase_UpdateItem(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := new(MockItemRepository)
 			tt.setupMock(mockRepo)
-			usecase := NewItemUsecase(mockRepo)
+			tx := &fakeTransactor{}
+			usecase := NewItemUsecase(mockRepo, WithTransactor(tx))
 
 			ctx := context.Background()
 			item, err := usecase.UpdateItem(ctx, tt.id, tt.input)
@@ -476,10 +863,12 @@ func TestItemUsecase_UpdateItem(t *testing.T) {
 					assert.ErrorIs(t, err, tt.expectedErr)
 				}
 				assert.Nil(t, item)
+				assert.False(t, tx.committed)
 			} else {
 				assert.NoError(t, err)
 				assert.NotNil(t, item)
 				assert.Equal(t, tt.id, item.ID)
+				assert.True(t, tx.committed)
 			}
 
 			mockRepo.AssertExpectations(t)
@@ -487,39 +876,65 @@ func TestItemUsecase_UpdateItem(t *testing.T) {
 	}
 }
 
-func TestItemUsecase_GetCategorySummary(t *testing.T) {
+func TestItemUsecase_GetSummary(t *testing.T) {
 	tests := []struct {
-		name               string
-		setupMock          func(*MockItemRepository)
-		expectedTotal      int
-		expectedWatchCount int
-		expectedBagCount   int
-		expectError        bool
+		name           string
+		groupBy        string
+		setupMock      func(*MockItemRepository)
+		expectedTotal  int
+		expectedGroups map[string]int
+		expectedErr    error
 	}{
 		{
-			name: "正常系: 複数カテゴリーのアイテムがある場合",
+			name:    "正常系: 複数カテゴリーのアイテムがある場合",
+			groupBy: "",
 			setupMock: func(mockRepo *MockItemRepository) {
 				summary := map[string]int{
 					"時計":  2,
 					"バッグ": 1,
 				}
-				mockRepo.On("GetSummaryByCategory", mock.Anything).Return(summary, nil)
+				mockRepo.On("GetSummaryBy", mock.Anything, entity.DimensionCategory).Return(summary, nil)
+			},
+			expectedTotal: 3,
+			// すべてのカテゴリーが件数0も含めて返る
+			expectedGroups: map[string]int{"時計": 2, "バッグ": 1, "ジュエリー": 0, "靴": 0, "その他": 0},
+		},
+		{
+			name:    "正常系: アイテムが0件の場合",
+			groupBy: "category",
+			setupMock: func(mockRepo *MockItemRepository) {
+				mockRepo.On("GetSummaryBy", mock.Anything, entity.DimensionCategory).Return(map[string]int{}, nil)
 			},
-			expectedTotal:      3,
-			expectedWatchCount: 2,
-			expectedBagCount:   1,
-			expectError:        false,
+			expectedTotal:  0,
+			expectedGroups: map[string]int{"時計": 0, "バッグ": 0, "ジュエリー": 0, "靴": 0, "その他": 0},
 		},
 		{
-			name: "正常系: アイテムが0件の場合",
+			name:    "正常系: ブランド別",
+			groupBy: "brand",
 			setupMock: func(mockRepo *MockItemRepository) {
-				summary := map[string]int{}
-				mockRepo.On("GetSummaryByCategory", mock.Anything).Return(summary, nil)
+				summary := map[string]int{"ROLEX": 2, "HERMÈS": 1}
+				mockRepo.On("GetSummaryBy", mock.Anything, entity.DimensionBrand).Return(summary, nil)
 			},
-			expectedTotal:      0,
-			expectedWatchCount: 0,
-			expectedBagCount:   0,
-			expectError:        false,
+			expectedTotal:  3,
+			expectedGroups: map[string]int{"ROLEX": 2, "HERMÈS": 1},
+		},
+		{
+			name:    "正常系: 購入年別",
+			groupBy: "year",
+			setupMock: func(mockRepo *MockItemRepository) {
+				summary := map[string]int{"2023": 4, "2024": 1}
+				mockRepo.On("GetSummaryBy", mock.Anything, entity.DimensionYear).Return(summary, nil)
+			},
+			expectedTotal:  5,
+			expectedGroups: map[string]int{"2023": 4, "2024": 1},
+		},
+		{
+			name:    "異常系: 許可されていない集計軸",
+			groupBy: "name; DROP TABLE items",
+			setupMock: func(mockRepo *MockItemRepository) {
+				// GetSummaryByは呼ばれない
+			},
+			expectedErr: domainErrors.ErrInvalidInput,
 		},
 	}
 
@@ -530,10 +945,10 @@ func TestItemUsecase_GetCategorySummary(t *testing.T) {
 			usecase := NewItemUsecase(mockRepo)
 
 			ctx := context.Background()
-			summary, err := usecase.GetCategorySummary(ctx)
+			summary, err := usecase.GetSummary(ctx, tt.groupBy)
 
-			if tt.expectError {
-				assert.Error(t, err)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
 				assert.Nil(t, summary)
 				mockRepo.AssertExpectations(t)
 				return
@@ -543,20 +958,113 @@ func TestItemUsecase_GetCategorySummary(t *testing.T) {
 			require.NotNil(t, summary)
 
 			assert.Equal(t, tt.expectedTotal, summary.Total)
-			assert.Equal(t, tt.expectedWatchCount, summary.Categories["時計"])
-			assert.Equal(t, tt.expectedBagCount, summary.Categories["バッグ"])
-
-			// すべてのカテゴリーがレスポンスに含まれているかチェック
-			expectedCategories := []string{"時計", "バッグ", "ジュエリー", "靴", "その他"}
-			for _, category := range expectedCategories {
-				assert.Contains(t, summary.Categories, category)
-			}
+			assert.Equal(t, tt.expectedGroups, summary.Groups)
 
 			mockRepo.AssertExpectations(t)
 		})
 	}
 }
 
+func TestItemUsecase_ReasonPolicy(t *testing.T) {
+	policy := StaticReasonPolicy{
+		Policy: entity.ReasonPolicy{RequireOnDelete: true, HighValueThreshold: 1000000},
+	}
+
+	t.Run("異常系: 理由なしの削除は拒否される", func(t *testing.T) {
+		mockRepo := new(MockItemRepository)
+		usecase := NewItemUsecase(mockRepo, WithReasonPolicy(policy))
+
+		err := usecase.DeleteItem(context.Background(), 1, "  ")
+		assert.ErrorIs(t, err, domainErrors.ErrReasonRequired)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("正常系: 理由付きの削除は監査ログに記録される", func(t *testing.T) {
+		mockRepo := new(MockItemRepository)
+		item, _ := entity.NewItem("時計1", "時計", "ROLEX", 1000000, "2023-01-01")
+		item.ID = 1
+		mockRepo.On("FindByID", mock.Anything, int64(1)).Return(item, nil)
+		mockRepo.On("SoftDelete", mock.Anything, int64(1), mock.Anything).Return(nil)
+		auditLog := new(MockAuditLogRepository)
+		auditLog.On("Record", mock.Anything, mock.MatchedBy(func(entry *entity.AuditEntry) bool {
+			return entry.Action == entity.AuditActionItemDelete && entry.ItemID == 1 && entry.Reason == "売却済み"
+		})).Return(nil)
+
+		usecase := NewItemUsecase(mockRepo, WithReasonPolicy(policy), WithAuditLog(auditLog))
+
+		err := usecase.DeleteItem(context.Background(), 1, "売却済み")
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+		auditLog.AssertExpectations(t)
+	})
+
+	t.Run("正常系: なりすまし中の操作は両方のユーザーが監査ログに記録される", func(t *testing.T) {
+		mockRepo := new(MockItemRepository)
+		item, _ := entity.NewItem("時計1", "時計", "ROLEX", 1000000, "2023-01-01")
+		item.ID = 1
+		mockRepo.On("FindByID", mock.Anything, int64(1)).Return(item, nil)
+		mockRepo.On("SoftDelete", mock.Anything, int64(1), mock.Anything).Return(nil)
+		auditLog := new(MockAuditLogRepository)
+		auditLog.On("Record", mock.Anything, mock.MatchedBy(func(entry *entity.AuditEntry) bool {
+			return entry.Actor == "user:2" && entry.Impersonator == "user:1"
+		})).Return(nil)
+
+		usecase := NewItemUsecase(mockRepo, WithReasonPolicy(policy), WithAuditLog(auditLog))
+
+		ctx := reqctx.WithImpersonatorID(reqctx.WithUserID(context.Background(), 2), 1)
+		err := usecase.DeleteItem(ctx, 1, "売却済み")
+		assert.NoError(t, err)
+		auditLog.AssertExpectations(t)
+	})
+
+	t.Run("異常系: 高額アイテムの理由なし更新は拒否される", func(t *testing.T) {
+		mockRepo := new(MockItemRepository)
+		item, _ := entity.NewItem("時計1", "時計", "ROLEX", 1500000, "2023-01-01")
+		item.ID = 1
+		mockRepo.On("FindByID", mock.Anything, int64(1)).Return(item, nil)
+		usecase := NewItemUsecase(mockRepo, WithReasonPolicy(policy))
+
+		_, err := usecase.UpdateItem(context.Background(), 1, UpdateItemInput{Name: strPtr("新しい名前")})
+		assert.ErrorIs(t, err, domainErrors.ErrReasonRequired)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("正常系: 対象外の組織にはポリシーを適用しない", func(t *testing.T) {
+		scoped := StaticReasonPolicy{Policy: policy.Policy, Orgs: []int64{1}}
+		ctx := reqctx.WithOrgID(context.Background(), 2)
+		assert.False(t, scoped.PolicyFor(ctx).RequiresReasonForDelete())
+		assert.True(t, scoped.PolicyFor(reqctx.WithOrgID(context.Background(), 1)).RequiresReasonForDelete())
+	})
+}
+
+// MockAuditLogRepository はテスト用の監査ログリポジトリ
+type MockAuditLogRepository struct {
+	mock.Mock
+}
+
+func (m *MockAuditLogRepository) Record(ctx context.Context, entry *entity.AuditEntry) error {
+	args := m.Called(ctx, entry)
+	return args.Error(0)
+}
+
+func (m *MockAuditLogRepository) FindByItemID(ctx context.Context, itemID int64) ([]*entity.AuditEntry, error) {
+	args := m.Called(ctx, itemID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.AuditEntry), args.Error(1)
+}
+
+func (m *MockAuditLogRepository) CountBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	args := m.Called(ctx, cutoff)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockAuditLogRepository) DeleteBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	args := m.Called(ctx, cutoff)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 // ヘルパー関数
 func strPtr(s string) *string {
 	return &s
@@ -565,3 +1073,296 @@ func strPtr(s string) *string {
 func intPtr(i int) *int {
 	return &i
 }
+
+func TestItemUsecase_GetOutlierReport(t *testing.T) {
+	t.Run("正常系: 指定したカテゴリーだけを調べる", func(t *testing.T) {
+		items := []*entity.Item{{ID: 1, Name: "バーキン", Category: "バッグ", PurchasePrice: 2000000, PurchaseDate: "2023-01-01"}}
+		for i := int64(2); i <= 6; i++ {
+			items = append(items, &entity.Item{ID: i, Name: "デイトナ", Category: "時計", PurchasePrice: int(i) * 100000, PurchaseDate: "2023-01-01"})
+		}
+		items[5].PurchasePrice = 90000000
+
+		mockRepo := new(MockItemRepository)
+		mockRepo.On("FindAll", mock.Anything).Return(items, nil)
+
+		report, err := NewItemUsecase(mockRepo).GetOutlierReport(context.Background(), "時計")
+		require.NoError(t, err)
+		assert.Equal(t, "時計", report.Category)
+		require.Len(t, report.Outliers, 1)
+		assert.Equal(t, int64(6), report.Outliers[0].Item.ID)
+	})
+
+	t.Run("異常系: 不正なカテゴリー", func(t *testing.T) {
+		_, err := NewItemUsecase(new(MockItemRepository)).GetOutlierReport(context.Background(), "家電")
+		assert.ErrorIs(t, err, domainErrors.ErrInvalidInput)
+	})
+}
+
+func TestItemUsecase_SummarizeByOrganization(t *testing.T) {
+	page := listquery.Query{Page: listquery.Page{Number: 2, Size: 2}}
+
+	t.Run("正常系: ページの組織ごとに集計し、アイテムのない組織は0件にする", func(t *testing.T) {
+		orgs := new(MockOrganizationRepository)
+		orgs.On("FindPage", mock.Anything, 2, 2).Return([]*entity.Organization{{ID: 3, Name: "Acme"}, {ID: 4, Name: "Globex"}}, nil)
+		orgs.On("Count", mock.Anything).Return(5, nil)
+		mockRepo := new(MockItemRepository)
+		mockRepo.On("SummarizeByOrganization", mock.Anything, []int64{3, 4}).Return(map[int64]*entity.OrganizationItemSummary{
+			3: {OrgID: 3, ItemCount: 2, TotalValue: 3500000},
+		}, nil)
+
+		result, err := NewItemUsecase(mockRepo, WithOrganizations(orgs)).SummarizeByOrganization(context.Background(), page)
+		require.NoError(t, err)
+		assert.Equal(t, 5, result.Total)
+		assert.Equal(t, []*entity.OrganizationItemSummary{
+			{OrgID: 3, Name: "Acme", ItemCount: 2, TotalValue: 3500000},
+			{OrgID: 4, Name: "Globex"},
+		}, result.Items)
+	})
+
+	t.Run("異常系: カーソルによるページングには対応しない", func(t *testing.T) {
+		q := listquery.Query{Page: listquery.Page{Size: 20, CursorMode: true}}
+		_, err := NewItemUsecase(new(MockItemRepository), WithOrganizations(new(MockOrganizationRepository))).SummarizeByOrganization(context.Background(), q)
+		assert.ErrorIs(t, err, domainErrors.ErrInvalidInput)
+	})
+}
+
+func TestItemUsecase_CreateItemInOrganization(t *testing.T) {
+	input := func(orgID int64) CreateItemInput {
+		return CreateItemInput{Name: "バーキン", Category: "バッグ", Brand: "HERMES", PurchasePrice: 2000000, PurchaseDate: "2023-01-01", OrgID: &orgID}
+	}
+
+	asMember := reqctx.WithUserRole(reqctx.WithUserID(context.Background(), 2), entity.UserRoleMember)
+
+	t.Run("正常系: 組織を設定して作成する", func(t *testing.T) {
+		orgs := new(MockOrganizationRepository)
+		orgs.On("FindByID", mock.Anything, int64(3)).Return(&entity.Organization{ID: 3}, nil)
+		orgs.On("FindMember", mock.Anything, int64(3), int64(2)).Return(&entity.Membership{OrgID: 3, UserID: 2}, nil)
+		mockRepo := new(MockItemRepository)
+		mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(item *entity.Item) bool {
+			return item.OrgID != nil && *item.OrgID == 3
+		})).Return(&entity.Item{ID: 1}, nil)
+
+		_, err := NewItemUsecase(mockRepo, WithOrganizations(orgs)).CreateItem(asMember, input(3))
+		require.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("正常系: 管理者はメンバーでない組織も設定できる", func(t *testing.T) {
+		orgs := new(MockOrganizationRepository)
+		orgs.On("FindByID", mock.Anything, int64(3)).Return(&entity.Organization{ID: 3}, nil)
+		mockRepo := new(MockItemRepository)
+		mockRepo.On("Create", mock.Anything, mock.Anything).Return(&entity.Item{ID: 1}, nil)
+		asAdmin := reqctx.WithUserRole(reqctx.WithUserID(context.Background(), 1), entity.UserRoleAdmin)
+
+		_, err := NewItemUsecase(mockRepo, WithOrganizations(orgs)).CreateItem(asAdmin, input(3))
+		require.NoError(t, err)
+		orgs.AssertNotCalled(t, "FindMember", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("異常系: メンバーでない組織", func(t *testing.T) {
+		orgs := new(MockOrganizationRepository)
+		orgs.On("FindByID", mock.Anything, int64(3)).Return(&entity.Organization{ID: 3}, nil)
+		orgs.On("FindMember", mock.Anything, int64(3), int64(2)).Return(nil, domainErrors.ErrMemberNotFound)
+		mockRepo := new(MockItemRepository)
+
+		_, err := NewItemUsecase(mockRepo, WithOrganizations(orgs)).CreateItem(asMember, input(3))
+		assert.ErrorIs(t, err, domainErrors.ErrInvalidInput)
+		assert.ErrorContains(t, err, "not a member of organization 3")
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("異常系: 呼び出し元の分からないリクエスト", func(t *testing.T) {
+		orgs := new(MockOrganizationRepository)
+		orgs.On("FindByID", mock.Anything, int64(3)).Return(&entity.Organization{ID: 3}, nil)
+		mockRepo := new(MockItemRepository)
+
+		_, err := NewItemUsecase(mockRepo, WithOrganizations(orgs)).CreateItem(context.Background(), input(3))
+		assert.ErrorIs(t, err, domainErrors.ErrInvalidInput)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("異常系: 存在しない組織", func(t *testing.T) {
+		orgs := new(MockOrganizationRepository)
+		orgs.On("FindByID", mock.Anything, int64(9)).Return(nil, domainErrors.ErrOrganizationNotFound)
+		mockRepo := new(MockItemRepository)
+
+		_, err := NewItemUsecase(mockRepo, WithOrganizations(orgs)).CreateItem(asMember, input(9))
+		assert.ErrorIs(t, err, domainErrors.ErrInvalidInput)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
+// recordingPublisher は発行されたイベントを記録する
+type recordingPublisher struct {
+	events []*entity.Event
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, event *entity.Event) {
+	p.events = append(p.events, event)
+}
+
+func TestItemUsecase_StrictPriceAnomaly(t *testing.T) {
+	input := CreateItemInput{Name: "デイトナ", Category: "時計", Brand: "ROLEX", PurchasePrice: 150, PurchaseDate: "2023-01-15"}
+	peers := []*entity.Item{{ID: 1, PurchasePrice: 1500000}, {ID: 2, PurchasePrice: 1600000}, {ID: 3, PurchasePrice: 1400000}}
+	strict := reqctx.WithStrict(context.Background(), true)
+
+	t.Run("異常系: strict モードでは相場から外れた価格を保存しない", func(t *testing.T) {
+		mockRepo := new(MockItemRepository)
+		mockRepo.On("FindByQuery", mock.Anything, mock.Anything).Return(peers, nil)
+
+		_, err := NewItemUsecase(mockRepo).CreateItem(strict, input)
+		assert.ErrorIs(t, err, domainErrors.ErrInvalidInput)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("正常系: confirm_price で確認済みなら strict モードでも保存する", func(t *testing.T) {
+		mockRepo := new(MockItemRepository)
+		mockRepo.On("Create", mock.Anything, mock.Anything).Return(&entity.Item{ID: 4}, nil)
+		confirmed := input
+		confirmed.ConfirmPrice = true
+
+		_, err := NewItemUsecase(mockRepo).CreateItem(strict, confirmed)
+		require.NoError(t, err)
+		mockRepo.AssertNotCalled(t, "FindByQuery", mock.Anything, mock.Anything)
+	})
+
+	t.Run("正常系: strict モードでなければ判定せずに保存する", func(t *testing.T) {
+		mockRepo := new(MockItemRepository)
+		mockRepo.On("Create", mock.Anything, mock.Anything).Return(&entity.Item{ID: 4}, nil)
+
+		_, err := NewItemUsecase(mockRepo).CreateItem(context.Background(), input)
+		require.NoError(t, err)
+		mockRepo.AssertNotCalled(t, "FindByQuery", mock.Anything, mock.Anything)
+	})
+}
+
+func TestItemUsecase_PurgeItem(t *testing.T) {
+	asAdmin := reqctx.WithUserRole(reqctx.WithUserID(context.Background(), 1), entity.UserRoleAdmin)
+
+	t.Run("正常系: 削除されていないアイテムは削除イベントを発行する", func(t *testing.T) {
+		item := &entity.Item{ID: 1, Name: "バーキン"}
+		mockRepo := new(MockItemRepository)
+		mockRepo.On("FindByID", mock.Anything, int64(1)).Return(item, nil)
+		mockRepo.On("Purge", mock.Anything, int64(1)).Return(nil)
+		events := &recordingPublisher{}
+
+		err := NewItemUsecase(mockRepo, WithEventPublisher(events)).PurgeItem(asAdmin, 1, "")
+		require.NoError(t, err)
+		require.Len(t, events.events, 1)
+		assert.Equal(t, entity.EventItemDeleted, events.events[0].Type)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("正常系: 論理削除したアイテムはイベントを発行せずに削除する", func(t *testing.T) {
+		mockRepo := new(MockItemRepository)
+		mockRepo.On("FindByID", mock.Anything, int64(2)).Return(nil, domainErrors.ErrItemNotFound)
+		mockRepo.On("Purge", mock.Anything, int64(2)).Return(nil)
+		events := &recordingPublisher{}
+
+		err := NewItemUsecase(mockRepo, WithEventPublisher(events)).PurgeItem(asAdmin, 2, "")
+		require.NoError(t, err)
+		assert.Empty(t, events.events)
+	})
+
+	t.Run("異常系: 存在しないアイテム", func(t *testing.T) {
+		mockRepo := new(MockItemRepository)
+		mockRepo.On("FindByID", mock.Anything, int64(3)).Return(nil, domainErrors.ErrItemNotFound)
+		mockRepo.On("Purge", mock.Anything, int64(3)).Return(domainErrors.ErrItemNotFound)
+
+		err := NewItemUsecase(mockRepo).PurgeItem(asAdmin, 3, "")
+		assert.ErrorIs(t, err, domainErrors.ErrItemNotFound)
+	})
+
+	t.Run("異常系: 管理者でないユーザー", func(t *testing.T) {
+		mockRepo := new(MockItemRepository)
+		ctx := reqctx.WithUserRole(reqctx.WithUserID(context.Background(), 2), entity.UserRoleMember)
+
+		err := NewItemUsecase(mockRepo).PurgeItem(ctx, 1, "")
+		assert.ErrorIs(t, err, domainErrors.ErrForbidden)
+		mockRepo.AssertNotCalled(t, "Purge", mock.Anything, mock.Anything)
+	})
+
+	t.Run("異常系: 呼び出し元の分からないリクエスト", func(t *testing.T) {
+		mockRepo := new(MockItemRepository)
+
+		err := NewItemUsecase(mockRepo).PurgeItem(context.Background(), 1, "")
+		assert.ErrorIs(t, err, domainErrors.ErrUnauthenticated)
+		mockRepo.AssertNotCalled(t, "Purge", mock.Anything, mock.Anything)
+	})
+
+	t.Run("正常系: システムの処理はユーザーなしで削除できる", func(t *testing.T) {
+		mockRepo := new(MockItemRepository)
+		mockRepo.On("FindByID", mock.Anything, int64(2)).Return(nil, domainErrors.ErrItemNotFound)
+		mockRepo.On("Purge", mock.Anything, int64(2)).Return(nil)
+
+		err := NewItemUsecase(mockRepo).PurgeItem(reqctx.WithSystem(context.Background()), 2, "")
+		require.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestItemUsecase_PurgeExpired(t *testing.T) {
+	cutoff := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("正常系: 削除した件数を返す", func(t *testing.T) {
+		mockRepo := new(MockItemRepository)
+		mockRepo.On("PurgeDeletedBefore", mock.Anything, cutoff).Return(int64(4), nil)
+
+		purged, err := NewItemUsecase(mockRepo).PurgeExpired(context.Background(), cutoff)
+		require.NoError(t, err)
+		assert.Equal(t, int64(4), purged)
+	})
+
+	t.Run("正常系: 削除したアイテムの画像も消す", func(t *testing.T) {
+		mockRepo := new(MockItemRepository)
+		mockRepo.On("FindIDsDeletedBefore", mock.Anything, cutoff).Return([]int64{2}, nil)
+		mockRepo.On("PurgeDeletedBefore", mock.Anything, cutoff).Return(int64(1), nil)
+		images := new(MockImageRepository)
+		images.On("FindByItemID", mock.Anything, int64(2)).Return([]*entity.ItemImage{{ID: 5, ItemID: 2, Key: "images/items/2/a.png"}}, nil)
+		images.On("Delete", mock.Anything, int64(5)).Return(nil)
+		blobs := &deletingBlobs{}
+
+		purged, err := NewItemUsecase(mockRepo, WithPurgedRecords(NewItemImageCleaner(images, blobs))).PurgeExpired(context.Background(), cutoff)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), purged)
+		assert.Equal(t, []string{"images/items/2/a.png"}, blobs.deleted)
+	})
+}
+
+func TestItemUsecase_GetAuditLog(t *testing.T) {
+	entries := []*entity.AuditEntry{{ID: 1, Action: entity.AuditActionItemDelete, ItemID: 5}}
+	member := reqctx.WithUserRole(reqctx.WithUserID(context.Background(), 2), entity.UserRoleMember)
+
+	t.Run("正常系: 論理削除した自分のアイテムの監査ログを返す", func(t *testing.T) {
+		mockRepo := new(MockItemRepository)
+		mockRepo.On("FindByIDIncludingDeleted", member, int64(5)).Return(&entity.Item{ID: 5}, nil)
+		auditLog := new(MockAuditLogRepository)
+		auditLog.On("FindByItemID", member, int64(5)).Return(entries, nil)
+
+		got, err := NewItemUsecase(mockRepo, WithAuditLog(auditLog)).GetAuditLog(member, 5)
+		require.NoError(t, err)
+		assert.Equal(t, entries, got)
+	})
+
+	t.Run("異常系: 他のユーザーのアイテムは見つからない扱いにする", func(t *testing.T) {
+		mockRepo := new(MockItemRepository)
+		mockRepo.On("FindByIDIncludingDeleted", member, int64(5)).Return(nil, domainErrors.ErrItemNotFound)
+		auditLog := new(MockAuditLogRepository)
+
+		_, err := NewItemUsecase(mockRepo, WithAuditLog(auditLog)).GetAuditLog(member, 5)
+		assert.ErrorIs(t, err, domainErrors.ErrItemNotFound)
+		auditLog.AssertNotCalled(t, "FindByItemID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("正常系: 管理者は持ち主を確かめない", func(t *testing.T) {
+		admin := reqctx.WithUserRole(reqctx.WithUserID(context.Background(), 1), entity.UserRoleAdmin)
+		mockRepo := new(MockItemRepository)
+		auditLog := new(MockAuditLogRepository)
+		auditLog.On("FindByItemID", admin, int64(5)).Return(entries, nil)
+
+		got, err := NewItemUsecase(mockRepo, WithAuditLog(auditLog)).GetAuditLog(admin, 5)
+		require.NoError(t, err)
+		assert.Equal(t, entries, got)
+		mockRepo.AssertNotCalled(t, "FindByIDIncludingDeleted", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,251 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"Aicon-assignment/internal/domain/entity"
+	"Aicon-assignment/internal/pkg/clock"
+	"Aicon-assignment/internal/pkg/reqctx"
+)
+
+// AlertNotifier delivers SLO alerts to the on-call channel
+type AlertNotifier interface {
+	Notify(ctx context.Context, alert entity.SLOAlert) error
+}
+
+// 燃焼率を計算する期間。短い期間で急な悪化を、長い期間で一時的なものでないことを確かめる
+const (
+	SLOShortWindow = 5 * time.Minute
+	SLOLongWindow  = time.Hour
+)
+
+// 件数が少ないうちは1件の失敗で燃焼率が跳ね上がるため、長い期間の件数がこれ未満なら通知しない
+const sloMinRequests = 20
+
+var sloWindows = map[string]time.Duration{"5m": SLOShortWindow, "1h": SLOLongWindow}
+
+type SLOUsecase interface {
+	// Record は1件のリクエストの結果を記録する。目標のないルートは記録しない
+	Record(method, route string, status int, latency time.Duration)
+	// Statuses はルート・SLI ごとの状況を返す
+	Statuses() []entity.SLOStatus
+	// CheckBurnRates は短い期間と長い期間の両方で燃焼率が閾値を超えたものを通知する
+	CheckBurnRates(ctx context.Context) error
+}
+
+// 1分ごとの件数
+type sloBucket struct {
+	minute int64
+	total  int64
+	errors int64
+	slow   int64
+}
+
+type sloSeries struct {
+	method    string
+	route     string
+	objective entity.SLObjective
+	buckets   [int(SLOLongWindow / time.Minute)]sloBucket
+
+	// 起動してからの件数
+	total  int64
+	errors int64
+	slow   int64
+}
+
+// SLI の目標を満たさなかった件数
+func (s *sloSeries) bad(sli string, b sloBucket) int64 {
+	if sli == entity.SLILatency {
+		return b.slow
+	}
+	return b.errors
+}
+
+// 直近 window の件数
+func (s *sloSeries) window(now int64, window time.Duration) sloBucket {
+	var sum sloBucket
+	minutes := int64(window / time.Minute)
+	for _, b := range s.buckets {
+		if b.minute > now-minutes && b.minute <= now {
+			sum.total += b.total
+			sum.errors += b.errors
+			sum.slow += b.slow
+		}
+	}
+	return sum
+}
+
+type sloUsecase struct {
+	objectives []entity.SLObjective
+	threshold  float64
+	notifier   AlertNotifier
+	clock      clock.Clock
+
+	mu     sync.Mutex
+	series map[string]*sloSeries
+	firing map[string]bool
+}
+
+// threshold は通知する燃焼率（14.4 で 1 時間に 30 日分のバジェットの 2% を消費する速さ）
+func NewSLOUsecase(objectives []entity.SLObjective, threshold float64, notifier AlertNotifier, clock clock.Clock) SLOUsecase {
+	return &sloUsecase{
+		objectives: objectives,
+		threshold:  threshold,
+		notifier:   notifier,
+		clock:      clock,
+		series:     make(map[string]*sloSeries),
+		firing:     make(map[string]bool),
+	}
+}
+
+func (u *sloUsecase) Record(method, route string, status int, latency time.Duration) {
+	if route == "" || len(u.objectives) == 0 {
+		return
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	key := method + " " + route
+	series, ok := u.series[key]
+	if !ok {
+		objective, found := u.objectiveFor(method, route)
+		if !found {
+			return
+		}
+		series = &sloSeries{method: method, route: route, objective: objective}
+		u.series[key] = series
+	}
+
+	minute := u.clock.Now().Unix() / 60
+	b := &series.buckets[minute%int64(len(series.buckets))]
+	if b.minute != minute {
+		*b = sloBucket{minute: minute}
+	}
+
+	failed := status >= 500
+	slow := series.objective.LatencyThreshold > 0 && latency > series.objective.LatencyThreshold
+	b.total++
+	series.total++
+	if failed {
+		b.errors++
+		series.errors++
+	}
+	if slow {
+		b.slow++
+		series.slow++
+	}
+}
+
+// 最初に一致した目標を使う
+func (u *sloUsecase) objectiveFor(method, route string) (entity.SLObjective, bool) {
+	for _, objective := range u.objectives {
+		if objective.Matches(method, route) {
+			return objective, true
+		}
+	}
+	return entity.SLObjective{}, false
+}
+
+func (u *sloUsecase) Statuses() []entity.SLOStatus {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	now := u.clock.Now().Unix() / 60
+	var statuses []entity.SLOStatus
+	for _, series := range u.sortedSeries() {
+		for _, sli := range []string{entity.SLIAvailability, entity.SLILatency} {
+			target := series.objective.Target(sli)
+			if target == 0 {
+				continue
+			}
+
+			status := entity.SLOStatus{
+				Method:    series.method,
+				Route:     series.route,
+				SLI:       sli,
+				Target:    target,
+				Requests:  series.total,
+				Bad:       series.bad(sli, sloBucket{errors: series.errors, slow: series.slow}),
+				BurnRates: make(map[string]float64, len(sloWindows)),
+			}
+			for name, window := range sloWindows {
+				w := series.window(now, window)
+				status.BurnRates[name] = entity.BurnRate(series.bad(sli, w), w.total, target)
+			}
+			statuses = append(statuses, status)
+		}
+	}
+	return statuses
+}
+
+func (u *sloUsecase) CheckBurnRates(ctx context.Context) error {
+	var alerts []entity.SLOAlert
+
+	u.mu.Lock()
+	now := u.clock.Now()
+	minute := now.Unix() / 60
+	for _, series := range u.sortedSeries() {
+		short := series.window(minute, SLOShortWindow)
+		long := series.window(minute, SLOLongWindow)
+
+		for _, sli := range []string{entity.SLIAvailability, entity.SLILatency} {
+			target := series.objective.Target(sli)
+			if target == 0 {
+				continue
+			}
+
+			alert := entity.SLOAlert{
+				Method:        series.method,
+				Route:         series.route,
+				SLI:           sli,
+				Target:        target,
+				ShortBurnRate: entity.BurnRate(series.bad(sli, short), short.total, target),
+				LongBurnRate:  entity.BurnRate(series.bad(sli, long), long.total, target),
+				Threshold:     u.threshold,
+				FiredAt:       now,
+				Channels:      series.objective.Channels,
+			}
+			burning := long.total >= sloMinRequests && alert.ShortBurnRate >= u.threshold && alert.LongBurnRate >= u.threshold
+
+			// 燃焼が続いている間は繰り返し通知しない
+			key := series.method + " " + series.route + " " + sli
+			switch {
+			case burning && !u.firing[key]:
+				u.firing[key] = true
+				alerts = append(alerts, alert)
+			case !burning && u.firing[key]:
+				delete(u.firing, key)
+				reqctx.Logger(ctx).Info("slo burn rate recovered", "method", series.method, "route", series.route, "sli", sli)
+			}
+		}
+	}
+	u.mu.Unlock()
+
+	var errs []error
+	for _, alert := range alerts {
+		reqctx.Logger(ctx).Warn("slo error budget burning too fast", "alert", alert.String())
+		if err := u.notifier.Notify(ctx, alert); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (u *sloUsecase) sortedSeries() []*sloSeries {
+	series := make([]*sloSeries, 0, len(u.series))
+	for _, s := range u.series {
+		series = append(series, s)
+	}
+	sort.Slice(series, func(i, j int) bool {
+		if series[i].route == series[j].route {
+			return series[i].method < series[j].method
+		}
+		return series[i].route < series[j].route
+	})
+	return series
+}
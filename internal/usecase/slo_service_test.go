@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"Aicon-assignment/internal/domain/entity"
+	"Aicon-assignment/internal/pkg/clock"
+)
+
+// 通知をためておく AlertNotifier
+type recordingNotifier struct {
+	alerts []entity.SLOAlert
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, alert entity.SLOAlert) error {
+	n.alerts = append(n.alerts, alert)
+	return nil
+}
+
+func TestSLOUsecase_CheckBurnRates(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	clk := clock.NewFrozen(now)
+	notifier := &recordingNotifier{}
+	objectives := []entity.SLObjective{
+		{Method: "GET", Route: "/items", Availability: 0.99, LatencyThreshold: 300 * time.Millisecond, LatencyTarget: 0.9},
+	}
+	slo := NewSLOUsecase(objectives, 9, notifier, clk)
+
+	// 50 分前までは正常、直近 5 分は 2 割が 500
+	for i := 0; i < 100; i++ {
+		clk.Set(now.Add(-50 * time.Minute))
+		slo.Record("GET", "/items", http.StatusOK, 10*time.Millisecond)
+	}
+	for i := 0; i < 100; i++ {
+		clk.Set(now.Add(-2 * time.Minute))
+		status := http.StatusOK
+		if i%5 == 0 {
+			status = http.StatusInternalServerError
+		}
+		slo.Record("GET", "/items", status, 10*time.Millisecond)
+	}
+	slo.Record("POST", "/items", http.StatusInternalServerError, 0) // 目標のないルートは記録しない
+	clk.Set(now)
+
+	require.NoError(t, slo.CheckBurnRates(context.Background()))
+	require.Len(t, notifier.alerts, 1)
+	alert := notifier.alerts[0]
+	assert.Equal(t, entity.SLIAvailability, alert.SLI)
+	assert.InDelta(t, 20, alert.ShortBurnRate, 0.001) // 20% / 1%
+	assert.InDelta(t, 10, alert.LongBurnRate, 0.001)  // 10% / 1%
+
+	t.Run("燃焼が続いている間は再通知しない", func(t *testing.T) {
+		require.NoError(t, slo.CheckBurnRates(context.Background()))
+		assert.Len(t, notifier.alerts, 1)
+	})
+
+	t.Run("状況はルート・SLI ごとに返す", func(t *testing.T) {
+		statuses := slo.Statuses()
+		require.Len(t, statuses, 2)
+		assert.Equal(t, int64(200), statuses[0].Requests)
+		assert.Equal(t, int64(20), statuses[0].Bad)
+		assert.Equal(t, entity.SLILatency, statuses[1].SLI)
+		assert.Zero(t, statuses[1].BurnRates["5m"])
+	})
+
+	t.Run("短い期間が落ち着けば回復し、再び燃焼すれば通知する", func(t *testing.T) {
+		clk.Set(now.Add(10 * time.Minute))
+		require.NoError(t, slo.CheckBurnRates(context.Background()))
+		assert.Len(t, notifier.alerts, 1)
+
+		for i := 0; i < 20; i++ {
+			slo.Record("GET", "/items", http.StatusServiceUnavailable, 0)
+		}
+		require.NoError(t, slo.CheckBurnRates(context.Background()))
+		assert.Len(t, notifier.alerts, 2)
+	})
+}
@@ -0,0 +1,80 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+type SplitItemInput struct {
+	Components []entity.SplitComponent `json:"components"`
+	Reason     string                  `json:"reason"`
+}
+
+// 1つとして登録されたアイテムを複数のアイテムに分割する
+// 分割後のアイテムの作成、元のアイテムの論理削除、監査ログの記録を1つのトランザクションで行う
+func (u *itemUsecase) SplitItem(ctx context.Context, id int64, input SplitItemInput) ([]*entity.Item, error) {
+	if id <= 0 {
+		return nil, domainErrors.ErrInvalidInput
+	}
+
+	reason := strings.TrimSpace(input.Reason)
+	if reason == "" && u.reasonPolicy.PolicyFor(ctx).RequiresReasonForDelete() {
+		return nil, domainErrors.ErrReasonRequired
+	}
+
+	var original *entity.Item
+	var created []*entity.Item
+	err := u.transactor.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		original, err = u.itemRepo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		now := u.clock.Now()
+		components, err := original.Split(input.Components, now)
+		if err != nil {
+			return fmt.Errorf("%w: %s", domainErrors.ErrInvalidInput, err.Error())
+		}
+
+		created = make([]*entity.Item, 0, len(components))
+		ids := make([]string, 0, len(components))
+		for _, component := range components {
+			item, err := u.itemRepo.Create(ctx, component)
+			if err != nil {
+				return err
+			}
+			created = append(created, item)
+			ids = append(ids, fmt.Sprint(item.ID))
+
+			if err := u.recordAuditIn(ctx, entity.AuditActionItemCreate, item.ID, withReason(fmt.Sprintf("split from item %d", id), reason)); err != nil {
+				return err
+			}
+		}
+
+		if err := u.itemRepo.SoftDelete(ctx, id, now); err != nil {
+			return err
+		}
+		return u.recordAuditIn(ctx, entity.AuditActionItemSplit, id, withReason("split into items "+strings.Join(ids, ", "), reason))
+	})
+	if err != nil {
+		if domainErrors.IsNotFoundError(err) {
+			return nil, domainErrors.ErrItemNotFound
+		}
+		if domainErrors.IsValidationError(err) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to split item: %w", err)
+	}
+
+	for _, item := range created {
+		u.publish(ctx, entity.EventItemCreated, item)
+	}
+	u.publishDeleted(ctx, original, entity.DeleteReasonSplit)
+
+	return created, nil
+}
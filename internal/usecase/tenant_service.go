@@ -0,0 +1,324 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/pkg/clock"
+	"Aicon-assignment/internal/pkg/filter"
+	"Aicon-assignment/internal/pkg/idgen"
+	"Aicon-assignment/internal/pkg/reqctx"
+)
+
+// 取り込み先に同じユーザー名のユーザーがいる場合の扱い
+const (
+	TenantUsersMatch = "match" // 既存のユーザーをそのまま使う
+	TenantUsersFail  = "fail"  // 取り込まずに ErrDuplicateEntry を返す
+)
+
+type TenantUsecase interface {
+	// Export は組織・ブランディング・メンバー・アイテム（価格変更履歴を含む）を書き出す
+	Export(ctx context.Context, orgID int64) (*entity.TenantBundle, error)
+	// Import は書き出したデータを新しい組織として取り込み、書き出し元の ID と新しい ID の対応を返す
+	// すべてを 1 つのトランザクションで行い、途中で失敗した場合は何も取り込まない
+	Import(ctx context.Context, bundle *entity.TenantBundle, input TenantImportInput) (*TenantImportResult, error)
+}
+
+type TenantImportInput struct {
+	Name  string // 空の場合は書き出し元の組織名
+	Users string // TenantUsersMatch（省略時）または TenantUsersFail
+}
+
+type TenantImportResult struct {
+	OrganizationID int64           `json:"organization_id"`
+	Users          map[int64]int64 `json:"users"` // 書き出し元のユーザーID → 取り込み先のユーザーID
+	Items          map[int64]int64 `json:"items"` // 書き出し元のアイテムID → 取り込み先のアイテムID
+	CreatedUsers   int             `json:"created_users"`
+	MatchedUsers   int             `json:"matched_users"` // 同じユーザー名の既存のユーザーを使った数
+}
+
+type tenantUsecase struct {
+	orgs       OrganizationRepository
+	users      UserRepository
+	items      ItemRepository
+	transactor Transactor
+	events     EventPublisher // nil の場合は取り込んだアイテムのイベントを発行しない
+	clock      clock.Clock
+}
+
+func NewTenantUsecase(orgs OrganizationRepository, users UserRepository, items ItemRepository, transactor Transactor, events EventPublisher, clock clock.Clock) TenantUsecase {
+	return &tenantUsecase{
+		orgs:       orgs,
+		users:      users,
+		items:      items,
+		transactor: transactor,
+		events:     events,
+		clock:      clock,
+	}
+}
+
+func (u *tenantUsecase) Export(ctx context.Context, orgID int64) (*entity.TenantBundle, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	// 管理者自身のアイテムだけでなく、組織のすべてのアイテムを対象にする
+	ctx = reqctx.WithAllOwners(ctx)
+
+	org, err := u.orgs.FindByID(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	bundle := &entity.TenantBundle{
+		Version:      entity.TenantBundleVersion,
+		ExportedAt:   u.clock.Now(),
+		Organization: *org,
+		Users:        []*entity.TenantUser{},
+		Items:        []*entity.TenantItem{},
+	}
+
+	branding, err := u.orgs.FindBranding(ctx, orgID)
+	if err != nil && !errors.Is(err, domainErrors.ErrBrandingNotFound) {
+		return nil, fmt.Errorf("failed to retrieve branding: %w", err)
+	}
+	bundle.Branding = branding
+
+	if bundle.Members, err = u.orgs.FindMembers(ctx, orgID); err != nil {
+		return nil, fmt.Errorf("failed to retrieve members: %w", err)
+	}
+	userIDs := make([]int64, 0, len(bundle.Members))
+	for _, member := range bundle.Members {
+		userIDs = append(userIDs, member.UserID)
+	}
+
+	items, err := u.items.FindByOrganization(ctx, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve items: %w", err)
+	}
+	for _, item := range items {
+		history, err := u.items.FindPriceHistory(ctx, item.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve price history of item %d: %w", item.ID, err)
+		}
+		bundle.Items = append(bundle.Items, &entity.TenantItem{Item: *item, PriceHistory: history})
+		if item.OwnerID != nil {
+			userIDs = append(userIDs, *item.OwnerID)
+		}
+	}
+
+	seen := make(map[int64]bool)
+	for _, id := range userIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		user, err := u.users.FindByID(ctx, id)
+		if errors.Is(err, domainErrors.ErrUserNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve user %d: %w", id, err)
+		}
+		bundle.Users = append(bundle.Users, &entity.TenantUser{
+			ID:          user.ID,
+			UserName:    user.UserName,
+			DisplayName: user.DisplayName,
+			Email:       user.Email,
+			ExternalID:  user.ExternalID,
+			Active:      user.Active,
+		})
+	}
+
+	reqctx.Logger(ctx).Info("organization exported",
+		"organization_id", orgID, "users", len(bundle.Users), "items", len(bundle.Items))
+	return bundle, nil
+}
+
+func (u *tenantUsecase) Import(ctx context.Context, bundle *entity.TenantBundle, input TenantImportInput) (*TenantImportResult, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	ctx = reqctx.WithAllOwners(ctx)
+
+	if input.Users == "" {
+		input.Users = TenantUsersMatch
+	}
+	if input.Users != TenantUsersMatch && input.Users != TenantUsersFail {
+		return nil, fmt.Errorf("%w: users must be one of: match, fail", domainErrors.ErrInvalidInput)
+	}
+	if bundle.Version != entity.TenantBundleVersion {
+		return nil, fmt.Errorf("%w: unsupported bundle version %d (want %d)", domainErrors.ErrInvalidInput, bundle.Version, entity.TenantBundleVersion)
+	}
+	name := input.Name
+	if name == "" {
+		name = bundle.Organization.Name
+	}
+
+	now := u.clock.Now()
+	org, err := entity.NewOrganizationAt(now, name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrInvalidInput, err.Error())
+	}
+	if err := validateTenantBundle(bundle); err != nil {
+		return nil, err
+	}
+
+	var result *TenantImportResult
+	var created []*entity.Item
+	err = u.transactor.Transaction(ctx, func(ctx context.Context) error {
+		result = &TenantImportResult{Users: make(map[int64]int64), Items: make(map[int64]int64)}
+		created = created[:0]
+
+		org.ID = 0
+		if err := u.orgs.Create(ctx, org); err != nil {
+			return fmt.Errorf("failed to create organization: %w", err)
+		}
+		result.OrganizationID = org.ID
+
+		for _, exported := range bundle.Users {
+			if err := u.importUser(ctx, exported, input.Users, now, result); err != nil {
+				return err
+			}
+		}
+
+		for _, member := range bundle.Members {
+			userID := result.Users[member.UserID]
+			if err := u.orgs.AddMember(ctx, &entity.Membership{
+				OrgID:     org.ID,
+				UserID:    userID,
+				Role:      member.Role,
+				CreatedAt: member.CreatedAt,
+				UpdatedAt: member.UpdatedAt,
+			}); err != nil {
+				return fmt.Errorf("failed to add member %d: %w", userID, err)
+			}
+		}
+
+		if bundle.Branding != nil {
+			branding := *bundle.Branding
+			branding.OrgID = org.ID
+			if err := u.orgs.SaveBranding(ctx, &branding); err != nil {
+				return fmt.Errorf("failed to save branding: %w", err)
+			}
+		}
+
+		for _, exported := range bundle.Items {
+			item := exported.Item
+			item.ID = 0
+			item.OrgID = &org.ID
+			item.OwnerID = nil
+			if exported.OwnerID != nil {
+				// 持ち主が書き出されていない場合は持ち主のいないアイテムにする
+				if ownerID, ok := result.Users[*exported.OwnerID]; ok {
+					item.OwnerID = &ownerID
+				}
+			}
+
+			stored, err := u.items.Create(ctx, &item)
+			if err != nil {
+				return fmt.Errorf("failed to create item %d: %w", exported.ID, err)
+			}
+			for _, change := range exported.PriceHistory {
+				copied := *change
+				copied.ID = 0
+				copied.ItemID = stored.ID
+				if err := u.items.RecordPriceChange(ctx, &copied); err != nil {
+					return fmt.Errorf("failed to record price history of item %d: %w", exported.ID, err)
+				}
+			}
+			result.Items[exported.ID] = stored.ID
+			created = append(created, stored)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 検索インデックス・Webhook・イベントストアには、通常の登録と同じく作成として伝える
+	if u.events != nil {
+		for _, item := range created {
+			u.events.Publish(ctx, &entity.Event{
+				ID:         idgen.NewRandomID(),
+				Type:       entity.EventItemCreated,
+				OccurredAt: now,
+				Actor:      actorFromContext(ctx),
+				ItemID:     item.ID,
+				Item:       item,
+			})
+		}
+	}
+
+	reqctx.Logger(ctx).Info("organization imported",
+		"organization_id", org.ID, "source_organization_id", bundle.Organization.ID,
+		"created_users", result.CreatedUsers, "matched_users", result.MatchedUsers, "items", len(result.Items))
+	return result, nil
+}
+
+// 同じユーザー名のユーザーがいる場合は policy に従い、いない場合はパスワードなしで作成する
+func (u *tenantUsecase) importUser(ctx context.Context, exported *entity.TenantUser, policy string, now time.Time, result *TenantImportResult) error {
+	existing, err := u.users.FindByQuery(ctx, entity.UserQuery{
+		Filter: &filter.Comparison{Field: "user_name", Op: filter.OpEq, Value: exported.UserName},
+		Limit:  1,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to look up user %q: %w", exported.UserName, err)
+	}
+	if len(existing) > 0 {
+		if policy == TenantUsersFail {
+			return fmt.Errorf("%w: user %q already exists", domainErrors.ErrDuplicateEntry, exported.UserName)
+		}
+		result.Users[exported.ID] = existing[0].ID
+		result.MatchedUsers++
+		return nil
+	}
+
+	user, err := entity.NewUserAt(now, exported.UserName, exported.DisplayName, exported.Email, exported.ExternalID, exported.Active)
+	if err != nil {
+		return fmt.Errorf("%w: user %q: %s", domainErrors.ErrInvalidInput, exported.UserName, err.Error())
+	}
+	if err := u.users.Create(ctx, user); err != nil {
+		return fmt.Errorf("failed to create user %q: %w", exported.UserName, err)
+	}
+	result.Users[exported.ID] = user.ID
+	result.CreatedUsers++
+	return nil
+}
+
+// 書き込みを始める前に、取り込めない内容がないかを確かめる
+func validateTenantBundle(bundle *entity.TenantBundle) error {
+	var errs []string
+	userIDs := make(map[int64]bool, len(bundle.Users))
+	for _, user := range bundle.Users {
+		if userIDs[user.ID] {
+			errs = append(errs, fmt.Sprintf("user %d appears more than once", user.ID))
+		}
+		userIDs[user.ID] = true
+	}
+	for _, member := range bundle.Members {
+		if !userIDs[member.UserID] {
+			errs = append(errs, fmt.Sprintf("member %d is not in users", member.UserID))
+		}
+		if !entity.IsOrgRole(member.Role) {
+			errs = append(errs, fmt.Sprintf("member %d: role must be one of: admin, member", member.UserID))
+		}
+	}
+	if b := bundle.Branding; b != nil {
+		if _, err := entity.NewBranding(&bundle.Organization, b.DisplayName, b.LogoURL, b.AccentColor, b.UpdatedAt); err != nil {
+			errs = append(errs, "branding: "+err.Error())
+		}
+	}
+	for _, item := range bundle.Items {
+		if err := item.Validate(); err != nil {
+			errs = append(errs, fmt.Sprintf("item %d: %s", item.ID, err.Error()))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: %s", domainErrors.ErrInvalidInput, strings.Join(errs, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/pkg/clock"
+	"Aicon-assignment/internal/pkg/filter"
+	"Aicon-assignment/internal/pkg/reqctx"
+)
+
+// ユーザー名で検索する条件に一致する
+func userNameIs(name string) any {
+	return mock.MatchedBy(func(q entity.UserQuery) bool {
+		c, ok := q.Filter.(*filter.Comparison)
+		return ok && c.Field == "user_name" && c.Value == name
+	})
+}
+
+func TestTenantUsecase_Export(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	asAdmin := reqctx.WithUserRole(reqctx.WithUserID(context.Background(), 1), entity.UserRoleAdmin)
+	owner := int64(2)
+
+	t.Run("正常系: メンバーとアイテムの持ち主をユーザーとして書き出す", func(t *testing.T) {
+		orgs := new(MockOrganizationRepository)
+		orgs.On("FindByID", mock.Anything, int64(1)).Return(&entity.Organization{ID: 1, Name: "Aicon"}, nil)
+		orgs.On("FindBranding", mock.Anything, int64(1)).Return(nil, domainErrors.ErrBrandingNotFound)
+		orgs.On("FindMembers", mock.Anything, int64(1)).Return([]*entity.Membership{{OrgID: 1, UserID: 1, Role: entity.OrgRoleAdmin}}, nil)
+		items := new(MockItemRepository)
+		items.On("FindByOrganization", mock.MatchedBy(reqctx.AllOwners), int64(1)).Return([]*entity.Item{{ID: 10, Name: "デイトナ", OwnerID: &owner}}, nil)
+		items.On("FindPriceHistory", mock.Anything, int64(10)).Return([]*entity.PriceChange{{ID: 3, ItemID: 10, OldPrice: 100, NewPrice: 200}}, nil)
+		users := new(MockUserRepository)
+		users.On("FindByID", mock.Anything, int64(1)).Return(&entity.User{ID: 1, UserName: "yamada", PasswordHash: "secret"}, nil)
+		users.On("FindByID", mock.Anything, int64(2)).Return(&entity.User{ID: 2, UserName: "sato"}, nil)
+
+		bundle, err := NewTenantUsecase(orgs, users, items, &fakeTransactor{}, nil, clock.NewFrozen(now)).Export(asAdmin, 1)
+		require.NoError(t, err)
+		assert.Equal(t, entity.TenantBundleVersion, bundle.Version)
+		assert.Nil(t, bundle.Branding)
+		require.Len(t, bundle.Users, 2)
+		assert.Equal(t, "sato", bundle.Users[1].UserName)
+		require.Len(t, bundle.Items, 1)
+		assert.Len(t, bundle.Items[0].PriceHistory, 1)
+	})
+
+	t.Run("異常系: 管理者でないユーザー", func(t *testing.T) {
+		asMember := reqctx.WithUserRole(reqctx.WithUserID(context.Background(), 2), entity.UserRoleMember)
+
+		_, err := NewTenantUsecase(new(MockOrganizationRepository), new(MockUserRepository), new(MockItemRepository), &fakeTransactor{}, nil, clock.NewFrozen(now)).Export(asMember, 1)
+		assert.ErrorIs(t, err, domainErrors.ErrForbidden)
+	})
+}
+
+func TestTenantUsecase_Import(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	asAdmin := reqctx.WithUserRole(reqctx.WithUserID(context.Background(), 1), entity.UserRoleAdmin)
+	sourceOwner := int64(2)
+
+	newBundle := func() *entity.TenantBundle {
+		return &entity.TenantBundle{
+			Version:      entity.TenantBundleVersion,
+			Organization: entity.Organization{ID: 1, Name: "Aicon"},
+			Users: []*entity.TenantUser{
+				{ID: 1, UserName: "yamada", Active: true},
+				{ID: 2, UserName: "sato", Active: true},
+			},
+			Members: []*entity.Membership{{OrgID: 1, UserID: 1, Role: entity.OrgRoleAdmin}},
+			Items: []*entity.TenantItem{{
+				Item:         entity.Item{ID: 10, Name: "デイトナ", Category: "時計", Brand: "ROLEX", PurchasePrice: 200, PurchaseDate: "2023-01-15", OwnerID: &sourceOwner},
+				PriceHistory: []*entity.PriceChange{{ID: 3, ItemID: 10, OldPrice: 100, NewPrice: 200}},
+			}},
+		}
+	}
+
+	t.Run("正常系: 既存のユーザーを使い、新しい ID を振り直して取り込む", func(t *testing.T) {
+		orgs := new(MockOrganizationRepository)
+		orgs.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			args.Get(1).(*entity.Organization).ID = 7
+		}).Return(nil)
+		orgs.On("AddMember", mock.Anything, mock.MatchedBy(func(m *entity.Membership) bool {
+			return m.OrgID == 7 && m.UserID == 50 && m.Role == entity.OrgRoleAdmin
+		})).Return(nil)
+		users := new(MockUserRepository)
+		users.On("FindByQuery", mock.Anything, userNameIs("yamada")).Return([]*entity.User{{ID: 50, UserName: "yamada"}}, nil)
+		users.On("FindByQuery", mock.Anything, userNameIs("sato")).Return([]*entity.User{}, nil)
+		users.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			args.Get(1).(*entity.User).ID = 60
+		}).Return(nil)
+		items := new(MockItemRepository)
+		items.On("Create", mock.Anything, mock.MatchedBy(func(item *entity.Item) bool {
+			return item.ID == 0 && *item.OrgID == 7 && *item.OwnerID == 60
+		})).Return(&entity.Item{ID: 100, Name: "デイトナ"}, nil)
+		items.On("RecordPriceChange", mock.Anything, mock.MatchedBy(func(change *entity.PriceChange) bool {
+			return change.ID == 0 && change.ItemID == 100 && change.NewPrice == 200
+		})).Return(nil)
+		events := &recordingPublisher{}
+		tx := &fakeTransactor{}
+
+		result, err := NewTenantUsecase(orgs, users, items, tx, events, clock.NewFrozen(now)).Import(asAdmin, newBundle(), TenantImportInput{})
+		require.NoError(t, err)
+		assert.Equal(t, int64(7), result.OrganizationID)
+		assert.Equal(t, map[int64]int64{1: 50, 2: 60}, result.Users)
+		assert.Equal(t, map[int64]int64{10: 100}, result.Items)
+		assert.Equal(t, 1, result.MatchedUsers)
+		assert.Equal(t, 1, result.CreatedUsers)
+		assert.True(t, tx.committed)
+		require.Len(t, events.events, 1)
+		assert.Equal(t, entity.EventItemCreated, events.events[0].Type)
+		orgs.AssertExpectations(t)
+		items.AssertExpectations(t)
+	})
+
+	t.Run("異常系: users=fail で同じユーザー名のユーザーがいる", func(t *testing.T) {
+		orgs := new(MockOrganizationRepository)
+		orgs.On("Create", mock.Anything, mock.Anything).Return(nil)
+		users := new(MockUserRepository)
+		users.On("FindByQuery", mock.Anything, userNameIs("yamada")).Return([]*entity.User{{ID: 50, UserName: "yamada"}}, nil)
+		events := &recordingPublisher{}
+		tx := &fakeTransactor{}
+
+		_, err := NewTenantUsecase(orgs, users, new(MockItemRepository), tx, events, clock.NewFrozen(now)).Import(asAdmin, newBundle(), TenantImportInput{Users: TenantUsersFail})
+		assert.ErrorIs(t, err, domainErrors.ErrDuplicateEntry)
+		assert.True(t, tx.rolledBack)
+		assert.Empty(t, events.events)
+	})
+
+	t.Run("異常系: 形式のバージョンが違う", func(t *testing.T) {
+		bundle := newBundle()
+		bundle.Version = 99
+
+		_, err := NewTenantUsecase(new(MockOrganizationRepository), new(MockUserRepository), new(MockItemRepository), &fakeTransactor{}, nil, clock.NewFrozen(now)).Import(asAdmin, bundle, TenantImportInput{})
+		assert.ErrorIs(t, err, domainErrors.ErrInvalidInput)
+	})
+
+	t.Run("異常系: ユーザーに含まれないメンバー", func(t *testing.T) {
+		bundle := newBundle()
+		bundle.Members = append(bundle.Members, &entity.Membership{OrgID: 1, UserID: 9, Role: entity.OrgRoleMember})
+		tx := &fakeTransactor{}
+
+		_, err := NewTenantUsecase(new(MockOrganizationRepository), new(MockUserRepository), new(MockItemRepository), tx, nil, clock.NewFrozen(now)).Import(asAdmin, bundle, TenantImportInput{})
+		assert.ErrorIs(t, err, domainErrors.ErrInvalidInput)
+		assert.False(t, tx.committed)
+	})
+}
@@ -0,0 +1,250 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/pkg/clock"
+	"Aicon-assignment/internal/pkg/reqctx"
+	"Aicon-assignment/internal/pkg/totp"
+)
+
+// パスワードを確かめた後の 2 つ目の要素（AuthUsecase がログインのときに使う）
+type SecondFactor interface {
+	// Check は二要素認証を有効にしたユーザーのコード（認証アプリのコードかリカバリーコード）を確かめる
+	// 有効にしていないユーザーは何もせずに成功する。コードがない場合は ErrTwoFactorRequired、違う場合は ErrInvalidTwoFactorCode を返す
+	Check(ctx context.Context, userID int64, code string) error
+}
+
+// 認証アプリ（TOTP）による二要素認証の登録・解除
+type TwoFactorUsecase interface {
+	SecondFactor
+
+	Status(ctx context.Context, userID int64) (*TwoFactorStatus, error)
+	// Enroll は新しい鍵を作り、認証アプリに登録する URL を返す。確認するまではログインに使わない
+	// 有効にしている場合は ErrDuplicateEntry を返す
+	Enroll(ctx context.Context, userID int64) (*TwoFactorEnrollment, error)
+	// Confirm は認証アプリのコードを確かめて有効にし、リカバリーコードを返す
+	Confirm(ctx context.Context, userID int64, code string) (*RecoveryCodes, error)
+	// RegenerateRecoveryCodes はリカバリーコードを作り直す。以前のコードは使えなくなる
+	RegenerateRecoveryCodes(ctx context.Context, userID int64, code string) (*RecoveryCodes, error)
+	// Disable はコードを確かめて二要素認証を解除する。登録中の場合はコードなしで取り消せる
+	Disable(ctx context.Context, userID int64, code string) error
+}
+
+type TwoFactorStatus struct {
+	Enabled                bool       `json:"enabled"`
+	Pending                bool       `json:"pending"` // 登録したが確認していない
+	EnabledAt              *time.Time `json:"enabled_at,omitempty"`
+	RecoveryCodesRemaining int        `json:"recovery_codes_remaining"`
+}
+
+type TwoFactorEnrollment struct {
+	Secret     string `json:"secret"`      // 手入力する場合の鍵
+	OTPAuthURL string `json:"otpauth_url"` // QR コードにして認証アプリで読み取る
+}
+
+// 発行したリカバリーコード。このときしか表示できない
+type RecoveryCodes struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type twoFactorUsecase struct {
+	settings TwoFactorRepository
+	users    UserRepository
+	issuer   string
+	clock    clock.Clock
+}
+
+// issuer は認証アプリに表示するサービス名
+func NewTwoFactorUsecase(settings TwoFactorRepository, users UserRepository, issuer string, clock clock.Clock) TwoFactorUsecase {
+	return &twoFactorUsecase{
+		settings: settings,
+		users:    users,
+		issuer:   issuer,
+		clock:    clock,
+	}
+}
+
+func (u *twoFactorUsecase) Check(ctx context.Context, userID int64, code string) error {
+	twoFactor, err := u.find(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if twoFactor == nil || !twoFactor.Enabled() {
+		return nil
+	}
+	if code == "" {
+		return domainErrors.ErrTwoFactorRequired
+	}
+
+	recovery, ok := u.verify(twoFactor, code)
+	if !ok {
+		reqctx.Logger(ctx).Info("two-factor code rejected", "user_id", userID)
+		return domainErrors.ErrInvalidTwoFactorCode
+	}
+	if err := u.settings.Save(ctx, twoFactor); err != nil {
+		return fmt.Errorf("failed to save two-factor settings: %w", err)
+	}
+	if recovery {
+		reqctx.Logger(ctx).Warn("recovery code used", "user_id", userID, "remaining", len(twoFactor.RecoveryCodeHashes))
+	}
+	return nil
+}
+
+func (u *twoFactorUsecase) Status(ctx context.Context, userID int64) (*TwoFactorStatus, error) {
+	twoFactor, err := u.find(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if twoFactor == nil {
+		return &TwoFactorStatus{}, nil
+	}
+	return &TwoFactorStatus{
+		Enabled:                twoFactor.Enabled(),
+		Pending:                !twoFactor.Enabled(),
+		EnabledAt:              twoFactor.EnabledAt,
+		RecoveryCodesRemaining: len(twoFactor.RecoveryCodeHashes),
+	}, nil
+}
+
+func (u *twoFactorUsecase) Enroll(ctx context.Context, userID int64) (*TwoFactorEnrollment, error) {
+	existing, err := u.find(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && existing.Enabled() {
+		return nil, fmt.Errorf("%w: two-factor authentication is already enabled; disable it first", domainErrors.ErrDuplicateEntry)
+	}
+	user, err := u.users.FindByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve user: %w", err)
+	}
+
+	// 登録中のものがあれば、新しい鍵で作り直す
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err := u.settings.Save(ctx, entity.NewTwoFactor(userID, secret, u.clock.Now())); err != nil {
+		return nil, fmt.Errorf("failed to save two-factor settings: %w", err)
+	}
+
+	return &TwoFactorEnrollment{
+		Secret:     secret,
+		OTPAuthURL: totp.URL(u.issuer, user.UserName, secret),
+	}, nil
+}
+
+func (u *twoFactorUsecase) Confirm(ctx context.Context, userID int64, code string) (*RecoveryCodes, error) {
+	twoFactor, err := u.find(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if twoFactor == nil {
+		return nil, fmt.Errorf("%w: enroll first", domainErrors.ErrTwoFactorNotFound)
+	}
+	if twoFactor.Enabled() {
+		return nil, fmt.Errorf("%w: two-factor authentication is already enabled", domainErrors.ErrDuplicateEntry)
+	}
+
+	now := u.clock.Now()
+	step, ok := totp.Verify(twoFactor.Secret, code, now)
+	if !ok {
+		return nil, domainErrors.ErrInvalidTwoFactorCode
+	}
+	codes, hashes, err := entity.NewRecoveryCodes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate recovery codes: %w", err)
+	}
+	twoFactor.Enable(step, hashes, now)
+	if err := u.settings.Save(ctx, twoFactor); err != nil {
+		return nil, fmt.Errorf("failed to save two-factor settings: %w", err)
+	}
+
+	reqctx.Logger(ctx).Info("two-factor authentication enabled", "user_id", userID)
+	return &RecoveryCodes{RecoveryCodes: codes}, nil
+}
+
+func (u *twoFactorUsecase) RegenerateRecoveryCodes(ctx context.Context, userID int64, code string) (*RecoveryCodes, error) {
+	twoFactor, err := u.findEnabled(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := u.verify(twoFactor, code); !ok {
+		return nil, domainErrors.ErrInvalidTwoFactorCode
+	}
+
+	codes, hashes, err := entity.NewRecoveryCodes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate recovery codes: %w", err)
+	}
+	twoFactor.RecoveryCodeHashes = hashes
+	twoFactor.UpdatedAt = u.clock.Now()
+	if err := u.settings.Save(ctx, twoFactor); err != nil {
+		return nil, fmt.Errorf("failed to save two-factor settings: %w", err)
+	}
+
+	reqctx.Logger(ctx).Info("recovery codes regenerated", "user_id", userID)
+	return &RecoveryCodes{RecoveryCodes: codes}, nil
+}
+
+func (u *twoFactorUsecase) Disable(ctx context.Context, userID int64, code string) error {
+	twoFactor, err := u.find(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if twoFactor == nil {
+		return domainErrors.ErrTwoFactorNotFound
+	}
+	if twoFactor.Enabled() {
+		if _, ok := u.verify(twoFactor, code); !ok {
+			return domainErrors.ErrInvalidTwoFactorCode
+		}
+	}
+
+	if err := u.settings.Delete(ctx, userID); err != nil {
+		return fmt.Errorf("failed to delete two-factor settings: %w", err)
+	}
+
+	reqctx.Logger(ctx).Info("two-factor authentication disabled", "user_id", userID)
+	return nil
+}
+
+// 6 桁の数字は認証アプリのコード、それ以外はリカバリーコードとして確かめ、使ったことを twoFactor に記録する
+func (u *twoFactorUsecase) verify(twoFactor *entity.TwoFactor, code string) (recovery bool, ok bool) {
+	now := u.clock.Now()
+	if len(code) == totp.Digits {
+		if step, ok := totp.Verify(twoFactor.Secret, code, now); ok {
+			return false, twoFactor.UseStep(step, now)
+		}
+	}
+	return true, twoFactor.UseRecoveryCode(code, now)
+}
+
+// 登録していない場合は nil を返す
+func (u *twoFactorUsecase) find(ctx context.Context, userID int64) (*entity.TwoFactor, error) {
+	twoFactor, err := u.settings.FindByUser(ctx, userID)
+	if errors.Is(err, domainErrors.ErrTwoFactorNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve two-factor settings: %w", err)
+	}
+	return twoFactor, nil
+}
+
+func (u *twoFactorUsecase) findEnabled(ctx context.Context, userID int64) (*entity.TwoFactor, error) {
+	twoFactor, err := u.find(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if twoFactor == nil || !twoFactor.Enabled() {
+		return nil, domainErrors.ErrTwoFactorNotFound
+	}
+	return twoFactor, nil
+}
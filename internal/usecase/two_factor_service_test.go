@@ -0,0 +1,253 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/pkg/clock"
+	"Aicon-assignment/internal/pkg/totp"
+)
+
+// MockTwoFactorRepository はテスト用の二要素認証の設定のリポジトリ
+type MockTwoFactorRepository struct {
+	mock.Mock
+}
+
+func (m *MockTwoFactorRepository) FindByUser(ctx context.Context, userID int64) (*entity.TwoFactor, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.TwoFactor), args.Error(1)
+}
+
+func (m *MockTwoFactorRepository) Save(ctx context.Context, twoFactor *entity.TwoFactor) error {
+	args := m.Called(ctx, twoFactor)
+	return args.Error(0)
+}
+
+func (m *MockTwoFactorRepository) Delete(ctx context.Context, userID int64) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+const testTOTPSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func testTOTPCode(t *testing.T, at time.Time) string {
+	t.Helper()
+	code, err := totp.Code(testTOTPSecret, totp.Step(at))
+	require.NoError(t, err)
+	return code
+}
+
+// 有効にした設定と、そのリカバリーコード
+func enabledTwoFactor(t *testing.T, enabledAt time.Time) (*entity.TwoFactor, []string) {
+	t.Helper()
+	codes, hashes, err := entity.NewRecoveryCodes()
+	require.NoError(t, err)
+	twoFactor := entity.NewTwoFactor(7, testTOTPSecret, enabledAt)
+	twoFactor.Enable(totp.Step(enabledAt), hashes, enabledAt)
+	return twoFactor, codes
+}
+
+func TestTwoFactorUsecase_Check(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+
+	t.Run("正常系: 設定していないユーザーはコードなしで通る", func(t *testing.T) {
+		settings := new(MockTwoFactorRepository)
+		settings.On("FindByUser", mock.Anything, int64(7)).Return(nil, domainErrors.ErrTwoFactorNotFound)
+
+		err := NewTwoFactorUsecase(settings, new(MockUserRepository), "Aicon", clock.NewFrozen(now)).Check(ctx, 7, "")
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("正常系: 登録中（未確認）のユーザーはコードなしで通る", func(t *testing.T) {
+		settings := new(MockTwoFactorRepository)
+		settings.On("FindByUser", mock.Anything, int64(7)).Return(entity.NewTwoFactor(7, testTOTPSecret, now), nil)
+
+		err := NewTwoFactorUsecase(settings, new(MockUserRepository), "Aicon", clock.NewFrozen(now)).Check(ctx, 7, "")
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("正常系: 認証アプリのコードを受け付け、ステップを記録する", func(t *testing.T) {
+		twoFactor, _ := enabledTwoFactor(t, now.Add(-time.Hour))
+		settings := new(MockTwoFactorRepository)
+		settings.On("FindByUser", mock.Anything, int64(7)).Return(twoFactor, nil)
+		settings.On("Save", mock.Anything, mock.MatchedBy(func(saved *entity.TwoFactor) bool {
+			return saved.LastUsedStep == totp.Step(now)
+		})).Return(nil)
+
+		err := NewTwoFactorUsecase(settings, new(MockUserRepository), "Aicon", clock.NewFrozen(now)).Check(ctx, 7, testTOTPCode(t, now))
+
+		assert.NoError(t, err)
+		settings.AssertExpectations(t)
+	})
+
+	t.Run("正常系: リカバリーコードは 1 回だけ使える", func(t *testing.T) {
+		twoFactor, codes := enabledTwoFactor(t, now.Add(-time.Hour))
+		settings := new(MockTwoFactorRepository)
+		settings.On("FindByUser", mock.Anything, int64(7)).Return(twoFactor, nil)
+		settings.On("Save", mock.Anything, mock.Anything).Return(nil)
+		usecase := NewTwoFactorUsecase(settings, new(MockUserRepository), "Aicon", clock.NewFrozen(now))
+
+		// 区切りを省き、前後に空白があっても受け付ける
+		err := usecase.Check(ctx, 7, "  "+codes[0][:4]+codes[0][5:]+" ")
+		require.NoError(t, err)
+		assert.Len(t, twoFactor.RecoveryCodeHashes, entity.RecoveryCodeCount-1)
+
+		err = usecase.Check(ctx, 7, codes[0])
+		assert.ErrorIs(t, err, domainErrors.ErrInvalidTwoFactorCode)
+	})
+
+	t.Run("異常系: コードがない", func(t *testing.T) {
+		twoFactor, _ := enabledTwoFactor(t, now.Add(-time.Hour))
+		settings := new(MockTwoFactorRepository)
+		settings.On("FindByUser", mock.Anything, int64(7)).Return(twoFactor, nil)
+
+		err := NewTwoFactorUsecase(settings, new(MockUserRepository), "Aicon", clock.NewFrozen(now)).Check(ctx, 7, "")
+
+		assert.ErrorIs(t, err, domainErrors.ErrTwoFactorRequired)
+		assert.True(t, domainErrors.IsTwoFactorError(err))
+	})
+
+	t.Run("異常系: コードが違う", func(t *testing.T) {
+		twoFactor, _ := enabledTwoFactor(t, now.Add(-time.Hour))
+		settings := new(MockTwoFactorRepository)
+		settings.On("FindByUser", mock.Anything, int64(7)).Return(twoFactor, nil)
+
+		err := NewTwoFactorUsecase(settings, new(MockUserRepository), "Aicon", clock.NewFrozen(now)).Check(ctx, 7, "000000")
+
+		assert.ErrorIs(t, err, domainErrors.ErrInvalidTwoFactorCode)
+		settings.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+
+	t.Run("異常系: 使ったコードをもう一度使う", func(t *testing.T) {
+		twoFactor, _ := enabledTwoFactor(t, now.Add(-time.Hour))
+		twoFactor.LastUsedStep = totp.Step(now)
+		settings := new(MockTwoFactorRepository)
+		settings.On("FindByUser", mock.Anything, int64(7)).Return(twoFactor, nil)
+
+		err := NewTwoFactorUsecase(settings, new(MockUserRepository), "Aicon", clock.NewFrozen(now)).Check(ctx, 7, testTOTPCode(t, now))
+
+		assert.ErrorIs(t, err, domainErrors.ErrInvalidTwoFactorCode)
+	})
+}
+
+func TestTwoFactorUsecase_EnrollAndConfirm(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+
+	t.Run("正常系: 登録して最初のコードで有効にする", func(t *testing.T) {
+		users := new(MockUserRepository)
+		users.On("FindByID", mock.Anything, int64(7)).Return(&entity.User{ID: 7, UserName: "yamada"}, nil)
+		var saved *entity.TwoFactor
+		settings := new(MockTwoFactorRepository)
+		settings.On("FindByUser", mock.Anything, int64(7)).Return(nil, domainErrors.ErrTwoFactorNotFound).Once()
+		settings.On("Save", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			saved = args.Get(1).(*entity.TwoFactor)
+		}).Return(nil)
+		usecase := NewTwoFactorUsecase(settings, users, "Aicon", clock.NewFrozen(now))
+
+		enrollment, err := usecase.Enroll(ctx, 7)
+		require.NoError(t, err)
+		assert.Equal(t, saved.Secret, enrollment.Secret)
+		assert.Contains(t, enrollment.OTPAuthURL, "otpauth://totp/Aicon:yamada?")
+		assert.False(t, saved.Enabled())
+
+		settings.On("FindByUser", mock.Anything, int64(7)).Return(saved, nil)
+		code, err := totp.Code(saved.Secret, totp.Step(now))
+		require.NoError(t, err)
+
+		codes, err := usecase.Confirm(ctx, 7, code)
+		require.NoError(t, err)
+		assert.Len(t, codes.RecoveryCodes, entity.RecoveryCodeCount)
+		assert.True(t, saved.Enabled())
+		assert.Equal(t, totp.Step(now), saved.LastUsedStep)
+	})
+
+	t.Run("異常系: 有効にしている場合は登録し直せない", func(t *testing.T) {
+		twoFactor, _ := enabledTwoFactor(t, now.Add(-time.Hour))
+		settings := new(MockTwoFactorRepository)
+		settings.On("FindByUser", mock.Anything, int64(7)).Return(twoFactor, nil)
+
+		_, err := NewTwoFactorUsecase(settings, new(MockUserRepository), "Aicon", clock.NewFrozen(now)).Enroll(ctx, 7)
+
+		assert.ErrorIs(t, err, domainErrors.ErrDuplicateEntry)
+	})
+
+	t.Run("異常系: 登録していないのに確認する", func(t *testing.T) {
+		settings := new(MockTwoFactorRepository)
+		settings.On("FindByUser", mock.Anything, int64(7)).Return(nil, domainErrors.ErrTwoFactorNotFound)
+
+		_, err := NewTwoFactorUsecase(settings, new(MockUserRepository), "Aicon", clock.NewFrozen(now)).Confirm(ctx, 7, "123456")
+
+		assert.ErrorIs(t, err, domainErrors.ErrTwoFactorNotFound)
+	})
+
+	t.Run("異常系: 確認のコードが違う", func(t *testing.T) {
+		settings := new(MockTwoFactorRepository)
+		settings.On("FindByUser", mock.Anything, int64(7)).Return(entity.NewTwoFactor(7, testTOTPSecret, now), nil)
+
+		_, err := NewTwoFactorUsecase(settings, new(MockUserRepository), "Aicon", clock.NewFrozen(now)).Confirm(ctx, 7, "000000")
+
+		assert.ErrorIs(t, err, domainErrors.ErrInvalidTwoFactorCode)
+		settings.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+}
+
+func TestTwoFactorUsecase_Disable(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+
+	t.Run("正常系: コードを確かめて解除する", func(t *testing.T) {
+		twoFactor, _ := enabledTwoFactor(t, now.Add(-time.Hour))
+		settings := new(MockTwoFactorRepository)
+		settings.On("FindByUser", mock.Anything, int64(7)).Return(twoFactor, nil)
+		settings.On("Delete", mock.Anything, int64(7)).Return(nil)
+
+		err := NewTwoFactorUsecase(settings, new(MockUserRepository), "Aicon", clock.NewFrozen(now)).Disable(ctx, 7, testTOTPCode(t, now))
+
+		assert.NoError(t, err)
+		settings.AssertExpectations(t)
+	})
+
+	t.Run("正常系: 登録中のものはコードなしで取り消せる", func(t *testing.T) {
+		settings := new(MockTwoFactorRepository)
+		settings.On("FindByUser", mock.Anything, int64(7)).Return(entity.NewTwoFactor(7, testTOTPSecret, now), nil)
+		settings.On("Delete", mock.Anything, int64(7)).Return(nil)
+
+		err := NewTwoFactorUsecase(settings, new(MockUserRepository), "Aicon", clock.NewFrozen(now)).Disable(ctx, 7, "")
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("異常系: コードが違う", func(t *testing.T) {
+		twoFactor, _ := enabledTwoFactor(t, now.Add(-time.Hour))
+		settings := new(MockTwoFactorRepository)
+		settings.On("FindByUser", mock.Anything, int64(7)).Return(twoFactor, nil)
+
+		err := NewTwoFactorUsecase(settings, new(MockUserRepository), "Aicon", clock.NewFrozen(now)).Disable(ctx, 7, "000000")
+
+		assert.ErrorIs(t, err, domainErrors.ErrInvalidTwoFactorCode)
+		settings.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+
+	t.Run("異常系: 設定していない", func(t *testing.T) {
+		settings := new(MockTwoFactorRepository)
+		settings.On("FindByUser", mock.Anything, int64(7)).Return(nil, domainErrors.ErrTwoFactorNotFound)
+
+		err := NewTwoFactorUsecase(settings, new(MockUserRepository), "Aicon", clock.NewFrozen(now)).Disable(ctx, 7, "")
+
+		assert.ErrorIs(t, err, domainErrors.ErrTwoFactorNotFound)
+	})
+}
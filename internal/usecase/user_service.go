@@ -0,0 +1,219 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/pkg/clock"
+	"Aicon-assignment/internal/pkg/filter"
+	"Aicon-assignment/internal/pkg/reqctx"
+)
+
+type UserUsecase interface {
+	CreateUser(ctx context.Context, input CreateUserInput) (*entity.User, error)
+	GetUser(ctx context.Context, id int64) (*entity.User, error)
+	ListUsers(ctx context.Context, query entity.UserQuery) ([]*entity.User, int, error)
+	UpdateUser(ctx context.Context, id int64, input UpdateUserInput) (*entity.User, error)
+	ActiveUser(ctx context.Context, id int64) (*entity.User, error)
+	// ChangeRole は管理者がユーザーのロールを変更する。有効な管理者を 1 人も残さない変更は ErrLastUserAdmin
+	ChangeRole(ctx context.Context, id int64, input ChangeUserRoleInput) (*entity.User, error)
+}
+
+type CreateUserInput struct {
+	UserName    string
+	DisplayName string
+	Email       string
+	ExternalID  string
+	Active      *bool  // 省略時は true
+	Password    string // 省略時はパスワードでログインできない
+}
+
+// nil のフィールドは変更しない
+type UpdateUserInput struct {
+	UserName    *string
+	DisplayName *string
+	Email       *string
+	ExternalID  *string
+	Active      *bool
+	Password    *string // 空文字列の場合はパスワードでログインできなくする
+}
+
+type ChangeUserRoleInput struct {
+	Role string `json:"role"`
+}
+
+type userUsecase struct {
+	users UserRepository
+	clock clock.Clock
+}
+
+func NewUserUsecase(users UserRepository, clock clock.Clock) UserUsecase {
+	return &userUsecase{
+		users: users,
+		clock: clock,
+	}
+}
+
+func (u *userUsecase) CreateUser(ctx context.Context, input CreateUserInput) (*entity.User, error) {
+	active := true
+	if input.Active != nil {
+		active = *input.Active
+	}
+
+	user, err := entity.NewUserAt(u.clock.Now(), input.UserName, input.DisplayName, input.Email, input.ExternalID, active)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrInvalidInput, err.Error())
+	}
+	if input.Password != "" {
+		if user.PasswordHash, err = hashPassword(input.Password); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := u.users.Create(ctx, user); err != nil {
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	reqctx.Logger(ctx).Info("user provisioned", "user_id", user.ID, "user_name", user.UserName)
+	return user, nil
+}
+
+func (u *userUsecase) GetUser(ctx context.Context, id int64) (*entity.User, error) {
+	if id <= 0 {
+		return nil, domainErrors.ErrUserNotFound
+	}
+	return u.users.FindByID(ctx, id)
+}
+
+// 条件に一致するユーザーと、limit・offset を無視した件数を返す
+func (u *userUsecase) ListUsers(ctx context.Context, query entity.UserQuery) ([]*entity.User, int, error) {
+	users, err := u.users.FindByQuery(ctx, query)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to retrieve users: %w", err)
+	}
+	total, err := u.users.CountByQuery(ctx, query)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count users: %w", err)
+	}
+	return users, total, nil
+}
+
+func (u *userUsecase) UpdateUser(ctx context.Context, id int64, input UpdateUserInput) (*entity.User, error) {
+	user, err := u.GetUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	wasActive := user.Active
+
+	if input.UserName != nil {
+		user.UserName = *input.UserName
+	}
+	if input.DisplayName != nil {
+		user.DisplayName = *input.DisplayName
+	}
+	if input.Email != nil {
+		user.Email = *input.Email
+	}
+	if input.ExternalID != nil {
+		user.ExternalID = *input.ExternalID
+	}
+	if input.Active != nil {
+		user.Active = *input.Active
+	}
+	if err := user.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrInvalidInput, err.Error())
+	}
+	if input.Password != nil {
+		user.PasswordHash = ""
+		if *input.Password != "" {
+			if user.PasswordHash, err = hashPassword(*input.Password); err != nil {
+				return nil, err
+			}
+		}
+	}
+	user.UpdatedAt = u.clock.Now()
+
+	if err := u.users.Update(ctx, user); err != nil {
+		return nil, fmt.Errorf("failed to update user: %w", err)
+	}
+
+	if wasActive && !user.Active {
+		reqctx.Logger(ctx).Info("user deprovisioned", "user_id", user.ID, "user_name", user.UserName)
+	}
+	return user, nil
+}
+
+// リクエストの呼び出し元として使えるユーザーを返す
+// 存在しない・無効化されている場合は ErrUnauthenticated
+func (u *userUsecase) ActiveUser(ctx context.Context, id int64) (*entity.User, error) {
+	user, err := u.GetUser(ctx, id)
+	if err != nil {
+		if errors.Is(err, domainErrors.ErrUserNotFound) {
+			return nil, domainErrors.ErrUnauthenticated
+		}
+		return nil, fmt.Errorf("failed to retrieve user: %w", err)
+	}
+	if !user.Active {
+		return nil, domainErrors.ErrUnauthenticated
+	}
+	return user, nil
+}
+
+func (u *userUsecase) ChangeRole(ctx context.Context, id int64, input ChangeUserRoleInput) (*entity.User, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if !entity.IsUserRole(input.Role) {
+		return nil, fmt.Errorf("%w: role must be one of: admin, member", domainErrors.ErrInvalidInput)
+	}
+	user, err := u.GetUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if user.Role == input.Role {
+		return user, nil
+	}
+	if user.IsAdmin() && user.Active {
+		admins, err := u.users.CountByQuery(ctx, entity.UserQuery{Filter: activeAdmins})
+		if err != nil {
+			return nil, fmt.Errorf("failed to count admins: %w", err)
+		}
+		if admins <= 1 {
+			return nil, domainErrors.ErrLastUserAdmin
+		}
+	}
+
+	user.Role = input.Role
+	user.UpdatedAt = u.clock.Now()
+	if err := u.users.Update(ctx, user); err != nil {
+		return nil, fmt.Errorf("failed to update user: %w", err)
+	}
+
+	reqctx.Logger(ctx).Info("user role changed", "user_id", user.ID, "role", user.Role)
+	return user, nil
+}
+
+// role = "admin" AND active = 1
+var activeAdmins = &filter.Logical{
+	Op:    filter.OpAnd,
+	Left:  &filter.Comparison{Field: "role", Op: filter.OpEq, Value: entity.UserRoleAdmin},
+	Right: &filter.Comparison{Field: "active", Op: filter.OpEq, Value: int64(1)},
+}
+
+// 管理者だけに許可する操作で使う
+// システムの処理（reqctx.WithSystem で示したジョブ・CLI）は許可し、呼び出し元のユーザーが分からない場合は許可しない
+func requireAdmin(ctx context.Context) error {
+	if reqctx.System(ctx) {
+		return nil
+	}
+	if _, ok := reqctx.UserID(ctx); !ok {
+		return domainErrors.ErrUnauthenticated
+	}
+	if reqctx.UserRole(ctx) != entity.UserRoleAdmin {
+		return fmt.Errorf("%w: admin role required", domainErrors.ErrForbidden)
+	}
+	return nil
+}
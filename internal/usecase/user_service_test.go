@@ -0,0 +1,216 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/pkg/clock"
+	"Aicon-assignment/internal/pkg/reqctx"
+)
+
+// MockUserRepository はテスト用のユーザーリポジトリ
+type MockUserRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserRepository) Create(ctx context.Context, user *entity.User) error {
+	args := m.Called(ctx, user)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) FindByID(ctx context.Context, id int64) (*entity.User, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.User), args.Error(1)
+}
+
+func (m *MockUserRepository) FindByQuery(ctx context.Context, query entity.UserQuery) ([]*entity.User, error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.User), args.Error(1)
+}
+
+func (m *MockUserRepository) CountByQuery(ctx context.Context, query entity.UserQuery) (int, error) {
+	args := m.Called(ctx, query)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockUserRepository) Update(ctx context.Context, user *entity.User) error {
+	args := m.Called(ctx, user)
+	return args.Error(0)
+}
+
+func TestUserUsecase_CreateUser(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	inactive := false
+
+	tests := []struct {
+		name       string
+		input      CreateUserInput
+		setupMock  func(*MockUserRepository)
+		wantActive bool
+		wantErr    error
+	}{
+		{
+			name:  "正常系: active を省略した場合は有効",
+			input: CreateUserInput{UserName: "yamada", Email: "yamada@example.com"},
+			setupMock: func(m *MockUserRepository) {
+				m.On("Create", mock.Anything, mock.AnythingOfType("*entity.User")).Return(nil)
+			},
+			wantActive: true,
+		},
+		{
+			name:  "正常系: 無効の状態で作成",
+			input: CreateUserInput{UserName: "yamada", Active: &inactive},
+			setupMock: func(m *MockUserRepository) {
+				m.On("Create", mock.Anything, mock.AnythingOfType("*entity.User")).Return(nil)
+			},
+			wantActive: false,
+		},
+		{
+			name:      "異常系: userName がない",
+			input:     CreateUserInput{Email: "yamada@example.com"},
+			setupMock: func(m *MockUserRepository) {},
+			wantErr:   domainErrors.ErrInvalidInput,
+		},
+		{
+			name:      "異常系: メールアドレスが不正",
+			input:     CreateUserInput{UserName: "yamada", Email: "yamada"},
+			setupMock: func(m *MockUserRepository) {},
+			wantErr:   domainErrors.ErrInvalidInput,
+		},
+		{
+			name:  "異常系: userName が重複",
+			input: CreateUserInput{UserName: "yamada"},
+			setupMock: func(m *MockUserRepository) {
+				m.On("Create", mock.Anything, mock.AnythingOfType("*entity.User")).Return(domainErrors.ErrDuplicateEntry)
+			},
+			wantErr: domainErrors.ErrDuplicateEntry,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockUserRepository)
+			tt.setupMock(repo)
+
+			user, err := NewUserUsecase(repo, clock.NewFrozen(now)).CreateUser(context.Background(), tt.input)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, user)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.wantActive, user.Active)
+				assert.Equal(t, now, user.CreatedAt)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserUsecase_UpdateUser(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	inactive := false
+
+	t.Run("正常系: active=false で無効化する", func(t *testing.T) {
+		repo := new(MockUserRepository)
+		repo.On("FindByID", mock.Anything, int64(1)).Return(&entity.User{ID: 1, UserName: "yamada", Active: true, Role: entity.UserRoleMember}, nil)
+		repo.On("Update", mock.Anything, mock.MatchedBy(func(user *entity.User) bool {
+			return !user.Active && user.UserName == "yamada" && user.UpdatedAt.Equal(now)
+		})).Return(nil)
+
+		user, err := NewUserUsecase(repo, clock.NewFrozen(now)).UpdateUser(context.Background(), 1, UpdateUserInput{Active: &inactive})
+		require.NoError(t, err)
+		assert.False(t, user.Active)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("異常系: 存在しないユーザー", func(t *testing.T) {
+		repo := new(MockUserRepository)
+		repo.On("FindByID", mock.Anything, int64(99)).Return(nil, domainErrors.ErrUserNotFound)
+
+		_, err := NewUserUsecase(repo, clock.NewFrozen(now)).UpdateUser(context.Background(), 99, UpdateUserInput{Active: &inactive})
+		assert.ErrorIs(t, err, domainErrors.ErrUserNotFound)
+		repo.AssertExpectations(t)
+	})
+}
+
+func TestUserUsecase_ChangeRole(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	asAdmin := reqctx.WithUserRole(reqctx.WithUserID(context.Background(), 1), entity.UserRoleAdmin)
+
+	t.Run("正常系: メンバーを管理者にする", func(t *testing.T) {
+		repo := new(MockUserRepository)
+		repo.On("FindByID", mock.Anything, int64(2)).Return(&entity.User{ID: 2, UserName: "staff", Active: true, Role: entity.UserRoleMember}, nil)
+		repo.On("Update", mock.Anything, mock.MatchedBy(func(user *entity.User) bool {
+			return user.Role == entity.UserRoleAdmin && user.UpdatedAt.Equal(now)
+		})).Return(nil)
+
+		user, err := NewUserUsecase(repo, clock.NewFrozen(now)).ChangeRole(asAdmin, 2, ChangeUserRoleInput{Role: entity.UserRoleAdmin})
+		require.NoError(t, err)
+		assert.True(t, user.IsAdmin())
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("正常系: ほかに管理者がいれば降格できる", func(t *testing.T) {
+		repo := new(MockUserRepository)
+		repo.On("FindByID", mock.Anything, int64(1)).Return(&entity.User{ID: 1, UserName: "admin", Active: true, Role: entity.UserRoleAdmin}, nil)
+		repo.On("CountByQuery", mock.Anything, mock.Anything).Return(2, nil)
+		repo.On("Update", mock.Anything, mock.Anything).Return(nil)
+
+		user, err := NewUserUsecase(repo, clock.NewFrozen(now)).ChangeRole(asAdmin, 1, ChangeUserRoleInput{Role: entity.UserRoleMember})
+		require.NoError(t, err)
+		assert.Equal(t, entity.UserRoleMember, user.Role)
+	})
+
+	t.Run("異常系: 最後の管理者は降格できない", func(t *testing.T) {
+		repo := new(MockUserRepository)
+		repo.On("FindByID", mock.Anything, int64(1)).Return(&entity.User{ID: 1, UserName: "admin", Active: true, Role: entity.UserRoleAdmin}, nil)
+		repo.On("CountByQuery", mock.Anything, mock.Anything).Return(1, nil)
+
+		_, err := NewUserUsecase(repo, clock.NewFrozen(now)).ChangeRole(asAdmin, 1, ChangeUserRoleInput{Role: entity.UserRoleMember})
+		assert.ErrorIs(t, err, domainErrors.ErrLastUserAdmin)
+		repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("異常系: メンバーはロールを変更できない", func(t *testing.T) {
+		asMember := reqctx.WithUserRole(reqctx.WithUserID(context.Background(), 2), entity.UserRoleMember)
+
+		_, err := NewUserUsecase(new(MockUserRepository), clock.NewFrozen(now)).ChangeRole(asMember, 2, ChangeUserRoleInput{Role: entity.UserRoleAdmin})
+		assert.ErrorIs(t, err, domainErrors.ErrForbidden)
+	})
+
+	t.Run("異常系: 不明なロール", func(t *testing.T) {
+		_, err := NewUserUsecase(new(MockUserRepository), clock.NewFrozen(now)).ChangeRole(asAdmin, 2, ChangeUserRoleInput{Role: "owner"})
+		assert.ErrorIs(t, err, domainErrors.ErrInvalidInput)
+	})
+}
+
+func TestUserUsecase_ActiveUser(t *testing.T) {
+	repo := new(MockUserRepository)
+	repo.On("FindByID", mock.Anything, int64(1)).Return(&entity.User{ID: 1, Active: true}, nil)
+	repo.On("FindByID", mock.Anything, int64(2)).Return(&entity.User{ID: 2, Active: false}, nil)
+	repo.On("FindByID", mock.Anything, int64(3)).Return(nil, domainErrors.ErrUserNotFound)
+	usecase := NewUserUsecase(repo, clock.System{})
+
+	user, err := usecase.ActiveUser(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), user.ID)
+
+	_, err = usecase.ActiveUser(context.Background(), 2)
+	assert.ErrorIs(t, err, domainErrors.ErrUnauthenticated)
+	_, err = usecase.ActiveUser(context.Background(), 3)
+	assert.ErrorIs(t, err, domainErrors.ErrUnauthenticated)
+}
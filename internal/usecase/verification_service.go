@@ -0,0 +1,243 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/pkg/clock"
+	"Aicon-assignment/internal/pkg/reqctx"
+)
+
+type VerificationUsecase interface {
+	Get(ctx context.Context, itemID int64) (*entity.ItemVerification, error)
+	// Start は鑑定士が検証を始める（未検証 → 検証中）
+	Start(ctx context.Context, itemID int64) (*entity.ItemVerification, error)
+	// SignOff は鑑定士が所見と鑑定書を添えて検証済みにし、フィールドをロックする（検証中 → 検証済）
+	SignOff(ctx context.Context, itemID int64, input SignOffVerificationInput) (*entity.ItemVerification, error)
+	// Reopen は管理者が検証をやり直し、ロックを解除する（検証中・検証済 → 未検証）
+	Reopen(ctx context.Context, itemID int64, reason string) (*entity.ItemVerification, error)
+}
+
+type SignOffVerificationInput struct {
+	Notes         string   `json:"notes"`
+	CertificateID int64    `json:"certificate_id"` // アイテムに添付した鑑定書
+	Fields        []string `json:"fields"`         // ロックするフィールド。空の場合はすべて
+}
+
+type verificationUsecase struct {
+	verifications ItemVerificationRepository
+	itemRepo      ItemRepository
+	attachments   AttachmentRepository
+	auditLog      AuditLogRepository
+	transactor    Transactor
+	clock         clock.Clock
+}
+
+func NewVerificationUsecase(verifications ItemVerificationRepository, itemRepo ItemRepository, attachments AttachmentRepository, auditLog AuditLogRepository, transactor Transactor, clock clock.Clock) VerificationUsecase {
+	return &verificationUsecase{
+		verifications: verifications,
+		itemRepo:      itemRepo,
+		attachments:   attachments,
+		auditLog:      auditLog,
+		transactor:    transactor,
+		clock:         clock,
+	}
+}
+
+func (u *verificationUsecase) Get(ctx context.Context, itemID int64) (*entity.ItemVerification, error) {
+	if _, err := u.itemRepo.FindByID(appraiserScope(ctx), itemID); err != nil {
+		return nil, err
+	}
+	return u.verifications.Find(ctx, itemID)
+}
+
+func (u *verificationUsecase) Start(ctx context.Context, itemID int64) (*entity.ItemVerification, error) {
+	appraiserID, err := requireAppraiser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.transition(appraiserScope(ctx), itemID, func(v *entity.ItemVerification) (string, string, error) {
+		if v.Status != entity.VerificationUnverified {
+			return "", "", fmt.Errorf("%w: verification is already %s", domainErrors.ErrVerificationState, v.Status)
+		}
+		v.Start(appraiserID, u.clock.Now())
+		return entity.AuditActionVerificationStart, "", nil
+	})
+}
+
+func (u *verificationUsecase) SignOff(ctx context.Context, itemID int64, input SignOffVerificationInput) (*entity.ItemVerification, error) {
+	appraiserID, err := requireAppraiser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.transition(appraiserScope(ctx), itemID, func(v *entity.ItemVerification) (string, string, error) {
+		if v.Status != entity.VerificationInReview {
+			return "", "", fmt.Errorf("%w: verification must be in_review to sign off, but is %s", domainErrors.ErrVerificationState, v.Status)
+		}
+		if input.CertificateID > 0 {
+			certificate, err := u.attachments.FindByID(ctx, input.CertificateID)
+			if domainErrors.IsNotFoundError(err) || (err == nil && (certificate.ItemID != itemID || certificate.Kind != entity.AttachmentKindDocument)) {
+				return "", "", fmt.Errorf("%w: certificate_id must be a document attached to the item", domainErrors.ErrInvalidInput)
+			}
+			if err != nil {
+				return "", "", err
+			}
+		}
+		if err := v.SignOff(appraiserID, input.Notes, input.CertificateID, input.Fields, u.clock.Now()); err != nil {
+			return "", "", fmt.Errorf("%w: %s", domainErrors.ErrInvalidInput, err.Error())
+		}
+		reason := fmt.Sprintf("locked %s with certificate %d: %s", strings.Join(v.LockedFields, ", "), input.CertificateID, v.Notes)
+		return entity.AuditActionVerificationSignOff, reason, nil
+	})
+}
+
+func (u *verificationUsecase) Reopen(ctx context.Context, itemID int64, reason string) (*entity.ItemVerification, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, domainErrors.ErrReasonRequired
+	}
+
+	// 管理者は持ち主に関係なく検証をやり直せる
+	return u.transition(reqctx.WithAllOwners(ctx), itemID, func(v *entity.ItemVerification) (string, string, error) {
+		if v.Status == entity.VerificationUnverified {
+			return "", "", fmt.Errorf("%w: verification has not been started", domainErrors.ErrVerificationState)
+		}
+		v.Reopen(u.clock.Now())
+		return entity.AuditActionVerificationReopen, reason, nil
+	})
+}
+
+// 検証状態を読み込んで apply で変更し、監査ログと同じトランザクションで保存する
+// apply は監査ログのアクションと理由を返す
+func (u *verificationUsecase) transition(ctx context.Context, itemID int64, apply func(v *entity.ItemVerification) (string, string, error)) (*entity.ItemVerification, error) {
+	var verification *entity.ItemVerification
+	err := u.transactor.Transaction(ctx, func(ctx context.Context) error {
+		if _, err := u.itemRepo.FindByID(ctx, itemID); err != nil {
+			return err
+		}
+		v, err := u.verifications.Find(ctx, itemID)
+		if err != nil {
+			return err
+		}
+
+		action, reason, err := apply(v)
+		if err != nil {
+			return err
+		}
+		if err := u.verifications.Save(ctx, v); err != nil {
+			return err
+		}
+		if u.auditLog != nil {
+			err := u.auditLog.Record(ctx, &entity.AuditEntry{
+				Action:       action,
+				ItemID:       itemID,
+				Actor:        actorFromContext(ctx),
+				Impersonator: impersonatorFromContext(ctx),
+				Reason:       reason,
+				CreatedAt:    u.clock.Now(),
+			})
+			if err != nil {
+				return err
+			}
+		}
+		verification = v
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	reqctx.Logger(ctx).Info("item verification changed", "item_id", itemID, "status", verification.Status)
+	return verification, nil
+}
+
+// 鑑定士のロールを持つユーザーだけが検証できる。検証した鑑定士として記録するため、ユーザーが特定できない場合も許可しない
+func requireAppraiser(ctx context.Context) (int64, error) {
+	userID, ok := reqctx.UserID(ctx)
+	if !ok {
+		return 0, domainErrors.ErrUnauthenticated
+	}
+	if reqctx.UserRole(ctx) != entity.UserRoleAppraiser {
+		return 0, fmt.Errorf("%w: appraiser role required", domainErrors.ErrForbidden)
+	}
+	return userID, nil
+}
+
+// 鑑定士は持ち主に関係なくアイテムを検証するため、すべてのアイテムを対象にする
+func appraiserScope(ctx context.Context) context.Context {
+	if reqctx.UserRole(ctx) == entity.UserRoleAppraiser {
+		return reqctx.WithAllOwners(ctx)
+	}
+	return ctx
+}
+
+// 検証済みのアイテムのロックされたフィールドを変更できないようにする（設定しない場合はロックしない）
+func WithVerifications(repo ItemVerificationRepository) Option {
+	return func(u *itemUsecase) {
+		u.verifications = repo
+	}
+}
+
+// before から after への変更に、ロックされたフィールド（サインオフでロックしたものと組織のルールでロックしたもの）が
+// 含まれていないか確かめる。override の場合は管理者に限って変更を認め、ロックを解除したルールを返す
+func (u *itemUsecase) checkLockedFields(ctx context.Context, before, after *entity.Item, override bool) ([]entity.FieldLock, error) {
+	if u.verifications == nil {
+		return nil, nil
+	}
+	changed := entity.ChangedFields(before, after)
+	if len(changed) == 0 {
+		return nil, nil
+	}
+
+	verification, err := u.verifications.Find(ctx, after.ID)
+	if err != nil {
+		return nil, err
+	}
+	var policy *entity.FieldLockPolicy
+	if u.orgs != nil && after.OrgID != nil {
+		policy, err = u.orgs.FindFieldLockPolicy(ctx, *after.OrgID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve field lock rules: %w", err)
+		}
+	}
+
+	var blocked []entity.FieldLock
+	var messages []string
+	for _, lock := range entity.FieldLocks(verification, policy) {
+		if slices.Contains(changed, lock.Field) {
+			blocked = append(blocked, lock)
+			messages = append(messages, lock.String())
+		}
+	}
+	if len(blocked) == 0 {
+		return nil, nil
+	}
+	if !override {
+		return nil, fmt.Errorf("%w: %s; an admin must reopen the verification or set override_locks with a reason to change it", domainErrors.ErrFieldLocked, strings.Join(messages, ", "))
+	}
+	if err := requireAdmin(ctx); err != nil {
+		return nil, fmt.Errorf("%w: only an admin can override field locks", domainErrors.ErrForbidden)
+	}
+	return blocked, nil
+}
+
+// ロックを解除して変更した場合に監査ログへ残す理由。解除したルールを理由の前に付ける
+func overrideReason(overridden []entity.FieldLock, reason string) string {
+	if len(overridden) == 0 {
+		return reason
+	}
+	rules := make([]string, len(overridden))
+	for i, lock := range overridden {
+		rules[i] = fmt.Sprintf("%s (rule %q)", lock.Field, lock.Rule)
+	}
+	return withReason("override lock on "+strings.Join(rules, ", "), reason)
+}
@@ -0,0 +1,238 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/pkg/clock"
+	"Aicon-assignment/internal/pkg/reqctx"
+)
+
+// MockItemVerificationRepository はテスト用の検証状態のリポジトリ
+type MockItemVerificationRepository struct {
+	mock.Mock
+}
+
+func (m *MockItemVerificationRepository) Find(ctx context.Context, itemID int64) (*entity.ItemVerification, error) {
+	args := m.Called(ctx, itemID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.ItemVerification), args.Error(1)
+}
+
+func (m *MockItemVerificationRepository) Save(ctx context.Context, verification *entity.ItemVerification) error {
+	args := m.Called(ctx, verification)
+	return args.Error(0)
+}
+
+// 検証済みで fields がロックされた状態
+func verifiedItem(itemID int64, fields ...string) *entity.ItemVerification {
+	v := entity.NewItemVerification(itemID)
+	v.Start(5, time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC))
+	if err := v.SignOff(5, "ok", 9, fields, time.Date(2024, 6, 1, 1, 0, 0, 0, time.UTC)); err != nil {
+		panic(err)
+	}
+	return v
+}
+
+func TestVerificationUsecase_Workflow(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	asAppraiser := reqctx.WithUserRole(reqctx.WithUserID(context.Background(), 5), entity.UserRoleAppraiser)
+	asAdmin := reqctx.WithUserRole(reqctx.WithUserID(context.Background(), 1), entity.UserRoleAdmin)
+	item := &entity.Item{ID: 3, Name: "時計1"}
+
+	setup := func(current *entity.ItemVerification) (*MockItemVerificationRepository, *MockAttachmentRepository, *MockAuditLogRepository, VerificationUsecase) {
+		verifications := new(MockItemVerificationRepository)
+		attachments := new(MockAttachmentRepository)
+		auditLog := new(MockAuditLogRepository)
+		items := new(MockItemRepository)
+		// 鑑定士・管理者は持ち主に関係なくアイテムを検証する
+		items.On("FindByID", mock.MatchedBy(reqctx.AllOwners), int64(3)).Return(item, nil)
+		verifications.On("Find", mock.Anything, int64(3)).Return(current, nil)
+		return verifications, attachments, auditLog, NewVerificationUsecase(verifications, items, attachments, auditLog, noTransaction{}, clock.NewFrozen(now))
+	}
+
+	t.Run("正常系: 鑑定士が検証を始めると検証中になり、監査ログに残る", func(t *testing.T) {
+		verifications, _, auditLog, u := setup(entity.NewItemVerification(3))
+		verifications.On("Save", mock.Anything, mock.MatchedBy(func(v *entity.ItemVerification) bool {
+			return v.Status == entity.VerificationInReview && *v.AppraiserID == 5
+		})).Return(nil)
+		auditLog.On("Record", mock.Anything, mock.MatchedBy(func(entry *entity.AuditEntry) bool {
+			return entry.Action == entity.AuditActionVerificationStart && entry.ItemID == 3 && entry.Actor == "user:5"
+		})).Return(nil)
+
+		v, err := u.Start(asAppraiser, 3)
+		require.NoError(t, err)
+		assert.Equal(t, now, *v.StartedAt)
+		verifications.AssertExpectations(t)
+		auditLog.AssertExpectations(t)
+	})
+
+	t.Run("正常系: 鑑定書を添えてサインオフすると検証済みになる", func(t *testing.T) {
+		current := entity.NewItemVerification(3)
+		current.Start(5, now)
+		verifications, attachments, auditLog, u := setup(current)
+		attachments.On("FindByID", mock.Anything, int64(9)).Return(&entity.Attachment{ID: 9, ItemID: 3, Kind: entity.AttachmentKindDocument}, nil)
+		verifications.On("Save", mock.Anything, mock.Anything).Return(nil)
+		auditLog.On("Record", mock.Anything, mock.MatchedBy(func(entry *entity.AuditEntry) bool {
+			return entry.Action == entity.AuditActionVerificationSignOff && entry.Reason == "locked brand, purchase_price with certificate 9: 刻印を確認"
+		})).Return(nil)
+
+		v, err := u.SignOff(asAppraiser, 3, SignOffVerificationInput{Notes: "刻印を確認", CertificateID: 9, Fields: []string{"purchase_price", "brand"}})
+		require.NoError(t, err)
+		assert.True(t, v.Verified())
+		assert.Equal(t, []string{"brand", "purchase_price"}, v.Locked())
+		auditLog.AssertExpectations(t)
+	})
+
+	t.Run("異常系: 他のアイテムの添付ファイルは鑑定書にできない", func(t *testing.T) {
+		current := entity.NewItemVerification(3)
+		current.Start(5, now)
+		verifications, attachments, _, u := setup(current)
+		attachments.On("FindByID", mock.Anything, int64(9)).Return(&entity.Attachment{ID: 9, ItemID: 4, Kind: entity.AttachmentKindDocument}, nil)
+
+		_, err := u.SignOff(asAppraiser, 3, SignOffVerificationInput{Notes: "ok", CertificateID: 9})
+		assert.True(t, domainErrors.IsValidationError(err))
+		verifications.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+
+	t.Run("異常系: 検証を始めていないアイテムはサインオフできない", func(t *testing.T) {
+		_, _, _, u := setup(entity.NewItemVerification(3))
+
+		_, err := u.SignOff(asAppraiser, 3, SignOffVerificationInput{Notes: "ok", CertificateID: 9})
+		assert.ErrorIs(t, err, domainErrors.ErrVerificationState)
+	})
+
+	t.Run("異常系: 鑑定士でなければ検証できない", func(t *testing.T) {
+		_, _, _, u := setup(entity.NewItemVerification(3))
+
+		_, err := u.Start(asAdmin, 3)
+		assert.True(t, domainErrors.IsForbiddenError(err))
+		_, err = u.Start(context.Background(), 3)
+		assert.True(t, domainErrors.IsUnauthenticatedError(err))
+	})
+
+	t.Run("正常系: 管理者がやり直すと未検証に戻り、理由が監査ログに残る", func(t *testing.T) {
+		verifications, _, auditLog, u := setup(verifiedItem(3))
+		verifications.On("Save", mock.Anything, mock.MatchedBy(func(v *entity.ItemVerification) bool {
+			return v.Status == entity.VerificationUnverified && len(v.Locked()) == 0
+		})).Return(nil)
+		auditLog.On("Record", mock.Anything, mock.MatchedBy(func(entry *entity.AuditEntry) bool {
+			return entry.Action == entity.AuditActionVerificationReopen && entry.Reason == "購入日の誤り"
+		})).Return(nil)
+
+		_, err := u.Reopen(asAdmin, 3, " 購入日の誤り ")
+		require.NoError(t, err)
+		verifications.AssertExpectations(t)
+	})
+
+	t.Run("異常系: 理由がない、または管理者でなければやり直せない", func(t *testing.T) {
+		_, _, _, u := setup(verifiedItem(3))
+
+		_, err := u.Reopen(asAdmin, 3, "")
+		assert.True(t, domainErrors.IsReasonRequiredError(err))
+		_, err = u.Reopen(asAppraiser, 3, "誤り")
+		assert.True(t, domainErrors.IsForbiddenError(err))
+	})
+}
+
+func TestItemUsecase_UpdateItem_LockedFields(t *testing.T) {
+	newItem := func() *entity.Item {
+		item, _ := entity.NewItem("時計1", "時計", "ROLEX", 1000000, "2023-01-01")
+		item.ID = 1
+		return item
+	}
+
+	t.Run("異常系: 検証済みでロックされたフィールドは変更できない", func(t *testing.T) {
+		mockRepo := new(MockItemRepository)
+		mockRepo.On("FindByID", mock.Anything, int64(1)).Return(newItem(), nil)
+		verifications := new(MockItemVerificationRepository)
+		verifications.On("Find", mock.Anything, int64(1)).Return(verifiedItem(1, "purchase_price"), nil)
+
+		u := NewItemUsecase(mockRepo, WithVerifications(verifications))
+		_, err := u.UpdateItem(context.Background(), 1, UpdateItemInput{PurchasePrice: intPtr(1200000)})
+		assert.ErrorIs(t, err, domainErrors.ErrFieldLocked)
+		assert.Contains(t, err.Error(), "purchase_price")
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("正常系: ロックされていないフィールドは変更できる", func(t *testing.T) {
+		mockRepo := new(MockItemRepository)
+		mockRepo.On("FindByID", mock.Anything, int64(1)).Return(newItem(), nil)
+		mockRepo.On("Update", mock.Anything, mock.AnythingOfType("*entity.Item")).Return(newItem(), nil)
+		verifications := new(MockItemVerificationRepository)
+		verifications.On("Find", mock.Anything, int64(1)).Return(verifiedItem(1, "purchase_price"), nil)
+
+		u := NewItemUsecase(mockRepo, WithVerifications(verifications))
+		_, err := u.UpdateItem(context.Background(), 1, UpdateItemInput{Name: strPtr("時計2")})
+		require.NoError(t, err)
+	})
+
+	t.Run("異常系: 組織のルールでロックされたフィールドは、どのルールか示して拒否する", func(t *testing.T) {
+		item := newItem()
+		orgID := int64(7)
+		item.OrgID = &orgID
+		mockRepo := new(MockItemRepository)
+		mockRepo.On("FindByID", mock.Anything, int64(1)).Return(item, nil)
+		inReview := entity.NewItemVerification(1)
+		inReview.Start(5, time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC))
+		verifications := new(MockItemVerificationRepository)
+		verifications.On("Find", mock.Anything, int64(1)).Return(inReview, nil)
+		policy := entity.NewFieldLockPolicy(7)
+		require.NoError(t, policy.Replace([]entity.FieldLockRule{{Name: "appraised", Status: entity.VerificationInReview, Fields: []string{"brand"}}}, time.Now()))
+		orgs := new(MockOrganizationRepository)
+		orgs.On("FindFieldLockPolicy", mock.Anything, int64(7)).Return(policy, nil)
+
+		u := NewItemUsecase(mockRepo, WithVerifications(verifications), WithOrganizations(orgs))
+		_, err := u.UpdateItem(context.Background(), 1, UpdateItemInput{Brand: strPtr("OMEGA")})
+		assert.ErrorIs(t, err, domainErrors.ErrFieldLocked)
+		assert.Contains(t, err.Error(), `brand is locked by rule "appraised" (in_review)`)
+	})
+
+	t.Run("正常系: 管理者は理由を添えてロックを解除して変更でき、監査ログに残る", func(t *testing.T) {
+		mockRepo := new(MockItemRepository)
+		mockRepo.On("FindByID", mock.Anything, int64(1)).Return(newItem(), nil)
+		mockRepo.On("Update", mock.Anything, mock.AnythingOfType("*entity.Item")).Return(newItem(), nil)
+		mockRepo.On("RecordPriceChange", mock.Anything, mock.Anything).Return(nil)
+		verifications := new(MockItemVerificationRepository)
+		verifications.On("Find", mock.Anything, int64(1)).Return(verifiedItem(1, "purchase_price"), nil)
+		auditLog := new(MockAuditLogRepository)
+		auditLog.On("Record", mock.Anything, mock.MatchedBy(func(entry *entity.AuditEntry) bool {
+			return entry.Reason == `override lock on purchase_price (rule "sign-off"): 入力ミス`
+		})).Return(nil)
+
+		asAdmin := reqctx.WithUserRole(reqctx.WithUserID(context.Background(), 1), entity.UserRoleAdmin)
+		u := NewItemUsecase(mockRepo, WithVerifications(verifications), WithAuditLog(auditLog))
+		_, err := u.UpdateItem(asAdmin, 1, UpdateItemInput{PurchasePrice: intPtr(1200000), OverrideLocks: true, Reason: strPtr("入力ミス")})
+		require.NoError(t, err)
+		auditLog.AssertExpectations(t)
+	})
+
+	t.Run("異常系: ロックの解除は管理者だけが理由を添えてできる", func(t *testing.T) {
+		update := func(ctx context.Context, input UpdateItemInput) error {
+			mockRepo := new(MockItemRepository)
+			mockRepo.On("FindByID", mock.Anything, int64(1)).Return(newItem(), nil)
+			verifications := new(MockItemVerificationRepository)
+			verifications.On("Find", mock.Anything, int64(1)).Return(verifiedItem(1, "purchase_price"), nil)
+			_, err := NewItemUsecase(mockRepo, WithVerifications(verifications)).UpdateItem(ctx, 1, input)
+			mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+			return err
+		}
+
+		asMember := reqctx.WithUserRole(reqctx.WithUserID(context.Background(), 2), entity.UserRoleMember)
+		err := update(asMember, UpdateItemInput{PurchasePrice: intPtr(1200000), OverrideLocks: true, Reason: strPtr("入力ミス")})
+		assert.True(t, domainErrors.IsForbiddenError(err))
+
+		asAdmin := reqctx.WithUserRole(reqctx.WithUserID(context.Background(), 1), entity.UserRoleAdmin)
+		err = update(asAdmin, UpdateItemInput{PurchasePrice: intPtr(1200000), OverrideLocks: true})
+		assert.True(t, domainErrors.IsReasonRequiredError(err))
+	})
+}
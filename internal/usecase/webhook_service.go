@@ -0,0 +1,455 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/domain/eventschema"
+	"Aicon-assignment/internal/pkg/clock"
+	"Aicon-assignment/internal/pkg/idgen"
+	"Aicon-assignment/internal/pkg/reqctx"
+)
+
+// ドメインイベントの発行先
+type EventPublisher interface {
+	Publish(ctx context.Context, event *entity.Event)
+}
+
+type WebhookUsecase interface {
+	EventPublisher
+
+	CreateWebhook(ctx context.Context, input CreateWebhookInput) (*entity.Webhook, error)
+	ListWebhooks(ctx context.Context) ([]*entity.Webhook, error)
+	GetWebhook(ctx context.Context, id int64) (*entity.Webhook, error)
+	UpdateWebhook(ctx context.Context, id int64, input UpdateWebhookInput) (*entity.Webhook, error)
+	DeleteWebhook(ctx context.Context, id int64) error
+	TestWebhook(ctx context.Context, id int64) (*WebhookTestResult, error)
+	ListDeliveries(ctx context.Context, webhookID int64, status string) ([]*entity.WebhookDelivery, error)
+	GetDeliveryStats(ctx context.Context, webhookID int64) (*entity.WebhookDeliveryStats, error)
+	Redeliver(ctx context.Context, webhookID, deliveryID int64) (*entity.WebhookDelivery, error)
+
+	// 配信中のWebhookの完了を待つ（シャットダウン時用）
+	Wait()
+}
+
+// Webhookの送信処理
+type WebhookSender interface {
+	Send(ctx context.Context, webhook *entity.Webhook, payload []byte) (*WebhookResponse, error)
+}
+
+// 送信先が返したボディは含めない（登録した URL の先の内容を読み出す手段にさせない）
+type WebhookResponse struct {
+	StatusCode int
+	Duration   time.Duration
+}
+
+// WebhookURLChecker is implemented by senders that check a URL's destination before it is saved.
+// It rejects URLs that resolve to addresses the sender refuses to connect to
+type WebhookURLChecker interface {
+	CheckURL(ctx context.Context, rawURL string) error
+}
+
+type CreateWebhookInput struct {
+	URL             string   `json:"url"`
+	Events          []string `json:"events"`
+	EventVersion    string   `json:"event_version"` // 省略時は最新の版
+	WatchFields     []string `json:"watch_fields"`
+	PayloadTemplate string   `json:"payload_template"`
+	Secret          string   `json:"secret"`
+}
+
+type UpdateWebhookInput struct {
+	URL             *string   `json:"url"`
+	Events          *[]string `json:"events"`
+	EventVersion    *string   `json:"event_version"`
+	WatchFields     *[]string `json:"watch_fields"`
+	PayloadTemplate *string   `json:"payload_template"`
+	Active          *bool     `json:"active"`
+}
+
+// テスト送信の結果
+type WebhookTestResult struct {
+	Event      *entity.Event   `json:"event"`
+	Payload    json.RawMessage `json:"payload"`
+	DeliveryID int64           `json:"delivery_id,omitempty"`
+	StatusCode int             `json:"status_code,omitempty"`
+	DurationMs int64           `json:"duration_ms"`
+	Error      string          `json:"error,omitempty"`
+}
+
+const webhookDeliveryTimeout = 10 * time.Second
+
+type webhookUsecase struct {
+	webhookRepo  WebhookRepository
+	deliveryRepo WebhookDeliveryRepository
+	sender       WebhookSender
+	clock        clock.Clock
+	wg           sync.WaitGroup
+}
+
+func NewWebhookUsecase(webhookRepo WebhookRepository, deliveryRepo WebhookDeliveryRepository, sender WebhookSender, c clock.Clock) WebhookUsecase {
+	return &webhookUsecase{
+		webhookRepo:  webhookRepo,
+		deliveryRepo: deliveryRepo,
+		sender:       sender,
+		clock:        c,
+	}
+}
+
+func (u *webhookUsecase) CreateWebhook(ctx context.Context, input CreateWebhookInput) (*entity.Webhook, error) {
+	userID, err := reqctx.RequireUserID(ctx)
+	if err != nil {
+		return nil, domainErrors.ErrUnauthenticated
+	}
+
+	webhook, err := entity.NewWebhook(input.URL, input.Events, input.PayloadTemplate, input.Secret, u.clock.Now())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrInvalidInput, err.Error())
+	}
+	webhook.OwnerID = &userID
+
+	webhook.WatchFields = input.WatchFields
+	if err := webhook.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrInvalidInput, err.Error())
+	}
+
+	webhook.EventVersion = input.EventVersion
+	if webhook.EventVersion == "" {
+		webhook.EventVersion = eventschema.Default.Latest()
+	}
+	if err := eventschema.Default.Validate(webhook.EventVersion); err != nil {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrInvalidInput, err.Error())
+	}
+	if err := u.checkURL(ctx, webhook.URL); err != nil {
+		return nil, err
+	}
+
+	created, err := u.webhookRepo.Create(ctx, webhook)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create webhook: %w", err)
+	}
+
+	return created, nil
+}
+
+// 呼び出し元のユーザーが扱える Webhook だけを返す
+func (u *webhookUsecase) ListWebhooks(ctx context.Context) ([]*entity.Webhook, error) {
+	webhooks, err := u.webhookRepo.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve webhooks: %w", err)
+	}
+
+	visible := make([]*entity.Webhook, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		if ownsWebhook(ctx, webhook) {
+			visible = append(visible, webhook)
+		}
+	}
+	return visible, nil
+}
+
+func (u *webhookUsecase) GetWebhook(ctx context.Context, id int64) (*entity.Webhook, error) {
+	if id <= 0 {
+		return nil, domainErrors.ErrInvalidInput
+	}
+
+	webhook, err := u.webhookRepo.FindByID(ctx, id)
+	if err != nil {
+		if domainErrors.IsNotFoundError(err) {
+			return nil, domainErrors.ErrWebhookNotFound
+		}
+		return nil, fmt.Errorf("failed to retrieve webhook: %w", err)
+	}
+	// 他のユーザーの Webhook は存在しないものとして扱う
+	if !ownsWebhook(ctx, webhook) {
+		return nil, domainErrors.ErrWebhookNotFound
+	}
+	return webhook, nil
+}
+
+// 登録したユーザーか。持ち主を記録する前に登録した Webhook（OwnerID が nil）は、ログインしているユーザーなら扱える
+func ownsWebhook(ctx context.Context, webhook *entity.Webhook) bool {
+	userID, ok := reqctx.UserID(ctx)
+	if !ok {
+		return false
+	}
+	return webhook.OwnerID == nil || *webhook.OwnerID == userID
+}
+
+// 送信先がサーバーの内側のアドレスでないかを送信の実装で確かめる
+func (u *webhookUsecase) checkURL(ctx context.Context, rawURL string) error {
+	checker, ok := u.sender.(WebhookURLChecker)
+	if !ok {
+		return nil
+	}
+	if err := checker.CheckURL(ctx, rawURL); err != nil {
+		return fmt.Errorf("%w: %s", domainErrors.ErrInvalidInput, err.Error())
+	}
+	return nil
+}
+
+func (u *webhookUsecase) UpdateWebhook(ctx context.Context, id int64, input UpdateWebhookInput) (*entity.Webhook, error) {
+	webhook, err := u.GetWebhook(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.URL != nil {
+		webhook.URL = *input.URL
+	}
+	if input.Events != nil {
+		webhook.Events = *input.Events
+	}
+	if input.EventVersion != nil {
+		webhook.EventVersion = *input.EventVersion
+	}
+	if input.WatchFields != nil {
+		webhook.WatchFields = *input.WatchFields
+	}
+	if input.PayloadTemplate != nil {
+		webhook.PayloadTemplate = *input.PayloadTemplate
+	}
+	if input.Active != nil {
+		webhook.Active = *input.Active
+	}
+	webhook.UpdatedAt = u.clock.Now()
+
+	if err := webhook.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrInvalidInput, err.Error())
+	}
+	if err := eventschema.Default.Validate(webhook.EventVersion); err != nil {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrInvalidInput, err.Error())
+	}
+	if input.URL != nil {
+		if err := u.checkURL(ctx, webhook.URL); err != nil {
+			return nil, err
+		}
+	}
+
+	updated, err := u.webhookRepo.Update(ctx, webhook)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update webhook: %w", err)
+	}
+	return updated, nil
+}
+
+func (u *webhookUsecase) DeleteWebhook(ctx context.Context, id int64) error {
+	if _, err := u.GetWebhook(ctx, id); err != nil {
+		return err
+	}
+
+	if err := u.webhookRepo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	return nil
+}
+
+// サンプルイベントでペイロードを組み立てて送信する
+// テンプレートの描画に失敗した場合はバリデーションエラー、送信の失敗は結果に含めて返す
+func (u *webhookUsecase) TestWebhook(ctx context.Context, id int64) (*WebhookTestResult, error) {
+	webhook, err := u.GetWebhook(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	event := u.sampleEvent(ctx, webhook)
+	payload, err := render(webhook, event)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrInvalidInput, err.Error())
+	}
+
+	delivery := u.deliver(ctx, webhook, event.ID, event.Type, payload, schemaVersionOf(webhook), nil)
+
+	return &WebhookTestResult{
+		Event:      event,
+		Payload:    payload,
+		DeliveryID: delivery.ID,
+		StatusCode: delivery.StatusCode,
+		DurationMs: delivery.DurationMs,
+		Error:      delivery.Error,
+	}, nil
+}
+
+func (u *webhookUsecase) ListDeliveries(ctx context.Context, webhookID int64, status string) ([]*entity.WebhookDelivery, error) {
+	if err := entity.ValidateDeliveryStatus(status); err != nil {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrInvalidInput, err.Error())
+	}
+	if _, err := u.GetWebhook(ctx, webhookID); err != nil {
+		return nil, err
+	}
+
+	deliveries, err := u.deliveryRepo.FindByWebhookID(ctx, webhookID, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+func (u *webhookUsecase) GetDeliveryStats(ctx context.Context, webhookID int64) (*entity.WebhookDeliveryStats, error) {
+	if _, err := u.GetWebhook(ctx, webhookID); err != nil {
+		return nil, err
+	}
+
+	stats, err := u.deliveryRepo.GetStats(ctx, webhookID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve delivery stats: %w", err)
+	}
+	return stats, nil
+}
+
+// 過去の配信と同じペイロードを同期的に再送する
+func (u *webhookUsecase) Redeliver(ctx context.Context, webhookID, deliveryID int64) (*entity.WebhookDelivery, error) {
+	webhook, err := u.GetWebhook(ctx, webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	original, err := u.deliveryRepo.FindByID(ctx, deliveryID)
+	if err != nil {
+		if domainErrors.IsNotFoundError(err) {
+			return nil, domainErrors.ErrDeliveryNotFound
+		}
+		return nil, fmt.Errorf("failed to retrieve delivery: %w", err)
+	}
+	if original.WebhookID != webhook.ID {
+		return nil, domainErrors.ErrDeliveryNotFound
+	}
+
+	// 記録後に購読する版が上がっていれば、記録したペイロードを新しい版に変換して送る
+	payload, version := []byte(original.Payload), original.SchemaVersion
+	if target := schemaVersionOf(webhook); version != "" && target != "" && version != target {
+		if payload, err = eventschema.Default.Upcast(payload, version, target); err != nil {
+			return nil, fmt.Errorf("%w: %s", domainErrors.ErrInvalidInput, err.Error())
+		}
+		version = target
+	}
+
+	return u.deliver(ctx, webhook, original.EventID, original.EventType, payload, version, &original.ID), nil
+}
+
+// 購読している版に変換してペイロードを組み立てる
+func render(webhook *entity.Webhook, event *entity.Event) ([]byte, error) {
+	encoded, err := eventschema.Default.Encode(event, eventVersionOf(webhook))
+	if err != nil {
+		return nil, err
+	}
+	return webhook.RenderPayload(encoded)
+}
+
+// 版の指定がない購読は版を導入する前の形（v1）で受け取っていた
+func eventVersionOf(webhook *entity.Webhook) string {
+	if webhook.EventVersion == "" {
+		return eventschema.V1
+	}
+	return webhook.EventVersion
+}
+
+// テンプレートなしのペイロードのみスキーマの版に従う
+func schemaVersionOf(webhook *entity.Webhook) string {
+	if webhook.PayloadTemplate != "" {
+		return ""
+	}
+	return eventVersionOf(webhook)
+}
+
+// 送信して配信記録を残す
+// 記録に失敗しても送信結果は返す
+func (u *webhookUsecase) deliver(ctx context.Context, webhook *entity.Webhook, eventID, eventType string, payload []byte, schemaVersion string, redeliveryOf *int64) *entity.WebhookDelivery {
+	delivery := &entity.WebhookDelivery{
+		WebhookID:     webhook.ID,
+		EventID:       eventID,
+		EventType:     eventType,
+		Payload:       payload,
+		SchemaVersion: schemaVersion,
+		RedeliveryOf:  redeliveryOf,
+		CreatedAt:     u.clock.Now(),
+	}
+
+	resp, err := u.sender.Send(ctx, webhook, payload)
+	if resp != nil {
+		delivery.StatusCode = resp.StatusCode
+		delivery.DurationMs = resp.Duration.Milliseconds()
+	}
+	if err != nil {
+		delivery.Error = err.Error()
+	} else {
+		delivery.Success = true
+	}
+
+	if recordErr := u.deliveryRepo.Record(ctx, delivery); recordErr != nil {
+		reqctx.Logger(ctx).Error("failed to record webhook delivery", "webhook_id", webhook.ID, "event_id", eventID, "error", recordErr)
+	}
+
+	return delivery
+}
+
+// イベントを購読中のWebhookに非同期で配信する
+func (u *webhookUsecase) Publish(ctx context.Context, event *entity.Event) {
+	logger := reqctx.Logger(ctx)
+
+	webhooks, err := u.webhookRepo.FindAll(ctx)
+	if err != nil {
+		logger.Error("failed to load webhooks", "event_id", event.ID, "error", err)
+		return
+	}
+
+	for _, webhook := range webhooks {
+		if !webhook.Active || !webhook.Wants(event) {
+			continue
+		}
+
+		payload, err := render(webhook, event)
+		if err != nil {
+			logger.Error("failed to render webhook payload", "webhook_id", webhook.ID, "event_id", event.ID, "error", err)
+			continue
+		}
+
+		u.wg.Add(1)
+		go func(webhook *entity.Webhook) {
+			defer u.wg.Done()
+
+			// リクエストの終了後も配信を続けるためキャンセルを引き継がない
+			deliveryCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), webhookDeliveryTimeout)
+			defer cancel()
+
+			delivery := u.deliver(deliveryCtx, webhook, event.ID, event.Type, payload, schemaVersionOf(webhook), nil)
+			if !delivery.Success {
+				logger.Warn("webhook delivery failed", "webhook_id", webhook.ID, "event_id", event.ID, "error", delivery.Error)
+				return
+			}
+			logger.Info("webhook delivered", "webhook_id", webhook.ID, "event_id", event.ID, "status", delivery.StatusCode)
+		}(webhook)
+	}
+}
+
+func (u *webhookUsecase) Wait() {
+	u.wg.Wait()
+}
+
+func (u *webhookUsecase) sampleEvent(ctx context.Context, webhook *entity.Webhook) *entity.Event {
+	eventType := entity.EventItemCreated
+	for _, event := range webhook.Events {
+		if event != entity.WebhookAllEvents {
+			eventType = event
+			break
+		}
+	}
+
+	now := u.clock.Now()
+	item, _ := entity.NewItemAt(now, "ロレックス デイトナ", "時計", "ROLEX", 1500000, "2023-01-15")
+	item.ID = 1
+
+	return &entity.Event{
+		ID:         idgen.NewRandomID(),
+		Type:       eventType,
+		OccurredAt: now,
+		Actor:      actorFromContext(ctx),
+		ItemID:     item.ID,
+		Item:       item,
+	}
+}
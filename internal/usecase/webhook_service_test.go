@@ -0,0 +1,478 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/pkg/clock"
+	"Aicon-assignment/internal/pkg/reqctx"
+)
+
+// テストの Webhook（持ち主を記録していない）を扱う管理者
+var asWebhookOwner = reqctx.WithUserID(context.Background(), 1)
+
+// MockWebhookRepository はテスト用のWebhookリポジトリ
+type MockWebhookRepository struct {
+	mock.Mock
+}
+
+func (m *MockWebhookRepository) FindAll(ctx context.Context) ([]*entity.Webhook, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.Webhook), args.Error(1)
+}
+
+func (m *MockWebhookRepository) FindByID(ctx context.Context, id int64) (*entity.Webhook, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Webhook), args.Error(1)
+}
+
+func (m *MockWebhookRepository) Create(ctx context.Context, webhook *entity.Webhook) (*entity.Webhook, error) {
+	args := m.Called(ctx, webhook)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Webhook), args.Error(1)
+}
+
+func (m *MockWebhookRepository) Update(ctx context.Context, webhook *entity.Webhook) (*entity.Webhook, error) {
+	args := m.Called(ctx, webhook)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Webhook), args.Error(1)
+}
+
+func (m *MockWebhookRepository) Delete(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// MockWebhookSender はテスト用の送信処理
+type MockWebhookSender struct {
+	mock.Mock
+}
+
+func (m *MockWebhookSender) Send(ctx context.Context, webhook *entity.Webhook, payload []byte) (*WebhookResponse, error) {
+	args := m.Called(ctx, webhook, payload)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*WebhookResponse), args.Error(1)
+}
+
+// MockWebhookDeliveryRepository はテスト用の配信記録リポジトリ
+type MockWebhookDeliveryRepository struct {
+	mock.Mock
+}
+
+func (m *MockWebhookDeliveryRepository) Record(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	args := m.Called(ctx, delivery)
+	return args.Error(0)
+}
+
+func (m *MockWebhookDeliveryRepository) FindByID(ctx context.Context, id int64) (*entity.WebhookDelivery, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookDeliveryRepository) FindByWebhookID(ctx context.Context, webhookID int64, status string) ([]*entity.WebhookDelivery, error) {
+	args := m.Called(ctx, webhookID, status)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookDeliveryRepository) GetStats(ctx context.Context, webhookID int64) (*entity.WebhookDeliveryStats, error) {
+	args := m.Called(ctx, webhookID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.WebhookDeliveryStats), args.Error(1)
+}
+
+func (m *MockWebhookDeliveryRepository) CountBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	args := m.Called(ctx, cutoff)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockWebhookDeliveryRepository) DeleteBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	args := m.Called(ctx, cutoff)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func TestWebhookUsecase_TestWebhook(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		webhook        *entity.Webhook
+		setupSender    func(*MockWebhookSender)
+		expectedErr    error
+		expectedStatus int
+		expectedError  string
+	}{
+		{
+			name: "正常系: テンプレートで描画して送信",
+			webhook: &entity.Webhook{
+				ID: 1, URL: "https://example.com/hook", Events: []string{entity.EventItemUpdated},
+				PayloadTemplate: `{"type": {{json .Type}}, "name": {{json .Item.Name}}}`,
+			},
+			setupSender: func(sender *MockWebhookSender) {
+				sender.On("Send", mock.Anything, mock.Anything, []byte(`{"type": "item.updated", "name": "ロレックス デイトナ"}`)).
+					Return(&WebhookResponse{StatusCode: 200, Duration: 30 * time.Millisecond}, nil)
+			},
+			expectedStatus: 200,
+		},
+		{
+			name: "正常系: 送信先のエラーは結果に含める",
+			webhook: &entity.Webhook{
+				ID: 1, URL: "https://example.com/hook", Events: []string{entity.EventItemCreated},
+			},
+			setupSender: func(sender *MockWebhookSender) {
+				sender.On("Send", mock.Anything, mock.Anything, mock.Anything).
+					Return(&WebhookResponse{StatusCode: 500}, errors.New("unexpected status code: 500"))
+			},
+			expectedStatus: 500,
+			expectedError:  "unexpected status code: 500",
+		},
+		{
+			name: "正常系: v2を購読している場合は金額に通貨が付く",
+			webhook: &entity.Webhook{
+				ID: 1, URL: "https://example.com/hook", Events: []string{entity.EventItemCreated}, EventVersion: "v2",
+				PayloadTemplate: `{"price": {{json .Item.PurchasePrice}}}`,
+			},
+			setupSender: func(sender *MockWebhookSender) {
+				sender.On("Send", mock.Anything, mock.Anything, []byte(`{"price": {"amount":1500000,"currency":"JPY"}}`)).
+					Return(&WebhookResponse{StatusCode: 200}, nil)
+			},
+			expectedStatus: 200,
+		},
+		{
+			name: "異常系: JSONにならないテンプレート",
+			webhook: &entity.Webhook{
+				ID: 1, URL: "https://example.com/hook", Events: []string{entity.EventItemCreated},
+				PayloadTemplate: `name={{.Item.Name}}`,
+			},
+			setupSender: func(sender *MockWebhookSender) {
+				// Sendは呼ばれない
+			},
+			expectedErr: domainErrors.ErrInvalidInput,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockWebhookRepository)
+			repo.On("FindByID", mock.Anything, int64(1)).Return(tt.webhook, nil)
+			sender := new(MockWebhookSender)
+			tt.setupSender(sender)
+			deliveries := new(MockWebhookDeliveryRepository)
+			deliveries.On("Record", mock.Anything, mock.Anything).Return(nil).Maybe()
+
+			usecase := NewWebhookUsecase(repo, deliveries, sender, clock.NewFrozen(now))
+			result, err := usecase.TestWebhook(asWebhookOwner, 1)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, result)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedStatus, result.StatusCode)
+				assert.Equal(t, tt.expectedError, result.Error)
+				assert.NotEmpty(t, result.Payload)
+			}
+
+			repo.AssertExpectations(t)
+			sender.AssertExpectations(t)
+		})
+	}
+}
+
+func TestWebhookUsecase_Publish(t *testing.T) {
+	webhooks := []*entity.Webhook{
+		{ID: 1, URL: "https://example.com/created", Events: []string{entity.EventItemCreated}, Active: true},
+		{ID: 2, URL: "https://example.com/deleted", Events: []string{entity.EventItemDeleted}, Active: true},
+		{ID: 3, URL: "https://example.com/inactive", Events: []string{"*"}, Active: false},
+	}
+
+	repo := new(MockWebhookRepository)
+	repo.On("FindAll", mock.Anything).Return(webhooks, nil)
+	sender := new(MockWebhookSender)
+	sender.On("Send", mock.Anything, webhooks[0], mock.Anything).Return(&WebhookResponse{StatusCode: 204}, nil).Once()
+	deliveries := new(MockWebhookDeliveryRepository)
+	deliveries.On("Record", mock.Anything, mock.MatchedBy(func(d *entity.WebhookDelivery) bool {
+		return d.WebhookID == 1 && d.EventID == "ev-1" && d.Success && d.StatusCode == 204
+	})).Return(nil).Once()
+
+	usecase := NewWebhookUsecase(repo, deliveries, sender, clock.System{})
+	item, _ := entity.NewItem("時計1", "時計", "ROLEX", 1000000, "2023-01-01")
+	usecase.Publish(context.Background(), &entity.Event{ID: "ev-1", Type: entity.EventItemCreated, Item: item})
+	usecase.Wait()
+
+	// 購読していない・無効なWebhookには送信しない
+	sender.AssertExpectations(t)
+	deliveries.AssertExpectations(t)
+}
+
+func TestWebhookUsecase_PublishWatchFields(t *testing.T) {
+	webhooks := []*entity.Webhook{
+		{ID: 1, URL: "https://example.com/price", Events: []string{entity.EventItemUpdated}, WatchFields: []string{"purchase_price"}, Active: true},
+		{ID: 2, URL: "https://example.com/name", Events: []string{entity.EventItemUpdated}, WatchFields: []string{"name"}, Active: true},
+	}
+
+	repo := new(MockWebhookRepository)
+	repo.On("FindAll", mock.Anything).Return(webhooks, nil)
+	sender := new(MockWebhookSender)
+	sender.On("Send", mock.Anything, webhooks[0], mock.Anything).Return(&WebhookResponse{StatusCode: 204}, nil).Once()
+	deliveries := new(MockWebhookDeliveryRepository)
+	deliveries.On("Record", mock.Anything, mock.Anything).Return(nil).Once()
+
+	usecase := NewWebhookUsecase(repo, deliveries, sender, clock.System{})
+	item, _ := entity.NewItem("時計1", "時計", "ROLEX", 1200000, "2023-01-01")
+	usecase.Publish(context.Background(), &entity.Event{ID: "ev-1", Type: entity.EventItemUpdated, Item: item, ChangedFields: []string{"purchase_price"}})
+	usecase.Wait()
+
+	// 監視していないフィールドの変更は送信しない
+	sender.AssertExpectations(t)
+	deliveries.AssertExpectations(t)
+}
+
+func TestWebhookUsecase_ListDeliveries(t *testing.T) {
+	webhook := &entity.Webhook{ID: 1, URL: "https://example.com/hook", Events: []string{"*"}}
+
+	tests := []struct {
+		name        string
+		status      string
+		setupMock   func(*MockWebhookRepository, *MockWebhookDeliveryRepository)
+		expectedErr error
+	}{
+		{
+			name:   "正常系: 失敗した配信で絞り込む",
+			status: entity.DeliveryStatusFailed,
+			setupMock: func(repo *MockWebhookRepository, deliveries *MockWebhookDeliveryRepository) {
+				repo.On("FindByID", mock.Anything, int64(1)).Return(webhook, nil)
+				deliveries.On("FindByWebhookID", mock.Anything, int64(1), entity.DeliveryStatusFailed).
+					Return([]*entity.WebhookDelivery{{ID: 3, WebhookID: 1}}, nil)
+			},
+		},
+		{
+			name:   "異常系: 不正なステータス",
+			status: "pending",
+			setupMock: func(repo *MockWebhookRepository, deliveries *MockWebhookDeliveryRepository) {
+				// リポジトリは呼ばれない
+			},
+			expectedErr: domainErrors.ErrInvalidInput,
+		},
+		{
+			name:   "異常系: Webhookが存在しない",
+			status: "",
+			setupMock: func(repo *MockWebhookRepository, deliveries *MockWebhookDeliveryRepository) {
+				repo.On("FindByID", mock.Anything, int64(1)).Return(nil, domainErrors.ErrWebhookNotFound)
+			},
+			expectedErr: domainErrors.ErrWebhookNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockWebhookRepository)
+			deliveries := new(MockWebhookDeliveryRepository)
+			tt.setupMock(repo, deliveries)
+
+			usecase := NewWebhookUsecase(repo, deliveries, new(MockWebhookSender), clock.System{})
+			result, err := usecase.ListDeliveries(asWebhookOwner, 1, tt.status)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				require.NoError(t, err)
+				assert.Len(t, result, 1)
+			}
+
+			repo.AssertExpectations(t)
+			deliveries.AssertExpectations(t)
+		})
+	}
+}
+
+func TestWebhookUsecase_Redeliver(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	webhook := &entity.Webhook{ID: 1, URL: "https://example.com/hook", Events: []string{"*"}, EventVersion: "v2"}
+	payload := []byte(`{"type":"item.created"}`)
+
+	tests := []struct {
+		name        string
+		deliveryID  int64
+		setupMock   func(*MockWebhookDeliveryRepository, *MockWebhookSender)
+		expectedErr error
+	}{
+		{
+			name:       "正常系: 同じペイロードで再送して記録する",
+			deliveryID: 5,
+			setupMock: func(deliveries *MockWebhookDeliveryRepository, sender *MockWebhookSender) {
+				deliveries.On("FindByID", mock.Anything, int64(5)).Return(&entity.WebhookDelivery{
+					ID: 5, WebhookID: 1, EventID: "ev-1", EventType: entity.EventItemCreated, Payload: payload,
+				}, nil)
+				sender.On("Send", mock.Anything, webhook, []byte(payload)).Return(&WebhookResponse{StatusCode: 200}, nil)
+				deliveries.On("Record", mock.Anything, mock.MatchedBy(func(d *entity.WebhookDelivery) bool {
+					return d.RedeliveryOf != nil && *d.RedeliveryOf == 5 && d.EventID == "ev-1" && d.Success
+				})).Return(nil)
+			},
+		},
+		{
+			name:       "正常系: 記録後に購読する版が上がった場合は変換して再送する",
+			deliveryID: 7,
+			setupMock: func(deliveries *MockWebhookDeliveryRepository, sender *MockWebhookSender) {
+				deliveries.On("FindByID", mock.Anything, int64(7)).Return(&entity.WebhookDelivery{
+					ID: 7, WebhookID: 1, EventID: "ev-1", EventType: entity.EventItemCreated, SchemaVersion: "v1",
+					Payload: []byte(`{"id":"ev-1","type":"item.created","item_id":1,"item":{"id":1,"purchase_price":1000}}`),
+				}, nil)
+				sender.On("Send", mock.Anything, webhook, mock.MatchedBy(func(payload []byte) bool {
+					return strings.Contains(string(payload), `"schema_version":"v2"`) &&
+						strings.Contains(string(payload), `"purchase_price":{"amount":1000,"currency":"JPY"}`)
+				})).Return(&WebhookResponse{StatusCode: 200}, nil)
+				deliveries.On("Record", mock.Anything, mock.MatchedBy(func(d *entity.WebhookDelivery) bool {
+					return d.SchemaVersion == "v2"
+				})).Return(nil)
+			},
+		},
+		{
+			name:       "異常系: 別のWebhookの配信",
+			deliveryID: 6,
+			setupMock: func(deliveries *MockWebhookDeliveryRepository, sender *MockWebhookSender) {
+				deliveries.On("FindByID", mock.Anything, int64(6)).Return(&entity.WebhookDelivery{ID: 6, WebhookID: 2}, nil)
+			},
+			expectedErr: domainErrors.ErrDeliveryNotFound,
+		},
+		{
+			name:       "異常系: 配信が存在しない",
+			deliveryID: 99,
+			setupMock: func(deliveries *MockWebhookDeliveryRepository, sender *MockWebhookSender) {
+				deliveries.On("FindByID", mock.Anything, int64(99)).Return(nil, domainErrors.ErrDeliveryNotFound)
+			},
+			expectedErr: domainErrors.ErrDeliveryNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockWebhookRepository)
+			repo.On("FindByID", mock.Anything, int64(1)).Return(webhook, nil)
+			deliveries := new(MockWebhookDeliveryRepository)
+			sender := new(MockWebhookSender)
+			tt.setupMock(deliveries, sender)
+
+			usecase := NewWebhookUsecase(repo, deliveries, sender, clock.NewFrozen(now))
+			result, err := usecase.Redeliver(asWebhookOwner, 1, tt.deliveryID)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, result)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, 200, result.StatusCode)
+				assert.Equal(t, now, result.CreatedAt)
+			}
+
+			deliveries.AssertExpectations(t)
+			sender.AssertExpectations(t)
+		})
+	}
+}
+
+// 送信先を確かめる送信処理
+type checkingWebhookSender struct {
+	MockWebhookSender
+	rejected string
+}
+
+func (s *checkingWebhookSender) CheckURL(ctx context.Context, rawURL string) error {
+	if rawURL == s.rejected {
+		return errors.New("webhook url must not point to a loopback, private, link-local or metadata address")
+	}
+	return nil
+}
+
+func TestWebhookUsecase_CreateWebhook(t *testing.T) {
+	input := CreateWebhookInput{URL: "https://example.com/hook", Events: []string{entity.EventItemCreated}}
+
+	t.Run("正常系: 登録したユーザーを持ち主にする", func(t *testing.T) {
+		webhookRepo := new(MockWebhookRepository)
+		webhookRepo.On("Create", mock.Anything, mock.MatchedBy(func(w *entity.Webhook) bool {
+			return w.OwnerID != nil && *w.OwnerID == 7
+		})).Return(&entity.Webhook{ID: 1}, nil)
+		usecase := NewWebhookUsecase(webhookRepo, nil, &checkingWebhookSender{}, clock.NewFrozen(time.Now()))
+
+		_, err := usecase.CreateWebhook(reqctx.WithUserID(context.Background(), 7), input)
+		require.NoError(t, err)
+		webhookRepo.AssertExpectations(t)
+	})
+
+	t.Run("異常系: サーバーの内側のアドレスは登録しない", func(t *testing.T) {
+		sender := &checkingWebhookSender{rejected: "http://169.254.169.254/latest/meta-data"}
+		usecase := NewWebhookUsecase(new(MockWebhookRepository), nil, sender, clock.NewFrozen(time.Now()))
+
+		_, err := usecase.CreateWebhook(asWebhookOwner, CreateWebhookInput{URL: sender.rejected, Events: input.Events})
+		assert.ErrorIs(t, err, domainErrors.ErrInvalidInput)
+		assert.ErrorContains(t, err, "metadata address")
+	})
+
+	t.Run("異常系: ログインしていない", func(t *testing.T) {
+		usecase := NewWebhookUsecase(new(MockWebhookRepository), nil, &checkingWebhookSender{}, clock.NewFrozen(time.Now()))
+
+		_, err := usecase.CreateWebhook(context.Background(), input)
+		assert.ErrorIs(t, err, domainErrors.ErrUnauthenticated)
+	})
+}
+
+func TestWebhookUsecase_Ownership(t *testing.T) {
+	owner, other := int64(7), int64(8)
+	own := &entity.Webhook{ID: 1, OwnerID: &owner}
+	others := &entity.Webhook{ID: 2, OwnerID: &other}
+	legacy := &entity.Webhook{ID: 3}
+
+	webhookRepo := new(MockWebhookRepository)
+	webhookRepo.On("FindAll", mock.Anything).Return([]*entity.Webhook{own, others, legacy}, nil)
+	webhookRepo.On("FindByID", mock.Anything, int64(2)).Return(others, nil)
+	usecase := NewWebhookUsecase(webhookRepo, nil, &MockWebhookSender{}, clock.NewFrozen(time.Now()))
+	ctx := reqctx.WithUserID(context.Background(), owner)
+
+	t.Run("正常系: 自分と持ち主のない Webhook だけを返す", func(t *testing.T) {
+		webhooks, err := usecase.ListWebhooks(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []*entity.Webhook{own, legacy}, webhooks)
+	})
+
+	t.Run("異常系: 他のユーザーの Webhook は見つからない", func(t *testing.T) {
+		_, err := usecase.GetWebhook(ctx, 2)
+		assert.ErrorIs(t, err, domainErrors.ErrWebhookNotFound)
+
+		assert.ErrorIs(t, usecase.DeleteWebhook(ctx, 2), domainErrors.ErrWebhookNotFound)
+		webhookRepo.AssertNotCalled(t, "Delete", mock.Anything, int64(2))
+	})
+
+	t.Run("異常系: ログインしていない場合はどれも扱えない", func(t *testing.T) {
+		webhooks, err := usecase.ListWebhooks(context.Background())
+		require.NoError(t, err)
+		assert.Empty(t, webhooks)
+	})
+}
@@ -12,17 +12,540 @@ CREATE TABLE IF NOT EXISTS items (
     purchase_date DATE NOT NULL COMMENT 'Purchase date in YYYY-MM-DD format',
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP COMMENT 'Record creation timestamp',
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT 'Record update timestamp',
+    deleted_at TIMESTAMP NULL DEFAULT NULL COMMENT 'Soft deletion timestamp, set when merged into another item or split',
+    merged_into BIGINT NULL DEFAULT NULL COMMENT 'Surviving item this record was merged into',
+    organization_id BIGINT NULL DEFAULT NULL COMMENT 'Organization owning the item, NULL if unassigned',
+    owner_id BIGINT NULL DEFAULT NULL COMMENT 'User owning the item; only visible to that user through the API, NULL for items created without a user',
     
     INDEX idx_category (category),
     INDEX idx_brand (brand),
     INDEX idx_purchase_date (purchase_date),
-    INDEX idx_created_at (created_at)
+    INDEX idx_created_at (created_at),
+    INDEX idx_deleted_at (deleted_at),
+    INDEX idx_organization_id (organization_id, deleted_at),
+    INDEX idx_owner_id (owner_id, deleted_at, created_at)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Table for managing valuable items and collections';
 
--- Insert sample data for testing
-INSERT INTO items (name, category, brand, purchase_price, purchase_date) VALUES
-('ロレックス デイトナ', '時計', 'ROLEX', 1500000, '2023-01-15'),
-('エルメス バーキン', 'バッグ', 'HERMÈS', 2000000, '2023-02-20'),
-('ティファニー ネックレス', 'ジュエリー', 'Tiffany & Co.', 300000, '2023-03-10'),
-('ルブタン パンプス', '靴', 'Christian Louboutin', 150000, '2023-04-05'),
-('アップルウォッチ', 'その他', 'Apple', 50000, '2023-05-12');
\ No newline at end of file
+-- Price change history of items, kept for dispute resolution
+CREATE TABLE IF NOT EXISTS item_price_history (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    item_id BIGINT NOT NULL COMMENT 'Changed item',
+    old_price INT NOT NULL COMMENT 'Purchase price before the change',
+    new_price INT NOT NULL COMMENT 'Purchase price after the change',
+    actor VARCHAR(100) NOT NULL COMMENT 'Who made the change',
+    reason VARCHAR(500) NOT NULL DEFAULT '' COMMENT 'Optional reason given in the PATCH body',
+    changed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT 'When the change was made',
+
+    INDEX idx_item_changed_at (item_id, changed_at),
+    CONSTRAINT fk_price_history_item FOREIGN KEY (item_id) REFERENCES items (id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Purchase price change history';
+
+-- Audit log of item operations
+-- item_id has no foreign key so entries survive deletion of the item
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    action VARCHAR(50) NOT NULL COMMENT 'Operation, e.g. item.delete',
+    item_id BIGINT NOT NULL COMMENT 'Target item',
+    actor VARCHAR(100) NOT NULL COMMENT 'Who performed the operation',
+    impersonator VARCHAR(100) NOT NULL DEFAULT '' COMMENT 'Administrator impersonating the actor, if any',
+    reason VARCHAR(500) NOT NULL DEFAULT '' COMMENT 'Reason given for the operation',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT 'When the operation was performed',
+
+    INDEX idx_item_id (item_id),
+    INDEX idx_created_at (created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Audit log of item operations';
+
+-- Webhook subscriptions
+CREATE TABLE IF NOT EXISTS webhooks (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    owner_id BIGINT NULL COMMENT 'User who registered the webhook, NULL for webhooks registered before owners were recorded',
+    url VARCHAR(2048) NOT NULL COMMENT 'Delivery URL',
+    events VARCHAR(500) NOT NULL COMMENT 'Comma separated event types, * for all',
+    watch_fields VARCHAR(255) NOT NULL DEFAULT '' COMMENT 'Comma separated item fields whose changes trigger item.updated, empty for all',
+    event_version VARCHAR(10) NOT NULL DEFAULT 'v1' COMMENT 'Pinned event schema version',
+    payload_template TEXT NOT NULL COMMENT 'Go text/template for the payload, empty for the default shape',
+    secret VARCHAR(255) NOT NULL DEFAULT '' COMMENT 'Shared secret for X-Webhook-Signature',
+    active BOOLEAN NOT NULL DEFAULT TRUE COMMENT 'Whether deliveries are enabled',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP COMMENT 'Record creation timestamp',
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT 'Record update timestamp'
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Webhook subscriptions';
+
+-- Create webhook_deliveries table
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    webhook_id BIGINT NOT NULL COMMENT 'Webhook the attempt was sent to',
+    event_id VARCHAR(64) NOT NULL COMMENT 'Delivered event ID',
+    event_type VARCHAR(100) NOT NULL COMMENT 'Delivered event type',
+    payload JSON NOT NULL COMMENT 'Rendered payload, reused on redelivery',
+    schema_version VARCHAR(10) NOT NULL DEFAULT '' COMMENT 'Event schema version of the payload, empty for templated payloads',
+    success BOOLEAN NOT NULL COMMENT 'Whether the receiver answered with 2xx',
+    status_code INT NOT NULL DEFAULT 0 COMMENT 'HTTP status code, 0 when no response',
+    duration_ms BIGINT NOT NULL DEFAULT 0 COMMENT 'Round trip latency in milliseconds',
+    response_snippet TEXT NOT NULL COMMENT 'No longer written (always empty); response bodies are not stored',
+    error TEXT NOT NULL COMMENT 'Delivery error message',
+    redelivery_of BIGINT NULL COMMENT 'Original delivery ID when this is a redelivery',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP COMMENT 'Attempt timestamp',
+    FOREIGN KEY (webhook_id) REFERENCES webhooks(id) ON DELETE CASCADE,
+    INDEX idx_webhook_deliveries_webhook (webhook_id, created_at),
+    INDEX idx_webhook_deliveries_created_at (created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Webhook delivery attempts';
+
+-- Append-only log of domain events, used to rebuild projections
+CREATE TABLE IF NOT EXISTS domain_events (
+    sequence BIGINT AUTO_INCREMENT PRIMARY KEY COMMENT 'Append order',
+    event_id VARCHAR(64) NOT NULL COMMENT 'Domain event ID',
+    event_type VARCHAR(100) NOT NULL COMMENT 'Event type, e.g. item.created',
+    item_id BIGINT NOT NULL COMMENT 'Target item',
+    schema_version VARCHAR(10) NOT NULL COMMENT 'Event schema version of the payload',
+    payload JSON NOT NULL COMMENT 'Event encoded with schema_version',
+    occurred_at TIMESTAMP NOT NULL COMMENT 'When the event occurred',
+
+    UNIQUE KEY uk_event_id (event_id),
+    INDEX idx_item_id (item_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Append-only domain event log';
+
+-- Retention periods changed by administrators
+-- Data types without a row use the defaults defined in the application
+CREATE TABLE IF NOT EXISTS retention_policies (
+    data_type VARCHAR(50) PRIMARY KEY COMMENT 'audit_logs, deleted_items or webhook_deliveries',
+    retain_days INT NOT NULL COMMENT 'Days to keep the data, 0 to keep forever',
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT 'When the policy was changed'
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Data retention policies';
+
+-- Staff accounts provisioned from the IdP over SCIM
+CREATE TABLE IF NOT EXISTS users (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_name VARCHAR(255) NOT NULL COMMENT 'Unique login name (case-insensitive)',
+    display_name VARCHAR(255) NOT NULL DEFAULT '' COMMENT 'Name shown in the UI',
+    email VARCHAR(255) NOT NULL DEFAULT '' COMMENT 'Primary email address',
+    external_id VARCHAR(255) NOT NULL DEFAULT '' COMMENT 'ID of the user in the IdP',
+    active BOOLEAN NOT NULL DEFAULT TRUE COMMENT 'FALSE once deprovisioned',
+    role VARCHAR(20) NOT NULL DEFAULT 'member' COMMENT 'admin, member or appraiser',
+    password_hash VARCHAR(255) NOT NULL DEFAULT '' COMMENT 'bcrypt hash of the password; empty if the user cannot log in with a password',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT 'Creation timestamp',
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT 'Last update timestamp',
+
+    UNIQUE KEY uk_user_name (user_name),
+    INDEX idx_external_id (external_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Staff accounts';
+
+-- Organizations that staff accounts belong to
+CREATE TABLE IF NOT EXISTS organizations (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(100) NOT NULL COMMENT 'Organization name',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT 'Creation timestamp'
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Organizations';
+
+-- Users belonging to an organization and their role
+CREATE TABLE IF NOT EXISTS organization_members (
+    organization_id BIGINT NOT NULL COMMENT 'Organization',
+    user_id BIGINT NOT NULL COMMENT 'Member',
+    role VARCHAR(20) NOT NULL COMMENT 'admin or member',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT 'When the user joined',
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT 'When the role last changed',
+
+    PRIMARY KEY (organization_id, user_id),
+    INDEX idx_user_id (user_id),
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Organization memberships';
+
+-- Email invitations to join an organization
+-- Only the SHA-256 hash of the token is stored
+CREATE TABLE IF NOT EXISTS organization_invitations (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    organization_id BIGINT NOT NULL COMMENT 'Organization the invitation is for',
+    email VARCHAR(255) NOT NULL COMMENT 'Invited address',
+    role VARCHAR(20) NOT NULL COMMENT 'Role given on acceptance',
+    token_hash CHAR(64) NOT NULL COMMENT 'SHA-256 of the invitation token',
+    invited_by BIGINT NOT NULL COMMENT 'Admin who sent the invitation',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT 'When the invitation was sent',
+    expires_at TIMESTAMP NOT NULL COMMENT 'When the token stops working',
+    accepted_at TIMESTAMP NULL DEFAULT NULL COMMENT 'When the invitation was accepted',
+    accepted_by BIGINT NULL DEFAULT NULL COMMENT 'User who accepted the invitation',
+
+    UNIQUE KEY uk_token_hash (token_hash),
+    INDEX idx_organization_id (organization_id),
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Organization invitations';
+
+-- Name, logo and accent color shown on pages visible outside the organization
+CREATE TABLE IF NOT EXISTS organization_branding (
+    organization_id BIGINT PRIMARY KEY COMMENT 'Organization',
+    display_name VARCHAR(100) NOT NULL COMMENT 'Name shown instead of the organization name',
+    logo_url VARCHAR(2048) NOT NULL DEFAULT '' COMMENT 'Absolute URL of the logo image (empty for none)',
+    accent_color CHAR(7) NOT NULL COMMENT 'Hex color such as #1F2937',
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT 'Last update timestamp',
+
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Organization branding';
+
+-- Fields of the organization's items that cannot be changed once the item reaches a verification status
+CREATE TABLE IF NOT EXISTS organization_field_locks (
+    organization_id BIGINT PRIMARY KEY COMMENT 'Organization',
+    rules JSON NOT NULL COMMENT 'List of {name, status, fields}',
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT 'Last update timestamp',
+
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Organization field lock rules';
+
+-- Sessions in which an administrator acts as another user
+-- Only the SHA-256 hash of the token is stored
+CREATE TABLE IF NOT EXISTS impersonation_sessions (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    token_hash CHAR(64) NOT NULL COMMENT 'SHA-256 of the bearer token',
+    admin_id BIGINT NOT NULL COMMENT 'Administrator who started the session',
+    user_id BIGINT NOT NULL COMMENT 'User being impersonated',
+    reason VARCHAR(500) NOT NULL COMMENT 'Why the session was started',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT 'When the session was started',
+    expires_at TIMESTAMP NOT NULL COMMENT 'When the token stops working',
+    ended_at TIMESTAMP NULL DEFAULT NULL COMMENT 'When the session was ended early',
+
+    UNIQUE KEY uk_token_hash (token_hash),
+    INDEX idx_expires_at (expires_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Administrator impersonation sessions';
+
+CREATE TABLE IF NOT EXISTS api_keys (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT NOT NULL COMMENT 'User the key acts as',
+    name VARCHAR(100) NOT NULL COMMENT 'Label chosen by the user',
+    prefix VARCHAR(20) NOT NULL COMMENT 'Leading characters of the key, shown in listings',
+    key_hash CHAR(64) NOT NULL COMMENT 'SHA-256 of the key',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT 'When the key was issued',
+    last_used_at TIMESTAMP NULL DEFAULT NULL COMMENT 'When the key last authenticated a request',
+    revoked_at TIMESTAMP NULL DEFAULT NULL COMMENT 'When the key was revoked',
+
+    UNIQUE KEY uk_key_hash (key_hash),
+    INDEX idx_user_id (user_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='API keys for machine clients';
+
+-- API keys for the sandbox; they only authenticate /sandbox routes, whose data is synthetic and kept in memory
+CREATE TABLE IF NOT EXISTS sandbox_api_keys (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT NOT NULL COMMENT 'User who issued the key',
+    name VARCHAR(100) NOT NULL COMMENT 'Label chosen by the user',
+    prefix VARCHAR(20) NOT NULL COMMENT 'Leading characters of the key, shown in listings',
+    key_hash CHAR(64) NOT NULL COMMENT 'SHA-256 of the key',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT 'When the key was issued',
+    last_used_at TIMESTAMP NULL DEFAULT NULL COMMENT 'When the key last authenticated a request',
+    revoked_at TIMESTAMP NULL DEFAULT NULL COMMENT 'When the key was revoked',
+
+    UNIQUE KEY uk_key_hash (key_hash),
+    INDEX idx_user_id (user_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='API keys for the sandbox';
+
+-- Column mappings for recurring import files (POST /items/import?profile=ID), one row per user-defined profile
+CREATE TABLE IF NOT EXISTS import_profiles (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    owner_id BIGINT NOT NULL COMMENT 'User who defined the profile',
+    name VARCHAR(100) NOT NULL COMMENT 'Label chosen by the user, e.g. the supplier',
+    column_map JSON NOT NULL COMMENT 'File column name (lowercase) to item field',
+    date_format VARCHAR(32) NOT NULL DEFAULT '' COMMENT 'Format of purchase_date in the file, e.g. DD.MM.YYYY (empty for YYYY-MM-DD)',
+    default_values JSON NOT NULL COMMENT 'Item field to the value used when the column is missing or empty',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    UNIQUE KEY uk_owner_name (owner_id, name)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Import mapping profiles managed through /import-profiles';
+
+-- Imports staged for review before they become items (/imports), removed once expired
+CREATE TABLE IF NOT EXISTS import_batches (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    owner_id BIGINT NOT NULL COMMENT 'User who uploaded the file',
+    filename VARCHAR(255) NOT NULL,
+    profile_id BIGINT NULL COMMENT 'Import profile used to read the file',
+    status VARCHAR(20) NOT NULL COMMENT 'staged or committed',
+    row_count INT NOT NULL DEFAULT 0,
+    invalid_rows INT NOT NULL DEFAULT 0,
+    created_count INT NOT NULL DEFAULT 0 COMMENT 'Items created on commit',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL COMMENT 'Extended on every edit (IMPORT_STAGING_TTL)',
+    committed_at TIMESTAMP NULL,
+
+    INDEX idx_owner (owner_id),
+    INDEX idx_expires_at (expires_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Staged imports managed through /imports';
+
+-- Rows of a staged import, deleted when the import is committed or discarded
+CREATE TABLE IF NOT EXISTS import_batch_rows (
+    batch_id BIGINT NOT NULL,
+    row_num INT NOT NULL COMMENT 'Line number in the uploaded file',
+    name TEXT NOT NULL COMMENT 'Values as read from the file, which may be invalid until edited',
+    category TEXT NOT NULL,
+    brand TEXT NOT NULL,
+    purchase_price BIGINT NOT NULL DEFAULT 0,
+    purchase_date VARCHAR(255) NOT NULL DEFAULT '',
+    organization_id BIGINT NULL,
+    read_errors JSON NOT NULL COMMENT 'Problems found while reading the file, cleared when the field is edited',
+    errors JSON NOT NULL COMMENT 'Problems found by the last validation',
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (batch_id, row_num)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Rows of staged imports';
+
+-- Calls to deprecated endpoints per caller, used to plan their removal
+-- method and route are the deprecation pattern from the DEPRECATIONS setting
+CREATE TABLE IF NOT EXISTS deprecation_usage (
+    method VARCHAR(10) NOT NULL COMMENT 'HTTP method, or * for all methods',
+    route VARCHAR(255) NOT NULL COMMENT 'Route pattern such as /items/:id',
+    caller VARCHAR(100) NOT NULL COMMENT 'user:<id>, or anonymous',
+    calls BIGINT NOT NULL DEFAULT 0 COMMENT 'Number of calls',
+    first_seen_at TIMESTAMP NOT NULL COMMENT 'First call',
+    last_seen_at TIMESTAMP NOT NULL COMMENT 'Most recent call',
+
+    PRIMARY KEY (method, route, caller),
+    INDEX idx_last_seen_at (last_seen_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Deprecated endpoint usage';
+
+-- Export bookmarks. The watermark is the last domain_events sequence covered by the export
+CREATE TABLE IF NOT EXISTS exports (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    watermark BIGINT NOT NULL COMMENT 'Last domain_events sequence included in the export',
+    since_export_id BIGINT NULL DEFAULT NULL COMMENT 'Export this one is a difference from, NULL for a full export',
+    format VARCHAR(16) NOT NULL DEFAULT 'json' COMMENT 'json or parquet',
+    record_count INT NOT NULL DEFAULT 0 COMMENT 'Number of records exported',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT 'When the export was taken'
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Export bookmarks for incremental exports';
+
+CREATE TABLE IF NOT EXISTS blobs (
+    blob_key VARCHAR(512) PRIMARY KEY COMMENT 'Key of the object in the blob store',
+    location VARCHAR(1024) NOT NULL COMMENT 'Where the object is stored (s3://bucket/key etc.)',
+    size BIGINT NOT NULL COMMENT 'Size in bytes',
+    sha256 CHAR(64) NOT NULL COMMENT 'Hex SHA-256 of the content, checked when the object is read',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT 'When the object was written',
+    expires_at TIMESTAMP NULL DEFAULT NULL COMMENT 'When the object is deleted, NULL to keep it',
+    INDEX idx_expires_at (expires_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Files in the blob store (exports, backups, attachments)';
+
+-- Content of item attachments, stored once per SHA-256 and shared by every attachment with the same bytes
+CREATE TABLE IF NOT EXISTS attachment_contents (
+    sha256 CHAR(64) PRIMARY KEY COMMENT 'Hex SHA-256 of the content, also the blob key (attachments/sha256/...)',
+    size BIGINT NOT NULL COMMENT 'Size in bytes',
+    ref_count INT NOT NULL DEFAULT 0 COMMENT 'Number of attachments using this content',
+    unreferenced_at TIMESTAMP NULL DEFAULT NULL COMMENT 'When ref_count dropped to zero; deleted by the GC job after a grace period',
+    INDEX idx_unreferenced_at (ref_count, unreferenced_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Deduplicated attachment content with reference counts';
+
+CREATE TABLE IF NOT EXISTS attachments (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    item_id BIGINT NOT NULL COMMENT 'Item the file is attached to',
+    kind VARCHAR(16) NOT NULL DEFAULT 'document' COMMENT 'document or receipt (at most one receipt per item)',
+    filename VARCHAR(255) NOT NULL COMMENT 'File name as uploaded',
+    content_type VARCHAR(255) NOT NULL DEFAULT '' COMMENT 'Content-Type sent with the upload',
+    size BIGINT NOT NULL COMMENT 'Size in bytes',
+    sha256 CHAR(64) NOT NULL COMMENT 'Content of the attachment',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT 'When the file was attached',
+
+    INDEX idx_item_kind (item_id, kind),
+    INDEX idx_sha256 (sha256),
+    CONSTRAINT fk_attachments_item FOREIGN KEY (item_id) REFERENCES items (id) ON DELETE CASCADE,
+    CONSTRAINT fk_attachments_content FOREIGN KEY (sha256) REFERENCES attachment_contents (sha256)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Files attached to items (certificates, receipts)';
+
+-- Verification of items by appraisers; items without a row are unverified
+CREATE TABLE IF NOT EXISTS item_verifications (
+    item_id BIGINT PRIMARY KEY,
+    status VARCHAR(20) NOT NULL COMMENT 'unverified, in_review or verified',
+    appraiser_id BIGINT NULL COMMENT 'Appraiser who started or signed off the verification',
+    notes TEXT NOT NULL COMMENT 'Findings of the appraiser',
+    certificate_id BIGINT NULL COMMENT 'Attachment holding the certificate',
+    locked_fields JSON NOT NULL COMMENT 'Fields that cannot be changed while verified',
+    started_at TIMESTAMP NULL,
+    verified_at TIMESTAMP NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT fk_item_verifications_item FOREIGN KEY (item_id) REFERENCES items (id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Item verification managed through /items/:id/verification';
+
+-- Images of items; the files are in the blob store and are deleted when the item is deleted (item.deleted event),
+-- so there is no foreign key to items that would remove the rows before the files
+CREATE TABLE IF NOT EXISTS item_images (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    item_id BIGINT NOT NULL COMMENT 'Item the image belongs to',
+    filename VARCHAR(255) NOT NULL COMMENT 'File name as uploaded',
+    content_type VARCHAR(64) NOT NULL COMMENT 'Image type detected from the content',
+    size BIGINT NOT NULL COMMENT 'Size in bytes',
+    blob_key VARCHAR(512) NOT NULL COMMENT 'Key of the file in the blob store (images/items/...)',
+    position INT NOT NULL DEFAULT 0 COMMENT 'Order in the gallery, from 0',
+    is_primary BOOLEAN NOT NULL DEFAULT FALSE COMMENT 'Image shown in item listings; one per item that has images',
+    thumbnail_status VARCHAR(16) NOT NULL DEFAULT 'none' COMMENT 'none, pending, ready, failed or unsupported; thumbnails are stored next to the image (<key>_small.jpg, ...)',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT 'When the image was uploaded',
+
+    INDEX idx_item_position (item_id, position),
+    INDEX idx_thumbnail_status (thumbnail_status)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Images of items';
+
+-- Uploads that failed the virus scan; the file is kept in the blob store (quarantine/...) until an admin deletes it
+CREATE TABLE IF NOT EXISTS quarantined_files (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    kind VARCHAR(32) NOT NULL COMMENT 'What was uploaded (image, attachment, receipt)',
+    item_id BIGINT NOT NULL COMMENT 'Item the file was uploaded to',
+    filename VARCHAR(255) NOT NULL COMMENT 'File name as uploaded',
+    content_type VARCHAR(255) NOT NULL DEFAULT '' COMMENT 'Content-Type sent with the upload',
+    size BIGINT NOT NULL COMMENT 'Size in bytes',
+    signature VARCHAR(255) NOT NULL COMMENT 'Name of the detected virus',
+    blob_key VARCHAR(512) NOT NULL COMMENT 'Key of the file in the blob store',
+    actor VARCHAR(255) NOT NULL COMMENT 'Who uploaded the file',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT 'When the file was quarantined'
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Quarantined uploads';
+
+-- How far a standby region has applied the change stream (domain_events) of each source
+CREATE TABLE IF NOT EXISTS replication_checkpoints (
+    source VARCHAR(255) PRIMARY KEY COMMENT 'Source the changes come from (REPLICATION_SOURCE_URL)',
+    last_sequence BIGINT NOT NULL DEFAULT 0 COMMENT 'Last source domain_events sequence applied',
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT 'When a batch was last applied'
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Replication progress of a standby region';
+
+-- Named readers of domain_events with at-least-once delivery. Events after acked_sequence are
+-- delivered again when they are not acknowledged before ack_deadline
+CREATE TABLE IF NOT EXISTS event_consumers (
+    name VARCHAR(64) PRIMARY KEY COMMENT 'Name chosen by the integration',
+    acked_sequence BIGINT NOT NULL DEFAULT 0 COMMENT 'Last domain_events sequence the consumer has processed',
+    delivered_sequence BIGINT NOT NULL DEFAULT 0 COMMENT 'Last domain_events sequence delivered, acknowledged or not',
+    ack_timeout_seconds INT NOT NULL DEFAULT 30 COMMENT 'How long a delivered batch waits for acknowledgement',
+    ack_deadline TIMESTAMP NULL COMMENT 'When the unacknowledged batch is delivered again; NULL if nothing is pending',
+    redeliveries INT NOT NULL DEFAULT 0 COMMENT 'How many batches were delivered again after their deadline',
+    last_acked_at TIMESTAMP NULL COMMENT 'When the consumer last acknowledged events',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Durable cursors of event consumers';
+
+-- Refresh tokens issued at login. Each use exchanges the token for a new one in the same family;
+-- reusing an exchanged token revokes the whole family
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT NOT NULL COMMENT 'User the token was issued to',
+    family_id VARCHAR(64) NOT NULL COMMENT 'Login session the token belongs to',
+    token_hash CHAR(64) NOT NULL COMMENT 'SHA-256 of the token',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT 'When the token was issued',
+    expires_at TIMESTAMP NOT NULL COMMENT 'When the token expires',
+    used_at TIMESTAMP NULL DEFAULT NULL COMMENT 'When the token was exchanged for a new one',
+    revoked_at TIMESTAMP NULL DEFAULT NULL COMMENT 'When the token was revoked',
+
+    UNIQUE KEY uk_token_hash (token_hash),
+    INDEX idx_family_id (family_id),
+    INDEX idx_user_id (user_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Rotating refresh tokens';
+
+-- Single-use tokens emailed to users who forgot their password
+CREATE TABLE IF NOT EXISTS password_reset_tokens (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT NOT NULL COMMENT 'User whose password the token resets',
+    token_hash CHAR(64) NOT NULL COMMENT 'SHA-256 of the token',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT 'When the token was issued',
+    expires_at TIMESTAMP NOT NULL COMMENT 'When the token expires',
+    used_at TIMESTAMP NULL DEFAULT NULL COMMENT 'When the password was reset',
+
+    UNIQUE KEY uk_token_hash (token_hash),
+    INDEX idx_user_id (user_id),
+    CONSTRAINT fk_password_reset_tokens_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Password reset tokens';
+
+-- TOTP two-factor settings, one row per user (enabled_at is NULL while enrolling)
+CREATE TABLE IF NOT EXISTS user_two_factor (
+    user_id BIGINT PRIMARY KEY COMMENT 'User',
+    secret VARCHAR(64) NOT NULL COMMENT 'Base32 TOTP secret shared with the authenticator app',
+    enabled_at TIMESTAMP NULL DEFAULT NULL COMMENT 'When the first code was confirmed (NULL while enrolling)',
+    last_used_step BIGINT NOT NULL DEFAULT 0 COMMENT 'TOTP time step of the last accepted code, to reject replays',
+    recovery_codes JSON NOT NULL COMMENT 'SHA-256 hashes of the unused recovery codes',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    CONSTRAINT fk_user_two_factor_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='TOTP two-factor authentication';
+
+-- Channels each user receives notifications on (SLO alerts to admins); Slack is shared and not stored per user
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    user_id BIGINT PRIMARY KEY COMMENT 'User',
+    channels JSON NOT NULL COMMENT 'Enabled personal channels (line, email, push)',
+    line_user_id VARCHAR(64) NOT NULL DEFAULT '' COMMENT 'LINE user ID that follows the official account',
+    push_tokens JSON NOT NULL COMMENT 'FCM registration tokens of the user''s devices',
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    CONSTRAINT fk_notification_preferences_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Per-user notification channels';
+
+-- External identities (OIDC / OAuth2) linked to users; an identity can be linked to one user only
+CREATE TABLE IF NOT EXISTS user_identities (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT NOT NULL COMMENT 'User the identity is linked to',
+    provider VARCHAR(32) NOT NULL COMMENT 'Identity provider (google, github)',
+    subject VARCHAR(255) NOT NULL COMMENT 'Stable ID of the user at the provider',
+    email VARCHAR(255) NOT NULL DEFAULT '' COMMENT 'Verified email at the time of linking',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT 'When the identity was linked',
+
+    UNIQUE KEY uk_provider_subject (provider, subject),
+    INDEX idx_user_id (user_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Linked external identities';
+
+-- Login requests between the redirect to the identity provider and the callback
+CREATE TABLE IF NOT EXISTS oidc_logins (
+    state_hash CHAR(64) PRIMARY KEY COMMENT 'SHA-256 of the state parameter',
+    provider VARCHAR(32) NOT NULL COMMENT 'Identity provider (google, github)',
+    nonce VARCHAR(64) NOT NULL COMMENT 'Nonce expected in the ID token',
+    code_verifier VARCHAR(128) NOT NULL COMMENT 'PKCE code verifier',
+    user_id BIGINT NULL DEFAULT NULL COMMENT 'User to link the identity to (NULL for a login)',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT 'When the login started',
+    expires_at TIMESTAMP NOT NULL COMMENT 'When the request expires',
+
+    INDEX idx_expires_at (expires_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Pending OIDC logins';
+
+-- Daily value of the whole portfolio, recorded by the portfolio snapshot job (one row per UTC date)
+CREATE TABLE IF NOT EXISTS portfolio_snapshots (
+    snapshot_date DATE PRIMARY KEY COMMENT 'Date of the snapshot (UTC)',
+    item_count INT NOT NULL COMMENT 'Number of live items',
+    purchase_value BIGINT NOT NULL COMMENT 'Sum of purchase prices',
+    current_value BIGINT NOT NULL COMMENT 'Sum of current values (purchase price times the category value_percent)',
+    categories JSON NOT NULL COMMENT 'The same values per category',
+    recorded_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT 'When the snapshot was last recorded'
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Daily portfolio value for GET /reports/portfolio-history';
+
+-- Admin-managed reference data of every type (see entity.ReferenceTypes), one row per record
+CREATE TABLE IF NOT EXISTS reference_data (
+    ref_type VARCHAR(50) NOT NULL COMMENT 'Reference data type, e.g. categories',
+    ref_key VARCHAR(100) NOT NULL COMMENT 'Key of the record within the type',
+    attributes JSON NOT NULL COMMENT 'Field values defined by the type',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (ref_type, ref_key)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Reference data managed through /admin/reference';
+
+-- Schema version checked at startup (see internal/infrastructure/database/schema.go)
+-- Migrations that change the schema must bump version, and min_compatible when older binaries can no longer run
+CREATE TABLE IF NOT EXISTS schema_version (
+    id TINYINT PRIMARY KEY COMMENT 'Always 1',
+    version INT NOT NULL COMMENT 'Applied schema version',
+    min_compatible INT NOT NULL COMMENT 'Oldest binary schema version that can run against this schema',
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT 'Last update timestamp'
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Applied schema version';
+
+-- Existing databases keep their recorded version; only a fresh database starts at the current one
+-- Version 2 covers the tables and columns added since version 1; older binaries are not tested against them
+INSERT INTO schema_version (id, version, min_compatible) VALUES (1, 2, 2)
+ON DUPLICATE KEY UPDATE id = id;
+
+-- Insert sample data for testing; the sample items belong to the sample admin so that they are visible through the API
+-- (items without an owner are visible only to admins until PUT /admin/items/{id}/owner assigns one)
+INSERT INTO users (id, user_name, display_name, email, role) VALUES
+(1, 'admin', '管理者', 'admin@example.com', 'admin')
+ON DUPLICATE KEY UPDATE id = id;
+
+INSERT INTO items (name, category, brand, purchase_price, purchase_date, owner_id) VALUES
+('ロレックス デイトナ', '時計', 'ROLEX', 1500000, '2023-01-15', 1),
+('エルメス バーキン', 'バッグ', 'HERMÈS', 2000000, '2023-02-20', 1),
+('ティファニー ネックレス', 'ジュエリー', 'Tiffany & Co.', 300000, '2023-03-10', 1),
+('ルブタン パンプス', '靴', 'Christian Louboutin', 150000, '2023-04-05', 1),
+('アップルウォッチ', 'その他', 'Apple', 50000, '2023-05-12', 1);
+
+-- Default item categories (entity.DefaultCategories); existing rows are left as they are
+INSERT INTO reference_data (ref_type, ref_key, attributes) VALUES
+('categories', '時計', '{"display_order": 1}'),
+('categories', 'バッグ', '{"display_order": 2}'),
+('categories', 'ジュエリー', '{"display_order": 3}'),
+('categories', '靴', '{"display_order": 4}'),
+('categories', 'その他', '{"display_order": 5}')
+ON DUPLICATE KEY UPDATE ref_key = ref_key;